@@ -0,0 +1,88 @@
+// Command codegen runs sqlc generate for one or more configurations and
+// skips those whose schema, queries and configuration are unchanged.
+//
+// Usage:
+//
+//	codegen -sqlc sqlc -json sqlc.yaml config/generated/sqlc-sqlite.yaml
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+
+	"github.com/LarsArtmann/template-sqlc/pkg/codegen"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	err := run(ctx, os.Args[1:], os.Stdout, os.Stderr)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "codegen: %v\n", err)
+		stop()
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("codegen", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	sqlc := flags.String("sqlc", "sqlc", "sqlc binary")
+	force := flags.Bool("force", false, "regenerate even if the inputs are unchanged")
+	cacheFile := flags.String("cache", codegen.DefaultCacheFile, "cache file, relative to each configuration")
+	asJSON := flags.Bool("json", false, "print progress as JSON lines")
+
+	err := flags.Parse(args)
+	if err != nil {
+		return fmt.Errorf("parse flags: %w", err)
+	}
+
+	configs := flags.Args()
+	if len(configs) == 0 {
+		configs = []string{"sqlc.yaml"}
+	}
+
+	encoder := json.NewEncoder(stdout)
+	progress := func(event codegen.Event) {
+		if *asJSON {
+			_ = encoder.Encode(event)
+
+			return
+		}
+
+		switch event.Stage {
+		case codegen.StageOutput:
+			fmt.Fprintf(stdout, "  %s\n", event.Message)
+		case codegen.StageCached:
+			fmt.Fprintf(stdout, "%s: unchanged, skipped\n", event.Config)
+		case codegen.StageGenerating:
+			fmt.Fprintf(stdout, "%s: generating\n", event.Config)
+		case codegen.StageDone:
+			fmt.Fprintf(stdout, "%s: generated in %s\n", event.Config, event.Elapsed.Round(1e6))
+		case codegen.StageHashing, codegen.StageFailed:
+		}
+	}
+
+	runner := codegen.New(
+		codegen.WithGenerator(codegen.Exec{Binary: *sqlc}),
+		codegen.WithProgress(progress),
+		codegen.WithCacheFile(*cacheFile),
+		codegen.WithForce(*force),
+	)
+
+	for _, config := range configs {
+		_, err := runner.Run(ctx, config)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+// Command doctor checks that the sqlc configuration, queries, generated code
+// and repository adapters agree with each other.
+//
+// Usage:
+//
+//	doctor -root . -sqlc sqlc -json
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+
+	"github.com/LarsArtmann/template-sqlc/internal/doctor"
+)
+
+var errUnhealthy = errors.New("doctor found errors")
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	err := run(ctx, os.Args[1:], os.Stdout, os.Stderr)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "doctor: %v\n", err)
+		stop()
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("doctor", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	root := flags.String("root", ".", "repository root")
+	sqlc := flags.String("sqlc", "sqlc", "sqlc binary used for vet; empty skips vet")
+	config := flags.String("config", "sqlc.yaml", "sqlc configuration file, relative to root")
+	asJSON := flags.Bool("json", false, "print the report as JSON")
+	strict := flags.Bool("strict", false, "fail on warnings as well as errors")
+
+	err := flags.Parse(args)
+	if err != nil {
+		return fmt.Errorf("parse flags: %w", err)
+	}
+
+	cfg := doctor.DefaultConfig(*root)
+	cfg.SQLC = *sqlc
+	cfg.SQLCConfig = *config
+
+	report, err := doctor.Run(ctx, cfg)
+	if err != nil {
+		return fmt.Errorf("run: %w", err)
+	}
+
+	err = printReport(stdout, report, *asJSON)
+	if err != nil {
+		return err
+	}
+
+	if report.HasErrors() || (*strict && len(report.Findings) > 0) {
+		return fmt.Errorf("findings=%v: %w", len(report.Findings), errUnhealthy)
+	}
+
+	return nil
+}
+
+func printReport(out io.Writer, report *doctor.Report, asJSON bool) error {
+	if asJSON {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+
+		err := encoder.Encode(report)
+		if err != nil {
+			return fmt.Errorf("encode report: %w", err)
+		}
+
+		return nil
+	}
+
+	if len(report.Findings) == 0 {
+		fmt.Fprintln(out, "no findings")
+
+		return nil
+	}
+
+	for _, finding := range report.Findings {
+		fmt.Fprintln(out, finding)
+	}
+
+	return nil
+}
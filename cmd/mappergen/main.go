@@ -0,0 +1,524 @@
+// Command mappergen generates the mappers between the sqlc users model of a
+// database engine and the engine-neutral mappers.UserRow.
+//
+// It reads the Users struct from the engine's generated models.go and emits
+// the DomainUserFrom<Engine> and <Engine>UserFromDomain methods of
+// mappers.UserMapper, converting every column with a UserRow counterpart.
+// It runs through go:generate in internal/adapters/mappers:
+//
+//	mappergen -engine SQLite -models ../../db/sqlite/models.go -out user_sqlite_gen.go
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var (
+	errModelNotFound   = errors.New("model struct not found")
+	errUnsupportedType = errors.New("unsupported column type")
+	errNoModule        = errors.New("no go.mod above models")
+)
+
+// rowFields maps the model fields to the UserRow fields and their kinds.
+var rowFields = map[string]struct{ field, kind string }{
+	"ID":              {"ID", "int64"},
+	"UUID":            {"UUID", "uuid"},
+	"Email":           {"Email", "string"},
+	"Username":        {"Username", "string"},
+	"PasswordHash":    {"PasswordHash", "string"},
+	"FirstName":       {"FirstName", "string"},
+	"LastName":        {"LastName", "string"},
+	"Status":          {"Status", "string"},
+	"Role":            {"Role", "string"},
+	"IsVerified":      {"IsVerified", "bool"},
+	"ProfileMetadata": {"Metadata", "json"},
+	"Tags":            {"Tags", "tags"},
+	"CreatedAt":       {"CreatedAt", "time"},
+	"UpdatedAt":       {"UpdatedAt", "time"},
+	"LastLoginAt":     {"LastLoginAt", "nullableTime"},
+}
+
+// conversion is a Go expression template: {} stands for the source value and
+// {T} for the model column type. Fallible conversions return (value, error).
+type conversion struct {
+	expr     string
+	fallible bool
+}
+
+// rule converts between a UserRow field and a model column in both directions.
+type rule struct {
+	toRow, toModel conversion
+}
+
+var (
+	direct = rule{toRow: conversion{expr: "{}"}, toModel: conversion{expr: "{}"}}
+
+	// rules is keyed by UserRow kind, then by model column type.
+	rules = map[string]map[string]rule{
+		"string": {"string": direct},
+		"int64": {
+			"int64":  direct,
+			"uint64": {toRow: conversion{expr: "int64({})"}, toModel: conversion{expr: "uint64({})"}},
+		},
+		"uuid": {
+			"string": direct,
+			"uuid.UUID": {
+				toRow:   conversion{expr: "{}.String()"},
+				toModel: conversion{expr: "uuid.Parse({})", fallible: true},
+			},
+			"[]byte": {
+				toRow:   conversion{expr: "UUIDString({})", fallible: true},
+				toModel: conversion{expr: "UUIDBytes({})", fallible: true},
+			},
+		},
+		"bool": {
+			"bool": direct,
+			"sql.NullBool": {
+				toRow:   conversion{expr: "{}.Bool"},
+				toModel: conversion{expr: "sql.NullBool{Bool: {}, Valid: true}"},
+			},
+			"*bool": {
+				toRow:   conversion{expr: "{} != nil && *{}"},
+				toModel: conversion{expr: "BoolPtr({})"},
+			},
+		},
+		"time": {
+			"time.Time": direct,
+			"sql.NullTime": {
+				toRow:   conversion{expr: "{}.Time"},
+				toModel: conversion{expr: "sql.NullTime{Time: {}, Valid: !{}.IsZero()}"},
+			},
+			"pgtype.Timestamptz": {
+				toRow:   conversion{expr: "{}.Time"},
+				toModel: conversion{expr: "pgtype.Timestamptz{Time: {}, Valid: !{}.IsZero()}"},
+			},
+		},
+		"nullableTime": {
+			"sql.NullTime": {
+				toRow:   conversion{expr: "nullTime.DBToDomain({})", fallible: true},
+				toModel: conversion{expr: "nullTime.DomainToDB({})"},
+			},
+			"pgtype.Timestamptz": {
+				toRow:   conversion{expr: "timestamptz.DBToDomain({})", fallible: true},
+				toModel: conversion{expr: "timestamptz.DomainToDB({})"},
+			},
+		},
+		"json": {
+			"interface{}":     direct,
+			"string":          {toRow: conversion{expr: "{}"}, toModel: conversion{expr: "JSONText[{T}]({})", fallible: true}},
+			"[]byte":          {toRow: conversion{expr: "{}"}, toModel: conversion{expr: "JSONText[{T}]({})", fallible: true}},
+			"json.RawMessage": {toRow: conversion{expr: "[]byte({})"}, toModel: conversion{expr: "JSONText[{T}]({})", fallible: true}},
+		},
+		"tags": {
+			"[]string":        {toRow: conversion{expr: "{}"}, toModel: conversion{expr: "DecodeTags({})", fallible: true}},
+			"string":          {toRow: conversion{expr: "{}"}, toModel: conversion{expr: "TagsJSON[{T}]({})", fallible: true}},
+			"json.RawMessage": {toRow: conversion{expr: "[]byte({})"}, toModel: conversion{expr: "TagsJSON[{T}]({})", fallible: true}},
+		},
+	}
+)
+
+func main() {
+	engine := flag.String("engine", "", "engine name used in the generated identifiers, e.g. SQLite (required)")
+	modelsPath := flag.String("models", "", "path to the sqlc models.go of the engine (required)")
+	typeName := flag.String("type", "Users", "model struct mapped to mappers.UserRow")
+	out := flag.String("out", "", "output file (default stdout)")
+
+	flag.Parse()
+
+	if *engine == "" || *modelsPath == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	src, err := generate(*engine, *modelsPath, *typeName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "mappergen: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *out == "" {
+		_, err = os.Stdout.Write(src)
+	} else {
+		err = os.WriteFile(*out, src, 0o600)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "mappergen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// field is a model column with a UserRow counterpart.
+type field struct {
+	name, rowField, modelType string
+	rule                      rule
+}
+
+// model describes the parsed sqlc model struct.
+type model struct {
+	buildTag   string
+	importPath string
+	alias      string
+	typeName   string
+	fields     []field
+	// unmapped lists the columns without a UserRow field.
+	unmapped []string
+	// imports maps the package names used by column types to their paths.
+	imports map[string]string
+}
+
+// generate renders the mapper source for the model struct in modelsPath.
+func generate(engine, modelsPath, typeName string) ([]byte, error) {
+	m, err := parseModel(modelsPath, typeName)
+	if err != nil {
+		return nil, err
+	}
+
+	var body bytes.Buffer
+
+	writeMethods(&body, engine, m)
+	writeToRow(&body, engine, m)
+	writeToModel(&body, engine, m)
+
+	var file bytes.Buffer
+
+	fmt.Fprintf(&file, "// Code generated by mappergen from %s. DO NOT EDIT.\n\n", filepath.ToSlash(modelsPath))
+
+	if m.buildTag != "" {
+		fmt.Fprintf(&file, "//go:build %s\n\n", m.buildTag)
+	}
+
+	file.WriteString("package mappers\n\n")
+	writeImports(&file, body.String(), m)
+	file.Write(body.Bytes())
+
+	src, err := format.Source(file.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format generated code: %w", err)
+	}
+
+	return src, nil
+}
+
+// parseModel reads the struct typeName and the build constraint of modelsPath.
+func parseModel(modelsPath, typeName string) (*model, error) {
+	fset := token.NewFileSet()
+
+	file, err := parser.ParseFile(fset, modelsPath, nil, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("parse models=%v: %w", modelsPath, err)
+	}
+
+	importPath, err := packageImportPath(filepath.Dir(modelsPath))
+	if err != nil {
+		return nil, err
+	}
+
+	m := &model{
+		buildTag:   buildTag(file),
+		importPath: importPath,
+		alias:      path.Base(importPath) + "db",
+		typeName:   typeName,
+		imports:    map[string]string{},
+	}
+
+	for _, spec := range file.Imports {
+		importPath, _ := strconv.Unquote(spec.Path.Value)
+		m.imports[path.Base(importPath)] = importPath
+	}
+
+	fields, err := structFields(file, typeName)
+	if err != nil {
+		return nil, fmt.Errorf("models=%v: %w", modelsPath, err)
+	}
+
+	for _, f := range fields {
+		for _, name := range f.Names {
+			modelType := types.ExprString(f.Type)
+
+			mapped, ok := rowFields[name.Name]
+			if !ok {
+				m.unmapped = append(m.unmapped, name.Name)
+
+				continue
+			}
+
+			r, ok := rules[mapped.kind][modelType]
+			if !ok {
+				return nil, fmt.Errorf("field=%v type=%v: %w", name.Name, modelType, errUnsupportedType)
+			}
+
+			m.fields = append(m.fields, field{
+				name:      name.Name,
+				rowField:  mapped.field,
+				modelType: modelType,
+				rule:      r,
+			})
+		}
+	}
+
+	return m, nil
+}
+
+// structFields returns the fields of the struct typeName declared in file.
+func structFields(file *ast.File, typeName string) ([]*ast.Field, error) {
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+
+		for _, spec := range gen.Specs {
+			typeSpec, ok := spec.(*ast.TypeSpec)
+			if !ok || typeSpec.Name.Name != typeName {
+				continue
+			}
+
+			structType, ok := typeSpec.Type.(*ast.StructType)
+			if !ok {
+				return nil, fmt.Errorf("type=%v: %w", typeName, errModelNotFound)
+			}
+
+			return structType.Fields.List, nil
+		}
+	}
+
+	return nil, fmt.Errorf("type=%v: %w", typeName, errModelNotFound)
+}
+
+// buildTag returns the //go:build expression of file, if any.
+func buildTag(file *ast.File) string {
+	for _, group := range file.Comments {
+		if group.Pos() > file.Package {
+			break
+		}
+
+		for _, comment := range group.List {
+			expr, ok := strings.CutPrefix(comment.Text, "//go:build ")
+			if ok {
+				return strings.TrimSpace(expr)
+			}
+		}
+	}
+
+	return ""
+}
+
+// packageImportPath derives the import path of dir from the enclosing go.mod.
+func packageImportPath(dir string) (string, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("dir=%v: %w", dir, err)
+	}
+
+	for root := abs; ; root = filepath.Dir(root) {
+		module, err := modulePath(filepath.Join(root, "go.mod"))
+		if err == nil {
+			rel, err := filepath.Rel(root, abs)
+			if err != nil {
+				return "", fmt.Errorf("dir=%v: %w", dir, err)
+			}
+
+			return path.Join(module, filepath.ToSlash(rel)), nil
+		}
+
+		if filepath.Dir(root) == root {
+			return "", fmt.Errorf("dir=%v: %w", dir, errNoModule)
+		}
+	}
+}
+
+// modulePath reads the module path declared in a go.mod file.
+func modulePath(goMod string) (string, error) {
+	file, err := os.Open(goMod)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		module, ok := strings.CutPrefix(strings.TrimSpace(scanner.Text()), "module ")
+		if ok {
+			return strings.Trim(strings.TrimSpace(module), `"`), nil
+		}
+	}
+
+	return "", fmt.Errorf("go.mod=%v: %w", goMod, errNoModule)
+}
+
+// writeImports writes the imports referenced by the generated body, standard
+// library first.
+func writeImports(w *bytes.Buffer, body string, m *model) {
+	var std, external []string
+
+	if strings.Contains(body, "fmt.") {
+		std = append(std, "fmt")
+	}
+
+	for name, importPath := range m.imports {
+		if !strings.Contains(body, name+".") {
+			continue
+		}
+
+		if strings.Contains(strings.Split(importPath, "/")[0], ".") {
+			external = append(external, importPath)
+		} else {
+			std = append(std, importPath)
+		}
+	}
+
+	external = append(external, "github.com/LarsArtmann/template-sqlc/internal/domain/entities")
+
+	slices.Sort(std)
+	slices.Sort(external)
+
+	w.WriteString("import (\n")
+
+	for _, importPath := range std {
+		fmt.Fprintf(w, "\t%q\n", importPath)
+	}
+
+	w.WriteString("\n")
+
+	for _, importPath := range external {
+		fmt.Fprintf(w, "\t%q\n", importPath)
+	}
+
+	fmt.Fprintf(w, "\t%s %q\n)\n\n", m.alias, m.importPath)
+}
+
+// writeMethods writes the UserMapper methods converting between the model and
+// domain entities.
+func writeMethods(w *bytes.Buffer, engine string, m *model) {
+	fmt.Fprintf(w, `// DomainUserFrom%[1]s converts a %[1]s users row into a domain entity.
+func (m *UserMapper) DomainUserFrom%[1]s(model *%[2]s.%[3]s) (*entities.User, error) {
+	row, err := %[1]sUserRow(model)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.DomainUserFromRow(row)
+}
+
+// %[1]sUserFromDomain converts a domain entity into a %[1]s users row.
+func (m *UserMapper) %[1]sUserFromDomain(user *entities.User) (*%[2]s.%[3]s, error) {
+	row, err := m.UserRowFromDomain(user)
+	if err != nil {
+		return nil, err
+	}
+
+	return %[1]sUserModel(row)
+}
+
+`, engine, m.alias, m.typeName)
+}
+
+// writeToRow writes the conversion from the model to UserRow.
+func writeToRow(w *bytes.Buffer, engine string, m *model) {
+	fmt.Fprintf(w, "// %[1]sUserRow copies a %[1]s users row into a UserRow.\n", engine)
+	fmt.Fprintf(w, "func %sUserRow(model *%s.%s) (*UserRow, error) {\n", engine, m.alias, m.typeName)
+	w.WriteString("\trow := &UserRow{\n")
+
+	var fallible []field
+
+	for _, f := range m.fields {
+		if f.rule.toRow.fallible {
+			fallible = append(fallible, f)
+
+			continue
+		}
+
+		fmt.Fprintf(w, "\t\t%s: %s,\n", f.rowField, f.rule.toRow.render("model."+f.name, f.modelType))
+	}
+
+	w.WriteString("\t}\n\n")
+	writeFallible(w, fallible, func(f field) (string, conversion) {
+		return "row." + f.rowField, f.rule.toRow
+	}, "model.")
+	w.WriteString("\treturn row, nil\n}\n\n")
+}
+
+// writeToModel writes the conversion from UserRow to the model.
+func writeToModel(w *bytes.Buffer, engine string, m *model) {
+	fmt.Fprintf(w, "// %[1]sUserModel copies a UserRow into a %[1]s users row.\n", engine)
+
+	if len(m.unmapped) > 0 {
+		fmt.Fprintf(w, "// %s have no UserRow field and are left zero.\n", joinNames(m.unmapped))
+	}
+
+	fmt.Fprintf(w, "func %sUserModel(row *UserRow) (*%s.%s, error) {\n", engine, m.alias, m.typeName)
+	fmt.Fprintf(w, "\tmodel := &%s.%s{\n", m.alias, m.typeName)
+
+	var fallible []field
+
+	for _, f := range m.fields {
+		if f.rule.toModel.fallible {
+			fallible = append(fallible, f)
+
+			continue
+		}
+
+		fmt.Fprintf(w, "\t\t%s: %s,\n", f.name, f.rule.toModel.render("row."+f.rowField, f.modelType))
+	}
+
+	w.WriteString("\t}\n\n")
+	writeFallible(w, fallible, func(f field) (string, conversion) {
+		return "model." + f.name, f.rule.toModel
+	}, "row.")
+	w.WriteString("\treturn model, nil\n}\n\n")
+}
+
+// writeFallible writes the assignments of conversions that can fail, each
+// returning the error annotated with the column.
+func writeFallible(w *bytes.Buffer, fields []field, target func(field) (string, conversion), source string) {
+	if len(fields) == 0 {
+		return
+	}
+
+	w.WriteString("\tvar err error\n\n")
+
+	for _, f := range fields {
+		dst, conv := target(f)
+
+		from := source + f.rowField
+		if source == "model." {
+			from = source + f.name
+		}
+
+		fmt.Fprintf(w, "\t%s, err = %s\n", dst, conv.render(from, f.modelType))
+		fmt.Fprintf(w, "\tif err != nil {\n\t\treturn nil, fmt.Errorf(\"user id=%%d %s: %%w\", row.ID, err)\n\t}\n\n",
+			f.rowField)
+	}
+}
+
+// render fills the expression template with the source value and model type.
+func (c conversion) render(source, modelType string) string {
+	expr := strings.ReplaceAll(c.expr, "{T}", modelType)
+
+	return strings.ReplaceAll(expr, "{}", source)
+}
+
+// joinNames lists names in prose: "A", "A and B", "A, B and C".
+func joinNames(names []string) string {
+	if len(names) == 1 {
+		return names[0]
+	}
+
+	return strings.Join(names[:len(names)-1], ", ") + " and " + names[len(names)-1]
+}
@@ -0,0 +1,189 @@
+// Command queryanalyzer explains the sqlc queries of one engine against a
+// development database and prints sequential scans, costs and index
+// suggestions, optionally as JSON.
+//
+// Sample parameters are read from a JSON file mapping query names to their
+// parameters in placeholder order.
+//
+// Usage:
+//
+//	queryanalyzer -driver postgres -dsn postgres://localhost/app -params samples.json -analyze
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/querylint"
+	"github.com/LarsArtmann/template-sqlc/pkg/db"
+	"github.com/LarsArtmann/template-sqlc/pkg/queryanalyzer"
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	_ "modernc.org/sqlite"
+)
+
+// driverNames maps engines to their registered database/sql drivers.
+var driverNames = map[db.Driver]string{
+	db.DriverPostgres: "pgx",
+	db.DriverMySQL:    "mysql",
+	db.DriverSQLite:   "sqlite",
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	err := run(ctx, os.Args[1:], os.Stdout, os.Stderr)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "queryanalyzer: %v\n", err)
+		stop()
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("queryanalyzer", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	driver := flags.String("driver", string(db.DriverSQLite), "engine: sqlite, postgres or mysql")
+	dsn := flags.String("dsn", "", "data source name of the development database")
+	queries := flags.String("queries", "", "query directory (default sql/<driver>/queries)")
+	paramsFile := flags.String("params", "", "JSON file of sample parameters by query name")
+	analyze := flags.Bool("analyze", false, "execute read-only queries with EXPLAIN ANALYZE")
+	timeout := flags.Duration("timeout", 30*time.Second, "time limit per query")
+	asJSON := flags.Bool("json", false, "print the report as JSON")
+
+	err := flags.Parse(args)
+	if err != nil {
+		return fmt.Errorf("parse flags: %w", err)
+	}
+
+	driverName, ok := driverNames[db.Driver(*driver)]
+	if !ok {
+		return fmt.Errorf("driver=%v: %w", *driver, queryanalyzer.ErrUnsupportedDriver)
+	}
+
+	if *dsn == "" {
+		return fmt.Errorf("driver=%v: %w", *driver, db.ErrMissingDSN)
+	}
+
+	if *queries == "" {
+		*queries = filepath.Join("sql", *driver, "queries")
+	}
+
+	parsed, err := querylint.ParseQueries(*queries)
+	if err != nil {
+		return err
+	}
+
+	opts := []queryanalyzer.Option{queryanalyzer.WithAnalyze(*analyze), queryanalyzer.WithTimeout(*timeout)}
+
+	if *paramsFile != "" {
+		params, err := loadParams(*paramsFile)
+		if err != nil {
+			return err
+		}
+
+		for name, values := range params {
+			opts = append(opts, queryanalyzer.WithParams(name, values...))
+		}
+	}
+
+	database, err := sql.Open(driverName, *dsn)
+	if err != nil {
+		return fmt.Errorf("driver=%v: %w", *driver, err)
+	}
+
+	defer func() { _ = database.Close() }()
+
+	analyzer, err := queryanalyzer.New(database, db.Driver(*driver), opts...)
+	if err != nil {
+		return err
+	}
+
+	named := make([]queryanalyzer.Query, len(parsed))
+	for i, query := range parsed {
+		named[i] = queryanalyzer.Query{Name: query.Name, SQL: query.SQL}
+	}
+
+	report, err := analyzer.Analyze(ctx, named)
+	if err != nil {
+		return err
+	}
+
+	return printReport(stdout, report, *asJSON)
+}
+
+func loadParams(name string) (map[string][]any, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("params=%v: %w", name, err)
+	}
+
+	var params map[string][]any
+
+	err = json.Unmarshal(data, &params)
+	if err != nil {
+		return nil, fmt.Errorf("params=%v: %w", name, err)
+	}
+
+	return params, nil
+}
+
+func printReport(out io.Writer, report *queryanalyzer.Report, asJSON bool) error {
+	if asJSON {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+
+		err := encoder.Encode(report)
+		if err != nil {
+			return fmt.Errorf("encode report: %w", err)
+		}
+
+		return nil
+	}
+
+	scans := 0
+
+	for _, result := range report.Results {
+		switch {
+		case result.Error != "":
+			fmt.Fprintf(out, "%s: error: %s\n", result.Query, result.Error)
+
+			continue
+		case result.Analyzed:
+			fmt.Fprintf(out, "%s: cost %.2f, %v\n", result.Query, result.Cost, result.Time)
+		case result.Cost > 0:
+			fmt.Fprintf(out, "%s: cost %.2f\n", result.Query, result.Cost)
+		default:
+			fmt.Fprintf(out, "%s\n", result.Query)
+		}
+
+		if len(result.SeqScans) > 0 {
+			scans++
+
+			fmt.Fprintf(out, "  seq scan: %s\n", strings.Join(result.SeqScans, ", "))
+		}
+
+		for _, note := range result.Notes {
+			fmt.Fprintf(out, "  note: %s\n", note)
+		}
+
+		for _, suggestion := range result.Suggestions {
+			fmt.Fprintf(out, "  suggest: %s\n", suggestion)
+		}
+	}
+
+	fmt.Fprintf(out, "%d queries, %d with sequential scans\n", len(report.Results), scans)
+
+	return nil
+}
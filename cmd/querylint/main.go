@@ -0,0 +1,113 @@
+// Command querylint checks the sqlc queries of each engine for dangerous
+// patterns and prints the findings, optionally as JSON.
+//
+// Usage:
+//
+//	querylint -root . -engines sqlite,postgres,mysql -fail-on warning -json
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/LarsArtmann/template-sqlc/internal/querylint"
+)
+
+var errFindings = errors.New("querylint found problems")
+
+// severities orders the severities from most to least severe.
+var severities = []querylint.Severity{querylint.SeverityError, querylint.SeverityWarning, querylint.SeverityInfo}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	err := run(ctx, os.Args[1:], os.Stdout, os.Stderr)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "querylint: %v\n", err)
+		stop()
+		os.Exit(1)
+	}
+}
+
+func run(_ context.Context, args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("querylint", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	root := flags.String("root", ".", "repository root containing sql/<engine>/{queries,schema}")
+	engines := flags.String("engines", "sqlite,postgres,mysql", "comma-separated engines to lint")
+	failOn := flags.String("fail-on", string(querylint.SeverityError), "lowest severity that fails the run: error, warning or info")
+	asJSON := flags.Bool("json", false, "print the report as JSON")
+
+	err := flags.Parse(args)
+	if err != nil {
+		return fmt.Errorf("parse flags: %w", err)
+	}
+
+	threshold := slices.Index(severities, querylint.Severity(*failOn))
+	if threshold < 0 {
+		return fmt.Errorf("fail-on=%v: %w", *failOn, flag.ErrHelp)
+	}
+
+	report := &querylint.Report{Findings: []querylint.Finding{}}
+
+	for _, engine := range strings.Split(*engines, ",") {
+		engine = strings.TrimSpace(engine)
+		dir := filepath.Join(*root, "sql", engine)
+
+		engineReport, err := querylint.LintDir(engine, filepath.Join(dir, "queries"), filepath.Join(dir, "schema"))
+		if err != nil {
+			return fmt.Errorf("engine=%v: %w", engine, err)
+		}
+
+		report.Findings = append(report.Findings, engineReport.Findings...)
+	}
+
+	err = printReport(stdout, report, *asJSON)
+	if err != nil {
+		return err
+	}
+
+	failing := 0
+	for _, severity := range severities[:threshold+1] {
+		failing += report.Count(severity)
+	}
+
+	if failing > 0 {
+		return fmt.Errorf("findings=%v: %w", failing, errFindings)
+	}
+
+	return nil
+}
+
+func printReport(out io.Writer, report *querylint.Report, asJSON bool) error {
+	if asJSON {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+
+		err := encoder.Encode(report)
+		if err != nil {
+			return fmt.Errorf("encode report: %w", err)
+		}
+
+		return nil
+	}
+
+	for _, finding := range report.Findings {
+		fmt.Fprintf(out, "%s/%s\n", finding.Engine, finding)
+	}
+
+	fmt.Fprintf(out, "%d errors, %d warnings, %d info\n",
+		report.Count(querylint.SeverityError), report.Count(querylint.SeverityWarning), report.Count(querylint.SeverityInfo))
+
+	return nil
+}
@@ -0,0 +1,169 @@
+// Command replay rebuilds projections by streaming exported domain events.
+//
+// Usage:
+//
+//	replay -events events.jsonl -projections audit,event_counts -checkpoints replay.json
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+	"github.com/LarsArtmann/template-sqlc/internal/replay"
+)
+
+var errUnknownProjection = errors.New("unknown projection")
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	err := run(ctx, os.Args[1:], os.Stdout, os.Stderr)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "replay: %v\n", err)
+		stop()
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("replay", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	eventsPath := flags.String("events", "", "path to newline-delimited JSON events (required)")
+	projectionNames := flags.String("projections", "event_counts", "comma-separated projections: audit, event_counts")
+	checkpointPath := flags.String("checkpoints", "replay-checkpoints.json", "checkpoint file used to resume replays")
+	auditPath := flags.String("audit-out", "", "audit view output file (default stdout)")
+	batchSize := flags.Int("batch", replay.DefaultBatchSize, "events read per batch")
+	fromStart := flags.Bool("from-start", false, "reset projections and ignore checkpoints")
+	dryRun := flags.Bool("dry-run", false, "count events without applying them or saving checkpoints")
+
+	err := flags.Parse(args)
+	if err != nil {
+		return fmt.Errorf("parse flags: %w", err)
+	}
+
+	if *eventsPath == "" {
+		flags.Usage()
+
+		return fmt.Errorf("events: %w", flag.ErrHelp)
+	}
+
+	store, err := loadEvents(ctx, *eventsPath)
+	if err != nil {
+		return err
+	}
+
+	auditOut := stdout
+	if *auditPath != "" && !*dryRun {
+		file, err := os.Create(*auditPath)
+		if err != nil {
+			return fmt.Errorf("create audit output path=%s: %w", *auditPath, err)
+		}
+		defer func() { _ = file.Close() }()
+
+		auditOut = file
+	}
+
+	counts := replay.NewEventCountProjection()
+
+	projections, err := selectProjections(*projectionNames, map[string]replay.Projection{
+		"audit":        replay.NewAuditProjection(auditOut),
+		"event_counts": counts,
+	})
+	if err != nil {
+		return err
+	}
+
+	replayer, err := replay.NewReplayer(store, replay.NewFileCheckpointStore(*checkpointPath), projections...)
+	if err != nil {
+		return fmt.Errorf("create replayer: %w", err)
+	}
+
+	report, err := replayer.Run(ctx, replay.Options{
+		BatchSize: *batchSize,
+		FromStart: *fromStart,
+		DryRun:    *dryRun,
+		Progress: func(p replay.Progress) {
+			fmt.Fprintf(
+				stderr, "replayed %d events (position %d, %s)\n",
+				p.Processed, p.Position, p.Elapsed.Round(time.Millisecond),
+			)
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("replay: %w", err)
+	}
+
+	printReport(stderr, report, counts)
+
+	return nil
+}
+
+func loadEvents(ctx context.Context, path string) (*events.InMemoryEventStore, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open events path=%s: %w", path, err)
+	}
+	defer func() { _ = file.Close() }()
+
+	store, err := replay.LoadEvents(ctx, file)
+	if err != nil {
+		return nil, fmt.Errorf("load events path=%s: %w", path, err)
+	}
+
+	return store, nil
+}
+
+func selectProjections(names string, available map[string]replay.Projection) ([]replay.Projection, error) {
+	selected := make([]replay.Projection, 0, len(available))
+
+	for name := range strings.SplitSeq(names, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		projection, ok := available[name]
+		if !ok {
+			return nil, fmt.Errorf("projection=%s: %w", name, errUnknownProjection)
+		}
+
+		selected = append(selected, projection)
+	}
+
+	return selected, nil
+}
+
+func printReport(out io.Writer, report *replay.Report, counts *replay.EventCountProjection) {
+	mode := "applied"
+	if report.DryRun {
+		mode = "would apply"
+	}
+
+	fmt.Fprintf(out, "processed %d events in %s\n", report.Processed, report.Duration.Round(time.Millisecond))
+
+	for name, applied := range report.Applied {
+		fmt.Fprintf(out, "  %s: %s %d events, checkpoint %d\n", name, mode, applied, report.Checkpoints[name])
+	}
+
+	if report.DryRun {
+		return
+	}
+
+	if _, ok := report.Applied[counts.Name()]; !ok {
+		return
+	}
+
+	for eventType, count := range counts.Counts() {
+		fmt.Fprintf(out, "  %s: %d\n", eventType, count)
+	}
+}
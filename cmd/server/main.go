@@ -0,0 +1,88 @@
+// Command server runs the application: GraphQL over HTTP, the user service
+// over gRPC, and metrics, health and profiling endpoints, all backed by one
+// database engine.
+//
+// Settings come from a YAML file, the environment and flags, as described
+// in pkg/config. Engines are compiled in with their build tag:
+//
+//	go build -tags postgres ./cmd/server
+//	DATABASE_DRIVER=postgres DATABASE_POSTGRES_DSN=postgres://localhost/app server
+//
+// Usage:
+//
+//	server -config config/app.yaml
+//	server -driver sqlite -sqlite-dsn app.db
+//	server -http-addr :8080 -grpc-addr :9090 -metrics-addr :9091 -profiling
+//	server -events-backend nats -events-url nats://localhost:4222
+//	server -job-workers 0 -shutdown-timeout 30s
+//
+// Personal data in published events and logs is redacted as configured by
+// the redaction settings; -redaction-mode none keeps it.
+//
+// SIGINT and SIGTERM stop the servers gracefully, waiting up to the
+// shutdown timeout for in-flight requests and jobs.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/LarsArtmann/template-sqlc/internal/monitoring"
+	"github.com/LarsArtmann/template-sqlc/internal/server"
+	"github.com/LarsArtmann/template-sqlc/pkg/config"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	err := run(ctx, os.Args[1:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "server: %v\n", err)
+		stop()
+		os.Exit(1)
+	}
+}
+
+// run starts the application and stops it once ctx is done.
+func run(ctx context.Context, args []string) error {
+	cfg, err := config.Load(args, os.Getenv)
+	if errors.Is(err, flag.ErrHelp) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	slog.SetDefault(slog.New(monitoring.NewRedactingHandler(
+		slog.NewTextHandler(os.Stderr, nil),
+		cfg.Redaction.Policy(),
+	)))
+	slog.Info("loaded configuration", "config", cfg.String())
+
+	app := server.New(cfg)
+
+	err = app.Start(ctx)
+	if err != nil {
+		return fmt.Errorf("start: %w", err)
+	}
+
+	<-ctx.Done()
+
+	stopCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancel()
+
+	err = app.Stop(stopCtx)
+	if err != nil {
+		return fmt.Errorf("stop: %w", err)
+	}
+
+	return nil
+}
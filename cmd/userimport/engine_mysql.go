@@ -0,0 +1,16 @@
+//go:build mysql
+
+package main
+
+import (
+	mysqladapter "github.com/LarsArtmann/template-sqlc/internal/adapters/mysql"
+	"github.com/LarsArtmann/template-sqlc/pkg/db"
+	_ "github.com/go-sql-driver/mysql"
+)
+
+// mysqlEngine creates the MySQL user repository.
+func mysqlEngine() engine {
+	return func(pool *db.Pool) *store {
+		return &store{pool: pool, users: mysqladapter.NewUserRepository(pool.SQL())}
+	}
+}
@@ -0,0 +1,15 @@
+//go:build postgres
+
+package main
+
+import (
+	postgresadapter "github.com/LarsArtmann/template-sqlc/internal/adapters/postgres"
+	"github.com/LarsArtmann/template-sqlc/pkg/db"
+)
+
+// postgresEngine creates the PostgreSQL user repository.
+func postgresEngine() engine {
+	return func(pool *db.Pool) *store {
+		return &store{pool: pool, users: postgresadapter.NewUserRepository(pool.PGX())}
+	}
+}
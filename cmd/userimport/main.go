@@ -0,0 +1,37 @@
+// Command userimport bulk imports users from CSV, JSON or NDJSON files and
+// exports them in the same formats, directly through the repositories.
+//
+// It connects like usersctl: the engine and data source name come from
+// DATABASE_DRIVER and DATABASE_DSN, or from the --driver and --dsn flags,
+// and engines are compiled in with their build tag. The format follows the
+// file extension unless --format is set; "-" reads stdin or writes stdout.
+//
+// Usage:
+//
+//	userimport import users.csv --dry-run
+//	userimport import users.ndjson --on-duplicate update --batch 1000
+//	userimport export users.json --status active --fields email,username,role
+//	userimport export - --format ndjson --mask mask
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	cmd := newRootCommand()
+	cmd.SetArgs(os.Args[1:])
+
+	err := cmd.ExecuteContext(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "userimport: %v\n", err)
+		stop()
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,281 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+	"github.com/LarsArtmann/template-sqlc/internal/userio"
+	"github.com/LarsArtmann/template-sqlc/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+// stdio names stdin or stdout instead of a file.
+const stdio = "-"
+
+// app is the state shared by the commands.
+type app struct {
+	settings settings
+	format   string
+}
+
+// newRootCommand creates the userimport command tree.
+func newRootCommand() *cobra.Command {
+	a := &app{settings: defaultSettings()}
+
+	root := &cobra.Command{
+		Use:           "userimport",
+		Short:         "Import and export users as CSV, JSON or NDJSON",
+		SilenceErrors: true,
+		SilenceUsage:  true,
+	}
+
+	flags := root.PersistentFlags()
+	flags.StringVar(&a.settings.driver, "driver", a.settings.driver,
+		"engine: sqlite, postgres or mysql (env "+config.DriverEnv+")")
+	flags.StringVar(&a.settings.dsn, "dsn", a.settings.dsn, "data source name (env "+config.DSNEnv+")")
+	flags.StringVar(&a.format, "format", "", "file format: csv, json or ndjson (default from the file extension)")
+
+	root.AddCommand(
+		newImportCommand(a),
+		newExportCommand(a),
+	)
+
+	return root
+}
+
+// run opens the store for the duration of fn.
+func (a *app) run(cmd *cobra.Command, fn func(ctx context.Context, s *store) error) (err error) {
+	s, err := openStore(cmd.Context(), a.settings)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		closeErr := s.Close()
+		if err == nil {
+			err = closeErr
+		}
+	}()
+
+	return fn(cmd.Context(), s)
+}
+
+// formatOf returns the --format flag, or the format of path.
+func (a *app) formatOf(path string) (userio.Format, error) {
+	if a.format == "" {
+		return userio.FormatOf(path)
+	}
+
+	format := userio.Format(a.format)
+	if !format.IsValid() {
+		return "", fmt.Errorf("format=%v: %w", a.format, userio.ErrInvalidFormat)
+	}
+
+	return format, nil
+}
+
+func newImportCommand(a *app) *cobra.Command {
+	var (
+		opts   userio.ImportOptions
+		policy string
+		asJSON bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "import FILE",
+		Short: "Import users, reporting the records that were rejected",
+		Long: "Import users from FILE. Invalid records are rejected and reported while the import goes on.\n" +
+			"A record duplicates the stored users with its email address or username; --on-duplicate says\n" +
+			"what happens to it. Updates apply to the user with the email address and keep its empty fields.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := a.formatOf(args[0])
+			if err != nil {
+				return err
+			}
+
+			in, err := openInput(args[0])
+			if err != nil {
+				return err
+			}
+			defer func() { _ = in.Close() }()
+
+			decoder, err := userio.NewDecoder(in, format)
+			if err != nil {
+				return err
+			}
+
+			opts.OnDuplicate = userio.DuplicatePolicy(policy)
+			opts.Progress = func(p userio.ImportProgress) {
+				fmt.Fprintf(cmd.ErrOrStderr(), "processed %d records (%s)\n",
+					p.Processed, p.Elapsed.Round(time.Millisecond))
+			}
+
+			return a.run(cmd, func(ctx context.Context, s *store) error {
+				report, err := userio.NewImporter(s.users).Import(ctx, decoder, opts)
+				if report != nil {
+					printErr := printImportReport(cmd.OutOrStdout(), report, asJSON)
+					if err == nil {
+						err = printErr
+					}
+				}
+
+				return err
+			})
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&policy, "on-duplicate", string(userio.DuplicateSkip),
+		"what importing an existing user does: skip, update or fail")
+	flags.BoolVar(&opts.DryRun, "dry-run", false, "validate the records and look up duplicates without writing")
+	flags.IntVar(&opts.BatchSize, "batch", userio.DefaultImportBatchSize, "number of new users created per batch")
+	flags.BoolVar(&asJSON, "json", false, "print the report as JSON")
+
+	return cmd
+}
+
+func newExportCommand(a *app) *cobra.Command {
+	var (
+		opts                  userio.ExportOptions
+		fields, mask, hashKey string
+		statuses, roles, tags []string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "export FILE",
+		Short: "Export users, newest first",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var err error
+
+			opts.Format, err = a.formatOf(args[0])
+			if err != nil {
+				return err
+			}
+
+			opts.Fields, err = userio.ParseFields(fields)
+			if err != nil {
+				return err
+			}
+
+			opts.Filter = entities.UserFilter{TagsAny: tags}
+
+			for _, status := range statuses {
+				opts.Filter.Statuses = append(opts.Filter.Statuses, entities.UserStatus(status))
+			}
+
+			for _, role := range roles {
+				opts.Filter.Roles = append(opts.Filter.Roles, entities.UserRole(role))
+			}
+
+			opts.Redaction = events.RedactionPolicy{Mode: events.RedactionMode(mask), HashKey: []byte(hashKey)}
+			opts.Progress = func(p userio.ExportProgress) {
+				fmt.Fprintf(cmd.ErrOrStderr(), "exported %d users (%s)\n",
+					p.Exported, p.Elapsed.Round(time.Millisecond))
+			}
+
+			return a.run(cmd, func(ctx context.Context, s *store) error {
+				return export(ctx, userio.NewExporter(s.users), args[0], cmd.OutOrStdout(), opts)
+			})
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&fields, "fields", "", "comma-separated fields to export (default all but password_hash)")
+	flags.StringVar(&mask, "mask", string(events.RedactionNone),
+		"redaction of emails, usernames and names: none, mask or hash")
+	flags.StringVar(&hashKey, "hash-key", "", "secret key of --mask hash")
+	flags.StringSliceVar(&statuses, "status", nil, "only users with one of these statuses")
+	flags.StringSliceVar(&roles, "role", nil, "only users with one of these roles")
+	flags.StringSliceVar(&tags, "tag", nil, "only users with one of these tags")
+	flags.IntVar(&opts.PageSize, "page-size", userio.DefaultExportPageSize, "number of users read per page")
+
+	return cmd
+}
+
+// export writes the export to path, or to stdout for "-". A failed export
+// leaves no partial file behind.
+func export(ctx context.Context, exporter *userio.Exporter, path string, stdout io.Writer,
+	opts userio.ExportOptions,
+) (err error) {
+	if path == stdio {
+		_, err = exporter.Export(ctx, stdout, opts)
+
+		return err
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create export path=%s: %w", path, err)
+	}
+
+	defer func() {
+		closeErr := file.Close()
+		if err == nil {
+			err = closeErr
+		}
+
+		if err != nil {
+			_ = os.Remove(path)
+		}
+	}()
+
+	exported, err := exporter.Export(ctx, file, opts)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "exported %d users to %s\n", exported, path)
+
+	return nil
+}
+
+// openInput opens path, or stdin for "-".
+func openInput(path string) (io.ReadCloser, error) {
+	if path == stdio {
+		return io.NopCloser(os.Stdin), nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open import path=%s: %w", path, err)
+	}
+
+	return file, nil
+}
+
+func printImportReport(out io.Writer, report *userio.ImportReport, asJSON bool) error {
+	if asJSON {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+
+		err := encoder.Encode(report)
+		if err != nil {
+			return fmt.Errorf("encode report: %w", err)
+		}
+
+		return nil
+	}
+
+	mode := "created"
+	if report.DryRun {
+		mode = "would create"
+	}
+
+	fmt.Fprintf(out, "processed %d records in %s: %s %d, updated %d, skipped %d, rejected %d\n",
+		report.Processed, report.Duration.Round(time.Millisecond), mode,
+		report.Created, report.Updated, report.Skipped, len(report.Rejected))
+
+	for _, rejected := range report.Rejected {
+		fmt.Fprintf(out, "  %v\n", rejected)
+	}
+
+	return nil
+}
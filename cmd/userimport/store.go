@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+	"github.com/LarsArtmann/template-sqlc/pkg/config"
+	"github.com/LarsArtmann/template-sqlc/pkg/db"
+)
+
+// errEngineNotBuilt is returned for an engine whose build tag was not set.
+var errEngineNotBuilt = errors.New("engine not compiled in; rebuild with its build tag")
+
+// settings are the connection settings of a command.
+type settings struct {
+	driver string
+	dsn    string
+}
+
+// defaultSettings reads the connection settings from the environment.
+func defaultSettings() settings {
+	driver := os.Getenv(config.DriverEnv)
+	if driver == "" {
+		driver = string(db.DriverSQLite)
+	}
+
+	return settings{driver: driver, dsn: os.Getenv(config.DSNEnv)}
+}
+
+// store holds the user repository of an open connection pool.
+type store struct {
+	pool  *db.Pool
+	users repositories.UserRepository
+}
+
+// engine creates the repository of one engine over an open pool. It is nil
+// for engines compiled without their build tag.
+type engine func(pool *db.Pool) *store
+
+// openStore connects to the database and creates the repository of its engine.
+func openStore(ctx context.Context, cfg settings) (*store, error) {
+	engines := map[db.Driver]engine{
+		db.DriverSQLite:   sqliteEngine(),
+		db.DriverPostgres: postgresEngine(),
+		db.DriverMySQL:    mysqlEngine(),
+	}
+
+	driver := db.Driver(cfg.driver)
+
+	newStore, ok := engines[driver]
+	if !ok {
+		return nil, fmt.Errorf("driver=%v: %w", cfg.driver, db.ErrUnsupportedDriver)
+	}
+
+	if newStore == nil {
+		return nil, fmt.Errorf("driver=%v: %w", cfg.driver, errEngineNotBuilt)
+	}
+
+	pool, err := db.Open(ctx, db.Config{Driver: driver, DSN: cfg.dsn})
+	if err != nil {
+		return nil, err
+	}
+
+	return newStore(pool), nil
+}
+
+// Close closes the connection pool.
+func (s *store) Close() error {
+	return s.pool.Close()
+}
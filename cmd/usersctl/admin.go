@@ -0,0 +1,225 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/pkg/fixtures"
+	"github.com/LarsArtmann/template-sqlc/pkg/passwords"
+	"github.com/LarsArtmann/template-sqlc/pkg/seed"
+	"github.com/spf13/cobra"
+)
+
+const defaultSeedUsers = 25
+
+func newRevokeSessionsCommand(a *app) *cobra.Command {
+	return &cobra.Command{
+		Use:   "revoke-sessions USER",
+		Short: "Deactivate every session of a user given by ID, email or username",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return a.run(cmd, func(ctx context.Context, s *store) error {
+				user, err := s.lookupUser(ctx, args[0])
+				if err != nil {
+					return fmt.Errorf("user=%v: %w", args[0], err)
+				}
+
+				active, err := s.sessions.GetActiveSessions(ctx, user.ID())
+				if err != nil {
+					return fmt.Errorf("count sessions user=%v: %w", args[0], err)
+				}
+
+				err = s.sessions.DeactivateByUserID(ctx, user.ID())
+				if err != nil {
+					return fmt.Errorf("revoke sessions user=%v: %w", args[0], err)
+				}
+
+				result := struct {
+					UserID  int64 `json:"userId"`
+					Revoked int64 `json:"revoked"`
+				}{user.ID().Int64(), active}
+
+				return a.print(cmd.OutOrStdout(), result, func(w io.Writer) {
+					fmt.Fprintf(w, "revoked %d active sessions of user %d\n", result.Revoked, result.UserID)
+				})
+			})
+		},
+	}
+}
+
+func newStatsCommand(a *app) *cobra.Command {
+	var withSessions bool
+
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Show user statistics",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return a.run(cmd, func(ctx context.Context, s *store) error {
+				users, err := s.users.GetStats(ctx)
+				if err != nil {
+					return fmt.Errorf("user stats: %w", err)
+				}
+
+				stats := struct {
+					Users    *entities.UserStats    `json:"users"`
+					Sessions *entities.SessionStats `json:"sessions,omitempty"`
+				}{Users: users}
+
+				if withSessions {
+					stats.Sessions, err = s.sessions.GetSessionStats(ctx)
+					if err != nil {
+						return fmt.Errorf("session stats: %w", err)
+					}
+				}
+
+				return a.print(cmd.OutOrStdout(), stats, func(w io.Writer) {
+					fmt.Fprintf(w, "users\t%d\n", users.TotalUsers)
+					fmt.Fprintf(w, "active\t%d\t(%.1f%%)\n", users.ActiveUsers, users.ActivePercentage)
+					fmt.Fprintf(w, "inactive\t%d\n", users.InactiveUsers)
+					fmt.Fprintf(w, "suspended\t%d\n", users.SuspendedUsers)
+					fmt.Fprintf(w, "verified\t%d\t(%.1f%%)\n", users.VerifiedUsers, users.VerificationRate)
+					fmt.Fprintf(w, "logged in\t%d\n", users.UsersWithLogins)
+					fmt.Fprintf(w, "new in 7 days\t%d\n", users.NewUsers7d)
+					fmt.Fprintf(w, "new in 30 days\t%d\n", users.NewUsers30d)
+
+					if stats.Sessions != nil {
+						fmt.Fprintf(w, "sessions\t%d\n", stats.Sessions.TotalSessions)
+						fmt.Fprintf(w, "active sessions\t%d\n", stats.Sessions.ActiveSessions)
+						fmt.Fprintf(w, "expired sessions\t%d\n", stats.Sessions.ExpiredSessions)
+					}
+				})
+			})
+		},
+	}
+
+	cmd.Flags().BoolVar(&withSessions, "sessions", false, "include session statistics")
+
+	return cmd
+}
+
+func newSeedCommand(a *app) *cobra.Command {
+	var (
+		prefix, password string
+		users, sessions  int
+		batchSize        int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "seed",
+		Short: "Insert deterministic demo users and sessions",
+		Long: "Insert demo users named <prefix>0001, <prefix>0002 and so on, with emails at example.com.\n" +
+			"Seeding the same prefix twice fails on the duplicate users.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			hash, err := passwords.NewBcryptHasher(passwords.DefaultBcryptCost).Hash(password)
+			if err != nil {
+				return fmt.Errorf("hash password: %w", err)
+			}
+
+			return a.run(cmd, func(ctx context.Context, s *store) error {
+				seeder := fixtures.NewSeeder(s.users, fixtures.WithSessions(s.sessions), fixtures.WithBatchSize(batchSize))
+
+				seeded, err := seeder.GenerateUsers(ctx, prefix, users, func(_ int, builder *fixtures.UserBuilder) {
+					builder.WithPassword(entities.PasswordHash(hash)).Verified().WithTags("demo")
+				})
+				if err != nil {
+					return err
+				}
+
+				builders := make([]*fixtures.SessionBuilder, 0, len(seeded)*sessions)
+
+				for _, user := range seeded {
+					for i := range sessions {
+						builders = append(builders, fixtures.Session(user).Named(fmt.Sprintf("session%d", i+1)))
+					}
+				}
+
+				created, err := seeder.Sessions(ctx, builders...)
+				if err != nil {
+					return err
+				}
+
+				result := struct {
+					Users    int `json:"users"`
+					Sessions int `json:"sessions"`
+				}{len(seeded), len(created)}
+
+				return a.print(cmd.OutOrStdout(), result, func(w io.Writer) {
+					fmt.Fprintf(w, "seeded %d users and %d sessions\n", result.Users, result.Sessions)
+				})
+			})
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&prefix, "prefix", "demo", "username prefix of the demo users")
+	flags.StringVar(&password, "password", "demo-password", "password of every demo user")
+	flags.IntVar(&users, "users", defaultSeedUsers, "number of users")
+	flags.IntVar(&sessions, "sessions", 0, "active sessions per user")
+	flags.IntVar(&batchSize, "batch", 0, "insert users in batches of this size; 0 inserts them one by one")
+
+	return cmd
+}
+
+func newDemoCommand(a *app) *cobra.Command {
+	var (
+		password  string
+		volume    seed.Volume
+		seedValue uint64
+		batchSize int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "demo",
+		Short: "Insert a realistic demo dataset of users, sessions and login histories",
+		Long: "Insert users with realistic names, statuses, roles, tags and signup dates, sessions of the\n" +
+			"active users and their login histories. The same --seed generates the same dataset; a database\n" +
+			"holds one dataset per seed.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			hash, err := passwords.NewBcryptHasher(passwords.DefaultBcryptCost).Hash(password)
+			if err != nil {
+				return fmt.Errorf("hash password: %w", err)
+			}
+
+			return a.run(cmd, func(ctx context.Context, s *store) error {
+				generator := seed.NewGenerator(s.users,
+					seed.WithSessions(s.sessions),
+					seed.WithLoginHistory(s.logins),
+					seed.WithSeed(seedValue),
+					seed.WithBatchSize(batchSize),
+					seed.WithPassword(entities.PasswordHash(hash)),
+				)
+
+				dataset, err := generator.Generate(ctx, volume)
+				if err != nil {
+					return err
+				}
+
+				result := struct {
+					Users    int `json:"users"`
+					Sessions int `json:"sessions"`
+					Logins   int `json:"logins"`
+				}{len(dataset.Users), len(dataset.Sessions), len(dataset.Logins)}
+
+				return a.print(cmd.OutOrStdout(), result, func(w io.Writer) {
+					fmt.Fprintf(w, "generated %d users, %d sessions and %d login attempts\n",
+						result.Users, result.Sessions, result.Logins)
+				})
+			})
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&password, "password", "demo-password", "password of every demo user")
+	flags.IntVar(&volume.Users, "users", defaultSeedUsers, "number of users")
+	flags.IntVar(&volume.SessionsPerUser, "sessions", 0, "sessions per active user")
+	flags.IntVar(&volume.LoginsPerUser, "logins", 0, "login attempts per user")
+	flags.Uint64Var(&seedValue, "seed", seed.DefaultSeed, "seed the dataset derives from")
+	flags.IntVar(&batchSize, "batch", seed.DefaultBatchSize, "insert users in batches of this size")
+
+	return cmd
+}
@@ -0,0 +1,22 @@
+//go:build mysql
+
+package main
+
+import (
+	mysqladapter "github.com/LarsArtmann/template-sqlc/internal/adapters/mysql"
+	"github.com/LarsArtmann/template-sqlc/pkg/db"
+	_ "github.com/go-sql-driver/mysql"
+)
+
+// mysqlEngine creates the MySQL repositories.
+func mysqlEngine() engine {
+	return func(pool *db.Pool) *store {
+		return &store{
+			pool:     pool,
+			users:    mysqladapter.NewUserRepository(pool.SQL()),
+			sessions: mysqladapter.NewSessionRepository(pool.SQL()),
+			logins:   mysqladapter.NewLoginHistoryRepository(pool.SQL()),
+			history:  mysqladapter.NewUserHistoryRepository(pool.SQL()),
+		}
+	}
+}
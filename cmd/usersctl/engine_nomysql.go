@@ -0,0 +1,6 @@
+//go:build !mysql
+
+package main
+
+// mysqlEngine is nil without the mysql build tag.
+func mysqlEngine() engine { return nil }
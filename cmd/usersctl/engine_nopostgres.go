@@ -0,0 +1,6 @@
+//go:build !postgres
+
+package main
+
+// postgresEngine is nil without the postgres build tag.
+func postgresEngine() engine { return nil }
@@ -0,0 +1,21 @@
+//go:build postgres
+
+package main
+
+import (
+	postgresadapter "github.com/LarsArtmann/template-sqlc/internal/adapters/postgres"
+	"github.com/LarsArtmann/template-sqlc/pkg/db"
+)
+
+// postgresEngine creates the PostgreSQL repositories.
+func postgresEngine() engine {
+	return func(pool *db.Pool) *store {
+		return &store{
+			pool:     pool,
+			users:    postgresadapter.NewUserRepository(pool.PGX()),
+			sessions: postgresadapter.NewSessionRepository(pool.PGX()),
+			logins:   postgresadapter.NewLoginHistoryRepository(pool.PGX()),
+			history:  postgresadapter.NewUserHistoryRepository(pool.PGX()),
+		}
+	}
+}
@@ -0,0 +1,22 @@
+//go:build sqlite
+
+package main
+
+import (
+	sqliteadapter "github.com/LarsArtmann/template-sqlc/internal/adapters/sqlite"
+	"github.com/LarsArtmann/template-sqlc/pkg/db"
+	_ "modernc.org/sqlite"
+)
+
+// sqliteEngine creates the SQLite repositories.
+func sqliteEngine() engine {
+	return func(pool *db.Pool) *store {
+		return &store{
+			pool:     pool,
+			users:    sqliteadapter.NewUserRepository(pool.SQL()),
+			sessions: sqliteadapter.NewSessionRepository(pool.SQL()),
+			logins:   sqliteadapter.NewLoginHistoryRepository(pool.SQL()),
+			history:  sqliteadapter.NewUserHistoryRepository(pool.SQL()),
+		}
+	}
+}
@@ -0,0 +1,43 @@
+// Command usersctl manages users directly through the repositories, without
+// a running server.
+//
+// It connects with the same settings as the server: the engine and data
+// source name come from DATABASE_DRIVER and DATABASE_DSN, or from the
+// --driver and --dsn flags. Engines are compiled in with their build tag:
+//
+//	go build -tags postgres ./cmd/usersctl
+//	DATABASE_DRIVER=postgres DATABASE_DSN=postgres://localhost/app usersctl list --status active
+//
+// Usage:
+//
+//	usersctl create --email ada@example.com --username ada --password secret --first-name Ada
+//	usersctl list --role admin --limit 20
+//	usersctl search lovelace
+//	usersctl set-role ada admin
+//	usersctl set-status 42 suspended
+//	usersctl revoke-sessions ada@example.com
+//	usersctl stats --json
+//	usersctl seed --users 100 --sessions 2
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	cmd := newRootCommand()
+	cmd.SetArgs(os.Args[1:])
+
+	err := cmd.ExecuteContext(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "usersctl: %v\n", err)
+		stop()
+		os.Exit(1)
+	}
+}
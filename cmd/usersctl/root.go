@@ -0,0 +1,166 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+// app is the state shared by the commands.
+type app struct {
+	settings settings
+	asJSON   bool
+}
+
+// newRootCommand creates the usersctl command tree.
+func newRootCommand() *cobra.Command {
+	a := &app{settings: defaultSettings()}
+
+	root := &cobra.Command{
+		Use:           "usersctl",
+		Short:         "Manage users directly through the repositories",
+		SilenceErrors: true,
+		SilenceUsage:  true,
+	}
+
+	flags := root.PersistentFlags()
+	flags.StringVar(&a.settings.driver, "driver", a.settings.driver,
+		"engine: sqlite, postgres or mysql (env "+config.DriverEnv+")")
+	flags.StringVar(&a.settings.dsn, "dsn", a.settings.dsn, "data source name (env "+config.DSNEnv+")")
+	flags.BoolVar(&a.asJSON, "json", false, "print results as JSON")
+
+	root.AddCommand(
+		newCreateCommand(a),
+		newListCommand(a),
+		newSearchCommand(a),
+		newSetRoleCommand(a),
+		newSetStatusCommand(a),
+		newHistoryCommand(a),
+		newRevokeSessionsCommand(a),
+		newStatsCommand(a),
+		newSeedCommand(a),
+		newDemoCommand(a),
+	)
+
+	return root
+}
+
+// run opens the store for the duration of fn.
+func (a *app) run(cmd *cobra.Command, fn func(ctx context.Context, s *store) error) (err error) {
+	s, err := openStore(cmd.Context(), a.settings)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		closeErr := s.Close()
+		if err == nil {
+			err = closeErr
+		}
+	}()
+
+	return fn(cmd.Context(), s)
+}
+
+// print writes value as JSON with --json, and with table otherwise.
+func (a *app) print(out io.Writer, value any, table func(w io.Writer)) error {
+	if a.asJSON {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+
+		err := encoder.Encode(value)
+		if err != nil {
+			return fmt.Errorf("encode output: %w", err)
+		}
+
+		return nil
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	table(w)
+
+	err := w.Flush()
+	if err != nil {
+		return fmt.Errorf("write output: %w", err)
+	}
+
+	return nil
+}
+
+// userView is the printed form of a user.
+type userView struct {
+	ID        int64     `json:"id"`
+	Username  string    `json:"username"`
+	Email     string    `json:"email"`
+	FirstName string    `json:"firstName"`
+	LastName  string    `json:"lastName"`
+	Role      string    `json:"role"`
+	Status    string    `json:"status"`
+	Verified  bool      `json:"verified"`
+	Tags      []string  `json:"tags"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func newUserViews(users []*entities.User) []userView {
+	views := make([]userView, 0, len(users))
+
+	for _, user := range users {
+		views = append(views, userView{
+			ID:        user.ID().Int64(),
+			Username:  user.Username().String(),
+			Email:     user.Email().String(),
+			FirstName: user.FirstName().String(),
+			LastName:  user.LastName().String(),
+			Role:      user.Role().String(),
+			Status:    user.Status().String(),
+			Verified:  user.IsVerified(),
+			Tags:      user.Tags(),
+			CreatedAt: user.CreatedAt(),
+		})
+	}
+
+	return views
+}
+
+// printUsers prints users as a table or JSON array.
+func (a *app) printUsers(out io.Writer, users []*entities.User) error {
+	views := newUserViews(users)
+
+	return a.print(out, views, func(w io.Writer) {
+		fmt.Fprintln(w, "ID\tUSERNAME\tEMAIL\tNAME\tROLE\tSTATUS\tVERIFIED\tTAGS\tCREATED")
+
+		for _, user := range views {
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%t\t%s\t%s\n",
+				user.ID, user.Username, user.Email, strings.TrimSpace(user.FirstName+" "+user.LastName),
+				user.Role, user.Status, user.Verified, strings.Join(user.Tags, ","),
+				user.CreatedAt.Format(time.RFC3339))
+		}
+	})
+}
+
+// printVersions prints user versions as a table or JSON array.
+func (a *app) printVersions(out io.Writer, versions []*entities.UserSnapshot) error {
+	return a.print(out, versions, func(w io.Writer) {
+		fmt.Fprintln(w, "OPERATION\tVALID FROM\tVALID TO\tUSERNAME\tEMAIL\tNAME\tROLE\tSTATUS")
+
+		for _, version := range versions {
+			validTo := "-"
+			if version.ValidTo != nil {
+				validTo = version.ValidTo.Format(time.RFC3339)
+			}
+
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s %s\t%s\t%s\n",
+				version.Operation, version.ValidFrom.Format(time.RFC3339), validTo,
+				version.Username, version.Email, version.FirstName, version.LastName,
+				version.Role, version.Status)
+		}
+	})
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+	"github.com/LarsArtmann/template-sqlc/pkg/config"
+	"github.com/LarsArtmann/template-sqlc/pkg/db"
+)
+
+// errEngineNotBuilt is returned for an engine whose build tag was not set.
+var errEngineNotBuilt = errors.New("engine not compiled in; rebuild with its build tag")
+
+// settings are the connection settings of a command.
+type settings struct {
+	driver string
+	dsn    string
+}
+
+// defaultSettings reads the connection settings from the environment.
+func defaultSettings() settings {
+	driver := os.Getenv(config.DriverEnv)
+	if driver == "" {
+		driver = string(db.DriverSQLite)
+	}
+
+	return settings{driver: driver, dsn: os.Getenv(config.DSNEnv)}
+}
+
+// store holds the repositories of an open connection pool.
+type store struct {
+	pool     *db.Pool
+	users    repositories.UserRepository
+	sessions repositories.SessionRepository
+	logins   repositories.LoginHistoryRepository
+	history  repositories.UserHistoryRepository
+}
+
+// engine creates the repositories of one engine over an open pool. It is
+// nil for engines compiled without their build tag.
+type engine func(pool *db.Pool) *store
+
+// openStore connects to the database and creates the repositories of its engine.
+func openStore(ctx context.Context, cfg settings) (*store, error) {
+	engines := map[db.Driver]engine{
+		db.DriverSQLite:   sqliteEngine(),
+		db.DriverPostgres: postgresEngine(),
+		db.DriverMySQL:    mysqlEngine(),
+	}
+
+	driver := db.Driver(cfg.driver)
+
+	newStore, ok := engines[driver]
+	if !ok {
+		return nil, fmt.Errorf("driver=%v: %w", cfg.driver, db.ErrUnsupportedDriver)
+	}
+
+	if newStore == nil {
+		return nil, fmt.Errorf("driver=%v: %w", cfg.driver, errEngineNotBuilt)
+	}
+
+	pool, err := db.Open(ctx, db.Config{Driver: driver, DSN: cfg.dsn})
+	if err != nil {
+		return nil, err
+	}
+
+	return newStore(pool), nil
+}
+
+// Close closes the connection pool.
+func (s *store) Close() error {
+	return s.pool.Close()
+}
+
+// lookupUser finds a user by ID, email or username.
+func (s *store) lookupUser(ctx context.Context, ref string) (*entities.User, error) {
+	id, err := strconv.ParseInt(ref, 10, 64)
+	if err == nil {
+		return s.users.GetByID(ctx, entities.UserID(id))
+	}
+
+	if strings.Contains(ref, "@") {
+		email, err := entities.NewEmail(ref)
+		if err != nil {
+			return nil, err
+		}
+
+		return s.users.GetByEmail(ctx, email)
+	}
+
+	username, err := entities.NewUsername(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.users.GetByUsername(ctx, username)
+}
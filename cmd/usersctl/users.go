@@ -0,0 +1,332 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/services"
+	"github.com/LarsArtmann/template-sqlc/pkg/passwords"
+	"github.com/spf13/cobra"
+)
+
+const defaultListLimit = 50
+
+func newCreateCommand(a *app) *cobra.Command {
+	var (
+		email, username, password, firstName, lastName string
+		role, status                                   string
+		tags                                           []string
+		verified                                       bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a user",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			user, err := buildUser(email, username, password, firstName, lastName, role, status, tags)
+			if err != nil {
+				return err
+			}
+
+			return a.run(cmd, func(ctx context.Context, s *store) error {
+				err := s.users.Create(ctx, user)
+				if err != nil {
+					return fmt.Errorf("create user email=%v: %w", user.Email(), err)
+				}
+
+				if verified {
+					err = s.users.MarkVerified(ctx, user.ID())
+					if err != nil {
+						return fmt.Errorf("verify user id=%d: %w", user.ID(), err)
+					}
+
+					user.Verify()
+				}
+
+				return a.printUsers(cmd.OutOrStdout(), []*entities.User{user})
+			})
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&email, "email", "", "email address (required)")
+	flags.StringVar(&username, "username", "", "username (required)")
+	flags.StringVar(&password, "password", "", "password, stored as a bcrypt hash (required)")
+	flags.StringVar(&firstName, "first-name", "", "first name (required)")
+	flags.StringVar(&lastName, "last-name", "-", "last name")
+	flags.StringVar(&role, "role", entities.UserRoleUser.String(), "role: user, moderator or admin")
+	flags.StringVar(&status, "status", entities.UserStatusActive.String(),
+		"status: active, inactive, suspended or pending")
+	flags.StringSliceVar(&tags, "tag", nil, "tag; repeat for several")
+	flags.BoolVar(&verified, "verified", false, "mark the email as verified")
+
+	for _, name := range []string{"email", "username", "password", "first-name"} {
+		_ = cmd.MarkFlagRequired(name)
+	}
+
+	return cmd
+}
+
+// buildUser validates the create flags and hashes the password.
+func buildUser(
+	email, username, password, firstName, lastName, role, status string,
+	tags []string,
+) (*entities.User, error) {
+	validEmail, err := entities.NewEmail(email)
+	if err != nil {
+		return nil, err
+	}
+
+	validUsername, err := entities.NewUsername(username)
+	if err != nil {
+		return nil, err
+	}
+
+	validFirstName, err := entities.NewFirstName(firstName)
+	if err != nil {
+		return nil, err
+	}
+
+	validLastName, err := entities.NewLastName(lastName)
+	if err != nil {
+		return nil, err
+	}
+
+	hash, err := passwords.NewBcryptHasher(passwords.DefaultBcryptCost).Hash(password)
+	if err != nil {
+		return nil, fmt.Errorf("hash password: %w", err)
+	}
+
+	return entities.NewUser(
+		validEmail, validUsername, entities.PasswordHash(hash), validFirstName, validLastName,
+		entities.UserStatus(status), entities.UserRole(role), entities.NewUserMetadata(), tags,
+	)
+}
+
+func newListCommand(a *app) *cobra.Command {
+	var (
+		statuses, roles, tags []string
+		limit, offset         int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List users, newest first",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			filter := entities.UserFilter{TagsAny: tags}
+
+			for _, status := range statuses {
+				filter.Statuses = append(filter.Statuses, entities.UserStatus(status))
+			}
+
+			for _, role := range roles {
+				filter.Roles = append(filter.Roles, entities.UserRole(role))
+			}
+
+			err := filter.Validate()
+			if err != nil {
+				return err
+			}
+
+			return a.run(cmd, func(ctx context.Context, s *store) error {
+				users, err := s.users.List(ctx, filter, limit, offset)
+				if err != nil {
+					return fmt.Errorf("list users: %w", err)
+				}
+
+				return a.printUsers(cmd.OutOrStdout(), users)
+			})
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringSliceVar(&statuses, "status", nil, "only users with one of these statuses")
+	flags.StringSliceVar(&roles, "role", nil, "only users with one of these roles")
+	flags.StringSliceVar(&tags, "tag", nil, "only users with one of these tags")
+	flags.IntVar(&limit, "limit", defaultListLimit, "maximum number of users")
+	flags.IntVar(&offset, "offset", 0, "number of users to skip")
+
+	return cmd
+}
+
+func newSearchCommand(a *app) *cobra.Command {
+	var (
+		status string
+		limit  int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "search QUERY",
+		Short: "Search users by email, username and name, with facet counts",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return a.run(cmd, func(ctx context.Context, s *store) error {
+				query := strings.Join(args, " ")
+
+				result, err := s.users.SearchWithFacets(ctx, query, entities.UserStatus(status), limit)
+				if err != nil {
+					return fmt.Errorf("search users query=%q: %w", query, err)
+				}
+
+				if a.asJSON {
+					return a.print(cmd.OutOrStdout(), struct {
+						Users  []userView             `json:"users"`
+						Facets *entities.SearchFacets `json:"facets"`
+					}{newUserViews(result.Users), result.Facets}, nil)
+				}
+
+				err = a.printUsers(cmd.OutOrStdout(), result.Users)
+				if err != nil {
+					return err
+				}
+
+				return a.print(cmd.OutOrStdout(), nil, func(w io.Writer) {
+					fmt.Fprintln(w)
+					fmt.Fprintln(w, "FACET\tVALUE\tUSERS")
+
+					for _, value := range slices.Sorted(maps.Keys(result.Facets.ByStatus)) {
+						fmt.Fprintf(w, "status\t%s\t%d\n", value, result.Facets.ByStatus[value])
+					}
+
+					for _, value := range slices.Sorted(maps.Keys(result.Facets.ByRole)) {
+						fmt.Fprintf(w, "role\t%s\t%d\n", value, result.Facets.ByRole[value])
+					}
+
+					for _, tag := range result.Facets.TopTags {
+						fmt.Fprintf(w, "tag\t%s\t%d\n", tag.Tag, tag.Count)
+					}
+				})
+			})
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&status, "status", "", "only users with this status")
+	flags.IntVar(&limit, "limit", defaultListLimit, "maximum number of users")
+
+	return cmd
+}
+
+func newSetRoleCommand(a *app) *cobra.Command {
+	return &cobra.Command{
+		Use:   "set-role USER ROLE",
+		Short: "Change the role of a user given by ID, email or username",
+		Args:  cobra.ExactArgs(2), //nolint:mnd // USER and ROLE
+		RunE: func(cmd *cobra.Command, args []string) error {
+			role := entities.UserRole(args[1])
+
+			return a.run(cmd, func(ctx context.Context, s *store) error {
+				user, err := s.lookupUser(ctx, args[0])
+				if err != nil {
+					return fmt.Errorf("user=%v: %w", args[0], err)
+				}
+
+				err = user.ChangeRole(role)
+				if err != nil {
+					return fmt.Errorf("role=%v: %w", role, err)
+				}
+
+				err = s.users.ChangeRole(ctx, user.ID(), role)
+				if err != nil {
+					return fmt.Errorf("change role user=%v: %w", args[0], err)
+				}
+
+				return a.printUsers(cmd.OutOrStdout(), []*entities.User{user})
+			})
+		},
+	}
+}
+
+func newSetStatusCommand(a *app) *cobra.Command {
+	return &cobra.Command{
+		Use:   "set-status USER STATUS",
+		Short: "Change the status of a user given by ID, email or username",
+		Args:  cobra.ExactArgs(2), //nolint:mnd // USER and STATUS
+		RunE: func(cmd *cobra.Command, args []string) error {
+			status := entities.UserStatus(args[1])
+
+			return a.run(cmd, func(ctx context.Context, s *store) error {
+				user, err := s.lookupUser(ctx, args[0])
+				if err != nil {
+					return fmt.Errorf("user=%v: %w", args[0], err)
+				}
+
+				err = user.ChangeStatus(status)
+				if err != nil {
+					return fmt.Errorf("status=%v: %w", status, err)
+				}
+
+				err = s.users.ChangeStatus(ctx, user.ID(), status)
+				if err != nil {
+					return fmt.Errorf("change status user=%v: %w", args[0], err)
+				}
+
+				return a.printUsers(cmd.OutOrStdout(), []*entities.User{user})
+			})
+		},
+	}
+}
+
+func newHistoryCommand(a *app) *cobra.Command {
+	var (
+		asOf  string
+		limit int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "history USER",
+		Short: "Show the archived versions of a user given by ID, email or username, newest first",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var at time.Time
+
+			if asOf != "" {
+				var err error
+
+				at, err = time.Parse(time.RFC3339, asOf)
+				if err != nil {
+					return fmt.Errorf("as-of=%v: %w", asOf, err)
+				}
+			}
+
+			return a.run(cmd, func(ctx context.Context, s *store) error {
+				user, err := s.lookupUser(ctx, args[0])
+				if err != nil {
+					return fmt.Errorf("user=%v: %w", args[0], err)
+				}
+
+				history := services.NewUserHistoryService(s.history)
+
+				if at.IsZero() {
+					versions, err := history.ListVersions(ctx, user.ID(), limit)
+					if err != nil {
+						return err
+					}
+
+					return a.printVersions(cmd.OutOrStdout(), versions)
+				}
+
+				version, err := history.GetUserAsOf(ctx, user.ID(), at)
+				if err != nil {
+					return err
+				}
+
+				return a.printVersions(cmd.OutOrStdout(), []*entities.UserSnapshot{version})
+			})
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&asOf, "as-of", "", "only the version valid at this RFC 3339 time")
+	flags.IntVar(&limit, "limit", defaultListLimit, "maximum number of versions")
+
+	return cmd
+}
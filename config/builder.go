@@ -1,150 +1,145 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
 	"os"
-	"path/filepath"
 	"strings"
 
-	"gopkg.in/yaml.v3"
+	"github.com/LarsArtmann/template-sqlc/pkg/sqlcconfig"
 )
 
-// ConfigBuilder builds sqlc configurations from components.
-type ConfigBuilder struct {
-	baseDir   string
-	outputDir string
-}
-
-// NewConfigBuilder creates a new configuration builder.
-func NewConfigBuilder(baseDir, outputDir string) *ConfigBuilder {
-	return &ConfigBuilder{
-		baseDir:   baseDir,
-		outputDir: outputDir,
-	}
-}
-
-// BuildConfig builds a complete sqlc configuration for the specified databases.
-func (cb *ConfigBuilder) BuildConfig(databases []string) error {
-	// Load base configuration
-	baseConfig, err := cb.loadBaseConfig()
-	if err != nil {
-		return fmt.Errorf("failed to load base config: %w", err)
+func main() {
+	flags := flag.NewFlagSet("builder", flag.ExitOnError)
+	profile := flags.String("profile", "", "profile overlay: hobby, microservice, enterprise or turso")
+	strict := flags.Bool("strict", false, "fail when layers conflict")
+	verbose := flags.Bool("v", false, "print the values overridden by later layers")
+	output := flags.String("o", "sqlc.yaml", "output file")
+	migrate := flags.String("migrate", "", "migrate this version 1 config to version 2 instead of building")
+	contexts := flags.String("contexts", "", "YAML file of bounded contexts to generate as separate packages")
+	manifest := flags.String("manifest", "sqlc-contexts.json", "bounded context manifest written with -contexts")
+	flags.Usage = func() {
+		fmt.Println("Usage: go run . [-profile name] [-strict] [-v] [-o sqlc.yaml] [-contexts file] <databases...>")
+		fmt.Println("       go run . -migrate sqlc.v1.yaml [-o sqlc.yaml]")
+		fmt.Println("Example: go run . -profile microservice sqlite,postgres,mysql")
 	}
 
-	// Build configurations for each database
-	var configs []map[string]any
+	_ = flags.Parse(os.Args[1:])
 
-	for _, db := range databases {
-		dbConfig, err := cb.buildDatabaseConfig(db, baseConfig)
-		if err != nil {
-			return fmt.Errorf("failed to build %s config: %w", db, err)
-		}
+	if *migrate != "" {
+		migrateConfig(*migrate, *output)
 
-		configs = append(configs, dbConfig)
+		return
 	}
 
-	// Combine configurations
-	finalConfig := map[string]any{
-		"version": "2",
-		"rules":   baseConfig["rules"],
-		"plugins": baseConfig["plugins"],
-		"sql":     configs,
+	if flags.NArg() < 1 {
+		flags.Usage()
+		os.Exit(1)
 	}
 
-	// Write final configuration
-	return cb.writeConfig(finalConfig)
-}
+	// Parse databases from command line
+	var databases []string
 
-// loadBaseConfig loads the base configuration.
-func (cb *ConfigBuilder) loadBaseConfig() (map[string]any, error) {
-	basePath := filepath.Join(cb.baseDir, "base", "common.yaml")
+	for _, arg := range flags.Args() {
+		for _, db := range strings.Split(arg, ",") {
+			if db = strings.TrimSpace(db); db != "" {
+				databases = append(databases, db)
+			}
+		}
+	}
 
-	data, err := os.ReadFile(basePath)
-	if err != nil {
-		return nil, err
+	// Build configuration
+	builder := sqlcconfig.NewBuilder(os.DirFS("internal")).Databases(databases...).Profile(*profile)
+	if *strict {
+		builder.Strict()
 	}
 
-	var config map[string]any
-	if err := yaml.Unmarshal(data, &config); err != nil {
-		return nil, err
+	if *contexts != "" {
+		builder.Contexts(loadContexts(*contexts)...)
 	}
 
-	return config, nil
-}
+	err := builder.WriteFile(*output)
 
-// buildDatabaseConfig builds configuration for a specific database.
-func (cb *ConfigBuilder) buildDatabaseConfig(
-	db string,
-	baseConfig map[string]any,
-) (map[string]any, error) {
-	// Load database-specific configuration
-	dbPath := filepath.Join(cb.baseDir, "databases", db+".yaml")
+	for _, conflict := range builder.Conflicts() {
+		fmt.Printf("⚠️  conflict %s\n", conflict)
+	}
 
-	data, err := os.ReadFile(dbPath)
-	if err != nil {
-		return nil, err
+	if *verbose {
+		for _, change := range builder.Changes() {
+			fmt.Printf("override %s\n", change)
+		}
 	}
 
-	var dbConfig map[string]any
-	if err := yaml.Unmarshal(data, &dbConfig); err != nil {
-		return nil, err
+	var invalid sqlcconfig.ValidationErrors
+	if errors.As(err, &invalid) {
+		fmt.Printf("❌ %v\n", invalid)
+		os.Exit(1)
 	}
 
-	// Extract the SQL configuration
-	sqlConfigs, ok := dbConfig["sql"].([]any)
-	if !ok || len(sqlConfigs) == 0 {
-		return nil, fmt.Errorf("no sql configuration found for %s", db)
+	if err != nil {
+		fmt.Printf("Error building configuration: %v\n", err)
+		os.Exit(1)
 	}
 
-	// Return the first SQL configuration
-	sqlConfig, ok := sqlConfigs[0].(map[string]any)
-	if !ok {
-		return nil, fmt.Errorf("invalid sql configuration format for %s", db)
+	if *contexts != "" {
+		err = builder.WriteManifest(*manifest)
+		if err != nil {
+			fmt.Printf("Error writing manifest: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("Wrote %d context packages to %s\n", len(builder.Manifest().Contexts), *manifest)
 	}
 
-	return sqlConfig, nil
+	fmt.Println("✅ Configuration built successfully!")
+	fmt.Printf("Generated for databases: %v\n", databases)
 }
 
-// writeConfig writes the final configuration to file.
-func (cb *ConfigBuilder) writeConfig(config map[string]any) error {
-	// Ensure output directory exists
-	if err := os.MkdirAll(cb.outputDir, 0o755); err != nil {
-		return err
+// loadContexts reads the bounded contexts file.
+func loadContexts(name string) []sqlcconfig.BoundedContext {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		fmt.Printf("Error reading contexts: %v\n", err)
+		os.Exit(1)
 	}
 
-	// Write configuration
-	outputPath := filepath.Join(cb.outputDir, "sqlc.yaml")
-
-	data, err := yaml.Marshal(config)
+	contexts, err := sqlcconfig.ParseContexts(data)
 	if err != nil {
-		return err
+		fmt.Printf("Error reading contexts: %v\n", err)
+		os.Exit(1)
 	}
 
-	return os.WriteFile(outputPath, data, 0o644)
+	return contexts
 }
 
-func main() {
-	if len(os.Args) < 2 {
-		fmt.Println("Usage: go run builder.go <databases...>")
-		fmt.Println("Example: go run builder.go sqlite postgres mysql")
+// migrateConfig upgrades a version 1 config and prints what changed.
+func migrateConfig(input, output string) {
+	v1, err := os.ReadFile(input)
+	if err != nil {
+		fmt.Printf("Error reading configuration: %v\n", err)
 		os.Exit(1)
 	}
 
-	// Parse databases from command line
-	databases := strings.Split(os.Args[1], ",")
-	for i, db := range databases {
-		databases[i] = strings.TrimSpace(db)
+	v2, report, err := sqlcconfig.Migrate(v1)
+	if err != nil {
+		fmt.Printf("Error migrating configuration: %v\n", err)
+		os.Exit(1)
 	}
 
-	// Build configuration
-	builder := NewConfigBuilder("internal", ".")
+	fmt.Print(report)
 
-	err := builder.BuildConfig(databases)
+	err = os.WriteFile(output, v2, 0o644)
 	if err != nil {
-		fmt.Printf("Error building configuration: %v\n", err)
+		fmt.Printf("Error writing configuration: %v\n", err)
 		os.Exit(1)
 	}
 
-	fmt.Println("✅ Configuration built successfully!")
-	fmt.Printf("Generated for databases: %v\n", databases)
+	if len(report.Unmapped) > 0 {
+		fmt.Printf("⚠️  Migrated to %s; fix the %d keys marked ! by hand\n", output, len(report.Unmapped))
+
+		return
+	}
+
+	fmt.Printf("✅ Migrated to %s\n", output)
 }
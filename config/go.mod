@@ -1,5 +1,9 @@
 module config
 
-go 1.26.3
+go 1.26.4
 
-require gopkg.in/yaml.v3 v3.0.1
+require github.com/LarsArtmann/template-sqlc v0.0.0
+
+require go.yaml.in/yaml/v3 v3.0.5 // indirect
+
+replace github.com/LarsArtmann/template-sqlc => ../
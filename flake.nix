@@ -59,6 +59,9 @@
                 pkgs.gotools
                 pkgs.gofumpt
                 pkgs.sqlc
+                pkgs.buf
+                pkgs.protoc-gen-go
+                pkgs.protoc-gen-go-grpc
               ];
 
               GOWORK = "off";
@@ -0,0 +1,80 @@
+// Package cache provides a cache-aside UserRepository decorator with local
+// LRU and Redis backends.
+package cache
+
+import (
+	"context"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+)
+
+// Backend stores encoded values under string keys.
+// Implementations must be safe for concurrent use.
+type Backend interface {
+	// Get returns the value stored under key, or false if it is missing or expired.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+}
+
+// lruEntry is a cached value with its expiry.
+type lruEntry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+// LRUBackend is an in-process backend that evicts the least recently used
+// entries beyond its size. Each process has its own copy, so invalidations
+// made by other instances are only seen after the TTL expires.
+type LRUBackend struct {
+	entries *lru.Cache[string, lruEntry]
+	now     func() time.Time
+}
+
+// NewLRUBackend creates a local backend holding at most size entries.
+func NewLRUBackend(size int) (*LRUBackend, error) {
+	entries, err := lru.New[string, lruEntry](size)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LRUBackend{entries: entries, now: time.Now}, nil
+}
+
+// Get returns the unexpired value under key.
+func (b *LRUBackend) Get(_ context.Context, key string) ([]byte, bool, error) {
+	entry, ok := b.entries.Get(key)
+	if !ok {
+		return nil, false, nil
+	}
+
+	if !b.now().Before(entry.expiresAt) {
+		b.entries.Remove(key)
+
+		return nil, false, nil
+	}
+
+	return entry.value, true, nil
+}
+
+// Set stores value under key for ttl.
+func (b *LRUBackend) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	b.entries.Add(key, lruEntry{value: value, expiresAt: b.now().Add(ttl)})
+
+	return nil
+}
+
+// Delete removes keys.
+func (b *LRUBackend) Delete(_ context.Context, keys ...string) error {
+	for _, key := range keys {
+		b.entries.Remove(key)
+	}
+
+	return nil
+}
+
+// Len returns the number of cached entries, including expired ones not yet evicted.
+func (b *LRUBackend) Len() int {
+	return b.entries.Len()
+}
@@ -0,0 +1,61 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisBackend stores entries in Redis so that all instances share one cache
+// and see each other's invalidations. Cached users include password hashes;
+// the Redis deployment must be as trusted as the database.
+type RedisBackend struct {
+	client redis.Cmdable
+}
+
+// NewRedisBackend creates a backend on client, which may be a *redis.Client,
+// *redis.ClusterClient or any other redis.Cmdable.
+func NewRedisBackend(client redis.Cmdable) *RedisBackend {
+	return &RedisBackend{client: client}
+}
+
+// Get returns the value under key.
+func (b *RedisBackend) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := b.client.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+
+	if err != nil {
+		return nil, false, fmt.Errorf("redis get key=%v: %w", key, err)
+	}
+
+	return value, true, nil
+}
+
+// Set stores value under key for ttl.
+func (b *RedisBackend) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	err := b.client.Set(ctx, key, value, ttl).Err()
+	if err != nil {
+		return fmt.Errorf("redis set key=%v: %w", key, err)
+	}
+
+	return nil
+}
+
+// Delete removes keys.
+func (b *RedisBackend) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	err := b.client.Del(ctx, keys...).Err()
+	if err != nil {
+		return fmt.Errorf("redis del keys=%v: %w", keys, err)
+	}
+
+	return nil
+}
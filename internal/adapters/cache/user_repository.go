@@ -0,0 +1,381 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+const (
+	defaultTTL       = 5 * time.Minute
+	defaultKeyPrefix = "users:"
+)
+
+// UserRepository is a cache-aside decorator for single-user lookups.
+//
+// Users are cached under their ID; UUID, email and username keys only point
+// at the ID, and a pointed-to user whose field no longer matches is treated
+// as a miss. Writes therefore only need to drop the ID entry. Lists, searches,
+// credential checks and locking reads always go to the database. Password
+// hashes are never cached: users served from the cache carry an empty hash
+// in place of a usable one. Backend failures are logged and fall through to
+// the database.
+type UserRepository struct {
+	repositories.UserRepository
+
+	backend Backend
+	ttl     time.Duration
+	prefix  string
+}
+
+// Option configures a UserRepository.
+type Option func(*UserRepository)
+
+// WithTTL sets how long users stay cached. Defaults to five minutes.
+func WithTTL(ttl time.Duration) Option {
+	return func(r *UserRepository) {
+		if ttl > 0 {
+			r.ttl = ttl
+		}
+	}
+}
+
+// WithKeyPrefix namespaces the cache keys, e.g. per tenant or schema version.
+// Defaults to "users:".
+func WithKeyPrefix(prefix string) Option {
+	return func(r *UserRepository) {
+		r.prefix = prefix
+	}
+}
+
+// NewUserRepository wraps repo with a cache on backend.
+func NewUserRepository(repo repositories.UserRepository, backend Backend, opts ...Option) *UserRepository {
+	cached := &UserRepository{
+		UserRepository: repo,
+		backend:        backend,
+		ttl:            defaultTTL,
+		prefix:         defaultKeyPrefix,
+	}
+
+	for _, opt := range opts {
+		opt(cached)
+	}
+
+	return cached
+}
+
+// Ensure UserRepository implements the domain UserRepository.
+var _ repositories.UserRepository = (*UserRepository)(nil)
+
+func (r *UserRepository) idKey(id entities.UserID) string {
+	return r.prefix + "id:" + strconv.FormatInt(id.Int64(), 10)
+}
+
+func (r *UserRepository) uuidKey(uuid string) string { return r.prefix + "uuid:" + uuid }
+
+func (r *UserRepository) emailKey(email entities.Email) string {
+	return r.prefix + "email:" + email.String()
+}
+
+func (r *UserRepository) usernameKey(username entities.Username) string {
+	return r.prefix + "username:" + username.Canonical()
+}
+
+// GetByID returns the cached user or loads it.
+func (r *UserRepository) GetByID(ctx context.Context, id entities.UserID) (*entities.User, error) {
+	user, ok := r.cached(ctx, id)
+	if ok {
+		return user, nil
+	}
+
+	user, err := r.UserRepository.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	r.store(ctx, user)
+
+	return user, nil
+}
+
+// GetByUUID returns the cached user or loads it.
+func (r *UserRepository) GetByUUID(ctx context.Context, uuid entities.UuID) (*entities.User, error) {
+	return r.lookup(ctx, r.uuidKey(uuid.String()),
+		func(user *entities.User) bool { return user.UUID().String() == uuid.String() },
+		func() (*entities.User, error) { return r.UserRepository.GetByUUID(ctx, uuid) },
+	)
+}
+
+// GetByEmail returns the cached user or loads it.
+func (r *UserRepository) GetByEmail(ctx context.Context, email entities.Email) (*entities.User, error) {
+	return r.lookup(ctx, r.emailKey(email),
+		func(user *entities.User) bool { return user.Email() == email },
+		func() (*entities.User, error) { return r.UserRepository.GetByEmail(ctx, email) },
+	)
+}
+
+// GetByUsername returns the cached user or loads it.
+func (r *UserRepository) GetByUsername(
+	ctx context.Context,
+	username entities.Username,
+) (*entities.User, error) {
+	return r.lookup(ctx, r.usernameKey(username),
+		func(user *entities.User) bool { return user.Username().Canonical() == username.Canonical() },
+		func() (*entities.User, error) { return r.UserRepository.GetByUsername(ctx, username) },
+	)
+}
+
+// GetByIDs serves cached users and loads the rest in one query.
+func (r *UserRepository) GetByIDs(ctx context.Context, ids []entities.UserID) ([]*entities.User, error) {
+	users := make([]*entities.User, 0, len(ids))
+	missing := make([]entities.UserID, 0, len(ids))
+
+	for _, id := range ids {
+		user, ok := r.cached(ctx, id)
+		if ok {
+			users = append(users, user)
+		} else {
+			missing = append(missing, id)
+		}
+	}
+
+	if len(missing) == 0 {
+		return users, nil
+	}
+
+	loaded, err := r.UserRepository.GetByIDs(ctx, missing)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, user := range loaded {
+		r.store(ctx, user)
+	}
+
+	return append(users, loaded...), nil
+}
+
+// Update updates the user and drops it from the cache.
+func (r *UserRepository) Update(ctx context.Context, user *entities.User) error {
+	defer r.invalidate(ctx, user.ID())
+
+	return r.UserRepository.Update(ctx, user)
+}
+
+// Delete deletes the user and drops it from the cache.
+func (r *UserRepository) Delete(ctx context.Context, id entities.UserID) error {
+	defer r.invalidate(ctx, id)
+
+	return r.UserRepository.Delete(ctx, id)
+}
+
+// UpdateStatusBatch changes the statuses and drops the users from the cache.
+func (r *UserRepository) UpdateStatusBatch(
+	ctx context.Context,
+	ids []entities.UserID,
+	status entities.UserStatus,
+) (int64, error) {
+	defer r.invalidate(ctx, ids...)
+
+	return r.UserRepository.UpdateStatusBatch(ctx, ids, status)
+}
+
+// DeleteBatch deletes the users and drops them from the cache.
+func (r *UserRepository) DeleteBatch(ctx context.Context, ids []entities.UserID) (int64, error) {
+	defer r.invalidate(ctx, ids...)
+
+	return r.UserRepository.DeleteBatch(ctx, ids)
+}
+
+// UpdatePassword updates the password and drops the user from the cache.
+func (r *UserRepository) UpdatePassword(
+	ctx context.Context,
+	id entities.UserID,
+	password entities.PasswordHash,
+) error {
+	defer r.invalidate(ctx, id)
+
+	return r.UserRepository.UpdatePassword(ctx, id, password)
+}
+
+// MarkVerified marks the user verified and drops it from the cache.
+func (r *UserRepository) MarkVerified(ctx context.Context, id entities.UserID) error {
+	defer r.invalidate(ctx, id)
+
+	return r.UserRepository.MarkVerified(ctx, id)
+}
+
+// ChangeStatus changes the status and drops the user from the cache.
+func (r *UserRepository) ChangeStatus(ctx context.Context, id entities.UserID, status entities.UserStatus) error {
+	defer r.invalidate(ctx, id)
+
+	return r.UserRepository.ChangeStatus(ctx, id, status)
+}
+
+// Activate activates the user and drops it from the cache.
+func (r *UserRepository) Activate(ctx context.Context, id entities.UserID) error {
+	defer r.invalidate(ctx, id)
+
+	return r.UserRepository.Activate(ctx, id)
+}
+
+// Deactivate deactivates the user and drops it from the cache.
+func (r *UserRepository) Deactivate(ctx context.Context, id entities.UserID) error {
+	defer r.invalidate(ctx, id)
+
+	return r.UserRepository.Deactivate(ctx, id)
+}
+
+// Suspend suspends the user and drops it from the cache.
+func (r *UserRepository) Suspend(ctx context.Context, id entities.UserID) error {
+	defer r.invalidate(ctx, id)
+
+	return r.UserRepository.Suspend(ctx, id)
+}
+
+// ChangeRole changes the role and drops the user from the cache.
+func (r *UserRepository) ChangeRole(ctx context.Context, id entities.UserID, role entities.UserRole) error {
+	defer r.invalidate(ctx, id)
+
+	return r.UserRepository.ChangeRole(ctx, id, role)
+}
+
+// Invalidate drops a user from the cache, for changes made outside this repository.
+func (r *UserRepository) Invalidate(ctx context.Context, id entities.UserID) {
+	r.invalidate(ctx, id)
+}
+
+// lookup resolves a secondary key to a cached user, verifying it with matches,
+// or loads the user with load and caches it.
+func (r *UserRepository) lookup(
+	ctx context.Context,
+	key string,
+	matches func(*entities.User) bool,
+	load func() (*entities.User, error),
+) (*entities.User, error) {
+	id, ok := r.pointer(ctx, key)
+	if ok {
+		user, ok := r.cached(ctx, id)
+		if ok && matches(user) {
+			return user, nil
+		}
+	}
+
+	user, err := load()
+	if err != nil {
+		return nil, err
+	}
+
+	r.store(ctx, user)
+
+	return user, nil
+}
+
+// pointer returns the user ID stored under a secondary key.
+func (r *UserRepository) pointer(ctx context.Context, key string) (entities.UserID, bool) {
+	value, ok := r.get(ctx, key)
+	if !ok {
+		return 0, false
+	}
+
+	id, err := strconv.ParseInt(string(value), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return entities.UserID(id), true
+}
+
+// cached returns the user cached under its ID.
+func (r *UserRepository) cached(ctx context.Context, id entities.UserID) (*entities.User, bool) {
+	value, ok := r.get(ctx, r.idKey(id))
+	if !ok {
+		return nil, false
+	}
+
+	var record entities.UserRecord
+
+	err := json.Unmarshal(value, &record)
+	if err != nil {
+		slog.Warn("dropping undecodable cached user", "id", id, "error", err)
+		r.invalidate(ctx, id)
+
+		return nil, false
+	}
+
+	user, err := entities.ReconstructUser(record)
+	if err != nil {
+		return nil, false
+	}
+
+	return user, true
+}
+
+// get reads key, treating backend errors as misses.
+func (r *UserRepository) get(ctx context.Context, key string) ([]byte, bool) {
+	value, ok, err := r.backend.Get(ctx, key)
+	if err != nil {
+		slog.Warn("user cache read failed", "key", key, "error", err)
+
+		return nil, false
+	}
+
+	return value, ok
+}
+
+// store caches user under its ID and points its secondary keys at it. A
+// usable password hash is left out; unusable ones match no password and are
+// kept, so that cached users still tell whether they have a password.
+func (r *UserRepository) store(ctx context.Context, user *entities.User) {
+	record := user.Record()
+	if record.Password.IsUsable() {
+		record.Password = ""
+	}
+
+	value, err := json.Marshal(record)
+	if err != nil {
+		slog.Warn("user not cacheable", "id", user.ID(), "error", err)
+
+		return
+	}
+
+	id := []byte(strconv.FormatInt(user.ID().Int64(), 10))
+	entries := map[string][]byte{
+		r.idKey(user.ID()):              value,
+		r.uuidKey(user.UUID().String()): id,
+		r.emailKey(user.Email()):        id,
+		r.usernameKey(user.Username()):  id,
+	}
+
+	for key, entry := range entries {
+		err = r.backend.Set(ctx, key, entry, r.ttl)
+		if err != nil {
+			slog.Warn("user cache write failed", "key", key, "error", err)
+
+			return
+		}
+	}
+}
+
+// invalidate drops the cached users. Secondary keys may keep pointing at the
+// IDs; lookup detects and skips them once the users are reloaded.
+func (r *UserRepository) invalidate(ctx context.Context, ids ...entities.UserID) {
+	if len(ids) == 0 {
+		return
+	}
+
+	keys := make([]string, 0, len(ids))
+	for _, id := range ids {
+		keys = append(keys, r.idKey(id))
+	}
+
+	err := r.backend.Delete(ctx, keys...)
+	if err != nil {
+		slog.Warn("user cache invalidation failed", "ids", ids, "error", err)
+	}
+}
@@ -0,0 +1,36 @@
+// Package clickhouse records user events in ClickHouse and answers the login
+// and signup reports from them.
+//
+// A Sink subscribes to the login, failed login and user created events and
+// inserts them in batches into the user_events table of sql/clickhouse; a
+// Repository reads the reports back for the ReportingService:
+//
+//	sink := clickhouse.NewSink(db)
+//	dispatcher.Subscribe("clickhouse", sink, sink.EventTypes()...)
+//	defer sink.Close(ctx)
+//
+//	reports := services.NewReportingService(clickhouse.NewRepository(db))
+//
+// The adapter uses database/sql only; the caller imports the driver,
+// github.com/ClickHouse/clickhouse-go/v2:
+//
+//	db, err := sql.Open("clickhouse", "clickhouse://localhost:9000/analytics")
+package clickhouse
+
+import (
+	"database/sql"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// Repository implements EventAnalyticsRepository over the user_events table.
+type Repository struct {
+	db *sql.DB
+}
+
+var _ repositories.EventAnalyticsRepository = (*Repository)(nil)
+
+// NewRepository creates a ClickHouse event analytics repository.
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db: db}
+}
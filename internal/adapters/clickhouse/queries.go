@@ -0,0 +1,120 @@
+package clickhouse
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	apperrors "github.com/LarsArtmann/template-sqlc/pkg/errors"
+)
+
+// The sink may insert an event more than once; FINAL collapses the copies
+// that ReplacingMergeTree has not merged yet.
+
+const loginsByDay = `
+SELECT
+    toDate(occurred_at) AS day,
+    toInt64(countIf(type = 'user.login')) AS logins,
+    toInt64(countIf(type = 'user.login.failed')) AS failures,
+    toInt64(uniqExactIf(user_id, type = 'user.login')) AS users
+FROM user_events FINAL
+WHERE type IN ('user.login', 'user.login.failed') AND occurred_at >= ? AND occurred_at < ?
+GROUP BY day
+ORDER BY day`
+
+const failuresByIP = `
+SELECT
+    ip_address,
+    toInt64(count()) AS failures,
+    toInt64(uniqExact(user_id)) AS users,
+    max(occurred_at) AS last_at
+FROM user_events FINAL
+WHERE type = 'user.login.failed' AND occurred_at >= ? AND occurred_at < ?
+GROUP BY ip_address
+ORDER BY failures DESC, ip_address
+LIMIT ?`
+
+const signupsBySource = `
+SELECT
+    source,
+    toInt64(count()) AS signups
+FROM user_events FINAL
+WHERE type = 'user.created' AND occurred_at >= ? AND occurred_at < ?
+GROUP BY source
+ORDER BY signups DESC, source`
+
+// LoginsByDay returns the login attempts per UTC day in [from, to).
+func (r *Repository) LoginsByDay(ctx context.Context, from, to time.Time) ([]entities.DailyLogins, error) {
+	return query(ctx, r.db, "logins by day", func(rows *sql.Rows) (entities.DailyLogins, error) {
+		var day entities.DailyLogins
+
+		err := rows.Scan(&day.Day, &day.Logins, &day.Failures, &day.Users)
+		day.Day = day.Day.UTC()
+
+		return day, err
+	}, loginsByDay, from.UTC(), to.UTC())
+}
+
+// FailuresByIP returns the limit addresses with the most failed logins in [from, to).
+func (r *Repository) FailuresByIP(
+	ctx context.Context,
+	from, to time.Time,
+	limit int,
+) ([]entities.IPFailures, error) {
+	return query(ctx, r.db, "failures by ip", func(rows *sql.Rows) (entities.IPFailures, error) {
+		var failures entities.IPFailures
+
+		err := rows.Scan(&failures.IPAddress, &failures.Failures, &failures.Users, &failures.LastAt)
+		failures.LastAt = failures.LastAt.UTC()
+
+		return failures, err
+	}, failuresByIP, from.UTC(), to.UTC(), limit)
+}
+
+// SignupsBySource returns the signups per source in [from, to).
+func (r *Repository) SignupsBySource(ctx context.Context, from, to time.Time) ([]entities.SignupSource, error) {
+	return query(ctx, r.db, "signups by source", func(rows *sql.Rows) (entities.SignupSource, error) {
+		var source entities.SignupSource
+
+		err := rows.Scan(&source.Source, &source.Signups)
+
+		return source, err
+	}, signupsBySource, from.UTC(), to.UTC())
+}
+
+// query runs a report query and scans every row with scan.
+func query[T any](
+	ctx context.Context,
+	db *sql.DB,
+	op string,
+	scan func(rows *sql.Rows) (T, error),
+	statement string,
+	args ...any,
+) ([]T, error) {
+	rows, err := db.QueryContext(ctx, statement, args...)
+	if err != nil {
+		return nil, apperrors.NewDatabaseError(op+" failed", err)
+	}
+
+	defer func() { _ = rows.Close() }()
+
+	var results []T
+
+	for rows.Next() {
+		result, err := scan(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan %s: %w", op, err)
+		}
+
+		results = append(results, result)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, apperrors.NewDatabaseError(op+" failed", err)
+	}
+
+	return results, nil
+}
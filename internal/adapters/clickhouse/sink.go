@@ -0,0 +1,288 @@
+package clickhouse
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+	apperrors "github.com/LarsArtmann/template-sqlc/pkg/errors"
+)
+
+const (
+	defaultBatchSize     = 1000
+	defaultFlushInterval = 5 * time.Second
+	// pendingBatches bounds the buffer while ClickHouse is unavailable, in
+	// batches; the oldest events are dropped beyond it.
+	pendingBatches = 10
+)
+
+const insertUserEvents = `
+INSERT INTO user_events (event_id, type, user_id, ip_address, source, occurred_at)`
+
+// eventRow is one row of user_events.
+type eventRow struct {
+	id         int64
+	eventType  events.EventType
+	userID     int64
+	ipAddress  string
+	source     string
+	occurredAt time.Time
+}
+
+// eventData holds the fields of the login and signup payloads the sink records.
+type eventData struct {
+	IPAddress string `json:"ipAddress"`
+	Source    string `json:"source"`
+}
+
+// Sink is an events.EventHandler that records login, failed login and user
+// created events in ClickHouse. Events are buffered and inserted once a
+// batch is full and at every flush interval; a failed insert keeps the
+// events for the next one. Close must be called to stop the flushes and
+// insert the remaining events.
+type Sink struct {
+	db            *sql.DB
+	batchSize     int
+	flushInterval time.Duration
+
+	mu      sync.Mutex
+	pending []eventRow
+
+	full   chan struct{}
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+var _ events.EventHandler = (*Sink)(nil)
+
+// SinkOption configures a Sink.
+type SinkOption func(*Sink)
+
+// WithBatchSize sets how many events are inserted at a time.
+func WithBatchSize(size int) SinkOption {
+	return func(s *Sink) {
+		if size > 0 {
+			s.batchSize = size
+		}
+	}
+}
+
+// WithFlushInterval sets how often pending events are inserted even if the
+// batch is not full.
+func WithFlushInterval(interval time.Duration) SinkOption {
+	return func(s *Sink) {
+		if interval > 0 {
+			s.flushInterval = interval
+		}
+	}
+}
+
+// NewSink creates a sink inserting into db and starts its flushes.
+func NewSink(db *sql.DB, opts ...SinkOption) *Sink {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	s := &Sink{
+		db:            db,
+		batchSize:     defaultBatchSize,
+		flushInterval: defaultFlushInterval,
+		full:          make(chan struct{}, 1),
+		ctx:           ctx,
+		cancel:        cancel,
+		done:          make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	go s.run()
+
+	return s
+}
+
+// EventTypes returns the event types the sink records, to subscribe it with.
+func (s *Sink) EventTypes() []events.EventType {
+	return []events.EventType{events.EventUserLogin, events.EventUserLoginFail, events.EventUserCreated}
+}
+
+// Handle buffers event. Events of other types are ignored.
+func (s *Sink) Handle(_ context.Context, event *events.UserEvent) error {
+	if !slices.Contains(s.EventTypes(), event.Type) {
+		return nil
+	}
+
+	row, err := rowOf(event)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.pending = append(s.pending, row)
+	s.dropOverflow()
+	full := len(s.pending) >= s.batchSize
+	s.mu.Unlock()
+
+	if full {
+		select {
+		case s.full <- struct{}{}:
+		default:
+		}
+	}
+
+	return nil
+}
+
+// Flush inserts the pending events in batches. Events of a failed batch
+// stay pending.
+func (s *Sink) Flush(ctx context.Context) error {
+	for {
+		s.mu.Lock()
+		batch := s.pending[:min(len(s.pending), s.batchSize)]
+		s.pending = s.pending[len(batch):]
+		s.mu.Unlock()
+
+		if len(batch) == 0 {
+			return nil
+		}
+
+		err := s.insert(ctx, batch)
+		if err != nil {
+			s.mu.Lock()
+			s.pending = slices.Concat(batch, s.pending)
+			s.dropOverflow()
+			s.mu.Unlock()
+
+			return err
+		}
+	}
+}
+
+// Close stops the periodic flushes and inserts the remaining events.
+func (s *Sink) Close(ctx context.Context) error {
+	s.cancel()
+	<-s.done
+
+	err := s.Flush(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to flush user events: %w", err)
+	}
+
+	return nil
+}
+
+// run flushes at every interval and whenever a batch is full until the sink
+// is closed.
+func (s *Sink) run() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.full:
+		}
+
+		err := s.Flush(s.ctx)
+		if err != nil && s.ctx.Err() == nil {
+			slog.Warn("failed to flush user events to clickhouse", "error", err)
+		}
+	}
+}
+
+// insert writes batch in one block.
+func (s *Sink) insert(ctx context.Context, batch []eventRow) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return apperrors.NewDatabaseError("begin insert user events failed", err)
+	}
+
+	defer func() { _ = tx.Rollback() }()
+
+	stmt, err := tx.PrepareContext(ctx, insertUserEvents)
+	if err != nil {
+		return apperrors.NewDatabaseError("prepare insert user events failed", err)
+	}
+
+	defer func() { _ = stmt.Close() }()
+
+	for _, row := range batch {
+		_, err = stmt.ExecContext(ctx,
+			row.id, string(row.eventType), row.userID, row.ipAddress, row.source, row.occurredAt)
+		if err != nil {
+			return apperrors.NewDatabaseError("append user event failed", err)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return apperrors.NewDatabaseError("insert user events failed", err)
+	}
+
+	return nil
+}
+
+// dropOverflow drops the oldest pending events beyond the buffer bound.
+// The caller holds mu.
+func (s *Sink) dropOverflow() {
+	limit := pendingBatches * s.batchSize
+	if len(s.pending) <= limit {
+		return
+	}
+
+	dropped := len(s.pending) - limit
+	s.pending = s.pending[dropped:]
+
+	slog.Warn("dropped user events for clickhouse", "dropped", dropped)
+}
+
+// rowOf maps event to its row. Payloads arrive as structs from in-process
+// publishers and as raw JSON from brokers, so both are decoded through JSON.
+func rowOf(event *events.UserEvent) (eventRow, error) {
+	var data eventData
+
+	if event.Data != nil {
+		payload, ok := event.Data.(json.RawMessage)
+		if !ok {
+			var err error
+
+			payload, err = json.Marshal(event.Data)
+			if err != nil {
+				return eventRow{}, fmt.Errorf("encode event id=%v data: %w", event.ID, err)
+			}
+		}
+
+		err := json.Unmarshal(payload, &data)
+		if err != nil {
+			return eventRow{}, fmt.Errorf("decode event id=%v data: %w", event.ID, err)
+		}
+	}
+
+	row := eventRow{
+		id:         event.ID.Int64(),
+		eventType:  event.Type,
+		userID:     event.UserID.Int64(),
+		ipAddress:  data.IPAddress,
+		occurredAt: event.Timestamp.UTC(),
+	}
+
+	if event.Type == events.EventUserCreated {
+		row.source = data.Source
+		if row.source == "" {
+			row.source = entities.SignupSourceDirect
+		}
+	}
+
+	return row, nil
+}
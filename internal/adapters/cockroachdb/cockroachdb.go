@@ -0,0 +1,120 @@
+// Package cockroachdb provides the CockroachDB repositories.
+//
+// CockroachDB speaks the PostgreSQL wire protocol and dialect, so the
+// repositories are the PostgreSQL adapters over pgx, running against the
+// schema in sql/cockroachdb; build with the postgres tag:
+//
+//	go build -tags postgres ./...
+//
+// CockroachDB runs transactions serializable and aborts one that conflicts
+// with another with SQLSTATE 40001, expecting the client to retry it.
+// Single statements are retried by the server; ExecuteTx retries explicit
+// transactions.
+package cockroachdb
+
+import (
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/converters"
+	postgresadapter "github.com/LarsArtmann/template-sqlc/internal/adapters/postgres"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// UserRepository is the PostgreSQL user repository, reporting CockroachDB
+// as its engine to metrics and converter lookups.
+type UserRepository struct {
+	repositories.UserRepository
+}
+
+// NewUserRepository creates a CockroachDB user repository.
+func NewUserRepository(db postgresadapter.DBTX) repositories.UserRepository {
+	return &UserRepository{UserRepository: postgresadapter.NewUserRepository(db)}
+}
+
+// Engine returns the converter engine of the repository's database.
+func (r *UserRepository) Engine() string {
+	return converters.DbTypeCockroachDB
+}
+
+// NewSessionRepository creates a CockroachDB session repository.
+func NewSessionRepository(db postgresadapter.DBTX) repositories.SessionRepository {
+	return postgresadapter.NewSessionRepository(db)
+}
+
+// NewJobRepository creates a CockroachDB job repository.
+func NewJobRepository(db postgresadapter.DBTX) repositories.JobRepository {
+	return postgresadapter.NewJobRepository(db)
+}
+
+// NewIdempotencyRepository creates a CockroachDB idempotency repository.
+func NewIdempotencyRepository(db postgresadapter.DBTX) repositories.IdempotencyRepository {
+	return postgresadapter.NewIdempotencyRepository(db)
+}
+
+// NewNotificationPreferenceRepository creates a CockroachDB notification preference repository.
+func NewNotificationPreferenceRepository(db postgresadapter.DBTX) repositories.NotificationPreferenceRepository {
+	return postgresadapter.NewNotificationPreferenceRepository(db)
+}
+
+// NewIdentityChangeRepository creates a CockroachDB identity change repository.
+func NewIdentityChangeRepository(db postgresadapter.DBTX) repositories.IdentityChangeRepository {
+	return postgresadapter.NewIdentityChangeRepository(db)
+}
+
+// NewModerationRepository creates a CockroachDB moderation repository.
+func NewModerationRepository(db postgresadapter.DBTX) repositories.ModerationRepository {
+	return postgresadapter.NewModerationRepository(db)
+}
+
+// NewAccountDeletionRepository creates a CockroachDB account deletion repository.
+func NewAccountDeletionRepository(db postgresadapter.DBTX) repositories.AccountDeletionRepository {
+	return postgresadapter.NewAccountDeletionRepository(db)
+}
+
+// NewUserPreferenceRepository creates a CockroachDB user preference repository.
+func NewUserPreferenceRepository(db postgresadapter.DBTX) repositories.UserPreferenceRepository {
+	return postgresadapter.NewUserPreferenceRepository(db)
+}
+
+// NewActivityRepository creates a CockroachDB activity repository.
+func NewActivityRepository(db postgresadapter.DBTX) repositories.ActivityRepository {
+	return postgresadapter.NewActivityRepository(db)
+}
+
+// NewLoginHistoryRepository creates a CockroachDB login history repository.
+func NewLoginHistoryRepository(db postgresadapter.DBTX) repositories.LoginHistoryRepository {
+	return postgresadapter.NewLoginHistoryRepository(db)
+}
+
+// NewUserEventStore creates a CockroachDB user event store.
+func NewUserEventStore(db postgresadapter.DBTX) repositories.UserEventStore {
+	return postgresadapter.NewUserEventStore(db)
+}
+
+// NewUserReadModelRepository creates a CockroachDB user read model repository.
+func NewUserReadModelRepository(db postgresadapter.DBTX) repositories.UserReadModelRepository {
+	return postgresadapter.NewUserReadModelRepository(db)
+}
+
+// NewFeatureFlagRepository creates a CockroachDB feature flag repository.
+func NewFeatureFlagRepository(db postgresadapter.DBTX) repositories.FeatureFlagRepository {
+	return postgresadapter.NewFeatureFlagRepository(db)
+}
+
+// NewAuditRepository creates a CockroachDB audit repository.
+func NewAuditRepository(db postgresadapter.DBTX) repositories.AuditRepository {
+	return postgresadapter.NewAuditRepository(db)
+}
+
+// NewIdentityRepository creates a CockroachDB identity repository.
+func NewIdentityRepository(db postgresadapter.DBTX) repositories.IdentityRepository {
+	return postgresadapter.NewIdentityRepository(db)
+}
+
+// NewOrganizationRepository creates a CockroachDB organization repository.
+func NewOrganizationRepository(db postgresadapter.DBTX) repositories.OrganizationRepository {
+	return postgresadapter.NewOrganizationRepository(db)
+}
+
+// NewMembershipRepository creates a CockroachDB membership repository.
+func NewMembershipRepository(db postgresadapter.DBTX) repositories.MembershipRepository {
+	return postgresadapter.NewMembershipRepository(db)
+}
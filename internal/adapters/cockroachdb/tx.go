@@ -0,0 +1,132 @@
+package cockroachdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// SerializationFailure is the SQLSTATE of a transaction aborted to keep
+// the history serializable. Running it again may succeed.
+const SerializationFailure = "40001"
+
+const (
+	defaultMaxRetries   = 10
+	defaultRetryBackoff = 10 * time.Millisecond
+	maxRetryBackoff     = time.Second
+)
+
+// ErrRetriesExhausted is returned when a transaction still fails with a
+// serialization failure after the last retry.
+var ErrRetriesExhausted = errors.New("transaction retries exhausted")
+
+// TxBeginner starts transactions, such as *pgxpool.Pool and *pgx.Conn.
+type TxBeginner interface {
+	BeginTx(ctx context.Context, opts pgx.TxOptions) (pgx.Tx, error)
+}
+
+// txConfig holds the settings of ExecuteTx.
+type txConfig struct {
+	opts       pgx.TxOptions
+	maxRetries int
+	backoff    time.Duration
+}
+
+// TxOption configures ExecuteTx.
+type TxOption func(*txConfig)
+
+// WithTxOptions sets the options every attempt begins its transaction with.
+func WithTxOptions(opts pgx.TxOptions) TxOption {
+	return func(c *txConfig) {
+		c.opts = opts
+	}
+}
+
+// WithMaxRetries sets how often a failed transaction is run again.
+func WithMaxRetries(retries int) TxOption {
+	return func(c *txConfig) {
+		if retries >= 0 {
+			c.maxRetries = retries
+		}
+	}
+}
+
+// WithRetryBackoff sets the delay before the first retry, which doubles each
+// retry up to a second.
+func WithRetryBackoff(backoff time.Duration) TxOption {
+	return func(c *txConfig) {
+		if backoff >= 0 {
+			c.backoff = backoff
+		}
+	}
+}
+
+// ExecuteTx runs fn in a transaction and commits it. A transaction that
+// fails with a serialization failure, in fn or on commit, is rolled back
+// and run again from the start, so fn must not have effects outside the
+// transaction. Other errors are returned as they are.
+func ExecuteTx(ctx context.Context, db TxBeginner, fn func(tx pgx.Tx) error, opts ...TxOption) error {
+	cfg := txConfig{maxRetries: defaultMaxRetries, backoff: defaultRetryBackoff}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	backoff := cfg.backoff
+
+	for attempt := 0; ; attempt++ {
+		err := runTx(ctx, db, cfg.opts, fn)
+		if err == nil || !IsRetryable(err) {
+			return err
+		}
+
+		if attempt == cfg.maxRetries {
+			return fmt.Errorf("%w after %d attempts: %w", ErrRetriesExhausted, attempt+1, err)
+		}
+
+		timer := time.NewTimer(backoff)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			return fmt.Errorf("retry transaction: %w", ctx.Err())
+		case <-timer.C:
+		}
+
+		backoff = min(2*backoff, maxRetryBackoff)
+	}
+}
+
+// runTx runs one attempt of ExecuteTx.
+func runTx(ctx context.Context, db TxBeginner, opts pgx.TxOptions, fn func(tx pgx.Tx) error) error {
+	tx, err := db.BeginTx(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+
+	err = fn(tx)
+	if err != nil {
+		_ = tx.Rollback(ctx)
+
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// IsRetryable reports whether err is a serialization failure, including one
+// wrapped by the repositories.
+func IsRetryable(err error) bool {
+	var pgErr *pgconn.PgError
+
+	return errors.As(err, &pgErr) && pgErr.Code == SerializationFailure
+}
@@ -0,0 +1,191 @@
+package converters
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// NullableConverter converts between an optional domain value, nil for NULL,
+// and the typed nullable column value of a driver.
+type NullableConverter[Domain any, DB any] interface {
+	TypeConverter[*Domain, DB]
+}
+
+// NullStringConverter handles nullable text for database/sql engines.
+type NullStringConverter struct{}
+
+// NewNullStringConverter creates a new NullStringConverter.
+func NewNullStringConverter() *NullStringConverter { return &NullStringConverter{} }
+
+// DomainToDB converts an optional string to sql.NullString.
+func (c *NullStringConverter) DomainToDB(domain *string) sql.NullString {
+	if domain == nil {
+		return sql.NullString{}
+	}
+
+	return sql.NullString{String: *domain, Valid: true}
+}
+
+// DBToDomain converts sql.NullString to an optional string.
+func (c *NullStringConverter) DBToDomain(db sql.NullString) (*string, error) {
+	return fromNull(db.String, db.Valid), nil
+}
+
+// NullTimeConverter handles nullable timestamps for database/sql engines.
+type NullTimeConverter struct{}
+
+// NewNullTimeConverter creates a new NullTimeConverter.
+func NewNullTimeConverter() *NullTimeConverter { return &NullTimeConverter{} }
+
+// DomainToDB converts an optional time to sql.NullTime.
+func (c *NullTimeConverter) DomainToDB(domain *time.Time) sql.NullTime {
+	if domain == nil {
+		return sql.NullTime{}
+	}
+
+	return sql.NullTime{Time: *domain, Valid: true}
+}
+
+// DBToDomain converts sql.NullTime to an optional time.
+func (c *NullTimeConverter) DBToDomain(db sql.NullTime) (*time.Time, error) {
+	return fromNull(db.Time, db.Valid), nil
+}
+
+// NullBoolConverter handles nullable booleans for database/sql engines.
+type NullBoolConverter struct{}
+
+// NewNullBoolConverter creates a new NullBoolConverter.
+func NewNullBoolConverter() *NullBoolConverter { return &NullBoolConverter{} }
+
+// DomainToDB converts an optional bool to sql.NullBool.
+func (c *NullBoolConverter) DomainToDB(domain *bool) sql.NullBool {
+	if domain == nil {
+		return sql.NullBool{}
+	}
+
+	return sql.NullBool{Bool: *domain, Valid: true}
+}
+
+// DBToDomain converts sql.NullBool to an optional bool.
+func (c *NullBoolConverter) DBToDomain(db sql.NullBool) (*bool, error) {
+	return fromNull(db.Bool, db.Valid), nil
+}
+
+// NullInt64Converter handles nullable integers for database/sql engines.
+type NullInt64Converter struct{}
+
+// NewNullInt64Converter creates a new NullInt64Converter.
+func NewNullInt64Converter() *NullInt64Converter { return &NullInt64Converter{} }
+
+// DomainToDB converts an optional int64 to sql.NullInt64.
+func (c *NullInt64Converter) DomainToDB(domain *int64) sql.NullInt64 {
+	if domain == nil {
+		return sql.NullInt64{}
+	}
+
+	return sql.NullInt64{Int64: *domain, Valid: true}
+}
+
+// DBToDomain converts sql.NullInt64 to an optional int64.
+func (c *NullInt64Converter) DBToDomain(db sql.NullInt64) (*int64, error) {
+	return fromNull(db.Int64, db.Valid), nil
+}
+
+// TimestamptzConverter handles nullable timestamps for PostgreSQL.
+type TimestamptzConverter struct{}
+
+// NewTimestamptzConverter creates a new TimestamptzConverter.
+func NewTimestamptzConverter() *TimestamptzConverter { return &TimestamptzConverter{} }
+
+// DomainToDB converts an optional time to pgtype.Timestamptz.
+func (c *TimestamptzConverter) DomainToDB(domain *time.Time) pgtype.Timestamptz {
+	if domain == nil {
+		return pgtype.Timestamptz{}
+	}
+
+	return pgtype.Timestamptz{Time: *domain, Valid: true}
+}
+
+// DBToDomain converts pgtype.Timestamptz to an optional time. Infinite
+// timestamps are rejected as they have no time.Time equivalent.
+func (c *TimestamptzConverter) DBToDomain(db pgtype.Timestamptz) (*time.Time, error) {
+	if db.InfinityModifier != pgtype.Finite {
+		return nil, NewConversionError("infinite timestamp", db)
+	}
+
+	return fromNull(db.Time, db.Valid), nil
+}
+
+// PgUUIDConverter handles nullable UUIDs for PostgreSQL.
+type PgUUIDConverter struct{}
+
+// NewPgUUIDConverter creates a new PgUUIDConverter.
+func NewPgUUIDConverter() *PgUUIDConverter { return &PgUUIDConverter{} }
+
+// DomainToDB converts an optional UUID to pgtype.UUID.
+func (c *PgUUIDConverter) DomainToDB(domain *uuid.UUID) pgtype.UUID {
+	if domain == nil {
+		return pgtype.UUID{}
+	}
+
+	return pgtype.UUID{Bytes: *domain, Valid: true}
+}
+
+// DBToDomain converts pgtype.UUID to an optional UUID.
+func (c *PgUUIDConverter) DBToDomain(db pgtype.UUID) (*uuid.UUID, error) {
+	return fromNull(uuid.UUID(db.Bytes), db.Valid), nil
+}
+
+// BinaryUUIDConverter handles UUIDs stored as BINARY(16).
+type BinaryUUIDConverter struct{}
+
+// NewBinaryUUIDConverter creates a new BinaryUUIDConverter.
+func NewBinaryUUIDConverter() *BinaryUUIDConverter { return &BinaryUUIDConverter{} }
+
+// DomainToDB converts a UUID to its 16 bytes.
+func (c *BinaryUUIDConverter) DomainToDB(domain uuid.UUID) []byte {
+	return domain[:]
+}
+
+// DBToDomain converts 16 bytes to a UUID.
+func (c *BinaryUUIDConverter) DBToDomain(db []byte) (uuid.UUID, error) {
+	parsed, err := uuid.FromBytes(db)
+	if err != nil {
+		return uuid.Nil, NewConversionError("invalid UUID bytes", db)
+	}
+
+	return parsed, nil
+}
+
+// TextUUIDConverter handles UUIDs stored as text.
+type TextUUIDConverter struct{}
+
+// NewTextUUIDConverter creates a new TextUUIDConverter.
+func NewTextUUIDConverter() *TextUUIDConverter { return &TextUUIDConverter{} }
+
+// DomainToDB converts a UUID to its canonical string.
+func (c *TextUUIDConverter) DomainToDB(domain uuid.UUID) string {
+	return domain.String()
+}
+
+// DBToDomain parses a UUID string.
+func (c *TextUUIDConverter) DBToDomain(db string) (uuid.UUID, error) {
+	parsed, err := uuid.Parse(db)
+	if err != nil {
+		return uuid.Nil, NewConversionError("invalid UUID string", db)
+	}
+
+	return parsed, nil
+}
+
+// fromNull returns a pointer to value, or nil when the column is NULL.
+func fromNull[T any](value T, valid bool) *T {
+	if !valid {
+		return nil
+	}
+
+	return &value
+}
@@ -0,0 +1,186 @@
+package converters
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// AnyEngine registers a converter for every engine without a more specific one.
+const AnyEngine = ""
+
+// ErrConverterNotFound is returned when no converter is registered for a type.
+var ErrConverterNotFound = errors.New("converter not registered")
+
+// Registry holds the type converters of every engine, keyed by domain Go type.
+//
+// Adapters look converters up at runtime, so custom domain types such as
+// money, enums or arrays are supported by registering a converter instead of
+// changing the adapters. A converter registered for a specific engine takes
+// precedence over one registered for AnyEngine. Registry is safe for
+// concurrent use.
+type Registry struct {
+	mu      sync.RWMutex
+	entries map[registryKey]registryEntry
+}
+
+type registryKey struct {
+	domain reflect.Type
+	engine string
+}
+
+// registryEntry keeps the typed converter and type-erased wrappers around it
+// for lookups by value.
+type registryEntry struct {
+	converter any
+	toDB      func(domain any) any
+	toDomain  func(db any) (any, error)
+}
+
+var defaultRegistry = sync.OnceValue(NewDefaultRegistry)
+
+// Default returns the process-wide registry used by the adapters. Register
+// custom converters on it during start-up, before repositories are used.
+func Default() *Registry {
+	return defaultRegistry()
+}
+
+// NewRegistry creates an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{entries: map[registryKey]registryEntry{}}
+}
+
+// NewDefaultRegistry creates a Registry with the built-in converters.
+func NewDefaultRegistry() *Registry {
+	registry := NewRegistry()
+
+	Register[uuid.UUID, string](registry, DbTypeSQLite, NewTextUUIDConverter())
+	Register[uuid.UUID, []byte](registry, DbTypeMySQL, NewBinaryUUIDConverter())
+	Register[uuid.UUID, any](registry, DbTypePostgres, NewPostgresUUIDConverter())
+	Register[*uuid.UUID, pgtype.UUID](registry, DbTypePostgres, NewPgUUIDConverter())
+	// CockroachDB is reached through pgx and shares the PostgreSQL types.
+	Register[uuid.UUID, any](registry, DbTypeCockroachDB, NewPostgresUUIDConverter())
+	Register[*uuid.UUID, pgtype.UUID](registry, DbTypeCockroachDB, NewPgUUIDConverter())
+
+	Register[*time.Time, sql.NullTime](registry, AnyEngine, NewNullTimeConverter())
+	Register[*time.Time, pgtype.Timestamptz](registry, DbTypePostgres, NewTimestamptzConverter())
+	Register[*time.Time, pgtype.Timestamptz](registry, DbTypeCockroachDB, NewTimestamptzConverter())
+	Register[*string, sql.NullString](registry, AnyEngine, NewNullStringConverter())
+	Register[*bool, sql.NullBool](registry, AnyEngine, NewNullBoolConverter())
+	Register[*int64, sql.NullInt64](registry, AnyEngine, NewNullInt64Converter())
+
+	Register[time.Time, any](registry, AnyEngine, NewSQLiteTimeConverter())
+	Register[bool, any](registry, AnyEngine, NewSQLiteBoolConverter())
+	Register[entities.Email, string](registry, AnyEngine, NewDefaultEmailConverter())
+	Register[entities.Username, string](registry, AnyEngine, NewDefaultUsernameConverter())
+	Register[entities.PasswordHash, string](registry, AnyEngine, NewDefaultPasswordHashConverter())
+	Register[entities.UserStatus, string](registry, AnyEngine, NewDefaultUserStatusConverter())
+	Register[entities.UserRole, string](registry, AnyEngine, NewDefaultUserRoleConverter())
+	Register[entities.SessionToken, any](registry, AnyEngine, NewDefaultSessionTokenConverter())
+
+	return registry
+}
+
+// Register adds converter for the domain type Domain on engine, replacing any
+// converter registered for the same type and engine.
+func Register[Domain, DB any](registry *Registry, engine string, converter TypeConverter[Domain, DB]) {
+	entry := registryEntry{
+		converter: converter,
+		toDB: func(domain any) any {
+			return converter.DomainToDB(domain.(Domain)) //nolint:forcetypeassert // keyed by Domain
+		},
+		toDomain: func(db any) (any, error) {
+			value, ok := db.(DB)
+			if !ok {
+				return nil, NewConversionError(fmt.Sprintf("expected %v", reflect.TypeFor[DB]()), db)
+			}
+
+			return converter.DBToDomain(value)
+		},
+	}
+
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	registry.entries[registryKey{domain: reflect.TypeFor[Domain](), engine: engine}] = entry
+}
+
+// Lookup returns the converter for Domain on engine, falling back to the one
+// registered for AnyEngine.
+//
+//nolint:ireturn // Converters are looked up by their interface.
+func Lookup[Domain, DB any](registry *Registry, engine string) (TypeConverter[Domain, DB], error) {
+	entry, err := registry.entry(reflect.TypeFor[Domain](), engine)
+	if err != nil {
+		return nil, err
+	}
+
+	converter, ok := entry.converter.(TypeConverter[Domain, DB])
+	if !ok {
+		return nil, fmt.Errorf("type=%v engine=%v db type=%v: %w",
+			reflect.TypeFor[Domain](), engine, reflect.TypeFor[DB](), ErrConverterNotFound)
+	}
+
+	return converter, nil
+}
+
+// FromDB converts a database value into Domain with the registered converter.
+//
+//nolint:ireturn // Generic converters intentionally return type parameters
+func FromDB[Domain any](registry *Registry, engine string, value any) (Domain, error) {
+	var zero Domain
+
+	entry, err := registry.entry(reflect.TypeFor[Domain](), engine)
+	if err != nil {
+		return zero, err
+	}
+
+	converted, err := entry.toDomain(value)
+	if err != nil {
+		return zero, fmt.Errorf("type=%v engine=%v: %w", reflect.TypeFor[Domain](), engine, err)
+	}
+
+	return converted.(Domain), nil //nolint:forcetypeassert // keyed by Domain
+}
+
+// ToDB converts a domain value into its database representation with the
+// converter registered for its dynamic type.
+func (r *Registry) ToDB(engine string, value any) (any, error) {
+	entry, err := r.entry(reflect.TypeOf(value), engine)
+	if err != nil {
+		return nil, err
+	}
+
+	return entry.toDB(value), nil
+}
+
+// Registered reports whether a converter for domain is available on engine.
+func (r *Registry) Registered(domain reflect.Type, engine string) bool {
+	_, err := r.entry(domain, engine)
+
+	return err == nil
+}
+
+// entry finds the converter for domain on engine or AnyEngine.
+func (r *Registry) entry(domain reflect.Type, engine string) (registryEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entry, ok := r.entries[registryKey{domain: domain, engine: engine}]
+	if !ok {
+		entry, ok = r.entries[registryKey{domain: domain, engine: AnyEngine}]
+	}
+
+	if !ok {
+		return registryEntry{}, fmt.Errorf("type=%v engine=%v: %w", domain, engine, ErrConverterNotFound)
+	}
+
+	return entry, nil
+}
@@ -12,9 +12,10 @@ import (
 
 // Database type constants for consistent database identification.
 const (
-	DbTypeSQLite   = "sqlite"
-	DbTypePostgres = "postgres"
-	DbTypeMySQL    = "mysql"
+	DbTypeSQLite      = "sqlite"
+	DbTypePostgres    = "postgres"
+	DbTypeMySQL       = "mysql"
+	DbTypeCockroachDB = "cockroachdb"
 )
 
 // TypeConverter handles database-specific type conversions
@@ -209,7 +210,7 @@ func NewUUIDConverter(database string) UUIDConverter {
 	switch database {
 	case DbTypeSQLite:
 		return NewSQLiteUUIDConverter()
-	case DbTypePostgres:
+	case DbTypePostgres, DbTypeCockroachDB:
 		return NewPostgresUUIDConverter()
 	case DbTypeMySQL:
 		return NewMySQLUUIDConverter()
@@ -391,34 +392,6 @@ func parseUUIDFromAny(dbValue any) (uuid.UUID, error) {
 	}
 }
 
-// ConverterSet holds all type converters for user repository operations.
-type ConverterSet struct {
-	UUID         UUIDConverter
-	Time         TimeConverter
-	Bool         BoolConverter
-	Email        EmailConverter
-	Username     UsernameConverter
-	Password     PasswordHashConverter
-	Status       UserStatusConverter
-	Role         UserRoleConverter
-	SessionToken SessionTokenConverter
-}
-
-// NewConverterSet creates a new ConverterSet for the specified database type.
-func NewConverterSet(database string) *ConverterSet {
-	return &ConverterSet{
-		UUID:         NewUUIDConverter(database),
-		Time:         NewTimeConverter(database),
-		Bool:         NewBoolConverter(database),
-		Email:        NewDefaultEmailConverter(),
-		Username:     NewDefaultUsernameConverter(),
-		Password:     NewDefaultPasswordHashConverter(),
-		Status:       NewDefaultUserStatusConverter(),
-		Role:         NewDefaultUserRoleConverter(),
-		SessionToken: NewDefaultSessionTokenConverter(),
-	}
-}
-
 // Helper functions
 
 // SafeString safely converts interface{} to string.
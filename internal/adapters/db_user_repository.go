@@ -1,6 +1,10 @@
 package adapters
 
 import (
+	"context"
+	"database/sql"
+	"fmt"
+
 	"github.com/LarsArtmann/template-sqlc/internal/adapters/converters"
 	"github.com/LarsArtmann/template-sqlc/internal/db/shared"
 )
@@ -9,13 +13,75 @@ import (
 // Both MySQL and SQLite use the same database/sql-based implementation.
 type DBUserRepository struct {
 	db         shared.DBTX
-	converters *converters.ConverterSet
+	engine     string
+	converters *converters.Registry
 }
 
-// NewDBUserRepository creates a new DBUserRepository with the given database and converter type.
+// NewDBUserRepository creates a new DBUserRepository with the given database and
+// engine, converting values with the default converter registry.
 func NewDBUserRepository(db shared.DBTX, dbType string) *DBUserRepository {
 	return &DBUserRepository{
 		db:         db,
-		converters: converters.NewConverterSet(dbType),
+		engine:     dbType,
+		converters: converters.Default(),
+	}
+}
+
+// DB returns the database handle queries should run against.
+func (r *DBUserRepository) DB() shared.DBTX {
+	return r.db
+}
+
+// Converters returns the registry the repository looks type converters up in.
+func (r *DBUserRepository) Converters() *converters.Registry {
+	return r.converters
+}
+
+// Engine returns the converter engine of the repository's database.
+func (r *DBUserRepository) Engine() string {
+	return r.engine
+}
+
+// BatchChunkSize bounds the rows or IDs sent in one statement by batch
+// operations, keeping them below the placeholder limits of MySQL and SQLite.
+const BatchChunkSize = 1000
+
+// txBeginner is implemented by handles that can start a transaction, such as *sql.DB.
+type txBeginner interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// InTransaction reports whether the repository is bound to a transaction.
+func (r *DBUserRepository) InTransaction() bool {
+	_, ok := r.db.(txBeginner)
+
+	return !ok
+}
+
+// InTx runs fn in a transaction. A repository already bound to a transaction
+// runs fn in it; otherwise a new transaction is committed when fn succeeds.
+func (r *DBUserRepository) InTx(ctx context.Context, fn func(db shared.DBTX) error) error {
+	beginner, ok := r.db.(txBeginner)
+	if !ok {
+		return fn(r.db)
+	}
+
+	tx, err := beginner.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
 	}
+
+	err = fn(tx)
+	if err != nil {
+		_ = tx.Rollback()
+
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return nil
 }
@@ -0,0 +1,169 @@
+// Package deadline provides repository decorators that bound every call with
+// a timeout of its operation class, so that a slow query fails the request
+// instead of holding a connection for as long as the caller waits.
+//
+// A call that exceeds its deadline, whether the timeout of its class or the
+// deadline of the caller, fails with an entities.TimeoutError, which maps to
+// the timeout code of pkg/errors and still matches context.DeadlineExceeded.
+package deadline
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+)
+
+const (
+	defaultReadTimeout   = 5 * time.Second
+	defaultWriteTimeout  = 10 * time.Second
+	defaultSearchTimeout = 15 * time.Second
+)
+
+// Class is the operation class of a repository method, which selects its
+// timeout.
+type Class string
+
+const (
+	// ClassRead is a lookup of single rows by key.
+	ClassRead Class = "read"
+	// ClassWrite changes rows.
+	ClassWrite Class = "write"
+	// ClassSearch lists, searches and aggregates rows.
+	ClassSearch Class = "search"
+)
+
+// Timeouts are the timeouts per operation class. A zero timeout leaves the
+// calls of its class bounded by the deadline of the caller only.
+type Timeouts struct {
+	Read   time.Duration
+	Write  time.Duration
+	Search time.Duration
+}
+
+// DefaultTimeouts returns the timeouts used when none are configured: five
+// seconds for reads, ten for writes and fifteen for searches.
+func DefaultTimeouts() Timeouts {
+	return Timeouts{
+		Read:   defaultReadTimeout,
+		Write:  defaultWriteTimeout,
+		Search: defaultSearchTimeout,
+	}
+}
+
+// of returns the timeout of class.
+func (t Timeouts) of(class Class) time.Duration {
+	switch class {
+	case ClassRead:
+		return t.Read
+	case ClassWrite:
+		return t.Write
+	case ClassSearch:
+		return t.Search
+	default:
+		return 0
+	}
+}
+
+// Observer receives the calls that exceeded their deadline, labeled with the
+// operation, e.g. "UserRepository.GetByID", and its class.
+// *monitoring.Metrics satisfies it.
+type Observer interface {
+	ObserveQueryTimeout(query, class string)
+}
+
+// Option configures a decorator.
+type Option func(*limiter)
+
+// WithTimeouts sets the timeouts per operation class. Defaults to
+// DefaultTimeouts.
+func WithTimeouts(timeouts Timeouts) Option {
+	return func(l *limiter) {
+		l.timeouts = timeouts
+	}
+}
+
+// WithObserver reports the calls that exceeded their deadline to observer.
+func WithObserver(observer Observer) Option {
+	return func(l *limiter) {
+		l.observer = observer
+	}
+}
+
+// limiter bounds the calls of one repository.
+type limiter struct {
+	name     string
+	timeouts Timeouts
+	observer Observer
+}
+
+func newLimiter(name string, opts []Option) *limiter {
+	l := &limiter{
+		name:     name,
+		timeouts: DefaultTimeouts(),
+		observer: nopObserver{},
+	}
+
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	return l
+}
+
+// call runs fn for method with the timeout of class and translates an
+// exceeded deadline into an entities.TimeoutError.
+func call[T any](
+	ctx context.Context,
+	l *limiter,
+	class Class,
+	method string,
+	fn func(ctx context.Context) (T, error),
+) (T, error) {
+	parent := ctx
+
+	timeout := l.timeouts.of(class)
+	if timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	result, err := fn(ctx)
+	if err == nil || entities.IsTimeoutError(err) || !exceeded(ctx, err) {
+		return result, err
+	}
+
+	// The deadline of the caller came first.
+	if parent.Err() != nil {
+		timeout = 0
+	}
+
+	operation := l.name + "." + method
+	l.observer.ObserveQueryTimeout(operation, string(class))
+
+	return result, entities.NewTimeoutError(operation, timeout, err)
+}
+
+// exec is call for methods that only return an error.
+func exec(ctx context.Context, l *limiter, class Class, method string, fn func(ctx context.Context) error) error {
+	_, err := call(ctx, l, class, method, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, fn(ctx)
+	})
+
+	return err
+}
+
+// exceeded reports whether err failed a call because ctx passed its
+// deadline. Drivers do not always wrap the context error, so an expired ctx
+// counts as well.
+func exceeded(ctx context.Context, err error) bool {
+	return errors.Is(err, context.DeadlineExceeded) || errors.Is(ctx.Err(), context.DeadlineExceeded)
+}
+
+// nopObserver discards observations.
+type nopObserver struct{}
+
+func (nopObserver) ObserveQueryTimeout(string, string) {}
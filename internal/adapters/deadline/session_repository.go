@@ -0,0 +1,124 @@
+package deadline
+
+import (
+	"context"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// SessionRepository bounds every method of a wrapped session repository with
+// the timeout of its operation class.
+type SessionRepository struct {
+	next    repositories.SessionRepository
+	limiter *limiter
+}
+
+// NewSessionRepository wraps repo with per-class timeouts.
+func NewSessionRepository(repo repositories.SessionRepository, opts ...Option) *SessionRepository {
+	return &SessionRepository{
+		next:    repo,
+		limiter: newLimiter("SessionRepository", opts),
+	}
+}
+
+// Ensure SessionRepository implements the domain SessionRepository.
+var _ repositories.SessionRepository = (*SessionRepository)(nil)
+
+// Create creates a session.
+func (r *SessionRepository) Create(ctx context.Context, session *entities.UserSession) error {
+	return exec(ctx, r.limiter, ClassWrite, "Create", func(ctx context.Context) error {
+		return r.next.Create(ctx, session)
+	})
+}
+
+// GetByToken retrieves a session by token.
+func (r *SessionRepository) GetByToken(
+	ctx context.Context,
+	token entities.SessionToken,
+) (*entities.UserSession, error) {
+	return call(ctx, r.limiter, ClassRead, "GetByToken", func(ctx context.Context) (*entities.UserSession, error) {
+		return r.next.GetByToken(ctx, token)
+	})
+}
+
+// GetByRefreshToken retrieves a session by refresh token.
+func (r *SessionRepository) GetByRefreshToken(
+	ctx context.Context,
+	token entities.RefreshToken,
+) (*entities.UserSession, error) {
+	return call(ctx, r.limiter, ClassRead, "GetByRefreshToken", func(ctx context.Context) (*entities.UserSession, error) {
+		return r.next.GetByRefreshToken(ctx, token)
+	})
+}
+
+// GetByUserID retrieves the sessions of a user.
+func (r *SessionRepository) GetByUserID(
+	ctx context.Context,
+	userID entities.UserID,
+	activeOnly bool,
+) ([]*entities.UserSession, error) {
+	return call(ctx, r.limiter, ClassRead, "GetByUserID", func(ctx context.Context) ([]*entities.UserSession, error) {
+		return r.next.GetByUserID(ctx, userID, activeOnly)
+	})
+}
+
+// Update updates a session.
+func (r *SessionRepository) Update(ctx context.Context, session *entities.UserSession) error {
+	return exec(ctx, r.limiter, ClassWrite, "Update", func(ctx context.Context) error {
+		return r.next.Update(ctx, session)
+	})
+}
+
+// UpdateIfCurrent updates a session unless it changed since it was read.
+func (r *SessionRepository) UpdateIfCurrent(
+	ctx context.Context,
+	session *entities.UserSession,
+	expected entities.RefreshToken,
+) error {
+	return exec(ctx, r.limiter, ClassWrite, "UpdateIfCurrent", func(ctx context.Context) error {
+		return r.next.UpdateIfCurrent(ctx, session, expected)
+	})
+}
+
+// Delete deletes a session.
+func (r *SessionRepository) Delete(ctx context.Context, id entities.SessionID) error {
+	return exec(ctx, r.limiter, ClassWrite, "Delete", func(ctx context.Context) error {
+		return r.next.Delete(ctx, id)
+	})
+}
+
+// DeactivateByToken deactivates a session by token.
+func (r *SessionRepository) DeactivateByToken(ctx context.Context, token entities.SessionToken) error {
+	return exec(ctx, r.limiter, ClassWrite, "DeactivateByToken", func(ctx context.Context) error {
+		return r.next.DeactivateByToken(ctx, token)
+	})
+}
+
+// DeactivateByUserID deactivates every session of a user.
+func (r *SessionRepository) DeactivateByUserID(ctx context.Context, userID entities.UserID) error {
+	return exec(ctx, r.limiter, ClassWrite, "DeactivateByUserID", func(ctx context.Context) error {
+		return r.next.DeactivateByUserID(ctx, userID)
+	})
+}
+
+// CleanupExpired removes expired sessions.
+func (r *SessionRepository) CleanupExpired(ctx context.Context) (int64, error) {
+	return call(ctx, r.limiter, ClassWrite, "CleanupExpired", func(ctx context.Context) (int64, error) {
+		return r.next.CleanupExpired(ctx)
+	})
+}
+
+// GetActiveSessions counts the active sessions of a user.
+func (r *SessionRepository) GetActiveSessions(ctx context.Context, userID entities.UserID) (int64, error) {
+	return call(ctx, r.limiter, ClassRead, "GetActiveSessions", func(ctx context.Context) (int64, error) {
+		return r.next.GetActiveSessions(ctx, userID)
+	})
+}
+
+// GetSessionStats returns aggregate session statistics.
+func (r *SessionRepository) GetSessionStats(ctx context.Context) (*entities.SessionStats, error) {
+	return call(ctx, r.limiter, ClassSearch, "GetSessionStats", func(ctx context.Context) (*entities.SessionStats, error) {
+		return r.next.GetSessionStats(ctx)
+	})
+}
@@ -0,0 +1,260 @@
+package deadline
+
+import (
+	"context"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// UserRepository bounds every method of a wrapped user repository with the
+// timeout of its operation class.
+type UserRepository struct {
+	next    repositories.UserRepository
+	limiter *limiter
+}
+
+// NewUserRepository wraps repo with per-class timeouts.
+func NewUserRepository(repo repositories.UserRepository, opts ...Option) *UserRepository {
+	return &UserRepository{
+		next:    repo,
+		limiter: newLimiter("UserRepository", opts),
+	}
+}
+
+// Ensure UserRepository implements the domain UserRepository.
+var _ repositories.UserRepository = (*UserRepository)(nil)
+
+// Create creates a user.
+func (r *UserRepository) Create(ctx context.Context, user *entities.User) error {
+	return exec(ctx, r.limiter, ClassWrite, "Create", func(ctx context.Context) error {
+		return r.next.Create(ctx, user)
+	})
+}
+
+// GetByID retrieves a user by ID.
+func (r *UserRepository) GetByID(ctx context.Context, id entities.UserID) (*entities.User, error) {
+	return call(ctx, r.limiter, ClassRead, "GetByID", func(ctx context.Context) (*entities.User, error) {
+		return r.next.GetByID(ctx, id)
+	})
+}
+
+// GetByUUID retrieves a user by UUID.
+func (r *UserRepository) GetByUUID(ctx context.Context, uuid entities.UuID) (*entities.User, error) {
+	return call(ctx, r.limiter, ClassRead, "GetByUUID", func(ctx context.Context) (*entities.User, error) {
+		return r.next.GetByUUID(ctx, uuid)
+	})
+}
+
+// GetByIDs retrieves the users with the given IDs.
+func (r *UserRepository) GetByIDs(ctx context.Context, ids []entities.UserID) ([]*entities.User, error) {
+	return call(ctx, r.limiter, ClassRead, "GetByIDs", func(ctx context.Context) ([]*entities.User, error) {
+		return r.next.GetByIDs(ctx, ids)
+	})
+}
+
+// GetByEmail retrieves a user by email.
+func (r *UserRepository) GetByEmail(ctx context.Context, email entities.Email) (*entities.User, error) {
+	return call(ctx, r.limiter, ClassRead, "GetByEmail", func(ctx context.Context) (*entities.User, error) {
+		return r.next.GetByEmail(ctx, email)
+	})
+}
+
+// GetByUsername retrieves a user by username.
+func (r *UserRepository) GetByUsername(
+	ctx context.Context,
+	username entities.Username,
+) (*entities.User, error) {
+	return call(ctx, r.limiter, ClassRead, "GetByUsername", func(ctx context.Context) (*entities.User, error) {
+		return r.next.GetByUsername(ctx, username)
+	})
+}
+
+// Update updates a user.
+func (r *UserRepository) Update(ctx context.Context, user *entities.User) error {
+	return exec(ctx, r.limiter, ClassWrite, "Update", func(ctx context.Context) error {
+		return r.next.Update(ctx, user)
+	})
+}
+
+// Delete soft deletes a user.
+func (r *UserRepository) Delete(ctx context.Context, id entities.UserID) error {
+	return exec(ctx, r.limiter, ClassWrite, "Delete", func(ctx context.Context) error {
+		return r.next.Delete(ctx, id)
+	})
+}
+
+// Restore restores a soft-deleted user.
+func (r *UserRepository) Restore(ctx context.Context, id entities.UserID) error {
+	return exec(ctx, r.limiter, ClassWrite, "Restore", func(ctx context.Context) error {
+		return r.next.Restore(ctx, id)
+	})
+}
+
+// PurgeDeletedOlderThan permanently removes users soft deleted before cutoff.
+func (r *UserRepository) PurgeDeletedOlderThan(ctx context.Context, cutoff time.Time) (int64, error) {
+	return call(ctx, r.limiter, ClassWrite, "PurgeDeletedOlderThan", func(ctx context.Context) (int64, error) {
+		return r.next.PurgeDeletedOlderThan(ctx, cutoff)
+	})
+}
+
+// CreateBatch creates the users.
+func (r *UserRepository) CreateBatch(ctx context.Context, users []*entities.User) error {
+	return exec(ctx, r.limiter, ClassWrite, "CreateBatch", func(ctx context.Context) error {
+		return r.next.CreateBatch(ctx, users)
+	})
+}
+
+// UpdateStatusBatch changes the status of the users.
+func (r *UserRepository) UpdateStatusBatch(
+	ctx context.Context,
+	ids []entities.UserID,
+	status entities.UserStatus,
+) (int64, error) {
+	return call(ctx, r.limiter, ClassWrite, "UpdateStatusBatch", func(ctx context.Context) (int64, error) {
+		return r.next.UpdateStatusBatch(ctx, ids, status)
+	})
+}
+
+// DeleteBatch soft deletes the users.
+func (r *UserRepository) DeleteBatch(ctx context.Context, ids []entities.UserID) (int64, error) {
+	return call(ctx, r.limiter, ClassWrite, "DeleteBatch", func(ctx context.Context) (int64, error) {
+		return r.next.DeleteBatch(ctx, ids)
+	})
+}
+
+// List returns a page of users matching filter.
+func (r *UserRepository) List(
+	ctx context.Context,
+	filter entities.UserFilter,
+	limit, offset int,
+) ([]*entities.User, error) {
+	return call(ctx, r.limiter, ClassSearch, "List", func(ctx context.Context) ([]*entities.User, error) {
+		return r.next.List(ctx, filter, limit, offset)
+	})
+}
+
+// ListPage returns a keyset-paginated page of users matching filter.
+func (r *UserRepository) ListPage(
+	ctx context.Context,
+	filter entities.UserFilter,
+	cursor string,
+	limit int,
+) (*entities.UserPage, error) {
+	return call(ctx, r.limiter, ClassSearch, "ListPage", func(ctx context.Context) (*entities.UserPage, error) {
+		return r.next.ListPage(ctx, filter, cursor, limit)
+	})
+}
+
+// SearchWithFacets searches users and counts the matches per facet.
+func (r *UserRepository) SearchWithFacets(
+	ctx context.Context,
+	query string,
+	status entities.UserStatus,
+	limit int,
+) (*entities.UserSearchResult, error) {
+	search := func(ctx context.Context) (*entities.UserSearchResult, error) {
+		return r.next.SearchWithFacets(ctx, query, status, limit)
+	}
+
+	return call(ctx, r.limiter, ClassSearch, "SearchWithFacets", search)
+}
+
+// CountByStatus counts users per status.
+func (r *UserRepository) CountByStatus(ctx context.Context) (map[entities.UserStatus]int64, error) {
+	count := func(ctx context.Context) (map[entities.UserStatus]int64, error) {
+		return r.next.CountByStatus(ctx)
+	}
+
+	return call(ctx, r.limiter, ClassSearch, "CountByStatus", count)
+}
+
+// GetStats returns aggregate user statistics.
+func (r *UserRepository) GetStats(ctx context.Context) (*entities.UserStats, error) {
+	return call(ctx, r.limiter, ClassSearch, "GetStats", func(ctx context.Context) (*entities.UserStats, error) {
+		return r.next.GetStats(ctx)
+	})
+}
+
+// GetCredentials returns a user with its password hash.
+func (r *UserRepository) GetCredentials(ctx context.Context, email entities.Email) (*entities.User, error) {
+	return call(ctx, r.limiter, ClassRead, "GetCredentials", func(ctx context.Context) (*entities.User, error) {
+		return r.next.GetCredentials(ctx, email)
+	})
+}
+
+// UpdatePassword updates the password of a user.
+func (r *UserRepository) UpdatePassword(
+	ctx context.Context,
+	id entities.UserID,
+	password entities.PasswordHash,
+) error {
+	return exec(ctx, r.limiter, ClassWrite, "UpdatePassword", func(ctx context.Context) error {
+		return r.next.UpdatePassword(ctx, id, password)
+	})
+}
+
+// MarkVerified marks a user verified.
+func (r *UserRepository) MarkVerified(ctx context.Context, id entities.UserID) error {
+	return exec(ctx, r.limiter, ClassWrite, "MarkVerified", func(ctx context.Context) error {
+		return r.next.MarkVerified(ctx, id)
+	})
+}
+
+// ChangeStatus changes the status of a user.
+func (r *UserRepository) ChangeStatus(ctx context.Context, id entities.UserID, status entities.UserStatus) error {
+	return exec(ctx, r.limiter, ClassWrite, "ChangeStatus", func(ctx context.Context) error {
+		return r.next.ChangeStatus(ctx, id, status)
+	})
+}
+
+// Activate activates a user.
+func (r *UserRepository) Activate(ctx context.Context, id entities.UserID) error {
+	return exec(ctx, r.limiter, ClassWrite, "Activate", func(ctx context.Context) error {
+		return r.next.Activate(ctx, id)
+	})
+}
+
+// Deactivate deactivates a user.
+func (r *UserRepository) Deactivate(ctx context.Context, id entities.UserID) error {
+	return exec(ctx, r.limiter, ClassWrite, "Deactivate", func(ctx context.Context) error {
+		return r.next.Deactivate(ctx, id)
+	})
+}
+
+// Suspend suspends a user.
+func (r *UserRepository) Suspend(ctx context.Context, id entities.UserID) error {
+	return exec(ctx, r.limiter, ClassWrite, "Suspend", func(ctx context.Context) error {
+		return r.next.Suspend(ctx, id)
+	})
+}
+
+// ChangeRole changes the role of a user.
+func (r *UserRepository) ChangeRole(ctx context.Context, id entities.UserID, role entities.UserRole) error {
+	return exec(ctx, r.limiter, ClassWrite, "ChangeRole", func(ctx context.Context) error {
+		return r.next.ChangeRole(ctx, id, role)
+	})
+}
+
+// GetByIDForUpdate retrieves and locks a user.
+func (r *UserRepository) GetByIDForUpdate(
+	ctx context.Context,
+	id entities.UserID,
+	opts ...repositories.LockOption,
+) (*entities.User, error) {
+	return call(ctx, r.limiter, ClassWrite, "GetByIDForUpdate", func(ctx context.Context) (*entities.User, error) {
+		return r.next.GetByIDForUpdate(ctx, id, opts...)
+	})
+}
+
+// ClaimNext locks up to limit users in the given status.
+func (r *UserRepository) ClaimNext(
+	ctx context.Context,
+	status entities.UserStatus,
+	limit int,
+) ([]*entities.User, error) {
+	return call(ctx, r.limiter, ClassWrite, "ClaimNext", func(ctx context.Context) ([]*entities.User, error) {
+		return r.next.ClaimNext(ctx, status, limit)
+	})
+}
@@ -0,0 +1,162 @@
+package duckdb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// defaultSyncBatchSize is the page size users are read from the source with.
+const defaultSyncBatchSize = 500
+
+// usersSyncName keys the users copy in sync_state.
+const usersSyncName = "users"
+
+const upsertSyncState = `
+INSERT OR REPLACE INTO sync_state (name, synced_at, row_count) VALUES (?, ?, ?)`
+
+const getSyncState = `SELECT synced_at FROM sync_state WHERE name = ?`
+
+// Syncer copies the live users of the primary store into DuckDB.
+type Syncer struct {
+	source    repositories.UserRepository
+	db        *sql.DB
+	batchSize int
+}
+
+// SyncOption configures a Syncer.
+type SyncOption func(*Syncer)
+
+// WithBatchSize sets how many users are read from the source and inserted
+// at a time, at most 1000.
+func WithBatchSize(size int) SyncOption {
+	return func(s *Syncer) {
+		if size > 0 && size <= 1000 {
+			s.batchSize = size
+		}
+	}
+}
+
+// NewSyncer creates a syncer copying the users of source into db.
+func NewSyncer(source repositories.UserRepository, db *sql.DB, opts ...SyncOption) *Syncer {
+	s := &Syncer{source: source, db: db, batchSize: defaultSyncBatchSize}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Sync replaces the copy with the users of the source and returns how many
+// were copied. The copy is replaced in one transaction, so readers see the
+// previous or the new copy, never a mix; the source is read page by page,
+// so users changed during the sync may appear in either state.
+func (s *Syncer) Sync(ctx context.Context) (int64, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin sync: %w", err)
+	}
+
+	defer func() { _ = tx.Rollback() }()
+
+	_, err = tx.ExecContext(ctx, "DELETE FROM users")
+	if err != nil {
+		return 0, fmt.Errorf("clear users copy: %w", err)
+	}
+
+	var (
+		copied int64
+		cursor string
+		page   *entities.UserPage
+	)
+
+	for {
+		page, err = s.source.ListPage(ctx, entities.UserFilter{}, cursor, s.batchSize)
+		if err != nil {
+			return 0, fmt.Errorf("read users after %d: %w", copied, err)
+		}
+
+		err = insertUsers(ctx, tx, page.Users)
+		if err != nil {
+			return 0, err
+		}
+
+		copied += int64(len(page.Users))
+
+		if page.NextCursor == "" {
+			break
+		}
+
+		cursor = page.NextCursor
+	}
+
+	_, err = tx.ExecContext(ctx, upsertSyncState, usersSyncName, time.Now().UTC(), copied)
+	if err != nil {
+		return 0, fmt.Errorf("record sync: %w", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return 0, fmt.Errorf("commit sync: %w", err)
+	}
+
+	return copied, nil
+}
+
+// insertUsers copies users in one multi-row statement.
+func insertUsers(ctx context.Context, tx *sql.Tx, users []*entities.User) error {
+	if len(users) == 0 {
+		return nil
+	}
+
+	const columns = 6
+
+	rows := make([]string, 0, len(users))
+	args := make([]any, 0, len(users)*columns)
+
+	for _, user := range users {
+		rows = append(rows, "(?, ?, ?, ?, ?, ?)")
+		args = append(args,
+			int64(user.ID()),
+			string(user.Status()),
+			string(user.Role()),
+			user.IsVerified(),
+			user.CreatedAt().UTC(),
+			user.LastLoginAt(),
+		)
+	}
+
+	query := "INSERT OR REPLACE INTO users (id, status, role, is_verified, created_at, last_login_at) VALUES " +
+		strings.Join(rows, ", ")
+
+	_, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("copy %d users: %w", len(users), err)
+	}
+
+	return nil
+}
+
+// SyncedAt returns when the users were last synced, or the zero time if
+// they never were.
+func (r *UserRepository) SyncedAt(ctx context.Context) (time.Time, error) {
+	var syncedAt time.Time
+
+	err := r.db.QueryRowContext(ctx, getSyncState, usersSyncName).Scan(&syncedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, nil
+	}
+
+	if err != nil {
+		return time.Time{}, fmt.Errorf("users synced at: %w", handleError(err, "get sync state"))
+	}
+
+	return syncedAt, nil
+}
@@ -0,0 +1,140 @@
+package duckdb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	apperrors "github.com/LarsArtmann/template-sqlc/pkg/errors"
+)
+
+const countUsersByStatus = `SELECT status, count(*) FROM users GROUP BY status`
+
+// getUserStats mirrors GetUserStats of the primary stores. Deleted users are
+// not synced, so every copied user counts as active.
+const getUserStats = `
+SELECT
+    count(*) AS total_users,
+    count(*) AS active_users,
+    count(*) FILTER (WHERE is_verified) AS verified_users,
+    count(*) FILTER (WHERE last_login_at IS NOT NULL) AS users_with_logins,
+    count(*) FILTER (WHERE status = 'inactive') AS inactive_users,
+    count(*) FILTER (WHERE status = 'suspended') AS suspended_users,
+    count(*) FILTER (WHERE created_at >= now() - INTERVAL 30 DAY) AS new_users_30d,
+    count(*) FILTER (WHERE created_at >= now() - INTERVAL 7 DAY) AS new_users_7d
+FROM users`
+
+// listUserCohorts groups users by the UTC start of their signup period; the
+// interval is validated before it is bound.
+const listUserCohorts = `
+SELECT
+    date_trunc(?, timezone('UTC', created_at)) AS period_start,
+    count(*) AS users,
+    count(*) FILTER (WHERE is_verified) AS verified,
+    count(*) FILTER (WHERE last_login_at IS NOT NULL) AS logged_in,
+    count(*) FILTER (WHERE status = 'active') AS active
+FROM users
+WHERE created_at >= ?
+GROUP BY period_start
+ORDER BY period_start`
+
+// CountByStatus returns the number of users per status.
+func (r *UserRepository) CountByStatus(ctx context.Context) (map[entities.UserStatus]int64, error) {
+	rows, err := r.db.QueryContext(ctx, countUsersByStatus)
+	if err != nil {
+		return nil, fmt.Errorf("count users by status: %w", handleError(err, "count users by status"))
+	}
+
+	defer func() { _ = rows.Close() }()
+
+	counts := make(map[entities.UserStatus]int64)
+
+	for rows.Next() {
+		var (
+			status string
+			total  int64
+		)
+
+		err = rows.Scan(&status, &total)
+		if err != nil {
+			return nil, fmt.Errorf("count users by status: %w", handleError(err, "scan status count"))
+		}
+
+		counts[entities.UserStatus(status)] = total
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("count users by status: %w", handleError(err, "count users by status"))
+	}
+
+	return counts, nil
+}
+
+// GetStats returns the user statistics of the last sync.
+func (r *UserRepository) GetStats(ctx context.Context) (*entities.UserStats, error) {
+	var stats entities.UserStats
+
+	err := r.db.QueryRowContext(ctx, getUserStats).Scan(
+		&stats.TotalUsers,
+		&stats.ActiveUsers,
+		&stats.VerifiedUsers,
+		&stats.UsersWithLogins,
+		&stats.InactiveUsers,
+		&stats.SuspendedUsers,
+		&stats.NewUsers30d,
+		&stats.NewUsers7d,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get user stats: %w", handleError(err, "get user stats"))
+	}
+
+	stats.ComputeRates()
+
+	return &stats, nil
+}
+
+// Cohorts groups the users who signed up at or after since by interval,
+// oldest period first. Periods without signups are omitted.
+func (r *UserRepository) Cohorts(
+	ctx context.Context,
+	interval entities.CohortInterval,
+	since time.Time,
+) ([]entities.UserCohort, error) {
+	if !interval.IsValid() {
+		return nil, fmt.Errorf("interval=%v: %w", interval, entities.ErrInvalidCohort)
+	}
+
+	rows, err := r.db.QueryContext(ctx, listUserCohorts, string(interval), since.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("user cohorts interval=%v: %w", interval, handleError(err, "list user cohorts"))
+	}
+
+	defer func() { _ = rows.Close() }()
+
+	var cohorts []entities.UserCohort
+
+	for rows.Next() {
+		var cohort entities.UserCohort
+
+		err = rows.Scan(&cohort.Start, &cohort.Users, &cohort.Verified, &cohort.LoggedIn, &cohort.Active)
+		if err != nil {
+			return nil, fmt.Errorf("user cohorts interval=%v: %w", interval, handleError(err, "scan user cohort"))
+		}
+
+		cohorts = append(cohorts, cohort)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("user cohorts interval=%v: %w", interval, handleError(err, "list user cohorts"))
+	}
+
+	return cohorts, nil
+}
+
+// handleError wraps a driver error as a database error.
+func handleError(err error, operation string) error {
+	return apperrors.NewDatabaseError(operation+" failed", err)
+}
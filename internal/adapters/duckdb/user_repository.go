@@ -0,0 +1,55 @@
+// Package duckdb answers the aggregate user queries from DuckDB, an
+// analytical copy of the users kept apart from the primary store so that
+// reporting does not load it.
+//
+// A Syncer copies the live users of the primary store into the schema in
+// sql/duckdb, typically from the analytics.sync job; the repository then
+// serves CountByStatus, GetStats and cohort queries from the copy. Every
+// other UserRepository method is not implemented.
+//
+// The adapter uses database/sql only; the caller imports the driver,
+// github.com/duckdb/duckdb-go/v2, which needs cgo:
+//
+//	db, err := sql.Open("duckdb", "analytics.duckdb")
+package duckdb
+
+import (
+	"github.com/LarsArtmann/template-sqlc/internal/adapters"
+	"github.com/LarsArtmann/template-sqlc/internal/db/shared"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// UserRepository implements the aggregate reads of UserRepository and
+// UserAnalyticsRepository over the DuckDB copy of the users.
+type UserRepository struct {
+	*adapters.NotImplementedUserRepository
+
+	db shared.DBTX
+}
+
+var (
+	_ repositories.UserRepository          = (*UserRepository)(nil)
+	_ repositories.UserAnalyticsRepository = (*UserRepository)(nil)
+)
+
+// NewUserRepository creates a DuckDB user repository.
+func NewUserRepository(db shared.DBTX) repositories.UserRepository {
+	return newUserRepository(db)
+}
+
+// NewAnalyticsRepository creates a DuckDB user analytics repository.
+func NewAnalyticsRepository(db shared.DBTX) repositories.UserAnalyticsRepository {
+	return newUserRepository(db)
+}
+
+func newUserRepository(db shared.DBTX) *UserRepository {
+	return &UserRepository{
+		NotImplementedUserRepository: adapters.NewNotImplementedUserRepository("DuckDB"),
+		db:                           db,
+	}
+}
+
+// Engine returns the name of the repository's database.
+func (r *UserRepository) Engine() string {
+	return "duckdb"
+}
@@ -0,0 +1,67 @@
+// Package email sends the emails of the user lifecycle: address
+// verification, password resets and suspension notices.
+//
+// An EmailSender delivers a Message over SMTP or Amazon SES. Templates
+// renders the messages from the templates embedded in the binary, and a
+// Notifier sends them in reaction to the domain events of an in-process
+// dispatcher:
+//
+//	sender := email.NewSMTPSender("smtp.example.com:587", smtp.PlainAuth("", user, password, "smtp.example.com"))
+//	notifier := email.NewNotifier(sender, repo, "App <no-reply@example.com>", email.Links{
+//		Verification:  "https://app.example.com/verify-email",
+//		PasswordReset: "https://app.example.com/reset-password",
+//	})
+//	dispatcher.Subscribe("email", notifier, notifier.EventTypes()...)
+package email
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+)
+
+// ErrInvalidMessage is returned for a message without a sender, recipient,
+// subject or body.
+var ErrInvalidMessage = errors.New("invalid email message")
+
+// EmailSender delivers email messages.
+type EmailSender interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// Message is an email with a plain text and an optional HTML body.
+type Message struct {
+	From    string
+	To      []string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Validate reports a message that cannot be sent.
+func (m Message) Validate() error {
+	if m.From == "" || len(m.To) == 0 || m.Subject == "" || m.Text == "" {
+		return fmt.Errorf("from=%q to=%q subject=%q: %w", m.From, m.To, m.Subject, ErrInvalidMessage)
+	}
+
+	for _, address := range append([]string{m.From}, m.To...) {
+		_, err := mail.ParseAddress(address)
+		if err != nil {
+			return fmt.Errorf("address=%q: %w: %w", address, ErrInvalidMessage, err)
+		}
+	}
+
+	return nil
+}
+
+// envelopeAddress returns the bare address of a header address such as
+// "App <no-reply@example.com>".
+func envelopeAddress(address string) string {
+	parsed, err := mail.ParseAddress(address)
+	if err != nil {
+		return address
+	}
+
+	return parsed.Address
+}
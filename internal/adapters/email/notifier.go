@@ -0,0 +1,334 @@
+package email
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/services"
+)
+
+// Links are the pages the emailed tokens are redeemed on. The token is
+// added to them as the token query parameter.
+type Links struct {
+	Verification  string
+	PasswordReset string
+	// EmailChange and IdentityRevert are optional; without them, email
+	// change confirmations and revert links are not sent.
+	EmailChange    string
+	IdentityRevert string
+}
+
+// Notifier is an events.EventHandler that emails users about verification
+// requests, password reset requests, email address and username changes and
+// suspensions. It reloads the
+// recipients rather than reading addresses from the events, whose personal
+// data may be redacted. Events may be delivered more than once, so a user
+// may rarely receive an email twice.
+//
+// A Notifier is also the email channel of a services.NotificationService.
+type Notifier struct {
+	sender    EmailSender
+	users     repositories.UserRepository
+	templates *Templates
+	from      string
+	links     Links
+}
+
+var (
+	_ events.EventHandler         = (*Notifier)(nil)
+	_ services.NotificationSender = (*Notifier)(nil)
+)
+
+// NotifierOption configures a Notifier.
+type NotifierOption func(*Notifier)
+
+// WithTemplates renders the emails with templates instead of DefaultTemplates.
+func WithTemplates(templates *Templates) NotifierOption {
+	return func(n *Notifier) {
+		n.templates = templates
+	}
+}
+
+// NewNotifier creates a notifier that sends emails from the address from
+// with sender to the users of users.
+func NewNotifier(
+	sender EmailSender,
+	users repositories.UserRepository,
+	from string,
+	links Links,
+	opts ...NotifierOption,
+) *Notifier {
+	n := &Notifier{sender: sender, users: users, from: from, links: links}
+
+	for _, opt := range opts {
+		opt(n)
+	}
+
+	if n.templates == nil {
+		n.templates = DefaultTemplates()
+	}
+
+	return n
+}
+
+// EventTypes returns the event types that send emails, to subscribe the
+// notifier with.
+func (n *Notifier) EventTypes() []events.EventType {
+	types := []events.EventType{
+		events.EventUserVerificationRequested,
+		events.EventPasswordResetRequested,
+		events.EventUserSuspended,
+		events.EventUsersBulkUpdated,
+		events.EventDeletionRequested,
+	}
+
+	if n.links.EmailChange != "" {
+		types = append(types, events.EventEmailChangeRequested)
+	}
+
+	if n.links.IdentityRevert != "" {
+		types = append(types, events.EventEmailChanged, events.EventUsernameChanged)
+	}
+
+	return types
+}
+
+// Handle sends the email of event. Events of other types, and bulk updates
+// other than suspensions, are ignored.
+func (n *Notifier) Handle(ctx context.Context, event *events.UserEvent) error {
+	switch event.Type {
+	case events.EventUserVerificationRequested:
+		return n.sendToken(ctx, event, TemplateVerification, n.links.Verification)
+	case events.EventPasswordResetRequested:
+		return n.sendToken(ctx, event, TemplatePasswordReset, n.links.PasswordReset)
+	case events.EventEmailChangeRequested:
+		var data events.EmailChangeRequestedEvent
+
+		err := decodeData(event, &data)
+		if err != nil {
+			return err
+		}
+
+		// The confirmation goes to the new address, proving the user owns it.
+		return n.sendLink(ctx, event.UserID, data.Email, TemplateEmailChange, n.links.EmailChange, data.Token,
+			TemplateData{ExpiresAt: data.ExpiresAt})
+	case events.EventEmailChanged, events.EventUsernameChanged:
+		var data events.IdentityChangeEvent
+
+		err := decodeData(event, &data)
+		if err != nil {
+			return err
+		}
+
+		// The revert link of an email change goes to the old address, in
+		// case the account was taken over.
+		var to string
+		if data.Field == entities.IdentityFieldEmail.String() {
+			to = data.OldValue
+		}
+
+		return n.sendLink(ctx, event.UserID, to, TemplateIdentityChanged, n.links.IdentityRevert, data.RevertToken,
+			TemplateData{ExpiresAt: data.RevertableUntil, Field: data.Field})
+	case events.EventUserSuspended:
+		var data events.UserSuspendedEvent
+
+		err := decodeData(event, &data)
+		if err != nil {
+			return err
+		}
+
+		return n.send(ctx, event.UserID, TemplateSuspension, TemplateData{Reason: data.Reason})
+	case events.EventDeletionRequested:
+		var data events.AccountDeletionEvent
+
+		err := decodeData(event, &data)
+		if err != nil {
+			return err
+		}
+
+		return n.send(ctx, event.UserID, TemplateAccountDeletion, TemplateData{ScheduledFor: data.ScheduledFor})
+	case events.EventUsersBulkUpdated:
+		var data events.UsersBulkUpdatedEvent
+
+		err := decodeData(event, &data)
+		if err != nil {
+			return err
+		}
+
+		if data.Field != "status" || data.Value != entities.UserStatusSuspended.String() {
+			return nil
+		}
+
+		for _, id := range data.UserIDs {
+			err = n.send(ctx, id, TemplateSuspension, TemplateData{})
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	default:
+		return nil
+	}
+}
+
+// sendToken emails the link to redeem the token of event on page. Tokens
+// that expired before they could be sent are dropped.
+func (n *Notifier) sendToken(ctx context.Context, event *events.UserEvent, kind Template, page string) error {
+	var data events.TokenRequestedEvent
+
+	err := decodeData(event, &data)
+	if err != nil {
+		return err
+	}
+
+	if !data.ExpiresAt.IsZero() && !time.Now().Before(data.ExpiresAt) {
+		return nil
+	}
+
+	link, err := tokenLink(page, data.Token)
+	if err != nil {
+		return fmt.Errorf("email template=%v link: %w", kind, err)
+	}
+
+	return n.send(ctx, event.UserID, kind, TemplateData{Link: link, ExpiresAt: data.ExpiresAt})
+}
+
+// sendLink emails the link to redeem token on page to the user with id, at
+// the address to or the user's own if to is empty. Nothing is sent without
+// a page, for expired tokens or to users that were deleted meanwhile.
+func (n *Notifier) sendLink(
+	ctx context.Context,
+	id entities.UserID,
+	to string,
+	kind Template,
+	page, token string,
+	data TemplateData,
+) error {
+	if page == "" || !time.Now().Before(data.ExpiresAt) {
+		return nil
+	}
+
+	link, err := tokenLink(page, token)
+	if err != nil {
+		return fmt.Errorf("email template=%v link: %w", kind, err)
+	}
+
+	user, err := n.users.GetByID(ctx, id)
+	if entities.IsNotFoundError(err) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("load email recipient id=%v: %w", id, err)
+	}
+
+	if to == "" {
+		to = user.Email().String()
+	}
+
+	data.Link = link
+
+	return n.sendToAddress(ctx, user, to, kind, data)
+}
+
+// send renders kind for the user with id and sends it to the user's
+// address. Users that were deleted meanwhile are skipped, and so are
+// verification emails to users that were verified meanwhile.
+func (n *Notifier) send(ctx context.Context, id entities.UserID, kind Template, data TemplateData) error {
+	user, err := n.users.GetByID(ctx, id)
+	if entities.IsNotFoundError(err) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("load email recipient id=%v: %w", id, err)
+	}
+
+	if kind == TemplateVerification && user.IsVerified() {
+		return nil
+	}
+
+	return n.sendTo(ctx, user, kind, data)
+}
+
+// Notify emails notification to its user.
+func (n *Notifier) Notify(ctx context.Context, notification services.Notification) error {
+	return n.sendTo(ctx, notification.User, TemplateNotification, TemplateData{Summary: notification.Summary})
+}
+
+// sendTo renders kind for user and sends it to the user's address.
+func (n *Notifier) sendTo(ctx context.Context, user *entities.User, kind Template, data TemplateData) error {
+	return n.sendToAddress(ctx, user, user.Email().String(), kind, data)
+}
+
+// sendToAddress renders kind for user and sends it to the address to.
+func (n *Notifier) sendToAddress(
+	ctx context.Context,
+	user *entities.User,
+	to string,
+	kind Template,
+	data TemplateData,
+) error {
+	data.Name = user.FirstName().String()
+	if data.Name == "" {
+		data.Name = user.Username().String()
+	}
+
+	msg, err := n.templates.Render(kind, data)
+	if err != nil {
+		return err
+	}
+
+	msg.From = n.from
+	msg.To = []string{to}
+
+	err = n.sender.Send(ctx, msg)
+	if err != nil {
+		return fmt.Errorf("email template=%v user=%v: %w", kind, user.ID(), err)
+	}
+
+	return nil
+}
+
+// tokenLink adds token to the query of page.
+func tokenLink(page, token string) (string, error) {
+	link, err := url.Parse(page)
+	if err != nil {
+		return "", err
+	}
+
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+
+	return link.String(), nil
+}
+
+// decodeData decodes the data of event into v. Payloads arrive as structs
+// from in-process publishers and as raw JSON once redacted or from
+// brokers, so both are decoded through JSON.
+func decodeData(event *events.UserEvent, v any) error {
+	payload, ok := event.Data.(json.RawMessage)
+	if !ok {
+		var err error
+
+		payload, err = json.Marshal(event.Data)
+		if err != nil {
+			return fmt.Errorf("encode event id=%v data: %w", event.ID, err)
+		}
+	}
+
+	err := json.Unmarshal(payload, v)
+	if err != nil {
+		return fmt.Errorf("decode event id=%v data: %w", event.ID, err)
+	}
+
+	return nil
+}
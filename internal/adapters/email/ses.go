@@ -0,0 +1,83 @@
+package email
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sesv2"
+	"github.com/aws/aws-sdk-go-v2/service/sesv2/types"
+)
+
+// charset is the character set of every message.
+const charset = "UTF-8"
+
+// SESClient is the part of *sesv2.Client that SESSender uses.
+type SESClient interface {
+	SendEmail(ctx context.Context, params *sesv2.SendEmailInput, optFns ...func(*sesv2.Options)) (*sesv2.SendEmailOutput, error)
+}
+
+// SESSender sends messages through the Amazon SES v2 API.
+type SESSender struct {
+	client           SESClient
+	configurationSet string
+}
+
+var _ EmailSender = (*SESSender)(nil)
+
+// SESOption configures an SESSender.
+type SESOption func(*SESSender)
+
+// WithConfigurationSet sends through an SES configuration set, e.g. to
+// track bounces and complaints.
+func WithConfigurationSet(name string) SESOption {
+	return func(s *SESSender) {
+		s.configurationSet = name
+	}
+}
+
+// NewSESSender creates a sender on client, usually sesv2.NewFromConfig.
+func NewSESSender(client SESClient, opts ...SESOption) *SESSender {
+	sender := &SESSender{client: client}
+
+	for _, opt := range opts {
+		opt(sender)
+	}
+
+	return sender
+}
+
+// Send delivers msg.
+func (s *SESSender) Send(ctx context.Context, msg Message) error {
+	err := msg.Validate()
+	if err != nil {
+		return err
+	}
+
+	body := &types.Body{Text: &types.Content{Data: aws.String(msg.Text), Charset: aws.String(charset)}}
+	if msg.HTML != "" {
+		body.Html = &types.Content{Data: aws.String(msg.HTML), Charset: aws.String(charset)}
+	}
+
+	input := &sesv2.SendEmailInput{
+		FromEmailAddress: aws.String(msg.From),
+		Destination:      &types.Destination{ToAddresses: msg.To},
+		Content: &types.EmailContent{
+			Simple: &types.Message{
+				Subject: &types.Content{Data: aws.String(msg.Subject), Charset: aws.String(charset)},
+				Body:    body,
+			},
+		},
+	}
+
+	if s.configurationSet != "" {
+		input.ConfigurationSetName = aws.String(s.configurationSet)
+	}
+
+	_, err = s.client.SendEmail(ctx, input)
+	if err != nil {
+		return fmt.Errorf("ses send subject=%q: %w", msg.Subject, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,144 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+// SMTPSender sends messages through an SMTP server. Connections are
+// upgraded with STARTTLS when the server offers it.
+type SMTPSender struct {
+	addr string
+	auth smtp.Auth
+	now  func() time.Time
+}
+
+var _ EmailSender = (*SMTPSender)(nil)
+
+// NewSMTPSender creates a sender for the server at addr, as host:port.
+// auth may be nil for servers that accept mail without authentication.
+func NewSMTPSender(addr string, auth smtp.Auth) *SMTPSender {
+	return &SMTPSender{addr: addr, auth: auth, now: time.Now}
+}
+
+// Send delivers msg. smtp.SendMail cannot be cancelled, so ctx is only
+// checked before the connection is opened.
+func (s *SMTPSender) Send(ctx context.Context, msg Message) error {
+	err := msg.Validate()
+	if err != nil {
+		return err
+	}
+
+	err = ctx.Err()
+	if err != nil {
+		return fmt.Errorf("smtp send subject=%q: %w", msg.Subject, err)
+	}
+
+	body, err := encodeMessage(msg, s.now())
+	if err != nil {
+		return fmt.Errorf("encode email subject=%q: %w", msg.Subject, err)
+	}
+
+	to := make([]string, 0, len(msg.To))
+	for _, address := range msg.To {
+		to = append(to, envelopeAddress(address))
+	}
+
+	err = smtp.SendMail(s.addr, s.auth, envelopeAddress(msg.From), to, body)
+	if err != nil {
+		return fmt.Errorf("smtp send addr=%v subject=%q: %w", s.addr, msg.Subject, err)
+	}
+
+	return nil
+}
+
+// encodeMessage renders msg as a MIME message: a quoted-printable text
+// part, in a multipart/alternative with the HTML part if there is one.
+func encodeMessage(msg Message, now time.Time) ([]byte, error) {
+	var buf bytes.Buffer
+
+	header := func(key, value string) {
+		fmt.Fprintf(&buf, "%s: %s\r\n", key, value)
+	}
+
+	header("From", msg.From)
+	header("To", strings.Join(msg.To, ", "))
+	header("Subject", mime.QEncoding.Encode("utf-8", msg.Subject))
+	header("Date", now.Format(time.RFC1123Z))
+	header("Message-ID", messageID(msg.From))
+	header("MIME-Version", "1.0")
+
+	if msg.HTML == "" {
+		header("Content-Type", `text/plain; charset="utf-8"`)
+		header("Content-Transfer-Encoding", "quoted-printable")
+		buf.WriteString("\r\n")
+
+		err := writeQuotedPrintable(&buf, msg.Text)
+		if err != nil {
+			return nil, err
+		}
+
+		return buf.Bytes(), nil
+	}
+
+	parts := multipart.NewWriter(&buf)
+	header("Content-Type", mime.FormatMediaType("multipart/alternative", map[string]string{"boundary": parts.Boundary()}))
+	buf.WriteString("\r\n")
+
+	for _, part := range []struct{ contentType, body string }{
+		{`text/plain; charset="utf-8"`, msg.Text},
+		{`text/html; charset="utf-8"`, msg.HTML},
+	} {
+		w, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		err = writeQuotedPrintable(w, part.body)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err := parts.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// writeQuotedPrintable writes body to w in quoted-printable encoding.
+func writeQuotedPrintable(w io.Writer, body string) error {
+	qp := quotedprintable.NewWriter(w)
+
+	_, err := qp.Write([]byte(body))
+	if err != nil {
+		return err
+	}
+
+	return qp.Close()
+}
+
+// messageID returns a unique Message-ID in the domain of the sender.
+func messageID(from string) string {
+	_, domain, ok := strings.Cut(envelopeAddress(from), "@")
+	if !ok {
+		domain = "localhost"
+	}
+
+	return "<" + rand.Text() + "@" + domain + ">"
+}
@@ -0,0 +1,159 @@
+package email
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"strings"
+	texttemplate "text/template"
+	"time"
+)
+
+// ErrUnknownTemplate is returned for a template that was not parsed.
+var ErrUnknownTemplate = errors.New("unknown email template")
+
+// Template names a kind of email. Each is parsed from <name>.txt, which
+// defines the "subject" and "text" templates, and an optional <name>.html
+// holding the HTML body.
+type Template string
+
+// The templates of the user lifecycle.
+const (
+	TemplateVerification  Template = "verification"
+	TemplatePasswordReset Template = "password_reset"
+	TemplateSuspension    Template = "suspension"
+	// TemplateEmailChange confirms a new email address.
+	TemplateEmailChange Template = "email_change"
+	// TemplateIdentityChanged offers to revert an email address or username change.
+	TemplateIdentityChanged Template = "identity_changed"
+	// TemplateNotification is the email of notifications users opted in to.
+	TemplateNotification Template = "notification"
+	// TemplateAccountDeletion confirms a scheduled account deletion.
+	TemplateAccountDeletion Template = "account_deletion"
+)
+
+// TemplateData is the data the templates are executed with.
+type TemplateData struct {
+	// Name is how the recipient is addressed.
+	Name string
+	// Link is the link to redeem the emailed token on.
+	Link string
+	// ExpiresAt is when Link expires.
+	ExpiresAt time.Time
+	// Reason explains a suspension.
+	Reason string
+	// Summary says what a notification is about.
+	Summary string
+	// Field is the changed field of a user, "email" or "username".
+	Field string
+	// ScheduledFor is when a requested account deletion is carried out.
+	ScheduledFor time.Time
+}
+
+//go:embed templates
+var embeddedTemplates embed.FS
+
+// Templates renders messages from text and HTML templates.
+type Templates struct {
+	text map[Template]*texttemplate.Template
+	html map[Template]*htmltemplate.Template
+}
+
+// DefaultTemplates returns the templates embedded in the binary. It panics
+// if they do not parse, which the tests catch.
+func DefaultTemplates() *Templates {
+	sub, err := fs.Sub(embeddedTemplates, "templates")
+	if err != nil {
+		panic(err)
+	}
+
+	templates, err := ParseTemplates(sub)
+	if err != nil {
+		panic(err)
+	}
+
+	return templates
+}
+
+// ParseTemplates parses the templates in the root of fsys, so that the
+// embedded ones can be replaced by branded copies.
+func ParseTemplates(fsys fs.FS) (*Templates, error) {
+	names, err := fs.Glob(fsys, "*.txt")
+	if err != nil {
+		return nil, fmt.Errorf("list email templates: %w", err)
+	}
+
+	templates := &Templates{
+		text: make(map[Template]*texttemplate.Template, len(names)),
+		html: make(map[Template]*htmltemplate.Template, len(names)),
+	}
+
+	for _, name := range names {
+		kind := Template(strings.TrimSuffix(name, ".txt"))
+
+		text, err := texttemplate.ParseFS(fsys, name)
+		if err != nil {
+			return nil, fmt.Errorf("parse email template=%v: %w", kind, err)
+		}
+
+		if text.Lookup("subject") == nil || text.Lookup("text") == nil {
+			return nil, fmt.Errorf("email template=%v must define subject and text: %w", kind, ErrUnknownTemplate)
+		}
+
+		templates.text[kind] = text
+
+		htmlName := string(kind) + ".html"
+
+		_, err = fs.Stat(fsys, htmlName)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+
+		html, err := htmltemplate.ParseFS(fsys, htmlName)
+		if err != nil {
+			return nil, fmt.Errorf("parse email template=%v: %w", kind, err)
+		}
+
+		templates.html[kind] = html
+	}
+
+	return templates, nil
+}
+
+// Render executes the templates of kind with data into a message without
+// sender and recipients.
+func (t *Templates) Render(kind Template, data TemplateData) (Message, error) {
+	text, ok := t.text[kind]
+	if !ok {
+		return Message{}, fmt.Errorf("email template=%v: %w", kind, ErrUnknownTemplate)
+	}
+
+	var subject, body, html bytes.Buffer
+
+	err := text.ExecuteTemplate(&subject, "subject", data)
+	if err != nil {
+		return Message{}, fmt.Errorf("render email template=%v subject: %w", kind, err)
+	}
+
+	err = text.ExecuteTemplate(&body, "text", data)
+	if err != nil {
+		return Message{}, fmt.Errorf("render email template=%v text: %w", kind, err)
+	}
+
+	if tmpl, ok := t.html[kind]; ok {
+		err = tmpl.Execute(&html, data)
+		if err != nil {
+			return Message{}, fmt.Errorf("render email template=%v html: %w", kind, err)
+		}
+	}
+
+	return Message{
+		// Subjects are single lines, whatever the template's layout.
+		Subject: strings.Join(strings.Fields(subject.String()), " "),
+		Text:    body.String(),
+		HTML:    html.String(),
+	}, nil
+}
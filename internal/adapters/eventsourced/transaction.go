@@ -0,0 +1,58 @@
+package eventsourced
+
+import (
+	"context"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// TransactionalRepository runs the transactions of the repository it wraps
+// with an event-sourced user repository, so that users changed in a
+// transaction are recorded in their streams like those changed outside.
+type TransactionalRepository struct {
+	repositories.TransactionalRepository
+
+	opts []Option
+}
+
+// NewTransactionalRepository event sources the users of the transactions of
+// txRepo in the event store of each transaction, configured by opts.
+func NewTransactionalRepository(
+	txRepo repositories.TransactionalRepository,
+	opts ...Option,
+) *TransactionalRepository {
+	return &TransactionalRepository{TransactionalRepository: txRepo, opts: opts}
+}
+
+// BeginTx starts a transaction of the wrapped repository.
+func (r *TransactionalRepository) BeginTx(ctx context.Context) (repositories.Transaction, error) {
+	tx, err := r.TransactionalRepository.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &transaction{Transaction: tx, opts: r.opts}, nil
+}
+
+// RunInTransaction runs fn in a transaction of the wrapped repository.
+func (r *TransactionalRepository) RunInTransaction(
+	ctx context.Context,
+	fn func(ctx context.Context, tx repositories.Transaction) error,
+) error {
+	return r.TransactionalRepository.RunInTransaction(ctx, func(ctx context.Context, tx repositories.Transaction) error {
+		return fn(ctx, &transaction{Transaction: tx, opts: r.opts})
+	})
+}
+
+// transaction is a transaction whose user repository is event sourced.
+type transaction struct {
+	repositories.Transaction
+
+	opts []Option
+}
+
+// UserRepository records the changes of users in the event store of the
+// transaction.
+func (t *transaction) UserRepository() repositories.UserRepository {
+	return NewUserRepository(t.Transaction.UserRepository(), t.UserEventStore(), t.opts...)
+}
@@ -0,0 +1,468 @@
+// Package eventsourced provides a UserRepository that records every change
+// of a user as events in its stream, the source of truth users are loaded
+// from, while the repository it wraps serves as the read model for lookups by
+// other keys, listings and searches.
+//
+// A write first goes to the read model, whose constraints, such as unique
+// email addresses, reject invalid changes, and then appends the events that
+// describe it. A user is loaded by ID or UUID by replaying its stream from
+// the latest snapshot, which is taken every few events. Users that existed
+// before event sourcing was enabled have no stream until their next write,
+// which starts it with their current state; until then they are read from
+// the read model.
+package eventsourced
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// UserRepository is an event-sourced UserRepository. Methods it does not
+// override are served by the read model.
+type UserRepository struct {
+	repositories.UserRepository
+
+	events        repositories.UserEventStore
+	snapshotEvery int64
+}
+
+// Option configures a UserRepository.
+type Option func(*UserRepository)
+
+// WithSnapshotEvery snapshots a user every n events. Defaults to
+// entities.DefaultSnapshotInterval.
+func WithSnapshotEvery(n int) Option {
+	return func(r *UserRepository) {
+		if n > 0 {
+			r.snapshotEvery = int64(n)
+		}
+	}
+}
+
+// NewUserRepository records the changes of the users of readModel in the
+// streams of store.
+func NewUserRepository(
+	readModel repositories.UserRepository,
+	store repositories.UserEventStore,
+	opts ...Option,
+) *UserRepository {
+	r := &UserRepository{
+		UserRepository: readModel,
+		events:         store,
+		snapshotEvery:  entities.DefaultSnapshotInterval,
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+// Ensure UserRepository implements the domain UserRepository.
+var _ repositories.UserRepository = (*UserRepository)(nil)
+
+// stream is the state of a user at a version of its stream. Version zero
+// means the user has no stream yet.
+type stream struct {
+	user    *entities.User
+	version int64
+	deleted bool
+}
+
+// Create creates the user in the read model, which assigns its ID, and
+// starts its stream.
+func (r *UserRepository) Create(ctx context.Context, user *entities.User) error {
+	err := r.UserRepository.Create(ctx, user)
+	if err != nil {
+		return err
+	}
+
+	return r.record(ctx, &stream{user: user}, user, nil)
+}
+
+// CreateBatch creates the users in the read model and starts their streams.
+func (r *UserRepository) CreateBatch(ctx context.Context, users []*entities.User) error {
+	err := r.UserRepository.CreateBatch(ctx, users)
+	if err != nil {
+		return err
+	}
+
+	for _, user := range users {
+		err = r.record(ctx, &stream{user: user}, user, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetByID replays the stream of the user.
+func (r *UserRepository) GetByID(ctx context.Context, id entities.UserID) (*entities.User, error) {
+	s, err := r.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case s.version == 0:
+		return r.UserRepository.GetByID(ctx, id)
+	case s.deleted:
+		return nil, fmt.Errorf("user id=%v: %w", id, entities.ErrUserNotFound)
+	default:
+		return s.user, nil
+	}
+}
+
+// GetByUUID finds the ID of the user in the read model and replays its
+// stream.
+func (r *UserRepository) GetByUUID(ctx context.Context, uuid entities.UuID) (*entities.User, error) {
+	user, err := r.UserRepository.GetByUUID(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(ctx, user.ID())
+}
+
+// Update records the changes of the profile, identity, status, role and
+// verification of user. Like the read model, it keeps the UUID, password
+// and creation time.
+func (r *UserRepository) Update(ctx context.Context, user *entities.User) error {
+	s, err := r.current(ctx, user.ID())
+	if err != nil {
+		return err
+	}
+
+	err = r.UserRepository.Update(ctx, user)
+	if err != nil {
+		return err
+	}
+
+	before := s.user.Record()
+	record := user.Record()
+	record.UUID = before.UUID
+	record.Password = before.Password
+	record.CreatedAt = before.CreatedAt
+
+	after, err := entities.ReconstructUser(record)
+	if err != nil {
+		return err
+	}
+
+	return r.record(ctx, s, after, entities.UserChangeEvents(s.user, after))
+}
+
+// UpdatePassword records the new password hash of the user.
+func (r *UserRepository) UpdatePassword(
+	ctx context.Context,
+	id entities.UserID,
+	password entities.PasswordHash,
+) error {
+	return r.change(ctx, id,
+		func(record *entities.UserRecord) { record.Password = password },
+		func() error { return r.UserRepository.UpdatePassword(ctx, id, password) },
+	)
+}
+
+// MarkVerified records the verification of the user.
+func (r *UserRepository) MarkVerified(ctx context.Context, id entities.UserID) error {
+	return r.change(ctx, id,
+		func(record *entities.UserRecord) { record.IsVerified = true },
+		func() error { return r.UserRepository.MarkVerified(ctx, id) },
+	)
+}
+
+// ChangeStatus records the new status of the user.
+func (r *UserRepository) ChangeStatus(ctx context.Context, id entities.UserID, status entities.UserStatus) error {
+	if !status.IsValid() {
+		return fmt.Errorf("status=%v: %w", status, entities.ErrInvalidUserStatus)
+	}
+
+	return r.change(ctx, id,
+		func(record *entities.UserRecord) { record.Status = status },
+		func() error { return r.UserRepository.ChangeStatus(ctx, id, status) },
+	)
+}
+
+// Activate records that the user became active.
+func (r *UserRepository) Activate(ctx context.Context, id entities.UserID) error {
+	return r.ChangeStatus(ctx, id, entities.UserStatusActive)
+}
+
+// Deactivate records that the user became inactive.
+func (r *UserRepository) Deactivate(ctx context.Context, id entities.UserID) error {
+	return r.ChangeStatus(ctx, id, entities.UserStatusInactive)
+}
+
+// Suspend records that the user was suspended.
+func (r *UserRepository) Suspend(ctx context.Context, id entities.UserID) error {
+	return r.ChangeStatus(ctx, id, entities.UserStatusSuspended)
+}
+
+// ChangeRole records the new role of the user.
+func (r *UserRepository) ChangeRole(ctx context.Context, id entities.UserID, role entities.UserRole) error {
+	if !role.IsValid() {
+		return fmt.Errorf("role=%v: %w", role, entities.ErrInvalidUserRole)
+	}
+
+	return r.change(ctx, id,
+		func(record *entities.UserRecord) { record.Role = role },
+		func() error { return r.UserRepository.ChangeRole(ctx, id, role) },
+	)
+}
+
+// UpdateStatusBatch records the new status of each user with one of ids.
+// Deleted and unknown IDs are skipped.
+func (r *UserRepository) UpdateStatusBatch(
+	ctx context.Context,
+	ids []entities.UserID,
+	status entities.UserStatus,
+) (int64, error) {
+	return forEach(ids, func(id entities.UserID) error {
+		return r.ChangeStatus(ctx, id, status)
+	})
+}
+
+// Delete records the soft deletion of the user.
+func (r *UserRepository) Delete(ctx context.Context, id entities.UserID) error {
+	s, err := r.current(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	err = r.UserRepository.Delete(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return r.recordEvent(ctx, s, entities.UserEventDeleted)
+}
+
+// DeleteBatch records the soft deletion of each user with one of ids.
+// Already deleted and unknown IDs are skipped.
+func (r *UserRepository) DeleteBatch(ctx context.Context, ids []entities.UserID) (int64, error) {
+	return forEach(ids, func(id entities.UserID) error {
+		return r.Delete(ctx, id)
+	})
+}
+
+// Restore records that the soft deletion of the user was undone.
+func (r *UserRepository) Restore(ctx context.Context, id entities.UserID) error {
+	s, err := r.load(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if s.version > 0 && !s.deleted {
+		return fmt.Errorf("restore user id=%v: %w", id, entities.ErrUserNotFound)
+	}
+
+	err = r.UserRepository.Restore(ctx, id)
+	if err != nil || s.version == 0 {
+		return err
+	}
+
+	return r.recordEvent(ctx, s, entities.UserEventRestored)
+}
+
+// load replays the stream of the user from its latest snapshot.
+func (r *UserRepository) load(ctx context.Context, id entities.UserID) (*stream, error) {
+	s := &stream{user: new(entities.User)}
+
+	snapshot, err := r.events.LatestSnapshot(ctx, id)
+
+	switch {
+	case err == nil:
+		s.user, err = entities.ReconstructUser(snapshot.State)
+		if err != nil {
+			return nil, fmt.Errorf("user snapshot id=%v version=%v: %w", id, snapshot.Version, err)
+		}
+
+		s.version = snapshot.Version
+		s.deleted = snapshot.Deleted
+	case !errors.Is(err, entities.ErrUserSnapshotNotFound):
+		return nil, err
+	}
+
+	events, err := r.events.Load(ctx, id, s.version)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, event := range events {
+		err = s.user.Apply(event)
+		if err != nil {
+			return nil, err
+		}
+
+		s.version = event.Version
+	}
+
+	s.deleted = deletedAfter(s.deleted, events)
+
+	return s, nil
+}
+
+// current returns the state of a live user, from the read model if it has
+// no stream yet.
+func (r *UserRepository) current(ctx context.Context, id entities.UserID) (*stream, error) {
+	s, err := r.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.deleted {
+		return nil, fmt.Errorf("user id=%v: %w", id, entities.ErrUserNotFound)
+	}
+
+	if s.version == 0 {
+		s.user, err = r.UserRepository.GetByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+// change projects a change of the live user to the read model and records
+// the events that apply it to the stream.
+func (r *UserRepository) change(
+	ctx context.Context,
+	id entities.UserID,
+	apply func(record *entities.UserRecord),
+	project func() error,
+) error {
+	s, err := r.current(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	err = project()
+	if err != nil {
+		return err
+	}
+
+	record := s.user.Record()
+	apply(&record)
+	record.UpdatedAt = time.Now()
+
+	after, err := entities.ReconstructUser(record)
+	if err != nil {
+		return err
+	}
+
+	return r.record(ctx, s, after, entities.UserChangeEvents(s.user, after))
+}
+
+// recordEvent records an event without data, such as a deletion, in the
+// stream of s.
+func (r *UserRepository) recordEvent(ctx context.Context, s *stream, eventType entities.UserEventType) error {
+	event := &entities.UserStreamEvent{
+		UserID:     s.user.ID(),
+		Type:       eventType,
+		OccurredAt: time.Now(),
+	}
+
+	after, err := entities.ReconstructUser(s.user.Record())
+	if err != nil {
+		return err
+	}
+
+	err = after.Apply(event)
+	if err != nil {
+		return err
+	}
+
+	return r.record(ctx, s, after, []*entities.UserStreamEvent{event})
+}
+
+// record appends changes to the stream of s, which starts with the state of
+// s if it has no stream yet, and snapshots after when a multiple of the
+// snapshot interval is passed.
+func (r *UserRepository) record(
+	ctx context.Context,
+	s *stream,
+	after *entities.User,
+	changes []*entities.UserStreamEvent,
+) error {
+	events := changes
+	if s.version == 0 {
+		events = append([]*entities.UserStreamEvent{entities.NewUserCreatedEvent(s.user)}, changes...)
+	}
+
+	if len(events) == 0 {
+		return nil
+	}
+
+	err := r.events.Append(ctx, after.ID(), s.version, events)
+	if err != nil {
+		return err
+	}
+
+	version := s.version + int64(len(events))
+	if version/r.snapshotEvery > s.version/r.snapshotEvery {
+		r.snapshot(ctx, &entities.UserStreamSnapshot{
+			UserID:    after.ID(),
+			Version:   version,
+			Deleted:   deletedAfter(s.deleted, events),
+			State:     after.Record(),
+			CreatedAt: time.Now(),
+		})
+	}
+
+	return nil
+}
+
+// snapshot saves snapshot. A failed snapshot only makes loading the user
+// replay more events, so it is logged.
+func (r *UserRepository) snapshot(ctx context.Context, snapshot *entities.UserStreamSnapshot) {
+	err := r.events.SaveSnapshot(ctx, snapshot)
+	if err != nil {
+		slog.WarnContext(ctx, "user snapshot failed",
+			"user_id", snapshot.UserID, "version", snapshot.Version, "error", err)
+	}
+}
+
+// forEach runs fn for every ID and counts the IDs it succeeded for, skipping
+// those of missing users.
+func forEach(ids []entities.UserID, fn func(id entities.UserID) error) (int64, error) {
+	var affected int64
+
+	for _, id := range ids {
+		err := fn(id)
+
+		switch {
+		case err == nil:
+			affected++
+		case !errors.Is(err, entities.ErrUserNotFound):
+			return affected, err
+		}
+	}
+
+	return affected, nil
+}
+
+// deletedAfter reports whether a user that was deleted or not is deleted
+// after events.
+func deletedAfter(deleted bool, events []*entities.UserStreamEvent) bool {
+	for _, event := range events {
+		switch event.Type {
+		case entities.UserEventDeleted:
+			deleted = true
+		case entities.UserEventRestored:
+			deleted = false
+		default:
+		}
+	}
+
+	return deleted
+}
@@ -0,0 +1,73 @@
+// Package geoip locates IP addresses with a MaxMind GeoIP2 or GeoLite2 City
+// database. It is the services.GeoResolver of login risk detection:
+//
+//	geo, err := geoip.Open("/var/lib/GeoIP/GeoLite2-City.mmdb")
+//	users := services.NewUserService(userRepo, sessionRepo, publisher, validator,
+//		services.WithLoginRiskDetection(geo))
+//
+// Only the country, subdivision and city are kept, never coordinates.
+package geoip
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/services"
+	"github.com/oschwald/geoip2-golang"
+)
+
+// CityReader looks up the city of an IP address. *geoip2.Reader implements it.
+type CityReader interface {
+	City(ip net.IP) (*geoip2.City, error)
+}
+
+// Resolver resolves locations with a CityReader.
+type Resolver struct {
+	reader CityReader
+	close  func() error
+}
+
+var _ services.GeoResolver = (*Resolver)(nil)
+
+// NewResolver creates a resolver looking up locations with reader.
+func NewResolver(reader CityReader) *Resolver {
+	return &Resolver{reader: reader, close: func() error { return nil }}
+}
+
+// Open creates a resolver reading the MaxMind database at path.
+// Close releases the database.
+func Open(path string) (*Resolver, error) {
+	reader, err := geoip2.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open geoip database path=%v: %w", path, err)
+	}
+
+	return &Resolver{reader: reader, close: reader.Close}, nil
+}
+
+// Locate returns the country, subdivision and English city name of ip.
+// Addresses missing from the database have a zero location.
+func (r *Resolver) Locate(_ context.Context, ip net.IP) (entities.GeoLocation, error) {
+	city, err := r.reader.City(ip)
+	if err != nil {
+		return entities.GeoLocation{}, fmt.Errorf("locate ip=%v: %w", ip, err)
+	}
+
+	location := entities.GeoLocation{
+		Country: city.Country.IsoCode,
+		City:    city.City.Names["en"],
+	}
+
+	if len(city.Subdivisions) > 0 {
+		location.Region = city.Subdivisions[0].IsoCode
+	}
+
+	return location, nil
+}
+
+// Close releases the database of resolvers created with Open.
+func (r *Resolver) Close() error {
+	return r.close()
+}
@@ -0,0 +1,118 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/services"
+)
+
+// SessionVerifier resolves session tokens. It is implemented by services.UserService.
+type SessionVerifier interface {
+	VerifySession(ctx context.Context, token string) (*entities.UserSession, *entities.User, error)
+}
+
+var _ SessionVerifier = (*services.UserService)(nil)
+
+// principalKey is the context key for the authenticated session and user.
+type principalKey struct{}
+
+// principal is the authenticated caller of a request.
+type principal struct {
+	session *entities.UserSession
+	user    *entities.User
+}
+
+// UserFromContext returns the authenticated user and session of the current request.
+func UserFromContext(ctx context.Context) (*entities.User, *entities.UserSession, bool) {
+	p, ok := ctx.Value(principalKey{}).(principal)
+	if !ok {
+		return nil, nil, false
+	}
+
+	return p.user, p.session, true
+}
+
+// AuthMiddleware requires a valid "Authorization: Bearer <token>" header on
+// every request and stores the caller in the context, as the gRPC
+// AuthInterceptor does.
+func AuthMiddleware(verifier SessionVerifier, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		token, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+		if !ok || token == "" {
+			unauthorized(writer)
+
+			return
+		}
+
+		ctx := req.Context()
+
+		session, user, err := verifier.VerifySession(ctx, token)
+		if err != nil {
+			unauthorized(writer)
+
+			return
+		}
+
+		ctx = context.WithValue(ctx, principalKey{}, principal{session: session, user: user})
+		// Operations in impersonation sessions are attributed to the impersonator.
+		actorID := user.ID()
+		if impersonatorID, ok := session.Impersonator(); ok {
+			actorID = impersonatorID
+		}
+
+		ctx = services.ContextWithAuditActor(ctx, services.AuditActor{
+			UserID:    actorID,
+			IPAddress: remoteIP(req),
+		})
+
+		next.ServeHTTP(writer, req.WithContext(ctx))
+	})
+}
+
+// unauthorized rejects a request without a valid session.
+func unauthorized(writer http.ResponseWriter) {
+	writer.Header().Set("WWW-Authenticate", "Bearer")
+	http.Error(writer, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+}
+
+// remoteIP returns the IP address of the client of req, or "" if unknown.
+func remoteIP(req *http.Request) string {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return ""
+	}
+
+	return host
+}
+
+// requirePermission returns ErrInsufficientPrivileges unless the caller has
+// a role granting permission.
+func requirePermission(ctx context.Context, permission entities.Permission) error {
+	user, _, ok := UserFromContext(ctx)
+	if !ok || !user.Role().Grants(permission) {
+		return fmt.Errorf("permission=%v: %w", permission, entities.ErrInsufficientPrivileges)
+	}
+
+	return nil
+}
+
+// requireSelfOrManager returns ErrInsufficientPrivileges unless the caller
+// is userID or may manage users. Users and their sessions are scoped this way.
+func requireSelfOrManager(ctx context.Context, userID entities.UserID) error {
+	user, _, ok := UserFromContext(ctx)
+	if ok && user.ID() == userID {
+		return nil
+	}
+
+	err := requirePermission(ctx, entities.PermissionUsersManage)
+	if err != nil {
+		return fmt.Errorf("user=%v: %w", userID, err)
+	}
+
+	return nil
+}
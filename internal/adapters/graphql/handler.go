@@ -0,0 +1,67 @@
+package graphql
+
+import (
+	"context"
+	"net/http"
+
+	gqlgen "github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/services"
+	apperrors "github.com/LarsArtmann/template-sqlc/pkg/errors"
+	"github.com/LarsArtmann/template-sqlc/pkg/i18n"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// NewHandler returns an HTTP handler serving the GraphQL schema over GET and
+// POST to callers with a valid session. Users and their sessions are only
+// resolved for their own user, unless the caller may manage users.
+func NewHandler(
+	users *services.UserService,
+	userRepo repositories.UserRepository,
+	sessionRepo repositories.SessionRepository,
+) http.Handler {
+	server := handler.New(NewExecutableSchema(Config{
+		Resolvers: NewResolver(users, userRepo, sessionRepo),
+	}))
+	server.AddTransport(transport.GET{})
+	server.AddTransport(transport.POST{})
+	server.Use(extension.Introspection{})
+	server.SetErrorPresenter(presentError)
+
+	return i18n.Middleware(i18n.DefaultCatalog())(AuthMiddleware(users, LoaderMiddleware(userRepo, server)))
+}
+
+// presentError adds the code and the field errors of resolver errors to their
+// extensions, localizes their messages into the language of the request and
+// hides the details of internal errors from clients. Errors of gqlgen itself,
+// such as invalid queries, are presented unchanged.
+func presentError(ctx context.Context, err error) *gqlerror.Error {
+	presented := gqlgen.DefaultErrorPresenter(ctx, err)
+	if presented == nil || presented.Err == nil {
+		return presented
+	}
+
+	localized := i18n.Localize(ctx, presented.Err)
+	if localized != presented.Err { //nolint:errorlint // Identity, not equivalence
+		presented.Message = localized.Error()
+	}
+
+	if apperrors.HTTPStatus(presented.Err) >= http.StatusInternalServerError {
+		presented.Message = "internal error"
+	}
+
+	if presented.Extensions == nil {
+		presented.Extensions = map[string]any{}
+	}
+
+	presented.Extensions["code"] = apperrors.CodeOf(presented.Err)
+
+	if fields := apperrors.FieldErrorsOf(localized); len(fields) > 0 {
+		presented.Extensions["fields"] = []apperrors.FieldError(fields)
+	}
+
+	return presented
+}
@@ -0,0 +1,64 @@
+package graphql
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+	"github.com/vikstrous/dataloadgen"
+)
+
+// loaderWait is how long a loader collects keys before issuing a batch query.
+const loaderWait = 2 * time.Millisecond
+
+// loadersKey is the context key for the request-scoped loaders.
+type loadersKey struct{}
+
+// Loaders batch lookups made while resolving a single request, so that
+// resolving the user of N sessions costs one query instead of N.
+type Loaders struct {
+	Users *dataloadgen.Loader[entities.UserID, *entities.User]
+}
+
+// NewLoaders creates request-scoped loaders backed by the repositories.
+func NewLoaders(users repositories.UserRepository) *Loaders {
+	return &Loaders{
+		Users: dataloadgen.NewMappedLoader(
+			func(ctx context.Context, ids []entities.UserID) (map[entities.UserID]*entities.User, error) {
+				found, err := users.GetByIDs(ctx, ids)
+				if err != nil {
+					return nil, err
+				}
+
+				byID := make(map[entities.UserID]*entities.User, len(found))
+				for _, user := range found {
+					byID[user.ID()] = user
+				}
+
+				return byID, nil
+			},
+			dataloadgen.WithWait(loaderWait),
+		),
+	}
+}
+
+// LoaderMiddleware attaches fresh loaders to every request.
+func LoaderMiddleware(users repositories.UserRepository, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		ctx := context.WithValue(req.Context(), loadersKey{}, NewLoaders(users))
+		next.ServeHTTP(writer, req.WithContext(ctx))
+	})
+}
+
+// loadersFrom returns the loaders of the current request.
+// Requests that bypass the middleware get unbatched loaders.
+func loadersFrom(ctx context.Context, users repositories.UserRepository) *Loaders {
+	loaders, ok := ctx.Value(loadersKey{}).(*Loaders)
+	if !ok {
+		return NewLoaders(users)
+	}
+
+	return loaders
+}
@@ -0,0 +1,92 @@
+package graphql
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+)
+
+// User is the GraphQL representation of a user. The password hash is never exposed.
+type User struct {
+	ID          string     `json:"id"`
+	UUID        string     `json:"uuid"`
+	Email       string     `json:"email"`
+	Username    string     `json:"username"`
+	FirstName   string     `json:"firstName"`
+	LastName    string     `json:"lastName"`
+	Status      UserStatus `json:"status"`
+	Role        UserRole   `json:"role"`
+	IsVerified  bool       `json:"isVerified"`
+	Tags        []string   `json:"tags"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+	LastLoginAt *time.Time `json:"lastLoginAt,omitempty"`
+
+	userID entities.UserID
+}
+
+// Session is the GraphQL representation of a session. The token is never exposed.
+type Session struct {
+	ID        string    `json:"id"`
+	IPAddress string    `json:"ipAddress"`
+	UserAgent string    `json:"userAgent"`
+	CreatedAt time.Time `json:"createdAt"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	IsActive  bool      `json:"isActive"`
+
+	userID entities.UserID
+}
+
+// newUser converts a domain user to its GraphQL representation.
+func newUser(user *entities.User) *User {
+	return &User{
+		ID:          strconv.FormatInt(user.ID().Int64(), 10),
+		UUID:        user.UUID().String(),
+		Email:       user.Email().String(),
+		Username:    user.Username().String(),
+		FirstName:   user.FirstName().String(),
+		LastName:    user.LastName().String(),
+		Status:      UserStatus(user.Status()),
+		Role:        UserRole(user.Role()),
+		IsVerified:  user.IsVerified(),
+		Tags:        user.Tags(),
+		CreatedAt:   user.CreatedAt(),
+		UpdatedAt:   user.UpdatedAt(),
+		LastLoginAt: user.LastLoginAt(),
+		userID:      user.ID(),
+	}
+}
+
+// newUsers converts a slice of domain users.
+func newUsers(users []*entities.User) []*User {
+	result := make([]*User, 0, len(users))
+	for _, user := range users {
+		result = append(result, newUser(user))
+	}
+
+	return result
+}
+
+// newSession converts a domain session to its GraphQL representation.
+func newSession(session *entities.UserSession) *Session {
+	return &Session{
+		ID:        strconv.FormatInt(session.ID().Int64(), 10),
+		IPAddress: session.IPAddress().String(),
+		UserAgent: session.UserAgent(),
+		CreatedAt: session.CreatedAt(),
+		ExpiresAt: session.ExpiresAt(),
+		IsActive:  session.IsActive(),
+		userID:    session.UserID(),
+	}
+}
+
+// parseUserID parses a GraphQL ID into a user ID.
+func parseUserID(id string) (entities.UserID, error) {
+	value, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return 0, entities.NewValidationError("id", "must be a numeric user id")
+	}
+
+	return entities.UserID(value), nil
+}
@@ -0,0 +1,52 @@
+package graphql
+
+import (
+	"context"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/services"
+)
+
+// Resolver resolves the GraphQL schema against the user service.
+// Nested lookups go through the request-scoped loaders.
+type Resolver struct {
+	users       *services.UserService
+	userRepo    repositories.UserRepository
+	sessionRepo repositories.SessionRepository
+}
+
+// NewResolver creates the root resolver.
+func NewResolver(
+	users *services.UserService,
+	userRepo repositories.UserRepository,
+	sessionRepo repositories.SessionRepository,
+) *Resolver {
+	return &Resolver{
+		users:       users,
+		userRepo:    userRepo,
+		sessionRepo: sessionRepo,
+	}
+}
+
+// prime seeds the user loader with users that were already fetched.
+func (r *Resolver) prime(ctx context.Context, users []*entities.User) {
+	loader := loadersFrom(ctx, r.userRepo).Users
+	for _, user := range users {
+		loader.Prime(user.ID(), user)
+	}
+}
+
+// deref returns the value of an optional argument, or zero when absent.
+func deref(value *int) int {
+	if value == nil {
+		return 0
+	}
+
+	return *value
+}
+
+// statusFilter matches the users with status.
+func statusFilter(status UserStatus) entities.UserFilter {
+	return entities.UserFilter{Statuses: []entities.UserStatus{entities.UserStatus(status)}}
+}
@@ -0,0 +1,153 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+)
+
+// User is the resolver for the user field.
+func (r *queryResolver) User(ctx context.Context, id string) (*User, error) {
+	userID, err := parseUserID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = requireSelfOrManager(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := loadersFrom(ctx, r.userRepo).Users.Load(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("id=%v: %w", id, entities.ErrUserNotFound)
+	}
+
+	return newUser(user), nil
+}
+
+// Users is the resolver for the users field.
+func (r *queryResolver) Users(ctx context.Context, status UserStatus, limit *int, offset *int) ([]*User, error) {
+	err := requirePermission(ctx, entities.PermissionUsersManage)
+	if err != nil {
+		return nil, err
+	}
+
+	users, err := r.users.ListUsers(ctx, statusFilter(status), deref(limit), deref(offset))
+	if err != nil {
+		return nil, err
+	}
+
+	r.prime(ctx, users)
+
+	return newUsers(users), nil
+}
+
+// SearchUsers is the resolver for the searchUsers field.
+func (r *queryResolver) SearchUsers(ctx context.Context, query string, status UserStatus, limit *int) ([]*User, error) {
+	err := requirePermission(ctx, entities.PermissionUsersManage)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := statusFilter(status)
+	filter.Query = query
+
+	users, err := r.users.ListUsers(ctx, filter, deref(limit), 0)
+	if err != nil {
+		return nil, fmt.Errorf("query=%v: %w", query, err)
+	}
+
+	r.prime(ctx, users)
+
+	return newUsers(users), nil
+}
+
+// Session is the resolver for the session field.
+func (r *queryResolver) Session(ctx context.Context, token string) (*Session, error) {
+	session, user, err := r.users.VerifySession(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	err = requireSelfOrManager(ctx, user.ID())
+	if err != nil {
+		return nil, err
+	}
+
+	loadersFrom(ctx, r.userRepo).Users.Prime(user.ID(), user)
+
+	return newSession(session), nil
+}
+
+// Stats is the resolver for the stats field.
+func (r *queryResolver) Stats(ctx context.Context) (*UserStats, error) {
+	err := requirePermission(ctx, entities.PermissionStatsRead)
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := r.users.GetUserStats(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UserStats{
+		TotalUsers:       int(stats.TotalUsers),
+		ActiveUsers:      int(stats.ActiveUsers),
+		InactiveUsers:    int(stats.InactiveUsers),
+		SuspendedUsers:   int(stats.SuspendedUsers),
+		VerifiedUsers:    int(stats.VerifiedUsers),
+		UsersWithLogins:  int(stats.UsersWithLogins),
+		NewUsersMonth:    int(stats.NewUsers30d),
+		NewUsersWeek:     int(stats.NewUsers7d),
+		ActivePercentage: stats.ActivePercentage,
+		VerificationRate: stats.VerificationRate,
+	}, nil
+}
+
+// User is the resolver for the user field.
+func (r *sessionResolver) User(ctx context.Context, obj *Session) (*User, error) {
+	user, err := loadersFrom(ctx, r.userRepo).Users.Load(ctx, obj.userID)
+	if err != nil {
+		return nil, fmt.Errorf("user=%v: %w", obj.userID, entities.ErrUserNotFound)
+	}
+
+	return newUser(user), nil
+}
+
+// Sessions is the resolver for the sessions field.
+func (r *userResolver) Sessions(ctx context.Context, obj *User, activeOnly *bool) ([]*Session, error) {
+	err := requireSelfOrManager(ctx, obj.userID)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := r.sessionRepo.GetByUserID(ctx, obj.userID, activeOnly == nil || *activeOnly)
+	if err != nil {
+		return nil, fmt.Errorf("user=%v: %w", obj.userID, err)
+	}
+
+	result := make([]*Session, 0, len(sessions))
+	for _, session := range sessions {
+		result = append(result, newSession(session))
+	}
+
+	return result, nil
+}
+
+// Query returns QueryResolver implementation.
+func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
+
+// Session returns SessionResolver implementation.
+func (r *Resolver) Session() SessionResolver { return &sessionResolver{r} }
+
+// User returns UserResolver implementation.
+func (r *Resolver) User() UserResolver { return &userResolver{r} }
+
+type (
+	queryResolver   struct{ *Resolver }
+	sessionResolver struct{ *Resolver }
+	userResolver    struct{ *Resolver }
+)
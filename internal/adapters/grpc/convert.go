@@ -0,0 +1,44 @@
+package grpc
+
+import (
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/grpc/usersv1"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// toProtoUser converts a domain user to its protobuf representation.
+// The password hash is never exposed.
+func toProtoUser(user *entities.User) *usersv1.User {
+	proto := &usersv1.User{
+		Id:         user.ID().Int64(),
+		Uuid:       user.UUID().String(),
+		Email:      user.Email().String(),
+		Username:   user.Username().String(),
+		FirstName:  user.FirstName().String(),
+		LastName:   user.LastName().String(),
+		Status:     user.Status().String(),
+		Role:       user.Role().String(),
+		IsVerified: user.IsVerified(),
+		Tags:       user.Tags(),
+		CreatedAt:  timestamppb.New(user.CreatedAt()),
+		UpdatedAt:  timestamppb.New(user.UpdatedAt()),
+	}
+
+	if lastLogin := user.LastLoginAt(); lastLogin != nil {
+		proto.LastLoginAt = timestamppb.New(*lastLogin)
+	}
+
+	return proto
+}
+
+// toProtoSession converts a domain session to its protobuf representation
+// carrying the client token issued for it.
+func toProtoSession(session *entities.UserSession, token string) *usersv1.Session {
+	return &usersv1.Session{
+		Id:        session.ID().Int64(),
+		UserId:    session.UserID().Int64(),
+		Token:     token,
+		CreatedAt: timestamppb.New(session.CreatedAt()),
+		ExpiresAt: timestamppb.New(session.ExpiresAt()),
+	}
+}
@@ -0,0 +1,104 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	apperrors "github.com/LarsArtmann/template-sqlc/pkg/errors"
+	"github.com/LarsArtmann/template-sqlc/pkg/i18n"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ToStatus converts a domain error to a gRPC status error.
+// Errors that already carry a status, and nil, are returned unchanged.
+// Details of internal errors are not sent to clients. The field errors of
+// invalid arguments are attached as the field violations of a BadRequest.
+func ToStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	code := codeFor(err)
+	if code == codes.Internal {
+		return status.Error(code, "internal error")
+	}
+
+	st := status.New(code, err.Error())
+
+	if fields := apperrors.FieldErrorsOf(err); code == codes.InvalidArgument && len(fields) > 0 {
+		if detailed, detailsErr := st.WithDetails(badRequest(fields)); detailsErr == nil {
+			st = detailed
+		}
+	}
+
+	return st.Err()
+}
+
+// badRequest returns the field violations of fields, with their codes as reasons.
+func badRequest(fields apperrors.ValidationErrors) *errdetails.BadRequest {
+	violations := make([]*errdetails.BadRequest_FieldViolation, len(fields))
+	for i, field := range fields {
+		violations[i] = &errdetails.BadRequest_FieldViolation{
+			Field:       field.Field,
+			Description: field.Message,
+			Reason:      string(field.Code),
+		}
+	}
+
+	return &errdetails.BadRequest{FieldViolations: violations}
+}
+
+// codeFor maps errors to gRPC codes by the HTTP status of their error code,
+// which covers the domain errors and the application errors of pkg/errors.
+func codeFor(err error) codes.Code {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return codes.Canceled
+	case errors.Is(err, context.DeadlineExceeded):
+		return codes.DeadlineExceeded
+	}
+
+	switch apperrors.HTTPStatus(err) {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.AlreadyExists
+	case http.StatusRequestTimeout, http.StatusGatewayTimeout:
+		return codes.DeadlineExceeded
+	case http.StatusServiceUnavailable:
+		return codes.Unavailable
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	default:
+		return codes.Internal
+	}
+}
+
+// ErrorInterceptor converts domain errors returned by handlers to gRPC
+// statuses, in the language negotiated by i18n.UnaryServerInterceptor.
+func ErrorInterceptor() grpclib.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req any,
+		_ *grpclib.UnaryServerInfo,
+		handler grpclib.UnaryHandler,
+	) (any, error) {
+		resp, err := handler(ctx, req)
+
+		return resp, ToStatus(i18n.Localize(ctx, err))
+	}
+}
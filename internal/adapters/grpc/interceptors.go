@@ -0,0 +1,199 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/grpc/usersv1"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/services"
+	"github.com/LarsArtmann/template-sqlc/internal/monitoring"
+	"github.com/LarsArtmann/template-sqlc/pkg/i18n"
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// authorizationHeader is the metadata key carrying the bearer session token.
+const authorizationHeader = "authorization"
+
+// PublicMethods are the RPCs that can be called without a session.
+var PublicMethods = []string{
+	usersv1.UserService_CreateUser_FullMethodName,
+	usersv1.UserService_Authenticate_FullMethodName,
+	usersv1.UserService_VerifySession_FullMethodName,
+}
+
+// SessionVerifier resolves session tokens. It is implemented by services.UserService.
+type SessionVerifier interface {
+	VerifySession(ctx context.Context, token string) (*entities.UserSession, *entities.User, error)
+}
+
+// RPCMetrics records RPC outcomes. It is implemented by monitoring.Metrics.
+type RPCMetrics interface {
+	ObserveRPC(method, code string, duration time.Duration)
+}
+
+var (
+	_ SessionVerifier = (*services.UserService)(nil)
+	_ RPCMetrics      = (*monitoring.Metrics)(nil)
+)
+
+// principalKey is the context key for the authenticated session and user.
+type principalKey struct{}
+
+// principal is the authenticated caller of an RPC.
+type principal struct {
+	session *entities.UserSession
+	user    *entities.User
+}
+
+// UserFromContext returns the authenticated user and session of the current RPC.
+func UserFromContext(ctx context.Context) (*entities.User, *entities.UserSession, bool) {
+	p, ok := ctx.Value(principalKey{}).(principal)
+	if !ok {
+		return nil, nil, false
+	}
+
+	return p.user, p.session, true
+}
+
+// AuthInterceptor requires a valid "authorization: Bearer <token>" header on
+// every method except the public ones, and stores the caller in the context.
+// Public methods called with a token still verify it, so that they can tell
+// an authenticated caller, such as an admin creating a user, from an
+// anonymous one.
+func AuthInterceptor(verifier SessionVerifier, public ...string) grpclib.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req any,
+		info *grpclib.UnaryServerInfo,
+		handler grpclib.UnaryHandler,
+	) (any, error) {
+		token, ok := bearerToken(ctx)
+		if !ok {
+			if slices.Contains(public, info.FullMethod) {
+				return handler(ctx, req)
+			}
+
+			return nil, status.Error(codes.Unauthenticated, "missing bearer token")
+		}
+
+		ctx, err := authenticate(ctx, verifier, token)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// authenticate verifies token and stores the caller and audit actor in ctx.
+func authenticate(ctx context.Context, verifier SessionVerifier, token string) (context.Context, error) {
+	session, user, err := verifier.VerifySession(ctx, token)
+	if err != nil {
+		return nil, ToStatus(err)
+	}
+
+	ctx = context.WithValue(ctx, principalKey{}, principal{session: session, user: user})
+	// Operations in impersonation sessions are attributed to the impersonator.
+	actorID := user.ID()
+	if impersonatorID, ok := session.Impersonator(); ok {
+		actorID = impersonatorID
+	}
+
+	return services.ContextWithAuditActor(ctx, services.AuditActor{
+		UserID:    actorID,
+		IPAddress: peerIP(ctx),
+	}), nil
+}
+
+// requirePermission returns PermissionDenied unless the caller of the RPC
+// has a role granting permission.
+func requirePermission(ctx context.Context, permission entities.Permission) error {
+	user, _, ok := UserFromContext(ctx)
+	if !ok {
+		return status.Error(codes.Unauthenticated, "missing bearer token")
+	}
+
+	if !user.Role().Grants(permission) {
+		return status.Errorf(codes.PermissionDenied, "role %s lacks permission %s", user.Role(), permission)
+	}
+
+	return nil
+}
+
+// peerIP returns the IP address of the calling peer, or "" if unknown.
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return ""
+	}
+
+	return host
+}
+
+// bearerToken extracts the bearer token from incoming metadata.
+func bearerToken(ctx context.Context) (string, bool) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", false
+	}
+
+	for _, value := range md.Get(authorizationHeader) {
+		token, found := strings.CutPrefix(value, "Bearer ")
+		if found && token != "" {
+			return token, true
+		}
+	}
+
+	return "", false
+}
+
+// MetricsInterceptor records the duration and status code of every RPC.
+// It should run outside ErrorInterceptor so that mapped codes are observed.
+func MetricsInterceptor(metrics RPCMetrics) grpclib.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req any,
+		info *grpclib.UnaryServerInfo,
+		handler grpclib.UnaryHandler,
+	) (any, error) {
+		start := time.Now()
+
+		resp, err := handler(ctx, req)
+
+		metrics.ObserveRPC(info.FullMethod, status.Code(err).String(), time.Since(start))
+
+		return resp, err
+	}
+}
+
+// ServerOptions returns the interceptor chain for a user service gRPC server:
+// metrics, then language negotiation and error conversion, then the guards,
+// such as rate limits, then authentication.
+func ServerOptions(
+	verifier SessionVerifier,
+	metrics RPCMetrics,
+	guards ...grpclib.UnaryServerInterceptor,
+) []grpclib.ServerOption {
+	chain := []grpclib.UnaryServerInterceptor{
+		MetricsInterceptor(metrics),
+		i18n.UnaryServerInterceptor(i18n.DefaultCatalog()),
+		ErrorInterceptor(),
+	}
+	chain = append(chain, guards...)
+	chain = append(chain, AuthInterceptor(verifier, PublicMethods...))
+
+	return []grpclib.ServerOption{grpclib.ChainUnaryInterceptor(chain...)}
+}
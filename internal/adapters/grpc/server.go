@@ -0,0 +1,192 @@
+// Package grpc exposes the user service over gRPC.
+// The protobuf types in usersv1 are generated from proto/users/v1 with buf.
+package grpc
+
+import (
+	"context"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/grpc/usersv1"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/services"
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// defaultListLimit is used when ListUsers is called without a limit.
+const defaultListLimit = 50
+
+// idempotencyKeyHeader is the metadata key carrying the idempotency key of a write.
+const idempotencyKeyHeader = "idempotency-key"
+
+// Server implements usersv1.UserServiceServer on top of services.UserService.
+type Server struct {
+	usersv1.UnimplementedUserServiceServer
+
+	users *services.UserService
+}
+
+var _ usersv1.UserServiceServer = (*Server)(nil)
+
+// NewServer creates a gRPC server for the user service.
+func NewServer(users *services.UserService) *Server {
+	return &Server{users: users}
+}
+
+// Register registers the server on a gRPC service registrar.
+func (s *Server) Register(registrar grpclib.ServiceRegistrar) {
+	usersv1.RegisterUserServiceServer(registrar, s)
+}
+
+// CreateUser registers a new user. A retry with the idempotency-key
+// metadata of an earlier call returns the user that call created.
+//
+// The requested role and status are only honored for callers allowed to
+// manage roles; anyone else signs up as a pending user.
+func (s *Server) CreateUser(
+	ctx context.Context,
+	req *usersv1.CreateUserRequest,
+) (*usersv1.CreateUserResponse, error) {
+	role, userStatus := string(entities.UserRoleUser), string(entities.UserStatusPending)
+	if requirePermission(ctx, entities.PermissionRolesManage) == nil {
+		role, userStatus = req.GetRole(), req.GetStatus()
+	}
+
+	user, err := s.users.CreateUser(ctx, &services.CreateUserRequest{
+		Email:     req.GetEmail(),
+		Username:  req.GetUsername(),
+		Password:  req.GetPassword(),
+		FirstName: req.GetFirstName(),
+		LastName:  req.GetLastName(),
+		Status:    userStatus,
+		Role:      role,
+		Tags:      req.GetTags(),
+
+		IdempotencyKey: idempotencyKey(ctx),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &usersv1.CreateUserResponse{User: toProtoUser(user)}, nil
+}
+
+// Authenticate verifies credentials and opens a session. The login is
+// throttled, risk-checked and recorded under the address of the calling
+// peer; the ip_address of the request is client-supplied and ignored.
+func (s *Server) Authenticate(
+	ctx context.Context,
+	req *usersv1.AuthenticateRequest,
+) (*usersv1.AuthenticateResponse, error) {
+	session, err := s.users.AuthenticateUser(
+		ctx,
+		req.GetEmail(),
+		req.GetPassword(),
+		peerIP(ctx),
+		req.GetUserAgent(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.users.GetUser(ctx, session.UserID())
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := s.users.IssueSessionToken(session, user)
+	if err != nil {
+		return nil, err
+	}
+
+	return &usersv1.AuthenticateResponse{Session: toProtoSession(session, token)}, nil
+}
+
+// VerifySession resolves a session token to its session and user.
+func (s *Server) VerifySession(
+	ctx context.Context,
+	req *usersv1.VerifySessionRequest,
+) (*usersv1.VerifySessionResponse, error) {
+	session, user, err := s.users.VerifySession(ctx, req.GetToken())
+	if err != nil {
+		return nil, err
+	}
+
+	return &usersv1.VerifySessionResponse{
+		Session: toProtoSession(session, req.GetToken()),
+		User:    toProtoUser(user),
+	}, nil
+}
+
+// ListUsers lists users with a given status. It requires the
+// users.manage permission.
+func (s *Server) ListUsers(
+	ctx context.Context,
+	req *usersv1.ListUsersRequest,
+) (*usersv1.ListUsersResponse, error) {
+	err := requirePermission(ctx, entities.PermissionUsersManage)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := int(req.GetLimit())
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+
+	users, err := s.users.ListUsers(
+		ctx,
+		entities.UserFilter{Statuses: []entities.UserStatus{entities.UserStatus(req.GetStatus())}},
+		limit,
+		int(req.GetOffset()),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &usersv1.ListUsersResponse{Users: make([]*usersv1.User, 0, len(users))}
+	for _, user := range users {
+		resp.Users = append(resp.Users, toProtoUser(user))
+	}
+
+	return resp, nil
+}
+
+// GetStats returns aggregate user statistics. It requires the stats.read
+// permission.
+func (s *Server) GetStats(
+	ctx context.Context,
+	_ *usersv1.GetStatsRequest,
+) (*usersv1.GetStatsResponse, error) {
+	err := requirePermission(ctx, entities.PermissionStatsRead)
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := s.users.GetUserStats(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &usersv1.GetStatsResponse{
+		TotalUsers:       stats.TotalUsers,
+		ActiveUsers:      stats.ActiveUsers,
+		InactiveUsers:    stats.InactiveUsers,
+		SuspendedUsers:   stats.SuspendedUsers,
+		VerifiedUsers:    stats.VerifiedUsers,
+		UsersWithLogins:  stats.UsersWithLogins,
+		NewUsersMonth:    stats.NewUsers30d,
+		NewUsersWeek:     stats.NewUsers7d,
+		ActivePercentage: stats.ActivePercentage,
+		VerificationRate: stats.VerificationRate,
+	}, nil
+}
+
+// idempotencyKey returns the idempotency key from incoming metadata, if any.
+func idempotencyKey(ctx context.Context) string {
+	values := metadata.ValueFromIncomingContext(ctx, idempotencyKeyHeader)
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
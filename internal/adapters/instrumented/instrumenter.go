@@ -0,0 +1,157 @@
+// Package instrumented provides repository decorators that record query
+// metrics, trace every call and log slow calls, so that the database
+// adapters stay free of instrumentation code.
+package instrumented
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+const (
+	tracerName = "github.com/LarsArtmann/template-sqlc/internal/adapters/instrumented"
+
+	defaultSlowThreshold = 100 * time.Millisecond
+
+	unknownEngine = "unknown"
+)
+
+// QueryObserver receives the duration and outcome of every repository call,
+// labeled with the operation, e.g. "UserRepository.GetByID", and the engine.
+// *monitoring.Metrics satisfies it.
+type QueryObserver interface {
+	ObserveQuery(query, engine string, duration time.Duration, err error)
+}
+
+// contextQueryObserver is a QueryObserver that links observations to the
+// trace in ctx, e.g. as histogram exemplars.
+type contextQueryObserver interface {
+	ObserveQueryContext(ctx context.Context, query, engine string, duration time.Duration, err error)
+}
+
+// engineReporter is implemented by repositories that know their database
+// engine, such as the adapters built on adapters.DBUserRepository.
+type engineReporter interface {
+	Engine() string
+}
+
+// Option configures a decorator.
+type Option func(*instrumenter)
+
+// WithMetrics reports every call to observer.
+func WithMetrics(observer QueryObserver) Option {
+	return func(in *instrumenter) {
+		in.observer = observer
+	}
+}
+
+// WithTracerProvider creates a span per call with a tracer from provider.
+// Without it calls are not traced.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(in *instrumenter) {
+		in.tracer = provider.Tracer(tracerName)
+	}
+}
+
+// WithEngine labels calls with engine. Defaults to the Engine() of the
+// wrapped repository, if it has one.
+func WithEngine(engine string) Option {
+	return func(in *instrumenter) {
+		in.engine = engine
+	}
+}
+
+// WithSlowThreshold logs calls taking at least threshold. Defaults to 100ms;
+// zero disables slow call logging.
+func WithSlowThreshold(threshold time.Duration) Option {
+	return func(in *instrumenter) {
+		in.slowThreshold = threshold
+	}
+}
+
+// instrumenter records metrics, spans and slow call logs for one repository.
+type instrumenter struct {
+	repository    string
+	engine        string
+	observer      QueryObserver
+	tracer        trace.Tracer
+	slowThreshold time.Duration
+}
+
+func newInstrumenter(repository string, next any, opts []Option) *instrumenter {
+	in := &instrumenter{
+		repository:    repository,
+		engine:        unknownEngine,
+		tracer:        noop.NewTracerProvider().Tracer(tracerName),
+		slowThreshold: defaultSlowThreshold,
+	}
+
+	if reporter, ok := next.(engineReporter); ok && reporter.Engine() != "" {
+		in.engine = reporter.Engine()
+	}
+
+	for _, opt := range opts {
+		opt(in)
+	}
+
+	return in
+}
+
+// call runs fn for method inside a span, then records its duration and error.
+func call[T any](
+	ctx context.Context,
+	in *instrumenter,
+	method string,
+	fn func(ctx context.Context) (T, error),
+) (T, error) {
+	operation := in.repository + "." + method
+
+	ctx, span := in.tracer.Start(ctx, operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("repository", in.repository),
+			attribute.String("db.operation.name", method),
+			attribute.String("db.system.name", in.engine),
+		),
+	)
+	defer span.End()
+
+	start := time.Now()
+	result, err := fn(ctx)
+	duration := time.Since(start)
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	switch observer := in.observer.(type) {
+	case nil:
+	case contextQueryObserver:
+		observer.ObserveQueryContext(ctx, operation, in.engine, duration, err)
+	default:
+		observer.ObserveQuery(operation, in.engine, duration, err)
+	}
+
+	if in.slowThreshold > 0 && duration >= in.slowThreshold {
+		slog.WarnContext(ctx, "slow repository call",
+			"operation", operation, "duration", duration, "threshold", in.slowThreshold, "error", err)
+	}
+
+	return result, err
+}
+
+// exec is call for methods that only return an error.
+func exec(ctx context.Context, in *instrumenter, method string, fn func(ctx context.Context) error) error {
+	_, err := call(ctx, in, method, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, fn(ctx)
+	})
+
+	return err
+}
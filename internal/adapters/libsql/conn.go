@@ -0,0 +1,108 @@
+//go:build libsql
+
+package libsql
+
+import (
+	"context"
+	"database/sql/driver"
+	"io"
+	"time"
+)
+
+// timeLayout is the layout modernc.org/sqlite binds times in. It sorts with
+// the values of CURRENT_TIMESTAMP, which the RFC 3339 layout of the libsql
+// driver does not: "2006-01-02T..." sorts after "2006-01-02 ..." on the same day.
+const timeLayout = "2006-01-02 15:04:05.999999999-07:00"
+
+// connector hands out connections that bind times like the SQLite driver,
+// so the SQLite queries compare timestamps the same way on libSQL.
+type connector struct {
+	driver.Connector
+}
+
+func (c connector) Connect(ctx context.Context) (driver.Conn, error) {
+	inner, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return conn{inner}, nil
+}
+
+// Close closes the underlying connector and its embedded replica.
+func (c connector) Close() error {
+	closer, ok := c.Connector.(io.Closer)
+	if !ok {
+		return nil
+	}
+
+	return closer.Close()
+}
+
+// conn converts time arguments and forwards everything else to the libsql
+// connection, which implements the context variants of the driver interfaces.
+type conn struct {
+	driver.Conn
+}
+
+var (
+	_ driver.NamedValueChecker  = conn{}
+	_ driver.ExecerContext      = conn{}
+	_ driver.QueryerContext     = conn{}
+	_ driver.ConnPrepareContext = conn{}
+	_ driver.ConnBeginTx        = conn{}
+)
+
+// CheckNamedValue converts arguments like database/sql does by default,
+// including driver.Valuer such as sql.NullTime, and then binds times as UTC
+// text in timeLayout.
+func (c conn) CheckNamedValue(value *driver.NamedValue) error {
+	converted, err := driver.DefaultParameterConverter.ConvertValue(value.Value)
+	if err != nil {
+		return err
+	}
+
+	if t, ok := converted.(time.Time); ok {
+		converted = t.UTC().Format(timeLayout)
+	}
+
+	value.Value = converted
+
+	return nil
+}
+
+func (c conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+
+	return execer.ExecContext(ctx, query, args)
+}
+
+func (c conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+
+	return queryer.QueryContext(ctx, query, args)
+}
+
+func (c conn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	preparer, ok := c.Conn.(driver.ConnPrepareContext)
+	if !ok {
+		return c.Prepare(query)
+	}
+
+	return preparer.PrepareContext(ctx, query)
+}
+
+func (c conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	beginner, ok := c.Conn.(driver.ConnBeginTx)
+	if !ok {
+		return c.Begin() //nolint:staticcheck // Fallback for drivers without BeginTx.
+	}
+
+	return beginner.BeginTx(ctx, opts)
+}
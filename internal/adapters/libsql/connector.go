@@ -0,0 +1,60 @@
+//go:build libsql
+
+package libsql
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+
+	"github.com/LarsArtmann/template-sqlc/pkg/db"
+	"github.com/tursodatabase/go-libsql"
+)
+
+// errNoConnector is returned when the libsql driver cannot create connectors.
+var errNoConnector = errors.New("libsql driver does not support connectors")
+
+// Connector creates a connector for cfg, to open a pool with db.WithConnector
+// or sql.OpenDB. Closing the pool closes the connector and its replica.
+// Times are bound in the layout of the SQLite driver; see timeLayout.
+func Connector(cfg Config) (driver.Connector, error) {
+	if cfg.ReplicaPath == "" {
+		handle, err := sql.Open(string(db.DriverLibSQL), cfg.DSN())
+		if err != nil {
+			return nil, fmt.Errorf("open libsql: %w", err)
+		}
+
+		// The handle is only needed to reach the unexported driver.
+		defer func() { _ = handle.Close() }()
+
+		driverContext, ok := handle.Driver().(driver.DriverContext)
+		if !ok {
+			return nil, errNoConnector
+		}
+
+		inner, err := driverContext.OpenConnector(cfg.DSN())
+		if err != nil {
+			return nil, fmt.Errorf("libsql connector: %w", err)
+		}
+
+		return connector{inner}, nil
+	}
+
+	opts := []libsql.Option{libsql.WithSyncInterval(cfg.SyncInterval)}
+
+	if cfg.AuthToken != "" {
+		opts = append(opts, libsql.WithAuthToken(cfg.AuthToken))
+	}
+
+	if cfg.EncryptionKey != "" {
+		opts = append(opts, libsql.WithEncryption(cfg.EncryptionKey))
+	}
+
+	replica, err := libsql.NewEmbeddedReplicaConnector(cfg.ReplicaPath, cfg.URL, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("libsql replica path=%v: %w", cfg.ReplicaPath, err)
+	}
+
+	return connector{replica}, nil
+}
@@ -0,0 +1,132 @@
+// Package libsql connects to libSQL databases, such as Turso, and provides
+// their repositories.
+//
+// libSQL speaks the SQLite dialect, so the repositories are the SQLite
+// adapters over a libSQL connection; build with both tags:
+//
+//	go build -tags sqlite,libsql ./...
+//
+// A database is used remotely, or through an embedded replica: a local copy
+// that serves reads and forwards writes to the primary. Connector creates the
+// connection for either from a Config.
+package libsql
+
+import (
+	"net/url"
+	"time"
+
+	sqliteadapter "github.com/LarsArtmann/template-sqlc/internal/adapters/sqlite"
+	"github.com/LarsArtmann/template-sqlc/internal/db/shared"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// Config describes a libSQL connection.
+type Config struct {
+	// URL is the database, e.g. libsql://app-org.turso.io, or file:app.db
+	// for a local database.
+	URL string
+	// AuthToken authenticates with a remote database.
+	AuthToken string
+
+	// ReplicaPath keeps an embedded replica of URL in this local file.
+	// Empty connects to URL directly.
+	ReplicaPath string
+	// SyncInterval pulls changes from the primary into the replica
+	// periodically; zero syncs only after writes and on Sync.
+	SyncInterval time.Duration
+	// EncryptionKey encrypts the replica at rest.
+	EncryptionKey string
+}
+
+// DSN returns the URL with the auth token, as the libsql driver expects it.
+func (c Config) DSN() string {
+	if c.AuthToken == "" {
+		return c.URL
+	}
+
+	parsed, err := url.Parse(c.URL)
+	if err != nil {
+		return c.URL
+	}
+
+	query := parsed.Query()
+	query.Set("authToken", c.AuthToken)
+	parsed.RawQuery = query.Encode()
+
+	return parsed.String()
+}
+
+// NewUserRepository creates a user repository over a libSQL connection.
+func NewUserRepository(db shared.DBTX) repositories.UserRepository {
+	return sqliteadapter.NewUserRepository(db)
+}
+
+// NewSessionRepository creates a session repository over a libSQL connection.
+func NewSessionRepository(db shared.DBTX) repositories.SessionRepository {
+	return sqliteadapter.NewSessionRepository(db)
+}
+
+// NewJobRepository creates a job repository over a libSQL connection.
+func NewJobRepository(db shared.DBTX) repositories.JobRepository {
+	return sqliteadapter.NewJobRepository(db)
+}
+
+// NewIdempotencyRepository creates an idempotency repository over a libSQL connection.
+func NewIdempotencyRepository(db shared.DBTX) repositories.IdempotencyRepository {
+	return sqliteadapter.NewIdempotencyRepository(db)
+}
+
+// NewNotificationPreferenceRepository creates a notification preference repository over a libSQL connection.
+func NewNotificationPreferenceRepository(db shared.DBTX) repositories.NotificationPreferenceRepository {
+	return sqliteadapter.NewNotificationPreferenceRepository(db)
+}
+
+// NewIdentityChangeRepository creates an identity change repository over a libSQL connection.
+func NewIdentityChangeRepository(db shared.DBTX) repositories.IdentityChangeRepository {
+	return sqliteadapter.NewIdentityChangeRepository(db)
+}
+
+// NewModerationRepository creates a moderation repository over a libSQL connection.
+func NewModerationRepository(db shared.DBTX) repositories.ModerationRepository {
+	return sqliteadapter.NewModerationRepository(db)
+}
+
+// NewAccountDeletionRepository creates an account deletion repository over a libSQL connection.
+func NewAccountDeletionRepository(db shared.DBTX) repositories.AccountDeletionRepository {
+	return sqliteadapter.NewAccountDeletionRepository(db)
+}
+
+// NewUserPreferenceRepository creates a user preference repository over a libSQL connection.
+func NewUserPreferenceRepository(db shared.DBTX) repositories.UserPreferenceRepository {
+	return sqliteadapter.NewUserPreferenceRepository(db)
+}
+
+// NewActivityRepository creates an activity repository over a libSQL connection.
+func NewActivityRepository(db shared.DBTX) repositories.ActivityRepository {
+	return sqliteadapter.NewActivityRepository(db)
+}
+
+// NewLoginHistoryRepository creates a login history repository over a libSQL connection.
+func NewLoginHistoryRepository(db shared.DBTX) repositories.LoginHistoryRepository {
+	return sqliteadapter.NewLoginHistoryRepository(db)
+}
+
+// NewUserEventStore creates a user event store over a libSQL connection.
+func NewUserEventStore(db shared.DBTX) repositories.UserEventStore {
+	return sqliteadapter.NewUserEventStore(db)
+}
+
+// NewUserReadModelRepository creates a user read model repository over a libSQL connection.
+func NewUserReadModelRepository(db shared.DBTX) repositories.UserReadModelRepository {
+	return sqliteadapter.NewUserReadModelRepository(db)
+}
+
+// NewFeatureFlagRepository creates a feature flag repository over a libSQL connection.
+func NewFeatureFlagRepository(db shared.DBTX) repositories.FeatureFlagRepository {
+	return sqliteadapter.NewFeatureFlagRepository(db)
+}
+
+// NewAuditRepository creates an audit repository over a libSQL connection.
+func NewAuditRepository(db shared.DBTX) repositories.AuditRepository {
+	return sqliteadapter.NewAuditRepository(db)
+}
@@ -0,0 +1,78 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/validation"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+var (
+	// ErrRowLockingNotSupported is returned by adapters whose engine has no row-level locks.
+	ErrRowLockingNotSupported = errors.New("row-level locking not supported")
+	// ErrLockOutsideTransaction is returned by locking reads on a repository
+	// not bound to a transaction, whose locks would be released at once.
+	ErrLockOutsideTransaction = errors.New("locking read outside a transaction")
+)
+
+// LockingClause renders lock options as a PostgreSQL/MySQL 8 locking clause,
+// e.g. "FOR UPDATE SKIP LOCKED".
+func LockingClause(opts repositories.LockOptions) string {
+	clause := "FOR UPDATE"
+	if opts.Strength == repositories.LockForShare {
+		clause = "FOR SHARE"
+	}
+
+	switch opts.Wait {
+	case repositories.LockWaitNoWait:
+		return clause + " NOWAIT"
+	case repositories.LockWaitSkipLocked:
+		return clause + " SKIP LOCKED"
+	case repositories.LockWaitBlock:
+		return clause
+	}
+
+	return clause
+}
+
+// WithLockingClause appends a locking clause to a generated SELECT statement,
+// so sqlc queries can be reused for locking reads.
+func WithLockingClause(query string, opts ...repositories.LockOption) string {
+	query = strings.TrimRight(strings.TrimSpace(query), ";")
+
+	return query + " " + LockingClause(repositories.NewLockOptions(opts...))
+}
+
+// ClaimNextWithValidation handles common validation for ClaimNext methods.
+// Returns validation error or calls notImplementedStub if validation passes.
+func ClaimNextWithValidation[T NotImplementedMethods](
+	_ context.Context,
+	repo T,
+	status entities.UserStatus,
+	limit int,
+	methodName string,
+) (NotImplementedListResult, error) {
+	if !status.IsValid() {
+		return nil, fmt.Errorf("status=%v: %w", status, entities.ErrInvalidUserStatus)
+	}
+
+	err := validation.ValidatePagination(limit, 0)
+	if err != nil {
+		return nil, fmt.Errorf("limit=%v: %w", limit, err)
+	}
+
+	return nil, notImplementedError(repo, methodName)
+}
+
+// ClaimNext locks the next users in the given status.
+func (r *BaseUserRepository) ClaimNext(
+	ctx context.Context,
+	status entities.UserStatus,
+	limit int,
+) ([]*entities.User, error) {
+	return ClaimNextWithValidation(ctx, r, status, limit, "ClaimNext")
+}
@@ -0,0 +1,40 @@
+package mappers
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+)
+
+// EncodeAuditChanges encodes audit field changes as a JSON array.
+func EncodeAuditChanges(changes []entities.FieldChange) (string, error) {
+	if changes == nil {
+		changes = []entities.FieldChange{}
+	}
+
+	encoded, err := json.Marshal(changes)
+	if err != nil {
+		return "", fmt.Errorf("encode audit changes: %w", err)
+	}
+
+	return string(encoded), nil
+}
+
+// DecodeAuditChanges decodes audit field changes stored as a JSON array
+// in a string or []byte.
+func DecodeAuditChanges(value any) ([]entities.FieldChange, error) {
+	raw, err := jsonBytes(value)
+	if err != nil || raw == nil {
+		return []entities.FieldChange{}, err
+	}
+
+	changes := make([]entities.FieldChange, 0)
+
+	err = json.Unmarshal(raw, &changes)
+	if err != nil {
+		return nil, fmt.Errorf("decode audit changes: %w", err)
+	}
+
+	return changes, nil
+}
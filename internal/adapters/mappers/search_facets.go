@@ -0,0 +1,80 @@
+package mappers
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+)
+
+// DecodeSearchFacets decodes the JSON facet columns returned by the
+// SearchUsersWithFacets queries: status and role counts are JSON objects,
+// tag counts are a JSON array of [tag, count] pairs ordered by count.
+func DecodeSearchFacets(statusCounts, roleCounts, tagCounts any) (*entities.SearchFacets, error) {
+	facets := entities.NewSearchFacets()
+
+	byStatus, err := decodeCounts(statusCounts)
+	if err != nil {
+		return nil, fmt.Errorf("status facets: %w", err)
+	}
+
+	for status, count := range byStatus {
+		facets.ByStatus[entities.UserStatus(status)] = count
+	}
+
+	byRole, err := decodeCounts(roleCounts)
+	if err != nil {
+		return nil, fmt.Errorf("role facets: %w", err)
+	}
+
+	for role, count := range byRole {
+		facets.ByRole[entities.UserRole(role)] = count
+	}
+
+	raw, err := jsonBytes(tagCounts)
+	if err != nil || raw == nil {
+		return facets, err
+	}
+
+	var pairs [][2]json.RawMessage
+
+	err = json.Unmarshal(raw, &pairs)
+	if err != nil {
+		return nil, fmt.Errorf("tag facets: %w", err)
+	}
+
+	for _, pair := range pairs {
+		var tagCount entities.TagCount
+
+		err = json.Unmarshal(pair[0], &tagCount.Tag)
+		if err != nil {
+			return nil, fmt.Errorf("tag facet name: %w", err)
+		}
+
+		err = json.Unmarshal(pair[1], &tagCount.Count)
+		if err != nil {
+			return nil, fmt.Errorf("tag facet tag=%s count: %w", tagCount.Tag, err)
+		}
+
+		facets.TopTags = append(facets.TopTags, tagCount)
+	}
+
+	return facets, nil
+}
+
+// decodeCounts decodes a JSON object of counts keyed by value.
+func decodeCounts(value any) (map[string]int64, error) {
+	counts := make(map[string]int64)
+
+	raw, err := jsonBytes(value)
+	if err != nil || raw == nil {
+		return counts, err
+	}
+
+	err = json.Unmarshal(raw, &counts)
+	if err != nil {
+		return nil, fmt.Errorf("decode counts: %w", err)
+	}
+
+	return counts, nil
+}
@@ -0,0 +1,50 @@
+package mappers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+)
+
+// UserSnapshotRow holds the columns of an archived or current user version.
+// Tags are a native array or a JSON array in a string or []byte; ValidTo is
+// nil for the current version.
+type UserSnapshotRow struct {
+	UserID     entities.UserID
+	Operation  string
+	Email      string
+	Username   string
+	FirstName  string
+	LastName   string
+	Status     string
+	Role       string
+	Tags       any
+	IsVerified bool
+	ValidFrom  time.Time
+	ValidTo    *time.Time
+}
+
+// DomainUserSnapshot converts the columns of a user version into a domain
+// snapshot.
+func DomainUserSnapshot(row UserSnapshotRow) (*entities.UserSnapshot, error) {
+	tags, err := DecodeTags(row.Tags)
+	if err != nil {
+		return nil, fmt.Errorf("user version user=%v: %w", row.UserID, err)
+	}
+
+	return &entities.UserSnapshot{
+		UserID:     row.UserID,
+		Operation:  entities.HistoryOperation(row.Operation),
+		Email:      entities.Email(row.Email),
+		Username:   entities.Username(row.Username),
+		FirstName:  entities.FirstName(row.FirstName),
+		LastName:   entities.LastName(row.LastName),
+		Status:     entities.UserStatus(row.Status),
+		Role:       entities.UserRole(row.Role),
+		Tags:       tags,
+		IsVerified: row.IsVerified,
+		ValidFrom:  row.ValidFrom,
+		ValidTo:    row.ValidTo,
+	}, nil
+}
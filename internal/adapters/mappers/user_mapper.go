@@ -12,118 +12,35 @@ import (
 
 // UserMapper handles conversion between domain entities and database models
 // This isolates domain entities from database-specific types.
+//
+// The per-engine user conversions are generated from the sqlc models; rerun
+// go generate after changing the users schema.
 type UserMapper struct{}
 
+//go:generate go run ../../../cmd/mappergen -engine SQLite -models ../../db/sqlite/models.go -out user_sqlite_gen.go
+//go:generate go run ../../../cmd/mappergen -engine Postgres -models ../../db/postgres/models.go -out user_postgres_gen.go
+//go:generate go run ../../../cmd/mappergen -engine MySQL -models ../../db/mysql/models.go -out user_mysql_gen.go
+
 // NewUserMapper creates a new UserMapper instance.
 func NewUserMapper() *UserMapper {
 	return &UserMapper{}
 }
 
-// DomainUserFromSQLite converts SQLite model to domain entity.
-func (m *UserMapper) DomainUserFromSQLite(sqliteUser any) (*entities.User, error) {
-	return m.DomainUser(sqliteUser)
-}
-
-// DomainUserFromPostgres converts PostgreSQL model to domain entity.
-func (m *UserMapper) DomainUserFromPostgres(postgresUser any) (*entities.User, error) {
-	return m.DomainUser(postgresUser)
-}
-
-// DomainUserFromMySQL converts MySQL model to domain entity.
-func (m *UserMapper) DomainUserFromMySQL(mysqlUser any) (*entities.User, error) {
-	return m.DomainUser(mysqlUser)
-}
-
-// SQLiteUserFromDomain converts domain entity to SQLite model.
-func (m *UserMapper) SQLiteUserFromDomain(_ *entities.User) (any, error) {
-	return unimplementedUserFromDomain("SQLite")
-}
-
-// unimplementedUserFromDomain is a helper for stub implementations.
-func unimplementedUserFromDomain(db string) (any, error) {
-	panic("implement me: convert domain entity to " + db + " user")
-}
-
-// DomainUser is the common implementation for DomainUserFromXxx methods.
-func (m *UserMapper) DomainUser(_ any) (*entities.User, error) {
-	panic("implement me: convert user to domain entity")
-}
-
-// PostgresUserFromDomain converts domain entity to PostgreSQL model.
-func (m *UserMapper) PostgresUserFromDomain(_ *entities.User) (any, error) {
-	return unimplementedUserFromDomain("PostgreSQL")
-}
-
-// MySQLUserFromDomain converts domain entity to MySQL model.
-func (m *UserMapper) MySQLUserFromDomain(_ *entities.User) (any, error) {
-	return unimplementedUserFromDomain("MySQL")
-}
-
-// DomainSessionFromSQLite converts SQLite session to domain entity.
-func (m *UserMapper) DomainSessionFromSQLite(sqliteSession any) (*entities.UserSession, error) {
-	return m.DomainSession(sqliteSession)
-}
-
-// DomainSessionFromPostgres converts PostgreSQL session to domain entity.
-func (m *UserMapper) DomainSessionFromPostgres(postgresSession any) (*entities.UserSession, error) {
-	return m.DomainSession(postgresSession)
-}
-
-// DomainSessionFromMySQL converts MySQL session to domain entity.
-func (m *UserMapper) DomainSessionFromMySQL(mysqlSession any) (*entities.UserSession, error) {
-	return m.DomainSession(mysqlSession)
-}
-
-// SQLiteSessionFromDomain converts domain entity to SQLite model.
-func (m *UserMapper) SQLiteSessionFromDomain(session *entities.UserSession) (any, error) {
-	return m.SessionFromDomain(session)
-}
-
-// SQLiteUserFromDomain is a standalone function wrapper for backward compatibility.
-func SQLiteUserFromDomain(user *entities.User) (any, error) {
-	return withMapper[*entities.User](user, func(m *UserMapper) (any, error) {
-		return m.SQLiteUserFromDomain(user)
-	})
-}
-
-// SQLiteSessionFromDomain is a standalone function wrapper for backward compatibility.
-func SQLiteSessionFromDomain(session *entities.UserSession) (any, error) {
-	return withMapper[*entities.UserSession](session, func(m *UserMapper) (any, error) {
-		return m.SQLiteSessionFromDomain(session)
-	})
-}
-
-// withMapper executes a mapper function with a fresh UserMapper instance.
-func withMapper[T any](_ T, fn func(*UserMapper) (any, error)) (any, error) {
-	m := &UserMapper{}
-
-	return fn(m)
-}
-
-// PostgresSessionFromDomain converts domain entity to PostgreSQL model.
-func (m *UserMapper) PostgresSessionFromDomain(session *entities.UserSession) (any, error) {
-	return m.SessionFromDomain(session)
-}
-
-// MySQLSessionFromDomain converts domain entity to MySQL model.
-func (m *UserMapper) MySQLSessionFromDomain(session *entities.UserSession) (any, error) {
-	return m.SessionFromDomain(session)
-}
-
-// SessionMapper interface for session conversion operations.
+// SessionMapper converts sessions to and from their stored records.
 type SessionMapper interface {
-	DomainSession(data any) (*entities.UserSession, error)
-	SessionFromDomain(session *entities.UserSession) (any, error)
+	DomainSession(record entities.SessionRecord) (*entities.UserSession, error)
+	SessionFromDomain(session *entities.UserSession) entities.SessionRecord
 }
 
-// DomainSession is the common implementation for DomainSessionFromXxx methods.
-func (m *UserMapper) DomainSession(_ any) (*entities.UserSession, error) {
-	panic("implement me: convert session to domain entity")
+// DomainSession reconstructs a session from its stored record. Sessions have
+// no table yet, so every engine stores them as an entities.SessionRecord.
+func (m *UserMapper) DomainSession(record entities.SessionRecord) (*entities.UserSession, error) {
+	return entities.ReconstructSession(record)
 }
 
-// SessionFromDomain is the common implementation for XxxSessionFromDomain methods.
-func (m *UserMapper) SessionFromDomain(_ *entities.UserSession) (any, error) {
-	panic("implement me: convert domain entity to session")
+// SessionFromDomain returns the record to store for session.
+func (m *UserMapper) SessionFromDomain(session *entities.UserSession) entities.SessionRecord {
+	return session.Record()
 }
 
 // Helper functions for common conversions
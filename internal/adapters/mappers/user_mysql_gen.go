@@ -0,0 +1,105 @@
+// Code generated by mappergen from ../../db/mysql/models.go. DO NOT EDIT.
+
+//go:build mysql
+
+package mappers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	mysqldb "github.com/LarsArtmann/template-sqlc/internal/db/mysql"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+)
+
+// DomainUserFromMySQL converts a MySQL users row into a domain entity.
+func (m *UserMapper) DomainUserFromMySQL(model *mysqldb.Users) (*entities.User, error) {
+	row, err := MySQLUserRow(model)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.DomainUserFromRow(row)
+}
+
+// MySQLUserFromDomain converts a domain entity into a MySQL users row.
+func (m *UserMapper) MySQLUserFromDomain(user *entities.User) (*mysqldb.Users, error) {
+	row, err := m.UserRowFromDomain(user)
+	if err != nil {
+		return nil, err
+	}
+
+	return MySQLUserModel(row)
+}
+
+// MySQLUserRow copies a MySQL users row into a UserRow.
+func MySQLUserRow(model *mysqldb.Users) (*UserRow, error) {
+	row := &UserRow{
+		ID:           int64(model.ID),
+		Email:        model.Email,
+		Username:     model.Username,
+		PasswordHash: model.PasswordHash,
+		FirstName:    model.FirstName,
+		LastName:     model.LastName,
+		CreatedAt:    model.CreatedAt.Time,
+		UpdatedAt:    model.UpdatedAt.Time,
+		IsVerified:   model.IsVerified.Bool,
+		Metadata:     []byte(model.ProfileMetadata),
+		Status:       model.Status,
+		Role:         model.Role,
+		Tags:         []byte(model.Tags),
+	}
+
+	var err error
+
+	row.LastLoginAt, err = nullTime.DBToDomain(model.LastLoginAt)
+	if err != nil {
+		return nil, fmt.Errorf("user id=%d LastLoginAt: %w", row.ID, err)
+	}
+
+	row.UUID, err = UUIDString(model.UUID)
+	if err != nil {
+		return nil, fmt.Errorf("user id=%d UUID: %w", row.ID, err)
+	}
+
+	return row, nil
+}
+
+// MySQLUserModel copies a UserRow into a MySQL users row.
+// IsActive, DeletedAt and UsernameCanonical have no UserRow field and are left zero.
+func MySQLUserModel(row *UserRow) (*mysqldb.Users, error) {
+	model := &mysqldb.Users{
+		ID:           uint64(row.ID),
+		Email:        row.Email,
+		Username:     row.Username,
+		PasswordHash: row.PasswordHash,
+		FirstName:    row.FirstName,
+		LastName:     row.LastName,
+		CreatedAt:    sql.NullTime{Time: row.CreatedAt, Valid: !row.CreatedAt.IsZero()},
+		UpdatedAt:    sql.NullTime{Time: row.UpdatedAt, Valid: !row.UpdatedAt.IsZero()},
+		LastLoginAt:  nullTime.DomainToDB(row.LastLoginAt),
+		IsVerified:   sql.NullBool{Bool: row.IsVerified, Valid: true},
+		Status:       row.Status,
+		Role:         row.Role,
+	}
+
+	var err error
+
+	model.ProfileMetadata, err = JSONText[json.RawMessage](row.Metadata)
+	if err != nil {
+		return nil, fmt.Errorf("user id=%d Metadata: %w", row.ID, err)
+	}
+
+	model.Tags, err = TagsJSON[json.RawMessage](row.Tags)
+	if err != nil {
+		return nil, fmt.Errorf("user id=%d Tags: %w", row.ID, err)
+	}
+
+	model.UUID, err = UUIDBytes(row.UUID)
+	if err != nil {
+		return nil, fmt.Errorf("user id=%d UUID: %w", row.ID, err)
+	}
+
+	return model, nil
+}
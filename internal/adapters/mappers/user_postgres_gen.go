@@ -0,0 +1,101 @@
+// Code generated by mappergen from ../../db/postgres/models.go. DO NOT EDIT.
+
+//go:build postgres
+
+package mappers
+
+import (
+	"fmt"
+
+	postgresdb "github.com/LarsArtmann/template-sqlc/internal/db/postgres"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// DomainUserFromPostgres converts a Postgres users row into a domain entity.
+func (m *UserMapper) DomainUserFromPostgres(model *postgresdb.Users) (*entities.User, error) {
+	row, err := PostgresUserRow(model)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.DomainUserFromRow(row)
+}
+
+// PostgresUserFromDomain converts a domain entity into a Postgres users row.
+func (m *UserMapper) PostgresUserFromDomain(user *entities.User) (*postgresdb.Users, error) {
+	row, err := m.UserRowFromDomain(user)
+	if err != nil {
+		return nil, err
+	}
+
+	return PostgresUserModel(row)
+}
+
+// PostgresUserRow copies a Postgres users row into a UserRow.
+func PostgresUserRow(model *postgresdb.Users) (*UserRow, error) {
+	row := &UserRow{
+		ID:           model.ID,
+		UUID:         model.UUID.String(),
+		Email:        model.Email,
+		Username:     model.Username,
+		PasswordHash: model.PasswordHash,
+		FirstName:    model.FirstName,
+		LastName:     model.LastName,
+		CreatedAt:    model.CreatedAt.Time,
+		UpdatedAt:    model.UpdatedAt.Time,
+		IsVerified:   model.IsVerified != nil && *model.IsVerified,
+		Metadata:     model.ProfileMetadata,
+		Status:       model.Status,
+		Role:         model.Role,
+		Tags:         model.Tags,
+	}
+
+	var err error
+
+	row.LastLoginAt, err = timestamptz.DBToDomain(model.LastLoginAt)
+	if err != nil {
+		return nil, fmt.Errorf("user id=%d LastLoginAt: %w", row.ID, err)
+	}
+
+	return row, nil
+}
+
+// PostgresUserModel copies a UserRow into a Postgres users row.
+// IsActive, DeletedAt and UsernameCanonical have no UserRow field and are left zero.
+func PostgresUserModel(row *UserRow) (*postgresdb.Users, error) {
+	model := &postgresdb.Users{
+		ID:           row.ID,
+		Email:        row.Email,
+		Username:     row.Username,
+		PasswordHash: row.PasswordHash,
+		FirstName:    row.FirstName,
+		LastName:     row.LastName,
+		CreatedAt:    pgtype.Timestamptz{Time: row.CreatedAt, Valid: !row.CreatedAt.IsZero()},
+		UpdatedAt:    pgtype.Timestamptz{Time: row.UpdatedAt, Valid: !row.UpdatedAt.IsZero()},
+		LastLoginAt:  timestamptz.DomainToDB(row.LastLoginAt),
+		IsVerified:   BoolPtr(row.IsVerified),
+		Status:       row.Status,
+		Role:         row.Role,
+	}
+
+	var err error
+
+	model.UUID, err = uuid.Parse(row.UUID)
+	if err != nil {
+		return nil, fmt.Errorf("user id=%d UUID: %w", row.ID, err)
+	}
+
+	model.ProfileMetadata, err = JSONText[[]byte](row.Metadata)
+	if err != nil {
+		return nil, fmt.Errorf("user id=%d Metadata: %w", row.ID, err)
+	}
+
+	model.Tags, err = DecodeTags(row.Tags)
+	if err != nil {
+		return nil, fmt.Errorf("user id=%d Tags: %w", row.ID, err)
+	}
+
+	return model, nil
+}
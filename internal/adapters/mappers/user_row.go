@@ -0,0 +1,230 @@
+package mappers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/google/uuid"
+)
+
+// ErrUnsupportedModel is returned when a mapper receives a model it cannot convert.
+var ErrUnsupportedModel = errors.New("unsupported database model")
+
+// sqliteTimeLayout is the format SQLite uses for CURRENT_TIMESTAMP.
+const sqliteTimeLayout = "2006-01-02 15:04:05"
+
+// UserRow is the engine-neutral shape of a persisted user.
+// Adapters copy their sqlc-generated models into it so that hydration
+// of domain entities is implemented once for every database.
+type UserRow struct {
+	ID           int64
+	UUID         string
+	Email        string
+	Username     string
+	PasswordHash string
+	FirstName    string
+	LastName     string
+	Status       string
+	Role         string
+	IsVerified   bool
+	// Metadata holds the JSON-encoded profile metadata as string, []byte or nil.
+	Metadata any
+	// Tags holds a []string, or the JSON-encoded tag array as string, []byte or nil.
+	Tags      any
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	// LastLoginAt is nil for users who never logged in.
+	LastLoginAt *time.Time
+}
+
+// DomainUserFromRow converts an engine-neutral row into a domain entity.
+func (m *UserMapper) DomainUserFromRow(row *UserRow) (*entities.User, error) {
+	parsedUUID, err := uuid.Parse(row.UUID)
+	if err != nil {
+		return nil, fmt.Errorf("user id=%d uuid=%q: %w", row.ID, row.UUID, err)
+	}
+
+	metadata, err := DecodeMetadata(row.Metadata)
+	if err != nil {
+		return nil, fmt.Errorf("user id=%d metadata: %w", row.ID, err)
+	}
+
+	tags, err := DecodeTags(row.Tags)
+	if err != nil {
+		return nil, fmt.Errorf("user id=%d tags: %w", row.ID, err)
+	}
+
+	user, err := entities.ReconstructUser(entities.UserRecord{
+		ID:          entities.UserID(row.ID),
+		UUID:        parsedUUID,
+		Email:       entities.Email(row.Email),
+		Username:    entities.Username(row.Username),
+		Password:    entities.PasswordHash(row.PasswordHash),
+		FirstName:   entities.FirstName(row.FirstName),
+		LastName:    entities.LastName(row.LastName),
+		Status:      entities.UserStatus(row.Status),
+		Role:        entities.UserRole(row.Role),
+		IsVerified:  row.IsVerified,
+		Metadata:    metadata,
+		Tags:        tags,
+		CreatedAt:   row.CreatedAt,
+		UpdatedAt:   row.UpdatedAt,
+		LastLoginAt: row.LastLoginAt,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("user id=%d: %w", row.ID, err)
+	}
+
+	return user, nil
+}
+
+// UserRowFromDomain converts a domain entity into an engine-neutral row.
+// Metadata is JSON-encoded and tags are kept as a []string.
+func (m *UserMapper) UserRowFromDomain(user *entities.User) (*UserRow, error) {
+	metadata, err := EncodeMetadata(user.Metadata())
+	if err != nil {
+		return nil, fmt.Errorf("user id=%d: %w", user.ID(), err)
+	}
+
+	return &UserRow{
+		ID:           user.ID().Int64(),
+		UUID:         user.UUID().String(),
+		Email:        user.Email().String(),
+		Username:     user.Username().String(),
+		PasswordHash: user.PasswordHash().String(),
+		FirstName:    user.FirstName().String(),
+		LastName:     user.LastName().String(),
+		Status:       user.Status().String(),
+		Role:         user.Role().String(),
+		IsVerified:   user.IsVerified(),
+		Metadata:     metadata,
+		Tags:         slices.Clone(user.Tags()),
+		CreatedAt:    user.CreatedAt(),
+		UpdatedAt:    user.UpdatedAt(),
+		LastLoginAt:  user.LastLoginAt(),
+	}, nil
+}
+
+// EncodeTags encodes tags as a JSON array.
+func EncodeTags(tags []string) (string, error) {
+	if tags == nil {
+		tags = []string{}
+	}
+
+	encoded, err := json.Marshal(tags)
+	if err != nil {
+		return "", fmt.Errorf("encode tags: %w", err)
+	}
+
+	return string(encoded), nil
+}
+
+// DecodeTags decodes tags stored as a native array or as a JSON array
+// in a string or []byte.
+func DecodeTags(value any) ([]string, error) {
+	if tags, ok := value.([]string); ok {
+		return slices.Clone(tags), nil
+	}
+
+	raw, err := jsonBytes(value)
+	if err != nil || raw == nil {
+		return []string{}, err
+	}
+
+	tags := make([]string, 0)
+
+	err = json.Unmarshal(raw, &tags)
+	if err != nil {
+		return nil, fmt.Errorf("decode tags: %w", err)
+	}
+
+	return tags, nil
+}
+
+// EncodeMetadata encodes user metadata as a JSON object.
+func EncodeMetadata(metadata entities.UserMetadata) (string, error) {
+	if metadata == nil {
+		metadata = entities.NewUserMetadata()
+	}
+
+	encoded, err := json.Marshal(metadata)
+	if err != nil {
+		return "", fmt.Errorf("encode metadata: %w", err)
+	}
+
+	return string(encoded), nil
+}
+
+// DecodeMetadata decodes a JSON object of metadata stored as string or []byte.
+func DecodeMetadata(value any) (entities.UserMetadata, error) {
+	raw, err := jsonBytes(value)
+	if err != nil || raw == nil {
+		return entities.NewUserMetadata(), err
+	}
+
+	metadata := entities.NewUserMetadata()
+
+	err = json.Unmarshal(raw, &metadata)
+	if err != nil {
+		return nil, fmt.Errorf("decode metadata: %w", err)
+	}
+
+	return metadata, nil
+}
+
+// DecodeNullableTime decodes a nullable timestamp returned by a driver.
+func DecodeNullableTime(value any) (*time.Time, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case time.Time:
+		return &v, nil
+	case *time.Time:
+		return v, nil
+	case []byte:
+		return DecodeNullableTime(string(v))
+	case string:
+		if v == "" {
+			return nil, nil
+		}
+
+		for _, layout := range []string{time.RFC3339Nano, sqliteTimeLayout} {
+			parsed, err := time.Parse(layout, v)
+			if err == nil {
+				return &parsed, nil
+			}
+		}
+
+		return nil, fmt.Errorf("invalid time value=%q: %w", v, ErrUnsupportedModel)
+	default:
+		return nil, fmt.Errorf("time value type=%T: %w", value, ErrUnsupportedModel)
+	}
+}
+
+// jsonBytes returns the raw JSON stored in a text column, or nil for NULL.
+func jsonBytes(value any) ([]byte, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		if v == "" {
+			return nil, nil
+		}
+
+		return []byte(v), nil
+	case []byte:
+		if len(v) == 0 {
+			return nil, nil
+		}
+
+		return v, nil
+	case json.RawMessage:
+		return jsonBytes([]byte(v))
+	default:
+		return nil, fmt.Errorf("json value type=%T: %w", value, ErrUnsupportedModel)
+	}
+}
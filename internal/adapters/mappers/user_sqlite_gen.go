@@ -0,0 +1,92 @@
+// Code generated by mappergen from ../../db/sqlite/models.go. DO NOT EDIT.
+
+//go:build sqlite
+
+package mappers
+
+import (
+	"database/sql"
+	"fmt"
+
+	sqlitedb "github.com/LarsArtmann/template-sqlc/internal/db/sqlite"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+)
+
+// DomainUserFromSQLite converts a SQLite users row into a domain entity.
+func (m *UserMapper) DomainUserFromSQLite(model *sqlitedb.Users) (*entities.User, error) {
+	row, err := SQLiteUserRow(model)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.DomainUserFromRow(row)
+}
+
+// SQLiteUserFromDomain converts a domain entity into a SQLite users row.
+func (m *UserMapper) SQLiteUserFromDomain(user *entities.User) (*sqlitedb.Users, error) {
+	row, err := m.UserRowFromDomain(user)
+	if err != nil {
+		return nil, err
+	}
+
+	return SQLiteUserModel(row)
+}
+
+// SQLiteUserRow copies a SQLite users row into a UserRow.
+func SQLiteUserRow(model *sqlitedb.Users) (*UserRow, error) {
+	row := &UserRow{
+		ID:           model.ID,
+		UUID:         model.UUID,
+		Email:        model.Email,
+		Username:     model.Username,
+		PasswordHash: model.PasswordHash,
+		FirstName:    model.FirstName,
+		LastName:     model.LastName,
+		CreatedAt:    model.CreatedAt,
+		UpdatedAt:    model.UpdatedAt,
+		IsVerified:   model.IsVerified.Bool,
+		Metadata:     model.ProfileMetadata,
+		Status:       model.Status,
+		Role:         model.Role,
+		Tags:         model.Tags,
+	}
+
+	var err error
+
+	row.LastLoginAt, err = nullTime.DBToDomain(model.LastLoginAt)
+	if err != nil {
+		return nil, fmt.Errorf("user id=%d LastLoginAt: %w", row.ID, err)
+	}
+
+	return row, nil
+}
+
+// SQLiteUserModel copies a UserRow into a SQLite users row.
+// IsActive, DeletedAt and UsernameCanonical have no UserRow field and are left zero.
+func SQLiteUserModel(row *UserRow) (*sqlitedb.Users, error) {
+	model := &sqlitedb.Users{
+		ID:              row.ID,
+		UUID:            row.UUID,
+		Email:           row.Email,
+		Username:        row.Username,
+		PasswordHash:    row.PasswordHash,
+		FirstName:       row.FirstName,
+		LastName:        row.LastName,
+		CreatedAt:       row.CreatedAt,
+		UpdatedAt:       row.UpdatedAt,
+		LastLoginAt:     nullTime.DomainToDB(row.LastLoginAt),
+		IsVerified:      sql.NullBool{Bool: row.IsVerified, Valid: true},
+		ProfileMetadata: row.Metadata,
+		Status:          row.Status,
+		Role:            row.Role,
+	}
+
+	var err error
+
+	model.Tags, err = TagsJSON[string](row.Tags)
+	if err != nil {
+		return nil, fmt.Errorf("user id=%d Tags: %w", row.ID, err)
+	}
+
+	return model, nil
+}
@@ -0,0 +1,89 @@
+package mappers
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+)
+
+// EncodeUserEventData encodes the data of a user stream event as a JSON
+// object.
+func EncodeUserEventData(data entities.UserEventData) (string, error) {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return "", fmt.Errorf("encode user event data: %w", err)
+	}
+
+	return string(encoded), nil
+}
+
+// DomainUserStreamEvent converts the columns of a stored user event into a
+// domain event. data is the JSON object in a string or []byte.
+func DomainUserStreamEvent(
+	userID entities.UserID,
+	version int64,
+	eventType string,
+	data any,
+	occurredAt time.Time,
+) (*entities.UserStreamEvent, error) {
+	event := &entities.UserStreamEvent{
+		UserID:     userID,
+		Version:    version,
+		Type:       entities.UserEventType(eventType),
+		OccurredAt: occurredAt,
+	}
+
+	raw, err := jsonBytes(data)
+	if err != nil || raw == nil {
+		return event, err
+	}
+
+	err = json.Unmarshal(raw, &event.Data)
+	if err != nil {
+		return nil, fmt.Errorf("decode user event user=%v version=%v: %w", userID, version, err)
+	}
+
+	return event, nil
+}
+
+// EncodeUserSnapshotState encodes the state of a user snapshot as a JSON
+// object.
+func EncodeUserSnapshotState(state entities.UserRecord) (string, error) {
+	encoded, err := json.Marshal(state)
+	if err != nil {
+		return "", fmt.Errorf("encode user snapshot: %w", err)
+	}
+
+	return string(encoded), nil
+}
+
+// DomainUserStreamSnapshot converts the columns of a stored user snapshot
+// into a domain snapshot. state is the JSON object in a string or []byte.
+func DomainUserStreamSnapshot(
+	userID entities.UserID,
+	version int64,
+	deleted bool,
+	state any,
+	createdAt time.Time,
+) (*entities.UserStreamSnapshot, error) {
+	raw, err := jsonBytes(state)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &entities.UserStreamSnapshot{
+		UserID:    userID,
+		Version:   version,
+		Deleted:   deleted,
+		CreatedAt: createdAt,
+	}
+
+	err = json.Unmarshal(raw, &snapshot.State)
+	if err != nil {
+		return nil, fmt.Errorf("decode user snapshot user=%v version=%v: %w", userID, version, err)
+	}
+
+	return snapshot, nil
+}
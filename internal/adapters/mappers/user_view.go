@@ -0,0 +1,54 @@
+package mappers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+)
+
+// UserViewRow holds the columns of a stored user view. Tags are a native
+// array or a JSON array in a string or []byte.
+type UserViewRow struct {
+	UserID          entities.UserID
+	UUID            string
+	Email           string
+	Username        string
+	FullName        string
+	Status          string
+	Role            string
+	Tags            any
+	IsVerified      bool
+	CreatedAt       time.Time
+	SourceUpdatedAt time.Time
+	ProjectedAt     time.Time
+}
+
+// DomainUserView converts the columns of a stored user view into a domain
+// view.
+func DomainUserView(row UserViewRow) (*entities.UserView, error) {
+	id, err := ParseUUID(entities.UuID(row.UUID))
+	if err != nil {
+		return nil, fmt.Errorf("user view user=%v: %w", row.UserID, err)
+	}
+
+	tags, err := DecodeTags(row.Tags)
+	if err != nil {
+		return nil, fmt.Errorf("user view user=%v: %w", row.UserID, err)
+	}
+
+	return &entities.UserView{
+		UserID:          row.UserID,
+		UUID:            id,
+		Email:           entities.Email(row.Email),
+		Username:        entities.Username(row.Username),
+		FullName:        row.FullName,
+		Status:          entities.UserStatus(row.Status),
+		Role:            entities.UserRole(row.Role),
+		Tags:            tags,
+		IsVerified:      row.IsVerified,
+		CreatedAt:       row.CreatedAt,
+		SourceUpdatedAt: row.SourceUpdatedAt,
+		ProjectedAt:     row.ProjectedAt,
+	}, nil
+}
@@ -0,0 +1,72 @@
+package mappers
+
+import (
+	"fmt"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/converters"
+	"github.com/google/uuid"
+)
+
+// Conversions between UserRow fields and the column types of the sqlc models,
+// used by the code mappergen generates.
+
+// Typed converters for nullable timestamp columns.
+var (
+	nullTime    = converters.NewNullTimeConverter()
+	timestamptz = converters.NewTimestamptzConverter()
+)
+
+// BoolPtr returns a pointer to a copy of b.
+func BoolPtr(b bool) *bool {
+	return &b
+}
+
+// UUIDString formats a UUID stored in binary form.
+func UUIDString(b []byte) (string, error) {
+	parsed, err := uuid.FromBytes(b)
+	if err != nil {
+		return "", fmt.Errorf("uuid bytes: %w", err)
+	}
+
+	return parsed.String(), nil
+}
+
+// UUIDBytes parses a UUID into its binary form.
+func UUIDBytes(s string) ([]byte, error) {
+	parsed, err := uuid.Parse(s)
+	if err != nil {
+		return nil, fmt.Errorf("uuid=%q: %w", s, err)
+	}
+
+	return parsed[:], nil
+}
+
+// JSONText returns JSON held as a string or []byte in the column type T.
+func JSONText[T ~string | ~[]byte](value any) (T, error) {
+	var text T
+
+	raw, err := jsonBytes(value)
+	if err != nil {
+		return text, err
+	}
+
+	return T(raw), nil
+}
+
+// TagsJSON encodes tags held as a []string or as JSON into a JSON array of
+// the column type T.
+func TagsJSON[T ~string | ~[]byte](value any) (T, error) {
+	var text T
+
+	tags, err := DecodeTags(value)
+	if err != nil {
+		return text, err
+	}
+
+	encoded, err := EncodeTags(tags)
+	if err != nil {
+		return text, err
+	}
+
+	return T(encoded), nil
+}
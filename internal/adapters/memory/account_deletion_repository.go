@@ -0,0 +1,97 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// AccountDeletionRepository implements AccountDeletionRepository in memory.
+type AccountDeletionRepository struct {
+	mu        sync.Mutex
+	deletions map[entities.UserID]entities.AccountDeletion
+}
+
+// NewAccountDeletionRepository creates an empty in-memory account deletion store.
+func NewAccountDeletionRepository() *AccountDeletionRepository {
+	return &AccountDeletionRepository{deletions: make(map[entities.UserID]entities.AccountDeletion)}
+}
+
+// Create records a deletion unless one is pending for the user.
+func (r *AccountDeletionRepository) Create(_ context.Context, deletion *entities.AccountDeletion) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, ok := r.deletions[deletion.UserID]
+	if ok {
+		return fmt.Errorf("user=%v: %w", deletion.UserID, entities.ErrAccountDeletionPending)
+	}
+
+	r.deletions[deletion.UserID] = *deletion
+
+	return nil
+}
+
+// GetByUser returns the pending deletion of a user.
+func (r *AccountDeletionRepository) GetByUser(
+	_ context.Context,
+	userID entities.UserID,
+) (*entities.AccountDeletion, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	deletion, ok := r.deletions[userID]
+	if !ok {
+		return nil, fmt.Errorf("user=%v: %w", userID, entities.ErrAccountDeletionNotFound)
+	}
+
+	return &deletion, nil
+}
+
+// Delete removes the pending deletion of a user.
+func (r *AccountDeletionRepository) Delete(_ context.Context, userID entities.UserID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, ok := r.deletions[userID]
+	if !ok {
+		return fmt.Errorf("user=%v: %w", userID, entities.ErrAccountDeletionNotFound)
+	}
+
+	delete(r.deletions, userID)
+
+	return nil
+}
+
+// ListDue returns up to limit deletions scheduled for now or earlier, the
+// earliest scheduled first.
+func (r *AccountDeletionRepository) ListDue(
+	_ context.Context,
+	now time.Time,
+	limit int,
+) ([]*entities.AccountDeletion, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	due := []*entities.AccountDeletion{}
+
+	for _, deletion := range r.deletions {
+		if deletion.IsDue(now) {
+			due = append(due, &deletion)
+		}
+	}
+
+	slices.SortFunc(due, func(a, b *entities.AccountDeletion) int {
+		return cmp.Or(a.ScheduledFor.Compare(b.ScheduledFor), cmp.Compare(a.UserID, b.UserID))
+	})
+
+	return due[:min(limit, len(due))], nil
+}
+
+var _ repositories.AccountDeletionRepository = (*AccountDeletionRepository)(nil)
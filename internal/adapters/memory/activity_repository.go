@@ -0,0 +1,113 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// ActivityRepository implements ActivityRepository in memory.
+type ActivityRepository struct {
+	mu      sync.Mutex
+	nextID  entities.ActivityID
+	entries []entities.ActivityEntry
+}
+
+// NewActivityRepository creates an empty in-memory activity store.
+func NewActivityRepository() *ActivityRepository {
+	return &ActivityRepository{}
+}
+
+// Record stores entry and assigns its ID, unless the event of entry is
+// already recorded for the user.
+func (r *ActivityRepository) Record(_ context.Context, entry *entities.ActivityEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if slices.ContainsFunc(r.entries, func(e entities.ActivityEntry) bool {
+		return e.UserID == entry.UserID && e.EventID == entry.EventID
+	}) {
+		return nil
+	}
+
+	r.nextID++
+	entry.ID = r.nextID
+	r.entries = append(r.entries, *entry)
+
+	return nil
+}
+
+// ListPage returns up to limit entries of a user after cursor, newest first.
+func (r *ActivityRepository) ListPage(
+	_ context.Context,
+	userID entities.UserID,
+	cursor string,
+	limit int,
+) (*entities.ActivityPage, error) {
+	after, err := adapters.ParseActivityPageRequest(cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	bound := after.Bound()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entries := []*entities.ActivityEntry{}
+
+	for _, entry := range r.entries {
+		if entry.UserID != userID {
+			continue
+		}
+
+		if entry.OccurredAt.Before(bound.OccurredAt) ||
+			(entry.OccurredAt.Equal(bound.OccurredAt) && entry.ID < bound.ID) {
+			entries = append(entries, &entry)
+		}
+	}
+
+	slices.SortFunc(entries, func(a, b *entities.ActivityEntry) int {
+		return cmp.Or(b.OccurredAt.Compare(a.OccurredAt), cmp.Compare(b.ID, a.ID))
+	})
+
+	if len(entries) > limit+1 {
+		entries = entries[:limit+1]
+	}
+
+	return entities.NewActivityPage(entries, limit), nil
+}
+
+// DeleteOlderThan removes the entries that occurred before cutoff.
+func (r *ActivityRepository) DeleteOlderThan(_ context.Context, cutoff time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	before := len(r.entries)
+	r.entries = slices.DeleteFunc(r.entries, func(e entities.ActivityEntry) bool {
+		return e.OccurredAt.Before(cutoff)
+	})
+
+	return int64(before - len(r.entries)), nil
+}
+
+// DeleteByUser removes the entries of a user.
+func (r *ActivityRepository) DeleteByUser(_ context.Context, userID entities.UserID) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	before := len(r.entries)
+	r.entries = slices.DeleteFunc(r.entries, func(e entities.ActivityEntry) bool {
+		return e.UserID == userID
+	})
+
+	return int64(before - len(r.entries)), nil
+}
+
+var _ repositories.ActivityRepository = (*ActivityRepository)(nil)
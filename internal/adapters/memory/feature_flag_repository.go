@@ -0,0 +1,51 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// FeatureFlagRepository implements FeatureFlagRepository in memory.
+type FeatureFlagRepository struct {
+	mu    sync.RWMutex
+	flags map[string]entities.FeatureFlag
+}
+
+// NewFeatureFlagRepository creates an empty in-memory feature flag store.
+func NewFeatureFlagRepository() *FeatureFlagRepository {
+	return &FeatureFlagRepository{
+		flags: make(map[string]entities.FeatureFlag),
+	}
+}
+
+// List returns the stored flags ordered by name.
+func (r *FeatureFlagRepository) List(_ context.Context) ([]*entities.FeatureFlag, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	flags := make([]*entities.FeatureFlag, 0, len(r.flags))
+	for _, flag := range r.flags {
+		flags = append(flags, &flag)
+	}
+
+	slices.SortFunc(flags, func(a, b *entities.FeatureFlag) int { return cmp.Compare(a.Name, b.Name) })
+
+	return flags, nil
+}
+
+// Set stores the flag, replacing the one of the same name.
+func (r *FeatureFlagRepository) Set(_ context.Context, flag *entities.FeatureFlag) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.flags[flag.Name] = *flag
+
+	return nil
+}
+
+var _ repositories.FeatureFlagRepository = (*FeatureFlagRepository)(nil)
@@ -0,0 +1,120 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// IdempotencyRepository implements IdempotencyRepository in memory.
+type IdempotencyRepository struct {
+	mu      sync.Mutex
+	records map[entities.IdempotencyKey]entities.IdempotencyRecord
+}
+
+// NewIdempotencyRepository creates an empty in-memory idempotency store.
+func NewIdempotencyRepository() *IdempotencyRepository {
+	return &IdempotencyRepository{
+		records: make(map[entities.IdempotencyKey]entities.IdempotencyRecord),
+	}
+}
+
+// copyIdempotencyRecord returns a copy of record that shares no memory with it.
+func copyIdempotencyRecord(record entities.IdempotencyRecord) *entities.IdempotencyRecord {
+	record.Response = slices.Clone(record.Response)
+
+	if record.CompletedAt != nil {
+		completedAt := *record.CompletedAt
+		record.CompletedAt = &completedAt
+	}
+
+	return &record
+}
+
+// Reserve stores a pending record unless one with its key is stored.
+func (r *IdempotencyRepository) Reserve(_ context.Context, record *entities.IdempotencyRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.records[record.Key]; ok {
+		return fmt.Errorf("key=%v: %w", record.Key, entities.ErrIdempotencyKeyExists)
+	}
+
+	r.records[record.Key] = *copyIdempotencyRecord(*record)
+
+	return nil
+}
+
+// Get retrieves the record of key.
+func (r *IdempotencyRepository) Get(
+	_ context.Context,
+	key entities.IdempotencyKey,
+) (*entities.IdempotencyRecord, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	record, ok := r.records[key]
+	if !ok {
+		return nil, fmt.Errorf("key=%v: %w", key, entities.ErrIdempotencyKeyNotFound)
+	}
+
+	return copyIdempotencyRecord(record), nil
+}
+
+// Complete stores the response and expiry of a pending record.
+func (r *IdempotencyRepository) Complete(_ context.Context, record *entities.IdempotencyRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.records[record.Key]
+	if !ok || stored.IsCompleted() {
+		return fmt.Errorf("key=%v: %w", record.Key, entities.ErrIdempotencyKeyNotFound)
+	}
+
+	stored.Response = record.Response
+	stored.CompletedAt = record.CompletedAt
+	stored.ExpiresAt = record.ExpiresAt
+	r.records[record.Key] = *copyIdempotencyRecord(stored)
+
+	return nil
+}
+
+// Release deletes a pending record.
+func (r *IdempotencyRepository) Release(_ context.Context, key entities.IdempotencyKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.records[key]
+	if !ok || stored.IsCompleted() {
+		return fmt.Errorf("key=%v: %w", key, entities.ErrIdempotencyKeyNotFound)
+	}
+
+	delete(r.records, key)
+
+	return nil
+}
+
+// DeleteExpired removes the records expired at now.
+func (r *IdempotencyRepository) DeleteExpired(_ context.Context, now time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var deleted int64
+
+	for key, record := range r.records {
+		if record.IsExpired(now) {
+			delete(r.records, key)
+
+			deleted++
+		}
+	}
+
+	return deleted, nil
+}
+
+var _ repositories.IdempotencyRepository = (*IdempotencyRepository)(nil)
@@ -0,0 +1,113 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// IdentityChangeRepository implements IdentityChangeRepository in memory.
+type IdentityChangeRepository struct {
+	mu      sync.Mutex
+	nextID  entities.IdentityChangeID
+	changes map[entities.IdentityChangeID]entities.IdentityChange
+}
+
+// NewIdentityChangeRepository creates an empty in-memory identity change store.
+func NewIdentityChangeRepository() *IdentityChangeRepository {
+	return &IdentityChangeRepository{
+		changes: make(map[entities.IdentityChangeID]entities.IdentityChange),
+	}
+}
+
+// Create records a change and assigns its ID.
+func (r *IdentityChangeRepository) Create(_ context.Context, change *entities.IdentityChange) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nextID++
+	change.ID = r.nextID
+	r.changes[change.ID] = *change
+
+	return nil
+}
+
+// GetByToken returns the change with token.
+func (r *IdentityChangeRepository) GetByToken(
+	_ context.Context,
+	token entities.IdentityChangeToken,
+) (*entities.IdentityChange, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, change := range r.changes {
+		if change.Token == token {
+			return &change, nil
+		}
+	}
+
+	return nil, fmt.Errorf("token=%v: %w", token, entities.ErrIdentityChangeNotFound)
+}
+
+// Update stores the status, token and expiry of a change.
+func (r *IdentityChangeRepository) Update(_ context.Context, change *entities.IdentityChange) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.changes[change.ID]
+	if !ok {
+		return fmt.Errorf("identity change id=%d: %w", change.ID, entities.ErrIdentityChangeNotFound)
+	}
+
+	stored.Status = change.Status
+	stored.Token = change.Token
+	stored.ExpiresAt = change.ExpiresAt
+	stored.UpdatedAt = change.UpdatedAt
+	r.changes[change.ID] = stored
+
+	return nil
+}
+
+// ListByUser returns the changes of a user, newest first.
+func (r *IdentityChangeRepository) ListByUser(
+	_ context.Context,
+	userID entities.UserID,
+) ([]*entities.IdentityChange, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	changes := []*entities.IdentityChange{}
+
+	for _, change := range r.changes {
+		if change.UserID == userID {
+			changes = append(changes, &change)
+		}
+	}
+
+	slices.SortFunc(changes, func(a, b *entities.IdentityChange) int {
+		return cmp.Or(b.CreatedAt.Compare(a.CreatedAt), cmp.Compare(b.ID, a.ID))
+	})
+
+	return changes, nil
+}
+
+// DeleteByUser removes the changes of a user.
+func (r *IdentityChangeRepository) DeleteByUser(_ context.Context, userID entities.UserID) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	before := len(r.changes)
+	maps.DeleteFunc(r.changes, func(_ entities.IdentityChangeID, change entities.IdentityChange) bool {
+		return change.UserID == userID
+	})
+
+	return int64(before - len(r.changes)), nil
+}
+
+var _ repositories.IdentityChangeRepository = (*IdentityChangeRepository)(nil)
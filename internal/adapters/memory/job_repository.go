@@ -0,0 +1,157 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// JobRepository implements JobRepository in memory.
+type JobRepository struct {
+	mu     sync.Mutex
+	jobs   map[entities.JobID]entities.Job
+	nextID entities.JobID
+}
+
+// NewJobRepository creates an empty in-memory job queue.
+func NewJobRepository() *JobRepository {
+	return &JobRepository{
+		jobs:   make(map[entities.JobID]entities.Job),
+		nextID: 1,
+	}
+}
+
+// copyJob returns a copy of job that shares no memory with it.
+func copyJob(job entities.Job) *entities.Job {
+	job.Payload = slices.Clone(job.Payload)
+
+	if job.LockedUntil != nil {
+		lockedUntil := *job.LockedUntil
+		job.LockedUntil = &lockedUntil
+	}
+
+	return &job
+}
+
+// Enqueue stores a pending job and assigns its ID.
+func (r *JobRepository) Enqueue(_ context.Context, job *entities.Job) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	job.ID = r.nextID
+	r.nextID++
+	r.jobs[job.ID] = *copyJob(*job)
+
+	return nil
+}
+
+// Get retrieves a job by ID.
+func (r *JobRepository) Get(_ context.Context, id entities.JobID) (*entities.Job, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	job, ok := r.jobs[id]
+	if !ok {
+		return nil, fmt.Errorf("job id=%d: %w", id, entities.ErrJobNotFound)
+	}
+
+	return copyJob(job), nil
+}
+
+// Claim leases up to limit due jobs, oldest RunAt first.
+func (r *JobRepository) Claim(
+	_ context.Context,
+	now time.Time,
+	lease time.Duration,
+	limit int,
+) ([]*entities.Job, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now = now.UTC()
+	due := make([]*entities.Job, 0)
+
+	for _, job := range r.jobs {
+		if job.IsDue(now) {
+			due = append(due, copyJob(job))
+		}
+	}
+
+	slices.SortFunc(due, func(a, b *entities.Job) int {
+		return cmp.Or(a.RunAt.Compare(b.RunAt), cmp.Compare(a.ID, b.ID))
+	})
+
+	due = due[:min(limit, len(due))]
+
+	for _, job := range due {
+		job.Claim(now, now.Add(lease))
+		r.jobs[job.ID] = *copyJob(*job)
+	}
+
+	return due, nil
+}
+
+// finish applies transition to the stored job if the claim of job still
+// holds, and then to job itself.
+func (r *JobRepository) finish(job *entities.Job, transition func(*entities.Job)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.jobs[job.ID]
+	if !ok || stored.Status != entities.JobStatusRunning || stored.Attempts != job.Attempts {
+		return fmt.Errorf("job id=%d: %w", job.ID, entities.ErrJobLeaseLost)
+	}
+
+	transition(&stored)
+	r.jobs[job.ID] = stored
+	transition(job)
+
+	return nil
+}
+
+// Complete marks a claimed job done.
+func (r *JobRepository) Complete(_ context.Context, job *entities.Job) error {
+	now := time.Now().UTC()
+
+	return r.finish(job, func(j *entities.Job) { j.Complete(now) })
+}
+
+// Retry returns a claimed job to the queue, due again at runAt.
+func (r *JobRepository) Retry(_ context.Context, job *entities.Job, runAt time.Time, lastError string) error {
+	now := time.Now().UTC()
+
+	return r.finish(job, func(j *entities.Job) { j.Reschedule(runAt.UTC(), lastError, now) })
+}
+
+// Fail marks a claimed job failed.
+func (r *JobRepository) Fail(_ context.Context, job *entities.Job, lastError string) error {
+	now := time.Now().UTC()
+
+	return r.finish(job, func(j *entities.Job) { j.Fail(lastError, now) })
+}
+
+// DeleteDone removes the done jobs finished before cutoff.
+func (r *JobRepository) DeleteDone(_ context.Context, cutoff time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var deleted int64
+
+	for id, job := range r.jobs {
+		if job.Status == entities.JobStatusDone && job.UpdatedAt.Before(cutoff) {
+			delete(r.jobs, id)
+
+			deleted++
+		}
+	}
+
+	return deleted, nil
+}
+
+var _ repositories.JobRepository = (*JobRepository)(nil)
@@ -0,0 +1,111 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/validation"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// LoginHistoryRepository implements LoginHistoryRepository in memory.
+type LoginHistoryRepository struct {
+	mu       sync.Mutex
+	nextID   entities.LoginAttemptID
+	attempts []entities.LoginAttempt
+}
+
+// NewLoginHistoryRepository creates an empty in-memory login history.
+func NewLoginHistoryRepository() *LoginHistoryRepository {
+	return &LoginHistoryRepository{}
+}
+
+// Record stores attempt and assigns its ID.
+func (r *LoginHistoryRepository) Record(_ context.Context, attempt *entities.LoginAttempt) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nextID++
+	attempt.ID = r.nextID
+	r.attempts = append(r.attempts, *attempt)
+
+	return nil
+}
+
+// ListByUser returns the last limit attempts of a user, newest first.
+func (r *LoginHistoryRepository) ListByUser(
+	_ context.Context,
+	userID entities.UserID,
+	limit int,
+) ([]*entities.LoginAttempt, error) {
+	err := validation.ValidatePagination(limit, 0)
+	if err != nil {
+		return nil, fmt.Errorf("limit=%v: %w", limit, err)
+	}
+
+	return r.list(limit, func(a *entities.LoginAttempt) bool {
+		return a.UserID == userID
+	}), nil
+}
+
+// ListFailuresByIP returns the last limit failed attempts from ipAddress
+// since the given time, newest first.
+func (r *LoginHistoryRepository) ListFailuresByIP(
+	_ context.Context,
+	ipAddress string,
+	since time.Time,
+	limit int,
+) ([]*entities.LoginAttempt, error) {
+	err := validation.ValidatePagination(limit, 0)
+	if err != nil {
+		return nil, fmt.Errorf("limit=%v: %w", limit, err)
+	}
+
+	return r.list(limit, func(a *entities.LoginAttempt) bool {
+		return a.IPAddress == ipAddress && !a.Succeeded() && !a.CreatedAt.Before(since)
+	}), nil
+}
+
+// DeleteByUser removes the attempts of a user.
+func (r *LoginHistoryRepository) DeleteByUser(_ context.Context, userID entities.UserID) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	before := len(r.attempts)
+	r.attempts = slices.DeleteFunc(r.attempts, func(a entities.LoginAttempt) bool {
+		return a.UserID == userID
+	})
+
+	return int64(before - len(r.attempts)), nil
+}
+
+// list returns up to limit attempts matching keep, newest first.
+func (r *LoginHistoryRepository) list(limit int, keep func(*entities.LoginAttempt) bool) []*entities.LoginAttempt {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	attempts := []*entities.LoginAttempt{}
+
+	for _, attempt := range r.attempts {
+		if keep(&attempt) {
+			attempts = append(attempts, &attempt)
+		}
+	}
+
+	slices.SortFunc(attempts, func(a, b *entities.LoginAttempt) int {
+		return cmp.Or(b.CreatedAt.Compare(a.CreatedAt), cmp.Compare(b.ID, a.ID))
+	})
+
+	if len(attempts) > limit {
+		attempts = attempts[:limit]
+	}
+
+	return attempts
+}
+
+var _ repositories.LoginHistoryRepository = (*LoginHistoryRepository)(nil)
@@ -0,0 +1,260 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// ModerationRepository implements ModerationRepository in memory.
+type ModerationRepository struct {
+	mu           sync.Mutex
+	nextActionID entities.ModerationActionID
+	nextAppealID entities.AppealID
+	actions      map[entities.ModerationActionID]entities.ModerationAction
+	appeals      map[entities.AppealID]entities.Appeal
+}
+
+// NewModerationRepository creates an empty in-memory moderation store.
+func NewModerationRepository() *ModerationRepository {
+	return &ModerationRepository{
+		actions: make(map[entities.ModerationActionID]entities.ModerationAction),
+		appeals: make(map[entities.AppealID]entities.Appeal),
+	}
+}
+
+// CreateAction records an action and assigns its ID.
+func (r *ModerationRepository) CreateAction(_ context.Context, action *entities.ModerationAction) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nextActionID++
+	action.ID = r.nextActionID
+	r.actions[action.ID] = *action
+
+	return nil
+}
+
+// GetAction returns the action with id.
+func (r *ModerationRepository) GetAction(
+	_ context.Context,
+	id entities.ModerationActionID,
+) (*entities.ModerationAction, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	action, ok := r.actions[id]
+	if !ok {
+		return nil, fmt.Errorf("moderation action id=%d: %w", id, entities.ErrModerationActionNotFound)
+	}
+
+	return &action, nil
+}
+
+// LiftAction stores when and by whom an action was lifted.
+func (r *ModerationRepository) LiftAction(_ context.Context, action *entities.ModerationAction) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.actions[action.ID]
+	if !ok || !stored.IsActive() {
+		return fmt.Errorf("moderation action id=%d: %w", action.ID, entities.ErrModerationActionNotFound)
+	}
+
+	stored.LiftedAt = action.LiftedAt
+	stored.LiftedBy = action.LiftedBy
+	r.actions[action.ID] = stored
+
+	return nil
+}
+
+// GetActiveSuspension returns the newest suspension of a user that was not lifted.
+func (r *ModerationRepository) GetActiveSuspension(
+	_ context.Context,
+	userID entities.UserID,
+) (*entities.ModerationAction, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	suspensions := r.filterActions(func(action *entities.ModerationAction) bool {
+		return action.UserID == userID && action.Kind == entities.ModerationSuspension && action.IsActive()
+	})
+	if len(suspensions) == 0 {
+		return nil, fmt.Errorf("user=%v: %w", userID, entities.ErrNotSuspended)
+	}
+
+	return suspensions[0], nil
+}
+
+// ListActionsByUser returns the actions against a user, newest first.
+func (r *ModerationRepository) ListActionsByUser(
+	_ context.Context,
+	userID entities.UserID,
+) ([]*entities.ModerationAction, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.filterActions(func(action *entities.ModerationAction) bool {
+		return action.UserID == userID
+	}), nil
+}
+
+// ListLapsedSuspensions returns up to limit suspensions that ended by now
+// but were not lifted yet, the earliest ended first.
+func (r *ModerationRepository) ListLapsedSuspensions(
+	_ context.Context,
+	now time.Time,
+	limit int,
+) ([]*entities.ModerationAction, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	lapsed := r.filterActions(func(action *entities.ModerationAction) bool {
+		return action.Kind == entities.ModerationSuspension && action.IsActive() && action.HasLapsed(now)
+	})
+
+	slices.SortFunc(lapsed, func(a, b *entities.ModerationAction) int {
+		return cmp.Or(a.EndsAt.Compare(*b.EndsAt), cmp.Compare(a.ID, b.ID))
+	})
+
+	return lapsed[:min(limit, len(lapsed))], nil
+}
+
+// filterActions returns copies of the actions that match, newest first.
+func (r *ModerationRepository) filterActions(
+	match func(action *entities.ModerationAction) bool,
+) []*entities.ModerationAction {
+	actions := []*entities.ModerationAction{}
+
+	for _, action := range r.actions {
+		if match(&action) {
+			actions = append(actions, &action)
+		}
+	}
+
+	slices.SortFunc(actions, func(a, b *entities.ModerationAction) int {
+		return cmp.Or(b.CreatedAt.Compare(a.CreatedAt), cmp.Compare(b.ID, a.ID))
+	})
+
+	return actions
+}
+
+// CreateAppeal records an appeal and assigns its ID.
+func (r *ModerationRepository) CreateAppeal(_ context.Context, appeal *entities.Appeal) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.appeals {
+		if existing.ActionID == appeal.ActionID {
+			return fmt.Errorf("moderation action id=%d: %w", appeal.ActionID, entities.ErrAppealExists)
+		}
+	}
+
+	r.nextAppealID++
+	appeal.ID = r.nextAppealID
+	r.appeals[appeal.ID] = *appeal
+
+	return nil
+}
+
+// GetAppeal returns the appeal with id.
+func (r *ModerationRepository) GetAppeal(_ context.Context, id entities.AppealID) (*entities.Appeal, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	appeal, ok := r.appeals[id]
+	if !ok {
+		return nil, fmt.Errorf("appeal id=%d: %w", id, entities.ErrAppealNotFound)
+	}
+
+	return &appeal, nil
+}
+
+// DecideAppeal stores the decision of an appeal.
+func (r *ModerationRepository) DecideAppeal(_ context.Context, appeal *entities.Appeal) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.appeals[appeal.ID]
+	if !ok || stored.Status != entities.AppealPending {
+		return fmt.Errorf("appeal id=%d: %w", appeal.ID, entities.ErrAppealNotFound)
+	}
+
+	stored.Status = appeal.Status
+	stored.ReviewerID = appeal.ReviewerID
+	stored.Response = appeal.Response
+	stored.DecidedAt = appeal.DecidedAt
+	r.appeals[appeal.ID] = stored
+
+	return nil
+}
+
+// ListPendingAppeals returns up to limit undecided appeals, oldest first.
+func (r *ModerationRepository) ListPendingAppeals(_ context.Context, limit int) ([]*entities.Appeal, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	pending := []*entities.Appeal{}
+
+	for _, appeal := range r.appeals {
+		if appeal.Status == entities.AppealPending {
+			pending = append(pending, &appeal)
+		}
+	}
+
+	slices.SortFunc(pending, func(a, b *entities.Appeal) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), cmp.Compare(a.ID, b.ID))
+	})
+
+	return pending[:min(limit, len(pending))], nil
+}
+
+// ListAppealsByUser returns the appeals of a user, newest first.
+func (r *ModerationRepository) ListAppealsByUser(
+	_ context.Context,
+	userID entities.UserID,
+) ([]*entities.Appeal, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	appeals := []*entities.Appeal{}
+
+	for _, appeal := range r.appeals {
+		if appeal.UserID == userID {
+			appeals = append(appeals, &appeal)
+		}
+	}
+
+	slices.SortFunc(appeals, func(a, b *entities.Appeal) int {
+		return cmp.Or(b.CreatedAt.Compare(a.CreatedAt), cmp.Compare(b.ID, a.ID))
+	})
+
+	return appeals, nil
+}
+
+// DeleteByUser removes the actions against a user and their appeals.
+func (r *ModerationRepository) DeleteByUser(_ context.Context, userID entities.UserID) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	before := len(r.actions)
+	maps.DeleteFunc(r.actions, func(_ entities.ModerationActionID, action entities.ModerationAction) bool {
+		return action.UserID == userID
+	})
+	maps.DeleteFunc(r.appeals, func(_ entities.AppealID, appeal entities.Appeal) bool {
+		_, ok := r.actions[appeal.ActionID]
+
+		return !ok
+	})
+
+	return int64(before - len(r.actions)), nil
+}
+
+var _ repositories.ModerationRepository = (*ModerationRepository)(nil)
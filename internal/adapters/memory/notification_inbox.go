@@ -0,0 +1,71 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/services"
+)
+
+// DefaultInboxSize is how many notifications an inbox keeps per user.
+const DefaultInboxSize = 50
+
+// NotificationInbox is the in-app channel of a services.NotificationService.
+// It keeps the latest notifications of each user for the application to
+// show; they are lost on restart.
+type NotificationInbox struct {
+	mu     sync.Mutex
+	size   int
+	byUser map[entities.UserID][]services.Notification
+}
+
+var _ services.NotificationSender = (*NotificationInbox)(nil)
+
+// NewNotificationInbox creates an inbox keeping size notifications per
+// user, or DefaultInboxSize if size is not positive.
+func NewNotificationInbox(size int) *NotificationInbox {
+	if size <= 0 {
+		size = DefaultInboxSize
+	}
+
+	return &NotificationInbox{
+		size:   size,
+		byUser: make(map[entities.UserID][]services.Notification),
+	}
+}
+
+// Notify adds notification to the inbox of its user, dropping the oldest
+// one when full. Redeliveries of a notification are ignored.
+func (i *NotificationInbox) Notify(_ context.Context, notification services.Notification) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	userID := notification.User.ID()
+	inbox := i.byUser[userID]
+
+	if slices.ContainsFunc(inbox, func(n services.Notification) bool { return n.ID == notification.ID }) {
+		return nil
+	}
+
+	inbox = append(inbox, notification)
+	if len(inbox) > i.size {
+		inbox = slices.Delete(inbox, 0, len(inbox)-i.size)
+	}
+
+	i.byUser[userID] = inbox
+
+	return nil
+}
+
+// List returns the notifications of a user, newest first.
+func (i *NotificationInbox) List(userID entities.UserID) []services.Notification {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	list := slices.Clone(i.byUser[userID])
+	slices.Reverse(list)
+
+	return list
+}
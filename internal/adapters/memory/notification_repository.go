@@ -0,0 +1,93 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// notificationKey identifies a stored notification preference.
+type notificationKey struct {
+	userID  entities.UserID
+	channel entities.NotificationChannel
+	topic   entities.NotificationTopic
+}
+
+// NotificationPreferenceRepository implements NotificationPreferenceRepository in memory.
+type NotificationPreferenceRepository struct {
+	mu    sync.Mutex
+	prefs map[notificationKey]entities.NotificationPreference
+}
+
+// NewNotificationPreferenceRepository creates an empty in-memory notification preference store.
+func NewNotificationPreferenceRepository() *NotificationPreferenceRepository {
+	return &NotificationPreferenceRepository{
+		prefs: make(map[notificationKey]entities.NotificationPreference),
+	}
+}
+
+// ListByUser returns the stored preferences of a user, ordered by channel and topic.
+func (r *NotificationPreferenceRepository) ListByUser(
+	_ context.Context,
+	userID entities.UserID,
+) ([]*entities.NotificationPreference, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	prefs := []*entities.NotificationPreference{}
+
+	for key, pref := range r.prefs {
+		if key.userID == userID {
+			prefs = append(prefs, &pref)
+		}
+	}
+
+	slices.SortFunc(prefs, func(a, b *entities.NotificationPreference) int {
+		return cmp.Or(cmp.Compare(a.Channel, b.Channel), cmp.Compare(a.Topic, b.Topic))
+	})
+
+	return prefs, nil
+}
+
+// Upsert stores a preference, replacing the one of its channel and topic.
+func (r *NotificationPreferenceRepository) Upsert(_ context.Context, pref *entities.NotificationPreference) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.prefs[notificationKey{pref.UserID, pref.Channel, pref.Topic}] = *pref
+
+	return nil
+}
+
+// Delete removes a stored preference, restoring its default.
+func (r *NotificationPreferenceRepository) Delete(
+	_ context.Context,
+	userID entities.UserID,
+	channel entities.NotificationChannel,
+	topic entities.NotificationTopic,
+) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := notificationKey{userID, channel, topic}
+	if _, ok := r.prefs[key]; !ok {
+		return fmt.Errorf(
+			"channel=%v topic=%v user=%v: %w",
+			channel,
+			topic,
+			userID,
+			entities.ErrNotificationPreferenceNotFound,
+		)
+	}
+
+	delete(r.prefs, key)
+
+	return nil
+}
+
+var _ repositories.NotificationPreferenceRepository = (*NotificationPreferenceRepository)(nil)
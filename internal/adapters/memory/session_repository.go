@@ -0,0 +1,269 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// SessionRepository implements SessionRepository in memory.
+type SessionRepository struct {
+	mu       sync.RWMutex
+	sessions map[entities.SessionID]entities.SessionRecord
+	nextID   entities.SessionID
+}
+
+// NewSessionRepository creates an empty in-memory session repository.
+func NewSessionRepository() *SessionRepository {
+	return &SessionRepository{
+		sessions: make(map[entities.SessionID]entities.SessionRecord),
+		nextID:   1,
+	}
+}
+
+// Create stores the session and assigns its ID.
+func (r *SessionRepository) Create(_ context.Context, session *entities.UserSession) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	record := session.Record()
+	record.ID = r.nextID
+
+	r.nextID++
+	r.sessions[record.ID] = record
+
+	session.SetID(record.ID)
+
+	return nil
+}
+
+// find returns the first session matching match; the caller holds a lock.
+func (r *SessionRepository) find(match func(*entities.UserSession) bool) (*entities.UserSession, error) {
+	for _, id := range slices.Sorted(maps.Keys(r.sessions)) {
+		session, err := entities.ReconstructSession(r.sessions[id])
+		if err != nil {
+			return nil, err
+		}
+
+		if match(session) {
+			return session, nil
+		}
+	}
+
+	return nil, entities.ErrSessionNotFound
+}
+
+// GetByToken retrieves a session by its token.
+func (r *SessionRepository) GetByToken(
+	_ context.Context,
+	token entities.SessionToken,
+) (*entities.UserSession, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.find(func(s *entities.UserSession) bool { return s.Token() == token })
+}
+
+// GetByRefreshToken retrieves a session by its current or a rotated refresh token.
+func (r *SessionRepository) GetByRefreshToken(
+	_ context.Context,
+	token entities.RefreshToken,
+) (*entities.UserSession, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.find(func(s *entities.UserSession) bool { return s.HasRefreshToken(token) })
+}
+
+// GetByUserID retrieves the sessions of a user, newest first.
+func (r *SessionRepository) GetByUserID(
+	_ context.Context,
+	userID entities.UserID,
+	activeOnly bool,
+) ([]*entities.UserSession, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	sessions := make([]*entities.UserSession, 0)
+
+	for _, id := range slices.Backward(slices.Sorted(maps.Keys(r.sessions))) {
+		record := r.sessions[id]
+		if record.UserID != userID || (activeOnly && !record.IsActive) {
+			continue
+		}
+
+		session, err := entities.ReconstructSession(record)
+		if err != nil {
+			return nil, err
+		}
+
+		sessions = append(sessions, session)
+	}
+
+	return sessions, nil
+}
+
+// Update persists every mutable field of the session.
+func (r *SessionRepository) Update(_ context.Context, session *entities.UserSession) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.sessions[session.ID()]; !ok {
+		return fmt.Errorf("update session id=%v: %w", session.ID(), entities.ErrSessionNotFound)
+	}
+
+	r.sessions[session.ID()] = session.Record()
+
+	return nil
+}
+
+// UpdateIfCurrent persists every mutable field of the session, provided the
+// stored session is still active with the expected refresh token.
+func (r *SessionRepository) UpdateIfCurrent(
+	_ context.Context,
+	session *entities.UserSession,
+	expected entities.RefreshToken,
+) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.sessions[session.ID()]
+	if !ok || !stored.IsActive || stored.RefreshToken != expected {
+		return fmt.Errorf("update session id=%v: %w", session.ID(), entities.ErrSessionConflict)
+	}
+
+	r.sessions[session.ID()] = session.Record()
+
+	return nil
+}
+
+// Delete removes a session.
+func (r *SessionRepository) Delete(_ context.Context, id entities.SessionID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.sessions[id]; !ok {
+		return fmt.Errorf("delete session id=%v: %w", id, entities.ErrSessionNotFound)
+	}
+
+	delete(r.sessions, id)
+
+	return nil
+}
+
+// DeactivateByToken deactivates the session with the given token.
+func (r *SessionRepository) DeactivateByToken(_ context.Context, token entities.SessionToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, record := range r.sessions {
+		if record.Token == token {
+			record.IsActive = false
+			r.sessions[id] = record
+
+			return nil
+		}
+	}
+
+	return fmt.Errorf("deactivate session token=%v: %w", token, entities.ErrSessionNotFound)
+}
+
+// DeactivateByUserID deactivates every session of a user.
+func (r *SessionRepository) DeactivateByUserID(_ context.Context, userID entities.UserID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, record := range r.sessions {
+		if record.UserID == userID {
+			record.IsActive = false
+			r.sessions[id] = record
+		}
+	}
+
+	return nil
+}
+
+// CleanupExpired removes the expired sessions.
+func (r *SessionRepository) CleanupExpired(_ context.Context) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+
+	var removed int64
+
+	for id, record := range r.sessions {
+		if now.After(record.ExpiresAt) {
+			delete(r.sessions, id)
+
+			removed++
+		}
+	}
+
+	return removed, nil
+}
+
+// GetActiveSessions counts the active, unexpired sessions of a user.
+func (r *SessionRepository) GetActiveSessions(_ context.Context, userID entities.UserID) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	now := time.Now()
+
+	var count int64
+
+	for _, record := range r.sessions {
+		if record.UserID == userID && record.IsActive && !now.After(record.ExpiresAt) {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// GetSessionStats returns aggregate session statistics.
+func (r *SessionRepository) GetSessionStats(_ context.Context) (*entities.SessionStats, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	now := time.Now()
+	stats := &entities.SessionStats{}
+
+	for _, record := range r.sessions {
+		expired := now.After(record.ExpiresAt)
+		age := now.Sub(record.CreatedAt)
+
+		stats.TotalSessions++
+
+		if record.IsActive && !expired {
+			stats.ActiveSessions++
+		}
+
+		if expired {
+			stats.ExpiredSessions++
+		}
+
+		if age <= 24*time.Hour {
+			stats.Sessions24h++
+		}
+
+		if age <= 7*24*time.Hour {
+			stats.Sessions7d++
+		}
+
+		if age <= 30*24*time.Hour {
+			stats.Sessions30d++
+		}
+	}
+
+	return stats, nil
+}
+
+// Ensure SessionRepository implements SessionRepository.
+var _ repositories.SessionRepository = (*SessionRepository)(nil)
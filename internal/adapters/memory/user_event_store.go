@@ -0,0 +1,115 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// UserEventStore implements UserEventStore in memory.
+type UserEventStore struct {
+	mu        sync.Mutex
+	streams   map[entities.UserID][]entities.UserStreamEvent
+	snapshots map[entities.UserID]entities.UserStreamSnapshot
+}
+
+// NewUserEventStore creates an empty in-memory user event store.
+func NewUserEventStore() *UserEventStore {
+	return &UserEventStore{
+		streams:   make(map[entities.UserID][]entities.UserStreamEvent),
+		snapshots: make(map[entities.UserID]entities.UserStreamSnapshot),
+	}
+}
+
+// Append adds events to the stream of userID after expectedVersion and
+// numbers them.
+func (s *UserEventStore) Append(
+	_ context.Context,
+	userID entities.UserID,
+	expectedVersion int64,
+	events []*entities.UserStreamEvent,
+) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stream := s.streams[userID]
+	if int64(len(stream)) != expectedVersion {
+		return fmt.Errorf("user=%v version=%v: %w", userID, expectedVersion, entities.ErrUserStreamConflict)
+	}
+
+	for i, event := range events {
+		event.UserID = userID
+		event.Version = expectedVersion + int64(i) + 1
+		stream = append(stream, *event)
+	}
+
+	s.streams[userID] = stream
+
+	return nil
+}
+
+// Load returns the events of the stream of userID after afterVersion,
+// oldest first.
+func (s *UserEventStore) Load(
+	_ context.Context,
+	userID entities.UserID,
+	afterVersion int64,
+) ([]*entities.UserStreamEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	events := []*entities.UserStreamEvent{}
+
+	for _, event := range s.streams[userID] {
+		if event.Version > afterVersion {
+			events = append(events, &event)
+		}
+	}
+
+	return events, nil
+}
+
+// SaveSnapshot stores snapshot unless a newer one is stored.
+func (s *UserEventStore) SaveSnapshot(_ context.Context, snapshot *entities.UserStreamSnapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	latest, ok := s.snapshots[snapshot.UserID]
+	if !ok || latest.Version < snapshot.Version {
+		s.snapshots[snapshot.UserID] = *snapshot
+	}
+
+	return nil
+}
+
+// LatestSnapshot returns the snapshot of userID with the highest version.
+func (s *UserEventStore) LatestSnapshot(
+	_ context.Context,
+	userID entities.UserID,
+) (*entities.UserStreamSnapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snapshot, ok := s.snapshots[userID]
+	if !ok {
+		return nil, fmt.Errorf("user=%v: %w", userID, entities.ErrUserSnapshotNotFound)
+	}
+
+	return &snapshot, nil
+}
+
+// DeleteStream removes the events and snapshots of userID.
+func (s *UserEventStore) DeleteStream(_ context.Context, userID entities.UserID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.streams, userID)
+	delete(s.snapshots, userID)
+
+	return nil
+}
+
+var _ repositories.UserEventStore = (*UserEventStore)(nil)
@@ -0,0 +1,74 @@
+package memory
+
+import (
+	"context"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// UserPreferenceRepository implements UserPreferenceRepository in memory.
+type UserPreferenceRepository struct {
+	mu    sync.Mutex
+	prefs map[entities.UserID]entities.UserPreferences
+}
+
+// NewUserPreferenceRepository creates an empty in-memory user preference store.
+func NewUserPreferenceRepository() *UserPreferenceRepository {
+	return &UserPreferenceRepository{
+		prefs: make(map[entities.UserID]entities.UserPreferences),
+	}
+}
+
+// Get returns the stored preferences of a user, empty if none were stored.
+func (r *UserPreferenceRepository) Get(_ context.Context, userID entities.UserID) (*entities.UserPreferences, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.copyOf(userID), nil
+}
+
+// Patch stores the Set preferences of patch and removes the Unset ones.
+func (r *UserPreferenceRepository) Patch(
+	_ context.Context,
+	userID entities.UserID,
+	patch entities.PreferencePatch,
+) (*entities.UserPreferences, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := r.copyOf(userID)
+	maps.Copy(stored.Values, patch.Set)
+
+	for _, key := range patch.Unset {
+		delete(stored.Values, key)
+	}
+
+	stored.UpdatedAt = time.Now().UTC()
+	r.prefs[userID] = *stored
+
+	return r.copyOf(userID), nil
+}
+
+// Delete removes the stored preferences of a user.
+func (r *UserPreferenceRepository) Delete(_ context.Context, userID entities.UserID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.prefs, userID)
+
+	return nil
+}
+
+// copyOf returns a copy of the stored preferences of userID, so callers
+// cannot change the stored values.
+func (r *UserPreferenceRepository) copyOf(userID entities.UserID) *entities.UserPreferences {
+	stored := r.prefs[userID]
+
+	return entities.NewUserPreferences(userID, maps.Clone(stored.Values), stored.UpdatedAt)
+}
+
+var _ repositories.UserPreferenceRepository = (*UserPreferenceRepository)(nil)
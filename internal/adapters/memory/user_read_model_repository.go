@@ -0,0 +1,129 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// UserReadModelRepository implements UserReadModelRepository in memory. Its
+// lag is measured against users, the repository its views are projected
+// from, as the databases measure it against the users table.
+type UserReadModelRepository struct {
+	mu    sync.RWMutex
+	views map[entities.UserID]entities.UserView
+	users *UserRepository
+}
+
+// NewUserReadModelRepository creates an empty in-memory read model of users.
+func NewUserReadModelRepository(users *UserRepository) *UserReadModelRepository {
+	return &UserReadModelRepository{
+		views: make(map[entities.UserID]entities.UserView),
+		users: users,
+	}
+}
+
+// Upsert stores the views, replacing those of the same users unless they
+// were projected from a newer version of the user.
+func (r *UserReadModelRepository) Upsert(_ context.Context, views ...*entities.UserView) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, view := range views {
+		if current, ok := r.views[view.UserID]; ok && current.SourceUpdatedAt.After(view.SourceUpdatedAt) {
+			continue
+		}
+
+		stored := *view
+		stored.Tags = slices.Clone(view.Tags)
+		r.views[view.UserID] = stored
+	}
+
+	return nil
+}
+
+// Remove drops the views of the users; unknown IDs are ignored.
+func (r *UserReadModelRepository) Remove(_ context.Context, ids ...entities.UserID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, id := range ids {
+		delete(r.views, id)
+	}
+
+	return nil
+}
+
+// List returns up to limit views matching filter after skipping offset,
+// newest first with the ID breaking ties.
+func (r *UserReadModelRepository) List(
+	_ context.Context,
+	filter entities.UserFilter,
+	limit, offset int,
+) ([]*entities.UserView, error) {
+	err := adapters.ValidateListRequest(filter, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	views := []*entities.UserView{}
+
+	for _, view := range r.views {
+		if filter.MatchesView(&view) {
+			view.Tags = slices.Clone(view.Tags)
+			views = append(views, &view)
+		}
+	}
+
+	slices.SortFunc(views, func(a, b *entities.UserView) int {
+		return cmp.Or(b.CreatedAt.Compare(a.CreatedAt), cmp.Compare(b.UserID, a.UserID))
+	})
+
+	views = views[min(offset, len(views)):]
+
+	return views[:min(limit, len(views))], nil
+}
+
+// Lag returns how long the oldest change of a user that is not in the read
+// model yet has been waiting: a live user without a current view, or a
+// deleted user whose view was not removed.
+func (r *UserReadModelRepository) Lag(_ context.Context) (time.Duration, error) {
+	r.users.mu.RLock()
+	defer r.users.mu.RUnlock()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var oldest time.Time
+
+	for id, user := range r.users.users {
+		view, projected := r.views[id]
+
+		changedAt := user.record.UpdatedAt
+		if user.deleted() {
+			changedAt = user.deletedAt
+		}
+
+		stale := projected == user.deleted() || (projected && view.SourceUpdatedAt.Before(changedAt))
+		if stale && (oldest.IsZero() || changedAt.Before(oldest)) {
+			oldest = changedAt
+		}
+	}
+
+	if oldest.IsZero() {
+		return 0, nil
+	}
+
+	return max(time.Since(oldest), 0), nil
+}
+
+var _ repositories.UserReadModelRepository = (*UserReadModelRepository)(nil)
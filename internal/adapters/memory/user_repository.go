@@ -0,0 +1,711 @@
+// Package memory provides in-memory repository adapters with the semantics of
+// the database adapters: generated IDs, unique emails and usernames, soft
+// deletes, filtering, search facets and statistics. They let services be
+// tested and demoed without a database.
+//
+// Repositories store copies of the entities, so changing a returned entity
+// has no effect until it is saved, as with a database. Every method is safe
+// for concurrent use; there are no transactions, so locking reads only read.
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters"
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/validation"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// Engine is the engine name reported by the memory repositories.
+const Engine = "memory"
+
+// storedUser is a user row; deletedAt is set for soft-deleted users.
+type storedUser struct {
+	record    entities.UserRecord
+	deletedAt time.Time
+}
+
+func (s *storedUser) deleted() bool {
+	return !s.deletedAt.IsZero()
+}
+
+// UserRepository implements UserRepository in memory.
+type UserRepository struct {
+	mu     sync.RWMutex
+	users  map[entities.UserID]*storedUser
+	nextID entities.UserID
+}
+
+// NewUserRepository creates an empty in-memory user repository.
+func NewUserRepository() *UserRepository {
+	return &UserRepository{
+		users:  make(map[entities.UserID]*storedUser),
+		nextID: 1,
+	}
+}
+
+// Engine returns the engine name.
+func (r *UserRepository) Engine() string {
+	return Engine
+}
+
+// Create stores the user and assigns its ID.
+func (r *UserRepository) Create(_ context.Context, user *entities.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	err := r.checkUnique(user.Record(), nil)
+	if err != nil {
+		return fmt.Errorf("create user email=%v: %w", user.Email(), err)
+	}
+
+	r.insert(user)
+
+	return nil
+}
+
+// insert stores user without checks; the caller holds the write lock.
+func (r *UserRepository) insert(user *entities.User) {
+	now := time.Now()
+	record := user.Record()
+	record.ID = r.nextID
+
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = now
+	}
+
+	if record.UpdatedAt.IsZero() {
+		record.UpdatedAt = record.CreatedAt
+	}
+
+	r.nextID++
+	r.users[record.ID] = &storedUser{record: record}
+
+	user.SetID(record.ID)
+}
+
+// checkUnique returns ErrUserAlreadyExists when another stored user, deleted
+// or not, or one of pending has the record's UUID, email or canonical username.
+func (r *UserRepository) checkUnique(record entities.UserRecord, pending []entities.UserRecord) error {
+	conflicts := func(other entities.UserRecord) bool {
+		return other.ID != record.ID &&
+			(other.UUID == record.UUID || other.Email == record.Email ||
+				other.Username.Canonical() == record.Username.Canonical())
+	}
+
+	for _, stored := range r.users {
+		if conflicts(stored.record) {
+			return entities.ErrUserAlreadyExists
+		}
+	}
+
+	if slices.ContainsFunc(pending, conflicts) {
+		return entities.ErrUserAlreadyExists
+	}
+
+	return nil
+}
+
+// live returns the stored user with id unless it is missing or deleted; the
+// caller holds a lock.
+func (r *UserRepository) live(id entities.UserID) (*storedUser, bool) {
+	stored, ok := r.users[id]
+	if !ok || stored.deleted() {
+		return nil, false
+	}
+
+	return stored, true
+}
+
+// find returns the first live user matching match; the caller holds a lock.
+func (r *UserRepository) find(match func(entities.UserRecord) bool) (*entities.User, error) {
+	for _, stored := range r.users {
+		if !stored.deleted() && match(stored.record) {
+			return entities.ReconstructUser(stored.record)
+		}
+	}
+
+	return nil, entities.ErrUserNotFound
+}
+
+// GetByID retrieves a user by ID.
+func (r *UserRepository) GetByID(_ context.Context, id entities.UserID) (*entities.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	stored, ok := r.live(id)
+	if !ok {
+		return nil, fmt.Errorf("id=%v: %w", id, entities.ErrUserNotFound)
+	}
+
+	return entities.ReconstructUser(stored.record)
+}
+
+// GetByIDs retrieves the users with the given IDs in ID order, skipping unknown IDs.
+func (r *UserRepository) GetByIDs(_ context.Context, ids []entities.UserID) ([]*entities.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ids = slices.Compact(slices.Sorted(slices.Values(ids)))
+	users := make([]*entities.User, 0, len(ids))
+
+	for _, id := range ids {
+		stored, ok := r.live(id)
+		if !ok {
+			continue
+		}
+
+		user, err := entities.ReconstructUser(stored.record)
+		if err != nil {
+			return nil, err
+		}
+
+		users = append(users, user)
+	}
+
+	return users, nil
+}
+
+// GetByUUID retrieves a user by UUID.
+func (r *UserRepository) GetByUUID(_ context.Context, uuid entities.UuID) (*entities.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	user, err := r.find(func(record entities.UserRecord) bool { return record.UUID.String() == string(uuid) })
+	if err != nil {
+		return nil, fmt.Errorf("uuid=%v: %w", uuid, err)
+	}
+
+	return user, nil
+}
+
+// GetByEmail retrieves a user by email.
+func (r *UserRepository) GetByEmail(_ context.Context, email entities.Email) (*entities.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	user, err := r.find(func(record entities.UserRecord) bool { return record.Email == email })
+	if err != nil {
+		return nil, fmt.Errorf("email=%v: %w", email, err)
+	}
+
+	return user, nil
+}
+
+// GetByUsername retrieves a user by canonical username.
+func (r *UserRepository) GetByUsername(
+	_ context.Context,
+	username entities.Username,
+) (*entities.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	canonical := username.Canonical()
+
+	user, err := r.find(func(record entities.UserRecord) bool { return record.Username.Canonical() == canonical })
+	if err != nil {
+		return nil, fmt.Errorf("username=%v: %w", username, err)
+	}
+
+	return user, nil
+}
+
+// Update persists every mutable field of the user. The UUID, password and
+// creation time are kept, as in the database adapters.
+func (r *UserRepository) Update(_ context.Context, user *entities.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.live(user.ID())
+	if !ok {
+		return fmt.Errorf("update user id=%v: %w", user.ID(), entities.ErrUserNotFound)
+	}
+
+	record := user.Record()
+	record.UUID = stored.record.UUID
+	record.Password = stored.record.Password
+	record.CreatedAt = stored.record.CreatedAt
+	record.UpdatedAt = time.Now()
+
+	err := r.checkUnique(record, nil)
+	if err != nil {
+		return fmt.Errorf("update user id=%v: %w", user.ID(), err)
+	}
+
+	stored.record = record
+
+	return nil
+}
+
+// modify applies change to the live user with id.
+func (r *UserRepository) modify(id entities.UserID, op string, change func(record *entities.UserRecord)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.live(id)
+	if !ok {
+		return fmt.Errorf("%s id=%v: %w", op, id, entities.ErrUserNotFound)
+	}
+
+	change(&stored.record)
+	stored.record.UpdatedAt = time.Now()
+
+	return nil
+}
+
+// Delete soft deletes a user.
+func (r *UserRepository) Delete(_ context.Context, id entities.UserID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.live(id)
+	if !ok {
+		return fmt.Errorf("delete user id=%v: %w", id, entities.ErrUserNotFound)
+	}
+
+	stored.deletedAt = time.Now()
+
+	return nil
+}
+
+// Restore restores a soft-deleted user.
+func (r *UserRepository) Restore(_ context.Context, id entities.UserID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.users[id]
+	if !ok || !stored.deleted() {
+		return fmt.Errorf("restore user id=%v: %w", id, entities.ErrUserNotFound)
+	}
+
+	stored.deletedAt = time.Time{}
+
+	return nil
+}
+
+// PurgeDeletedOlderThan permanently removes the users soft deleted before cutoff.
+func (r *UserRepository) PurgeDeletedOlderThan(_ context.Context, cutoff time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var purged int64
+
+	for id, stored := range r.users {
+		if stored.deleted() && stored.deletedAt.Before(cutoff) {
+			delete(r.users, id)
+
+			purged++
+		}
+	}
+
+	return purged, nil
+}
+
+// CreateBatch stores the users all or nothing.
+func (r *UserRepository) CreateBatch(_ context.Context, users []*entities.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	pending := make([]entities.UserRecord, 0, len(users))
+
+	for _, user := range users {
+		record := user.Record()
+
+		err := r.checkUnique(record, pending)
+		if err != nil {
+			return fmt.Errorf("create batch of %d email=%v: %w", len(users), user.Email(), err)
+		}
+
+		pending = append(pending, record)
+	}
+
+	for _, user := range users {
+		r.insert(user)
+	}
+
+	return nil
+}
+
+// UpdateStatusBatch changes the status of the live users with the given IDs.
+func (r *UserRepository) UpdateStatusBatch(
+	_ context.Context,
+	ids []entities.UserID,
+	status entities.UserStatus,
+) (int64, error) {
+	if !status.IsValid() {
+		return 0, fmt.Errorf("status=%v: %w", status, entities.ErrInvalidUserStatus)
+	}
+
+	return r.batchByIDs(ids, func(stored *storedUser) {
+		stored.record.Status = status
+		stored.record.UpdatedAt = time.Now()
+	}), nil
+}
+
+// DeleteBatch soft deletes the live users with the given IDs.
+func (r *UserRepository) DeleteBatch(_ context.Context, ids []entities.UserID) (int64, error) {
+	return r.batchByIDs(ids, func(stored *storedUser) {
+		stored.deletedAt = time.Now()
+	}), nil
+}
+
+// batchByIDs applies change to each live user with one of ids and counts them.
+func (r *UserRepository) batchByIDs(ids []entities.UserID, change func(stored *storedUser)) int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var affected int64
+
+	for _, id := range slices.Compact(slices.Sorted(slices.Values(ids))) {
+		stored, ok := r.live(id)
+		if !ok {
+			continue
+		}
+
+		change(stored)
+
+		affected++
+	}
+
+	return affected
+}
+
+// List retrieves a page of the users matching filter, newest first.
+func (r *UserRepository) List(
+	_ context.Context,
+	filter entities.UserFilter,
+	limit, offset int,
+) ([]*entities.User, error) {
+	err := adapters.ValidateListRequest(filter, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	var first *entities.UserCursor
+
+	return r.filterUsers(filter, first.Bound(), limit, offset)
+}
+
+// ListPage retrieves a page of users with keyset pagination, newest first.
+func (r *UserRepository) ListPage(
+	_ context.Context,
+	filter entities.UserFilter,
+	cursor string,
+	limit int,
+) (*entities.UserPage, error) {
+	after, err := adapters.ParsePageRequest(filter, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	// One extra user tells whether another page follows.
+	users, err := r.filterUsers(filter, after.Bound(), limit+1, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return entities.NewUserPage(users, limit), nil
+}
+
+// filterUsers returns the live users matching filter after bound, newest
+// first with the ID breaking ties.
+func (r *UserRepository) filterUsers(
+	filter entities.UserFilter,
+	bound entities.UserCursor,
+	limit, offset int,
+) ([]*entities.User, error) {
+	users, err := r.liveUsers()
+	if err != nil {
+		return nil, err
+	}
+
+	users = slices.DeleteFunc(users, func(user *entities.User) bool {
+		before := user.CreatedAt().Before(bound.CreatedAt) ||
+			(user.CreatedAt().Equal(bound.CreatedAt) && user.ID() < bound.ID)
+
+		return !before || !filter.Matches(user)
+	})
+
+	if offset >= len(users) {
+		return []*entities.User{}, nil
+	}
+
+	return users[offset:min(offset+limit, len(users))], nil
+}
+
+// liveUsers returns every live user, newest first with the ID breaking ties.
+func (r *UserRepository) liveUsers() ([]*entities.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	users := make([]*entities.User, 0, len(r.users))
+
+	for _, stored := range r.users {
+		if stored.deleted() {
+			continue
+		}
+
+		user, err := entities.ReconstructUser(stored.record)
+		if err != nil {
+			return nil, err
+		}
+
+		users = append(users, user)
+	}
+
+	slices.SortFunc(users, func(a, b *entities.User) int {
+		return cmp.Or(b.CreatedAt().Compare(a.CreatedAt()), cmp.Compare(b.ID(), a.ID()))
+	})
+
+	return users, nil
+}
+
+// SearchWithFacets searches the users whose email, username or names contain
+// query, ignoring case, and counts facets over every match. An empty status
+// returns users of any status.
+func (r *UserRepository) SearchWithFacets(
+	_ context.Context,
+	query string,
+	status entities.UserStatus,
+	limit int,
+) (*entities.UserSearchResult, error) {
+	err := validation.ValidateSearchQuery(query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("query=%v limit=%v: %w", query, limit, err)
+	}
+
+	users, err := r.liveUsers()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &entities.UserSearchResult{
+		Users:  make([]*entities.User, 0, limit),
+		Facets: entities.NewSearchFacets(),
+	}
+	tags := make(map[string]int64)
+	needle := strings.ToLower(query)
+
+	for _, user := range users {
+		if !contains(user, needle) {
+			continue
+		}
+
+		result.Facets.ByStatus[user.Status()]++
+		result.Facets.ByRole[user.Role()]++
+
+		for _, tag := range user.Tags() {
+			tags[tag]++
+		}
+
+		if (status == "" || user.Status() == status) && len(result.Users) < limit {
+			result.Users = append(result.Users, user)
+		}
+	}
+
+	for _, tag := range slices.Sorted(maps.Keys(tags)) {
+		result.Facets.TopTags = append(result.Facets.TopTags, entities.TagCount{Tag: tag, Count: tags[tag]})
+	}
+
+	slices.SortStableFunc(result.Facets.TopTags, func(a, b entities.TagCount) int {
+		return cmp.Compare(b.Count, a.Count)
+	})
+
+	result.Facets.TopTags = result.Facets.TopTags[:min(len(result.Facets.TopTags), adapters.DefaultFacetTagLimit)]
+
+	return result, nil
+}
+
+// contains reports whether the searchable fields of user contain needle.
+func contains(user *entities.User, needle string) bool {
+	return slices.ContainsFunc([]string{
+		user.Email().String(),
+		user.Username().String(),
+		user.FirstName().String(),
+		user.LastName().String(),
+	}, func(field string) bool { return strings.Contains(strings.ToLower(field), needle) })
+}
+
+// CountByStatus counts live users per status.
+func (r *UserRepository) CountByStatus(_ context.Context) (map[entities.UserStatus]int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	counts := make(map[entities.UserStatus]int64)
+
+	for _, stored := range r.users {
+		if !stored.deleted() {
+			counts[stored.record.Status]++
+		}
+	}
+
+	return counts, nil
+}
+
+// GetStats returns aggregate user statistics computed like the databases do:
+// deleted users count towards the total, and active users are those not deleted.
+func (r *UserRepository) GetStats(_ context.Context) (*entities.UserStats, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	now := time.Now()
+	stats := &entities.UserStats{}
+
+	for _, stored := range r.users {
+		record := stored.record
+		stats.TotalUsers++
+
+		counts := []struct {
+			match bool
+			count *int64
+		}{
+			{!stored.deleted(), &stats.ActiveUsers},
+			{record.IsVerified, &stats.VerifiedUsers},
+			{record.LastLoginAt != nil, &stats.UsersWithLogins},
+			{record.Status == entities.UserStatusInactive, &stats.InactiveUsers},
+			{record.Status == entities.UserStatusSuspended, &stats.SuspendedUsers},
+			{!record.CreatedAt.Before(now.AddDate(0, 0, -30)), &stats.NewUsers30d},
+			{!record.CreatedAt.Before(now.AddDate(0, 0, -7)), &stats.NewUsers7d},
+		}
+
+		for _, c := range counts {
+			if c.match {
+				*c.count++
+			}
+		}
+	}
+
+	stats.ComputeRates()
+
+	return stats, nil
+}
+
+// GetCredentials returns the user of email with its password hash.
+func (r *UserRepository) GetCredentials(ctx context.Context, email entities.Email) (*entities.User, error) {
+	return r.GetByEmail(ctx, email)
+}
+
+// UpdatePassword stores a new password hash.
+func (r *UserRepository) UpdatePassword(
+	_ context.Context,
+	id entities.UserID,
+	password entities.PasswordHash,
+) error {
+	return r.modify(id, "update password", func(record *entities.UserRecord) {
+		record.Password = password
+	})
+}
+
+// MarkVerified marks the user's email as verified.
+func (r *UserRepository) MarkVerified(_ context.Context, id entities.UserID) error {
+	return r.modify(id, "mark verified", func(record *entities.UserRecord) {
+		record.IsVerified = true
+	})
+}
+
+// ChangeStatus changes user status.
+func (r *UserRepository) ChangeStatus(
+	_ context.Context,
+	id entities.UserID,
+	status entities.UserStatus,
+) error {
+	if !status.IsValid() {
+		return fmt.Errorf("status=%v: %w", status, entities.ErrInvalidUserStatus)
+	}
+
+	return r.modify(id, "change status", func(record *entities.UserRecord) {
+		record.Status = status
+	})
+}
+
+// Activate activates a user.
+func (r *UserRepository) Activate(ctx context.Context, id entities.UserID) error {
+	return r.ChangeStatus(ctx, id, entities.UserStatusActive)
+}
+
+// Deactivate deactivates a user.
+func (r *UserRepository) Deactivate(ctx context.Context, id entities.UserID) error {
+	return r.ChangeStatus(ctx, id, entities.UserStatusInactive)
+}
+
+// Suspend suspends a user.
+func (r *UserRepository) Suspend(ctx context.Context, id entities.UserID) error {
+	return r.ChangeStatus(ctx, id, entities.UserStatusSuspended)
+}
+
+// ChangeRole changes user role.
+func (r *UserRepository) ChangeRole(
+	_ context.Context,
+	id entities.UserID,
+	role entities.UserRole,
+) error {
+	if !role.IsValid() {
+		return fmt.Errorf("role=%v: %w", role, entities.ErrInvalidUserRole)
+	}
+
+	return r.modify(id, "change role", func(record *entities.UserRecord) {
+		record.Role = role
+	})
+}
+
+// GetByIDForUpdate retrieves a user by ID. Without transactions there is no
+// lock to hold, so it is a plain read.
+func (r *UserRepository) GetByIDForUpdate(
+	ctx context.Context,
+	id entities.UserID,
+	_ ...repositories.LockOption,
+) (*entities.User, error) {
+	return r.GetByID(ctx, id)
+}
+
+// ClaimNext returns up to limit live users with the given status in ID order.
+func (r *UserRepository) ClaimNext(
+	_ context.Context,
+	status entities.UserStatus,
+	limit int,
+) ([]*entities.User, error) {
+	if !status.IsValid() {
+		return nil, fmt.Errorf("status=%v: %w", status, entities.ErrInvalidUserStatus)
+	}
+
+	err := validation.ValidatePagination(limit, 0)
+	if err != nil {
+		return nil, fmt.Errorf("limit=%v: %w", limit, err)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	claimed := make([]*entities.User, 0, limit)
+
+	for _, id := range slices.Sorted(maps.Keys(r.users)) {
+		stored := r.users[id]
+		if len(claimed) == limit {
+			break
+		}
+
+		if stored.deleted() || stored.record.Status != status {
+			continue
+		}
+
+		user, err := entities.ReconstructUser(stored.record)
+		if err != nil {
+			return nil, err
+		}
+
+		claimed = append(claimed, user)
+	}
+
+	return claimed, nil
+}
+
+// Ensure UserRepository implements UserRepository.
+var _ repositories.UserRepository = (*UserRepository)(nil)
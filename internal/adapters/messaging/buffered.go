@@ -0,0 +1,318 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+)
+
+const (
+	defaultBufferCapacity = 1024
+	defaultBatchSize      = 100
+	defaultFlushInterval  = 100 * time.Millisecond
+)
+
+// Reasons buffered events were not published, as reported to a BufferObserver.
+const (
+	DropReasonOverflow      = "overflow"
+	DropReasonPublishFailed = "publish_failed"
+	DropReasonShutdown      = "shutdown"
+)
+
+var (
+	// ErrPublisherClosed is returned when events are published to a closed
+	// buffered publisher.
+	ErrPublisherClosed = errors.New("event publisher closed")
+	// ErrBufferFull is returned for events rejected by a full buffer with
+	// OverflowReject.
+	ErrBufferFull = errors.New("event buffer full")
+)
+
+// Overflow selects what publishing to a full buffer does.
+type Overflow string
+
+const (
+	// OverflowBlock blocks the publisher until the buffer has room, pushing
+	// back on the request path.
+	OverflowBlock Overflow = "block"
+	// OverflowDrop drops the events that do not fit and reports success.
+	OverflowDrop Overflow = "drop"
+	// OverflowReject drops the events that do not fit and returns
+	// ErrBufferFull.
+	OverflowReject Overflow = "reject"
+)
+
+// BufferPolicy controls how events are buffered and when they are flushed.
+type BufferPolicy struct {
+	// Capacity is the number of events the buffer holds.
+	Capacity int
+	// BatchSize flushes the pending events once that many are waiting.
+	BatchSize int
+	// FlushInterval flushes the pending events at least this often; zero
+	// only flushes full batches and on Close.
+	FlushInterval time.Duration
+	Overflow      Overflow
+}
+
+// DefaultBufferPolicy returns the buffer policy used when none is
+// configured: 1024 events, flushed in batches of 100 or every 100ms, and
+// blocking while the buffer is full.
+func DefaultBufferPolicy() BufferPolicy {
+	return BufferPolicy{
+		Capacity:      defaultBufferCapacity,
+		BatchSize:     defaultBatchSize,
+		FlushInterval: defaultFlushInterval,
+		Overflow:      OverflowBlock,
+	}
+}
+
+// BufferObserver receives the depth of the buffer and the events that were
+// not published. *monitoring.Metrics satisfies it.
+type BufferObserver interface {
+	ObserveEventBuffer(depth int)
+	ObserveEventsDropped(reason string, count int)
+}
+
+// BufferOption configures a BufferedPublisher.
+type BufferOption func(*BufferedPublisher)
+
+// WithBufferPolicy sets the buffer policy. Values below one keep the
+// defaults of the capacity and batch size.
+func WithBufferPolicy(policy BufferPolicy) BufferOption {
+	return func(p *BufferedPublisher) {
+		if policy.Capacity < 1 {
+			policy.Capacity = p.policy.Capacity
+		}
+
+		if policy.BatchSize < 1 {
+			policy.BatchSize = p.policy.BatchSize
+		}
+
+		if policy.Overflow == "" {
+			policy.Overflow = p.policy.Overflow
+		}
+
+		p.policy = policy
+	}
+}
+
+// WithBufferObserver reports the buffer depth and dropped events to observer.
+func WithBufferObserver(observer BufferObserver) BufferOption {
+	return func(p *BufferedPublisher) {
+		if observer != nil {
+			p.observer = observer
+		}
+	}
+}
+
+// BufferedPublisher publishes events asynchronously: Publish queues the
+// event and returns, and a background loop publishes the queued events in
+// batches with PublishBatch of the wrapped publisher, whenever a batch is
+// full or the flush interval passed. Events whose batch fails are logged
+// and dropped, as the request that published them has already completed.
+// Close must be called to publish the remaining events and stop the loop.
+type BufferedPublisher struct {
+	next     events.EventPublisher
+	policy   BufferPolicy
+	observer BufferObserver
+
+	// closing is closed first by Close, so that publishers blocked on a
+	// full queue give up and release mu for Close to close the queue.
+	closing   chan struct{}
+	closeOnce sync.Once
+	mu        sync.RWMutex
+	queue     chan *events.UserEvent
+
+	abort     chan struct{}
+	abortOnce sync.Once
+	done      chan struct{}
+}
+
+// Ensure BufferedPublisher implements events.EventPublisher.
+var _ events.EventPublisher = (*BufferedPublisher)(nil)
+
+// NewBufferedPublisher wraps next with a buffer and starts its flush loop.
+func NewBufferedPublisher(next events.EventPublisher, opts ...BufferOption) *BufferedPublisher {
+	p := &BufferedPublisher{
+		next:     next,
+		policy:   DefaultBufferPolicy(),
+		observer: nopBufferObserver{},
+		closing:  make(chan struct{}),
+		abort:    make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	p.queue = make(chan *events.UserEvent, p.policy.Capacity)
+
+	go p.loop()
+
+	return p
+}
+
+// Publish queues event.
+func (p *BufferedPublisher) Publish(event *events.UserEvent) error {
+	return p.PublishBatch([]*events.UserEvent{event})
+}
+
+// PublishBatch queues evts. While the buffer is full it blocks, drops or
+// rejects the remaining events as its Overflow policy says. Blocking gives
+// up with ErrPublisherClosed once Close is called.
+func (p *BufferedPublisher) PublishBatch(evts []*events.UserEvent) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.isClosing() {
+		return ErrPublisherClosed
+	}
+
+	defer func() { p.observer.ObserveEventBuffer(len(p.queue)) }()
+
+	for i, event := range evts {
+		if p.policy.Overflow == OverflowBlock {
+			select {
+			case p.queue <- event:
+				continue
+			case <-p.closing:
+				dropped := len(evts) - i
+				p.observer.ObserveEventsDropped(DropReasonShutdown, dropped)
+
+				return fmt.Errorf("dropped=%d: %w", dropped, ErrPublisherClosed)
+			}
+		}
+
+		select {
+		case p.queue <- event:
+		default:
+			dropped := len(evts) - i
+			p.observer.ObserveEventsDropped(DropReasonOverflow, dropped)
+
+			if p.policy.Overflow == OverflowReject {
+				return fmt.Errorf("dropped=%d: %w", dropped, ErrBufferFull)
+			}
+
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// Close stops accepting events and waits until the queued events are
+// published. If ctx is done first, the remaining events are dropped and
+// ctx's error is returned.
+func (p *BufferedPublisher) Close(ctx context.Context) error {
+	p.closeOnce.Do(func() {
+		close(p.closing)
+
+		p.mu.Lock()
+		close(p.queue)
+		p.mu.Unlock()
+	})
+
+	select {
+	case <-p.done:
+		return nil
+	case <-ctx.Done():
+		p.abortOnce.Do(func() { close(p.abort) })
+
+		return fmt.Errorf("failed to drain event buffer: %w", ctx.Err())
+	}
+}
+
+// loop collects queued events into batches and flushes them until the queue
+// is closed and drained, or Close gives up.
+func (p *BufferedPublisher) loop() {
+	defer close(p.done)
+
+	var tick <-chan time.Time
+
+	if p.policy.FlushInterval > 0 {
+		ticker := time.NewTicker(p.policy.FlushInterval)
+		defer ticker.Stop()
+
+		tick = ticker.C
+	}
+
+	batch := make([]*events.UserEvent, 0, p.policy.BatchSize)
+
+	for {
+		// Once Close gave up, the queued events are not published anymore.
+		if p.aborted() {
+			p.observer.ObserveEventsDropped(DropReasonShutdown, len(batch)+len(p.queue))
+
+			return
+		}
+
+		select {
+		case event, ok := <-p.queue:
+			if !ok {
+				p.flush(batch)
+
+				return
+			}
+
+			batch = append(batch, event)
+			if len(batch) >= p.policy.BatchSize {
+				batch = p.flush(batch)
+			}
+		case <-tick:
+			batch = p.flush(batch)
+		case <-p.abort:
+		}
+	}
+}
+
+// isClosing reports whether Close was called.
+func (p *BufferedPublisher) isClosing() bool {
+	select {
+	case <-p.closing:
+		return true
+	default:
+		return false
+	}
+}
+
+// aborted reports whether Close gave up waiting for the queued events.
+func (p *BufferedPublisher) aborted() bool {
+	select {
+	case <-p.abort:
+		return true
+	default:
+		return false
+	}
+}
+
+// flush publishes batch and returns an empty batch to collect the next
+// events in.
+func (p *BufferedPublisher) flush(batch []*events.UserEvent) []*events.UserEvent {
+	if len(batch) == 0 {
+		return batch
+	}
+
+	err := p.next.PublishBatch(batch)
+	if err != nil {
+		slog.Error("event batch publish failed", "events", len(batch), "error", err)
+		p.observer.ObserveEventsDropped(DropReasonPublishFailed, len(batch))
+	}
+
+	p.observer.ObserveEventBuffer(len(p.queue))
+
+	// The wrapped publisher may still hold the published batch.
+	return make([]*events.UserEvent, 0, p.policy.BatchSize)
+}
+
+// nopBufferObserver discards observations.
+type nopBufferObserver struct{}
+
+func (nopBufferObserver) ObserveEventBuffer(int) {}
+
+func (nopBufferObserver) ObserveEventsDropped(string, int) {}
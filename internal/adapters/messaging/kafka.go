@@ -0,0 +1,83 @@
+package messaging
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+	"github.com/segmentio/kafka-go"
+)
+
+// MessageWriter is the part of kafka.Writer used for publishing.
+// The writer must not have a Topic set, since topics are chosen per message.
+type MessageWriter interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+}
+
+// KafkaPublisher publishes user events to Kafka topics.
+// Messages are keyed by user ID so events of one user stay ordered within a partition.
+type KafkaPublisher struct {
+	writer MessageWriter
+	cfg    config
+}
+
+var _ events.EventPublisher = (*KafkaPublisher)(nil)
+
+// NewKafkaPublisher creates a publisher on top of a Kafka writer.
+func NewKafkaPublisher(writer MessageWriter, opts ...Option) *KafkaPublisher {
+	return &KafkaPublisher{writer: writer, cfg: newConfig(opts)}
+}
+
+// Publish publishes a single event.
+func (p *KafkaPublisher) Publish(event *events.UserEvent) error {
+	return p.PublishBatch([]*events.UserEvent{event})
+}
+
+// PublishBatch publishes events in a single write.
+func (p *KafkaPublisher) PublishBatch(batch []*events.UserEvent) error {
+	if len(batch) == 0 {
+		return nil
+	}
+
+	msgs := make([]kafka.Message, 0, len(batch))
+
+	for _, event := range batch {
+		msg, err := p.message(event)
+		if err != nil {
+			return err
+		}
+
+		msgs = append(msgs, msg)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), p.cfg.timeout)
+	defer cancel()
+
+	err := p.cfg.retry.do(ctx, func(ctx context.Context) error {
+		return p.writer.WriteMessages(ctx, msgs...)
+	})
+	if err != nil {
+		return fmt.Errorf("events=%d: %w", len(msgs), err)
+	}
+
+	return nil
+}
+
+func (p *KafkaPublisher) message(event *events.UserEvent) (kafka.Message, error) {
+	payload, err := p.cfg.serializer.Serialize(event)
+	if err != nil {
+		return kafka.Message{}, fmt.Errorf("failed to serialize event: %w", err)
+	}
+
+	return kafka.Message{
+		Topic: p.cfg.topics.For(event.Type),
+		Key:   []byte(strconv.FormatInt(event.UserID.Int64(), 10)),
+		Value: payload,
+		Time:  event.Timestamp,
+		Headers: []kafka.Header{
+			{Key: "content-type", Value: []byte(p.cfg.serializer.ContentType())},
+			{Key: "event-type", Value: []byte(event.Type.String())},
+		},
+	}, nil
+}
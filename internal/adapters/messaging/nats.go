@@ -0,0 +1,76 @@
+package messaging
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// JetStreamPublisher is the part of jetstream.JetStream used for publishing.
+type JetStreamPublisher interface {
+	PublishMsg(ctx context.Context, msg *nats.Msg, opts ...jetstream.PublishOpt) (*jetstream.PubAck, error)
+}
+
+// NATSPublisher publishes user events to NATS JetStream subjects.
+// The event ID is sent as Nats-Msg-Id so retried publishes are deduplicated by the stream.
+type NATSPublisher struct {
+	js  JetStreamPublisher
+	cfg config
+}
+
+var _ events.EventPublisher = (*NATSPublisher)(nil)
+
+// NewNATSPublisher creates a publisher on top of a JetStream context.
+func NewNATSPublisher(js JetStreamPublisher, opts ...Option) *NATSPublisher {
+	return &NATSPublisher{js: js, cfg: newConfig(opts)}
+}
+
+// Publish publishes a single event.
+func (p *NATSPublisher) Publish(event *events.UserEvent) error {
+	ctx, cancel := context.WithTimeout(context.Background(), p.cfg.timeout)
+	defer cancel()
+
+	return p.publish(ctx, event)
+}
+
+// PublishBatch publishes events in order and stops at the first failure.
+func (p *NATSPublisher) PublishBatch(batch []*events.UserEvent) error {
+	ctx, cancel := context.WithTimeout(context.Background(), p.cfg.timeout)
+	defer cancel()
+
+	for _, event := range batch {
+		err := p.publish(ctx, event)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *NATSPublisher) publish(ctx context.Context, event *events.UserEvent) error {
+	payload, err := p.cfg.serializer.Serialize(event)
+	if err != nil {
+		return fmt.Errorf("failed to serialize event: %w", err)
+	}
+
+	msg := nats.NewMsg(p.cfg.topics.For(event.Type))
+	msg.Data = payload
+	msg.Header.Set("Content-Type", p.cfg.serializer.ContentType())
+	msg.Header.Set(jetstream.MsgIDHeader, strconv.FormatInt(event.ID.Int64(), 10))
+
+	err = p.cfg.retry.do(ctx, func(ctx context.Context) error {
+		_, err := p.js.PublishMsg(ctx, msg)
+
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("subject=%v event=%v: %w", msg.Subject, event.ID, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,154 @@
+package messaging
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+)
+
+const (
+	defaultTopic          = "users.events"
+	defaultTimeout        = 5 * time.Second
+	defaultMaxAttempts    = 3
+	defaultInitialBackoff = 100 * time.Millisecond
+	defaultMaxBackoff     = 2 * time.Second
+)
+
+// Topics maps event types to broker topics or subjects.
+// Event types without an explicit mapping are published to Default.
+type Topics struct {
+	Default string
+	ByType  map[events.EventType]string
+}
+
+// For returns the topic for eventType.
+func (t Topics) For(eventType events.EventType) string {
+	if topic, ok := t.ByType[eventType]; ok {
+		return topic
+	}
+
+	return t.Default
+}
+
+// RetryPolicy controls how failed publishes are retried with exponential backoff.
+type RetryPolicy struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// DefaultRetryPolicy returns the retry policy used when none is configured.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    defaultMaxAttempts,
+		InitialBackoff: defaultInitialBackoff,
+		MaxBackoff:     defaultMaxBackoff,
+	}
+}
+
+// backoff returns the delay before the given retry, starting at 1.
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	delay := p.InitialBackoff << (retry - 1)
+	if delay <= 0 || delay > p.MaxBackoff {
+		return p.MaxBackoff
+	}
+
+	return delay
+}
+
+// do runs fn until it succeeds, the attempts are exhausted or ctx is done.
+func (p RetryPolicy) do(ctx context.Context, fn func(ctx context.Context) error) error {
+	attempts := max(p.MaxAttempts, 1)
+
+	var err error
+
+	for attempt := 1; attempt <= attempts; attempt++ {
+		err = fn(ctx)
+		if err == nil {
+			return nil
+		}
+
+		if attempt == attempts {
+			break
+		}
+
+		timer := time.NewTimer(p.backoff(attempt))
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			return fmt.Errorf("attempt=%d: %w: %w", attempt, ctx.Err(), err)
+		case <-timer.C:
+		}
+	}
+
+	return fmt.Errorf("attempts=%d: %w", attempts, err)
+}
+
+// config holds the settings shared by all broker publishers.
+type config struct {
+	serializer Serializer
+	topics     Topics
+	retry      RetryPolicy
+	timeout    time.Duration
+}
+
+func defaultConfig() config {
+	return config{
+		serializer: JSONSerializer{},
+		topics:     Topics{Default: defaultTopic},
+		retry:      DefaultRetryPolicy(),
+		timeout:    defaultTimeout,
+	}
+}
+
+// Option configures a broker publisher.
+type Option func(*config)
+
+// WithSerializer sets the payload encoding. JSON is used by default.
+func WithSerializer(serializer Serializer) Option {
+	return func(c *config) {
+		if serializer != nil {
+			c.serializer = serializer
+		}
+	}
+}
+
+// WithTopics sets the topic mapping. A missing Default keeps "users.events".
+func WithTopics(topics Topics) Option {
+	return func(c *config) {
+		if topics.Default == "" {
+			topics.Default = c.topics.Default
+		}
+
+		c.topics = topics
+	}
+}
+
+// WithRetry sets the retry policy for failed publishes.
+func WithRetry(policy RetryPolicy) Option {
+	return func(c *config) {
+		c.retry = policy
+	}
+}
+
+// WithTimeout bounds a single Publish or PublishBatch call including retries.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *config) {
+		if timeout > 0 {
+			c.timeout = timeout
+		}
+	}
+}
+
+func newConfig(opts []Option) config {
+	cfg := defaultConfig()
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return cfg
+}
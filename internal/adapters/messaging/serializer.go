@@ -0,0 +1,36 @@
+// Package messaging provides EventPublisher implementations for message brokers.
+package messaging
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+)
+
+// Serializer encodes user events into message payloads.
+type Serializer interface {
+	// ContentType is the MIME type of the encoded payload.
+	ContentType() string
+	// Serialize encodes event.
+	Serialize(event *events.UserEvent) ([]byte, error)
+}
+
+// JSONSerializer encodes events as plain JSON.
+type JSONSerializer struct{}
+
+// ContentType returns the JSON MIME type.
+func (JSONSerializer) ContentType() string { return "application/json" }
+
+// Serialize encodes event as JSON.
+func (JSONSerializer) Serialize(event *events.UserEvent) ([]byte, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("event=%v: %w", event.ID, err)
+	}
+
+	return payload, nil
+}
+
+// CloudEvents payloads are produced by events.CloudEventCodec, which satisfies Serializer.
+var _ Serializer = events.CloudEventCodec{}
@@ -0,0 +1,22 @@
+package mysql
+
+import (
+	"github.com/LarsArtmann/template-sqlc/internal/adapters"
+	"github.com/LarsArtmann/template-sqlc/internal/db/shared"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// ActivityRepository implements ActivityRepository for MySQL.
+type ActivityRepository struct {
+	*adapters.NotImplementedActivityRepository
+
+	db shared.DBTX
+}
+
+// NewActivityRepository creates a new MySQL activity repository.
+func NewActivityRepository(db shared.DBTX) repositories.ActivityRepository {
+	return &ActivityRepository{
+		NotImplementedActivityRepository: adapters.NewNotImplementedActivityRepository("MySQL"),
+		db:                               db,
+	}
+}
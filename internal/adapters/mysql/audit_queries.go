@@ -0,0 +1,107 @@
+//go:build mysql
+
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/mappers"
+	mysqldb "github.com/LarsArtmann/template-sqlc/internal/db/mysql"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+)
+
+// queries returns the sqlc queries bound to the repository's database handle.
+func (r *AuditRepository) queries() *mysqldb.Queries {
+	return mysqldb.New(r.db)
+}
+
+// Record stores an audit log entry and assigns the generated ID.
+func (r *AuditRepository) Record(ctx context.Context, entry *entities.AuditLog) error {
+	changes, err := mappers.EncodeAuditChanges(entry.Changes)
+	if err != nil {
+		return fmt.Errorf("record audit action=%v user=%v: %w", entry.Action, entry.UserID, err)
+	}
+
+	result, err := r.queries().CreateAuditLog(ctx, &mysqldb.CreateAuditLogParams{
+		ActorID:   sql.NullInt64{Int64: int64(entry.ActorID), Valid: entry.ActorID != 0},
+		UserID:    uint64(entry.UserID),
+		Action:    entry.Action.String(),
+		Changes:   json.RawMessage(changes),
+		IpAddress: entry.IPAddress,
+		CreatedAt: entry.CreatedAt,
+	})
+	if err != nil {
+		return fmt.Errorf(
+			"record audit action=%v user=%v: %w",
+			entry.Action,
+			entry.UserID,
+			handleError(err, "record audit log"),
+		)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("record audit action=%v: %w", entry.Action, handleError(err, "read audit log id"))
+	}
+
+	entry.ID = entities.AuditLogID(id)
+
+	return nil
+}
+
+// List returns audit log entries matching filter, newest first.
+// The actor parameter is always non-NULL so that a zero actor matches any entry.
+func (r *AuditRepository) List(
+	ctx context.Context,
+	filter entities.AuditFilter,
+) ([]*entities.AuditLog, error) {
+	from, to := filter.TimeRange()
+
+	rows, err := r.queries().ListAuditLogs(ctx, &mysqldb.ListAuditLogsParams{
+		UserID:      uint64(filter.UserID),
+		ActorID:     sql.NullInt64{Int64: int64(filter.ActorID), Valid: true},
+		Action:      filter.Action.String(),
+		CreatedFrom: from,
+		CreatedTo:   to,
+		Limit:       int32(filter.Limit),
+		Offset:      int32(filter.Offset),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list audit user=%v: %w", filter.UserID, handleError(err, "list audit log"))
+	}
+
+	entries := make([]*entities.AuditLog, 0, len(rows))
+
+	for _, row := range rows {
+		changes, err := mappers.DecodeAuditChanges(row.Changes)
+		if err != nil {
+			return nil, fmt.Errorf("audit id=%v: %w", row.ID, err)
+		}
+
+		entries = append(entries, &entities.AuditLog{
+			ID:        entities.AuditLogID(row.ID),
+			ActorID:   entities.UserID(row.ActorID.Int64),
+			UserID:    entities.UserID(row.UserID),
+			Action:    entities.AuditAction(row.Action),
+			Changes:   changes,
+			IPAddress: row.IpAddress,
+			CreatedAt: row.CreatedAt,
+		})
+	}
+
+	return entries, nil
+}
+
+// AnonymizeUser clears the changes and IP addresses of the entries about or
+// performed by a user.
+func (r *AuditRepository) AnonymizeUser(ctx context.Context, userID entities.UserID) (int64, error) {
+	anonymized, err := r.queries().AnonymizeAuditLogs(ctx, uint64(userID))
+	if err != nil {
+		return 0, fmt.Errorf("anonymize audit user=%v: %w", userID, handleError(err, "anonymize audit log"))
+	}
+
+	return anonymized, nil
+}
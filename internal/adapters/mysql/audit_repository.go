@@ -0,0 +1,22 @@
+package mysql
+
+import (
+	"github.com/LarsArtmann/template-sqlc/internal/adapters"
+	"github.com/LarsArtmann/template-sqlc/internal/db/shared"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// AuditRepository implements AuditRepository for MySQL.
+type AuditRepository struct {
+	*adapters.NotImplementedAuditRepository
+
+	db shared.DBTX
+}
+
+// NewAuditRepository creates a new MySQL audit repository.
+func NewAuditRepository(db shared.DBTX) repositories.AuditRepository {
+	return &AuditRepository{
+		NotImplementedAuditRepository: adapters.NewNotImplementedAuditRepository("MySQL"),
+		db:                            db,
+	}
+}
@@ -0,0 +1,156 @@
+//go:build mysql
+
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/validation"
+	mysqldb "github.com/LarsArtmann/template-sqlc/internal/db/mysql"
+	"github.com/LarsArtmann/template-sqlc/internal/db/shared"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+)
+
+// Table maps an aggregate to its generated queries and row type. It is all
+// that BaseRepository needs to implement repositories.Repository.
+type Table[T any, ID ~int64, Row any] struct {
+	// Name names the aggregate in errors, such as "organization".
+	Name string
+	// NotFound is returned for missing rows, Conflict for duplicate entries.
+	NotFound error
+	Conflict error
+
+	ID       func(entity *T) ID
+	SetID    func(entity *T, id ID)
+	ToDomain func(row *Row) (*T, error)
+
+	Insert func(ctx context.Context, q *mysqldb.Queries, entity *T) (sql.Result, error)
+	Select func(ctx context.Context, q *mysqldb.Queries, id uint64) (*Row, error)
+	Update func(ctx context.Context, q *mysqldb.Queries, entity *T) (int64, error)
+	Delete func(ctx context.Context, q *mysqldb.Queries, id uint64) (int64, error)
+	List   func(ctx context.Context, q *mysqldb.Queries, limit, offset int32) ([]*Row, error)
+}
+
+// BaseRepository implements repositories.Repository for MySQL over a
+// Table: it validates pagination, converts rows, and translates errors.
+// Aggregate repositories delegate their CRUD methods to it.
+type BaseRepository[T any, ID ~int64, Row any] struct {
+	db    shared.DBTX
+	table *Table[T, ID, Row]
+}
+
+// NewBaseRepository creates a MySQL base repository for table.
+func NewBaseRepository[T any, ID ~int64, Row any](
+	db shared.DBTX,
+	table *Table[T, ID, Row],
+) *BaseRepository[T, ID, Row] {
+	return &BaseRepository[T, ID, Row]{db: db, table: table}
+}
+
+// queries returns the sqlc queries bound to the repository's database handle.
+func (r *BaseRepository[T, ID, Row]) queries() *mysqldb.Queries {
+	return mysqldb.New(r.db)
+}
+
+// Create inserts entity and assigns the generated ID.
+func (r *BaseRepository[T, ID, Row]) Create(ctx context.Context, entity *T) error {
+	result, err := r.table.Insert(ctx, r.queries(), entity)
+	if err != nil {
+		return r.translateError(err, "create")
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return r.translateError(err, "read id of")
+	}
+
+	r.table.SetID(entity, ID(id))
+
+	return nil
+}
+
+// GetByID retrieves the entity with id.
+func (r *BaseRepository[T, ID, Row]) GetByID(ctx context.Context, id ID) (*T, error) {
+	row, err := r.table.Select(ctx, r.queries(), uint64(id))
+	if err != nil {
+		return nil, fmt.Errorf("id=%v: %w", id, r.translateError(err, "get"))
+	}
+
+	return r.table.ToDomain(row)
+}
+
+// Update persists entity.
+func (r *BaseRepository[T, ID, Row]) Update(ctx context.Context, entity *T) error {
+	id := r.table.ID(entity)
+
+	affected, err := r.table.Update(ctx, r.queries(), entity)
+	if err != nil {
+		return fmt.Errorf("id=%v: %w", id, r.translateError(err, "update"))
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("update %s id=%v: %w", r.table.Name, id, r.table.NotFound)
+	}
+
+	return nil
+}
+
+// Delete removes the entity with id.
+func (r *BaseRepository[T, ID, Row]) Delete(ctx context.Context, id ID) error {
+	affected, err := r.table.Delete(ctx, r.queries(), uint64(id))
+	if err != nil {
+		return fmt.Errorf("id=%v: %w", id, r.translateError(err, "delete"))
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("delete %s id=%v: %w", r.table.Name, id, r.table.NotFound)
+	}
+
+	return nil
+}
+
+// List returns up to limit entities after skipping offset, ordered by ID.
+func (r *BaseRepository[T, ID, Row]) List(ctx context.Context, limit, offset int) ([]*T, error) {
+	err := validation.ValidatePagination(limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("limit=%v offset=%v: %w", limit, offset, err)
+	}
+
+	rows, err := r.table.List(ctx, r.queries(), int32(limit), int32(offset))
+	if err != nil {
+		return nil, r.translateError(err, "list")
+	}
+
+	return r.domainRows(rows)
+}
+
+// domainRows converts generated rows into domain entities.
+func (r *BaseRepository[T, ID, Row]) domainRows(rows []*Row) ([]*T, error) {
+	items := make([]*T, 0, len(rows))
+
+	for _, row := range rows {
+		entity, err := r.table.ToDomain(row)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, entity)
+	}
+
+	return items, nil
+}
+
+// translateError maps database errors of operation to the errors of the aggregate.
+func (r *BaseRepository[T, ID, Row]) translateError(err error, operation string) error {
+	operation += " " + r.table.Name
+
+	return fmt.Errorf("%s: %w", operation, mysqldb.HandleDBError(
+		err,
+		operation,
+		r.table.NotFound,
+		r.table.Conflict,
+		entities.ErrInvalidReference,
+	))
+}
@@ -0,0 +1,46 @@
+//go:build mysql
+
+package mysql
+
+import (
+	"context"
+	"fmt"
+
+	mysqldb "github.com/LarsArtmann/template-sqlc/internal/db/mysql"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+)
+
+// queries returns the sqlc queries bound to the repository's database handle.
+func (r *FeatureFlagRepository) queries() *mysqldb.Queries {
+	return mysqldb.New(r.db)
+}
+
+// List returns the stored flags ordered by name.
+func (r *FeatureFlagRepository) List(ctx context.Context) ([]*entities.FeatureFlag, error) {
+	rows, err := r.queries().ListFeatureFlags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("feature flags: %w", handleError(err, "list feature flags"))
+	}
+
+	flags := make([]*entities.FeatureFlag, 0, len(rows))
+
+	for _, row := range rows {
+		flags = append(flags, &entities.FeatureFlag{Name: row.Name, Enabled: row.Enabled, UpdatedAt: row.UpdatedAt})
+	}
+
+	return flags, nil
+}
+
+// Set stores the flag, replacing the one of the same name.
+func (r *FeatureFlagRepository) Set(ctx context.Context, flag *entities.FeatureFlag) error {
+	err := r.queries().SetFeatureFlag(ctx, &mysqldb.SetFeatureFlagParams{
+		Name:      flag.Name,
+		Enabled:   flag.Enabled,
+		UpdatedAt: flag.UpdatedAt.UTC(),
+	})
+	if err != nil {
+		return fmt.Errorf("feature flag name=%v: %w", flag.Name, handleError(err, "set feature flag"))
+	}
+
+	return nil
+}
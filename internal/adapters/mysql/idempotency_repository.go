@@ -0,0 +1,22 @@
+package mysql
+
+import (
+	"github.com/LarsArtmann/template-sqlc/internal/adapters"
+	"github.com/LarsArtmann/template-sqlc/internal/db/shared"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// IdempotencyRepository implements IdempotencyRepository for MySQL.
+type IdempotencyRepository struct {
+	*adapters.NotImplementedIdempotencyRepository
+
+	db shared.DBTX
+}
+
+// NewIdempotencyRepository creates a new MySQL idempotency repository.
+func NewIdempotencyRepository(db shared.DBTX) repositories.IdempotencyRepository {
+	return &IdempotencyRepository{
+		NotImplementedIdempotencyRepository: adapters.NewNotImplementedIdempotencyRepository("MySQL"),
+		db:                                  db,
+	}
+}
@@ -0,0 +1,147 @@
+//go:build mysql
+
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	mysqldb "github.com/LarsArtmann/template-sqlc/internal/db/mysql"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	apperrors "github.com/LarsArtmann/template-sqlc/pkg/errors"
+	"github.com/google/uuid"
+)
+
+// queries returns the sqlc queries bound to the repository's database handle.
+func (r *IdentityChangeRepository) queries() *mysqldb.Queries {
+	return mysqldb.New(r.db)
+}
+
+// Create records a change and assigns the generated ID.
+func (r *IdentityChangeRepository) Create(ctx context.Context, change *entities.IdentityChange) error {
+	result, err := r.queries().CreateIdentityChange(ctx, &mysqldb.CreateIdentityChangeParams{
+		UserID:    uint64(change.UserID),
+		Field:     change.Field.String(),
+		OldValue:  change.OldValue,
+		NewValue:  change.NewValue,
+		Status:    change.Status.String(),
+		Token:     change.Token.String(),
+		ExpiresAt: change.ExpiresAt,
+		CreatedAt: change.CreatedAt,
+		UpdatedAt: change.UpdatedAt,
+	})
+	if err != nil {
+		return fmt.Errorf(
+			"record identity change field=%v user=%v: %w",
+			change.Field,
+			change.UserID,
+			handleIdentityChangeError(err, "record identity change"),
+		)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf(
+			"record identity change field=%v: %w",
+			change.Field,
+			handleIdentityChangeError(err, "read identity change id"),
+		)
+	}
+
+	change.ID = entities.IdentityChangeID(id)
+
+	return nil
+}
+
+// GetByToken retrieves the change with token.
+func (r *IdentityChangeRepository) GetByToken(
+	ctx context.Context,
+	token entities.IdentityChangeToken,
+) (*entities.IdentityChange, error) {
+	row, err := r.queries().GetIdentityChangeByToken(ctx, token.String())
+	if err != nil {
+		return nil, handleIdentityChangeError(err, "get identity change")
+	}
+
+	return domainIdentityChange(row)
+}
+
+// Update persists the status, token and expiry of a change.
+func (r *IdentityChangeRepository) Update(ctx context.Context, change *entities.IdentityChange) error {
+	affected, err := r.queries().UpdateIdentityChange(ctx, &mysqldb.UpdateIdentityChangeParams{
+		Status:    change.Status.String(),
+		Token:     change.Token.String(),
+		ExpiresAt: change.ExpiresAt,
+		UpdatedAt: change.UpdatedAt,
+		ID:        uint64(change.ID),
+	})
+	if err != nil {
+		return fmt.Errorf("identity change id=%d: %w", change.ID, handleIdentityChangeError(err, "update identity change"))
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("identity change id=%d: %w", change.ID, entities.ErrIdentityChangeNotFound)
+	}
+
+	return nil
+}
+
+// ListByUser returns the changes of a user, newest first.
+func (r *IdentityChangeRepository) ListByUser(
+	ctx context.Context,
+	userID entities.UserID,
+) ([]*entities.IdentityChange, error) {
+	rows, err := r.queries().ListIdentityChanges(ctx, uint64(userID))
+	if err != nil {
+		return nil, fmt.Errorf("user=%v: %w", userID, handleIdentityChangeError(err, "list identity changes"))
+	}
+
+	return scanAll[*entities.IdentityChange](rows)
+}
+
+// DeleteByUser removes the changes of a user.
+func (r *IdentityChangeRepository) DeleteByUser(ctx context.Context, userID entities.UserID) (int64, error) {
+	deleted, err := r.queries().DeleteIdentityChangesByUser(ctx, uint64(userID))
+	if err != nil {
+		return 0, fmt.Errorf("user=%v: %w", userID, handleIdentityChangeError(err, "delete identity changes"))
+	}
+
+	return deleted, nil
+}
+
+// domainIdentityChange converts a generated identity_changes row into a domain entity.
+func domainIdentityChange(row *mysqldb.IdentityChanges) (*entities.IdentityChange, error) {
+	token, err := uuid.Parse(row.Token)
+	if err != nil {
+		return nil, fmt.Errorf("identity change id=%d: parse token: %w", row.ID, err)
+	}
+
+	return &entities.IdentityChange{
+		ID:        entities.IdentityChangeID(row.ID),
+		UserID:    entities.UserID(row.UserID),
+		Field:     entities.IdentityField(row.Field),
+		OldValue:  row.OldValue,
+		NewValue:  row.NewValue,
+		Status:    entities.IdentityChangeStatus(row.Status),
+		Token:     entities.IdentityChangeToken(token),
+		ExpiresAt: row.ExpiresAt,
+		CreatedAt: row.CreatedAt,
+		UpdatedAt: row.UpdatedAt,
+	}, nil
+}
+
+// handleIdentityChangeError maps database errors for identity change queries
+// to domain errors.
+func handleIdentityChangeError(err error, operation string) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return entities.ErrIdentityChangeNotFound
+	}
+
+	if mysqldb.IsMySQLForeignKeyError(err) {
+		return entities.ErrInvalidReference
+	}
+
+	return apperrors.NewDatabaseError(operation+" failed", err)
+}
@@ -0,0 +1,22 @@
+package mysql
+
+import (
+	"github.com/LarsArtmann/template-sqlc/internal/adapters"
+	"github.com/LarsArtmann/template-sqlc/internal/db/shared"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// IdentityChangeRepository implements IdentityChangeRepository for MySQL.
+type IdentityChangeRepository struct {
+	*adapters.NotImplementedIdentityChangeRepository
+
+	db shared.DBTX
+}
+
+// NewIdentityChangeRepository creates a new MySQL identity change repository.
+func NewIdentityChangeRepository(db shared.DBTX) repositories.IdentityChangeRepository {
+	return &IdentityChangeRepository{
+		NotImplementedIdentityChangeRepository: adapters.NewNotImplementedIdentityChangeRepository("MySQL"),
+		db:                                     db,
+	}
+}
@@ -0,0 +1,142 @@
+//go:build mysql
+
+package mysql
+
+import (
+	"context"
+	"fmt"
+
+	mysqldb "github.com/LarsArtmann/template-sqlc/internal/db/mysql"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+)
+
+// queries returns the sqlc queries bound to the repository's database handle.
+func (r *IdentityRepository) queries() *mysqldb.Queries {
+	return mysqldb.New(r.db)
+}
+
+// Create links an identity and assigns the generated ID.
+func (r *IdentityRepository) Create(ctx context.Context, identity *entities.UserIdentity) error {
+	result, err := r.queries().CreateUserIdentity(ctx, &mysqldb.CreateUserIdentityParams{
+		UserID:   uint64(identity.UserID),
+		Provider: identity.Provider.String(),
+		Subject:  identity.Subject,
+		Email:    identity.Email,
+		LinkedAt: identity.LinkedAt,
+	})
+	if err != nil {
+		return fmt.Errorf(
+			"link identity provider=%v user=%v: %w",
+			identity.Provider,
+			identity.UserID,
+			handleIdentityError(err, "link identity"),
+		)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("link identity provider=%v: %w", identity.Provider, handleIdentityError(err, "read identity id"))
+	}
+
+	identity.ID = entities.UserIdentityID(id)
+
+	return nil
+}
+
+// GetByProviderSubject retrieves the identity of subject at provider.
+func (r *IdentityRepository) GetByProviderSubject(
+	ctx context.Context,
+	provider entities.IdentityProvider,
+	subject string,
+) (*entities.UserIdentity, error) {
+	row, err := r.queries().GetUserIdentity(ctx, &mysqldb.GetUserIdentityParams{
+		Provider: provider.String(),
+		Subject:  subject,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("provider=%v: %w", provider, handleIdentityError(err, "get identity"))
+	}
+
+	return domainIdentity(row)
+}
+
+// RecordLogin persists the identity's last login time and email.
+func (r *IdentityRepository) RecordLogin(ctx context.Context, identity *entities.UserIdentity) error {
+	affected, err := r.queries().RecordIdentityLogin(ctx, &mysqldb.RecordIdentityLoginParams{
+		Email:       identity.Email,
+		LastLoginAt: nullTime.DomainToDB(identity.LastLoginAt),
+		ID:          uint64(identity.ID),
+	})
+	if err != nil {
+		return fmt.Errorf("identity id=%d: %w", identity.ID, handleIdentityError(err, "record identity login"))
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("identity id=%d: %w", identity.ID, entities.ErrIdentityNotFound)
+	}
+
+	return nil
+}
+
+// ListByUser returns the identities linked to a user, ordered by provider.
+func (r *IdentityRepository) ListByUser(
+	ctx context.Context,
+	userID entities.UserID,
+) ([]*entities.UserIdentity, error) {
+	rows, err := r.queries().ListUserIdentities(ctx, uint64(userID))
+	if err != nil {
+		return nil, fmt.Errorf("user=%v: %w", userID, handleIdentityError(err, "list identities"))
+	}
+
+	return scanAll[*entities.UserIdentity](rows)
+}
+
+// Delete unlinks the user's identity at provider.
+func (r *IdentityRepository) Delete(
+	ctx context.Context,
+	userID entities.UserID,
+	provider entities.IdentityProvider,
+) error {
+	affected, err := r.queries().DeleteUserIdentity(ctx, &mysqldb.DeleteUserIdentityParams{
+		UserID:   uint64(userID),
+		Provider: provider.String(),
+	})
+	if err != nil {
+		return fmt.Errorf("unlink provider=%v user=%v: %w", provider, userID, handleIdentityError(err, "unlink identity"))
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("unlink provider=%v user=%v: %w", provider, userID, entities.ErrIdentityNotFound)
+	}
+
+	return nil
+}
+
+// domainIdentity converts a generated user_identities row into a domain entity.
+func domainIdentity(row *mysqldb.UserIdentities) (*entities.UserIdentity, error) {
+	lastLoginAt, err := nullTime.DBToDomain(row.LastLoginAt)
+	if err != nil {
+		return nil, fmt.Errorf("identity id=%d: %w", row.ID, err)
+	}
+
+	return &entities.UserIdentity{
+		ID:          entities.UserIdentityID(row.ID),
+		UserID:      entities.UserID(row.UserID),
+		Provider:    entities.IdentityProvider(row.Provider),
+		Subject:     row.Subject,
+		Email:       row.Email,
+		LinkedAt:    row.LinkedAt,
+		LastLoginAt: lastLoginAt,
+	}, nil
+}
+
+// handleIdentityError maps database errors for identity queries to domain errors.
+func handleIdentityError(err error, operation string) error {
+	return mysqldb.HandleDBError(
+		err,
+		operation,
+		entities.ErrIdentityNotFound,
+		entities.ErrIdentityAlreadyLinked,
+		entities.ErrInvalidReference,
+	)
+}
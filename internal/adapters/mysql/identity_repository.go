@@ -0,0 +1,22 @@
+package mysql
+
+import (
+	"github.com/LarsArtmann/template-sqlc/internal/adapters"
+	"github.com/LarsArtmann/template-sqlc/internal/db/shared"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// IdentityRepository implements IdentityRepository for MySQL.
+type IdentityRepository struct {
+	*adapters.NotImplementedIdentityRepository
+
+	db shared.DBTX
+}
+
+// NewIdentityRepository creates a new MySQL identity repository.
+func NewIdentityRepository(db shared.DBTX) repositories.IdentityRepository {
+	return &IdentityRepository{
+		NotImplementedIdentityRepository: adapters.NewNotImplementedIdentityRepository("MySQL"),
+		db:                               db,
+	}
+}
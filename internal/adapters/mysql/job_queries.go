@@ -0,0 +1,214 @@
+//go:build mysql
+
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	mysqldb "github.com/LarsArtmann/template-sqlc/internal/db/mysql"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	apperrors "github.com/LarsArtmann/template-sqlc/pkg/errors"
+)
+
+// queries returns the sqlc queries bound to the repository's database handle.
+func (r *JobRepository) queries() *mysqldb.Queries {
+	return mysqldb.New(r.db)
+}
+
+// Enqueue stores a pending job and assigns the generated ID.
+func (r *JobRepository) Enqueue(ctx context.Context, job *entities.Job) error {
+	result, err := r.queries().EnqueueJob(ctx, &mysqldb.EnqueueJobParams{
+		Name:        job.Name,
+		Payload:     json.RawMessage(job.Payload),
+		MaxAttempts: int32(job.MaxAttempts),
+		RunAt:       job.RunAt.UTC(),
+		CreatedAt:   job.CreatedAt.UTC(),
+	})
+	if err != nil {
+		return fmt.Errorf("enqueue job name=%v: %w", job.Name, handleJobError(err, "enqueue job"))
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("enqueue job name=%v: %w", job.Name, handleJobError(err, "read job id"))
+	}
+
+	job.ID = entities.JobID(id)
+
+	return nil
+}
+
+// Get retrieves a job by ID.
+func (r *JobRepository) Get(ctx context.Context, id entities.JobID) (*entities.Job, error) {
+	row, err := r.queries().GetJob(ctx, uint64(id))
+	if err != nil {
+		return nil, fmt.Errorf("job id=%d: %w", id, handleJobError(err, "get job"))
+	}
+
+	return domainJob(row)
+}
+
+// Claim polls for due jobs and claims each with a conditional update.
+// Jobs another worker claims in between are skipped.
+func (r *JobRepository) Claim(
+	ctx context.Context,
+	now time.Time,
+	lease time.Duration,
+	limit int,
+) ([]*entities.Job, error) {
+	now = now.UTC()
+	lockedUntil := now.Add(lease)
+
+	rows, err := r.queries().ListDueJobs(ctx, &mysqldb.ListDueJobsParams{
+		Now:   now,
+		Limit: int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("claim jobs: %w", handleJobError(err, "list due jobs"))
+	}
+
+	claimed := make([]*entities.Job, 0, len(rows))
+
+	for _, row := range rows {
+		affected, err := r.queries().ClaimJob(ctx, &mysqldb.ClaimJobParams{
+			LockedUntil: sql.NullTime{Time: lockedUntil, Valid: true},
+			Now:         now,
+			ID:          row.ID,
+			Status:      row.Status,
+			Attempts:    row.Attempts,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("claim job id=%d: %w", row.ID, handleJobError(err, "claim job"))
+		}
+
+		if affected == 0 {
+			continue
+		}
+
+		job, err := domainJob(row)
+		if err != nil {
+			return nil, err
+		}
+
+		job.Claim(now, lockedUntil)
+		claimed = append(claimed, job)
+	}
+
+	return claimed, nil
+}
+
+// Complete marks a claimed job done.
+func (r *JobRepository) Complete(ctx context.Context, job *entities.Job) error {
+	now := time.Now().UTC()
+
+	affected, err := r.queries().CompleteJob(ctx, &mysqldb.CompleteJobParams{
+		Now:      now,
+		ID:       uint64(job.ID),
+		Attempts: int32(job.Attempts),
+	})
+	if err != nil {
+		return fmt.Errorf("job id=%d: %w", job.ID, handleJobError(err, "complete job"))
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("job id=%d: %w", job.ID, entities.ErrJobLeaseLost)
+	}
+
+	job.Complete(now)
+
+	return nil
+}
+
+// Retry returns a claimed job to the queue, due again at runAt.
+func (r *JobRepository) Retry(ctx context.Context, job *entities.Job, runAt time.Time, lastError string) error {
+	now := time.Now().UTC()
+
+	affected, err := r.queries().RetryJob(ctx, &mysqldb.RetryJobParams{
+		RunAt:     runAt.UTC(),
+		LastError: lastError,
+		Now:       now,
+		ID:        uint64(job.ID),
+		Attempts:  int32(job.Attempts),
+	})
+	if err != nil {
+		return fmt.Errorf("job id=%d: %w", job.ID, handleJobError(err, "retry job"))
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("job id=%d: %w", job.ID, entities.ErrJobLeaseLost)
+	}
+
+	job.Reschedule(runAt.UTC(), lastError, now)
+
+	return nil
+}
+
+// Fail marks a claimed job failed.
+func (r *JobRepository) Fail(ctx context.Context, job *entities.Job, lastError string) error {
+	now := time.Now().UTC()
+
+	affected, err := r.queries().FailJob(ctx, &mysqldb.FailJobParams{
+		LastError: lastError,
+		Now:       now,
+		ID:        uint64(job.ID),
+		Attempts:  int32(job.Attempts),
+	})
+	if err != nil {
+		return fmt.Errorf("job id=%d: %w", job.ID, handleJobError(err, "fail job"))
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("job id=%d: %w", job.ID, entities.ErrJobLeaseLost)
+	}
+
+	job.Fail(lastError, now)
+
+	return nil
+}
+
+// DeleteDone removes the done jobs finished before cutoff.
+func (r *JobRepository) DeleteDone(ctx context.Context, cutoff time.Time) (int64, error) {
+	deleted, err := r.queries().DeleteDoneJobs(ctx, cutoff.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("delete done jobs: %w", handleJobError(err, "delete done jobs"))
+	}
+
+	return deleted, nil
+}
+
+// domainJob converts a generated jobs row into a domain entity.
+func domainJob(row *mysqldb.Jobs) (*entities.Job, error) {
+	lockedUntil, err := nullTime.DBToDomain(row.LockedUntil)
+	if err != nil {
+		return nil, fmt.Errorf("job id=%d: %w", row.ID, err)
+	}
+
+	return &entities.Job{
+		ID:          entities.JobID(row.ID),
+		Name:        row.Name,
+		Payload:     []byte(row.Payload),
+		Status:      entities.JobStatus(row.Status),
+		Attempts:    int(row.Attempts),
+		MaxAttempts: int(row.MaxAttempts),
+		RunAt:       row.RunAt,
+		LockedUntil: lockedUntil,
+		LastError:   row.LastError,
+		CreatedAt:   row.CreatedAt,
+		UpdatedAt:   row.UpdatedAt,
+	}, nil
+}
+
+// handleJobError maps database errors for job queries to domain errors.
+// The jobs table has no unique or foreign keys, so only missing rows are mapped.
+func handleJobError(err error, operation string) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return entities.ErrJobNotFound
+	}
+
+	return apperrors.NewDatabaseError(operation+" failed", err)
+}
@@ -0,0 +1,22 @@
+package mysql
+
+import (
+	"github.com/LarsArtmann/template-sqlc/internal/adapters"
+	"github.com/LarsArtmann/template-sqlc/internal/db/shared"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// JobRepository implements JobRepository for MySQL.
+type JobRepository struct {
+	*adapters.NotImplementedJobRepository
+
+	db shared.DBTX
+}
+
+// NewJobRepository creates a new MySQL job repository.
+func NewJobRepository(db shared.DBTX) repositories.JobRepository {
+	return &JobRepository{
+		NotImplementedJobRepository: adapters.NewNotImplementedJobRepository("MySQL"),
+		db:                          db,
+	}
+}
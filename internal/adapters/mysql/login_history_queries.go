@@ -0,0 +1,135 @@
+//go:build mysql
+
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/validation"
+	mysqldb "github.com/LarsArtmann/template-sqlc/internal/db/mysql"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	apperrors "github.com/LarsArtmann/template-sqlc/pkg/errors"
+)
+
+// queries returns the sqlc queries bound to the repository's database handle.
+func (r *LoginHistoryRepository) queries() *mysqldb.Queries {
+	return mysqldb.New(r.db)
+}
+
+// Record stores attempt and assigns its ID.
+func (r *LoginHistoryRepository) Record(ctx context.Context, attempt *entities.LoginAttempt) error {
+	result, err := r.queries().RecordLoginAttempt(ctx, &mysqldb.RecordLoginAttemptParams{
+		UserID:    sql.NullInt64{Int64: int64(attempt.UserID), Valid: attempt.UserID != 0},
+		IpAddress: attempt.IPAddress,
+		UserAgent: attempt.UserAgent,
+		Method:    attempt.Method,
+		Failure:   attempt.Failure.String(),
+		CreatedAt: attempt.CreatedAt.UTC(),
+	})
+	if err != nil {
+		return fmt.Errorf("user=%v: %w", attempt.UserID, handleLoginHistoryError(err, "record login attempt"))
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("user=%v: %w", attempt.UserID, handleLoginHistoryError(err, "read login attempt id"))
+	}
+
+	attempt.ID = entities.LoginAttemptID(id)
+
+	return nil
+}
+
+// ListByUser returns the last limit attempts of a user, newest first.
+func (r *LoginHistoryRepository) ListByUser(
+	ctx context.Context,
+	userID entities.UserID,
+	limit int,
+) ([]*entities.LoginAttempt, error) {
+	err := validation.ValidatePagination(limit, 0)
+	if err != nil {
+		return nil, fmt.Errorf("limit=%v: %w", limit, err)
+	}
+
+	rows, err := r.queries().ListLoginAttemptsByUser(ctx, &mysqldb.ListLoginAttemptsByUserParams{
+		UserID: sql.NullInt64{Int64: int64(userID), Valid: true},
+		Limit:  int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("user=%v: %w", userID, handleLoginHistoryError(err, "list login attempts"))
+	}
+
+	return domainLoginAttempts(rows), nil
+}
+
+// ListFailuresByIP returns the last limit failed attempts from ipAddress
+// since the given time, newest first, including those of unknown accounts.
+func (r *LoginHistoryRepository) ListFailuresByIP(
+	ctx context.Context,
+	ipAddress string,
+	since time.Time,
+	limit int,
+) ([]*entities.LoginAttempt, error) {
+	err := validation.ValidatePagination(limit, 0)
+	if err != nil {
+		return nil, fmt.Errorf("limit=%v: %w", limit, err)
+	}
+
+	rows, err := r.queries().ListLoginFailuresByIP(ctx, &mysqldb.ListLoginFailuresByIPParams{
+		IpAddress: ipAddress,
+		Since:     since.UTC(),
+		Limit:     int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ip=%v: %w", ipAddress, handleLoginHistoryError(err, "list login failures"))
+	}
+
+	return domainLoginAttempts(rows), nil
+}
+
+// DeleteByUser removes the attempts of a user; those of unknown accounts
+// are kept.
+func (r *LoginHistoryRepository) DeleteByUser(ctx context.Context, userID entities.UserID) (int64, error) {
+	deleted, err := r.queries().DeleteLoginAttemptsByUser(ctx, sql.NullInt64{Int64: int64(userID), Valid: true})
+	if err != nil {
+		return 0, fmt.Errorf("user=%v: %w", userID, handleLoginHistoryError(err, "delete login attempts"))
+	}
+
+	return deleted, nil
+}
+
+// domainLoginAttempts converts generated login_history rows into domain entities.
+func domainLoginAttempts(rows []*mysqldb.LoginHistory) []*entities.LoginAttempt {
+	attempts := make([]*entities.LoginAttempt, 0, len(rows))
+
+	for _, row := range rows {
+		var userID entities.UserID
+		if row.UserID.Valid {
+			userID = entities.UserID(row.UserID.Int64)
+		}
+
+		attempts = append(attempts, &entities.LoginAttempt{
+			ID:        entities.LoginAttemptID(row.ID),
+			UserID:    userID,
+			IPAddress: row.IpAddress,
+			UserAgent: row.UserAgent,
+			Method:    row.Method,
+			Failure:   entities.LoginFailure(row.Failure),
+			CreatedAt: row.CreatedAt,
+		})
+	}
+
+	return attempts
+}
+
+// handleLoginHistoryError maps database errors for login history queries to domain errors.
+func handleLoginHistoryError(err error, operation string) error {
+	if mysqldb.IsMySQLForeignKeyError(err) {
+		return entities.ErrInvalidReference
+	}
+
+	return apperrors.NewDatabaseError(operation+" failed", err)
+}
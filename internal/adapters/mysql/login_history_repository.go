@@ -0,0 +1,22 @@
+package mysql
+
+import (
+	"github.com/LarsArtmann/template-sqlc/internal/adapters"
+	"github.com/LarsArtmann/template-sqlc/internal/db/shared"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// LoginHistoryRepository implements LoginHistoryRepository for MySQL.
+type LoginHistoryRepository struct {
+	*adapters.NotImplementedLoginHistoryRepository
+
+	db shared.DBTX
+}
+
+// NewLoginHistoryRepository creates a new MySQL login history repository.
+func NewLoginHistoryRepository(db shared.DBTX) repositories.LoginHistoryRepository {
+	return &LoginHistoryRepository{
+		NotImplementedLoginHistoryRepository: adapters.NewNotImplementedLoginHistoryRepository("MySQL"),
+		db:                                   db,
+	}
+}
@@ -0,0 +1,280 @@
+//go:build mysql
+
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	mysqldb "github.com/LarsArtmann/template-sqlc/internal/db/mysql"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+)
+
+// queries returns the sqlc queries bound to the repository's database handle.
+func (r *ModerationRepository) queries() *mysqldb.Queries {
+	return mysqldb.New(r.db)
+}
+
+// CreateAction records an action and assigns the generated ID.
+func (r *ModerationRepository) CreateAction(ctx context.Context, action *entities.ModerationAction) error {
+	result, err := r.queries().CreateModerationAction(ctx, &mysqldb.CreateModerationActionParams{
+		UserID:      uint64(action.UserID),
+		Kind:        action.Kind.String(),
+		Reason:      action.Reason,
+		ModeratorID: uint64(action.ModeratorID),
+		EndsAt:      nullTime.DomainToDB(action.EndsAt),
+		CreatedAt:   action.CreatedAt,
+	})
+	if err != nil {
+		return fmt.Errorf(
+			"record %v user=%v: %w",
+			action.Kind,
+			action.UserID,
+			handleModerationError(err, "record moderation action", entities.ErrModerationActionNotFound),
+		)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("record %v user=%v: %w", action.Kind, action.UserID,
+			handleModerationError(err, "read moderation action id", entities.ErrModerationActionNotFound))
+	}
+
+	action.ID = entities.ModerationActionID(id)
+
+	return nil
+}
+
+// GetAction retrieves the action with id.
+func (r *ModerationRepository) GetAction(
+	ctx context.Context,
+	id entities.ModerationActionID,
+) (*entities.ModerationAction, error) {
+	row, err := r.queries().GetModerationAction(ctx, uint64(id))
+	if err != nil {
+		return nil, fmt.Errorf("moderation action id=%d: %w", id,
+			handleModerationError(err, "get moderation action", entities.ErrModerationActionNotFound))
+	}
+
+	return domainModerationAction(row)
+}
+
+// LiftAction stores when and by whom an action was lifted, unless it was
+// lifted before.
+func (r *ModerationRepository) LiftAction(ctx context.Context, action *entities.ModerationAction) error {
+	affected, err := r.queries().LiftModerationAction(ctx, &mysqldb.LiftModerationActionParams{
+		LiftedAt: nullTime.DomainToDB(action.LiftedAt),
+		LiftedBy: uint64(action.LiftedBy),
+		ID:       uint64(action.ID),
+	})
+	if err != nil {
+		return fmt.Errorf("moderation action id=%d: %w", action.ID,
+			handleModerationError(err, "lift moderation action", entities.ErrModerationActionNotFound))
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("moderation action id=%d: %w", action.ID, entities.ErrModerationActionNotFound)
+	}
+
+	return nil
+}
+
+// GetActiveSuspension retrieves the newest suspension of a user that was not lifted.
+func (r *ModerationRepository) GetActiveSuspension(
+	ctx context.Context,
+	userID entities.UserID,
+) (*entities.ModerationAction, error) {
+	row, err := r.queries().GetActiveSuspension(ctx, uint64(userID))
+	if err != nil {
+		return nil, fmt.Errorf("user=%v: %w", userID,
+			handleModerationError(err, "get active suspension", entities.ErrNotSuspended))
+	}
+
+	return domainModerationAction(row)
+}
+
+// ListActionsByUser returns the actions against a user, newest first.
+func (r *ModerationRepository) ListActionsByUser(
+	ctx context.Context,
+	userID entities.UserID,
+) ([]*entities.ModerationAction, error) {
+	rows, err := r.queries().ListModerationActions(ctx, uint64(userID))
+	if err != nil {
+		return nil, fmt.Errorf("user=%v: %w", userID,
+			handleModerationError(err, "list moderation actions", entities.ErrModerationActionNotFound))
+	}
+
+	return scanAll[*entities.ModerationAction](rows)
+}
+
+// ListLapsedSuspensions returns up to limit suspensions that ended by now
+// but were not lifted yet, the earliest ended first.
+func (r *ModerationRepository) ListLapsedSuspensions(
+	ctx context.Context,
+	now time.Time,
+	limit int,
+) ([]*entities.ModerationAction, error) {
+	end := now.UTC()
+
+	rows, err := r.queries().ListLapsedSuspensions(ctx, &mysqldb.ListLapsedSuspensionsParams{
+		Now:   nullTime.DomainToDB(&end),
+		Limit: int32(limit),
+	})
+	if err != nil {
+		return nil, handleModerationError(err, "list lapsed suspensions", entities.ErrModerationActionNotFound)
+	}
+
+	return scanAll[*entities.ModerationAction](rows)
+}
+
+// CreateAppeal records an appeal and assigns the generated ID.
+func (r *ModerationRepository) CreateAppeal(ctx context.Context, appeal *entities.Appeal) error {
+	result, err := r.queries().CreateModerationAppeal(ctx, &mysqldb.CreateModerationAppealParams{
+		ActionID:  uint64(appeal.ActionID),
+		UserID:    uint64(appeal.UserID),
+		Statement: appeal.Statement,
+		Status:    appeal.Status.String(),
+		CreatedAt: appeal.CreatedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("appeal of moderation action id=%d: %w", appeal.ActionID,
+			handleModerationError(err, "record appeal", entities.ErrAppealNotFound))
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("appeal of moderation action id=%d: %w", appeal.ActionID,
+			handleModerationError(err, "read appeal id", entities.ErrAppealNotFound))
+	}
+
+	appeal.ID = entities.AppealID(id)
+
+	return nil
+}
+
+// GetAppeal retrieves the appeal with id.
+func (r *ModerationRepository) GetAppeal(ctx context.Context, id entities.AppealID) (*entities.Appeal, error) {
+	row, err := r.queries().GetModerationAppeal(ctx, uint64(id))
+	if err != nil {
+		return nil, fmt.Errorf("appeal id=%d: %w", id,
+			handleModerationError(err, "get appeal", entities.ErrAppealNotFound))
+	}
+
+	return domainAppeal(row)
+}
+
+// DecideAppeal stores the decision of an appeal that was not decided before.
+func (r *ModerationRepository) DecideAppeal(ctx context.Context, appeal *entities.Appeal) error {
+	affected, err := r.queries().DecideModerationAppeal(ctx, &mysqldb.DecideModerationAppealParams{
+		Status:     appeal.Status.String(),
+		ReviewerID: uint64(appeal.ReviewerID),
+		Response:   appeal.Response,
+		DecidedAt:  nullTime.DomainToDB(appeal.DecidedAt),
+		ID:         uint64(appeal.ID),
+	})
+	if err != nil {
+		return fmt.Errorf("appeal id=%d: %w", appeal.ID,
+			handleModerationError(err, "decide appeal", entities.ErrAppealNotFound))
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("appeal id=%d: %w", appeal.ID, entities.ErrAppealNotFound)
+	}
+
+	return nil
+}
+
+// ListPendingAppeals returns up to limit undecided appeals, oldest first.
+func (r *ModerationRepository) ListPendingAppeals(ctx context.Context, limit int) ([]*entities.Appeal, error) {
+	rows, err := r.queries().ListPendingModerationAppeals(ctx, int32(limit))
+	if err != nil {
+		return nil, handleModerationError(err, "list pending appeals", entities.ErrAppealNotFound)
+	}
+
+	return scanAll[*entities.Appeal](rows)
+}
+
+// ListAppealsByUser returns the appeals of a user, newest first.
+func (r *ModerationRepository) ListAppealsByUser(
+	ctx context.Context,
+	userID entities.UserID,
+) ([]*entities.Appeal, error) {
+	rows, err := r.queries().ListModerationAppealsByUser(ctx, uint64(userID))
+	if err != nil {
+		return nil, fmt.Errorf("user=%v: %w", userID,
+			handleModerationError(err, "list moderation appeals", entities.ErrAppealNotFound))
+	}
+
+	return scanAll[*entities.Appeal](rows)
+}
+
+// DeleteByUser removes the actions against a user and the appeals the user
+// made. The appeals are deleted first rather than left to the foreign key
+// cascade, which SQLite only enforces when enabled on the connection.
+func (r *ModerationRepository) DeleteByUser(ctx context.Context, userID entities.UserID) (int64, error) {
+	_, err := r.queries().DeleteModerationAppealsByUser(ctx, uint64(userID))
+	if err != nil {
+		return 0, fmt.Errorf("user=%v: %w", userID,
+			handleModerationError(err, "delete moderation appeals", entities.ErrAppealNotFound))
+	}
+
+	deleted, err := r.queries().DeleteModerationActionsByUser(ctx, uint64(userID))
+	if err != nil {
+		return 0, fmt.Errorf("user=%v: %w", userID,
+			handleModerationError(err, "delete moderation actions", entities.ErrModerationActionNotFound))
+	}
+
+	return deleted, nil
+}
+
+// domainModerationAction converts a generated moderation_actions row into a domain entity.
+func domainModerationAction(row *mysqldb.ModerationActions) (*entities.ModerationAction, error) {
+	endsAt, err := nullTime.DBToDomain(row.EndsAt)
+	if err != nil {
+		return nil, fmt.Errorf("moderation action id=%d: %w", row.ID, err)
+	}
+
+	liftedAt, err := nullTime.DBToDomain(row.LiftedAt)
+	if err != nil {
+		return nil, fmt.Errorf("moderation action id=%d: %w", row.ID, err)
+	}
+
+	return &entities.ModerationAction{
+		ID:          entities.ModerationActionID(row.ID),
+		UserID:      entities.UserID(row.UserID),
+		Kind:        entities.ModerationKind(row.Kind),
+		Reason:      row.Reason,
+		ModeratorID: entities.UserID(row.ModeratorID),
+		EndsAt:      endsAt,
+		LiftedAt:    liftedAt,
+		LiftedBy:    entities.UserID(row.LiftedBy),
+		CreatedAt:   row.CreatedAt,
+	}, nil
+}
+
+// domainAppeal converts a generated moderation_appeals row into a domain entity.
+func domainAppeal(row *mysqldb.ModerationAppeals) (*entities.Appeal, error) {
+	decidedAt, err := nullTime.DBToDomain(row.DecidedAt)
+	if err != nil {
+		return nil, fmt.Errorf("appeal id=%d: %w", row.ID, err)
+	}
+
+	return &entities.Appeal{
+		ID:         entities.AppealID(row.ID),
+		ActionID:   entities.ModerationActionID(row.ActionID),
+		UserID:     entities.UserID(row.UserID),
+		Statement:  row.Statement,
+		Status:     entities.AppealStatus(row.Status),
+		ReviewerID: entities.UserID(row.ReviewerID),
+		Response:   row.Response,
+		CreatedAt:  row.CreatedAt,
+		DecidedAt:  decidedAt,
+	}, nil
+}
+
+// handleModerationError maps database errors for moderation queries to
+// domain errors. The only unique constraint allows one appeal per action.
+func handleModerationError(err error, operation string, notFound error) error {
+	return mysqldb.HandleDBError(err, operation, notFound, entities.ErrAppealExists, entities.ErrInvalidReference)
+}
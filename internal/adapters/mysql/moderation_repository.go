@@ -0,0 +1,22 @@
+package mysql
+
+import (
+	"github.com/LarsArtmann/template-sqlc/internal/adapters"
+	"github.com/LarsArtmann/template-sqlc/internal/db/shared"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// ModerationRepository implements ModerationRepository for MySQL.
+type ModerationRepository struct {
+	*adapters.NotImplementedModerationRepository
+
+	db shared.DBTX
+}
+
+// NewModerationRepository creates a new MySQL moderation repository.
+func NewModerationRepository(db shared.DBTX) repositories.ModerationRepository {
+	return &ModerationRepository{
+		NotImplementedModerationRepository: adapters.NewNotImplementedModerationRepository("MySQL"),
+		db:                                 db,
+	}
+}
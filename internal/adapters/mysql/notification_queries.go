@@ -0,0 +1,117 @@
+//go:build mysql
+
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	mysqldb "github.com/LarsArtmann/template-sqlc/internal/db/mysql"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	apperrors "github.com/LarsArtmann/template-sqlc/pkg/errors"
+)
+
+// queries returns the sqlc queries bound to the repository's database handle.
+func (r *NotificationPreferenceRepository) queries() *mysqldb.Queries {
+	return mysqldb.New(r.db)
+}
+
+// ListByUser returns the stored preferences of a user, ordered by channel and topic.
+func (r *NotificationPreferenceRepository) ListByUser(
+	ctx context.Context,
+	userID entities.UserID,
+) ([]*entities.NotificationPreference, error) {
+	rows, err := r.queries().ListNotificationPreferences(ctx, uint64(userID))
+	if err != nil {
+		return nil, fmt.Errorf("user=%v: %w", userID, handleNotificationError(err, "list notification preferences"))
+	}
+
+	prefs := make([]*entities.NotificationPreference, 0, len(rows))
+
+	for _, row := range rows {
+		prefs = append(prefs, &entities.NotificationPreference{
+			UserID:    entities.UserID(row.UserID),
+			Channel:   entities.NotificationChannel(row.Channel),
+			Topic:     entities.NotificationTopic(row.Topic),
+			Enabled:   row.Enabled,
+			Target:    row.Target,
+			UpdatedAt: row.UpdatedAt,
+		})
+	}
+
+	return prefs, nil
+}
+
+// Upsert stores a preference, replacing the one of its channel and topic.
+func (r *NotificationPreferenceRepository) Upsert(ctx context.Context, pref *entities.NotificationPreference) error {
+	err := r.queries().UpsertNotificationPreference(ctx, &mysqldb.UpsertNotificationPreferenceParams{
+		UserID:    uint64(pref.UserID),
+		Channel:   pref.Channel.String(),
+		Topic:     pref.Topic.String(),
+		Enabled:   pref.Enabled,
+		Target:    pref.Target,
+		UpdatedAt: pref.UpdatedAt,
+	})
+	if err != nil {
+		return fmt.Errorf(
+			"channel=%v topic=%v user=%v: %w",
+			pref.Channel,
+			pref.Topic,
+			pref.UserID,
+			handleNotificationError(err, "upsert notification preference"),
+		)
+	}
+
+	return nil
+}
+
+// Delete removes a stored preference, restoring its default.
+func (r *NotificationPreferenceRepository) Delete(
+	ctx context.Context,
+	userID entities.UserID,
+	channel entities.NotificationChannel,
+	topic entities.NotificationTopic,
+) error {
+	affected, err := r.queries().DeleteNotificationPreference(ctx, &mysqldb.DeleteNotificationPreferenceParams{
+		UserID:  uint64(userID),
+		Channel: channel.String(),
+		Topic:   topic.String(),
+	})
+	if err != nil {
+		return fmt.Errorf(
+			"channel=%v topic=%v user=%v: %w",
+			channel,
+			topic,
+			userID,
+			handleNotificationError(err, "delete notification preference"),
+		)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf(
+			"channel=%v topic=%v user=%v: %w",
+			channel,
+			topic,
+			userID,
+			entities.ErrNotificationPreferenceNotFound,
+		)
+	}
+
+	return nil
+}
+
+// handleNotificationError maps database errors for notification preference
+// queries to domain errors.
+func handleNotificationError(err error, operation string) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return entities.ErrNotificationPreferenceNotFound
+	}
+
+	if mysqldb.IsMySQLForeignKeyError(err) {
+		return entities.ErrInvalidReference
+	}
+
+	return apperrors.NewDatabaseError(operation+" failed", err)
+}
@@ -0,0 +1,24 @@
+package mysql
+
+import (
+	"github.com/LarsArtmann/template-sqlc/internal/adapters"
+	"github.com/LarsArtmann/template-sqlc/internal/db/shared"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// NotificationPreferenceRepository implements NotificationPreferenceRepository for MySQL.
+type NotificationPreferenceRepository struct {
+	*adapters.NotImplementedNotificationPreferenceRepository
+
+	db shared.DBTX
+}
+
+// NewNotificationPreferenceRepository creates a new MySQL notification preference repository.
+func NewNotificationPreferenceRepository(db shared.DBTX) repositories.NotificationPreferenceRepository {
+	return &NotificationPreferenceRepository{
+		NotImplementedNotificationPreferenceRepository: adapters.NewNotImplementedNotificationPreferenceRepository(
+			"MySQL",
+		),
+		db: db,
+	}
+}
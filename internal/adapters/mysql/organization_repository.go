@@ -0,0 +1,37 @@
+package mysql
+
+import (
+	"github.com/LarsArtmann/template-sqlc/internal/adapters"
+	"github.com/LarsArtmann/template-sqlc/internal/db/shared"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// OrganizationRepository implements OrganizationRepository for MySQL.
+type OrganizationRepository struct {
+	*adapters.NotImplementedOrganizationRepository
+
+	db shared.DBTX
+}
+
+// NewOrganizationRepository creates a new MySQL organization repository.
+func NewOrganizationRepository(db shared.DBTX) repositories.OrganizationRepository {
+	return &OrganizationRepository{
+		NotImplementedOrganizationRepository: adapters.NewNotImplementedOrganizationRepository("MySQL"),
+		db:                                   db,
+	}
+}
+
+// MembershipRepository implements MembershipRepository for MySQL.
+type MembershipRepository struct {
+	*adapters.NotImplementedMembershipRepository
+
+	db shared.DBTX
+}
+
+// NewMembershipRepository creates a new MySQL membership repository.
+func NewMembershipRepository(db shared.DBTX) repositories.MembershipRepository {
+	return &MembershipRepository{
+		NotImplementedMembershipRepository: adapters.NewNotImplementedMembershipRepository("MySQL"),
+		db:                                 db,
+	}
+}
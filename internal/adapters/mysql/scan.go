@@ -0,0 +1,32 @@
+//go:build mysql
+
+package mysql
+
+import (
+	"sync"
+
+	"github.com/LarsArtmann/template-sqlc/pkg/scan"
+)
+
+// registerRowMappers registers the MySQL row mappers with the default scan
+// registry. It runs once, before the first rows are mapped.
+var registerRowMappers = sync.OnceFunc(func() {
+	scan.Register(domainUser)
+	scan.Register(domainIdentity)
+	scan.Register(domainIdentityChange)
+	scan.Register(domainModerationAction)
+	scan.Register(domainAppeal)
+	scan.Register(domainAccountDeletion)
+	scan.Register(domainSession)
+	scan.Register(domainIdempotencyRecord)
+	scan.Register(domainJob)
+	scan.Register(domainOrganization)
+	scan.Register(domainMembership)
+})
+
+// scanAll converts generated rows into entities with their registered mapper.
+func scanAll[Entity, Row any](rows []*Row) ([]Entity, error) {
+	registerRowMappers()
+
+	return scan.All[Entity](rows)
+}
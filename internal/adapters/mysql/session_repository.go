@@ -0,0 +1,22 @@
+package mysql
+
+import (
+	"github.com/LarsArtmann/template-sqlc/internal/adapters"
+	"github.com/LarsArtmann/template-sqlc/internal/db/shared"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// SessionRepository implements SessionRepository for MySQL.
+type SessionRepository struct {
+	*adapters.NotImplementedSessionRepository
+
+	db shared.DBTX
+}
+
+// NewSessionRepository creates a new MySQL session repository.
+func NewSessionRepository(db shared.DBTX) repositories.SessionRepository {
+	return &SessionRepository{
+		NotImplementedSessionRepository: adapters.NewNotImplementedSessionRepository("MySQL"),
+		db:                              db,
+	}
+}
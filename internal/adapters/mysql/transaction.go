@@ -0,0 +1,146 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+	apperrors "github.com/LarsArtmann/template-sqlc/pkg/errors"
+)
+
+// TransactionalRepository runs transactions on a MySQL database, handing
+// out the MySQL repositories bound to them.
+type TransactionalRepository struct {
+	db *sql.DB
+}
+
+// NewTransactionalRepository creates a MySQL transactional repository.
+func NewTransactionalRepository(db *sql.DB) repositories.TransactionalRepository {
+	return &TransactionalRepository{db: db}
+}
+
+// BeginTx starts a transaction, which the caller commits or rolls back.
+func (r *TransactionalRepository) BeginTx(ctx context.Context) (repositories.Transaction, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, apperrors.NewDatabaseError("begin transaction failed", err)
+	}
+
+	return &Transaction{tx: tx}, nil
+}
+
+// RunInTransaction runs fn in a transaction, which is committed if fn
+// succeeds and rolled back otherwise.
+func (r *TransactionalRepository) RunInTransaction(
+	ctx context.Context,
+	fn func(ctx context.Context, tx repositories.Transaction) error,
+) error {
+	tx, err := r.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = fn(ctx, tx)
+	if err != nil {
+		return errors.Join(err, tx.Rollback())
+	}
+
+	return tx.Commit()
+}
+
+// Transaction is a MySQL transaction with the repositories bound to it.
+type Transaction struct {
+	tx *sql.Tx
+}
+
+// Commit commits the transaction.
+func (t *Transaction) Commit() error {
+	err := t.tx.Commit()
+	if err != nil {
+		return apperrors.NewDatabaseError("commit transaction failed", err)
+	}
+
+	return nil
+}
+
+// Rollback rolls the transaction back. Rolling back a finished transaction
+// does nothing.
+func (t *Transaction) Rollback() error {
+	err := t.tx.Rollback()
+	if err != nil && !errors.Is(err, sql.ErrTxDone) {
+		return apperrors.NewDatabaseError("rollback transaction failed", err)
+	}
+
+	return nil
+}
+
+// UserRepository returns the user repository of the transaction.
+func (t *Transaction) UserRepository() repositories.UserRepository {
+	return NewUserRepository(t.tx)
+}
+
+// SessionRepository returns the session repository of the transaction.
+func (t *Transaction) SessionRepository() repositories.SessionRepository {
+	return NewSessionRepository(t.tx)
+}
+
+// OrganizationRepository returns the organization repository of the transaction.
+func (t *Transaction) OrganizationRepository() repositories.OrganizationRepository {
+	return NewOrganizationRepository(t.tx)
+}
+
+// MembershipRepository returns the membership repository of the transaction.
+func (t *Transaction) MembershipRepository() repositories.MembershipRepository {
+	return NewMembershipRepository(t.tx)
+}
+
+// IdentityRepository returns the identity repository of the transaction.
+func (t *Transaction) IdentityRepository() repositories.IdentityRepository {
+	return NewIdentityRepository(t.tx)
+}
+
+// AuditRepository returns the audit repository of the transaction.
+func (t *Transaction) AuditRepository() repositories.AuditRepository {
+	return NewAuditRepository(t.tx)
+}
+
+// UserHistoryRepository returns the user history repository of the transaction.
+func (t *Transaction) UserHistoryRepository() repositories.UserHistoryRepository {
+	return NewUserHistoryRepository(t.tx)
+}
+
+// IdentityChangeRepository returns the identity change repository of the transaction.
+func (t *Transaction) IdentityChangeRepository() repositories.IdentityChangeRepository {
+	return NewIdentityChangeRepository(t.tx)
+}
+
+// ModerationRepository returns the moderation repository of the transaction.
+func (t *Transaction) ModerationRepository() repositories.ModerationRepository {
+	return NewModerationRepository(t.tx)
+}
+
+// AccountDeletionRepository returns the account deletion repository of the transaction.
+func (t *Transaction) AccountDeletionRepository() repositories.AccountDeletionRepository {
+	return NewAccountDeletionRepository(t.tx)
+}
+
+// LoginHistoryRepository returns the login history repository of the transaction.
+func (t *Transaction) LoginHistoryRepository() repositories.LoginHistoryRepository {
+	return NewLoginHistoryRepository(t.tx)
+}
+
+// ActivityRepository returns the activity repository of the transaction.
+func (t *Transaction) ActivityRepository() repositories.ActivityRepository {
+	return NewActivityRepository(t.tx)
+}
+
+// UserPreferenceRepository returns the user preference repository of the transaction.
+func (t *Transaction) UserPreferenceRepository() repositories.UserPreferenceRepository {
+	return NewUserPreferenceRepository(t.tx)
+}
+
+// UserEventStore returns the user event store of the transaction.
+func (t *Transaction) UserEventStore() repositories.UserEventStore {
+	return NewUserEventStore(t.tx)
+}
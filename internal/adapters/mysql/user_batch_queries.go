@@ -0,0 +1,148 @@
+//go:build mysql
+
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters"
+	mysqldb "github.com/LarsArtmann/template-sqlc/internal/db/mysql"
+	"github.com/LarsArtmann/template-sqlc/internal/db/shared"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+)
+
+// insertUsers is CreateUser with a variable number of rows, which sqlc cannot
+// generate; one userRow placeholder group is appended per user.
+const (
+	insertUsers = `INSERT INTO users (
+    uuid, email, username, password_hash,
+    first_name, last_name, profile_metadata, is_active,
+    is_verified, status, role, tags, created_at, updated_at,
+    username_canonical
+) VALUES `
+	userRow = "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+)
+
+// CreateBatch inserts the users with multi-row INSERTs in one transaction and
+// then reads back their IDs by UUID, since auto-increment values of a
+// multi-row insert are not guaranteed to be consecutive.
+func (r *UserRepository) CreateBatch(ctx context.Context, users []*entities.User) error {
+	if len(users) == 0 {
+		return nil
+	}
+
+	byUUID := make(map[string]*entities.User, len(users))
+
+	return r.InTx(ctx, func(db shared.DBTX) error {
+		for chunk := range slices.Chunk(users, adapters.BatchChunkSize) {
+			uuids := make([][]byte, 0, len(chunk))
+			args := make([]any, 0, len(chunk)*strings.Count(userRow, "?"))
+
+			for _, user := range chunk {
+				params, err := r.createParams(user)
+				if err != nil {
+					return err
+				}
+
+				args = append(args,
+					params.UUID, params.Email, params.Username, params.PasswordHash,
+					params.FirstName, params.LastName, params.ProfileMetadata, params.IsActive,
+					params.IsVerified, params.Status, params.Role, params.Tags, params.CreatedAt, params.UpdatedAt,
+					params.UsernameCanonical,
+				)
+				uuids = append(uuids, params.UUID)
+				byUUID[string(params.UUID)] = user
+			}
+
+			query := insertUsers + strings.Repeat(userRow+", ", len(chunk)-1) + userRow
+
+			_, err := db.ExecContext(ctx, query, args...)
+			if err != nil {
+				return fmt.Errorf("create batch of %d: %w", len(users), handleError(err, "create users"))
+			}
+
+			created, err := mysqldb.New(db).GetUserIDsByUUIDs(ctx, uuids)
+			if err != nil {
+				return fmt.Errorf("create batch of %d: %w", len(users), handleError(err, "read user ids"))
+			}
+
+			for _, row := range created {
+				byUUID[string(row.UUID)].SetID(entities.UserID(row.ID))
+			}
+		}
+
+		return nil
+	})
+}
+
+// UpdateStatusBatch changes the statuses in one transaction.
+func (r *UserRepository) UpdateStatusBatch(
+	ctx context.Context,
+	ids []entities.UserID,
+	status entities.UserStatus,
+) (int64, error) {
+	if !status.IsValid() {
+		return 0, fmt.Errorf("status=%v: %w", status, entities.ErrInvalidUserStatus)
+	}
+
+	return r.batchByIDs(ctx, ids, "update user statuses", func(queries *mysqldb.Queries, chunk []uint64) (int64, error) {
+		return queries.UpdateUsersStatus(ctx, &mysqldb.UpdateUsersStatusParams{
+			Status: string(status),
+			Ids:    chunk,
+		})
+	})
+}
+
+// DeleteBatch soft deletes the users in one transaction.
+func (r *UserRepository) DeleteBatch(ctx context.Context, ids []entities.UserID) (int64, error) {
+	return r.batchByIDs(ctx, ids, "delete users", func(queries *mysqldb.Queries, chunk []uint64) (int64, error) {
+		return queries.SoftDeleteUsers(ctx, chunk)
+	})
+}
+
+// batchByIDs runs update for chunks of ids in one transaction and sums the affected rows.
+func (r *UserRepository) batchByIDs(
+	ctx context.Context,
+	ids []entities.UserID,
+	op string,
+	update func(queries *mysqldb.Queries, chunk []uint64) (int64, error),
+) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	var affected int64
+
+	err := r.InTx(ctx, func(db shared.DBTX) error {
+		queries := mysqldb.New(db)
+
+		for chunk := range slices.Chunk(userIDKeys(ids), adapters.BatchChunkSize) {
+			rows, err := update(queries, chunk)
+			if err != nil {
+				return fmt.Errorf("%s batch of %d: %w", op, len(ids), handleError(err, op))
+			}
+
+			affected += rows
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return affected, nil
+}
+
+// userIDKeys converts user IDs to their column values.
+func userIDKeys(ids []entities.UserID) []uint64 {
+	keys := make([]uint64, 0, len(ids))
+	for _, id := range ids {
+		keys = append(keys, uint64(id))
+	}
+
+	return keys
+}
@@ -0,0 +1,187 @@
+//go:build mysql
+
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/mappers"
+	mysqldb "github.com/LarsArtmann/template-sqlc/internal/db/mysql"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	apperrors "github.com/LarsArtmann/template-sqlc/pkg/errors"
+)
+
+// queries returns the sqlc queries bound to the store's database handle.
+func (s *UserEventStore) queries() *mysqldb.Queries {
+	return mysqldb.New(s.db)
+}
+
+// Append adds events to the stream of userID after expectedVersion and
+// numbers them, in a transaction unless the store already runs in one.
+func (s *UserEventStore) Append(
+	ctx context.Context,
+	userID entities.UserID,
+	expectedVersion int64,
+	events []*entities.UserStreamEvent,
+) error {
+	return s.inTransaction(ctx, func(q *mysqldb.Queries) error {
+		version, err := q.GetUserStreamVersion(ctx, uint64(userID))
+		if err != nil {
+			return fmt.Errorf("user=%v: %w", userID, handleUserEventError(err, "get user stream version"))
+		}
+
+		if version != expectedVersion {
+			return fmt.Errorf("user=%v version=%v: %w", userID, expectedVersion, entities.ErrUserStreamConflict)
+		}
+
+		for _, event := range events {
+			data, err := mappers.EncodeUserEventData(event.Data)
+			if err != nil {
+				return err
+			}
+
+			version++
+
+			err = q.AppendUserEvent(ctx, &mysqldb.AppendUserEventParams{
+				UserID:     uint64(userID),
+				Version:    version,
+				EventType:  event.Type.String(),
+				Data:       json.RawMessage(data),
+				OccurredAt: event.OccurredAt.UTC(),
+			})
+			if err != nil {
+				return fmt.Errorf("user=%v version=%v: %w", userID, version, handleUserEventError(err, "append user event"))
+			}
+
+			event.UserID = userID
+			event.Version = version
+		}
+
+		return nil
+	})
+}
+
+// Load returns the events of the stream of userID after afterVersion,
+// oldest first.
+func (s *UserEventStore) Load(
+	ctx context.Context,
+	userID entities.UserID,
+	afterVersion int64,
+) ([]*entities.UserStreamEvent, error) {
+	rows, err := s.queries().ListUserEvents(ctx, &mysqldb.ListUserEventsParams{
+		UserID:       uint64(userID),
+		AfterVersion: afterVersion,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("user=%v: %w", userID, handleUserEventError(err, "list user events"))
+	}
+
+	events := make([]*entities.UserStreamEvent, 0, len(rows))
+
+	for _, row := range rows {
+		event, err := mappers.DomainUserStreamEvent(userID, row.Version, row.EventType, row.Data, row.OccurredAt)
+		if err != nil {
+			return nil, err
+		}
+
+		events = append(events, event)
+	}
+
+	return events, nil
+}
+
+// SaveSnapshot stores snapshot; a snapshot of the same version is kept.
+func (s *UserEventStore) SaveSnapshot(ctx context.Context, snapshot *entities.UserStreamSnapshot) error {
+	state, err := mappers.EncodeUserSnapshotState(snapshot.State)
+	if err != nil {
+		return err
+	}
+
+	err = s.queries().SaveUserSnapshot(ctx, &mysqldb.SaveUserSnapshotParams{
+		UserID:    uint64(snapshot.UserID),
+		Version:   snapshot.Version,
+		Deleted:   snapshot.Deleted,
+		State:     json.RawMessage(state),
+		CreatedAt: snapshot.CreatedAt.UTC(),
+	})
+	if err != nil {
+		return fmt.Errorf("user=%v version=%v: %w", snapshot.UserID, snapshot.Version,
+			handleUserEventError(err, "save user snapshot"))
+	}
+
+	return nil
+}
+
+// LatestSnapshot returns the snapshot of userID with the highest version.
+func (s *UserEventStore) LatestSnapshot(
+	ctx context.Context,
+	userID entities.UserID,
+) (*entities.UserStreamSnapshot, error) {
+	row, err := s.queries().GetLatestUserSnapshot(ctx, uint64(userID))
+	if err != nil {
+		return nil, fmt.Errorf("user=%v: %w", userID, handleUserEventError(err, "get user snapshot"))
+	}
+
+	return mappers.DomainUserStreamSnapshot(userID, row.Version, row.Deleted, row.State, row.CreatedAt)
+}
+
+// DeleteStream removes the events and snapshots of userID in one
+// transaction, so a later Append starts the stream at version 0 again.
+func (s *UserEventStore) DeleteStream(ctx context.Context, userID entities.UserID) error {
+	return s.inTransaction(ctx, func(q *mysqldb.Queries) error {
+		_, err := q.DeleteUserEvents(ctx, uint64(userID))
+		if err != nil {
+			return fmt.Errorf("user=%v: %w", userID, handleUserEventError(err, "delete user events"))
+		}
+
+		_, err = q.DeleteUserSnapshots(ctx, uint64(userID))
+		if err != nil {
+			return fmt.Errorf("user=%v: %w", userID, handleUserEventError(err, "delete user snapshots"))
+		}
+
+		return nil
+	})
+}
+
+// inTransaction runs fn in a new transaction, or with the store's handle if
+// it is not a *sql.DB and so already a transaction.
+func (s *UserEventStore) inTransaction(ctx context.Context, fn func(q *mysqldb.Queries) error) error {
+	db, ok := s.db.(*sql.DB)
+	if !ok {
+		return fn(s.queries())
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return apperrors.NewDatabaseError("begin transaction failed", err)
+	}
+
+	err = fn(mysqldb.New(tx))
+	if err != nil {
+		return errors.Join(err, tx.Rollback())
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return apperrors.NewDatabaseError("commit transaction failed", err)
+	}
+
+	return nil
+}
+
+// handleUserEventError maps database errors for user event queries to
+// domain errors. A second event of a version means a concurrent append,
+// and a missing user an invalid reference.
+func handleUserEventError(err error, operation string) error {
+	return mysqldb.HandleDBError(
+		err,
+		operation,
+		entities.ErrUserSnapshotNotFound,
+		entities.ErrUserStreamConflict,
+		entities.ErrInvalidReference,
+	)
+}
@@ -0,0 +1,22 @@
+package mysql
+
+import (
+	"github.com/LarsArtmann/template-sqlc/internal/adapters"
+	"github.com/LarsArtmann/template-sqlc/internal/db/shared"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// UserEventStore implements UserEventStore for MySQL.
+type UserEventStore struct {
+	*adapters.NotImplementedUserEventStore
+
+	db shared.DBTX
+}
+
+// NewUserEventStore creates a new MySQL user event store.
+func NewUserEventStore(db shared.DBTX) repositories.UserEventStore {
+	return &UserEventStore{
+		NotImplementedUserEventStore: adapters.NewNotImplementedUserEventStore("MySQL"),
+		db:                           db,
+	}
+}
@@ -0,0 +1,110 @@
+//go:build mysql
+
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/mappers"
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/validation"
+	mysqldb "github.com/LarsArtmann/template-sqlc/internal/db/mysql"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+)
+
+// queries returns the sqlc queries bound to the repository's database handle.
+func (r *UserHistoryRepository) queries() *mysqldb.Queries {
+	return mysqldb.New(r.db)
+}
+
+// GetUserAsOf returns the version of a user that was valid at the given time.
+func (r *UserHistoryRepository) GetUserAsOf(
+	ctx context.Context,
+	id entities.UserID,
+	at time.Time,
+) (*entities.UserSnapshot, error) {
+	row, err := r.queries().GetUserAsOf(ctx, &mysqldb.GetUserAsOfParams{
+		UserID: uint64(id),
+		AsOf:   at.UTC(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("user id=%v as of %v: %w", id, at, handleError(err, "get user as of"))
+	}
+
+	validTo, err := mappers.DecodeNullableTime(row.ValidTo)
+	if err != nil {
+		return nil, fmt.Errorf("user id=%v as of %v: %w", id, at, err)
+	}
+
+	return mappers.DomainUserSnapshot(mappers.UserSnapshotRow{
+		UserID:     entities.UserID(row.UserID),
+		Operation:  row.Operation,
+		Email:      row.Email,
+		Username:   row.Username,
+		FirstName:  row.FirstName,
+		LastName:   row.LastName,
+		Status:     row.Status,
+		Role:       row.Role,
+		Tags:       row.Tags,
+		IsVerified: row.IsVerified.Bool,
+		ValidFrom:  row.ValidFrom.Time,
+		ValidTo:    validTo,
+	})
+}
+
+// ListVersions returns the archived versions of a user, newest first.
+func (r *UserHistoryRepository) ListVersions(
+	ctx context.Context,
+	id entities.UserID,
+	limit int,
+) ([]*entities.UserSnapshot, error) {
+	err := validation.ValidatePagination(limit, 0)
+	if err != nil {
+		return nil, fmt.Errorf("limit=%v: %w", limit, err)
+	}
+
+	rows, err := r.queries().ListUserHistory(ctx, &mysqldb.ListUserHistoryParams{
+		UserID: uint64(id),
+		Limit:  int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("user id=%v: %w", id, handleError(err, "list user versions"))
+	}
+
+	versions := make([]*entities.UserSnapshot, 0, len(rows))
+
+	for _, row := range rows {
+		version, err := mappers.DomainUserSnapshot(mappers.UserSnapshotRow{
+			UserID:     entities.UserID(row.UserID),
+			Operation:  row.Operation,
+			Email:      row.Email,
+			Username:   row.Username,
+			FirstName:  row.FirstName,
+			LastName:   row.LastName,
+			Status:     row.Status,
+			Role:       row.Role,
+			Tags:       row.Tags,
+			IsVerified: row.IsVerified.Bool,
+			ValidFrom:  row.ValidFrom,
+			ValidTo:    &row.ValidTo,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		versions = append(versions, version)
+	}
+
+	return versions, nil
+}
+
+// DeleteVersions removes every archived version of a user.
+func (r *UserHistoryRepository) DeleteVersions(ctx context.Context, id entities.UserID) (int64, error) {
+	deleted, err := r.queries().DeleteUserHistory(ctx, uint64(id))
+	if err != nil {
+		return 0, fmt.Errorf("user id=%v: %w", id, handleError(err, "delete user versions"))
+	}
+
+	return deleted, nil
+}
@@ -0,0 +1,23 @@
+package mysql
+
+import (
+	"github.com/LarsArtmann/template-sqlc/internal/adapters"
+	"github.com/LarsArtmann/template-sqlc/internal/db/shared"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// UserHistoryRepository implements UserHistoryRepository for MySQL. The
+// versions are archived by the user repository's updates and deletes.
+type UserHistoryRepository struct {
+	*adapters.NotImplementedUserHistoryRepository
+
+	db shared.DBTX
+}
+
+// NewUserHistoryRepository creates a new MySQL user history repository.
+func NewUserHistoryRepository(db shared.DBTX) repositories.UserHistoryRepository {
+	return &UserHistoryRepository{
+		NotImplementedUserHistoryRepository: adapters.NewNotImplementedUserHistoryRepository("MySQL"),
+		db:                                  db,
+	}
+}
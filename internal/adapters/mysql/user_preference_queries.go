@@ -0,0 +1,177 @@
+//go:build mysql
+
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	mysqldb "github.com/LarsArtmann/template-sqlc/internal/db/mysql"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	apperrors "github.com/LarsArtmann/template-sqlc/pkg/errors"
+)
+
+// queries returns the sqlc queries bound to the repository's database handle.
+func (r *UserPreferenceRepository) queries() *mysqldb.Queries {
+	return mysqldb.New(r.db)
+}
+
+// Get returns the stored preferences of a user, empty if none were stored.
+func (r *UserPreferenceRepository) Get(ctx context.Context, userID entities.UserID) (*entities.UserPreferences, error) {
+	return getUserPreferences(ctx, r.queries(), userID)
+}
+
+// Patch sets every Set preference with JSON_SET and removes every Unset
+// one with JSON_REMOVE, one statement per key, in a transaction unless the
+// repository already runs in one.
+func (r *UserPreferenceRepository) Patch(
+	ctx context.Context,
+	userID entities.UserID,
+	patch entities.PreferencePatch,
+) (*entities.UserPreferences, error) {
+	var prefs *entities.UserPreferences
+
+	err := r.inTransaction(ctx, func(q *mysqldb.Queries) error {
+		now := time.Now().UTC()
+
+		for key, value := range patch.Set {
+			err := setUserPreference(ctx, q, userID, key, value, now)
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, key := range patch.Unset {
+			if !key.IsValid() {
+				return fmt.Errorf("preference %v: %w", key, entities.ErrUnknownPreference)
+			}
+
+			err := q.RemoveUserPreference(ctx, &mysqldb.RemoveUserPreferenceParams{
+				Name:      key.String(),
+				UpdatedAt: now,
+				UserID:    uint64(userID),
+			})
+			if err != nil {
+				return fmt.Errorf("preference %v user=%v: %w", key, userID,
+					handleUserPreferenceError(err, "remove user preference"))
+			}
+		}
+
+		var err error
+		prefs, err = getUserPreferences(ctx, q, userID)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return prefs, nil
+}
+
+// Delete removes the preferences of a user; none stored is not an error.
+func (r *UserPreferenceRepository) Delete(ctx context.Context, userID entities.UserID) error {
+	_, err := r.queries().DeleteUserPreferences(ctx, uint64(userID))
+	if err != nil {
+		return fmt.Errorf("user=%v: %w", userID, handleUserPreferenceError(err, "delete user preferences"))
+	}
+
+	return nil
+}
+
+// inTransaction runs fn in a new transaction, or with the repository's
+// handle if it is not a *sql.DB and so already a transaction.
+func (r *UserPreferenceRepository) inTransaction(ctx context.Context, fn func(q *mysqldb.Queries) error) error {
+	db, ok := r.db.(*sql.DB)
+	if !ok {
+		return fn(r.queries())
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return apperrors.NewDatabaseError("begin transaction failed", err)
+	}
+
+	err = fn(mysqldb.New(tx))
+	if err != nil {
+		return errors.Join(err, tx.Rollback())
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return apperrors.NewDatabaseError("commit transaction failed", err)
+	}
+
+	return nil
+}
+
+// setUserPreference stores one preference. Keys are quoted into a JSON
+// path, so invalid ones are rejected first.
+func setUserPreference(
+	ctx context.Context,
+	q *mysqldb.Queries,
+	userID entities.UserID,
+	key entities.PreferenceKey,
+	value any,
+	now time.Time,
+) error {
+	if !key.IsValid() {
+		return fmt.Errorf("preference %v: %w", key, entities.ErrUnknownPreference)
+	}
+
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("encode preference %v user=%v: %w", key, userID, err)
+	}
+
+	err = q.SetUserPreference(ctx, &mysqldb.SetUserPreferenceParams{
+		UserID:    uint64(userID),
+		Name:      key.String(),
+		Value:     string(encoded),
+		UpdatedAt: now,
+	})
+	if err != nil {
+		return fmt.Errorf("preference %v user=%v: %w", key, userID, handleUserPreferenceError(err, "set user preference"))
+	}
+
+	return nil
+}
+
+// getUserPreferences loads the preferences of a user with q.
+func getUserPreferences(
+	ctx context.Context,
+	q *mysqldb.Queries,
+	userID entities.UserID,
+) (*entities.UserPreferences, error) {
+	row, err := q.GetUserPreferences(ctx, uint64(userID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return entities.NewUserPreferences(userID, nil, time.Time{}), nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("user=%v: %w", userID, handleUserPreferenceError(err, "get user preferences"))
+	}
+
+	var values map[entities.PreferenceKey]any
+
+	err = json.Unmarshal(row.Preferences, &values)
+	if err != nil {
+		return nil, fmt.Errorf("decode preferences user=%v: %w", row.UserID, err)
+	}
+
+	return entities.NewUserPreferences(entities.UserID(row.UserID), values, row.UpdatedAt), nil
+}
+
+// handleUserPreferenceError maps database errors for user preference
+// queries to domain errors.
+func handleUserPreferenceError(err error, operation string) error {
+	if mysqldb.IsMySQLForeignKeyError(err) {
+		return entities.ErrInvalidReference
+	}
+
+	return apperrors.NewDatabaseError(operation+" failed", err)
+}
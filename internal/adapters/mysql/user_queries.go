@@ -0,0 +1,709 @@
+//go:build mysql
+
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters"
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/converters"
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/mappers"
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/validation"
+	mysqldb "github.com/LarsArtmann/template-sqlc/internal/db/mysql"
+	"github.com/LarsArtmann/template-sqlc/internal/db/shared"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+	"github.com/google/uuid"
+)
+
+// queries returns the sqlc queries bound to the repository's database handle.
+func (r *UserRepository) queries() *mysqldb.Queries {
+	return mysqldb.New(r.DB())
+}
+
+// uuidBytes converts a UUID to its BINARY(16) column value.
+func (r *UserRepository) uuidBytes(id uuid.UUID) ([]byte, error) {
+	converter, err := converters.Lookup[uuid.UUID, []byte](r.Converters(), r.Engine())
+	if err != nil {
+		return nil, fmt.Errorf("uuid=%v: %w", id, err)
+	}
+
+	return converter.DomainToDB(id), nil
+}
+
+// Create inserts a new user and assigns the generated ID.
+func (r *UserRepository) Create(ctx context.Context, user *entities.User) error {
+	params, err := r.createParams(user)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.queries().CreateUser(ctx, params)
+	if err != nil {
+		return fmt.Errorf("create user email=%v: %w", user.Email(), handleError(err, "create user"))
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("create user email=%v: %w", user.Email(), handleError(err, "read user id"))
+	}
+
+	user.SetID(entities.UserID(id))
+
+	return nil
+}
+
+// createParams converts user to the insert parameters.
+func (r *UserRepository) createParams(user *entities.User) (*mysqldb.CreateUserParams, error) {
+	metadata, err := mappers.EncodeMetadata(user.Metadata())
+	if err != nil {
+		return nil, fmt.Errorf("create user email=%v: %w", user.Email(), err)
+	}
+
+	tags, err := mappers.EncodeTags(user.Tags())
+	if err != nil {
+		return nil, fmt.Errorf("create user email=%v: %w", user.Email(), err)
+	}
+
+	userUUID, err := r.uuidBytes(user.UUID())
+	if err != nil {
+		return nil, fmt.Errorf("create user email=%v: %w", user.Email(), err)
+	}
+
+	return &mysqldb.CreateUserParams{
+		UUID:              userUUID,
+		Email:             user.Email().String(),
+		Username:          user.Username().String(),
+		PasswordHash:      user.PasswordHash().String(),
+		FirstName:         user.FirstName().String(),
+		LastName:          user.LastName().String(),
+		ProfileMetadata:   json.RawMessage(metadata),
+		IsActive:          sql.NullBool{Bool: true, Valid: true},
+		IsVerified:        sql.NullBool{Bool: user.IsVerified(), Valid: true},
+		Status:            string(user.Status()),
+		Role:              string(user.Role()),
+		Tags:              json.RawMessage(tags),
+		CreatedAt:         sql.NullTime{Time: user.CreatedAt(), Valid: !user.CreatedAt().IsZero()},
+		UpdatedAt:         sql.NullTime{Time: user.UpdatedAt(), Valid: !user.UpdatedAt().IsZero()},
+		UsernameCanonical: user.Username().Canonical(),
+	}, nil
+}
+
+// GetByID retrieves a user by ID.
+func (r *UserRepository) GetByID(ctx context.Context, id entities.UserID) (*entities.User, error) {
+	row, err := r.queries().GetUserByID(ctx, uint64(id))
+	if err != nil {
+		return nil, fmt.Errorf("id=%v: %w", id, handleError(err, "get user"))
+	}
+
+	return domainUser(row)
+}
+
+// GetByIDs retrieves the users with the given IDs in one query.
+func (r *UserRepository) GetByIDs(ctx context.Context, ids []entities.UserID) ([]*entities.User, error) {
+	if len(ids) == 0 {
+		return []*entities.User{}, nil
+	}
+
+	rows, err := r.queries().GetUsersByIDs(ctx, userIDKeys(ids))
+	if err != nil {
+		return nil, fmt.Errorf("count=%v: %w", len(ids), handleError(err, "get users"))
+	}
+
+	return domainUsers(rows)
+}
+
+// GetByUUID retrieves a user by UUID.
+func (r *UserRepository) GetByUUID(ctx context.Context, uuid entities.UuID) (*entities.User, error) {
+	parsed, err := mappers.ParseUUID(uuid)
+	if err != nil {
+		return nil, fmt.Errorf("uuid=%v: %w", uuid, entities.ErrUserNotFound)
+	}
+
+	key, err := r.uuidBytes(parsed)
+	if err != nil {
+		return nil, err
+	}
+
+	row, err := r.queries().GetUserByUUID(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("uuid=%v: %w", uuid, handleError(err, "get user"))
+	}
+
+	return domainUser(row)
+}
+
+// GetByEmail retrieves a user by email.
+func (r *UserRepository) GetByEmail(ctx context.Context, email entities.Email) (*entities.User, error) {
+	row, err := r.queries().GetUserByEmail(ctx, email.String())
+	if err != nil {
+		return nil, fmt.Errorf("email=%v: %w", email, handleError(err, "get user"))
+	}
+
+	return domainUser(row)
+}
+
+// GetByUsername retrieves a user by canonical username.
+func (r *UserRepository) GetByUsername(
+	ctx context.Context,
+	username entities.Username,
+) (*entities.User, error) {
+	row, err := r.queries().GetUserByUsername(ctx, username.Canonical())
+	if err != nil {
+		return nil, fmt.Errorf("username=%v: %w", username, handleError(err, "get user"))
+	}
+
+	return domainUser(row)
+}
+
+// Update persists every mutable field of the user.
+func (r *UserRepository) Update(ctx context.Context, user *entities.User) error {
+	metadata, err := mappers.EncodeMetadata(user.Metadata())
+	if err != nil {
+		return fmt.Errorf("update user id=%v: %w", user.ID(), err)
+	}
+
+	tags, err := mappers.EncodeTags(user.Tags())
+	if err != nil {
+		return fmt.Errorf("update user id=%v: %w", user.ID(), err)
+	}
+
+	return r.InTx(ctx, func(db shared.DBTX) error {
+		q := mysqldb.New(db)
+
+		err := archiveChangedUser(ctx, q, user, metadata)
+		if err != nil {
+			return err
+		}
+
+		_, err = q.UpdateUser(ctx, &mysqldb.UpdateUserParams{
+			Email:             user.Email().String(),
+			Username:          user.Username().String(),
+			FirstName:         user.FirstName().String(),
+			LastName:          user.LastName().String(),
+			ProfileMetadata:   json.RawMessage(metadata),
+			IsVerified:        sql.NullBool{Bool: user.IsVerified(), Valid: true},
+			Status:            sql.NullString{String: string(user.Status()), Valid: true},
+			Role:              sql.NullString{String: string(user.Role()), Valid: true},
+			Tags:              json.RawMessage(tags),
+			LastLoginAt:       nullTime.DomainToDB(user.LastLoginAt()),
+			UsernameCanonical: user.Username().Canonical(),
+			ID:                uint64(user.ID()),
+		})
+		if err != nil {
+			return fmt.Errorf("update user id=%v: %w", user.ID(), handleError(err, "update user"))
+		}
+
+		return nil
+	})
+}
+
+// Delete soft deletes a user.
+func (r *UserRepository) Delete(ctx context.Context, id entities.UserID) error {
+	return r.InTx(ctx, func(db shared.DBTX) error {
+		q := mysqldb.New(db)
+
+		err := archiveUser(ctx, q, id, entities.HistoryOperationDelete)
+		if err != nil {
+			return err
+		}
+
+		err = q.SoftDeleteUser(ctx, uint64(id))
+		if err != nil {
+			return fmt.Errorf("delete user id=%v: %w", id, handleError(err, "delete user"))
+		}
+
+		return nil
+	})
+}
+
+// archiveUser copies the current version of a user into users_history.
+// MySQL has no history triggers, so it is called in the transaction of the
+// statement that supersedes the version.
+func archiveUser(
+	ctx context.Context,
+	q *mysqldb.Queries,
+	id entities.UserID,
+	operation entities.HistoryOperation,
+) error {
+	err := q.RecordUserHistory(ctx, &mysqldb.RecordUserHistoryParams{
+		Operation: string(operation),
+		UserID:    uint64(id),
+	})
+	if err != nil {
+		return fmt.Errorf("archive user id=%v: %w", id, handleError(err, "record user history"))
+	}
+
+	return nil
+}
+
+// archiveChangedUser archives the current version of user before it is
+// updated, unless the update changes none of the columns users_history
+// keeps, as when a login only records the last login. metadata is the
+// encoded metadata of user.
+func archiveChangedUser(ctx context.Context, q *mysqldb.Queries, user *entities.User, metadata string) error {
+	row, err := q.GetUserByIDForUpdate(ctx, uint64(user.ID()))
+	if err != nil {
+		return fmt.Errorf("archive user id=%v: %w", user.ID(), handleError(err, "lock user"))
+	}
+
+	stored, err := domainUser(row)
+	if err != nil {
+		return fmt.Errorf("archive user id=%v: %w", user.ID(), err)
+	}
+
+	storedMetadata, err := mappers.EncodeMetadata(stored.Metadata())
+	if err != nil {
+		return fmt.Errorf("archive user id=%v: %w", user.ID(), err)
+	}
+
+	if stored.Email() == user.Email() &&
+		stored.Username() == user.Username() &&
+		stored.FirstName() == user.FirstName() &&
+		stored.LastName() == user.LastName() &&
+		stored.Status() == user.Status() &&
+		stored.Role() == user.Role() &&
+		stored.IsVerified() == user.IsVerified() &&
+		slices.Equal(stored.Tags(), user.Tags()) &&
+		storedMetadata == metadata {
+		return nil
+	}
+
+	return archiveUser(ctx, q, user.ID(), entities.HistoryOperationUpdate)
+}
+
+// Restore restores a soft-deleted user.
+func (r *UserRepository) Restore(ctx context.Context, id entities.UserID) error {
+	restored, err := r.queries().RestoreUser(ctx, uint64(id))
+	if err != nil {
+		return fmt.Errorf("restore user id=%v: %w", id, handleError(err, "restore user"))
+	}
+
+	if restored == 0 {
+		return fmt.Errorf("restore user id=%v: %w", id, entities.ErrUserNotFound)
+	}
+
+	return nil
+}
+
+// PurgeDeletedOlderThan permanently removes the users soft deleted before cutoff.
+func (r *UserRepository) PurgeDeletedOlderThan(ctx context.Context, cutoff time.Time) (int64, error) {
+	purged, err := r.queries().PurgeDeletedUsers(ctx, sql.NullTime{Time: cutoff, Valid: true})
+	if err != nil {
+		return 0, fmt.Errorf("purge users cutoff=%v: %w", cutoff, handleError(err, "purge users"))
+	}
+
+	return purged, nil
+}
+
+// List retrieves a page of the users matching filter, newest first.
+func (r *UserRepository) List(
+	ctx context.Context,
+	filter entities.UserFilter,
+	limit, offset int,
+) ([]*entities.User, error) {
+	err := adapters.ValidateListRequest(filter, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	var first *entities.UserCursor
+
+	return r.filterUsers(ctx, filter, first.Bound(), limit, offset)
+}
+
+// ListPage retrieves a page of users with keyset pagination, newest first.
+func (r *UserRepository) ListPage(
+	ctx context.Context,
+	filter entities.UserFilter,
+	cursor string,
+	limit int,
+) (*entities.UserPage, error) {
+	after, err := adapters.ParsePageRequest(filter, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	// One extra row tells whether another page follows.
+	users, err := r.filterUsers(ctx, filter, after.Bound(), limit+1, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return entities.NewUserPage(users, limit), nil
+}
+
+// filterUsers runs FilterUsers for the users matching filter after bound.
+// Sets are passed as JSON arrays and the query in FULLTEXT boolean mode.
+func (r *UserRepository) filterUsers(
+	ctx context.Context,
+	filter entities.UserFilter,
+	bound entities.UserCursor,
+	limit, offset int,
+) ([]*entities.User, error) {
+	createdAfter, createdBefore := filter.CreatedRange()
+
+	params := &mysqldb.FilterUsersParams{
+		Verified:        int64(filter.VerifiedState()),
+		CreatedAfter:    sql.NullTime{Time: createdAfter, Valid: true},
+		CreatedBefore:   sql.NullTime{Time: createdBefore, Valid: true},
+		Query:           booleanQuery(filter.Query),
+		CursorCreatedAt: sql.NullTime{Time: bound.CreatedAt, Valid: true},
+		CursorID:        uint64(bound.ID),
+		Limit:           int32(limit),
+		Offset:          int32(offset),
+	}
+
+	sets := map[*string][]string{
+		&params.Statuses: filter.StatusValues(),
+		&params.Roles:    filter.RoleValues(),
+		&params.TagsAny:  filter.TagsAny,
+		&params.TagsAll:  filter.TagsAll,
+	}
+
+	for param, values := range sets {
+		encoded, err := mappers.EncodeTags(values)
+		if err != nil {
+			return nil, fmt.Errorf("filter=%+v: %w", filter, err)
+		}
+
+		*param = encoded
+	}
+
+	rows, err := r.queries().FilterUsers(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("filter users filter=%+v: %w", filter, handleError(err, "filter users"))
+	}
+
+	return domainUsers(rows)
+}
+
+// SearchWithFacets searches users and returns facet counts over every match.
+// Users in the result carry the columns needed for listing only; load a user
+// by ID for the full record.
+func (r *UserRepository) SearchWithFacets(
+	ctx context.Context,
+	query string,
+	status entities.UserStatus,
+	limit int,
+) (*entities.UserSearchResult, error) {
+	err := validation.ValidateSearchQuery(query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("query=%v limit=%v: %w", query, limit, err)
+	}
+
+	rows, err := r.queries().SearchUsersWithFacets(ctx, &mysqldb.SearchUsersWithFacetsParams{
+		Query:   query,
+		Limit:   adapters.DefaultFacetTagLimit,
+		Status:  string(status),
+		Limit_2: int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("search users query=%v: %w", query, handleError(err, "search users with facets"))
+	}
+
+	result := &entities.UserSearchResult{
+		Users:  make([]*entities.User, 0, len(rows)),
+		Facets: entities.NewSearchFacets(),
+	}
+
+	if len(rows) == 0 {
+		return result, nil
+	}
+
+	result.Facets, err = mappers.DecodeSearchFacets(rows[0].StatusFacets, rows[0].RoleFacets, rows[0].TagFacets)
+	if err != nil {
+		return nil, fmt.Errorf("search users query=%v: %w", query, err)
+	}
+
+	for _, row := range rows {
+		if !row.ID.Valid {
+			continue
+		}
+
+		userUUID, err := converters.FromDB[uuid.UUID](r.Converters(), r.Engine(), []byte(row.UUID.String))
+		if err != nil {
+			return nil, fmt.Errorf("user id=%d uuid: %w", row.ID.Int64, err)
+		}
+
+		user, err := mappers.NewUserMapper().DomainUserFromRow(&mappers.UserRow{
+			ID:        row.ID.Int64,
+			UUID:      userUUID.String(),
+			Email:     row.Email.String,
+			Username:  row.Username.String,
+			FirstName: row.FirstName.String,
+			LastName:  row.LastName.String,
+			Status:    row.Status.String,
+			Role:      row.Role.String,
+			Tags:      row.Tags,
+			CreatedAt: row.CreatedAt.Time,
+			UpdatedAt: row.CreatedAt.Time,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		result.Users = append(result.Users, user)
+	}
+
+	return result, nil
+}
+
+// CountByStatus counts active users per status.
+func (r *UserRepository) CountByStatus(ctx context.Context) (map[entities.UserStatus]int64, error) {
+	rows, err := r.queries().CountUsersByStatus(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("count users by status: %w", handleError(err, "count users by status"))
+	}
+
+	counts := make(map[entities.UserStatus]int64, len(rows))
+	for _, row := range rows {
+		counts[entities.UserStatus(row.Status)] = row.Total
+	}
+
+	return counts, nil
+}
+
+// GetStats returns aggregate user statistics.
+func (r *UserRepository) GetStats(ctx context.Context) (*entities.UserStats, error) {
+	row, err := r.queries().GetUserStats(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get user stats: %w", handleError(err, "get user stats"))
+	}
+
+	stats := &entities.UserStats{
+		TotalUsers:      row.TotalUsers,
+		ActiveUsers:     row.ActiveUsers,
+		InactiveUsers:   row.InactiveUsers,
+		SuspendedUsers:  row.SuspendedUsers,
+		VerifiedUsers:   row.VerifiedUsers,
+		UsersWithLogins: row.UsersWithLogins,
+		NewUsers30d:     row.NewUsers30d,
+		NewUsers7d:      row.NewUsers7d,
+	}
+	stats.ComputeRates()
+
+	return stats, nil
+}
+
+// GetCredentials returns the user of email with its password hash.
+func (r *UserRepository) GetCredentials(ctx context.Context, email entities.Email) (*entities.User, error) {
+	return r.GetByEmail(ctx, email)
+}
+
+// UpdatePassword stores a new password hash.
+func (r *UserRepository) UpdatePassword(
+	ctx context.Context,
+	id entities.UserID,
+	password entities.PasswordHash,
+) error {
+	err := r.queries().UpdatePassword(ctx, &mysqldb.UpdatePasswordParams{
+		PasswordHash: password.String(),
+		ID:           uint64(id),
+	})
+	if err != nil {
+		return fmt.Errorf("update password id=%v: %w", id, handleError(err, "update password"))
+	}
+
+	return nil
+}
+
+// MarkVerified marks a user's email as verified.
+func (r *UserRepository) MarkVerified(ctx context.Context, id entities.UserID) error {
+	err := r.queries().MarkUserVerified(ctx, uint64(id))
+	if err != nil {
+		return fmt.Errorf("mark verified id=%v: %w", id, handleError(err, "mark user verified"))
+	}
+
+	return nil
+}
+
+// ChangeStatus changes user status.
+func (r *UserRepository) ChangeStatus(
+	ctx context.Context,
+	id entities.UserID,
+	status entities.UserStatus,
+) error {
+	if !status.IsValid() {
+		return fmt.Errorf("status=%v: %w", status, entities.ErrInvalidUserStatus)
+	}
+
+	err := r.queries().UpdateUserStatus(ctx, &mysqldb.UpdateUserStatusParams{
+		Status: string(status),
+		ID:     uint64(id),
+	})
+	if err != nil {
+		return fmt.Errorf("change status id=%v: %w", id, handleError(err, "update user status"))
+	}
+
+	return nil
+}
+
+// Activate activates a user.
+func (r *UserRepository) Activate(ctx context.Context, id entities.UserID) error {
+	return r.ChangeStatus(ctx, id, entities.UserStatusActive)
+}
+
+// Deactivate deactivates a user.
+func (r *UserRepository) Deactivate(ctx context.Context, id entities.UserID) error {
+	return r.ChangeStatus(ctx, id, entities.UserStatusInactive)
+}
+
+// Suspend suspends a user.
+func (r *UserRepository) Suspend(ctx context.Context, id entities.UserID) error {
+	return r.ChangeStatus(ctx, id, entities.UserStatusSuspended)
+}
+
+// ChangeRole changes user role.
+func (r *UserRepository) ChangeRole(
+	ctx context.Context,
+	id entities.UserID,
+	role entities.UserRole,
+) error {
+	if !role.IsValid() {
+		return fmt.Errorf("role=%v: %w", role, entities.ErrInvalidUserRole)
+	}
+
+	err := r.queries().UpdateUserRole(ctx, &mysqldb.UpdateUserRoleParams{
+		Role: string(role),
+		ID:   uint64(id),
+	})
+	if err != nil {
+		return fmt.Errorf("change role id=%v: %w", id, handleError(err, "update user role"))
+	}
+
+	return nil
+}
+
+// GetByIDForUpdate locks the user row until the surrounding transaction ends.
+// It fails with adapters.ErrLockOutsideTransaction outside a transaction.
+func (r *UserRepository) GetByIDForUpdate(
+	ctx context.Context,
+	id entities.UserID,
+	opts ...repositories.LockOption,
+) (*entities.User, error) {
+	if !r.InTransaction() {
+		return nil, fmt.Errorf("GetByIDForUpdate id=%v: %w", id, adapters.ErrLockOutsideTransaction)
+	}
+
+	if len(opts) == 0 {
+		row, err := r.queries().GetUserByIDForUpdate(ctx, uint64(id))
+		if err != nil {
+			return nil, fmt.Errorf("id=%v: %w", id, handleError(err, "lock user"))
+		}
+
+		return domainUser(row)
+	}
+
+	query := adapters.WithLockingClause(mysqldb.GetUserByID, opts...)
+
+	row, err := scanUser(r.DB().QueryRowContext(ctx, query, uint64(id)))
+	if err != nil {
+		return nil, fmt.Errorf("id=%v: %w", id, handleError(err, "lock user"))
+	}
+
+	return domainUser(row)
+}
+
+// ClaimNext locks up to limit users in the given status with FOR UPDATE SKIP LOCKED.
+// It fails with adapters.ErrLockOutsideTransaction outside a transaction.
+func (r *UserRepository) ClaimNext(
+	ctx context.Context,
+	status entities.UserStatus,
+	limit int,
+) ([]*entities.User, error) {
+	if !r.InTransaction() {
+		return nil, fmt.Errorf("ClaimNext status=%v: %w", status, adapters.ErrLockOutsideTransaction)
+	}
+
+	if !status.IsValid() {
+		return nil, fmt.Errorf("status=%v: %w", status, entities.ErrInvalidUserStatus)
+	}
+
+	err := validation.ValidatePagination(limit, 0)
+	if err != nil {
+		return nil, fmt.Errorf("limit=%v: %w", limit, err)
+	}
+
+	rows, err := r.queries().ClaimUsersByStatus(ctx, &mysqldb.ClaimUsersByStatusParams{
+		Status: string(status),
+		Limit:  int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("claim users status=%v: %w", status, handleError(err, "claim users"))
+	}
+
+	return domainUsers(rows)
+}
+
+// handleError maps MySQL errors to domain errors.
+func handleError(err error, operation string) error {
+	return mysqldb.HandleDBError(
+		err,
+		operation,
+		entities.ErrUserNotFound,
+		entities.ErrUserAlreadyExists,
+		entities.ErrInvalidReference,
+	)
+}
+
+// booleanQuery turns free text into a FULLTEXT boolean-mode expression that
+// requires every word as a prefix. Operator characters are dropped so that
+// user input cannot change the query semantics.
+func booleanQuery(query string) string {
+	words := strings.FieldsFunc(query, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, word := range words {
+		words[i] = "+" + word + "*"
+	}
+
+	return strings.Join(words, " ")
+}
+
+// scanUser scans a row selected with the generated users column list.
+func scanUser(row *sql.Row) (*mysqldb.Users, error) {
+	var user mysqldb.Users
+
+	err := row.Scan(
+		&user.ID,
+		&user.Email,
+		&user.Username,
+		&user.PasswordHash,
+		&user.FirstName,
+		&user.LastName,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+		&user.LastLoginAt,
+		&user.IsActive,
+		&user.IsVerified,
+		&user.ProfileMetadata,
+		&user.Status,
+		&user.Role,
+		&user.Tags,
+		&user.UUID,
+		&user.DeletedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// domainUser converts a generated users row into a domain entity.
+func domainUser(row *mysqldb.Users) (*entities.User, error) {
+	return mappers.NewUserMapper().DomainUserFromMySQL(row)
+}
+
+// domainUsers converts generated users rows into domain entities.
+func domainUsers(rows []*mysqldb.Users) ([]*entities.User, error) {
+	return scanAll[*entities.User](rows)
+}
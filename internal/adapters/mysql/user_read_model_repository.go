@@ -0,0 +1,22 @@
+package mysql
+
+import (
+	"github.com/LarsArtmann/template-sqlc/internal/adapters"
+	"github.com/LarsArtmann/template-sqlc/internal/db/shared"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// UserReadModelRepository implements UserReadModelRepository for MySQL.
+type UserReadModelRepository struct {
+	*adapters.NotImplementedUserReadModelRepository
+
+	db shared.DBTX
+}
+
+// NewUserReadModelRepository creates a new MySQL user read model repository.
+func NewUserReadModelRepository(db shared.DBTX) repositories.UserReadModelRepository {
+	return &UserReadModelRepository{
+		NotImplementedUserReadModelRepository: adapters.NewNotImplementedUserReadModelRepository("MySQL"),
+		db:                                    db,
+	}
+}
@@ -2,6 +2,7 @@ package adapters
 
 import (
 	"context"
+	"time"
 
 	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
 	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
@@ -50,6 +51,14 @@ func (r *NotImplementedUserRepository) GetByUUID(
 	return nil, r.NotImplemented("GetByUUID")
 }
 
+// GetByIDs is a stub implementation.
+func (r *NotImplementedUserRepository) GetByIDs(
+	_ context.Context,
+	_ []entities.UserID,
+) ([]*entities.User, error) {
+	return nil, r.NotImplemented("GetByIDs")
+}
+
 // GetByEmail is a stub implementation.
 func (r *NotImplementedUserRepository) GetByEmail(
 	_ context.Context,
@@ -76,33 +85,33 @@ func (r *NotImplementedUserRepository) Delete(_ context.Context, _ entities.User
 	return r.NotImplemented("Delete")
 }
 
+// Restore is a stub implementation.
+func (r *NotImplementedUserRepository) Restore(_ context.Context, _ entities.UserID) error {
+	return r.NotImplemented("Restore")
+}
+
+// PurgeDeletedOlderThan is a stub implementation.
+func (r *NotImplementedUserRepository) PurgeDeletedOlderThan(_ context.Context, _ time.Time) (int64, error) {
+	return 0, r.NotImplemented("PurgeDeletedOlderThan")
+}
+
 // List is a stub implementation.
 func (r *NotImplementedUserRepository) List(
 	_ context.Context,
-	_ entities.UserStatus,
+	_ entities.UserFilter,
 	_, _ int,
 ) ([]*entities.User, error) {
 	return nil, r.NotImplemented("List")
 }
 
-// Search is a stub implementation.
-func (r *NotImplementedUserRepository) Search(
+// SearchWithFacets is a stub implementation.
+func (r *NotImplementedUserRepository) SearchWithFacets(
 	_ context.Context,
 	_ string,
 	_ entities.UserStatus,
 	_ int,
-) ([]*entities.User, error) {
-	return nil, r.NotImplemented("Search")
-}
-
-// SearchByTags is a stub implementation.
-func (r *NotImplementedUserRepository) SearchByTags(
-	_ context.Context,
-	_ []string,
-	_ entities.UserStatus,
-	_, _ int,
-) ([]*entities.User, error) {
-	return nil, r.NotImplemented("SearchByTags")
+) (*entities.UserSearchResult, error) {
+	return nil, r.NotImplemented("SearchWithFacets")
 }
 
 // CountByStatus is a stub implementation.
@@ -117,13 +126,9 @@ func (r *NotImplementedUserRepository) GetStats(_ context.Context) (*entities.Us
 	return nil, r.NotImplemented("GetStats")
 }
 
-// VerifyCredentials is a stub implementation.
-func (r *NotImplementedUserRepository) VerifyCredentials(
-	_ context.Context,
-	_ entities.Email,
-	_ entities.PasswordHash,
-) (*entities.User, error) {
-	return nil, r.NotImplemented("VerifyCredentials")
+// GetCredentials is a stub implementation.
+func (r *NotImplementedUserRepository) GetCredentials(_ context.Context, _ entities.Email) (*entities.User, error) {
+	return nil, r.NotImplemented("GetCredentials")
 }
 
 // UpdatePassword is a stub implementation.
@@ -149,6 +154,35 @@ func (r *NotImplementedUserRepository) ChangeStatus(
 	return r.NotImplemented("ChangeStatus")
 }
 
+// ListPage is a stub implementation.
+func (r *NotImplementedUserRepository) ListPage(
+	_ context.Context,
+	_ entities.UserFilter,
+	_ string,
+	_ int,
+) (*entities.UserPage, error) {
+	return nil, r.NotImplemented("ListPage")
+}
+
+// CreateBatch is a stub implementation.
+func (r *NotImplementedUserRepository) CreateBatch(_ context.Context, _ []*entities.User) error {
+	return r.NotImplemented("CreateBatch")
+}
+
+// UpdateStatusBatch is a stub implementation.
+func (r *NotImplementedUserRepository) UpdateStatusBatch(
+	_ context.Context,
+	_ []entities.UserID,
+	_ entities.UserStatus,
+) (int64, error) {
+	return 0, r.NotImplemented("UpdateStatusBatch")
+}
+
+// DeleteBatch is a stub implementation.
+func (r *NotImplementedUserRepository) DeleteBatch(_ context.Context, _ []entities.UserID) (int64, error) {
+	return 0, r.NotImplemented("DeleteBatch")
+}
+
 // Activate is a stub implementation.
 func (r *NotImplementedUserRepository) Activate(_ context.Context, _ entities.UserID) error {
 	return r.NotImplemented("Activate")
@@ -173,9 +207,70 @@ func (r *NotImplementedUserRepository) ChangeRole(
 	return r.NotImplemented("ChangeRole")
 }
 
+// GetByIDForUpdate is a stub implementation.
+func (r *NotImplementedUserRepository) GetByIDForUpdate(
+	_ context.Context,
+	_ entities.UserID,
+	_ ...repositories.LockOption,
+) (*entities.User, error) {
+	return nil, r.NotImplemented("GetByIDForUpdate")
+}
+
+// ClaimNext is a stub implementation.
+func (r *NotImplementedUserRepository) ClaimNext(
+	_ context.Context,
+	_ entities.UserStatus,
+	_ int,
+) ([]*entities.User, error) {
+	return nil, r.NotImplemented("ClaimNext")
+}
+
 // Ensure NotImplementedUserRepository implements UserRepository.
 var _ repositories.UserRepository = (*NotImplementedUserRepository)(nil)
 
+// NotImplementedUserHistoryRepository provides stub implementations for UserHistoryRepository methods.
+type NotImplementedUserHistoryRepository struct {
+	NotImplementedRepository
+
+	dbName string
+}
+
+// NewNotImplementedUserHistoryRepository creates a new NotImplementedUserHistoryRepository.
+func NewNotImplementedUserHistoryRepository(dbName string) *NotImplementedUserHistoryRepository {
+	return &NotImplementedUserHistoryRepository{dbName: dbName}
+}
+
+// NotImplemented returns an error indicating the method is not implemented.
+func (r *NotImplementedUserHistoryRepository) NotImplemented(method string) error {
+	return entities.StubNotImplemented(method, r.dbName)
+}
+
+// GetUserAsOf is a stub implementation.
+func (r *NotImplementedUserHistoryRepository) GetUserAsOf(
+	_ context.Context,
+	_ entities.UserID,
+	_ time.Time,
+) (*entities.UserSnapshot, error) {
+	return nil, r.NotImplemented("GetUserAsOf")
+}
+
+// ListVersions is a stub implementation.
+func (r *NotImplementedUserHistoryRepository) ListVersions(
+	_ context.Context,
+	_ entities.UserID,
+	_ int,
+) ([]*entities.UserSnapshot, error) {
+	return nil, r.NotImplemented("ListVersions")
+}
+
+// DeleteVersions is a stub implementation.
+func (r *NotImplementedUserHistoryRepository) DeleteVersions(_ context.Context, _ entities.UserID) (int64, error) {
+	return 0, r.NotImplemented("DeleteVersions")
+}
+
+// Ensure NotImplementedUserHistoryRepository implements UserHistoryRepository.
+var _ repositories.UserHistoryRepository = (*NotImplementedUserHistoryRepository)(nil)
+
 // NotImplementedSessionRepository provides stub implementations for SessionRepository methods.
 type NotImplementedSessionRepository struct {
 	NotImplementedRepository
@@ -206,6 +301,14 @@ func (r *NotImplementedSessionRepository) GetByToken(
 	return nil, r.NotImplemented("GetByToken")
 }
 
+// GetByRefreshToken is a stub implementation.
+func (r *NotImplementedSessionRepository) GetByRefreshToken(
+	_ context.Context,
+	_ entities.RefreshToken,
+) (*entities.UserSession, error) {
+	return nil, r.NotImplemented("GetByRefreshToken")
+}
+
 // GetByUserID is a stub implementation.
 func (r *NotImplementedSessionRepository) GetByUserID(
 	_ context.Context,
@@ -220,6 +323,15 @@ func (r *NotImplementedSessionRepository) Update(_ context.Context, _ *entities.
 	return r.NotImplemented("Update")
 }
 
+// UpdateIfCurrent is a stub implementation.
+func (r *NotImplementedSessionRepository) UpdateIfCurrent(
+	_ context.Context,
+	_ *entities.UserSession,
+	_ entities.RefreshToken,
+) error {
+	return r.NotImplemented("UpdateIfCurrent")
+}
+
 // Delete is a stub implementation.
 func (r *NotImplementedSessionRepository) Delete(_ context.Context, _ entities.SessionID) error {
 	return r.NotImplemented("Delete")
@@ -263,3 +375,882 @@ func (r *NotImplementedSessionRepository) GetSessionStats(
 
 // Ensure NotImplementedSessionRepository implements SessionRepository.
 var _ repositories.SessionRepository = (*NotImplementedSessionRepository)(nil)
+
+// NotImplementedAuditRepository provides stub implementations for AuditRepository methods.
+type NotImplementedAuditRepository struct {
+	NotImplementedRepository
+
+	dbName string
+}
+
+// NewNotImplementedAuditRepository creates a new NotImplementedAuditRepository.
+func NewNotImplementedAuditRepository(dbName string) *NotImplementedAuditRepository {
+	return &NotImplementedAuditRepository{dbName: dbName}
+}
+
+// NotImplemented returns an error indicating the method is not implemented.
+func (r *NotImplementedAuditRepository) NotImplemented(method string) error {
+	return entities.StubNotImplemented(method, r.dbName)
+}
+
+// Record is a stub implementation.
+func (r *NotImplementedAuditRepository) Record(_ context.Context, _ *entities.AuditLog) error {
+	return r.NotImplemented("Record")
+}
+
+// List is a stub implementation.
+func (r *NotImplementedAuditRepository) List(
+	_ context.Context,
+	_ entities.AuditFilter,
+) ([]*entities.AuditLog, error) {
+	return nil, r.NotImplemented("List")
+}
+
+// AnonymizeUser is a stub implementation.
+func (r *NotImplementedAuditRepository) AnonymizeUser(_ context.Context, _ entities.UserID) (int64, error) {
+	return 0, r.NotImplemented("AnonymizeUser")
+}
+
+// Ensure NotImplementedAuditRepository implements AuditRepository.
+var _ repositories.AuditRepository = (*NotImplementedAuditRepository)(nil)
+
+// NotImplementedCRUDRepository provides stub implementations for the
+// methods of repositories.Repository.
+type NotImplementedCRUDRepository[T any, ID comparable] struct {
+	NotImplementedRepository
+
+	dbName string
+}
+
+// NewNotImplementedCRUDRepository creates a new NotImplementedCRUDRepository.
+func NewNotImplementedCRUDRepository[T any, ID comparable](dbName string) *NotImplementedCRUDRepository[T, ID] {
+	return &NotImplementedCRUDRepository[T, ID]{dbName: dbName}
+}
+
+// NotImplemented returns an error indicating the method is not implemented.
+func (r *NotImplementedCRUDRepository[T, ID]) NotImplemented(method string) error {
+	return entities.StubNotImplemented(method, r.dbName)
+}
+
+// Create is a stub implementation.
+func (r *NotImplementedCRUDRepository[T, ID]) Create(_ context.Context, _ *T) error {
+	return r.NotImplemented("Create")
+}
+
+// GetByID is a stub implementation.
+func (r *NotImplementedCRUDRepository[T, ID]) GetByID(_ context.Context, _ ID) (*T, error) {
+	return nil, r.NotImplemented("GetByID")
+}
+
+// Update is a stub implementation.
+func (r *NotImplementedCRUDRepository[T, ID]) Update(_ context.Context, _ *T) error {
+	return r.NotImplemented("Update")
+}
+
+// Delete is a stub implementation.
+func (r *NotImplementedCRUDRepository[T, ID]) Delete(_ context.Context, _ ID) error {
+	return r.NotImplemented("Delete")
+}
+
+// List is a stub implementation.
+func (r *NotImplementedCRUDRepository[T, ID]) List(_ context.Context, _, _ int) ([]*T, error) {
+	return nil, r.NotImplemented("List")
+}
+
+// NotImplementedOrganizationRepository provides stub implementations for OrganizationRepository methods.
+type NotImplementedOrganizationRepository struct {
+	*NotImplementedCRUDRepository[entities.Organization, entities.OrganizationID]
+
+	dbName string
+}
+
+// NewNotImplementedOrganizationRepository creates a new NotImplementedOrganizationRepository.
+func NewNotImplementedOrganizationRepository(dbName string) *NotImplementedOrganizationRepository {
+	return &NotImplementedOrganizationRepository{
+		NotImplementedCRUDRepository: NewNotImplementedCRUDRepository[
+			entities.Organization, entities.OrganizationID,
+		](dbName),
+		dbName: dbName,
+	}
+}
+
+// NotImplemented returns an error indicating the method is not implemented.
+func (r *NotImplementedOrganizationRepository) NotImplemented(method string) error {
+	return entities.StubNotImplemented(method, r.dbName)
+}
+
+// GetBySlug is a stub implementation.
+func (r *NotImplementedOrganizationRepository) GetBySlug(
+	_ context.Context,
+	_ entities.OrganizationSlug,
+) (*entities.Organization, error) {
+	return nil, r.NotImplemented("GetBySlug")
+}
+
+// ListByUser is a stub implementation.
+func (r *NotImplementedOrganizationRepository) ListByUser(
+	_ context.Context,
+	_ entities.UserID,
+	_ int,
+) ([]*entities.Organization, error) {
+	return nil, r.NotImplemented("ListByUser")
+}
+
+// Ensure NotImplementedOrganizationRepository implements OrganizationRepository.
+var _ repositories.OrganizationRepository = (*NotImplementedOrganizationRepository)(nil)
+
+// NotImplementedMembershipRepository provides stub implementations for MembershipRepository methods.
+type NotImplementedMembershipRepository struct {
+	NotImplementedRepository
+
+	dbName string
+}
+
+// NewNotImplementedMembershipRepository creates a new NotImplementedMembershipRepository.
+func NewNotImplementedMembershipRepository(dbName string) *NotImplementedMembershipRepository {
+	return &NotImplementedMembershipRepository{dbName: dbName}
+}
+
+// NotImplemented returns an error indicating the method is not implemented.
+func (r *NotImplementedMembershipRepository) NotImplemented(method string) error {
+	return entities.StubNotImplemented(method, r.dbName)
+}
+
+// Create is a stub implementation.
+func (r *NotImplementedMembershipRepository) Create(_ context.Context, _ *entities.Membership) error {
+	return r.NotImplemented("Create")
+}
+
+// Get is a stub implementation.
+func (r *NotImplementedMembershipRepository) Get(
+	_ context.Context,
+	_ entities.OrganizationID,
+	_ entities.UserID,
+) (*entities.Membership, error) {
+	return nil, r.NotImplemented("Get")
+}
+
+// Update is a stub implementation.
+func (r *NotImplementedMembershipRepository) Update(_ context.Context, _ *entities.Membership) error {
+	return r.NotImplemented("Update")
+}
+
+// Delete is a stub implementation.
+func (r *NotImplementedMembershipRepository) Delete(
+	_ context.Context,
+	_ entities.OrganizationID,
+	_ entities.UserID,
+) error {
+	return r.NotImplemented("Delete")
+}
+
+// ListByOrganization is a stub implementation.
+func (r *NotImplementedMembershipRepository) ListByOrganization(
+	_ context.Context,
+	_ entities.OrganizationID,
+	_, _ int,
+) ([]*entities.Membership, error) {
+	return nil, r.NotImplemented("ListByOrganization")
+}
+
+// CountAdmins is a stub implementation.
+func (r *NotImplementedMembershipRepository) CountAdmins(
+	_ context.Context,
+	_ entities.OrganizationID,
+) (int64, error) {
+	return 0, r.NotImplemented("CountAdmins")
+}
+
+// RoleInOrganization is a stub implementation.
+func (r *NotImplementedMembershipRepository) RoleInOrganization(
+	_ context.Context,
+	_ entities.UserID,
+	_ entities.OrganizationID,
+) (entities.UserRole, error) {
+	return "", r.NotImplemented("RoleInOrganization")
+}
+
+// Ensure NotImplementedMembershipRepository implements MembershipRepository.
+var _ repositories.MembershipRepository = (*NotImplementedMembershipRepository)(nil)
+
+// NotImplementedIdentityRepository provides stub implementations for IdentityRepository methods.
+type NotImplementedIdentityRepository struct {
+	NotImplementedRepository
+
+	dbName string
+}
+
+// NewNotImplementedIdentityRepository creates a new NotImplementedIdentityRepository.
+func NewNotImplementedIdentityRepository(dbName string) *NotImplementedIdentityRepository {
+	return &NotImplementedIdentityRepository{dbName: dbName}
+}
+
+// NotImplemented returns an error indicating the method is not implemented.
+func (r *NotImplementedIdentityRepository) NotImplemented(method string) error {
+	return entities.StubNotImplemented(method, r.dbName)
+}
+
+// Create is a stub implementation.
+func (r *NotImplementedIdentityRepository) Create(_ context.Context, _ *entities.UserIdentity) error {
+	return r.NotImplemented("Create")
+}
+
+// GetByProviderSubject is a stub implementation.
+func (r *NotImplementedIdentityRepository) GetByProviderSubject(
+	_ context.Context,
+	_ entities.IdentityProvider,
+	_ string,
+) (*entities.UserIdentity, error) {
+	return nil, r.NotImplemented("GetByProviderSubject")
+}
+
+// RecordLogin is a stub implementation.
+func (r *NotImplementedIdentityRepository) RecordLogin(_ context.Context, _ *entities.UserIdentity) error {
+	return r.NotImplemented("RecordLogin")
+}
+
+// ListByUser is a stub implementation.
+func (r *NotImplementedIdentityRepository) ListByUser(
+	_ context.Context,
+	_ entities.UserID,
+) ([]*entities.UserIdentity, error) {
+	return nil, r.NotImplemented("ListByUser")
+}
+
+// Delete is a stub implementation.
+func (r *NotImplementedIdentityRepository) Delete(
+	_ context.Context,
+	_ entities.UserID,
+	_ entities.IdentityProvider,
+) error {
+	return r.NotImplemented("Delete")
+}
+
+// Ensure NotImplementedIdentityRepository implements IdentityRepository.
+var _ repositories.IdentityRepository = (*NotImplementedIdentityRepository)(nil)
+
+// NotImplementedNotificationPreferenceRepository provides stub implementations for
+// NotificationPreferenceRepository methods.
+type NotImplementedNotificationPreferenceRepository struct {
+	NotImplementedRepository
+
+	dbName string
+}
+
+// NewNotImplementedNotificationPreferenceRepository creates a new NotImplementedNotificationPreferenceRepository.
+func NewNotImplementedNotificationPreferenceRepository(dbName string) *NotImplementedNotificationPreferenceRepository {
+	return &NotImplementedNotificationPreferenceRepository{dbName: dbName}
+}
+
+// NotImplemented returns an error indicating the method is not implemented.
+func (r *NotImplementedNotificationPreferenceRepository) NotImplemented(method string) error {
+	return entities.StubNotImplemented(method, r.dbName)
+}
+
+// ListByUser is a stub implementation.
+func (r *NotImplementedNotificationPreferenceRepository) ListByUser(
+	_ context.Context,
+	_ entities.UserID,
+) ([]*entities.NotificationPreference, error) {
+	return nil, r.NotImplemented("ListByUser")
+}
+
+// Upsert is a stub implementation.
+func (r *NotImplementedNotificationPreferenceRepository) Upsert(
+	_ context.Context,
+	_ *entities.NotificationPreference,
+) error {
+	return r.NotImplemented("Upsert")
+}
+
+// Delete is a stub implementation.
+func (r *NotImplementedNotificationPreferenceRepository) Delete(
+	_ context.Context,
+	_ entities.UserID,
+	_ entities.NotificationChannel,
+	_ entities.NotificationTopic,
+) error {
+	return r.NotImplemented("Delete")
+}
+
+// Ensure NotImplementedNotificationPreferenceRepository implements NotificationPreferenceRepository.
+var _ repositories.NotificationPreferenceRepository = (*NotImplementedNotificationPreferenceRepository)(nil)
+
+// NotImplementedIdentityChangeRepository provides stub implementations for
+// IdentityChangeRepository methods.
+type NotImplementedIdentityChangeRepository struct {
+	NotImplementedRepository
+
+	dbName string
+}
+
+// NewNotImplementedIdentityChangeRepository creates a new NotImplementedIdentityChangeRepository.
+func NewNotImplementedIdentityChangeRepository(dbName string) *NotImplementedIdentityChangeRepository {
+	return &NotImplementedIdentityChangeRepository{dbName: dbName}
+}
+
+// NotImplemented returns an error indicating the method is not implemented.
+func (r *NotImplementedIdentityChangeRepository) NotImplemented(method string) error {
+	return entities.StubNotImplemented(method, r.dbName)
+}
+
+// Create is a stub implementation.
+func (r *NotImplementedIdentityChangeRepository) Create(_ context.Context, _ *entities.IdentityChange) error {
+	return r.NotImplemented("Create")
+}
+
+// GetByToken is a stub implementation.
+func (r *NotImplementedIdentityChangeRepository) GetByToken(
+	_ context.Context,
+	_ entities.IdentityChangeToken,
+) (*entities.IdentityChange, error) {
+	return nil, r.NotImplemented("GetByToken")
+}
+
+// Update is a stub implementation.
+func (r *NotImplementedIdentityChangeRepository) Update(_ context.Context, _ *entities.IdentityChange) error {
+	return r.NotImplemented("Update")
+}
+
+// ListByUser is a stub implementation.
+func (r *NotImplementedIdentityChangeRepository) ListByUser(
+	_ context.Context,
+	_ entities.UserID,
+) ([]*entities.IdentityChange, error) {
+	return nil, r.NotImplemented("ListByUser")
+}
+
+// DeleteByUser is a stub implementation.
+func (r *NotImplementedIdentityChangeRepository) DeleteByUser(_ context.Context, _ entities.UserID) (int64, error) {
+	return 0, r.NotImplemented("DeleteByUser")
+}
+
+// Ensure NotImplementedIdentityChangeRepository implements IdentityChangeRepository.
+var _ repositories.IdentityChangeRepository = (*NotImplementedIdentityChangeRepository)(nil)
+
+// NotImplementedModerationRepository provides stub implementations for
+// ModerationRepository methods.
+type NotImplementedModerationRepository struct {
+	NotImplementedRepository
+
+	dbName string
+}
+
+// NewNotImplementedModerationRepository creates a new NotImplementedModerationRepository.
+func NewNotImplementedModerationRepository(dbName string) *NotImplementedModerationRepository {
+	return &NotImplementedModerationRepository{dbName: dbName}
+}
+
+// NotImplemented returns an error indicating the method is not implemented.
+func (r *NotImplementedModerationRepository) NotImplemented(method string) error {
+	return entities.StubNotImplemented(method, r.dbName)
+}
+
+// CreateAction is a stub implementation.
+func (r *NotImplementedModerationRepository) CreateAction(_ context.Context, _ *entities.ModerationAction) error {
+	return r.NotImplemented("CreateAction")
+}
+
+// GetAction is a stub implementation.
+func (r *NotImplementedModerationRepository) GetAction(
+	_ context.Context,
+	_ entities.ModerationActionID,
+) (*entities.ModerationAction, error) {
+	return nil, r.NotImplemented("GetAction")
+}
+
+// LiftAction is a stub implementation.
+func (r *NotImplementedModerationRepository) LiftAction(_ context.Context, _ *entities.ModerationAction) error {
+	return r.NotImplemented("LiftAction")
+}
+
+// GetActiveSuspension is a stub implementation.
+func (r *NotImplementedModerationRepository) GetActiveSuspension(
+	_ context.Context,
+	_ entities.UserID,
+) (*entities.ModerationAction, error) {
+	return nil, r.NotImplemented("GetActiveSuspension")
+}
+
+// ListActionsByUser is a stub implementation.
+func (r *NotImplementedModerationRepository) ListActionsByUser(
+	_ context.Context,
+	_ entities.UserID,
+) ([]*entities.ModerationAction, error) {
+	return nil, r.NotImplemented("ListActionsByUser")
+}
+
+// ListLapsedSuspensions is a stub implementation.
+func (r *NotImplementedModerationRepository) ListLapsedSuspensions(
+	_ context.Context,
+	_ time.Time,
+	_ int,
+) ([]*entities.ModerationAction, error) {
+	return nil, r.NotImplemented("ListLapsedSuspensions")
+}
+
+// CreateAppeal is a stub implementation.
+func (r *NotImplementedModerationRepository) CreateAppeal(_ context.Context, _ *entities.Appeal) error {
+	return r.NotImplemented("CreateAppeal")
+}
+
+// GetAppeal is a stub implementation.
+func (r *NotImplementedModerationRepository) GetAppeal(
+	_ context.Context,
+	_ entities.AppealID,
+) (*entities.Appeal, error) {
+	return nil, r.NotImplemented("GetAppeal")
+}
+
+// DecideAppeal is a stub implementation.
+func (r *NotImplementedModerationRepository) DecideAppeal(_ context.Context, _ *entities.Appeal) error {
+	return r.NotImplemented("DecideAppeal")
+}
+
+// ListPendingAppeals is a stub implementation.
+func (r *NotImplementedModerationRepository) ListPendingAppeals(_ context.Context, _ int) ([]*entities.Appeal, error) {
+	return nil, r.NotImplemented("ListPendingAppeals")
+}
+
+// DeleteByUser is a stub implementation.
+func (r *NotImplementedModerationRepository) DeleteByUser(_ context.Context, _ entities.UserID) (int64, error) {
+	return 0, r.NotImplemented("DeleteByUser")
+}
+
+// ListAppealsByUser is a stub implementation.
+func (r *NotImplementedModerationRepository) ListAppealsByUser(
+	_ context.Context,
+	_ entities.UserID,
+) ([]*entities.Appeal, error) {
+	return nil, r.NotImplemented("ListAppealsByUser")
+}
+
+// Ensure NotImplementedModerationRepository implements ModerationRepository.
+var _ repositories.ModerationRepository = (*NotImplementedModerationRepository)(nil)
+
+// NotImplementedAccountDeletionRepository provides stub implementations for
+// AccountDeletionRepository methods.
+type NotImplementedAccountDeletionRepository struct {
+	NotImplementedRepository
+
+	dbName string
+}
+
+// NewNotImplementedAccountDeletionRepository creates a new NotImplementedAccountDeletionRepository.
+func NewNotImplementedAccountDeletionRepository(dbName string) *NotImplementedAccountDeletionRepository {
+	return &NotImplementedAccountDeletionRepository{dbName: dbName}
+}
+
+// NotImplemented returns an error indicating the method is not implemented.
+func (r *NotImplementedAccountDeletionRepository) NotImplemented(method string) error {
+	return entities.StubNotImplemented(method, r.dbName)
+}
+
+// Create is a stub implementation.
+func (r *NotImplementedAccountDeletionRepository) Create(_ context.Context, _ *entities.AccountDeletion) error {
+	return r.NotImplemented("Create")
+}
+
+// GetByUser is a stub implementation.
+func (r *NotImplementedAccountDeletionRepository) GetByUser(
+	_ context.Context,
+	_ entities.UserID,
+) (*entities.AccountDeletion, error) {
+	return nil, r.NotImplemented("GetByUser")
+}
+
+// Delete is a stub implementation.
+func (r *NotImplementedAccountDeletionRepository) Delete(_ context.Context, _ entities.UserID) error {
+	return r.NotImplemented("Delete")
+}
+
+// ListDue is a stub implementation.
+func (r *NotImplementedAccountDeletionRepository) ListDue(
+	_ context.Context,
+	_ time.Time,
+	_ int,
+) ([]*entities.AccountDeletion, error) {
+	return nil, r.NotImplemented("ListDue")
+}
+
+// Ensure NotImplementedAccountDeletionRepository implements AccountDeletionRepository.
+var _ repositories.AccountDeletionRepository = (*NotImplementedAccountDeletionRepository)(nil)
+
+// NotImplementedJobRepository provides stub implementations for JobRepository methods.
+type NotImplementedJobRepository struct {
+	NotImplementedRepository
+
+	dbName string
+}
+
+// NewNotImplementedJobRepository creates a new NotImplementedJobRepository.
+func NewNotImplementedJobRepository(dbName string) *NotImplementedJobRepository {
+	return &NotImplementedJobRepository{dbName: dbName}
+}
+
+// NotImplemented returns an error indicating the method is not implemented.
+func (r *NotImplementedJobRepository) NotImplemented(method string) error {
+	return entities.StubNotImplemented(method, r.dbName)
+}
+
+// Enqueue is a stub implementation.
+func (r *NotImplementedJobRepository) Enqueue(_ context.Context, _ *entities.Job) error {
+	return r.NotImplemented("Enqueue")
+}
+
+// Get is a stub implementation.
+func (r *NotImplementedJobRepository) Get(_ context.Context, _ entities.JobID) (*entities.Job, error) {
+	return nil, r.NotImplemented("Get")
+}
+
+// Claim is a stub implementation.
+func (r *NotImplementedJobRepository) Claim(
+	_ context.Context,
+	_ time.Time,
+	_ time.Duration,
+	_ int,
+) ([]*entities.Job, error) {
+	return nil, r.NotImplemented("Claim")
+}
+
+// Complete is a stub implementation.
+func (r *NotImplementedJobRepository) Complete(_ context.Context, _ *entities.Job) error {
+	return r.NotImplemented("Complete")
+}
+
+// Retry is a stub implementation.
+func (r *NotImplementedJobRepository) Retry(_ context.Context, _ *entities.Job, _ time.Time, _ string) error {
+	return r.NotImplemented("Retry")
+}
+
+// Fail is a stub implementation.
+func (r *NotImplementedJobRepository) Fail(_ context.Context, _ *entities.Job, _ string) error {
+	return r.NotImplemented("Fail")
+}
+
+// DeleteDone is a stub implementation.
+func (r *NotImplementedJobRepository) DeleteDone(_ context.Context, _ time.Time) (int64, error) {
+	return 0, r.NotImplemented("DeleteDone")
+}
+
+// Ensure NotImplementedJobRepository implements JobRepository.
+var _ repositories.JobRepository = (*NotImplementedJobRepository)(nil)
+
+// NotImplementedIdempotencyRepository provides stub implementations for IdempotencyRepository methods.
+type NotImplementedIdempotencyRepository struct {
+	NotImplementedRepository
+
+	dbName string
+}
+
+// NewNotImplementedIdempotencyRepository creates a new NotImplementedIdempotencyRepository.
+func NewNotImplementedIdempotencyRepository(dbName string) *NotImplementedIdempotencyRepository {
+	return &NotImplementedIdempotencyRepository{dbName: dbName}
+}
+
+// NotImplemented returns an error indicating the method is not implemented.
+func (r *NotImplementedIdempotencyRepository) NotImplemented(method string) error {
+	return entities.StubNotImplemented(method, r.dbName)
+}
+
+// Reserve is a stub implementation.
+func (r *NotImplementedIdempotencyRepository) Reserve(_ context.Context, _ *entities.IdempotencyRecord) error {
+	return r.NotImplemented("Reserve")
+}
+
+// Get is a stub implementation.
+func (r *NotImplementedIdempotencyRepository) Get(
+	_ context.Context,
+	_ entities.IdempotencyKey,
+) (*entities.IdempotencyRecord, error) {
+	return nil, r.NotImplemented("Get")
+}
+
+// Complete is a stub implementation.
+func (r *NotImplementedIdempotencyRepository) Complete(_ context.Context, _ *entities.IdempotencyRecord) error {
+	return r.NotImplemented("Complete")
+}
+
+// Release is a stub implementation.
+func (r *NotImplementedIdempotencyRepository) Release(_ context.Context, _ entities.IdempotencyKey) error {
+	return r.NotImplemented("Release")
+}
+
+// DeleteExpired is a stub implementation.
+func (r *NotImplementedIdempotencyRepository) DeleteExpired(_ context.Context, _ time.Time) (int64, error) {
+	return 0, r.NotImplemented("DeleteExpired")
+}
+
+// Ensure NotImplementedIdempotencyRepository implements IdempotencyRepository.
+var _ repositories.IdempotencyRepository = (*NotImplementedIdempotencyRepository)(nil)
+
+// NotImplementedUserPreferenceRepository provides stub implementations for
+// UserPreferenceRepository methods.
+type NotImplementedUserPreferenceRepository struct {
+	NotImplementedRepository
+
+	dbName string
+}
+
+// NewNotImplementedUserPreferenceRepository creates a new NotImplementedUserPreferenceRepository.
+func NewNotImplementedUserPreferenceRepository(dbName string) *NotImplementedUserPreferenceRepository {
+	return &NotImplementedUserPreferenceRepository{dbName: dbName}
+}
+
+// NotImplemented returns an error indicating the method is not implemented.
+func (r *NotImplementedUserPreferenceRepository) NotImplemented(method string) error {
+	return entities.StubNotImplemented(method, r.dbName)
+}
+
+// Get is a stub implementation.
+func (r *NotImplementedUserPreferenceRepository) Get(
+	_ context.Context,
+	_ entities.UserID,
+) (*entities.UserPreferences, error) {
+	return nil, r.NotImplemented("Get")
+}
+
+// Patch is a stub implementation.
+func (r *NotImplementedUserPreferenceRepository) Patch(
+	_ context.Context,
+	_ entities.UserID,
+	_ entities.PreferencePatch,
+) (*entities.UserPreferences, error) {
+	return nil, r.NotImplemented("Patch")
+}
+
+// Delete is a stub implementation.
+func (r *NotImplementedUserPreferenceRepository) Delete(_ context.Context, _ entities.UserID) error {
+	return r.NotImplemented("Delete")
+}
+
+// Ensure NotImplementedUserPreferenceRepository implements UserPreferenceRepository.
+var _ repositories.UserPreferenceRepository = (*NotImplementedUserPreferenceRepository)(nil)
+
+// NotImplementedActivityRepository provides stub implementations for
+// ActivityRepository methods.
+type NotImplementedActivityRepository struct {
+	NotImplementedRepository
+
+	dbName string
+}
+
+// NewNotImplementedActivityRepository creates a new NotImplementedActivityRepository.
+func NewNotImplementedActivityRepository(dbName string) *NotImplementedActivityRepository {
+	return &NotImplementedActivityRepository{dbName: dbName}
+}
+
+// NotImplemented returns an error indicating the method is not implemented.
+func (r *NotImplementedActivityRepository) NotImplemented(method string) error {
+	return entities.StubNotImplemented(method, r.dbName)
+}
+
+// Record is a stub implementation.
+func (r *NotImplementedActivityRepository) Record(_ context.Context, _ *entities.ActivityEntry) error {
+	return r.NotImplemented("Record")
+}
+
+// ListPage is a stub implementation.
+func (r *NotImplementedActivityRepository) ListPage(
+	_ context.Context,
+	_ entities.UserID,
+	_ string,
+	_ int,
+) (*entities.ActivityPage, error) {
+	return nil, r.NotImplemented("ListPage")
+}
+
+// DeleteOlderThan is a stub implementation.
+func (r *NotImplementedActivityRepository) DeleteOlderThan(_ context.Context, _ time.Time) (int64, error) {
+	return 0, r.NotImplemented("DeleteOlderThan")
+}
+
+// DeleteByUser is a stub implementation.
+func (r *NotImplementedActivityRepository) DeleteByUser(_ context.Context, _ entities.UserID) (int64, error) {
+	return 0, r.NotImplemented("DeleteByUser")
+}
+
+// Ensure NotImplementedActivityRepository implements ActivityRepository.
+var _ repositories.ActivityRepository = (*NotImplementedActivityRepository)(nil)
+
+// NotImplementedLoginHistoryRepository provides stub implementations for
+// LoginHistoryRepository methods.
+type NotImplementedLoginHistoryRepository struct {
+	NotImplementedRepository
+
+	dbName string
+}
+
+// NewNotImplementedLoginHistoryRepository creates a new NotImplementedLoginHistoryRepository.
+func NewNotImplementedLoginHistoryRepository(dbName string) *NotImplementedLoginHistoryRepository {
+	return &NotImplementedLoginHistoryRepository{dbName: dbName}
+}
+
+// NotImplemented returns an error indicating the method is not implemented.
+func (r *NotImplementedLoginHistoryRepository) NotImplemented(method string) error {
+	return entities.StubNotImplemented(method, r.dbName)
+}
+
+// Record is a stub implementation.
+func (r *NotImplementedLoginHistoryRepository) Record(_ context.Context, _ *entities.LoginAttempt) error {
+	return r.NotImplemented("Record")
+}
+
+// ListByUser is a stub implementation.
+func (r *NotImplementedLoginHistoryRepository) ListByUser(
+	_ context.Context,
+	_ entities.UserID,
+	_ int,
+) ([]*entities.LoginAttempt, error) {
+	return nil, r.NotImplemented("ListByUser")
+}
+
+// ListFailuresByIP is a stub implementation.
+func (r *NotImplementedLoginHistoryRepository) ListFailuresByIP(
+	_ context.Context,
+	_ string,
+	_ time.Time,
+	_ int,
+) ([]*entities.LoginAttempt, error) {
+	return nil, r.NotImplemented("ListFailuresByIP")
+}
+
+// DeleteByUser is a stub implementation.
+func (r *NotImplementedLoginHistoryRepository) DeleteByUser(_ context.Context, _ entities.UserID) (int64, error) {
+	return 0, r.NotImplemented("DeleteByUser")
+}
+
+// Ensure NotImplementedLoginHistoryRepository implements LoginHistoryRepository.
+var _ repositories.LoginHistoryRepository = (*NotImplementedLoginHistoryRepository)(nil)
+
+// NotImplementedUserEventStore provides stub implementations for
+// UserEventStore methods.
+type NotImplementedUserEventStore struct {
+	NotImplementedRepository
+
+	dbName string
+}
+
+// NewNotImplementedUserEventStore creates a new NotImplementedUserEventStore.
+func NewNotImplementedUserEventStore(dbName string) *NotImplementedUserEventStore {
+	return &NotImplementedUserEventStore{dbName: dbName}
+}
+
+// NotImplemented returns an error indicating the method is not implemented.
+func (r *NotImplementedUserEventStore) NotImplemented(method string) error {
+	return entities.StubNotImplemented(method, r.dbName)
+}
+
+// Append is a stub implementation.
+func (r *NotImplementedUserEventStore) Append(
+	_ context.Context,
+	_ entities.UserID,
+	_ int64,
+	_ []*entities.UserStreamEvent,
+) error {
+	return r.NotImplemented("Append")
+}
+
+// Load is a stub implementation.
+func (r *NotImplementedUserEventStore) Load(
+	_ context.Context,
+	_ entities.UserID,
+	_ int64,
+) ([]*entities.UserStreamEvent, error) {
+	return nil, r.NotImplemented("Load")
+}
+
+// SaveSnapshot is a stub implementation.
+func (r *NotImplementedUserEventStore) SaveSnapshot(_ context.Context, _ *entities.UserStreamSnapshot) error {
+	return r.NotImplemented("SaveSnapshot")
+}
+
+// LatestSnapshot is a stub implementation.
+func (r *NotImplementedUserEventStore) LatestSnapshot(
+	_ context.Context,
+	_ entities.UserID,
+) (*entities.UserStreamSnapshot, error) {
+	return nil, r.NotImplemented("LatestSnapshot")
+}
+
+// DeleteStream is a stub implementation.
+func (r *NotImplementedUserEventStore) DeleteStream(_ context.Context, _ entities.UserID) error {
+	return r.NotImplemented("DeleteStream")
+}
+
+// Ensure NotImplementedUserEventStore implements UserEventStore.
+var _ repositories.UserEventStore = (*NotImplementedUserEventStore)(nil)
+
+// NotImplementedUserReadModelRepository provides stub implementations for
+// UserReadModelRepository methods.
+type NotImplementedUserReadModelRepository struct {
+	NotImplementedRepository
+
+	dbName string
+}
+
+// NewNotImplementedUserReadModelRepository creates a new NotImplementedUserReadModelRepository.
+func NewNotImplementedUserReadModelRepository(dbName string) *NotImplementedUserReadModelRepository {
+	return &NotImplementedUserReadModelRepository{dbName: dbName}
+}
+
+// NotImplemented returns an error indicating the method is not implemented.
+func (r *NotImplementedUserReadModelRepository) NotImplemented(method string) error {
+	return entities.StubNotImplemented(method, r.dbName)
+}
+
+// Upsert is a stub implementation.
+func (r *NotImplementedUserReadModelRepository) Upsert(_ context.Context, _ ...*entities.UserView) error {
+	return r.NotImplemented("Upsert")
+}
+
+// Remove is a stub implementation.
+func (r *NotImplementedUserReadModelRepository) Remove(_ context.Context, _ ...entities.UserID) error {
+	return r.NotImplemented("Remove")
+}
+
+// List is a stub implementation.
+func (r *NotImplementedUserReadModelRepository) List(
+	_ context.Context,
+	_ entities.UserFilter,
+	_, _ int,
+) ([]*entities.UserView, error) {
+	return nil, r.NotImplemented("List")
+}
+
+// Lag is a stub implementation.
+func (r *NotImplementedUserReadModelRepository) Lag(_ context.Context) (time.Duration, error) {
+	return 0, r.NotImplemented("Lag")
+}
+
+// Ensure NotImplementedUserReadModelRepository implements UserReadModelRepository.
+var _ repositories.UserReadModelRepository = (*NotImplementedUserReadModelRepository)(nil)
+
+// NotImplementedFeatureFlagRepository provides stub implementations for all
+// FeatureFlagRepository methods.
+type NotImplementedFeatureFlagRepository struct {
+	dbName string
+}
+
+// NewNotImplementedFeatureFlagRepository creates a new NotImplementedFeatureFlagRepository.
+func NewNotImplementedFeatureFlagRepository(dbName string) *NotImplementedFeatureFlagRepository {
+	return &NotImplementedFeatureFlagRepository{dbName: dbName}
+}
+
+// NotImplemented returns a standard not-implemented error.
+func (r *NotImplementedFeatureFlagRepository) NotImplemented(method string) error {
+	return entities.StubNotImplemented(method, r.dbName)
+}
+
+// List is a stub implementation.
+func (r *NotImplementedFeatureFlagRepository) List(_ context.Context) ([]*entities.FeatureFlag, error) {
+	return nil, r.NotImplemented("List")
+}
+
+// Set is a stub implementation.
+func (r *NotImplementedFeatureFlagRepository) Set(_ context.Context, _ *entities.FeatureFlag) error {
+	return r.NotImplemented("Set")
+}
+
+// Ensure NotImplementedFeatureFlagRepository implements FeatureFlagRepository.
+var _ repositories.FeatureFlagRepository = (*NotImplementedFeatureFlagRepository)(nil)
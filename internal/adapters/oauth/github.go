@@ -0,0 +1,158 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/services"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/github"
+)
+
+const (
+	gitHubProviderName = entities.IdentityProvider("github")
+	gitHubAPIURL       = "https://api.github.com"
+)
+
+// gitHubScopes are requested when Config.Scopes is empty.
+var gitHubScopes = []string{"read:user", "user:email"}
+
+// gitHubUser is the subset of GET /user mapped onto a federated identity.
+type gitHubUser struct {
+	ID    int64  `json:"id"`
+	Login string `json:"login"`
+	Name  string `json:"name"`
+}
+
+// gitHubEmail is one entry of GET /user/emails.
+type gitHubEmail struct {
+	Email    string `json:"email"`
+	Primary  bool   `json:"primary"`
+	Verified bool   `json:"verified"`
+}
+
+// GitHubProvider signs users in with GitHub, which speaks OAuth2 but not OIDC.
+// The identity is read from the REST API with the issued access token.
+type GitHubProvider struct {
+	oauth  *oauth2.Config
+	apiURL string
+}
+
+// GitHubOption configures a GitHubProvider.
+type GitHubOption func(*GitHubProvider)
+
+// WithGitHubEnterprise points the provider at a GitHub Enterprise Server
+// instance, e.g. "https://github.example.com".
+func WithGitHubEnterprise(baseURL string) GitHubOption {
+	return func(p *GitHubProvider) {
+		baseURL = strings.TrimSuffix(baseURL, "/")
+		p.oauth.Endpoint = oauth2.Endpoint{
+			AuthURL:  baseURL + "/login/oauth/authorize",
+			TokenURL: baseURL + "/login/oauth/access_token",
+		}
+		p.apiURL = baseURL + "/api/v3"
+	}
+}
+
+// NewGitHubProvider creates the "github" provider.
+func NewGitHubProvider(cfg Config, opts ...GitHubOption) *GitHubProvider {
+	provider := &GitHubProvider{
+		oauth:  cfg.oauth2Config(github.Endpoint, gitHubScopes),
+		apiURL: gitHubAPIURL,
+	}
+
+	for _, opt := range opts {
+		opt(provider)
+	}
+
+	return provider
+}
+
+// Name returns the provider name identities are linked under.
+func (p *GitHubProvider) Name() entities.IdentityProvider { return gitHubProviderName }
+
+// AuthCodeURL returns the authorization URL with PKCE.
+func (p *GitHubProvider) AuthCodeURL(req AuthRequest) string {
+	return p.oauth.AuthCodeURL(req.State, oauth2.S256ChallengeOption(req.Verifier))
+}
+
+// Exchange redeems code and reads the account and its primary email from the API.
+// The numeric account ID is the subject since logins can be renamed.
+func (p *GitHubProvider) Exchange(
+	ctx context.Context,
+	code string,
+	req AuthRequest,
+) (*services.FederatedIdentity, error) {
+	token, err := p.oauth.Exchange(ctx, code, oauth2.VerifierOption(req.Verifier))
+	if err != nil {
+		return nil, fmt.Errorf("provider=%v code exchange: %w", gitHubProviderName, err)
+	}
+
+	client := p.oauth.Client(ctx, token)
+
+	var user gitHubUser
+
+	err = p.get(ctx, client, "/user", &user)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.ID == 0 {
+		return nil, fmt.Errorf("provider=%v missing account id: %w", gitHubProviderName, ErrInvalidIdentity)
+	}
+
+	var emails []gitHubEmail
+
+	err = p.get(ctx, client, "/user/emails", &emails)
+	if err != nil {
+		return nil, err
+	}
+
+	identity := &services.FederatedIdentity{
+		Provider:          gitHubProviderName,
+		Subject:           strconv.FormatInt(user.ID, 10),
+		PreferredUsername: user.Login,
+	}
+	identity.FirstName, identity.LastName, _ = strings.Cut(strings.TrimSpace(user.Name), " ")
+
+	for _, email := range emails {
+		if email.Primary {
+			identity.Email = email.Email
+			identity.EmailVerified = email.Verified
+		}
+	}
+
+	return identity, nil
+}
+
+// get decodes the JSON response of an authenticated API request.
+func (p *GitHubProvider) get(ctx context.Context, client *http.Client, path string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.apiURL+path, nil)
+	if err != nil {
+		return fmt.Errorf("provider=%v %s: %w", gitHubProviderName, path, err)
+	}
+
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("provider=%v %s: %w", gitHubProviderName, path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("provider=%v %s status=%d: %w", gitHubProviderName, path, resp.StatusCode, ErrInvalidIdentity)
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(out)
+	if err != nil {
+		return fmt.Errorf("provider=%v %s: %w", gitHubProviderName, path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,123 @@
+package oauth
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/services"
+)
+
+// Authenticator runs the authorization code flow against registered providers
+// and hands the resulting identities to the identity service.
+type Authenticator struct {
+	identities *services.IdentityService
+	providers  map[entities.IdentityProvider]Provider
+}
+
+// NewAuthenticator creates an authenticator for providers.
+func NewAuthenticator(identities *services.IdentityService, providers ...Provider) *Authenticator {
+	registered := make(map[entities.IdentityProvider]Provider, len(providers))
+	for _, provider := range providers {
+		registered[provider.Name()] = provider
+	}
+
+	return &Authenticator{
+		identities: identities,
+		providers:  registered,
+	}
+}
+
+// Begin starts a login at provider. The caller redirects to the returned URL
+// and keeps the auth request until the callback.
+func (a *Authenticator) Begin(provider entities.IdentityProvider) (string, AuthRequest, error) {
+	p, err := a.provider(provider)
+	if err != nil {
+		return "", AuthRequest{}, err
+	}
+
+	req := NewAuthRequest()
+
+	return p.AuthCodeURL(req), req, nil
+}
+
+// Complete finishes a login from the provider callback and starts a session.
+func (a *Authenticator) Complete(
+	ctx context.Context,
+	provider entities.IdentityProvider,
+	code, state string,
+	req AuthRequest,
+	ipAddress, userAgent string,
+) (*services.FederatedLogin, error) {
+	identity, err := a.exchange(ctx, provider, code, state, req)
+	if err != nil {
+		return nil, err
+	}
+
+	login, err := a.identities.Login(ctx, *identity, ipAddress, userAgent)
+	if err != nil {
+		return nil, fmt.Errorf("login provider=%v: %w", provider, err)
+	}
+
+	return login, nil
+}
+
+// Link finishes a flow started by a signed-in user and links the identity to them.
+func (a *Authenticator) Link(
+	ctx context.Context,
+	userID entities.UserID,
+	provider entities.IdentityProvider,
+	code, state string,
+	req AuthRequest,
+) (*entities.UserIdentity, error) {
+	identity, err := a.exchange(ctx, provider, code, state, req)
+	if err != nil {
+		return nil, err
+	}
+
+	linked, err := a.identities.Link(ctx, userID, *identity)
+	if err != nil {
+		return nil, fmt.Errorf("link provider=%v: %w", provider, err)
+	}
+
+	return linked, nil
+}
+
+// exchange checks the callback state and redeems code at provider.
+func (a *Authenticator) exchange(
+	ctx context.Context,
+	provider entities.IdentityProvider,
+	code, state string,
+	req AuthRequest,
+) (*services.FederatedIdentity, error) {
+	p, err := a.provider(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.State == "" || subtle.ConstantTimeCompare([]byte(state), []byte(req.State)) != 1 {
+		return nil, fmt.Errorf("provider=%v: %w", provider, ErrStateMismatch)
+	}
+
+	identity, err := p.Exchange(ctx, code, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if identity.Subject == "" {
+		return nil, fmt.Errorf("provider=%v missing subject: %w", provider, ErrInvalidIdentity)
+	}
+
+	return identity, nil
+}
+
+// provider returns the registered provider named name.
+func (a *Authenticator) provider(name entities.IdentityProvider) (Provider, error) {
+	p, ok := a.providers[name]
+	if !ok {
+		return nil, fmt.Errorf("provider=%v: %w", name, ErrUnknownProvider)
+	}
+
+	return p, nil
+}
@@ -0,0 +1,110 @@
+package oauth
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/services"
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+)
+
+// googleIssuer is the OpenID Connect issuer of Google accounts.
+const googleIssuer = "https://accounts.google.com"
+
+// oidcScopes are requested when Config.Scopes is empty.
+var oidcScopes = []string{oidc.ScopeOpenID, "email", "profile"}
+
+// oidcClaims are the ID token claims mapped onto a federated identity.
+type oidcClaims struct {
+	Subject           string `json:"sub"`
+	Email             string `json:"email"`
+	EmailVerified     bool   `json:"email_verified"`
+	PreferredUsername string `json:"preferred_username"`
+	GivenName         string `json:"given_name"`
+	FamilyName        string `json:"family_name"`
+}
+
+// OIDCProvider signs users in through any OpenID Connect provider.
+// The identity is taken from the verified ID token.
+type OIDCProvider struct {
+	name     entities.IdentityProvider
+	oauth    *oauth2.Config
+	verifier *oidc.IDTokenVerifier
+}
+
+// NewOIDCProvider discovers the provider at issuer and registers it under name.
+func NewOIDCProvider(ctx context.Context, name, issuer string, cfg Config) (*OIDCProvider, error) {
+	providerName, err := entities.NewIdentityProvider(name)
+	if err != nil {
+		return nil, fmt.Errorf("provider name=%v: %w", name, err)
+	}
+
+	discovered, err := oidc.NewProvider(ctx, issuer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover issuer=%v: %w", issuer, err)
+	}
+
+	return &OIDCProvider{
+		name:     providerName,
+		oauth:    cfg.oauth2Config(discovered.Endpoint(), oidcScopes),
+		verifier: discovered.Verifier(&oidc.Config{ClientID: cfg.ClientID}),
+	}, nil
+}
+
+// NewGoogleProvider creates the "google" provider.
+func NewGoogleProvider(ctx context.Context, cfg Config) (*OIDCProvider, error) {
+	return NewOIDCProvider(ctx, "google", googleIssuer, cfg)
+}
+
+// Name returns the provider name identities are linked under.
+func (p *OIDCProvider) Name() entities.IdentityProvider { return p.name }
+
+// AuthCodeURL returns the authorization URL with PKCE and nonce.
+func (p *OIDCProvider) AuthCodeURL(req AuthRequest) string {
+	return p.oauth.AuthCodeURL(req.State, oauth2.S256ChallengeOption(req.Verifier), oidc.Nonce(req.Nonce))
+}
+
+// Exchange redeems code and verifies the returned ID token, including its nonce.
+func (p *OIDCProvider) Exchange(
+	ctx context.Context,
+	code string,
+	req AuthRequest,
+) (*services.FederatedIdentity, error) {
+	token, err := p.oauth.Exchange(ctx, code, oauth2.VerifierOption(req.Verifier))
+	if err != nil {
+		return nil, fmt.Errorf("provider=%v code exchange: %w", p.name, err)
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok || rawIDToken == "" {
+		return nil, fmt.Errorf("provider=%v missing id_token: %w", p.name, ErrInvalidIdentity)
+	}
+
+	idToken, err := p.verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, fmt.Errorf("provider=%v id_token: %w", p.name, err)
+	}
+
+	if idToken.Nonce != req.Nonce {
+		return nil, fmt.Errorf("provider=%v nonce mismatch: %w", p.name, ErrInvalidIdentity)
+	}
+
+	var claims oidcClaims
+
+	err = idToken.Claims(&claims)
+	if err != nil {
+		return nil, fmt.Errorf("provider=%v claims: %w", p.name, err)
+	}
+
+	return &services.FederatedIdentity{
+		Provider:          p.name,
+		Subject:           claims.Subject,
+		Email:             claims.Email,
+		EmailVerified:     claims.EmailVerified,
+		PreferredUsername: claims.PreferredUsername,
+		FirstName:         claims.GivenName,
+		LastName:          claims.FamilyName,
+	}, nil
+}
@@ -0,0 +1,76 @@
+// Package oauth provides OAuth2 and OpenID Connect identity providers for
+// federated login through the identity service.
+package oauth
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/services"
+	"golang.org/x/oauth2"
+)
+
+var (
+	// ErrUnknownProvider is returned for a provider that was not registered.
+	ErrUnknownProvider = errors.New("unknown identity provider")
+
+	// ErrStateMismatch is returned when the callback state does not match the auth request.
+	ErrStateMismatch = errors.New("oauth state mismatch")
+
+	// ErrInvalidIdentity is returned when a provider response lacks a usable identity.
+	ErrInvalidIdentity = errors.New("invalid identity from provider")
+)
+
+// Config holds the client registration at an identity provider.
+// Scopes replace the provider's defaults when set.
+type Config struct {
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+}
+
+// oauth2Config builds the oauth2 client configuration for endpoint.
+func (c Config) oauth2Config(endpoint oauth2.Endpoint, defaultScopes []string) *oauth2.Config {
+	scopes := c.Scopes
+	if len(scopes) == 0 {
+		scopes = defaultScopes
+	}
+
+	return &oauth2.Config{
+		ClientID:     c.ClientID,
+		ClientSecret: c.ClientSecret,
+		RedirectURL:  c.RedirectURL,
+		Endpoint:     endpoint,
+		Scopes:       scopes,
+	}
+}
+
+// AuthRequest holds the per-login secrets that must survive the redirect to the
+// provider, typically in a short-lived cookie. State protects against CSRF, the
+// PKCE verifier binds the code to this client, and the nonce binds OIDC ID tokens.
+type AuthRequest struct {
+	State    string
+	Verifier string
+	Nonce    string
+}
+
+// NewAuthRequest generates fresh secrets for one login attempt.
+func NewAuthRequest() AuthRequest {
+	return AuthRequest{
+		State:    rand.Text(),
+		Verifier: oauth2.GenerateVerifier(),
+		Nonce:    rand.Text(),
+	}
+}
+
+// Provider exchanges authorization codes for the identity of the signed-in account.
+type Provider interface {
+	Name() entities.IdentityProvider
+	// AuthCodeURL returns the provider URL to send the user to.
+	AuthCodeURL(req AuthRequest) string
+	// Exchange redeems code and returns the verified identity.
+	Exchange(ctx context.Context, code string, req AuthRequest) (*services.FederatedIdentity, error)
+}
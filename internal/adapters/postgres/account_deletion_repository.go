@@ -0,0 +1,21 @@
+package postgres
+
+import (
+	"github.com/LarsArtmann/template-sqlc/internal/adapters"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// AccountDeletionRepository implements AccountDeletionRepository for PostgreSQL.
+type AccountDeletionRepository struct {
+	*adapters.NotImplementedAccountDeletionRepository
+
+	db DBTX
+}
+
+// NewAccountDeletionRepository creates a new PostgreSQL account deletion repository.
+func NewAccountDeletionRepository(db DBTX) repositories.AccountDeletionRepository {
+	return &AccountDeletionRepository{
+		NotImplementedAccountDeletionRepository: adapters.NewNotImplementedAccountDeletionRepository("PostgreSQL"),
+		db:                                      db,
+	}
+}
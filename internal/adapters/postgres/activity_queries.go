@@ -0,0 +1,114 @@
+//go:build postgres
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters"
+	postgresdb "github.com/LarsArtmann/template-sqlc/internal/db/postgres"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	apperrors "github.com/LarsArtmann/template-sqlc/pkg/errors"
+)
+
+// queries returns the sqlc queries bound to the repository's database handle.
+func (r *ActivityRepository) queries() *postgresdb.Queries {
+	return postgresdb.New(r.db)
+}
+
+// Record stores entry unless the event of entry is already recorded for the
+// user. The ID of entry is not set; entries are read back with ListPage.
+func (r *ActivityRepository) Record(ctx context.Context, entry *entities.ActivityEntry) error {
+	err := r.queries().RecordUserActivity(ctx, &postgresdb.RecordUserActivityParams{
+		UserID:     int64(entry.UserID),
+		EventID:    int64(entry.EventID),
+		EventType:  entry.EventType,
+		Kind:       entry.Kind.String(),
+		Summary:    entry.Summary,
+		ActorID:    int64(entry.ActorID),
+		OccurredAt: entry.OccurredAt,
+	})
+	if err != nil {
+		return fmt.Errorf(
+			"event=%v user=%v: %w",
+			entry.EventID,
+			entry.UserID,
+			handleActivityError(err, "record user activity"),
+		)
+	}
+
+	return nil
+}
+
+// ListPage returns up to limit entries of a user after cursor, newest first.
+func (r *ActivityRepository) ListPage(
+	ctx context.Context,
+	userID entities.UserID,
+	cursor string,
+	limit int,
+) (*entities.ActivityPage, error) {
+	after, err := adapters.ParseActivityPageRequest(cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	bound := after.Bound()
+
+	rows, err := r.queries().ListUserActivityPage(ctx, &postgresdb.ListUserActivityPageParams{
+		UserID:           int64(userID),
+		CursorOccurredAt: bound.OccurredAt,
+		CursorID:         int64(bound.ID),
+		RowLimit:         int32(limit + 1),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("user=%v: %w", userID, handleActivityError(err, "list user activity"))
+	}
+
+	entries := make([]*entities.ActivityEntry, 0, len(rows))
+	for _, row := range rows {
+		entries = append(entries, domainActivityEntry(row))
+	}
+
+	return entities.NewActivityPage(entries, limit), nil
+}
+
+// DeleteOlderThan removes the entries that occurred before cutoff.
+func (r *ActivityRepository) DeleteOlderThan(ctx context.Context, cutoff time.Time) (int64, error) {
+	deleted, err := r.queries().DeleteUserActivityBefore(ctx, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("cutoff=%v: %w", cutoff, handleActivityError(err, "delete user activity"))
+	}
+
+	return deleted, nil
+}
+
+// DeleteByUser removes the entries of a user.
+func (r *ActivityRepository) DeleteByUser(ctx context.Context, userID entities.UserID) (int64, error) {
+	deleted, err := r.queries().DeleteUserActivityByUser(ctx, int64(userID))
+	if err != nil {
+		return 0, fmt.Errorf("user=%v: %w", userID, handleActivityError(err, "delete user activity"))
+	}
+
+	return deleted, nil
+}
+
+// domainActivityEntry converts a generated user_activity row into a domain entity.
+func domainActivityEntry(row *postgresdb.UserActivity) *entities.ActivityEntry {
+	return &entities.ActivityEntry{
+		ID:         entities.ActivityID(row.ID),
+		UserID:     entities.UserID(row.UserID),
+		EventID:    entities.IDID(row.EventID),
+		EventType:  row.EventType,
+		Kind:       entities.ActivityKind(row.Kind),
+		Summary:    row.Summary,
+		ActorID:    entities.UserID(row.ActorID),
+		OccurredAt: row.OccurredAt,
+	}
+}
+
+// handleActivityError maps database errors for activity queries to domain errors.
+func handleActivityError(err error, operation string) error {
+	return apperrors.NewDatabaseError(operation+" failed", err)
+}
@@ -0,0 +1,21 @@
+package postgres
+
+import (
+	"github.com/LarsArtmann/template-sqlc/internal/adapters"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// ActivityRepository implements ActivityRepository for PostgreSQL.
+type ActivityRepository struct {
+	*adapters.NotImplementedActivityRepository
+
+	db DBTX
+}
+
+// NewActivityRepository creates a new PostgreSQL activity repository.
+func NewActivityRepository(db DBTX) repositories.ActivityRepository {
+	return &ActivityRepository{
+		NotImplementedActivityRepository: adapters.NewNotImplementedActivityRepository("PostgreSQL"),
+		db:                               db,
+	}
+}
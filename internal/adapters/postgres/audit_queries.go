@@ -0,0 +1,112 @@
+//go:build postgres
+
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/mappers"
+	postgresdb "github.com/LarsArtmann/template-sqlc/internal/db/postgres"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+)
+
+// queries returns the sqlc queries bound to the repository's database handle.
+func (r *AuditRepository) queries() *postgresdb.Queries {
+	return postgresdb.New(r.db)
+}
+
+// Record stores an audit log entry and assigns the generated ID.
+func (r *AuditRepository) Record(ctx context.Context, entry *entities.AuditLog) error {
+	changes, err := mappers.EncodeAuditChanges(entry.Changes)
+	if err != nil {
+		return fmt.Errorf("record audit action=%v user=%v: %w", entry.Action, entry.UserID, err)
+	}
+
+	var actorID *int64
+
+	if entry.ActorID != 0 {
+		id := int64(entry.ActorID)
+		actorID = &id
+	}
+
+	id, err := r.queries().CreateAuditLog(ctx, &postgresdb.CreateAuditLogParams{
+		ActorID:   actorID,
+		UserID:    int64(entry.UserID),
+		Action:    entry.Action.String(),
+		Changes:   json.RawMessage(changes),
+		IpAddress: entry.IPAddress,
+		CreatedAt: entry.CreatedAt,
+	})
+	if err != nil {
+		return fmt.Errorf(
+			"record audit action=%v user=%v: %w",
+			entry.Action,
+			entry.UserID,
+			handleError(err, "record audit log"),
+		)
+	}
+
+	entry.ID = entities.AuditLogID(id)
+
+	return nil
+}
+
+// List returns audit log entries matching filter, newest first.
+func (r *AuditRepository) List(
+	ctx context.Context,
+	filter entities.AuditFilter,
+) ([]*entities.AuditLog, error) {
+	from, to := filter.TimeRange()
+
+	rows, err := r.queries().ListAuditLogs(ctx, &postgresdb.ListAuditLogsParams{
+		UserID:      int64(filter.UserID),
+		ActorID:     int64(filter.ActorID),
+		Action:      filter.Action.String(),
+		CreatedFrom: from,
+		CreatedTo:   to,
+		RowLimit:    int32(filter.Limit),
+		RowOffset:   int32(filter.Offset),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list audit user=%v: %w", filter.UserID, handleError(err, "list audit log"))
+	}
+
+	entries := make([]*entities.AuditLog, 0, len(rows))
+
+	for _, row := range rows {
+		changes, err := mappers.DecodeAuditChanges(row.Changes)
+		if err != nil {
+			return nil, fmt.Errorf("audit id=%v: %w", row.ID, err)
+		}
+
+		var actorID entities.UserID
+		if row.ActorID != nil {
+			actorID = entities.UserID(*row.ActorID)
+		}
+
+		entries = append(entries, &entities.AuditLog{
+			ID:        entities.AuditLogID(row.ID),
+			ActorID:   actorID,
+			UserID:    entities.UserID(row.UserID),
+			Action:    entities.AuditAction(row.Action),
+			Changes:   changes,
+			IPAddress: row.IpAddress,
+			CreatedAt: row.CreatedAt,
+		})
+	}
+
+	return entries, nil
+}
+
+// AnonymizeUser clears the changes and IP addresses of the entries about or
+// performed by a user.
+func (r *AuditRepository) AnonymizeUser(ctx context.Context, userID entities.UserID) (int64, error) {
+	anonymized, err := r.queries().AnonymizeAuditLogs(ctx, int64(userID))
+	if err != nil {
+		return 0, fmt.Errorf("anonymize audit user=%v: %w", userID, handleError(err, "anonymize audit log"))
+	}
+
+	return anonymized, nil
+}
@@ -0,0 +1,21 @@
+package postgres
+
+import (
+	"github.com/LarsArtmann/template-sqlc/internal/adapters"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// AuditRepository implements AuditRepository for PostgreSQL.
+type AuditRepository struct {
+	*adapters.NotImplementedAuditRepository
+
+	db DBTX
+}
+
+// NewAuditRepository creates a new PostgreSQL audit repository.
+func NewAuditRepository(db DBTX) repositories.AuditRepository {
+	return &AuditRepository{
+		NotImplementedAuditRepository: adapters.NewNotImplementedAuditRepository("PostgreSQL"),
+		db:                            db,
+	}
+}
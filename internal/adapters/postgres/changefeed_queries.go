@@ -0,0 +1,113 @@
+//go:build postgres
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/changefeed"
+	postgresdb "github.com/LarsArtmann/template-sqlc/internal/db/postgres"
+	"github.com/LarsArtmann/template-sqlc/internal/replay"
+	apperrors "github.com/LarsArtmann/template-sqlc/pkg/errors"
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	_ changefeed.Source      = (*ChangefeedSource)(nil)
+	_ replay.CheckpointStore = (*ChangefeedCheckpointStore)(nil)
+)
+
+// queries returns the sqlc queries bound to the source's database handle.
+func (s *ChangefeedSource) queries() *postgresdb.Queries {
+	return postgresdb.New(s.db)
+}
+
+// CreateSlot creates slot with the wal2json output plugin unless it exists.
+func (s *ChangefeedSource) CreateSlot(ctx context.Context, slot string) error {
+	err := s.queries().CreateChangefeedSlot(ctx, slot)
+	if err != nil {
+		return fmt.Errorf("slot=%s: %w", slot, apperrors.NewDatabaseError("create replication slot failed", err))
+	}
+
+	return nil
+}
+
+// Peek returns up to limit changes of slot for tables without consuming them.
+func (s *ChangefeedSource) Peek(
+	ctx context.Context,
+	slot string,
+	tables []string,
+	limit int,
+) ([]changefeed.Message, error) {
+	rows, err := s.queries().PeekChangefeedChanges(ctx, &postgresdb.PeekChangefeedChangesParams{
+		SlotName: slot,
+		RowLimit: int32(limit),
+		Tables:   strings.Join(tables, ","),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("slot=%s: %w", slot, apperrors.NewDatabaseError("peek replication slot failed", err))
+	}
+
+	messages := make([]changefeed.Message, 0, len(rows))
+
+	for _, row := range rows {
+		lsn, err := changefeed.ParseLSN(row.Lsn)
+		if err != nil {
+			return nil, fmt.Errorf("slot=%s: %w", slot, err)
+		}
+
+		messages = append(messages, changefeed.Message{LSN: lsn, Data: []byte(row.Data)})
+	}
+
+	return messages, nil
+}
+
+// Advance consumes the changes of slot up to lsn.
+func (s *ChangefeedSource) Advance(ctx context.Context, slot string, lsn changefeed.LSN) error {
+	err := s.queries().AdvanceChangefeedSlot(ctx, &postgresdb.AdvanceChangefeedSlotParams{
+		SlotName: slot,
+		UptoLsn:  lsn.String(),
+	})
+	if err != nil {
+		return fmt.Errorf("slot=%s: %w", slot, apperrors.NewDatabaseError("advance replication slot failed", err))
+	}
+
+	return nil
+}
+
+// queries returns the sqlc queries bound to the store's database handle.
+func (s *ChangefeedCheckpointStore) queries() *postgresdb.Queries {
+	return postgresdb.New(s.db)
+}
+
+// Load returns the saved position of slot, or zero if it has none.
+func (s *ChangefeedCheckpointStore) Load(ctx context.Context, slot string) (int64, error) {
+	lsn, err := s.queries().GetChangefeedCheckpoint(ctx, slot)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, nil
+	}
+
+	if err != nil {
+		return 0, fmt.Errorf("slot=%s: %w", slot, apperrors.NewDatabaseError("get changefeed checkpoint failed", err))
+	}
+
+	return lsn, nil
+}
+
+// Save stores the position of slot.
+func (s *ChangefeedCheckpointStore) Save(ctx context.Context, slot string, position int64) error {
+	err := s.queries().SaveChangefeedCheckpoint(ctx, &postgresdb.SaveChangefeedCheckpointParams{
+		SlotName:  slot,
+		Lsn:       position,
+		UpdatedAt: time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("slot=%s: %w", slot, apperrors.NewDatabaseError("save changefeed checkpoint failed", err))
+	}
+
+	return nil
+}
@@ -0,0 +1,24 @@
+package postgres
+
+// ChangefeedSource reads the changes of logical replication slots decoded by
+// wal2json, implementing changefeed.Source for PostgreSQL.
+type ChangefeedSource struct {
+	db DBTX
+}
+
+// NewChangefeedSource creates a new PostgreSQL changefeed source.
+func NewChangefeedSource(db DBTX) *ChangefeedSource {
+	return &ChangefeedSource{db: db}
+}
+
+// ChangefeedCheckpointStore keeps the published position of each
+// replication slot in the changefeed_checkpoints table, implementing
+// replay.CheckpointStore keyed by slot name.
+type ChangefeedCheckpointStore struct {
+	db DBTX
+}
+
+// NewChangefeedCheckpointStore creates a new PostgreSQL changefeed checkpoint store.
+func NewChangefeedCheckpointStore(db DBTX) *ChangefeedCheckpointStore {
+	return &ChangefeedCheckpointStore{db: db}
+}
@@ -0,0 +1,21 @@
+package postgres
+
+import (
+	"github.com/LarsArtmann/template-sqlc/internal/adapters"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// FeatureFlagRepository implements FeatureFlagRepository for PostgreSQL.
+type FeatureFlagRepository struct {
+	*adapters.NotImplementedFeatureFlagRepository
+
+	db DBTX
+}
+
+// NewFeatureFlagRepository creates a new PostgreSQL feature flag repository.
+func NewFeatureFlagRepository(db DBTX) repositories.FeatureFlagRepository {
+	return &FeatureFlagRepository{
+		NotImplementedFeatureFlagRepository: adapters.NewNotImplementedFeatureFlagRepository("PostgreSQL"),
+		db:                                  db,
+	}
+}
@@ -0,0 +1,21 @@
+package postgres
+
+import (
+	"github.com/LarsArtmann/template-sqlc/internal/adapters"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// IdempotencyRepository implements IdempotencyRepository for PostgreSQL.
+type IdempotencyRepository struct {
+	*adapters.NotImplementedIdempotencyRepository
+
+	db DBTX
+}
+
+// NewIdempotencyRepository creates a new PostgreSQL idempotency repository.
+func NewIdempotencyRepository(db DBTX) repositories.IdempotencyRepository {
+	return &IdempotencyRepository{
+		NotImplementedIdempotencyRepository: adapters.NewNotImplementedIdempotencyRepository("PostgreSQL"),
+		db:                                  db,
+	}
+}
@@ -0,0 +1,134 @@
+//go:build postgres
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	postgresdb "github.com/LarsArtmann/template-sqlc/internal/db/postgres"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	apperrors "github.com/LarsArtmann/template-sqlc/pkg/errors"
+	"github.com/jackc/pgx/v5"
+)
+
+// queries returns the sqlc queries bound to the repository's database handle.
+func (r *IdentityChangeRepository) queries() *postgresdb.Queries {
+	return postgresdb.New(r.db)
+}
+
+// Create records a change and assigns the generated ID.
+func (r *IdentityChangeRepository) Create(ctx context.Context, change *entities.IdentityChange) error {
+	id, err := r.queries().CreateIdentityChange(ctx, &postgresdb.CreateIdentityChangeParams{
+		UserID:    int64(change.UserID),
+		Field:     change.Field.String(),
+		OldValue:  change.OldValue,
+		NewValue:  change.NewValue,
+		Status:    change.Status.String(),
+		Token:     change.Token.UUID(),
+		ExpiresAt: change.ExpiresAt,
+		CreatedAt: change.CreatedAt,
+		UpdatedAt: change.UpdatedAt,
+	})
+	if err != nil {
+		return fmt.Errorf(
+			"record identity change field=%v user=%v: %w",
+			change.Field,
+			change.UserID,
+			handleIdentityChangeError(err, "record identity change"),
+		)
+	}
+
+	change.ID = entities.IdentityChangeID(id)
+
+	return nil
+}
+
+// GetByToken retrieves the change with token.
+func (r *IdentityChangeRepository) GetByToken(
+	ctx context.Context,
+	token entities.IdentityChangeToken,
+) (*entities.IdentityChange, error) {
+	row, err := r.queries().GetIdentityChangeByToken(ctx, token.UUID())
+	if err != nil {
+		return nil, handleIdentityChangeError(err, "get identity change")
+	}
+
+	return domainIdentityChange(row), nil
+}
+
+// Update persists the status, token and expiry of a change.
+func (r *IdentityChangeRepository) Update(ctx context.Context, change *entities.IdentityChange) error {
+	affected, err := r.queries().UpdateIdentityChange(ctx, &postgresdb.UpdateIdentityChangeParams{
+		Status:    change.Status.String(),
+		Token:     change.Token.UUID(),
+		ExpiresAt: change.ExpiresAt,
+		UpdatedAt: change.UpdatedAt,
+		ID:        change.ID.Int64(),
+	})
+	if err != nil {
+		return fmt.Errorf("identity change id=%d: %w", change.ID, handleIdentityChangeError(err, "update identity change"))
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("identity change id=%d: %w", change.ID, entities.ErrIdentityChangeNotFound)
+	}
+
+	return nil
+}
+
+// ListByUser returns the changes of a user, newest first.
+func (r *IdentityChangeRepository) ListByUser(
+	ctx context.Context,
+	userID entities.UserID,
+) ([]*entities.IdentityChange, error) {
+	rows, err := r.queries().ListIdentityChanges(ctx, int64(userID))
+	if err != nil {
+		return nil, fmt.Errorf("user=%v: %w", userID, handleIdentityChangeError(err, "list identity changes"))
+	}
+
+	changes := make([]*entities.IdentityChange, 0, len(rows))
+
+	for _, row := range rows {
+		changes = append(changes, domainIdentityChange(row))
+	}
+
+	return changes, nil
+}
+
+// DeleteByUser removes the changes of a user.
+func (r *IdentityChangeRepository) DeleteByUser(ctx context.Context, userID entities.UserID) (int64, error) {
+	deleted, err := r.queries().DeleteIdentityChangesByUser(ctx, int64(userID))
+	if err != nil {
+		return 0, fmt.Errorf("user=%v: %w", userID, handleIdentityChangeError(err, "delete identity changes"))
+	}
+
+	return deleted, nil
+}
+
+// domainIdentityChange converts a generated identity_changes row into a domain entity.
+func domainIdentityChange(row *postgresdb.IdentityChanges) *entities.IdentityChange {
+	return &entities.IdentityChange{
+		ID:        entities.IdentityChangeID(row.ID),
+		UserID:    entities.UserID(row.UserID),
+		Field:     entities.IdentityField(row.Field),
+		OldValue:  row.OldValue,
+		NewValue:  row.NewValue,
+		Status:    entities.IdentityChangeStatus(row.Status),
+		Token:     entities.IdentityChangeToken(row.Token),
+		ExpiresAt: row.ExpiresAt,
+		CreatedAt: row.CreatedAt,
+		UpdatedAt: row.UpdatedAt,
+	}
+}
+
+// handleIdentityChangeError maps database errors for identity change queries
+// to domain errors.
+func handleIdentityChangeError(err error, operation string) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entities.ErrIdentityChangeNotFound
+	}
+
+	return apperrors.NewDatabaseError(operation+" failed", err)
+}
@@ -0,0 +1,21 @@
+package postgres
+
+import (
+	"github.com/LarsArtmann/template-sqlc/internal/adapters"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// IdentityChangeRepository implements IdentityChangeRepository for PostgreSQL.
+type IdentityChangeRepository struct {
+	*adapters.NotImplementedIdentityChangeRepository
+
+	db DBTX
+}
+
+// NewIdentityChangeRepository creates a new PostgreSQL identity change repository.
+func NewIdentityChangeRepository(db DBTX) repositories.IdentityChangeRepository {
+	return &IdentityChangeRepository{
+		NotImplementedIdentityChangeRepository: adapters.NewNotImplementedIdentityChangeRepository("PostgreSQL"),
+		db:                                     db,
+	}
+}
@@ -0,0 +1,21 @@
+package postgres
+
+import (
+	"github.com/LarsArtmann/template-sqlc/internal/adapters"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// IdentityRepository implements IdentityRepository for PostgreSQL.
+type IdentityRepository struct {
+	*adapters.NotImplementedIdentityRepository
+
+	db DBTX
+}
+
+// NewIdentityRepository creates a new PostgreSQL identity repository.
+func NewIdentityRepository(db DBTX) repositories.IdentityRepository {
+	return &IdentityRepository{
+		NotImplementedIdentityRepository: adapters.NewNotImplementedIdentityRepository("PostgreSQL"),
+		db:                               db,
+	}
+}
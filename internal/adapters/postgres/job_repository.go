@@ -0,0 +1,21 @@
+package postgres
+
+import (
+	"github.com/LarsArtmann/template-sqlc/internal/adapters"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// JobRepository implements JobRepository for PostgreSQL.
+type JobRepository struct {
+	*adapters.NotImplementedJobRepository
+
+	db DBTX
+}
+
+// NewJobRepository creates a new PostgreSQL job repository.
+func NewJobRepository(db DBTX) repositories.JobRepository {
+	return &JobRepository{
+		NotImplementedJobRepository: adapters.NewNotImplementedJobRepository("PostgreSQL"),
+		db:                          db,
+	}
+}
@@ -0,0 +1,21 @@
+package postgres
+
+import (
+	"github.com/LarsArtmann/template-sqlc/internal/adapters"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// LoginHistoryRepository implements LoginHistoryRepository for PostgreSQL.
+type LoginHistoryRepository struct {
+	*adapters.NotImplementedLoginHistoryRepository
+
+	db DBTX
+}
+
+// NewLoginHistoryRepository creates a new PostgreSQL login history repository.
+func NewLoginHistoryRepository(db DBTX) repositories.LoginHistoryRepository {
+	return &LoginHistoryRepository{
+		NotImplementedLoginHistoryRepository: adapters.NewNotImplementedLoginHistoryRepository("PostgreSQL"),
+		db:                                   db,
+	}
+}
@@ -0,0 +1,266 @@
+//go:build postgres
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	postgresdb "github.com/LarsArtmann/template-sqlc/internal/db/postgres"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+)
+
+// queries returns the sqlc queries bound to the repository's database handle.
+func (r *ModerationRepository) queries() *postgresdb.Queries {
+	return postgresdb.New(r.db)
+}
+
+// CreateAction records an action and assigns the generated ID.
+func (r *ModerationRepository) CreateAction(ctx context.Context, action *entities.ModerationAction) error {
+	id, err := r.queries().CreateModerationAction(ctx, &postgresdb.CreateModerationActionParams{
+		UserID:      int64(action.UserID),
+		Kind:        action.Kind.String(),
+		Reason:      action.Reason,
+		ModeratorID: int64(action.ModeratorID),
+		EndsAt:      nullTimestamptz.DomainToDB(action.EndsAt),
+		CreatedAt:   action.CreatedAt,
+	})
+	if err != nil {
+		return fmt.Errorf(
+			"record %v user=%v: %w",
+			action.Kind,
+			action.UserID,
+			handleModerationError(err, "record moderation action", entities.ErrModerationActionNotFound),
+		)
+	}
+
+	action.ID = entities.ModerationActionID(id)
+
+	return nil
+}
+
+// GetAction retrieves the action with id.
+func (r *ModerationRepository) GetAction(
+	ctx context.Context,
+	id entities.ModerationActionID,
+) (*entities.ModerationAction, error) {
+	row, err := r.queries().GetModerationAction(ctx, id.Int64())
+	if err != nil {
+		return nil, fmt.Errorf("moderation action id=%d: %w", id,
+			handleModerationError(err, "get moderation action", entities.ErrModerationActionNotFound))
+	}
+
+	return domainModerationAction(row)
+}
+
+// LiftAction stores when and by whom an action was lifted, unless it was
+// lifted before.
+func (r *ModerationRepository) LiftAction(ctx context.Context, action *entities.ModerationAction) error {
+	affected, err := r.queries().LiftModerationAction(ctx, &postgresdb.LiftModerationActionParams{
+		LiftedAt: nullTimestamptz.DomainToDB(action.LiftedAt),
+		LiftedBy: int64(action.LiftedBy),
+		ID:       action.ID.Int64(),
+	})
+	if err != nil {
+		return fmt.Errorf("moderation action id=%d: %w", action.ID,
+			handleModerationError(err, "lift moderation action", entities.ErrModerationActionNotFound))
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("moderation action id=%d: %w", action.ID, entities.ErrModerationActionNotFound)
+	}
+
+	return nil
+}
+
+// GetActiveSuspension retrieves the newest suspension of a user that was not lifted.
+func (r *ModerationRepository) GetActiveSuspension(
+	ctx context.Context,
+	userID entities.UserID,
+) (*entities.ModerationAction, error) {
+	row, err := r.queries().GetActiveSuspension(ctx, int64(userID))
+	if err != nil {
+		return nil, fmt.Errorf("user=%v: %w", userID,
+			handleModerationError(err, "get active suspension", entities.ErrNotSuspended))
+	}
+
+	return domainModerationAction(row)
+}
+
+// ListActionsByUser returns the actions against a user, newest first.
+func (r *ModerationRepository) ListActionsByUser(
+	ctx context.Context,
+	userID entities.UserID,
+) ([]*entities.ModerationAction, error) {
+	rows, err := r.queries().ListModerationActions(ctx, int64(userID))
+	if err != nil {
+		return nil, fmt.Errorf("user=%v: %w", userID,
+			handleModerationError(err, "list moderation actions", entities.ErrModerationActionNotFound))
+	}
+
+	return scanAll[*entities.ModerationAction](rows)
+}
+
+// ListLapsedSuspensions returns up to limit suspensions that ended by now
+// but were not lifted yet, the earliest ended first.
+func (r *ModerationRepository) ListLapsedSuspensions(
+	ctx context.Context,
+	now time.Time,
+	limit int,
+) ([]*entities.ModerationAction, error) {
+	rows, err := r.queries().ListLapsedSuspensions(ctx, &postgresdb.ListLapsedSuspensionsParams{
+		Now:      now.UTC(),
+		RowLimit: int32(limit),
+	})
+	if err != nil {
+		return nil, handleModerationError(err, "list lapsed suspensions", entities.ErrModerationActionNotFound)
+	}
+
+	return scanAll[*entities.ModerationAction](rows)
+}
+
+// CreateAppeal records an appeal and assigns the generated ID.
+func (r *ModerationRepository) CreateAppeal(ctx context.Context, appeal *entities.Appeal) error {
+	id, err := r.queries().CreateModerationAppeal(ctx, &postgresdb.CreateModerationAppealParams{
+		ActionID:  appeal.ActionID.Int64(),
+		UserID:    int64(appeal.UserID),
+		Statement: appeal.Statement,
+		Status:    appeal.Status.String(),
+		CreatedAt: appeal.CreatedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("appeal of moderation action id=%d: %w", appeal.ActionID,
+			handleModerationError(err, "record appeal", entities.ErrAppealNotFound))
+	}
+
+	appeal.ID = entities.AppealID(id)
+
+	return nil
+}
+
+// GetAppeal retrieves the appeal with id.
+func (r *ModerationRepository) GetAppeal(ctx context.Context, id entities.AppealID) (*entities.Appeal, error) {
+	row, err := r.queries().GetModerationAppeal(ctx, id.Int64())
+	if err != nil {
+		return nil, fmt.Errorf("appeal id=%d: %w", id,
+			handleModerationError(err, "get appeal", entities.ErrAppealNotFound))
+	}
+
+	return domainAppeal(row)
+}
+
+// DecideAppeal stores the decision of an appeal that was not decided before.
+func (r *ModerationRepository) DecideAppeal(ctx context.Context, appeal *entities.Appeal) error {
+	affected, err := r.queries().DecideModerationAppeal(ctx, &postgresdb.DecideModerationAppealParams{
+		Status:     appeal.Status.String(),
+		ReviewerID: int64(appeal.ReviewerID),
+		Response:   appeal.Response,
+		DecidedAt:  nullTimestamptz.DomainToDB(appeal.DecidedAt),
+		ID:         appeal.ID.Int64(),
+	})
+	if err != nil {
+		return fmt.Errorf("appeal id=%d: %w", appeal.ID,
+			handleModerationError(err, "decide appeal", entities.ErrAppealNotFound))
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("appeal id=%d: %w", appeal.ID, entities.ErrAppealNotFound)
+	}
+
+	return nil
+}
+
+// ListPendingAppeals returns up to limit undecided appeals, oldest first.
+func (r *ModerationRepository) ListPendingAppeals(ctx context.Context, limit int) ([]*entities.Appeal, error) {
+	rows, err := r.queries().ListPendingModerationAppeals(ctx, int32(limit))
+	if err != nil {
+		return nil, handleModerationError(err, "list pending appeals", entities.ErrAppealNotFound)
+	}
+
+	return scanAll[*entities.Appeal](rows)
+}
+
+// ListAppealsByUser returns the appeals of a user, newest first.
+func (r *ModerationRepository) ListAppealsByUser(
+	ctx context.Context,
+	userID entities.UserID,
+) ([]*entities.Appeal, error) {
+	rows, err := r.queries().ListModerationAppealsByUser(ctx, int64(userID))
+	if err != nil {
+		return nil, fmt.Errorf("user=%v: %w", userID,
+			handleModerationError(err, "list moderation appeals", entities.ErrAppealNotFound))
+	}
+
+	return scanAll[*entities.Appeal](rows)
+}
+
+// DeleteByUser removes the actions against a user and the appeals the user
+// made. The appeals are deleted first rather than left to the foreign key
+// cascade, which SQLite only enforces when enabled on the connection.
+func (r *ModerationRepository) DeleteByUser(ctx context.Context, userID entities.UserID) (int64, error) {
+	_, err := r.queries().DeleteModerationAppealsByUser(ctx, int64(userID))
+	if err != nil {
+		return 0, fmt.Errorf("user=%v: %w", userID,
+			handleModerationError(err, "delete moderation appeals", entities.ErrAppealNotFound))
+	}
+
+	deleted, err := r.queries().DeleteModerationActionsByUser(ctx, int64(userID))
+	if err != nil {
+		return 0, fmt.Errorf("user=%v: %w", userID,
+			handleModerationError(err, "delete moderation actions", entities.ErrModerationActionNotFound))
+	}
+
+	return deleted, nil
+}
+
+// domainModerationAction converts a generated moderation_actions row into a domain entity.
+func domainModerationAction(row *postgresdb.ModerationActions) (*entities.ModerationAction, error) {
+	endsAt, err := nullTimestamptz.DBToDomain(row.EndsAt)
+	if err != nil {
+		return nil, fmt.Errorf("moderation action id=%d: %w", row.ID, err)
+	}
+
+	liftedAt, err := nullTimestamptz.DBToDomain(row.LiftedAt)
+	if err != nil {
+		return nil, fmt.Errorf("moderation action id=%d: %w", row.ID, err)
+	}
+
+	return &entities.ModerationAction{
+		ID:          entities.ModerationActionID(row.ID),
+		UserID:      entities.UserID(row.UserID),
+		Kind:        entities.ModerationKind(row.Kind),
+		Reason:      row.Reason,
+		ModeratorID: entities.UserID(row.ModeratorID),
+		EndsAt:      endsAt,
+		LiftedAt:    liftedAt,
+		LiftedBy:    entities.UserID(row.LiftedBy),
+		CreatedAt:   row.CreatedAt,
+	}, nil
+}
+
+// domainAppeal converts a generated moderation_appeals row into a domain entity.
+func domainAppeal(row *postgresdb.ModerationAppeals) (*entities.Appeal, error) {
+	decidedAt, err := nullTimestamptz.DBToDomain(row.DecidedAt)
+	if err != nil {
+		return nil, fmt.Errorf("appeal id=%d: %w", row.ID, err)
+	}
+
+	return &entities.Appeal{
+		ID:         entities.AppealID(row.ID),
+		ActionID:   entities.ModerationActionID(row.ActionID),
+		UserID:     entities.UserID(row.UserID),
+		Statement:  row.Statement,
+		Status:     entities.AppealStatus(row.Status),
+		ReviewerID: entities.UserID(row.ReviewerID),
+		Response:   row.Response,
+		CreatedAt:  row.CreatedAt,
+		DecidedAt:  decidedAt,
+	}, nil
+}
+
+// handleModerationError maps database errors for moderation queries to
+// domain errors. The only unique constraint allows one appeal per action.
+func handleModerationError(err error, operation string, notFound error) error {
+	return postgresdb.HandleDBError(err, operation, notFound, entities.ErrAppealExists)
+}
@@ -0,0 +1,21 @@
+package postgres
+
+import (
+	"github.com/LarsArtmann/template-sqlc/internal/adapters"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// ModerationRepository implements ModerationRepository for PostgreSQL.
+type ModerationRepository struct {
+	*adapters.NotImplementedModerationRepository
+
+	db DBTX
+}
+
+// NewModerationRepository creates a new PostgreSQL moderation repository.
+func NewModerationRepository(db DBTX) repositories.ModerationRepository {
+	return &ModerationRepository{
+		NotImplementedModerationRepository: adapters.NewNotImplementedModerationRepository("PostgreSQL"),
+		db:                                 db,
+	}
+}
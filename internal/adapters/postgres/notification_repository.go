@@ -0,0 +1,23 @@
+package postgres
+
+import (
+	"github.com/LarsArtmann/template-sqlc/internal/adapters"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// NotificationPreferenceRepository implements NotificationPreferenceRepository for PostgreSQL.
+type NotificationPreferenceRepository struct {
+	*adapters.NotImplementedNotificationPreferenceRepository
+
+	db DBTX
+}
+
+// NewNotificationPreferenceRepository creates a new PostgreSQL notification preference repository.
+func NewNotificationPreferenceRepository(db DBTX) repositories.NotificationPreferenceRepository {
+	return &NotificationPreferenceRepository{
+		NotImplementedNotificationPreferenceRepository: adapters.NewNotImplementedNotificationPreferenceRepository(
+			"PostgreSQL",
+		),
+		db: db,
+	}
+}
@@ -0,0 +1,36 @@
+package postgres
+
+import (
+	"github.com/LarsArtmann/template-sqlc/internal/adapters"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// OrganizationRepository implements OrganizationRepository for PostgreSQL.
+type OrganizationRepository struct {
+	*adapters.NotImplementedOrganizationRepository
+
+	db DBTX
+}
+
+// NewOrganizationRepository creates a new PostgreSQL organization repository.
+func NewOrganizationRepository(db DBTX) repositories.OrganizationRepository {
+	return &OrganizationRepository{
+		NotImplementedOrganizationRepository: adapters.NewNotImplementedOrganizationRepository("PostgreSQL"),
+		db:                                   db,
+	}
+}
+
+// MembershipRepository implements MembershipRepository for PostgreSQL.
+type MembershipRepository struct {
+	*adapters.NotImplementedMembershipRepository
+
+	db DBTX
+}
+
+// NewMembershipRepository creates a new PostgreSQL membership repository.
+func NewMembershipRepository(db DBTX) repositories.MembershipRepository {
+	return &MembershipRepository{
+		NotImplementedMembershipRepository: adapters.NewNotImplementedMembershipRepository("PostgreSQL"),
+		db:                                 db,
+	}
+}
@@ -0,0 +1,21 @@
+package postgres
+
+import (
+	"github.com/LarsArtmann/template-sqlc/internal/adapters"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// SessionRepository implements SessionRepository for PostgreSQL.
+type SessionRepository struct {
+	*adapters.NotImplementedSessionRepository
+
+	db DBTX
+}
+
+// NewSessionRepository creates a new PostgreSQL session repository.
+func NewSessionRepository(db DBTX) repositories.SessionRepository {
+	return &SessionRepository{
+		NotImplementedSessionRepository: adapters.NewNotImplementedSessionRepository("PostgreSQL"),
+		db:                              db,
+	}
+}
@@ -0,0 +1,120 @@
+//go:build postgres
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/mappers"
+	postgresdb "github.com/LarsArtmann/template-sqlc/internal/db/postgres"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/google/uuid"
+)
+
+// CreateBatch streams the users through COPY and then reads back their IDs by
+// UUID, since COPY cannot return generated columns.
+func (r *UserRepository) CreateBatch(ctx context.Context, users []*entities.User) error {
+	if len(users) == 0 {
+		return nil
+	}
+
+	rows := make([]*postgresdb.CopyUsersParams, 0, len(users))
+	uuids := make([]uuid.UUID, 0, len(users))
+	byUUID := make(map[uuid.UUID]*entities.User, len(users))
+
+	for _, user := range users {
+		metadata, err := mappers.EncodeMetadata(user.Metadata())
+		if err != nil {
+			return fmt.Errorf("create user email=%v: %w", user.Email(), err)
+		}
+
+		isActive := true
+		isVerified := user.IsVerified()
+
+		rows = append(rows, &postgresdb.CopyUsersParams{
+			UUID:              user.UUID(),
+			Email:             user.Email().String(),
+			Username:          user.Username().String(),
+			PasswordHash:      user.PasswordHash().String(),
+			FirstName:         user.FirstName().String(),
+			LastName:          user.LastName().String(),
+			ProfileMetadata:   []byte(metadata),
+			IsActive:          &isActive,
+			IsVerified:        &isVerified,
+			Status:            string(user.Status()),
+			Role:              string(user.Role()),
+			Tags:              nonNilTags(user.Tags()),
+			CreatedAt:         timestamptz(user.CreatedAt()),
+			UpdatedAt:         timestamptz(user.UpdatedAt()),
+			UsernameCanonical: user.Username().Canonical(),
+		})
+		uuids = append(uuids, user.UUID())
+		byUUID[user.UUID()] = user
+	}
+
+	_, err := r.queries().CopyUsers(ctx, rows)
+	if err != nil {
+		return fmt.Errorf("create batch of %d: %w", len(users), handleError(err, "copy users"))
+	}
+
+	created, err := r.queries().GetUserIDsByUUIDs(ctx, uuids)
+	if err != nil {
+		return fmt.Errorf("create batch of %d: %w", len(users), handleError(err, "read user ids"))
+	}
+
+	for _, row := range created {
+		byUUID[row.UUID].SetID(entities.UserID(row.ID))
+	}
+
+	return nil
+}
+
+// UpdateStatusBatch changes the statuses in one statement over an ID array.
+func (r *UserRepository) UpdateStatusBatch(
+	ctx context.Context,
+	ids []entities.UserID,
+	status entities.UserStatus,
+) (int64, error) {
+	if !status.IsValid() {
+		return 0, fmt.Errorf("status=%v: %w", status, entities.ErrInvalidUserStatus)
+	}
+
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	updated, err := r.queries().UpdateUsersStatus(ctx, &postgresdb.UpdateUsersStatusParams{
+		Status: string(status),
+		Ids:    userIDKeys(ids),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("update batch of %d: %w", len(ids), handleError(err, "update user statuses"))
+	}
+
+	return updated, nil
+}
+
+// DeleteBatch soft deletes the users in one statement over an ID array.
+func (r *UserRepository) DeleteBatch(ctx context.Context, ids []entities.UserID) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	deleted, err := r.queries().SoftDeleteUsers(ctx, userIDKeys(ids))
+	if err != nil {
+		return 0, fmt.Errorf("delete batch of %d: %w", len(ids), handleError(err, "delete users"))
+	}
+
+	return deleted, nil
+}
+
+// userIDKeys converts user IDs to their column values.
+func userIDKeys(ids []entities.UserID) []int64 {
+	keys := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		keys = append(keys, int64(id))
+	}
+
+	return keys
+}
@@ -0,0 +1,21 @@
+package postgres
+
+import (
+	"github.com/LarsArtmann/template-sqlc/internal/adapters"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// UserEventStore implements UserEventStore for PostgreSQL.
+type UserEventStore struct {
+	*adapters.NotImplementedUserEventStore
+
+	db DBTX
+}
+
+// NewUserEventStore creates a new PostgreSQL user event store.
+func NewUserEventStore(db DBTX) repositories.UserEventStore {
+	return &UserEventStore{
+		NotImplementedUserEventStore: adapters.NewNotImplementedUserEventStore("PostgreSQL"),
+		db:                           db,
+	}
+}
@@ -0,0 +1,110 @@
+//go:build postgres
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/mappers"
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/validation"
+	postgresdb "github.com/LarsArtmann/template-sqlc/internal/db/postgres"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+)
+
+// queries returns the sqlc queries bound to the repository's database handle.
+func (r *UserHistoryRepository) queries() *postgresdb.Queries {
+	return postgresdb.New(r.db)
+}
+
+// GetUserAsOf returns the version of a user that was valid at the given time.
+func (r *UserHistoryRepository) GetUserAsOf(
+	ctx context.Context,
+	id entities.UserID,
+	at time.Time,
+) (*entities.UserSnapshot, error) {
+	row, err := r.queries().GetUserAsOf(ctx, &postgresdb.GetUserAsOfParams{
+		UserID: int64(id),
+		AsOf:   at.UTC(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("user id=%v as of %v: %w", id, at, handleError(err, "get user as of"))
+	}
+
+	var validTo *time.Time
+	if row.ValidTo.Valid {
+		validTo = &row.ValidTo.Time
+	}
+
+	return mappers.DomainUserSnapshot(mappers.UserSnapshotRow{
+		UserID:     entities.UserID(row.UserID),
+		Operation:  row.Operation,
+		Email:      row.Email,
+		Username:   row.Username,
+		FirstName:  row.FirstName,
+		LastName:   row.LastName,
+		Status:     row.Status,
+		Role:       row.Role,
+		Tags:       row.Tags,
+		IsVerified: row.IsVerified != nil && *row.IsVerified,
+		ValidFrom:  row.ValidFrom.Time,
+		ValidTo:    validTo,
+	})
+}
+
+// ListVersions returns the archived versions of a user, newest first.
+func (r *UserHistoryRepository) ListVersions(
+	ctx context.Context,
+	id entities.UserID,
+	limit int,
+) ([]*entities.UserSnapshot, error) {
+	err := validation.ValidatePagination(limit, 0)
+	if err != nil {
+		return nil, fmt.Errorf("limit=%v: %w", limit, err)
+	}
+
+	rows, err := r.queries().ListUserHistory(ctx, &postgresdb.ListUserHistoryParams{
+		UserID: int64(id),
+		Limit:  int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("user id=%v: %w", id, handleError(err, "list user versions"))
+	}
+
+	versions := make([]*entities.UserSnapshot, 0, len(rows))
+
+	for _, row := range rows {
+		version, err := mappers.DomainUserSnapshot(mappers.UserSnapshotRow{
+			UserID:     entities.UserID(row.UserID),
+			Operation:  row.Operation,
+			Email:      row.Email,
+			Username:   row.Username,
+			FirstName:  row.FirstName,
+			LastName:   row.LastName,
+			Status:     row.Status,
+			Role:       row.Role,
+			Tags:       row.Tags,
+			IsVerified: row.IsVerified != nil && *row.IsVerified,
+			ValidFrom:  row.ValidFrom,
+			ValidTo:    &row.ValidTo,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		versions = append(versions, version)
+	}
+
+	return versions, nil
+}
+
+// DeleteVersions removes every archived version of a user.
+func (r *UserHistoryRepository) DeleteVersions(ctx context.Context, id entities.UserID) (int64, error) {
+	deleted, err := r.queries().DeleteUserHistory(ctx, int64(id))
+	if err != nil {
+		return 0, fmt.Errorf("user id=%v: %w", id, handleError(err, "delete user versions"))
+	}
+
+	return deleted, nil
+}
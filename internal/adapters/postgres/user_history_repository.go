@@ -0,0 +1,22 @@
+package postgres
+
+import (
+	"github.com/LarsArtmann/template-sqlc/internal/adapters"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// UserHistoryRepository implements UserHistoryRepository for PostgreSQL.
+// The versions are archived by the triggers of the users table.
+type UserHistoryRepository struct {
+	*adapters.NotImplementedUserHistoryRepository
+
+	db DBTX
+}
+
+// NewUserHistoryRepository creates a new PostgreSQL user history repository.
+func NewUserHistoryRepository(db DBTX) repositories.UserHistoryRepository {
+	return &UserHistoryRepository{
+		NotImplementedUserHistoryRepository: adapters.NewNotImplementedUserHistoryRepository("PostgreSQL"),
+		db:                                  db,
+	}
+}
@@ -0,0 +1,88 @@
+//go:build postgres
+
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	postgresdb "github.com/LarsArtmann/template-sqlc/internal/db/postgres"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	apperrors "github.com/LarsArtmann/template-sqlc/pkg/errors"
+	"github.com/jackc/pgx/v5"
+)
+
+// queries returns the sqlc queries bound to the repository's database handle.
+func (r *UserPreferenceRepository) queries() *postgresdb.Queries {
+	return postgresdb.New(r.db)
+}
+
+// Get returns the stored preferences of a user, empty if none were stored.
+func (r *UserPreferenceRepository) Get(ctx context.Context, userID entities.UserID) (*entities.UserPreferences, error) {
+	row, err := r.queries().GetUserPreferences(ctx, int64(userID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entities.NewUserPreferences(userID, nil, time.Time{}), nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("user=%v: %w", userID, apperrors.NewDatabaseError("get user preferences failed", err))
+	}
+
+	return domainUserPreferences(row)
+}
+
+// Patch merges the Set preferences into the stored JSONB document and
+// deletes the Unset keys in one upsert.
+func (r *UserPreferenceRepository) Patch(
+	ctx context.Context,
+	userID entities.UserID,
+	patch entities.PreferencePatch,
+) (*entities.UserPreferences, error) {
+	set, err := json.Marshal(patch.Set)
+	if err != nil {
+		return nil, fmt.Errorf("encode preferences user=%v: %w", userID, err)
+	}
+
+	// A NULL array would turn the whole document NULL.
+	removed := make([]string, 0, len(patch.Unset))
+	for _, key := range patch.Unset {
+		removed = append(removed, key.String())
+	}
+
+	row, err := r.queries().PatchUserPreferences(ctx, &postgresdb.PatchUserPreferencesParams{
+		UserID:    int64(userID),
+		Set:       set,
+		UpdatedAt: time.Now().UTC(),
+		Removed:   removed,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("user=%v: %w", userID, apperrors.NewDatabaseError("patch user preferences failed", err))
+	}
+
+	return domainUserPreferences(row)
+}
+
+// Delete removes the preferences of a user; none stored is not an error.
+func (r *UserPreferenceRepository) Delete(ctx context.Context, userID entities.UserID) error {
+	_, err := r.queries().DeleteUserPreferences(ctx, int64(userID))
+	if err != nil {
+		return fmt.Errorf("user=%v: %w", userID, apperrors.NewDatabaseError("delete user preferences failed", err))
+	}
+
+	return nil
+}
+
+// domainUserPreferences converts a generated user_preferences row into a domain entity.
+func domainUserPreferences(row *postgresdb.UserPreferences) (*entities.UserPreferences, error) {
+	var values map[entities.PreferenceKey]any
+
+	err := json.Unmarshal(row.Preferences, &values)
+	if err != nil {
+		return nil, fmt.Errorf("decode preferences user=%v: %w", row.UserID, err)
+	}
+
+	return entities.NewUserPreferences(entities.UserID(row.UserID), values, row.UpdatedAt), nil
+}
@@ -0,0 +1,21 @@
+package postgres
+
+import (
+	"github.com/LarsArtmann/template-sqlc/internal/adapters"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// UserPreferenceRepository implements UserPreferenceRepository for PostgreSQL.
+type UserPreferenceRepository struct {
+	*adapters.NotImplementedUserPreferenceRepository
+
+	db DBTX
+}
+
+// NewUserPreferenceRepository creates a new PostgreSQL user preference repository.
+func NewUserPreferenceRepository(db DBTX) repositories.UserPreferenceRepository {
+	return &UserPreferenceRepository{
+		NotImplementedUserPreferenceRepository: adapters.NewNotImplementedUserPreferenceRepository("PostgreSQL"),
+		db:                                     db,
+	}
+}
@@ -0,0 +1,571 @@
+//go:build postgres
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters"
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/mappers"
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/validation"
+	postgresdb "github.com/LarsArtmann/template-sqlc/internal/db/postgres"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// queries returns the sqlc queries bound to the repository's database handle.
+func (r *UserRepository) queries() *postgresdb.Queries {
+	return postgresdb.New(r.db)
+}
+
+// Create inserts a new user and assigns the generated ID.
+func (r *UserRepository) Create(ctx context.Context, user *entities.User) error {
+	metadata, err := mappers.EncodeMetadata(user.Metadata())
+	if err != nil {
+		return fmt.Errorf("create user email=%v: %w", user.Email(), err)
+	}
+
+	isActive := true
+	isVerified := user.IsVerified()
+
+	created, err := r.queries().CreateUser(ctx, &postgresdb.CreateUserParams{
+		UUID:              user.UUID(),
+		Email:             user.Email().String(),
+		Username:          user.Username().String(),
+		PasswordHash:      user.PasswordHash().String(),
+		FirstName:         user.FirstName().String(),
+		LastName:          user.LastName().String(),
+		ProfileMetadata:   []byte(metadata),
+		IsActive:          &isActive,
+		IsVerified:        &isVerified,
+		Status:            string(user.Status()),
+		Role:              string(user.Role()),
+		Tags:              nonNilTags(user.Tags()),
+		CreatedAt:         timestamptz(user.CreatedAt()),
+		UpdatedAt:         timestamptz(user.UpdatedAt()),
+		UsernameCanonical: user.Username().Canonical(),
+	})
+	if err != nil {
+		return fmt.Errorf("create user email=%v: %w", user.Email(), handleError(err, "create user"))
+	}
+
+	user.SetID(entities.UserID(created.ID))
+
+	return nil
+}
+
+// GetByID retrieves a user by ID.
+func (r *UserRepository) GetByID(ctx context.Context, id entities.UserID) (*entities.User, error) {
+	row, err := r.queries().GetUserByID(ctx, int64(id))
+	if err != nil {
+		return nil, fmt.Errorf("id=%v: %w", id, handleError(err, "get user"))
+	}
+
+	return domainUser(row)
+}
+
+// GetByIDs retrieves the users with the given IDs in one query.
+func (r *UserRepository) GetByIDs(ctx context.Context, ids []entities.UserID) ([]*entities.User, error) {
+	if len(ids) == 0 {
+		return []*entities.User{}, nil
+	}
+
+	rows, err := r.queries().GetUsersByIDs(ctx, userIDKeys(ids))
+	if err != nil {
+		return nil, fmt.Errorf("count=%v: %w", len(ids), handleError(err, "get users"))
+	}
+
+	return domainUsers(rows)
+}
+
+// GetByUUID retrieves a user by UUID.
+func (r *UserRepository) GetByUUID(ctx context.Context, uuid entities.UuID) (*entities.User, error) {
+	parsed, err := mappers.ParseUUID(uuid)
+	if err != nil {
+		return nil, fmt.Errorf("uuid=%v: %w", uuid, entities.ErrUserNotFound)
+	}
+
+	row, err := r.queries().GetUserByUUID(ctx, parsed)
+	if err != nil {
+		return nil, fmt.Errorf("uuid=%v: %w", uuid, handleError(err, "get user"))
+	}
+
+	return domainUser(row)
+}
+
+// GetByEmail retrieves a user by email.
+func (r *UserRepository) GetByEmail(ctx context.Context, email entities.Email) (*entities.User, error) {
+	row, err := r.queries().GetUserByEmail(ctx, email.String())
+	if err != nil {
+		return nil, fmt.Errorf("email=%v: %w", email, handleError(err, "get user"))
+	}
+
+	return domainUser(row)
+}
+
+// GetByUsername retrieves a user by canonical username.
+func (r *UserRepository) GetByUsername(
+	ctx context.Context,
+	username entities.Username,
+) (*entities.User, error) {
+	row, err := r.queries().GetUserByUsername(ctx, username.Canonical())
+	if err != nil {
+		return nil, fmt.Errorf("username=%v: %w", username, handleError(err, "get user"))
+	}
+
+	return domainUser(row)
+}
+
+// Update persists every mutable field of the user.
+func (r *UserRepository) Update(ctx context.Context, user *entities.User) error {
+	metadata, err := mappers.EncodeMetadata(user.Metadata())
+	if err != nil {
+		return fmt.Errorf("update user id=%v: %w", user.ID(), err)
+	}
+
+	isVerified := user.IsVerified()
+	status := string(user.Status())
+	role := string(user.Role())
+
+	_, err = r.queries().UpdateUser(ctx, &postgresdb.UpdateUserParams{
+		ID:                int64(user.ID()),
+		Email:             user.Email().String(),
+		Username:          user.Username().String(),
+		FirstName:         user.FirstName().String(),
+		LastName:          user.LastName().String(),
+		ProfileMetadata:   []byte(metadata),
+		IsVerified:        &isVerified,
+		Status:            &status,
+		Role:              &role,
+		Tags:              nonNilTags(user.Tags()),
+		LastLoginAt:       nullTimestamptz.DomainToDB(user.LastLoginAt()),
+		UsernameCanonical: user.Username().Canonical(),
+	})
+	if err != nil {
+		return fmt.Errorf("update user id=%v: %w", user.ID(), handleError(err, "update user"))
+	}
+
+	return nil
+}
+
+// Delete soft deletes a user.
+func (r *UserRepository) Delete(ctx context.Context, id entities.UserID) error {
+	err := r.queries().SoftDeleteUser(ctx, int64(id))
+	if err != nil {
+		return fmt.Errorf("delete user id=%v: %w", id, handleError(err, "delete user"))
+	}
+
+	return nil
+}
+
+// Restore restores a soft-deleted user.
+func (r *UserRepository) Restore(ctx context.Context, id entities.UserID) error {
+	restored, err := r.queries().RestoreUser(ctx, int64(id))
+	if err != nil {
+		return fmt.Errorf("restore user id=%v: %w", id, handleError(err, "restore user"))
+	}
+
+	if restored == 0 {
+		return fmt.Errorf("restore user id=%v: %w", id, entities.ErrUserNotFound)
+	}
+
+	return nil
+}
+
+// PurgeDeletedOlderThan permanently removes the users soft deleted before cutoff.
+func (r *UserRepository) PurgeDeletedOlderThan(ctx context.Context, cutoff time.Time) (int64, error) {
+	purged, err := r.queries().PurgeDeletedUsers(ctx, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("purge users cutoff=%v: %w", cutoff, handleError(err, "purge users"))
+	}
+
+	return purged, nil
+}
+
+// List retrieves a page of the users matching filter, newest first.
+func (r *UserRepository) List(
+	ctx context.Context,
+	filter entities.UserFilter,
+	limit, offset int,
+) ([]*entities.User, error) {
+	err := adapters.ValidateListRequest(filter, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	var first *entities.UserCursor
+
+	return r.filterUsers(ctx, filter, first.Bound(), limit, offset)
+}
+
+// ListPage retrieves a page of users with keyset pagination, newest first.
+func (r *UserRepository) ListPage(
+	ctx context.Context,
+	filter entities.UserFilter,
+	cursor string,
+	limit int,
+) (*entities.UserPage, error) {
+	after, err := adapters.ParsePageRequest(filter, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	// One extra row tells whether another page follows.
+	users, err := r.filterUsers(ctx, filter, after.Bound(), limit+1, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return entities.NewUserPage(users, limit), nil
+}
+
+// filterUsers runs FilterUsers for the users matching filter after bound.
+// The query is matched through the tsvector search document.
+func (r *UserRepository) filterUsers(
+	ctx context.Context,
+	filter entities.UserFilter,
+	bound entities.UserCursor,
+	limit, offset int,
+) ([]*entities.User, error) {
+	createdAfter, createdBefore := filter.CreatedRange()
+
+	rows, err := r.queries().FilterUsers(ctx, &postgresdb.FilterUsersParams{
+		Statuses:        filter.StatusValues(),
+		Roles:           filter.RoleValues(),
+		Verified:        int32(filter.VerifiedState()),
+		CreatedAfter:    createdAfter,
+		CreatedBefore:   createdBefore,
+		TagsAny:         nonNilTags(filter.TagsAny),
+		TagsAll:         nonNilTags(filter.TagsAll),
+		Query:           tsQuery(filter.Query),
+		CursorCreatedAt: bound.CreatedAt,
+		CursorID:        bound.ID.Int64(),
+		RowOffset:       int32(offset),
+		RowLimit:        int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("filter users filter=%+v: %w", filter, handleError(err, "filter users"))
+	}
+
+	return domainUsers(rows)
+}
+
+// SearchWithFacets searches users and returns facet counts over every match.
+// Users in the result carry the columns needed for listing only; load a user
+// by ID for the full record.
+func (r *UserRepository) SearchWithFacets(
+	ctx context.Context,
+	query string,
+	status entities.UserStatus,
+	limit int,
+) (*entities.UserSearchResult, error) {
+	err := validation.ValidateSearchQuery(query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("query=%v limit=%v: %w", query, limit, err)
+	}
+
+	rows, err := r.queries().SearchUsersWithFacets(ctx, &postgresdb.SearchUsersWithFacetsParams{
+		Query:       query,
+		TagLimit:    adapters.DefaultFacetTagLimit,
+		Status:      string(status),
+		ResultLimit: int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("search users query=%v: %w", query, handleError(err, "search users with facets"))
+	}
+
+	result := &entities.UserSearchResult{
+		Users:  make([]*entities.User, 0, len(rows)),
+		Facets: entities.NewSearchFacets(),
+	}
+
+	if len(rows) == 0 {
+		return result, nil
+	}
+
+	result.Facets, err = mappers.DecodeSearchFacets(rows[0].StatusFacets, rows[0].RoleFacets, rows[0].TagFacets)
+	if err != nil {
+		return nil, fmt.Errorf("search users query=%v: %w", query, err)
+	}
+
+	mapper := mappers.NewUserMapper()
+
+	for _, row := range rows {
+		if row.ID == nil {
+			continue
+		}
+
+		user, err := mapper.DomainUserFromRow(&mappers.UserRow{
+			ID:        *row.ID,
+			UUID:      row.UUID.String(),
+			Email:     deref(row.Email),
+			Username:  deref(row.Username),
+			FirstName: deref(row.FirstName),
+			LastName:  deref(row.LastName),
+			Status:    deref(row.Status),
+			Role:      deref(row.Role),
+			Tags:      row.Tags,
+			CreatedAt: row.CreatedAt.Time,
+			UpdatedAt: row.CreatedAt.Time,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		result.Users = append(result.Users, user)
+	}
+
+	return result, nil
+}
+
+// CountByStatus counts active users per status.
+func (r *UserRepository) CountByStatus(ctx context.Context) (map[entities.UserStatus]int64, error) {
+	rows, err := r.queries().CountUsersByStatus(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("count users by status: %w", handleError(err, "count users by status"))
+	}
+
+	counts := make(map[entities.UserStatus]int64, len(rows))
+	for _, row := range rows {
+		counts[entities.UserStatus(row.Status)] = row.Total
+	}
+
+	return counts, nil
+}
+
+// GetStats returns aggregate user statistics.
+func (r *UserRepository) GetStats(ctx context.Context) (*entities.UserStats, error) {
+	row, err := r.queries().GetUserStats(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get user stats: %w", handleError(err, "get user stats"))
+	}
+
+	stats := &entities.UserStats{
+		TotalUsers:      row.TotalUsers,
+		ActiveUsers:     row.ActiveUsers,
+		InactiveUsers:   row.InactiveUsers,
+		SuspendedUsers:  row.SuspendedUsers,
+		VerifiedUsers:   row.VerifiedUsers,
+		UsersWithLogins: row.UsersWithLogins,
+		NewUsers30d:     row.NewUsers30d,
+		NewUsers7d:      row.NewUsers7d,
+	}
+	stats.ComputeRates()
+
+	return stats, nil
+}
+
+// GetCredentials returns the user of email with its password hash.
+func (r *UserRepository) GetCredentials(ctx context.Context, email entities.Email) (*entities.User, error) {
+	return r.GetByEmail(ctx, email)
+}
+
+// UpdatePassword stores a new password hash.
+func (r *UserRepository) UpdatePassword(
+	ctx context.Context,
+	id entities.UserID,
+	password entities.PasswordHash,
+) error {
+	err := r.queries().UpdatePassword(ctx, &postgresdb.UpdatePasswordParams{
+		ID:           int64(id),
+		PasswordHash: password.String(),
+	})
+	if err != nil {
+		return fmt.Errorf("update password id=%v: %w", id, handleError(err, "update password"))
+	}
+
+	return nil
+}
+
+// MarkVerified marks a user's email as verified.
+func (r *UserRepository) MarkVerified(ctx context.Context, id entities.UserID) error {
+	err := r.queries().MarkUserVerified(ctx, int64(id))
+	if err != nil {
+		return fmt.Errorf("mark verified id=%v: %w", id, handleError(err, "mark user verified"))
+	}
+
+	return nil
+}
+
+// ChangeStatus changes user status.
+func (r *UserRepository) ChangeStatus(
+	ctx context.Context,
+	id entities.UserID,
+	status entities.UserStatus,
+) error {
+	if !status.IsValid() {
+		return fmt.Errorf("status=%v: %w", status, entities.ErrInvalidUserStatus)
+	}
+
+	err := r.queries().UpdateUserStatus(ctx, &postgresdb.UpdateUserStatusParams{
+		ID:     int64(id),
+		Status: string(status),
+	})
+	if err != nil {
+		return fmt.Errorf("change status id=%v: %w", id, handleError(err, "update user status"))
+	}
+
+	return nil
+}
+
+// Activate activates a user.
+func (r *UserRepository) Activate(ctx context.Context, id entities.UserID) error {
+	return r.ChangeStatus(ctx, id, entities.UserStatusActive)
+}
+
+// Deactivate deactivates a user.
+func (r *UserRepository) Deactivate(ctx context.Context, id entities.UserID) error {
+	return r.ChangeStatus(ctx, id, entities.UserStatusInactive)
+}
+
+// Suspend suspends a user.
+func (r *UserRepository) Suspend(ctx context.Context, id entities.UserID) error {
+	return r.ChangeStatus(ctx, id, entities.UserStatusSuspended)
+}
+
+// ChangeRole changes user role.
+func (r *UserRepository) ChangeRole(
+	ctx context.Context,
+	id entities.UserID,
+	role entities.UserRole,
+) error {
+	if !role.IsValid() {
+		return fmt.Errorf("role=%v: %w", role, entities.ErrInvalidUserRole)
+	}
+
+	err := r.queries().UpdateUserRole(ctx, &postgresdb.UpdateUserRoleParams{
+		ID:   int64(id),
+		Role: string(role),
+	})
+	if err != nil {
+		return fmt.Errorf("change role id=%v: %w", id, handleError(err, "update user role"))
+	}
+
+	return nil
+}
+
+// GetByIDForUpdate locks the user row until the surrounding transaction ends.
+// It fails with adapters.ErrLockOutsideTransaction outside a transaction.
+func (r *UserRepository) GetByIDForUpdate(
+	ctx context.Context,
+	id entities.UserID,
+	opts ...repositories.LockOption,
+) (*entities.User, error) {
+	if !r.inTransaction() {
+		return nil, fmt.Errorf("GetByIDForUpdate id=%v: %w", id, adapters.ErrLockOutsideTransaction)
+	}
+
+	if len(opts) == 0 {
+		row, err := r.queries().GetUserByIDForUpdate(ctx, int64(id))
+		if err != nil {
+			return nil, fmt.Errorf("id=%v: %w", id, handleError(err, "lock user"))
+		}
+
+		return domainUser(row)
+	}
+
+	rows, err := r.db.Query(ctx, adapters.WithLockingClause(postgresdb.GetUserByID, opts...), int64(id))
+	if err != nil {
+		return nil, fmt.Errorf("id=%v: %w", id, handleError(err, "lock user"))
+	}
+
+	row, err := pgx.CollectOneRow(rows, pgx.RowToAddrOfStructByName[postgresdb.Users])
+	if err != nil {
+		return nil, fmt.Errorf("id=%v: %w", id, handleError(err, "lock user"))
+	}
+
+	return domainUser(row)
+}
+
+// ClaimNext locks up to limit users in the given status with FOR UPDATE SKIP LOCKED.
+// It fails with adapters.ErrLockOutsideTransaction outside a transaction.
+func (r *UserRepository) ClaimNext(
+	ctx context.Context,
+	status entities.UserStatus,
+	limit int,
+) ([]*entities.User, error) {
+	if !r.inTransaction() {
+		return nil, fmt.Errorf("ClaimNext status=%v: %w", status, adapters.ErrLockOutsideTransaction)
+	}
+
+	if !status.IsValid() {
+		return nil, fmt.Errorf("status=%v: %w", status, entities.ErrInvalidUserStatus)
+	}
+
+	err := validation.ValidatePagination(limit, 0)
+	if err != nil {
+		return nil, fmt.Errorf("limit=%v: %w", limit, err)
+	}
+
+	rows, err := r.queries().ClaimUsersByStatus(ctx, &postgresdb.ClaimUsersByStatusParams{
+		Status:     string(status),
+		ClaimLimit: int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("claim users status=%v: %w", status, handleError(err, "claim users"))
+	}
+
+	return domainUsers(rows)
+}
+
+// inTransaction reports whether the repository is bound to a transaction.
+func (r *UserRepository) inTransaction() bool {
+	_, ok := r.db.(pgx.Tx)
+
+	return ok
+}
+
+// handleError maps PostgreSQL errors to domain errors.
+func handleError(err error, operation string) error {
+	return postgresdb.HandleDBError(err, operation, entities.ErrUserNotFound, entities.ErrUserAlreadyExists)
+}
+
+// tsQuery turns free text into a to_tsquery expression requiring every term
+// as a prefix. Terms are quoted so that user input cannot inject tsquery operators.
+func tsQuery(query string) string {
+	terms := strings.Fields(query)
+	for i, term := range terms {
+		term = strings.ReplaceAll(term, `\`, `\\`)
+		terms[i] = "'" + strings.ReplaceAll(term, "'", "''") + "':*"
+	}
+
+	return strings.Join(terms, " & ")
+}
+
+// timestamptz converts a time to a nullable timestamptz; the zero time is NULL.
+func timestamptz(t time.Time) pgtype.Timestamptz {
+	return pgtype.Timestamptz{Time: t, Valid: !t.IsZero()}
+}
+
+// nonNilTags returns an empty slice for nil tags so that TEXT[] NOT NULL holds.
+func nonNilTags(tags []string) []string {
+	if tags == nil {
+		return []string{}
+	}
+
+	return tags
+}
+
+// deref returns the pointed-to string, or "" for nil.
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+
+	return *s
+}
+
+// domainUser converts a generated users row into a domain entity.
+func domainUser(row *postgresdb.Users) (*entities.User, error) {
+	return mappers.NewUserMapper().DomainUserFromPostgres(row)
+}
+
+// domainUsers converts generated users rows into domain entities.
+func domainUsers(rows []*postgresdb.Users) ([]*entities.User, error) {
+	return scanAll[*entities.User](rows)
+}
@@ -0,0 +1,21 @@
+package postgres
+
+import (
+	"github.com/LarsArtmann/template-sqlc/internal/adapters"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// UserReadModelRepository implements UserReadModelRepository for PostgreSQL.
+type UserReadModelRepository struct {
+	*adapters.NotImplementedUserReadModelRepository
+
+	db DBTX
+}
+
+// NewUserReadModelRepository creates a new PostgreSQL user read model repository.
+func NewUserReadModelRepository(db DBTX) repositories.UserReadModelRepository {
+	return &UserReadModelRepository{
+		NotImplementedUserReadModelRepository: adapters.NewNotImplementedUserReadModelRepository("PostgreSQL"),
+		db:                                    db,
+	}
+}
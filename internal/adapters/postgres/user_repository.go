@@ -2,10 +2,35 @@
 package postgres
 
 import (
+	"context"
+
 	"github.com/LarsArtmann/template-sqlc/internal/adapters"
 	"github.com/LarsArtmann/template-sqlc/internal/adapters/converters"
 	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// DBTX is the pgx handle repositories run queries against.
+// It is satisfied by *pgxpool.Pool, *pgx.Conn and pgx.Tx, so the same
+// repository works on a pool or inside a transaction.
+type DBTX interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	CopyFrom(
+		ctx context.Context,
+		tableName pgx.Identifier,
+		columnNames []string,
+		rowSrc pgx.CopyFromSource,
+	) (int64, error)
+}
+
+var (
+	_ DBTX = (*pgxpool.Pool)(nil)
+	_ DBTX = (*pgx.Conn)(nil)
+	_ DBTX = (pgx.Tx)(nil)
 )
 
 // UserRepository implements UserRepository for PostgreSQL
@@ -13,15 +38,20 @@ import (
 type UserRepository struct {
 	*adapters.BaseUserRepository
 
-	pool       pgx.Tx
-	converters *converters.ConverterSet
+	db         DBTX
+	converters *converters.Registry
 }
 
 // NewUserRepository creates a new PostgreSQL user repository.
-func NewUserRepository(pool pgx.Tx) repositories.UserRepository {
+func NewUserRepository(db DBTX) repositories.UserRepository {
 	return &UserRepository{
 		BaseUserRepository: adapters.NewBaseUserRepository("PostgreSQL"),
-		pool:               pool,
-		converters:         converters.NewConverterSet(converters.DbTypePostgres),
+		db:                 db,
+		converters:         converters.Default(),
 	}
 }
+
+// Engine returns the converter engine of the repository's database.
+func (r *UserRepository) Engine() string {
+	return converters.DbTypePostgres
+}
@@ -0,0 +1,121 @@
+// Package pwned queries the Pwned Passwords range API of Have I Been Pwned,
+// which finds breached passwords by k-anonymity: only the first five
+// characters of the SHA-1 hash of a password are sent.
+package pwned
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/validation"
+)
+
+// Defaults of a Client.
+const (
+	DefaultBaseURL = "https://api.pwnedpasswords.com"
+	DefaultTimeout = 5 * time.Second
+)
+
+// userAgent identifies the client, as the API requires.
+const userAgent = "template-sqlc"
+
+// ErrUnexpectedResponse is returned for a failed request or a malformed line.
+var ErrUnexpectedResponse = errors.New("unexpected pwned passwords response")
+
+// Client is a validation.BreachRangeAPI over HTTP.
+type Client struct {
+	baseURL string
+	http    *http.Client
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithBaseURL points the client at a mirror or a test server.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		if baseURL != "" {
+			c.baseURL = strings.TrimSuffix(baseURL, "/")
+		}
+	}
+}
+
+// WithTimeout bounds each request, DefaultTimeout by default.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		if timeout > 0 {
+			c.http.Timeout = timeout
+		}
+	}
+}
+
+// NewClient creates a client of the public API.
+func NewClient(opts ...Option) *Client {
+	client := &Client{
+		baseURL: DefaultBaseURL,
+		http:    &http.Client{Timeout: DefaultTimeout},
+	}
+
+	for _, opt := range opts {
+		opt(client)
+	}
+
+	return client
+}
+
+// Range returns the hash suffixes of prefix and their breach counts. The
+// response is padded with decoy suffixes, so that its size does not reveal
+// the prefix; decoys have a count of zero and are left out.
+func (c *Client) Range(ctx context.Context, prefix string) (map[string]int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/range/"+prefix, nil)
+	if err != nil {
+		return nil, fmt.Errorf("range prefix=%v: %w", prefix, err)
+	}
+
+	req.Header.Set("Add-Padding", "true")
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("range prefix=%v: %w", prefix, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("range prefix=%v status=%d: %w", prefix, resp.StatusCode, ErrUnexpectedResponse)
+	}
+
+	counts := make(map[string]int)
+	scanner := bufio.NewScanner(resp.Body)
+
+	for scanner.Scan() {
+		suffix, count, ok := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		if !ok {
+			continue
+		}
+
+		n, err := strconv.Atoi(count)
+		if err != nil {
+			return nil, fmt.Errorf("range prefix=%v count=%q: %w", prefix, count, ErrUnexpectedResponse)
+		}
+
+		if n > 0 {
+			counts[strings.ToUpper(suffix)] = n
+		}
+	}
+
+	err = scanner.Err()
+	if err != nil {
+		return nil, fmt.Errorf("range prefix=%v: %w", prefix, err)
+	}
+
+	return counts, nil
+}
+
+var _ validation.BreachRangeAPI = (*Client)(nil)
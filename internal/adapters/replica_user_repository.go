@@ -0,0 +1,466 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+	apperrors "github.com/LarsArtmann/template-sqlc/pkg/errors"
+)
+
+const (
+	defaultMaxStaleness     = time.Second
+	defaultReplicaCooldown  = 30 * time.Second
+	defaultLagCheckInterval = time.Second
+)
+
+// primaryKey marks contexts whose reads must see the primary.
+type primaryKey struct{}
+
+// ContextWithPrimary routes every read made with the returned context to the
+// primary, for requests that must read their own writes.
+func ContextWithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+// usePrimary reports whether ctx was marked by ContextWithPrimary.
+func usePrimary(ctx context.Context) bool {
+	forced, _ := ctx.Value(primaryKey{}).(bool)
+
+	return forced
+}
+
+// Replica is a read-only copy of the primary database.
+type Replica struct {
+	Name string
+	Repo repositories.UserRepository
+	// Lag reports how far the replica is behind the primary, e.g. from
+	// pg_last_xact_replay_timestamp() or SHOW REPLICA STATUS.
+	// Without it the replica is assumed to be current.
+	Lag func(ctx context.Context) (time.Duration, error)
+}
+
+// replicaState tracks the health and lag of one replica.
+type replicaState struct {
+	Replica
+
+	mu        sync.Mutex
+	lag       time.Duration
+	checkedAt time.Time
+	downUntil time.Time
+}
+
+// ReplicaUserRepository routes read-only methods to replicas and everything
+// else to the primary. Replicas lagging more than the staleness tolerance are
+// skipped, and for that long after a write through this repository all reads
+// go to the primary. Reads that fail on a replica are retried on the primary
+// and the replica is avoided until its cooldown passes.
+//
+// Credential checks, locking reads and claims always use the primary.
+type ReplicaUserRepository struct {
+	primary  repositories.UserRepository
+	replicas []*replicaState
+
+	maxStaleness     time.Duration
+	cooldown         time.Duration
+	lagCheckInterval time.Duration
+
+	next      atomic.Uint64
+	lastWrite atomic.Int64
+	now       func() time.Time
+}
+
+// ReplicaOption configures a ReplicaUserRepository.
+type ReplicaOption func(*ReplicaUserRepository)
+
+// WithMaxStaleness sets how stale replica reads may be. Defaults to one second.
+func WithMaxStaleness(staleness time.Duration) ReplicaOption {
+	return func(r *ReplicaUserRepository) {
+		if staleness >= 0 {
+			r.maxStaleness = staleness
+		}
+	}
+}
+
+// WithReplicaCooldown sets how long a failed replica is avoided. Defaults to 30 seconds.
+func WithReplicaCooldown(cooldown time.Duration) ReplicaOption {
+	return func(r *ReplicaUserRepository) {
+		if cooldown > 0 {
+			r.cooldown = cooldown
+		}
+	}
+}
+
+// WithLagCheckInterval sets how long a measured replica lag is reused. Defaults to one second.
+func WithLagCheckInterval(interval time.Duration) ReplicaOption {
+	return func(r *ReplicaUserRepository) {
+		if interval > 0 {
+			r.lagCheckInterval = interval
+		}
+	}
+}
+
+// NewReplicaUserRepository creates a repository that splits reads and writes
+// between primary and replicas.
+func NewReplicaUserRepository(
+	primary repositories.UserRepository,
+	replicas []Replica,
+	opts ...ReplicaOption,
+) *ReplicaUserRepository {
+	repo := &ReplicaUserRepository{
+		primary:          primary,
+		maxStaleness:     defaultMaxStaleness,
+		cooldown:         defaultReplicaCooldown,
+		lagCheckInterval: defaultLagCheckInterval,
+		now:              time.Now,
+	}
+
+	for _, replica := range replicas {
+		repo.replicas = append(repo.replicas, &replicaState{Replica: replica})
+	}
+
+	for _, opt := range opts {
+		opt(repo)
+	}
+
+	return repo
+}
+
+// Ensure ReplicaUserRepository implements UserRepository.
+var _ repositories.UserRepository = (*ReplicaUserRepository)(nil)
+
+// pick returns the next usable replica in round-robin order, or nil when
+// reads must go to the primary.
+func (r *ReplicaUserRepository) pick(ctx context.Context) *replicaState {
+	if len(r.replicas) == 0 || usePrimary(ctx) {
+		return nil
+	}
+
+	now := r.now()
+	if now.Sub(time.Unix(0, r.lastWrite.Load())) < r.maxStaleness {
+		return nil
+	}
+
+	start := r.next.Add(1)
+	for i := range uint64(len(r.replicas)) {
+		replica := r.replicas[(start+i)%uint64(len(r.replicas))]
+		if replica.usable(ctx, now, r) {
+			return replica
+		}
+	}
+
+	return nil
+}
+
+// usable reports whether the replica is up and within the staleness tolerance.
+func (s *replicaState) usable(ctx context.Context, now time.Time, r *ReplicaUserRepository) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now.Before(s.downUntil) {
+		return false
+	}
+
+	if s.Lag == nil {
+		return true
+	}
+
+	if now.Sub(s.checkedAt) >= r.lagCheckInterval {
+		lag, err := s.Lag(ctx)
+		if err != nil {
+			slog.Warn("replica lag check failed", "replica", s.Name, "error", err)
+			s.downUntil = now.Add(r.cooldown)
+
+			return false
+		}
+
+		s.lag = lag
+		s.checkedAt = now
+	}
+
+	return s.lag <= r.maxStaleness
+}
+
+// markDown keeps reads away from the replica until the cooldown passes.
+func (s *replicaState) markDown(until time.Time) {
+	s.mu.Lock()
+	s.downUntil = until
+	s.mu.Unlock()
+}
+
+// read runs fn on a replica, falling back to the primary.
+// Not-found results are confirmed on the primary since the replica may not
+// have the row yet; other domain errors are returned as is.
+func read[T any](
+	ctx context.Context,
+	r *ReplicaUserRepository,
+	fn func(repositories.UserRepository) (T, error),
+) (T, error) {
+	replica := r.pick(ctx)
+	if replica == nil {
+		return fn(r.primary)
+	}
+
+	result, err := fn(replica.Repo)
+	if err == nil || ctx.Err() != nil || isFinalReplicaError(err) {
+		return result, err
+	}
+
+	if !apperrors.IsNotFoundError(err) {
+		slog.Warn("replica read failed, using primary", "replica", replica.Name, "error", err)
+		replica.markDown(r.now().Add(r.cooldown))
+	}
+
+	return fn(r.primary)
+}
+
+// isFinalReplicaError reports whether err would be the same on the primary.
+func isFinalReplicaError(err error) bool {
+	return apperrors.IsValidationError(err) ||
+		apperrors.IsConflictError(err) ||
+		apperrors.IsUnauthorizedError(err) ||
+		apperrors.IsForbiddenError(err) ||
+		errors.Is(err, context.Canceled) ||
+		errors.Is(err, context.DeadlineExceeded)
+}
+
+// write runs fn on the primary and starts the read-your-writes window.
+func (r *ReplicaUserRepository) write(fn func() error) error {
+	err := fn()
+	r.lastWrite.Store(r.now().UnixNano())
+
+	return err
+}
+
+// Create inserts the user on the primary.
+func (r *ReplicaUserRepository) Create(ctx context.Context, user *entities.User) error {
+	return r.write(func() error { return r.primary.Create(ctx, user) })
+}
+
+// GetByID reads the user from a replica.
+func (r *ReplicaUserRepository) GetByID(ctx context.Context, id entities.UserID) (*entities.User, error) {
+	return read(ctx, r, func(repo repositories.UserRepository) (*entities.User, error) {
+		return repo.GetByID(ctx, id)
+	})
+}
+
+// GetByUUID reads the user from a replica.
+func (r *ReplicaUserRepository) GetByUUID(ctx context.Context, uuid entities.UuID) (*entities.User, error) {
+	return read(ctx, r, func(repo repositories.UserRepository) (*entities.User, error) {
+		return repo.GetByUUID(ctx, uuid)
+	})
+}
+
+// GetByIDs reads the users from a replica.
+func (r *ReplicaUserRepository) GetByIDs(ctx context.Context, ids []entities.UserID) ([]*entities.User, error) {
+	return read(ctx, r, func(repo repositories.UserRepository) ([]*entities.User, error) {
+		return repo.GetByIDs(ctx, ids)
+	})
+}
+
+// GetByEmail reads the user from a replica.
+func (r *ReplicaUserRepository) GetByEmail(ctx context.Context, email entities.Email) (*entities.User, error) {
+	return read(ctx, r, func(repo repositories.UserRepository) (*entities.User, error) {
+		return repo.GetByEmail(ctx, email)
+	})
+}
+
+// GetByUsername reads the user from a replica.
+func (r *ReplicaUserRepository) GetByUsername(
+	ctx context.Context,
+	username entities.Username,
+) (*entities.User, error) {
+	return read(ctx, r, func(repo repositories.UserRepository) (*entities.User, error) {
+		return repo.GetByUsername(ctx, username)
+	})
+}
+
+// Update updates the user on the primary.
+func (r *ReplicaUserRepository) Update(ctx context.Context, user *entities.User) error {
+	return r.write(func() error { return r.primary.Update(ctx, user) })
+}
+
+// Delete deletes the user on the primary.
+func (r *ReplicaUserRepository) Delete(ctx context.Context, id entities.UserID) error {
+	return r.write(func() error { return r.primary.Delete(ctx, id) })
+}
+
+// Restore restores the user on the primary.
+func (r *ReplicaUserRepository) Restore(ctx context.Context, id entities.UserID) error {
+	return r.write(func() error { return r.primary.Restore(ctx, id) })
+}
+
+// PurgeDeletedOlderThan purges deleted users on the primary.
+func (r *ReplicaUserRepository) PurgeDeletedOlderThan(ctx context.Context, cutoff time.Time) (int64, error) {
+	var purged int64
+
+	err := r.write(func() error {
+		var err error
+
+		purged, err = r.primary.PurgeDeletedOlderThan(ctx, cutoff)
+
+		return err
+	})
+
+	return purged, err
+}
+
+// ListPage reads the page from a replica.
+func (r *ReplicaUserRepository) ListPage(
+	ctx context.Context,
+	filter entities.UserFilter,
+	cursor string,
+	limit int,
+) (*entities.UserPage, error) {
+	return read(ctx, r, func(repo repositories.UserRepository) (*entities.UserPage, error) {
+		return repo.ListPage(ctx, filter, cursor, limit)
+	})
+}
+
+// CreateBatch inserts the users on the primary.
+func (r *ReplicaUserRepository) CreateBatch(ctx context.Context, users []*entities.User) error {
+	return r.write(func() error { return r.primary.CreateBatch(ctx, users) })
+}
+
+// UpdateStatusBatch changes the statuses on the primary.
+func (r *ReplicaUserRepository) UpdateStatusBatch(
+	ctx context.Context,
+	ids []entities.UserID,
+	status entities.UserStatus,
+) (int64, error) {
+	var updated int64
+
+	err := r.write(func() error {
+		var err error
+
+		updated, err = r.primary.UpdateStatusBatch(ctx, ids, status)
+
+		return err
+	})
+
+	return updated, err
+}
+
+// DeleteBatch deletes the users on the primary.
+func (r *ReplicaUserRepository) DeleteBatch(ctx context.Context, ids []entities.UserID) (int64, error) {
+	var deleted int64
+
+	err := r.write(func() error {
+		var err error
+
+		deleted, err = r.primary.DeleteBatch(ctx, ids)
+
+		return err
+	})
+
+	return deleted, err
+}
+
+// List reads the page from a replica.
+func (r *ReplicaUserRepository) List(
+	ctx context.Context,
+	filter entities.UserFilter,
+	limit, offset int,
+) ([]*entities.User, error) {
+	return read(ctx, r, func(repo repositories.UserRepository) ([]*entities.User, error) {
+		return repo.List(ctx, filter, limit, offset)
+	})
+}
+
+// SearchWithFacets searches a replica.
+func (r *ReplicaUserRepository) SearchWithFacets(
+	ctx context.Context,
+	query string,
+	status entities.UserStatus,
+	limit int,
+) (*entities.UserSearchResult, error) {
+	return read(ctx, r, func(repo repositories.UserRepository) (*entities.UserSearchResult, error) {
+		return repo.SearchWithFacets(ctx, query, status, limit)
+	})
+}
+
+// CountByStatus counts on a replica.
+func (r *ReplicaUserRepository) CountByStatus(ctx context.Context) (map[entities.UserStatus]int64, error) {
+	return read(ctx, r, func(repo repositories.UserRepository) (map[entities.UserStatus]int64, error) {
+		return repo.CountByStatus(ctx)
+	})
+}
+
+// GetStats aggregates on a replica.
+func (r *ReplicaUserRepository) GetStats(ctx context.Context) (*entities.UserStats, error) {
+	return read(ctx, r, func(repo repositories.UserRepository) (*entities.UserStats, error) {
+		return repo.GetStats(ctx)
+	})
+}
+
+// GetCredentials reads the password hash from the primary so that a
+// password change takes effect immediately.
+func (r *ReplicaUserRepository) GetCredentials(ctx context.Context, email entities.Email) (*entities.User, error) {
+	return r.primary.GetCredentials(ctx, email)
+}
+
+// UpdatePassword updates the password on the primary.
+func (r *ReplicaUserRepository) UpdatePassword(
+	ctx context.Context,
+	id entities.UserID,
+	password entities.PasswordHash,
+) error {
+	return r.write(func() error { return r.primary.UpdatePassword(ctx, id, password) })
+}
+
+// MarkVerified marks the user verified on the primary.
+func (r *ReplicaUserRepository) MarkVerified(ctx context.Context, id entities.UserID) error {
+	return r.write(func() error { return r.primary.MarkVerified(ctx, id) })
+}
+
+// ChangeStatus changes the status on the primary.
+func (r *ReplicaUserRepository) ChangeStatus(
+	ctx context.Context,
+	id entities.UserID,
+	status entities.UserStatus,
+) error {
+	return r.write(func() error { return r.primary.ChangeStatus(ctx, id, status) })
+}
+
+// Activate activates the user on the primary.
+func (r *ReplicaUserRepository) Activate(ctx context.Context, id entities.UserID) error {
+	return r.write(func() error { return r.primary.Activate(ctx, id) })
+}
+
+// Deactivate deactivates the user on the primary.
+func (r *ReplicaUserRepository) Deactivate(ctx context.Context, id entities.UserID) error {
+	return r.write(func() error { return r.primary.Deactivate(ctx, id) })
+}
+
+// Suspend suspends the user on the primary.
+func (r *ReplicaUserRepository) Suspend(ctx context.Context, id entities.UserID) error {
+	return r.write(func() error { return r.primary.Suspend(ctx, id) })
+}
+
+// ChangeRole changes the role on the primary.
+func (r *ReplicaUserRepository) ChangeRole(ctx context.Context, id entities.UserID, role entities.UserRole) error {
+	return r.write(func() error { return r.primary.ChangeRole(ctx, id, role) })
+}
+
+// GetByIDForUpdate locks the user on the primary.
+func (r *ReplicaUserRepository) GetByIDForUpdate(
+	ctx context.Context,
+	id entities.UserID,
+	opts ...repositories.LockOption,
+) (*entities.User, error) {
+	return r.primary.GetByIDForUpdate(ctx, id, opts...)
+}
+
+// ClaimNext claims users on the primary.
+func (r *ReplicaUserRepository) ClaimNext(
+	ctx context.Context,
+	status entities.UserStatus,
+	limit int,
+) ([]*entities.User, error) {
+	return r.primary.ClaimNext(ctx, status, limit)
+}
@@ -0,0 +1,191 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	apperrors "github.com/LarsArtmann/template-sqlc/pkg/errors"
+)
+
+const (
+	defaultFailureThreshold = 5
+	defaultOpenTimeout      = 30 * time.Second
+)
+
+// ErrCircuitOpen is returned without calling the dependency while its circuit
+// breaker is open. It is a service unavailable error.
+var ErrCircuitOpen = apperrors.NewUnavailableError("circuit breaker open")
+
+// State is the state of a circuit breaker.
+type State int
+
+const (
+	// StateClosed lets every call through.
+	StateClosed State = iota
+	// StateHalfOpen lets one trial call through, which decides whether the
+	// breaker closes or opens again.
+	StateHalfOpen
+	// StateOpen rejects every call with ErrCircuitOpen.
+	StateOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateHalfOpen:
+		return "half_open"
+	case StateOpen:
+		return "open"
+	default:
+		return "unknown"
+	}
+}
+
+// BreakerPolicy controls when a circuit breaker opens and for how long.
+type BreakerPolicy struct {
+	// FailureThreshold is the number of consecutive failures that opens the
+	// breaker. Zero disables it.
+	FailureThreshold int
+	// OpenTimeout is how long the breaker stays open before a trial call.
+	OpenTimeout time.Duration
+}
+
+// DefaultBreakerPolicy returns the breaker policy used when none is
+// configured: open after five consecutive failures, for 30 seconds.
+func DefaultBreakerPolicy() BreakerPolicy {
+	return BreakerPolicy{
+		FailureThreshold: defaultFailureThreshold,
+		OpenTimeout:      defaultOpenTimeout,
+	}
+}
+
+// CircuitBreaker stops calling a dependency after consecutive failures and
+// lets a trial call through once its open timeout has passed. Only failures
+// of the dependency and timeouts count: errors with a client error status,
+// such as not found or validation errors, and cancellations of the caller do
+// not. It is safe for concurrent use.
+type CircuitBreaker struct {
+	name     string
+	policy   BreakerPolicy
+	observer Observer
+	now      func() time.Time
+
+	mu       sync.Mutex
+	state    State
+	failures int
+	openedAt time.Time
+	trial    bool
+}
+
+func newCircuitBreaker(name string, policy BreakerPolicy, observer Observer, now func() time.Time) *CircuitBreaker {
+	observer.ObserveCircuitState(name, StateClosed.String())
+
+	return &CircuitBreaker{name: name, policy: policy, observer: observer, now: now}
+}
+
+// State returns the current state of the breaker. A nil breaker is closed.
+func (b *CircuitBreaker) State() State {
+	if b == nil {
+		return StateClosed
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == StateOpen && !b.now().Before(b.openedAt.Add(b.policy.OpenTimeout)) {
+		return StateHalfOpen
+	}
+
+	return b.state
+}
+
+// allow reports whether a call may proceed, returning the function that
+// records its outcome, or ErrCircuitOpen.
+func (b *CircuitBreaker) allow() (func(err error), error) {
+	if b == nil {
+		return func(error) {}, nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == StateOpen && !b.now().Before(b.openedAt.Add(b.policy.OpenTimeout)) {
+		b.transition(StateHalfOpen)
+	}
+
+	switch {
+	case b.state == StateOpen, b.state == StateHalfOpen && b.trial:
+		return nil, ErrCircuitOpen
+	case b.state == StateHalfOpen:
+		b.trial = true
+
+		return b.recordTrial, nil
+	default:
+		return b.record, nil
+	}
+}
+
+// record records the outcome of a call while the breaker is closed.
+func (b *CircuitBreaker) record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !isFailure(err) {
+		b.failures = 0
+
+		return
+	}
+
+	b.failures++
+	if b.state == StateClosed && b.failures >= b.policy.FailureThreshold {
+		b.open()
+	}
+}
+
+// recordTrial records the outcome of the trial call of the half-open breaker.
+func (b *CircuitBreaker) recordTrial(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.trial = false
+
+	if isFailure(err) {
+		b.open()
+
+		return
+	}
+
+	b.failures = 0
+	b.transition(StateClosed)
+}
+
+// open opens the breaker. The caller holds b.mu.
+func (b *CircuitBreaker) open() {
+	b.openedAt = b.now()
+	b.transition(StateOpen)
+}
+
+// transition changes the state and reports it. The caller holds b.mu.
+func (b *CircuitBreaker) transition(state State) {
+	if b.state == state {
+		return
+	}
+
+	b.state = state
+	b.observer.ObserveCircuitState(b.name, state.String())
+}
+
+// isFailure reports whether err is a failure of the dependency rather than
+// of the request. Timeouts count: a dependency that stops answering fails
+// every call with one.
+func isFailure(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	return apperrors.HTTPStatus(err) >= http.StatusInternalServerError || apperrors.IsTimeoutError(err)
+}
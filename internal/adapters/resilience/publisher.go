@@ -0,0 +1,41 @@
+package resilience
+
+import (
+	"context"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+)
+
+// Publisher guards a wrapped event publisher, such as a broker publisher of
+// package messaging, with a circuit breaker, so that an unreachable broker
+// fails publishes at once instead of after its retries and timeout.
+type Publisher struct {
+	next  events.EventPublisher
+	guard *guard
+}
+
+// NewPublisher wraps publisher with a circuit breaker labeled name, e.g.
+// "nats". Publishes are only retried with WithRetry.
+func NewPublisher(name string, publisher events.EventPublisher, opts ...Option) *Publisher {
+	return &Publisher{
+		next:  publisher,
+		guard: newGuard(name, RetryPolicy{MaxAttempts: 1}, opts),
+	}
+}
+
+// Ensure Publisher implements events.EventPublisher.
+var _ events.EventPublisher = (*Publisher)(nil)
+
+// Publish publishes event.
+func (p *Publisher) Publish(event *events.UserEvent) error {
+	return exec(context.Background(), p.guard, "Publish", func(context.Context) error {
+		return p.next.Publish(event)
+	})
+}
+
+// PublishBatch publishes evts.
+func (p *Publisher) PublishBatch(evts []*events.UserEvent) error {
+	return exec(context.Background(), p.guard, "PublishBatch", func(context.Context) error {
+		return p.next.PublishBatch(evts)
+	})
+}
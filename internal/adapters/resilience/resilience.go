@@ -0,0 +1,341 @@
+// Package resilience provides repository and event publisher decorators that
+// retry transient failures and stop calling a failing dependency with a
+// circuit breaker, so that the adapters stay free of retry loops.
+//
+// Failures are transient when running the call again may succeed, such as
+// deadlocks, serialization failures and connection resets; TransientFor
+// classifies them per engine. Writes are only retried after failures that
+// guarantee they did not take effect, which WriteTransientFor classifies.
+// Every other error is returned at once.
+package resilience
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"syscall"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/converters"
+	mysqldb "github.com/LarsArtmann/template-sqlc/internal/db/mysql"
+	postgresdb "github.com/LarsArtmann/template-sqlc/internal/db/postgres"
+	sqlitedb "github.com/LarsArtmann/template-sqlc/internal/db/sqlite"
+	"github.com/LarsArtmann/template-sqlc/pkg/db"
+)
+
+const (
+	defaultMaxAttempts    = 3
+	defaultInitialBackoff = 20 * time.Millisecond
+	defaultMaxBackoff     = time.Second
+)
+
+// Observer receives the retries and circuit breaker state changes of a
+// decorated dependency, labeled with its name, e.g. "UserRepository".
+// *monitoring.Metrics satisfies it.
+type Observer interface {
+	ObserveRetry(name string)
+	ObserveCircuitState(name, state string)
+}
+
+// RetryPolicy controls how transient failures are retried with exponential
+// backoff.
+type RetryPolicy struct {
+	// MaxAttempts is the number of calls including the first; below two
+	// nothing is retried.
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// Transient reports whether a failed read may succeed when retried.
+	Transient func(err error) bool
+	// TransientWrite reports whether a failed write may be retried, which
+	// requires that it certainly did not take effect. Writes are not
+	// retried if it is nil.
+	TransientWrite func(err error) bool
+}
+
+// DefaultRetryPolicy returns the retry policy for engine: three attempts
+// with a backoff from 20ms up to a second, for the failures TransientFor
+// and WriteTransientFor classify as transient.
+func DefaultRetryPolicy(engine string) RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    defaultMaxAttempts,
+		InitialBackoff: defaultInitialBackoff,
+		MaxBackoff:     defaultMaxBackoff,
+		Transient:      TransientFor(engine),
+		TransientWrite: WriteTransientFor(engine),
+	}
+}
+
+// TransientFor returns the transient failures of engine, its lock conflicts
+// and lost connections, together with the network failures of every engine.
+// Unknown engines only retry network failures.
+func TransientFor(engine string) func(err error) bool {
+	var engineTransient func(err error) bool
+
+	switch engine {
+	case converters.DbTypeSQLite, string(db.DriverLibSQL):
+		engineTransient = sqlitedb.IsSQLiteTransientError
+	case converters.DbTypePostgres, converters.DbTypeCockroachDB:
+		engineTransient = postgresdb.IsPostgresTransientError
+	case converters.DbTypeMySQL:
+		engineTransient = mysqldb.IsMySQLTransientError
+	default:
+		return IsNetworkError
+	}
+
+	return func(err error) bool {
+		return IsNetworkError(err) || engineTransient(err)
+	}
+}
+
+// WriteTransientFor returns the failures of engine after which a write
+// certainly did not take effect, its lock conflicts, together with the
+// connection failures of every engine that happen before anything is sent.
+// Unknown engines only retry those.
+func WriteTransientFor(engine string) func(err error) bool {
+	var engineTransient func(err error) bool
+
+	switch engine {
+	case converters.DbTypeSQLite, string(db.DriverLibSQL):
+		engineTransient = sqlitedb.IsSQLiteTransientError
+	case converters.DbTypePostgres, converters.DbTypeCockroachDB:
+		engineTransient = postgresdb.IsPostgresWriteRetryableError
+	case converters.DbTypeMySQL:
+		engineTransient = mysqldb.IsMySQLWriteRetryableError
+	default:
+		return IsConnectError
+	}
+
+	return func(err error) bool {
+		return IsConnectError(err) || engineTransient(err)
+	}
+}
+
+// IsNetworkError reports whether err is a lost or refused connection or a
+// network timeout. Cancellations and deadlines of the caller are not. Only
+// reads may be retried after them: a connection reset or a timeout may
+// follow a write the database applied.
+func IsNetworkError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	if errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+
+	var netErr net.Error
+
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// IsConnectError reports whether err is a failure to use a connection before
+// anything was sent on it: a connection the pool found bad or a refused
+// connection. A write failing with it did not take effect.
+func IsConnectError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	return errors.Is(err, driver.ErrBadConn) || errors.Is(err, syscall.ECONNREFUSED)
+}
+
+// engineReporter is implemented by repositories that know their database
+// engine, such as the adapters built on adapters.DBUserRepository.
+type engineReporter interface {
+	Engine() string
+}
+
+// Option configures a decorator.
+type Option func(*guard)
+
+// WithRetry sets the retry policy. Repositories default to the
+// DefaultRetryPolicy of their engine; publishers retry by themselves, see
+// messaging.WithRetry, so Publisher only retries with this option.
+func WithRetry(policy RetryPolicy) Option {
+	return func(g *guard) {
+		g.retry = policy
+	}
+}
+
+// WithEngine retries the transient failures of engine. Defaults to the
+// Engine() of the wrapped repository, if it has one.
+func WithEngine(engine string) Option {
+	return func(g *guard) {
+		g.retry.Transient = TransientFor(engine)
+		g.retry.TransientWrite = WriteTransientFor(engine)
+	}
+}
+
+// WithBreaker sets the circuit breaker policy. A zero FailureThreshold
+// disables the breaker.
+func WithBreaker(policy BreakerPolicy) Option {
+	return func(g *guard) {
+		g.breakerPolicy = policy
+	}
+}
+
+// WithObserver reports retries and circuit breaker state changes to observer.
+func WithObserver(observer Observer) Option {
+	return func(g *guard) {
+		g.observer = observer
+	}
+}
+
+// WithClock sets the clock the circuit breaker times its open state with.
+func WithClock(now func() time.Time) Option {
+	return func(g *guard) {
+		g.now = now
+	}
+}
+
+// guard retries and breaks the calls of one dependency.
+type guard struct {
+	name          string
+	retry         RetryPolicy
+	breakerPolicy BreakerPolicy
+	breaker       *CircuitBreaker
+	observer      Observer
+	now           func() time.Time
+}
+
+func newGuard(name string, retry RetryPolicy, opts []Option) *guard {
+	g := &guard{
+		name:          name,
+		retry:         retry,
+		breakerPolicy: DefaultBreakerPolicy(),
+		observer:      nopObserver{},
+		now:           time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(g)
+	}
+
+	if g.breakerPolicy.FailureThreshold > 0 {
+		g.breaker = newCircuitBreaker(name, g.breakerPolicy, g.observer, g.now)
+	}
+
+	return g
+}
+
+// newRepositoryGuard creates the guard of a repository, which retries the
+// transient failures of the engine of next by default.
+func newRepositoryGuard(name string, next any, opts []Option) *guard {
+	engine := ""
+	if reporter, ok := next.(engineReporter); ok {
+		engine = reporter.Engine()
+	}
+
+	return newGuard(name, DefaultRetryPolicy(engine), opts)
+}
+
+// call runs the read fn for method unless the circuit is open, retrying
+// transient failures, and records the outcome with the circuit breaker. The
+// error of the last attempt is returned unchanged.
+func call[T any](
+	ctx context.Context,
+	g *guard,
+	method string,
+	fn func(ctx context.Context) (T, error),
+) (T, error) {
+	return guarded(ctx, g, method, g.retry.Transient, fn)
+}
+
+// write is call for methods that change data, which are only retried after
+// the failures of TransientWrite.
+func write[T any](
+	ctx context.Context,
+	g *guard,
+	method string,
+	fn func(ctx context.Context) (T, error),
+) (T, error) {
+	return guarded(ctx, g, method, g.retry.TransientWrite, fn)
+}
+
+// exec is call for methods that only return an error.
+func exec(ctx context.Context, g *guard, method string, fn func(ctx context.Context) error) error {
+	_, err := call(ctx, g, method, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, fn(ctx)
+	})
+
+	return err
+}
+
+// execWrite is write for methods that only return an error.
+func execWrite(ctx context.Context, g *guard, method string, fn func(ctx context.Context) error) error {
+	_, err := write(ctx, g, method, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, fn(ctx)
+	})
+
+	return err
+}
+
+// guarded runs fn unless the circuit is open, retrying the failures
+// transient accepts, and records the outcome with the circuit breaker.
+func guarded[T any](
+	ctx context.Context,
+	g *guard,
+	method string,
+	transient func(err error) bool,
+	fn func(ctx context.Context) (T, error),
+) (T, error) {
+	var zero T
+
+	done, err := g.breaker.allow()
+	if err != nil {
+		return zero, fmt.Errorf("%s.%s: %w", g.name, method, err)
+	}
+
+	result, err := retry(ctx, g, transient, fn)
+	done(err)
+
+	return result, err
+}
+
+// retry runs fn until it succeeds, fails with an error transient rejects,
+// the attempts are exhausted or ctx is done.
+func retry[T any](
+	ctx context.Context,
+	g *guard,
+	transient func(err error) bool,
+	fn func(ctx context.Context) (T, error),
+) (T, error) {
+	backoff := g.retry.InitialBackoff
+
+	for attempt := 1; ; attempt++ {
+		result, err := fn(ctx)
+		if err == nil || attempt >= g.retry.MaxAttempts || ctx.Err() != nil ||
+			transient == nil || !transient(err) {
+			return result, err
+		}
+
+		g.observer.ObserveRetry(g.name)
+
+		timer := time.NewTimer(backoff)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			return result, err
+		case <-timer.C:
+		}
+
+		backoff = min(2*backoff, g.retry.MaxBackoff)
+	}
+}
+
+// nopObserver discards observations.
+type nopObserver struct{}
+
+func (nopObserver) ObserveRetry(string) {}
+
+func (nopObserver) ObserveCircuitState(string, string) {}
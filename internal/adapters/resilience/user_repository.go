@@ -0,0 +1,256 @@
+package resilience
+
+import (
+	"context"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// UserRepository retries the transient failures of a wrapped user repository
+// and guards it with a circuit breaker.
+type UserRepository struct {
+	next  repositories.UserRepository
+	guard *guard
+}
+
+// NewUserRepository wraps repo with retries and a circuit breaker.
+func NewUserRepository(repo repositories.UserRepository, opts ...Option) *UserRepository {
+	return &UserRepository{
+		next:  repo,
+		guard: newRepositoryGuard("UserRepository", repo, opts),
+	}
+}
+
+// Ensure UserRepository implements the domain UserRepository.
+var _ repositories.UserRepository = (*UserRepository)(nil)
+
+// Create creates a user.
+func (r *UserRepository) Create(ctx context.Context, user *entities.User) error {
+	return execWrite(ctx, r.guard, "Create", func(ctx context.Context) error {
+		return r.next.Create(ctx, user)
+	})
+}
+
+// GetByID retrieves a user by ID.
+func (r *UserRepository) GetByID(ctx context.Context, id entities.UserID) (*entities.User, error) {
+	return call(ctx, r.guard, "GetByID", func(ctx context.Context) (*entities.User, error) {
+		return r.next.GetByID(ctx, id)
+	})
+}
+
+// GetByUUID retrieves a user by UUID.
+func (r *UserRepository) GetByUUID(ctx context.Context, uuid entities.UuID) (*entities.User, error) {
+	return call(ctx, r.guard, "GetByUUID", func(ctx context.Context) (*entities.User, error) {
+		return r.next.GetByUUID(ctx, uuid)
+	})
+}
+
+// GetByIDs retrieves the users with the given IDs.
+func (r *UserRepository) GetByIDs(ctx context.Context, ids []entities.UserID) ([]*entities.User, error) {
+	return call(ctx, r.guard, "GetByIDs", func(ctx context.Context) ([]*entities.User, error) {
+		return r.next.GetByIDs(ctx, ids)
+	})
+}
+
+// GetByEmail retrieves a user by email.
+func (r *UserRepository) GetByEmail(ctx context.Context, email entities.Email) (*entities.User, error) {
+	return call(ctx, r.guard, "GetByEmail", func(ctx context.Context) (*entities.User, error) {
+		return r.next.GetByEmail(ctx, email)
+	})
+}
+
+// GetByUsername retrieves a user by username.
+func (r *UserRepository) GetByUsername(
+	ctx context.Context,
+	username entities.Username,
+) (*entities.User, error) {
+	return call(ctx, r.guard, "GetByUsername", func(ctx context.Context) (*entities.User, error) {
+		return r.next.GetByUsername(ctx, username)
+	})
+}
+
+// Update updates a user.
+func (r *UserRepository) Update(ctx context.Context, user *entities.User) error {
+	return execWrite(ctx, r.guard, "Update", func(ctx context.Context) error {
+		return r.next.Update(ctx, user)
+	})
+}
+
+// Delete soft deletes a user.
+func (r *UserRepository) Delete(ctx context.Context, id entities.UserID) error {
+	return execWrite(ctx, r.guard, "Delete", func(ctx context.Context) error {
+		return r.next.Delete(ctx, id)
+	})
+}
+
+// Restore restores a soft-deleted user.
+func (r *UserRepository) Restore(ctx context.Context, id entities.UserID) error {
+	return execWrite(ctx, r.guard, "Restore", func(ctx context.Context) error {
+		return r.next.Restore(ctx, id)
+	})
+}
+
+// PurgeDeletedOlderThan permanently removes users soft deleted before cutoff.
+func (r *UserRepository) PurgeDeletedOlderThan(ctx context.Context, cutoff time.Time) (int64, error) {
+	return write(ctx, r.guard, "PurgeDeletedOlderThan", func(ctx context.Context) (int64, error) {
+		return r.next.PurgeDeletedOlderThan(ctx, cutoff)
+	})
+}
+
+// CreateBatch creates the users.
+func (r *UserRepository) CreateBatch(ctx context.Context, users []*entities.User) error {
+	return execWrite(ctx, r.guard, "CreateBatch", func(ctx context.Context) error {
+		return r.next.CreateBatch(ctx, users)
+	})
+}
+
+// UpdateStatusBatch changes the status of the users.
+func (r *UserRepository) UpdateStatusBatch(
+	ctx context.Context,
+	ids []entities.UserID,
+	status entities.UserStatus,
+) (int64, error) {
+	return write(ctx, r.guard, "UpdateStatusBatch", func(ctx context.Context) (int64, error) {
+		return r.next.UpdateStatusBatch(ctx, ids, status)
+	})
+}
+
+// DeleteBatch soft deletes the users.
+func (r *UserRepository) DeleteBatch(ctx context.Context, ids []entities.UserID) (int64, error) {
+	return write(ctx, r.guard, "DeleteBatch", func(ctx context.Context) (int64, error) {
+		return r.next.DeleteBatch(ctx, ids)
+	})
+}
+
+// List returns a page of users matching filter.
+func (r *UserRepository) List(
+	ctx context.Context,
+	filter entities.UserFilter,
+	limit, offset int,
+) ([]*entities.User, error) {
+	return call(ctx, r.guard, "List", func(ctx context.Context) ([]*entities.User, error) {
+		return r.next.List(ctx, filter, limit, offset)
+	})
+}
+
+// ListPage returns a keyset-paginated page of users matching filter.
+func (r *UserRepository) ListPage(
+	ctx context.Context,
+	filter entities.UserFilter,
+	cursor string,
+	limit int,
+) (*entities.UserPage, error) {
+	return call(ctx, r.guard, "ListPage", func(ctx context.Context) (*entities.UserPage, error) {
+		return r.next.ListPage(ctx, filter, cursor, limit)
+	})
+}
+
+// SearchWithFacets searches users and counts the matches per facet.
+func (r *UserRepository) SearchWithFacets(
+	ctx context.Context,
+	query string,
+	status entities.UserStatus,
+	limit int,
+) (*entities.UserSearchResult, error) {
+	return call(ctx, r.guard, "SearchWithFacets", func(ctx context.Context) (*entities.UserSearchResult, error) {
+		return r.next.SearchWithFacets(ctx, query, status, limit)
+	})
+}
+
+// CountByStatus counts users per status.
+func (r *UserRepository) CountByStatus(ctx context.Context) (map[entities.UserStatus]int64, error) {
+	return call(ctx, r.guard, "CountByStatus", func(ctx context.Context) (map[entities.UserStatus]int64, error) {
+		return r.next.CountByStatus(ctx)
+	})
+}
+
+// GetStats returns aggregate user statistics.
+func (r *UserRepository) GetStats(ctx context.Context) (*entities.UserStats, error) {
+	return call(ctx, r.guard, "GetStats", func(ctx context.Context) (*entities.UserStats, error) {
+		return r.next.GetStats(ctx)
+	})
+}
+
+// GetCredentials returns a user with its password hash.
+func (r *UserRepository) GetCredentials(ctx context.Context, email entities.Email) (*entities.User, error) {
+	return call(ctx, r.guard, "GetCredentials", func(ctx context.Context) (*entities.User, error) {
+		return r.next.GetCredentials(ctx, email)
+	})
+}
+
+// UpdatePassword updates the password of a user.
+func (r *UserRepository) UpdatePassword(
+	ctx context.Context,
+	id entities.UserID,
+	password entities.PasswordHash,
+) error {
+	return execWrite(ctx, r.guard, "UpdatePassword", func(ctx context.Context) error {
+		return r.next.UpdatePassword(ctx, id, password)
+	})
+}
+
+// MarkVerified marks a user verified.
+func (r *UserRepository) MarkVerified(ctx context.Context, id entities.UserID) error {
+	return execWrite(ctx, r.guard, "MarkVerified", func(ctx context.Context) error {
+		return r.next.MarkVerified(ctx, id)
+	})
+}
+
+// ChangeStatus changes the status of a user.
+func (r *UserRepository) ChangeStatus(ctx context.Context, id entities.UserID, status entities.UserStatus) error {
+	return execWrite(ctx, r.guard, "ChangeStatus", func(ctx context.Context) error {
+		return r.next.ChangeStatus(ctx, id, status)
+	})
+}
+
+// Activate activates a user.
+func (r *UserRepository) Activate(ctx context.Context, id entities.UserID) error {
+	return execWrite(ctx, r.guard, "Activate", func(ctx context.Context) error {
+		return r.next.Activate(ctx, id)
+	})
+}
+
+// Deactivate deactivates a user.
+func (r *UserRepository) Deactivate(ctx context.Context, id entities.UserID) error {
+	return execWrite(ctx, r.guard, "Deactivate", func(ctx context.Context) error {
+		return r.next.Deactivate(ctx, id)
+	})
+}
+
+// Suspend suspends a user.
+func (r *UserRepository) Suspend(ctx context.Context, id entities.UserID) error {
+	return execWrite(ctx, r.guard, "Suspend", func(ctx context.Context) error {
+		return r.next.Suspend(ctx, id)
+	})
+}
+
+// ChangeRole changes the role of a user.
+func (r *UserRepository) ChangeRole(ctx context.Context, id entities.UserID, role entities.UserRole) error {
+	return execWrite(ctx, r.guard, "ChangeRole", func(ctx context.Context) error {
+		return r.next.ChangeRole(ctx, id, role)
+	})
+}
+
+// GetByIDForUpdate retrieves and locks a user.
+func (r *UserRepository) GetByIDForUpdate(
+	ctx context.Context,
+	id entities.UserID,
+	opts ...repositories.LockOption,
+) (*entities.User, error) {
+	return call(ctx, r.guard, "GetByIDForUpdate", func(ctx context.Context) (*entities.User, error) {
+		return r.next.GetByIDForUpdate(ctx, id, opts...)
+	})
+}
+
+// ClaimNext locks up to limit users in the given status.
+func (r *UserRepository) ClaimNext(
+	ctx context.Context,
+	status entities.UserStatus,
+	limit int,
+) ([]*entities.User, error) {
+	return call(ctx, r.guard, "ClaimNext", func(ctx context.Context) ([]*entities.User, error) {
+		return r.next.ClaimNext(ctx, status, limit)
+	})
+}
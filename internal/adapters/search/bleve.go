@@ -0,0 +1,353 @@
+package search
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/analysis/analyzer/custom"
+	"github.com/blevesearch/bleve/v2/analysis/token/lowercase"
+	"github.com/blevesearch/bleve/v2/analysis/tokenizer/regexp"
+	"github.com/blevesearch/bleve/v2/mapping"
+	"github.com/blevesearch/bleve/v2/search/query"
+)
+
+// wordsAnalyzer splits the text fields into lowercase words the way
+// entities.QueryTerms splits queries.
+const wordsAnalyzer = "words"
+
+// enumFacetSize exceeds the number of statuses and roles, so their facets
+// count every value.
+const enumFacetSize = 10
+
+// Fields of the indexed user documents, shared by both backends.
+const (
+	fieldID        = "id"
+	fieldEmail     = "email"
+	fieldUsername  = "username"
+	fieldFirstName = "first_name"
+	fieldLastName  = "last_name"
+	fieldStatus    = "status"
+	fieldRole      = "role"
+	fieldVerified  = "verified"
+	fieldTags      = "tags"
+	fieldCreatedAt = "created_at"
+)
+
+// textFields are matched by the query terms.
+func textFields() []string {
+	return []string{fieldEmail, fieldUsername, fieldFirstName, fieldLastName}
+}
+
+// BleveIndexer is a SearchIndexer embedded in the process with Bleve. Each
+// process has its own index, so it suits single-instance deployments.
+type BleveIndexer struct {
+	index bleve.Index
+}
+
+var _ repositories.SearchIndexer = (*BleveIndexer)(nil)
+
+// OpenBleveIndexer opens the index at path, creating it if it does not
+// exist. An empty path creates an index in memory.
+func OpenBleveIndexer(path string) (*BleveIndexer, error) {
+	indexMapping, err := bleveMapping()
+	if err != nil {
+		return nil, err
+	}
+
+	if path == "" {
+		index, err := bleve.NewMemOnly(indexMapping)
+		if err != nil {
+			return nil, fmt.Errorf("create bleve index in memory: %w", err)
+		}
+
+		return &BleveIndexer{index: index}, nil
+	}
+
+	index, err := bleve.Open(path)
+	if errors.Is(err, bleve.ErrorIndexPathDoesNotExist) {
+		index, err = bleve.New(path, indexMapping)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("open bleve index path=%v: %w", path, err)
+	}
+
+	return &BleveIndexer{index: index}, nil
+}
+
+// Close closes the index.
+func (b *BleveIndexer) Close() error {
+	return b.index.Close()
+}
+
+// Index adds or replaces the users in one batch.
+func (b *BleveIndexer) Index(_ context.Context, users ...*entities.User) error {
+	batch := b.index.NewBatch()
+
+	for _, user := range users {
+		err := batch.Index(documentID(user.ID()), documentOf(user))
+		if err != nil {
+			return fmt.Errorf("index user %s: %w", user.ID(), err)
+		}
+	}
+
+	return b.index.Batch(batch)
+}
+
+// Remove deletes the users in one batch.
+func (b *BleveIndexer) Remove(_ context.Context, ids ...entities.UserID) error {
+	batch := b.index.NewBatch()
+
+	for _, id := range ids {
+		batch.Delete(documentID(id))
+	}
+
+	return b.index.Batch(batch)
+}
+
+// Search returns the IDs of the users matching filter, newest first.
+func (b *BleveIndexer) Search(
+	ctx context.Context,
+	filter entities.UserFilter,
+	limit, offset int,
+) ([]entities.UserID, error) {
+	request := bleve.NewSearchRequestOptions(bleveFilterQuery(filter), limit, offset, false)
+	request.SortBy([]string{"-" + fieldCreatedAt, "-" + fieldID})
+
+	result, err := b.index.SearchInContext(ctx, request)
+	if err != nil {
+		return nil, fmt.Errorf("bleve search: %w", err)
+	}
+
+	return hitIDs(result)
+}
+
+// SearchWithFacets returns the IDs of the best matches of query with the
+// facets of every match. With a status the hits are searched separately,
+// since the facets count users of every status.
+func (b *BleveIndexer) SearchWithFacets(
+	ctx context.Context,
+	text string,
+	status entities.UserStatus,
+	limit int,
+) ([]entities.UserID, *entities.SearchFacets, error) {
+	matches := bleveTextQuery(text)
+
+	request := bleve.NewSearchRequestOptions(matches, limit, 0, false)
+	request.AddFacet(fieldStatus, bleve.NewFacetRequest(fieldStatus, enumFacetSize))
+	request.AddFacet(fieldRole, bleve.NewFacetRequest(fieldRole, enumFacetSize))
+	request.AddFacet(fieldTags, bleve.NewFacetRequest(fieldTags, adapters.DefaultFacetTagLimit))
+
+	if status != "" {
+		request.Size = 0
+	}
+
+	result, err := b.index.SearchInContext(ctx, request)
+	if err != nil {
+		return nil, nil, fmt.Errorf("bleve search: %w", err)
+	}
+
+	facets := bleveFacets(result)
+
+	if status != "" {
+		statusQuery := bleve.NewTermQuery(status.String())
+		statusQuery.SetField(fieldStatus)
+
+		result, err = b.index.SearchInContext(ctx,
+			bleve.NewSearchRequestOptions(bleve.NewConjunctionQuery(matches, statusQuery), limit, 0, false))
+		if err != nil {
+			return nil, nil, fmt.Errorf("bleve search status=%v: %w", status, err)
+		}
+	}
+
+	ids, err := hitIDs(result)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return ids, facets, nil
+}
+
+// bleveMapping maps the user documents; other fields are not indexed.
+func bleveMapping() (*mapping.IndexMappingImpl, error) {
+	indexMapping := bleve.NewIndexMapping()
+
+	err := indexMapping.AddCustomTokenizer(wordsAnalyzer, map[string]any{
+		"type":   regexp.Name,
+		"regexp": `[\p{L}\p{N}]+`,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("bleve tokenizer: %w", err)
+	}
+
+	err = indexMapping.AddCustomAnalyzer(wordsAnalyzer, map[string]any{
+		"type":          custom.Name,
+		"tokenizer":     wordsAnalyzer,
+		"token_filters": []string{lowercase.Name},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("bleve analyzer: %w", err)
+	}
+
+	document := bleve.NewDocumentStaticMapping()
+
+	for _, field := range textFields() {
+		text := bleve.NewTextFieldMapping()
+		text.Analyzer = wordsAnalyzer
+		document.AddFieldMappingsAt(field, text)
+	}
+
+	for _, field := range []string{fieldStatus, fieldRole, fieldTags} {
+		document.AddFieldMappingsAt(field, bleve.NewKeywordFieldMapping())
+	}
+
+	document.AddFieldMappingsAt(fieldID, bleve.NewNumericFieldMapping())
+	document.AddFieldMappingsAt(fieldVerified, bleve.NewBooleanFieldMapping())
+	document.AddFieldMappingsAt(fieldCreatedAt, bleve.NewDateTimeFieldMapping())
+
+	indexMapping.DefaultMapping = document
+
+	return indexMapping, nil
+}
+
+// bleveTextQuery matches the users whose text fields have a word starting
+// with every term of text; an empty text matches every user.
+func bleveTextQuery(text string) query.Query {
+	terms := entities.QueryTerms(text)
+	if len(terms) == 0 {
+		return bleve.NewMatchAllQuery()
+	}
+
+	conjuncts := make([]query.Query, 0, len(terms))
+
+	for _, term := range terms {
+		disjuncts := make([]query.Query, 0, len(textFields()))
+
+		for _, field := range textFields() {
+			prefix := bleve.NewPrefixQuery(term)
+			prefix.SetField(field)
+			disjuncts = append(disjuncts, prefix)
+		}
+
+		conjuncts = append(conjuncts, bleve.NewDisjunctionQuery(disjuncts...))
+	}
+
+	return bleve.NewConjunctionQuery(conjuncts...)
+}
+
+// bleveFilterQuery matches the users satisfying every criterion of filter.
+func bleveFilterQuery(filter entities.UserFilter) query.Query {
+	conjuncts := []query.Query{bleveTextQuery(filter.Query)}
+
+	if len(filter.Statuses) > 0 {
+		conjuncts = append(conjuncts, bleveAnyTerm(fieldStatus, filter.StatusValues()))
+	}
+
+	if len(filter.Roles) > 0 {
+		conjuncts = append(conjuncts, bleveAnyTerm(fieldRole, filter.RoleValues()))
+	}
+
+	if filter.Verified != nil {
+		verified := bleve.NewBoolFieldQuery(*filter.Verified)
+		verified.SetField(fieldVerified)
+		conjuncts = append(conjuncts, verified)
+	}
+
+	if !filter.CreatedAfter.IsZero() || !filter.CreatedBefore.IsZero() {
+		exclusive := false
+		created := bleve.NewDateRangeInclusiveQuery(filter.CreatedAfter, filter.CreatedBefore, &exclusive, &exclusive)
+		created.SetField(fieldCreatedAt)
+		conjuncts = append(conjuncts, created)
+	}
+
+	if len(filter.TagsAny) > 0 {
+		conjuncts = append(conjuncts, bleveAnyTerm(fieldTags, filter.TagsAny))
+	}
+
+	for _, tag := range filter.TagsAll {
+		conjuncts = append(conjuncts, bleveAnyTerm(fieldTags, []string{tag}))
+	}
+
+	return bleve.NewConjunctionQuery(conjuncts...)
+}
+
+// bleveAnyTerm matches documents whose keyword field has any of values.
+func bleveAnyTerm(field string, values []string) query.Query {
+	disjuncts := make([]query.Query, 0, len(values))
+
+	for _, value := range values {
+		term := bleve.NewTermQuery(value)
+		term.SetField(field)
+		disjuncts = append(disjuncts, term)
+	}
+
+	return bleve.NewDisjunctionQuery(disjuncts...)
+}
+
+// bleveFacets converts the term facets of result.
+func bleveFacets(result *bleve.SearchResult) *entities.SearchFacets {
+	facets := entities.NewSearchFacets()
+
+	for _, term := range result.Facets[fieldStatus].Terms.Terms() {
+		facets.ByStatus[entities.UserStatus(term.Term)] = int64(term.Count)
+	}
+
+	for _, term := range result.Facets[fieldRole].Terms.Terms() {
+		facets.ByRole[entities.UserRole(term.Term)] = int64(term.Count)
+	}
+
+	for _, term := range result.Facets[fieldTags].Terms.Terms() {
+		facets.TopTags = append(facets.TopTags, entities.TagCount{Tag: term.Term, Count: int64(term.Count)})
+	}
+
+	slices.SortFunc(facets.TopTags, func(a, b entities.TagCount) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), cmp.Compare(a.Tag, b.Tag))
+	})
+
+	return facets
+}
+
+// hitIDs returns the user IDs of the hits in order.
+func hitIDs(result *bleve.SearchResult) ([]entities.UserID, error) {
+	ids := make([]entities.UserID, 0, len(result.Hits))
+
+	for _, hit := range result.Hits {
+		id, err := strconv.ParseInt(hit.ID, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("hit id=%v: %w", hit.ID, err)
+		}
+
+		ids = append(ids, entities.UserID(id))
+	}
+
+	return ids, nil
+}
+
+// documentID returns the document ID of a user.
+func documentID(id entities.UserID) string {
+	return strconv.FormatInt(id.Int64(), 10)
+}
+
+// documentOf returns the indexed fields of user.
+func documentOf(user *entities.User) map[string]any {
+	return map[string]any{
+		fieldID:        user.ID().Int64(),
+		fieldEmail:     user.Email().String(),
+		fieldUsername:  user.Username().String(),
+		fieldFirstName: user.FirstName().String(),
+		fieldLastName:  user.LastName().String(),
+		fieldStatus:    user.Status().String(),
+		fieldRole:      user.Role().String(),
+		fieldVerified:  user.IsVerified(),
+		fieldTags:      user.Tags(),
+		fieldCreatedAt: user.CreatedAt().UTC(),
+	}
+}
@@ -0,0 +1,424 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+	"github.com/elastic/go-elasticsearch/v8"
+	"github.com/elastic/go-elasticsearch/v8/esapi"
+)
+
+// ErrElasticsearch is returned for requests Elasticsearch rejects.
+var ErrElasticsearch = errors.New("elasticsearch request failed")
+
+// ElasticsearchIndexer is a SearchIndexer on an Elasticsearch index shared
+// by every instance of the application.
+type ElasticsearchIndexer struct {
+	client  *elasticsearch.Client
+	index   string
+	refresh string
+}
+
+var _ repositories.SearchIndexer = (*ElasticsearchIndexer)(nil)
+
+// ElasticsearchOption configures an ElasticsearchIndexer.
+type ElasticsearchOption func(*ElasticsearchIndexer)
+
+// WithRefresh makes every write wait until it is visible to searches, for
+// tests and low write volumes. By default writes become visible with the
+// next periodic refresh of the index.
+func WithRefresh() ElasticsearchOption {
+	return func(e *ElasticsearchIndexer) {
+		e.refresh = "wait_for"
+	}
+}
+
+// NewElasticsearchIndexer creates an indexer on the named index. Call
+// CreateIndex once to create the index with its mapping.
+func NewElasticsearchIndexer(
+	client *elasticsearch.Client,
+	index string,
+	opts ...ElasticsearchOption,
+) *ElasticsearchIndexer {
+	e := &ElasticsearchIndexer{client: client, index: index, refresh: "false"}
+
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	return e
+}
+
+// CreateIndex creates the index with the mapping of the user documents
+// unless it exists.
+func (e *ElasticsearchIndexer) CreateIndex(ctx context.Context) error {
+	exists, err := e.client.Indices.Exists([]string{e.index}, e.client.Indices.Exists.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("check index=%v: %w", e.index, err)
+	}
+
+	_ = exists.Body.Close()
+
+	if exists.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	body, err := json.Marshal(elasticsearchMapping())
+	if err != nil {
+		return fmt.Errorf("encode mapping: %w", err)
+	}
+
+	res, err := e.client.Indices.Create(e.index,
+		e.client.Indices.Create.WithContext(ctx),
+		e.client.Indices.Create.WithBody(bytes.NewReader(body)),
+	)
+
+	return e.decode(res, err, "create index", nil)
+}
+
+// Index adds or replaces the users with one bulk request.
+func (e *ElasticsearchIndexer) Index(ctx context.Context, users ...*entities.User) error {
+	var body bytes.Buffer
+
+	encoder := json.NewEncoder(&body)
+
+	for _, user := range users {
+		document := documentOf(user)
+		document[fieldCreatedAt] = user.CreatedAt().UTC().Format(time.RFC3339Nano)
+
+		err := errors.Join(
+			encoder.Encode(map[string]any{"index": map[string]any{"_id": documentID(user.ID())}}),
+			encoder.Encode(document),
+		)
+		if err != nil {
+			return fmt.Errorf("encode user %s: %w", user.ID(), err)
+		}
+	}
+
+	return e.bulk(ctx, &body, len(users))
+}
+
+// Remove deletes the users with one bulk request.
+func (e *ElasticsearchIndexer) Remove(ctx context.Context, ids ...entities.UserID) error {
+	var body bytes.Buffer
+
+	encoder := json.NewEncoder(&body)
+
+	for _, id := range ids {
+		err := encoder.Encode(map[string]any{"delete": map[string]any{"_id": documentID(id)}})
+		if err != nil {
+			return fmt.Errorf("encode user %s: %w", id, err)
+		}
+	}
+
+	return e.bulk(ctx, &body, len(ids))
+}
+
+// Search returns the IDs of the users matching filter, newest first.
+func (e *ElasticsearchIndexer) Search(
+	ctx context.Context,
+	filter entities.UserFilter,
+	limit, offset int,
+) ([]entities.UserID, error) {
+	var result searchResponse
+
+	err := e.search(ctx, map[string]any{
+		"query":   elasticsearchFilterQuery(filter),
+		"sort":    []any{map[string]any{fieldCreatedAt: "desc"}, map[string]any{fieldID: "desc"}},
+		"from":    offset,
+		"size":    limit,
+		"_source": false,
+	}, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.ids()
+}
+
+// SearchWithFacets returns the IDs of the best matches of query with the
+// facets of every match. The status is applied as a post filter, which
+// narrows the hits but not the aggregations.
+func (e *ElasticsearchIndexer) SearchWithFacets(
+	ctx context.Context,
+	text string,
+	status entities.UserStatus,
+	limit int,
+) ([]entities.UserID, *entities.SearchFacets, error) {
+	request := map[string]any{
+		"query": elasticsearchTextQuery(text),
+		"aggs": map[string]any{
+			fieldStatus: map[string]any{"terms": map[string]any{"field": fieldStatus, "size": enumFacetSize}},
+			fieldRole:   map[string]any{"terms": map[string]any{"field": fieldRole, "size": enumFacetSize}},
+			fieldTags: map[string]any{"terms": map[string]any{
+				"field": fieldTags,
+				"size":  adapters.DefaultFacetTagLimit,
+				"order": []any{map[string]any{"_count": "desc"}, map[string]any{"_key": "asc"}},
+			}},
+		},
+		"size":    limit,
+		"_source": false,
+	}
+
+	if status != "" {
+		request["post_filter"] = map[string]any{"term": map[string]any{fieldStatus: status.String()}}
+	}
+
+	var result searchResponse
+
+	err := e.search(ctx, request, &result)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ids, err := result.ids()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	facets := entities.NewSearchFacets()
+
+	for _, bucket := range result.Aggregations[fieldStatus].Buckets {
+		facets.ByStatus[entities.UserStatus(bucket.Key)] = bucket.DocCount
+	}
+
+	for _, bucket := range result.Aggregations[fieldRole].Buckets {
+		facets.ByRole[entities.UserRole(bucket.Key)] = bucket.DocCount
+	}
+
+	for _, bucket := range result.Aggregations[fieldTags].Buckets {
+		facets.TopTags = append(facets.TopTags, entities.TagCount{Tag: bucket.Key, Count: bucket.DocCount})
+	}
+
+	return ids, facets, nil
+}
+
+// search runs a search request and decodes its response into result.
+func (e *ElasticsearchIndexer) search(ctx context.Context, request map[string]any, result any) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("encode search: %w", err)
+	}
+
+	res, err := e.client.Search(
+		e.client.Search.WithContext(ctx),
+		e.client.Search.WithIndex(e.index),
+		e.client.Search.WithBody(bytes.NewReader(body)),
+	)
+
+	return e.decode(res, err, "search", result)
+}
+
+// bulk sends a bulk request of count actions and reports the first failed one.
+func (e *ElasticsearchIndexer) bulk(ctx context.Context, body io.Reader, count int) error {
+	if count == 0 {
+		return nil
+	}
+
+	res, err := e.client.Bulk(body,
+		e.client.Bulk.WithContext(ctx),
+		e.client.Bulk.WithIndex(e.index),
+		e.client.Bulk.WithRefresh(e.refresh),
+	)
+
+	var result bulkResponse
+
+	err = e.decode(res, err, "bulk", &result)
+	if err != nil {
+		return err
+	}
+
+	if !result.Errors {
+		return nil
+	}
+
+	for _, item := range result.Items {
+		for action, status := range item {
+			// Deleting a user that was never indexed is not an error.
+			if status.Error != nil && (action != "delete" || status.Status != http.StatusNotFound) {
+				return fmt.Errorf("bulk %s id=%v: %s: %w", action, status.ID, status.Error.Reason, ErrElasticsearch)
+			}
+		}
+	}
+
+	return nil
+}
+
+// decode closes the response of an operation and decodes its body into
+// result unless result is nil.
+func (e *ElasticsearchIndexer) decode(res *esapi.Response, err error, op string, result any) error {
+	if err != nil {
+		return fmt.Errorf("%s index=%v: %w", op, e.index, err)
+	}
+
+	defer func() { _ = res.Body.Close() }()
+
+	if res.IsError() {
+		return fmt.Errorf("%s index=%v: %s: %w", op, e.index, res.Status(), ErrElasticsearch)
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	err = json.NewDecoder(res.Body).Decode(result)
+	if err != nil {
+		return fmt.Errorf("decode %s response: %w", op, err)
+	}
+
+	return nil
+}
+
+// elasticsearchMapping returns the index settings and mapping; the words
+// analyzer splits text the way entities.QueryTerms splits queries.
+func elasticsearchMapping() map[string]any {
+	text := map[string]any{"type": "text", "analyzer": wordsAnalyzer}
+	keyword := map[string]any{"type": "keyword"}
+
+	return map[string]any{
+		"settings": map[string]any{"analysis": map[string]any{
+			"tokenizer": map[string]any{wordsAnalyzer: map[string]any{
+				"type":    "pattern",
+				"pattern": `[^\p{L}\p{N}]+`,
+			}},
+			"analyzer": map[string]any{wordsAnalyzer: map[string]any{
+				"type":      "custom",
+				"tokenizer": wordsAnalyzer,
+				"filter":    []string{"lowercase"},
+			}},
+		}},
+		"mappings": map[string]any{
+			"dynamic": "strict",
+			"properties": map[string]any{
+				fieldID:        map[string]any{"type": "long"},
+				fieldEmail:     text,
+				fieldUsername:  text,
+				fieldFirstName: text,
+				fieldLastName:  text,
+				fieldStatus:    keyword,
+				fieldRole:      keyword,
+				fieldTags:      keyword,
+				fieldVerified:  map[string]any{"type": "boolean"},
+				fieldCreatedAt: map[string]any{"type": "date"},
+			},
+		},
+	}
+}
+
+// elasticsearchTextQuery matches the users whose text fields have a word
+// starting with every term of text; an empty text matches every user.
+func elasticsearchTextQuery(text string) map[string]any {
+	terms := entities.QueryTerms(text)
+	if len(terms) == 0 {
+		return map[string]any{"match_all": map[string]any{}}
+	}
+
+	must := make([]any, 0, len(terms))
+
+	for _, term := range terms {
+		should := make([]any, 0, len(textFields()))
+		for _, field := range textFields() {
+			should = append(should, map[string]any{"prefix": map[string]any{field: term}})
+		}
+
+		must = append(must, map[string]any{"bool": map[string]any{"should": should, "minimum_should_match": 1}})
+	}
+
+	return map[string]any{"bool": map[string]any{"must": must}}
+}
+
+// elasticsearchFilterQuery matches the users satisfying every criterion of filter.
+func elasticsearchFilterQuery(filter entities.UserFilter) map[string]any {
+	clauses := []any{}
+
+	if len(filter.Statuses) > 0 {
+		clauses = append(clauses, map[string]any{"terms": map[string]any{fieldStatus: filter.StatusValues()}})
+	}
+
+	if len(filter.Roles) > 0 {
+		clauses = append(clauses, map[string]any{"terms": map[string]any{fieldRole: filter.RoleValues()}})
+	}
+
+	if filter.Verified != nil {
+		clauses = append(clauses, map[string]any{"term": map[string]any{fieldVerified: *filter.Verified}})
+	}
+
+	if !filter.CreatedAfter.IsZero() || !filter.CreatedBefore.IsZero() {
+		created := map[string]any{}
+		if !filter.CreatedAfter.IsZero() {
+			created["gt"] = filter.CreatedAfter.UTC().Format(time.RFC3339Nano)
+		}
+
+		if !filter.CreatedBefore.IsZero() {
+			created["lt"] = filter.CreatedBefore.UTC().Format(time.RFC3339Nano)
+		}
+
+		clauses = append(clauses, map[string]any{"range": map[string]any{fieldCreatedAt: created}})
+	}
+
+	if len(filter.TagsAny) > 0 {
+		clauses = append(clauses, map[string]any{"terms": map[string]any{fieldTags: filter.TagsAny}})
+	}
+
+	for _, tag := range filter.TagsAll {
+		clauses = append(clauses, map[string]any{"term": map[string]any{fieldTags: tag}})
+	}
+
+	return map[string]any{"bool": map[string]any{
+		"must":   elasticsearchTextQuery(filter.Query),
+		"filter": clauses,
+	}}
+}
+
+// searchResponse is the part of a search response the indexer reads.
+type searchResponse struct {
+	Hits struct {
+		Hits []struct {
+			ID string `json:"_id"`
+		} `json:"hits"`
+	} `json:"hits"`
+	Aggregations map[string]struct {
+		Buckets []struct {
+			Key      string `json:"key"`
+			DocCount int64  `json:"doc_count"`
+		} `json:"buckets"`
+	} `json:"aggregations"`
+}
+
+// ids returns the user IDs of the hits in order.
+func (r *searchResponse) ids() ([]entities.UserID, error) {
+	ids := make([]entities.UserID, 0, len(r.Hits.Hits))
+
+	for _, hit := range r.Hits.Hits {
+		id, err := strconv.ParseInt(hit.ID, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("hit id=%v: %w", hit.ID, err)
+		}
+
+		ids = append(ids, entities.UserID(id))
+	}
+
+	return ids, nil
+}
+
+// bulkResponse is the part of a bulk response the indexer reads.
+type bulkResponse struct {
+	Errors bool `json:"errors"`
+	Items  []map[string]struct {
+		ID     string `json:"_id"`
+		Status int    `json:"status"`
+		Error  *struct {
+			Reason string `json:"reason"`
+		} `json:"error"`
+	} `json:"items"`
+}
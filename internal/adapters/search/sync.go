@@ -0,0 +1,156 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// defaultReindexBatchSize is the page size users are read and indexed with.
+const defaultReindexBatchSize = 500
+
+// SyncHandler is an events.EventHandler that keeps an index in sync with
+// the users the events are about. It reloads the users rather than reading
+// the event payloads, so it is idempotent and never indexes stale data.
+type SyncHandler struct {
+	index repositories.SearchIndexer
+	users repositories.UserRepository
+}
+
+var _ events.EventHandler = (*SyncHandler)(nil)
+
+// NewSyncHandler creates a handler indexing the users of users into index.
+func NewSyncHandler(index repositories.SearchIndexer, users repositories.UserRepository) *SyncHandler {
+	return &SyncHandler{index: index, users: users}
+}
+
+// EventTypes returns the event types that change indexed fields, to
+// subscribe the handler with.
+func (h *SyncHandler) EventTypes() []events.EventType {
+	return []events.EventType{
+		events.EventUserCreated,
+		events.EventUserUpdated,
+		events.EventUserDeleted,
+		events.EventUserRestored,
+		events.EventUserActivated,
+		events.EventUserDeactivated,
+		events.EventUserSuspended,
+		events.EventUserReinstated,
+		events.EventUserVerified,
+		events.EventProfileUpdated,
+		events.EventRoleChanged,
+		events.EventEmailChanged,
+		events.EventUsernameChanged,
+		events.EventIdentityChangeReverted,
+		events.EventUsersBulkUpdated,
+		events.EventUserErased,
+	}
+}
+
+// Handle reindexes the users event is about. Events of other types are ignored.
+func (h *SyncHandler) Handle(ctx context.Context, event *events.UserEvent) error {
+	if !slices.Contains(h.EventTypes(), event.Type) {
+		return nil
+	}
+
+	ids := []entities.UserID{event.UserID}
+
+	if event.Type == events.EventUsersBulkUpdated {
+		var err error
+
+		ids, err = bulkUserIDs(event)
+		if err != nil {
+			return err
+		}
+	}
+
+	return h.Sync(ctx, ids...)
+}
+
+// Sync indexes the users with ids as stored now and removes those that were
+// deleted.
+func (h *SyncHandler) Sync(ctx context.Context, ids ...entities.UserID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	users, err := h.users.GetByIDs(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("load %d users to index: %w", len(ids), err)
+	}
+
+	err = h.index.Index(ctx, users...)
+	if err != nil {
+		return fmt.Errorf("index %d users: %w", len(users), err)
+	}
+
+	removed := slices.DeleteFunc(slices.Clone(ids), func(id entities.UserID) bool {
+		return slices.ContainsFunc(users, func(user *entities.User) bool { return user.ID() == id })
+	})
+
+	err = h.index.Remove(ctx, removed...)
+	if err != nil {
+		return fmt.Errorf("remove %d users from index: %w", len(removed), err)
+	}
+
+	return nil
+}
+
+// Reindex adds every live user of users to index and returns how many were
+// indexed. Entries of deleted users are not removed, so rebuild into a new
+// index and switch to it once Reindex returns.
+func Reindex(ctx context.Context, index repositories.SearchIndexer, users repositories.UserRepository) (int64, error) {
+	var (
+		indexed int64
+		cursor  string
+	)
+
+	for {
+		page, err := users.ListPage(ctx, entities.UserFilter{}, cursor, defaultReindexBatchSize)
+		if err != nil {
+			return indexed, fmt.Errorf("list users after cursor=%q: %w", cursor, err)
+		}
+
+		err = index.Index(ctx, page.Users...)
+		if err != nil {
+			return indexed, fmt.Errorf("index %d users: %w", len(page.Users), err)
+		}
+
+		indexed += int64(len(page.Users))
+
+		if page.NextCursor == "" {
+			return indexed, nil
+		}
+
+		cursor = page.NextCursor
+	}
+}
+
+// bulkUserIDs returns the users of a users.bulk_updated event. Payloads
+// arrive as structs from in-process publishers and as raw JSON from brokers,
+// so both are decoded through JSON.
+func bulkUserIDs(event *events.UserEvent) ([]entities.UserID, error) {
+	payload, ok := event.Data.(json.RawMessage)
+	if !ok {
+		var err error
+
+		payload, err = json.Marshal(event.Data)
+		if err != nil {
+			return nil, fmt.Errorf("encode event id=%v data: %w", event.ID, err)
+		}
+	}
+
+	var data events.UsersBulkUpdatedEvent
+
+	err := json.Unmarshal(payload, &data)
+	if err != nil {
+		return nil, fmt.Errorf("decode event id=%v data: %w", event.ID, err)
+	}
+
+	return data.UserIDs, nil
+}
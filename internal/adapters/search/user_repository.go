@@ -0,0 +1,131 @@
+// Package search serves user searches from a full-text index instead of the
+// database, whose full-text support differs per engine.
+//
+// A SearchIndexer is backed by Bleve, embedded in the process, or by
+// Elasticsearch. A SyncHandler keeps it in sync from the domain events and
+// Reindex fills a new index; the UserRepository decorator then answers
+// SearchWithFacets and the List filters with a query or tags from the index:
+//
+//	index, err := search.OpenBleveIndexer("users.bleve")
+//	sync := search.NewSyncHandler(index, repo)
+//	dispatcher.Subscribe("search", sync, sync.EventTypes()...)
+//	users := search.NewUserRepository(repo, index)
+//
+// The index only returns IDs; the users are loaded from the database, and
+// those that no longer match, because the index lags behind, are dropped.
+package search
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/validation"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// UserRepository is a decorator that serves searches from an index. Index
+// failures are logged and fall back to the database; every other method,
+// including keyset pagination, goes to the database.
+type UserRepository struct {
+	repositories.UserRepository
+
+	index repositories.SearchIndexer
+}
+
+// NewUserRepository wraps repo with searches served from index.
+func NewUserRepository(repo repositories.UserRepository, index repositories.SearchIndexer) *UserRepository {
+	return &UserRepository{UserRepository: repo, index: index}
+}
+
+// Ensure UserRepository implements the domain UserRepository.
+var _ repositories.UserRepository = (*UserRepository)(nil)
+
+// List serves filters with a query or tags from the index and every other
+// filter from the database.
+func (r *UserRepository) List(
+	ctx context.Context,
+	filter entities.UserFilter,
+	limit, offset int,
+) ([]*entities.User, error) {
+	searched := filter.Query != "" || len(filter.TagsAny) > 0 || len(filter.TagsAll) > 0
+
+	// Invalid arguments are left to the database, which reports them.
+	if !searched || filter.Validate() != nil || limit <= 0 || offset < 0 {
+		return r.UserRepository.List(ctx, filter, limit, offset)
+	}
+
+	ids, err := r.index.Search(ctx, filter, limit, offset)
+	if err != nil {
+		slog.Warn("search index failed, listing from the database", "error", err)
+
+		return r.UserRepository.List(ctx, filter, limit, offset)
+	}
+
+	users, err := r.load(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	return slices.DeleteFunc(users, func(user *entities.User) bool { return !filter.Matches(user) }), nil
+}
+
+// SearchWithFacets serves the search from the index. Unlike the databases,
+// which match substrings, the index matches every query word as a prefix.
+func (r *UserRepository) SearchWithFacets(
+	ctx context.Context,
+	query string,
+	status entities.UserStatus,
+	limit int,
+) (*entities.UserSearchResult, error) {
+	if validation.ValidateSearchQuery(query, limit) != nil {
+		return r.UserRepository.SearchWithFacets(ctx, query, status, limit)
+	}
+
+	ids, facets, err := r.index.SearchWithFacets(ctx, query, status, limit)
+	if err != nil {
+		slog.Warn("search index failed, searching the database", "error", err)
+
+		return r.UserRepository.SearchWithFacets(ctx, query, status, limit)
+	}
+
+	users, err := r.load(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	if status != "" {
+		users = slices.DeleteFunc(users, func(user *entities.User) bool { return user.Status() != status })
+	}
+
+	return &entities.UserSearchResult{Users: users, Facets: facets}, nil
+}
+
+// load returns the users with ids in the order of ids, omitting those that
+// were deleted since they were indexed.
+func (r *UserRepository) load(ctx context.Context, ids []entities.UserID) ([]*entities.User, error) {
+	users := make([]*entities.User, 0, len(ids))
+	if len(ids) == 0 {
+		return users, nil
+	}
+
+	found, err := r.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("load %d search hits: %w", len(ids), err)
+	}
+
+	byID := make(map[entities.UserID]*entities.User, len(found))
+	for _, user := range found {
+		byID[user.ID()] = user
+	}
+
+	for _, id := range ids {
+		if user, ok := byID[id]; ok {
+			users = append(users, user)
+		}
+	}
+
+	return users, nil
+}
@@ -0,0 +1,108 @@
+package adapters
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/google/uuid"
+)
+
+// EncodeSessionDocuments encodes the device info and the rotated refresh
+// tokens of a session for the JSON columns of user_sessions.
+func EncodeSessionDocuments(record entities.SessionRecord) (deviceInfo, rotated []byte, err error) {
+	deviceInfo, err = json.Marshal(record.DeviceInfo)
+	if err != nil {
+		return nil, nil, fmt.Errorf("encode device info of session id=%v: %w", record.ID, err)
+	}
+
+	tokens := make([]string, 0, len(record.RotatedRefreshTokens))
+	for _, token := range record.RotatedRefreshTokens {
+		tokens = append(tokens, token.String())
+	}
+
+	rotated, err = json.Marshal(tokens)
+	if err != nil {
+		return nil, nil, fmt.Errorf("encode rotated refresh tokens of session id=%v: %w", record.ID, err)
+	}
+
+	return deviceInfo, rotated, nil
+}
+
+// DecodeSessionDocuments sets the device info and the rotated refresh tokens
+// of record from the JSON columns of user_sessions.
+func DecodeSessionDocuments(record *entities.SessionRecord, deviceInfo, rotated []byte) error {
+	err := json.Unmarshal(deviceInfo, &record.DeviceInfo)
+	if err != nil {
+		return fmt.Errorf("decode device info of session id=%v: %w", record.ID, err)
+	}
+
+	var tokens []string
+
+	err = json.Unmarshal(rotated, &tokens)
+	if err != nil {
+		return fmt.Errorf("decode rotated refresh tokens of session id=%v: %w", record.ID, err)
+	}
+
+	record.RotatedRefreshTokens = make([]entities.RefreshToken, 0, len(tokens))
+
+	for _, token := range tokens {
+		parsed, err := uuid.Parse(token)
+		if err != nil {
+			return fmt.Errorf("decode rotated refresh token of session id=%v: %w", record.ID, err)
+		}
+
+		record.RotatedRefreshTokens = append(record.RotatedRefreshTokens, entities.RefreshToken(parsed))
+	}
+
+	return nil
+}
+
+// SessionIPAddress returns the text stored for the IP address of a session,
+// which is empty when the address is unknown.
+func SessionIPAddress(ip net.IP) string {
+	if ip == nil {
+		return ""
+	}
+
+	return ip.String()
+}
+
+// SessionRefreshExpiresAt returns nil when the session has no refresh token
+// expiry, so the nullable column stays NULL.
+func SessionRefreshExpiresAt(record entities.SessionRecord) *time.Time {
+	if record.RefreshExpiresAt.IsZero() {
+		return nil
+	}
+
+	expiresAt := record.RefreshExpiresAt.UTC()
+
+	return &expiresAt
+}
+
+// SessionRefreshTokens returns the current and the rotated refresh tokens of
+// a session that were issued, to index them for GetByRefreshToken.
+func SessionRefreshTokens(record entities.SessionRecord) []entities.RefreshToken {
+	tokens := make([]entities.RefreshToken, 0, len(record.RotatedRefreshTokens)+1)
+	if !record.RefreshToken.IsZero() {
+		tokens = append(tokens, record.RefreshToken)
+	}
+
+	for _, token := range record.RotatedRefreshTokens {
+		if !token.IsZero() {
+			tokens = append(tokens, token)
+		}
+	}
+
+	return tokens
+}
+
+// SessionStatsWindows returns the start of the 24 hour, 7 day and 30 day
+// windows of GetSessionStats ending at now.
+func SessionStatsWindows(now time.Time) (dayAgo, weekAgo, monthAgo time.Time) {
+	const day = 24 * time.Hour
+
+	return now.Add(-day), now.Add(-7 * day), now.Add(-30 * day)
+}
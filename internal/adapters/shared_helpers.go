@@ -10,6 +10,9 @@ import (
 	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
 )
 
+// DefaultFacetTagLimit is the number of top tags returned with search facets.
+const DefaultFacetTagLimit = 10
+
 // NotImplementedMethods interface for repositories with NotImplemented method.
 type NotImplementedMethods interface {
 	NotImplemented(method string) error
@@ -32,13 +35,13 @@ type NotImplementedListResult = []*entities.User
 func ListWithPagination[T NotImplementedMethods](
 	_ context.Context,
 	repo T,
-	_ entities.UserStatus,
+	filter entities.UserFilter,
 	limit, offset int,
 	methodName string,
 ) (NotImplementedListResult, error) {
-	err := validation.ValidatePagination(limit, offset)
+	err := ValidateListRequest(filter, limit, offset)
 	if err != nil {
-		return nil, fmt.Errorf("limit=%v: %w", limit, err)
+		return nil, err
 	}
 
 	return nil, fmt.Errorf(
@@ -49,56 +52,64 @@ func ListWithPagination[T NotImplementedMethods](
 	)
 }
 
-// SearchWithValidation handles common validation for Search methods.
-// Returns validation error or calls notImplementedStub if validation passes.
-func SearchWithValidation[T NotImplementedMethods](
-	_ context.Context,
-	repo T,
-	query string,
-	_ entities.UserStatus,
-	limit int,
-	methodName string,
-) (NotImplementedListResult, error) {
-	err := validation.ValidateSearchQuery(query, limit)
+// ValidateListRequest validates the filter and pagination of List.
+func ValidateListRequest(filter entities.UserFilter, limit, offset int) error {
+	err := validation.ValidatePagination(limit, offset)
 	if err != nil {
-		return nil, fmt.Errorf("query=%v limit=%v: %w", query, limit, err)
+		return fmt.Errorf("limit=%v offset=%v: %w", limit, offset, err)
 	}
 
-	return nil, fmt.Errorf(
-		"method %s not implemented for query=%v limit=%v: %w",
-		methodName,
-		query,
-		limit,
-		repo.NotImplemented(methodName),
-	)
+	return filter.Validate()
+}
+
+// ParsePageRequest validates the arguments of ListPage and decodes the cursor,
+// which is nil for the first page.
+func ParsePageRequest(filter entities.UserFilter, cursor string, limit int) (*entities.UserCursor, error) {
+	err := ValidateListRequest(filter, limit, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	after, err := entities.DecodeUserCursor(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("cursor=%q: %w", cursor, err)
+	}
+
+	return after, nil
+}
+
+// ParseActivityPageRequest validates the limit of ActivityRepository.ListPage
+// and decodes the cursor, which is nil for the first page.
+func ParseActivityPageRequest(cursor string, limit int) (*entities.ActivityCursor, error) {
+	err := validation.ValidatePagination(limit, 0)
+	if err != nil {
+		return nil, fmt.Errorf("limit=%v: %w", limit, err)
+	}
+
+	after, err := entities.DecodeActivityCursor(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("cursor=%q: %w", cursor, err)
+	}
+
+	return after, nil
 }
 
-// SearchByTagsWithValidation handles common validation for SearchByTags methods.
+// SearchWithFacetsValidation handles common validation for SearchWithFacets methods.
 // Returns validation error or calls notImplementedStub if validation passes.
-func SearchByTagsWithValidation[T NotImplementedMethods](
+func SearchWithFacetsValidation[T NotImplementedMethods](
 	_ context.Context,
 	repo T,
-	tags []string,
+	query string,
 	_ entities.UserStatus,
-	_, offset int,
+	limit int,
 	methodName string,
-) (NotImplementedListResult, error) {
-	err := validation.ValidateTags(tags)
+) (*entities.UserSearchResult, error) {
+	err := validation.ValidateSearchQuery(query, limit)
 	if err != nil {
-		return nil, err
-	}
-
-	if offset < 0 {
-		offset = 0
+		return nil, fmt.Errorf("query=%v limit=%v: %w", query, limit, err)
 	}
 
-	_ = offset
-
-	return nil, fmt.Errorf(
-		"method %s not implemented: %w",
-		methodName,
-		repo.NotImplemented(methodName),
-	)
+	return nil, notImplementedError(repo, methodName)
 }
 
 // ChangeStatusWithValidation handles common validation for ChangeStatus methods.
@@ -131,32 +142,10 @@ func ChangeRoleWithValidation[T NotImplementedMethods](
 func ListUsers[T NotImplementedMethods](
 	ctx context.Context,
 	repo T,
-	status entities.UserStatus,
-	limit, offset int,
-) ([]*entities.User, error) {
-	return ListWithPagination(ctx, repo, status, limit, offset, "List")
-}
-
-// SearchUsers handles the common Search implementation for user repositories.
-func SearchUsers[T NotImplementedMethods](
-	ctx context.Context,
-	repo T,
-	query string,
-	status entities.UserStatus,
-	limit int,
-) ([]*entities.User, error) {
-	return SearchWithValidation(ctx, repo, query, status, limit, "Search")
-}
-
-// SearchUsersByTags handles the common SearchByTags implementation for user repositories.
-func SearchUsersByTags[T NotImplementedMethods](
-	ctx context.Context,
-	repo T,
-	tags []string,
-	status entities.UserStatus,
+	filter entities.UserFilter,
 	limit, offset int,
 ) ([]*entities.User, error) {
-	return SearchByTagsWithValidation(ctx, repo, tags, status, limit, offset, "SearchByTags")
+	return ListWithPagination(ctx, repo, filter, limit, offset, "List")
 }
 
 // ChangeUserStatus handles the common ChangeStatus implementation for user repositories.
@@ -185,30 +174,20 @@ func NewBaseUserRepository(dbName string) *BaseUserRepository {
 // List retrieves users with pagination.
 func (r *BaseUserRepository) List(
 	ctx context.Context,
-	status entities.UserStatus,
+	filter entities.UserFilter,
 	limit, offset int,
 ) ([]*entities.User, error) {
-	return ListUsers(ctx, r, status, limit, offset)
+	return ListUsers(ctx, r, filter, limit, offset)
 }
 
-// Search searches users by query.
-func (r *BaseUserRepository) Search(
+// SearchWithFacets searches users by query and returns facet counts.
+func (r *BaseUserRepository) SearchWithFacets(
 	ctx context.Context,
 	query string,
 	status entities.UserStatus,
 	limit int,
-) ([]*entities.User, error) {
-	return SearchUsers(ctx, r, query, status, limit)
-}
-
-// SearchByTags searches users by tags.
-func (r *BaseUserRepository) SearchByTags(
-	ctx context.Context,
-	tags []string,
-	status entities.UserStatus,
-	limit, offset int,
-) ([]*entities.User, error) {
-	return SearchUsersByTags(ctx, r, tags, status, limit, offset)
+) (*entities.UserSearchResult, error) {
+	return SearchWithFacetsValidation(ctx, r, query, status, limit, "SearchWithFacets")
 }
 
 // ChangeStatus changes user status.
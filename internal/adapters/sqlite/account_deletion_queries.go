@@ -0,0 +1,101 @@
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	sqlitedb "github.com/LarsArtmann/template-sqlc/internal/db/sqlite"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+)
+
+// queries returns the sqlc queries bound to the repository's database handle.
+func (r *AccountDeletionRepository) queries() *sqlitedb.Queries {
+	return sqlitedb.New(r.db)
+}
+
+// Create records a deletion unless one is pending for the user.
+func (r *AccountDeletionRepository) Create(ctx context.Context, deletion *entities.AccountDeletion) error {
+	err := r.queries().CreateAccountDeletion(ctx, &sqlitedb.CreateAccountDeletionParams{
+		UserID:       int64(deletion.UserID),
+		RequestedBy:  int64(deletion.RequestedBy),
+		RequestedAt:  deletion.RequestedAt,
+		ScheduledFor: deletion.ScheduledFor,
+	})
+	if err != nil {
+		return fmt.Errorf("user=%v: %w", deletion.UserID,
+			handleAccountDeletionError(err, "record account deletion"))
+	}
+
+	return nil
+}
+
+// GetByUser retrieves the pending deletion of a user.
+func (r *AccountDeletionRepository) GetByUser(
+	ctx context.Context,
+	userID entities.UserID,
+) (*entities.AccountDeletion, error) {
+	row, err := r.queries().GetAccountDeletion(ctx, int64(userID))
+	if err != nil {
+		return nil, fmt.Errorf("user=%v: %w", userID, handleAccountDeletionError(err, "get account deletion"))
+	}
+
+	return domainAccountDeletion(row)
+}
+
+// Delete removes the pending deletion of a user.
+func (r *AccountDeletionRepository) Delete(ctx context.Context, userID entities.UserID) error {
+	affected, err := r.queries().DeleteAccountDeletion(ctx, int64(userID))
+	if err != nil {
+		return fmt.Errorf("user=%v: %w", userID, handleAccountDeletionError(err, "delete account deletion"))
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("user=%v: %w", userID, entities.ErrAccountDeletionNotFound)
+	}
+
+	return nil
+}
+
+// ListDue returns up to limit deletions scheduled for now or earlier, the
+// earliest scheduled first.
+func (r *AccountDeletionRepository) ListDue(
+	ctx context.Context,
+	now time.Time,
+	limit int,
+) ([]*entities.AccountDeletion, error) {
+	rows, err := r.queries().ListDueAccountDeletions(ctx, &sqlitedb.ListDueAccountDeletionsParams{
+		Now:      now.UTC(),
+		RowLimit: int64(limit),
+	})
+	if err != nil {
+		return nil, handleAccountDeletionError(err, "list due account deletions")
+	}
+
+	return scanAll[*entities.AccountDeletion](rows)
+}
+
+// domainAccountDeletion converts a generated account_deletions row into a domain entity.
+func domainAccountDeletion(row *sqlitedb.AccountDeletions) (*entities.AccountDeletion, error) {
+	return &entities.AccountDeletion{
+		UserID:       entities.UserID(row.UserID),
+		RequestedBy:  entities.UserID(row.RequestedBy),
+		RequestedAt:  row.RequestedAt,
+		ScheduledFor: row.ScheduledFor,
+	}, nil
+}
+
+// handleAccountDeletionError maps database errors for account deletion
+// queries to domain errors. The user is the primary key, so a unique
+// violation means a deletion is pending already.
+func handleAccountDeletionError(err error, operation string) error {
+	return sqlitedb.HandleDBError(
+		err,
+		operation,
+		entities.ErrAccountDeletionNotFound,
+		entities.ErrAccountDeletionPending,
+		sqlitedb.IsSQLiteUniqueConstraintError,
+	)
+}
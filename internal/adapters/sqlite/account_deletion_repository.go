@@ -0,0 +1,22 @@
+package sqlite
+
+import (
+	"github.com/LarsArtmann/template-sqlc/internal/adapters"
+	"github.com/LarsArtmann/template-sqlc/internal/db/shared"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// AccountDeletionRepository implements AccountDeletionRepository for SQLite.
+type AccountDeletionRepository struct {
+	*adapters.NotImplementedAccountDeletionRepository
+
+	db shared.DBTX
+}
+
+// NewAccountDeletionRepository creates a new SQLite account deletion repository.
+func NewAccountDeletionRepository(db shared.DBTX) repositories.AccountDeletionRepository {
+	return &AccountDeletionRepository{
+		NotImplementedAccountDeletionRepository: adapters.NewNotImplementedAccountDeletionRepository("SQLite"),
+		db:                                      db,
+	}
+}
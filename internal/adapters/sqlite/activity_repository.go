@@ -0,0 +1,22 @@
+package sqlite
+
+import (
+	"github.com/LarsArtmann/template-sqlc/internal/adapters"
+	"github.com/LarsArtmann/template-sqlc/internal/db/shared"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// ActivityRepository implements ActivityRepository for SQLite.
+type ActivityRepository struct {
+	*adapters.NotImplementedActivityRepository
+
+	db shared.DBTX
+}
+
+// NewActivityRepository creates a new SQLite activity repository.
+func NewActivityRepository(db shared.DBTX) repositories.ActivityRepository {
+	return &ActivityRepository{
+		NotImplementedActivityRepository: adapters.NewNotImplementedActivityRepository("SQLite"),
+		db:                               db,
+	}
+}
@@ -0,0 +1,22 @@
+package sqlite
+
+import (
+	"github.com/LarsArtmann/template-sqlc/internal/adapters"
+	"github.com/LarsArtmann/template-sqlc/internal/db/shared"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// FeatureFlagRepository implements FeatureFlagRepository for SQLite.
+type FeatureFlagRepository struct {
+	*adapters.NotImplementedFeatureFlagRepository
+
+	db shared.DBTX
+}
+
+// NewFeatureFlagRepository creates a new SQLite feature flag repository.
+func NewFeatureFlagRepository(db shared.DBTX) repositories.FeatureFlagRepository {
+	return &FeatureFlagRepository{
+		NotImplementedFeatureFlagRepository: adapters.NewNotImplementedFeatureFlagRepository("SQLite"),
+		db:                                  db,
+	}
+}
@@ -0,0 +1,119 @@
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	sqlitedb "github.com/LarsArtmann/template-sqlc/internal/db/sqlite"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+)
+
+// queries returns the sqlc queries bound to the repository's database handle.
+func (r *IdempotencyRepository) queries() *sqlitedb.Queries {
+	return sqlitedb.New(r.db)
+}
+
+// Reserve stores a pending record.
+func (r *IdempotencyRepository) Reserve(ctx context.Context, record *entities.IdempotencyRecord) error {
+	err := r.queries().ReserveIdempotencyKey(ctx, &sqlitedb.ReserveIdempotencyKeyParams{
+		IdempotencyKey: record.Key.String(),
+		Operation:      record.Operation,
+		RequestHash:    record.RequestHash,
+		CreatedAt:      record.CreatedAt.UTC(),
+		ExpiresAt:      record.ExpiresAt.UTC(),
+	})
+	if err != nil {
+		return fmt.Errorf("key=%v: %w", record.Key, handleIdempotencyError(err, "reserve idempotency key"))
+	}
+
+	return nil
+}
+
+// Get retrieves the record of key.
+func (r *IdempotencyRepository) Get(
+	ctx context.Context,
+	key entities.IdempotencyKey,
+) (*entities.IdempotencyRecord, error) {
+	row, err := r.queries().GetIdempotencyKey(ctx, key.String())
+	if err != nil {
+		return nil, fmt.Errorf("key=%v: %w", key, handleIdempotencyError(err, "get idempotency key"))
+	}
+
+	return domainIdempotencyRecord(row)
+}
+
+// Complete stores the response and expiry of a pending record.
+func (r *IdempotencyRepository) Complete(ctx context.Context, record *entities.IdempotencyRecord) error {
+	affected, err := r.queries().CompleteIdempotencyKey(ctx, &sqlitedb.CompleteIdempotencyKeyParams{
+		Response:       string(record.Response),
+		CompletedAt:    nullTime.DomainToDB(record.CompletedAt),
+		ExpiresAt:      record.ExpiresAt.UTC(),
+		IdempotencyKey: record.Key.String(),
+	})
+	if err != nil {
+		return fmt.Errorf("key=%v: %w", record.Key, handleIdempotencyError(err, "complete idempotency key"))
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("key=%v: %w", record.Key, entities.ErrIdempotencyKeyNotFound)
+	}
+
+	return nil
+}
+
+// Release deletes a pending record.
+func (r *IdempotencyRepository) Release(ctx context.Context, key entities.IdempotencyKey) error {
+	affected, err := r.queries().ReleaseIdempotencyKey(ctx, key.String())
+	if err != nil {
+		return fmt.Errorf("key=%v: %w", key, handleIdempotencyError(err, "release idempotency key"))
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("key=%v: %w", key, entities.ErrIdempotencyKeyNotFound)
+	}
+
+	return nil
+}
+
+// DeleteExpired removes the records expired at now.
+func (r *IdempotencyRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	deleted, err := r.queries().DeleteExpiredIdempotencyKeys(ctx, now.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("delete expired idempotency keys: %w",
+			handleIdempotencyError(err, "delete expired idempotency keys"))
+	}
+
+	return deleted, nil
+}
+
+// domainIdempotencyRecord converts a generated idempotency_keys row into a domain entity.
+func domainIdempotencyRecord(row *sqlitedb.IdempotencyKeys) (*entities.IdempotencyRecord, error) {
+	completedAt, err := nullTime.DBToDomain(row.CompletedAt)
+	if err != nil {
+		return nil, fmt.Errorf("key=%v: %w", row.IdempotencyKey, err)
+	}
+
+	return &entities.IdempotencyRecord{
+		Key:         entities.IdempotencyKey(row.IdempotencyKey),
+		Operation:   row.Operation,
+		RequestHash: row.RequestHash,
+		Response:    []byte(row.Response),
+		CreatedAt:   row.CreatedAt,
+		CompletedAt: completedAt,
+		ExpiresAt:   row.ExpiresAt,
+	}, nil
+}
+
+// handleIdempotencyError maps database errors for idempotency queries to domain errors.
+func handleIdempotencyError(err error, operation string) error {
+	return sqlitedb.HandleDBError(
+		err,
+		operation,
+		entities.ErrIdempotencyKeyNotFound,
+		entities.ErrIdempotencyKeyExists,
+		sqlitedb.IsSQLiteUniqueConstraintError,
+	)
+}
@@ -0,0 +1,22 @@
+package sqlite
+
+import (
+	"github.com/LarsArtmann/template-sqlc/internal/adapters"
+	"github.com/LarsArtmann/template-sqlc/internal/db/shared"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// IdentityRepository implements IdentityRepository for SQLite.
+type IdentityRepository struct {
+	*adapters.NotImplementedIdentityRepository
+
+	db shared.DBTX
+}
+
+// NewIdentityRepository creates a new SQLite identity repository.
+func NewIdentityRepository(db shared.DBTX) repositories.IdentityRepository {
+	return &IdentityRepository{
+		NotImplementedIdentityRepository: adapters.NewNotImplementedIdentityRepository("SQLite"),
+		db:                               db,
+	}
+}
@@ -0,0 +1,22 @@
+package sqlite
+
+import (
+	"github.com/LarsArtmann/template-sqlc/internal/adapters"
+	"github.com/LarsArtmann/template-sqlc/internal/db/shared"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// JobRepository implements JobRepository for SQLite.
+type JobRepository struct {
+	*adapters.NotImplementedJobRepository
+
+	db shared.DBTX
+}
+
+// NewJobRepository creates a new SQLite job repository.
+func NewJobRepository(db shared.DBTX) repositories.JobRepository {
+	return &JobRepository{
+		NotImplementedJobRepository: adapters.NewNotImplementedJobRepository("SQLite"),
+		db:                          db,
+	}
+}
@@ -0,0 +1,22 @@
+package sqlite
+
+import (
+	"github.com/LarsArtmann/template-sqlc/internal/adapters"
+	"github.com/LarsArtmann/template-sqlc/internal/db/shared"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// LoginHistoryRepository implements LoginHistoryRepository for SQLite.
+type LoginHistoryRepository struct {
+	*adapters.NotImplementedLoginHistoryRepository
+
+	db shared.DBTX
+}
+
+// NewLoginHistoryRepository creates a new SQLite login history repository.
+func NewLoginHistoryRepository(db shared.DBTX) repositories.LoginHistoryRepository {
+	return &LoginHistoryRepository{
+		NotImplementedLoginHistoryRepository: adapters.NewNotImplementedLoginHistoryRepository("SQLite"),
+		db:                                   db,
+	}
+}
@@ -0,0 +1,22 @@
+package sqlite
+
+import (
+	"github.com/LarsArtmann/template-sqlc/internal/adapters"
+	"github.com/LarsArtmann/template-sqlc/internal/db/shared"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// ModerationRepository implements ModerationRepository for SQLite.
+type ModerationRepository struct {
+	*adapters.NotImplementedModerationRepository
+
+	db shared.DBTX
+}
+
+// NewModerationRepository creates a new SQLite moderation repository.
+func NewModerationRepository(db shared.DBTX) repositories.ModerationRepository {
+	return &ModerationRepository{
+		NotImplementedModerationRepository: adapters.NewNotImplementedModerationRepository("SQLite"),
+		db:                                 db,
+	}
+}
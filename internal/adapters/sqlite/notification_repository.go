@@ -0,0 +1,24 @@
+package sqlite
+
+import (
+	"github.com/LarsArtmann/template-sqlc/internal/adapters"
+	"github.com/LarsArtmann/template-sqlc/internal/db/shared"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// NotificationPreferenceRepository implements NotificationPreferenceRepository for SQLite.
+type NotificationPreferenceRepository struct {
+	*adapters.NotImplementedNotificationPreferenceRepository
+
+	db shared.DBTX
+}
+
+// NewNotificationPreferenceRepository creates a new SQLite notification preference repository.
+func NewNotificationPreferenceRepository(db shared.DBTX) repositories.NotificationPreferenceRepository {
+	return &NotificationPreferenceRepository{
+		NotImplementedNotificationPreferenceRepository: adapters.NewNotImplementedNotificationPreferenceRepository(
+			"SQLite",
+		),
+		db: db,
+	}
+}
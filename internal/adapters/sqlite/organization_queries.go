@@ -0,0 +1,324 @@
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/converters"
+	sqlitedb "github.com/LarsArtmann/template-sqlc/internal/db/sqlite"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/google/uuid"
+)
+
+// queries returns the sqlc queries bound to the repository's database handle.
+func (r *OrganizationRepository) queries() *sqlitedb.Queries {
+	return sqlitedb.New(r.db)
+}
+
+// organizationTable maps organizations to their generated queries.
+func organizationTable() *Table[entities.Organization, entities.OrganizationID, sqlitedb.Organizations] {
+	return &Table[entities.Organization, entities.OrganizationID, sqlitedb.Organizations]{
+		Name:     "organization",
+		NotFound: entities.ErrOrganizationNotFound,
+		Conflict: entities.ErrOrganizationAlreadyExists,
+		ID:       (*entities.Organization).ID,
+		SetID:    (*entities.Organization).SetID,
+		ToDomain: domainOrganization,
+		Insert: func(ctx context.Context, q *sqlitedb.Queries, org *entities.Organization) (int64, error) {
+			return q.CreateOrganization(ctx, &sqlitedb.CreateOrganizationParams{
+				UUID:      org.UUID().String(),
+				Name:      org.Name().String(),
+				Slug:      org.Slug().String(),
+				CreatedAt: org.CreatedAt(),
+				UpdatedAt: org.UpdatedAt(),
+			})
+		},
+		Select: func(ctx context.Context, q *sqlitedb.Queries, id int64) (*sqlitedb.Organizations, error) {
+			return q.GetOrganizationByID(ctx, id)
+		},
+		Update: func(ctx context.Context, q *sqlitedb.Queries, org *entities.Organization) (int64, error) {
+			return q.UpdateOrganization(ctx, &sqlitedb.UpdateOrganizationParams{
+				Name:      org.Name().String(),
+				Slug:      org.Slug().String(),
+				UpdatedAt: org.UpdatedAt(),
+				ID:        int64(org.ID()),
+			})
+		},
+		Delete: func(ctx context.Context, q *sqlitedb.Queries, id int64) (int64, error) {
+			return q.DeleteOrganization(ctx, id)
+		},
+		List: func(ctx context.Context, q *sqlitedb.Queries, limit, offset int64) ([]*sqlitedb.Organizations, error) {
+			return q.ListOrganizations(ctx, &sqlitedb.ListOrganizationsParams{RowOffset: offset, RowLimit: limit})
+		},
+	}
+}
+
+// base returns the CRUD implementation of the repository.
+func (r *OrganizationRepository) base() *BaseRepository[
+	entities.Organization, entities.OrganizationID, sqlitedb.Organizations,
+] {
+	return NewBaseRepository(r.db, organizationTable())
+}
+
+// Create inserts a new organization and assigns the generated ID.
+func (r *OrganizationRepository) Create(ctx context.Context, org *entities.Organization) error {
+	return r.base().Create(ctx, org)
+}
+
+// GetByID retrieves an organization by ID.
+func (r *OrganizationRepository) GetByID(
+	ctx context.Context,
+	id entities.OrganizationID,
+) (*entities.Organization, error) {
+	return r.base().GetByID(ctx, id)
+}
+
+// Update persists the organization's name and slug.
+func (r *OrganizationRepository) Update(ctx context.Context, org *entities.Organization) error {
+	return r.base().Update(ctx, org)
+}
+
+// Delete removes an organization with its memberships.
+func (r *OrganizationRepository) Delete(ctx context.Context, id entities.OrganizationID) error {
+	return r.base().Delete(ctx, id)
+}
+
+// List returns organizations ordered by ID.
+func (r *OrganizationRepository) List(ctx context.Context, limit, offset int) ([]*entities.Organization, error) {
+	return r.base().List(ctx, limit, offset)
+}
+
+// GetBySlug retrieves an organization by slug.
+func (r *OrganizationRepository) GetBySlug(
+	ctx context.Context,
+	slug entities.OrganizationSlug,
+) (*entities.Organization, error) {
+	row, err := r.queries().GetOrganizationBySlug(ctx, slug.String())
+	if err != nil {
+		return nil, fmt.Errorf("slug=%v: %w", slug, handleOrganizationError(err, "get organization"))
+	}
+
+	return domainOrganization(row)
+}
+
+// ListByUser returns the organizations a user is an active member of.
+func (r *OrganizationRepository) ListByUser(
+	ctx context.Context,
+	userID entities.UserID,
+	limit int,
+) ([]*entities.Organization, error) {
+	rows, err := r.queries().ListOrganizationsByUser(ctx, &sqlitedb.ListOrganizationsByUserParams{
+		UserID:   int64(userID),
+		RowLimit: int64(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("user=%v: %w", userID, handleOrganizationError(err, "list organizations"))
+	}
+
+	return r.base().domainRows(rows)
+}
+
+// queries returns the sqlc queries bound to the repository's database handle.
+func (r *MembershipRepository) queries() *sqlitedb.Queries {
+	return sqlitedb.New(r.db)
+}
+
+// Create inserts a membership or invitation.
+func (r *MembershipRepository) Create(ctx context.Context, membership *entities.Membership) error {
+	err := r.queries().CreateMembership(ctx, &sqlitedb.CreateMembershipParams{
+		OrganizationID: int64(membership.OrganizationID()),
+		UserID:         int64(membership.UserID()),
+		Role:           membership.Role().String(),
+		Status:         membership.Status().String(),
+		InvitedBy:      sql.NullInt64{Int64: int64(membership.InvitedBy()), Valid: membership.InvitedBy() != 0},
+		InvitedAt:      membership.InvitedAt(),
+		JoinedAt:       nullTime.DomainToDB(membership.JoinedAt()),
+	})
+	if err != nil {
+		return fmt.Errorf(
+			"create membership org=%v user=%v: %w",
+			membership.OrganizationID(),
+			membership.UserID(),
+			handleMembershipError(err, "create membership"),
+		)
+	}
+
+	return nil
+}
+
+// Get retrieves the membership of a user in an organization.
+func (r *MembershipRepository) Get(
+	ctx context.Context,
+	orgID entities.OrganizationID,
+	userID entities.UserID,
+) (*entities.Membership, error) {
+	row, err := r.queries().GetMembership(ctx, &sqlitedb.GetMembershipParams{
+		OrganizationID: int64(orgID),
+		UserID:         int64(userID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("org=%v user=%v: %w", orgID, userID, handleMembershipError(err, "get membership"))
+	}
+
+	return domainMembership(row)
+}
+
+// Update persists the member's role, status and join time.
+func (r *MembershipRepository) Update(ctx context.Context, membership *entities.Membership) error {
+	affected, err := r.queries().UpdateMembership(ctx, &sqlitedb.UpdateMembershipParams{
+		Role:           membership.Role().String(),
+		Status:         membership.Status().String(),
+		JoinedAt:       nullTime.DomainToDB(membership.JoinedAt()),
+		OrganizationID: int64(membership.OrganizationID()),
+		UserID:         int64(membership.UserID()),
+	})
+	if err != nil {
+		return fmt.Errorf(
+			"update membership org=%v user=%v: %w",
+			membership.OrganizationID(),
+			membership.UserID(),
+			handleMembershipError(err, "update membership"),
+		)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf(
+			"update membership org=%v user=%v: %w",
+			membership.OrganizationID(),
+			membership.UserID(),
+			entities.ErrMembershipNotFound,
+		)
+	}
+
+	return nil
+}
+
+// Delete removes a membership or invitation.
+func (r *MembershipRepository) Delete(
+	ctx context.Context,
+	orgID entities.OrganizationID,
+	userID entities.UserID,
+) error {
+	affected, err := r.queries().DeleteMembership(ctx, &sqlitedb.DeleteMembershipParams{
+		OrganizationID: int64(orgID),
+		UserID:         int64(userID),
+	})
+	if err != nil {
+		return fmt.Errorf("delete membership org=%v user=%v: %w", orgID, userID, handleMembershipError(err, "delete membership"))
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("delete membership org=%v user=%v: %w", orgID, userID, entities.ErrMembershipNotFound)
+	}
+
+	return nil
+}
+
+// ListByOrganization returns the members of an organization, including pending invitations.
+func (r *MembershipRepository) ListByOrganization(
+	ctx context.Context,
+	orgID entities.OrganizationID,
+	limit, offset int,
+) ([]*entities.Membership, error) {
+	rows, err := r.queries().ListMemberships(ctx, &sqlitedb.ListMembershipsParams{
+		OrganizationID: int64(orgID),
+		RowLimit:       int64(limit),
+		RowOffset:      int64(offset),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("org=%v: %w", orgID, handleMembershipError(err, "list memberships"))
+	}
+
+	return scanAll[*entities.Membership](rows)
+}
+
+// CountAdmins returns the number of active admins of an organization.
+func (r *MembershipRepository) CountAdmins(ctx context.Context, orgID entities.OrganizationID) (int64, error) {
+	count, err := r.queries().CountOrganizationAdmins(ctx, int64(orgID))
+	if err != nil {
+		return 0, fmt.Errorf("org=%v: %w", orgID, handleMembershipError(err, "count admins"))
+	}
+
+	return count, nil
+}
+
+// RoleInOrganization returns the role of an active member.
+func (r *MembershipRepository) RoleInOrganization(
+	ctx context.Context,
+	userID entities.UserID,
+	orgID entities.OrganizationID,
+) (entities.UserRole, error) {
+	membership, err := r.Get(ctx, orgID, userID)
+	if err != nil {
+		return "", err
+	}
+
+	if !membership.IsActive() {
+		return "", fmt.Errorf("org=%v user=%v: %w", orgID, userID, entities.ErrMembershipNotFound)
+	}
+
+	return membership.Role(), nil
+}
+
+// domainOrganization converts a generated organizations row into a domain entity.
+func domainOrganization(row *sqlitedb.Organizations) (*entities.Organization, error) {
+	orgUUID, err := uuid.Parse(row.UUID)
+	if err != nil {
+		return nil, fmt.Errorf("organization id=%d uuid=%v: %w", row.ID, row.UUID, err)
+	}
+
+	return entities.ReconstructOrganization(entities.OrganizationRecord{
+		ID:        entities.OrganizationID(row.ID),
+		UUID:      orgUUID,
+		Name:      entities.OrganizationName(row.Name),
+		Slug:      entities.OrganizationSlug(row.Slug),
+		CreatedAt: row.CreatedAt,
+		UpdatedAt: row.UpdatedAt,
+	}), nil
+}
+
+// domainMembership converts a generated organization_members row into a domain entity.
+func domainMembership(row *sqlitedb.OrganizationMembers) (*entities.Membership, error) {
+	joinedAt, err := nullTime.DBToDomain(row.JoinedAt)
+	if err != nil {
+		return nil, fmt.Errorf("membership org=%d user=%d: %w", row.OrganizationID, row.UserID, err)
+	}
+
+	return entities.ReconstructMembership(entities.MembershipRecord{
+		OrganizationID: entities.OrganizationID(row.OrganizationID),
+		UserID:         entities.UserID(row.UserID),
+		Role:           entities.UserRole(row.Role),
+		Status:         entities.MembershipStatus(row.Status),
+		InvitedBy:      entities.UserID(row.InvitedBy.Int64),
+		InvitedAt:      row.InvitedAt,
+		JoinedAt:       joinedAt,
+	})
+}
+
+// nullTime converts optional times to and from nullable DATETIME columns.
+var nullTime = converters.NewNullTimeConverter()
+
+// handleOrganizationError maps database errors for organization queries to domain errors.
+func handleOrganizationError(err error, operation string) error {
+	return sqlitedb.HandleDBError(
+		err,
+		operation,
+		entities.ErrOrganizationNotFound,
+		entities.ErrOrganizationAlreadyExists,
+		sqlitedb.IsSQLiteUniqueConstraintError,
+	)
+}
+
+// handleMembershipError maps database errors for membership queries to domain errors.
+func handleMembershipError(err error, operation string) error {
+	return sqlitedb.HandleDBError(
+		err,
+		operation,
+		entities.ErrMembershipNotFound,
+		entities.ErrMembershipAlreadyExists,
+		sqlitedb.IsSQLiteUniqueConstraintError,
+	)
+}
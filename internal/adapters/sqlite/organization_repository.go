@@ -0,0 +1,37 @@
+package sqlite
+
+import (
+	"github.com/LarsArtmann/template-sqlc/internal/adapters"
+	"github.com/LarsArtmann/template-sqlc/internal/db/shared"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// OrganizationRepository implements OrganizationRepository for SQLite.
+type OrganizationRepository struct {
+	*adapters.NotImplementedOrganizationRepository
+
+	db shared.DBTX
+}
+
+// NewOrganizationRepository creates a new SQLite organization repository.
+func NewOrganizationRepository(db shared.DBTX) repositories.OrganizationRepository {
+	return &OrganizationRepository{
+		NotImplementedOrganizationRepository: adapters.NewNotImplementedOrganizationRepository("SQLite"),
+		db:                                   db,
+	}
+}
+
+// MembershipRepository implements MembershipRepository for SQLite.
+type MembershipRepository struct {
+	*adapters.NotImplementedMembershipRepository
+
+	db shared.DBTX
+}
+
+// NewMembershipRepository creates a new SQLite membership repository.
+func NewMembershipRepository(db shared.DBTX) repositories.MembershipRepository {
+	return &MembershipRepository{
+		NotImplementedMembershipRepository: adapters.NewNotImplementedMembershipRepository("SQLite"),
+		db:                                 db,
+	}
+}
@@ -0,0 +1,33 @@
+//go:build sqlite
+
+package sqlite
+
+import (
+	"sync"
+
+	"github.com/LarsArtmann/template-sqlc/pkg/scan"
+)
+
+// registerRowMappers registers the SQLite row mappers with the default scan
+// registry. It runs once, before the first rows are mapped.
+var registerRowMappers = sync.OnceFunc(func() {
+	scan.Register(domainUser)
+	scan.Register(domainIdentity)
+	scan.Register(domainIdentityChange)
+	scan.Register(domainIdempotencyRecord)
+	scan.Register(domainJob)
+	scan.Register(domainOrganization)
+	scan.Register(domainMembership)
+	scan.Register(domainUserPreferences)
+	scan.Register(domainModerationAction)
+	scan.Register(domainAppeal)
+	scan.Register(domainAccountDeletion)
+	scan.Register(domainSession)
+})
+
+// scanAll converts generated rows into entities with their registered mapper.
+func scanAll[Entity, Row any](rows []*Row) ([]Entity, error) {
+	registerRowMappers()
+
+	return scan.All[Entity](rows)
+}
@@ -0,0 +1,381 @@
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters"
+	sqlitedb "github.com/LarsArtmann/template-sqlc/internal/db/sqlite"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/google/uuid"
+)
+
+// queries returns the sqlc queries bound to the repository's database handle.
+func (r *SessionRepository) queries() *sqlitedb.Queries {
+	return sqlitedb.New(r.db)
+}
+
+// Create stores a new session, assigns the generated ID and indexes its
+// refresh tokens.
+func (r *SessionRepository) Create(ctx context.Context, session *entities.UserSession) error {
+	record := session.Record()
+
+	deviceInfo, rotated, err := adapters.EncodeSessionDocuments(record)
+	if err != nil {
+		return err
+	}
+
+	id, err := r.queries().CreateSession(ctx, &sqlitedb.CreateSessionParams{
+		UserID:               int64(record.UserID),
+		SessionToken:         record.Token.String(),
+		DeviceInfo:           string(deviceInfo),
+		IpAddress:            adapters.SessionIPAddress(record.IPAddress),
+		UserAgent:            record.UserAgent,
+		Country:              record.Location.Country,
+		Region:               record.Location.Region,
+		City:                 record.Location.City,
+		OrganizationID:       nullSessionOrganization(record.OrganizationID),
+		ImpersonatorID:       nullSessionImpersonator(record.ImpersonatorID),
+		RefreshToken:         record.RefreshToken.String(),
+		RefreshExpiresAt:     nullTime.DomainToDB(adapters.SessionRefreshExpiresAt(record)),
+		RotatedRefreshTokens: string(rotated),
+		CreatedAt:            record.CreatedAt.UTC(),
+		ExpiresAt:            record.ExpiresAt.UTC(),
+		IsActive:             record.IsActive,
+	})
+	if err != nil {
+		return fmt.Errorf("user=%v: %w", record.UserID, handleSessionError(err, "create session"))
+	}
+
+	session.SetID(entities.SessionID(id))
+
+	return r.indexRefreshTokens(ctx, session.ID(), record)
+}
+
+// GetByToken retrieves the session with token.
+func (r *SessionRepository) GetByToken(
+	ctx context.Context,
+	token entities.SessionToken,
+) (*entities.UserSession, error) {
+	row, err := r.queries().GetSessionByToken(ctx, token.String())
+	if err != nil {
+		return nil, handleSessionError(err, "get session")
+	}
+
+	return domainSession(row)
+}
+
+// GetByRefreshToken retrieves the session whose current or rotated refresh
+// token is token.
+func (r *SessionRepository) GetByRefreshToken(
+	ctx context.Context,
+	token entities.RefreshToken,
+) (*entities.UserSession, error) {
+	row, err := r.queries().GetSessionByRefreshToken(ctx, token.String())
+	if err != nil {
+		return nil, handleSessionError(err, "get session by refresh token")
+	}
+
+	return domainSession(row)
+}
+
+// GetByUserID returns the sessions of a user, newest first.
+func (r *SessionRepository) GetByUserID(
+	ctx context.Context,
+	userID entities.UserID,
+	activeOnly bool,
+) ([]*entities.UserSession, error) {
+	list := r.queries().ListSessionsByUser
+	if activeOnly {
+		list = r.queries().ListActiveSessionsByUser
+	}
+
+	rows, err := list(ctx, int64(userID))
+	if err != nil {
+		return nil, fmt.Errorf("user=%v: %w", userID, handleSessionError(err, "list sessions"))
+	}
+
+	return scanAll[*entities.UserSession](rows)
+}
+
+// Update stores the mutable state of a session and indexes refresh tokens
+// issued since it was stored.
+func (r *SessionRepository) Update(ctx context.Context, session *entities.UserSession) error {
+	record := session.Record()
+
+	params, err := updateSessionParams(record)
+	if err != nil {
+		return err
+	}
+
+	affected, err := r.queries().UpdateSession(ctx, params)
+	if err != nil {
+		return fmt.Errorf("update session id=%v: %w", record.ID, handleSessionError(err, "update session"))
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("update session id=%v: %w", record.ID, entities.ErrSessionNotFound)
+	}
+
+	return r.indexRefreshTokens(ctx, record.ID, record)
+}
+
+// UpdateIfCurrent stores the mutable state of a session like Update,
+// provided the stored session is still active with the expected refresh
+// token.
+func (r *SessionRepository) UpdateIfCurrent(
+	ctx context.Context,
+	session *entities.UserSession,
+	expected entities.RefreshToken,
+) error {
+	record := session.Record()
+
+	params, err := updateSessionParams(record)
+	if err != nil {
+		return err
+	}
+
+	affected, err := r.queries().UpdateSessionIfCurrent(ctx, &sqlitedb.UpdateSessionIfCurrentParams{
+		DeviceInfo:           params.DeviceInfo,
+		IpAddress:            params.IpAddress,
+		UserAgent:            params.UserAgent,
+		Country:              params.Country,
+		Region:               params.Region,
+		City:                 params.City,
+		OrganizationID:       params.OrganizationID,
+		ImpersonatorID:       params.ImpersonatorID,
+		RefreshToken:         params.RefreshToken,
+		RefreshExpiresAt:     params.RefreshExpiresAt,
+		RotatedRefreshTokens: params.RotatedRefreshTokens,
+		ExpiresAt:            params.ExpiresAt,
+		IsActive:             params.IsActive,
+		ID:                   params.ID,
+		ExpectedRefreshToken: expected.String(),
+	})
+	if err != nil {
+		return fmt.Errorf("update session id=%v: %w", record.ID, handleSessionError(err, "update session"))
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("update session id=%v: %w", record.ID, entities.ErrSessionConflict)
+	}
+
+	return r.indexRefreshTokens(ctx, record.ID, record)
+}
+
+// Delete removes the session with id.
+func (r *SessionRepository) Delete(ctx context.Context, id entities.SessionID) error {
+	affected, err := r.queries().DeleteSession(ctx, id.Int64())
+	if err != nil {
+		return fmt.Errorf("delete session id=%v: %w", id, handleSessionError(err, "delete session"))
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("delete session id=%v: %w", id, entities.ErrSessionNotFound)
+	}
+
+	return nil
+}
+
+// DeactivateByToken deactivates the session with token.
+func (r *SessionRepository) DeactivateByToken(ctx context.Context, token entities.SessionToken) error {
+	affected, err := r.queries().DeactivateSessionByToken(ctx, token.String())
+	if err != nil {
+		return fmt.Errorf("deactivate session token=%v: %w", token,
+			handleSessionError(err, "deactivate session"))
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("deactivate session token=%v: %w", token, entities.ErrSessionNotFound)
+	}
+
+	return nil
+}
+
+// DeactivateByUserID deactivates every session of a user.
+func (r *SessionRepository) DeactivateByUserID(ctx context.Context, userID entities.UserID) error {
+	err := r.queries().DeactivateSessionsByUser(ctx, int64(userID))
+	if err != nil {
+		return fmt.Errorf("user=%v: %w", userID, handleSessionError(err, "deactivate sessions"))
+	}
+
+	return nil
+}
+
+// CleanupExpired removes the expired sessions.
+func (r *SessionRepository) CleanupExpired(ctx context.Context) (int64, error) {
+	removed, err := r.queries().DeleteExpiredSessions(ctx, time.Now().UTC())
+	if err != nil {
+		return 0, handleSessionError(err, "delete expired sessions")
+	}
+
+	return removed, nil
+}
+
+// GetActiveSessions counts the active, unexpired sessions of a user.
+func (r *SessionRepository) GetActiveSessions(ctx context.Context, userID entities.UserID) (int64, error) {
+	count, err := r.queries().CountActiveSessions(ctx, &sqlitedb.CountActiveSessionsParams{
+		UserID: int64(userID),
+		Now:    time.Now().UTC(),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("user=%v: %w", userID, handleSessionError(err, "count active sessions"))
+	}
+
+	return count, nil
+}
+
+// GetSessionStats returns aggregate session statistics.
+func (r *SessionRepository) GetSessionStats(ctx context.Context) (*entities.SessionStats, error) {
+	now := time.Now().UTC()
+	dayAgo, weekAgo, monthAgo := adapters.SessionStatsWindows(now)
+
+	row, err := r.queries().GetSessionStats(ctx, &sqlitedb.GetSessionStatsParams{
+		Now:      now,
+		DayAgo:   dayAgo,
+		WeekAgo:  weekAgo,
+		MonthAgo: monthAgo,
+	})
+	if err != nil {
+		return nil, handleSessionError(err, "get session stats")
+	}
+
+	return &entities.SessionStats{
+		TotalSessions:   row.TotalSessions,
+		ActiveSessions:  row.ActiveSessions,
+		ExpiredSessions: row.ExpiredSessions,
+		Sessions24h:     row.Sessions24h,
+		Sessions7d:      row.Sessions7d,
+		Sessions30d:     row.Sessions30d,
+	}, nil
+}
+
+// indexRefreshTokens records the refresh tokens of a session for
+// GetByRefreshToken. Tokens indexed before are skipped by the query.
+func (r *SessionRepository) indexRefreshTokens(
+	ctx context.Context,
+	id entities.SessionID,
+	record entities.SessionRecord,
+) error {
+	for _, token := range adapters.SessionRefreshTokens(record) {
+		err := r.queries().AddSessionRefreshToken(ctx, &sqlitedb.AddSessionRefreshTokenParams{
+			RefreshToken: token.String(),
+			SessionID:    id.Int64(),
+		})
+		if err != nil {
+			return fmt.Errorf("session id=%v: %w", id, handleSessionError(err, "index refresh token"))
+		}
+	}
+
+	return nil
+}
+
+// updateSessionParams converts the mutable state of a session into the
+// parameters of an update.
+func updateSessionParams(record entities.SessionRecord) (*sqlitedb.UpdateSessionParams, error) {
+	deviceInfo, rotated, err := adapters.EncodeSessionDocuments(record)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sqlitedb.UpdateSessionParams{
+		DeviceInfo:           string(deviceInfo),
+		IpAddress:            adapters.SessionIPAddress(record.IPAddress),
+		UserAgent:            record.UserAgent,
+		Country:              record.Location.Country,
+		Region:               record.Location.Region,
+		City:                 record.Location.City,
+		OrganizationID:       nullSessionOrganization(record.OrganizationID),
+		ImpersonatorID:       nullSessionImpersonator(record.ImpersonatorID),
+		RefreshToken:         record.RefreshToken.String(),
+		RefreshExpiresAt:     nullTime.DomainToDB(adapters.SessionRefreshExpiresAt(record)),
+		RotatedRefreshTokens: string(rotated),
+		ExpiresAt:            record.ExpiresAt.UTC(),
+		IsActive:             record.IsActive,
+		ID:                   record.ID.Int64(),
+	}, nil
+}
+
+// domainSession converts a generated user_sessions row into a domain entity.
+func domainSession(row *sqlitedb.UserSessions) (*entities.UserSession, error) {
+	token, err := uuid.Parse(row.SessionToken)
+	if err != nil {
+		return nil, fmt.Errorf("session id=%d: %w", row.ID, err)
+	}
+
+	refreshToken, err := uuid.Parse(row.RefreshToken)
+	if err != nil {
+		return nil, fmt.Errorf("session id=%d: %w", row.ID, err)
+	}
+
+	record := entities.SessionRecord{
+		ID:           entities.SessionID(row.ID),
+		UserID:       entities.UserID(row.UserID),
+		Token:        entities.SessionToken(token),
+		IPAddress:    net.ParseIP(row.IpAddress),
+		UserAgent:    row.UserAgent,
+		CreatedAt:    row.CreatedAt,
+		ExpiresAt:    row.ExpiresAt,
+		IsActive:     row.IsActive,
+		Location:     entities.GeoLocation{Country: row.Country, Region: row.Region, City: row.City},
+		RefreshToken: entities.RefreshToken(refreshToken),
+	}
+
+	if row.OrganizationID.Valid {
+		organizationID := entities.OrganizationID(row.OrganizationID.Int64)
+		record.OrganizationID = &organizationID
+	}
+
+	if row.ImpersonatorID.Valid {
+		impersonatorID := entities.UserID(row.ImpersonatorID.Int64)
+		record.ImpersonatorID = &impersonatorID
+	}
+
+	if row.RefreshExpiresAt.Valid {
+		record.RefreshExpiresAt = row.RefreshExpiresAt.Time
+	}
+
+	err = adapters.DecodeSessionDocuments(&record, []byte(row.DeviceInfo), []byte(row.RotatedRefreshTokens))
+	if err != nil {
+		return nil, err
+	}
+
+	return entities.ReconstructSession(record)
+}
+
+// nullSessionOrganization converts the optional organization of a session
+// into a nullable INTEGER.
+func nullSessionOrganization(id *entities.OrganizationID) sql.NullInt64 {
+	if id == nil {
+		return sql.NullInt64{}
+	}
+
+	return sql.NullInt64{Int64: int64(*id), Valid: true}
+}
+
+// nullSessionImpersonator converts the optional impersonator of a session
+// into a nullable INTEGER.
+func nullSessionImpersonator(id *entities.UserID) sql.NullInt64 {
+	if id == nil {
+		return sql.NullInt64{}
+	}
+
+	return sql.NullInt64{Int64: int64(*id), Valid: true}
+}
+
+// handleSessionError maps database errors for session queries to domain
+// errors. The only unique constraints are on the random tokens.
+func handleSessionError(err error, operation string) error {
+	return sqlitedb.HandleDBError(
+		err,
+		operation,
+		entities.ErrSessionNotFound,
+		entities.ErrInvalidSessionToken,
+		sqlitedb.IsSQLiteUniqueConstraintError,
+	)
+}
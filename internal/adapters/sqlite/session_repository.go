@@ -3,7 +3,6 @@ package sqlite
 
 import (
 	"github.com/LarsArtmann/template-sqlc/internal/adapters"
-	"github.com/LarsArtmann/template-sqlc/internal/adapters/converters"
 	"github.com/LarsArtmann/template-sqlc/internal/db/shared"
 	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
 )
@@ -12,8 +11,7 @@ import (
 type SessionRepository struct {
 	*adapters.NotImplementedSessionRepository
 
-	db         shared.DBTX
-	converters *converters.ConverterSet
+	db shared.DBTX
 }
 
 // NewSessionRepository creates a new SQLite session repository.
@@ -21,6 +19,5 @@ func NewSessionRepository(db shared.DBTX) repositories.SessionRepository {
 	return &SessionRepository{
 		NotImplementedSessionRepository: adapters.NewNotImplementedSessionRepository("SQLite"),
 		db:                              db,
-		converters:                      converters.NewConverterSet(converters.DbTypeSQLite),
 	}
 }
@@ -0,0 +1,99 @@
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters"
+	"github.com/LarsArtmann/template-sqlc/internal/db/shared"
+	sqlitedb "github.com/LarsArtmann/template-sqlc/internal/db/sqlite"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+)
+
+// CreateBatch inserts the users in one transaction. SQLite inserts are cheap
+// once the per-statement commit is gone, so rows are inserted one by one.
+func (r *UserRepository) CreateBatch(ctx context.Context, users []*entities.User) error {
+	if len(users) == 0 {
+		return nil
+	}
+
+	return r.InTx(ctx, func(db shared.DBTX) error {
+		queries := sqlitedb.New(db)
+
+		for _, user := range users {
+			err := createUser(ctx, queries, user)
+			if err != nil {
+				return fmt.Errorf("create batch of %d: %w", len(users), err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// UpdateStatusBatch changes the statuses in one transaction.
+func (r *UserRepository) UpdateStatusBatch(
+	ctx context.Context,
+	ids []entities.UserID,
+	status entities.UserStatus,
+) (int64, error) {
+	if !status.IsValid() {
+		return 0, fmt.Errorf("status=%v: %w", status, entities.ErrInvalidUserStatus)
+	}
+
+	return r.batchByIDs(ctx, ids, "update user statuses", func(queries *sqlitedb.Queries, chunk []int64) (int64, error) {
+		return queries.UpdateUsersStatus(ctx, &sqlitedb.UpdateUsersStatusParams{
+			Status: string(status),
+			Ids:    chunk,
+		})
+	})
+}
+
+// DeleteBatch soft deletes the users in one transaction.
+func (r *UserRepository) DeleteBatch(ctx context.Context, ids []entities.UserID) (int64, error) {
+	return r.batchByIDs(ctx, ids, "delete users", func(queries *sqlitedb.Queries, chunk []int64) (int64, error) {
+		return queries.SoftDeleteUsers(ctx, chunk)
+	})
+}
+
+// batchByIDs runs update for chunks of ids in one transaction and sums the affected rows.
+func (r *UserRepository) batchByIDs(
+	ctx context.Context,
+	ids []entities.UserID,
+	op string,
+	update func(queries *sqlitedb.Queries, chunk []int64) (int64, error),
+) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	keys := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		keys = append(keys, int64(id))
+	}
+
+	var affected int64
+
+	err := r.InTx(ctx, func(db shared.DBTX) error {
+		queries := sqlitedb.New(db)
+
+		for chunk := range slices.Chunk(keys, adapters.BatchChunkSize) {
+			rows, err := update(queries, chunk)
+			if err != nil {
+				return fmt.Errorf("%s batch of %d: %w", op, len(ids), handleError(err, op))
+			}
+
+			affected += rows
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return affected, nil
+}
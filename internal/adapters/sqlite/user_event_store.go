@@ -0,0 +1,22 @@
+package sqlite
+
+import (
+	"github.com/LarsArtmann/template-sqlc/internal/adapters"
+	"github.com/LarsArtmann/template-sqlc/internal/db/shared"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// UserEventStore implements UserEventStore for SQLite.
+type UserEventStore struct {
+	*adapters.NotImplementedUserEventStore
+
+	db shared.DBTX
+}
+
+// NewUserEventStore creates a new SQLite user event store.
+func NewUserEventStore(db shared.DBTX) repositories.UserEventStore {
+	return &UserEventStore{
+		NotImplementedUserEventStore: adapters.NewNotImplementedUserEventStore("SQLite"),
+		db:                           db,
+	}
+}
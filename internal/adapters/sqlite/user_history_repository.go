@@ -0,0 +1,23 @@
+package sqlite
+
+import (
+	"github.com/LarsArtmann/template-sqlc/internal/adapters"
+	"github.com/LarsArtmann/template-sqlc/internal/db/shared"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// UserHistoryRepository implements UserHistoryRepository for SQLite. The
+// versions are archived by the triggers of the users table.
+type UserHistoryRepository struct {
+	*adapters.NotImplementedUserHistoryRepository
+
+	db shared.DBTX
+}
+
+// NewUserHistoryRepository creates a new SQLite user history repository.
+func NewUserHistoryRepository(db shared.DBTX) repositories.UserHistoryRepository {
+	return &UserHistoryRepository{
+		NotImplementedUserHistoryRepository: adapters.NewNotImplementedUserHistoryRepository("SQLite"),
+		db:                                  db,
+	}
+}
@@ -0,0 +1,91 @@
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	sqlitedb "github.com/LarsArtmann/template-sqlc/internal/db/sqlite"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	apperrors "github.com/LarsArtmann/template-sqlc/pkg/errors"
+)
+
+// queries returns the sqlc queries bound to the repository's database handle.
+func (r *UserPreferenceRepository) queries() *sqlitedb.Queries {
+	return sqlitedb.New(r.db)
+}
+
+// Get returns the stored preferences of a user, empty if none were stored.
+func (r *UserPreferenceRepository) Get(ctx context.Context, userID entities.UserID) (*entities.UserPreferences, error) {
+	row, err := r.queries().GetUserPreferences(ctx, int64(userID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return entities.NewUserPreferences(userID, nil, time.Time{}), nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("user=%v: %w", userID, apperrors.NewDatabaseError("get user preferences failed", err))
+	}
+
+	return domainUserPreferences(row)
+}
+
+// Patch merges patch into the stored preferences with json_patch, which
+// removes the keys patched to null.
+func (r *UserPreferenceRepository) Patch(
+	ctx context.Context,
+	userID entities.UserID,
+	patch entities.PreferencePatch,
+) (*entities.UserPreferences, error) {
+	merge := make(map[entities.PreferenceKey]any, len(patch.Set)+len(patch.Unset))
+
+	for key, value := range patch.Set {
+		merge[key] = value
+	}
+
+	for _, key := range patch.Unset {
+		merge[key] = nil
+	}
+
+	doc, err := json.Marshal(merge)
+	if err != nil {
+		return nil, fmt.Errorf("encode preferences user=%v: %w", userID, err)
+	}
+
+	row, err := r.queries().PatchUserPreferences(ctx, &sqlitedb.PatchUserPreferencesParams{
+		UserID:    int64(userID),
+		Patch:     string(doc),
+		UpdatedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("user=%v: %w", userID, apperrors.NewDatabaseError("patch user preferences failed", err))
+	}
+
+	return domainUserPreferences(row)
+}
+
+// Delete removes the preferences of a user; none stored is not an error.
+func (r *UserPreferenceRepository) Delete(ctx context.Context, userID entities.UserID) error {
+	_, err := r.queries().DeleteUserPreferences(ctx, int64(userID))
+	if err != nil {
+		return fmt.Errorf("user=%v: %w", userID, apperrors.NewDatabaseError("delete user preferences failed", err))
+	}
+
+	return nil
+}
+
+// domainUserPreferences converts a generated user_preferences row into a domain entity.
+func domainUserPreferences(row *sqlitedb.UserPreferences) (*entities.UserPreferences, error) {
+	var values map[entities.PreferenceKey]any
+
+	err := json.Unmarshal([]byte(row.Preferences), &values)
+	if err != nil {
+		return nil, fmt.Errorf("decode preferences user=%v: %w", row.UserID, err)
+	}
+
+	return entities.NewUserPreferences(entities.UserID(row.UserID), values, row.UpdatedAt), nil
+}
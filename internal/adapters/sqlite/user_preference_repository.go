@@ -0,0 +1,22 @@
+package sqlite
+
+import (
+	"github.com/LarsArtmann/template-sqlc/internal/adapters"
+	"github.com/LarsArtmann/template-sqlc/internal/db/shared"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// UserPreferenceRepository implements UserPreferenceRepository for SQLite.
+type UserPreferenceRepository struct {
+	*adapters.NotImplementedUserPreferenceRepository
+
+	db shared.DBTX
+}
+
+// NewUserPreferenceRepository creates a new SQLite user preference repository.
+func NewUserPreferenceRepository(db shared.DBTX) repositories.UserPreferenceRepository {
+	return &UserPreferenceRepository{
+		NotImplementedUserPreferenceRepository: adapters.NewNotImplementedUserPreferenceRepository("SQLite"),
+		db:                                     db,
+	}
+}
@@ -0,0 +1,500 @@
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters"
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/mappers"
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/validation"
+	sqlitedb "github.com/LarsArtmann/template-sqlc/internal/db/sqlite"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+)
+
+// queries returns the sqlc queries bound to the repository's database handle.
+func (r *UserRepository) queries() *sqlitedb.Queries {
+	return sqlitedb.New(r.DB())
+}
+
+// Create inserts a new user and assigns the generated ID.
+func (r *UserRepository) Create(ctx context.Context, user *entities.User) error {
+	return createUser(ctx, r.queries(), user)
+}
+
+// createUser inserts user through queries and assigns the generated ID.
+func createUser(ctx context.Context, queries *sqlitedb.Queries, user *entities.User) error {
+	metadata, err := mappers.EncodeMetadata(user.Metadata())
+	if err != nil {
+		return fmt.Errorf("create user email=%v: %w", user.Email(), err)
+	}
+
+	tags, err := mappers.EncodeTags(user.Tags())
+	if err != nil {
+		return fmt.Errorf("create user email=%v: %w", user.Email(), err)
+	}
+
+	created, err := queries.CreateUser(ctx, &sqlitedb.CreateUserParams{
+		UUID:              user.UUID().String(),
+		Email:             user.Email().String(),
+		Username:          user.Username().String(),
+		PasswordHash:      user.PasswordHash().String(),
+		FirstName:         user.FirstName().String(),
+		LastName:          user.LastName().String(),
+		ProfileMetadata:   metadata,
+		IsActive:          sql.NullBool{Bool: true, Valid: true},
+		IsVerified:        sql.NullBool{Bool: user.IsVerified(), Valid: true},
+		Status:            string(user.Status()),
+		Role:              string(user.Role()),
+		Tags:              tags,
+		CreatedAt:         user.CreatedAt().UTC(),
+		UpdatedAt:         user.UpdatedAt().UTC(),
+		UsernameCanonical: user.Username().Canonical(),
+	})
+	if err != nil {
+		return fmt.Errorf("create user email=%v: %w", user.Email(), handleError(err, "create user"))
+	}
+
+	user.SetID(entities.UserID(created.ID))
+
+	return nil
+}
+
+// GetByID retrieves a user by ID.
+func (r *UserRepository) GetByID(ctx context.Context, id entities.UserID) (*entities.User, error) {
+	row, err := r.queries().GetUserByID(ctx, int64(id))
+	if err != nil {
+		return nil, fmt.Errorf("id=%v: %w", id, handleError(err, "get user"))
+	}
+
+	return domainUser(row)
+}
+
+// GetByIDs retrieves the users with the given IDs in one query.
+func (r *UserRepository) GetByIDs(ctx context.Context, ids []entities.UserID) ([]*entities.User, error) {
+	if len(ids) == 0 {
+		return []*entities.User{}, nil
+	}
+
+	keys := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		keys = append(keys, int64(id))
+	}
+
+	rows, err := r.queries().GetUsersByIDs(ctx, keys)
+	if err != nil {
+		return nil, fmt.Errorf("count=%v: %w", len(ids), handleError(err, "get users"))
+	}
+
+	return domainUsers(rows)
+}
+
+// GetByUUID retrieves a user by UUID.
+func (r *UserRepository) GetByUUID(ctx context.Context, uuid entities.UuID) (*entities.User, error) {
+	row, err := r.queries().GetUserByUUID(ctx, string(uuid))
+	if err != nil {
+		return nil, fmt.Errorf("uuid=%v: %w", uuid, handleError(err, "get user"))
+	}
+
+	return domainUser(row)
+}
+
+// GetByEmail retrieves a user by email.
+func (r *UserRepository) GetByEmail(ctx context.Context, email entities.Email) (*entities.User, error) {
+	row, err := r.queries().GetUserByEmail(ctx, email.String())
+	if err != nil {
+		return nil, fmt.Errorf("email=%v: %w", email, handleError(err, "get user"))
+	}
+
+	return domainUser(row)
+}
+
+// GetByUsername retrieves a user by canonical username.
+func (r *UserRepository) GetByUsername(
+	ctx context.Context,
+	username entities.Username,
+) (*entities.User, error) {
+	row, err := r.queries().GetUserByUsername(ctx, username.Canonical())
+	if err != nil {
+		return nil, fmt.Errorf("username=%v: %w", username, handleError(err, "get user"))
+	}
+
+	return domainUser(row)
+}
+
+// Update persists every mutable field of the user.
+func (r *UserRepository) Update(ctx context.Context, user *entities.User) error {
+	metadata, err := mappers.EncodeMetadata(user.Metadata())
+	if err != nil {
+		return fmt.Errorf("update user id=%v: %w", user.ID(), err)
+	}
+
+	tags, err := mappers.EncodeTags(user.Tags())
+	if err != nil {
+		return fmt.Errorf("update user id=%v: %w", user.ID(), err)
+	}
+
+	_, err = r.queries().UpdateUser(ctx, &sqlitedb.UpdateUserParams{
+		Email:             user.Email().String(),
+		Username:          user.Username().String(),
+		FirstName:         user.FirstName().String(),
+		LastName:          user.LastName().String(),
+		ProfileMetadata:   metadata,
+		IsVerified:        sql.NullBool{Bool: user.IsVerified(), Valid: true},
+		Status:            sql.NullString{String: string(user.Status()), Valid: true},
+		Role:              sql.NullString{String: string(user.Role()), Valid: true},
+		Tags:              sql.NullString{String: tags, Valid: true},
+		LastLoginAt:       nullTime.DomainToDB(user.LastLoginAt()),
+		UsernameCanonical: user.Username().Canonical(),
+		ID:                int64(user.ID()),
+	})
+	if err != nil {
+		return fmt.Errorf("update user id=%v: %w", user.ID(), handleError(err, "update user"))
+	}
+
+	return nil
+}
+
+// Delete soft deletes a user.
+func (r *UserRepository) Delete(ctx context.Context, id entities.UserID) error {
+	err := r.queries().SoftDeleteUser(ctx, int64(id))
+	if err != nil {
+		return fmt.Errorf("delete user id=%v: %w", id, handleError(err, "delete user"))
+	}
+
+	return nil
+}
+
+// Restore restores a soft-deleted user.
+func (r *UserRepository) Restore(ctx context.Context, id entities.UserID) error {
+	restored, err := r.queries().RestoreUser(ctx, int64(id))
+	if err != nil {
+		return fmt.Errorf("restore user id=%v: %w", id, handleError(err, "restore user"))
+	}
+
+	if restored == 0 {
+		return fmt.Errorf("restore user id=%v: %w", id, entities.ErrUserNotFound)
+	}
+
+	return nil
+}
+
+// PurgeDeletedOlderThan permanently removes the users soft deleted before cutoff.
+func (r *UserRepository) PurgeDeletedOlderThan(ctx context.Context, cutoff time.Time) (int64, error) {
+	purged, err := r.queries().PurgeDeletedUsers(ctx, sql.NullTime{Time: cutoff.UTC(), Valid: true})
+	if err != nil {
+		return 0, fmt.Errorf("purge users cutoff=%v: %w", cutoff, handleError(err, "purge users"))
+	}
+
+	return purged, nil
+}
+
+// List retrieves a page of the users matching filter, newest first.
+func (r *UserRepository) List(
+	ctx context.Context,
+	filter entities.UserFilter,
+	limit, offset int,
+) ([]*entities.User, error) {
+	err := adapters.ValidateListRequest(filter, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	var first *entities.UserCursor
+
+	return r.filterUsers(ctx, filter, first.Bound(), limit, offset)
+}
+
+// ListPage retrieves a page of users with keyset pagination, newest first.
+func (r *UserRepository) ListPage(
+	ctx context.Context,
+	filter entities.UserFilter,
+	cursor string,
+	limit int,
+) (*entities.UserPage, error) {
+	after, err := adapters.ParsePageRequest(filter, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	// One extra row tells whether another page follows.
+	users, err := r.filterUsers(ctx, filter, after.Bound(), limit+1, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return entities.NewUserPage(users, limit), nil
+}
+
+// filterUsers runs FilterUsers for the users matching filter after bound.
+// Sets are passed as JSON arrays and the query through FTS5.
+func (r *UserRepository) filterUsers(
+	ctx context.Context,
+	filter entities.UserFilter,
+	bound entities.UserCursor,
+	limit, offset int,
+) ([]*entities.User, error) {
+	params := &sqlitedb.FilterUsersParams{
+		Verified:        int64(filter.VerifiedState()),
+		Query:           ftsQuery(filter.Query),
+		CursorCreatedAt: bound.CreatedAt,
+		CursorID:        bound.ID.Int64(),
+		RowOffset:       int64(offset),
+		RowLimit:        int64(limit),
+	}
+
+	params.CreatedAfter, params.CreatedBefore = filter.CreatedRange()
+
+	sets := map[*string][]string{
+		&params.Statuses: filter.StatusValues(),
+		&params.Roles:    filter.RoleValues(),
+		&params.TagsAny:  filter.TagsAny,
+		&params.TagsAll:  filter.TagsAll,
+	}
+
+	for param, values := range sets {
+		encoded, err := mappers.EncodeTags(values)
+		if err != nil {
+			return nil, fmt.Errorf("filter=%+v: %w", filter, err)
+		}
+
+		*param = encoded
+	}
+
+	rows, err := r.queries().FilterUsers(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("filter users filter=%+v: %w", filter, handleError(err, "filter users"))
+	}
+
+	return domainUsers(rows)
+}
+
+// SearchWithFacets searches users and returns facet counts over every match.
+// Users in the result carry the columns needed for listing only; load a user
+// by ID for the full record.
+func (r *UserRepository) SearchWithFacets(
+	ctx context.Context,
+	query string,
+	status entities.UserStatus,
+	limit int,
+) (*entities.UserSearchResult, error) {
+	err := validation.ValidateSearchQuery(query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("query=%v limit=%v: %w", query, limit, err)
+	}
+
+	rows, err := r.queries().SearchUsersWithFacets(ctx, &sqlitedb.SearchUsersWithFacetsParams{
+		Query:       query,
+		TagLimit:    adapters.DefaultFacetTagLimit,
+		Status:      string(status),
+		ResultLimit: int64(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("search users query=%v: %w", query, handleError(err, "search users with facets"))
+	}
+
+	result := &entities.UserSearchResult{
+		Users:  make([]*entities.User, 0, len(rows)),
+		Facets: entities.NewSearchFacets(),
+	}
+
+	if len(rows) == 0 {
+		return result, nil
+	}
+
+	result.Facets, err = mappers.DecodeSearchFacets(rows[0].StatusFacets, rows[0].RoleFacets, rows[0].TagFacets)
+	if err != nil {
+		return nil, fmt.Errorf("search users query=%v: %w", query, err)
+	}
+
+	mapper := mappers.NewUserMapper()
+
+	for _, row := range rows {
+		if !row.ID.Valid {
+			continue
+		}
+
+		user, err := mapper.DomainUserFromRow(&mappers.UserRow{
+			ID:        row.ID.Int64,
+			UUID:      row.UUID.String,
+			Email:     row.Email.String,
+			Username:  row.Username.String,
+			FirstName: row.FirstName.String,
+			LastName:  row.LastName.String,
+			Status:    row.Status.String,
+			Role:      row.Role.String,
+			Tags:      row.Tags.String,
+			CreatedAt: row.CreatedAt.Time,
+			UpdatedAt: row.CreatedAt.Time,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		result.Users = append(result.Users, user)
+	}
+
+	return result, nil
+}
+
+// CountByStatus counts active users per status.
+func (r *UserRepository) CountByStatus(ctx context.Context) (map[entities.UserStatus]int64, error) {
+	rows, err := r.queries().CountUsersByStatus(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("count users by status: %w", handleError(err, "count users by status"))
+	}
+
+	counts := make(map[entities.UserStatus]int64, len(rows))
+	for _, row := range rows {
+		counts[entities.UserStatus(row.Status)] = row.Total
+	}
+
+	return counts, nil
+}
+
+// GetStats returns aggregate user statistics.
+func (r *UserRepository) GetStats(ctx context.Context) (*entities.UserStats, error) {
+	row, err := r.queries().GetUserStats(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get user stats: %w", handleError(err, "get user stats"))
+	}
+
+	stats := &entities.UserStats{
+		TotalUsers:      row.TotalUsers,
+		ActiveUsers:     row.ActiveUsers,
+		InactiveUsers:   row.InactiveUsers,
+		SuspendedUsers:  row.SuspendedUsers,
+		VerifiedUsers:   row.VerifiedUsers,
+		UsersWithLogins: row.UsersWithLogins,
+		NewUsers30d:     row.NewUsers30d,
+		NewUsers7d:      row.NewUsers7d,
+	}
+	stats.ComputeRates()
+
+	return stats, nil
+}
+
+// GetCredentials returns the user of email with its password hash.
+func (r *UserRepository) GetCredentials(ctx context.Context, email entities.Email) (*entities.User, error) {
+	return r.GetByEmail(ctx, email)
+}
+
+// UpdatePassword stores a new password hash.
+func (r *UserRepository) UpdatePassword(
+	ctx context.Context,
+	id entities.UserID,
+	password entities.PasswordHash,
+) error {
+	err := r.queries().UpdatePassword(ctx, &sqlitedb.UpdatePasswordParams{
+		PasswordHash: password.String(),
+		ID:           int64(id),
+	})
+	if err != nil {
+		return fmt.Errorf("update password id=%v: %w", id, handleError(err, "update password"))
+	}
+
+	return nil
+}
+
+// MarkVerified marks a user's email as verified.
+func (r *UserRepository) MarkVerified(ctx context.Context, id entities.UserID) error {
+	err := r.queries().MarkUserVerified(ctx, int64(id))
+	if err != nil {
+		return fmt.Errorf("mark verified id=%v: %w", id, handleError(err, "mark user verified"))
+	}
+
+	return nil
+}
+
+// ChangeStatus changes user status.
+func (r *UserRepository) ChangeStatus(
+	ctx context.Context,
+	id entities.UserID,
+	status entities.UserStatus,
+) error {
+	if !status.IsValid() {
+		return fmt.Errorf("status=%v: %w", status, entities.ErrInvalidUserStatus)
+	}
+
+	err := r.queries().UpdateUserStatus(ctx, &sqlitedb.UpdateUserStatusParams{
+		Status: string(status),
+		ID:     int64(id),
+	})
+	if err != nil {
+		return fmt.Errorf("change status id=%v: %w", id, handleError(err, "update user status"))
+	}
+
+	return nil
+}
+
+// Activate activates a user.
+func (r *UserRepository) Activate(ctx context.Context, id entities.UserID) error {
+	return r.ChangeStatus(ctx, id, entities.UserStatusActive)
+}
+
+// Deactivate deactivates a user.
+func (r *UserRepository) Deactivate(ctx context.Context, id entities.UserID) error {
+	return r.ChangeStatus(ctx, id, entities.UserStatusInactive)
+}
+
+// Suspend suspends a user.
+func (r *UserRepository) Suspend(ctx context.Context, id entities.UserID) error {
+	return r.ChangeStatus(ctx, id, entities.UserStatusSuspended)
+}
+
+// ChangeRole changes user role.
+func (r *UserRepository) ChangeRole(
+	ctx context.Context,
+	id entities.UserID,
+	role entities.UserRole,
+) error {
+	if !role.IsValid() {
+		return fmt.Errorf("role=%v: %w", role, entities.ErrInvalidUserRole)
+	}
+
+	err := r.queries().UpdateUserRole(ctx, &sqlitedb.UpdateUserRoleParams{
+		Role: string(role),
+		ID:   int64(id),
+	})
+	if err != nil {
+		return fmt.Errorf("change role id=%v: %w", id, handleError(err, "update user role"))
+	}
+
+	return nil
+}
+
+// handleError maps SQLite errors to domain errors.
+func handleError(err error, operation string) error {
+	return sqlitedb.HandleDBError(
+		err,
+		operation,
+		entities.ErrUserNotFound,
+		entities.ErrUserAlreadyExists,
+		sqlitedb.IsSQLiteUniqueConstraintError,
+	)
+}
+
+// ftsQuery turns free text into an FTS5 query matching every term as a prefix.
+// Terms are quoted so that user input cannot inject FTS5 query syntax.
+func ftsQuery(query string) string {
+	terms := strings.Fields(query)
+	for i, term := range terms {
+		terms[i] = `"` + strings.ReplaceAll(term, `"`, `""`) + `"*`
+	}
+
+	return strings.Join(terms, " ")
+}
+
+// domainUser converts a generated users row into a domain entity.
+func domainUser(row *sqlitedb.Users) (*entities.User, error) {
+	return mappers.NewUserMapper().DomainUserFromSQLite(row)
+}
+
+// domainUsers converts generated users rows into domain entities.
+func domainUsers(rows []*sqlitedb.Users) ([]*entities.User, error) {
+	return scanAll[*entities.User](rows)
+}
@@ -0,0 +1,156 @@
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters"
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/mappers"
+	sqlitedb "github.com/LarsArtmann/template-sqlc/internal/db/sqlite"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+)
+
+// queries returns the sqlc queries bound to the repository's database handle.
+func (r *UserReadModelRepository) queries() *sqlitedb.Queries {
+	return sqlitedb.New(r.db)
+}
+
+// Upsert stores the views, replacing those of the same users unless they
+// were projected from a newer version of the user.
+func (r *UserReadModelRepository) Upsert(ctx context.Context, views ...*entities.UserView) error {
+	for _, view := range views {
+		tags, err := mappers.EncodeTags(view.Tags)
+		if err != nil {
+			return fmt.Errorf("user view user=%v: %w", view.UserID, err)
+		}
+
+		err = r.queries().UpsertUserView(ctx, &sqlitedb.UpsertUserViewParams{
+			UserID:          view.UserID.Int64(),
+			UUID:            mappers.FormatUUID(view.UUID),
+			Email:           view.Email.String(),
+			Username:        view.Username.String(),
+			FullName:        view.FullName,
+			Status:          view.Status.String(),
+			Role:            view.Role.String(),
+			Tags:            tags,
+			IsVerified:      view.IsVerified,
+			SearchText:      view.SearchText(),
+			CreatedAt:       view.CreatedAt.UTC(),
+			SourceUpdatedAt: view.SourceUpdatedAt.UTC(),
+			ProjectedAt:     view.ProjectedAt.UTC(),
+		})
+		if err != nil {
+			return fmt.Errorf("user view user=%v: %w", view.UserID, handleError(err, "upsert user view"))
+		}
+	}
+
+	return nil
+}
+
+// Remove drops the views of the users; unknown IDs are ignored.
+func (r *UserReadModelRepository) Remove(ctx context.Context, ids ...entities.UserID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	keys := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		keys = append(keys, int64(id))
+	}
+
+	err := r.queries().DeleteUserViews(ctx, keys)
+	if err != nil {
+		return fmt.Errorf("remove %d user views: %w", len(ids), handleError(err, "delete user views"))
+	}
+
+	return nil
+}
+
+// List returns up to limit views matching filter after skipping offset,
+// newest first. Sets and query terms are passed as JSON arrays.
+func (r *UserReadModelRepository) List(
+	ctx context.Context,
+	filter entities.UserFilter,
+	limit, offset int,
+) ([]*entities.UserView, error) {
+	err := adapters.ValidateListRequest(filter, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	params := &sqlitedb.ListUserViewsParams{
+		Verified:  int64(filter.VerifiedState()),
+		RowLimit:  int64(limit),
+		RowOffset: int64(offset),
+	}
+
+	params.CreatedAfter, params.CreatedBefore = filter.CreatedRange()
+
+	sets := map[*string][]string{
+		&params.Statuses: filter.StatusValues(),
+		&params.Roles:    filter.RoleValues(),
+		&params.TagsAny:  filter.TagsAny,
+		&params.TagsAll:  filter.TagsAll,
+		&params.Terms:    entities.QueryTerms(filter.Query),
+	}
+
+	for param, values := range sets {
+		encoded, err := mappers.EncodeTags(values)
+		if err != nil {
+			return nil, fmt.Errorf("filter=%+v: %w", filter, err)
+		}
+
+		*param = encoded
+	}
+
+	rows, err := r.queries().ListUserViews(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("list user views filter=%+v: %w", filter, handleError(err, "list user views"))
+	}
+
+	views := make([]*entities.UserView, 0, len(rows))
+
+	for _, row := range rows {
+		view, err := mappers.DomainUserView(mappers.UserViewRow{
+			UserID:          entities.UserID(row.UserID),
+			UUID:            row.UUID,
+			Email:           row.Email,
+			Username:        row.Username,
+			FullName:        row.FullName,
+			Status:          row.Status,
+			Role:            row.Role,
+			Tags:            row.Tags,
+			IsVerified:      row.IsVerified,
+			CreatedAt:       row.CreatedAt,
+			SourceUpdatedAt: row.SourceUpdatedAt,
+			ProjectedAt:     row.ProjectedAt,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		views = append(views, view)
+	}
+
+	return views, nil
+}
+
+// Lag returns how long the oldest change of a user that is not in the read
+// model yet has been waiting, or 0 if the read model is current.
+func (r *UserReadModelRepository) Lag(ctx context.Context) (time.Duration, error) {
+	changedAt, err := r.queries().GetOldestUnprojectedUserChange(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+
+	if err != nil {
+		return 0, fmt.Errorf("user read model lag: %w", handleError(err, "get oldest unprojected user change"))
+	}
+
+	return max(time.Since(changedAt), 0), nil
+}
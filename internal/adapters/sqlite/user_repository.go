@@ -1,9 +1,13 @@
 package sqlite
 
 import (
+	"context"
+	"fmt"
+
 	"github.com/LarsArtmann/template-sqlc/internal/adapters"
 	"github.com/LarsArtmann/template-sqlc/internal/adapters/converters"
 	"github.com/LarsArtmann/template-sqlc/internal/db/shared"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
 	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
 )
 
@@ -21,3 +25,22 @@ func NewUserRepository(db shared.DBTX) repositories.UserRepository {
 		DBUserRepository:   adapters.NewDBUserRepository(db, converters.DbTypeSQLite),
 	}
 }
+
+// GetByIDForUpdate is not supported: SQLite locks the whole database for writes,
+// so callers should rely on an IMMEDIATE transaction instead of row locks.
+func (r *UserRepository) GetByIDForUpdate(
+	_ context.Context,
+	id entities.UserID,
+	_ ...repositories.LockOption,
+) (*entities.User, error) {
+	return nil, fmt.Errorf("GetByIDForUpdate id=%v: %w", id, adapters.ErrRowLockingNotSupported)
+}
+
+// ClaimNext is not supported: SQLite has no SKIP LOCKED equivalent.
+func (r *UserRepository) ClaimNext(
+	_ context.Context,
+	status entities.UserStatus,
+	_ int,
+) ([]*entities.User, error) {
+	return nil, fmt.Errorf("ClaimNext status=%v: %w", status, adapters.ErrRowLockingNotSupported)
+}
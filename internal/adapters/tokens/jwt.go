@@ -0,0 +1,195 @@
+// Package tokens provides session token strategies for the user service.
+package tokens
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/services"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrUnknownKey is returned when a token was signed with a key that is not registered.
+	ErrUnknownKey = errors.New("unknown signing key")
+
+	// ErrInvalidClaims is returned when a verified token carries malformed claims.
+	ErrInvalidClaims = errors.New("invalid token claims")
+)
+
+// SigningKey is a JWT key identified by the kid header.
+// Verify is the matching public key, or the secret itself for HMAC.
+type SigningKey struct {
+	ID     string
+	Method jwt.SigningMethod
+	Sign   any
+	Verify any
+}
+
+// NewHMACKey creates an HS256 key from a shared secret.
+func NewHMACKey(id string, secret []byte) SigningKey {
+	return SigningKey{ID: id, Method: jwt.SigningMethodHS256, Sign: secret, Verify: secret}
+}
+
+// NewRSAKey creates an RS256 key.
+func NewRSAKey(id string, key *rsa.PrivateKey) SigningKey {
+	return SigningKey{ID: id, Method: jwt.SigningMethodRS256, Sign: key, Verify: &key.PublicKey}
+}
+
+// NewEd25519Key creates an EdDSA key.
+func NewEd25519Key(id string, key ed25519.PrivateKey) SigningKey {
+	public, _ := key.Public().(ed25519.PublicKey)
+
+	return SigningKey{ID: id, Method: jwt.SigningMethodEdDSA, Sign: key, Verify: public}
+}
+
+// sessionClaims maps session claims onto JWT claims.
+// The session token is the jti and the user ID the subject.
+type sessionClaims struct {
+	jwt.RegisteredClaims
+
+	Role string `json:"role,omitempty"`
+}
+
+// JWTStrategy issues signed JWTs for sessions and verifies them.
+// Tokens still reference a server-side session so they can be revoked.
+type JWTStrategy struct {
+	issuer string
+
+	mu      sync.RWMutex
+	current SigningKey
+	keys    map[string]SigningKey
+}
+
+var _ services.TokenStrategy = (*JWTStrategy)(nil)
+
+// NewJWTStrategy creates a strategy that signs with key.
+func NewJWTStrategy(issuer string, key SigningKey) *JWTStrategy {
+	return &JWTStrategy{
+		issuer:  issuer,
+		current: key,
+		keys:    map[string]SigningKey{key.ID: key},
+	}
+}
+
+// Rotate starts signing with next. Previously registered keys keep
+// verifying tokens until they are retired.
+func (s *JWTStrategy) Rotate(next SigningKey) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.current = next
+	s.keys[next.ID] = next
+}
+
+// Retire stops accepting tokens signed with the key id.
+// The key currently used for signing cannot be retired.
+func (s *JWTStrategy) Retire(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if id != s.current.ID {
+		delete(s.keys, id)
+	}
+}
+
+// Issue signs claims with the current key.
+func (s *JWTStrategy) Issue(claims services.TokenClaims) (string, error) {
+	s.mu.RLock()
+	key := s.current
+	s.mu.RUnlock()
+
+	token := jwt.NewWithClaims(key.Method, sessionClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        claims.SessionToken.String(),
+			Subject:   strconv.FormatInt(claims.UserID.Int64(), 10),
+			Issuer:    s.issuer,
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(claims.ExpiresAt),
+		},
+		Role: claims.Role.String(),
+	})
+	token.Header["kid"] = key.ID
+
+	signed, err := token.SignedString(key.Sign)
+	if err != nil {
+		return "", fmt.Errorf("kid=%v: %w", key.ID, err)
+	}
+
+	return signed, nil
+}
+
+// Parse verifies the signature, issuer and expiry of token and maps its claims.
+func (s *JWTStrategy) Parse(token string) (*services.TokenClaims, error) {
+	var claims sessionClaims
+
+	_, err := jwt.ParseWithClaims(
+		token,
+		&claims,
+		s.verificationKey,
+		jwt.WithIssuer(s.issuer),
+		jwt.WithExpirationRequired(),
+		jwt.WithValidMethods([]string{
+			jwt.SigningMethodHS256.Alg(),
+			jwt.SigningMethodRS256.Alg(),
+			jwt.SigningMethodEdDSA.Alg(),
+		}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return mapClaims(&claims)
+}
+
+// verificationKey resolves the kid header to a registered key and rejects
+// tokens whose algorithm does not match the key.
+func (s *JWTStrategy) verificationKey(token *jwt.Token) (any, error) {
+	id, _ := token.Header["kid"].(string)
+
+	s.mu.RLock()
+	key, ok := s.keys[id]
+	s.mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("kid=%v: %w", id, ErrUnknownKey)
+	}
+
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("kid=%v alg=%v: %w", id, token.Method.Alg(), ErrUnknownKey)
+	}
+
+	return key.Verify, nil
+}
+
+// mapClaims converts verified JWT claims to session claims.
+func mapClaims(claims *sessionClaims) (*services.TokenClaims, error) {
+	sessionToken, err := uuid.Parse(claims.ID)
+	if err != nil {
+		return nil, fmt.Errorf("jti=%v: %w", claims.ID, ErrInvalidClaims)
+	}
+
+	userID, err := strconv.ParseInt(claims.Subject, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("sub=%v: %w", claims.Subject, ErrInvalidClaims)
+	}
+
+	role := entities.UserRole(claims.Role)
+	if role != "" && !role.IsValid() {
+		return nil, fmt.Errorf("role=%v: %w", claims.Role, ErrInvalidClaims)
+	}
+
+	return &services.TokenClaims{
+		SessionToken: entities.SessionToken(sessionToken),
+		UserID:       entities.UserID(userID),
+		Role:         role,
+		ExpiresAt:    claims.ExpiresAt.Time,
+	}, nil
+}
@@ -10,7 +10,6 @@ import (
 const (
 	maxPaginationLimit = 1000
 	maxSearchLimit     = 100
-	maxTagsLimit       = 10
 	maxQueryLength     = 500
 )
 
@@ -31,19 +30,6 @@ func isValidPaginationLimit(limit int) bool {
 	return limit > 0 && limit <= maxPaginationLimit
 }
 
-// ValidateTags validates tags parameter.
-func ValidateTags(tags []string) error {
-	if len(tags) == 0 {
-		return errors.NewValidationError("tags", "cannot be empty")
-	}
-
-	if len(tags) > maxTagsLimit {
-		return errors.NewValidationError("tags", "cannot exceed 10 tags")
-	}
-
-	return nil
-}
-
 // ValidateSearchQuery validates search query and limit.
 func ValidateSearchQuery(query string, limit int) error {
 	if query == "" {
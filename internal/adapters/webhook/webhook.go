@@ -0,0 +1,142 @@
+// Package webhook posts notifications to the URLs users registered for them.
+// It is the webhook channel of services.NotificationService:
+//
+//	notifications := services.NewNotificationService(prefs, users,
+//		services.WithNotificationChannel(entities.NotificationChannelWebhook,
+//			webhook.NewSender(webhook.WithSigningSecret(secret))))
+//
+// Bodies are JSON Payloads. With a signing secret, receivers authenticate
+// them by comparing SignatureHeader with Sign over the raw body.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/services"
+)
+
+// SignatureHeader carries the signature of signed bodies.
+const SignatureHeader = "X-Webhook-Signature"
+
+// defaultTimeout bounds a delivery when no client is configured.
+const defaultTimeout = 10 * time.Second
+
+var (
+	// ErrNoTarget is returned for a notification without a URL to post to.
+	ErrNoTarget = errors.New("webhook target missing")
+	// ErrDeliveryFailed is returned when the target does not answer with a 2xx status.
+	ErrDeliveryFailed = errors.New("webhook delivery failed")
+)
+
+// Payload is the body posted for a notification. It holds no personal data
+// beyond the user ID, since targets are outside the application.
+type Payload struct {
+	ID         entities.IDID              `json:"id"`
+	Type       events.EventType           `json:"type"`
+	Topic      entities.NotificationTopic `json:"topic"`
+	UserID     entities.UserID            `json:"userId"`
+	Summary    string                     `json:"summary"`
+	OccurredAt time.Time                  `json:"occurredAt"`
+}
+
+// Sender posts notifications to their targets.
+type Sender struct {
+	client *http.Client
+	secret []byte
+}
+
+var _ services.NotificationSender = (*Sender)(nil)
+
+// Option configures a Sender.
+type Option func(*Sender)
+
+// WithHTTPClient posts with client instead of a client with a 10s timeout.
+func WithHTTPClient(client *http.Client) Option {
+	return func(s *Sender) {
+		s.client = client
+	}
+}
+
+// WithSigningSecret signs bodies with secret in SignatureHeader.
+func WithSigningSecret(secret []byte) Option {
+	return func(s *Sender) {
+		s.secret = secret
+	}
+}
+
+// NewSender creates a webhook sender.
+func NewSender(opts ...Option) *Sender {
+	s := &Sender{client: &http.Client{Timeout: defaultTimeout}}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Notify posts notification to its target.
+func (s *Sender) Notify(ctx context.Context, notification services.Notification) error {
+	if notification.Target == "" {
+		return fmt.Errorf("notification=%v: %w", notification.ID, ErrNoTarget)
+	}
+
+	body, err := json.Marshal(Payload{
+		ID:         notification.ID,
+		Type:       notification.Type,
+		Topic:      notification.Topic,
+		UserID:     notification.User.ID(),
+		Summary:    notification.Summary,
+		OccurredAt: notification.OccurredAt,
+	})
+	if err != nil {
+		return fmt.Errorf("encode webhook notification=%v: %w", notification.ID, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, notification.Target, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("webhook notification=%v: %w", notification.ID, err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	if len(s.secret) > 0 {
+		req.Header.Set(SignatureHeader, Sign(s.secret, body))
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("post webhook notification=%v: %w", notification.ID, err)
+	}
+
+	defer resp.Body.Close()
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("notification=%v status=%d: %w", notification.ID, resp.StatusCode, ErrDeliveryFailed)
+	}
+
+	return nil
+}
+
+// Sign returns the signature of body with secret: "sha256=" and the hex
+// HMAC-SHA256 of body.
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,141 @@
+// Package changefeed turns the row changes that PostgreSQL logical decoding
+// reports for the users and user_sessions tables into domain events, for
+// deployments that cannot publish events through the outbox. Changes are
+// decoded from the wal2json output plugin (format version 2) and published
+// per transaction once it committed; the position of the last published
+// transaction is checkpointed so a restarted listener resumes there.
+package changefeed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Sentinel errors for decoding changes.
+var (
+	ErrInvalidLSN     = errors.New("invalid log sequence number")
+	ErrInvalidChange  = errors.New("invalid change")
+	ErrMissingColumn  = errors.New("missing column")
+	ErrUnknownAction  = errors.New("unknown change action")
+	ErrNoConverters   = errors.New("at least one table converter is required")
+	ErrSlotRequired   = errors.New("replication slot name is required")
+	ErrNoTransactions = errors.New("changes outside a transaction; enable include-transaction")
+)
+
+// LSN is a PostgreSQL log sequence number, a position in the write-ahead log.
+type LSN uint64
+
+// ParseLSN parses the textual form of an LSN, such as "16/B374D848".
+func ParseLSN(s string) (LSN, error) {
+	high, low, ok := strings.Cut(s, "/")
+	if !ok {
+		return 0, fmt.Errorf("lsn=%q: %w", s, ErrInvalidLSN)
+	}
+
+	hi, err := strconv.ParseUint(high, 16, 32)
+	if err != nil {
+		return 0, fmt.Errorf("lsn=%q: %w", s, ErrInvalidLSN)
+	}
+
+	lo, err := strconv.ParseUint(low, 16, 32)
+	if err != nil {
+		return 0, fmt.Errorf("lsn=%q: %w", s, ErrInvalidLSN)
+	}
+
+	return LSN(hi<<32 | lo), nil
+}
+
+// String returns the textual form of the LSN.
+func (l LSN) String() string {
+	return fmt.Sprintf("%X/%X", uint64(l)>>32, uint32(l))
+}
+
+// Action is the kind of a decoded change.
+type Action string
+
+// Actions reported by wal2json.
+const (
+	ActionBegin    Action = "B"
+	ActionCommit   Action = "C"
+	ActionInsert   Action = "I"
+	ActionUpdate   Action = "U"
+	ActionDelete   Action = "D"
+	ActionTruncate Action = "T"
+	ActionMessage  Action = "M"
+)
+
+// Column is the value of a column in a changed row.
+type Column struct {
+	Name string
+	Type string
+	// Value is the decoded JSON value: nil, a bool, a string, a json.Number,
+	// or a slice or map for array and composite values.
+	Value any
+}
+
+// Change is a decoded change of a row, or the begin or commit of its
+// transaction.
+type Change struct {
+	Action Action
+	Schema string
+	Table  string
+	// Columns are the new values of inserted and updated rows.
+	Columns []Column
+	// Identity holds the old values of updated and deleted rows: the
+	// primary key, or every column with REPLICA IDENTITY FULL.
+	Identity []Column
+	LSN      LSN
+}
+
+// Message is a change as reported by the replication slot, before decoding.
+type Message struct {
+	LSN  LSN
+	Data []byte
+}
+
+// Source reads the changes of a logical replication slot decoded by wal2json.
+type Source interface {
+	// CreateSlot creates the replication slot unless it exists.
+	CreateSlot(ctx context.Context, slot string) error
+	// Peek returns up to limit messages of slot for the qualified tables
+	// without consuming them. Transactions are never split.
+	Peek(ctx context.Context, slot string, tables []string, limit int) ([]Message, error)
+	// Advance consumes the messages of slot up to lsn, so the database can
+	// recycle the write-ahead log before it.
+	Advance(ctx context.Context, slot string, lsn LSN) error
+}
+
+// New returns the new value of the named column.
+func (c *Change) New(name string) (Column, bool) {
+	return findColumn(c.Columns, name)
+}
+
+// Old returns the old value of the named column, if the replica identity
+// of the table includes it.
+func (c *Change) Old(name string) (Column, bool) {
+	return findColumn(c.Identity, name)
+}
+
+// Value returns the new value of the named column, or the old one for
+// deleted rows.
+func (c *Change) Value(name string) (Column, bool) {
+	if c.Action == ActionDelete {
+		return c.Old(name)
+	}
+
+	return c.New(name)
+}
+
+// findColumn returns the column called name.
+func findColumn(columns []Column, name string) (Column, bool) {
+	for _, column := range columns {
+		if column.Name == name {
+			return column, true
+		}
+	}
+
+	return Column{}, false
+}
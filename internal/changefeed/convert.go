@@ -0,0 +1,248 @@
+package changefeed
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+)
+
+// Converter turns the change of a row into domain events. Changes it does
+// not care about convert to no events.
+type Converter func(change *Change) ([]*events.UserEvent, error)
+
+// DefaultConverters converts the changes of the users and user_sessions
+// tables, keyed by qualified table name.
+func DefaultConverters() map[string]Converter {
+	return map[string]Converter{
+		"public.users":         UserChanges,
+		"public.user_sessions": SessionChanges,
+	}
+}
+
+// EventTypes returns the types of the events DefaultConverters report. While
+// the changefeed runs, the services leave these events to it, so that
+// subscribers receive them once.
+func EventTypes() []events.EventType {
+	return []events.EventType{
+		events.EventUserCreated,
+		events.EventUserUpdated,
+		events.EventUserDeleted,
+		events.EventUserRestored,
+		events.EventUserErased,
+		events.EventRoleChanged,
+		events.EventUserLogin,
+		events.EventUserLogout,
+	}
+}
+
+// userUpdateColumns returns the users columns reported in user.updated
+// events. Passwords, timestamps and derived columns are left out.
+func userUpdateColumns() []string {
+	return []string{
+		"email",
+		"username",
+		"first_name",
+		"last_name",
+		"status",
+		"is_active",
+		"is_verified",
+		"profile_metadata",
+		"tags",
+	}
+}
+
+// UserChanges converts the changes of users rows: inserts are reported as
+// user.created, deletes as user.erased, setting and clearing deleted_at as
+// user.deleted and user.restored, role changes as role.changed, and the
+// other changes of userUpdateColumns as user.updated. Old values require
+// REPLICA IDENTITY FULL; without them every reported column counts as
+// changed. The actor is unknown, so it is always zero.
+func UserChanges(change *Change) ([]*events.UserEvent, error) {
+	if change.Action != ActionInsert && change.Action != ActionUpdate && change.Action != ActionDelete {
+		return nil, nil
+	}
+
+	id, err := intColumn(change, "id")
+	if err != nil {
+		return nil, err
+	}
+
+	userID := entities.UserID(id)
+
+	switch change.Action {
+	case ActionInsert:
+		return []*events.UserEvent{events.UserCreated(
+			userID,
+			stringColumn(change, "email"),
+			stringColumn(change, "username"),
+			stringColumn(change, "first_name"),
+			stringColumn(change, "last_name"),
+			stringColumn(change, "role"),
+			stringColumn(change, "status"),
+		)}, nil
+	case ActionDelete:
+		return []*events.UserEvent{events.UserErased(userID, 0)}, nil
+	default:
+		return userUpdates(change, userID), nil
+	}
+}
+
+// userUpdates converts the update of a users row.
+func userUpdates(change *Change, userID entities.UserID) []*events.UserEvent {
+	var converted []*events.UserEvent
+
+	if oldDeleted, deleted, changed := compare(change, "deleted_at"); changed {
+		deletion := events.UserDeletionEvent{
+			UserID:   userID,
+			Email:    stringColumn(change, "email"),
+			Username: stringColumn(change, "username"),
+		}
+
+		switch {
+		case deleted.Value != nil:
+			converted = append(converted, events.NewUserEvent(events.EventUserDeleted, userID, deletion))
+		case oldDeleted.Value != nil:
+			converted = append(converted, events.NewUserEvent(events.EventUserRestored, userID, deletion))
+		}
+	}
+
+	if oldRole, role, changed := compare(change, "role"); changed {
+		converted = append(converted,
+			events.RoleChanged(userID, valueString(oldRole.Value), valueString(role.Value), 0))
+	}
+
+	changes := make(map[string]any)
+
+	for _, name := range userUpdateColumns() {
+		old, updated, changed := compare(change, name)
+		if !changed {
+			continue
+		}
+
+		fieldChange := map[string]any{"new": updated.Value}
+		if old.Name != "" {
+			fieldChange["old"] = old.Value
+		}
+
+		changes[name] = fieldChange
+	}
+
+	if len(changes) > 0 {
+		converted = append(converted, events.UserUpdated(userID, changes, 0))
+	}
+
+	return converted
+}
+
+// SessionChanges converts the changes of user_sessions rows: inserts are
+// reported as user.login, and deletes and deactivations of active sessions
+// as user.logout.
+func SessionChanges(change *Change) ([]*events.UserEvent, error) {
+	switch change.Action {
+	case ActionInsert:
+		userID, err := intColumn(change, "user_id")
+		if err != nil {
+			return nil, err
+		}
+
+		var device entities.SessionDeviceInfo
+		if info := stringColumn(change, "device_info"); info != "" {
+			// Sessions without readable device details are still logins.
+			_ = json.Unmarshal([]byte(info), &device)
+		}
+
+		return []*events.UserEvent{events.UserLoggedIn(
+			entities.UserID(userID),
+			stringColumn(change, "ip_address"),
+			stringColumn(change, "user_agent"),
+			device.Device,
+		)}, nil
+	case ActionUpdate:
+		old, active, changed := compare(change, "is_active")
+		if !changed || old.Name == "" || old.Value != true || active.Value != false {
+			return nil, nil
+		}
+	case ActionDelete:
+		// Ended sessions were reported when they were deactivated.
+		if active, ok := change.Old("is_active"); ok && active.Value == false {
+			return nil, nil
+		}
+	default:
+		return nil, nil
+	}
+
+	return sessionLogout(change)
+}
+
+// sessionLogout reports the end of the session of change.
+func sessionLogout(change *Change) ([]*events.UserEvent, error) {
+	id, err := intColumn(change, "id")
+	if err != nil {
+		return nil, err
+	}
+
+	userID, err := intColumn(change, "user_id")
+	if err != nil {
+		return nil, err
+	}
+
+	return []*events.UserEvent{events.UserLoggedOut(entities.UserID(userID), entities.SessionID(id))}, nil
+}
+
+// compare returns the old and new value of the named column of an update,
+// and whether it changed. The old column is zero if the replica identity
+// does not include it, which counts as a change.
+func compare(change *Change, name string) (Column, Column, bool) {
+	updated, ok := change.New(name)
+	if !ok {
+		return Column{}, Column{}, false
+	}
+
+	old, ok := change.Old(name)
+	if !ok {
+		return Column{}, updated, true
+	}
+
+	return old, updated, !reflect.DeepEqual(old.Value, updated.Value)
+}
+
+// intColumn returns the value of the named integer column.
+func intColumn(change *Change, name string) (int64, error) {
+	column, ok := change.Value(name)
+	if !ok || column.Value == nil {
+		return 0, fmt.Errorf("%s.%s column=%s: %w", change.Schema, change.Table, name, ErrMissingColumn)
+	}
+
+	value, err := strconv.ParseInt(valueString(column.Value), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s.%s column=%s: %w: %w", change.Schema, change.Table, name, ErrInvalidChange, err)
+	}
+
+	return value, nil
+}
+
+// stringColumn returns the value of the named column as text, or an empty
+// string if it is missing or NULL.
+func stringColumn(change *Change, name string) string {
+	column, _ := change.Value(name)
+
+	return valueString(column.Value)
+}
+
+// valueString formats a decoded column value as text.
+func valueString(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
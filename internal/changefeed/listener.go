@@ -0,0 +1,254 @@
+package changefeed
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+	"github.com/LarsArtmann/template-sqlc/internal/replay"
+	"github.com/LarsArtmann/template-sqlc/pkg/dblock"
+)
+
+const (
+	// defaultBatchSize is the number of messages read from the slot per poll.
+	defaultBatchSize = 500
+	// defaultPollInterval is how long the listener waits when the slot has
+	// no more changes.
+	defaultPollInterval = time.Second
+)
+
+// ListenerOption configures a Listener.
+type ListenerOption func(*Listener)
+
+// WithBatchSize sets how many messages are read from the slot per poll.
+// Transactions are never split, so a poll may read more.
+func WithBatchSize(size int) ListenerOption {
+	return func(l *Listener) {
+		if size > 0 {
+			l.batchSize = size
+		}
+	}
+}
+
+// WithPollInterval sets how long the listener waits when the slot has no
+// more changes.
+func WithPollInterval(interval time.Duration) ListenerOption {
+	return func(l *Listener) {
+		if interval > 0 {
+			l.pollInterval = interval
+		}
+	}
+}
+
+// WithConverters replaces DefaultConverters, keyed by qualified table
+// name. Only the changes of these tables are read from the slot.
+func WithConverters(converters map[string]Converter) ListenerOption {
+	return func(l *Listener) {
+		l.converters = converters
+	}
+}
+
+// WithLocker makes Run hold the database lock of the slot while it runs, so
+// that of several instances one publishes and the others stand by.
+func WithLocker(locker dblock.Locker) ListenerOption {
+	return func(l *Listener) {
+		l.locker = locker
+	}
+}
+
+// Listener publishes the row changes of a replication slot as domain events.
+//
+// The events of a transaction are published once its commit was read, and
+// the commit position is then saved as the checkpoint of the slot before
+// the slot is advanced past it. A listener restarted after a crash reads
+// the transactions the slot still holds again: those up to the checkpoint
+// are skipped, the others are replayed, so every event is published at
+// least once.
+type Listener struct {
+	source       Source
+	checkpoints  replay.CheckpointStore
+	publisher    events.EventPublisher
+	slot         string
+	batchSize    int
+	pollInterval time.Duration
+	converters   map[string]Converter
+	locker       dblock.Locker
+}
+
+// NewListener creates a listener for slot, checkpointed in checkpoints
+// under the slot name.
+func NewListener(
+	source Source,
+	checkpoints replay.CheckpointStore,
+	publisher events.EventPublisher,
+	slot string,
+	opts ...ListenerOption,
+) (*Listener, error) {
+	l := &Listener{
+		source:       source,
+		checkpoints:  checkpoints,
+		publisher:    publisher,
+		slot:         slot,
+		batchSize:    defaultBatchSize,
+		pollInterval: defaultPollInterval,
+		converters:   DefaultConverters(),
+	}
+
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	if slot == "" {
+		return nil, ErrSlotRequired
+	}
+
+	if len(l.converters) == 0 {
+		return nil, ErrNoConverters
+	}
+
+	return l, nil
+}
+
+// Run creates the slot unless it exists and publishes its changes until
+// ctx is canceled. Failed polls are logged and retried.
+func (l *Listener) Run(ctx context.Context) error {
+	if l.locker != nil {
+		lock, err := l.locker.Lock(ctx, "changefeed:"+l.slot)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			return fmt.Errorf("lock replication slot=%s: %w", l.slot, err)
+		}
+
+		defer func() { _ = lock.Unlock(context.WithoutCancel(ctx)) }()
+	}
+
+	err := l.source.CreateSlot(ctx, l.slot)
+	if err != nil {
+		return fmt.Errorf("create replication slot=%s: %w", l.slot, err)
+	}
+
+	for {
+		read, err := l.Poll(ctx)
+		if err != nil && ctx.Err() == nil {
+			slog.Warn("failed to publish changes", "slot", l.slot, "error", err)
+		}
+
+		// A full batch suggests more changes are waiting, so poll again right away.
+		if err == nil && read >= l.batchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(l.pollInterval):
+		}
+	}
+}
+
+// Poll publishes the committed transactions the slot holds, up to the
+// batch size, and returns how many messages it read.
+func (l *Listener) Poll(ctx context.Context) (int, error) {
+	checkpoint, err := l.checkpoints.Load(ctx, l.slot)
+	if err != nil {
+		return 0, fmt.Errorf("load checkpoint of slot=%s: %w", l.slot, err)
+	}
+
+	messages, err := l.source.Peek(ctx, l.slot, slices.Sorted(maps.Keys(l.converters)), l.batchSize)
+	if err != nil {
+		return 0, fmt.Errorf("read slot=%s: %w", l.slot, err)
+	}
+
+	var (
+		pending   []*events.UserEvent
+		open      bool
+		committed LSN
+	)
+
+	for _, message := range messages {
+		change, err := Decode(message)
+		if err != nil {
+			return len(messages), err
+		}
+
+		switch change.Action {
+		case ActionBegin:
+			pending, open = pending[:0], true
+		case ActionCommit:
+			open = false
+
+			err = l.commit(ctx, change.LSN, LSN(checkpoint), pending)
+			if err != nil {
+				return len(messages), err
+			}
+
+			committed = change.LSN
+		default:
+			if !open {
+				return len(messages), fmt.Errorf("lsn=%v: %w", change.LSN, ErrNoTransactions)
+			}
+
+			converted, err := l.convert(change)
+			if err != nil {
+				return len(messages), err
+			}
+
+			pending = append(pending, converted...)
+		}
+	}
+
+	if committed == 0 {
+		return len(messages), nil
+	}
+
+	err = l.source.Advance(ctx, l.slot, committed)
+	if err != nil {
+		return len(messages), fmt.Errorf("advance slot=%s to lsn=%v: %w", l.slot, committed, err)
+	}
+
+	return len(messages), nil
+}
+
+// commit publishes the events of the transaction committed at lsn and
+// checkpoints it, unless it was published before the checkpoint.
+func (l *Listener) commit(ctx context.Context, lsn, checkpoint LSN, pending []*events.UserEvent) error {
+	if lsn <= checkpoint {
+		return nil
+	}
+
+	for _, event := range pending {
+		err := l.publisher.Publish(event)
+		if err != nil {
+			return fmt.Errorf("publish %s of lsn=%v: %w", event.Type, lsn, err)
+		}
+	}
+
+	err := l.checkpoints.Save(ctx, l.slot, int64(lsn))
+	if err != nil {
+		return fmt.Errorf("save checkpoint of slot=%s at lsn=%v: %w", l.slot, lsn, err)
+	}
+
+	return nil
+}
+
+// convert converts a row change with the converter of its table.
+func (l *Listener) convert(change *Change) ([]*events.UserEvent, error) {
+	converter, ok := l.converters[change.Schema+"."+change.Table]
+	if !ok {
+		return nil, nil
+	}
+
+	converted, err := converter(change)
+	if err != nil {
+		return nil, fmt.Errorf("convert %s.%s change at lsn=%v: %w", change.Schema, change.Table, change.LSN, err)
+	}
+
+	return converted, nil
+}
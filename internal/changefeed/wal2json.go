@@ -0,0 +1,66 @@
+package changefeed
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// wal2jsonChange is a change in wal2json format version 2.
+type wal2jsonChange struct {
+	Action   Action           `json:"action"`
+	Schema   string           `json:"schema"`
+	Table    string           `json:"table"`
+	Columns  []wal2jsonColumn `json:"columns"`
+	Identity []wal2jsonColumn `json:"identity"`
+}
+
+// wal2jsonColumn is a column value in wal2json format version 2.
+type wal2jsonColumn struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Value any    `json:"value"`
+}
+
+// Decode decodes a message written by wal2json with format-version 2.
+// Numbers are kept as json.Number so 64-bit IDs survive.
+func Decode(message Message) (*Change, error) {
+	decoder := json.NewDecoder(bytes.NewReader(message.Data))
+	decoder.UseNumber()
+
+	var raw wal2jsonChange
+
+	err := decoder.Decode(&raw)
+	if err != nil {
+		return nil, fmt.Errorf("lsn=%v: %w: %w", message.LSN, ErrInvalidChange, err)
+	}
+
+	switch raw.Action {
+	case ActionBegin, ActionCommit, ActionInsert, ActionUpdate, ActionDelete, ActionTruncate, ActionMessage:
+	default:
+		return nil, fmt.Errorf("lsn=%v action=%q: %w", message.LSN, raw.Action, ErrUnknownAction)
+	}
+
+	return &Change{
+		Action:   raw.Action,
+		Schema:   raw.Schema,
+		Table:    raw.Table,
+		Columns:  columns(raw.Columns),
+		Identity: columns(raw.Identity),
+		LSN:      message.LSN,
+	}, nil
+}
+
+// columns converts wal2json columns.
+func columns(raw []wal2jsonColumn) []Column {
+	if len(raw) == 0 {
+		return nil
+	}
+
+	converted := make([]Column, 0, len(raw))
+	for _, column := range raw {
+		converted = append(converted, Column{Name: column.Name, Type: column.Type, Value: column.Value})
+	}
+
+	return converted
+}
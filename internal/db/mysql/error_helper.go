@@ -0,0 +1,85 @@
+package mysql
+
+import (
+	"database/sql"
+	stderrors "errors"
+
+	apperrors "github.com/LarsArtmann/template-sqlc/pkg/errors"
+	driver "github.com/go-sql-driver/mysql"
+)
+
+// MySQL server error numbers mapped to domain errors or classified as transient.
+const (
+	errDuplicateEntry        = 1062
+	errNoReferencedRow       = 1452
+	errNoReferencedRowLegacy = 1216
+	errLockWaitTimeout       = 1205
+	errLockDeadlock          = 1213
+	errServerShutdown        = 1053
+)
+
+// HandleDBError converts database errors to domain errors.
+// Takes entity-specific notFoundErr for sql.ErrNoRows, conflictErr for
+// duplicate keys (1062) and referenceErr for foreign key violations (1452).
+func HandleDBError(
+	err error,
+	operation string,
+	notFoundErr, conflictErr, referenceErr error,
+) error {
+	if err == nil {
+		return nil
+	}
+
+	switch {
+	case stderrors.Is(err, sql.ErrNoRows):
+		return notFoundErr
+	case IsMySQLDuplicateEntryError(err):
+		return conflictErr
+	case IsMySQLForeignKeyError(err):
+		return referenceErr
+	default:
+		return apperrors.NewDatabaseError(operation+" failed", err)
+	}
+}
+
+// IsMySQLDuplicateEntryError checks if error is a MySQL duplicate key violation (1062).
+func IsMySQLDuplicateEntryError(err error) bool {
+	return hasErrorNumber(err, errDuplicateEntry)
+}
+
+// IsMySQLForeignKeyError checks if error is a MySQL foreign key violation (1452).
+func IsMySQLForeignKeyError(err error) bool {
+	return hasErrorNumber(err, errNoReferencedRow, errNoReferencedRowLegacy)
+}
+
+// IsMySQLTransientError checks if error is a MySQL failure that may succeed
+// when retried: a deadlock (1213), a lock wait timeout (1205), a server
+// shutting down (1053) or a connection the driver found broken.
+func IsMySQLTransientError(err error) bool {
+	return stderrors.Is(err, driver.ErrInvalidConn) ||
+		hasErrorNumber(err, errLockDeadlock, errLockWaitTimeout, errServerShutdown)
+}
+
+// IsMySQLWriteRetryableError checks if error is a MySQL failure after which
+// a write certainly did not take effect: a deadlock (1213) or a lock wait
+// timeout (1205), which roll the statement back. Broken connections and
+// server shutdowns are not, as the write may have been applied before them.
+func IsMySQLWriteRetryableError(err error) bool {
+	return hasErrorNumber(err, errLockDeadlock, errLockWaitTimeout)
+}
+
+// hasErrorNumber reports whether err is a MySQL server error with one of the numbers.
+func hasErrorNumber(err error, numbers ...uint16) bool {
+	var mysqlErr *driver.MySQLError
+	if !stderrors.As(err, &mysqlErr) {
+		return false
+	}
+
+	for _, number := range numbers {
+		if mysqlErr.Number == number {
+			return true
+		}
+	}
+
+	return false
+}
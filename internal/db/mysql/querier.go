@@ -9,72 +9,950 @@ package mysql
 import (
 	"context"
 	"database/sql"
+	"time"
 )
 
 type Querier interface {
+	// Tokens indexed before are left alone, so every token can be added again
+	// on each update.
+	//
+	//  INSERT IGNORE INTO session_refresh_tokens (refresh_token, session_id)
+	//  VALUES (?, ?)
+	AddSessionRefreshToken(ctx context.Context, arg *AddSessionRefreshTokenParams) error
+	// Clears the personal data of the entries about or performed by a user.
+	//
+	//  UPDATE audit_log
+	//  SET changes = JSON_ARRAY(), ip_address = ''
+	//  WHERE user_id = ? OR actor_id = ?
+	AnonymizeAuditLogs(ctx context.Context, userID uint64) (int64, error)
+	//AppendUserEvent
+	//
+	//  INSERT INTO user_events (user_id, version, event_type, data, occurred_at)
+	//  VALUES (?, ?, ?, ?, ?)
+	AppendUserEvent(ctx context.Context, arg *AppendUserEventParams) error
+	// Claims a job returned by ListDueJobs unless another worker has claimed or
+	// finished it since; status and attempts are the ones that were listed.
+	//
+	//  UPDATE jobs
+	//  SET status = 'running', attempts = attempts + 1,
+	//      locked_until = ?, updated_at = ?
+	//  WHERE id = ? AND status = ? AND attempts = ?
+	ClaimJob(ctx context.Context, arg *ClaimJobParams) (int64, error)
+	// Work-queue claim: locks the oldest users in a status, skipping rows
+	// already claimed by concurrent transactions (MySQL 8.0+).
+	//
+	//  SELECT id, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, uuid, deleted_at, username_canonical FROM users
+	//  WHERE status = ? AND deleted_at IS NULL
+	//  ORDER BY updated_at, id
+	//  LIMIT ?
+	//  FOR UPDATE SKIP LOCKED
+	ClaimUsersByStatus(ctx context.Context, arg *ClaimUsersByStatusParams) ([]*Users, error)
+	//CompleteIdempotencyKey
+	//
+	//  UPDATE idempotency_keys
+	//  SET response = ?, completed_at = ?, expires_at = ?
+	//  WHERE idempotency_key = ? AND completed_at IS NULL
+	CompleteIdempotencyKey(ctx context.Context, arg *CompleteIdempotencyKeyParams) (int64, error)
+	// Finishing a claim matches its attempts, so a worker whose lease expired
+	// cannot overwrite a job another worker has claimed since.
+	//
+	//  UPDATE jobs
+	//  SET status = 'done', locked_until = NULL, last_error = '', updated_at = ?
+	//  WHERE id = ? AND status = 'running' AND attempts = ?
+	CompleteJob(ctx context.Context, arg *CompleteJobParams) (int64, error)
+	//CountActiveSessions
+	//
+	//  SELECT COUNT(*) AS count
+	//  FROM user_sessions
+	//  WHERE user_id = ? AND is_active = TRUE AND expires_at >= ?
+	CountActiveSessions(ctx context.Context, arg *CountActiveSessionsParams) (int64, error)
 	//CountActiveUsers
 	//
-	//  SELECT COUNT(*) FROM users WHERE is_active = TRUE
+	//  SELECT COUNT(*) FROM users WHERE deleted_at IS NULL
 	CountActiveUsers(ctx context.Context) (int64, error)
+	//CountOrganizationAdmins
+	//
+	//  SELECT COUNT(*)
+	//  FROM organization_members
+	//  WHERE organization_id = ? AND role = 'admin' AND status = 'active'
+	CountOrganizationAdmins(ctx context.Context, organizationID uint64) (int64, error)
+	//CountUsersByStatus
+	//
+	//  SELECT status, COUNT(*) AS total
+	//  FROM users
+	//  WHERE deleted_at IS NULL
+	//  GROUP BY status
+	CountUsersByStatus(ctx context.Context) ([]*CountUsersByStatusRow, error)
+	//CreateAccountDeletion
+	//
+	//  INSERT INTO account_deletions (user_id, requested_by, requested_at, scheduled_for)
+	//  VALUES (?, ?, ?, ?)
+	CreateAccountDeletion(ctx context.Context, arg *CreateAccountDeletionParams) error
+	//CreateAuditLog
+	//
+	//  INSERT INTO audit_log (
+	//      actor_id, user_id, action, changes, ip_address, created_at
+	//  ) VALUES (
+	//      ?, ?, ?,
+	//      ?, ?, ?
+	//  )
+	CreateAuditLog(ctx context.Context, arg *CreateAuditLogParams) (sql.Result, error)
+	//CreateIdentityChange
+	//
+	//  INSERT INTO identity_changes (user_id, field, old_value, new_value, status, token, expires_at, created_at, updated_at)
+	//  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	CreateIdentityChange(ctx context.Context, arg *CreateIdentityChangeParams) (sql.Result, error)
+	//CreateMembership
+	//
+	//  INSERT INTO organization_members (
+	//      organization_id, user_id, role, status, invited_by, invited_at, joined_at
+	//  ) VALUES (
+	//      ?, ?, ?, ?,
+	//      ?, ?, ?
+	//  )
+	CreateMembership(ctx context.Context, arg *CreateMembershipParams) error
+	//CreateModerationAction
+	//
+	//  INSERT INTO moderation_actions (user_id, kind, reason, moderator_id, ends_at, created_at)
+	//  VALUES (?, ?, ?, ?, ?, ?)
+	CreateModerationAction(ctx context.Context, arg *CreateModerationActionParams) (sql.Result, error)
+	//CreateModerationAppeal
+	//
+	//  INSERT INTO moderation_appeals (action_id, user_id, statement, status, response, created_at)
+	//  VALUES (?, ?, ?, ?, '', ?)
+	CreateModerationAppeal(ctx context.Context, arg *CreateModerationAppealParams) (sql.Result, error)
+	//CreateOrganization
+	//
+	//  INSERT INTO organizations (uuid, name, slug, created_at, updated_at)
+	//  VALUES (?, ?, ?, ?, ?)
+	CreateOrganization(ctx context.Context, arg *CreateOrganizationParams) (sql.Result, error)
+	//CreateSession
+	//
+	//  INSERT INTO user_sessions (
+	//      user_id, session_token, device_info, ip_address, user_agent, country, region, city,
+	//      organization_id, impersonator_id, refresh_token, refresh_expires_at, rotated_refresh_tokens,
+	//      created_at, expires_at, is_active
+	//  ) VALUES (
+	//      ?, ?, ?, ?,
+	//      ?, ?, ?, ?,
+	//      ?, ?, ?,
+	//      ?, ?,
+	//      ?, ?, ?
+	//  )
+	CreateSession(ctx context.Context, arg *CreateSessionParams) (sql.Result, error)
 	//CreateUser
 	//
 	//  INSERT INTO users (
 	//      uuid, email, username, password_hash,
-	//      first_name, last_name, profile_metadata, is_active
+	//      first_name, last_name, profile_metadata, is_active,
+	//      is_verified, status, role, tags, created_at, updated_at,
+	//      username_canonical
 	//  ) VALUES (
-	//      ?, ?, ?, ?, ?, ?, ?, ?
+	//      ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
 	//  )
 	CreateUser(ctx context.Context, arg *CreateUserParams) (sql.Result, error)
+	//CreateUserIdentity
+	//
+	//  INSERT INTO user_identities (user_id, provider, subject, email, linked_at)
+	//  VALUES (?, ?, ?, ?, ?)
+	CreateUserIdentity(ctx context.Context, arg *CreateUserIdentityParams) (sql.Result, error)
+	//DeactivateSessionByToken
+	//
+	//  UPDATE user_sessions SET is_active = FALSE
+	//  WHERE session_token = ?
+	DeactivateSessionByToken(ctx context.Context, sessionToken string) (int64, error)
+	//DeactivateSessionsByUser
+	//
+	//  UPDATE user_sessions SET is_active = FALSE
+	//  WHERE user_id = ?
+	DeactivateSessionsByUser(ctx context.Context, userID uint64) error
+	//DecideModerationAppeal
+	//
+	//  UPDATE moderation_appeals
+	//  SET status = ?, reviewer_id = ?, response = ?, decided_at = ?
+	//  WHERE id = ? AND status = 'pending'
+	DecideModerationAppeal(ctx context.Context, arg *DecideModerationAppealParams) (int64, error)
+	//DeleteAccountDeletion
+	//
+	//  DELETE FROM account_deletions
+	//  WHERE user_id = ?
+	DeleteAccountDeletion(ctx context.Context, userID uint64) (int64, error)
+	//DeleteDoneJobs
+	//
+	//  DELETE FROM jobs
+	//  WHERE status = 'done' AND updated_at < ?
+	DeleteDoneJobs(ctx context.Context, cutoff time.Time) (int64, error)
+	//DeleteExpiredIdempotencyKeys
+	//
+	//  DELETE FROM idempotency_keys
+	//  WHERE expires_at <= ?
+	DeleteExpiredIdempotencyKeys(ctx context.Context, now time.Time) (int64, error)
+	//DeleteExpiredSessions
+	//
+	//  DELETE FROM user_sessions
+	//  WHERE expires_at < ?
+	DeleteExpiredSessions(ctx context.Context, now time.Time) (int64, error)
+	//DeleteIdentityChangesByUser
+	//
+	//  DELETE FROM identity_changes
+	//  WHERE user_id = ?
+	DeleteIdentityChangesByUser(ctx context.Context, userID uint64) (int64, error)
+	//DeleteLoginAttemptsByUser
+	//
+	//  DELETE FROM login_history
+	//  WHERE user_id = ?
+	DeleteLoginAttemptsByUser(ctx context.Context, userID sql.NullInt64) (int64, error)
+	//DeleteMembership
+	//
+	//  DELETE FROM organization_members
+	//  WHERE organization_id = ? AND user_id = ?
+	DeleteMembership(ctx context.Context, arg *DeleteMembershipParams) (int64, error)
+	//DeleteModerationActionsByUser
+	//
+	//  DELETE FROM moderation_actions
+	//  WHERE user_id = ?
+	DeleteModerationActionsByUser(ctx context.Context, userID uint64) (int64, error)
+	//DeleteModerationAppealsByUser
+	//
+	//  DELETE FROM moderation_appeals
+	//  WHERE user_id = ?
+	DeleteModerationAppealsByUser(ctx context.Context, userID uint64) (int64, error)
+	//DeleteNotificationPreference
+	//
+	//  DELETE FROM notification_preferences
+	//  WHERE user_id = ? AND channel = ? AND topic = ?
+	DeleteNotificationPreference(ctx context.Context, arg *DeleteNotificationPreferenceParams) (int64, error)
+	//DeleteOrganization
+	//
+	//  DELETE FROM organizations
+	//  WHERE id = ?
+	DeleteOrganization(ctx context.Context, id uint64) (int64, error)
+	//DeleteSession
+	//
+	//  DELETE FROM user_sessions
+	//  WHERE id = ?
+	DeleteSession(ctx context.Context, id uint64) (int64, error)
+	//DeleteUserActivityBefore
+	//
+	//  DELETE FROM user_activity
+	//  WHERE occurred_at < ?
+	DeleteUserActivityBefore(ctx context.Context, cutoff time.Time) (int64, error)
+	//DeleteUserActivityByUser
+	//
+	//  DELETE FROM user_activity
+	//  WHERE user_id = ?
+	DeleteUserActivityByUser(ctx context.Context, userID uint64) (int64, error)
+	//DeleteUserEvents
+	//
+	//  DELETE FROM user_events
+	//  WHERE user_id = ?
+	DeleteUserEvents(ctx context.Context, userID uint64) (int64, error)
+	// Removes every archived version of a user.
+	//
+	//  DELETE FROM users_history
+	//  WHERE user_id = ?
+	DeleteUserHistory(ctx context.Context, userID uint64) (int64, error)
+	//DeleteUserIdentity
+	//
+	//  DELETE FROM user_identities
+	//  WHERE user_id = ? AND provider = ?
+	DeleteUserIdentity(ctx context.Context, arg *DeleteUserIdentityParams) (int64, error)
+	//DeleteUserPreferences
+	//
+	//  DELETE FROM user_preferences
+	//  WHERE user_id = ?
+	DeleteUserPreferences(ctx context.Context, userID uint64) (int64, error)
+	//DeleteUserSnapshots
+	//
+	//  DELETE FROM user_snapshots
+	//  WHERE user_id = ?
+	DeleteUserSnapshots(ctx context.Context, userID uint64) (int64, error)
+	//DeleteUserViews
+	//
+	//  DELETE FROM user_read_model WHERE user_id IN (/*SLICE:ids*/?)
+	DeleteUserViews(ctx context.Context, ids []uint64) error
+	//EnqueueJob
+	//
+	//  INSERT INTO jobs (
+	//      name, payload, max_attempts, run_at, last_error, created_at, updated_at
+	//  ) VALUES (
+	//      ?, ?, ?,
+	//      ?, '', ?, ?
+	//  )
+	EnqueueJob(ctx context.Context, arg *EnqueueJobParams) (sql.Result, error)
+	//FailJob
+	//
+	//  UPDATE jobs
+	//  SET status = 'failed', locked_until = NULL, last_error = ?, updated_at = ?
+	//  WHERE id = ? AND status = 'running' AND attempts = ?
+	FailJob(ctx context.Context, arg *FailJobParams) (int64, error)
+	// Lists users matching a UserFilter, newest first with the ID breaking ties.
+	// Empty sets and an empty query match any user; a negative verified matches
+	// both states. Open time ranges and the first page's keyset cursor are
+	// replaced by bounds beyond any stored value.
+	// Statuses, roles and tag sets are JSON arrays of strings; the query is a
+	// boolean-mode search expression.
+	//
+	//  SELECT id, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, uuid, deleted_at, username_canonical FROM users
+	//  WHERE deleted_at IS NULL
+	//    AND (JSON_LENGTH(?) = 0 OR JSON_CONTAINS(?, JSON_QUOTE(status)))
+	//    AND (JSON_LENGTH(?) = 0 OR JSON_CONTAINS(?, JSON_QUOTE(role)))
+	//    AND (CAST(? AS SIGNED) < 0 OR is_verified = CAST(? AS SIGNED))
+	//    AND created_at > ?
+	//    AND created_at < ?
+	//    AND (JSON_LENGTH(?) = 0 OR JSON_OVERLAPS(tags, ?))
+	//    AND JSON_CONTAINS(tags, ?)
+	//    AND (? = ''
+	//         OR MATCH (email, username, first_name, last_name) AGAINST (? IN BOOLEAN MODE))
+	//    AND (created_at < ?
+	//         OR (created_at = ? AND id < ?))
+	//  ORDER BY created_at DESC, id DESC
+	//  LIMIT ? OFFSET ?
+	FilterUsers(ctx context.Context, arg *FilterUsersParams) ([]*Users, error)
+	//GetAccountDeletion
+	//
+	//  SELECT user_id, requested_by, requested_at, scheduled_for
+	//  FROM account_deletions
+	//  WHERE user_id = ?
+	//  LIMIT 1
+	GetAccountDeletion(ctx context.Context, userID uint64) (*AccountDeletions, error)
+	//GetActiveSuspension
+	//
+	//  SELECT id, user_id, kind, reason, moderator_id, ends_at, lifted_at, lifted_by, created_at
+	//  FROM moderation_actions
+	//  WHERE user_id = ? AND kind = 'suspension' AND lifted_at IS NULL
+	//  ORDER BY created_at DESC, id DESC
+	//  LIMIT 1
+	GetActiveSuspension(ctx context.Context, userID uint64) (*ModerationActions, error)
+	//GetIdempotencyKey
+	//
+	//  SELECT idempotency_key, operation, request_hash, response, created_at, completed_at, expires_at
+	//  FROM idempotency_keys
+	//  WHERE idempotency_key = ?
+	//  LIMIT 1
+	GetIdempotencyKey(ctx context.Context, idempotencyKey string) (*IdempotencyKeys, error)
+	//GetIdentityChangeByToken
+	//
+	//  SELECT id, user_id, field, old_value, new_value, status, token, expires_at, created_at, updated_at
+	//  FROM identity_changes
+	//  WHERE token = ?
+	//  LIMIT 1
+	GetIdentityChangeByToken(ctx context.Context, token string) (*IdentityChanges, error)
+	//GetJob
+	//
+	//  SELECT id, name, payload, status, attempts, max_attempts, run_at, locked_until, last_error, created_at, updated_at
+	//  FROM jobs
+	//  WHERE id = ?
+	//  LIMIT 1
+	GetJob(ctx context.Context, id uint64) (*Jobs, error)
+	//GetLatestUserSnapshot
+	//
+	//  SELECT user_id, version, deleted, state, created_at
+	//  FROM user_snapshots
+	//  WHERE user_id = ?
+	//  ORDER BY version DESC
+	//  LIMIT 1
+	GetLatestUserSnapshot(ctx context.Context, userID uint64) (*UserSnapshots, error)
+	//GetMembership
+	//
+	//  SELECT organization_id, user_id, role, status, invited_by, invited_at, joined_at
+	//  FROM organization_members
+	//  WHERE organization_id = ? AND user_id = ?
+	//  LIMIT 1
+	GetMembership(ctx context.Context, arg *GetMembershipParams) (*OrganizationMembers, error)
+	//GetModerationAction
+	//
+	//  SELECT id, user_id, kind, reason, moderator_id, ends_at, lifted_at, lifted_by, created_at
+	//  FROM moderation_actions
+	//  WHERE id = ?
+	//  LIMIT 1
+	GetModerationAction(ctx context.Context, id uint64) (*ModerationActions, error)
+	//GetModerationAppeal
+	//
+	//  SELECT id, action_id, user_id, statement, status, reviewer_id, response, created_at, decided_at
+	//  FROM moderation_appeals
+	//  WHERE id = ?
+	//  LIMIT 1
+	GetModerationAppeal(ctx context.Context, id uint64) (*ModerationAppeals, error)
+	// Returns the update time of the user changed longest ago whose change is
+	// not in the read model: a live user without a current view, or a deleted
+	// user whose view was not removed.
+	//
+	//  SELECT users.updated_at
+	//  FROM users
+	//  LEFT JOIN user_read_model ON user_read_model.user_id = users.id
+	//  WHERE (users.deleted_at IS NULL
+	//         AND (user_read_model.user_id IS NULL OR user_read_model.source_updated_at < users.updated_at))
+	//     OR (users.deleted_at IS NOT NULL AND user_read_model.user_id IS NOT NULL)
+	//  ORDER BY users.updated_at
+	//  LIMIT 1
+	GetOldestUnprojectedUserChange(ctx context.Context) (sql.NullTime, error)
+	//GetOrganizationByID
+	//
+	//  SELECT id, uuid, name, slug, created_at, updated_at
+	//  FROM organizations
+	//  WHERE id = ?
+	//  LIMIT 1
+	GetOrganizationByID(ctx context.Context, id uint64) (*Organizations, error)
+	//GetOrganizationBySlug
+	//
+	//  SELECT id, uuid, name, slug, created_at, updated_at
+	//  FROM organizations
+	//  WHERE slug = ?
+	//  LIMIT 1
+	GetOrganizationBySlug(ctx context.Context, slug string) (*Organizations, error)
+	// Matches the current and the rotated refresh tokens of a session.
+	//
+	//  SELECT s.id, s.user_id, s.session_token, s.device_info, s.ip_address, s.user_agent, s.country,
+	//         s.region, s.city, s.organization_id, s.impersonator_id, s.refresh_token, s.refresh_expires_at,
+	//         s.rotated_refresh_tokens, s.created_at, s.expires_at, s.is_active
+	//  FROM user_sessions s
+	//  JOIN session_refresh_tokens t ON t.session_id = s.id
+	//  WHERE t.refresh_token = ?
+	//  LIMIT 1
+	GetSessionByRefreshToken(ctx context.Context, refreshToken string) (*UserSessions, error)
+	//GetSessionByToken
+	//
+	//  SELECT id, user_id, session_token, device_info, ip_address, user_agent, country, region, city,
+	//         organization_id, impersonator_id, refresh_token, refresh_expires_at, rotated_refresh_tokens,
+	//         created_at, expires_at, is_active
+	//  FROM user_sessions
+	//  WHERE session_token = ?
+	//  LIMIT 1
+	GetSessionByToken(ctx context.Context, sessionToken string) (*UserSessions, error)
+	//GetSessionStats
+	//
+	//  SELECT
+	//      COUNT(*) AS total_sessions,
+	//      COUNT(CASE WHEN is_active = TRUE AND expires_at >= ? THEN 1 END) AS active_sessions,
+	//      COUNT(CASE WHEN expires_at < ? THEN 1 END) AS expired_sessions,
+	//      COUNT(CASE WHEN created_at >= ? THEN 1 END) AS sessions_24h,
+	//      COUNT(CASE WHEN created_at >= ? THEN 1 END) AS sessions_7d,
+	//      COUNT(CASE WHEN created_at >= ? THEN 1 END) AS sessions_30d
+	//  FROM user_sessions
+	GetSessionStats(ctx context.Context, arg *GetSessionStatsParams) (*GetSessionStatsRow, error)
+	// Returns the version of a user that was valid at the given time, preferring
+	// archived versions over the current row.
+	//
+	//  SELECT user_id, operation, email, username, first_name, last_name, status, role, tags,
+	//         is_active, is_verified, valid_from, valid_to
+	//  FROM (
+	//      SELECT u.id AS user_id, 'current' AS operation, u.email, u.username, u.first_name, u.last_name,
+	//             u.status, u.role, u.tags, u.is_active, u.is_verified,
+	//             COALESCE(u.updated_at, u.created_at) AS valid_from,
+	//             NULLIF(u.created_at, u.created_at) AS valid_to, 1 AS is_current
+	//      FROM users u
+	//      WHERE u.id = ?
+	//        AND COALESCE(u.updated_at, u.created_at) <= CAST(? AS DATETIME)
+	//      UNION ALL
+	//      SELECT h.user_id, h.operation, h.email, h.username, h.first_name, h.last_name,
+	//             h.status, h.role, h.tags, h.is_active, h.is_verified,
+	//             h.valid_from, h.valid_to, 0
+	//      FROM users_history h
+	//      WHERE h.user_id = ?
+	//        AND h.valid_from <= CAST(? AS DATETIME)
+	//        AND h.valid_to > CAST(? AS DATETIME)
+	//  ) AS versions
+	//  ORDER BY is_current, valid_from DESC
+	//  LIMIT 1
+	GetUserAsOf(ctx context.Context, arg *GetUserAsOfParams) (*GetUserAsOfRow, error)
 	//GetUserByEmail
 	//
-	//  SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata FROM users WHERE email = ? AND is_active = TRUE
+	//  SELECT id, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, uuid, deleted_at, username_canonical FROM users WHERE email = ? AND deleted_at IS NULL
 	GetUserByEmail(ctx context.Context, email string) (*Users, error)
 	//GetUserByID
 	//
-	//  SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata FROM users WHERE id = ? AND is_active = TRUE
+	//  SELECT id, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, uuid, deleted_at, username_canonical FROM users WHERE id = ? AND deleted_at IS NULL
 	GetUserByID(ctx context.Context, id uint64) (*Users, error)
+	// Locks the user row until the surrounding transaction ends.
+	//
+	//  SELECT id, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, uuid, deleted_at, username_canonical FROM users WHERE id = ? AND deleted_at IS NULL LIMIT 1 FOR UPDATE
+	GetUserByIDForUpdate(ctx context.Context, id uint64) (*Users, error)
 	//GetUserByUUID
 	//
-	//  SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata FROM users WHERE uuid = ? AND is_active = TRUE
-	GetUserByUUID(ctx context.Context, uuid string) (*Users, error)
+	//  SELECT id, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, uuid, deleted_at, username_canonical FROM users WHERE uuid = ? AND deleted_at IS NULL
+	GetUserByUUID(ctx context.Context, uuid []byte) (*Users, error)
 	//GetUserByUsername
 	//
-	//  SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata FROM users WHERE username = ? AND is_active = TRUE
-	GetUserByUsername(ctx context.Context, username string) (*Users, error)
+	//  SELECT id, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, uuid, deleted_at, username_canonical FROM users WHERE username_canonical = ? AND deleted_at IS NULL
+	GetUserByUsername(ctx context.Context, usernameCanonical string) (*Users, error)
+	//GetUserIDsByUUIDs
+	//
+	//  SELECT id, uuid FROM users WHERE uuid IN (/*SLICE:uuids*/?)
+	GetUserIDsByUUIDs(ctx context.Context, uuids [][]byte) ([]*GetUserIDsByUUIDsRow, error)
+	//GetUserIdentity
+	//
+	//  SELECT id, user_id, provider, subject, email, linked_at, last_login_at
+	//  FROM user_identities
+	//  WHERE provider = ? AND subject = ?
+	//  LIMIT 1
+	GetUserIdentity(ctx context.Context, arg *GetUserIdentityParams) (*UserIdentities, error)
+	//GetUserPreferences
+	//
+	//  SELECT user_id, preferences, updated_at
+	//  FROM user_preferences
+	//  WHERE user_id = ?
+	//  LIMIT 1
+	GetUserPreferences(ctx context.Context, userID uint64) (*UserPreferences, error)
 	//GetUserStats
 	//
 	//  SELECT
 	//      COUNT(*) as total_users,
-	//      SUM(CASE WHEN is_active = TRUE THEN 1 ELSE 0 END) as active_users,
-	//      SUM(CASE WHEN is_verified = TRUE THEN 1 ELSE 0 END) as verified_users,
-	//      SUM(CASE WHEN last_login_at IS NOT NULL THEN 1 ELSE 0 END) as users_with_logins
+	//      COUNT(CASE WHEN deleted_at IS NULL THEN 1 END) as active_users,
+	//      COUNT(CASE WHEN is_verified = TRUE THEN 1 END) as verified_users,
+	//      COUNT(CASE WHEN last_login_at IS NOT NULL THEN 1 END) as users_with_logins,
+	//      COUNT(CASE WHEN status = 'inactive' THEN 1 END) as inactive_users,
+	//      COUNT(CASE WHEN status = 'suspended' THEN 1 END) as suspended_users,
+	//      COUNT(CASE WHEN created_at >= NOW() - INTERVAL 30 DAY THEN 1 END) as new_users_30d,
+	//      COUNT(CASE WHEN created_at >= NOW() - INTERVAL 7 DAY THEN 1 END) as new_users_7d
 	//  FROM users
 	GetUserStats(ctx context.Context) (*GetUserStatsRow, error)
+	//GetUserStreamVersion
+	//
+	//  SELECT CAST(COALESCE(MAX(version), 0) AS SIGNED) AS version
+	//  FROM user_events
+	//  WHERE user_id = ?
+	GetUserStreamVersion(ctx context.Context, userID uint64) (int64, error)
+	//GetUsersByIDs
+	//
+	//  SELECT id, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, uuid, deleted_at, username_canonical FROM users WHERE id IN (/*SLICE:ids*/?) AND deleted_at IS NULL
+	GetUsersByIDs(ctx context.Context, ids []uint64) ([]*Users, error)
+	//LiftModerationAction
+	//
+	//  UPDATE moderation_actions
+	//  SET lifted_at = ?, lifted_by = ?
+	//  WHERE id = ? AND lifted_at IS NULL
+	LiftModerationAction(ctx context.Context, arg *LiftModerationActionParams) (int64, error)
+	// lint:ignore missing-limit
+	//
+	//  SELECT id, user_id, session_token, device_info, ip_address, user_agent, country, region, city,
+	//         organization_id, impersonator_id, refresh_token, refresh_expires_at, rotated_refresh_tokens,
+	//         created_at, expires_at, is_active
+	//  FROM user_sessions
+	//  WHERE user_id = ? AND is_active = TRUE
+	//  ORDER BY id DESC
+	ListActiveSessionsByUser(ctx context.Context, userID uint64) ([]*UserSessions, error)
+	// Zero user/actor IDs and an empty action match any value.
+	//
+	//  SELECT id, actor_id, user_id, action, changes, ip_address, created_at
+	//  FROM audit_log
+	//  WHERE (? = 0 OR user_id = ?)
+	//    AND (? = 0 OR actor_id = ?)
+	//    AND (? = '' OR action = ?)
+	//    AND created_at >= ?
+	//    AND created_at < ?
+	//  ORDER BY created_at DESC, id DESC
+	//  LIMIT ? OFFSET ?
+	ListAuditLogs(ctx context.Context, arg *ListAuditLogsParams) ([]*AuditLog, error)
+	//ListDueAccountDeletions
+	//
+	//  SELECT user_id, requested_by, requested_at, scheduled_for
+	//  FROM account_deletions
+	//  WHERE scheduled_for <= ?
+	//  ORDER BY scheduled_for, user_id
+	//  LIMIT ?
+	ListDueAccountDeletions(ctx context.Context, arg *ListDueAccountDeletionsParams) ([]*AccountDeletions, error)
+	// Due jobs are pending ones whose run_at has passed and running ones whose lease expired.
+	//
+	//  SELECT id, name, payload, status, attempts, max_attempts, run_at, locked_until, last_error, created_at, updated_at
+	//  FROM jobs
+	//  WHERE (status = 'pending' AND run_at <= ?)
+	//     OR (status = 'running' AND locked_until < ?)
+	//  ORDER BY run_at, id
+	//  LIMIT ?
+	ListDueJobs(ctx context.Context, arg *ListDueJobsParams) ([]*Jobs, error)
+	//ListFeatureFlags
+	//
+	//  SELECT name, enabled, updated_at
+	//  FROM feature_flags
+	//  ORDER BY name
+	ListFeatureFlags(ctx context.Context) ([]*FeatureFlags, error)
+	//ListIdentityChanges
+	//
+	//  SELECT id, user_id, field, old_value, new_value, status, token, expires_at, created_at, updated_at
+	//  FROM identity_changes
+	//  WHERE user_id = ?
+	//  ORDER BY created_at DESC, id DESC
+	ListIdentityChanges(ctx context.Context, userID uint64) ([]*IdentityChanges, error)
+	// Lapsed suspensions ended by now but were not lifted yet.
+	//
+	//  SELECT id, user_id, kind, reason, moderator_id, ends_at, lifted_at, lifted_by, created_at
+	//  FROM moderation_actions
+	//  WHERE kind = 'suspension' AND lifted_at IS NULL AND ends_at <= ?
+	//  ORDER BY ends_at, id
+	//  LIMIT ?
+	ListLapsedSuspensions(ctx context.Context, arg *ListLapsedSuspensionsParams) ([]*ModerationActions, error)
+	//ListLoginAttemptsByUser
+	//
+	//  SELECT id, user_id, ip_address, user_agent, method, failure, created_at
+	//  FROM login_history
+	//  WHERE user_id = ?
+	//  ORDER BY created_at DESC, id DESC
+	//  LIMIT ?
+	ListLoginAttemptsByUser(ctx context.Context, arg *ListLoginAttemptsByUserParams) ([]*LoginHistory, error)
+	// Includes the failures of unknown accounts, whose user_id is NULL.
+	//
+	//  SELECT id, user_id, ip_address, user_agent, method, failure, created_at
+	//  FROM login_history
+	//  WHERE ip_address = ?
+	//    AND failure <> ''
+	//    AND created_at >= ?
+	//  ORDER BY created_at DESC, id DESC
+	//  LIMIT ?
+	ListLoginFailuresByIP(ctx context.Context, arg *ListLoginFailuresByIPParams) ([]*LoginHistory, error)
+	//ListMemberships
+	//
+	//  SELECT organization_id, user_id, role, status, invited_by, invited_at, joined_at
+	//  FROM organization_members
+	//  WHERE organization_id = ?
+	//  ORDER BY invited_at, user_id
+	//  LIMIT ? OFFSET ?
+	ListMemberships(ctx context.Context, arg *ListMembershipsParams) ([]*OrganizationMembers, error)
+	//ListModerationActions
+	//
+	//  SELECT id, user_id, kind, reason, moderator_id, ends_at, lifted_at, lifted_by, created_at
+	//  FROM moderation_actions
+	//  WHERE user_id = ?
+	//  ORDER BY created_at DESC, id DESC
+	ListModerationActions(ctx context.Context, userID uint64) ([]*ModerationActions, error)
+	//ListModerationAppealsByUser
+	//
+	//  SELECT id, action_id, user_id, statement, status, reviewer_id, response, created_at, decided_at
+	//  FROM moderation_appeals
+	//  WHERE user_id = ?
+	//  ORDER BY created_at DESC, id DESC
+	ListModerationAppealsByUser(ctx context.Context, userID uint64) ([]*ModerationAppeals, error)
+	//ListNotificationPreferences
+	//
+	//  SELECT user_id, channel, topic, enabled, target, updated_at
+	//  FROM notification_preferences
+	//  WHERE user_id = ?
+	//  ORDER BY channel, topic
+	ListNotificationPreferences(ctx context.Context, userID uint64) ([]*NotificationPreferences, error)
+	//ListOrganizations
+	//
+	//  SELECT id, uuid, name, slug, created_at, updated_at
+	//  FROM organizations
+	//  ORDER BY id
+	//  LIMIT ? OFFSET ?
+	ListOrganizations(ctx context.Context, arg *ListOrganizationsParams) ([]*Organizations, error)
+	//ListOrganizationsByUser
+	//
+	//  SELECT o.id, o.uuid, o.name, o.slug, o.created_at, o.updated_at
+	//  FROM organizations o
+	//  JOIN organization_members m ON m.organization_id = o.id
+	//  WHERE m.user_id = ? AND m.status = 'active'
+	//  ORDER BY o.name
+	//  LIMIT ?
+	ListOrganizationsByUser(ctx context.Context, arg *ListOrganizationsByUserParams) ([]*Organizations, error)
+	//ListPendingModerationAppeals
+	//
+	//  SELECT id, action_id, user_id, statement, status, reviewer_id, response, created_at, decided_at
+	//  FROM moderation_appeals
+	//  WHERE status = 'pending'
+	//  ORDER BY created_at, id
+	//  LIMIT ?
+	ListPendingModerationAppeals(ctx context.Context, limit int32) ([]*ModerationAppeals, error)
+	// lint:ignore missing-limit
+	//
+	//  SELECT id, user_id, session_token, device_info, ip_address, user_agent, country, region, city,
+	//         organization_id, impersonator_id, refresh_token, refresh_expires_at, rotated_refresh_tokens,
+	//         created_at, expires_at, is_active
+	//  FROM user_sessions
+	//  WHERE user_id = ?
+	//  ORDER BY id DESC
+	ListSessionsByUser(ctx context.Context, userID uint64) ([]*UserSessions, error)
+	// The first page's keyset cursor is replaced by a bound beyond any stored value.
+	//
+	//  SELECT id, user_id, event_id, event_type, kind, summary, actor_id, occurred_at
+	//  FROM user_activity
+	//  WHERE user_id = ?
+	//    AND (occurred_at < ?
+	//         OR (occurred_at = ? AND id < ?))
+	//  ORDER BY occurred_at DESC, id DESC
+	//  LIMIT ?
+	ListUserActivityPage(ctx context.Context, arg *ListUserActivityPageParams) ([]*UserActivity, error)
+	//ListUserEvents
+	//
+	//  SELECT user_id, version, event_type, data, occurred_at
+	//  FROM user_events
+	//  WHERE user_id = ?
+	//    AND version > ?
+	//  ORDER BY version
+	ListUserEvents(ctx context.Context, arg *ListUserEventsParams) ([]*UserEvents, error)
+	//ListUserHistory
+	//
+	//  SELECT history_id, user_id, operation, email, username, first_name, last_name, status, role, tags, is_active, is_verified, profile_metadata, valid_from, valid_to FROM users_history
+	//  WHERE user_id = ?
+	//  ORDER BY valid_from DESC
+	//  LIMIT ?
+	ListUserHistory(ctx context.Context, arg *ListUserHistoryParams) ([]*UsersHistory, error)
+	//ListUserIdentities
+	//
+	//  SELECT id, user_id, provider, subject, email, linked_at, last_login_at
+	//  FROM user_identities
+	//  WHERE user_id = ?
+	//  ORDER BY provider
+	ListUserIdentities(ctx context.Context, userID uint64) ([]*UserIdentities, error)
+	// Lists views matching a UserFilter, newest first with the user ID breaking
+	// ties. Empty sets match any view; a negative verified matches both states.
+	// Open time ranges are replaced by bounds beyond any stored value.
+	// Statuses, roles, tag sets and the query terms are JSON arrays of strings.
+	//
+	//  SELECT user_id, uuid, email, username, full_name, status, role, tags, is_verified,
+	//         created_at, source_updated_at, projected_at
+	//  FROM user_read_model
+	//  WHERE (JSON_LENGTH(?) = 0 OR JSON_CONTAINS(?, JSON_QUOTE(status)))
+	//    AND (JSON_LENGTH(?) = 0 OR JSON_CONTAINS(?, JSON_QUOTE(role)))
+	//    AND (CAST(? AS SIGNED) < 0 OR is_verified = CAST(? AS SIGNED))
+	//    AND created_at > ?
+	//    AND created_at < ?
+	//    AND (JSON_LENGTH(?) = 0 OR JSON_OVERLAPS(tags, ?))
+	//    AND JSON_CONTAINS(tags, ?)
+	//    AND NOT EXISTS (
+	//        SELECT 1 FROM JSON_TABLE(?, '$[*]' COLUMNS (term VARCHAR(500) PATH '$')) AS terms
+	//        WHERE user_read_model.search_text NOT LIKE CONCAT('% ', terms.term, '%')
+	//    )
+	//  ORDER BY created_at DESC, user_id DESC
+	//  LIMIT ? OFFSET ?
+	ListUserViews(ctx context.Context, arg *ListUserViewsParams) ([]*ListUserViewsRow, error)
 	//ListUsers
 	//
-	//  SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata FROM users
-	//  WHERE is_active = TRUE
+	//  SELECT id, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, uuid, deleted_at, username_canonical FROM users
+	//  WHERE deleted_at IS NULL
 	//  ORDER BY created_at DESC
 	//  LIMIT ? OFFSET ?
 	ListUsers(ctx context.Context, arg *ListUsersParams) ([]*Users, error)
-	//SoftDeleteUser
+	//MarkUserVerified
 	//
 	//  UPDATE users
-	//  SET is_active = FALSE, updated_at = CURRENT_TIMESTAMP
+	//  SET is_verified = TRUE, updated_at = CURRENT_TIMESTAMP
+	//  WHERE id = ?
+	MarkUserVerified(ctx context.Context, id uint64) error
+	// Permanently removes users soft deleted before the cutoff.
+	//
+	//  DELETE FROM users
+	//  WHERE deleted_at IS NOT NULL AND deleted_at < ?
+	PurgeDeletedUsers(ctx context.Context, cutoff sql.NullTime) (int64, error)
+	//RecordIdentityLogin
+	//
+	//  UPDATE user_identities
+	//  SET email = ?, last_login_at = ?
 	//  WHERE id = ?
+	RecordIdentityLogin(ctx context.Context, arg *RecordIdentityLoginParams) (int64, error)
+	//RecordLoginAttempt
+	//
+	//  INSERT INTO login_history (user_id, ip_address, user_agent, method, failure, created_at)
+	//  VALUES (?, ?, ?, ?, ?, ?)
+	RecordLoginAttempt(ctx context.Context, arg *RecordLoginAttemptParams) (sql.Result, error)
+	// Entries of an event already recorded for the user are ignored.
+	//
+	//  INSERT INTO user_activity (user_id, event_id, event_type, kind, summary, actor_id, occurred_at)
+	//  VALUES (?, ?, ?, ?, ?, ?, ?)
+	//  ON DUPLICATE KEY UPDATE id = id
+	RecordUserActivity(ctx context.Context, arg *RecordUserActivityParams) error
+	// Archives the current version of a user; call in the same transaction
+	// before updating or deleting the row.
+	//
+	//  INSERT INTO users_history (
+	//      user_id, operation, email, username, first_name, last_name,
+	//      status, role, tags, is_active, is_verified, profile_metadata, valid_from
+	//  )
+	//  SELECT id, ?, email, username, first_name, last_name,
+	//         status, role, tags, is_active, is_verified, profile_metadata,
+	//         COALESCE(updated_at, created_at, CURRENT_TIMESTAMP)
+	//  FROM users
+	//  WHERE id = ?
+	RecordUserHistory(ctx context.Context, arg *RecordUserHistoryParams) error
+	//ReleaseIdempotencyKey
+	//
+	//  DELETE FROM idempotency_keys
+	//  WHERE idempotency_key = ? AND completed_at IS NULL
+	ReleaseIdempotencyKey(ctx context.Context, idempotencyKey string) (int64, error)
+	//RemoveUserPreference
+	//
+	//  UPDATE user_preferences
+	//  SET preferences = JSON_REMOVE(preferences, CONCAT('$."', ?, '"')), updated_at = ?
+	//  WHERE user_id = ?
+	RemoveUserPreference(ctx context.Context, arg *RemoveUserPreferenceParams) error
+	//ReserveIdempotencyKey
+	//
+	//  INSERT INTO idempotency_keys (idempotency_key, operation, request_hash, response, created_at, expires_at)
+	//  VALUES (?, ?, ?, '', ?, ?)
+	ReserveIdempotencyKey(ctx context.Context, arg *ReserveIdempotencyKeyParams) error
+	//RestoreUser
+	//
+	//  UPDATE users
+	//  SET is_active = TRUE, deleted_at = NULL, updated_at = CURRENT_TIMESTAMP
+	//  WHERE id = ? AND deleted_at IS NOT NULL
+	RestoreUser(ctx context.Context, id uint64) (int64, error)
+	//RetryJob
+	//
+	//  UPDATE jobs
+	//  SET status = 'pending', run_at = ?, locked_until = NULL,
+	//      last_error = ?, updated_at = ?
+	//  WHERE id = ? AND status = 'running' AND attempts = ?
+	RetryJob(ctx context.Context, arg *RetryJobParams) (int64, error)
+	// A snapshot of the same version is kept.
+	//
+	//  INSERT INTO user_snapshots (user_id, version, deleted, state, created_at)
+	//  VALUES (?, ?, ?, ?, ?)
+	//  ON DUPLICATE KEY UPDATE user_id = user_id
+	SaveUserSnapshot(ctx context.Context, arg *SaveUserSnapshotParams) error
+	// Returns one page of matching users together with facet counts computed
+	// over every match. Facets are JSON columns repeated on each row; a single
+	// row with NULL user columns is returned when the page is empty.
+	// Tags are expanded by index because JSON_TABLE is not understood by sqlc.
+	//
+	//  WITH RECURSIVE tag_index AS (
+	//      SELECT 0 AS n
+	//      UNION ALL
+	//      SELECT n + 1 FROM tag_index WHERE n < 49
+	//  ),
+	//  matched AS (
+	//      SELECT id, uuid, email, username, first_name, last_name, status, role, tags, created_at
+	//      FROM users
+	//      WHERE deleted_at IS NULL
+	//        AND (
+	//            email LIKE CONCAT('%', ?, '%')
+	//            OR username LIKE CONCAT('%', ?, '%')
+	//            OR first_name LIKE CONCAT('%', ?, '%')
+	//            OR last_name LIKE CONCAT('%', ?, '%')
+	//        )
+	//  ),
+	//  status_facets AS (
+	//      SELECT COALESCE(JSON_OBJECTAGG(status, total), JSON_OBJECT()) AS counts
+	//      FROM (SELECT status, COUNT(*) AS total FROM matched GROUP BY status) AS s
+	//  ),
+	//  role_facets AS (
+	//      SELECT COALESCE(JSON_OBJECTAGG(role, total), JSON_OBJECT()) AS counts
+	//      FROM (SELECT role, COUNT(*) AS total FROM matched GROUP BY role) AS r
+	//  ),
+	//  tag_facets AS (
+	//      SELECT COALESCE(JSON_ARRAYAGG(JSON_ARRAY(tag, total)), JSON_ARRAY()) AS counts
+	//      FROM (
+	//          SELECT JSON_UNQUOTE(JSON_EXTRACT(matched.tags, CONCAT('$[', tag_index.n, ']'))) AS tag, COUNT(*) AS total
+	//          FROM matched
+	//          JOIN tag_index ON tag_index.n < JSON_LENGTH(matched.tags)
+	//          GROUP BY tag
+	//          ORDER BY total DESC, tag
+	//          LIMIT ?
+	//      ) AS t
+	//  ),
+	//  page AS (
+	//      SELECT id, uuid, email, username, first_name, last_name, status, role, tags, created_at
+	//      FROM matched
+	//      WHERE ? = '' OR status = ?
+	//      ORDER BY created_at DESC
+	//      LIMIT ?
+	//  )
+	//  SELECT
+	//      page.id, page.uuid, page.email, page.username, page.first_name, page.last_name,
+	//      page.status, page.role, page.tags, page.created_at,
+	//      status_facets.counts AS status_facets,
+	//      role_facets.counts AS role_facets,
+	//      tag_facets.counts AS tag_facets
+	//  FROM status_facets
+	//  CROSS JOIN role_facets
+	//  CROSS JOIN tag_facets
+	//  LEFT JOIN page ON TRUE
+	SearchUsersWithFacets(ctx context.Context, arg *SearchUsersWithFacetsParams) ([]*SearchUsersWithFacetsRow, error)
+	//SetFeatureFlag
+	//
+	//  INSERT INTO feature_flags (name, enabled, updated_at)
+	//  VALUES (?, ?, ?)
+	//  ON DUPLICATE KEY UPDATE enabled = VALUES(enabled), updated_at = VALUES(updated_at)
+	SetFeatureFlag(ctx context.Context, arg *SetFeatureFlagParams) error
+	// Sets one preference; name must be a valid preference key, since it is
+	// quoted into a JSON path.
+	//
+	//  INSERT INTO user_preferences (user_id, preferences, updated_at)
+	//  VALUES (?, JSON_OBJECT(?, CAST(? AS JSON)), ?)
+	//  ON DUPLICATE KEY UPDATE
+	//      preferences = JSON_SET(preferences, CONCAT('$."', ?, '"'), CAST(? AS JSON)),
+	//      updated_at = VALUES(updated_at)
+	SetUserPreference(ctx context.Context, arg *SetUserPreferenceParams) error
+	//SoftDeleteUser
+	//
+	//  UPDATE users
+	//  SET is_active = FALSE, deleted_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+	//  WHERE id = ? AND deleted_at IS NULL
 	SoftDeleteUser(ctx context.Context, id uint64) error
+	//SoftDeleteUsers
+	//
+	//  UPDATE users
+	//  SET is_active = FALSE, deleted_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+	//  WHERE id IN (/*SLICE:ids*/?) AND deleted_at IS NULL
+	SoftDeleteUsers(ctx context.Context, ids []uint64) (int64, error)
+	//UpdateIdentityChange
+	//
+	//  UPDATE identity_changes
+	//  SET status = ?, token = ?, expires_at = ?, updated_at = ?
+	//  WHERE id = ?
+	UpdateIdentityChange(ctx context.Context, arg *UpdateIdentityChangeParams) (int64, error)
 	//UpdateLastLogin
 	//
 	//  UPDATE users
 	//  SET last_login_at = CURRENT_TIMESTAMP
 	//  WHERE id = ?
 	UpdateLastLogin(ctx context.Context, id uint64) error
+	//UpdateMembership
+	//
+	//  UPDATE organization_members
+	//  SET role = ?, status = ?, joined_at = ?
+	//  WHERE organization_id = ? AND user_id = ?
+	UpdateMembership(ctx context.Context, arg *UpdateMembershipParams) (int64, error)
+	//UpdateOrganization
+	//
+	//  UPDATE organizations
+	//  SET name = ?, slug = ?, updated_at = ?
+	//  WHERE id = ?
+	UpdateOrganization(ctx context.Context, arg *UpdateOrganizationParams) (int64, error)
 	//UpdatePassword
 	//
 	//  UPDATE users
 	//  SET password_hash = ?, updated_at = CURRENT_TIMESTAMP
 	//  WHERE id = ?
 	UpdatePassword(ctx context.Context, arg *UpdatePasswordParams) error
+	//UpdateSession
+	//
+	//  UPDATE user_sessions SET
+	//      device_info = ?,
+	//      ip_address = ?,
+	//      user_agent = ?,
+	//      country = ?,
+	//      region = ?,
+	//      city = ?,
+	//      organization_id = ?,
+	//      impersonator_id = ?,
+	//      refresh_token = ?,
+	//      refresh_expires_at = ?,
+	//      rotated_refresh_tokens = ?,
+	//      expires_at = ?,
+	//      is_active = ?
+	//  WHERE id = ?
+	UpdateSession(ctx context.Context, arg *UpdateSessionParams) (int64, error)
+	// Stores the session only while it is active with the expected refresh token,
+	// so that concurrent rotations and revocations are not overwritten.
+	//
+	//  UPDATE user_sessions SET
+	//      device_info = ?,
+	//      ip_address = ?,
+	//      user_agent = ?,
+	//      country = ?,
+	//      region = ?,
+	//      city = ?,
+	//      organization_id = ?,
+	//      impersonator_id = ?,
+	//      refresh_token = ?,
+	//      refresh_expires_at = ?,
+	//      rotated_refresh_tokens = ?,
+	//      expires_at = ?,
+	//      is_active = ?
+	//  WHERE id = ?
+	//    AND refresh_token = ?
+	//    AND is_active = TRUE
+	UpdateSessionIfCurrent(ctx context.Context, arg *UpdateSessionIfCurrentParams) (int64, error)
 	//UpdateUser
 	//
 	//  UPDATE users
@@ -85,15 +963,71 @@ type Querier interface {
 	//      last_name = COALESCE(?, last_name),
 	//      profile_metadata = COALESCE(?, profile_metadata),
 	//      is_active = COALESCE(?, is_active),
-	//      is_verified = COALESCE(?, is_verified)
-	//  WHERE id = ?
+	//      is_verified = COALESCE(?, is_verified),
+	//      status = COALESCE(?, status),
+	//      role = COALESCE(?, role),
+	//      tags = COALESCE(?, tags),
+	//      last_login_at = COALESCE(?, last_login_at),
+	//      username_canonical = ?,
+	//      updated_at = CURRENT_TIMESTAMP
+	//  WHERE id = ? AND deleted_at IS NULL
 	UpdateUser(ctx context.Context, arg *UpdateUserParams) (sql.Result, error)
+	//UpdateUserRole
+	//
+	//  UPDATE users
+	//  SET role = ?, updated_at = CURRENT_TIMESTAMP
+	//  WHERE id = ?
+	UpdateUserRole(ctx context.Context, arg *UpdateUserRoleParams) error
+	//UpdateUserStatus
+	//
+	//  UPDATE users
+	//  SET status = ?, updated_at = CURRENT_TIMESTAMP
+	//  WHERE id = ?
+	UpdateUserStatus(ctx context.Context, arg *UpdateUserStatusParams) error
+	//UpdateUsersStatus
+	//
+	//  UPDATE users
+	//  SET status = ?, updated_at = CURRENT_TIMESTAMP
+	//  WHERE id IN (/*SLICE:ids*/?) AND deleted_at IS NULL
+	UpdateUsersStatus(ctx context.Context, arg *UpdateUsersStatusParams) (int64, error)
+	//UpsertNotificationPreference
+	//
+	//  INSERT INTO notification_preferences (user_id, channel, topic, enabled, target, updated_at)
+	//  VALUES (?, ?, ?, ?, ?, ?)
+	//  ON DUPLICATE KEY UPDATE enabled = VALUES(enabled), target = VALUES(target), updated_at = VALUES(updated_at)
+	UpsertNotificationPreference(ctx context.Context, arg *UpsertNotificationPreferenceParams) error
+	// A view projected from an older version of the user is not stored, so
+	// views projected out of order never go back in time. Assignments are
+	// evaluated in order, so source_updated_at is replaced last.
+	//
+	//  INSERT INTO user_read_model (
+	//      user_id, uuid, email, username, full_name, status, role, tags, is_verified,
+	//      search_text, created_at, source_updated_at, projected_at
+	//  ) VALUES (
+	//      ?, ?, ?, ?, ?,
+	//      ?, ?, ?, ?,
+	//      ?, ?, ?, ?
+	//  )
+	//  ON DUPLICATE KEY UPDATE
+	//      uuid = IF(source_updated_at <= VALUES(source_updated_at), VALUES(uuid), uuid),
+	//      email = IF(source_updated_at <= VALUES(source_updated_at), VALUES(email), email),
+	//      username = IF(source_updated_at <= VALUES(source_updated_at), VALUES(username), username),
+	//      full_name = IF(source_updated_at <= VALUES(source_updated_at), VALUES(full_name), full_name),
+	//      status = IF(source_updated_at <= VALUES(source_updated_at), VALUES(status), status),
+	//      role = IF(source_updated_at <= VALUES(source_updated_at), VALUES(role), role),
+	//      tags = IF(source_updated_at <= VALUES(source_updated_at), VALUES(tags), tags),
+	//      is_verified = IF(source_updated_at <= VALUES(source_updated_at), VALUES(is_verified), is_verified),
+	//      search_text = IF(source_updated_at <= VALUES(source_updated_at), VALUES(search_text), search_text),
+	//      created_at = IF(source_updated_at <= VALUES(source_updated_at), VALUES(created_at), created_at),
+	//      projected_at = IF(source_updated_at <= VALUES(source_updated_at), VALUES(projected_at), projected_at),
+	//      source_updated_at = GREATEST(source_updated_at, VALUES(source_updated_at))
+	UpsertUserView(ctx context.Context, arg *UpsertUserViewParams) error
 	//VerifyUser
 	//
 	//  UPDATE users
 	//  SET is_verified = TRUE, updated_at = CURRENT_TIMESTAMP
 	//  WHERE uuid = ?
-	VerifyUser(ctx context.Context, uuid string) error
+	VerifyUser(ctx context.Context, uuid []byte) error
 }
 
 var _ Querier = (*Queries)(nil)
@@ -11,17 +11,80 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
-
-	"json"
+	"strings"
+	"time"
 )
 
+const ClaimUsersByStatus = `-- name: ClaimUsersByStatus :many
+SELECT id, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, uuid, deleted_at, username_canonical FROM users
+WHERE status = ? AND deleted_at IS NULL
+ORDER BY updated_at, id
+LIMIT ?
+FOR UPDATE SKIP LOCKED
+`
+
+type ClaimUsersByStatusParams struct {
+	Status string `db:"status" json:"status"`
+	Limit  int32  `db:"limit" json:"limit"`
+}
+
+// Work-queue claim: locks the oldest users in a status, skipping rows
+// already claimed by concurrent transactions (MySQL 8.0+).
+//
+//	SELECT id, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, uuid, deleted_at, username_canonical FROM users
+//	WHERE status = ? AND deleted_at IS NULL
+//	ORDER BY updated_at, id
+//	LIMIT ?
+//	FOR UPDATE SKIP LOCKED
+func (q *Queries) ClaimUsersByStatus(ctx context.Context, arg *ClaimUsersByStatusParams) ([]*Users, error) {
+	rows, err := q.db.QueryContext(ctx, ClaimUsersByStatus, arg.Status, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*Users{}
+	for rows.Next() {
+		var i Users
+		if err := rows.Scan(
+			&i.ID,
+			&i.Email,
+			&i.Username,
+			&i.PasswordHash,
+			&i.FirstName,
+			&i.LastName,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.LastLoginAt,
+			&i.IsActive,
+			&i.IsVerified,
+			&i.ProfileMetadata,
+			&i.Status,
+			&i.Role,
+			&i.Tags,
+			&i.UUID,
+			&i.DeletedAt,
+			&i.UsernameCanonical,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const CountActiveUsers = `-- name: CountActiveUsers :one
-SELECT COUNT(*) FROM users WHERE is_active = TRUE
+SELECT COUNT(*) FROM users WHERE deleted_at IS NULL
 `
 
 // CountActiveUsers
 //
-//	SELECT COUNT(*) FROM users WHERE is_active = TRUE
+//	SELECT COUNT(*) FROM users WHERE deleted_at IS NULL
 func (q *Queries) CountActiveUsers(ctx context.Context) (int64, error) {
 	row := q.db.QueryRowContext(ctx, CountActiveUsers)
 	var count int64
@@ -29,33 +92,85 @@ func (q *Queries) CountActiveUsers(ctx context.Context) (int64, error) {
 	return count, err
 }
 
+const CountUsersByStatus = `-- name: CountUsersByStatus :many
+SELECT status, COUNT(*) AS total
+FROM users
+WHERE deleted_at IS NULL
+GROUP BY status
+`
+
+type CountUsersByStatusRow struct {
+	Status string `db:"status" json:"status"`
+	Total  int64  `db:"total" json:"total"`
+}
+
+// CountUsersByStatus
+//
+//	SELECT status, COUNT(*) AS total
+//	FROM users
+//	WHERE deleted_at IS NULL
+//	GROUP BY status
+func (q *Queries) CountUsersByStatus(ctx context.Context) ([]*CountUsersByStatusRow, error) {
+	rows, err := q.db.QueryContext(ctx, CountUsersByStatus)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*CountUsersByStatusRow{}
+	for rows.Next() {
+		var i CountUsersByStatusRow
+		if err := rows.Scan(&i.Status, &i.Total); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const CreateUser = `-- name: CreateUser :execresult
 INSERT INTO users (
-    uuid, email, username, password_hash, 
-    first_name, last_name, profile_metadata, is_active
+    uuid, email, username, password_hash,
+    first_name, last_name, profile_metadata, is_active,
+    is_verified, status, role, tags, created_at, updated_at,
+    username_canonical
 ) VALUES (
-    ?, ?, ?, ?, ?, ?, ?, ?
+    ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
 )
 `
 
 type CreateUserParams struct {
-	UUID            string          `db:"uuid" json:"uuid"`
-	Email           string          `db:"email" json:"email"`
-	Username        string          `db:"username" json:"username"`
-	PasswordHash    string          `db:"password_hash" json:"passwordHash"`
-	FirstName       string          `db:"first_name" json:"firstName"`
-	LastName        string          `db:"last_name" json:"lastName"`
-	ProfileMetadata json.RawMessage `db:"profile_metadata" json:"profileMetadata"`
-	IsActive        sql.NullBool    `db:"is_active" json:"isActive"`
+	UUID              []byte          `db:"uuid" json:"uuid"`
+	Email             string          `db:"email" json:"email"`
+	Username          string          `db:"username" json:"username"`
+	PasswordHash      string          `db:"password_hash" json:"passwordHash"`
+	FirstName         string          `db:"first_name" json:"firstName"`
+	LastName          string          `db:"last_name" json:"lastName"`
+	ProfileMetadata   json.RawMessage `db:"profile_metadata" json:"profileMetadata"`
+	IsActive          sql.NullBool    `db:"is_active" json:"isActive"`
+	IsVerified        sql.NullBool    `db:"is_verified" json:"isVerified"`
+	Status            string          `db:"status" json:"status"`
+	Role              string          `db:"role" json:"role"`
+	Tags              json.RawMessage `db:"tags" json:"tags"`
+	CreatedAt         sql.NullTime    `db:"created_at" json:"createdAt"`
+	UpdatedAt         sql.NullTime    `db:"updated_at" json:"updatedAt"`
+	UsernameCanonical string          `db:"username_canonical" json:"usernameCanonical"`
 }
 
 // CreateUser
 //
 //	INSERT INTO users (
 //	    uuid, email, username, password_hash,
-//	    first_name, last_name, profile_metadata, is_active
+//	    first_name, last_name, profile_metadata, is_active,
+//	    is_verified, status, role, tags, created_at, updated_at,
+//	    username_canonical
 //	) VALUES (
-//	    ?, ?, ?, ?, ?, ?, ?, ?
+//	    ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
 //	)
 func (q *Queries) CreateUser(ctx context.Context, arg *CreateUserParams) (sql.Result, error) {
 	return q.db.ExecContext(ctx, CreateUser,
@@ -67,22 +182,257 @@ func (q *Queries) CreateUser(ctx context.Context, arg *CreateUserParams) (sql.Re
 		arg.LastName,
 		arg.ProfileMetadata,
 		arg.IsActive,
+		arg.IsVerified,
+		arg.Status,
+		arg.Role,
+		arg.Tags,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.UsernameCanonical,
 	)
 }
 
+const DeleteUserHistory = `-- name: DeleteUserHistory :execrows
+DELETE FROM users_history
+WHERE user_id = ?
+`
+
+// Removes every archived version of a user.
+//
+//	DELETE FROM users_history
+//	WHERE user_id = ?
+func (q *Queries) DeleteUserHistory(ctx context.Context, userID uint64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, DeleteUserHistory, userID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const FilterUsers = `-- name: FilterUsers :many
+SELECT id, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, uuid, deleted_at, username_canonical FROM users
+WHERE deleted_at IS NULL
+  AND (JSON_LENGTH(?) = 0 OR JSON_CONTAINS(?, JSON_QUOTE(status)))
+  AND (JSON_LENGTH(?) = 0 OR JSON_CONTAINS(?, JSON_QUOTE(role)))
+  AND (CAST(? AS SIGNED) < 0 OR is_verified = CAST(? AS SIGNED))
+  AND created_at > ?
+  AND created_at < ?
+  AND (JSON_LENGTH(?) = 0 OR JSON_OVERLAPS(tags, ?))
+  AND JSON_CONTAINS(tags, ?)
+  AND (? = ''
+       OR MATCH (email, username, first_name, last_name) AGAINST (? IN BOOLEAN MODE))
+  AND (created_at < ?
+       OR (created_at = ? AND id < ?))
+ORDER BY created_at DESC, id DESC
+LIMIT ? OFFSET ?
+`
+
+type FilterUsersParams struct {
+	Statuses        string       `db:"statuses" json:"statuses"`
+	Roles           string       `db:"roles" json:"roles"`
+	Verified        int64        `db:"verified" json:"verified"`
+	CreatedAfter    sql.NullTime `db:"created_after" json:"createdAfter"`
+	CreatedBefore   sql.NullTime `db:"created_before" json:"createdBefore"`
+	TagsAny         string       `db:"tags_any" json:"tagsAny"`
+	TagsAll         string       `db:"tags_all" json:"tagsAll"`
+	Query           string       `db:"query" json:"query"`
+	CursorCreatedAt sql.NullTime `db:"cursor_created_at" json:"cursorCreatedAt"`
+	CursorID        uint64       `db:"cursor_id" json:"cursorId"`
+	Limit           int32        `db:"limit" json:"limit"`
+	Offset          int32        `db:"offset" json:"offset"`
+}
+
+// Lists users matching a UserFilter, newest first with the ID breaking ties.
+// Empty sets and an empty query match any user; a negative verified matches
+// both states. Open time ranges and the first page's keyset cursor are
+// replaced by bounds beyond any stored value.
+// Statuses, roles and tag sets are JSON arrays of strings; the query is a
+// boolean-mode search expression.
+//
+//	SELECT id, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, uuid, deleted_at, username_canonical FROM users
+//	WHERE deleted_at IS NULL
+//	  AND (JSON_LENGTH(?) = 0 OR JSON_CONTAINS(?, JSON_QUOTE(status)))
+//	  AND (JSON_LENGTH(?) = 0 OR JSON_CONTAINS(?, JSON_QUOTE(role)))
+//	  AND (CAST(? AS SIGNED) < 0 OR is_verified = CAST(? AS SIGNED))
+//	  AND created_at > ?
+//	  AND created_at < ?
+//	  AND (JSON_LENGTH(?) = 0 OR JSON_OVERLAPS(tags, ?))
+//	  AND JSON_CONTAINS(tags, ?)
+//	  AND (? = ''
+//	       OR MATCH (email, username, first_name, last_name) AGAINST (? IN BOOLEAN MODE))
+//	  AND (created_at < ?
+//	       OR (created_at = ? AND id < ?))
+//	ORDER BY created_at DESC, id DESC
+//	LIMIT ? OFFSET ?
+func (q *Queries) FilterUsers(ctx context.Context, arg *FilterUsersParams) ([]*Users, error) {
+	rows, err := q.db.QueryContext(ctx, FilterUsers,
+		arg.Statuses,
+		arg.Statuses,
+		arg.Roles,
+		arg.Roles,
+		arg.Verified,
+		arg.Verified,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.TagsAny,
+		arg.TagsAny,
+		arg.TagsAll,
+		arg.Query,
+		arg.Query,
+		arg.CursorCreatedAt,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*Users{}
+	for rows.Next() {
+		var i Users
+		if err := rows.Scan(
+			&i.ID,
+			&i.Email,
+			&i.Username,
+			&i.PasswordHash,
+			&i.FirstName,
+			&i.LastName,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.LastLoginAt,
+			&i.IsActive,
+			&i.IsVerified,
+			&i.ProfileMetadata,
+			&i.Status,
+			&i.Role,
+			&i.Tags,
+			&i.UUID,
+			&i.DeletedAt,
+			&i.UsernameCanonical,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const GetUserAsOf = `-- name: GetUserAsOf :one
+SELECT user_id, operation, email, username, first_name, last_name, status, role, tags,
+       is_active, is_verified, valid_from, valid_to
+FROM (
+    SELECT u.id AS user_id, 'current' AS operation, u.email, u.username, u.first_name, u.last_name,
+           u.status, u.role, u.tags, u.is_active, u.is_verified,
+           COALESCE(u.updated_at, u.created_at) AS valid_from,
+           NULLIF(u.created_at, u.created_at) AS valid_to, 1 AS is_current
+    FROM users u
+    WHERE u.id = ?
+      AND COALESCE(u.updated_at, u.created_at) <= CAST(? AS DATETIME)
+    UNION ALL
+    SELECT h.user_id, h.operation, h.email, h.username, h.first_name, h.last_name,
+           h.status, h.role, h.tags, h.is_active, h.is_verified,
+           h.valid_from, h.valid_to, 0
+    FROM users_history h
+    WHERE h.user_id = ?
+      AND h.valid_from <= CAST(? AS DATETIME)
+      AND h.valid_to > CAST(? AS DATETIME)
+) AS versions
+ORDER BY is_current, valid_from DESC
+LIMIT 1
+`
+
+type GetUserAsOfParams struct {
+	UserID uint64    `db:"user_id" json:"userId"`
+	AsOf   time.Time `db:"as_of" json:"asOf"`
+}
+
+type GetUserAsOfRow struct {
+	UserID     uint64          `db:"user_id" json:"userId"`
+	Operation  string          `db:"operation" json:"operation"`
+	Email      string          `db:"email" json:"email"`
+	Username   string          `db:"username" json:"username"`
+	FirstName  string          `db:"first_name" json:"firstName"`
+	LastName   string          `db:"last_name" json:"lastName"`
+	Status     string          `db:"status" json:"status"`
+	Role       string          `db:"role" json:"role"`
+	Tags       json.RawMessage `db:"tags" json:"tags"`
+	IsActive   sql.NullBool    `db:"is_active" json:"isActive"`
+	IsVerified sql.NullBool    `db:"is_verified" json:"isVerified"`
+	ValidFrom  sql.NullTime    `db:"valid_from" json:"validFrom"`
+	ValidTo    interface{}     `db:"valid_to" json:"validTo"`
+}
+
+// Returns the version of a user that was valid at the given time, preferring
+// archived versions over the current row.
+//
+//	SELECT user_id, operation, email, username, first_name, last_name, status, role, tags,
+//	       is_active, is_verified, valid_from, valid_to
+//	FROM (
+//	    SELECT u.id AS user_id, 'current' AS operation, u.email, u.username, u.first_name, u.last_name,
+//	           u.status, u.role, u.tags, u.is_active, u.is_verified,
+//	           COALESCE(u.updated_at, u.created_at) AS valid_from,
+//	           NULLIF(u.created_at, u.created_at) AS valid_to, 1 AS is_current
+//	    FROM users u
+//	    WHERE u.id = ?
+//	      AND COALESCE(u.updated_at, u.created_at) <= CAST(? AS DATETIME)
+//	    UNION ALL
+//	    SELECT h.user_id, h.operation, h.email, h.username, h.first_name, h.last_name,
+//	           h.status, h.role, h.tags, h.is_active, h.is_verified,
+//	           h.valid_from, h.valid_to, 0
+//	    FROM users_history h
+//	    WHERE h.user_id = ?
+//	      AND h.valid_from <= CAST(? AS DATETIME)
+//	      AND h.valid_to > CAST(? AS DATETIME)
+//	) AS versions
+//	ORDER BY is_current, valid_from DESC
+//	LIMIT 1
+func (q *Queries) GetUserAsOf(ctx context.Context, arg *GetUserAsOfParams) (*GetUserAsOfRow, error) {
+	row := q.db.QueryRowContext(ctx, GetUserAsOf,
+		arg.UserID,
+		arg.AsOf,
+		arg.UserID,
+		arg.AsOf,
+		arg.AsOf,
+	)
+	var i GetUserAsOfRow
+	err := row.Scan(
+		&i.UserID,
+		&i.Operation,
+		&i.Email,
+		&i.Username,
+		&i.FirstName,
+		&i.LastName,
+		&i.Status,
+		&i.Role,
+		&i.Tags,
+		&i.IsActive,
+		&i.IsVerified,
+		&i.ValidFrom,
+		&i.ValidTo,
+	)
+	return &i, err
+}
+
 const GetUserByEmail = `-- name: GetUserByEmail :one
-SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata FROM users WHERE email = ? AND is_active = TRUE
+SELECT id, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, uuid, deleted_at, username_canonical FROM users WHERE email = ? AND deleted_at IS NULL
 `
 
 // GetUserByEmail
 //
-//	SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata FROM users WHERE email = ? AND is_active = TRUE
+//	SELECT id, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, uuid, deleted_at, username_canonical FROM users WHERE email = ? AND deleted_at IS NULL
 func (q *Queries) GetUserByEmail(ctx context.Context, email string) (*Users, error) {
 	row := q.db.QueryRowContext(ctx, GetUserByEmail, email)
 	var i Users
 	err := row.Scan(
 		&i.ID,
-		&i.UUID,
 		&i.Email,
 		&i.Username,
 		&i.PasswordHash,
@@ -94,23 +444,61 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (*Users, err
 		&i.IsActive,
 		&i.IsVerified,
 		&i.ProfileMetadata,
+		&i.Status,
+		&i.Role,
+		&i.Tags,
+		&i.UUID,
+		&i.DeletedAt,
+		&i.UsernameCanonical,
 	)
 	return &i, err
 }
 
 const GetUserByID = `-- name: GetUserByID :one
-SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata FROM users WHERE id = ? AND is_active = TRUE
+SELECT id, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, uuid, deleted_at, username_canonical FROM users WHERE id = ? AND deleted_at IS NULL
 `
 
 // GetUserByID
 //
-//	SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata FROM users WHERE id = ? AND is_active = TRUE
+//	SELECT id, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, uuid, deleted_at, username_canonical FROM users WHERE id = ? AND deleted_at IS NULL
 func (q *Queries) GetUserByID(ctx context.Context, id uint64) (*Users, error) {
 	row := q.db.QueryRowContext(ctx, GetUserByID, id)
 	var i Users
 	err := row.Scan(
 		&i.ID,
+		&i.Email,
+		&i.Username,
+		&i.PasswordHash,
+		&i.FirstName,
+		&i.LastName,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.LastLoginAt,
+		&i.IsActive,
+		&i.IsVerified,
+		&i.ProfileMetadata,
+		&i.Status,
+		&i.Role,
+		&i.Tags,
 		&i.UUID,
+		&i.DeletedAt,
+		&i.UsernameCanonical,
+	)
+	return &i, err
+}
+
+const GetUserByIDForUpdate = `-- name: GetUserByIDForUpdate :one
+SELECT id, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, uuid, deleted_at, username_canonical FROM users WHERE id = ? AND deleted_at IS NULL LIMIT 1 FOR UPDATE
+`
+
+// Locks the user row until the surrounding transaction ends.
+//
+//	SELECT id, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, uuid, deleted_at, username_canonical FROM users WHERE id = ? AND deleted_at IS NULL LIMIT 1 FOR UPDATE
+func (q *Queries) GetUserByIDForUpdate(ctx context.Context, id uint64) (*Users, error) {
+	row := q.db.QueryRowContext(ctx, GetUserByIDForUpdate, id)
+	var i Users
+	err := row.Scan(
+		&i.ID,
 		&i.Email,
 		&i.Username,
 		&i.PasswordHash,
@@ -122,23 +510,28 @@ func (q *Queries) GetUserByID(ctx context.Context, id uint64) (*Users, error) {
 		&i.IsActive,
 		&i.IsVerified,
 		&i.ProfileMetadata,
+		&i.Status,
+		&i.Role,
+		&i.Tags,
+		&i.UUID,
+		&i.DeletedAt,
+		&i.UsernameCanonical,
 	)
 	return &i, err
 }
 
 const GetUserByUUID = `-- name: GetUserByUUID :one
-SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata FROM users WHERE uuid = ? AND is_active = TRUE
+SELECT id, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, uuid, deleted_at, username_canonical FROM users WHERE uuid = ? AND deleted_at IS NULL
 `
 
 // GetUserByUUID
 //
-//	SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata FROM users WHERE uuid = ? AND is_active = TRUE
-func (q *Queries) GetUserByUUID(ctx context.Context, uuid string) (*Users, error) {
+//	SELECT id, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, uuid, deleted_at, username_canonical FROM users WHERE uuid = ? AND deleted_at IS NULL
+func (q *Queries) GetUserByUUID(ctx context.Context, uuid []byte) (*Users, error) {
 	row := q.db.QueryRowContext(ctx, GetUserByUUID, uuid)
 	var i Users
 	err := row.Scan(
 		&i.ID,
-		&i.UUID,
 		&i.Email,
 		&i.Username,
 		&i.PasswordHash,
@@ -150,23 +543,28 @@ func (q *Queries) GetUserByUUID(ctx context.Context, uuid string) (*Users, error
 		&i.IsActive,
 		&i.IsVerified,
 		&i.ProfileMetadata,
+		&i.Status,
+		&i.Role,
+		&i.Tags,
+		&i.UUID,
+		&i.DeletedAt,
+		&i.UsernameCanonical,
 	)
 	return &i, err
 }
 
 const GetUserByUsername = `-- name: GetUserByUsername :one
-SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata FROM users WHERE username = ? AND is_active = TRUE
+SELECT id, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, uuid, deleted_at, username_canonical FROM users WHERE username_canonical = ? AND deleted_at IS NULL
 `
 
 // GetUserByUsername
 //
-//	SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata FROM users WHERE username = ? AND is_active = TRUE
-func (q *Queries) GetUserByUsername(ctx context.Context, username string) (*Users, error) {
-	row := q.db.QueryRowContext(ctx, GetUserByUsername, username)
+//	SELECT id, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, uuid, deleted_at, username_canonical FROM users WHERE username_canonical = ? AND deleted_at IS NULL
+func (q *Queries) GetUserByUsername(ctx context.Context, usernameCanonical string) (*Users, error) {
+	row := q.db.QueryRowContext(ctx, GetUserByUsername, usernameCanonical)
 	var i Users
 	err := row.Scan(
 		&i.ID,
-		&i.UUID,
 		&i.Email,
 		&i.Username,
 		&i.PasswordHash,
@@ -178,33 +576,96 @@ func (q *Queries) GetUserByUsername(ctx context.Context, username string) (*User
 		&i.IsActive,
 		&i.IsVerified,
 		&i.ProfileMetadata,
+		&i.Status,
+		&i.Role,
+		&i.Tags,
+		&i.UUID,
+		&i.DeletedAt,
+		&i.UsernameCanonical,
 	)
 	return &i, err
 }
 
+const GetUserIDsByUUIDs = `-- name: GetUserIDsByUUIDs :many
+SELECT id, uuid FROM users WHERE uuid IN (/*SLICE:uuids*/?)
+`
+
+type GetUserIDsByUUIDsRow struct {
+	ID   uint64 `db:"id" json:"id"`
+	UUID []byte `db:"uuid" json:"uuid"`
+}
+
+// GetUserIDsByUUIDs
+//
+//	SELECT id, uuid FROM users WHERE uuid IN (/*SLICE:uuids*/?)
+func (q *Queries) GetUserIDsByUUIDs(ctx context.Context, uuids [][]byte) ([]*GetUserIDsByUUIDsRow, error) {
+	query := GetUserIDsByUUIDs
+	var queryParams []interface{}
+	if len(uuids) > 0 {
+		for _, v := range uuids {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:uuids*/?", strings.Repeat(",?", len(uuids))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:uuids*/?", "NULL", 1)
+	}
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*GetUserIDsByUUIDsRow{}
+	for rows.Next() {
+		var i GetUserIDsByUUIDsRow
+		if err := rows.Scan(&i.ID, &i.UUID); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const GetUserStats = `-- name: GetUserStats :one
 SELECT 
     COUNT(*) as total_users,
-    SUM(CASE WHEN is_active = TRUE THEN 1 ELSE 0 END) as active_users,
-    SUM(CASE WHEN is_verified = TRUE THEN 1 ELSE 0 END) as verified_users,
-    SUM(CASE WHEN last_login_at IS NOT NULL THEN 1 ELSE 0 END) as users_with_logins
+    COUNT(CASE WHEN deleted_at IS NULL THEN 1 END) as active_users,
+    COUNT(CASE WHEN is_verified = TRUE THEN 1 END) as verified_users,
+    COUNT(CASE WHEN last_login_at IS NOT NULL THEN 1 END) as users_with_logins,
+    COUNT(CASE WHEN status = 'inactive' THEN 1 END) as inactive_users,
+    COUNT(CASE WHEN status = 'suspended' THEN 1 END) as suspended_users,
+    COUNT(CASE WHEN created_at >= NOW() - INTERVAL 30 DAY THEN 1 END) as new_users_30d,
+    COUNT(CASE WHEN created_at >= NOW() - INTERVAL 7 DAY THEN 1 END) as new_users_7d
 FROM users
 `
 
 type GetUserStatsRow struct {
-	TotalUsers      int64       `db:"total_users" json:"totalUsers"`
-	ActiveUsers     interface{} `db:"active_users" json:"activeUsers"`
-	VerifiedUsers   interface{} `db:"verified_users" json:"verifiedUsers"`
-	UsersWithLogins interface{} `db:"users_with_logins" json:"usersWithLogins"`
+	TotalUsers      int64 `db:"total_users" json:"totalUsers"`
+	ActiveUsers     int64 `db:"active_users" json:"activeUsers"`
+	VerifiedUsers   int64 `db:"verified_users" json:"verifiedUsers"`
+	UsersWithLogins int64 `db:"users_with_logins" json:"usersWithLogins"`
+	InactiveUsers   int64 `db:"inactive_users" json:"inactiveUsers"`
+	SuspendedUsers  int64 `db:"suspended_users" json:"suspendedUsers"`
+	NewUsers30d     int64 `db:"new_users_30d" json:"newUsers30d"`
+	NewUsers7d      int64 `db:"new_users_7d" json:"newUsers7d"`
 }
 
 // GetUserStats
 //
 //	SELECT
 //	    COUNT(*) as total_users,
-//	    SUM(CASE WHEN is_active = TRUE THEN 1 ELSE 0 END) as active_users,
-//	    SUM(CASE WHEN is_verified = TRUE THEN 1 ELSE 0 END) as verified_users,
-//	    SUM(CASE WHEN last_login_at IS NOT NULL THEN 1 ELSE 0 END) as users_with_logins
+//	    COUNT(CASE WHEN deleted_at IS NULL THEN 1 END) as active_users,
+//	    COUNT(CASE WHEN is_verified = TRUE THEN 1 END) as verified_users,
+//	    COUNT(CASE WHEN last_login_at IS NOT NULL THEN 1 END) as users_with_logins,
+//	    COUNT(CASE WHEN status = 'inactive' THEN 1 END) as inactive_users,
+//	    COUNT(CASE WHEN status = 'suspended' THEN 1 END) as suspended_users,
+//	    COUNT(CASE WHEN created_at >= NOW() - INTERVAL 30 DAY THEN 1 END) as new_users_30d,
+//	    COUNT(CASE WHEN created_at >= NOW() - INTERVAL 7 DAY THEN 1 END) as new_users_7d
 //	FROM users
 func (q *Queries) GetUserStats(ctx context.Context) (*GetUserStatsRow, error) {
 	row := q.db.QueryRowContext(ctx, GetUserStats)
@@ -214,13 +675,133 @@ func (q *Queries) GetUserStats(ctx context.Context) (*GetUserStatsRow, error) {
 		&i.ActiveUsers,
 		&i.VerifiedUsers,
 		&i.UsersWithLogins,
+		&i.InactiveUsers,
+		&i.SuspendedUsers,
+		&i.NewUsers30d,
+		&i.NewUsers7d,
 	)
 	return &i, err
 }
 
+const GetUsersByIDs = `-- name: GetUsersByIDs :many
+SELECT id, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, uuid, deleted_at, username_canonical FROM users WHERE id IN (/*SLICE:ids*/?) AND deleted_at IS NULL
+`
+
+// GetUsersByIDs
+//
+//	SELECT id, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, uuid, deleted_at, username_canonical FROM users WHERE id IN (/*SLICE:ids*/?) AND deleted_at IS NULL
+func (q *Queries) GetUsersByIDs(ctx context.Context, ids []uint64) ([]*Users, error) {
+	query := GetUsersByIDs
+	var queryParams []interface{}
+	if len(ids) > 0 {
+		for _, v := range ids {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:ids*/?", strings.Repeat(",?", len(ids))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:ids*/?", "NULL", 1)
+	}
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*Users{}
+	for rows.Next() {
+		var i Users
+		if err := rows.Scan(
+			&i.ID,
+			&i.Email,
+			&i.Username,
+			&i.PasswordHash,
+			&i.FirstName,
+			&i.LastName,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.LastLoginAt,
+			&i.IsActive,
+			&i.IsVerified,
+			&i.ProfileMetadata,
+			&i.Status,
+			&i.Role,
+			&i.Tags,
+			&i.UUID,
+			&i.DeletedAt,
+			&i.UsernameCanonical,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const ListUserHistory = `-- name: ListUserHistory :many
+SELECT history_id, user_id, operation, email, username, first_name, last_name, status, role, tags, is_active, is_verified, profile_metadata, valid_from, valid_to FROM users_history
+WHERE user_id = ?
+ORDER BY valid_from DESC
+LIMIT ?
+`
+
+type ListUserHistoryParams struct {
+	UserID uint64 `db:"user_id" json:"userId"`
+	Limit  int32  `db:"limit" json:"limit"`
+}
+
+// ListUserHistory
+//
+//	SELECT history_id, user_id, operation, email, username, first_name, last_name, status, role, tags, is_active, is_verified, profile_metadata, valid_from, valid_to FROM users_history
+//	WHERE user_id = ?
+//	ORDER BY valid_from DESC
+//	LIMIT ?
+func (q *Queries) ListUserHistory(ctx context.Context, arg *ListUserHistoryParams) ([]*UsersHistory, error) {
+	rows, err := q.db.QueryContext(ctx, ListUserHistory, arg.UserID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*UsersHistory{}
+	for rows.Next() {
+		var i UsersHistory
+		if err := rows.Scan(
+			&i.HistoryID,
+			&i.UserID,
+			&i.Operation,
+			&i.Email,
+			&i.Username,
+			&i.FirstName,
+			&i.LastName,
+			&i.Status,
+			&i.Role,
+			&i.Tags,
+			&i.IsActive,
+			&i.IsVerified,
+			&i.ProfileMetadata,
+			&i.ValidFrom,
+			&i.ValidTo,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const ListUsers = `-- name: ListUsers :many
-SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata FROM users 
-WHERE is_active = TRUE 
+SELECT id, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, uuid, deleted_at, username_canonical FROM users 
+WHERE deleted_at IS NULL 
 ORDER BY created_at DESC
 LIMIT ? OFFSET ?
 `
@@ -232,8 +813,8 @@ type ListUsersParams struct {
 
 // ListUsers
 //
-//	SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata FROM users
-//	WHERE is_active = TRUE
+//	SELECT id, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, uuid, deleted_at, username_canonical FROM users
+//	WHERE deleted_at IS NULL
 //	ORDER BY created_at DESC
 //	LIMIT ? OFFSET ?
 func (q *Queries) ListUsers(ctx context.Context, arg *ListUsersParams) ([]*Users, error) {
@@ -247,7 +828,6 @@ func (q *Queries) ListUsers(ctx context.Context, arg *ListUsersParams) ([]*Users
 		var i Users
 		if err := rows.Scan(
 			&i.ID,
-			&i.UUID,
 			&i.Email,
 			&i.Username,
 			&i.PasswordHash,
@@ -259,6 +839,12 @@ func (q *Queries) ListUsers(ctx context.Context, arg *ListUsersParams) ([]*Users
 			&i.IsActive,
 			&i.IsVerified,
 			&i.ProfileMetadata,
+			&i.Status,
+			&i.Role,
+			&i.Tags,
+			&i.UUID,
+			&i.DeletedAt,
+			&i.UsernameCanonical,
 		); err != nil {
 			return nil, err
 		}
@@ -273,22 +859,318 @@ func (q *Queries) ListUsers(ctx context.Context, arg *ListUsersParams) ([]*Users
 	return items, nil
 }
 
-const SoftDeleteUser = `-- name: SoftDeleteUser :exec
-UPDATE users 
-SET is_active = FALSE, updated_at = CURRENT_TIMESTAMP
+const MarkUserVerified = `-- name: MarkUserVerified :exec
+UPDATE users
+SET is_verified = TRUE, updated_at = CURRENT_TIMESTAMP
 WHERE id = ?
 `
 
-// SoftDeleteUser
+// MarkUserVerified
 //
 //	UPDATE users
-//	SET is_active = FALSE, updated_at = CURRENT_TIMESTAMP
+//	SET is_verified = TRUE, updated_at = CURRENT_TIMESTAMP
 //	WHERE id = ?
+func (q *Queries) MarkUserVerified(ctx context.Context, id uint64) error {
+	_, err := q.db.ExecContext(ctx, MarkUserVerified, id)
+	return err
+}
+
+const PurgeDeletedUsers = `-- name: PurgeDeletedUsers :execrows
+DELETE FROM users
+WHERE deleted_at IS NOT NULL AND deleted_at < ?
+`
+
+// Permanently removes users soft deleted before the cutoff.
+//
+//	DELETE FROM users
+//	WHERE deleted_at IS NOT NULL AND deleted_at < ?
+func (q *Queries) PurgeDeletedUsers(ctx context.Context, cutoff sql.NullTime) (int64, error) {
+	result, err := q.db.ExecContext(ctx, PurgeDeletedUsers, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const RecordUserHistory = `-- name: RecordUserHistory :exec
+INSERT INTO users_history (
+    user_id, operation, email, username, first_name, last_name,
+    status, role, tags, is_active, is_verified, profile_metadata, valid_from
+)
+SELECT id, ?, email, username, first_name, last_name,
+       status, role, tags, is_active, is_verified, profile_metadata,
+       COALESCE(updated_at, created_at, CURRENT_TIMESTAMP)
+FROM users
+WHERE id = ?
+`
+
+type RecordUserHistoryParams struct {
+	Operation string `db:"operation" json:"operation"`
+	UserID    uint64 `db:"user_id" json:"userId"`
+}
+
+// Archives the current version of a user; call in the same transaction
+// before updating or deleting the row.
+//
+//	INSERT INTO users_history (
+//	    user_id, operation, email, username, first_name, last_name,
+//	    status, role, tags, is_active, is_verified, profile_metadata, valid_from
+//	)
+//	SELECT id, ?, email, username, first_name, last_name,
+//	       status, role, tags, is_active, is_verified, profile_metadata,
+//	       COALESCE(updated_at, created_at, CURRENT_TIMESTAMP)
+//	FROM users
+//	WHERE id = ?
+func (q *Queries) RecordUserHistory(ctx context.Context, arg *RecordUserHistoryParams) error {
+	_, err := q.db.ExecContext(ctx, RecordUserHistory, arg.Operation, arg.UserID)
+	return err
+}
+
+const RestoreUser = `-- name: RestoreUser :execrows
+UPDATE users
+SET is_active = TRUE, deleted_at = NULL, updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND deleted_at IS NOT NULL
+`
+
+// RestoreUser
+//
+//	UPDATE users
+//	SET is_active = TRUE, deleted_at = NULL, updated_at = CURRENT_TIMESTAMP
+//	WHERE id = ? AND deleted_at IS NOT NULL
+func (q *Queries) RestoreUser(ctx context.Context, id uint64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, RestoreUser, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const SearchUsersWithFacets = `-- name: SearchUsersWithFacets :many
+WITH RECURSIVE tag_index AS (
+    SELECT 0 AS n
+    UNION ALL
+    SELECT n + 1 FROM tag_index WHERE n < 49
+),
+matched AS (
+    SELECT id, uuid, email, username, first_name, last_name, status, role, tags, created_at
+    FROM users
+    WHERE deleted_at IS NULL
+      AND (
+          email LIKE CONCAT('%', ?, '%')
+          OR username LIKE CONCAT('%', ?, '%')
+          OR first_name LIKE CONCAT('%', ?, '%')
+          OR last_name LIKE CONCAT('%', ?, '%')
+      )
+),
+status_facets AS (
+    SELECT COALESCE(JSON_OBJECTAGG(status, total), JSON_OBJECT()) AS counts
+    FROM (SELECT status, COUNT(*) AS total FROM matched GROUP BY status) AS s
+),
+role_facets AS (
+    SELECT COALESCE(JSON_OBJECTAGG(role, total), JSON_OBJECT()) AS counts
+    FROM (SELECT role, COUNT(*) AS total FROM matched GROUP BY role) AS r
+),
+tag_facets AS (
+    SELECT COALESCE(JSON_ARRAYAGG(JSON_ARRAY(tag, total)), JSON_ARRAY()) AS counts
+    FROM (
+        SELECT JSON_UNQUOTE(JSON_EXTRACT(matched.tags, CONCAT('$[', tag_index.n, ']'))) AS tag, COUNT(*) AS total
+        FROM matched
+        JOIN tag_index ON tag_index.n < JSON_LENGTH(matched.tags)
+        GROUP BY tag
+        ORDER BY total DESC, tag
+        LIMIT ?
+    ) AS t
+),
+page AS (
+    SELECT id, uuid, email, username, first_name, last_name, status, role, tags, created_at
+    FROM matched
+    WHERE ? = '' OR status = ?
+    ORDER BY created_at DESC
+    LIMIT ?
+)
+SELECT
+    page.id, page.uuid, page.email, page.username, page.first_name, page.last_name,
+    page.status, page.role, page.tags, page.created_at,
+    status_facets.counts AS status_facets,
+    role_facets.counts AS role_facets,
+    tag_facets.counts AS tag_facets
+FROM status_facets
+CROSS JOIN role_facets
+CROSS JOIN tag_facets
+LEFT JOIN page ON TRUE
+`
+
+type SearchUsersWithFacetsParams struct {
+	Query   interface{} `db:"query" json:"query"`
+	Limit   int32       `db:"limit" json:"limit"`
+	Status  string      `db:"status" json:"status"`
+	Limit_2 int32       `db:"limit_2" json:"limit2"`
+}
+
+type SearchUsersWithFacetsRow struct {
+	ID           sql.NullInt64   `db:"id" json:"id"`
+	UUID         sql.NullString  `db:"uuid" json:"uuid"`
+	Email        sql.NullString  `db:"email" json:"email"`
+	Username     sql.NullString  `db:"username" json:"username"`
+	FirstName    sql.NullString  `db:"first_name" json:"firstName"`
+	LastName     sql.NullString  `db:"last_name" json:"lastName"`
+	Status       sql.NullString  `db:"status" json:"status"`
+	Role         sql.NullString  `db:"role" json:"role"`
+	Tags         json.RawMessage `db:"tags" json:"tags"`
+	CreatedAt    sql.NullTime    `db:"created_at" json:"createdAt"`
+	StatusFacets interface{}     `db:"status_facets" json:"statusFacets"`
+	RoleFacets   interface{}     `db:"role_facets" json:"roleFacets"`
+	TagFacets    interface{}     `db:"tag_facets" json:"tagFacets"`
+}
+
+// Returns one page of matching users together with facet counts computed
+// over every match. Facets are JSON columns repeated on each row; a single
+// row with NULL user columns is returned when the page is empty.
+// Tags are expanded by index because JSON_TABLE is not understood by sqlc.
+//
+//	WITH RECURSIVE tag_index AS (
+//	    SELECT 0 AS n
+//	    UNION ALL
+//	    SELECT n + 1 FROM tag_index WHERE n < 49
+//	),
+//	matched AS (
+//	    SELECT id, uuid, email, username, first_name, last_name, status, role, tags, created_at
+//	    FROM users
+//	    WHERE deleted_at IS NULL
+//	      AND (
+//	          email LIKE CONCAT('%', ?, '%')
+//	          OR username LIKE CONCAT('%', ?, '%')
+//	          OR first_name LIKE CONCAT('%', ?, '%')
+//	          OR last_name LIKE CONCAT('%', ?, '%')
+//	      )
+//	),
+//	status_facets AS (
+//	    SELECT COALESCE(JSON_OBJECTAGG(status, total), JSON_OBJECT()) AS counts
+//	    FROM (SELECT status, COUNT(*) AS total FROM matched GROUP BY status) AS s
+//	),
+//	role_facets AS (
+//	    SELECT COALESCE(JSON_OBJECTAGG(role, total), JSON_OBJECT()) AS counts
+//	    FROM (SELECT role, COUNT(*) AS total FROM matched GROUP BY role) AS r
+//	),
+//	tag_facets AS (
+//	    SELECT COALESCE(JSON_ARRAYAGG(JSON_ARRAY(tag, total)), JSON_ARRAY()) AS counts
+//	    FROM (
+//	        SELECT JSON_UNQUOTE(JSON_EXTRACT(matched.tags, CONCAT('$[', tag_index.n, ']'))) AS tag, COUNT(*) AS total
+//	        FROM matched
+//	        JOIN tag_index ON tag_index.n < JSON_LENGTH(matched.tags)
+//	        GROUP BY tag
+//	        ORDER BY total DESC, tag
+//	        LIMIT ?
+//	    ) AS t
+//	),
+//	page AS (
+//	    SELECT id, uuid, email, username, first_name, last_name, status, role, tags, created_at
+//	    FROM matched
+//	    WHERE ? = '' OR status = ?
+//	    ORDER BY created_at DESC
+//	    LIMIT ?
+//	)
+//	SELECT
+//	    page.id, page.uuid, page.email, page.username, page.first_name, page.last_name,
+//	    page.status, page.role, page.tags, page.created_at,
+//	    status_facets.counts AS status_facets,
+//	    role_facets.counts AS role_facets,
+//	    tag_facets.counts AS tag_facets
+//	FROM status_facets
+//	CROSS JOIN role_facets
+//	CROSS JOIN tag_facets
+//	LEFT JOIN page ON TRUE
+func (q *Queries) SearchUsersWithFacets(ctx context.Context, arg *SearchUsersWithFacetsParams) ([]*SearchUsersWithFacetsRow, error) {
+	rows, err := q.db.QueryContext(ctx, SearchUsersWithFacets,
+		arg.Query,
+		arg.Query,
+		arg.Query,
+		arg.Query,
+		arg.Limit,
+		arg.Status,
+		arg.Status,
+		arg.Limit_2,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*SearchUsersWithFacetsRow{}
+	for rows.Next() {
+		var i SearchUsersWithFacetsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UUID,
+			&i.Email,
+			&i.Username,
+			&i.FirstName,
+			&i.LastName,
+			&i.Status,
+			&i.Role,
+			&i.Tags,
+			&i.CreatedAt,
+			&i.StatusFacets,
+			&i.RoleFacets,
+			&i.TagFacets,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const SoftDeleteUser = `-- name: SoftDeleteUser :exec
+UPDATE users
+SET is_active = FALSE, deleted_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND deleted_at IS NULL
+`
+
+// SoftDeleteUser
+//
+//	UPDATE users
+//	SET is_active = FALSE, deleted_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+//	WHERE id = ? AND deleted_at IS NULL
 func (q *Queries) SoftDeleteUser(ctx context.Context, id uint64) error {
 	_, err := q.db.ExecContext(ctx, SoftDeleteUser, id)
 	return err
 }
 
+const SoftDeleteUsers = `-- name: SoftDeleteUsers :execrows
+UPDATE users
+SET is_active = FALSE, deleted_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+WHERE id IN (/*SLICE:ids*/?) AND deleted_at IS NULL
+`
+
+// SoftDeleteUsers
+//
+//	UPDATE users
+//	SET is_active = FALSE, deleted_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+//	WHERE id IN (/*SLICE:ids*/?) AND deleted_at IS NULL
+func (q *Queries) SoftDeleteUsers(ctx context.Context, ids []uint64) (int64, error) {
+	query := SoftDeleteUsers
+	var queryParams []interface{}
+	if len(ids) > 0 {
+		for _, v := range ids {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:ids*/?", strings.Repeat(",?", len(ids))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:ids*/?", "NULL", 1)
+	}
+	result, err := q.db.ExecContext(ctx, query, queryParams...)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const UpdateLastLogin = `-- name: UpdateLastLogin :exec
 UPDATE users 
 SET last_login_at = CURRENT_TIMESTAMP
@@ -335,19 +1217,30 @@ SET
     last_name = COALESCE(?, last_name),
     profile_metadata = COALESCE(?, profile_metadata),
     is_active = COALESCE(?, is_active),
-    is_verified = COALESCE(?, is_verified)
-WHERE id = ?
+    is_verified = COALESCE(?, is_verified),
+    status = COALESCE(?, status),
+    role = COALESCE(?, role),
+    tags = COALESCE(?, tags),
+    last_login_at = COALESCE(?, last_login_at),
+    username_canonical = ?,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND deleted_at IS NULL
 `
 
 type UpdateUserParams struct {
-	Email           string          `db:"email" json:"email"`
-	Username        string          `db:"username" json:"username"`
-	FirstName       string          `db:"first_name" json:"firstName"`
-	LastName        string          `db:"last_name" json:"lastName"`
-	ProfileMetadata json.RawMessage `db:"profile_metadata" json:"profileMetadata"`
-	IsActive        sql.NullBool    `db:"is_active" json:"isActive"`
-	IsVerified      sql.NullBool    `db:"is_verified" json:"isVerified"`
-	ID              uint64          `db:"id" json:"id"`
+	Email             string          `db:"email" json:"email"`
+	Username          string          `db:"username" json:"username"`
+	FirstName         string          `db:"first_name" json:"firstName"`
+	LastName          string          `db:"last_name" json:"lastName"`
+	ProfileMetadata   json.RawMessage `db:"profile_metadata" json:"profileMetadata"`
+	IsActive          sql.NullBool    `db:"is_active" json:"isActive"`
+	IsVerified        sql.NullBool    `db:"is_verified" json:"isVerified"`
+	Status            sql.NullString  `db:"status" json:"status"`
+	Role              sql.NullString  `db:"role" json:"role"`
+	Tags              json.RawMessage `db:"tags" json:"tags"`
+	LastLoginAt       sql.NullTime    `db:"last_login_at" json:"lastLoginAt"`
+	UsernameCanonical string          `db:"username_canonical" json:"usernameCanonical"`
+	ID                uint64          `db:"id" json:"id"`
 }
 
 // UpdateUser
@@ -360,8 +1253,14 @@ type UpdateUserParams struct {
 //	    last_name = COALESCE(?, last_name),
 //	    profile_metadata = COALESCE(?, profile_metadata),
 //	    is_active = COALESCE(?, is_active),
-//	    is_verified = COALESCE(?, is_verified)
-//	WHERE id = ?
+//	    is_verified = COALESCE(?, is_verified),
+//	    status = COALESCE(?, status),
+//	    role = COALESCE(?, role),
+//	    tags = COALESCE(?, tags),
+//	    last_login_at = COALESCE(?, last_login_at),
+//	    username_canonical = ?,
+//	    updated_at = CURRENT_TIMESTAMP
+//	WHERE id = ? AND deleted_at IS NULL
 func (q *Queries) UpdateUser(ctx context.Context, arg *UpdateUserParams) (sql.Result, error) {
 	return q.db.ExecContext(ctx, UpdateUser,
 		arg.Email,
@@ -371,10 +1270,92 @@ func (q *Queries) UpdateUser(ctx context.Context, arg *UpdateUserParams) (sql.Re
 		arg.ProfileMetadata,
 		arg.IsActive,
 		arg.IsVerified,
+		arg.Status,
+		arg.Role,
+		arg.Tags,
+		arg.LastLoginAt,
+		arg.UsernameCanonical,
 		arg.ID,
 	)
 }
 
+const UpdateUserRole = `-- name: UpdateUserRole :exec
+UPDATE users
+SET role = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?
+`
+
+type UpdateUserRoleParams struct {
+	Role string `db:"role" json:"role"`
+	ID   uint64 `db:"id" json:"id"`
+}
+
+// UpdateUserRole
+//
+//	UPDATE users
+//	SET role = ?, updated_at = CURRENT_TIMESTAMP
+//	WHERE id = ?
+func (q *Queries) UpdateUserRole(ctx context.Context, arg *UpdateUserRoleParams) error {
+	_, err := q.db.ExecContext(ctx, UpdateUserRole, arg.Role, arg.ID)
+	return err
+}
+
+const UpdateUserStatus = `-- name: UpdateUserStatus :exec
+UPDATE users
+SET status = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?
+`
+
+type UpdateUserStatusParams struct {
+	Status string `db:"status" json:"status"`
+	ID     uint64 `db:"id" json:"id"`
+}
+
+// UpdateUserStatus
+//
+//	UPDATE users
+//	SET status = ?, updated_at = CURRENT_TIMESTAMP
+//	WHERE id = ?
+func (q *Queries) UpdateUserStatus(ctx context.Context, arg *UpdateUserStatusParams) error {
+	_, err := q.db.ExecContext(ctx, UpdateUserStatus, arg.Status, arg.ID)
+	return err
+}
+
+const UpdateUsersStatus = `-- name: UpdateUsersStatus :execrows
+UPDATE users
+SET status = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id IN (/*SLICE:ids*/?) AND deleted_at IS NULL
+`
+
+type UpdateUsersStatusParams struct {
+	Status string   `db:"status" json:"status"`
+	Ids    []uint64 `db:"ids" json:"ids"`
+}
+
+// UpdateUsersStatus
+//
+//	UPDATE users
+//	SET status = ?, updated_at = CURRENT_TIMESTAMP
+//	WHERE id IN (/*SLICE:ids*/?) AND deleted_at IS NULL
+func (q *Queries) UpdateUsersStatus(ctx context.Context, arg *UpdateUsersStatusParams) (int64, error) {
+	query := UpdateUsersStatus
+	var queryParams []interface{}
+	queryParams = append(queryParams, arg.Status)
+	if len(arg.Ids) > 0 {
+		for _, v := range arg.Ids {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:ids*/?", strings.Repeat(",?", len(arg.Ids))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:ids*/?", "NULL", 1)
+	}
+	result, err := q.db.ExecContext(ctx, query, queryParams...)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const VerifyUser = `-- name: VerifyUser :exec
 UPDATE users 
 SET is_verified = TRUE, updated_at = CURRENT_TIMESTAMP
@@ -386,7 +1367,7 @@ WHERE uuid = ?
 //	UPDATE users
 //	SET is_verified = TRUE, updated_at = CURRENT_TIMESTAMP
 //	WHERE uuid = ?
-func (q *Queries) VerifyUser(ctx context.Context, uuid string) error {
+func (q *Queries) VerifyUser(ctx context.Context, uuid []byte) error {
 	_, err := q.db.ExecContext(ctx, VerifyUser, uuid)
 	return err
 }
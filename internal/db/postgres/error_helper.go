@@ -0,0 +1,93 @@
+package postgres
+
+import (
+	stderrors "errors"
+	"strings"
+
+	apperrors "github.com/LarsArtmann/template-sqlc/pkg/errors"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// SQLSTATEs mapped to domain errors or classified as transient.
+const (
+	uniqueViolation      = "23505"
+	serializationFailure = "40001"
+	deadlockDetected     = "40P01"
+	lockNotAvailable     = "55P03"
+	adminShutdown        = "57P01"
+	cannotConnectNow     = "57P03"
+	// connectionException is the class of SQLSTATEs for lost connections.
+	connectionException = "08"
+)
+
+// HandleDBError converts database errors to domain errors.
+// Takes entity-specific notFoundErr for pgx.ErrNoRows and conflictErr
+// for unique constraint violations.
+func HandleDBError(err error, operation string, notFoundErr, conflictErr error) error {
+	if err == nil {
+		return nil
+	}
+
+	switch {
+	case stderrors.Is(err, pgx.ErrNoRows):
+		return notFoundErr
+	case IsPostgresUniqueConstraintError(err):
+		return conflictErr
+	default:
+		return apperrors.NewDatabaseError(operation+" failed", err)
+	}
+}
+
+// IsPostgresUniqueConstraintError checks if error is a PostgreSQL unique constraint violation.
+func IsPostgresUniqueConstraintError(err error) bool {
+	var pgErr *pgconn.PgError
+
+	return stderrors.As(err, &pgErr) && pgErr.Code == uniqueViolation
+}
+
+// IsPostgresTransientError checks if error is a PostgreSQL failure that may
+// succeed when retried: a serialization failure, a deadlock, a lock timeout,
+// a server shutting down or starting up, a lost connection, or an error pgx
+// reports as safe to retry because nothing was sent.
+func IsPostgresTransientError(err error) bool {
+	if pgconn.SafeToRetry(err) {
+		return true
+	}
+
+	var pgErr *pgconn.PgError
+	if !stderrors.As(err, &pgErr) {
+		return false
+	}
+
+	switch pgErr.Code {
+	case serializationFailure, deadlockDetected, lockNotAvailable, adminShutdown, cannotConnectNow:
+		return true
+	default:
+		return strings.HasPrefix(pgErr.Code, connectionException)
+	}
+}
+
+// IsPostgresWriteRetryableError checks if error is a PostgreSQL failure
+// after which a write certainly did not take effect: a serialization
+// failure, a deadlock or a lock timeout, which roll the statement back, a
+// server starting up, or an error pgx reports as safe to retry because
+// nothing was sent. Lost connections are not, as the write may have been
+// applied before the connection broke.
+func IsPostgresWriteRetryableError(err error) bool {
+	if pgconn.SafeToRetry(err) {
+		return true
+	}
+
+	var pgErr *pgconn.PgError
+	if !stderrors.As(err, &pgErr) {
+		return false
+	}
+
+	switch pgErr.Code {
+	case serializationFailure, deadlockDetected, lockNotAvailable, cannotConnectNow:
+		return true
+	default:
+		return false
+	}
+}
@@ -7,22 +7,255 @@
 package postgres
 
 import (
+	"encoding/json"
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type AccountDeletions struct {
+	UserID       int64     `db:"user_id" json:"userId"`
+	RequestedBy  int64     `db:"requested_by" json:"requestedBy"`
+	RequestedAt  time.Time `db:"requested_at" json:"requestedAt"`
+	ScheduledFor time.Time `db:"scheduled_for" json:"scheduledFor"`
+}
+
+type AuditLog struct {
+	ID        int64           `db:"id" json:"id"`
+	ActorID   *int64          `db:"actor_id" json:"actorId"`
+	UserID    int64           `db:"user_id" json:"userId"`
+	Action    string          `db:"action" json:"action"`
+	Changes   json.RawMessage `db:"changes" json:"changes"`
+	IpAddress string          `db:"ip_address" json:"ipAddress"`
+	CreatedAt time.Time       `db:"created_at" json:"createdAt"`
+}
+
+type ChangefeedCheckpoints struct {
+	SlotName  string    `db:"slot_name" json:"slotName"`
+	Lsn       int64     `db:"lsn" json:"lsn"`
+	UpdatedAt time.Time `db:"updated_at" json:"updatedAt"`
+}
+
+type FeatureFlags struct {
+	Name      string    `db:"name" json:"name"`
+	Enabled   bool      `db:"enabled" json:"enabled"`
+	UpdatedAt time.Time `db:"updated_at" json:"updatedAt"`
+}
+
+type IdempotencyKeys struct {
+	IdempotencyKey string             `db:"idempotency_key" json:"idempotencyKey"`
+	Operation      string             `db:"operation" json:"operation"`
+	RequestHash    string             `db:"request_hash" json:"requestHash"`
+	Response       string             `db:"response" json:"response"`
+	CreatedAt      time.Time          `db:"created_at" json:"createdAt"`
+	CompletedAt    pgtype.Timestamptz `db:"completed_at" json:"completedAt"`
+	ExpiresAt      time.Time          `db:"expires_at" json:"expiresAt"`
+}
+
+type IdentityChanges struct {
+	ID        int64     `db:"id" json:"id"`
+	UserID    int64     `db:"user_id" json:"userId"`
+	Field     string    `db:"field" json:"field"`
+	OldValue  string    `db:"old_value" json:"oldValue"`
+	NewValue  string    `db:"new_value" json:"newValue"`
+	Status    string    `db:"status" json:"status"`
+	Token     uuid.UUID `db:"token" json:"token"`
+	ExpiresAt time.Time `db:"expires_at" json:"expiresAt"`
+	CreatedAt time.Time `db:"created_at" json:"createdAt"`
+	UpdatedAt time.Time `db:"updated_at" json:"updatedAt"`
+}
+
+type Jobs struct {
+	ID          int64              `db:"id" json:"id"`
+	Name        string             `db:"name" json:"name"`
+	Payload     json.RawMessage    `db:"payload" json:"payload"`
+	Status      string             `db:"status" json:"status"`
+	Attempts    int32              `db:"attempts" json:"attempts"`
+	MaxAttempts int32              `db:"max_attempts" json:"maxAttempts"`
+	RunAt       time.Time          `db:"run_at" json:"runAt"`
+	LockedUntil pgtype.Timestamptz `db:"locked_until" json:"lockedUntil"`
+	LastError   string             `db:"last_error" json:"lastError"`
+	CreatedAt   time.Time          `db:"created_at" json:"createdAt"`
+	UpdatedAt   time.Time          `db:"updated_at" json:"updatedAt"`
+}
+
+type LoginHistory struct {
+	ID        int64     `db:"id" json:"id"`
+	UserID    *int64    `db:"user_id" json:"userId"`
+	IpAddress string    `db:"ip_address" json:"ipAddress"`
+	UserAgent string    `db:"user_agent" json:"userAgent"`
+	Method    string    `db:"method" json:"method"`
+	Failure   string    `db:"failure" json:"failure"`
+	CreatedAt time.Time `db:"created_at" json:"createdAt"`
+}
+
+type ModerationActions struct {
+	ID          int64              `db:"id" json:"id"`
+	UserID      int64              `db:"user_id" json:"userId"`
+	Kind        string             `db:"kind" json:"kind"`
+	Reason      string             `db:"reason" json:"reason"`
+	ModeratorID int64              `db:"moderator_id" json:"moderatorId"`
+	EndsAt      pgtype.Timestamptz `db:"ends_at" json:"endsAt"`
+	LiftedAt    pgtype.Timestamptz `db:"lifted_at" json:"liftedAt"`
+	LiftedBy    int64              `db:"lifted_by" json:"liftedBy"`
+	CreatedAt   time.Time          `db:"created_at" json:"createdAt"`
+}
+
+type ModerationAppeals struct {
+	ID         int64              `db:"id" json:"id"`
+	ActionID   int64              `db:"action_id" json:"actionId"`
+	UserID     int64              `db:"user_id" json:"userId"`
+	Statement  string             `db:"statement" json:"statement"`
+	Status     string             `db:"status" json:"status"`
+	ReviewerID int64              `db:"reviewer_id" json:"reviewerId"`
+	Response   string             `db:"response" json:"response"`
+	CreatedAt  time.Time          `db:"created_at" json:"createdAt"`
+	DecidedAt  pgtype.Timestamptz `db:"decided_at" json:"decidedAt"`
+}
+
+type NotificationPreferences struct {
+	UserID    int64     `db:"user_id" json:"userId"`
+	Channel   string    `db:"channel" json:"channel"`
+	Topic     string    `db:"topic" json:"topic"`
+	Enabled   bool      `db:"enabled" json:"enabled"`
+	Target    string    `db:"target" json:"target"`
+	UpdatedAt time.Time `db:"updated_at" json:"updatedAt"`
+}
+
+type OrganizationMembers struct {
+	OrganizationID int64              `db:"organization_id" json:"organizationId"`
+	UserID         int64              `db:"user_id" json:"userId"`
+	Role           string             `db:"role" json:"role"`
+	Status         string             `db:"status" json:"status"`
+	InvitedBy      *int64             `db:"invited_by" json:"invitedBy"`
+	InvitedAt      time.Time          `db:"invited_at" json:"invitedAt"`
+	JoinedAt       pgtype.Timestamptz `db:"joined_at" json:"joinedAt"`
+}
+
+type Organizations struct {
+	ID        int64     `db:"id" json:"id"`
+	UUID      uuid.UUID `db:"uuid" json:"uuid"`
+	Name      string    `db:"name" json:"name"`
+	Slug      string    `db:"slug" json:"slug"`
+	CreatedAt time.Time `db:"created_at" json:"createdAt"`
+	UpdatedAt time.Time `db:"updated_at" json:"updatedAt"`
+}
+
+type UserActivity struct {
+	ID         int64     `db:"id" json:"id"`
+	UserID     int64     `db:"user_id" json:"userId"`
+	EventID    int64     `db:"event_id" json:"eventId"`
+	EventType  string    `db:"event_type" json:"eventType"`
+	Kind       string    `db:"kind" json:"kind"`
+	Summary    string    `db:"summary" json:"summary"`
+	ActorID    int64     `db:"actor_id" json:"actorId"`
+	OccurredAt time.Time `db:"occurred_at" json:"occurredAt"`
+}
+
+type UserEvents struct {
+	UserID     int64           `db:"user_id" json:"userId"`
+	Version    int64           `db:"version" json:"version"`
+	EventType  string          `db:"event_type" json:"eventType"`
+	Data       json.RawMessage `db:"data" json:"data"`
+	OccurredAt time.Time       `db:"occurred_at" json:"occurredAt"`
+}
+
+type UserIdentities struct {
+	ID          int64              `db:"id" json:"id"`
+	UserID      int64              `db:"user_id" json:"userId"`
+	Provider    string             `db:"provider" json:"provider"`
+	Subject     string             `db:"subject" json:"subject"`
+	Email       string             `db:"email" json:"email"`
+	LinkedAt    time.Time          `db:"linked_at" json:"linkedAt"`
+	LastLoginAt pgtype.Timestamptz `db:"last_login_at" json:"lastLoginAt"`
+}
+
+type UserPreferences struct {
+	UserID      int64           `db:"user_id" json:"userId"`
+	Preferences json.RawMessage `db:"preferences" json:"preferences"`
+	UpdatedAt   time.Time       `db:"updated_at" json:"updatedAt"`
+}
+
+type UserReadModel struct {
+	UserID          int64     `db:"user_id" json:"userId"`
+	UUID            uuid.UUID `db:"uuid" json:"uuid"`
+	Email           string    `db:"email" json:"email"`
+	Username        string    `db:"username" json:"username"`
+	FullName        string    `db:"full_name" json:"fullName"`
+	Status          string    `db:"status" json:"status"`
+	Role            string    `db:"role" json:"role"`
+	Tags            []string  `db:"tags" json:"tags"`
+	IsVerified      bool      `db:"is_verified" json:"isVerified"`
+	SearchText      string    `db:"search_text" json:"searchText"`
+	CreatedAt       time.Time `db:"created_at" json:"createdAt"`
+	SourceUpdatedAt time.Time `db:"source_updated_at" json:"sourceUpdatedAt"`
+	ProjectedAt     time.Time `db:"projected_at" json:"projectedAt"`
+}
+
+type UserSessions struct {
+	ID                   int64              `db:"id" json:"id"`
+	UserID               int64              `db:"user_id" json:"userId"`
+	SessionToken         uuid.UUID          `db:"session_token" json:"sessionToken"`
+	DeviceInfo           json.RawMessage    `db:"device_info" json:"deviceInfo"`
+	IpAddress            string             `db:"ip_address" json:"ipAddress"`
+	UserAgent            string             `db:"user_agent" json:"userAgent"`
+	Country              string             `db:"country" json:"country"`
+	Region               string             `db:"region" json:"region"`
+	City                 string             `db:"city" json:"city"`
+	OrganizationID       *int64             `db:"organization_id" json:"organizationId"`
+	ImpersonatorID       *int64             `db:"impersonator_id" json:"impersonatorId"`
+	RefreshToken         uuid.UUID          `db:"refresh_token" json:"refreshToken"`
+	RefreshExpiresAt     pgtype.Timestamptz `db:"refresh_expires_at" json:"refreshExpiresAt"`
+	RotatedRefreshTokens json.RawMessage    `db:"rotated_refresh_tokens" json:"rotatedRefreshTokens"`
+	CreatedAt            time.Time          `db:"created_at" json:"createdAt"`
+	ExpiresAt            time.Time          `db:"expires_at" json:"expiresAt"`
+	IsActive             bool               `db:"is_active" json:"isActive"`
+}
+
+type UserSnapshots struct {
+	UserID    int64           `db:"user_id" json:"userId"`
+	Version   int64           `db:"version" json:"version"`
+	Deleted   bool            `db:"deleted" json:"deleted"`
+	State     json.RawMessage `db:"state" json:"state"`
+	CreatedAt time.Time       `db:"created_at" json:"createdAt"`
+}
+
 type Users struct {
-	ID              int64              `db:"id" json:"id"`
-	UUID            uuid.UUID          `db:"uuid" json:"uuid"`
-	Email           string             `db:"email" json:"email"`
-	Username        string             `db:"username" json:"username"`
-	PasswordHash    string             `db:"password_hash" json:"passwordHash"`
-	FirstName       string             `db:"first_name" json:"firstName"`
-	LastName        string             `db:"last_name" json:"lastName"`
-	CreatedAt       pgtype.Timestamptz `db:"created_at" json:"createdAt"`
-	UpdatedAt       pgtype.Timestamptz `db:"updated_at" json:"updatedAt"`
-	LastLoginAt     pgtype.Timestamptz `db:"last_login_at" json:"lastLoginAt"`
-	IsActive        *bool              `db:"is_active" json:"isActive"`
-	IsVerified      *bool              `db:"is_verified" json:"isVerified"`
-	ProfileMetadata []byte             `db:"profile_metadata" json:"profileMetadata"`
+	ID                int64              `db:"id" json:"id"`
+	UUID              uuid.UUID          `db:"uuid" json:"uuid"`
+	Email             string             `db:"email" json:"email"`
+	Username          string             `db:"username" json:"username"`
+	PasswordHash      string             `db:"password_hash" json:"passwordHash"`
+	FirstName         string             `db:"first_name" json:"firstName"`
+	LastName          string             `db:"last_name" json:"lastName"`
+	CreatedAt         pgtype.Timestamptz `db:"created_at" json:"createdAt"`
+	UpdatedAt         pgtype.Timestamptz `db:"updated_at" json:"updatedAt"`
+	LastLoginAt       pgtype.Timestamptz `db:"last_login_at" json:"lastLoginAt"`
+	IsActive          *bool              `db:"is_active" json:"isActive"`
+	IsVerified        *bool              `db:"is_verified" json:"isVerified"`
+	ProfileMetadata   []byte             `db:"profile_metadata" json:"profileMetadata"`
+	Status            string             `db:"status" json:"status"`
+	Role              string             `db:"role" json:"role"`
+	Tags              []string           `db:"tags" json:"tags"`
+	DeletedAt         pgtype.Timestamptz `db:"deleted_at" json:"deletedAt"`
+	UsernameCanonical string             `db:"username_canonical" json:"usernameCanonical"`
+}
+
+type UsersHistory struct {
+	HistoryID       int64     `db:"history_id" json:"historyId"`
+	UserID          int64     `db:"user_id" json:"userId"`
+	Operation       string    `db:"operation" json:"operation"`
+	Email           string    `db:"email" json:"email"`
+	Username        string    `db:"username" json:"username"`
+	FirstName       string    `db:"first_name" json:"firstName"`
+	LastName        string    `db:"last_name" json:"lastName"`
+	Status          string    `db:"status" json:"status"`
+	Role            string    `db:"role" json:"role"`
+	Tags            []string  `db:"tags" json:"tags"`
+	IsActive        *bool     `db:"is_active" json:"isActive"`
+	IsVerified      *bool     `db:"is_verified" json:"isVerified"`
+	ProfileMetadata []byte    `db:"profile_metadata" json:"profileMetadata"`
+	ValidFrom       time.Time `db:"valid_from" json:"validFrom"`
+	ValidTo         time.Time `db:"valid_to" json:"validTo"`
 }
@@ -8,75 +8,984 @@ package postgres
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type Querier interface {
+	// Tokens indexed before are left alone, so every token can be added again
+	// on each update.
+	//
+	//  INSERT INTO session_refresh_tokens (refresh_token, session_id)
+	//  VALUES ($1, $2)
+	//  ON CONFLICT (refresh_token) DO NOTHING
+	AddSessionRefreshToken(ctx context.Context, arg *AddSessionRefreshTokenParams) error
+	// Consumes the changes of a slot up to upto_lsn.
+	//
+	//  SELECT pg_replication_slot_advance($1::text, $2::text::pg_lsn)
+	AdvanceChangefeedSlot(ctx context.Context, arg *AdvanceChangefeedSlotParams) error
+	// Clears the personal data of the entries about or performed by a user.
+	//
+	//  UPDATE audit_log
+	//  SET changes = '[]'::jsonb, ip_address = ''
+	//  WHERE user_id = $1 OR actor_id = $1
+	AnonymizeAuditLogs(ctx context.Context, userID int64) (int64, error)
+	//AppendUserEvent
+	//
+	//  INSERT INTO user_events (user_id, version, event_type, data, occurred_at)
+	//  VALUES ($1, $2, $3, $4, $5)
+	AppendUserEvent(ctx context.Context, arg *AppendUserEventParams) error
+	// Leases up to row_limit due jobs: pending ones whose run_at has passed and
+	// running ones whose lease expired. Rows locked by other workers are skipped.
+	//
+	//  UPDATE jobs
+	//  SET status = 'running', attempts = attempts + 1,
+	//      locked_until = $1, updated_at = $2
+	//  WHERE id IN (
+	//      SELECT id FROM jobs
+	//      WHERE (status = 'pending' AND run_at <= $2)
+	//         OR (status = 'running' AND locked_until < $2)
+	//      ORDER BY run_at, id
+	//      LIMIT $3
+	//      FOR UPDATE SKIP LOCKED
+	//  )
+	//  RETURNING id, name, payload, status, attempts, max_attempts, run_at, locked_until, last_error, created_at, updated_at
+	ClaimJobs(ctx context.Context, arg *ClaimJobsParams) ([]*Jobs, error)
+	// Work-queue claim: locks the oldest users in a status, skipping rows
+	// already claimed by concurrent transactions.
+	//
+	//  SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, deleted_at, username_canonical FROM users
+	//  WHERE status = $1::text AND deleted_at IS NULL
+	//  ORDER BY updated_at, id
+	//  LIMIT $2::int
+	//  FOR UPDATE SKIP LOCKED
+	ClaimUsersByStatus(ctx context.Context, arg *ClaimUsersByStatusParams) ([]*Users, error)
+	//CompleteIdempotencyKey
+	//
+	//  UPDATE idempotency_keys
+	//  SET response = $1, completed_at = $2, expires_at = $3
+	//  WHERE idempotency_key = $4 AND completed_at IS NULL
+	CompleteIdempotencyKey(ctx context.Context, arg *CompleteIdempotencyKeyParams) (int64, error)
+	// Finishing a claim matches its attempts, so a worker whose lease expired
+	// cannot overwrite a job another worker has claimed since.
+	//
+	//  UPDATE jobs
+	//  SET status = 'done', locked_until = NULL, last_error = '', updated_at = $1
+	//  WHERE id = $2 AND status = 'running' AND attempts = $3
+	CompleteJob(ctx context.Context, arg *CompleteJobParams) (int64, error)
+	// Bulk insert through the COPY protocol; IDs are looked up by UUID afterwards.
+	//
+	//  INSERT INTO users (
+	//      uuid, email, username, password_hash,
+	//      first_name, last_name, profile_metadata, is_active,
+	//      is_verified, status, role, tags, created_at, updated_at,
+	//      username_canonical
+	//  ) VALUES (
+	//      $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15
+	//  )
+	CopyUsers(ctx context.Context, arg []*CopyUsersParams) (int64, error)
+	//CountActiveSessions
+	//
+	//  SELECT COUNT(*) AS count
+	//  FROM user_sessions
+	//  WHERE user_id = $1 AND is_active = TRUE AND expires_at >= $2
+	CountActiveSessions(ctx context.Context, arg *CountActiveSessionsParams) (int64, error)
 	//CountActiveUsers
 	//
-	//  SELECT COUNT(*) FROM users WHERE is_active = TRUE
+	//  SELECT COUNT(*) FROM users WHERE deleted_at IS NULL
 	CountActiveUsers(ctx context.Context) (int64, error)
+	//CountOrganizationAdmins
+	//
+	//  SELECT COUNT(*)
+	//  FROM organization_members
+	//  WHERE organization_id = $1 AND role = 'admin' AND status = 'active'
+	CountOrganizationAdmins(ctx context.Context, organizationID int64) (int64, error)
+	//CountUsersByStatus
+	//
+	//  SELECT status, COUNT(*) AS total
+	//  FROM users
+	//  WHERE deleted_at IS NULL
+	//  GROUP BY status
+	CountUsersByStatus(ctx context.Context) ([]*CountUsersByStatusRow, error)
+	//CreateAccountDeletion
+	//
+	//  INSERT INTO account_deletions (user_id, requested_by, requested_at, scheduled_for)
+	//  VALUES ($1, $2, $3, $4)
+	CreateAccountDeletion(ctx context.Context, arg *CreateAccountDeletionParams) error
+	//CreateAuditLog
+	//
+	//  INSERT INTO audit_log (
+	//      actor_id, user_id, action, changes, ip_address, created_at
+	//  ) VALUES (
+	//      $1, $2, $3,
+	//      $4, $5, $6
+	//  )
+	//  RETURNING id
+	CreateAuditLog(ctx context.Context, arg *CreateAuditLogParams) (int64, error)
+	// Creates the logical replication slot decoded by wal2json unless it exists.
+	//
+	//  SELECT pg_create_logical_replication_slot($1::text, 'wal2json')
+	//  WHERE NOT EXISTS (
+	//      SELECT 1 FROM pg_replication_slots WHERE slot_name = $1::text
+	//  )
+	CreateChangefeedSlot(ctx context.Context, slotName string) error
+	//CreateIdentityChange
+	//
+	//  INSERT INTO identity_changes (user_id, field, old_value, new_value, status, token, expires_at, created_at, updated_at)
+	//  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	//  RETURNING id
+	CreateIdentityChange(ctx context.Context, arg *CreateIdentityChangeParams) (int64, error)
+	//CreateMembership
+	//
+	//  INSERT INTO organization_members (
+	//      organization_id, user_id, role, status, invited_by, invited_at, joined_at
+	//  ) VALUES (
+	//      $1, $2, $3, $4,
+	//      $5, $6, $7
+	//  )
+	CreateMembership(ctx context.Context, arg *CreateMembershipParams) error
+	//CreateModerationAction
+	//
+	//  INSERT INTO moderation_actions (user_id, kind, reason, moderator_id, ends_at, created_at)
+	//  VALUES ($1, $2, $3, $4, $5, $6)
+	//  RETURNING id
+	CreateModerationAction(ctx context.Context, arg *CreateModerationActionParams) (int64, error)
+	//CreateModerationAppeal
+	//
+	//  INSERT INTO moderation_appeals (action_id, user_id, statement, status, response, created_at)
+	//  VALUES ($1, $2, $3, $4, '', $5)
+	//  RETURNING id
+	CreateModerationAppeal(ctx context.Context, arg *CreateModerationAppealParams) (int64, error)
+	//CreateOrganization
+	//
+	//  INSERT INTO organizations (uuid, name, slug, created_at, updated_at)
+	//  VALUES ($1, $2, $3, $4, $5)
+	//  RETURNING id
+	CreateOrganization(ctx context.Context, arg *CreateOrganizationParams) (int64, error)
+	//CreateSession
+	//
+	//  INSERT INTO user_sessions (
+	//      user_id, session_token, device_info, ip_address, user_agent, country, region, city,
+	//      organization_id, impersonator_id, refresh_token, refresh_expires_at, rotated_refresh_tokens,
+	//      created_at, expires_at, is_active
+	//  ) VALUES (
+	//      $1, $2, $3, $4,
+	//      $5, $6, $7, $8,
+	//      $9, $10, $11,
+	//      $12, $13,
+	//      $14, $15, $16
+	//  )
+	//  RETURNING id
+	CreateSession(ctx context.Context, arg *CreateSessionParams) (int64, error)
 	//CreateUser
 	//
 	//  INSERT INTO users (
 	//      uuid, email, username, password_hash,
-	//      first_name, last_name, profile_metadata, is_active
+	//      first_name, last_name, profile_metadata, is_active,
+	//      is_verified, status, role, tags, created_at, updated_at,
+	//      username_canonical
 	//  ) VALUES (
-	//      $1, $2, $3, $4, $5, $6, $7, $8
+	//      $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15
 	//  )
-	//  RETURNING id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata
+	//  RETURNING id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, deleted_at, username_canonical
 	CreateUser(ctx context.Context, arg *CreateUserParams) (*Users, error)
+	//CreateUserIdentity
+	//
+	//  INSERT INTO user_identities (user_id, provider, subject, email, linked_at)
+	//  VALUES ($1, $2, $3, $4, $5)
+	//  RETURNING id
+	CreateUserIdentity(ctx context.Context, arg *CreateUserIdentityParams) (int64, error)
+	//DeactivateSessionByToken
+	//
+	//  UPDATE user_sessions SET is_active = FALSE
+	//  WHERE session_token = $1
+	DeactivateSessionByToken(ctx context.Context, sessionToken uuid.UUID) (int64, error)
+	//DeactivateSessionsByUser
+	//
+	//  UPDATE user_sessions SET is_active = FALSE
+	//  WHERE user_id = $1
+	DeactivateSessionsByUser(ctx context.Context, userID int64) error
+	//DecideModerationAppeal
+	//
+	//  UPDATE moderation_appeals
+	//  SET status = $1, reviewer_id = $2, response = $3, decided_at = $4
+	//  WHERE id = $5 AND status = 'pending'
+	DecideModerationAppeal(ctx context.Context, arg *DecideModerationAppealParams) (int64, error)
+	//DeleteAccountDeletion
+	//
+	//  DELETE FROM account_deletions
+	//  WHERE user_id = $1
+	DeleteAccountDeletion(ctx context.Context, userID int64) (int64, error)
+	//DeleteDoneJobs
+	//
+	//  DELETE FROM jobs
+	//  WHERE status = 'done' AND updated_at < $1
+	DeleteDoneJobs(ctx context.Context, cutoff time.Time) (int64, error)
+	//DeleteExpiredIdempotencyKeys
+	//
+	//  DELETE FROM idempotency_keys
+	//  WHERE expires_at <= $1
+	DeleteExpiredIdempotencyKeys(ctx context.Context, now time.Time) (int64, error)
+	//DeleteExpiredSessions
+	//
+	//  DELETE FROM user_sessions
+	//  WHERE expires_at < $1
+	DeleteExpiredSessions(ctx context.Context, now time.Time) (int64, error)
+	//DeleteIdentityChangesByUser
+	//
+	//  DELETE FROM identity_changes
+	//  WHERE user_id = $1
+	DeleteIdentityChangesByUser(ctx context.Context, userID int64) (int64, error)
+	//DeleteLoginAttemptsByUser
+	//
+	//  DELETE FROM login_history
+	//  WHERE user_id = $1
+	DeleteLoginAttemptsByUser(ctx context.Context, userID *int64) (int64, error)
+	//DeleteMembership
+	//
+	//  DELETE FROM organization_members
+	//  WHERE organization_id = $1 AND user_id = $2
+	DeleteMembership(ctx context.Context, arg *DeleteMembershipParams) (int64, error)
+	//DeleteModerationActionsByUser
+	//
+	//  DELETE FROM moderation_actions
+	//  WHERE user_id = $1
+	DeleteModerationActionsByUser(ctx context.Context, userID int64) (int64, error)
+	//DeleteModerationAppealsByUser
+	//
+	//  DELETE FROM moderation_appeals
+	//  WHERE user_id = $1
+	DeleteModerationAppealsByUser(ctx context.Context, userID int64) (int64, error)
+	//DeleteNotificationPreference
+	//
+	//  DELETE FROM notification_preferences
+	//  WHERE user_id = $1 AND channel = $2 AND topic = $3
+	DeleteNotificationPreference(ctx context.Context, arg *DeleteNotificationPreferenceParams) (int64, error)
+	//DeleteOrganization
+	//
+	//  DELETE FROM organizations
+	//  WHERE id = $1
+	DeleteOrganization(ctx context.Context, id int64) (int64, error)
+	//DeleteSession
+	//
+	//  DELETE FROM user_sessions
+	//  WHERE id = $1
+	DeleteSession(ctx context.Context, id int64) (int64, error)
+	//DeleteUserActivityBefore
+	//
+	//  DELETE FROM user_activity
+	//  WHERE occurred_at < $1
+	DeleteUserActivityBefore(ctx context.Context, cutoff time.Time) (int64, error)
+	//DeleteUserActivityByUser
+	//
+	//  DELETE FROM user_activity
+	//  WHERE user_id = $1
+	DeleteUserActivityByUser(ctx context.Context, userID int64) (int64, error)
+	//DeleteUserEvents
+	//
+	//  DELETE FROM user_events
+	//  WHERE user_id = $1
+	DeleteUserEvents(ctx context.Context, userID int64) (int64, error)
+	// Removes every archived version of a user.
+	//
+	//  DELETE FROM users_history
+	//  WHERE user_id = $1
+	DeleteUserHistory(ctx context.Context, userID int64) (int64, error)
+	//DeleteUserIdentity
+	//
+	//  DELETE FROM user_identities
+	//  WHERE user_id = $1 AND provider = $2
+	DeleteUserIdentity(ctx context.Context, arg *DeleteUserIdentityParams) (int64, error)
+	//DeleteUserPreferences
+	//
+	//  DELETE FROM user_preferences
+	//  WHERE user_id = $1
+	DeleteUserPreferences(ctx context.Context, userID int64) (int64, error)
+	//DeleteUserSnapshots
+	//
+	//  DELETE FROM user_snapshots
+	//  WHERE user_id = $1
+	DeleteUserSnapshots(ctx context.Context, userID int64) (int64, error)
+	//DeleteUserViews
+	//
+	//  DELETE FROM user_read_model WHERE user_id = ANY($1::bigint[])
+	DeleteUserViews(ctx context.Context, ids []int64) error
+	//EnqueueJob
+	//
+	//  INSERT INTO jobs (
+	//      name, payload, max_attempts, run_at, created_at, updated_at
+	//  ) VALUES (
+	//      $1, $2, $3,
+	//      $4, $5, $5
+	//  )
+	//  RETURNING id
+	EnqueueJob(ctx context.Context, arg *EnqueueJobParams) (int64, error)
+	//FailJob
+	//
+	//  UPDATE jobs
+	//  SET status = 'failed', locked_until = NULL, last_error = $1, updated_at = $2
+	//  WHERE id = $3 AND status = 'running' AND attempts = $4
+	FailJob(ctx context.Context, arg *FailJobParams) (int64, error)
+	// Lists users matching a UserFilter, newest first with the ID breaking ties.
+	// Empty sets and an empty query match any user; a negative verified matches
+	// both states. Open time ranges and the first page's keyset cursor are
+	// replaced by bounds beyond any stored value.
+	// The query is a to_tsquery expression over users_search_document.
+	//
+	//  SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, deleted_at, username_canonical FROM users
+	//  WHERE deleted_at IS NULL
+	//    AND (cardinality($1::text[]) = 0 OR status = ANY($1::text[]))
+	//    AND (cardinality($2::text[]) = 0 OR role = ANY($2::text[]))
+	//    AND ($3::int < 0 OR is_verified = ($3::int = 1))
+	//    AND created_at > $4::timestamptz
+	//    AND created_at < $5::timestamptz
+	//    AND (cardinality($6::text[]) = 0 OR tags && $6::text[])
+	//    AND tags @> $7::text[]
+	//    AND ($8::text = ''
+	//         OR users_search_document(email, username, first_name, last_name)
+	//            @@ to_tsquery('simple', $8::text))
+	//    AND (created_at, id) < ($9::timestamptz, $10::bigint)
+	//  ORDER BY created_at DESC, id DESC
+	//  LIMIT $12::int OFFSET $11::int
+	FilterUsers(ctx context.Context, arg *FilterUsersParams) ([]*Users, error)
+	//GetAccountDeletion
+	//
+	//  SELECT user_id, requested_by, requested_at, scheduled_for
+	//  FROM account_deletions
+	//  WHERE user_id = $1
+	//  LIMIT 1
+	GetAccountDeletion(ctx context.Context, userID int64) (*AccountDeletions, error)
+	//GetActiveSuspension
+	//
+	//  SELECT id, user_id, kind, reason, moderator_id, ends_at, lifted_at, lifted_by, created_at
+	//  FROM moderation_actions
+	//  WHERE user_id = $1 AND kind = 'suspension' AND lifted_at IS NULL
+	//  ORDER BY created_at DESC, id DESC
+	//  LIMIT 1
+	GetActiveSuspension(ctx context.Context, userID int64) (*ModerationActions, error)
+	//GetChangefeedCheckpoint
+	//
+	//  SELECT lsn
+	//  FROM changefeed_checkpoints
+	//  WHERE slot_name = $1
+	//  LIMIT 1
+	GetChangefeedCheckpoint(ctx context.Context, slotName string) (int64, error)
+	//GetIdempotencyKey
+	//
+	//  SELECT idempotency_key, operation, request_hash, response, created_at, completed_at, expires_at
+	//  FROM idempotency_keys
+	//  WHERE idempotency_key = $1
+	//  LIMIT 1
+	GetIdempotencyKey(ctx context.Context, idempotencyKey string) (*IdempotencyKeys, error)
+	//GetIdentityChangeByToken
+	//
+	//  SELECT id, user_id, field, old_value, new_value, status, token, expires_at, created_at, updated_at
+	//  FROM identity_changes
+	//  WHERE token = $1
+	//  LIMIT 1
+	GetIdentityChangeByToken(ctx context.Context, token uuid.UUID) (*IdentityChanges, error)
+	//GetJob
+	//
+	//  SELECT id, name, payload, status, attempts, max_attempts, run_at, locked_until, last_error, created_at, updated_at
+	//  FROM jobs
+	//  WHERE id = $1
+	//  LIMIT 1
+	GetJob(ctx context.Context, id int64) (*Jobs, error)
+	//GetLatestUserSnapshot
+	//
+	//  SELECT user_id, version, deleted, state, created_at
+	//  FROM user_snapshots
+	//  WHERE user_id = $1
+	//  ORDER BY version DESC
+	//  LIMIT 1
+	GetLatestUserSnapshot(ctx context.Context, userID int64) (*UserSnapshots, error)
+	//GetMembership
+	//
+	//  SELECT organization_id, user_id, role, status, invited_by, invited_at, joined_at
+	//  FROM organization_members
+	//  WHERE organization_id = $1 AND user_id = $2
+	//  LIMIT 1
+	GetMembership(ctx context.Context, arg *GetMembershipParams) (*OrganizationMembers, error)
+	//GetModerationAction
+	//
+	//  SELECT id, user_id, kind, reason, moderator_id, ends_at, lifted_at, lifted_by, created_at
+	//  FROM moderation_actions
+	//  WHERE id = $1
+	//  LIMIT 1
+	GetModerationAction(ctx context.Context, id int64) (*ModerationActions, error)
+	//GetModerationAppeal
+	//
+	//  SELECT id, action_id, user_id, statement, status, reviewer_id, response, created_at, decided_at
+	//  FROM moderation_appeals
+	//  WHERE id = $1
+	//  LIMIT 1
+	GetModerationAppeal(ctx context.Context, id int64) (*ModerationAppeals, error)
+	// Returns the update time of the user changed longest ago whose change is
+	// not in the read model: a live user without a current view, or a deleted
+	// user whose view was not removed.
+	//
+	//  SELECT users.updated_at
+	//  FROM users
+	//  LEFT JOIN user_read_model ON user_read_model.user_id = users.id
+	//  WHERE (users.deleted_at IS NULL
+	//         AND (user_read_model.user_id IS NULL OR user_read_model.source_updated_at < users.updated_at))
+	//     OR (users.deleted_at IS NOT NULL AND user_read_model.user_id IS NOT NULL)
+	//  ORDER BY users.updated_at
+	//  LIMIT 1
+	GetOldestUnprojectedUserChange(ctx context.Context) (pgtype.Timestamptz, error)
+	//GetOrganizationByID
+	//
+	//  SELECT id, uuid, name, slug, created_at, updated_at
+	//  FROM organizations
+	//  WHERE id = $1
+	//  LIMIT 1
+	GetOrganizationByID(ctx context.Context, id int64) (*Organizations, error)
+	//GetOrganizationBySlug
+	//
+	//  SELECT id, uuid, name, slug, created_at, updated_at
+	//  FROM organizations
+	//  WHERE slug = $1
+	//  LIMIT 1
+	GetOrganizationBySlug(ctx context.Context, slug string) (*Organizations, error)
+	// Matches the current and the rotated refresh tokens of a session.
+	//
+	//  SELECT s.id, s.user_id, s.session_token, s.device_info, s.ip_address, s.user_agent, s.country,
+	//         s.region, s.city, s.organization_id, s.impersonator_id, s.refresh_token, s.refresh_expires_at,
+	//         s.rotated_refresh_tokens, s.created_at, s.expires_at, s.is_active
+	//  FROM user_sessions s
+	//  JOIN session_refresh_tokens t ON t.session_id = s.id
+	//  WHERE t.refresh_token = $1
+	//  LIMIT 1
+	GetSessionByRefreshToken(ctx context.Context, refreshToken uuid.UUID) (*UserSessions, error)
+	//GetSessionByToken
+	//
+	//  SELECT id, user_id, session_token, device_info, ip_address, user_agent, country, region, city,
+	//         organization_id, impersonator_id, refresh_token, refresh_expires_at, rotated_refresh_tokens,
+	//         created_at, expires_at, is_active
+	//  FROM user_sessions
+	//  WHERE session_token = $1
+	//  LIMIT 1
+	GetSessionByToken(ctx context.Context, sessionToken uuid.UUID) (*UserSessions, error)
+	//GetSessionStats
+	//
+	//  SELECT
+	//      COUNT(*) AS total_sessions,
+	//      COUNT(CASE WHEN is_active = TRUE AND expires_at >= $1 THEN 1 END) AS active_sessions,
+	//      COUNT(CASE WHEN expires_at < $1 THEN 1 END) AS expired_sessions,
+	//      COUNT(CASE WHEN created_at >= $2 THEN 1 END) AS sessions_24h,
+	//      COUNT(CASE WHEN created_at >= $3 THEN 1 END) AS sessions_7d,
+	//      COUNT(CASE WHEN created_at >= $4 THEN 1 END) AS sessions_30d
+	//  FROM user_sessions
+	GetSessionStats(ctx context.Context, arg *GetSessionStatsParams) (*GetSessionStatsRow, error)
+	// Returns the version of a user that was valid at the given time, preferring
+	// archived versions over the current row.
+	//
+	//  SELECT user_id, operation, email, username, first_name, last_name, status, role, tags,
+	//         is_active, is_verified, valid_from, valid_to
+	//  FROM (
+	//      SELECT u.id AS user_id, 'current' AS operation, u.email, u.username, u.first_name, u.last_name,
+	//             u.status, u.role, u.tags, u.is_active, u.is_verified,
+	//             COALESCE(u.updated_at, u.created_at) AS valid_from,
+	//             NULL::timestamptz AS valid_to, 1 AS is_current
+	//      FROM users u
+	//      WHERE u.id = $1::bigint
+	//        AND COALESCE(u.updated_at, u.created_at) <= $2::timestamptz
+	//      UNION ALL
+	//      SELECT h.user_id, h.operation, h.email, h.username, h.first_name, h.last_name,
+	//             h.status, h.role, h.tags, h.is_active, h.is_verified,
+	//             h.valid_from, h.valid_to, 0
+	//      FROM users_history h
+	//      WHERE h.user_id = $1::bigint
+	//        AND h.valid_from <= $2::timestamptz
+	//        AND h.valid_to > $2::timestamptz
+	//  ) AS versions
+	//  ORDER BY is_current, valid_from DESC
+	//  LIMIT 1
+	GetUserAsOf(ctx context.Context, arg *GetUserAsOfParams) (*GetUserAsOfRow, error)
 	//GetUserByEmail
 	//
-	//  SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata FROM users WHERE email = $1 AND is_active = TRUE
+	//  SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, deleted_at, username_canonical FROM users WHERE email = $1 AND deleted_at IS NULL
 	GetUserByEmail(ctx context.Context, email string) (*Users, error)
 	//GetUserByID
 	//
-	//  SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata FROM users WHERE id = $1 AND is_active = TRUE
+	//  SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, deleted_at, username_canonical FROM users WHERE id = $1 AND deleted_at IS NULL
 	GetUserByID(ctx context.Context, id int64) (*Users, error)
+	// Locks the user row until the surrounding transaction ends.
+	//
+	//  SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, deleted_at, username_canonical FROM users WHERE id = $1 AND deleted_at IS NULL LIMIT 1 FOR UPDATE
+	GetUserByIDForUpdate(ctx context.Context, id int64) (*Users, error)
 	//GetUserByUUID
 	//
-	//  SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata FROM users WHERE uuid = $1 AND is_active = TRUE
+	//  SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, deleted_at, username_canonical FROM users WHERE uuid = $1 AND deleted_at IS NULL
 	GetUserByUUID(ctx context.Context, argUuid uuid.UUID) (*Users, error)
 	//GetUserByUsername
 	//
-	//  SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata FROM users WHERE username = $1 AND is_active = TRUE
-	GetUserByUsername(ctx context.Context, username string) (*Users, error)
+	//  SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, deleted_at, username_canonical FROM users WHERE username_canonical = $1 AND deleted_at IS NULL
+	GetUserByUsername(ctx context.Context, usernameCanonical string) (*Users, error)
+	//GetUserIDsByUUIDs
+	//
+	//  SELECT id, uuid FROM users WHERE uuid = ANY($1::uuid[])
+	GetUserIDsByUUIDs(ctx context.Context, uuids []uuid.UUID) ([]*GetUserIDsByUUIDsRow, error)
+	//GetUserIdentity
+	//
+	//  SELECT id, user_id, provider, subject, email, linked_at, last_login_at
+	//  FROM user_identities
+	//  WHERE provider = $1 AND subject = $2
+	//  LIMIT 1
+	GetUserIdentity(ctx context.Context, arg *GetUserIdentityParams) (*UserIdentities, error)
+	//GetUserPreferences
+	//
+	//  SELECT user_id, preferences, updated_at
+	//  FROM user_preferences
+	//  WHERE user_id = $1
+	//  LIMIT 1
+	GetUserPreferences(ctx context.Context, userID int64) (*UserPreferences, error)
 	//GetUserStats
 	//
 	//  SELECT
 	//      COUNT(*) as total_users,
-	//      COUNT(*) FILTER (WHERE is_active = TRUE) as active_users,
+	//      COUNT(*) FILTER (WHERE deleted_at IS NULL) as active_users,
 	//      COUNT(*) FILTER (WHERE is_verified = TRUE) as verified_users,
-	//      COUNT(*) FILTER (WHERE last_login_at IS NOT NULL) as users_with_logins
+	//      COUNT(*) FILTER (WHERE last_login_at IS NOT NULL) as users_with_logins,
+	//      COUNT(*) FILTER (WHERE status = 'inactive') as inactive_users,
+	//      COUNT(*) FILTER (WHERE status = 'suspended') as suspended_users,
+	//      COUNT(*) FILTER (WHERE created_at >= NOW() - INTERVAL '30 days') as new_users_30d,
+	//      COUNT(*) FILTER (WHERE created_at >= NOW() - INTERVAL '7 days') as new_users_7d
 	//  FROM users
 	GetUserStats(ctx context.Context) (*GetUserStatsRow, error)
+	//GetUserStreamVersion
+	//
+	//  SELECT COALESCE(MAX(version), 0)::BIGINT AS version
+	//  FROM user_events
+	//  WHERE user_id = $1
+	GetUserStreamVersion(ctx context.Context, userID int64) (int64, error)
+	//GetUsersByIDs
+	//
+	//  SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, deleted_at, username_canonical FROM users WHERE id = ANY($1::bigint[]) AND deleted_at IS NULL
+	GetUsersByIDs(ctx context.Context, ids []int64) ([]*Users, error)
+	//LiftModerationAction
+	//
+	//  UPDATE moderation_actions
+	//  SET lifted_at = $1, lifted_by = $2
+	//  WHERE id = $3 AND lifted_at IS NULL
+	LiftModerationAction(ctx context.Context, arg *LiftModerationActionParams) (int64, error)
+	// lint:ignore missing-limit
+	//
+	//  SELECT id, user_id, session_token, device_info, ip_address, user_agent, country, region, city,
+	//         organization_id, impersonator_id, refresh_token, refresh_expires_at, rotated_refresh_tokens,
+	//         created_at, expires_at, is_active
+	//  FROM user_sessions
+	//  WHERE user_id = $1 AND is_active = TRUE
+	//  ORDER BY id DESC
+	ListActiveSessionsByUser(ctx context.Context, userID int64) ([]*UserSessions, error)
+	// Zero user/actor IDs and an empty action match any value.
+	//
+	//  SELECT id, actor_id, user_id, action, changes, ip_address, created_at
+	//  FROM audit_log
+	//  WHERE ($1::bigint = 0 OR user_id = $1)
+	//    AND ($2::bigint = 0 OR actor_id = $2)
+	//    AND ($3::text = '' OR action = $3)
+	//    AND created_at >= $4
+	//    AND created_at < $5
+	//  ORDER BY created_at DESC, id DESC
+	//  LIMIT $7 OFFSET $6
+	ListAuditLogs(ctx context.Context, arg *ListAuditLogsParams) ([]*AuditLog, error)
+	//ListDueAccountDeletions
+	//
+	//  SELECT user_id, requested_by, requested_at, scheduled_for
+	//  FROM account_deletions
+	//  WHERE scheduled_for <= $1::timestamptz
+	//  ORDER BY scheduled_for, user_id
+	//  LIMIT $2
+	ListDueAccountDeletions(ctx context.Context, arg *ListDueAccountDeletionsParams) ([]*AccountDeletions, error)
+	//ListFeatureFlags
+	//
+	//  SELECT name, enabled, updated_at
+	//  FROM feature_flags
+	//  ORDER BY name
+	ListFeatureFlags(ctx context.Context) ([]*FeatureFlags, error)
+	//ListIdentityChanges
+	//
+	//  SELECT id, user_id, field, old_value, new_value, status, token, expires_at, created_at, updated_at
+	//  FROM identity_changes
+	//  WHERE user_id = $1
+	//  ORDER BY created_at DESC, id DESC
+	ListIdentityChanges(ctx context.Context, userID int64) ([]*IdentityChanges, error)
+	// Lapsed suspensions ended by now but were not lifted yet.
+	//
+	//  SELECT id, user_id, kind, reason, moderator_id, ends_at, lifted_at, lifted_by, created_at
+	//  FROM moderation_actions
+	//  WHERE kind = 'suspension' AND lifted_at IS NULL AND ends_at <= $1::timestamptz
+	//  ORDER BY ends_at, id
+	//  LIMIT $2
+	ListLapsedSuspensions(ctx context.Context, arg *ListLapsedSuspensionsParams) ([]*ModerationActions, error)
+	//ListLoginAttemptsByUser
+	//
+	//  SELECT id, user_id, ip_address, user_agent, method, failure, created_at
+	//  FROM login_history
+	//  WHERE user_id = $1
+	//  ORDER BY created_at DESC, id DESC
+	//  LIMIT $2
+	ListLoginAttemptsByUser(ctx context.Context, arg *ListLoginAttemptsByUserParams) ([]*LoginHistory, error)
+	// Includes the failures of unknown accounts, whose user_id is NULL.
+	//
+	//  SELECT id, user_id, ip_address, user_agent, method, failure, created_at
+	//  FROM login_history
+	//  WHERE ip_address = $1
+	//    AND failure <> ''
+	//    AND created_at >= $2
+	//  ORDER BY created_at DESC, id DESC
+	//  LIMIT $3
+	ListLoginFailuresByIP(ctx context.Context, arg *ListLoginFailuresByIPParams) ([]*LoginHistory, error)
+	//ListMemberships
+	//
+	//  SELECT organization_id, user_id, role, status, invited_by, invited_at, joined_at
+	//  FROM organization_members
+	//  WHERE organization_id = $1
+	//  ORDER BY invited_at, user_id
+	//  LIMIT $3 OFFSET $2
+	ListMemberships(ctx context.Context, arg *ListMembershipsParams) ([]*OrganizationMembers, error)
+	//ListModerationActions
+	//
+	//  SELECT id, user_id, kind, reason, moderator_id, ends_at, lifted_at, lifted_by, created_at
+	//  FROM moderation_actions
+	//  WHERE user_id = $1
+	//  ORDER BY created_at DESC, id DESC
+	ListModerationActions(ctx context.Context, userID int64) ([]*ModerationActions, error)
+	//ListModerationAppealsByUser
+	//
+	//  SELECT id, action_id, user_id, statement, status, reviewer_id, response, created_at, decided_at
+	//  FROM moderation_appeals
+	//  WHERE user_id = $1
+	//  ORDER BY created_at DESC, id DESC
+	ListModerationAppealsByUser(ctx context.Context, userID int64) ([]*ModerationAppeals, error)
+	//ListNotificationPreferences
+	//
+	//  SELECT user_id, channel, topic, enabled, target, updated_at
+	//  FROM notification_preferences
+	//  WHERE user_id = $1
+	//  ORDER BY channel, topic
+	ListNotificationPreferences(ctx context.Context, userID int64) ([]*NotificationPreferences, error)
+	//ListOrganizations
+	//
+	//  SELECT id, uuid, name, slug, created_at, updated_at
+	//  FROM organizations
+	//  ORDER BY id
+	//  LIMIT $2 OFFSET $1
+	ListOrganizations(ctx context.Context, arg *ListOrganizationsParams) ([]*Organizations, error)
+	//ListOrganizationsByUser
+	//
+	//  SELECT o.id, o.uuid, o.name, o.slug, o.created_at, o.updated_at
+	//  FROM organizations o
+	//  JOIN organization_members m ON m.organization_id = o.id
+	//  WHERE m.user_id = $1 AND m.status = 'active'
+	//  ORDER BY o.name
+	//  LIMIT $2
+	ListOrganizationsByUser(ctx context.Context, arg *ListOrganizationsByUserParams) ([]*Organizations, error)
+	//ListPendingModerationAppeals
+	//
+	//  SELECT id, action_id, user_id, statement, status, reviewer_id, response, created_at, decided_at
+	//  FROM moderation_appeals
+	//  WHERE status = 'pending'
+	//  ORDER BY created_at, id
+	//  LIMIT $1
+	ListPendingModerationAppeals(ctx context.Context, rowLimit int32) ([]*ModerationAppeals, error)
+	// lint:ignore missing-limit
+	//
+	//  SELECT id, user_id, session_token, device_info, ip_address, user_agent, country, region, city,
+	//         organization_id, impersonator_id, refresh_token, refresh_expires_at, rotated_refresh_tokens,
+	//         created_at, expires_at, is_active
+	//  FROM user_sessions
+	//  WHERE user_id = $1
+	//  ORDER BY id DESC
+	ListSessionsByUser(ctx context.Context, userID int64) ([]*UserSessions, error)
+	// The first page's keyset cursor is replaced by a bound beyond any stored value.
+	//
+	//  SELECT id, user_id, event_id, event_type, kind, summary, actor_id, occurred_at
+	//  FROM user_activity
+	//  WHERE user_id = $1
+	//    AND (occurred_at < $2
+	//         OR (occurred_at = $2 AND id < $3))
+	//  ORDER BY occurred_at DESC, id DESC
+	//  LIMIT $4
+	ListUserActivityPage(ctx context.Context, arg *ListUserActivityPageParams) ([]*UserActivity, error)
+	//ListUserEvents
+	//
+	//  SELECT user_id, version, event_type, data, occurred_at
+	//  FROM user_events
+	//  WHERE user_id = $1
+	//    AND version > $2
+	//  ORDER BY version
+	ListUserEvents(ctx context.Context, arg *ListUserEventsParams) ([]*UserEvents, error)
+	//ListUserHistory
+	//
+	//  SELECT history_id, user_id, operation, email, username, first_name, last_name, status, role, tags, is_active, is_verified, profile_metadata, valid_from, valid_to FROM users_history
+	//  WHERE user_id = $1
+	//  ORDER BY valid_from DESC
+	//  LIMIT $2
+	ListUserHistory(ctx context.Context, arg *ListUserHistoryParams) ([]*UsersHistory, error)
+	//ListUserIdentities
+	//
+	//  SELECT id, user_id, provider, subject, email, linked_at, last_login_at
+	//  FROM user_identities
+	//  WHERE user_id = $1
+	//  ORDER BY provider
+	ListUserIdentities(ctx context.Context, userID int64) ([]*UserIdentities, error)
+	// Lists views matching a UserFilter, newest first with the user ID breaking
+	// ties. Empty sets match any view; a negative verified matches both states.
+	// Open time ranges are replaced by bounds beyond any stored value.
+	//
+	//  SELECT user_id, uuid, email, username, full_name, status, role, tags, is_verified,
+	//         created_at, source_updated_at, projected_at
+	//  FROM user_read_model
+	//  WHERE (cardinality($1::text[]) = 0 OR status = ANY($1::text[]))
+	//    AND (cardinality($2::text[]) = 0 OR role = ANY($2::text[]))
+	//    AND ($3::int < 0 OR is_verified = ($3::int = 1))
+	//    AND created_at > $4::timestamptz
+	//    AND created_at < $5::timestamptz
+	//    AND (cardinality($6::text[]) = 0 OR tags && $6::text[])
+	//    AND tags @> $7::text[]
+	//    AND NOT EXISTS (
+	//        SELECT 1 FROM unnest($8::text[]) AS term
+	//        WHERE user_read_model.search_text NOT LIKE '% ' || term || '%'
+	//    )
+	//  ORDER BY created_at DESC, user_id DESC
+	//  LIMIT $9::int OFFSET $10::int
+	ListUserViews(ctx context.Context, arg *ListUserViewsParams) ([]*ListUserViewsRow, error)
 	//ListUsers
 	//
-	//  SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata FROM users
-	//  WHERE is_active = TRUE
+	//  SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, deleted_at, username_canonical FROM users
+	//  WHERE deleted_at IS NULL
 	//  ORDER BY created_at DESC
 	//  LIMIT $1 OFFSET $2
 	ListUsers(ctx context.Context, arg *ListUsersParams) ([]*Users, error)
-	//SoftDeleteUser
+	//MarkUserVerified
 	//
 	//  UPDATE users
-	//  SET is_active = FALSE, updated_at = CURRENT_TIMESTAMP
+	//  SET is_verified = TRUE, updated_at = CURRENT_TIMESTAMP
 	//  WHERE id = $1
+	MarkUserVerified(ctx context.Context, id int64) error
+	// Merges the set object into the stored preferences and removes the removed keys.
+	//
+	//  INSERT INTO user_preferences (user_id, preferences, updated_at)
+	//  VALUES ($1, $2::jsonb, $3)
+	//  ON CONFLICT (user_id) DO UPDATE
+	//  SET preferences = (user_preferences.preferences || excluded.preferences) - $4::text[],
+	//      updated_at = excluded.updated_at
+	//  RETURNING user_id, preferences, updated_at
+	PatchUserPreferences(ctx context.Context, arg *PatchUserPreferencesParams) (*UserPreferences, error)
+	// Reads the changes of a slot without consuming them, stopping after the
+	// transaction that reaches row_limit. tables is a comma-separated list of
+	// qualified table names.
+	// lint:ignore missing-limit
+	//
+	//  SELECT lsn::text AS lsn, data::text AS data
+	//  FROM pg_logical_slot_peek_changes(
+	//      $1::text, NULL, $2::int,
+	//      'format-version', '2', 'include-transaction', 'true', 'add-tables', $3::text
+	//  )
+	PeekChangefeedChanges(ctx context.Context, arg *PeekChangefeedChangesParams) ([]*PeekChangefeedChangesRow, error)
+	// Permanently removes users soft deleted before the cutoff.
+	//
+	//  DELETE FROM users
+	//  WHERE deleted_at IS NOT NULL AND deleted_at < $1::timestamptz
+	PurgeDeletedUsers(ctx context.Context, cutoff time.Time) (int64, error)
+	//RecordIdentityLogin
+	//
+	//  UPDATE user_identities
+	//  SET email = $1, last_login_at = $2
+	//  WHERE id = $3
+	RecordIdentityLogin(ctx context.Context, arg *RecordIdentityLoginParams) (int64, error)
+	//RecordLoginAttempt
+	//
+	//  INSERT INTO login_history (user_id, ip_address, user_agent, method, failure, created_at)
+	//  VALUES ($1, $2, $3, $4, $5, $6)
+	//  RETURNING id
+	RecordLoginAttempt(ctx context.Context, arg *RecordLoginAttemptParams) (int64, error)
+	// Entries of an event already recorded for the user are ignored.
+	//
+	//  INSERT INTO user_activity (user_id, event_id, event_type, kind, summary, actor_id, occurred_at)
+	//  VALUES ($1, $2, $3, $4, $5, $6, $7)
+	//  ON CONFLICT (user_id, event_id) DO NOTHING
+	RecordUserActivity(ctx context.Context, arg *RecordUserActivityParams) error
+	//ReleaseIdempotencyKey
+	//
+	//  DELETE FROM idempotency_keys
+	//  WHERE idempotency_key = $1 AND completed_at IS NULL
+	ReleaseIdempotencyKey(ctx context.Context, idempotencyKey string) (int64, error)
+	//ReserveIdempotencyKey
+	//
+	//  INSERT INTO idempotency_keys (idempotency_key, operation, request_hash, created_at, expires_at)
+	//  VALUES ($1, $2, $3, $4, $5)
+	ReserveIdempotencyKey(ctx context.Context, arg *ReserveIdempotencyKeyParams) error
+	//RestoreUser
+	//
+	//  UPDATE users
+	//  SET is_active = TRUE, deleted_at = NULL, updated_at = CURRENT_TIMESTAMP
+	//  WHERE id = $1 AND deleted_at IS NOT NULL
+	RestoreUser(ctx context.Context, id int64) (int64, error)
+	//RetryJob
+	//
+	//  UPDATE jobs
+	//  SET status = 'pending', run_at = $1, locked_until = NULL,
+	//      last_error = $2, updated_at = $3
+	//  WHERE id = $4 AND status = 'running' AND attempts = $5
+	RetryJob(ctx context.Context, arg *RetryJobParams) (int64, error)
+	//SaveChangefeedCheckpoint
+	//
+	//  INSERT INTO changefeed_checkpoints (slot_name, lsn, updated_at)
+	//  VALUES ($1, $2, $3)
+	//  ON CONFLICT (slot_name) DO UPDATE SET
+	//      lsn = EXCLUDED.lsn,
+	//      updated_at = EXCLUDED.updated_at
+	SaveChangefeedCheckpoint(ctx context.Context, arg *SaveChangefeedCheckpointParams) error
+	//SaveUserSnapshot
+	//
+	//  INSERT INTO user_snapshots (user_id, version, deleted, state, created_at)
+	//  VALUES ($1, $2, $3, $4, $5)
+	//  ON CONFLICT (user_id, version) DO NOTHING
+	SaveUserSnapshot(ctx context.Context, arg *SaveUserSnapshotParams) error
+	// Returns one page of matching users together with facet counts computed
+	// over every match. Facets are JSONB columns repeated on each row; a single
+	// row with NULL user columns is returned when the page is empty.
+	//
+	//  WITH matched AS (
+	//      SELECT id, uuid, email, username, first_name, last_name, status, role, tags, created_at
+	//      FROM users
+	//      WHERE deleted_at IS NULL
+	//        AND (
+	//            email ILIKE '%' || $1::text || '%'
+	//            OR username ILIKE '%' || $1::text || '%'
+	//            OR first_name ILIKE '%' || $1::text || '%'
+	//            OR last_name ILIKE '%' || $1::text || '%'
+	//        )
+	//  ),
+	//  status_facets AS (
+	//      SELECT COALESCE(jsonb_object_agg(status, total), '{}'::jsonb) AS counts
+	//      FROM (SELECT status, COUNT(*) AS total FROM matched GROUP BY status) AS s
+	//  ),
+	//  role_facets AS (
+	//      SELECT COALESCE(jsonb_object_agg(role, total), '{}'::jsonb) AS counts
+	//      FROM (SELECT role, COUNT(*) AS total FROM matched GROUP BY role) AS r
+	//  ),
+	//  tag_facets AS (
+	//      SELECT COALESCE(jsonb_agg(jsonb_build_array(tag, total) ORDER BY total DESC, tag), '[]'::jsonb) AS counts
+	//      FROM (
+	//          SELECT tag, COUNT(*) AS total
+	//          FROM matched, unnest(matched.tags) AS tag
+	//          GROUP BY tag
+	//          ORDER BY total DESC, tag
+	//          LIMIT $2::int
+	//      ) AS t
+	//  ),
+	//  page AS (
+	//      SELECT id, uuid, email, username, first_name, last_name, status, role, tags, created_at
+	//      FROM matched
+	//      WHERE $3::text = '' OR status = $3::text
+	//      ORDER BY created_at DESC
+	//      LIMIT $4::int
+	//  )
+	//  SELECT
+	//      page.id, page.uuid, page.email, page.username, page.first_name, page.last_name,
+	//      page.status, page.role, page.tags, page.created_at,
+	//      status_facets.counts::jsonb AS status_facets,
+	//      role_facets.counts::jsonb AS role_facets,
+	//      tag_facets.counts::jsonb AS tag_facets
+	//  FROM status_facets
+	//  CROSS JOIN role_facets
+	//  CROSS JOIN tag_facets
+	//  LEFT JOIN page ON TRUE
+	SearchUsersWithFacets(ctx context.Context, arg *SearchUsersWithFacetsParams) ([]*SearchUsersWithFacetsRow, error)
+	//SetFeatureFlag
+	//
+	//  INSERT INTO feature_flags (name, enabled, updated_at)
+	//  VALUES ($1, $2, $3)
+	//  ON CONFLICT (name) DO UPDATE
+	//  SET enabled = excluded.enabled, updated_at = excluded.updated_at
+	SetFeatureFlag(ctx context.Context, arg *SetFeatureFlagParams) error
+	//SoftDeleteUser
+	//
+	//  UPDATE users
+	//  SET is_active = FALSE, deleted_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+	//  WHERE id = $1 AND deleted_at IS NULL
 	SoftDeleteUser(ctx context.Context, id int64) error
+	//SoftDeleteUsers
+	//
+	//  UPDATE users
+	//  SET is_active = FALSE, deleted_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+	//  WHERE id = ANY($1::bigint[]) AND deleted_at IS NULL
+	SoftDeleteUsers(ctx context.Context, ids []int64) (int64, error)
+	//UpdateIdentityChange
+	//
+	//  UPDATE identity_changes
+	//  SET status = $1, token = $2, expires_at = $3, updated_at = $4
+	//  WHERE id = $5
+	UpdateIdentityChange(ctx context.Context, arg *UpdateIdentityChangeParams) (int64, error)
 	//UpdateLastLogin
 	//
 	//  UPDATE users
 	//  SET last_login_at = CURRENT_TIMESTAMP
 	//  WHERE id = $1
 	UpdateLastLogin(ctx context.Context, id int64) error
+	//UpdateMembership
+	//
+	//  UPDATE organization_members
+	//  SET role = $1, status = $2, joined_at = $3
+	//  WHERE organization_id = $4 AND user_id = $5
+	UpdateMembership(ctx context.Context, arg *UpdateMembershipParams) (int64, error)
+	//UpdateOrganization
+	//
+	//  UPDATE organizations
+	//  SET name = $1, slug = $2, updated_at = $3
+	//  WHERE id = $4
+	UpdateOrganization(ctx context.Context, arg *UpdateOrganizationParams) (int64, error)
 	//UpdatePassword
 	//
 	//  UPDATE users
 	//  SET password_hash = $2, updated_at = CURRENT_TIMESTAMP
 	//  WHERE id = $1
 	UpdatePassword(ctx context.Context, arg *UpdatePasswordParams) error
+	//UpdateSession
+	//
+	//  UPDATE user_sessions SET
+	//      device_info = $1,
+	//      ip_address = $2,
+	//      user_agent = $3,
+	//      country = $4,
+	//      region = $5,
+	//      city = $6,
+	//      organization_id = $7,
+	//      impersonator_id = $8,
+	//      refresh_token = $9,
+	//      refresh_expires_at = $10,
+	//      rotated_refresh_tokens = $11,
+	//      expires_at = $12,
+	//      is_active = $13
+	//  WHERE id = $14
+	UpdateSession(ctx context.Context, arg *UpdateSessionParams) (int64, error)
+	// Stores the session only while it is active with the expected refresh token,
+	// so that concurrent rotations and revocations are not overwritten.
+	//
+	//  UPDATE user_sessions SET
+	//      device_info = $1,
+	//      ip_address = $2,
+	//      user_agent = $3,
+	//      country = $4,
+	//      region = $5,
+	//      city = $6,
+	//      organization_id = $7,
+	//      impersonator_id = $8,
+	//      refresh_token = $9,
+	//      refresh_expires_at = $10,
+	//      rotated_refresh_tokens = $11,
+	//      expires_at = $12,
+	//      is_active = $13
+	//  WHERE id = $14
+	//    AND refresh_token = $15
+	//    AND is_active = TRUE
+	UpdateSessionIfCurrent(ctx context.Context, arg *UpdateSessionIfCurrentParams) (int64, error)
 	//UpdateUser
 	//
 	//  UPDATE users
@@ -87,10 +996,59 @@ type Querier interface {
 	//      last_name = COALESCE($5, last_name),
 	//      profile_metadata = COALESCE($6, profile_metadata),
 	//      is_active = COALESCE($7, is_active),
-	//      is_verified = COALESCE($8, is_verified)
-	//  WHERE id = $1
-	//  RETURNING id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata
+	//      is_verified = COALESCE($8, is_verified),
+	//      status = COALESCE($9, status),
+	//      role = COALESCE($10, role),
+	//      tags = COALESCE($11, tags),
+	//      last_login_at = COALESCE($12, last_login_at),
+	//      username_canonical = $13,
+	//      updated_at = CURRENT_TIMESTAMP
+	//  WHERE id = $1 AND deleted_at IS NULL
+	//  RETURNING id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, deleted_at, username_canonical
 	UpdateUser(ctx context.Context, arg *UpdateUserParams) (*Users, error)
+	//UpdateUserRole
+	//
+	//  UPDATE users
+	//  SET role = $2, updated_at = CURRENT_TIMESTAMP
+	//  WHERE id = $1
+	UpdateUserRole(ctx context.Context, arg *UpdateUserRoleParams) error
+	//UpdateUserStatus
+	//
+	//  UPDATE users
+	//  SET status = $2, updated_at = CURRENT_TIMESTAMP
+	//  WHERE id = $1
+	UpdateUserStatus(ctx context.Context, arg *UpdateUserStatusParams) error
+	//UpdateUsersStatus
+	//
+	//  UPDATE users
+	//  SET status = $1, updated_at = CURRENT_TIMESTAMP
+	//  WHERE id = ANY($2::bigint[]) AND deleted_at IS NULL
+	UpdateUsersStatus(ctx context.Context, arg *UpdateUsersStatusParams) (int64, error)
+	//UpsertNotificationPreference
+	//
+	//  INSERT INTO notification_preferences (user_id, channel, topic, enabled, target, updated_at)
+	//  VALUES ($1, $2, $3, $4, $5, $6)
+	//  ON CONFLICT (user_id, channel, topic) DO UPDATE
+	//  SET enabled = excluded.enabled, target = excluded.target, updated_at = excluded.updated_at
+	UpsertNotificationPreference(ctx context.Context, arg *UpsertNotificationPreferenceParams) error
+	// A view projected from an older version of the user is not stored, so
+	// views projected out of order never go back in time.
+	//
+	//  INSERT INTO user_read_model (
+	//      user_id, uuid, email, username, full_name, status, role, tags, is_verified,
+	//      search_text, created_at, source_updated_at, projected_at
+	//  ) VALUES (
+	//      $1, $2, $3, $4, $5,
+	//      $6, $7, $8, $9,
+	//      $10, $11, $12, $13
+	//  )
+	//  ON CONFLICT (user_id) DO UPDATE
+	//  SET uuid = excluded.uuid, email = excluded.email, username = excluded.username,
+	//      full_name = excluded.full_name, status = excluded.status, role = excluded.role, tags = excluded.tags,
+	//      is_verified = excluded.is_verified, search_text = excluded.search_text, created_at = excluded.created_at,
+	//      source_updated_at = excluded.source_updated_at, projected_at = excluded.projected_at
+	//  WHERE user_read_model.source_updated_at <= excluded.source_updated_at
+	UpsertUserView(ctx context.Context, arg *UpsertUserViewParams) error
 	//VerifyUser
 	//
 	//  UPDATE users
@@ -9,17 +9,98 @@ package postgres
 
 import (
 	"context"
+	"encoding/json"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const ClaimUsersByStatus = `-- name: ClaimUsersByStatus :many
+SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, deleted_at, username_canonical FROM users
+WHERE status = $1::text AND deleted_at IS NULL
+ORDER BY updated_at, id
+LIMIT $2::int
+FOR UPDATE SKIP LOCKED
+`
+
+type ClaimUsersByStatusParams struct {
+	Status     string `db:"status" json:"status"`
+	ClaimLimit int32  `db:"claim_limit" json:"claimLimit"`
+}
+
+// Work-queue claim: locks the oldest users in a status, skipping rows
+// already claimed by concurrent transactions.
+//
+//	SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, deleted_at, username_canonical FROM users
+//	WHERE status = $1::text AND deleted_at IS NULL
+//	ORDER BY updated_at, id
+//	LIMIT $2::int
+//	FOR UPDATE SKIP LOCKED
+func (q *Queries) ClaimUsersByStatus(ctx context.Context, arg *ClaimUsersByStatusParams) ([]*Users, error) {
+	rows, err := q.db.Query(ctx, ClaimUsersByStatus, arg.Status, arg.ClaimLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*Users{}
+	for rows.Next() {
+		var i Users
+		if err := rows.Scan(
+			&i.ID,
+			&i.UUID,
+			&i.Email,
+			&i.Username,
+			&i.PasswordHash,
+			&i.FirstName,
+			&i.LastName,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.LastLoginAt,
+			&i.IsActive,
+			&i.IsVerified,
+			&i.ProfileMetadata,
+			&i.Status,
+			&i.Role,
+			&i.Tags,
+			&i.DeletedAt,
+			&i.UsernameCanonical,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+type CopyUsersParams struct {
+	UUID              uuid.UUID          `db:"uuid" json:"uuid"`
+	Email             string             `db:"email" json:"email"`
+	Username          string             `db:"username" json:"username"`
+	PasswordHash      string             `db:"password_hash" json:"passwordHash"`
+	FirstName         string             `db:"first_name" json:"firstName"`
+	LastName          string             `db:"last_name" json:"lastName"`
+	ProfileMetadata   []byte             `db:"profile_metadata" json:"profileMetadata"`
+	IsActive          *bool              `db:"is_active" json:"isActive"`
+	IsVerified        *bool              `db:"is_verified" json:"isVerified"`
+	Status            string             `db:"status" json:"status"`
+	Role              string             `db:"role" json:"role"`
+	Tags              []string           `db:"tags" json:"tags"`
+	CreatedAt         pgtype.Timestamptz `db:"created_at" json:"createdAt"`
+	UpdatedAt         pgtype.Timestamptz `db:"updated_at" json:"updatedAt"`
+	UsernameCanonical string             `db:"username_canonical" json:"usernameCanonical"`
+}
+
 const CountActiveUsers = `-- name: CountActiveUsers :one
-SELECT COUNT(*) FROM users WHERE is_active = TRUE
+SELECT COUNT(*) FROM users WHERE deleted_at IS NULL
 `
 
 // CountActiveUsers
 //
-//	SELECT COUNT(*) FROM users WHERE is_active = TRUE
+//	SELECT COUNT(*) FROM users WHERE deleted_at IS NULL
 func (q *Queries) CountActiveUsers(ctx context.Context) (int64, error) {
 	row := q.db.QueryRow(ctx, CountActiveUsers)
 	var count int64
@@ -27,36 +108,85 @@ func (q *Queries) CountActiveUsers(ctx context.Context) (int64, error) {
 	return count, err
 }
 
+const CountUsersByStatus = `-- name: CountUsersByStatus :many
+SELECT status, COUNT(*) AS total
+FROM users
+WHERE deleted_at IS NULL
+GROUP BY status
+`
+
+type CountUsersByStatusRow struct {
+	Status string `db:"status" json:"status"`
+	Total  int64  `db:"total" json:"total"`
+}
+
+// CountUsersByStatus
+//
+//	SELECT status, COUNT(*) AS total
+//	FROM users
+//	WHERE deleted_at IS NULL
+//	GROUP BY status
+func (q *Queries) CountUsersByStatus(ctx context.Context) ([]*CountUsersByStatusRow, error) {
+	rows, err := q.db.Query(ctx, CountUsersByStatus)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*CountUsersByStatusRow{}
+	for rows.Next() {
+		var i CountUsersByStatusRow
+		if err := rows.Scan(&i.Status, &i.Total); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const CreateUser = `-- name: CreateUser :one
 INSERT INTO users (
-    uuid, email, username, password_hash, 
-    first_name, last_name, profile_metadata, is_active
+    uuid, email, username, password_hash,
+    first_name, last_name, profile_metadata, is_active,
+    is_verified, status, role, tags, created_at, updated_at,
+    username_canonical
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15
 )
-RETURNING id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata
+RETURNING id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, deleted_at, username_canonical
 `
 
 type CreateUserParams struct {
-	UUID            uuid.UUID `db:"uuid" json:"uuid"`
-	Email           string    `db:"email" json:"email"`
-	Username        string    `db:"username" json:"username"`
-	PasswordHash    string    `db:"password_hash" json:"passwordHash"`
-	FirstName       string    `db:"first_name" json:"firstName"`
-	LastName        string    `db:"last_name" json:"lastName"`
-	ProfileMetadata []byte    `db:"profile_metadata" json:"profileMetadata"`
-	IsActive        *bool     `db:"is_active" json:"isActive"`
+	UUID              uuid.UUID          `db:"uuid" json:"uuid"`
+	Email             string             `db:"email" json:"email"`
+	Username          string             `db:"username" json:"username"`
+	PasswordHash      string             `db:"password_hash" json:"passwordHash"`
+	FirstName         string             `db:"first_name" json:"firstName"`
+	LastName          string             `db:"last_name" json:"lastName"`
+	ProfileMetadata   []byte             `db:"profile_metadata" json:"profileMetadata"`
+	IsActive          *bool              `db:"is_active" json:"isActive"`
+	IsVerified        *bool              `db:"is_verified" json:"isVerified"`
+	Status            string             `db:"status" json:"status"`
+	Role              string             `db:"role" json:"role"`
+	Tags              []string           `db:"tags" json:"tags"`
+	CreatedAt         pgtype.Timestamptz `db:"created_at" json:"createdAt"`
+	UpdatedAt         pgtype.Timestamptz `db:"updated_at" json:"updatedAt"`
+	UsernameCanonical string             `db:"username_canonical" json:"usernameCanonical"`
 }
 
 // CreateUser
 //
 //	INSERT INTO users (
 //	    uuid, email, username, password_hash,
-//	    first_name, last_name, profile_metadata, is_active
+//	    first_name, last_name, profile_metadata, is_active,
+//	    is_verified, status, role, tags, created_at, updated_at,
+//	    username_canonical
 //	) VALUES (
-//	    $1, $2, $3, $4, $5, $6, $7, $8
+//	    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15
 //	)
-//	RETURNING id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata
+//	RETURNING id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, deleted_at, username_canonical
 func (q *Queries) CreateUser(ctx context.Context, arg *CreateUserParams) (*Users, error) {
 	row := q.db.QueryRow(ctx, CreateUser,
 		arg.UUID,
@@ -67,6 +197,13 @@ func (q *Queries) CreateUser(ctx context.Context, arg *CreateUserParams) (*Users
 		arg.LastName,
 		arg.ProfileMetadata,
 		arg.IsActive,
+		arg.IsVerified,
+		arg.Status,
+		arg.Role,
+		arg.Tags,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.UsernameCanonical,
 	)
 	var i Users
 	err := row.Scan(
@@ -83,17 +220,235 @@ func (q *Queries) CreateUser(ctx context.Context, arg *CreateUserParams) (*Users
 		&i.IsActive,
 		&i.IsVerified,
 		&i.ProfileMetadata,
+		&i.Status,
+		&i.Role,
+		&i.Tags,
+		&i.DeletedAt,
+		&i.UsernameCanonical,
+	)
+	return &i, err
+}
+
+const DeleteUserHistory = `-- name: DeleteUserHistory :execrows
+DELETE FROM users_history
+WHERE user_id = $1
+`
+
+// Removes every archived version of a user.
+//
+//	DELETE FROM users_history
+//	WHERE user_id = $1
+func (q *Queries) DeleteUserHistory(ctx context.Context, userID int64) (int64, error) {
+	result, err := q.db.Exec(ctx, DeleteUserHistory, userID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const FilterUsers = `-- name: FilterUsers :many
+SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, deleted_at, username_canonical FROM users
+WHERE deleted_at IS NULL
+  AND (cardinality($1::text[]) = 0 OR status = ANY($1::text[]))
+  AND (cardinality($2::text[]) = 0 OR role = ANY($2::text[]))
+  AND ($3::int < 0 OR is_verified = ($3::int = 1))
+  AND created_at > $4::timestamptz
+  AND created_at < $5::timestamptz
+  AND (cardinality($6::text[]) = 0 OR tags && $6::text[])
+  AND tags @> $7::text[]
+  AND ($8::text = ''
+       OR users_search_document(email, username, first_name, last_name)
+          @@ to_tsquery('simple', $8::text))
+  AND (created_at, id) < ($9::timestamptz, $10::bigint)
+ORDER BY created_at DESC, id DESC
+LIMIT $12::int OFFSET $11::int
+`
+
+type FilterUsersParams struct {
+	Statuses        []string  `db:"statuses" json:"statuses"`
+	Roles           []string  `db:"roles" json:"roles"`
+	Verified        int32     `db:"verified" json:"verified"`
+	CreatedAfter    time.Time `db:"created_after" json:"createdAfter"`
+	CreatedBefore   time.Time `db:"created_before" json:"createdBefore"`
+	TagsAny         []string  `db:"tags_any" json:"tagsAny"`
+	TagsAll         []string  `db:"tags_all" json:"tagsAll"`
+	Query           string    `db:"query" json:"query"`
+	CursorCreatedAt time.Time `db:"cursor_created_at" json:"cursorCreatedAt"`
+	CursorID        int64     `db:"cursor_id" json:"cursorId"`
+	RowOffset       int32     `db:"row_offset" json:"rowOffset"`
+	RowLimit        int32     `db:"row_limit" json:"rowLimit"`
+}
+
+// Lists users matching a UserFilter, newest first with the ID breaking ties.
+// Empty sets and an empty query match any user; a negative verified matches
+// both states. Open time ranges and the first page's keyset cursor are
+// replaced by bounds beyond any stored value.
+// The query is a to_tsquery expression over users_search_document.
+//
+//	SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, deleted_at, username_canonical FROM users
+//	WHERE deleted_at IS NULL
+//	  AND (cardinality($1::text[]) = 0 OR status = ANY($1::text[]))
+//	  AND (cardinality($2::text[]) = 0 OR role = ANY($2::text[]))
+//	  AND ($3::int < 0 OR is_verified = ($3::int = 1))
+//	  AND created_at > $4::timestamptz
+//	  AND created_at < $5::timestamptz
+//	  AND (cardinality($6::text[]) = 0 OR tags && $6::text[])
+//	  AND tags @> $7::text[]
+//	  AND ($8::text = ''
+//	       OR users_search_document(email, username, first_name, last_name)
+//	          @@ to_tsquery('simple', $8::text))
+//	  AND (created_at, id) < ($9::timestamptz, $10::bigint)
+//	ORDER BY created_at DESC, id DESC
+//	LIMIT $12::int OFFSET $11::int
+func (q *Queries) FilterUsers(ctx context.Context, arg *FilterUsersParams) ([]*Users, error) {
+	rows, err := q.db.Query(ctx, FilterUsers,
+		arg.Statuses,
+		arg.Roles,
+		arg.Verified,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.TagsAny,
+		arg.TagsAll,
+		arg.Query,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.RowOffset,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*Users{}
+	for rows.Next() {
+		var i Users
+		if err := rows.Scan(
+			&i.ID,
+			&i.UUID,
+			&i.Email,
+			&i.Username,
+			&i.PasswordHash,
+			&i.FirstName,
+			&i.LastName,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.LastLoginAt,
+			&i.IsActive,
+			&i.IsVerified,
+			&i.ProfileMetadata,
+			&i.Status,
+			&i.Role,
+			&i.Tags,
+			&i.DeletedAt,
+			&i.UsernameCanonical,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const GetUserAsOf = `-- name: GetUserAsOf :one
+SELECT user_id, operation, email, username, first_name, last_name, status, role, tags,
+       is_active, is_verified, valid_from, valid_to
+FROM (
+    SELECT u.id AS user_id, 'current' AS operation, u.email, u.username, u.first_name, u.last_name,
+           u.status, u.role, u.tags, u.is_active, u.is_verified,
+           COALESCE(u.updated_at, u.created_at) AS valid_from,
+           NULL::timestamptz AS valid_to, 1 AS is_current
+    FROM users u
+    WHERE u.id = $1::bigint
+      AND COALESCE(u.updated_at, u.created_at) <= $2::timestamptz
+    UNION ALL
+    SELECT h.user_id, h.operation, h.email, h.username, h.first_name, h.last_name,
+           h.status, h.role, h.tags, h.is_active, h.is_verified,
+           h.valid_from, h.valid_to, 0
+    FROM users_history h
+    WHERE h.user_id = $1::bigint
+      AND h.valid_from <= $2::timestamptz
+      AND h.valid_to > $2::timestamptz
+) AS versions
+ORDER BY is_current, valid_from DESC
+LIMIT 1
+`
+
+type GetUserAsOfParams struct {
+	UserID int64     `db:"user_id" json:"userId"`
+	AsOf   time.Time `db:"as_of" json:"asOf"`
+}
+
+type GetUserAsOfRow struct {
+	UserID     int64              `db:"user_id" json:"userId"`
+	Operation  string             `db:"operation" json:"operation"`
+	Email      string             `db:"email" json:"email"`
+	Username   string             `db:"username" json:"username"`
+	FirstName  string             `db:"first_name" json:"firstName"`
+	LastName   string             `db:"last_name" json:"lastName"`
+	Status     string             `db:"status" json:"status"`
+	Role       string             `db:"role" json:"role"`
+	Tags       []string           `db:"tags" json:"tags"`
+	IsActive   *bool              `db:"is_active" json:"isActive"`
+	IsVerified *bool              `db:"is_verified" json:"isVerified"`
+	ValidFrom  pgtype.Timestamptz `db:"valid_from" json:"validFrom"`
+	ValidTo    pgtype.Timestamptz `db:"valid_to" json:"validTo"`
+}
+
+// Returns the version of a user that was valid at the given time, preferring
+// archived versions over the current row.
+//
+//	SELECT user_id, operation, email, username, first_name, last_name, status, role, tags,
+//	       is_active, is_verified, valid_from, valid_to
+//	FROM (
+//	    SELECT u.id AS user_id, 'current' AS operation, u.email, u.username, u.first_name, u.last_name,
+//	           u.status, u.role, u.tags, u.is_active, u.is_verified,
+//	           COALESCE(u.updated_at, u.created_at) AS valid_from,
+//	           NULL::timestamptz AS valid_to, 1 AS is_current
+//	    FROM users u
+//	    WHERE u.id = $1::bigint
+//	      AND COALESCE(u.updated_at, u.created_at) <= $2::timestamptz
+//	    UNION ALL
+//	    SELECT h.user_id, h.operation, h.email, h.username, h.first_name, h.last_name,
+//	           h.status, h.role, h.tags, h.is_active, h.is_verified,
+//	           h.valid_from, h.valid_to, 0
+//	    FROM users_history h
+//	    WHERE h.user_id = $1::bigint
+//	      AND h.valid_from <= $2::timestamptz
+//	      AND h.valid_to > $2::timestamptz
+//	) AS versions
+//	ORDER BY is_current, valid_from DESC
+//	LIMIT 1
+func (q *Queries) GetUserAsOf(ctx context.Context, arg *GetUserAsOfParams) (*GetUserAsOfRow, error) {
+	row := q.db.QueryRow(ctx, GetUserAsOf, arg.UserID, arg.AsOf)
+	var i GetUserAsOfRow
+	err := row.Scan(
+		&i.UserID,
+		&i.Operation,
+		&i.Email,
+		&i.Username,
+		&i.FirstName,
+		&i.LastName,
+		&i.Status,
+		&i.Role,
+		&i.Tags,
+		&i.IsActive,
+		&i.IsVerified,
+		&i.ValidFrom,
+		&i.ValidTo,
 	)
 	return &i, err
 }
 
 const GetUserByEmail = `-- name: GetUserByEmail :one
-SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata FROM users WHERE email = $1 AND is_active = TRUE
+SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, deleted_at, username_canonical FROM users WHERE email = $1 AND deleted_at IS NULL
 `
 
 // GetUserByEmail
 //
-//	SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata FROM users WHERE email = $1 AND is_active = TRUE
+//	SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, deleted_at, username_canonical FROM users WHERE email = $1 AND deleted_at IS NULL
 func (q *Queries) GetUserByEmail(ctx context.Context, email string) (*Users, error) {
 	row := q.db.QueryRow(ctx, GetUserByEmail, email)
 	var i Users
@@ -111,17 +466,22 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (*Users, err
 		&i.IsActive,
 		&i.IsVerified,
 		&i.ProfileMetadata,
+		&i.Status,
+		&i.Role,
+		&i.Tags,
+		&i.DeletedAt,
+		&i.UsernameCanonical,
 	)
 	return &i, err
 }
 
 const GetUserByID = `-- name: GetUserByID :one
-SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata FROM users WHERE id = $1 AND is_active = TRUE
+SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, deleted_at, username_canonical FROM users WHERE id = $1 AND deleted_at IS NULL
 `
 
 // GetUserByID
 //
-//	SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata FROM users WHERE id = $1 AND is_active = TRUE
+//	SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, deleted_at, username_canonical FROM users WHERE id = $1 AND deleted_at IS NULL
 func (q *Queries) GetUserByID(ctx context.Context, id int64) (*Users, error) {
 	row := q.db.QueryRow(ctx, GetUserByID, id)
 	var i Users
@@ -139,17 +499,55 @@ func (q *Queries) GetUserByID(ctx context.Context, id int64) (*Users, error) {
 		&i.IsActive,
 		&i.IsVerified,
 		&i.ProfileMetadata,
+		&i.Status,
+		&i.Role,
+		&i.Tags,
+		&i.DeletedAt,
+		&i.UsernameCanonical,
+	)
+	return &i, err
+}
+
+const GetUserByIDForUpdate = `-- name: GetUserByIDForUpdate :one
+SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, deleted_at, username_canonical FROM users WHERE id = $1 AND deleted_at IS NULL LIMIT 1 FOR UPDATE
+`
+
+// Locks the user row until the surrounding transaction ends.
+//
+//	SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, deleted_at, username_canonical FROM users WHERE id = $1 AND deleted_at IS NULL LIMIT 1 FOR UPDATE
+func (q *Queries) GetUserByIDForUpdate(ctx context.Context, id int64) (*Users, error) {
+	row := q.db.QueryRow(ctx, GetUserByIDForUpdate, id)
+	var i Users
+	err := row.Scan(
+		&i.ID,
+		&i.UUID,
+		&i.Email,
+		&i.Username,
+		&i.PasswordHash,
+		&i.FirstName,
+		&i.LastName,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.LastLoginAt,
+		&i.IsActive,
+		&i.IsVerified,
+		&i.ProfileMetadata,
+		&i.Status,
+		&i.Role,
+		&i.Tags,
+		&i.DeletedAt,
+		&i.UsernameCanonical,
 	)
 	return &i, err
 }
 
 const GetUserByUUID = `-- name: GetUserByUUID :one
-SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata FROM users WHERE uuid = $1 AND is_active = TRUE
+SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, deleted_at, username_canonical FROM users WHERE uuid = $1 AND deleted_at IS NULL
 `
 
 // GetUserByUUID
 //
-//	SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata FROM users WHERE uuid = $1 AND is_active = TRUE
+//	SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, deleted_at, username_canonical FROM users WHERE uuid = $1 AND deleted_at IS NULL
 func (q *Queries) GetUserByUUID(ctx context.Context, argUuid uuid.UUID) (*Users, error) {
 	row := q.db.QueryRow(ctx, GetUserByUUID, argUuid)
 	var i Users
@@ -167,19 +565,24 @@ func (q *Queries) GetUserByUUID(ctx context.Context, argUuid uuid.UUID) (*Users,
 		&i.IsActive,
 		&i.IsVerified,
 		&i.ProfileMetadata,
+		&i.Status,
+		&i.Role,
+		&i.Tags,
+		&i.DeletedAt,
+		&i.UsernameCanonical,
 	)
 	return &i, err
 }
 
 const GetUserByUsername = `-- name: GetUserByUsername :one
-SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata FROM users WHERE username = $1 AND is_active = TRUE
+SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, deleted_at, username_canonical FROM users WHERE username_canonical = $1 AND deleted_at IS NULL
 `
 
 // GetUserByUsername
 //
-//	SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata FROM users WHERE username = $1 AND is_active = TRUE
-func (q *Queries) GetUserByUsername(ctx context.Context, username string) (*Users, error) {
-	row := q.db.QueryRow(ctx, GetUserByUsername, username)
+//	SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, deleted_at, username_canonical FROM users WHERE username_canonical = $1 AND deleted_at IS NULL
+func (q *Queries) GetUserByUsername(ctx context.Context, usernameCanonical string) (*Users, error) {
+	row := q.db.QueryRow(ctx, GetUserByUsername, usernameCanonical)
 	var i Users
 	err := row.Scan(
 		&i.ID,
@@ -195,16 +598,57 @@ func (q *Queries) GetUserByUsername(ctx context.Context, username string) (*User
 		&i.IsActive,
 		&i.IsVerified,
 		&i.ProfileMetadata,
+		&i.Status,
+		&i.Role,
+		&i.Tags,
+		&i.DeletedAt,
+		&i.UsernameCanonical,
 	)
 	return &i, err
 }
 
+const GetUserIDsByUUIDs = `-- name: GetUserIDsByUUIDs :many
+SELECT id, uuid FROM users WHERE uuid = ANY($1::uuid[])
+`
+
+type GetUserIDsByUUIDsRow struct {
+	ID   int64     `db:"id" json:"id"`
+	UUID uuid.UUID `db:"uuid" json:"uuid"`
+}
+
+// GetUserIDsByUUIDs
+//
+//	SELECT id, uuid FROM users WHERE uuid = ANY($1::uuid[])
+func (q *Queries) GetUserIDsByUUIDs(ctx context.Context, uuids []uuid.UUID) ([]*GetUserIDsByUUIDsRow, error) {
+	rows, err := q.db.Query(ctx, GetUserIDsByUUIDs, uuids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*GetUserIDsByUUIDsRow{}
+	for rows.Next() {
+		var i GetUserIDsByUUIDsRow
+		if err := rows.Scan(&i.ID, &i.UUID); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const GetUserStats = `-- name: GetUserStats :one
 SELECT 
     COUNT(*) as total_users,
-    COUNT(*) FILTER (WHERE is_active = TRUE) as active_users,
+    COUNT(*) FILTER (WHERE deleted_at IS NULL) as active_users,
     COUNT(*) FILTER (WHERE is_verified = TRUE) as verified_users,
-    COUNT(*) FILTER (WHERE last_login_at IS NOT NULL) as users_with_logins
+    COUNT(*) FILTER (WHERE last_login_at IS NOT NULL) as users_with_logins,
+    COUNT(*) FILTER (WHERE status = 'inactive') as inactive_users,
+    COUNT(*) FILTER (WHERE status = 'suspended') as suspended_users,
+    COUNT(*) FILTER (WHERE created_at >= NOW() - INTERVAL '30 days') as new_users_30d,
+    COUNT(*) FILTER (WHERE created_at >= NOW() - INTERVAL '7 days') as new_users_7d
 FROM users
 `
 
@@ -213,15 +657,23 @@ type GetUserStatsRow struct {
 	ActiveUsers     int64 `db:"active_users" json:"activeUsers"`
 	VerifiedUsers   int64 `db:"verified_users" json:"verifiedUsers"`
 	UsersWithLogins int64 `db:"users_with_logins" json:"usersWithLogins"`
+	InactiveUsers   int64 `db:"inactive_users" json:"inactiveUsers"`
+	SuspendedUsers  int64 `db:"suspended_users" json:"suspendedUsers"`
+	NewUsers30d     int64 `db:"new_users_30d" json:"newUsers30d"`
+	NewUsers7d      int64 `db:"new_users_7d" json:"newUsers7d"`
 }
 
 // GetUserStats
 //
 //	SELECT
 //	    COUNT(*) as total_users,
-//	    COUNT(*) FILTER (WHERE is_active = TRUE) as active_users,
+//	    COUNT(*) FILTER (WHERE deleted_at IS NULL) as active_users,
 //	    COUNT(*) FILTER (WHERE is_verified = TRUE) as verified_users,
-//	    COUNT(*) FILTER (WHERE last_login_at IS NOT NULL) as users_with_logins
+//	    COUNT(*) FILTER (WHERE last_login_at IS NOT NULL) as users_with_logins,
+//	    COUNT(*) FILTER (WHERE status = 'inactive') as inactive_users,
+//	    COUNT(*) FILTER (WHERE status = 'suspended') as suspended_users,
+//	    COUNT(*) FILTER (WHERE created_at >= NOW() - INTERVAL '30 days') as new_users_30d,
+//	    COUNT(*) FILTER (WHERE created_at >= NOW() - INTERVAL '7 days') as new_users_7d
 //	FROM users
 func (q *Queries) GetUserStats(ctx context.Context) (*GetUserStatsRow, error) {
 	row := q.db.QueryRow(ctx, GetUserStats)
@@ -231,13 +683,117 @@ func (q *Queries) GetUserStats(ctx context.Context) (*GetUserStatsRow, error) {
 		&i.ActiveUsers,
 		&i.VerifiedUsers,
 		&i.UsersWithLogins,
+		&i.InactiveUsers,
+		&i.SuspendedUsers,
+		&i.NewUsers30d,
+		&i.NewUsers7d,
 	)
 	return &i, err
 }
 
+const GetUsersByIDs = `-- name: GetUsersByIDs :many
+SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, deleted_at, username_canonical FROM users WHERE id = ANY($1::bigint[]) AND deleted_at IS NULL
+`
+
+// GetUsersByIDs
+//
+//	SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, deleted_at, username_canonical FROM users WHERE id = ANY($1::bigint[]) AND deleted_at IS NULL
+func (q *Queries) GetUsersByIDs(ctx context.Context, ids []int64) ([]*Users, error) {
+	rows, err := q.db.Query(ctx, GetUsersByIDs, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*Users{}
+	for rows.Next() {
+		var i Users
+		if err := rows.Scan(
+			&i.ID,
+			&i.UUID,
+			&i.Email,
+			&i.Username,
+			&i.PasswordHash,
+			&i.FirstName,
+			&i.LastName,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.LastLoginAt,
+			&i.IsActive,
+			&i.IsVerified,
+			&i.ProfileMetadata,
+			&i.Status,
+			&i.Role,
+			&i.Tags,
+			&i.DeletedAt,
+			&i.UsernameCanonical,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const ListUserHistory = `-- name: ListUserHistory :many
+SELECT history_id, user_id, operation, email, username, first_name, last_name, status, role, tags, is_active, is_verified, profile_metadata, valid_from, valid_to FROM users_history
+WHERE user_id = $1
+ORDER BY valid_from DESC
+LIMIT $2
+`
+
+type ListUserHistoryParams struct {
+	UserID int64 `db:"user_id" json:"userId"`
+	Limit  int32 `db:"limit" json:"limit"`
+}
+
+// ListUserHistory
+//
+//	SELECT history_id, user_id, operation, email, username, first_name, last_name, status, role, tags, is_active, is_verified, profile_metadata, valid_from, valid_to FROM users_history
+//	WHERE user_id = $1
+//	ORDER BY valid_from DESC
+//	LIMIT $2
+func (q *Queries) ListUserHistory(ctx context.Context, arg *ListUserHistoryParams) ([]*UsersHistory, error) {
+	rows, err := q.db.Query(ctx, ListUserHistory, arg.UserID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*UsersHistory{}
+	for rows.Next() {
+		var i UsersHistory
+		if err := rows.Scan(
+			&i.HistoryID,
+			&i.UserID,
+			&i.Operation,
+			&i.Email,
+			&i.Username,
+			&i.FirstName,
+			&i.LastName,
+			&i.Status,
+			&i.Role,
+			&i.Tags,
+			&i.IsActive,
+			&i.IsVerified,
+			&i.ProfileMetadata,
+			&i.ValidFrom,
+			&i.ValidTo,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const ListUsers = `-- name: ListUsers :many
-SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata FROM users 
-WHERE is_active = TRUE 
+SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, deleted_at, username_canonical FROM users 
+WHERE deleted_at IS NULL 
 ORDER BY created_at DESC
 LIMIT $1 OFFSET $2
 `
@@ -249,8 +805,8 @@ type ListUsersParams struct {
 
 // ListUsers
 //
-//	SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata FROM users
-//	WHERE is_active = TRUE
+//	SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, deleted_at, username_canonical FROM users
+//	WHERE deleted_at IS NULL
 //	ORDER BY created_at DESC
 //	LIMIT $1 OFFSET $2
 func (q *Queries) ListUsers(ctx context.Context, arg *ListUsersParams) ([]*Users, error) {
@@ -276,6 +832,11 @@ func (q *Queries) ListUsers(ctx context.Context, arg *ListUsersParams) ([]*Users
 			&i.IsActive,
 			&i.IsVerified,
 			&i.ProfileMetadata,
+			&i.Status,
+			&i.Role,
+			&i.Tags,
+			&i.DeletedAt,
+			&i.UsernameCanonical,
 		); err != nil {
 			return nil, err
 		}
@@ -287,22 +848,254 @@ func (q *Queries) ListUsers(ctx context.Context, arg *ListUsersParams) ([]*Users
 	return items, nil
 }
 
-const SoftDeleteUser = `-- name: SoftDeleteUser :exec
-UPDATE users 
-SET is_active = FALSE, updated_at = CURRENT_TIMESTAMP
+const MarkUserVerified = `-- name: MarkUserVerified :exec
+UPDATE users
+SET is_verified = TRUE, updated_at = CURRENT_TIMESTAMP
 WHERE id = $1
 `
 
-// SoftDeleteUser
+// MarkUserVerified
 //
 //	UPDATE users
-//	SET is_active = FALSE, updated_at = CURRENT_TIMESTAMP
+//	SET is_verified = TRUE, updated_at = CURRENT_TIMESTAMP
 //	WHERE id = $1
+func (q *Queries) MarkUserVerified(ctx context.Context, id int64) error {
+	_, err := q.db.Exec(ctx, MarkUserVerified, id)
+	return err
+}
+
+const PurgeDeletedUsers = `-- name: PurgeDeletedUsers :execrows
+DELETE FROM users
+WHERE deleted_at IS NOT NULL AND deleted_at < $1::timestamptz
+`
+
+// Permanently removes users soft deleted before the cutoff.
+//
+//	DELETE FROM users
+//	WHERE deleted_at IS NOT NULL AND deleted_at < $1::timestamptz
+func (q *Queries) PurgeDeletedUsers(ctx context.Context, cutoff time.Time) (int64, error) {
+	result, err := q.db.Exec(ctx, PurgeDeletedUsers, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const RestoreUser = `-- name: RestoreUser :execrows
+UPDATE users
+SET is_active = TRUE, deleted_at = NULL, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND deleted_at IS NOT NULL
+`
+
+// RestoreUser
+//
+//	UPDATE users
+//	SET is_active = TRUE, deleted_at = NULL, updated_at = CURRENT_TIMESTAMP
+//	WHERE id = $1 AND deleted_at IS NOT NULL
+func (q *Queries) RestoreUser(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.Exec(ctx, RestoreUser, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const SearchUsersWithFacets = `-- name: SearchUsersWithFacets :many
+WITH matched AS (
+    SELECT id, uuid, email, username, first_name, last_name, status, role, tags, created_at
+    FROM users
+    WHERE deleted_at IS NULL
+      AND (
+          email ILIKE '%' || $1::text || '%'
+          OR username ILIKE '%' || $1::text || '%'
+          OR first_name ILIKE '%' || $1::text || '%'
+          OR last_name ILIKE '%' || $1::text || '%'
+      )
+),
+status_facets AS (
+    SELECT COALESCE(jsonb_object_agg(status, total), '{}'::jsonb) AS counts
+    FROM (SELECT status, COUNT(*) AS total FROM matched GROUP BY status) AS s
+),
+role_facets AS (
+    SELECT COALESCE(jsonb_object_agg(role, total), '{}'::jsonb) AS counts
+    FROM (SELECT role, COUNT(*) AS total FROM matched GROUP BY role) AS r
+),
+tag_facets AS (
+    SELECT COALESCE(jsonb_agg(jsonb_build_array(tag, total) ORDER BY total DESC, tag), '[]'::jsonb) AS counts
+    FROM (
+        SELECT tag, COUNT(*) AS total
+        FROM matched, unnest(matched.tags) AS tag
+        GROUP BY tag
+        ORDER BY total DESC, tag
+        LIMIT $2::int
+    ) AS t
+),
+page AS (
+    SELECT id, uuid, email, username, first_name, last_name, status, role, tags, created_at
+    FROM matched
+    WHERE $3::text = '' OR status = $3::text
+    ORDER BY created_at DESC
+    LIMIT $4::int
+)
+SELECT
+    page.id, page.uuid, page.email, page.username, page.first_name, page.last_name,
+    page.status, page.role, page.tags, page.created_at,
+    status_facets.counts::jsonb AS status_facets,
+    role_facets.counts::jsonb AS role_facets,
+    tag_facets.counts::jsonb AS tag_facets
+FROM status_facets
+CROSS JOIN role_facets
+CROSS JOIN tag_facets
+LEFT JOIN page ON TRUE
+`
+
+type SearchUsersWithFacetsParams struct {
+	Query       string `db:"query" json:"query"`
+	TagLimit    int32  `db:"tag_limit" json:"tagLimit"`
+	Status      string `db:"status" json:"status"`
+	ResultLimit int32  `db:"result_limit" json:"resultLimit"`
+}
+
+type SearchUsersWithFacetsRow struct {
+	ID           *int64             `db:"id" json:"id"`
+	UUID         pgtype.UUID        `db:"uuid" json:"uuid"`
+	Email        *string            `db:"email" json:"email"`
+	Username     *string            `db:"username" json:"username"`
+	FirstName    *string            `db:"first_name" json:"firstName"`
+	LastName     *string            `db:"last_name" json:"lastName"`
+	Status       *string            `db:"status" json:"status"`
+	Role         *string            `db:"role" json:"role"`
+	Tags         []string           `db:"tags" json:"tags"`
+	CreatedAt    pgtype.Timestamptz `db:"created_at" json:"createdAt"`
+	StatusFacets json.RawMessage    `db:"status_facets" json:"statusFacets"`
+	RoleFacets   json.RawMessage    `db:"role_facets" json:"roleFacets"`
+	TagFacets    json.RawMessage    `db:"tag_facets" json:"tagFacets"`
+}
+
+// Returns one page of matching users together with facet counts computed
+// over every match. Facets are JSONB columns repeated on each row; a single
+// row with NULL user columns is returned when the page is empty.
+//
+//	WITH matched AS (
+//	    SELECT id, uuid, email, username, first_name, last_name, status, role, tags, created_at
+//	    FROM users
+//	    WHERE deleted_at IS NULL
+//	      AND (
+//	          email ILIKE '%' || $1::text || '%'
+//	          OR username ILIKE '%' || $1::text || '%'
+//	          OR first_name ILIKE '%' || $1::text || '%'
+//	          OR last_name ILIKE '%' || $1::text || '%'
+//	      )
+//	),
+//	status_facets AS (
+//	    SELECT COALESCE(jsonb_object_agg(status, total), '{}'::jsonb) AS counts
+//	    FROM (SELECT status, COUNT(*) AS total FROM matched GROUP BY status) AS s
+//	),
+//	role_facets AS (
+//	    SELECT COALESCE(jsonb_object_agg(role, total), '{}'::jsonb) AS counts
+//	    FROM (SELECT role, COUNT(*) AS total FROM matched GROUP BY role) AS r
+//	),
+//	tag_facets AS (
+//	    SELECT COALESCE(jsonb_agg(jsonb_build_array(tag, total) ORDER BY total DESC, tag), '[]'::jsonb) AS counts
+//	    FROM (
+//	        SELECT tag, COUNT(*) AS total
+//	        FROM matched, unnest(matched.tags) AS tag
+//	        GROUP BY tag
+//	        ORDER BY total DESC, tag
+//	        LIMIT $2::int
+//	    ) AS t
+//	),
+//	page AS (
+//	    SELECT id, uuid, email, username, first_name, last_name, status, role, tags, created_at
+//	    FROM matched
+//	    WHERE $3::text = '' OR status = $3::text
+//	    ORDER BY created_at DESC
+//	    LIMIT $4::int
+//	)
+//	SELECT
+//	    page.id, page.uuid, page.email, page.username, page.first_name, page.last_name,
+//	    page.status, page.role, page.tags, page.created_at,
+//	    status_facets.counts::jsonb AS status_facets,
+//	    role_facets.counts::jsonb AS role_facets,
+//	    tag_facets.counts::jsonb AS tag_facets
+//	FROM status_facets
+//	CROSS JOIN role_facets
+//	CROSS JOIN tag_facets
+//	LEFT JOIN page ON TRUE
+func (q *Queries) SearchUsersWithFacets(ctx context.Context, arg *SearchUsersWithFacetsParams) ([]*SearchUsersWithFacetsRow, error) {
+	rows, err := q.db.Query(ctx, SearchUsersWithFacets,
+		arg.Query,
+		arg.TagLimit,
+		arg.Status,
+		arg.ResultLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*SearchUsersWithFacetsRow{}
+	for rows.Next() {
+		var i SearchUsersWithFacetsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UUID,
+			&i.Email,
+			&i.Username,
+			&i.FirstName,
+			&i.LastName,
+			&i.Status,
+			&i.Role,
+			&i.Tags,
+			&i.CreatedAt,
+			&i.StatusFacets,
+			&i.RoleFacets,
+			&i.TagFacets,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const SoftDeleteUser = `-- name: SoftDeleteUser :exec
+UPDATE users
+SET is_active = FALSE, deleted_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND deleted_at IS NULL
+`
+
+// SoftDeleteUser
+//
+//	UPDATE users
+//	SET is_active = FALSE, deleted_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+//	WHERE id = $1 AND deleted_at IS NULL
 func (q *Queries) SoftDeleteUser(ctx context.Context, id int64) error {
 	_, err := q.db.Exec(ctx, SoftDeleteUser, id)
 	return err
 }
 
+const SoftDeleteUsers = `-- name: SoftDeleteUsers :execrows
+UPDATE users
+SET is_active = FALSE, deleted_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+WHERE id = ANY($1::bigint[]) AND deleted_at IS NULL
+`
+
+// SoftDeleteUsers
+//
+//	UPDATE users
+//	SET is_active = FALSE, deleted_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+//	WHERE id = ANY($1::bigint[]) AND deleted_at IS NULL
+func (q *Queries) SoftDeleteUsers(ctx context.Context, ids []int64) (int64, error) {
+	result, err := q.db.Exec(ctx, SoftDeleteUsers, ids)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const UpdateLastLogin = `-- name: UpdateLastLogin :exec
 UPDATE users 
 SET last_login_at = CURRENT_TIMESTAMP
@@ -349,20 +1142,31 @@ SET
     last_name = COALESCE($5, last_name),
     profile_metadata = COALESCE($6, profile_metadata),
     is_active = COALESCE($7, is_active),
-    is_verified = COALESCE($8, is_verified)
-WHERE id = $1
-RETURNING id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata
+    is_verified = COALESCE($8, is_verified),
+    status = COALESCE($9, status),
+    role = COALESCE($10, role),
+    tags = COALESCE($11, tags),
+    last_login_at = COALESCE($12, last_login_at),
+    username_canonical = $13,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, deleted_at, username_canonical
 `
 
 type UpdateUserParams struct {
-	ID              int64  `db:"id" json:"id"`
-	Email           string `db:"email" json:"email"`
-	Username        string `db:"username" json:"username"`
-	FirstName       string `db:"first_name" json:"firstName"`
-	LastName        string `db:"last_name" json:"lastName"`
-	ProfileMetadata []byte `db:"profile_metadata" json:"profileMetadata"`
-	IsActive        *bool  `db:"is_active" json:"isActive"`
-	IsVerified      *bool  `db:"is_verified" json:"isVerified"`
+	ID                int64              `db:"id" json:"id"`
+	Email             string             `db:"email" json:"email"`
+	Username          string             `db:"username" json:"username"`
+	FirstName         string             `db:"first_name" json:"firstName"`
+	LastName          string             `db:"last_name" json:"lastName"`
+	ProfileMetadata   []byte             `db:"profile_metadata" json:"profileMetadata"`
+	IsActive          *bool              `db:"is_active" json:"isActive"`
+	IsVerified        *bool              `db:"is_verified" json:"isVerified"`
+	Status            *string            `db:"status" json:"status"`
+	Role              *string            `db:"role" json:"role"`
+	Tags              []string           `db:"tags" json:"tags"`
+	LastLoginAt       pgtype.Timestamptz `db:"last_login_at" json:"lastLoginAt"`
+	UsernameCanonical string             `db:"username_canonical" json:"usernameCanonical"`
 }
 
 // UpdateUser
@@ -375,9 +1179,15 @@ type UpdateUserParams struct {
 //	    last_name = COALESCE($5, last_name),
 //	    profile_metadata = COALESCE($6, profile_metadata),
 //	    is_active = COALESCE($7, is_active),
-//	    is_verified = COALESCE($8, is_verified)
-//	WHERE id = $1
-//	RETURNING id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata
+//	    is_verified = COALESCE($8, is_verified),
+//	    status = COALESCE($9, status),
+//	    role = COALESCE($10, role),
+//	    tags = COALESCE($11, tags),
+//	    last_login_at = COALESCE($12, last_login_at),
+//	    username_canonical = $13,
+//	    updated_at = CURRENT_TIMESTAMP
+//	WHERE id = $1 AND deleted_at IS NULL
+//	RETURNING id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, deleted_at, username_canonical
 func (q *Queries) UpdateUser(ctx context.Context, arg *UpdateUserParams) (*Users, error) {
 	row := q.db.QueryRow(ctx, UpdateUser,
 		arg.ID,
@@ -388,6 +1198,11 @@ func (q *Queries) UpdateUser(ctx context.Context, arg *UpdateUserParams) (*Users
 		arg.ProfileMetadata,
 		arg.IsActive,
 		arg.IsVerified,
+		arg.Status,
+		arg.Role,
+		arg.Tags,
+		arg.LastLoginAt,
+		arg.UsernameCanonical,
 	)
 	var i Users
 	err := row.Scan(
@@ -404,10 +1219,81 @@ func (q *Queries) UpdateUser(ctx context.Context, arg *UpdateUserParams) (*Users
 		&i.IsActive,
 		&i.IsVerified,
 		&i.ProfileMetadata,
+		&i.Status,
+		&i.Role,
+		&i.Tags,
+		&i.DeletedAt,
+		&i.UsernameCanonical,
 	)
 	return &i, err
 }
 
+const UpdateUserRole = `-- name: UpdateUserRole :exec
+UPDATE users
+SET role = $2, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+`
+
+type UpdateUserRoleParams struct {
+	ID   int64  `db:"id" json:"id"`
+	Role string `db:"role" json:"role"`
+}
+
+// UpdateUserRole
+//
+//	UPDATE users
+//	SET role = $2, updated_at = CURRENT_TIMESTAMP
+//	WHERE id = $1
+func (q *Queries) UpdateUserRole(ctx context.Context, arg *UpdateUserRoleParams) error {
+	_, err := q.db.Exec(ctx, UpdateUserRole, arg.ID, arg.Role)
+	return err
+}
+
+const UpdateUserStatus = `-- name: UpdateUserStatus :exec
+UPDATE users
+SET status = $2, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+`
+
+type UpdateUserStatusParams struct {
+	ID     int64  `db:"id" json:"id"`
+	Status string `db:"status" json:"status"`
+}
+
+// UpdateUserStatus
+//
+//	UPDATE users
+//	SET status = $2, updated_at = CURRENT_TIMESTAMP
+//	WHERE id = $1
+func (q *Queries) UpdateUserStatus(ctx context.Context, arg *UpdateUserStatusParams) error {
+	_, err := q.db.Exec(ctx, UpdateUserStatus, arg.ID, arg.Status)
+	return err
+}
+
+const UpdateUsersStatus = `-- name: UpdateUsersStatus :execrows
+UPDATE users
+SET status = $1, updated_at = CURRENT_TIMESTAMP
+WHERE id = ANY($2::bigint[]) AND deleted_at IS NULL
+`
+
+type UpdateUsersStatusParams struct {
+	Status string  `db:"status" json:"status"`
+	Ids    []int64 `db:"ids" json:"ids"`
+}
+
+// UpdateUsersStatus
+//
+//	UPDATE users
+//	SET status = $1, updated_at = CURRENT_TIMESTAMP
+//	WHERE id = ANY($2::bigint[]) AND deleted_at IS NULL
+func (q *Queries) UpdateUsersStatus(ctx context.Context, arg *UpdateUsersStatusParams) (int64, error) {
+	result, err := q.db.Exec(ctx, UpdateUsersStatus, arg.Status, arg.Ids)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const VerifyUser = `-- name: VerifyUser :exec
 UPDATE users 
 SET is_verified = TRUE, updated_at = CURRENT_TIMESTAMP
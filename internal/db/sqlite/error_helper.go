@@ -61,3 +61,33 @@ func IsSQLiteSessionTokenConstraintError(err error) bool {
 		"session token already exists",
 	)
 }
+
+// Primary result codes of SQLite lock conflicts.
+const (
+	sqliteBusy   = 5
+	sqliteLocked = 6
+	// primaryResultCodeMask strips the extended result code.
+	primaryResultCodeMask = 0xff
+)
+
+// IsSQLiteTransientError checks if error is a SQLite failure that may succeed
+// when retried, because another connection held a lock on the database
+// (SQLITE_BUSY) or on a table (SQLITE_LOCKED). Errors wrapped by the
+// repositories are unwrapped.
+func IsSQLiteTransientError(err error) bool {
+	var coded interface{ Code() int }
+	if stderrors.As(err, &coded) {
+		switch coded.Code() & primaryResultCodeMask {
+		case sqliteBusy, sqliteLocked:
+			return true
+		}
+	}
+
+	for ; err != nil; err = stderrors.Unwrap(err) {
+		if isNilOrErrorMsgContains(err, "database is locked", "database table is locked") {
+			return true
+		}
+	}
+
+	return false
+}
@@ -10,15 +10,17 @@ package sqlite
 import (
 	"context"
 	"database/sql"
+	"strings"
+	"time"
 )
 
 const CountActiveUsers = `-- name: CountActiveUsers :one
-SELECT COUNT(*) FROM users WHERE is_active = TRUE
+SELECT COUNT(*) FROM users WHERE deleted_at IS NULL
 `
 
 // CountActiveUsers
 //
-//	SELECT COUNT(*) FROM users WHERE is_active = TRUE
+//	SELECT COUNT(*) FROM users WHERE deleted_at IS NULL
 func (q *Queries) CountActiveUsers(ctx context.Context) (int64, error) {
 	row := q.db.QueryRowContext(ctx, CountActiveUsers)
 	var count int64
@@ -26,36 +28,88 @@ func (q *Queries) CountActiveUsers(ctx context.Context) (int64, error) {
 	return count, err
 }
 
+const CountUsersByStatus = `-- name: CountUsersByStatus :many
+SELECT status, COUNT(*) AS total
+FROM users
+WHERE deleted_at IS NULL
+GROUP BY status
+`
+
+type CountUsersByStatusRow struct {
+	Status string `db:"status" json:"status"`
+	Total  int64  `db:"total" json:"total"`
+}
+
+// CountUsersByStatus
+//
+//	SELECT status, COUNT(*) AS total
+//	FROM users
+//	WHERE deleted_at IS NULL
+//	GROUP BY status
+func (q *Queries) CountUsersByStatus(ctx context.Context) ([]*CountUsersByStatusRow, error) {
+	rows, err := q.db.QueryContext(ctx, CountUsersByStatus)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*CountUsersByStatusRow{}
+	for rows.Next() {
+		var i CountUsersByStatusRow
+		if err := rows.Scan(&i.Status, &i.Total); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const CreateUser = `-- name: CreateUser :one
 INSERT INTO users (
-    uuid, email, username, password_hash, 
-    first_name, last_name, profile_metadata, is_active
+    uuid, email, username, password_hash,
+    first_name, last_name, profile_metadata, is_active,
+    is_verified, status, role, tags, created_at, updated_at,
+    username_canonical
 ) VALUES (
-    ?, ?, ?, ?, ?, ?, ?, ?
+    ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
 )
-RETURNING id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata
+RETURNING id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, deleted_at, username_canonical
 `
 
 type CreateUserParams struct {
-	UUID            string       `db:"uuid" json:"uuid"`
-	Email           string       `db:"email" json:"email"`
-	Username        string       `db:"username" json:"username"`
-	PasswordHash    string       `db:"password_hash" json:"passwordHash"`
-	FirstName       string       `db:"first_name" json:"firstName"`
-	LastName        string       `db:"last_name" json:"lastName"`
-	ProfileMetadata interface{}  `db:"profile_metadata" json:"profileMetadata"`
-	IsActive        sql.NullBool `db:"is_active" json:"isActive"`
+	UUID              string       `db:"uuid" json:"uuid"`
+	Email             string       `db:"email" json:"email"`
+	Username          string       `db:"username" json:"username"`
+	PasswordHash      string       `db:"password_hash" json:"passwordHash"`
+	FirstName         string       `db:"first_name" json:"firstName"`
+	LastName          string       `db:"last_name" json:"lastName"`
+	ProfileMetadata   interface{}  `db:"profile_metadata" json:"profileMetadata"`
+	IsActive          sql.NullBool `db:"is_active" json:"isActive"`
+	IsVerified        sql.NullBool `db:"is_verified" json:"isVerified"`
+	Status            string       `db:"status" json:"status"`
+	Role              string       `db:"role" json:"role"`
+	Tags              string       `db:"tags" json:"tags"`
+	CreatedAt         time.Time    `db:"created_at" json:"createdAt"`
+	UpdatedAt         time.Time    `db:"updated_at" json:"updatedAt"`
+	UsernameCanonical string       `db:"username_canonical" json:"usernameCanonical"`
 }
 
 // CreateUser
 //
 //	INSERT INTO users (
 //	    uuid, email, username, password_hash,
-//	    first_name, last_name, profile_metadata, is_active
+//	    first_name, last_name, profile_metadata, is_active,
+//	    is_verified, status, role, tags, created_at, updated_at,
+//	    username_canonical
 //	) VALUES (
-//	    ?, ?, ?, ?, ?, ?, ?, ?
+//	    ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
 //	)
-//	RETURNING id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata
+//	RETURNING id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, deleted_at, username_canonical
 func (q *Queries) CreateUser(ctx context.Context, arg *CreateUserParams) (*Users, error) {
 	row := q.db.QueryRowContext(ctx, CreateUser,
 		arg.UUID,
@@ -66,6 +120,13 @@ func (q *Queries) CreateUser(ctx context.Context, arg *CreateUserParams) (*Users
 		arg.LastName,
 		arg.ProfileMetadata,
 		arg.IsActive,
+		arg.IsVerified,
+		arg.Status,
+		arg.Role,
+		arg.Tags,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.UsernameCanonical,
 	)
 	var i Users
 	err := row.Scan(
@@ -82,17 +143,254 @@ func (q *Queries) CreateUser(ctx context.Context, arg *CreateUserParams) (*Users
 		&i.IsActive,
 		&i.IsVerified,
 		&i.ProfileMetadata,
+		&i.Status,
+		&i.Role,
+		&i.Tags,
+		&i.DeletedAt,
+		&i.UsernameCanonical,
+	)
+	return &i, err
+}
+
+const DeleteUserHistory = `-- name: DeleteUserHistory :execrows
+DELETE FROM users_history
+WHERE user_id = ?1
+`
+
+// Removes every archived version of a user.
+//
+//	DELETE FROM users_history
+//	WHERE user_id = ?1
+func (q *Queries) DeleteUserHistory(ctx context.Context, userID int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, DeleteUserHistory, userID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const FilterUsers = `-- name: FilterUsers :many
+SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, deleted_at, username_canonical FROM users
+WHERE deleted_at IS NULL
+  AND (json_array_length(CAST(?1 AS TEXT)) = 0
+       OR status IN (SELECT value FROM json_each(CAST(?1 AS TEXT))))
+  AND (json_array_length(CAST(?2 AS TEXT)) = 0
+       OR role IN (SELECT value FROM json_each(CAST(?2 AS TEXT))))
+  AND (CAST(?3 AS INTEGER) < 0 OR is_verified = CAST(?3 AS INTEGER))
+  AND created_at > ?4
+  AND created_at < ?5
+  AND (json_array_length(CAST(?6 AS TEXT)) = 0 OR EXISTS (
+      SELECT 1 FROM json_each(users.tags) AS user_tag
+      WHERE user_tag.value IN (SELECT value FROM json_each(CAST(?6 AS TEXT)))
+  ))
+  AND NOT EXISTS (
+      SELECT 1 FROM json_each(CAST(?7 AS TEXT)) AS wanted
+      WHERE wanted.value NOT IN (SELECT value FROM json_each(users.tags))
+  )
+  AND (CAST(?8 AS TEXT) = ''
+       OR id IN (SELECT rowid FROM users_fts WHERE users_fts MATCH CAST(?8 AS TEXT)))
+  AND (created_at < ?9
+       OR (created_at = ?9 AND id < ?10))
+ORDER BY created_at DESC, id DESC
+LIMIT ?12 OFFSET ?11
+`
+
+type FilterUsersParams struct {
+	Statuses        string    `db:"statuses" json:"statuses"`
+	Roles           string    `db:"roles" json:"roles"`
+	Verified        int64     `db:"verified" json:"verified"`
+	CreatedAfter    time.Time `db:"created_after" json:"createdAfter"`
+	CreatedBefore   time.Time `db:"created_before" json:"createdBefore"`
+	TagsAny         string    `db:"tags_any" json:"tagsAny"`
+	TagsAll         string    `db:"tags_all" json:"tagsAll"`
+	Query           string    `db:"query" json:"query"`
+	CursorCreatedAt time.Time `db:"cursor_created_at" json:"cursorCreatedAt"`
+	CursorID        int64     `db:"cursor_id" json:"cursorId"`
+	RowOffset       int64     `db:"row_offset" json:"rowOffset"`
+	RowLimit        int64     `db:"row_limit" json:"rowLimit"`
+}
+
+// Lists users matching a UserFilter, newest first with the ID breaking ties.
+// Empty sets and an empty query match any user; a negative verified matches
+// both states. Open time ranges and the first page's keyset cursor are
+// replaced by bounds beyond any stored value.
+// Statuses, roles and tag sets are JSON arrays of strings.
+//
+//	SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, deleted_at, username_canonical FROM users
+//	WHERE deleted_at IS NULL
+//	  AND (json_array_length(CAST(?1 AS TEXT)) = 0
+//	       OR status IN (SELECT value FROM json_each(CAST(?1 AS TEXT))))
+//	  AND (json_array_length(CAST(?2 AS TEXT)) = 0
+//	       OR role IN (SELECT value FROM json_each(CAST(?2 AS TEXT))))
+//	  AND (CAST(?3 AS INTEGER) < 0 OR is_verified = CAST(?3 AS INTEGER))
+//	  AND created_at > ?4
+//	  AND created_at < ?5
+//	  AND (json_array_length(CAST(?6 AS TEXT)) = 0 OR EXISTS (
+//	      SELECT 1 FROM json_each(users.tags) AS user_tag
+//	      WHERE user_tag.value IN (SELECT value FROM json_each(CAST(?6 AS TEXT)))
+//	  ))
+//	  AND NOT EXISTS (
+//	      SELECT 1 FROM json_each(CAST(?7 AS TEXT)) AS wanted
+//	      WHERE wanted.value NOT IN (SELECT value FROM json_each(users.tags))
+//	  )
+//	  AND (CAST(?8 AS TEXT) = ''
+//	       OR id IN (SELECT rowid FROM users_fts WHERE users_fts MATCH CAST(?8 AS TEXT)))
+//	  AND (created_at < ?9
+//	       OR (created_at = ?9 AND id < ?10))
+//	ORDER BY created_at DESC, id DESC
+//	LIMIT ?12 OFFSET ?11
+func (q *Queries) FilterUsers(ctx context.Context, arg *FilterUsersParams) ([]*Users, error) {
+	rows, err := q.db.QueryContext(ctx, FilterUsers,
+		arg.Statuses,
+		arg.Roles,
+		arg.Verified,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.TagsAny,
+		arg.TagsAll,
+		arg.Query,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.RowOffset,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*Users{}
+	for rows.Next() {
+		var i Users
+		if err := rows.Scan(
+			&i.ID,
+			&i.UUID,
+			&i.Email,
+			&i.Username,
+			&i.PasswordHash,
+			&i.FirstName,
+			&i.LastName,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.LastLoginAt,
+			&i.IsActive,
+			&i.IsVerified,
+			&i.ProfileMetadata,
+			&i.Status,
+			&i.Role,
+			&i.Tags,
+			&i.DeletedAt,
+			&i.UsernameCanonical,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const GetUserAsOf = `-- name: GetUserAsOf :one
+SELECT user_id, operation, email, username, first_name, last_name, status, role, tags,
+       is_active, is_verified, valid_from, valid_to
+FROM (
+    SELECT u.id AS user_id, 'current' AS operation, u.email, u.username, u.first_name, u.last_name,
+           u.status, u.role, u.tags, u.is_active, u.is_verified,
+           COALESCE(u.updated_at, u.created_at) AS valid_from,
+           NULL AS valid_to, 1 AS is_current
+    FROM users u
+    WHERE u.id = ?1
+      AND COALESCE(u.updated_at, u.created_at) <= ?2
+    UNION ALL
+    SELECT h.user_id, h.operation, h.email, h.username, h.first_name, h.last_name,
+           h.status, h.role, h.tags, h.is_active, h.is_verified,
+           h.valid_from, h.valid_to, 0
+    FROM users_history h
+    WHERE h.user_id = ?1
+      AND h.valid_from <= ?2
+      AND h.valid_to > ?2
+) AS versions
+ORDER BY is_current, valid_from DESC
+LIMIT 1
+`
+
+type GetUserAsOfParams struct {
+	UserID int64     `db:"user_id" json:"userId"`
+	AsOf   time.Time `db:"as_of" json:"asOf"`
+}
+
+type GetUserAsOfRow struct {
+	UserID     int64        `db:"user_id" json:"userId"`
+	Operation  string       `db:"operation" json:"operation"`
+	Email      string       `db:"email" json:"email"`
+	Username   string       `db:"username" json:"username"`
+	FirstName  string       `db:"first_name" json:"firstName"`
+	LastName   string       `db:"last_name" json:"lastName"`
+	Status     string       `db:"status" json:"status"`
+	Role       string       `db:"role" json:"role"`
+	Tags       string       `db:"tags" json:"tags"`
+	IsActive   sql.NullBool `db:"is_active" json:"isActive"`
+	IsVerified sql.NullBool `db:"is_verified" json:"isVerified"`
+	ValidFrom  time.Time    `db:"valid_from" json:"validFrom"`
+	ValidTo    sql.NullTime `db:"valid_to" json:"validTo"`
+}
+
+// Returns the version of a user that was valid at the given time, preferring
+// archived versions over the current row.
+//
+//	SELECT user_id, operation, email, username, first_name, last_name, status, role, tags,
+//	       is_active, is_verified, valid_from, valid_to
+//	FROM (
+//	    SELECT u.id AS user_id, 'current' AS operation, u.email, u.username, u.first_name, u.last_name,
+//	           u.status, u.role, u.tags, u.is_active, u.is_verified,
+//	           COALESCE(u.updated_at, u.created_at) AS valid_from,
+//	           NULL AS valid_to, 1 AS is_current
+//	    FROM users u
+//	    WHERE u.id = ?1
+//	      AND COALESCE(u.updated_at, u.created_at) <= ?2
+//	    UNION ALL
+//	    SELECT h.user_id, h.operation, h.email, h.username, h.first_name, h.last_name,
+//	           h.status, h.role, h.tags, h.is_active, h.is_verified,
+//	           h.valid_from, h.valid_to, 0
+//	    FROM users_history h
+//	    WHERE h.user_id = ?1
+//	      AND h.valid_from <= ?2
+//	      AND h.valid_to > ?2
+//	) AS versions
+//	ORDER BY is_current, valid_from DESC
+//	LIMIT 1
+func (q *Queries) GetUserAsOf(ctx context.Context, arg *GetUserAsOfParams) (*GetUserAsOfRow, error) {
+	row := q.db.QueryRowContext(ctx, GetUserAsOf, arg.UserID, arg.AsOf)
+	var i GetUserAsOfRow
+	err := row.Scan(
+		&i.UserID,
+		&i.Operation,
+		&i.Email,
+		&i.Username,
+		&i.FirstName,
+		&i.LastName,
+		&i.Status,
+		&i.Role,
+		&i.Tags,
+		&i.IsActive,
+		&i.IsVerified,
+		&i.ValidFrom,
+		&i.ValidTo,
 	)
 	return &i, err
 }
 
 const GetUserByEmail = `-- name: GetUserByEmail :one
-SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata FROM users WHERE email = ? AND is_active = TRUE
+SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, deleted_at, username_canonical FROM users WHERE email = ? AND deleted_at IS NULL
 `
 
 // GetUserByEmail
 //
-//	SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata FROM users WHERE email = ? AND is_active = TRUE
+//	SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, deleted_at, username_canonical FROM users WHERE email = ? AND deleted_at IS NULL
 func (q *Queries) GetUserByEmail(ctx context.Context, email string) (*Users, error) {
 	row := q.db.QueryRowContext(ctx, GetUserByEmail, email)
 	var i Users
@@ -110,17 +408,22 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (*Users, err
 		&i.IsActive,
 		&i.IsVerified,
 		&i.ProfileMetadata,
+		&i.Status,
+		&i.Role,
+		&i.Tags,
+		&i.DeletedAt,
+		&i.UsernameCanonical,
 	)
 	return &i, err
 }
 
 const GetUserByID = `-- name: GetUserByID :one
-SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata FROM users WHERE id = ? AND is_active = TRUE
+SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, deleted_at, username_canonical FROM users WHERE id = ? AND deleted_at IS NULL
 `
 
 // GetUserByID
 //
-//	SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata FROM users WHERE id = ? AND is_active = TRUE
+//	SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, deleted_at, username_canonical FROM users WHERE id = ? AND deleted_at IS NULL
 func (q *Queries) GetUserByID(ctx context.Context, id int64) (*Users, error) {
 	row := q.db.QueryRowContext(ctx, GetUserByID, id)
 	var i Users
@@ -138,17 +441,22 @@ func (q *Queries) GetUserByID(ctx context.Context, id int64) (*Users, error) {
 		&i.IsActive,
 		&i.IsVerified,
 		&i.ProfileMetadata,
+		&i.Status,
+		&i.Role,
+		&i.Tags,
+		&i.DeletedAt,
+		&i.UsernameCanonical,
 	)
 	return &i, err
 }
 
 const GetUserByUUID = `-- name: GetUserByUUID :one
-SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata FROM users WHERE uuid = ? AND is_active = TRUE
+SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, deleted_at, username_canonical FROM users WHERE uuid = ? AND deleted_at IS NULL
 `
 
 // GetUserByUUID
 //
-//	SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata FROM users WHERE uuid = ? AND is_active = TRUE
+//	SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, deleted_at, username_canonical FROM users WHERE uuid = ? AND deleted_at IS NULL
 func (q *Queries) GetUserByUUID(ctx context.Context, uuid string) (*Users, error) {
 	row := q.db.QueryRowContext(ctx, GetUserByUUID, uuid)
 	var i Users
@@ -166,19 +474,24 @@ func (q *Queries) GetUserByUUID(ctx context.Context, uuid string) (*Users, error
 		&i.IsActive,
 		&i.IsVerified,
 		&i.ProfileMetadata,
+		&i.Status,
+		&i.Role,
+		&i.Tags,
+		&i.DeletedAt,
+		&i.UsernameCanonical,
 	)
 	return &i, err
 }
 
 const GetUserByUsername = `-- name: GetUserByUsername :one
-SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata FROM users WHERE username = ? AND is_active = TRUE
+SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, deleted_at, username_canonical FROM users WHERE username_canonical = ? AND deleted_at IS NULL
 `
 
 // GetUserByUsername
 //
-//	SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata FROM users WHERE username = ? AND is_active = TRUE
-func (q *Queries) GetUserByUsername(ctx context.Context, username string) (*Users, error) {
-	row := q.db.QueryRowContext(ctx, GetUserByUsername, username)
+//	SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, deleted_at, username_canonical FROM users WHERE username_canonical = ? AND deleted_at IS NULL
+func (q *Queries) GetUserByUsername(ctx context.Context, usernameCanonical string) (*Users, error) {
+	row := q.db.QueryRowContext(ctx, GetUserByUsername, usernameCanonical)
 	var i Users
 	err := row.Scan(
 		&i.ID,
@@ -194,6 +507,11 @@ func (q *Queries) GetUserByUsername(ctx context.Context, username string) (*User
 		&i.IsActive,
 		&i.IsVerified,
 		&i.ProfileMetadata,
+		&i.Status,
+		&i.Role,
+		&i.Tags,
+		&i.DeletedAt,
+		&i.UsernameCanonical,
 	)
 	return &i, err
 }
@@ -201,9 +519,13 @@ func (q *Queries) GetUserByUsername(ctx context.Context, username string) (*User
 const GetUserStats = `-- name: GetUserStats :one
 SELECT 
     COUNT(*) as total_users,
-    COUNT(CASE WHEN is_active = TRUE THEN 1 END) as active_users,
+    COUNT(CASE WHEN deleted_at IS NULL THEN 1 END) as active_users,
     COUNT(CASE WHEN is_verified = TRUE THEN 1 END) as verified_users,
-    COUNT(CASE WHEN last_login_at IS NOT NULL THEN 1 END) as users_with_logins
+    COUNT(CASE WHEN last_login_at IS NOT NULL THEN 1 END) as users_with_logins,
+    COUNT(CASE WHEN status = 'inactive' THEN 1 END) as inactive_users,
+    COUNT(CASE WHEN status = 'suspended' THEN 1 END) as suspended_users,
+    COUNT(CASE WHEN created_at >= datetime('now', '-30 days') THEN 1 END) as new_users_30d,
+    COUNT(CASE WHEN created_at >= datetime('now', '-7 days') THEN 1 END) as new_users_7d
 FROM users
 `
 
@@ -212,15 +534,23 @@ type GetUserStatsRow struct {
 	ActiveUsers     int64 `db:"active_users" json:"activeUsers"`
 	VerifiedUsers   int64 `db:"verified_users" json:"verifiedUsers"`
 	UsersWithLogins int64 `db:"users_with_logins" json:"usersWithLogins"`
+	InactiveUsers   int64 `db:"inactive_users" json:"inactiveUsers"`
+	SuspendedUsers  int64 `db:"suspended_users" json:"suspendedUsers"`
+	NewUsers30d     int64 `db:"new_users_30d" json:"newUsers30d"`
+	NewUsers7d      int64 `db:"new_users_7d" json:"newUsers7d"`
 }
 
 // GetUserStats
 //
 //	SELECT
 //	    COUNT(*) as total_users,
-//	    COUNT(CASE WHEN is_active = TRUE THEN 1 END) as active_users,
+//	    COUNT(CASE WHEN deleted_at IS NULL THEN 1 END) as active_users,
 //	    COUNT(CASE WHEN is_verified = TRUE THEN 1 END) as verified_users,
-//	    COUNT(CASE WHEN last_login_at IS NOT NULL THEN 1 END) as users_with_logins
+//	    COUNT(CASE WHEN last_login_at IS NOT NULL THEN 1 END) as users_with_logins,
+//	    COUNT(CASE WHEN status = 'inactive' THEN 1 END) as inactive_users,
+//	    COUNT(CASE WHEN status = 'suspended' THEN 1 END) as suspended_users,
+//	    COUNT(CASE WHEN created_at >= datetime('now', '-30 days') THEN 1 END) as new_users_30d,
+//	    COUNT(CASE WHEN created_at >= datetime('now', '-7 days') THEN 1 END) as new_users_7d
 //	FROM users
 func (q *Queries) GetUserStats(ctx context.Context) (*GetUserStatsRow, error) {
 	row := q.db.QueryRowContext(ctx, GetUserStats)
@@ -230,13 +560,133 @@ func (q *Queries) GetUserStats(ctx context.Context) (*GetUserStatsRow, error) {
 		&i.ActiveUsers,
 		&i.VerifiedUsers,
 		&i.UsersWithLogins,
+		&i.InactiveUsers,
+		&i.SuspendedUsers,
+		&i.NewUsers30d,
+		&i.NewUsers7d,
 	)
 	return &i, err
 }
 
+const GetUsersByIDs = `-- name: GetUsersByIDs :many
+SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, deleted_at, username_canonical FROM users WHERE id IN (/*SLICE:ids*/?) AND deleted_at IS NULL
+`
+
+// GetUsersByIDs
+//
+//	SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, deleted_at, username_canonical FROM users WHERE id IN (/*SLICE:ids*/?) AND deleted_at IS NULL
+func (q *Queries) GetUsersByIDs(ctx context.Context, ids []int64) ([]*Users, error) {
+	query := GetUsersByIDs
+	var queryParams []interface{}
+	if len(ids) > 0 {
+		for _, v := range ids {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:ids*/?", strings.Repeat(",?", len(ids))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:ids*/?", "NULL", 1)
+	}
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*Users{}
+	for rows.Next() {
+		var i Users
+		if err := rows.Scan(
+			&i.ID,
+			&i.UUID,
+			&i.Email,
+			&i.Username,
+			&i.PasswordHash,
+			&i.FirstName,
+			&i.LastName,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.LastLoginAt,
+			&i.IsActive,
+			&i.IsVerified,
+			&i.ProfileMetadata,
+			&i.Status,
+			&i.Role,
+			&i.Tags,
+			&i.DeletedAt,
+			&i.UsernameCanonical,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const ListUserHistory = `-- name: ListUserHistory :many
+SELECT history_id, user_id, operation, email, username, first_name, last_name, status, role, tags, is_active, is_verified, profile_metadata, valid_from, valid_to FROM users_history
+WHERE user_id = ?
+ORDER BY valid_from DESC
+LIMIT ?
+`
+
+type ListUserHistoryParams struct {
+	UserID int64 `db:"user_id" json:"userId"`
+	Limit  int64 `db:"limit" json:"limit"`
+}
+
+// ListUserHistory
+//
+//	SELECT history_id, user_id, operation, email, username, first_name, last_name, status, role, tags, is_active, is_verified, profile_metadata, valid_from, valid_to FROM users_history
+//	WHERE user_id = ?
+//	ORDER BY valid_from DESC
+//	LIMIT ?
+func (q *Queries) ListUserHistory(ctx context.Context, arg *ListUserHistoryParams) ([]*UsersHistory, error) {
+	rows, err := q.db.QueryContext(ctx, ListUserHistory, arg.UserID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*UsersHistory{}
+	for rows.Next() {
+		var i UsersHistory
+		if err := rows.Scan(
+			&i.HistoryID,
+			&i.UserID,
+			&i.Operation,
+			&i.Email,
+			&i.Username,
+			&i.FirstName,
+			&i.LastName,
+			&i.Status,
+			&i.Role,
+			&i.Tags,
+			&i.IsActive,
+			&i.IsVerified,
+			&i.ProfileMetadata,
+			&i.ValidFrom,
+			&i.ValidTo,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const ListUsers = `-- name: ListUsers :many
-SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata FROM users 
-WHERE is_active = TRUE 
+SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, deleted_at, username_canonical FROM users 
+WHERE deleted_at IS NULL 
 ORDER BY created_at DESC
 LIMIT ? OFFSET ?
 `
@@ -248,8 +698,8 @@ type ListUsersParams struct {
 
 // ListUsers
 //
-//	SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata FROM users
-//	WHERE is_active = TRUE
+//	SELECT id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, deleted_at, username_canonical FROM users
+//	WHERE deleted_at IS NULL
 //	ORDER BY created_at DESC
 //	LIMIT ? OFFSET ?
 func (q *Queries) ListUsers(ctx context.Context, arg *ListUsersParams) ([]*Users, error) {
@@ -275,6 +725,11 @@ func (q *Queries) ListUsers(ctx context.Context, arg *ListUsersParams) ([]*Users
 			&i.IsActive,
 			&i.IsVerified,
 			&i.ProfileMetadata,
+			&i.Status,
+			&i.Role,
+			&i.Tags,
+			&i.DeletedAt,
+			&i.UsernameCanonical,
 		); err != nil {
 			return nil, err
 		}
@@ -289,22 +744,267 @@ func (q *Queries) ListUsers(ctx context.Context, arg *ListUsersParams) ([]*Users
 	return items, nil
 }
 
-const SoftDeleteUser = `-- name: SoftDeleteUser :exec
-UPDATE users 
-SET is_active = FALSE, updated_at = CURRENT_TIMESTAMP
+const MarkUserVerified = `-- name: MarkUserVerified :exec
+UPDATE users
+SET is_verified = TRUE, updated_at = CURRENT_TIMESTAMP
 WHERE id = ?
 `
 
-// SoftDeleteUser
+// MarkUserVerified
 //
 //	UPDATE users
-//	SET is_active = FALSE, updated_at = CURRENT_TIMESTAMP
+//	SET is_verified = TRUE, updated_at = CURRENT_TIMESTAMP
 //	WHERE id = ?
+func (q *Queries) MarkUserVerified(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, MarkUserVerified, id)
+	return err
+}
+
+const PurgeDeletedUsers = `-- name: PurgeDeletedUsers :execrows
+DELETE FROM users
+WHERE deleted_at IS NOT NULL AND deleted_at < ?1
+`
+
+// Permanently removes users soft deleted before the cutoff.
+//
+//	DELETE FROM users
+//	WHERE deleted_at IS NOT NULL AND deleted_at < ?1
+func (q *Queries) PurgeDeletedUsers(ctx context.Context, cutoff sql.NullTime) (int64, error) {
+	result, err := q.db.ExecContext(ctx, PurgeDeletedUsers, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const RestoreUser = `-- name: RestoreUser :execrows
+UPDATE users
+SET is_active = TRUE, deleted_at = NULL, updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND deleted_at IS NOT NULL
+`
+
+// RestoreUser
+//
+//	UPDATE users
+//	SET is_active = TRUE, deleted_at = NULL, updated_at = CURRENT_TIMESTAMP
+//	WHERE id = ? AND deleted_at IS NOT NULL
+func (q *Queries) RestoreUser(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, RestoreUser, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const SearchUsersWithFacets = `-- name: SearchUsersWithFacets :many
+WITH matched AS (
+    SELECT id, uuid, email, username, first_name, last_name, status, role, tags, created_at
+    FROM users
+    WHERE deleted_at IS NULL
+      AND (
+          email LIKE '%' || CAST(?1 AS TEXT) || '%'
+          OR username LIKE '%' || CAST(?1 AS TEXT) || '%'
+          OR first_name LIKE '%' || CAST(?1 AS TEXT) || '%'
+          OR last_name LIKE '%' || CAST(?1 AS TEXT) || '%'
+      )
+),
+status_facets AS (
+    SELECT json_group_object(status, total) AS counts
+    FROM (SELECT status, COUNT(*) AS total FROM matched GROUP BY status)
+),
+role_facets AS (
+    SELECT json_group_object(role, total) AS counts
+    FROM (SELECT role, COUNT(*) AS total FROM matched GROUP BY role)
+),
+tag_facets AS (
+    SELECT json_group_array(json_array(tag, total)) AS counts
+    FROM (
+        SELECT je.value AS tag, COUNT(*) AS total
+        FROM matched, json_each(matched.tags) AS je
+        GROUP BY je.value
+        ORDER BY total DESC, tag
+        LIMIT ?2
+    )
+),
+page AS (
+    SELECT id, uuid, email, username, first_name, last_name, status, role, tags, created_at
+    FROM matched
+    WHERE CAST(?3 AS TEXT) = '' OR status = CAST(?3 AS TEXT)
+    ORDER BY created_at DESC
+    LIMIT ?4
+)
+SELECT
+    page.id, page.uuid, page.email, page.username, page.first_name, page.last_name,
+    page.status, page.role, page.tags, page.created_at,
+    status_facets.counts AS status_facets,
+    role_facets.counts AS role_facets,
+    tag_facets.counts AS tag_facets
+FROM status_facets
+CROSS JOIN role_facets
+CROSS JOIN tag_facets
+LEFT JOIN page ON TRUE
+`
+
+type SearchUsersWithFacetsParams struct {
+	Query       string `db:"query" json:"query"`
+	TagLimit    int64  `db:"tag_limit" json:"tagLimit"`
+	Status      string `db:"status" json:"status"`
+	ResultLimit int64  `db:"result_limit" json:"resultLimit"`
+}
+
+type SearchUsersWithFacetsRow struct {
+	ID           sql.NullInt64  `db:"id" json:"id"`
+	UUID         sql.NullString `db:"uuid" json:"uuid"`
+	Email        sql.NullString `db:"email" json:"email"`
+	Username     sql.NullString `db:"username" json:"username"`
+	FirstName    sql.NullString `db:"first_name" json:"firstName"`
+	LastName     sql.NullString `db:"last_name" json:"lastName"`
+	Status       sql.NullString `db:"status" json:"status"`
+	Role         sql.NullString `db:"role" json:"role"`
+	Tags         sql.NullString `db:"tags" json:"tags"`
+	CreatedAt    sql.NullTime   `db:"created_at" json:"createdAt"`
+	StatusFacets interface{}    `db:"status_facets" json:"statusFacets"`
+	RoleFacets   interface{}    `db:"role_facets" json:"roleFacets"`
+	TagFacets    interface{}    `db:"tag_facets" json:"tagFacets"`
+}
+
+// Returns one page of matching users together with facet counts computed
+// over every match. Facets are JSON columns repeated on each row; a single
+// row with NULL user columns is returned when the page is empty.
+//
+//	WITH matched AS (
+//	    SELECT id, uuid, email, username, first_name, last_name, status, role, tags, created_at
+//	    FROM users
+//	    WHERE deleted_at IS NULL
+//	      AND (
+//	          email LIKE '%' || CAST(?1 AS TEXT) || '%'
+//	          OR username LIKE '%' || CAST(?1 AS TEXT) || '%'
+//	          OR first_name LIKE '%' || CAST(?1 AS TEXT) || '%'
+//	          OR last_name LIKE '%' || CAST(?1 AS TEXT) || '%'
+//	      )
+//	),
+//	status_facets AS (
+//	    SELECT json_group_object(status, total) AS counts
+//	    FROM (SELECT status, COUNT(*) AS total FROM matched GROUP BY status)
+//	),
+//	role_facets AS (
+//	    SELECT json_group_object(role, total) AS counts
+//	    FROM (SELECT role, COUNT(*) AS total FROM matched GROUP BY role)
+//	),
+//	tag_facets AS (
+//	    SELECT json_group_array(json_array(tag, total)) AS counts
+//	    FROM (
+//	        SELECT je.value AS tag, COUNT(*) AS total
+//	        FROM matched, json_each(matched.tags) AS je
+//	        GROUP BY je.value
+//	        ORDER BY total DESC, tag
+//	        LIMIT ?2
+//	    )
+//	),
+//	page AS (
+//	    SELECT id, uuid, email, username, first_name, last_name, status, role, tags, created_at
+//	    FROM matched
+//	    WHERE CAST(?3 AS TEXT) = '' OR status = CAST(?3 AS TEXT)
+//	    ORDER BY created_at DESC
+//	    LIMIT ?4
+//	)
+//	SELECT
+//	    page.id, page.uuid, page.email, page.username, page.first_name, page.last_name,
+//	    page.status, page.role, page.tags, page.created_at,
+//	    status_facets.counts AS status_facets,
+//	    role_facets.counts AS role_facets,
+//	    tag_facets.counts AS tag_facets
+//	FROM status_facets
+//	CROSS JOIN role_facets
+//	CROSS JOIN tag_facets
+//	LEFT JOIN page ON TRUE
+func (q *Queries) SearchUsersWithFacets(ctx context.Context, arg *SearchUsersWithFacetsParams) ([]*SearchUsersWithFacetsRow, error) {
+	rows, err := q.db.QueryContext(ctx, SearchUsersWithFacets,
+		arg.Query,
+		arg.TagLimit,
+		arg.Status,
+		arg.ResultLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*SearchUsersWithFacetsRow{}
+	for rows.Next() {
+		var i SearchUsersWithFacetsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UUID,
+			&i.Email,
+			&i.Username,
+			&i.FirstName,
+			&i.LastName,
+			&i.Status,
+			&i.Role,
+			&i.Tags,
+			&i.CreatedAt,
+			&i.StatusFacets,
+			&i.RoleFacets,
+			&i.TagFacets,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const SoftDeleteUser = `-- name: SoftDeleteUser :exec
+UPDATE users
+SET is_active = FALSE, deleted_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND deleted_at IS NULL
+`
+
+// SoftDeleteUser
+//
+//	UPDATE users
+//	SET is_active = FALSE, deleted_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+//	WHERE id = ? AND deleted_at IS NULL
 func (q *Queries) SoftDeleteUser(ctx context.Context, id int64) error {
 	_, err := q.db.ExecContext(ctx, SoftDeleteUser, id)
 	return err
 }
 
+const SoftDeleteUsers = `-- name: SoftDeleteUsers :execrows
+UPDATE users
+SET is_active = FALSE, deleted_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+WHERE id IN (/*SLICE:ids*/?) AND deleted_at IS NULL
+`
+
+// SoftDeleteUsers
+//
+//	UPDATE users
+//	SET is_active = FALSE, deleted_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+//	WHERE id IN (/*SLICE:ids*/?) AND deleted_at IS NULL
+func (q *Queries) SoftDeleteUsers(ctx context.Context, ids []int64) (int64, error) {
+	query := SoftDeleteUsers
+	var queryParams []interface{}
+	if len(ids) > 0 {
+		for _, v := range ids {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:ids*/?", strings.Repeat(",?", len(ids))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:ids*/?", "NULL", 1)
+	}
+	result, err := q.db.ExecContext(ctx, query, queryParams...)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const UpdateLastLogin = `-- name: UpdateLastLogin :exec
 UPDATE users 
 SET last_login_at = CURRENT_TIMESTAMP
@@ -351,20 +1051,31 @@ SET
     last_name = COALESCE(?, last_name),
     profile_metadata = COALESCE(?, profile_metadata),
     is_active = COALESCE(?, is_active),
-    is_verified = COALESCE(?, is_verified)
-WHERE id = ?
-RETURNING id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata
+    is_verified = COALESCE(?, is_verified),
+    status = COALESCE(?8, status),
+    role = COALESCE(?9, role),
+    tags = COALESCE(?10, tags),
+    last_login_at = COALESCE(?11, last_login_at),
+    username_canonical = ?12,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = ?13 AND deleted_at IS NULL
+RETURNING id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, deleted_at, username_canonical
 `
 
 type UpdateUserParams struct {
-	Email           string       `db:"email" json:"email"`
-	Username        string       `db:"username" json:"username"`
-	FirstName       string       `db:"first_name" json:"firstName"`
-	LastName        string       `db:"last_name" json:"lastName"`
-	ProfileMetadata interface{}  `db:"profile_metadata" json:"profileMetadata"`
-	IsActive        sql.NullBool `db:"is_active" json:"isActive"`
-	IsVerified      sql.NullBool `db:"is_verified" json:"isVerified"`
-	ID              int64        `db:"id" json:"id"`
+	Email             string         `db:"email" json:"email"`
+	Username          string         `db:"username" json:"username"`
+	FirstName         string         `db:"first_name" json:"firstName"`
+	LastName          string         `db:"last_name" json:"lastName"`
+	ProfileMetadata   interface{}    `db:"profile_metadata" json:"profileMetadata"`
+	IsActive          sql.NullBool   `db:"is_active" json:"isActive"`
+	IsVerified        sql.NullBool   `db:"is_verified" json:"isVerified"`
+	Status            sql.NullString `db:"status" json:"status"`
+	Role              sql.NullString `db:"role" json:"role"`
+	Tags              sql.NullString `db:"tags" json:"tags"`
+	LastLoginAt       sql.NullTime   `db:"last_login_at" json:"lastLoginAt"`
+	UsernameCanonical string         `db:"username_canonical" json:"usernameCanonical"`
+	ID                int64          `db:"id" json:"id"`
 }
 
 // UpdateUser
@@ -377,9 +1088,15 @@ type UpdateUserParams struct {
 //	    last_name = COALESCE(?, last_name),
 //	    profile_metadata = COALESCE(?, profile_metadata),
 //	    is_active = COALESCE(?, is_active),
-//	    is_verified = COALESCE(?, is_verified)
-//	WHERE id = ?
-//	RETURNING id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata
+//	    is_verified = COALESCE(?, is_verified),
+//	    status = COALESCE(?8, status),
+//	    role = COALESCE(?9, role),
+//	    tags = COALESCE(?10, tags),
+//	    last_login_at = COALESCE(?11, last_login_at),
+//	    username_canonical = ?12,
+//	    updated_at = CURRENT_TIMESTAMP
+//	WHERE id = ?13 AND deleted_at IS NULL
+//	RETURNING id, uuid, email, username, password_hash, first_name, last_name, created_at, updated_at, last_login_at, is_active, is_verified, profile_metadata, status, role, tags, deleted_at, username_canonical
 func (q *Queries) UpdateUser(ctx context.Context, arg *UpdateUserParams) (*Users, error) {
 	row := q.db.QueryRowContext(ctx, UpdateUser,
 		arg.Email,
@@ -389,6 +1106,11 @@ func (q *Queries) UpdateUser(ctx context.Context, arg *UpdateUserParams) (*Users
 		arg.ProfileMetadata,
 		arg.IsActive,
 		arg.IsVerified,
+		arg.Status,
+		arg.Role,
+		arg.Tags,
+		arg.LastLoginAt,
+		arg.UsernameCanonical,
 		arg.ID,
 	)
 	var i Users
@@ -406,10 +1128,92 @@ func (q *Queries) UpdateUser(ctx context.Context, arg *UpdateUserParams) (*Users
 		&i.IsActive,
 		&i.IsVerified,
 		&i.ProfileMetadata,
+		&i.Status,
+		&i.Role,
+		&i.Tags,
+		&i.DeletedAt,
+		&i.UsernameCanonical,
 	)
 	return &i, err
 }
 
+const UpdateUserRole = `-- name: UpdateUserRole :exec
+UPDATE users
+SET role = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?
+`
+
+type UpdateUserRoleParams struct {
+	Role string `db:"role" json:"role"`
+	ID   int64  `db:"id" json:"id"`
+}
+
+// UpdateUserRole
+//
+//	UPDATE users
+//	SET role = ?, updated_at = CURRENT_TIMESTAMP
+//	WHERE id = ?
+func (q *Queries) UpdateUserRole(ctx context.Context, arg *UpdateUserRoleParams) error {
+	_, err := q.db.ExecContext(ctx, UpdateUserRole, arg.Role, arg.ID)
+	return err
+}
+
+const UpdateUserStatus = `-- name: UpdateUserStatus :exec
+UPDATE users
+SET status = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?
+`
+
+type UpdateUserStatusParams struct {
+	Status string `db:"status" json:"status"`
+	ID     int64  `db:"id" json:"id"`
+}
+
+// UpdateUserStatus
+//
+//	UPDATE users
+//	SET status = ?, updated_at = CURRENT_TIMESTAMP
+//	WHERE id = ?
+func (q *Queries) UpdateUserStatus(ctx context.Context, arg *UpdateUserStatusParams) error {
+	_, err := q.db.ExecContext(ctx, UpdateUserStatus, arg.Status, arg.ID)
+	return err
+}
+
+const UpdateUsersStatus = `-- name: UpdateUsersStatus :execrows
+UPDATE users
+SET status = ?1, updated_at = CURRENT_TIMESTAMP
+WHERE id IN (/*SLICE:ids*/?) AND deleted_at IS NULL
+`
+
+type UpdateUsersStatusParams struct {
+	Status string  `db:"status" json:"status"`
+	Ids    []int64 `db:"ids" json:"ids"`
+}
+
+// UpdateUsersStatus
+//
+//	UPDATE users
+//	SET status = ?1, updated_at = CURRENT_TIMESTAMP
+//	WHERE id IN (/*SLICE:ids*/?) AND deleted_at IS NULL
+func (q *Queries) UpdateUsersStatus(ctx context.Context, arg *UpdateUsersStatusParams) (int64, error) {
+	query := UpdateUsersStatus
+	var queryParams []interface{}
+	queryParams = append(queryParams, arg.Status)
+	if len(arg.Ids) > 0 {
+		for _, v := range arg.Ids {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:ids*/?", strings.Repeat(",?", len(arg.Ids))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:ids*/?", "NULL", 1)
+	}
+	result, err := q.db.ExecContext(ctx, query, queryParams...)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const VerifyUser = `-- name: VerifyUser :exec
 UPDATE users 
 SET is_verified = TRUE, updated_at = CURRENT_TIMESTAMP
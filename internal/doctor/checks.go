@@ -0,0 +1,384 @@
+package doctor
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// queryNamePattern matches sqlc query annotations such as "-- name: GetUser :one".
+var queryNamePattern = regexp.MustCompile(`^--\s*name:\s*(\w+)\s+:\w+`)
+
+// dbCalls are the driver methods that run SQL directly, for adapters that
+// bypass the generated queries.
+var dbCalls = map[string]bool{
+	"Exec": true, "ExecContext": true,
+	"Query": true, "QueryContext": true,
+	"QueryRow": true, "QueryRowContext": true,
+	"CopyFrom": true, "SendBatch": true,
+}
+
+// vet runs `sqlc vet` and reports its output as findings.
+func vet(ctx context.Context, cfg Config, report *Report) {
+	binary, err := exec.LookPath(cfg.SQLC)
+	if err != nil {
+		report.add(CheckVet, SeverityWarning, "", "%s not found; skipped sqlc vet", cfg.SQLC)
+
+		return
+	}
+
+	var output bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, binary, "vet", "-f", cfg.SQLCConfig)
+	cmd.Dir = cfg.Root
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+
+	err = cmd.Run()
+	if err == nil {
+		return
+	}
+
+	message := strings.TrimSpace(output.String())
+	if message == "" {
+		message = err.Error()
+	}
+
+	for line := range strings.Lines(message) {
+		if line = strings.TrimSpace(line); line != "" {
+			report.add(CheckVet, SeverityError, "", "%s", line)
+		}
+	}
+}
+
+// parseQueryNames returns the named queries in dir mapped to their file.
+func parseQueryNames(dir string) (map[string]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.sql"))
+	if err != nil {
+		return nil, fmt.Errorf("dir=%v: %w", dir, err)
+	}
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("dir=%v: %w", dir, os.ErrNotExist)
+	}
+
+	names := make(map[string]string)
+
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("file=%v: %w", file, err)
+		}
+
+		scanner := bufio.NewScanner(bytes.NewReader(content))
+		for scanner.Scan() {
+			match := queryNamePattern.FindStringSubmatch(strings.TrimSpace(scanner.Text()))
+			if match != nil {
+				names[match[1]] = filepath.Base(file)
+			}
+		}
+	}
+
+	return names, nil
+}
+
+// generatedPackage is what the doctor needs from a sqlc output package.
+type generatedPackage struct {
+	// methods are the methods of the Querier interface.
+	methods map[string]bool
+	// untyped are model fields sqlc typed as interface{}, as "Model.Field".
+	untyped []string
+}
+
+// errNoQuerier is returned when a generated package lacks the Querier
+// interface, which requires emit_interface in sqlc.yaml.
+var errNoQuerier = errors.New("no Querier interface; enable emit_interface")
+
+// parseGenerated reads the Querier interface and models of a sqlc package.
+func parseGenerated(dir string) (*generatedPackage, error) {
+	files, err := parseDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	generated := &generatedPackage{}
+
+	for _, file := range files {
+		ast.Inspect(file, func(node ast.Node) bool {
+			spec, ok := node.(*ast.TypeSpec)
+			if !ok {
+				return true
+			}
+
+			switch typ := spec.Type.(type) {
+			case *ast.InterfaceType:
+				if spec.Name.Name == "Querier" {
+					generated.methods = interfaceMethods(typ)
+				}
+			case *ast.StructType:
+				generated.untyped = append(generated.untyped, untypedFields(spec.Name.Name, typ)...)
+			}
+
+			return false
+		})
+	}
+
+	if generated.methods == nil {
+		return nil, fmt.Errorf("dir=%v: %w", dir, errNoQuerier)
+	}
+
+	slices.Sort(generated.untyped)
+
+	return generated, nil
+}
+
+// interfaceMethods returns the method names of an interface type.
+func interfaceMethods(typ *ast.InterfaceType) map[string]bool {
+	methods := make(map[string]bool)
+
+	for _, field := range typ.Methods.List {
+		for _, name := range field.Names {
+			methods[name.Name] = true
+		}
+	}
+
+	return methods
+}
+
+// untypedFields returns the interface{} or any fields of a struct.
+func untypedFields(structName string, typ *ast.StructType) []string {
+	var fields []string
+
+	for _, field := range typ.Fields.List {
+		if !isEmptyInterface(field.Type) {
+			continue
+		}
+
+		for _, name := range field.Names {
+			fields = append(fields, structName+"."+name.Name)
+		}
+	}
+
+	return fields
+}
+
+func isEmptyInterface(expr ast.Expr) bool {
+	switch typ := expr.(type) {
+	case *ast.InterfaceType:
+		return len(typ.Methods.List) == 0
+	case *ast.Ident:
+		return typ.Name == "any"
+	default:
+		return false
+	}
+}
+
+// adapterPackage is what the doctor found in a repository adapter package.
+type adapterPackage struct {
+	// called are the Querier methods the adapter calls.
+	called map[string]bool
+	// unbacked are the repository methods, as "Type.Method", that neither run
+	// a query nor call a function that does.
+	unbacked []string
+}
+
+// funcInfo summarizes one function or method of an adapter package.
+type funcInfo struct {
+	qualified  string
+	repository bool
+	direct     bool
+	calls      []string
+}
+
+// parseAdapter finds which queries an adapter calls and which of its
+// repository methods run no query. Calls are resolved by name within the
+// package, which is precise enough for the flat adapter packages.
+func parseAdapter(dir string, queries map[string]bool) (*adapterPackage, error) {
+	files, err := parseDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	adapter := &adapterPackage{called: make(map[string]bool)}
+	funcs := make(map[string][]*funcInfo)
+
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Body == nil {
+				continue
+			}
+
+			info := inspectFunc(fn, queries, adapter.called)
+			funcs[fn.Name.Name] = append(funcs[fn.Name.Name], info)
+		}
+	}
+
+	backed := resolveBacked(funcs)
+
+	for _, infos := range funcs {
+		for _, info := range infos {
+			if info.repository && !backed[info] {
+				adapter.unbacked = append(adapter.unbacked, info.qualified)
+			}
+		}
+	}
+
+	slices.Sort(adapter.unbacked)
+
+	return adapter, nil
+}
+
+// inspectFunc records the calls of fn and whether it runs a query itself.
+func inspectFunc(fn *ast.FuncDecl, queries, called map[string]bool) *funcInfo {
+	info := &funcInfo{qualified: fn.Name.Name}
+
+	if fn.Recv != nil && len(fn.Recv.List) == 1 {
+		receiver := receiverName(fn.Recv.List[0].Type)
+		info.qualified = receiver + "." + fn.Name.Name
+		info.repository = fn.Name.IsExported() &&
+			strings.HasSuffix(receiver, "Repository") &&
+			takesContext(fn.Type)
+	}
+
+	ast.Inspect(fn.Body, func(node ast.Node) bool {
+		call, ok := node.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+
+		switch target := call.Fun.(type) {
+		case *ast.SelectorExpr:
+			name := target.Sel.Name
+
+			switch {
+			case queries[name]:
+				called[name] = true
+				info.direct = true
+			case dbCalls[name]:
+				info.direct = true
+			default:
+				info.calls = append(info.calls, name)
+			}
+		case *ast.Ident:
+			info.calls = append(info.calls, target.Name)
+		}
+
+		return true
+	})
+
+	return info
+}
+
+// resolveBacked marks the functions that run a query directly or through
+// the functions they call.
+func resolveBacked(funcs map[string][]*funcInfo) map[*funcInfo]bool {
+	backed := make(map[*funcInfo]bool)
+
+	for changed := true; changed; {
+		changed = false
+
+		for _, infos := range funcs {
+			for _, info := range infos {
+				if backed[info] || !(info.direct || callsBacked(info, funcs, backed)) {
+					continue
+				}
+
+				backed[info] = true
+				changed = true
+			}
+		}
+	}
+
+	return backed
+}
+
+func callsBacked(info *funcInfo, funcs map[string][]*funcInfo, backed map[*funcInfo]bool) bool {
+	for _, name := range info.calls {
+		for _, callee := range funcs[name] {
+			if backed[callee] {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// receiverName returns the type name of a method receiver.
+func receiverName(expr ast.Expr) string {
+	switch typ := expr.(type) {
+	case *ast.StarExpr:
+		return receiverName(typ.X)
+	case *ast.IndexExpr:
+		return receiverName(typ.X)
+	case *ast.Ident:
+		return typ.Name
+	default:
+		return ""
+	}
+}
+
+// takesContext reports whether the first parameter is a context.Context.
+func takesContext(fn *ast.FuncType) bool {
+	if fn.Params == nil || len(fn.Params.List) == 0 {
+		return false
+	}
+
+	selector, ok := fn.Params.List[0].Type.(*ast.SelectorExpr)
+
+	return ok && selector.Sel.Name == "Context"
+}
+
+// parseDir parses the non-test Go files of dir, regardless of build tags.
+func parseDir(dir string) ([]*ast.File, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, fmt.Errorf("dir=%v: %w", dir, err)
+	}
+
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("dir=%v: %w", dir, os.ErrNotExist)
+	}
+
+	fset := token.NewFileSet()
+	files := make([]*ast.File, 0, len(paths))
+
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+
+		file, err := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, fmt.Errorf("file=%v: %w", path, err)
+		}
+
+		files = append(files, file)
+	}
+
+	return files, nil
+}
+
+// sortedKeys returns the keys of m in order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+
+	return keys
+}
@@ -0,0 +1,179 @@
+// Package doctor checks that the sqlc configuration, the SQL queries, the
+// generated code and the repository adapters agree with each other.
+//
+// It runs `sqlc vet`, confirms every named query has been generated and that
+// no stale generated methods remain, reports queries no adapter calls and
+// adapter methods that run no query, and flags columns sqlc could not map to
+// a Go type because an override is missing.
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+)
+
+// Severity ranks a finding.
+type Severity string
+
+const (
+	// SeverityError marks findings that break the build or the repository contract.
+	SeverityError Severity = "error"
+	// SeverityWarning marks findings worth a look that do not break anything.
+	SeverityWarning Severity = "warning"
+)
+
+// Check names.
+const (
+	CheckVet       = "sqlc-vet"
+	CheckGenerated = "generated"
+	CheckUnused    = "unused-query"
+	CheckBacking   = "backing-query"
+	CheckOverrides = "missing-override"
+)
+
+// Finding is a single problem reported by a check.
+type Finding struct {
+	Check    string   `json:"check"`
+	Severity Severity `json:"severity"`
+	Engine   string   `json:"engine,omitempty"`
+	Message  string   `json:"message"`
+}
+
+// String formats the finding for terminal output.
+func (f Finding) String() string {
+	if f.Engine == "" {
+		return fmt.Sprintf("%s [%s] %s", f.Severity, f.Check, f.Message)
+	}
+
+	return fmt.Sprintf("%s [%s] %s: %s", f.Severity, f.Check, f.Engine, f.Message)
+}
+
+// Report collects the findings of a run.
+type Report struct {
+	Findings []Finding `json:"findings"`
+}
+
+// HasErrors reports whether any finding is an error.
+func (r *Report) HasErrors() bool {
+	return slices.ContainsFunc(r.Findings, func(f Finding) bool { return f.Severity == SeverityError })
+}
+
+func (r *Report) add(check string, severity Severity, engine, format string, args ...any) {
+	r.Findings = append(r.Findings, Finding{
+		Check:    check,
+		Severity: severity,
+		Engine:   engine,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// Engine locates the files of one database engine.
+type Engine struct {
+	// Name identifies the engine in findings, e.g. "sqlite".
+	Name string
+	// Queries is the directory of the .sql query files.
+	Queries string
+	// Generated is the sqlc output directory containing the Querier interface.
+	Generated string
+	// Adapter is the directory of the repository adapters using the queries.
+	Adapter string
+}
+
+// Config configures a doctor run. Relative paths are resolved against Root.
+type Config struct {
+	Root    string
+	Engines []Engine
+
+	// SQLC is the sqlc binary used for `sqlc vet`. Empty skips vet.
+	SQLC string
+	// SQLCConfig is the sqlc configuration file passed to vet.
+	SQLCConfig string
+}
+
+// DefaultConfig returns the layout of this repository rooted at root.
+func DefaultConfig(root string) Config {
+	engines := make([]Engine, 0, 3)
+	for _, name := range []string{"sqlite", "postgres", "mysql"} {
+		engines = append(engines, Engine{
+			Name:      name,
+			Queries:   filepath.Join("sql", name, "queries"),
+			Generated: filepath.Join("internal", "db", name),
+			Adapter:   filepath.Join("internal", "adapters", name),
+		})
+	}
+
+	return Config{
+		Root:       root,
+		Engines:    engines,
+		SQLC:       "sqlc",
+		SQLCConfig: "sqlc.yaml",
+	}
+}
+
+// Run executes every check and returns their findings. The error is only
+// non-nil if a check could not run at all, e.g. because a directory is missing.
+func Run(ctx context.Context, cfg Config) (*Report, error) {
+	report := &Report{}
+
+	if cfg.SQLC != "" {
+		vet(ctx, cfg, report)
+	}
+
+	for _, engine := range cfg.Engines {
+		err := checkEngine(cfg.Root, engine, report)
+		if err != nil {
+			return nil, fmt.Errorf("engine=%v: %w", engine.Name, err)
+		}
+	}
+
+	return report, nil
+}
+
+// checkEngine runs the source checks of one engine.
+func checkEngine(root string, engine Engine, report *Report) error {
+	queries, err := parseQueryNames(filepath.Join(root, engine.Queries))
+	if err != nil {
+		return err
+	}
+
+	generated, err := parseGenerated(filepath.Join(root, engine.Generated))
+	if err != nil {
+		return err
+	}
+
+	adapter, err := parseAdapter(filepath.Join(root, engine.Adapter), generated.methods)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range sortedKeys(queries) {
+		if !generated.methods[name] {
+			report.add(CheckGenerated, SeverityError, engine.Name,
+				"query %s in %s is not generated; run sqlc generate", name, queries[name])
+		}
+	}
+
+	for _, name := range sortedKeys(generated.methods) {
+		if _, ok := queries[name]; !ok {
+			report.add(CheckGenerated, SeverityError, engine.Name,
+				"generated method %s has no query; run sqlc generate", name)
+		}
+
+		if !adapter.called[name] {
+			report.add(CheckUnused, SeverityWarning, engine.Name, "query %s is not used by any adapter", name)
+		}
+	}
+
+	for _, method := range adapter.unbacked {
+		report.add(CheckBacking, SeverityWarning, engine.Name, "repository method %s runs no query", method)
+	}
+
+	for _, field := range generated.untyped {
+		report.add(CheckOverrides, SeverityWarning, engine.Name,
+			"%s has no Go type; add a column or db_type override", field)
+	}
+
+	return nil
+}
@@ -0,0 +1,35 @@
+package entities
+
+import "time"
+
+// AccountDeletionGracePeriod is how long a requested account deletion can
+// be canceled before the account is erased.
+const AccountDeletionGracePeriod = 30 * 24 * time.Hour
+
+// AccountDeletion is a pending request to erase an account once its grace
+// period ends. It is removed when the deletion is canceled or carried out.
+type AccountDeletion struct {
+	UserID UserID
+	// RequestedBy is who requested the deletion, the user or an administrator.
+	RequestedBy  UserID
+	RequestedAt  time.Time
+	ScheduledFor time.Time
+}
+
+// NewAccountDeletion schedules the deletion of the account of userID,
+// requested by requestedBy, for when grace has passed.
+func NewAccountDeletion(userID, requestedBy UserID, grace time.Duration) *AccountDeletion {
+	now := time.Now().UTC()
+
+	return &AccountDeletion{
+		UserID:       userID,
+		RequestedBy:  requestedBy,
+		RequestedAt:  now,
+		ScheduledFor: now.Add(grace),
+	}
+}
+
+// IsDue returns true if the grace period ended at now.
+func (d *AccountDeletion) IsDue(now time.Time) bool {
+	return !now.Before(d.ScheduledFor)
+}
@@ -0,0 +1,170 @@
+package entities
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+)
+
+// DefaultActivityRetention is how long activity entries are kept.
+const DefaultActivityRetention = 90 * 24 * time.Hour
+
+// maxActivitySummaryLength bounds the summary of an activity entry.
+const maxActivitySummaryLength = 255
+
+// ActivityID is the identifier of an activity entry.
+type ActivityID int64
+
+// Int64 returns the ID as int64.
+func (id ActivityID) Int64() int64 { return int64(id) }
+
+// ActivityKind groups activity entries, e.g. to pick an icon for them.
+type ActivityKind string
+
+// Activity kinds.
+const (
+	ActivityKindSignIn       ActivityKind = "sign_in"
+	ActivityKindSecurity     ActivityKind = "security"
+	ActivityKindProfile      ActivityKind = "profile"
+	ActivityKindAccount      ActivityKind = "account"
+	ActivityKindOrganization ActivityKind = "organization"
+)
+
+// IsValid returns true if the kind is one of the defined kinds.
+func (k ActivityKind) IsValid() bool {
+	switch k {
+	case ActivityKindSignIn, ActivityKindSecurity, ActivityKindProfile, ActivityKindAccount, ActivityKindOrganization:
+		return true
+	default:
+		return false
+	}
+}
+
+// String implements fmt.Stringer for ActivityKind.
+func (k ActivityKind) String() string { return string(k) }
+
+// ActivityEntry is a human-readable line of a user's activity feed, such
+// as "Signed in" or "Role changed from user to admin". Entries are
+// projected from domain events and never changed.
+type ActivityEntry struct {
+	ID     ActivityID
+	UserID UserID
+	// EventID is the event the entry was projected from, so redelivered
+	// events are recorded once.
+	EventID   IDID
+	EventType string
+	Kind      ActivityKind
+	Summary   string
+	// ActorID is who caused the activity if it was someone other than the
+	// user, such as an administrator; zero otherwise.
+	ActorID    UserID
+	OccurredAt time.Time
+}
+
+// NewActivityEntry creates an entry of the feed of userID.
+func NewActivityEntry(
+	userID UserID,
+	eventID IDID,
+	eventType string,
+	kind ActivityKind,
+	summary string,
+	actorID UserID,
+	occurredAt time.Time,
+) (*ActivityEntry, error) {
+	if userID <= 0 {
+		return nil, NewValidationError("user_id", "must be positive")
+	}
+
+	if !kind.IsValid() {
+		return nil, ErrInvalidActivityKind
+	}
+
+	if summary == "" || len(summary) > maxActivitySummaryLength {
+		return nil, ErrInvalidActivitySummary
+	}
+
+	if actorID == userID {
+		actorID = 0
+	}
+
+	return &ActivityEntry{
+		UserID:     userID,
+		EventID:    eventID,
+		EventType:  eventType,
+		Kind:       kind,
+		Summary:    summary,
+		ActorID:    actorID,
+		OccurredAt: occurredAt,
+	}, nil
+}
+
+// ActivityCursor is the keyset position after the last entry of a page.
+// Feeds are ordered newest first, by occurrence and then by ID.
+type ActivityCursor struct {
+	OccurredAt time.Time  `json:"t"`
+	ID         ActivityID `json:"id"`
+}
+
+// Bound returns the cursor a keyset query continues after; a nil cursor
+// starts at the first page.
+func (c *ActivityCursor) Bound() ActivityCursor {
+	if c == nil {
+		return ActivityCursor{OccurredAt: rangeEnd, ID: math.MaxInt64}
+	}
+
+	return *c
+}
+
+// Encode returns the opaque token handed to clients.
+func (c ActivityCursor) Encode() string {
+	data, _ := json.Marshal(c)
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeActivityCursor parses a token returned by Encode. An empty token
+// yields nil, which requests the first page.
+func DecodeActivityCursor(token string) (*ActivityCursor, error) {
+	if token == "" {
+		return nil, nil //nolint:nilnil // no cursor means the first page
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("decode: %w", ErrInvalidCursor)
+	}
+
+	var cursor ActivityCursor
+
+	err = json.Unmarshal(data, &cursor)
+	if err != nil || cursor.ID <= 0 || cursor.OccurredAt.IsZero() {
+		return nil, fmt.Errorf("parse: %w", ErrInvalidCursor)
+	}
+
+	return &cursor, nil
+}
+
+// ActivityPage is one page of a user's activity feed.
+type ActivityPage struct {
+	Entries []*ActivityEntry `json:"entries"`
+	// NextCursor requests the following page; it is empty on the last page.
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+// NewActivityPage builds a page from up to limit+1 entries; the extra entry
+// only signals that another page follows.
+func NewActivityPage(entries []*ActivityEntry, limit int) *ActivityPage {
+	if len(entries) <= limit {
+		return &ActivityPage{Entries: entries}
+	}
+
+	entries = entries[:limit]
+	last := entries[limit-1]
+
+	return &ActivityPage{
+		Entries:    entries,
+		NextCursor: ActivityCursor{OccurredAt: last.OccurredAt, ID: last.ID}.Encode(),
+	}
+}
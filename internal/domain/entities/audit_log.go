@@ -0,0 +1,153 @@
+package entities
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AuditLogID is the identifier of an audit log entry.
+type AuditLogID int64
+
+// Int64 returns the ID as int64.
+func (id AuditLogID) Int64() int64 { return int64(id) }
+
+// AuditAction names a mutating operation recorded in the audit log.
+type AuditAction string
+
+// Audited operations.
+const (
+	AuditActionUserCreate   AuditAction = "user.create"
+	AuditActionUserUpdate   AuditAction = "user.update"
+	AuditActionRoleChange   AuditAction = "user.role_change"
+	AuditActionStatusChange AuditAction = "user.status_change"
+	AuditActionUserVerify   AuditAction = "user.verify"
+	AuditActionUserDelete   AuditAction = "user.delete"
+	AuditActionUserRestore  AuditAction = "user.restore"
+	AuditActionDataExport   AuditAction = "user.data_export"
+	AuditActionUserErase    AuditAction = "user.erase"
+
+	AuditActionImpersonationStart AuditAction = "user.impersonation_start"
+	AuditActionImpersonationEnd   AuditAction = "user.impersonation_end"
+
+	AuditActionEmailChange    AuditAction = "user.email_change"
+	AuditActionUsernameChange AuditAction = "user.username_change"
+	AuditActionIdentityRevert AuditAction = "user.identity_revert"
+
+	AuditActionAvatarChange AuditAction = "user.avatar_change"
+
+	AuditActionUserFlag      AuditAction = "user.flag"
+	AuditActionUserSuspend   AuditAction = "user.suspend"
+	AuditActionUserReinstate AuditAction = "user.reinstate"
+	AuditActionAppealDecide  AuditAction = "user.appeal_decide"
+
+	AuditActionDeletionRequest AuditAction = "user.deletion_request"
+	AuditActionDeletionCancel  AuditAction = "user.deletion_cancel"
+
+	AuditActionBulkRoleChange   AuditAction = "users.bulk_role_change"
+	AuditActionBulkStatusChange AuditAction = "users.bulk_status_change"
+)
+
+// String implements fmt.Stringer for AuditAction.
+func (a AuditAction) String() string { return string(a) }
+
+// AuditLog records who changed a user, what changed, and from where.
+// Entries are immutable once recorded.
+type AuditLog struct {
+	ID AuditLogID
+	// ActorID is the user who performed the operation, or zero for the system.
+	ActorID UserID
+	// UserID is the user that was changed, or zero for bulk operations,
+	// which name the users they changed in Changes.
+	UserID    UserID
+	Action    AuditAction
+	Changes   []FieldChange
+	IPAddress string
+	CreatedAt time.Time
+}
+
+// NewAuditLog creates an audit log entry for an operation that happened now.
+func NewAuditLog(
+	actorID, userID UserID,
+	action AuditAction,
+	changes []FieldChange,
+	ipAddress string,
+) *AuditLog {
+	if changes == nil {
+		changes = make([]FieldChange, 0)
+	}
+
+	return &AuditLog{
+		ActorID:   actorID,
+		UserID:    userID,
+		Action:    action,
+		Changes:   changes,
+		IPAddress: ipAddress,
+		CreatedAt: time.Now().UTC(),
+	}
+}
+
+// NewBulkAuditLog creates the single audit log entry of a bulk operation
+// that set field to value. The IDs of the users that were changed and of
+// those that failed are recorded as comma-separated changes.
+func NewBulkAuditLog(
+	actorID UserID,
+	action AuditAction,
+	field, value string,
+	succeeded, failed []UserID,
+	ipAddress string,
+) *AuditLog {
+	return NewAuditLog(actorID, 0, action, []FieldChange{
+		{Field: field, New: value},
+		{Field: "succeeded", New: joinUserIDs(succeeded)},
+		{Field: "failed", New: joinUserIDs(failed)},
+	}, ipAddress)
+}
+
+// joinUserIDs returns ids as a comma-separated list of numbers.
+func joinUserIDs(ids []UserID) string {
+	parts := make([]string, 0, len(ids))
+	for _, id := range ids {
+		parts = append(parts, strconv.FormatInt(int64(id), 10))
+	}
+
+	return strings.Join(parts, ",")
+}
+
+// IsSystem returns true if the operation was not performed by a user.
+func (l *AuditLog) IsSystem() bool {
+	return l.ActorID == 0
+}
+
+// AuditFilter selects audit log entries. Zero values match everything.
+type AuditFilter struct {
+	UserID  UserID
+	ActorID UserID
+	Action  AuditAction
+	// From is inclusive and To exclusive.
+	From   time.Time
+	To     time.Time
+	Limit  int
+	Offset int
+}
+
+// Bounds replacing the open ends of time ranges; beyond any stored value.
+var (
+	rangeStart = time.Unix(0, 0).UTC()
+	rangeEnd   = time.Date(9999, time.December, 31, 0, 0, 0, 0, time.UTC)
+)
+
+// TimeRange returns the filter's time range with open ends replaced by bounds
+// every engine can compare against.
+func (f AuditFilter) TimeRange() (time.Time, time.Time) {
+	from, to := f.From, f.To
+	if from.IsZero() {
+		from = rangeStart
+	}
+
+	if to.IsZero() {
+		to = rangeEnd
+	}
+
+	return from, to
+}
@@ -0,0 +1,122 @@
+package entities
+
+import (
+	"time"
+)
+
+// Limits of avatar images.
+const (
+	// AvatarSize is the width and height stored avatars are scaled down to.
+	AvatarSize = 256
+	// AvatarMaxBytes is the largest accepted upload.
+	AvatarMaxBytes = 5 << 20
+	// AvatarMaxDimension is the largest accepted width or height of an upload,
+	// which bounds the memory needed to decode it.
+	AvatarMaxDimension = 4096
+)
+
+// AvatarMetadataKey is the metadata key holding the avatar of a user. Only
+// SetAvatar and ClearAvatar should write it.
+const AvatarMetadataKey = "avatar"
+
+// UserAvatar links a user to an avatar image in blob storage. It is kept in
+// the user's metadata, so every repository persists it without a schema change.
+type UserAvatar struct {
+	// Key locates the image in blob storage.
+	Key string
+	// URL is the address clients load the image from.
+	URL         string
+	ContentType string
+	Width       int
+	Height      int
+	Size        int64
+	UpdatedAt   time.Time
+}
+
+// IsZero returns true if the user has no avatar.
+func (a UserAvatar) IsZero() bool {
+	return a.Key == ""
+}
+
+// metadata renders the avatar as a metadata value.
+func (a UserAvatar) metadata() map[string]any {
+	return map[string]any{
+		"key":          a.Key,
+		"url":          a.URL,
+		"content_type": a.ContentType,
+		"width":        a.Width,
+		"height":       a.Height,
+		"size":         a.Size,
+		"updated_at":   a.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+// avatarFromMetadata parses a metadata value written by UserAvatar.metadata.
+// Values read back from JSON hold float64 numbers.
+func avatarFromMetadata(value any) UserAvatar {
+	fields, ok := value.(map[string]any)
+	if !ok {
+		return UserAvatar{}
+	}
+
+	text := func(name string) string {
+		s, _ := fields[name].(string)
+
+		return s
+	}
+
+	number := func(name string) int64 {
+		switch n := fields[name].(type) {
+		case int:
+			return int64(n)
+		case int64:
+			return n
+		case float64:
+			return int64(n)
+		default:
+			return 0
+		}
+	}
+
+	updatedAt, _ := time.Parse(time.RFC3339, text("updated_at"))
+
+	return UserAvatar{
+		Key:         text("key"),
+		URL:         text("url"),
+		ContentType: text("content_type"),
+		Width:       int(number("width")),
+		Height:      int(number("height")),
+		Size:        number("size"),
+		UpdatedAt:   updatedAt,
+	}
+}
+
+// Avatar returns the avatar of the user, which is zero if the user has none.
+func (u *User) Avatar() UserAvatar {
+	value, ok := u.metadata.Get(AvatarMetadataKey)
+	if !ok {
+		return UserAvatar{}
+	}
+
+	return avatarFromMetadata(value)
+}
+
+// SetAvatar links the user to avatar, replacing any previous one.
+func (u *User) SetAvatar(avatar UserAvatar) {
+	if u.metadata == nil {
+		u.metadata = NewUserMetadata()
+	}
+
+	u.metadata.Set(AvatarMetadataKey, avatar.metadata())
+	u.updatedAt = time.Now()
+}
+
+// ClearAvatar removes the avatar link of the user.
+func (u *User) ClearAvatar() {
+	if _, ok := u.metadata.Get(AvatarMetadataKey); !ok {
+		return
+	}
+
+	delete(u.metadata, AvatarMetadataKey)
+	u.updatedAt = time.Now()
+}
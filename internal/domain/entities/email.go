@@ -0,0 +1,136 @@
+package entities
+
+import (
+	"strings"
+
+	"golang.org/x/net/idna"
+)
+
+// Length limits of email addresses, from RFC 5321.
+const (
+	maxEmailLength       = 254
+	maxEmailLocalLength  = 64
+	maxDomainLength      = 253
+	maxDomainLabelLength = 63
+)
+
+// atextSymbols are the symbols RFC 5322 allows in an atom besides letters
+// and digits.
+const atextSymbols = "!#$%&'*+-/=?^_`{|}~"
+
+// ErrInvalidDomain is returned for a string that is not a host name.
+var ErrInvalidDomain = NewValidationError("domain", "must be a valid domain name")
+
+// Email represents a validated email address.
+type Email string
+
+// NewEmail creates a new Email from a string, validating its syntax: an
+// addr-spec of RFC 5322 whose local part is a dot-atom and whose domain is
+// a host name. Quoted local parts, domain literals and non-ASCII local parts
+// are rejected. Surrounding whitespace is trimmed, as the request validator
+// does, the local part is lowercased and the domain is normalized by
+// NormalizeDomain, so that internationalized domains are stored in punycode.
+func NewEmail(email string) (Email, error) {
+	local, domain, ok := strings.Cut(strings.TrimSpace(email), "@")
+	if !ok || len(local) > maxEmailLocalLength || !isDotAtom(local) {
+		return "", ErrInvalidEmail
+	}
+
+	domain, err := NormalizeDomain(domain)
+	if err != nil {
+		return "", ErrInvalidEmail
+	}
+
+	address := strings.ToLower(local) + "@" + domain
+	if len(address) > maxEmailLength {
+		return "", ErrInvalidEmail
+	}
+
+	return Email(address), nil
+}
+
+func (e Email) String() string { return string(e) }
+
+// LocalPart returns the part of the address before the "@".
+func (e Email) LocalPart() string {
+	local, _, _ := strings.Cut(string(e), "@")
+
+	return local
+}
+
+// Domain returns the domain of the address, in punycode.
+func (e Email) Domain() string {
+	_, domain, _ := strings.Cut(string(e), "@")
+
+	return domain
+}
+
+// NormalizeDomain returns the lowercase ASCII form of a host name, with
+// internationalized labels converted to punycode per IDNA2008, so that
+// "Exämple.COM" becomes "xn--exmple-cua.com". The name must have at least
+// two labels of letters, digits and inner hyphens, and a top-level domain
+// that is not numeric.
+func NormalizeDomain(domain string) (string, error) {
+	ascii, err := idna.Lookup.ToASCII(domain)
+	if err != nil {
+		return "", ErrInvalidDomain
+	}
+
+	ascii = strings.ToLower(ascii)
+
+	labels := strings.Split(ascii, ".")
+	if len(ascii) > maxDomainLength || len(labels) < 2 {
+		return "", ErrInvalidDomain
+	}
+
+	for _, label := range labels {
+		if !isHostLabel(label) {
+			return "", ErrInvalidDomain
+		}
+	}
+
+	if strings.Trim(labels[len(labels)-1], "0123456789") == "" {
+		return "", ErrInvalidDomain
+	}
+
+	return ascii, nil
+}
+
+// isDotAtom reports whether s is a dot-atom of RFC 5322: atoms of atext
+// separated by single dots.
+func isDotAtom(s string) bool {
+	for atom := range strings.SplitSeq(s, ".") {
+		if atom == "" {
+			return false
+		}
+
+		for _, r := range atom {
+			if !isAlphanumeric(r) && !strings.ContainsRune(atextSymbols, r) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// isHostLabel reports whether label is a label of a host name: 1 to 63
+// letters, digits and hyphens, not starting or ending with a hyphen.
+func isHostLabel(label string) bool {
+	if label == "" || len(label) > maxDomainLabelLength || label[0] == '-' || label[len(label)-1] == '-' {
+		return false
+	}
+
+	for _, r := range label {
+		if !isAlphanumeric(r) && r != '-' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isAlphanumeric reports whether r is an ASCII letter or digit.
+func isAlphanumeric(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9'
+}
@@ -4,8 +4,10 @@
 package entities
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"time"
 )
 
 // Domain errors for user entity.
@@ -18,6 +20,8 @@ var (
 	ErrInvalidLastName     = NewValidationError("last_name", "must not be empty")
 	ErrInvalidUserStatus   = NewValidationError("status", "must be a valid user status")
 	ErrInvalidUserRole     = NewValidationError("role", "must be a valid user role")
+	ErrInvalidCursor       = NewValidationError("cursor", "must be a cursor returned with a previous page")
+	ErrInvalidCohort       = NewValidationError("interval", "must be day, week or month")
 
 	// ErrUserNotFound is returned when a user is not found.
 	ErrUserNotFound           = NewNotFoundError("user", "user not found")
@@ -27,10 +31,155 @@ var (
 	ErrAccountInactive        = NewAuthorizationError("account inactive")
 	ErrInsufficientPrivileges = NewAuthorizationError("insufficient privileges")
 
+	// ErrAccountDeleting is returned for logging in to an account whose
+	// deletion is pending.
+	ErrAccountDeleting = NewAuthorizationError("account deletion is pending")
+
+	// ErrImpersonationForbidden is returned for impersonating oneself or another admin.
+	ErrImpersonationForbidden = NewAuthorizationError("impersonation forbidden")
+	// ErrModerationForbidden is returned for moderating oneself or a user
+	// whose role is not below one's own.
+	ErrModerationForbidden = NewAuthorizationError("moderation forbidden")
+	// ErrNotImpersonating is returned for ending an impersonation in a regular session.
+	ErrNotImpersonating = NewValidationError("session", "is not an impersonation")
+
+	// ErrInvalidReference is returned when a write references a record that does not exist.
+	ErrInvalidReference = NewValidationError("reference", "must reference an existing record")
+
 	// ErrSessionNotFound is returned when a session is not found.
 	ErrSessionNotFound     = NewNotFoundError("session", "session not found")
 	ErrSessionExpired      = NewAuthenticationError("session expired")
 	ErrInvalidSessionToken = NewAuthenticationError("invalid session token")
+	// ErrSessionConflict is returned when a session was rotated or revoked
+	// since it was read.
+	ErrSessionConflict = NewConflictError("session", "session was changed concurrently")
+
+	// ErrInvalidRefreshToken is returned when a refresh token is unknown or no longer current.
+	ErrInvalidRefreshToken = NewAuthenticationError("invalid refresh token")
+	// ErrRefreshTokenReused is returned when a rotated refresh token is presented again.
+	ErrRefreshTokenReused = NewAuthenticationError("refresh token reused")
+	// ErrStepUpRequired is returned when a suspicious login must be verified before it succeeds.
+	ErrStepUpRequired = NewAuthenticationError("step-up verification required")
+
+	// ErrOrganizationNotFound is returned when an organization is not found.
+	ErrOrganizationNotFound      = NewNotFoundError("organization", "organization not found")
+	ErrOrganizationAlreadyExists = NewConflictError("organization", "organization already exists")
+	ErrInvalidOrganizationName   = NewValidationError("name", "must be 1-100 characters")
+	ErrInvalidOrganizationSlug   = NewValidationError("slug", "must be 3-50 lowercase letters, digits or hyphens")
+
+	// ErrMembershipNotFound is returned when a user is not a member of an organization.
+	ErrMembershipNotFound      = NewNotFoundError("membership", "membership not found")
+	ErrMembershipAlreadyExists = NewConflictError("membership", "membership already exists")
+	ErrInvitationNotPending    = NewValidationError("membership", "invitation is not pending")
+	ErrInvalidMembershipStatus = NewValidationError("status", "must be a valid membership status")
+	// ErrLastOrganizationAdmin is returned when the only admin of an organization would be removed or demoted.
+	ErrLastOrganizationAdmin = NewConflictError("membership", "organization must keep an admin")
+
+	// ErrIdentityNotFound is returned when no user is linked to an external identity.
+	ErrIdentityNotFound        = NewNotFoundError("identity", "identity not found")
+	ErrIdentityAlreadyLinked   = NewConflictError("identity", "identity already linked")
+	ErrInvalidIdentityProvider = NewValidationError("provider", "must be 1-50 lowercase letters, digits or hyphens")
+	ErrInvalidIdentitySubject  = NewValidationError("subject", "must not be empty")
+	// ErrLastLoginMethod is returned when unlinking would leave a user unable to sign in.
+	ErrLastLoginMethod = NewConflictError("identity", "user must keep a way to sign in")
+
+	// ErrIdentityChangeNotFound is returned for an unknown email or username change token.
+	ErrIdentityChangeNotFound   = NewNotFoundError("identity_change", "identity change not found")
+	ErrIdentityChangeExpired    = NewValidationError("token", "has expired")
+	ErrIdentityChangeNotPending = NewConflictError("identity_change", "identity change is not pending")
+	ErrIdentityChangeNotApplied = NewConflictError("identity_change", "identity change is not applied")
+	// ErrIdentityChangeSuperseded is returned when the email address or username
+	// was changed again since the change was made.
+	ErrIdentityChangeSuperseded = NewConflictError("identity_change", "a later change was made")
+	// ErrIdentityUnchanged is returned for changing an email address or username to itself.
+	ErrIdentityUnchanged = NewValidationError("value", "must differ from the current one")
+
+	// ErrModerationActionNotFound is returned when a moderation action is not found.
+	ErrModerationActionNotFound  = NewNotFoundError("moderation_action", "moderation action not found")
+	ErrModerationActionLifted    = NewConflictError("moderation_action", "moderation action was lifted")
+	ErrInvalidModerationReason   = NewValidationError("reason", "must be 1-500 characters")
+	ErrInvalidSuspensionDuration = NewValidationError("duration", "must not be negative")
+	// ErrAlreadySuspended is returned for suspending a user with a suspension in effect.
+	ErrAlreadySuspended = NewConflictError("moderation_action", "user is already suspended")
+	ErrNotSuspended     = NewNotFoundError("moderation_action", "user has no suspension in effect")
+
+	// ErrAppealNotFound is returned when an appeal is not found.
+	ErrAppealNotFound = NewNotFoundError("appeal", "appeal not found")
+	// ErrAppealExists is returned for appealing a suspension that was appealed before.
+	ErrAppealExists           = NewConflictError("appeal", "suspension was already appealed")
+	ErrAppealNotPending       = NewConflictError("appeal", "appeal was already decided")
+	ErrNotAppealable          = NewConflictError("appeal", "only suspensions in effect can be appealed")
+	ErrInvalidAppealStatement = NewValidationError("statement", "must be 1-2000 characters")
+	ErrInvalidAppealResponse  = NewValidationError("response", "must be at most 2000 characters")
+
+	// ErrAccountDeletionNotFound is returned when no deletion of an account is pending.
+	ErrAccountDeletionNotFound = NewNotFoundError("account_deletion", "no account deletion is pending")
+	// ErrAccountDeletionPending is returned for requesting the deletion of an
+	// account whose deletion is pending already.
+	ErrAccountDeletionPending = NewConflictError("account_deletion", "account deletion is already pending")
+
+	// ErrJobNotFound is returned when a job is not found.
+	ErrJobNotFound       = NewNotFoundError("job", "job not found")
+	ErrInvalidJobName    = NewValidationError("name", "must be 1-100 characters")
+	ErrInvalidJobPayload = NewValidationError("payload", "must be valid JSON")
+	// ErrJobLeaseLost is returned when finishing a job another worker has claimed since.
+	ErrJobLeaseLost = NewConflictError("job", "job lease lost")
+
+	// ErrIdempotencyKeyNotFound is returned when no write is recorded under an idempotency key.
+	ErrIdempotencyKeyNotFound = NewNotFoundError("idempotency_key", "idempotency key not found")
+	ErrIdempotencyKeyExists   = NewConflictError("idempotency_key", "idempotency key already recorded")
+	ErrInvalidIdempotencyKey  = NewValidationError("idempotency_key", "must be 1-255 printable ASCII characters")
+	// ErrIdempotencyKeyMismatch is returned when a key is reused for a different request.
+	ErrIdempotencyKeyMismatch = NewConflictError("idempotency_key", "idempotency key used for a different request")
+	// ErrIdempotentRequestInProgress is returned while the first write with a key is still running.
+	ErrIdempotentRequestInProgress = NewConflictError("idempotency_key", "request with idempotency key in progress")
+
+	// ErrNotificationPreferenceNotFound is returned when a user has no stored preference for a channel and topic.
+	ErrNotificationPreferenceNotFound = NewNotFoundError("notification_preference", "notification preference not found")
+	ErrInvalidNotificationChannel     = NewValidationError("channel", "must be email, webhook or in_app")
+	ErrInvalidNotificationTopic       = NewValidationError("topic", "must be security, account or organization")
+	ErrInvalidNotificationTarget      = NewValidationError("target", "must be an http(s) URL for webhooks and empty otherwise")
+
+	ErrUnknownPreference      = NewValidationError("preference", "no preference is defined with this key")
+	ErrInvalidPreferenceValue = NewValidationError("preference", "value does not match the preference definition")
+
+	// ErrInvalidAvatar is returned for an avatar upload that is not a supported image.
+	ErrInvalidAvatar  = NewValidationError("avatar", "must be a JPEG, PNG, GIF or WebP image")
+	ErrAvatarTooLarge = NewValidationError("avatar", "must be at most 5 MiB and 4096x4096 pixels")
+
+	// ErrUserStreamConflict is returned when the event stream of a user was
+	// appended to since it was loaded.
+	ErrUserStreamConflict   = NewConflictError("user_events", "user was changed concurrently")
+	ErrUserSnapshotNotFound = NewNotFoundError("user_snapshot", "user snapshot not found")
+	ErrInvalidUserEvent     = NewValidationError("event", "must be a user event that applies to the user")
+
+	// ErrInvalidActivityKind is returned for an activity entry of an unknown kind.
+	ErrInvalidActivityKind    = NewValidationError("kind", "must be sign_in, security, profile, account or organization")
+	ErrInvalidActivitySummary = NewValidationError("summary", "must be 1-255 characters")
+
+	// ErrInvalidFeatureFlagName is returned for a feature flag without a name of at most 100 characters.
+	ErrInvalidFeatureFlagName = NewValidationError("name", "must be 1-100 characters")
+	ErrInvalidServiceSwitch   = NewValidationError("switch", "must be a defined service switch")
+
+	// ErrReadOnlyMode is returned for changes while the service is in read-only mode.
+	ErrReadOnlyMode         = NewUnavailableError("writes", "service is in read-only mode")
+	ErrRegistrationDisabled = NewUnavailableError("registration", "registration is disabled")
+	ErrLoginDisabled        = NewUnavailableError("login", "sign-in is disabled")
+)
+
+// Error codes classify domain errors for the adapters. They are the codes of
+// pkg/errors, which maps them to HTTP and gRPC statuses; the domain does not
+// import it.
+const (
+	CodeValidationFailed = "VALIDATION_FAILED"
+	CodeNotFound         = "NOT_FOUND"
+	CodeConflict         = "RESOURCE_CONFLICT"
+	CodeUnauthorized     = "UNAUTHORIZED"
+	CodeForbidden        = "FORBIDDEN"
+	CodeRateLimited      = "RATE_LIMITED"
+	CodeTimeout          = "TIMEOUT"
+	CodeUnavailable      = "UNAVAILABLE"
+	CodeInternal         = "INTERNAL_ERROR"
 )
 
 // ValidationError represents a field validation error.
@@ -51,6 +200,18 @@ func (e *ValidationError) Error() string {
 	return fmt.Sprintf("validation error on field '%s': %s", e.Field, e.Message)
 }
 
+// ErrorCode returns CodeValidationFailed.
+func (e *ValidationError) ErrorCode() string {
+	return CodeValidationFailed
+}
+
+// Is reports whether target is a ValidationError of the same field and message.
+func (e *ValidationError) Is(target error) bool {
+	t, ok := target.(*ValidationError)
+
+	return ok && *t == *e
+}
+
 // ResourceError represents a resource-level error with resource and message.
 type ResourceError struct {
 	Resource string `json:"resource"`
@@ -78,6 +239,18 @@ func (e *NotFoundError) Error() string {
 	return e.ResourceError.Error()
 }
 
+// ErrorCode returns CodeNotFound.
+func (e *NotFoundError) ErrorCode() string {
+	return CodeNotFound
+}
+
+// Is reports whether target is a NotFoundError of the same resource and message.
+func (e *NotFoundError) Is(target error) bool {
+	t, ok := target.(*NotFoundError)
+
+	return ok && t.ResourceError == e.ResourceError
+}
+
 // ConflictError represents a resource conflict error.
 type ConflictError struct {
 	ResourceError
@@ -94,6 +267,18 @@ func (e *ConflictError) Error() string {
 	return e.ResourceError.Error()
 }
 
+// ErrorCode returns CodeConflict.
+func (e *ConflictError) ErrorCode() string {
+	return CodeConflict
+}
+
+// Is reports whether target is a ConflictError of the same resource and message.
+func (e *ConflictError) Is(target error) bool {
+	t, ok := target.(*ConflictError)
+
+	return ok && t.ResourceError == e.ResourceError
+}
+
 // AuthenticationError represents an authentication failure.
 type AuthenticationError struct {
 	Message string `json:"message"`
@@ -110,6 +295,18 @@ func (e *AuthenticationError) Error() string {
 	return "authentication error: " + e.Message
 }
 
+// ErrorCode returns CodeUnauthorized.
+func (e *AuthenticationError) ErrorCode() string {
+	return CodeUnauthorized
+}
+
+// Is reports whether target is an AuthenticationError with the same message.
+func (e *AuthenticationError) Is(target error) bool {
+	t, ok := target.(*AuthenticationError)
+
+	return ok && t.Message == e.Message
+}
+
 // AuthorizationError represents an authorization failure.
 type AuthorizationError struct {
 	Message string `json:"message"`
@@ -126,6 +323,133 @@ func (e *AuthorizationError) Error() string {
 	return "authorization error: " + e.Message
 }
 
+// ErrorCode returns CodeForbidden.
+func (e *AuthorizationError) ErrorCode() string {
+	return CodeForbidden
+}
+
+// Is reports whether target is an AuthorizationError with the same message.
+func (e *AuthorizationError) Is(target error) bool {
+	t, ok := target.(*AuthorizationError)
+
+	return ok && t.Message == e.Message
+}
+
+// RateLimitError represents a request rejected because too many were made.
+type RateLimitError struct {
+	Message string `json:"message"`
+	// RetryAfter is how long to wait before the next request is allowed.
+	RetryAfter time.Duration `json:"retryAfter"`
+}
+
+// NewRateLimitError creates a new RateLimitError with message and retry delay.
+func NewRateLimitError(message string, retryAfter time.Duration) *RateLimitError {
+	return &RateLimitError{
+		Message:    message,
+		RetryAfter: retryAfter,
+	}
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("rate limit error: %s, retry after %v", e.Message, e.RetryAfter.Round(time.Millisecond))
+}
+
+// ErrorCode returns CodeRateLimited.
+func (e *RateLimitError) ErrorCode() string {
+	return CodeRateLimited
+}
+
+// Is reports whether target is a RateLimitError with the same message,
+// whatever its retry delay.
+func (e *RateLimitError) Is(target error) bool {
+	t, ok := target.(*RateLimitError)
+
+	return ok && t.Message == e.Message
+}
+
+// TimeoutError represents an operation that did not finish before its
+// deadline. It matches context.DeadlineExceeded with errors.Is.
+type TimeoutError struct {
+	Operation string `json:"operation"`
+	// Timeout is the time the operation was given; zero when the deadline
+	// was set by the caller.
+	Timeout time.Duration `json:"timeout"`
+	Cause   error         `json:"-"`
+}
+
+// NewTimeoutError creates a new TimeoutError for operation with its timeout
+// and the error it failed with.
+func NewTimeoutError(operation string, timeout time.Duration, cause error) *TimeoutError {
+	return &TimeoutError{
+		Operation: operation,
+		Timeout:   timeout,
+		Cause:     cause,
+	}
+}
+
+func (e *TimeoutError) Error() string {
+	if e.Timeout > 0 {
+		return fmt.Sprintf("timeout error: %s did not finish within %v", e.Operation, e.Timeout)
+	}
+
+	return fmt.Sprintf("timeout error: %s did not finish before its deadline", e.Operation)
+}
+
+// Unwrap returns context.DeadlineExceeded and the cause, as drivers do not
+// always wrap the context error they were interrupted by.
+func (e *TimeoutError) Unwrap() []error {
+	if e.Cause == nil {
+		return []error{context.DeadlineExceeded}
+	}
+
+	return []error{context.DeadlineExceeded, e.Cause}
+}
+
+// ErrorCode returns CodeTimeout.
+func (e *TimeoutError) ErrorCode() string {
+	return CodeTimeout
+}
+
+// Is reports whether target is a TimeoutError of the same operation,
+// whatever its timeout.
+func (e *TimeoutError) Is(target error) bool {
+	t, ok := target.(*TimeoutError)
+
+	return ok && t.Operation == e.Operation
+}
+
+// UnavailableError represents a feature that is switched off, e.g. during
+// maintenance. Unlike an AuthorizationError, it holds for every caller and
+// passes once the feature is switched on again.
+type UnavailableError struct {
+	Feature string `json:"feature"`
+	Message string `json:"message"`
+}
+
+// NewUnavailableError creates a new UnavailableError for feature with a message.
+func NewUnavailableError(feature, message string) *UnavailableError {
+	return &UnavailableError{
+		Feature: feature,
+		Message: message,
+	}
+}
+
+func (e *UnavailableError) Error() string {
+	return fmt.Sprintf("unavailable error: %s: %s", e.Feature, e.Message)
+}
+
+// ErrorCode returns CodeUnavailable.
+func (e *UnavailableError) ErrorCode() string {
+	return CodeUnavailable
+}
+
+// Is reports whether target is an UnavailableError of the same feature and message.
+func (e *UnavailableError) Is(target error) bool {
+	t, ok := target.(*UnavailableError)
+
+	return ok && *t == *e
+}
+
 // InternalError represents an internal server error.
 type InternalError struct {
 	Message string `json:"message"`
@@ -152,6 +476,11 @@ func (e *InternalError) Unwrap() error {
 	return e.Cause
 }
 
+// ErrorCode returns CodeInternal.
+func (e *InternalError) ErrorCode() string {
+	return CodeInternal
+}
+
 // is[T any] is a generic helper that checks if err is of type T.
 func is[T any](err error, target *T) bool {
 	if err == nil {
@@ -196,6 +525,27 @@ func IsUnauthorizedError(err error) bool {
 	return is(err, &aze)
 }
 
+// IsRateLimitError checks if an error is a RateLimitError.
+func IsRateLimitError(err error) bool {
+	var rle *RateLimitError
+
+	return is(err, &rle)
+}
+
+// IsTimeoutError checks if an error is a TimeoutError.
+func IsTimeoutError(err error) bool {
+	var te *TimeoutError
+
+	return is(err, &te)
+}
+
+// IsUnavailableError checks if an error is an UnavailableError.
+func IsUnavailableError(err error) bool {
+	var ue *UnavailableError
+
+	return is(err, &ue)
+}
+
 // IsInternalError checks if an error is an InternalError.
 func IsInternalError(err error) bool {
 	var ie *InternalError
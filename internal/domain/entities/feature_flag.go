@@ -0,0 +1,73 @@
+package entities
+
+import (
+	"strings"
+	"time"
+)
+
+// maxFeatureFlagNameLength bounds the name of a feature flag.
+const maxFeatureFlagNameLength = 100
+
+// FeatureFlag is a named switch that changes the behavior of running
+// instances without a deploy, such as a maintenance mode.
+type FeatureFlag struct {
+	Name      string
+	Enabled   bool
+	UpdatedAt time.Time
+}
+
+// NewFeatureFlag creates the flag name set to enabled at updatedAt.
+func NewFeatureFlag(name string, enabled bool, updatedAt time.Time) (*FeatureFlag, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > maxFeatureFlagNameLength {
+		return nil, ErrInvalidFeatureFlagName
+	}
+
+	return &FeatureFlag{Name: name, Enabled: enabled, UpdatedAt: updatedAt}, nil
+}
+
+// ServiceSwitch is a feature flag that degrades the service gracefully, e.g.
+// during maintenance. Switches are off unless turned on.
+type ServiceSwitch string
+
+// Service switches.
+const (
+	// SwitchReadOnly rejects changes to users with ErrReadOnlyMode.
+	SwitchReadOnly ServiceSwitch = "maintenance.read_only"
+	// SwitchRegistrationDisabled rejects new users with ErrRegistrationDisabled.
+	SwitchRegistrationDisabled ServiceSwitch = "maintenance.registration_disabled"
+	// SwitchLoginDisabled rejects sign-ins with ErrLoginDisabled.
+	SwitchLoginDisabled ServiceSwitch = "maintenance.login_disabled"
+)
+
+// ServiceSwitches returns every service switch.
+func ServiceSwitches() []ServiceSwitch {
+	return []ServiceSwitch{SwitchReadOnly, SwitchRegistrationDisabled, SwitchLoginDisabled}
+}
+
+// IsValid returns true if the switch is one of the defined switches.
+func (s ServiceSwitch) IsValid() bool {
+	switch s {
+	case SwitchReadOnly, SwitchRegistrationDisabled, SwitchLoginDisabled:
+		return true
+	default:
+		return false
+	}
+}
+
+// String implements fmt.Stringer for ServiceSwitch.
+func (s ServiceSwitch) String() string { return string(s) }
+
+// Err returns the error operations fail with while the switch is on.
+func (s ServiceSwitch) Err() error {
+	switch s {
+	case SwitchReadOnly:
+		return ErrReadOnlyMode
+	case SwitchRegistrationDisabled:
+		return ErrRegistrationDisabled
+	case SwitchLoginDisabled:
+		return ErrLoginDisabled
+	default:
+		return ErrInvalidServiceSwitch
+	}
+}
@@ -0,0 +1,99 @@
+package entities
+
+import (
+	"slices"
+	"strings"
+)
+
+// GeoLocation is the coarse location of an IP address. It never holds
+// coordinates, so it can be stored with sessions without tracking users.
+type GeoLocation struct {
+	// Country is the ISO 3166-1 alpha-2 code, such as "DE".
+	Country string `json:"country,omitempty"`
+	// Region is the ISO 3166-2 subdivision code without the country, such as "BE".
+	Region string `json:"region,omitempty"`
+	City   string `json:"city,omitempty"`
+}
+
+// IsZero returns true if the location is unknown.
+func (l GeoLocation) IsZero() bool {
+	return l == GeoLocation{}
+}
+
+// String renders the location from most to least specific, e.g. "Berlin, BE, DE".
+func (l GeoLocation) String() string {
+	var parts []string
+
+	for _, part := range []string{l.City, l.Region, l.Country} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// LoginRiskReason says why a login is suspicious.
+type LoginRiskReason string
+
+// Reasons a login is suspicious.
+const (
+	// LoginRiskNewCountry is a login from a country the user never signed in from.
+	LoginRiskNewCountry LoginRiskReason = "new_country"
+	// LoginRiskNewDevice is a login with a user agent the user never signed in with.
+	LoginRiskNewDevice LoginRiskReason = "new_device"
+)
+
+// String implements fmt.Stringer for LoginRiskReason.
+func (r LoginRiskReason) String() string { return string(r) }
+
+// LoginRisk is the assessment of a login against the user's earlier sessions.
+type LoginRisk struct {
+	Location GeoLocation
+	// Reasons is empty for logins that are not suspicious.
+	Reasons []LoginRiskReason
+}
+
+// IsSuspicious returns true if the login has a reason to be suspicious.
+func (r LoginRisk) IsSuspicious() bool {
+	return len(r.Reasons) > 0
+}
+
+// AssessLoginRisk compares a new session with the earlier sessions of its
+// user. A country or user agent is only new if the earlier sessions have
+// some, so the first login and logins without a known location are never
+// suspicious.
+func AssessLoginRisk(session *UserSession, previous []*UserSession) LoginRisk {
+	risk := LoginRisk{Location: session.Location()}
+
+	var countries, agents []string
+
+	for _, prev := range previous {
+		if prev.ID() == session.ID() {
+			continue
+		}
+
+		if country := prev.Location().Country; country != "" {
+			countries = append(countries, country)
+		}
+
+		if agent := prev.UserAgent(); agent != "" {
+			agents = append(agents, agent)
+		}
+	}
+
+	if isNew(risk.Location.Country, countries) {
+		risk.Reasons = append(risk.Reasons, LoginRiskNewCountry)
+	}
+
+	if isNew(session.UserAgent(), agents) {
+		risk.Reasons = append(risk.Reasons, LoginRiskNewDevice)
+	}
+
+	return risk
+}
+
+// isNew returns true if value is known and seen is not empty but lacks it.
+func isNew(value string, seen []string) bool {
+	return value != "" && len(seen) > 0 && !slices.Contains(seen, value)
+}
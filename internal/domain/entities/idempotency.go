@@ -0,0 +1,81 @@
+package entities
+
+import (
+	"regexp"
+	"time"
+)
+
+// IdempotencyKey is chosen by a client to identify one logical write, such
+// as the Idempotency-Key header of a request. Retries of the write carry the
+// same key, so that it is applied once.
+type IdempotencyKey string
+
+var idempotencyKeyPattern = regexp.MustCompile(`^[\x21-\x7e]{1,255}$`)
+
+// NewIdempotencyKey creates a validated idempotency key of 1-255 printable
+// ASCII characters without spaces.
+func NewIdempotencyKey(key string) (IdempotencyKey, error) {
+	if !idempotencyKeyPattern.MatchString(key) {
+		return "", ErrInvalidIdempotencyKey
+	}
+
+	return IdempotencyKey(key), nil
+}
+
+// String implements fmt.Stringer for IdempotencyKey.
+func (k IdempotencyKey) String() string { return string(k) }
+
+// IdempotencyRecord is the outcome of the write made with an idempotency key.
+// A record is pending while the write runs and completed once its response
+// is stored; duplicates of a completed write get the stored response.
+type IdempotencyRecord struct {
+	Key IdempotencyKey
+	// Operation names the write the key was used for, such as "CreateUser".
+	Operation string
+	// RequestHash fingerprints the request, so that a key reused for a
+	// different request is detected.
+	RequestHash string
+	// Response is the JSON encoded result of a completed write.
+	Response    []byte
+	CreatedAt   time.Time
+	CompletedAt *time.Time
+	// ExpiresAt is when the key may be used again. A pending record expires
+	// after a short lock, so that the key of a write that crashed is freed.
+	ExpiresAt time.Time
+}
+
+// NewIdempotencyRecord creates a pending record of key that expires after lock.
+func NewIdempotencyRecord(key IdempotencyKey, operation, requestHash string, lock time.Duration) *IdempotencyRecord {
+	now := time.Now().UTC()
+
+	return &IdempotencyRecord{
+		Key:         key,
+		Operation:   operation,
+		RequestHash: requestHash,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(lock),
+	}
+}
+
+// IsCompleted returns true if the response of the write is stored.
+func (r *IdempotencyRecord) IsCompleted() bool {
+	return r.CompletedAt != nil
+}
+
+// IsExpired returns true if the key may be used again at now.
+func (r *IdempotencyRecord) IsExpired(now time.Time) bool {
+	return !now.Before(r.ExpiresAt)
+}
+
+// Matches returns true if the record was made for the same request.
+func (r *IdempotencyRecord) Matches(operation, requestHash string) bool {
+	return r.Operation == operation && r.RequestHash == requestHash
+}
+
+// Complete stores the response and keeps the record for ttl.
+func (r *IdempotencyRecord) Complete(response []byte, ttl time.Duration) {
+	now := time.Now().UTC()
+	r.Response = response
+	r.CompletedAt = &now
+	r.ExpiresAt = now.Add(ttl)
+}
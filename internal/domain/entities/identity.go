@@ -0,0 +1,76 @@
+package entities
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// UserIdentityID is the identifier of a linked external identity.
+type UserIdentityID int64
+
+// Int64 returns the ID as int64.
+func (id UserIdentityID) Int64() int64 { return int64(id) }
+
+// IdentityProvider names an external identity provider such as "google" or "github".
+type IdentityProvider string
+
+var identityProviderPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,49}$`)
+
+// NewIdentityProvider creates a validated, lowercased provider name.
+func NewIdentityProvider(name string) (IdentityProvider, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if !identityProviderPattern.MatchString(name) {
+		return "", ErrInvalidIdentityProvider
+	}
+
+	return IdentityProvider(name), nil
+}
+
+// String implements fmt.Stringer for IdentityProvider.
+func (p IdentityProvider) String() string { return string(p) }
+
+// UserIdentity links a local user to the subject of an external identity provider.
+// A provider subject is linked to at most one user, and a user has at most one
+// identity per provider.
+type UserIdentity struct {
+	ID       UserIdentityID
+	UserID   UserID
+	Provider IdentityProvider
+	// Subject is the provider's stable identifier for the account, never the email.
+	Subject string
+	// Email is the address the provider reported at the last login; informational only.
+	Email       string
+	LinkedAt    time.Time
+	LastLoginAt *time.Time
+}
+
+// NewUserIdentity links subject at provider to userID as of now.
+func NewUserIdentity(userID UserID, provider IdentityProvider, subject, email string) (*UserIdentity, error) {
+	if !identityProviderPattern.MatchString(provider.String()) {
+		return nil, ErrInvalidIdentityProvider
+	}
+
+	subject = strings.TrimSpace(subject)
+	if subject == "" {
+		return nil, ErrInvalidIdentitySubject
+	}
+
+	return &UserIdentity{
+		UserID:   userID,
+		Provider: provider,
+		Subject:  subject,
+		Email:    email,
+		LinkedAt: time.Now().UTC(),
+	}, nil
+}
+
+// RecordLogin records a sign-in through this identity.
+func (i *UserIdentity) RecordLogin(email string) {
+	now := time.Now().UTC()
+	i.LastLoginAt = &now
+
+	if email != "" {
+		i.Email = email
+	}
+}
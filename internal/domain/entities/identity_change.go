@@ -0,0 +1,165 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Lifetimes of identity change tokens.
+const (
+	// EmailChangeLifetime is how long a new email address can be confirmed.
+	EmailChangeLifetime = 24 * time.Hour
+	// IdentityChangeGracePeriod is how long an applied change can be reverted.
+	IdentityChangeGracePeriod = 7 * 24 * time.Hour
+)
+
+// IdentityChangeID is the identifier of an identity change.
+type IdentityChangeID int64
+
+// Int64 returns the ID as int64.
+func (id IdentityChangeID) Int64() int64 { return int64(id) }
+
+// IdentityField names a field that identifies a user.
+type IdentityField string
+
+// Identity fields.
+const (
+	IdentityFieldEmail    IdentityField = "email"
+	IdentityFieldUsername IdentityField = "username"
+)
+
+// String implements fmt.Stringer for IdentityField.
+func (f IdentityField) String() string { return string(f) }
+
+// IdentityChangeStatus is the state of an identity change.
+type IdentityChangeStatus string
+
+// Identity change states.
+const (
+	// IdentityChangePending is an email change waiting for confirmation
+	// from the new address.
+	IdentityChangePending IdentityChangeStatus = "pending"
+	// IdentityChangeApplied is a change made to the user.
+	IdentityChangeApplied IdentityChangeStatus = "applied"
+	// IdentityChangeReverted is an applied change that was undone.
+	IdentityChangeReverted IdentityChangeStatus = "reverted"
+)
+
+// String implements fmt.Stringer for IdentityChangeStatus.
+func (s IdentityChangeStatus) String() string { return string(s) }
+
+// IdentityChangeToken is the secret that confirms a pending change or
+// reverts an applied one. It is emailed to the user and never shown elsewhere.
+type IdentityChangeToken uuid.UUID
+
+// NewIdentityChangeToken generates a new identity change token.
+func NewIdentityChangeToken() IdentityChangeToken {
+	return IdentityChangeToken(uuid.New())
+}
+
+// UUID returns the underlying uuid.UUID representation of the token.
+func (t IdentityChangeToken) UUID() uuid.UUID { return uuid.UUID(t) }
+func (t IdentityChangeToken) String() string  { return uuid.UUID(t).String() }
+
+// IdentityChange is an entry of the history of a user's email address and
+// username. Email changes start pending until the new address confirms
+// them; username changes are applied right away. Applied changes can be
+// reverted until their grace period ends, in case the account was taken over.
+type IdentityChange struct {
+	ID       IdentityChangeID
+	UserID   UserID
+	Field    IdentityField
+	OldValue string
+	NewValue string
+	Status   IdentityChangeStatus
+	// Token confirms the change while pending and reverts it once applied.
+	Token IdentityChangeToken
+	// ExpiresAt is when Token expires: the confirmation deadline of pending
+	// changes and the end of the grace period of applied ones.
+	ExpiresAt time.Time
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// NewEmailChange creates a pending change of a user's email address from
+// old to changed that must be confirmed within lifetime.
+func NewEmailChange(userID UserID, old, changed Email, lifetime time.Duration) (*IdentityChange, error) {
+	return newIdentityChange(userID, IdentityFieldEmail, old.String(), changed.String(),
+		IdentityChangePending, lifetime)
+}
+
+// NewUsernameChange creates an applied change of a user's username from old
+// to changed that can be reverted within grace.
+func NewUsernameChange(userID UserID, old, changed Username, grace time.Duration) (*IdentityChange, error) {
+	return newIdentityChange(userID, IdentityFieldUsername, old.String(), changed.String(),
+		IdentityChangeApplied, grace)
+}
+
+func newIdentityChange(
+	userID UserID,
+	field IdentityField,
+	old, changed string,
+	status IdentityChangeStatus,
+	lifetime time.Duration,
+) (*IdentityChange, error) {
+	if old == changed {
+		return nil, ErrIdentityUnchanged
+	}
+
+	now := time.Now().UTC()
+
+	return &IdentityChange{
+		UserID:    userID,
+		Field:     field,
+		OldValue:  old,
+		NewValue:  changed,
+		Status:    status,
+		Token:     NewIdentityChangeToken(),
+		ExpiresAt: now.Add(lifetime),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+// IsExpired returns true if the token of the change expired at now.
+func (c *IdentityChange) IsExpired(now time.Time) bool {
+	return !now.Before(c.ExpiresAt)
+}
+
+// Apply confirms a pending change. It issues a new token that reverts the
+// change within grace.
+func (c *IdentityChange) Apply(grace time.Duration) error {
+	if c.Status != IdentityChangePending {
+		return ErrIdentityChangeNotPending
+	}
+
+	now := time.Now().UTC()
+	if c.IsExpired(now) {
+		return ErrIdentityChangeExpired
+	}
+
+	c.Status = IdentityChangeApplied
+	c.Token = NewIdentityChangeToken()
+	c.ExpiresAt = now.Add(grace)
+	c.UpdatedAt = now
+
+	return nil
+}
+
+// Revert undoes an applied change within its grace period.
+func (c *IdentityChange) Revert() error {
+	if c.Status != IdentityChangeApplied {
+		return ErrIdentityChangeNotApplied
+	}
+
+	now := time.Now().UTC()
+	if c.IsExpired(now) {
+		return ErrIdentityChangeExpired
+	}
+
+	c.Status = IdentityChangeReverted
+	c.UpdatedAt = now
+
+	return nil
+}
@@ -0,0 +1,151 @@
+package entities
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// DefaultJobMaxAttempts is how often a job is tried unless enqueued with another limit.
+const DefaultJobMaxAttempts = 5
+
+// JobID is the identifier of a queued job.
+type JobID int64
+
+// Int64 returns the ID as int64.
+func (id JobID) Int64() int64 { return int64(id) }
+
+// JobStatus is the state of a job in the queue.
+type JobStatus string
+
+// Job states. Pending jobs wait for RunAt, running jobs are leased by a
+// worker, and done and failed jobs are finished.
+const (
+	JobStatusPending JobStatus = "pending"
+	JobStatusRunning JobStatus = "running"
+	JobStatusDone    JobStatus = "done"
+	JobStatusFailed  JobStatus = "failed"
+)
+
+// String implements fmt.Stringer for JobStatus.
+func (s JobStatus) String() string { return string(s) }
+
+// IsValid returns true if the job status is valid.
+func (s JobStatus) IsValid() bool {
+	switch s {
+	case JobStatusPending, JobStatusRunning, JobStatusDone, JobStatusFailed:
+		return true
+	default:
+		return false
+	}
+}
+
+// Job is a unit of background work run by the handler registered under Name.
+// Jobs are delivered at least once, so handlers must be idempotent.
+type Job struct {
+	ID   JobID
+	Name string
+	// Payload is the JSON input of the handler.
+	Payload []byte
+	Status  JobStatus
+	// Attempts counts the claims of the job, including a running one. It is
+	// the version that finishing a claim is checked against.
+	Attempts    int
+	MaxAttempts int
+	// RunAt is when a pending job becomes due.
+	RunAt time.Time
+	// LockedUntil is when the lease of a running job expires and another
+	// worker may claim it again.
+	LockedUntil *time.Time
+	LastError   string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// NewJob creates a pending job due at runAt, or now for the zero time.
+// A nil payload is stored as an empty JSON object and a non-positive
+// maxAttempts means DefaultJobMaxAttempts.
+func NewJob(name string, payload []byte, runAt time.Time, maxAttempts int) (*Job, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > 100 {
+		return nil, ErrInvalidJobName
+	}
+
+	if payload == nil {
+		payload = []byte("{}")
+	}
+
+	if !json.Valid(payload) {
+		return nil, ErrInvalidJobPayload
+	}
+
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultJobMaxAttempts
+	}
+
+	now := time.Now().UTC()
+	if runAt.IsZero() {
+		runAt = now
+	}
+
+	return &Job{
+		Name:        name,
+		Payload:     payload,
+		Status:      JobStatusPending,
+		MaxAttempts: maxAttempts,
+		RunAt:       runAt.UTC(),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}, nil
+}
+
+// IsDue returns true if a worker may claim the job at now: it is pending and
+// its RunAt has passed, or it is running and its lease has expired.
+func (j *Job) IsDue(now time.Time) bool {
+	switch j.Status {
+	case JobStatusPending:
+		return !j.RunAt.After(now)
+	case JobStatusRunning:
+		return j.LockedUntil != nil && j.LockedUntil.Before(now)
+	default:
+		return false
+	}
+}
+
+// CanRetry returns true if the job has attempts left after the current one.
+func (j *Job) CanRetry() bool {
+	return j.Attempts < j.MaxAttempts
+}
+
+// Claim leases the job to a worker until lockedUntil.
+func (j *Job) Claim(now, lockedUntil time.Time) {
+	j.Status = JobStatusRunning
+	j.Attempts++
+	j.LockedUntil = &lockedUntil
+	j.UpdatedAt = now
+}
+
+// Complete marks the job done.
+func (j *Job) Complete(now time.Time) {
+	j.Status = JobStatusDone
+	j.LockedUntil = nil
+	j.LastError = ""
+	j.UpdatedAt = now
+}
+
+// Reschedule returns the job to the queue, due again at runAt.
+func (j *Job) Reschedule(runAt time.Time, lastError string, now time.Time) {
+	j.Status = JobStatusPending
+	j.RunAt = runAt
+	j.LockedUntil = nil
+	j.LastError = lastError
+	j.UpdatedAt = now
+}
+
+// Fail marks the job failed for good.
+func (j *Job) Fail(lastError string, now time.Time) {
+	j.Status = JobStatusFailed
+	j.LockedUntil = nil
+	j.LastError = lastError
+	j.UpdatedAt = now
+}
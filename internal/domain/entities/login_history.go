@@ -0,0 +1,72 @@
+package entities
+
+import (
+	"net"
+	"time"
+)
+
+// LoginAttemptID is the identifier of a recorded login attempt.
+type LoginAttemptID int64
+
+// Int64 returns the ID as int64.
+func (id LoginAttemptID) Int64() int64 { return int64(id) }
+
+// LoginMethodPassword is the method of logins with email and password;
+// federated logins are named after their identity provider.
+const LoginMethodPassword = "password"
+
+// LoginFailure is why a login attempt failed.
+type LoginFailure string
+
+// Login failures; successful attempts have none.
+const (
+	LoginFailureNone               LoginFailure = ""
+	LoginFailureInvalidCredentials LoginFailure = "invalid_credentials"
+	LoginFailureInactiveAccount    LoginFailure = "inactive_account"
+	LoginFailureRateLimited        LoginFailure = "rate_limited"
+	LoginFailurePendingDeletion    LoginFailure = "pending_deletion"
+)
+
+// String implements fmt.Stringer for LoginFailure.
+func (f LoginFailure) String() string { return string(f) }
+
+// LoginAttempt is a recorded login, successful or not, for security pages
+// and abuse detection. Attempts are immutable once recorded.
+type LoginAttempt struct {
+	ID LoginAttemptID
+	// UserID is the account signed in to, or zero if no account matched.
+	UserID    UserID
+	IPAddress string
+	UserAgent string
+	Method    string
+	Failure   LoginFailure
+	CreatedAt time.Time
+}
+
+// NewLoginAttempt creates an attempt that happened now. IP addresses are
+// stored in canonical form, so attempts from one address are found together.
+func NewLoginAttempt(userID UserID, ipAddress, userAgent, method string, failure LoginFailure) *LoginAttempt {
+	return &LoginAttempt{
+		UserID:    userID,
+		IPAddress: CanonicalIPAddress(ipAddress),
+		UserAgent: userAgent,
+		Method:    method,
+		Failure:   failure,
+		CreatedAt: time.Now(),
+	}
+}
+
+// CanonicalIPAddress returns ipAddress in canonical form, or unchanged if
+// it is not an IP address.
+func CanonicalIPAddress(ipAddress string) string {
+	if ip := net.ParseIP(ipAddress); ip != nil {
+		return ip.String()
+	}
+
+	return ipAddress
+}
+
+// Succeeded returns true if the attempt started a session.
+func (a *LoginAttempt) Succeeded() bool {
+	return a.Failure == LoginFailureNone
+}
@@ -0,0 +1,152 @@
+package entities
+
+import (
+	"fmt"
+	"time"
+)
+
+// MembershipStatus is the state of a user's membership in an organization.
+type MembershipStatus string
+
+// Membership states.
+const (
+	MembershipStatusInvited MembershipStatus = "invited"
+	MembershipStatusActive  MembershipStatus = "active"
+)
+
+func (s MembershipStatus) String() string { return string(s) }
+
+// IsValid returns true if the membership status is valid.
+func (s MembershipStatus) IsValid() bool {
+	return s == MembershipStatusInvited || s == MembershipStatusActive
+}
+
+// Membership grants a user a role inside an organization.
+// The role is independent of the user's global role.
+type Membership struct {
+	organizationID OrganizationID
+	userID         UserID
+	role           UserRole
+	status         MembershipStatus
+	invitedBy      UserID
+	invitedAt      time.Time
+	joinedAt       *time.Time
+}
+
+// NewMembership creates an active membership, e.g. for an organization's founder.
+func NewMembership(orgID OrganizationID, userID UserID, role UserRole) (*Membership, error) {
+	if !role.IsValid() {
+		return nil, ErrInvalidUserRole
+	}
+
+	now := time.Now()
+
+	return &Membership{
+		organizationID: orgID,
+		userID:         userID,
+		role:           role,
+		status:         MembershipStatusActive,
+		invitedAt:      now,
+		joinedAt:       &now,
+	}, nil
+}
+
+// NewInvitation creates a pending membership that becomes active once the user joins.
+func NewInvitation(orgID OrganizationID, userID UserID, role UserRole, invitedBy UserID) (*Membership, error) {
+	if !role.IsValid() {
+		return nil, ErrInvalidUserRole
+	}
+
+	return &Membership{
+		organizationID: orgID,
+		userID:         userID,
+		role:           role,
+		status:         MembershipStatusInvited,
+		invitedBy:      invitedBy,
+		invitedAt:      time.Now(),
+	}, nil
+}
+
+// MembershipRecord holds every persisted field of a membership.
+type MembershipRecord struct {
+	OrganizationID OrganizationID
+	UserID         UserID
+	Role           UserRole
+	Status         MembershipStatus
+	InvitedBy      UserID
+	InvitedAt      time.Time
+	JoinedAt       *time.Time
+}
+
+// ReconstructMembership rebuilds a membership from persisted state.
+// It is intended for repository adapters.
+func ReconstructMembership(record MembershipRecord) (*Membership, error) {
+	if !record.Role.IsValid() {
+		return nil, fmt.Errorf("role=%v: %w", record.Role, ErrInvalidUserRole)
+	}
+
+	if !record.Status.IsValid() {
+		return nil, fmt.Errorf("status=%v: %w", record.Status, ErrInvalidMembershipStatus)
+	}
+
+	return &Membership{
+		organizationID: record.OrganizationID,
+		userID:         record.UserID,
+		role:           record.Role,
+		status:         record.Status,
+		invitedBy:      record.InvitedBy,
+		invitedAt:      record.InvitedAt,
+		joinedAt:       record.JoinedAt,
+	}, nil
+}
+
+// OrganizationID returns the organization the membership belongs to.
+func (m *Membership) OrganizationID() OrganizationID { return m.organizationID }
+
+// UserID returns the member.
+func (m *Membership) UserID() UserID { return m.userID }
+
+// Role returns the member's role inside the organization.
+func (m *Membership) Role() UserRole { return m.role }
+
+// Status returns whether the membership is pending or active.
+func (m *Membership) Status() MembershipStatus { return m.status }
+
+// InvitedBy returns who invited the member, or zero for founders.
+func (m *Membership) InvitedBy() UserID { return m.invitedBy }
+
+// InvitedAt returns when the membership was created.
+func (m *Membership) InvitedAt() time.Time { return m.invitedAt }
+
+// JoinedAt returns when the invitation was accepted, or nil while pending.
+func (m *Membership) JoinedAt() *time.Time { return m.joinedAt }
+
+// IsActive returns true once the user has joined.
+func (m *Membership) IsActive() bool { return m.status == MembershipStatusActive }
+
+// IsAdmin returns true if the member administers the organization.
+func (m *Membership) IsAdmin() bool { return m.IsActive() && m.role == UserRoleAdmin }
+
+// Accept activates a pending invitation.
+func (m *Membership) Accept() error {
+	if m.status != MembershipStatusInvited {
+		return ErrInvitationNotPending
+	}
+
+	now := time.Now()
+	m.status = MembershipStatusActive
+	m.joinedAt = &now
+
+	return nil
+}
+
+// ChangeRole changes the member's role inside the organization.
+func (m *Membership) ChangeRole(role UserRole) error {
+	if !role.IsValid() {
+		return ErrInvalidUserRole
+	}
+
+	m.role = role
+
+	return nil
+}
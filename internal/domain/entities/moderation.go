@@ -0,0 +1,206 @@
+package entities
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Limits of the texts of moderation actions and appeals.
+const (
+	MaxModerationReasonLength = 500
+	MaxAppealStatementLength  = 2000
+	MaxAppealResponseLength   = 2000
+)
+
+// ModerationActionID is the identifier of a moderation action.
+type ModerationActionID int64
+
+// Int64 returns the ID as int64.
+func (id ModerationActionID) Int64() int64 { return int64(id) }
+
+// ModerationKind names a kind of moderation action.
+type ModerationKind string
+
+// Moderation action kinds.
+const (
+	// ModerationFlag marks a user for review by moderators without
+	// restricting them.
+	ModerationFlag ModerationKind = "flag"
+	// ModerationSuspension suspends a user until it is lifted or ends.
+	ModerationSuspension ModerationKind = "suspension"
+)
+
+// String implements fmt.Stringer for ModerationKind.
+func (k ModerationKind) String() string { return string(k) }
+
+// ModerationAction is an action a moderator took against a user. It stays
+// in effect until it is lifted; suspensions with an end are lifted once
+// they lapse.
+type ModerationAction struct {
+	ID          ModerationActionID
+	UserID      UserID
+	Kind        ModerationKind
+	Reason      string
+	ModeratorID UserID
+	// EndsAt is when a suspension lapses. It is nil for flags and for
+	// suspensions that last until they are lifted.
+	EndsAt *time.Time
+	// LiftedAt is when the action was lifted, nil while it is in effect.
+	LiftedAt *time.Time
+	// LiftedBy is who lifted the action, zero if it lapsed.
+	LiftedBy  UserID
+	CreatedAt time.Time
+}
+
+// NewFlag creates a flag of a user raised by moderatorID for reason.
+func NewFlag(userID, moderatorID UserID, reason string) (*ModerationAction, error) {
+	return newModerationAction(userID, moderatorID, ModerationFlag, reason, nil)
+}
+
+// NewSuspension creates a suspension of a user by moderatorID for reason
+// that lapses after duration, or lasts until it is lifted if duration is
+// zero.
+func NewSuspension(userID, moderatorID UserID, reason string, duration time.Duration) (*ModerationAction, error) {
+	if duration < 0 {
+		return nil, ErrInvalidSuspensionDuration
+	}
+
+	var endsAt *time.Time
+
+	if duration > 0 {
+		end := time.Now().UTC().Add(duration)
+		endsAt = &end
+	}
+
+	return newModerationAction(userID, moderatorID, ModerationSuspension, reason, endsAt)
+}
+
+func newModerationAction(
+	userID, moderatorID UserID,
+	kind ModerationKind,
+	reason string,
+	endsAt *time.Time,
+) (*ModerationAction, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" || utf8.RuneCountInString(reason) > MaxModerationReasonLength {
+		return nil, ErrInvalidModerationReason
+	}
+
+	return &ModerationAction{
+		UserID:      userID,
+		Kind:        kind,
+		Reason:      reason,
+		ModeratorID: moderatorID,
+		EndsAt:      endsAt,
+		CreatedAt:   time.Now().UTC(),
+	}, nil
+}
+
+// IsActive returns true while the action has not been lifted.
+func (a *ModerationAction) IsActive() bool {
+	return a.LiftedAt == nil
+}
+
+// HasLapsed returns true if the action is a suspension that ended at now.
+func (a *ModerationAction) HasLapsed(now time.Time) bool {
+	return a.EndsAt != nil && !now.Before(*a.EndsAt)
+}
+
+// Lift ends the action on behalf of liftedBy, which is zero for lapsed
+// suspensions.
+func (a *ModerationAction) Lift(liftedBy UserID) error {
+	if !a.IsActive() {
+		return ErrModerationActionLifted
+	}
+
+	now := time.Now().UTC()
+	a.LiftedAt = &now
+	a.LiftedBy = liftedBy
+
+	return nil
+}
+
+// AppealID is the identifier of an appeal.
+type AppealID int64
+
+// Int64 returns the ID as int64.
+func (id AppealID) Int64() int64 { return int64(id) }
+
+// AppealStatus is the state of an appeal.
+type AppealStatus string
+
+// Appeal states.
+const (
+	// AppealPending is an appeal waiting for a moderator's decision.
+	AppealPending AppealStatus = "pending"
+	// AppealGranted is an appeal that lifted its suspension.
+	AppealGranted AppealStatus = "granted"
+	// AppealDenied is an appeal that left its suspension in effect.
+	AppealDenied AppealStatus = "denied"
+)
+
+// String implements fmt.Stringer for AppealStatus.
+func (s AppealStatus) String() string { return string(s) }
+
+// Appeal is a user's request to lift their suspension. Each suspension can
+// be appealed once.
+type Appeal struct {
+	ID        AppealID
+	ActionID  ModerationActionID
+	UserID    UserID
+	Statement string
+	Status    AppealStatus
+	// ReviewerID and Response are set once the appeal is decided.
+	ReviewerID UserID
+	Response   string
+	CreatedAt  time.Time
+	DecidedAt  *time.Time
+}
+
+// NewAppeal creates a pending appeal of the suspension action with the
+// user's statement. Only suspensions in effect can be appealed.
+func NewAppeal(action *ModerationAction, statement string) (*Appeal, error) {
+	if action.Kind != ModerationSuspension || !action.IsActive() {
+		return nil, ErrNotAppealable
+	}
+
+	statement = strings.TrimSpace(statement)
+	if statement == "" || utf8.RuneCountInString(statement) > MaxAppealStatementLength {
+		return nil, ErrInvalidAppealStatement
+	}
+
+	return &Appeal{
+		ActionID:  action.ID,
+		UserID:    action.UserID,
+		Statement: statement,
+		Status:    AppealPending,
+		CreatedAt: time.Now().UTC(),
+	}, nil
+}
+
+// Decide grants or denies a pending appeal on behalf of reviewerID with a
+// response to the user.
+func (a *Appeal) Decide(reviewerID UserID, grant bool, response string) error {
+	if a.Status != AppealPending {
+		return ErrAppealNotPending
+	}
+
+	response = strings.TrimSpace(response)
+	if utf8.RuneCountInString(response) > MaxAppealResponseLength {
+		return ErrInvalidAppealResponse
+	}
+
+	now := time.Now().UTC()
+
+	a.Status = AppealDenied
+	if grant {
+		a.Status = AppealGranted
+	}
+
+	a.ReviewerID = reviewerID
+	a.Response = response
+	a.DecidedAt = &now
+
+	return nil
+}
@@ -0,0 +1,172 @@
+package entities
+
+import (
+	"net/url"
+	"slices"
+	"time"
+)
+
+// NotificationChannel is a way of notifying a user.
+type NotificationChannel string
+
+// Supported notification channels.
+const (
+	// NotificationChannelEmail emails the user's address.
+	NotificationChannelEmail NotificationChannel = "email"
+	// NotificationChannelWebhook posts to a URL the user registered.
+	NotificationChannelWebhook NotificationChannel = "webhook"
+	// NotificationChannelInApp shows the notification in the application.
+	NotificationChannelInApp NotificationChannel = "in_app"
+)
+
+// NotificationChannels lists every channel.
+func NotificationChannels() []NotificationChannel {
+	return []NotificationChannel{NotificationChannelEmail, NotificationChannelWebhook, NotificationChannelInApp}
+}
+
+// IsValid returns true if the channel is a valid value.
+func (c NotificationChannel) IsValid() bool {
+	return slices.Contains(NotificationChannels(), c)
+}
+
+// String implements fmt.Stringer for NotificationChannel.
+func (c NotificationChannel) String() string { return string(c) }
+
+// NotificationTopic groups the events users are notified about.
+type NotificationTopic string
+
+// Supported notification topics.
+const (
+	// NotificationTopicSecurity covers failed logins, password and identity changes.
+	NotificationTopicSecurity NotificationTopic = "security"
+	// NotificationTopicAccount covers verification, role changes and data exports.
+	NotificationTopicAccount NotificationTopic = "account"
+	// NotificationTopicOrganization covers invitations and membership changes.
+	NotificationTopicOrganization NotificationTopic = "organization"
+)
+
+// NotificationTopics lists every topic.
+func NotificationTopics() []NotificationTopic {
+	return []NotificationTopic{NotificationTopicSecurity, NotificationTopicAccount, NotificationTopicOrganization}
+}
+
+// IsValid returns true if the topic is a valid value.
+func (t NotificationTopic) IsValid() bool {
+	return slices.Contains(NotificationTopics(), t)
+}
+
+// String implements fmt.Stringer for NotificationTopic.
+func (t NotificationTopic) String() string { return string(t) }
+
+// NotificationPreference opts a user in to or out of a topic on a channel.
+type NotificationPreference struct {
+	UserID  UserID
+	Channel NotificationChannel
+	Topic   NotificationTopic
+	Enabled bool
+	// Target is the URL webhooks are posted to. The other channels deliver
+	// to the user and have no target.
+	Target    string
+	UpdatedAt time.Time
+}
+
+// NewNotificationPreference creates a validated preference as of now.
+// Enabled webhooks need an absolute http or https target.
+func NewNotificationPreference(
+	userID UserID,
+	channel NotificationChannel,
+	topic NotificationTopic,
+	enabled bool,
+	target string,
+) (*NotificationPreference, error) {
+	if !channel.IsValid() {
+		return nil, ErrInvalidNotificationChannel
+	}
+
+	if !topic.IsValid() {
+		return nil, ErrInvalidNotificationTopic
+	}
+
+	if channel == NotificationChannelWebhook {
+		if (enabled || target != "") && !isWebhookURL(target) {
+			return nil, ErrInvalidNotificationTarget
+		}
+	} else if target != "" {
+		return nil, ErrInvalidNotificationTarget
+	}
+
+	return &NotificationPreference{
+		UserID:    userID,
+		Channel:   channel,
+		Topic:     topic,
+		Enabled:   enabled,
+		Target:    target,
+		UpdatedAt: time.Now().UTC(),
+	}, nil
+}
+
+// DefaultNotificationPreference returns the preference of a user who never
+// chose one: email and in-app notifications are on, webhooks are off until
+// a target is registered.
+func DefaultNotificationPreference(
+	userID UserID,
+	channel NotificationChannel,
+	topic NotificationTopic,
+) NotificationPreference {
+	return NotificationPreference{
+		UserID:  userID,
+		Channel: channel,
+		Topic:   topic,
+		Enabled: channel != NotificationChannelWebhook,
+	}
+}
+
+// isWebhookURL returns true for absolute http and https URLs.
+func isWebhookURL(target string) bool {
+	u, err := url.Parse(target)
+
+	return err == nil && (u.Scheme == "https" || u.Scheme == "http") && u.Host != ""
+}
+
+// NotificationPreferences are the opt-ins of one user: the stored
+// preferences, and the defaults for every channel and topic without one.
+type NotificationPreferences struct {
+	UserID UserID
+	stored []*NotificationPreference
+}
+
+// NewNotificationPreferences creates the opt-ins of userID from its stored
+// preferences.
+func NewNotificationPreferences(userID UserID, stored []*NotificationPreference) *NotificationPreferences {
+	return &NotificationPreferences{UserID: userID, stored: stored}
+}
+
+// Lookup returns the preference of channel and topic.
+func (p *NotificationPreferences) Lookup(channel NotificationChannel, topic NotificationTopic) NotificationPreference {
+	for _, pref := range p.stored {
+		if pref.Channel == channel && pref.Topic == topic {
+			return *pref
+		}
+	}
+
+	return DefaultNotificationPreference(p.UserID, channel, topic)
+}
+
+// Allows returns true if the user is notified about topic on channel.
+func (p *NotificationPreferences) Allows(channel NotificationChannel, topic NotificationTopic) bool {
+	return p.Lookup(channel, topic).Enabled
+}
+
+// All returns the preference of every channel and topic, ordered by
+// channel and topic.
+func (p *NotificationPreferences) All() []NotificationPreference {
+	all := make([]NotificationPreference, 0, len(NotificationChannels())*len(NotificationTopics()))
+
+	for _, channel := range NotificationChannels() {
+		for _, topic := range NotificationTopics() {
+			all = append(all, p.Lookup(channel, topic))
+		}
+	}
+
+	return all
+}
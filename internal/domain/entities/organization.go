@@ -0,0 +1,120 @@
+package entities
+
+import (
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+const maxOrganizationNameLength = 100
+
+// organizationSlugPattern matches URL-safe organization slugs.
+var organizationSlugPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{1,48}[a-z0-9]$`)
+
+// OrganizationName is the display name of an organization.
+type OrganizationName string
+
+// NewOrganizationName creates a validated organization name.
+func NewOrganizationName(name string) (OrganizationName, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > maxOrganizationNameLength {
+		return "", ErrInvalidOrganizationName
+	}
+
+	return OrganizationName(name), nil
+}
+
+func (n OrganizationName) String() string { return string(n) }
+
+// OrganizationSlug is the unique, URL-safe handle of an organization.
+type OrganizationSlug string
+
+// NewOrganizationSlug creates a validated organization slug.
+func NewOrganizationSlug(slug string) (OrganizationSlug, error) {
+	if !organizationSlugPattern.MatchString(slug) {
+		return "", ErrInvalidOrganizationSlug
+	}
+
+	return OrganizationSlug(slug), nil
+}
+
+func (s OrganizationSlug) String() string { return string(s) }
+
+// Organization is a team of users. Membership is a separate aggregate so that
+// large organizations do not have to be loaded to change a single member.
+type Organization struct {
+	id        OrganizationID
+	uuid      uuid.UUID
+	name      OrganizationName
+	slug      OrganizationSlug
+	createdAt time.Time
+	updatedAt time.Time
+}
+
+// NewOrganization creates a new organization.
+func NewOrganization(name OrganizationName, slug OrganizationSlug) *Organization {
+	now := time.Now()
+
+	return &Organization{
+		uuid:      uuid.New(),
+		name:      name,
+		slug:      slug,
+		createdAt: now,
+		updatedAt: now,
+	}
+}
+
+// OrganizationRecord holds every persisted field of an organization.
+type OrganizationRecord struct {
+	ID        OrganizationID
+	UUID      uuid.UUID
+	Name      OrganizationName
+	Slug      OrganizationSlug
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// ReconstructOrganization rebuilds an organization from persisted state.
+// It is intended for repository adapters.
+func ReconstructOrganization(record OrganizationRecord) *Organization {
+	return &Organization{
+		id:        record.ID,
+		uuid:      record.UUID,
+		name:      record.Name,
+		slug:      record.Slug,
+		createdAt: record.CreatedAt,
+		updatedAt: record.UpdatedAt,
+	}
+}
+
+// ID returns the organization's internal ID.
+func (o *Organization) ID() OrganizationID { return o.id }
+
+// UUID returns the organization's public identifier.
+func (o *Organization) UUID() uuid.UUID { return o.uuid }
+
+// Name returns the organization's display name.
+func (o *Organization) Name() OrganizationName { return o.name }
+
+// Slug returns the organization's unique handle.
+func (o *Organization) Slug() OrganizationSlug { return o.slug }
+
+// CreatedAt returns when the organization was created.
+func (o *Organization) CreatedAt() time.Time { return o.createdAt }
+
+// UpdatedAt returns when the organization was last changed.
+func (o *Organization) UpdatedAt() time.Time { return o.updatedAt }
+
+// Rename changes the organization's display name.
+func (o *Organization) Rename(name OrganizationName) {
+	o.name = name
+	o.updatedAt = time.Now()
+}
+
+// SetID sets the ID assigned by the repository.
+func (o *Organization) SetID(id OrganizationID) {
+	o.id = id
+}
@@ -0,0 +1,75 @@
+package entities
+
+import (
+	"fmt"
+	"slices"
+)
+
+// OrganizationID is a strongly-typed organization identifier.
+type OrganizationID int64
+
+// Int64 returns the int64 representation of the organization ID.
+func (id OrganizationID) Int64() int64   { return int64(id) }
+func (id OrganizationID) String() string { return fmt.Sprintf("organization:%d", id) }
+
+// Permission represents an action a user may perform.
+type Permission string
+
+// Known permissions.
+const (
+	PermissionUsersRead      Permission = "users.read"
+	PermissionUsersWrite     Permission = "users.write"
+	PermissionUsersManage    Permission = "users.manage"
+	PermissionRolesManage    Permission = "roles.manage"
+	PermissionSessionsRevoke Permission = "sessions.revoke"
+	PermissionStatsRead      Permission = "stats.read"
+)
+
+func (p Permission) String() string { return string(p) }
+
+// rolePermissions maps each role to the permissions it grants.
+//
+//nolint:gochecknoglobals // Intentional lookup table for authorization
+var rolePermissions = map[UserRole][]Permission{
+	UserRoleUser: {
+		PermissionUsersRead,
+	},
+	UserRoleModerator: {
+		PermissionUsersRead,
+		PermissionUsersWrite,
+		PermissionSessionsRevoke,
+		PermissionStatsRead,
+	},
+	UserRoleAdmin: {
+		PermissionUsersRead,
+		PermissionUsersWrite,
+		PermissionUsersManage,
+		PermissionRolesManage,
+		PermissionSessionsRevoke,
+		PermissionStatsRead,
+	},
+}
+
+// roleRanks orders the roles by the authority they carry.
+//
+//nolint:gochecknoglobals // Intentional lookup table for authorization
+var roleRanks = map[UserRole]int{
+	UserRoleUser:      1,
+	UserRoleModerator: 2,
+	UserRoleAdmin:     3,
+}
+
+// Outranks returns true if the role carries more authority than other.
+func (r UserRole) Outranks(other UserRole) bool {
+	return roleRanks[r] > roleRanks[other]
+}
+
+// Grants returns true if the role grants the given permission.
+func (r UserRole) Grants(permission Permission) bool {
+	return slices.Contains(rolePermissions[r], permission)
+}
+
+// Permissions returns all permissions granted by the role.
+func (r UserRole) Permissions() []Permission {
+	return slices.Clone(rolePermissions[r])
+}
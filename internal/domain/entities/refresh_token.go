@@ -0,0 +1,110 @@
+package entities
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RefreshToken is a single-use credential that renews a session.
+// Each use rotates it; presenting an already rotated token indicates theft.
+type RefreshToken uuid.UUID
+
+// NewRefreshToken generates a new refresh token.
+func NewRefreshToken() RefreshToken {
+	return RefreshToken(uuid.New())
+}
+
+// UUID returns the underlying uuid.UUID representation of the token.
+func (t RefreshToken) UUID() uuid.UUID { return uuid.UUID(t) }
+func (t RefreshToken) String() string  { return uuid.UUID(t).String() }
+
+// IsZero returns true if no token has been issued.
+func (t RefreshToken) IsZero() bool { return uuid.UUID(t) == uuid.Nil }
+
+// RefreshPolicy controls the lifetimes of access and refresh tokens.
+// Access tokens are short-lived; refresh tokens outlive them so clients can
+// obtain new access tokens without re-authenticating.
+type RefreshPolicy struct {
+	AccessTokenLifetime  time.Duration
+	RefreshTokenLifetime time.Duration
+}
+
+// DefaultRefreshPolicy returns a policy with fifteen minute access tokens
+// and refresh tokens valid for one week.
+func DefaultRefreshPolicy() RefreshPolicy {
+	return RefreshPolicy{
+		AccessTokenLifetime:  15 * time.Minute,
+		RefreshTokenLifetime: SessionDurationMedium,
+	}
+}
+
+// Validate checks that the policy durations are consistent.
+func (p RefreshPolicy) Validate() error {
+	if p.AccessTokenLifetime <= 0 {
+		return NewValidationError("access_token_lifetime", "must be positive")
+	}
+
+	if p.RefreshTokenLifetime < p.AccessTokenLifetime {
+		return NewValidationError("refresh_token_lifetime", "must not be shorter than the access token lifetime")
+	}
+
+	return nil
+}
+
+// RefreshToken returns the current refresh token of the session.
+func (s *UserSession) RefreshToken() RefreshToken { return s.refreshToken }
+
+// RefreshExpiresAt returns when the current refresh token expires.
+func (s *UserSession) RefreshExpiresAt() time.Time { return s.refreshExpiresAt }
+
+// RotatedRefreshTokens returns the refresh tokens that have already been used.
+func (s *UserSession) RotatedRefreshTokens() []RefreshToken {
+	return slices.Clone(s.rotatedRefreshTokens)
+}
+
+// HasRefreshToken returns true if token is the current or a rotated refresh
+// token of this session.
+func (s *UserSession) HasRefreshToken(token RefreshToken) bool {
+	return !token.IsZero() &&
+		(s.refreshToken == token || slices.Contains(s.rotatedRefreshTokens, token))
+}
+
+// IssueRefreshToken replaces the current refresh token with a new one.
+// The previous token is kept so that its reuse can be detected.
+func (s *UserSession) IssueRefreshToken(expiresAt time.Time) RefreshToken {
+	if !s.refreshToken.IsZero() {
+		s.rotatedRefreshTokens = append(s.rotatedRefreshTokens, s.refreshToken)
+	}
+
+	s.refreshToken = NewRefreshToken()
+	s.refreshExpiresAt = expiresAt
+
+	return s.refreshToken
+}
+
+// RotateRefreshToken exchanges the presented refresh token for a new one.
+// Presenting a rotated token revokes the session, since either the client
+// or an attacker holds a stolen copy.
+func (s *UserSession) RotateRefreshToken(
+	presented RefreshToken,
+	expiresAt time.Time,
+	now time.Time,
+) (RefreshToken, error) {
+	if slices.Contains(s.rotatedRefreshTokens, presented) {
+		s.Deactivate()
+
+		return RefreshToken{}, ErrRefreshTokenReused
+	}
+
+	if presented.IsZero() || presented != s.refreshToken || !s.isActive {
+		return RefreshToken{}, ErrInvalidRefreshToken
+	}
+
+	if !now.Before(s.refreshExpiresAt) {
+		return RefreshToken{}, ErrSessionExpired
+	}
+
+	return s.IssueRefreshToken(expiresAt), nil
+}
@@ -2,7 +2,9 @@ package entities
 
 import (
 	"fmt"
+	"maps"
 	"net"
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
@@ -19,6 +21,18 @@ type UserSession struct {
 	createdAt  time.Time
 	expiresAt  time.Time
 	isActive   bool
+
+	// location is resolved from ipAddress when the session starts.
+	location GeoLocation
+
+	organizationID *OrganizationID
+
+	// impersonatorID is the staff member acting as the user, if any.
+	impersonatorID *UserID
+
+	refreshToken         RefreshToken
+	refreshExpiresAt     time.Time
+	rotatedRefreshTokens []RefreshToken
 }
 
 // SessionID is a strongly-typed session identifier.
@@ -78,11 +92,105 @@ func NewUserSession(
 	}
 }
 
+// SessionRecord holds every persisted field of a session.
+type SessionRecord struct {
+	ID         SessionID
+	UserID     UserID
+	Token      SessionToken
+	DeviceInfo SessionDeviceInfo
+	IPAddress  net.IP
+	UserAgent  string
+	CreatedAt  time.Time
+	ExpiresAt  time.Time
+	IsActive   bool
+
+	Location GeoLocation
+
+	OrganizationID *OrganizationID
+	ImpersonatorID *UserID
+
+	RefreshToken         RefreshToken
+	RefreshExpiresAt     time.Time
+	RotatedRefreshTokens []RefreshToken
+}
+
+// ReconstructSession rebuilds a session from persisted state without issuing
+// a new token or resetting timestamps. It is intended for repository adapters.
+func ReconstructSession(record SessionRecord) (*UserSession, error) {
+	if uuid.UUID(record.Token) == uuid.Nil {
+		return nil, fmt.Errorf("session id=%v: %w", record.ID, ErrInvalidSessionToken)
+	}
+
+	deviceInfo := record.DeviceInfo
+	if deviceInfo.Metadata == nil {
+		deviceInfo.Metadata = make(map[string]any)
+	}
+
+	return &UserSession{
+		id:                   record.ID,
+		userID:               record.UserID,
+		token:                record.Token,
+		deviceInfo:           deviceInfo,
+		ipAddress:            record.IPAddress,
+		userAgent:            record.UserAgent,
+		createdAt:            record.CreatedAt,
+		expiresAt:            record.ExpiresAt,
+		isActive:             record.IsActive,
+		location:             record.Location,
+		organizationID:       record.OrganizationID,
+		impersonatorID:       record.ImpersonatorID,
+		refreshToken:         record.RefreshToken,
+		refreshExpiresAt:     record.RefreshExpiresAt,
+		rotatedRefreshTokens: record.RotatedRefreshTokens,
+	}, nil
+}
+
+// Record returns the state of the session as accepted by ReconstructSession.
+// Device metadata and rotated tokens are copied so the record can outlive
+// later changes.
+func (s *UserSession) Record() SessionRecord {
+	deviceInfo := s.deviceInfo
+	deviceInfo.Metadata = maps.Clone(s.deviceInfo.Metadata)
+
+	var organizationID *OrganizationID
+	if s.organizationID != nil {
+		id := *s.organizationID
+		organizationID = &id
+	}
+
+	var impersonatorID *UserID
+	if s.impersonatorID != nil {
+		id := *s.impersonatorID
+		impersonatorID = &id
+	}
+
+	return SessionRecord{
+		ID:                   s.id,
+		UserID:               s.userID,
+		Token:                s.token,
+		DeviceInfo:           deviceInfo,
+		IPAddress:            slices.Clone(s.ipAddress),
+		UserAgent:            s.userAgent,
+		CreatedAt:            s.createdAt,
+		ExpiresAt:            s.expiresAt,
+		IsActive:             s.isActive,
+		Location:             s.location,
+		OrganizationID:       organizationID,
+		ImpersonatorID:       impersonatorID,
+		RefreshToken:         s.refreshToken,
+		RefreshExpiresAt:     s.refreshExpiresAt,
+		RotatedRefreshTokens: slices.Clone(s.rotatedRefreshTokens),
+	}
+}
+
 // Session methods.
 
 // ID returns the session ID.
 func (s *UserSession) ID() SessionID { return s.id }
 
+// SetID sets the session ID (used by repository after creation).
+func (s *UserSession) SetID(id SessionID) { s.id = id }
+
 // UserID returns the user ID associated with this session.
 func (s *UserSession) UserID() UserID { return s.userID }
 
@@ -98,6 +206,12 @@ func (s *UserSession) IPAddress() net.IP { return s.ipAddress }
 // UserAgent returns the user agent string for this session.
 func (s *UserSession) UserAgent() string { return s.userAgent }
 
+// Location returns the coarse location the session was started from.
+func (s *UserSession) Location() GeoLocation { return s.location }
+
+// SetLocation sets the location the session was started from.
+func (s *UserSession) SetLocation(location GeoLocation) { s.location = location }
+
 // CreatedAt returns the session creation timestamp.
 func (s *UserSession) CreatedAt() time.Time { return s.createdAt }
 
@@ -127,6 +241,59 @@ func (s *UserSession) Extend(duration time.Duration) {
 	s.expiresAt = time.Now().Add(duration)
 }
 
+// ExtendUntil sets the session expiration to the given time.
+func (s *UserSession) ExtendUntil(expiresAt time.Time) {
+	s.expiresAt = expiresAt
+}
+
+// OrganizationID returns the active organization of the session, if one is selected.
+func (s *UserSession) OrganizationID() (OrganizationID, bool) {
+	if s.organizationID == nil {
+		return 0, false
+	}
+
+	return *s.organizationID, true
+}
+
+// SwitchOrganization scopes the session to the given organization.
+func (s *UserSession) SwitchOrganization(id OrganizationID) {
+	s.organizationID = &id
+}
+
+// ClearOrganization removes the organization scope from the session.
+func (s *UserSession) ClearOrganization() {
+	s.organizationID = nil
+}
+
+// NewImpersonationSession creates a session in which impersonatorID acts as
+// userID. It lasts duration and is never renewed.
+func NewImpersonationSession(
+	impersonatorID, userID UserID,
+	ipAddress net.IP,
+	userAgent string,
+	duration time.Duration,
+) *UserSession {
+	session := NewUserSession(userID, ipAddress, userAgent, NewSessionDeviceInfo(), duration)
+	session.impersonatorID = &impersonatorID
+
+	return session
+}
+
+// Impersonator returns the staff member acting as the user, if the session
+// is an impersonation.
+func (s *UserSession) Impersonator() (UserID, bool) {
+	if s.impersonatorID == nil {
+		return 0, false
+	}
+
+	return *s.impersonatorID, true
+}
+
+// IsImpersonation returns true if a staff member acts as the user in the session.
+func (s *UserSession) IsImpersonation() bool {
+	return s.impersonatorID != nil
+}
+
 // GetMetadata returns device metadata.
 func (d SessionDeviceInfo) GetMetadata(key string) (any, bool) {
 	val, ok := d.Metadata[key]
@@ -149,4 +316,7 @@ const (
 	SessionDurationMedium   = 7 * 24 * time.Hour  // 1 week
 	SessionDurationLong     = 30 * 24 * time.Hour // 1 month
 	SessionDurationRemember = 90 * 24 * time.Hour // 3 months (remember me)
+
+	// SessionDurationImpersonation is the default lifetime of impersonation sessions.
+	SessionDurationImpersonation = time.Hour
 )
@@ -0,0 +1,85 @@
+package entities
+
+import "time"
+
+// SessionPolicy controls sliding session expiration.
+// A session expires after IdleTimeout without activity, is extended on activity
+// once less than RenewalThreshold remains, and never outlives AbsoluteLifetime.
+type SessionPolicy struct {
+	AbsoluteLifetime time.Duration
+	IdleTimeout      time.Duration
+	RenewalThreshold time.Duration
+}
+
+// DefaultSessionPolicy returns a policy with a one week idle timeout,
+// renewed daily, capped at one month.
+func DefaultSessionPolicy() SessionPolicy {
+	return SessionPolicy{
+		AbsoluteLifetime: SessionDurationLong,
+		IdleTimeout:      SessionDurationMedium,
+		RenewalThreshold: SessionDurationMedium - SessionDurationShort,
+	}
+}
+
+// ImpersonationPolicy returns a policy for sessions that last lifetime
+// and are never renewed.
+func ImpersonationPolicy(lifetime time.Duration) SessionPolicy {
+	return SessionPolicy{
+		AbsoluteLifetime: lifetime,
+		IdleTimeout:      lifetime,
+		RenewalThreshold: 0,
+	}
+}
+
+// Validate checks that the policy durations are consistent.
+func (p SessionPolicy) Validate() error {
+	if p.AbsoluteLifetime <= 0 {
+		return NewValidationError("absolute_lifetime", "must be positive")
+	}
+
+	if p.IdleTimeout <= 0 || p.IdleTimeout > p.AbsoluteLifetime {
+		return NewValidationError("idle_timeout", "must be positive and not exceed the absolute lifetime")
+	}
+
+	if p.RenewalThreshold < 0 || p.RenewalThreshold > p.IdleTimeout {
+		return NewValidationError("renewal_threshold", "must be between zero and the idle timeout")
+	}
+
+	return nil
+}
+
+// InitialLifetime returns the lifetime of a newly created session.
+func (p SessionPolicy) InitialLifetime() time.Duration {
+	return min(p.IdleTimeout, p.AbsoluteLifetime)
+}
+
+// AbsoluteExpiry returns the time after which the session can no longer be renewed.
+func (p SessionPolicy) AbsoluteExpiry(session *UserSession) time.Time {
+	return session.CreatedAt().Add(p.AbsoluteLifetime)
+}
+
+// Exceeded returns true if the session has outlived the absolute lifetime.
+func (p SessionPolicy) Exceeded(session *UserSession, now time.Time) bool {
+	return !now.Before(p.AbsoluteExpiry(session))
+}
+
+// Renew extends the session on activity when less than RenewalThreshold remains,
+// without exceeding the absolute lifetime. It returns true if the session changed.
+func (p SessionPolicy) Renew(session *UserSession, now time.Time) bool {
+	if session.ExpiresAt().Sub(now) > p.RenewalThreshold {
+		return false
+	}
+
+	expiresAt := now.Add(p.IdleTimeout)
+	if absolute := p.AbsoluteExpiry(session); expiresAt.After(absolute) {
+		expiresAt = absolute
+	}
+
+	if !expiresAt.After(session.ExpiresAt()) {
+		return false
+	}
+
+	session.ExtendUntil(expiresAt)
+
+	return true
+}
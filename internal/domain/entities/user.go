@@ -1,13 +1,17 @@
 package entities
 
 import (
+	"crypto/rand"
 	"fmt"
+	"maps"
 	"regexp"
 	"slices"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
 )
 
 // minBcryptLength is the minimum length for a bcrypt hash.
@@ -74,32 +78,12 @@ func NewUuIDFromUUID(u uuid.UUID) UuID {
 	return UuID(u.String())
 }
 
-// EmailRegex is a simple email validation pattern.
-var EmailRegex = regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
-
-// isValidEmail validates an email address.
-func isValidEmail(email string) bool {
-	return EmailRegex.MatchString(email)
-}
-
-// Email represents a validated email address.
-type Email string
-
-// NewEmail creates a new Email from a string, validating its format.
-func NewEmail(email string) (Email, error) {
-	if !isValidEmail(email) {
-		return "", ErrInvalidEmail
-	}
-
-	return Email(strings.ToLower(strings.TrimSpace(email))), nil
-}
-
-func (e Email) String() string { return string(e) }
-
 // Username represents a validated username.
 type Username string
 
-// ReservedUsernames contains usernames that cannot be registered.
+// ReservedUsernames contains usernames that cannot be registered. They are
+// the built-in part of the reserved names of validation.UsernamePolicy, which
+// extends them by configuration and matches their look-alikes too.
 //
 //nolint:gochecknoglobals // Intentional lookup table for validation
 var ReservedUsernames = map[string]bool{
@@ -139,6 +123,13 @@ func NewUsername(username string) (Username, error) {
 
 func (u Username) String() string { return string(u) }
 
+// Canonical returns the form usernames are unique and looked up by: Unicode
+// NFKC with the case folded, so that "Ada", "ADA" and the full-width "Ａｄａ"
+// are the same username. Folding can denormalize, hence the second NFKC.
+func (u Username) Canonical() string {
+	return norm.NFKC.String(cases.Fold().String(norm.NFKC.String(string(u))))
+}
+
 // PasswordHash represents a secure password hash.
 type PasswordHash string
 
@@ -153,6 +144,21 @@ func NewPasswordHash(hash string) (PasswordHash, error) {
 
 func (p PasswordHash) String() string { return string(p) }
 
+// unusablePasswordPrefix marks hashes that no password can match.
+// No supported hash format starts with it.
+const unusablePasswordPrefix = "!"
+
+// NewUnusablePasswordHash returns a random hash for users that sign in only
+// through external identity providers.
+func NewUnusablePasswordHash() PasswordHash {
+	return PasswordHash(unusablePasswordPrefix + rand.Text() + rand.Text())
+}
+
+// IsUsable returns false for hashes created by NewUnusablePasswordHash.
+func (p PasswordHash) IsUsable() bool {
+	return !strings.HasPrefix(string(p), unusablePasswordPrefix)
+}
+
 // FirstName represents a validated first name.
 type FirstName string
 
@@ -297,6 +303,90 @@ func NewUser(
 	}, nil
 }
 
+// UserRecord holds every persisted field of a user.
+type UserRecord struct {
+	ID          UserID
+	UUID        uuid.UUID
+	Email       Email
+	Username    Username
+	Password    PasswordHash
+	FirstName   FirstName
+	LastName    LastName
+	Status      UserStatus
+	Role        UserRole
+	IsVerified  bool
+	Metadata    UserMetadata
+	Tags        []string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	LastLoginAt *time.Time
+}
+
+// ReconstructUser rebuilds a user from persisted state without resetting
+// identity or timestamps. It is intended for repository adapters.
+func ReconstructUser(record UserRecord) (*User, error) {
+	if !record.Status.IsValid() {
+		return nil, fmt.Errorf("status=%v: %w", record.Status, ErrInvalidUserStatus)
+	}
+
+	if !record.Role.IsValid() {
+		return nil, fmt.Errorf("role=%v: %w", record.Role, ErrInvalidUserRole)
+	}
+
+	metadata := record.Metadata
+	if metadata == nil {
+		metadata = NewUserMetadata()
+	}
+
+	tags := record.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+
+	return &User{
+		id:          record.ID,
+		uuid:        record.UUID,
+		email:       record.Email,
+		username:    record.Username,
+		password:    record.Password,
+		firstName:   record.FirstName,
+		lastName:    record.LastName,
+		status:      record.Status,
+		role:        record.Role,
+		isVerified:  record.IsVerified,
+		metadata:    metadata,
+		tags:        tags,
+		createdAt:   record.CreatedAt,
+		updatedAt:   record.UpdatedAt,
+		lastLoginAt: record.LastLoginAt,
+	}, nil
+}
+
+// Record returns the state of the user as accepted by ReconstructUser.
+// Metadata and tags are copied so the record can outlive later changes.
+func (u *User) Record() UserRecord {
+	metadata := NewUserMetadata()
+	maps.Copy(metadata, u.metadata)
+
+	return UserRecord{
+		ID:          u.id,
+		UUID:        u.uuid,
+		Email:       u.email,
+		Username:    u.username,
+		Password:    u.password,
+		FirstName:   u.firstName,
+		LastName:    u.lastName,
+		Status:      u.status,
+		Role:        u.role,
+		IsVerified:  u.isVerified,
+		Metadata:    metadata,
+		Tags:        slices.Clone(u.tags),
+		CreatedAt:   u.createdAt,
+		UpdatedAt:   u.updatedAt,
+		LastLoginAt: u.lastLoginAt,
+	}
+}
+
 // Methods for the User entity
 
 // ID returns the user's internal ID.
@@ -311,6 +401,9 @@ func (u *User) Email() Email { return u.email }
 // Username returns the user's username.
 func (u *User) Username() Username { return u.username }
 
+// PasswordHash returns the stored password hash.
+func (u *User) PasswordHash() PasswordHash { return u.password }
+
 // FirstName returns the user's first name.
 func (u *User) FirstName() FirstName { return u.firstName }
 
@@ -414,6 +507,19 @@ func (u *User) ChangeRole(role UserRole) error {
 	)
 }
 
+// ChangeEmail replaces the email address of the user. The address is
+// expected to be confirmed, so the user stays verified.
+func (u *User) ChangeEmail(email Email) {
+	u.email = email
+	u.updatedAt = time.Now()
+}
+
+// ChangeUsername replaces the username of the user.
+func (u *User) ChangeUsername(username Username) {
+	u.username = username
+	u.updatedAt = time.Now()
+}
+
 // Verify marks user as verified.
 func (u *User) Verify() {
 	u.isVerified = true
@@ -449,6 +555,23 @@ func (u *User) RemoveTag(tag string) {
 	}
 }
 
+// erasedName replaces the first and last name of anonymized users.
+const erasedName = "Erased"
+
+// Anonymize replaces the personal data of the user with placeholders derived
+// from its ID, clears its metadata and tags, and deactivates it. The password
+// hash is kept; it is replaced separately through UpdatePassword.
+func (u *User) Anonymize() {
+	u.email = Email(fmt.Sprintf("erased-%d@erased.invalid", u.id))
+	u.username = Username(fmt.Sprintf("erased-%d", u.id))
+	u.firstName = erasedName
+	u.lastName = erasedName
+	u.metadata = NewUserMetadata()
+	u.tags = make([]string, 0)
+	u.status = UserStatusInactive
+	u.updatedAt = time.Now()
+}
+
 // SetID sets the user ID (used by repository after creation)
 // This is intentionally package-private to allow repository to set ID after creation.
 func (u *User) SetID(id UserID) {
@@ -469,6 +592,48 @@ type UserStats struct {
 	VerificationRate float64 `json:"verificationRate"`
 }
 
+// ComputeRates derives the percentage fields from the raw counts.
+func (s *UserStats) ComputeRates() {
+	if s.TotalUsers == 0 {
+		s.ActivePercentage = 0
+		s.VerificationRate = 0
+
+		return
+	}
+
+	total := float64(s.TotalUsers)
+	s.ActivePercentage = float64(s.ActiveUsers) / total * 100
+	s.VerificationRate = float64(s.VerifiedUsers) / total * 100
+}
+
+// TagCount represents how many users carry a tag.
+type TagCount struct {
+	Tag   string `json:"tag"`
+	Count int64  `json:"count"`
+}
+
+// SearchFacets contains per-filter counts for a search result set.
+type SearchFacets struct {
+	ByStatus map[UserStatus]int64 `json:"byStatus"`
+	ByRole   map[UserRole]int64   `json:"byRole"`
+	TopTags  []TagCount           `json:"topTags"`
+}
+
+// NewSearchFacets creates empty search facets.
+func NewSearchFacets() *SearchFacets {
+	return &SearchFacets{
+		ByStatus: make(map[UserStatus]int64),
+		ByRole:   make(map[UserRole]int64),
+		TopTags:  make([]TagCount, 0),
+	}
+}
+
+// UserSearchResult represents search results together with their facet counts.
+type UserSearchResult struct {
+	Users  []*User       `json:"users"`
+	Facets *SearchFacets `json:"facets"`
+}
+
 // SessionStats represents session statistics.
 type SessionStats struct {
 	TotalSessions   int64 `json:"totalSessions"`
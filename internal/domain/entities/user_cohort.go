@@ -0,0 +1,45 @@
+package entities
+
+import "time"
+
+// CohortInterval is the length of the signup periods users are grouped by.
+type CohortInterval string
+
+// Supported cohort intervals.
+const (
+	CohortIntervalDay   CohortInterval = "day"
+	CohortIntervalWeek  CohortInterval = "week"
+	CohortIntervalMonth CohortInterval = "month"
+)
+
+// IsValid returns true if the interval is supported.
+func (i CohortInterval) IsValid() bool {
+	switch i {
+	case CohortIntervalDay, CohortIntervalWeek, CohortIntervalMonth:
+		return true
+	}
+
+	return false
+}
+
+// UserCohort summarizes the users who signed up in one period.
+type UserCohort struct {
+	// Start is the beginning of the period in UTC; weeks start on Monday.
+	Start time.Time `json:"start"`
+	Users int64     `json:"users"`
+	// Verified users confirmed their email address.
+	Verified int64 `json:"verified"`
+	// LoggedIn users logged in at least once.
+	LoggedIn int64 `json:"loggedIn"`
+	// Active users still have the active status.
+	Active int64 `json:"active"`
+}
+
+// RetentionRate returns the share of the cohort that logged in, in percent.
+func (c UserCohort) RetentionRate() float64 {
+	if c.Users == 0 {
+		return 0
+	}
+
+	return float64(c.LoggedIn) / float64(c.Users) * 100
+}
@@ -0,0 +1,163 @@
+package entities
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Limits keeping filters within what every engine can evaluate efficiently.
+const (
+	MaxFilterTags        = 10
+	MaxFilterQueryLength = 500
+)
+
+// Values of UserFilter.VerifiedState.
+const (
+	VerifiedAny   = -1
+	VerifiedFalse = 0
+	VerifiedTrue  = 1
+)
+
+// UserFilter selects users in listings. Every criterion that is set must
+// match; zero criteria match every user.
+type UserFilter struct {
+	// Statuses and Roles match users with any of the listed values.
+	Statuses []UserStatus
+	Roles    []UserRole
+	// Verified matches users in the given verification state.
+	Verified *bool
+	// CreatedAfter and CreatedBefore are exclusive bounds of the creation time.
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	// TagsAny matches users carrying at least one of the tags, TagsAll users
+	// carrying all of them.
+	TagsAny []string
+	TagsAll []string
+	// Query is a full-text search over email, username and names; every term
+	// must match as a prefix.
+	Query string
+}
+
+// Validate checks every criterion of the filter.
+func (f UserFilter) Validate() error {
+	for _, status := range f.Statuses {
+		if !status.IsValid() {
+			return fmt.Errorf("status=%v: %w", status, ErrInvalidUserStatus)
+		}
+	}
+
+	for _, role := range f.Roles {
+		if !role.IsValid() {
+			return fmt.Errorf("role=%v: %w", role, ErrInvalidUserRole)
+		}
+	}
+
+	if len(f.TagsAny) > MaxFilterTags || len(f.TagsAll) > MaxFilterTags {
+		return NewValidationError("tags", fmt.Sprintf("cannot exceed %d tags", MaxFilterTags))
+	}
+
+	if utf8.RuneCountInString(f.Query) > MaxFilterQueryLength {
+		return NewValidationError("query", fmt.Sprintf("cannot exceed %d characters", MaxFilterQueryLength))
+	}
+
+	if !f.CreatedAfter.IsZero() && !f.CreatedBefore.IsZero() && !f.CreatedAfter.Before(f.CreatedBefore) {
+		return NewValidationError("created_before", "must be after created_after")
+	}
+
+	return nil
+}
+
+// Matches reports whether user satisfies the filter, so that in-memory
+// repositories evaluate filters the way the databases do.
+func (f UserFilter) Matches(user *User) bool {
+	after, before := f.CreatedRange()
+
+	return (len(f.Statuses) == 0 || slices.Contains(f.Statuses, user.Status())) &&
+		(len(f.Roles) == 0 || slices.Contains(f.Roles, user.Role())) &&
+		(f.Verified == nil || *f.Verified == user.IsVerified()) &&
+		user.CreatedAt().After(after) && user.CreatedAt().Before(before) &&
+		(len(f.TagsAny) == 0 || slices.ContainsFunc(f.TagsAny, hasTag(user))) &&
+		!slices.ContainsFunc(f.TagsAll, func(tag string) bool { return !hasTag(user)(tag) }) &&
+		f.matchesQuery(user)
+}
+
+// hasTag returns a predicate reporting whether user carries a tag.
+func hasTag(user *User) func(string) bool {
+	return func(tag string) bool { return slices.Contains(user.Tags(), tag) }
+}
+
+// matchesQuery reports whether every query term is a prefix of a word of the
+// user's email, username or names, ignoring case.
+func (f UserFilter) matchesQuery(user *User) bool {
+	words := QueryTerms(strings.Join([]string{
+		user.Email().String(), user.Username().String(), user.FirstName().String(), user.LastName().String(),
+	}, " "))
+
+	for _, term := range QueryTerms(f.Query) {
+		if !slices.ContainsFunc(words, func(word string) bool { return strings.HasPrefix(word, term) }) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// QueryTerms splits text into lowercase words at every character that is
+// neither a letter nor a digit, the way Query is matched.
+func QueryTerms(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// CreatedRange returns the exclusive creation time bounds with open ends
+// replaced by bounds every engine can compare against.
+func (f UserFilter) CreatedRange() (time.Time, time.Time) {
+	after, before := f.CreatedAfter, f.CreatedBefore
+	if after.IsZero() {
+		after = rangeStart
+	}
+
+	if before.IsZero() {
+		before = rangeEnd
+	}
+
+	return after, before
+}
+
+// VerifiedState returns VerifiedAny, VerifiedFalse or VerifiedTrue for the
+// integer parameter of the filter queries.
+func (f UserFilter) VerifiedState() int {
+	switch {
+	case f.Verified == nil:
+		return VerifiedAny
+	case *f.Verified:
+		return VerifiedTrue
+	default:
+		return VerifiedFalse
+	}
+}
+
+// StatusValues returns the statuses as strings for query parameters.
+func (f UserFilter) StatusValues() []string {
+	values := make([]string, 0, len(f.Statuses))
+	for _, status := range f.Statuses {
+		values = append(values, status.String())
+	}
+
+	return values
+}
+
+// RoleValues returns the roles as strings for query parameters.
+func (f UserFilter) RoleValues() []string {
+	values := make([]string, 0, len(f.Roles))
+	for _, role := range f.Roles {
+		values = append(values, role.String())
+	}
+
+	return values
+}
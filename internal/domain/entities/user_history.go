@@ -0,0 +1,87 @@
+package entities
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HistoryOperation describes how a user version was superseded.
+type HistoryOperation string
+
+// Known history operations.
+const (
+	HistoryOperationCurrent HistoryOperation = "current"
+	HistoryOperationUpdate  HistoryOperation = "update"
+	HistoryOperationDelete  HistoryOperation = "delete"
+)
+
+// UserSnapshot is an immutable version of a user record valid for a time range.
+type UserSnapshot struct {
+	UserID     UserID
+	Operation  HistoryOperation
+	Email      Email
+	Username   Username
+	FirstName  FirstName
+	LastName   LastName
+	Status     UserStatus
+	Role       UserRole
+	Tags       []string
+	IsVerified bool
+	ValidFrom  time.Time
+	// ValidTo is nil for the current version.
+	ValidTo *time.Time
+}
+
+// SnapshotOf captures the current state of a user.
+func SnapshotOf(user *User) *UserSnapshot {
+	return &UserSnapshot{
+		UserID:     user.ID(),
+		Operation:  HistoryOperationCurrent,
+		Email:      user.Email(),
+		Username:   user.Username(),
+		FirstName:  user.FirstName(),
+		LastName:   user.LastName(),
+		Status:     user.Status(),
+		Role:       user.Role(),
+		Tags:       slices.Clone(user.Tags()),
+		IsVerified: user.IsVerified(),
+		ValidFrom:  user.UpdatedAt(),
+		ValidTo:    nil,
+	}
+}
+
+// IsCurrent returns true if the snapshot is the current version.
+func (s *UserSnapshot) IsCurrent() bool {
+	return s.ValidTo == nil
+}
+
+// FieldChange describes a single field that differs between two user versions.
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+// Diff returns the fields that changed from this snapshot to the other one.
+func (s *UserSnapshot) Diff(other *UserSnapshot) []FieldChange {
+	changes := make([]FieldChange, 0)
+
+	add := func(field, oldValue, newValue string) {
+		if oldValue != newValue {
+			changes = append(changes, FieldChange{Field: field, Old: oldValue, New: newValue})
+		}
+	}
+
+	add("email", s.Email.String(), other.Email.String())
+	add("username", s.Username.String(), other.Username.String())
+	add("first_name", s.FirstName.String(), other.FirstName.String())
+	add("last_name", s.LastName.String(), other.LastName.String())
+	add("status", string(s.Status), string(other.Status))
+	add("role", string(s.Role), string(other.Role))
+	add("tags", strings.Join(s.Tags, ","), strings.Join(other.Tags, ","))
+	add("is_verified", strconv.FormatBool(s.IsVerified), strconv.FormatBool(other.IsVerified))
+
+	return changes
+}
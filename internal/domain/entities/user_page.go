@@ -0,0 +1,86 @@
+package entities
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+)
+
+// UserCursor is the keyset position after the last user of a page.
+// Listings are ordered newest first, by creation time and then by ID.
+type UserCursor struct {
+	CreatedAt time.Time `json:"t"`
+	ID        UserID    `json:"id"`
+}
+
+// firstPage is the cursor before every user, for queries that always apply
+// the keyset predicate.
+var firstPage = UserCursor{CreatedAt: rangeEnd, ID: math.MaxInt64}
+
+// Bound returns the cursor a keyset query continues after; a nil cursor
+// starts at the first page.
+func (c *UserCursor) Bound() UserCursor {
+	if c == nil {
+		return firstPage
+	}
+
+	return *c
+}
+
+// CursorAfter returns the cursor positioned after user.
+func CursorAfter(user *User) UserCursor {
+	return UserCursor{CreatedAt: user.CreatedAt(), ID: user.ID()}
+}
+
+// Encode returns the opaque token handed to clients.
+func (c UserCursor) Encode() string {
+	data, _ := json.Marshal(c)
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeUserCursor parses a token returned by Encode. An empty token yields
+// nil, which requests the first page.
+func DecodeUserCursor(token string) (*UserCursor, error) {
+	if token == "" {
+		return nil, nil //nolint:nilnil // no cursor means the first page
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("decode: %w", ErrInvalidCursor)
+	}
+
+	var cursor UserCursor
+
+	err = json.Unmarshal(data, &cursor)
+	if err != nil || cursor.ID <= 0 || cursor.CreatedAt.IsZero() {
+		return nil, fmt.Errorf("parse: %w", ErrInvalidCursor)
+	}
+
+	return &cursor, nil
+}
+
+// UserPage is one page of a keyset-paginated user listing.
+type UserPage struct {
+	Users []*User `json:"users"`
+	// NextCursor requests the following page; it is empty on the last page.
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+// NewUserPage builds a page from up to limit+1 users; the extra user only
+// signals that another page follows.
+func NewUserPage(users []*User, limit int) *UserPage {
+	if len(users) <= limit {
+		return &UserPage{Users: users}
+	}
+
+	users = users[:limit]
+
+	return &UserPage{
+		Users:      users,
+		NextCursor: CursorAfter(users[limit-1]).Encode(),
+	}
+}
@@ -0,0 +1,306 @@
+package entities
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// PreferenceKey names a user preference. Keys are namespaced with dots,
+// like ui.theme, and consist of lowercase letters, digits, underscores and
+// dots.
+type PreferenceKey string
+
+// Built-in preferences.
+const (
+	// PreferenceTheme is the color scheme of the user interface.
+	PreferenceTheme PreferenceKey = "ui.theme"
+	// PreferenceLocale is the BCP 47 language tag the user interface uses.
+	PreferenceLocale PreferenceKey = "ui.locale"
+	// PreferencePageSize is how many items lists show per page.
+	PreferencePageSize PreferenceKey = "ui.page_size"
+	// PreferenceEmailDigest is how often notifications are summarized by email.
+	PreferenceEmailDigest PreferenceKey = "notifications.email_digest"
+	// PreferenceNotificationSounds plays a sound for in-app notifications.
+	PreferenceNotificationSounds PreferenceKey = "notifications.sounds"
+)
+
+// maxPreferenceKeyLength bounds preference keys, which are stored as JSON
+// object keys and MySQL JSON paths.
+const maxPreferenceKeyLength = 64
+
+// IsValid returns true if the key is a namespace and a name joined by dots.
+func (k PreferenceKey) IsValid() bool {
+	if len(k) > maxPreferenceKeyLength || !strings.Contains(string(k), ".") {
+		return false
+	}
+
+	for _, part := range strings.Split(string(k), ".") {
+		if part == "" {
+			return false
+		}
+
+		for _, r := range part {
+			if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '_' {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// Namespace returns the part of the key before the first dot.
+func (k PreferenceKey) Namespace() string {
+	namespace, _, _ := strings.Cut(string(k), ".")
+
+	return namespace
+}
+
+// String implements fmt.Stringer for PreferenceKey.
+func (k PreferenceKey) String() string { return string(k) }
+
+// PreferenceType is the type of the values of a preference.
+type PreferenceType string
+
+// Preference types.
+const (
+	PreferenceTypeString PreferenceType = "string"
+	PreferenceTypeBool   PreferenceType = "bool"
+	PreferenceTypeInt    PreferenceType = "int"
+	// PreferenceTypeEnum is a string out of PreferenceDefinition.Allowed.
+	PreferenceTypeEnum PreferenceType = "enum"
+)
+
+// String implements fmt.Stringer for PreferenceType.
+func (t PreferenceType) String() string { return string(t) }
+
+// PreferenceDefinition describes a preference: its type, the values it
+// accepts and the default of users who never set it.
+type PreferenceDefinition struct {
+	Key     PreferenceKey
+	Type    PreferenceType
+	Default any
+	// Allowed lists the values of enum preferences.
+	Allowed []string
+	// MaxLength bounds the characters of string preferences, if positive.
+	MaxLength int
+	// Min and Max bound int preferences if Min is less than Max.
+	Min int
+	Max int
+}
+
+// Normalize checks that value is valid for the preference and returns it
+// as a string, bool or int. Ints may be given as any whole number, since
+// decoded JSON has float64 numbers.
+func (d PreferenceDefinition) Normalize(value any) (any, error) {
+	var ok bool
+
+	switch d.Type {
+	case PreferenceTypeString:
+		var s string
+		s, ok = value.(string)
+		ok = ok && (d.MaxLength <= 0 || utf8.RuneCountInString(s) <= d.MaxLength)
+	case PreferenceTypeEnum:
+		var s string
+		s, ok = value.(string)
+		ok = ok && slices.Contains(d.Allowed, s)
+	case PreferenceTypeBool:
+		_, ok = value.(bool)
+	case PreferenceTypeInt:
+		n, isInt := preferenceInt(value)
+		ok = isInt && (d.Min >= d.Max || (n >= d.Min && n <= d.Max))
+
+		if ok {
+			value = n
+		}
+	}
+
+	if !ok {
+		return nil, fmt.Errorf("preference %v=%v: %w", d.Key, value, ErrInvalidPreferenceValue)
+	}
+
+	return value, nil
+}
+
+// preferenceInt converts whole numbers to int.
+func preferenceInt(value any) (int, bool) {
+	switch n := value.(type) {
+	case int:
+		return n, true
+	case int64:
+		return int(n), true
+	case float64:
+		return int(n), n == math.Trunc(n) && n >= math.MinInt && n < math.MaxInt
+	default:
+		return 0, false
+	}
+}
+
+// PreferenceSchema is the set of preferences users can set.
+type PreferenceSchema []PreferenceDefinition
+
+// DefaultPreferenceSchema returns the built-in preferences.
+func DefaultPreferenceSchema() PreferenceSchema {
+	return PreferenceSchema{
+		{
+			Key:     PreferenceTheme,
+			Type:    PreferenceTypeEnum,
+			Default: "system",
+			Allowed: []string{"system", "light", "dark"},
+		},
+		{Key: PreferenceLocale, Type: PreferenceTypeString, Default: "en", MaxLength: 35},
+		{Key: PreferencePageSize, Type: PreferenceTypeInt, Default: 25, Min: 10, Max: 100},
+		{
+			Key:     PreferenceEmailDigest,
+			Type:    PreferenceTypeEnum,
+			Default: "off",
+			Allowed: []string{"off", "daily", "weekly"},
+		},
+		{Key: PreferenceNotificationSounds, Type: PreferenceTypeBool, Default: true},
+	}
+}
+
+// Lookup returns the definition of key.
+func (s PreferenceSchema) Lookup(key PreferenceKey) (PreferenceDefinition, bool) {
+	i := slices.IndexFunc(s, func(d PreferenceDefinition) bool { return d.Key == key })
+	if i < 0 {
+		return PreferenceDefinition{}, false
+	}
+
+	return s[i], true
+}
+
+// Validate checks the keys and values of patch against the schema and
+// returns it with normalized values.
+func (s PreferenceSchema) Validate(patch PreferencePatch) (PreferencePatch, error) {
+	normalized := PreferencePatch{
+		Set:   make(map[PreferenceKey]any, len(patch.Set)),
+		Unset: slices.Clone(patch.Unset),
+	}
+
+	for key, value := range patch.Set {
+		def, ok := s.Lookup(key)
+		if !ok || !key.IsValid() {
+			return PreferencePatch{}, fmt.Errorf("preference %v: %w", key, ErrUnknownPreference)
+		}
+
+		if slices.Contains(patch.Unset, key) {
+			return PreferencePatch{}, fmt.Errorf("preference %v set and unset: %w", key, ErrInvalidPreferenceValue)
+		}
+
+		v, err := def.Normalize(value)
+		if err != nil {
+			return PreferencePatch{}, err
+		}
+
+		normalized.Set[key] = v
+	}
+
+	for _, key := range patch.Unset {
+		if _, ok := s.Lookup(key); !ok || !key.IsValid() {
+			return PreferencePatch{}, fmt.Errorf("preference %v: %w", key, ErrUnknownPreference)
+		}
+	}
+
+	return normalized, nil
+}
+
+// PreferencePatch is a partial update of the preferences of a user.
+// Preferences it does not mention keep their stored values.
+type PreferencePatch struct {
+	// Set are the preferences to store.
+	Set map[PreferenceKey]any
+	// Unset are the preferences to remove, restoring their defaults.
+	Unset []PreferenceKey
+}
+
+// IsEmpty returns true if the patch changes nothing.
+func (p PreferencePatch) IsEmpty() bool {
+	return len(p.Set) == 0 && len(p.Unset) == 0
+}
+
+// UserPreferences are the preferences of one user. Preferences without a
+// stored value, or whose stored value no longer matches their definition,
+// read as their default.
+type UserPreferences struct {
+	UserID UserID
+	// Values are the stored preferences as decoded from the database.
+	Values    map[PreferenceKey]any
+	UpdatedAt time.Time
+
+	schema PreferenceSchema
+}
+
+// NewUserPreferences creates the preferences of userID from its stored
+// values, read with the DefaultPreferenceSchema.
+func NewUserPreferences(userID UserID, values map[PreferenceKey]any, updatedAt time.Time) *UserPreferences {
+	if values == nil {
+		values = make(map[PreferenceKey]any)
+	}
+
+	return &UserPreferences{
+		UserID:    userID,
+		Values:    values,
+		UpdatedAt: updatedAt,
+		schema:    DefaultPreferenceSchema(),
+	}
+}
+
+// WithSchema reads the preferences with schema and returns them.
+func (p *UserPreferences) WithSchema(schema PreferenceSchema) *UserPreferences {
+	p.schema = schema
+
+	return p
+}
+
+// Value returns the normalized value of key, its default if it has no
+// valid stored value, or nil if key is not in the schema.
+func (p *UserPreferences) Value(key PreferenceKey) any {
+	def, ok := p.schema.Lookup(key)
+	if !ok {
+		return nil
+	}
+
+	if stored, ok := p.Values[key]; ok {
+		value, err := def.Normalize(stored)
+		if err == nil {
+			return value
+		}
+	}
+
+	return def.Default
+}
+
+// String returns the value of a string or enum preference.
+func (p *UserPreferences) String(key PreferenceKey) string {
+	s, _ := p.Value(key).(string)
+
+	return s
+}
+
+// Bool returns the value of a bool preference.
+func (p *UserPreferences) Bool(key PreferenceKey) bool {
+	b, _ := p.Value(key).(bool)
+
+	return b
+}
+
+// Int returns the value of an int preference.
+func (p *UserPreferences) Int(key PreferenceKey) int {
+	n, _ := preferenceInt(p.Value(key))
+
+	return n
+}
+
+// Theme returns the color scheme of the user interface.
+func (p *UserPreferences) Theme() string { return p.String(PreferenceTheme) }
+
+// Locale returns the language tag of the user interface.
+func (p *UserPreferences) Locale() string { return p.String(PreferenceLocale) }
+
+// EmailDigest returns how often notifications are summarized by email.
+func (p *UserPreferences) EmailDigest() string { return p.String(PreferenceEmailDigest) }
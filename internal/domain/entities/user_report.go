@@ -0,0 +1,43 @@
+package entities
+
+import "time"
+
+// SignupSourceDirect is the source of users who signed up with a password
+// rather than through an identity provider.
+const SignupSourceDirect = "direct"
+
+// DailyLogins counts the login attempts of one UTC day.
+type DailyLogins struct {
+	Day      time.Time `json:"day"`
+	Logins   int64     `json:"logins"`
+	Failures int64     `json:"failures"`
+	// Users is the number of distinct users who logged in.
+	Users int64 `json:"users"`
+}
+
+// FailureRate returns the share of failed attempts, in percent.
+func (d DailyLogins) FailureRate() float64 {
+	attempts := d.Logins + d.Failures
+	if attempts == 0 {
+		return 0
+	}
+
+	return float64(d.Failures) / float64(attempts) * 100
+}
+
+// IPFailures counts the failed logins from one IP address.
+type IPFailures struct {
+	IPAddress string `json:"ipAddress"`
+	Failures  int64  `json:"failures"`
+	// Users is the number of distinct accounts the address failed on; many
+	// accounts from one address suggest credential stuffing.
+	Users  int64     `json:"users"`
+	LastAt time.Time `json:"lastAt"`
+}
+
+// SignupSource counts the users who signed up through one source, an
+// identity provider or SignupSourceDirect.
+type SignupSource struct {
+	Source  string `json:"source"`
+	Signups int64  `json:"signups"`
+}
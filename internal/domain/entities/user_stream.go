@@ -0,0 +1,297 @@
+package entities
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultSnapshotInterval is the number of events after which the state of
+// an event-sourced user is snapshotted, so that loading it replays at most
+// that many events.
+const DefaultSnapshotInterval = 50
+
+// UserEventType names a change in the event stream of a user. The values
+// are the event types of package events that report the same change.
+type UserEventType string
+
+// Types of the events in the stream of a user.
+const (
+	UserEventCreated         UserEventType = "user.created"
+	UserEventProfileUpdated  UserEventType = "profile.updated"
+	UserEventEmailChanged    UserEventType = "user.email.changed"
+	UserEventUsernameChanged UserEventType = "user.username.changed"
+	UserEventPasswordChanged UserEventType = "password.changed"
+	UserEventStatusChanged   UserEventType = "user.status.changed"
+	UserEventRoleChanged     UserEventType = "role.changed"
+	UserEventVerified        UserEventType = "user.verified"
+	UserEventLoggedIn        UserEventType = "user.login"
+	UserEventDeleted         UserEventType = "user.deleted"
+	UserEventRestored        UserEventType = "user.restored"
+)
+
+func (t UserEventType) String() string { return string(t) }
+
+// UserEventData holds the fields an event sets. Fields the event does not
+// set are nil.
+type UserEventData struct {
+	UUID      *uuid.UUID    `json:"uuid,omitempty"`
+	Email     *Email        `json:"email,omitempty"`
+	Username  *Username     `json:"username,omitempty"`
+	Password  *PasswordHash `json:"password,omitempty"`
+	FirstName *FirstName    `json:"firstName,omitempty"`
+	LastName  *LastName     `json:"lastName,omitempty"`
+	Status    *UserStatus   `json:"status,omitempty"`
+	Role      *UserRole     `json:"role,omitempty"`
+	Metadata  *UserMetadata `json:"metadata,omitempty"`
+	Tags      *[]string     `json:"tags,omitempty"`
+	// Verified, CreatedAt and LastLoginAt are only set by the creation event.
+	Verified    *bool      `json:"verified,omitempty"`
+	CreatedAt   *time.Time `json:"createdAt,omitempty"`
+	LastLoginAt *time.Time `json:"lastLoginAt,omitempty"`
+}
+
+// UserStreamEvent is an event in the stream of one user. The event store
+// numbers the events of a stream from 1 when it appends them.
+type UserStreamEvent struct {
+	UserID     UserID
+	Version    int64
+	Type       UserEventType
+	Data       UserEventData
+	OccurredAt time.Time
+}
+
+// UserStreamSnapshot is the state of a user after the first Version events
+// of its stream.
+type UserStreamSnapshot struct {
+	UserID  UserID
+	Version int64
+	// Deleted reports whether the user was soft deleted at Version.
+	Deleted   bool
+	State     UserRecord
+	CreatedAt time.Time
+}
+
+// NewUserCreatedEvent returns the event that starts the stream of user with
+// its current state. It occurs at the last update of user, so that a stream
+// started for a user that existed before keeps its timestamps.
+func NewUserCreatedEvent(user *User) *UserStreamEvent {
+	record := user.Record()
+
+	return &UserStreamEvent{
+		UserID: user.id,
+		Type:   UserEventCreated,
+		Data: UserEventData{
+			UUID:        &record.UUID,
+			Email:       &record.Email,
+			Username:    &record.Username,
+			Password:    &record.Password,
+			FirstName:   &record.FirstName,
+			LastName:    &record.LastName,
+			Status:      &record.Status,
+			Role:        &record.Role,
+			Metadata:    &record.Metadata,
+			Tags:        &record.Tags,
+			Verified:    &record.IsVerified,
+			CreatedAt:   &record.CreatedAt,
+			LastLoginAt: record.LastLoginAt,
+		},
+		OccurredAt: user.updatedAt,
+	}
+}
+
+// UserChangeEvents returns the events that change before into after, one
+// per kind of change, stamped with the update time of after. Metadata and
+// tags are compared by value.
+func UserChangeEvents(before, after *User) []*UserStreamEvent {
+	var changes []*UserStreamEvent
+
+	change := func(eventType UserEventType, data UserEventData) {
+		changes = append(changes, &UserStreamEvent{
+			UserID:     after.id,
+			Type:       eventType,
+			Data:       data,
+			OccurredAt: after.updatedAt,
+		})
+	}
+
+	var profile UserEventData
+
+	if before.firstName != after.firstName {
+		profile.FirstName = &after.firstName
+	}
+
+	if before.lastName != after.lastName {
+		profile.LastName = &after.lastName
+	}
+
+	if !reflect.DeepEqual(before.metadata, after.metadata) {
+		metadata := maps.Clone(after.metadata)
+		profile.Metadata = &metadata
+	}
+
+	if !slices.Equal(before.tags, after.tags) {
+		tags := slices.Clone(after.tags)
+		profile.Tags = &tags
+	}
+
+	if profile != (UserEventData{}) {
+		change(UserEventProfileUpdated, profile)
+	}
+
+	if before.email != after.email {
+		change(UserEventEmailChanged, UserEventData{Email: &after.email})
+	}
+
+	if before.username != after.username {
+		change(UserEventUsernameChanged, UserEventData{Username: &after.username})
+	}
+
+	if before.password != after.password {
+		change(UserEventPasswordChanged, UserEventData{Password: &after.password})
+	}
+
+	if before.status != after.status {
+		change(UserEventStatusChanged, UserEventData{Status: &after.status})
+	}
+
+	if before.role != after.role {
+		change(UserEventRoleChanged, UserEventData{Role: &after.role})
+	}
+
+	if !before.isVerified && after.isVerified {
+		change(UserEventVerified, UserEventData{})
+	}
+
+	if after.lastLoginAt != nil && (before.lastLoginAt == nil || !before.lastLoginAt.Equal(*after.lastLoginAt)) {
+		changes = append(changes, &UserStreamEvent{
+			UserID:     after.id,
+			Type:       UserEventLoggedIn,
+			OccurredAt: *after.lastLoginAt,
+		})
+	}
+
+	return changes
+}
+
+// Apply changes the user as event reports and sets its update time to when
+// the event occurred. Only a user that has not been created yet, such as
+// new(User), accepts the creation event, and only a created user accepts
+// the others. Deletions and restores leave the state unchanged; whether a
+// user is deleted is tracked by its repository.
+func (u *User) Apply(event *UserStreamEvent) error {
+	created := u.uuid != uuid.Nil
+	if created == (event.Type == UserEventCreated) {
+		return fmt.Errorf("apply %v to user=%v: %w", event.Type, event.UserID, ErrInvalidUserEvent)
+	}
+
+	var err error
+
+	switch event.Type {
+	case UserEventCreated:
+		err = u.applyCreated(event)
+	case UserEventProfileUpdated:
+		err = u.UpdateProfile(event.Data.FirstName, event.Data.LastName, event.Data.Metadata, event.Data.Tags)
+	case UserEventEmailChanged, UserEventUsernameChanged, UserEventPasswordChanged:
+		err = u.applyIdentity(event.Data)
+	case UserEventStatusChanged:
+		err = applyField(event.Data.Status, u.ChangeStatus)
+	case UserEventRoleChanged:
+		err = applyField(event.Data.Role, u.ChangeRole)
+	case UserEventVerified:
+		u.Verify()
+	case UserEventLoggedIn:
+		loggedInAt := event.OccurredAt
+		u.lastLoginAt = &loggedInAt
+	case UserEventDeleted, UserEventRestored:
+	default:
+		err = ErrInvalidUserEvent
+	}
+
+	if err != nil {
+		return fmt.Errorf("apply %v to user=%v: %w", event.Type, event.UserID, err)
+	}
+
+	u.updatedAt = event.OccurredAt
+
+	return nil
+}
+
+// applyCreated sets the initial state of the user from the creation event.
+func (u *User) applyCreated(event *UserStreamEvent) error {
+	data := event.Data
+	if data.UUID == nil || data.Email == nil || data.Username == nil || data.Password == nil ||
+		data.FirstName == nil || data.LastName == nil || data.Status == nil || data.Role == nil {
+		return ErrInvalidUserEvent
+	}
+
+	record := UserRecord{
+		ID:          event.UserID,
+		UUID:        *data.UUID,
+		Email:       *data.Email,
+		Username:    *data.Username,
+		Password:    *data.Password,
+		FirstName:   *data.FirstName,
+		LastName:    *data.LastName,
+		Status:      *data.Status,
+		Role:        *data.Role,
+		CreatedAt:   event.OccurredAt,
+		UpdatedAt:   event.OccurredAt,
+		LastLoginAt: data.LastLoginAt,
+	}
+
+	if data.CreatedAt != nil {
+		record.CreatedAt = *data.CreatedAt
+	}
+
+	if data.Verified != nil {
+		record.IsVerified = *data.Verified
+	}
+
+	if data.Metadata != nil {
+		record.Metadata = *data.Metadata
+	}
+
+	if data.Tags != nil {
+		record.Tags = *data.Tags
+	}
+
+	user, err := ReconstructUser(record)
+	if err != nil {
+		return err
+	}
+
+	*u = *user
+
+	return nil
+}
+
+// applyIdentity sets the email address, username or password hash the
+// event carries.
+func (u *User) applyIdentity(data UserEventData) error {
+	switch {
+	case data.Email != nil:
+		u.email = *data.Email
+	case data.Username != nil:
+		u.username = *data.Username
+	case data.Password != nil:
+		u.password = *data.Password
+	default:
+		return ErrInvalidUserEvent
+	}
+
+	return nil
+}
+
+// applyField passes the value an event carries to set.
+func applyField[T any](value *T, set func(T) error) error {
+	if value == nil {
+		return ErrInvalidUserEvent
+	}
+
+	return set(*value)
+}
@@ -0,0 +1,76 @@
+package entities
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserView is a user as the read model lists it: the fields listings and
+// searches show and filter by, denormalized into one row so that they are
+// served without the users table. Views are projected from the domain
+// events and may lag behind the users they show.
+type UserView struct {
+	UserID     UserID
+	UUID       uuid.UUID
+	Email      Email
+	Username   Username
+	FullName   string
+	Status     UserStatus
+	Role       UserRole
+	Tags       []string
+	IsVerified bool
+	CreatedAt  time.Time
+	// SourceUpdatedAt is the update time of the user the view was projected
+	// from, ProjectedAt when it was projected.
+	SourceUpdatedAt time.Time
+	ProjectedAt     time.Time
+}
+
+// NewUserView returns the view of user projected at projectedAt.
+func NewUserView(user *User, projectedAt time.Time) *UserView {
+	return &UserView{
+		UserID:          user.ID(),
+		UUID:            user.UUID(),
+		Email:           user.Email(),
+		Username:        user.Username(),
+		FullName:        strings.TrimSpace(user.FirstName().String() + " " + user.LastName().String()),
+		Status:          user.Status(),
+		Role:            user.Role(),
+		Tags:            slices.Clone(user.Tags()),
+		IsVerified:      user.IsVerified(),
+		CreatedAt:       user.CreatedAt(),
+		SourceUpdatedAt: user.UpdatedAt(),
+		ProjectedAt:     projectedAt,
+	}
+}
+
+// SearchText returns the words of the email, username and full name that
+// query terms are matched against, each preceded by a space, so that a term
+// is a prefix of a word where " "+term occurs.
+func (v *UserView) SearchText() string {
+	words := QueryTerms(strings.Join([]string{v.Email.String(), v.Username.String(), v.FullName}, " "))
+
+	return " " + strings.Join(words, " ")
+}
+
+// MatchesView reports whether view satisfies the filter, so that in-memory
+// read models evaluate filters the way the databases do.
+func (f UserFilter) MatchesView(view *UserView) bool {
+	after, before := f.CreatedRange()
+	text := view.SearchText()
+
+	return (len(f.Statuses) == 0 || slices.Contains(f.Statuses, view.Status)) &&
+		(len(f.Roles) == 0 || slices.Contains(f.Roles, view.Role)) &&
+		(f.Verified == nil || *f.Verified == view.IsVerified) &&
+		view.CreatedAt.After(after) && view.CreatedAt.Before(before) &&
+		(len(f.TagsAny) == 0 || slices.ContainsFunc(f.TagsAny, func(tag string) bool {
+			return slices.Contains(view.Tags, tag)
+		})) &&
+		!slices.ContainsFunc(f.TagsAll, func(tag string) bool { return !slices.Contains(view.Tags, tag) }) &&
+		!slices.ContainsFunc(QueryTerms(f.Query), func(term string) bool {
+			return !strings.Contains(text, " "+term)
+		})
+}
@@ -0,0 +1,194 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+)
+
+// CloudEventsSpecVersion is the CloudEvents specification version produced by CloudEventCodec.
+const CloudEventsSpecVersion = "1.0"
+
+const (
+	// CloudEventsContentType is the media type of structured-mode JSON CloudEvents.
+	CloudEventsContentType = "application/cloudevents+json"
+
+	cloudEventsDataContentType = "application/json"
+	cloudEventsHeaderPrefix    = "Ce-"
+)
+
+// ErrInvalidCloudEvent is returned when a CloudEvent cannot be mapped to a UserEvent.
+var ErrInvalidCloudEvent = errors.New("invalid cloud event")
+
+// CloudEvent is the CloudEvents 1.0 envelope of a UserEvent.
+// The event version is carried in the "eventversion" extension attribute and
+// the emitting trace in the "traceparent" distributed tracing extension.
+type CloudEvent struct {
+	SpecVersion     string          `json:"specversion"`
+	ID              string          `json:"id"`
+	Source          string          `json:"source"`
+	Type            string          `json:"type"`
+	Subject         string          `json:"subject,omitempty"`
+	Time            time.Time       `json:"time"`
+	DataContentType string          `json:"datacontenttype,omitempty"`
+	EventVersion    string          `json:"eventversion,omitempty"`
+	TraceParent     string          `json:"traceparent,omitempty"`
+	Data            json.RawMessage `json:"data,omitempty"`
+}
+
+// CloudEventCodec maps UserEvents to and from CloudEvents.
+//
+// The CloudEvent type is TypePrefix followed by the EventType, e.g.
+// "com.example.users.user.created", and the subject is the user ID.
+type CloudEventCodec struct {
+	// Source identifies the producing service, e.g. "/template-sqlc/users".
+	Source string
+
+	// TypePrefix is prepended to the EventType to form the CloudEvent type.
+	TypePrefix string
+}
+
+// Envelope wraps event in a CloudEvent.
+func (c CloudEventCodec) Envelope(event *UserEvent) (*CloudEvent, error) {
+	data, err := json.Marshal(event.Data)
+	if err != nil {
+		return nil, fmt.Errorf("event=%v: %w", event.ID, err)
+	}
+
+	return &CloudEvent{
+		SpecVersion:     CloudEventsSpecVersion,
+		ID:              strconv.FormatInt(event.ID.Int64(), 10),
+		Source:          c.Source,
+		Type:            c.TypePrefix + event.Type.String(),
+		Subject:         strconv.FormatInt(event.UserID.Int64(), 10),
+		Time:            event.Timestamp.UTC(),
+		DataContentType: cloudEventsDataContentType,
+		EventVersion:    event.Version,
+		TraceParent:     event.TraceParent,
+		Data:            data,
+	}, nil
+}
+
+// Open unwraps a CloudEvent into a UserEvent. Data is kept as json.RawMessage
+// so consumers can decode it into the payload type matching the event type.
+func (c CloudEventCodec) Open(envelope *CloudEvent) (*UserEvent, error) {
+	if envelope.SpecVersion != CloudEventsSpecVersion {
+		return nil, fmt.Errorf("specversion=%v: %w", envelope.SpecVersion, ErrInvalidCloudEvent)
+	}
+
+	eventType, ok := strings.CutPrefix(envelope.Type, c.TypePrefix)
+	if !ok || eventType == "" {
+		return nil, fmt.Errorf("type=%v: %w", envelope.Type, ErrInvalidCloudEvent)
+	}
+
+	id, err := strconv.ParseInt(envelope.ID, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("id=%v: %w", envelope.ID, ErrInvalidCloudEvent)
+	}
+
+	userID, err := strconv.ParseInt(envelope.Subject, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("subject=%v: %w", envelope.Subject, ErrInvalidCloudEvent)
+	}
+
+	event := &UserEvent{
+		ID:          entities.AsIDID(id),
+		Type:        EventType(eventType),
+		UserID:      entities.UserID(userID),
+		Timestamp:   envelope.Time,
+		Version:     envelope.EventVersion,
+		TraceParent: envelope.TraceParent,
+	}
+
+	if len(envelope.Data) > 0 {
+		event.Data = envelope.Data
+	}
+
+	return event, nil
+}
+
+// ContentType returns the structured-mode media type.
+func (c CloudEventCodec) ContentType() string {
+	return CloudEventsContentType
+}
+
+// Serialize renders event as a structured-mode JSON CloudEvent.
+func (c CloudEventCodec) Serialize(event *UserEvent) ([]byte, error) {
+	envelope, err := c.Envelope(event)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := json.Marshal(envelope)
+	if err != nil {
+		return nil, fmt.Errorf("event=%v: %w", event.ID, err)
+	}
+
+	return payload, nil
+}
+
+// Deserialize parses a structured-mode JSON CloudEvent.
+func (c CloudEventCodec) Deserialize(payload []byte) (*UserEvent, error) {
+	var envelope CloudEvent
+
+	err := json.Unmarshal(payload, &envelope)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCloudEvent, err)
+	}
+
+	return c.Open(&envelope)
+}
+
+// WriteBinary renders event in binary HTTP mode: attributes go into ce-
+// headers and the body is the event data.
+func (c CloudEventCodec) WriteBinary(header http.Header, event *UserEvent) ([]byte, error) {
+	envelope, err := c.Envelope(event)
+	if err != nil {
+		return nil, err
+	}
+
+	header.Set("Content-Type", envelope.DataContentType)
+	header.Set(cloudEventsHeaderPrefix+"Specversion", envelope.SpecVersion)
+	header.Set(cloudEventsHeaderPrefix+"Id", envelope.ID)
+	header.Set(cloudEventsHeaderPrefix+"Source", envelope.Source)
+	header.Set(cloudEventsHeaderPrefix+"Type", envelope.Type)
+	header.Set(cloudEventsHeaderPrefix+"Subject", envelope.Subject)
+	header.Set(cloudEventsHeaderPrefix+"Time", envelope.Time.Format(time.RFC3339Nano))
+
+	if envelope.EventVersion != "" {
+		header.Set(cloudEventsHeaderPrefix+"Eventversion", envelope.EventVersion)
+	}
+
+	if envelope.TraceParent != "" {
+		header.Set(cloudEventsHeaderPrefix+"Traceparent", envelope.TraceParent)
+	}
+
+	return envelope.Data, nil
+}
+
+// ReadBinary parses a binary HTTP mode CloudEvent.
+func (c CloudEventCodec) ReadBinary(header http.Header, body []byte) (*UserEvent, error) {
+	timestamp, err := time.Parse(time.RFC3339Nano, header.Get(cloudEventsHeaderPrefix+"Time"))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCloudEvent, err)
+	}
+
+	return c.Open(&CloudEvent{
+		SpecVersion:     header.Get(cloudEventsHeaderPrefix + "Specversion"),
+		ID:              header.Get(cloudEventsHeaderPrefix + "Id"),
+		Source:          header.Get(cloudEventsHeaderPrefix + "Source"),
+		Type:            header.Get(cloudEventsHeaderPrefix + "Type"),
+		Subject:         header.Get(cloudEventsHeaderPrefix + "Subject"),
+		Time:            timestamp,
+		DataContentType: header.Get("Content-Type"),
+		EventVersion:    header.Get(cloudEventsHeaderPrefix + "Eventversion"),
+		TraceParent:     header.Get(cloudEventsHeaderPrefix + "Traceparent"),
+		Data:            body,
+	})
+}
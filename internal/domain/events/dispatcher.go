@@ -0,0 +1,308 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// ErrDispatcherClosed is returned when events are published to a closed dispatcher.
+var ErrDispatcherClosed = errors.New("event dispatcher closed")
+
+const (
+	defaultDispatchConcurrency = 4
+	defaultDispatchQueueSize   = 256
+	defaultDispatchAttempts    = 3
+	defaultDispatchBackoff     = 50 * time.Millisecond
+)
+
+// EventHandler reacts to a domain event. Handlers may see an event more than
+// once and must therefore be idempotent.
+type EventHandler interface {
+	Handle(ctx context.Context, event *UserEvent) error
+}
+
+// EventHandlerFunc adapts a function to EventHandler.
+type EventHandlerFunc func(ctx context.Context, event *UserEvent) error
+
+// Handle calls f.
+func (f EventHandlerFunc) Handle(ctx context.Context, event *UserEvent) error {
+	return f(ctx, event)
+}
+
+// EventSubscriber registers handlers for domain events.
+type EventSubscriber interface {
+	// Subscribe registers handler under name for the given event types,
+	// or for all event types if none are given.
+	Subscribe(name string, handler EventHandler, types ...EventType)
+}
+
+// DeadLetter is an event a handler failed to process after all attempts.
+type DeadLetter struct {
+	Event    *UserEvent
+	Handler  string
+	Attempts int
+	Err      error
+}
+
+// DeadLetterHandler receives events that exhausted their delivery attempts.
+type DeadLetterHandler func(letter DeadLetter)
+
+// subscription is a named handler.
+type subscription struct {
+	name    string
+	handler EventHandler
+}
+
+// delivery is an event queued for a single subscription.
+type delivery struct {
+	event *UserEvent
+	sub   subscription
+}
+
+// Dispatcher routes published events to subscribed handlers in-process.
+// Each handler receives every matching event at least once: failed or
+// panicking handlers are retried, and events that still fail are passed to
+// the dead-letter handler. Events are handled concurrently, so handlers must
+// not depend on delivery order.
+type Dispatcher struct {
+	concurrency int
+	attempts    int
+	backoff     time.Duration
+	deadLetter  DeadLetterHandler
+
+	subsMu   sync.RWMutex
+	byType   map[EventType][]subscription
+	wildcard []subscription
+
+	queueMu sync.RWMutex
+	closed  bool
+	queue   chan delivery
+
+	ctx     context.Context
+	cancel  context.CancelFunc
+	workers sync.WaitGroup
+}
+
+var (
+	_ EventPublisher  = (*Dispatcher)(nil)
+	_ EventSubscriber = (*Dispatcher)(nil)
+)
+
+// DispatcherOption configures a Dispatcher.
+type DispatcherOption func(*Dispatcher)
+
+// WithConcurrency sets how many events are handled in parallel.
+func WithConcurrency(workers int) DispatcherOption {
+	return func(d *Dispatcher) {
+		if workers > 0 {
+			d.concurrency = workers
+		}
+	}
+}
+
+// WithQueueSize sets how many deliveries may be pending before Publish blocks.
+func WithQueueSize(size int) DispatcherOption {
+	return func(d *Dispatcher) {
+		if size > 0 {
+			d.queue = make(chan delivery, size)
+		}
+	}
+}
+
+// WithDeliveryAttempts sets how often a handler is tried before the event is
+// dead-lettered, and the backoff between attempts, which doubles each retry.
+func WithDeliveryAttempts(attempts int, backoff time.Duration) DispatcherOption {
+	return func(d *Dispatcher) {
+		if attempts > 0 {
+			d.attempts = attempts
+		}
+
+		if backoff >= 0 {
+			d.backoff = backoff
+		}
+	}
+}
+
+// WithDeadLetterHandler replaces the default dead-letter handler, which logs the failure.
+func WithDeadLetterHandler(handler DeadLetterHandler) DispatcherOption {
+	return func(d *Dispatcher) {
+		if handler != nil {
+			d.deadLetter = handler
+		}
+	}
+}
+
+// NewDispatcher creates a dispatcher and starts its workers.
+// Close must be called to stop them.
+func NewDispatcher(opts ...DispatcherOption) *Dispatcher {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	d := &Dispatcher{
+		concurrency: defaultDispatchConcurrency,
+		attempts:    defaultDispatchAttempts,
+		backoff:     defaultDispatchBackoff,
+		deadLetter:  logDeadLetter,
+		byType:      make(map[EventType][]subscription),
+		queue:       make(chan delivery, defaultDispatchQueueSize),
+		ctx:         ctx,
+		cancel:      cancel,
+	}
+
+	for _, opt := range opts {
+		opt(d)
+	}
+
+	for range d.concurrency {
+		d.workers.Add(1)
+
+		go d.work()
+	}
+
+	return d
+}
+
+// Subscribe registers handler under name for the given event types,
+// or for all event types if none are given.
+func (d *Dispatcher) Subscribe(name string, handler EventHandler, types ...EventType) {
+	d.subsMu.Lock()
+	defer d.subsMu.Unlock()
+
+	sub := subscription{name: name, handler: handler}
+
+	if len(types) == 0 {
+		d.wildcard = append(d.wildcard, sub)
+
+		return
+	}
+
+	for _, eventType := range types {
+		d.byType[eventType] = append(d.byType[eventType], sub)
+	}
+}
+
+// Publish queues event for every matching handler.
+// It blocks while the queue is full.
+func (d *Dispatcher) Publish(event *UserEvent) error {
+	return d.PublishBatch([]*UserEvent{event})
+}
+
+// PublishBatch queues events for every matching handler.
+func (d *Dispatcher) PublishBatch(events []*UserEvent) error {
+	d.queueMu.RLock()
+	defer d.queueMu.RUnlock()
+
+	if d.closed {
+		return ErrDispatcherClosed
+	}
+
+	for _, event := range events {
+		for _, sub := range d.subscriptions(event.Type) {
+			d.queue <- delivery{event: event, sub: sub}
+		}
+	}
+
+	return nil
+}
+
+// Close stops accepting events and waits until queued events are handled.
+// If ctx is done first, in-flight handlers are cancelled and ctx's error is returned.
+func (d *Dispatcher) Close(ctx context.Context) error {
+	d.queueMu.Lock()
+	if !d.closed {
+		d.closed = true
+		close(d.queue)
+	}
+	d.queueMu.Unlock()
+
+	done := make(chan struct{})
+
+	go func() {
+		d.workers.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		d.cancel()
+
+		return nil
+	case <-ctx.Done():
+		d.cancel()
+
+		return fmt.Errorf("failed to drain event dispatcher: %w", ctx.Err())
+	}
+}
+
+// subscriptions returns the handlers for eventType.
+func (d *Dispatcher) subscriptions(eventType EventType) []subscription {
+	d.subsMu.RLock()
+	defer d.subsMu.RUnlock()
+
+	subs := make([]subscription, 0, len(d.byType[eventType])+len(d.wildcard))
+	subs = append(subs, d.byType[eventType]...)
+
+	return append(subs, d.wildcard...)
+}
+
+// work handles queued deliveries until the queue is closed.
+func (d *Dispatcher) work() {
+	defer d.workers.Done()
+
+	for item := range d.queue {
+		d.deliver(item)
+	}
+}
+
+// deliver runs a handler with retries and dead-letters the event if all attempts fail.
+func (d *Dispatcher) deliver(item delivery) {
+	var err error
+
+	backoff := d.backoff
+
+	for attempt := 1; attempt <= d.attempts; attempt++ {
+		err = d.handle(item)
+		if err == nil {
+			return
+		}
+
+		if attempt == d.attempts || d.ctx.Err() != nil {
+			d.deadLetter(DeadLetter{Event: item.event, Handler: item.sub.name, Attempts: attempt, Err: err})
+
+			return
+		}
+
+		select {
+		case <-d.ctx.Done():
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+	}
+}
+
+// handle runs a handler once, turning a panic into an error.
+func (d *Dispatcher) handle(item delivery) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("handler=%v: panic: %v", item.sub.name, r)
+		}
+	}()
+
+	return item.sub.handler.Handle(d.ctx, item.event)
+}
+
+// logDeadLetter is the default dead-letter handler.
+func logDeadLetter(letter DeadLetter) {
+	slog.Error("event handler failed",
+		"handler", letter.Handler,
+		"event_type", letter.Event.Type,
+		"event_id", letter.Event.ID,
+		"user_id", letter.Event.UserID,
+		"attempts", letter.Attempts,
+		"error", letter.Err,
+	)
+}
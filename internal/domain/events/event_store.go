@@ -0,0 +1,81 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// StoredEvent is a domain event together with its position in the event store.
+// Positions are assigned on append, start at 1, and increase monotonically.
+type StoredEvent struct {
+	Position int64      `json:"position"`
+	Event    *UserEvent `json:"event"`
+}
+
+// EventStore persists domain events in order and streams them back for replay.
+type EventStore interface {
+	Append(ctx context.Context, events ...*UserEvent) error
+	// ReadFrom returns up to limit events with a position greater than after.
+	ReadFrom(ctx context.Context, after int64, limit int) ([]StoredEvent, error)
+}
+
+// InMemoryEventStore is a simple in-memory event store.
+type InMemoryEventStore struct {
+	mu     sync.RWMutex
+	events []StoredEvent
+}
+
+// NewInMemoryEventStore creates a new InMemoryEventStore.
+func NewInMemoryEventStore() *InMemoryEventStore {
+	return &InMemoryEventStore{
+		mu:     sync.RWMutex{},
+		events: make([]StoredEvent, 0),
+	}
+}
+
+// Append stores events after the current head of the store.
+func (s *InMemoryEventStore) Append(_ context.Context, events ...*UserEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, event := range events {
+		s.events = append(s.events, StoredEvent{
+			Position: int64(len(s.events)) + 1,
+			Event:    event,
+		})
+	}
+
+	return nil
+}
+
+// ReadFrom returns up to limit events with a position greater than after.
+func (s *InMemoryEventStore) ReadFrom(
+	ctx context.Context,
+	after int64,
+	limit int,
+) ([]StoredEvent, error) {
+	err := ctx.Err()
+	if err != nil {
+		return nil, fmt.Errorf("read events after=%d: %w", after, err)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	start := min(max(after, 0), int64(len(s.events)))
+	end := min(start+int64(max(limit, 0)), int64(len(s.events)))
+
+	batch := make([]StoredEvent, end-start)
+	copy(batch, s.events[start:end])
+
+	return batch, nil
+}
+
+// Head returns the position of the last appended event.
+func (s *InMemoryEventStore) Head() int64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return int64(len(s.events))
+}
@@ -0,0 +1,39 @@
+package events
+
+import "errors"
+
+// FanOutPublisher publishes every event to each of its publishers, for
+// example to a message broker and to the in-process dispatcher.
+type FanOutPublisher struct {
+	publishers []EventPublisher
+}
+
+var _ EventPublisher = (*FanOutPublisher)(nil)
+
+// NewFanOutPublisher creates a publisher that publishes to each of publishers.
+func NewFanOutPublisher(publishers ...EventPublisher) *FanOutPublisher {
+	return &FanOutPublisher{publishers: publishers}
+}
+
+// Publish publishes event to each publisher. A failing publisher does not
+// keep the event from the others; their errors are joined.
+func (p *FanOutPublisher) Publish(event *UserEvent) error {
+	errs := make([]error, 0, len(p.publishers))
+
+	for _, publisher := range p.publishers {
+		errs = append(errs, publisher.Publish(event))
+	}
+
+	return errors.Join(errs...)
+}
+
+// PublishBatch publishes events to each publisher, like Publish.
+func (p *FanOutPublisher) PublishBatch(events []*UserEvent) error {
+	errs := make([]error, 0, len(p.publishers))
+
+	for _, publisher := range p.publishers {
+		errs = append(errs, publisher.PublishBatch(events))
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,46 @@
+package events
+
+import "slices"
+
+// FilteringPublisher drops the events of some types and publishes the
+// others to the next publisher, for example to leave the events of table
+// changes to a changefeed.
+type FilteringPublisher struct {
+	next    EventPublisher
+	dropped map[EventType]bool
+}
+
+var _ EventPublisher = (*FilteringPublisher)(nil)
+
+// NewFilteringPublisher creates a publisher that drops the events of the
+// dropped types and publishes the others to next.
+func NewFilteringPublisher(next EventPublisher, dropped ...EventType) *FilteringPublisher {
+	p := &FilteringPublisher{next: next, dropped: make(map[EventType]bool, len(dropped))}
+
+	for _, eventType := range dropped {
+		p.dropped[eventType] = true
+	}
+
+	return p
+}
+
+// Publish publishes event unless its type is dropped.
+func (p *FilteringPublisher) Publish(event *UserEvent) error {
+	if p.dropped[event.Type] {
+		return nil
+	}
+
+	return p.next.Publish(event)
+}
+
+// PublishBatch publishes the events whose types are not dropped.
+func (p *FilteringPublisher) PublishBatch(events []*UserEvent) error {
+	kept := slices.DeleteFunc(slices.Clone(events), func(event *UserEvent) bool {
+		return p.dropped[event.Type]
+	})
+	if len(kept) == 0 {
+		return nil
+	}
+
+	return p.next.PublishBatch(kept)
+}
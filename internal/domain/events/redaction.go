@@ -0,0 +1,317 @@
+package events
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrInvalidRedactionMode is returned for a redaction policy with an unknown mode.
+var ErrInvalidRedactionMode = errors.New("invalid redaction mode")
+
+// RedactionMode says how personal data is redacted.
+type RedactionMode string
+
+// Supported redaction modes.
+const (
+	// RedactionNone keeps personal data, for tests and trusted consumers.
+	RedactionNone RedactionMode = "none"
+	// RedactionMask hides most of a value but keeps its shape: the domain of
+	// email addresses, the network of IP addresses and the first character
+	// of anything else.
+	RedactionMask RedactionMode = "mask"
+	// RedactionHash replaces a value with a keyed hash, so equal values can
+	// still be correlated, e.g. failed logins per IP address.
+	RedactionHash RedactionMode = "hash"
+)
+
+// IsValid returns true if the redaction mode is a valid value.
+func (m RedactionMode) IsValid() bool {
+	switch m {
+	case RedactionNone, RedactionMask, RedactionHash:
+		return true
+	default:
+		return false
+	}
+}
+
+// PIIField is a kind of personal data carried by events and log attributes.
+type PIIField string
+
+// Personal data recognized by the redaction policy.
+const (
+	PIIEmail     PIIField = "email"
+	PIIName      PIIField = "name"
+	PIIUsername  PIIField = "username"
+	PIIIPAddress PIIField = "ip_address"
+	PIIUserAgent PIIField = "user_agent"
+	// PIISubject is the user ID at an external identity provider.
+	PIISubject PIIField = "subject"
+)
+
+// hashPrefix marks hashed values.
+const hashPrefix = "sha256:"
+
+// hashLength is the number of hash bytes kept in hashed values.
+const hashLength = 16
+
+// maskedValue replaces the hidden part of masked values.
+const maskedValue = "***"
+
+// Prefix lengths of the networks that masked IP addresses are reduced to.
+const (
+	maskedIPv4Bits = 24
+	maskedIPv6Bits = 48
+)
+
+// PIIFieldOf returns the personal data held under a JSON or log attribute
+// key, in snake or camel case, such as "email", "first_name" or "ipAddress".
+func PIIFieldOf(key string) (PIIField, bool) {
+	switch strings.ToLower(strings.ReplaceAll(key, "_", "")) {
+	case "email":
+		return PIIEmail, true
+	case "firstname", "lastname":
+		return PIIName, true
+	case "username":
+		return PIIUsername, true
+	case "ip", "ipaddress":
+		return PIIIPAddress, true
+	case "useragent":
+		return PIIUserAgent, true
+	case "subject":
+		return PIISubject, true
+	}
+
+	return "", false
+}
+
+// RedactionPolicy says how each kind of personal data is redacted in
+// published events and logs.
+type RedactionPolicy struct {
+	// Mode applies to every field without an override.
+	Mode RedactionMode
+	// Fields overrides Mode per field.
+	Fields map[PIIField]RedactionMode
+	// HashKey keys the hashes so that they cannot be reversed by hashing
+	// guessed values. It must be kept secret and stable to correlate values
+	// over time.
+	HashKey []byte
+}
+
+// FullPayloads returns the policy that keeps all personal data, for tests.
+func FullPayloads() RedactionPolicy {
+	return RedactionPolicy{Mode: RedactionNone}
+}
+
+// ModeFor returns the redaction mode of field.
+func (p RedactionPolicy) ModeFor(field PIIField) RedactionMode {
+	if mode, ok := p.Fields[field]; ok {
+		return mode
+	}
+
+	return p.Mode
+}
+
+// KeepsAll returns true if the policy redacts nothing.
+func (p RedactionPolicy) KeepsAll() bool {
+	if p.Mode != RedactionNone {
+		return false
+	}
+
+	for _, mode := range p.Fields {
+		if mode != RedactionNone {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Validate reports unknown modes.
+func (p RedactionPolicy) Validate() error {
+	if !p.Mode.IsValid() {
+		return fmt.Errorf("redaction mode=%v: %w", p.Mode, ErrInvalidRedactionMode)
+	}
+
+	for field, mode := range p.Fields {
+		if !mode.IsValid() {
+			return fmt.Errorf("redaction field=%v mode=%v: %w", field, mode, ErrInvalidRedactionMode)
+		}
+	}
+
+	return nil
+}
+
+// Redact returns value redacted as field. Empty values are kept.
+func (p RedactionPolicy) Redact(field PIIField, value string) string {
+	if value == "" {
+		return value
+	}
+
+	switch p.ModeFor(field) {
+	case RedactionNone:
+		return value
+	case RedactionHash:
+		mac := hmac.New(sha256.New, p.HashKey)
+		mac.Write([]byte(value))
+
+		return hashPrefix + hex.EncodeToString(mac.Sum(nil)[:hashLength])
+	case RedactionMask:
+		return mask(field, value)
+	}
+
+	// Unknown modes redact fully rather than leak.
+	return maskedValue
+}
+
+// mask hides most of value but keeps its shape.
+func mask(field PIIField, value string) string {
+	if field == PIIIPAddress {
+		if ip := net.ParseIP(value); ip != nil {
+			if v4 := ip.To4(); v4 != nil {
+				return v4.Mask(net.CIDRMask(maskedIPv4Bits, 8*net.IPv4len)).String()
+			}
+
+			return ip.Mask(net.CIDRMask(maskedIPv6Bits, 8*net.IPv6len)).String()
+		}
+	}
+
+	if field == PIIEmail {
+		if local, domain, ok := strings.Cut(value, "@"); ok {
+			return maskPrefix(local) + "@" + domain
+		}
+	}
+
+	return maskPrefix(value)
+}
+
+// maskPrefix keeps the first character of value.
+func maskPrefix(value string) string {
+	first, _ := utf8.DecodeRuneInString(value)
+	if first == utf8.RuneError {
+		return maskedValue
+	}
+
+	return string(first) + maskedValue
+}
+
+// RedactJSON redacts the string values held under personal data keys of
+// the JSON document data, at any depth.
+func (p RedactionPolicy) RedactJSON(data []byte) ([]byte, error) {
+	var document any
+
+	// Numbers are kept as written, so that large IDs survive.
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	err := decoder.Decode(&document)
+	if err != nil {
+		return nil, fmt.Errorf("decode document: %w", err)
+	}
+
+	redacted, err := json.Marshal(p.redactValue(document, ""))
+	if err != nil {
+		return nil, fmt.Errorf("encode document: %w", err)
+	}
+
+	return redacted, nil
+}
+
+// redactValue redacts the strings in value. field is the personal data
+// that value is held under, if any; it also applies to nested values, such
+// as the old and new names of a change.
+func (p RedactionPolicy) redactValue(value any, field PIIField) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, nested := range v {
+			nestedField := field
+			if pii, ok := PIIFieldOf(key); ok {
+				nestedField = pii
+			}
+
+			v[key] = p.redactValue(nested, nestedField)
+		}
+	case []any:
+		for i, nested := range v {
+			v[i] = p.redactValue(nested, field)
+		}
+	case string:
+		if field != "" {
+			return p.Redact(field, v)
+		}
+	}
+
+	return value
+}
+
+// RedactEvent returns a copy of event whose data has its personal data
+// redacted and is held as json.RawMessage. event is not changed.
+func (p RedactionPolicy) RedactEvent(event *UserEvent) (*UserEvent, error) {
+	if p.KeepsAll() || event.Data == nil {
+		return event, nil
+	}
+
+	data, err := json.Marshal(event.Data)
+	if err != nil {
+		return nil, fmt.Errorf("encode event data id=%v: %w", event.ID, err)
+	}
+
+	data, err = p.RedactJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("redact event id=%v: %w", event.ID, err)
+	}
+
+	redacted := *event
+	redacted.Data = json.RawMessage(data)
+
+	return &redacted, nil
+}
+
+// RedactingPublisher redacts the personal data of events before passing
+// them to the next publisher.
+type RedactingPublisher struct {
+	next   EventPublisher
+	policy RedactionPolicy
+}
+
+var _ EventPublisher = (*RedactingPublisher)(nil)
+
+// NewRedactingPublisher creates a publisher that redacts events with policy
+// and publishes them to next.
+func NewRedactingPublisher(next EventPublisher, policy RedactionPolicy) *RedactingPublisher {
+	return &RedactingPublisher{next: next, policy: policy}
+}
+
+// Publish redacts and publishes event.
+func (p *RedactingPublisher) Publish(event *UserEvent) error {
+	redacted, err := p.policy.RedactEvent(event)
+	if err != nil {
+		return err
+	}
+
+	return p.next.Publish(redacted)
+}
+
+// PublishBatch redacts and publishes events. Nothing is published if any
+// of them cannot be redacted.
+func (p *RedactingPublisher) PublishBatch(events []*UserEvent) error {
+	redacted := make([]*UserEvent, 0, len(events))
+
+	for _, event := range events {
+		r, err := p.policy.RedactEvent(event)
+		if err != nil {
+			return err
+		}
+
+		redacted = append(redacted, r)
+	}
+
+	return p.next.PublishBatch(redacted)
+}
@@ -15,6 +15,10 @@ type UserEvent struct {
 	Data      any             `json:"data"`
 	Timestamp time.Time       `json:"timestamp"`
 	Version   string          `json:"version"`
+
+	// TraceParent is the W3C traceparent of the operation that emitted the
+	// event, so consumers can continue its trace.
+	TraceParent string `json:"traceParent,omitempty"`
 }
 
 // EventType represents the type of domain event.
@@ -27,12 +31,22 @@ const (
 	EventUserUpdated EventType = "user.updated"
 	// EventUserDeleted is emitted when a user is deleted.
 	EventUserDeleted EventType = "user.deleted"
+	// EventUserRestored is emitted when a deleted user is restored.
+	EventUserRestored EventType = "user.restored"
 	// EventUserActivated is emitted when a user is activated.
 	EventUserActivated EventType = "user.activated"
 	// EventUserDeactivated is emitted when a user is deactivated.
 	EventUserDeactivated EventType = "user.deactivated"
 	// EventUserSuspended is emitted when a user is suspended.
 	EventUserSuspended EventType = "user.suspended"
+	// EventUserReinstated is emitted when the suspension of a user is lifted or lapses.
+	EventUserReinstated EventType = "user.reinstated"
+	// EventUserFlagged is emitted when a moderator flags a user for review.
+	EventUserFlagged EventType = "user.flagged"
+	// EventAppealSubmitted is emitted when a user appeals their suspension.
+	EventAppealSubmitted EventType = "user.appeal.submitted"
+	// EventAppealDecided is emitted when a moderator grants or denies an appeal.
+	EventAppealDecided EventType = "user.appeal.decided"
 
 	// EventUserLogin is emitted when a user logs in.
 	EventUserLogin EventType = "user.login"
@@ -40,6 +54,8 @@ const (
 	EventUserLogout EventType = "user.logout"
 	// EventUserLoginFail is emitted when a user login fails.
 	EventUserLoginFail EventType = "user.login.failed"
+	// EventUserLoginSuspicious is emitted for a login from a new country or device.
+	EventUserLoginSuspicious EventType = "user.login.suspicious"
 
 	// EventUserVerified is emitted when a user is verified.
 	EventUserVerified EventType = "user.verified"
@@ -57,6 +73,53 @@ const (
 	EventProfileUpdated EventType = "profile.updated"
 	// EventRoleChanged is emitted when a role is changed.
 	EventRoleChanged EventType = "role.changed"
+
+	// EventUsersBulkUpdated is emitted once per bulk role or status change.
+	EventUsersBulkUpdated EventType = "users.bulk_updated"
+
+	// EventRefreshTokenReused is emitted when a rotated refresh token is presented again
+	// and its session is revoked.
+	EventRefreshTokenReused EventType = "session.refresh_token.reused"
+
+	// EventImpersonationStarted is emitted when a staff member starts acting as a user.
+	EventImpersonationStarted EventType = "user.impersonation.started"
+	// EventImpersonationEnded is emitted when an impersonation session is ended.
+	EventImpersonationEnded EventType = "user.impersonation.ended"
+
+	// EventEmailChangeRequested is emitted when a user asks to change their email address.
+	EventEmailChangeRequested EventType = "user.email_change.requested"
+	// EventEmailChanged is emitted when the new email address of a user is confirmed.
+	EventEmailChanged EventType = "user.email.changed"
+	// EventUsernameChanged is emitted when the username of a user is changed.
+	EventUsernameChanged EventType = "user.username.changed"
+	// EventIdentityChangeReverted is emitted when an email or username change is reverted.
+	EventIdentityChangeReverted EventType = "user.identity_change.reverted"
+
+	// EventOrganizationCreated is emitted when a user founds an organization.
+	EventOrganizationCreated EventType = "organization.created"
+	// EventMemberInvited is emitted when a user is invited to an organization.
+	EventMemberInvited EventType = "organization.member.invited"
+	// EventMemberJoined is emitted when a user accepts an invitation.
+	EventMemberJoined EventType = "organization.member.joined"
+	// EventMemberLeft is emitted when a user leaves an organization.
+	EventMemberLeft EventType = "organization.member.left"
+	// EventMemberRoleChanged is emitted when a member's organization role changes.
+	EventMemberRoleChanged EventType = "organization.member.role_changed"
+
+	// EventIdentityLinked is emitted when an external identity is linked to a user.
+	EventIdentityLinked EventType = "identity.linked"
+	// EventIdentityUnlinked is emitted when an external identity is unlinked from a user.
+	EventIdentityUnlinked EventType = "identity.unlinked"
+
+	// EventUserDataExported is emitted when the personal data of a user is exported.
+	EventUserDataExported EventType = "user.data.exported"
+	// EventUserErased is emitted when the personal data of a user is erased.
+	EventUserErased EventType = "user.erased"
+	// EventDeletionRequested is emitted when the deletion of an account is
+	// scheduled for the end of its grace period.
+	EventDeletionRequested EventType = "user.deletion.requested"
+	// EventDeletionCanceled is emitted when a pending account deletion is canceled.
+	EventDeletionCanceled EventType = "user.deletion.canceled"
 )
 
 // UserCreatedEvent data for user creation.
@@ -68,6 +131,9 @@ type UserCreatedEvent struct {
 	LastName  string          `json:"lastName"`
 	Role      string          `json:"role"`
 	Status    string          `json:"status"`
+	// Source is where the user signed up, such as an identity provider;
+	// empty for direct signups.
+	Source string `json:"source,omitempty"`
 }
 
 // UserUpdatedEvent data for user updates.
@@ -77,6 +143,14 @@ type UserUpdatedEvent struct {
 	UpdatedBy entities.UserID `json:"updatedBy"`
 }
 
+// UserDeletionEvent data for deleting and restoring users.
+type UserDeletionEvent struct {
+	UserID    entities.UserID `json:"userId"`
+	Email     string          `json:"email"`
+	Username  string          `json:"username"`
+	ChangedBy entities.UserID `json:"changedBy"`
+}
+
 // UserLoginEvent data for user login.
 type UserLoginEvent struct {
 	UserID    entities.UserID `json:"userId"`
@@ -86,6 +160,22 @@ type UserLoginEvent struct {
 	Success   bool            `json:"success"`
 }
 
+// UserLogoutEvent data for user logout.
+type UserLogoutEvent struct {
+	UserID    entities.UserID    `json:"userId"`
+	SessionID entities.SessionID `json:"sessionId"`
+}
+
+// SuspiciousLoginEvent data for a login from a new country or device.
+type SuspiciousLoginEvent struct {
+	UserID    entities.UserID `json:"userId"`
+	IPAddress string          `json:"ipAddress"`
+	UserAgent string          `json:"userAgent"`
+	// Country is the ISO code of the country the login came from, if known.
+	Country string   `json:"country,omitempty"`
+	Reasons []string `json:"reasons"`
+}
+
 // UserVerifiedEvent data for user verification.
 type UserVerifiedEvent struct {
 	UserID    entities.UserID `json:"userId"`
@@ -101,6 +191,29 @@ type RoleChangedEvent struct {
 	ChangedBy entities.UserID `json:"changedBy"`
 }
 
+// UsersBulkUpdatedEvent data for bulk role or status changes.
+type UsersBulkUpdatedEvent struct {
+	Field       string            `json:"field"`
+	Value       string            `json:"value"`
+	UserIDs     []entities.UserID `json:"userIds"`
+	FailedCount int               `json:"failedCount"`
+	ChangedBy   entities.UserID   `json:"changedBy"`
+}
+
+// RefreshTokenReusedEvent data for detected refresh token reuse.
+type RefreshTokenReusedEvent struct {
+	UserID    entities.UserID    `json:"userId"`
+	SessionID entities.SessionID `json:"sessionId"`
+}
+
+// ImpersonationEvent data for the start and end of an impersonation.
+type ImpersonationEvent struct {
+	UserID         entities.UserID    `json:"userId"`
+	ImpersonatorID entities.UserID    `json:"impersonatorId"`
+	SessionID      entities.SessionID `json:"sessionId"`
+	ExpiresAt      time.Time          `json:"expiresAt"`
+}
+
 // NewUserEvent creates a new user domain event.
 func NewUserEvent(eventType EventType, userID entities.UserID, data any) *UserEvent {
 	return &UserEvent{
@@ -146,6 +259,26 @@ func UserUpdated(
 	return NewUserEvent(EventUserUpdated, userID, data)
 }
 
+// UserDeleted creates a user deleted event.
+func UserDeleted(user *entities.User, deletedBy entities.UserID) *UserEvent {
+	return NewUserEvent(EventUserDeleted, user.ID(), userDeletion(user, deletedBy))
+}
+
+// UserRestored creates a user restored event.
+func UserRestored(user *entities.User, restoredBy entities.UserID) *UserEvent {
+	return NewUserEvent(EventUserRestored, user.ID(), userDeletion(user, restoredBy))
+}
+
+// userDeletion describes the deleted or restored user.
+func userDeletion(user *entities.User, changedBy entities.UserID) UserDeletionEvent {
+	return UserDeletionEvent{
+		UserID:    user.ID(),
+		Email:     user.Email().String(),
+		Username:  user.Username().String(),
+		ChangedBy: changedBy,
+	}
+}
+
 // UserLoginAttempt creates a user login attempt event.
 func UserLoginAttempt(
 	userID entities.UserID,
@@ -169,11 +302,34 @@ func UserLoggedIn(userID entities.UserID, ipAddress, userAgent, device string) *
 	return UserLoginAttempt(userID, ipAddress, userAgent, device, true, EventUserLogin)
 }
 
+// UserLoggedOut creates a user logout event for the session that ended.
+func UserLoggedOut(userID entities.UserID, sessionID entities.SessionID) *UserEvent {
+	return NewUserEvent(EventUserLogout, userID, UserLogoutEvent{UserID: userID, SessionID: sessionID})
+}
+
 // UserLoginFailed creates a user login failed event.
 func UserLoginFailed(userID entities.UserID, ipAddress, userAgent, device string) *UserEvent {
 	return UserLoginAttempt(userID, ipAddress, userAgent, device, false, EventUserLoginFail)
 }
 
+// UserLoginSuspicious creates a suspicious login event.
+func UserLoginSuspicious(userID entities.UserID, ipAddress, userAgent string, risk entities.LoginRisk) *UserEvent {
+	reasons := make([]string, 0, len(risk.Reasons))
+	for _, reason := range risk.Reasons {
+		reasons = append(reasons, reason.String())
+	}
+
+	data := SuspiciousLoginEvent{
+		UserID:    userID,
+		IPAddress: ipAddress,
+		UserAgent: userAgent,
+		Country:   risk.Location.Country,
+		Reasons:   reasons,
+	}
+
+	return NewUserEvent(EventUserLoginSuspicious, userID, data)
+}
+
 // UserVerified creates a user verified event.
 func UserVerified(userID entities.UserID, method string) *UserEvent {
 	data := UserVerifiedEvent{
@@ -201,6 +357,379 @@ func RoleChanged(
 	return NewUserEvent(EventRoleChanged, userID, data)
 }
 
+// UsersBulkUpdated creates a single aggregated event for a bulk change.
+func UsersBulkUpdated(
+	field, value string,
+	userIDs []entities.UserID,
+	failedCount int,
+	changedBy entities.UserID,
+) *UserEvent {
+	data := UsersBulkUpdatedEvent{
+		Field:       field,
+		Value:       value,
+		UserIDs:     userIDs,
+		FailedCount: failedCount,
+		ChangedBy:   changedBy,
+	}
+
+	return NewUserEvent(EventUsersBulkUpdated, changedBy, data)
+}
+
+// RefreshTokenReused creates a refresh token reuse event.
+func RefreshTokenReused(userID entities.UserID, sessionID entities.SessionID) *UserEvent {
+	data := RefreshTokenReusedEvent{
+		UserID:    userID,
+		SessionID: sessionID,
+	}
+
+	return NewUserEvent(EventRefreshTokenReused, userID, data)
+}
+
+// ImpersonationStarted creates an event for the start of an impersonation session.
+func ImpersonationStarted(session *entities.UserSession) *UserEvent {
+	return impersonationEvent(EventImpersonationStarted, session)
+}
+
+// ImpersonationEnded creates an event for the end of an impersonation session.
+func ImpersonationEnded(session *entities.UserSession) *UserEvent {
+	return impersonationEvent(EventImpersonationEnded, session)
+}
+
+func impersonationEvent(eventType EventType, session *entities.UserSession) *UserEvent {
+	impersonatorID, _ := session.Impersonator()
+	data := ImpersonationEvent{
+		UserID:         session.UserID(),
+		ImpersonatorID: impersonatorID,
+		SessionID:      session.ID(),
+		ExpiresAt:      session.ExpiresAt(),
+	}
+
+	return NewUserEvent(eventType, session.UserID(), data)
+}
+
+// TokenRequestedEvent data for verification and password reset requests.
+// Token is a secret that proves the request, so these events must only be
+// published to in-process subscribers.
+type TokenRequestedEvent struct {
+	UserID    entities.UserID `json:"userId"`
+	Token     string          `json:"token"`
+	ExpiresAt time.Time       `json:"expiresAt"`
+}
+
+// UserVerificationRequested creates an event asking to verify the email
+// address of a user with token.
+func UserVerificationRequested(userID entities.UserID, token string, expiresAt time.Time) *UserEvent {
+	data := TokenRequestedEvent{
+		UserID:    userID,
+		Token:     token,
+		ExpiresAt: expiresAt,
+	}
+
+	return NewUserEvent(EventUserVerificationRequested, userID, data)
+}
+
+// PasswordResetRequested creates an event asking to reset the password of
+// a user with token.
+func PasswordResetRequested(userID entities.UserID, token string, expiresAt time.Time) *UserEvent {
+	data := TokenRequestedEvent{
+		UserID:    userID,
+		Token:     token,
+		ExpiresAt: expiresAt,
+	}
+
+	return NewUserEvent(EventPasswordResetRequested, userID, data)
+}
+
+// EmailChangeRequestedEvent data for a requested email change. Token
+// confirms the change and is sent to the new address Email, so these events
+// must only be published to in-process subscribers.
+type EmailChangeRequestedEvent struct {
+	UserID    entities.UserID `json:"userId"`
+	Email     string          `json:"email"`
+	Token     string          `json:"token"`
+	ExpiresAt time.Time       `json:"expiresAt"`
+}
+
+// IdentityChangeEvent data for applied and reverted email and username
+// changes. RevertToken undoes an applied change until RevertableUntil; it
+// is a secret, so events carrying it must only be published to in-process
+// subscribers.
+type IdentityChangeEvent struct {
+	UserID          entities.UserID `json:"userId"`
+	Field           string          `json:"field"`
+	OldValue        string          `json:"oldValue"`
+	NewValue        string          `json:"newValue"`
+	RevertToken     string          `json:"revertToken,omitempty"`
+	RevertableUntil time.Time       `json:"revertableUntil,omitzero"`
+}
+
+// EmailChangeRequested creates an event asking to confirm the pending
+// email change of a user at its new address.
+func EmailChangeRequested(change *entities.IdentityChange) *UserEvent {
+	data := EmailChangeRequestedEvent{
+		UserID:    change.UserID,
+		Email:     change.NewValue,
+		Token:     change.Token.String(),
+		ExpiresAt: change.ExpiresAt,
+	}
+
+	return NewUserEvent(EventEmailChangeRequested, change.UserID, data)
+}
+
+// IdentityChangeApplied creates an event for an applied email or username
+// change, carrying the token that reverts it.
+func IdentityChangeApplied(change *entities.IdentityChange) *UserEvent {
+	eventType := EventUsernameChanged
+	if change.Field == entities.IdentityFieldEmail {
+		eventType = EventEmailChanged
+	}
+
+	data := IdentityChangeEvent{
+		UserID:          change.UserID,
+		Field:           change.Field.String(),
+		OldValue:        change.OldValue,
+		NewValue:        change.NewValue,
+		RevertToken:     change.Token.String(),
+		RevertableUntil: change.ExpiresAt,
+	}
+
+	return NewUserEvent(eventType, change.UserID, data)
+}
+
+// IdentityChangeReverted creates an event for a reverted email or username
+// change, which restored OldValue.
+func IdentityChangeReverted(change *entities.IdentityChange) *UserEvent {
+	data := IdentityChangeEvent{
+		UserID:   change.UserID,
+		Field:    change.Field.String(),
+		OldValue: change.OldValue,
+		NewValue: change.NewValue,
+	}
+
+	return NewUserEvent(EventIdentityChangeReverted, change.UserID, data)
+}
+
+// UserSuspendedEvent data for suspending a user. ActionID and EndsAt are
+// set for suspensions imposed by a moderator.
+type UserSuspendedEvent struct {
+	UserID    entities.UserID             `json:"userId"`
+	Reason    string                      `json:"reason,omitempty"`
+	ChangedBy entities.UserID             `json:"changedBy"`
+	ActionID  entities.ModerationActionID `json:"actionId,omitempty"`
+	EndsAt    *time.Time                  `json:"endsAt,omitempty"`
+}
+
+// UserSuspended creates a user suspended event.
+func UserSuspended(userID entities.UserID, reason string, changedBy entities.UserID) *UserEvent {
+	data := UserSuspendedEvent{
+		UserID:    userID,
+		Reason:    reason,
+		ChangedBy: changedBy,
+	}
+
+	return NewUserEvent(EventUserSuspended, userID, data)
+}
+
+// SuspensionImposed creates a user suspended event for a suspension imposed
+// by a moderator.
+func SuspensionImposed(action *entities.ModerationAction) *UserEvent {
+	data := UserSuspendedEvent{
+		UserID:    action.UserID,
+		Reason:    action.Reason,
+		ChangedBy: action.ModeratorID,
+		ActionID:  action.ID,
+		EndsAt:    action.EndsAt,
+	}
+
+	return NewUserEvent(EventUserSuspended, action.UserID, data)
+}
+
+// ModerationEvent data for flagging users and lifting their suspensions.
+// LiftedBy is zero for suspensions that lapsed.
+type ModerationEvent struct {
+	UserID      entities.UserID             `json:"userId"`
+	ActionID    entities.ModerationActionID `json:"actionId"`
+	Kind        string                      `json:"kind"`
+	Reason      string                      `json:"reason,omitempty"`
+	ModeratorID entities.UserID             `json:"moderatorId,omitempty"`
+	LiftedBy    entities.UserID             `json:"liftedBy,omitempty"`
+}
+
+// UserFlagged creates an event for a user flagged for review.
+func UserFlagged(action *entities.ModerationAction) *UserEvent {
+	return NewUserEvent(EventUserFlagged, action.UserID, moderationEvent(action))
+}
+
+// UserReinstated creates an event for a user whose suspension was lifted or lapsed.
+func UserReinstated(action *entities.ModerationAction) *UserEvent {
+	return NewUserEvent(EventUserReinstated, action.UserID, moderationEvent(action))
+}
+
+func moderationEvent(action *entities.ModerationAction) ModerationEvent {
+	return ModerationEvent{
+		UserID:      action.UserID,
+		ActionID:    action.ID,
+		Kind:        action.Kind.String(),
+		Reason:      action.Reason,
+		ModeratorID: action.ModeratorID,
+		LiftedBy:    action.LiftedBy,
+	}
+}
+
+// AppealEvent data for submitted and decided appeals. The statement of the
+// user and the response of the reviewer are left out; they are read from
+// the appeal.
+type AppealEvent struct {
+	UserID     entities.UserID             `json:"userId"`
+	AppealID   entities.AppealID           `json:"appealId"`
+	ActionID   entities.ModerationActionID `json:"actionId"`
+	Status     string                      `json:"status"`
+	ReviewerID entities.UserID             `json:"reviewerId,omitempty"`
+}
+
+// AppealSubmitted creates an event for a user appealing their suspension.
+func AppealSubmitted(appeal *entities.Appeal) *UserEvent {
+	return NewUserEvent(EventAppealSubmitted, appeal.UserID, appealEvent(appeal))
+}
+
+// AppealDecided creates an event for a granted or denied appeal.
+func AppealDecided(appeal *entities.Appeal) *UserEvent {
+	return NewUserEvent(EventAppealDecided, appeal.UserID, appealEvent(appeal))
+}
+
+func appealEvent(appeal *entities.Appeal) AppealEvent {
+	return AppealEvent{
+		UserID:     appeal.UserID,
+		AppealID:   appeal.ID,
+		ActionID:   appeal.ActionID,
+		Status:     appeal.Status.String(),
+		ReviewerID: appeal.ReviewerID,
+	}
+}
+
+// MembershipEvent data for organization and membership changes.
+type MembershipEvent struct {
+	OrganizationID entities.OrganizationID `json:"organizationId"`
+	Role           string                  `json:"role,omitempty"`
+	ActorID        entities.UserID         `json:"actorId,omitempty"`
+}
+
+// MembershipChanged creates an organization event about userID.
+func MembershipChanged(
+	eventType EventType,
+	userID entities.UserID,
+	orgID entities.OrganizationID,
+	role entities.UserRole,
+	actorID entities.UserID,
+) *UserEvent {
+	data := MembershipEvent{
+		OrganizationID: orgID,
+		Role:           role.String(),
+		ActorID:        actorID,
+	}
+
+	return NewUserEvent(eventType, userID, data)
+}
+
+// FederatedLoginEvent data for a login through an external identity provider.
+// It is published as EventUserLogin so existing consumers see every login.
+type FederatedLoginEvent struct {
+	UserLoginEvent
+
+	Provider string `json:"provider"`
+	Subject  string `json:"subject"`
+	// FirstLogin is true when the identity was linked by this login.
+	FirstLogin bool `json:"firstLogin"`
+}
+
+// UserFederatedLogin creates a login event carrying the identity provider.
+func UserFederatedLogin(
+	userID entities.UserID,
+	provider entities.IdentityProvider,
+	subject, ipAddress, userAgent string,
+	firstLogin bool,
+) *UserEvent {
+	data := FederatedLoginEvent{
+		UserLoginEvent: UserLoginEvent{
+			UserID:    userID,
+			IPAddress: ipAddress,
+			UserAgent: userAgent,
+			Device:    provider.String(),
+			Success:   true,
+		},
+		Provider:   provider.String(),
+		Subject:    subject,
+		FirstLogin: firstLogin,
+	}
+
+	return NewUserEvent(EventUserLogin, userID, data)
+}
+
+// ComplianceEvent data for data subject requests such as exports and erasures.
+type ComplianceEvent struct {
+	UserID      entities.UserID `json:"userId"`
+	RequestedBy entities.UserID `json:"requestedBy"`
+}
+
+// UserDataExported creates a data export event.
+func UserDataExported(userID, requestedBy entities.UserID) *UserEvent {
+	return NewUserEvent(EventUserDataExported, userID, ComplianceEvent{UserID: userID, RequestedBy: requestedBy})
+}
+
+// UserErased creates an erasure event. It carries no personal data.
+func UserErased(userID, requestedBy entities.UserID) *UserEvent {
+	return NewUserEvent(EventUserErased, userID, ComplianceEvent{UserID: userID, RequestedBy: requestedBy})
+}
+
+// AccountDeletionEvent data for requested and canceled account deletions.
+type AccountDeletionEvent struct {
+	UserID       entities.UserID `json:"userId"`
+	RequestedBy  entities.UserID `json:"requestedBy"`
+	ScheduledFor time.Time       `json:"scheduledFor"`
+	CanceledBy   entities.UserID `json:"canceledBy,omitempty"`
+}
+
+// DeletionRequested creates an event for an account deletion scheduled for
+// the end of its grace period.
+func DeletionRequested(deletion *entities.AccountDeletion) *UserEvent {
+	data := AccountDeletionEvent{
+		UserID:       deletion.UserID,
+		RequestedBy:  deletion.RequestedBy,
+		ScheduledFor: deletion.ScheduledFor,
+	}
+
+	return NewUserEvent(EventDeletionRequested, deletion.UserID, data)
+}
+
+// DeletionCanceled creates an event for a pending account deletion
+// canceled by canceledBy.
+func DeletionCanceled(deletion *entities.AccountDeletion, canceledBy entities.UserID) *UserEvent {
+	data := AccountDeletionEvent{
+		UserID:       deletion.UserID,
+		RequestedBy:  deletion.RequestedBy,
+		ScheduledFor: deletion.ScheduledFor,
+		CanceledBy:   canceledBy,
+	}
+
+	return NewUserEvent(EventDeletionCanceled, deletion.UserID, data)
+}
+
+// IdentityEvent data for linking and unlinking external identities.
+type IdentityEvent struct {
+	Provider string `json:"provider"`
+	Subject  string `json:"subject"`
+}
+
+// IdentityChanged creates an identity event about userID.
+func IdentityChanged(eventType EventType, identity *entities.UserIdentity) *UserEvent {
+	data := IdentityEvent{
+		Provider: identity.Provider.String(),
+		Subject:  identity.Subject,
+	}
+
+	return NewUserEvent(eventType, identity.UserID, data)
+}
+
 // EventPublisher interface for publishing domain events.
 type EventPublisher interface {
 	Publish(event *UserEvent) error
@@ -254,12 +783,20 @@ func (e EventType) IsValid() bool {
 		EventUserCreated:               true,
 		EventUserUpdated:               true,
 		EventUserDeleted:               true,
+		EventUserRestored:              true,
 		EventUserActivated:             true,
 		EventUserDeactivated:           true,
 		EventUserSuspended:             true,
+		EventUserReinstated:            true,
+		EventUserFlagged:               true,
+		EventAppealSubmitted:           true,
+		EventAppealDecided:             true,
+		EventDeletionRequested:         true,
+		EventDeletionCanceled:          true,
 		EventUserLogin:                 true,
 		EventUserLogout:                true,
 		EventUserLoginFail:             true,
+		EventUserLoginSuspicious:       true,
 		EventUserVerified:              true,
 		EventUserVerificationRequested: true,
 		EventPasswordChanged:           true,
@@ -267,6 +804,21 @@ func (e EventType) IsValid() bool {
 		EventPasswordResetRequested:    true,
 		EventProfileUpdated:            true,
 		EventRoleChanged:               true,
+		EventUsersBulkUpdated:          true,
+		EventRefreshTokenReused:        true,
+		EventImpersonationStarted:      true,
+		EventImpersonationEnded:        true,
+		EventEmailChangeRequested:      true,
+		EventEmailChanged:              true,
+		EventUsernameChanged:           true,
+		EventIdentityChangeReverted:    true,
+		EventOrganizationCreated:       true,
+		EventMemberInvited:             true,
+		EventMemberJoined:              true,
+		EventMemberLeft:                true,
+		EventMemberRoleChanged:         true,
+		EventIdentityLinked:            true,
+		EventIdentityUnlinked:          true,
 	}
 
 	return validTypes[e]
@@ -0,0 +1,16 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+)
+
+// FeatureFlagRepository stores the feature flags that were set. Flags
+// without a stored value use their default.
+type FeatureFlagRepository interface {
+	// List returns the stored flags ordered by name.
+	List(ctx context.Context) ([]*entities.FeatureFlag, error)
+	// Set stores the flag, replacing the one of the same name.
+	Set(ctx context.Context, flag *entities.FeatureFlag) error
+}
@@ -0,0 +1,26 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+)
+
+// IdempotencyRepository stores the outcomes of writes by idempotency key.
+//
+// A write reserves its key with a pending record, runs, and then either
+// completes the record with its response or releases it, so that a failed
+// write can be retried.
+type IdempotencyRepository interface {
+	// Reserve stores a pending record. It returns ErrIdempotencyKeyExists if
+	// a record with the key is stored, even an expired one.
+	Reserve(ctx context.Context, record *entities.IdempotencyRecord) error
+	Get(ctx context.Context, key entities.IdempotencyKey) (*entities.IdempotencyRecord, error)
+	// Complete stores the response and expiry of a pending record.
+	Complete(ctx context.Context, record *entities.IdempotencyRecord) error
+	// Release deletes a pending record.
+	Release(ctx context.Context, key entities.IdempotencyKey) error
+	// DeleteExpired removes the records expired at now and returns how many.
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
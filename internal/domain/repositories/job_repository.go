@@ -0,0 +1,31 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+)
+
+// JobRepository persists the background job queue.
+//
+// Workers claim due jobs with a lease and finish each claim with Complete,
+// Retry or Fail. Finishing compares the job's Attempts with the stored ones
+// and returns ErrJobLeaseLost if the lease expired and another worker has
+// claimed the job since.
+type JobRepository interface {
+	// Enqueue stores a pending job and assigns its ID.
+	Enqueue(ctx context.Context, job *entities.Job) error
+	Get(ctx context.Context, id entities.JobID) (*entities.Job, error)
+	// Claim leases up to limit jobs that are due at now until now+lease,
+	// oldest RunAt first, and returns them running.
+	Claim(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*entities.Job, error)
+	// Complete marks a claimed job done.
+	Complete(ctx context.Context, job *entities.Job) error
+	// Retry returns a claimed job to the queue, due again at runAt.
+	Retry(ctx context.Context, job *entities.Job, runAt time.Time, lastError string) error
+	// Fail marks a claimed job failed; it is kept for inspection.
+	Fail(ctx context.Context, job *entities.Job, lastError string) error
+	// DeleteDone removes the done jobs finished before cutoff and returns how many.
+	DeleteDone(ctx context.Context, cutoff time.Time) (int64, error)
+}
@@ -0,0 +1,61 @@
+package repositories
+
+// LockStrength selects the row lock acquired by a locking read.
+type LockStrength int
+
+const (
+	// LockForUpdate acquires an exclusive row lock (SELECT ... FOR UPDATE).
+	LockForUpdate LockStrength = iota
+	// LockForShare acquires a shared row lock (SELECT ... FOR SHARE).
+	LockForShare
+)
+
+// LockWait controls how a locking read behaves when a row is already locked.
+type LockWait int
+
+const (
+	// LockWaitBlock waits until the conflicting lock is released.
+	LockWaitBlock LockWait = iota
+	// LockWaitNoWait fails immediately instead of waiting (NOWAIT).
+	LockWaitNoWait
+	// LockWaitSkipLocked silently skips locked rows (SKIP LOCKED).
+	LockWaitSkipLocked
+)
+
+// LockOptions describes the row lock acquired by a locking read.
+type LockOptions struct {
+	Strength LockStrength
+	Wait     LockWait
+}
+
+// LockOption configures LockOptions.
+type LockOption func(*LockOptions)
+
+// WithShareLock acquires a shared instead of an exclusive lock.
+func WithShareLock() LockOption {
+	return func(o *LockOptions) { o.Strength = LockForShare }
+}
+
+// WithNoWait fails instead of waiting for a conflicting lock.
+func WithNoWait() LockOption {
+	return func(o *LockOptions) { o.Wait = LockWaitNoWait }
+}
+
+// WithSkipLocked skips rows locked by other transactions.
+func WithSkipLocked() LockOption {
+	return func(o *LockOptions) { o.Wait = LockWaitSkipLocked }
+}
+
+// NewLockOptions returns an exclusive, blocking lock with the options applied.
+func NewLockOptions(opts ...LockOption) LockOptions {
+	options := LockOptions{
+		Strength: LockForUpdate,
+		Wait:     LockWaitBlock,
+	}
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return options
+}
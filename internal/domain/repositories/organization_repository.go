@@ -0,0 +1,40 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+)
+
+// OrganizationRepository defines the interface for organization data access.
+type OrganizationRepository interface {
+	Repository[entities.Organization, entities.OrganizationID]
+
+	GetBySlug(ctx context.Context, slug entities.OrganizationSlug) (*entities.Organization, error)
+	// ListByUser returns the organizations a user is an active member of.
+	ListByUser(ctx context.Context, userID entities.UserID, limit int) ([]*entities.Organization, error)
+}
+
+// MembershipRepository defines the interface for organization membership data access.
+// It resolves organization-scoped roles for the permission checker.
+type MembershipRepository interface {
+	Create(ctx context.Context, membership *entities.Membership) error
+	Get(ctx context.Context, orgID entities.OrganizationID, userID entities.UserID) (*entities.Membership, error)
+	Update(ctx context.Context, membership *entities.Membership) error
+	Delete(ctx context.Context, orgID entities.OrganizationID, userID entities.UserID) error
+	// ListByOrganization returns the members of an organization, including pending invitations.
+	ListByOrganization(
+		ctx context.Context,
+		orgID entities.OrganizationID,
+		limit, offset int,
+	) ([]*entities.Membership, error)
+	// CountAdmins returns the number of active admins of an organization.
+	CountAdmins(ctx context.Context, orgID entities.OrganizationID) (int64, error)
+	// RoleInOrganization returns the role of an active member.
+	// Pending invitations return ErrMembershipNotFound.
+	RoleInOrganization(
+		ctx context.Context,
+		userID entities.UserID,
+		orgID entities.OrganizationID,
+	) (entities.UserRole, error)
+}
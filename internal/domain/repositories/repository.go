@@ -0,0 +1,19 @@
+package repositories
+
+import "context"
+
+// Repository is the CRUD surface shared by aggregates stored under an ID.
+// Aggregate repositories embed it and add their own queries. The adapters
+// of each engine implement it once, in their BaseRepository.
+type Repository[T any, ID comparable] interface {
+	// Create inserts entity and assigns its ID.
+	Create(ctx context.Context, entity *T) error
+	// GetByID returns the entity with id, or the not found error of the aggregate.
+	GetByID(ctx context.Context, id ID) (*T, error)
+	// Update persists entity, or returns the not found error of the aggregate.
+	Update(ctx context.Context, entity *T) error
+	// Delete removes the entity with id, or returns the not found error of the aggregate.
+	Delete(ctx context.Context, id ID) error
+	// List returns up to limit entities after skipping offset, ordered by ID.
+	List(ctx context.Context, limit, offset int) ([]*T, error)
+}
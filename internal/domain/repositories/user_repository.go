@@ -5,6 +5,7 @@ package repositories
 
 import (
 	"context"
+	"time"
 
 	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
 )
@@ -18,40 +19,66 @@ type UserRepository interface {
 	Create(ctx context.Context, user *entities.User) error
 	GetByID(ctx context.Context, id entities.UserID) (*entities.User, error)
 	GetByUUID(ctx context.Context, uuid entities.UuID) (*entities.User, error)
+	// GetByIDs returns the users with the given IDs in one query, in no particular order.
+	// IDs that do not exist are omitted from the result.
+	GetByIDs(ctx context.Context, ids []entities.UserID) ([]*entities.User, error)
 	GetByEmail(ctx context.Context, email entities.Email) (*entities.User, error)
+	// GetByUsername returns the user whose username has the same
+	// Username.Canonical form, so that lookups ignore case and compatibility
+	// characters; canonical usernames are unique like emails.
 	GetByUsername(ctx context.Context, username entities.Username) (*entities.User, error)
 	Update(ctx context.Context, user *entities.User) error
+	// Delete soft deletes a user: lookups, listings and updates skip it until
+	// it is restored or purged.
 	Delete(ctx context.Context, id entities.UserID) error
+	// Restore undoes Delete. It returns ErrUserNotFound when no deleted user has the ID.
+	Restore(ctx context.Context, id entities.UserID) error
+	// PurgeDeletedOlderThan permanently removes the users soft deleted before
+	// cutoff and returns how many were removed.
+	PurgeDeletedOlderThan(ctx context.Context, cutoff time.Time) (int64, error)
+
+	// Bulk operations for imports and administrative changes, using the
+	// fastest insert path of each engine.
+	// CreateBatch creates all users or none of them and assigns their IDs.
+	CreateBatch(ctx context.Context, users []*entities.User) error
+	// UpdateStatusBatch changes the status of the users with the given IDs and
+	// returns how many were updated. Deleted and unknown IDs are skipped.
+	UpdateStatusBatch(ctx context.Context, ids []entities.UserID, status entities.UserStatus) (int64, error)
+	// DeleteBatch soft deletes the users with the given IDs and returns how many
+	// were deleted. Already deleted and unknown IDs are skipped.
+	DeleteBatch(ctx context.Context, ids []entities.UserID) (int64, error)
 
 	// List and search operations
+	// List returns a page of users matching filter, newest first.
 	List(
 		ctx context.Context,
-		status entities.UserStatus,
+		filter entities.UserFilter,
 		limit, offset int,
 	) ([]*entities.User, error)
-	Search(
+	// ListPage returns up to limit users matching filter, newest first, using
+	// keyset pagination. Pass an empty cursor for the first page and the
+	// page's NextCursor for the following one.
+	ListPage(
 		ctx context.Context,
-		query string,
-		status entities.UserStatus,
+		filter entities.UserFilter,
+		cursor string,
 		limit int,
-	) ([]*entities.User, error)
-	SearchByTags(
+	) (*entities.UserPage, error)
+	SearchWithFacets(
 		ctx context.Context,
-		tags []string,
+		query string,
 		status entities.UserStatus,
-		limit, offset int,
-	) ([]*entities.User, error)
+		limit int,
+	) (*entities.UserSearchResult, error)
 
 	// Aggregate operations
 	CountByStatus(ctx context.Context) (map[entities.UserStatus]int64, error)
 	GetStats(ctx context.Context) (*entities.UserStats, error)
 
 	// Authentication operations
-	VerifyCredentials(
-		ctx context.Context,
-		email entities.Email,
-		password entities.PasswordHash,
-	) (*entities.User, error)
+	// GetCredentials returns the user of email with its current password
+	// hash; verifying a password against it is up to the caller.
+	GetCredentials(ctx context.Context, email entities.Email) (*entities.User, error)
 	UpdatePassword(ctx context.Context, id entities.UserID, password entities.PasswordHash) error
 	MarkVerified(ctx context.Context, id entities.UserID) error
 
@@ -63,6 +90,282 @@ type UserRepository interface {
 
 	// Role operations
 	ChangeRole(ctx context.Context, id entities.UserID, role entities.UserRole) error
+
+	// Locking operations; only available on a repository bound to a
+	// transaction, adapters fail them otherwise. The locks are held until the
+	// transaction commits or rolls back.
+	GetByIDForUpdate(
+		ctx context.Context,
+		id entities.UserID,
+		opts ...LockOption,
+	) (*entities.User, error)
+	// ClaimNext locks up to limit users in the given status, skipping rows already
+	// claimed by concurrent transactions, for work-queue style processing.
+	ClaimNext(
+		ctx context.Context,
+		status entities.UserStatus,
+		limit int,
+	) ([]*entities.User, error)
+}
+
+// SearchIndexer maintains a full-text index of users that answers the
+// searches of UserRepository without loading the database. The index holds
+// copies of the searchable fields only and returns user IDs; it is kept in
+// sync asynchronously, so results may briefly lag behind the database.
+type SearchIndexer interface {
+	// Index adds the users to the index or replaces their entries.
+	Index(ctx context.Context, users ...*entities.User) error
+	// Remove drops the users from the index; unknown IDs are ignored.
+	Remove(ctx context.Context, ids ...entities.UserID) error
+	// Search returns the IDs of up to limit users matching filter after
+	// skipping offset, newest first like UserRepository.List.
+	Search(ctx context.Context, filter entities.UserFilter, limit, offset int) ([]entities.UserID, error)
+	// SearchWithFacets returns the IDs of up to limit users matching query
+	// and, unless empty, status, best match first, with the facets counted
+	// over every user matching query.
+	SearchWithFacets(
+		ctx context.Context,
+		query string,
+		status entities.UserStatus,
+		limit int,
+	) ([]entities.UserID, *entities.SearchFacets, error)
+}
+
+// UserAnalyticsRepository answers aggregate questions about users from an
+// analytical store, which is synced from the primary store periodically and
+// may lag behind it.
+type UserAnalyticsRepository interface {
+	CountByStatus(ctx context.Context) (map[entities.UserStatus]int64, error)
+	GetStats(ctx context.Context) (*entities.UserStats, error)
+	// Cohorts groups the users who signed up at or after since by interval,
+	// oldest period first.
+	Cohorts(ctx context.Context, interval entities.CohortInterval, since time.Time) ([]entities.UserCohort, error)
+	// SyncedAt returns when the store was last synced, or the zero time if
+	// it never was.
+	SyncedAt(ctx context.Context) (time.Time, error)
+}
+
+// EventAnalyticsRepository answers aggregate questions about the user
+// events recorded in an analytical store. Every query covers the events
+// that occurred in [from, to).
+type EventAnalyticsRepository interface {
+	// LoginsByDay returns one entry per UTC day with login attempts, oldest first.
+	LoginsByDay(ctx context.Context, from, to time.Time) ([]entities.DailyLogins, error)
+	// FailuresByIP returns the limit addresses with the most failed logins,
+	// most failures first.
+	FailuresByIP(ctx context.Context, from, to time.Time, limit int) ([]entities.IPFailures, error)
+	// SignupsBySource returns the signups per source, most signups first.
+	SignupsBySource(ctx context.Context, from, to time.Time) ([]entities.SignupSource, error)
+}
+
+// UserHistoryRepository defines access to archived user versions.
+// Versions are written by triggers where the engine supports them,
+// otherwise application-side in the transaction that changes the user.
+type UserHistoryRepository interface {
+	// GetUserAsOf returns the version of a user that was valid at the given time.
+	GetUserAsOf(ctx context.Context, id entities.UserID, at time.Time) (*entities.UserSnapshot, error)
+	// ListVersions returns archived versions of a user, newest first.
+	ListVersions(ctx context.Context, id entities.UserID, limit int) ([]*entities.UserSnapshot, error)
+	// DeleteVersions removes every archived version of a user and returns
+	// how many were removed.
+	DeleteVersions(ctx context.Context, id entities.UserID) (int64, error)
+}
+
+// AuditRepository persists the audit log of mutating operations.
+type AuditRepository interface {
+	// Record stores an entry and assigns its ID.
+	Record(ctx context.Context, entry *entities.AuditLog) error
+	// List returns entries matching filter, newest first.
+	List(ctx context.Context, filter entities.AuditFilter) ([]*entities.AuditLog, error)
+	// AnonymizeUser clears the changes and IP addresses of the entries about
+	// or performed by a user and returns how many were changed. The entries
+	// themselves are kept.
+	AnonymizeUser(ctx context.Context, userID entities.UserID) (int64, error)
+}
+
+// IdentityRepository persists the external identities linked to users.
+type IdentityRepository interface {
+	// Create links an identity and assigns its ID. Linking a provider subject
+	// that is already linked returns ErrIdentityAlreadyLinked.
+	Create(ctx context.Context, identity *entities.UserIdentity) error
+	GetByProviderSubject(
+		ctx context.Context,
+		provider entities.IdentityProvider,
+		subject string,
+	) (*entities.UserIdentity, error)
+	// RecordLogin persists the identity's last login time and email.
+	RecordLogin(ctx context.Context, identity *entities.UserIdentity) error
+	ListByUser(ctx context.Context, userID entities.UserID) ([]*entities.UserIdentity, error)
+	Delete(ctx context.Context, userID entities.UserID, provider entities.IdentityProvider) error
+}
+
+// IdentityChangeRepository persists the history of email address and
+// username changes.
+type IdentityChangeRepository interface {
+	// Create records a change and assigns its ID.
+	Create(ctx context.Context, change *entities.IdentityChange) error
+	// GetByToken returns the change with token, or ErrIdentityChangeNotFound.
+	GetByToken(ctx context.Context, token entities.IdentityChangeToken) (*entities.IdentityChange, error)
+	// Update stores the status, token and expiry of a change.
+	Update(ctx context.Context, change *entities.IdentityChange) error
+	// ListByUser returns the changes of a user, newest first.
+	ListByUser(ctx context.Context, userID entities.UserID) ([]*entities.IdentityChange, error)
+	// DeleteByUser removes the changes of a user and returns how many were removed.
+	DeleteByUser(ctx context.Context, userID entities.UserID) (int64, error)
+}
+
+// ModerationRepository persists the moderation actions taken against users
+// and the appeals of suspensions.
+type ModerationRepository interface {
+	// CreateAction records an action and assigns its ID.
+	CreateAction(ctx context.Context, action *entities.ModerationAction) error
+	// GetAction returns the action with id, or ErrModerationActionNotFound.
+	GetAction(ctx context.Context, id entities.ModerationActionID) (*entities.ModerationAction, error)
+	// LiftAction stores when and by whom an action was lifted. It returns
+	// ErrModerationActionNotFound if the action does not exist or was
+	// lifted before.
+	LiftAction(ctx context.Context, action *entities.ModerationAction) error
+	// GetActiveSuspension returns the newest suspension of a user that was
+	// not lifted, or ErrNotSuspended.
+	GetActiveSuspension(ctx context.Context, userID entities.UserID) (*entities.ModerationAction, error)
+	// ListActionsByUser returns the actions against a user, newest first.
+	ListActionsByUser(ctx context.Context, userID entities.UserID) ([]*entities.ModerationAction, error)
+	// ListLapsedSuspensions returns up to limit suspensions that ended by
+	// now but were not lifted yet, the earliest ended first.
+	ListLapsedSuspensions(ctx context.Context, now time.Time, limit int) ([]*entities.ModerationAction, error)
+	// DeleteByUser removes the actions against a user together with their
+	// appeals and returns how many actions were removed.
+	DeleteByUser(ctx context.Context, userID entities.UserID) (int64, error)
+
+	// CreateAppeal records an appeal and assigns its ID, or returns
+	// ErrAppealExists if its suspension was appealed before.
+	CreateAppeal(ctx context.Context, appeal *entities.Appeal) error
+	// GetAppeal returns the appeal with id, or ErrAppealNotFound.
+	GetAppeal(ctx context.Context, id entities.AppealID) (*entities.Appeal, error)
+	// DecideAppeal stores the decision of an appeal. It returns
+	// ErrAppealNotFound if the appeal does not exist or was decided before.
+	DecideAppeal(ctx context.Context, appeal *entities.Appeal) error
+	// ListPendingAppeals returns up to limit undecided appeals, oldest first.
+	ListPendingAppeals(ctx context.Context, limit int) ([]*entities.Appeal, error)
+	// ListAppealsByUser returns the appeals of a user, newest first.
+	ListAppealsByUser(ctx context.Context, userID entities.UserID) ([]*entities.Appeal, error)
+}
+
+// AccountDeletionRepository persists the pending deletions of accounts, at
+// most one per user.
+type AccountDeletionRepository interface {
+	// Create records a deletion, or returns ErrAccountDeletionPending if one
+	// is pending for the user already.
+	Create(ctx context.Context, deletion *entities.AccountDeletion) error
+	// GetByUser returns the pending deletion of a user, or ErrAccountDeletionNotFound.
+	GetByUser(ctx context.Context, userID entities.UserID) (*entities.AccountDeletion, error)
+	// Delete removes the pending deletion of a user, or returns
+	// ErrAccountDeletionNotFound if there is none.
+	Delete(ctx context.Context, userID entities.UserID) error
+	// ListDue returns up to limit deletions scheduled for now or earlier,
+	// the earliest scheduled first.
+	ListDue(ctx context.Context, now time.Time, limit int) ([]*entities.AccountDeletion, error)
+}
+
+// NotificationPreferenceRepository persists the notification opt-ins of users.
+// Channels and topics without a stored preference use the defaults of
+// entities.DefaultNotificationPreference.
+type NotificationPreferenceRepository interface {
+	// ListByUser returns the stored preferences of a user, ordered by channel
+	// and topic.
+	ListByUser(ctx context.Context, userID entities.UserID) ([]*entities.NotificationPreference, error)
+	// Upsert stores a preference, replacing the one of its channel and topic.
+	Upsert(ctx context.Context, pref *entities.NotificationPreference) error
+	// Delete removes a stored preference, restoring its default.
+	Delete(
+		ctx context.Context,
+		userID entities.UserID,
+		channel entities.NotificationChannel,
+		topic entities.NotificationTopic,
+	) error
+}
+
+// UserPreferenceRepository persists the preferences of users as one JSON
+// document per user that is patched in place. Values are validated by the
+// service against an entities.PreferenceSchema before they are stored.
+type UserPreferenceRepository interface {
+	// Get returns the stored preferences of a user, empty if none were stored.
+	Get(ctx context.Context, userID entities.UserID) (*entities.UserPreferences, error)
+	// Patch stores the Set preferences of patch and removes the Unset ones,
+	// keeping every other stored preference, and returns the result.
+	Patch(
+		ctx context.Context,
+		userID entities.UserID,
+		patch entities.PreferencePatch,
+	) (*entities.UserPreferences, error)
+	// Delete removes the stored preferences of a user; none stored is not an error.
+	Delete(ctx context.Context, userID entities.UserID) error
+}
+
+// ActivityRepository persists the activity feeds of users.
+type ActivityRepository interface {
+	// Record stores entry. Entries of an event already recorded for the
+	// user are ignored, so redelivered events are harmless.
+	Record(ctx context.Context, entry *entities.ActivityEntry) error
+	// ListPage returns up to limit entries of a user after the position of
+	// cursor, newest first. An empty cursor starts at the newest entry.
+	ListPage(ctx context.Context, userID entities.UserID, cursor string, limit int) (*entities.ActivityPage, error)
+	// DeleteOlderThan removes the entries that occurred before cutoff and
+	// returns how many were removed.
+	DeleteOlderThan(ctx context.Context, cutoff time.Time) (int64, error)
+	// DeleteByUser removes the entries of a user and returns how many were removed.
+	DeleteByUser(ctx context.Context, userID entities.UserID) (int64, error)
+}
+
+// LoginHistoryRepository persists the successful and failed login attempts.
+type LoginHistoryRepository interface {
+	// Record stores attempt and assigns its ID.
+	Record(ctx context.Context, attempt *entities.LoginAttempt) error
+	// ListByUser returns the last limit attempts of a user, newest first.
+	ListByUser(ctx context.Context, userID entities.UserID, limit int) ([]*entities.LoginAttempt, error)
+	// ListFailuresByIP returns the last limit failed attempts from ipAddress
+	// since the given time, newest first, including those of unknown accounts.
+	ListFailuresByIP(ctx context.Context, ipAddress string, since time.Time, limit int) ([]*entities.LoginAttempt, error)
+	// DeleteByUser removes the attempts of a user and returns how many were removed.
+	DeleteByUser(ctx context.Context, userID entities.UserID) (int64, error)
+}
+
+// UserEventStore stores the event streams of event-sourced users, numbered
+// from 1 per user, and snapshots of their state.
+type UserEventStore interface {
+	// Append adds events to the stream of userID after expectedVersion, the
+	// version the caller built them on, and numbers them. It fails with
+	// entities.ErrUserStreamConflict and appends nothing if the stream is
+	// at another version.
+	Append(ctx context.Context, userID entities.UserID, expectedVersion int64, events []*entities.UserStreamEvent) error
+	// Load returns the events of the stream of userID after afterVersion,
+	// oldest first.
+	Load(ctx context.Context, userID entities.UserID, afterVersion int64) ([]*entities.UserStreamEvent, error)
+	// SaveSnapshot stores snapshot; a snapshot of the same version is kept.
+	SaveSnapshot(ctx context.Context, snapshot *entities.UserStreamSnapshot) error
+	// LatestSnapshot returns the snapshot of userID with the highest version,
+	// or entities.ErrUserSnapshotNotFound.
+	LatestSnapshot(ctx context.Context, userID entities.UserID) (*entities.UserStreamSnapshot, error)
+	// DeleteStream removes the events and snapshots of userID. A later
+	// Append starts a new stream at version 0.
+	DeleteStream(ctx context.Context, userID entities.UserID) error
+}
+
+// UserReadModelRepository stores the user read model, one denormalized view
+// per live user that listings and searches are served from. Views are kept
+// in sync with the users asynchronously, so they may briefly lag behind.
+type UserReadModelRepository interface {
+	// Upsert stores the views, replacing those of the same users unless they
+	// were projected from a newer version of the user.
+	Upsert(ctx context.Context, views ...*entities.UserView) error
+	// Remove drops the views of the users; unknown IDs are ignored.
+	Remove(ctx context.Context, ids ...entities.UserID) error
+	// List returns up to limit views matching filter after skipping offset,
+	// newest first like UserRepository.List.
+	List(ctx context.Context, filter entities.UserFilter, limit, offset int) ([]*entities.UserView, error)
+	// Lag returns how long the oldest change of a user that is not in the
+	// read model yet has been waiting, or 0 if the read model is current.
+	Lag(ctx context.Context) (time.Duration, error)
 }
 
 // SessionRepository defines the interface for session data access.
@@ -70,12 +373,19 @@ type SessionRepository interface {
 	// CRUD operations
 	Create(ctx context.Context, session *entities.UserSession) error
 	GetByToken(ctx context.Context, token entities.SessionToken) (*entities.UserSession, error)
+	// GetByRefreshToken returns the session whose current or rotated refresh token matches.
+	GetByRefreshToken(ctx context.Context, token entities.RefreshToken) (*entities.UserSession, error)
 	GetByUserID(
 		ctx context.Context,
 		userID entities.UserID,
 		activeOnly bool,
 	) ([]*entities.UserSession, error)
 	Update(ctx context.Context, session *entities.UserSession) error
+	// UpdateIfCurrent stores session like Update, provided the stored session
+	// is still active with the expected refresh token, the one the caller
+	// read. It fails with entities.ErrSessionConflict and stores nothing if
+	// the session was rotated or revoked since.
+	UpdateIfCurrent(ctx context.Context, session *entities.UserSession, expected entities.RefreshToken) error
 	Delete(ctx context.Context, id entities.SessionID) error
 
 	// Session management
@@ -106,4 +416,17 @@ type Transaction interface {
 	// Repository interfaces within transaction
 	UserRepository() UserRepository
 	SessionRepository() SessionRepository
+	OrganizationRepository() OrganizationRepository
+	MembershipRepository() MembershipRepository
+	// The following return nil when the engine does not store the data.
+	IdentityRepository() IdentityRepository
+	AuditRepository() AuditRepository
+	UserHistoryRepository() UserHistoryRepository
+	IdentityChangeRepository() IdentityChangeRepository
+	ModerationRepository() ModerationRepository
+	AccountDeletionRepository() AccountDeletionRepository
+	LoginHistoryRepository() LoginHistoryRepository
+	ActivityRepository() ActivityRepository
+	UserPreferenceRepository() UserPreferenceRepository
+	UserEventStore() UserEventStore
 }
@@ -0,0 +1,226 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// ErrAccountDeletionUnavailable is returned when no account deletion repository is configured.
+var ErrAccountDeletionUnavailable = errors.New("account deletion is not available")
+
+// WithAccountDeletion lets users schedule the deletion of their accounts,
+// recording the pending deletions in repo. Accounts are erased by
+// DeleteDueAccounts once grace has passed, or
+// entities.AccountDeletionGracePeriod if grace is not positive.
+func WithAccountDeletion(repo repositories.AccountDeletionRepository, grace time.Duration) UserServiceOption {
+	return func(s *UserService) {
+		s.deletions = repo
+		s.deletionGrace = grace
+
+		if grace <= 0 {
+			s.deletionGrace = entities.AccountDeletionGracePeriod
+		}
+	}
+}
+
+// RequestAccountDeletion schedules the erasure of the account of a user for
+// the end of the grace period and signs out their sessions. Until then the
+// user cannot log in, and the deletion can be canceled with
+// CancelAccountDeletion; users with a pending deletion are rejected with
+// ErrAccountDeletionPending. The requester is the actor of ctx, or the user
+// without one.
+func (s *UserService) RequestAccountDeletion(
+	ctx context.Context,
+	userID entities.UserID,
+) (_ *entities.AccountDeletion, err error) {
+	ctx, end := s.startSpan(ctx, "RequestAccountDeletion")
+	defer end(&err)
+
+	err = s.checkSwitches(ctx, entities.SwitchReadOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.deletions == nil {
+		return nil, ErrAccountDeletionUnavailable
+	}
+
+	_, err = s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("user %s not found: %w", userID, err)
+	}
+
+	requestedBy := userID
+	if actor, ok := AuditActorFromContext(ctx); ok && actor.UserID != 0 {
+		requestedBy = actor.UserID
+	}
+
+	deletion := entities.NewAccountDeletion(userID, requestedBy, s.deletionGrace)
+
+	err = s.deletions.Create(ctx, deletion)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.sessionRepo.DeactivateByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("sign out user=%v pending deletion: %w", userID, err)
+	}
+
+	s.recordAudit(ctx, entities.AuditActionDeletionRequest, userID, []entities.FieldChange{
+		{Field: "scheduled_for", New: deletion.ScheduledFor.Format(time.RFC3339)},
+	})
+	s.publishEvent(ctx, events.DeletionRequested(deletion))
+
+	return deletion, nil
+}
+
+// CancelAccountDeletion cancels the pending deletion of the account of a
+// user, or returns ErrAccountDeletionNotFound if there is none. The
+// canceler is the actor of ctx, or the user without one.
+func (s *UserService) CancelAccountDeletion(ctx context.Context, userID entities.UserID) (err error) {
+	ctx, end := s.startSpan(ctx, "CancelAccountDeletion")
+	defer end(&err)
+
+	err = s.checkSwitches(ctx, entities.SwitchReadOnly)
+	if err != nil {
+		return err
+	}
+
+	if s.deletions == nil {
+		return ErrAccountDeletionUnavailable
+	}
+
+	deletion, err := s.deletions.GetByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	err = s.deletions.Delete(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	canceledBy := userID
+	if actor, ok := AuditActorFromContext(ctx); ok && actor.UserID != 0 {
+		canceledBy = actor.UserID
+	}
+
+	s.recordAudit(ctx, entities.AuditActionDeletionCancel, userID, []entities.FieldChange{
+		{Field: "scheduled_for", Old: deletion.ScheduledFor.Format(time.RFC3339)},
+	})
+	s.publishEvent(ctx, events.DeletionCanceled(deletion, canceledBy))
+
+	return nil
+}
+
+// PendingAccountDeletion returns the pending deletion of the account of a
+// user, or ErrAccountDeletionNotFound if there is none.
+func (s *UserService) PendingAccountDeletion(
+	ctx context.Context,
+	userID entities.UserID,
+) (_ *entities.AccountDeletion, err error) {
+	ctx, end := s.startSpan(ctx, "PendingAccountDeletion")
+	defer end(&err)
+
+	if s.deletions == nil {
+		return nil, ErrAccountDeletionUnavailable
+	}
+
+	return s.deletions.GetByUser(ctx, userID)
+}
+
+// DeleteDueAccounts erases up to limit accounts whose grace period ended,
+// like EraseUser, and returns how many it erased. Deletions canceled
+// meanwhile are skipped; the other failures are joined and retried by the
+// next run.
+func (s *UserService) DeleteDueAccounts(ctx context.Context, limit int) (_ int, err error) {
+	ctx, end := s.startSpan(ctx, "DeleteDueAccounts")
+	defer end(&err)
+
+	err = s.checkSwitches(ctx, entities.SwitchReadOnly)
+	if err != nil {
+		return 0, err
+	}
+
+	if s.deletions == nil {
+		return 0, ErrAccountDeletionUnavailable
+	}
+
+	if s.txRepo == nil {
+		return 0, ErrTransactionsUnavailable
+	}
+
+	due, err := s.deletions.ListDue(ctx, time.Now(), limit)
+	if err != nil {
+		return 0, fmt.Errorf("list due account deletions: %w", err)
+	}
+
+	var (
+		erased int
+		errs   []error
+	)
+
+	for _, deletion := range due {
+		var avatar entities.UserAvatar
+
+		err = s.txRepo.RunInTransaction(ctx, func(ctx context.Context, tx repositories.Transaction) error {
+			deletions := tx.AccountDeletionRepository()
+			if deletions == nil {
+				deletions = s.deletions
+			}
+
+			// Removing the deletion first claims it, and fails if it was
+			// canceled since it was listed.
+			err := deletions.Delete(ctx, deletion.UserID)
+			if err != nil {
+				return err
+			}
+
+			avatar, err = eraseUser(ctx, tx, deletion.UserID)
+
+			return err
+		})
+
+		switch {
+		case errors.Is(err, entities.ErrAccountDeletionNotFound):
+			continue
+		case err != nil:
+			errs = append(errs, fmt.Errorf("erase user %s due for deletion: %w", deletion.UserID, err))
+
+			continue
+		}
+
+		erased++
+
+		s.completeErasure(ctx, deletion.UserID, avatar, deletion.RequestedBy)
+	}
+
+	return erased, errors.Join(errs...)
+}
+
+// checkDeletionPending returns ErrAccountDeleting if the deletion of the
+// account of a user is pending, so that they stay signed out until it is
+// canceled.
+func (s *UserService) checkDeletionPending(ctx context.Context, userID entities.UserID) error {
+	if s.deletions == nil {
+		return nil
+	}
+
+	_, err := s.deletions.GetByUser(ctx, userID)
+
+	switch {
+	case err == nil:
+		return fmt.Errorf("user=%v: %w", userID, entities.ErrAccountDeleting)
+	case errors.Is(err, entities.ErrAccountDeletionNotFound):
+		return nil
+	default:
+		return fmt.Errorf("pending deletion of user=%v: %w", userID, err)
+	}
+}
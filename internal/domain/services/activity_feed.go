@@ -0,0 +1,250 @@
+package services
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// activityKind is how an event type shows up in activity feeds. The summary
+// is used unless describe returns a more specific one from the event data.
+type activityKind struct {
+	kind     entities.ActivityKind
+	summary  string
+	describe func(data activityData) string
+}
+
+// activityKinds returns the event types recorded in activity feeds.
+func activityKinds() map[events.EventType]activityKind {
+	signIn := entities.ActivityKindSignIn
+	security := entities.ActivityKindSecurity
+	profile := entities.ActivityKindProfile
+	account := entities.ActivityKindAccount
+	organization := entities.ActivityKindOrganization
+
+	return map[events.EventType]activityKind{
+		events.EventUserLogin:              {signIn, "Signed in", describeLogin},
+		events.EventUserLogout:             {signIn, "Signed out", nil},
+		events.EventUserLoginFail:          {security, "A sign-in failed", nil},
+		events.EventUserLoginSuspicious:    {security, "Signed in from a new country or device", nil},
+		events.EventPasswordChanged:        {security, "Changed password", nil},
+		events.EventPasswordReset:          {security, "Reset password", nil},
+		events.EventRefreshTokenReused:     {security, "Signed out everywhere after a revoked token was used", nil},
+		events.EventImpersonationStarted:   {security, "An administrator signed in as you", nil},
+		events.EventImpersonationEnded:     {security, "An administrator stopped signing in as you", nil},
+		events.EventEmailChanged:           {security, "Changed email address", nil},
+		events.EventUsernameChanged:        {security, "Changed username", nil},
+		events.EventIdentityChangeReverted: {security, "Reverted a change of email address or username", nil},
+		events.EventIdentityLinked:         {security, "Linked an external account", describeIdentity("Linked")},
+		events.EventIdentityUnlinked:       {security, "Unlinked an external account", describeIdentity("Unlinked")},
+		events.EventUserUpdated:            {profile, "Updated profile", describeUpdate},
+		events.EventProfileUpdated:         {profile, "Updated profile", nil},
+		events.EventUserCreated:            {account, "Created account", nil},
+		events.EventUserVerified:           {account, "Verified email address", nil},
+		events.EventRoleChanged:            {account, "Role changed", describeRoleChange},
+		events.EventUserActivated:          {account, "Account activated", nil},
+		events.EventUserDeactivated:        {account, "Account deactivated", nil},
+		events.EventUserSuspended:          {account, "Account suspended", nil},
+		events.EventUserReinstated:         {account, "Account reinstated", nil},
+		events.EventAppealSubmitted:        {account, "Appealed a suspension", nil},
+		events.EventAppealDecided:          {account, "Appeal of a suspension decided", nil},
+		events.EventDeletionRequested:      {account, "Requested to delete account", nil},
+		events.EventDeletionCanceled:       {account, "Canceled deleting account", nil},
+		events.EventUserRestored:           {account, "Account restored", nil},
+		events.EventUserDataExported:       {account, "Exported a copy of your data", nil},
+		events.EventMemberInvited:          {organization, "Invited to an organization", nil},
+		events.EventMemberJoined:           {organization, "Joined an organization", nil},
+		events.EventMemberLeft:             {organization, "Left an organization", nil},
+		events.EventMemberRoleChanged:      {organization, "Role in an organization changed", describeMemberRole},
+	}
+}
+
+// activityData holds the fields of the event payloads that activity entries
+// are described with. Payloads arrive as structs from in-process publishers
+// and as raw JSON once redacted, so both are decoded through JSON. None of
+// the fields is personal data that redaction would hide.
+type activityData struct {
+	Provider string         `json:"provider"`
+	Changes  map[string]any `json:"changes"`
+	OldRole  string         `json:"oldRole"`
+	NewRole  string         `json:"newRole"`
+	Role     string         `json:"role"`
+
+	UpdatedBy      entities.UserID `json:"updatedBy"`
+	ChangedBy      entities.UserID `json:"changedBy"`
+	ImpersonatorID entities.UserID `json:"impersonatorId"`
+	ActorID        entities.UserID `json:"actorId"`
+	RequestedBy    entities.UserID `json:"requestedBy"`
+}
+
+// decodeActivityData decodes the payload of event.
+func decodeActivityData(event *events.UserEvent) (activityData, error) {
+	var data activityData
+
+	payload, ok := event.Data.(json.RawMessage)
+	if !ok {
+		var err error
+
+		payload, err = json.Marshal(event.Data)
+		if err != nil {
+			return data, fmt.Errorf("encode event id=%v data: %w", event.ID, err)
+		}
+	}
+
+	err := json.Unmarshal(payload, &data)
+	if err != nil {
+		return data, fmt.Errorf("decode event id=%v data: %w", event.ID, err)
+	}
+
+	return data, nil
+}
+
+// actor returns who caused the event; each payload names at most one.
+func (d activityData) actor() entities.UserID {
+	return cmp.Or(d.UpdatedBy, d.ChangedBy, d.ImpersonatorID, d.ActorID, d.RequestedBy)
+}
+
+// describeLogin names the identity provider of federated logins.
+func describeLogin(data activityData) string {
+	if data.Provider == "" {
+		return ""
+	}
+
+	return "Signed in with " + data.Provider
+}
+
+// describeIdentity names the identity provider of linked and unlinked identities.
+func describeIdentity(verb string) func(data activityData) string {
+	return func(data activityData) string {
+		if data.Provider == "" {
+			return ""
+		}
+
+		return verb + " a " + data.Provider + " account"
+	}
+}
+
+// describeUpdate lists the changed fields, never their values.
+func describeUpdate(data activityData) string {
+	if len(data.Changes) == 0 {
+		return ""
+	}
+
+	fields := slices.Sorted(maps.Keys(data.Changes))
+	for i, field := range fields {
+		fields[i] = strings.ReplaceAll(field, "_", " ")
+	}
+
+	return "Updated profile: " + strings.Join(fields, ", ")
+}
+
+// describeRoleChange names the old and the new role.
+func describeRoleChange(data activityData) string {
+	if data.OldRole == "" || data.NewRole == "" {
+		return ""
+	}
+
+	return "Role changed from " + data.OldRole + " to " + data.NewRole
+}
+
+// describeMemberRole names the new role in the organization.
+func describeMemberRole(data activityData) string {
+	if data.Role == "" {
+		return ""
+	}
+
+	return "Role in an organization changed to " + data.Role
+}
+
+// ActivityFeed is an events.EventHandler projecting domain events into
+// human-readable activity entries, such as "Signed in" or "Role changed
+// from user to admin", and reads the feeds back. Summaries never contain
+// personal data, so the feed needs no redaction.
+type ActivityFeed struct {
+	activity repositories.ActivityRepository
+	users    repositories.UserRepository
+}
+
+var _ events.EventHandler = (*ActivityFeed)(nil)
+
+// NewActivityFeed creates an activity feed stored in activity. users is
+// consulted when an entry cannot be stored, to drop the entries of users
+// that were deleted.
+func NewActivityFeed(activity repositories.ActivityRepository, users repositories.UserRepository) *ActivityFeed {
+	return &ActivityFeed{activity: activity, users: users}
+}
+
+// EventTypes returns the event types recorded in activity feeds, to
+// subscribe the feed with.
+func (f *ActivityFeed) EventTypes() []events.EventType {
+	return slices.Sorted(maps.Keys(activityKinds()))
+}
+
+// Handle records event in the feed of the user it is about. Redelivered
+// events are recorded once. Events of other types, events about no user,
+// and events about users that were deleted are ignored.
+func (f *ActivityFeed) Handle(ctx context.Context, event *events.UserEvent) error {
+	kind, ok := activityKinds()[event.Type]
+	if !ok || event.UserID <= 0 {
+		return nil
+	}
+
+	data, err := decodeActivityData(event)
+	if err != nil {
+		return err
+	}
+
+	summary := kind.summary
+	if kind.describe != nil {
+		summary = cmp.Or(kind.describe(data), summary)
+	}
+
+	entry, err := entities.NewActivityEntry(
+		event.UserID,
+		event.ID,
+		string(event.Type),
+		kind.kind,
+		summary,
+		data.actor(),
+		event.Timestamp,
+	)
+	if err != nil {
+		return fmt.Errorf("project activity event=%v: %w", event.ID, err)
+	}
+
+	err = f.activity.Record(ctx, entry)
+	if err != nil {
+		_, getErr := f.users.GetByID(ctx, event.UserID)
+		if entities.IsNotFoundError(getErr) {
+			return nil
+		}
+
+		return fmt.Errorf("record activity event=%v: %w", event.ID, err)
+	}
+
+	return nil
+}
+
+// List returns up to limit entries of the feed of a user after cursor,
+// newest first. An empty cursor requests the first page.
+func (f *ActivityFeed) List(
+	ctx context.Context,
+	userID entities.UserID,
+	cursor string,
+	limit int,
+) (*entities.ActivityPage, error) {
+	page, err := f.activity.ListPage(ctx, userID, cursor, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list activity user=%v: %w", userID, err)
+	}
+
+	return page, nil
+}
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// AuditActor identifies who performs an operation and from where.
+type AuditActor struct {
+	UserID    entities.UserID
+	IPAddress string
+}
+
+// auditActorKey is the context key for the AuditActor.
+type auditActorKey struct{}
+
+// ContextWithAuditActor attaches actor to ctx so that audit entries written
+// by the service layer attribute operations to it. Transports set it once
+// the caller is authenticated.
+func ContextWithAuditActor(ctx context.Context, actor AuditActor) context.Context {
+	return context.WithValue(ctx, auditActorKey{}, actor)
+}
+
+// AuditActorFromContext returns the actor attached to ctx.
+func AuditActorFromContext(ctx context.Context) (AuditActor, bool) {
+	actor, ok := ctx.Value(auditActorKey{}).(AuditActor)
+
+	return actor, ok
+}
+
+// withFallbackActor attributes operations to userID unless ctx already names an actor.
+func withFallbackActor(ctx context.Context, userID entities.UserID) context.Context {
+	_, ok := AuditActorFromContext(ctx)
+	if ok || userID == 0 {
+		return ctx
+	}
+
+	return ContextWithAuditActor(ctx, AuditActor{UserID: userID})
+}
+
+// WithAuditLog records every mutating operation of the service in repo.
+func WithAuditLog(repo repositories.AuditRepository) UserServiceOption {
+	return func(s *UserService) {
+		s.audit = repo
+	}
+}
+
+// recordAudit writes an audit entry for a change to userID and logs a warning if it fails.
+// Like events, entries are written after the change is persisted.
+func (s *UserService) recordAudit(
+	ctx context.Context,
+	action entities.AuditAction,
+	userID entities.UserID,
+	changes []entities.FieldChange,
+) {
+	if s.audit == nil {
+		return
+	}
+
+	actor, _ := AuditActorFromContext(ctx)
+	entry := entities.NewAuditLog(actor.UserID, userID, action, changes, actor.IPAddress)
+
+	err := s.audit.Record(ctx, entry)
+	if err != nil {
+		slog.Warn("failed to record audit log", "action", action, "user_id", userID, "error", err)
+	}
+}
+
+// auditDiff returns the audited fields that differ between before and user.
+// A nil before describes a newly created user.
+func auditDiff(before *entities.UserSnapshot, user *entities.User) []entities.FieldChange {
+	if before == nil {
+		before = &entities.UserSnapshot{}
+	}
+
+	return before.Diff(entities.SnapshotOf(user))
+}
+
+// AuditService queries the audit log.
+type AuditService struct {
+	audit repositories.AuditRepository
+}
+
+// NewAuditService creates a new audit service.
+func NewAuditService(audit repositories.AuditRepository) *AuditService {
+	return &AuditService{audit: audit}
+}
+
+// List returns audit entries matching filter, newest first.
+func (s *AuditService) List(
+	ctx context.Context,
+	filter entities.AuditFilter,
+) ([]*entities.AuditLog, error) {
+	if filter.Limit <= 0 {
+		return nil, entities.NewValidationError("limit", "must be positive")
+	}
+
+	if filter.Offset < 0 {
+		return nil, entities.NewValidationError("offset", "must not be negative")
+	}
+
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.To.After(filter.From) {
+		return nil, entities.NewValidationError("to", "must be after from")
+	}
+
+	entries, err := s.audit.List(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("list audit log user=%v action=%v: %w", filter.UserID, filter.Action, err)
+	}
+
+	return entries, nil
+}
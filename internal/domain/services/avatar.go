@@ -0,0 +1,257 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif" // Accept GIF avatars.
+	"image/jpeg"
+	"image/png"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+	"github.com/google/uuid"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // Accept WebP avatars.
+)
+
+// avatarJPEGQuality is the quality of stored JPEG avatars.
+const avatarJPEGQuality = 85
+
+// avatarFormats are the image formats accepted for avatars, as named by image.DecodeConfig.
+var avatarFormats = []string{"jpeg", "png", "gif", "webp"} //nolint:gochecknoglobals // constant list
+
+// ErrAvatarsUnavailable is returned when no avatar store is configured.
+var ErrAvatarsUnavailable = errors.New("avatars are not available")
+
+// AvatarStore stores avatar images and serves them to clients. It is
+// implemented by the blob stores in pkg/storage.
+type AvatarStore interface {
+	Put(ctx context.Context, key string, body io.Reader, contentType string) error
+	Delete(ctx context.Context, key string) error
+	URL(key string) string
+}
+
+// WithAvatars lets users upload avatar images, which are stored in store.
+func WithAvatars(store AvatarStore) UserServiceOption {
+	return func(s *UserService) {
+		s.avatars = store
+	}
+}
+
+// UploadAvatar replaces the avatar of a user with the image read from
+// upload. JPEG, PNG, GIF and WebP images of up to entities.AvatarMaxBytes
+// and entities.AvatarMaxDimension pixels are accepted. They are cropped to
+// a square and scaled down to entities.AvatarSize, so stored avatars never
+// carry the metadata of the upload. PNG and GIF images are stored as PNG to
+// keep transparency; the others as JPEG.
+func (s *UserService) UploadAvatar(
+	ctx context.Context,
+	userID entities.UserID,
+	upload io.Reader,
+) (_ *entities.User, err error) {
+	ctx, end := s.startSpan(ctx, "UploadAvatar")
+	defer end(&err)
+
+	err = s.checkSwitches(ctx, entities.SwitchReadOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.avatars == nil {
+		return nil, ErrAvatarsUnavailable
+	}
+
+	data, err := io.ReadAll(io.LimitReader(upload, entities.AvatarMaxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("read avatar user=%v: %w", userID, err)
+	}
+
+	if len(data) > entities.AvatarMaxBytes {
+		return nil, entities.ErrAvatarTooLarge
+	}
+
+	img, err := processAvatar(data)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("user %s not found: %w", userID, err)
+	}
+
+	key := avatarKeyPrefix(userID) + uuid.NewString() + img.extension
+
+	err = s.avatars.Put(ctx, key, bytes.NewReader(img.data), img.contentType)
+	if err != nil {
+		return nil, fmt.Errorf("store avatar user=%v: %w", userID, err)
+	}
+
+	previous := user.Avatar()
+	user.SetAvatar(entities.UserAvatar{
+		Key:         key,
+		URL:         s.avatars.URL(key),
+		ContentType: img.contentType,
+		Width:       img.width,
+		Height:      img.height,
+		Size:        int64(len(img.data)),
+		UpdatedAt:   time.Now(),
+	})
+
+	err = s.userRepo.Update(ctx, user)
+	if err != nil {
+		s.deleteAvatar(ctx, userID, key)
+
+		return nil, fmt.Errorf("failed to update user: %w", err)
+	}
+
+	s.deleteAvatar(ctx, userID, previous.Key)
+	s.avatarChanged(ctx, user, previous)
+
+	return user, nil
+}
+
+// RemoveAvatar removes the avatar of a user. Users without an avatar are
+// returned unchanged.
+func (s *UserService) RemoveAvatar(ctx context.Context, userID entities.UserID) (_ *entities.User, err error) {
+	ctx, end := s.startSpan(ctx, "RemoveAvatar")
+	defer end(&err)
+
+	err = s.checkSwitches(ctx, entities.SwitchReadOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.avatars == nil {
+		return nil, ErrAvatarsUnavailable
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("user %s not found: %w", userID, err)
+	}
+
+	previous := user.Avatar()
+	if previous.IsZero() {
+		return user, nil
+	}
+
+	user.ClearAvatar()
+
+	err = s.userRepo.Update(ctx, user)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update user: %w", err)
+	}
+
+	s.deleteAvatar(ctx, userID, previous.Key)
+	s.avatarChanged(ctx, user, previous)
+
+	return user, nil
+}
+
+// avatarChanged audits and publishes the replacement of previous with the
+// current avatar of user.
+func (s *UserService) avatarChanged(ctx context.Context, user *entities.User, previous entities.UserAvatar) {
+	current := user.Avatar()
+
+	ctx = withFallbackActor(ctx, user.ID())
+	s.recordAudit(ctx, entities.AuditActionAvatarChange, user.ID(), []entities.FieldChange{
+		{Field: "avatar", Old: previous.URL, New: current.URL},
+	})
+	s.publishEvent(ctx, events.UserUpdated(user.ID(), map[string]any{
+		"avatar": map[string]any{
+			changeKeyOld: previous.URL,
+			changeKeyNew: current.URL,
+		},
+	}, user.ID()))
+}
+
+// deleteAvatar deletes the avatar image key of userID and logs a warning if
+// it fails, since the user no longer links to it. Keys outside the avatars
+// of the user are never deleted.
+func (s *UserService) deleteAvatar(ctx context.Context, userID entities.UserID, key string) {
+	if s.avatars == nil || !strings.HasPrefix(key, avatarKeyPrefix(userID)) {
+		return
+	}
+
+	err := s.avatars.Delete(ctx, key)
+	if err != nil {
+		slog.Warn("failed to delete avatar", "user_id", userID, "key", key, "error", err)
+	}
+}
+
+// avatarKeyPrefix returns the prefix of the avatar keys of userID.
+func avatarKeyPrefix(userID entities.UserID) string {
+	return fmt.Sprintf("avatars/%d/", userID)
+}
+
+// avatarImage is an encoded avatar ready to be stored.
+type avatarImage struct {
+	data        []byte
+	contentType string
+	extension   string
+	width       int
+	height      int
+}
+
+// processAvatar validates an uploaded image, crops it to a square around
+// its center, scales it down to entities.AvatarSize and encodes it again.
+func processAvatar(data []byte) (avatarImage, error) {
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return avatarImage{}, entities.ErrInvalidAvatar
+	}
+
+	// Other packages may register decoders for formats browsers do not show.
+	if !slices.Contains(avatarFormats, format) || config.Width <= 0 || config.Height <= 0 {
+		return avatarImage{}, entities.ErrInvalidAvatar
+	}
+
+	if config.Width > entities.AvatarMaxDimension || config.Height > entities.AvatarMaxDimension {
+		return avatarImage{}, entities.ErrAvatarTooLarge
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return avatarImage{}, entities.ErrInvalidAvatar
+	}
+
+	side := min(src.Bounds().Dx(), src.Bounds().Dy())
+	crop := image.Rect(0, 0, side, side).Add(src.Bounds().Min).Add(image.Pt(
+		(src.Bounds().Dx()-side)/2,
+		(src.Bounds().Dy()-side)/2,
+	))
+
+	size := min(side, entities.AvatarSize)
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, crop, draw.Src, nil)
+
+	var buf bytes.Buffer
+
+	img := avatarImage{width: size, height: size}
+
+	switch format {
+	case "png", "gif":
+		img.contentType, img.extension = "image/png", ".png"
+		err = png.Encode(&buf, dst)
+	default:
+		img.contentType, img.extension = "image/jpeg", ".jpg"
+		err = jpeg.Encode(&buf, dst, &jpeg.Options{Quality: avatarJPEGQuality})
+	}
+
+	if err != nil {
+		return avatarImage{}, fmt.Errorf("encode avatar: %w", err)
+	}
+
+	img.data = buf.Bytes()
+
+	return img, nil
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+)
+
+// ErrPasswordHasherUnavailable is returned when users are authenticated by a
+// service without a password hasher; see WithPasswordHasher.
+var ErrPasswordHasherUnavailable = errors.New("password hasher is not configured")
+
+// dummyHash is the hash of a random password that is verified in place of
+// a missing one, so that an unknown email costs as much as a wrong password.
+// It is hashed once, on first use, with the service's hasher.
+type dummyHash struct {
+	once sync.Once
+	hash string
+}
+
+// get returns the dummy hash of hasher.
+func (d *dummyHash) get(hasher PasswordHasher) string {
+	d.once.Do(func() {
+		hash, err := hasher.Hash(rand.Text())
+		if err != nil {
+			slog.Warn("failed to hash dummy password", "error", err)
+
+			hash = entities.NewUnusablePasswordHash().String()
+		}
+
+		d.hash = hash
+	})
+
+	return d.hash
+}
+
+// verifyCredentials returns the user matching email and password. The
+// repository only fetches the user; the password is verified here, and an
+// unknown email or a user without a usable password is verified against the
+// dummy hash, so that every attempt takes the same path: one lookup and one
+// verification. Callers check that the service has a hasher.
+func (s *UserService) verifyCredentials(
+	ctx context.Context,
+	email entities.Email,
+	password string,
+) (*entities.User, error) {
+	user, err := s.userRepo.GetCredentials(ctx, email)
+	if err != nil && !errors.Is(err, entities.ErrUserNotFound) {
+		return nil, err
+	}
+
+	usable := user != nil && user.PasswordHash().IsUsable()
+
+	hash := s.dummy.get(s.hasher)
+	if usable {
+		hash = user.PasswordHash().String()
+	}
+
+	err = s.hasher.Verify(hash, password)
+	if err != nil || !usable {
+		return nil, fmt.Errorf("email=%v: %w", email, entities.ErrInvalidCredentials)
+	}
+
+	return user, nil
+}
@@ -0,0 +1,175 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// DefaultIdempotencyTTL is how long the response of a write with an
+// idempotency key is replayed unless WithIdempotency sets another TTL.
+const DefaultIdempotencyTTL = 24 * time.Hour
+
+// idempotencyLock is how long a running write holds its key. A write that
+// crashed before completing frees its key after the lock.
+const idempotencyLock = time.Minute
+
+// WithIdempotency makes CreateUser and UpdateUser honor the IdempotencyKey
+// of their requests: the first write with a key runs and the ID of the user
+// it wrote is kept in repo for ttl, or DefaultIdempotencyTTL if ttl is not
+// positive, and later requests with the key get that user as it is now.
+// Failed writes are not kept, so they can be retried. Without this option
+// keys are ignored.
+func WithIdempotency(repo repositories.IdempotencyRepository, ttl time.Duration) UserServiceOption {
+	return func(s *UserService) {
+		s.idempotency = repo
+		s.idempotencyTTL = ttl
+
+		if ttl <= 0 {
+			s.idempotencyTTL = DefaultIdempotencyTTL
+		}
+	}
+}
+
+// idempotentResponse is the response kept for a user write. Only the ID is
+// kept and the user reloaded on replay, so that keys hold no personal data
+// and an erased user cannot be replayed. It also decodes the user records
+// kept as responses before.
+type idempotentResponse struct {
+	ID entities.UserID
+}
+
+// idempotentUser runs the user write operation once per key. request is
+// fingerprinted to detect a key reused for a different request, which
+// returns ErrIdempotencyKeyMismatch; a duplicate of a write that is still
+// running returns ErrIdempotentRequestInProgress.
+func (s *UserService) idempotentUser(
+	ctx context.Context,
+	key, operation string,
+	request any,
+	write func(ctx context.Context) (*entities.User, error),
+) (*entities.User, error) {
+	if key == "" || s.idempotency == nil {
+		return write(ctx)
+	}
+
+	idempotencyKey, err := entities.NewIdempotencyKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	requestHash, err := fingerprint(request)
+	if err != nil {
+		return nil, err
+	}
+
+	record := entities.NewIdempotencyRecord(idempotencyKey, operation, requestHash, idempotencyLock)
+
+	stored, err := s.reserveIdempotencyKey(ctx, record)
+	if err != nil {
+		return nil, err
+	}
+
+	if stored != nil {
+		return s.replayUser(ctx, stored)
+	}
+
+	user, err := write(ctx)
+	if err != nil {
+		releaseErr := s.idempotency.Release(ctx, record.Key)
+		if releaseErr != nil {
+			slog.Warn("failed to release idempotency key", "operation", operation, "error", releaseErr)
+		}
+
+		return nil, err
+	}
+
+	encoded, err := json.Marshal(idempotentResponse{ID: user.ID()})
+	if err == nil {
+		record.Complete(encoded, s.idempotencyTTL)
+		err = s.idempotency.Complete(ctx, record)
+	}
+
+	// The write succeeded, so it is returned even if its response is not
+	// kept; a retry then fails like a duplicate without a key would.
+	if err != nil {
+		slog.Warn("failed to keep idempotent response", "operation", operation, "error", err)
+	}
+
+	return user, nil
+}
+
+// reserveIdempotencyKey reserves the key of record for a new write. If a
+// write with the key completed before, its record is returned instead.
+func (s *UserService) reserveIdempotencyKey(
+	ctx context.Context,
+	record *entities.IdempotencyRecord,
+) (*entities.IdempotencyRecord, error) {
+	err := s.idempotency.Reserve(ctx, record)
+	if !errors.Is(err, entities.ErrIdempotencyKeyExists) {
+		return nil, err
+	}
+
+	stored, err := s.idempotency.Get(ctx, record.Key)
+	if errors.Is(err, entities.ErrIdempotencyKeyNotFound) {
+		// The earlier write failed and released the key in between.
+		return nil, s.idempotency.Reserve(ctx, record)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case stored.IsExpired(record.CreatedAt):
+		_, err = s.idempotency.DeleteExpired(ctx, record.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, s.idempotency.Reserve(ctx, record)
+	case !stored.Matches(record.Operation, record.RequestHash):
+		return nil, fmt.Errorf("key=%v operation=%v: %w", record.Key, stored.Operation, entities.ErrIdempotencyKeyMismatch)
+	case !stored.IsCompleted():
+		return nil, fmt.Errorf("key=%v: %w", record.Key, entities.ErrIdempotentRequestInProgress)
+	}
+
+	return stored, nil
+}
+
+// replayUser returns the user whose ID is kept as the response of record.
+func (s *UserService) replayUser(ctx context.Context, record *entities.IdempotencyRecord) (*entities.User, error) {
+	var response idempotentResponse
+
+	err := json.Unmarshal(record.Response, &response)
+	if err != nil {
+		return nil, fmt.Errorf("replay key=%v: %w", record.Key, err)
+	}
+
+	user, err := s.userRepo.GetByID(ctx, response.ID)
+	if err != nil {
+		return nil, fmt.Errorf("replay key=%v: %w", record.Key, err)
+	}
+
+	return user, nil
+}
+
+// fingerprint returns the hex SHA-256 hash of the JSON encoding of request.
+func fingerprint(request any) (string, error) {
+	encoded, err := json.Marshal(request)
+	if err != nil {
+		return "", fmt.Errorf("fingerprint request: %w", err)
+	}
+
+	sum := sha256.Sum256(encoded)
+
+	return hex.EncodeToString(sum[:]), nil
+}
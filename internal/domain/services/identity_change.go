@@ -0,0 +1,438 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+	"github.com/google/uuid"
+)
+
+// ErrIdentityChangesUnavailable is returned when no identity change repository is configured.
+var ErrIdentityChangesUnavailable = errors.New("identity changes are not available")
+
+// WithIdentityChanges lets users change their email address and username,
+// recording the changes in repo. Applied changes can be reverted for grace,
+// or entities.IdentityChangeGracePeriod if grace is not positive.
+func WithIdentityChanges(repo repositories.IdentityChangeRepository, grace time.Duration) UserServiceOption {
+	return func(s *UserService) {
+		s.identityChanges = repo
+		s.identityGrace = grace
+
+		if grace <= 0 {
+			s.identityGrace = entities.IdentityChangeGracePeriod
+		}
+	}
+}
+
+// RequestEmailChange starts changing the email address of a user to email.
+// The change stays pending until it is confirmed with ConfirmEmailChange and
+// the token of the user.email_change.requested event, which is meant to be
+// sent to the new address. Addresses of other users are rejected with
+// ErrUserAlreadyExists.
+func (s *UserService) RequestEmailChange(ctx context.Context, userID entities.UserID, email string) (err error) {
+	ctx, end := s.startSpan(ctx, "RequestEmailChange")
+	defer end(&err)
+
+	err = s.checkSwitches(ctx, entities.SwitchReadOnly)
+	if err != nil {
+		return err
+	}
+
+	if s.identityChanges == nil {
+		return ErrIdentityChangesUnavailable
+	}
+
+	newEmail, err := entities.NewEmail(email)
+	if err != nil {
+		return err
+	}
+
+	if s.validator != nil {
+		err = s.validator.ValidateEmail(ctx, newEmail.String())
+		if err != nil {
+			return fmt.Errorf("validation failed: %w", err)
+		}
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("user %s not found: %w", userID, err)
+	}
+
+	change, err := entities.NewEmailChange(userID, user.Email(), newEmail, entities.EmailChangeLifetime)
+	if err != nil {
+		return err
+	}
+
+	// Checked again on confirmation, since the address may be taken meanwhile.
+	err = identityAvailable(ctx, s.userRepo, change.Field, change.NewValue, userID)
+	if err != nil {
+		return err
+	}
+
+	err = s.identityChanges.Create(ctx, change)
+	if err != nil {
+		return fmt.Errorf("request email change user=%v: %w", userID, err)
+	}
+
+	s.publishEvent(ctx, events.EmailChangeRequested(change))
+
+	return nil
+}
+
+// ConfirmEmailChange applies the pending email change of token. Confirming
+// proves the user owns the new address, so the user is verified. The change
+// can then be reverted with the token of the user.email.changed event, which
+// is meant to be sent to the old address, until the grace period ends.
+func (s *UserService) ConfirmEmailChange(ctx context.Context, token string) (_ *entities.User, err error) {
+	ctx, end := s.startSpan(ctx, "ConfirmEmailChange")
+	defer end(&err)
+
+	err = s.checkSwitches(ctx, entities.SwitchReadOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	change, err := s.identityChangeByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	err = change.Apply(s.identityGrace)
+	if err != nil {
+		return nil, fmt.Errorf("identity change id=%v: %w", change.ID, err)
+	}
+
+	var (
+		user   *entities.User
+		before *entities.UserSnapshot
+	)
+
+	err = s.inIdentityTransaction(ctx, func(
+		ctx context.Context,
+		users repositories.UserRepository,
+		changes repositories.IdentityChangeRepository,
+	) error {
+		user, err = users.GetByID(ctx, change.UserID)
+		if err != nil {
+			return fmt.Errorf("user %s not found: %w", change.UserID, err)
+		}
+
+		if user.Email().String() != change.OldValue {
+			return fmt.Errorf("identity change id=%v: %w", change.ID, entities.ErrIdentityChangeSuperseded)
+		}
+
+		err = identityAvailable(ctx, users, change.Field, change.NewValue, user.ID())
+		if err != nil {
+			return err
+		}
+
+		before = entities.SnapshotOf(user)
+		user.ChangeEmail(entities.Email(change.NewValue))
+		user.Verify()
+
+		return saveIdentityChange(ctx, users, changes, user, change)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = withFallbackActor(ctx, user.ID())
+	s.recordAudit(ctx, entities.AuditActionEmailChange, user.ID(), auditDiff(before, user))
+	s.publishEvent(ctx, events.IdentityChangeApplied(change))
+
+	return user, nil
+}
+
+// ChangeUsername changes the username of a user right away. Usernames of
+// other users are rejected with ErrUserAlreadyExists. The change can be
+// reverted with the token of the user.username.changed event until the
+// grace period ends.
+func (s *UserService) ChangeUsername(
+	ctx context.Context,
+	userID entities.UserID,
+	username string,
+) (_ *entities.User, err error) {
+	ctx, end := s.startSpan(ctx, "ChangeUsername")
+	defer end(&err)
+
+	err = s.checkSwitches(ctx, entities.SwitchReadOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.identityChanges == nil {
+		return nil, ErrIdentityChangesUnavailable
+	}
+
+	newUsername, err := entities.NewUsername(username)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.validator != nil {
+		err = s.validator.ValidateUsername(newUsername.String())
+		if err != nil {
+			return nil, fmt.Errorf("validation failed: %w", err)
+		}
+	}
+
+	var (
+		user   *entities.User
+		before *entities.UserSnapshot
+		change *entities.IdentityChange
+	)
+
+	err = s.inIdentityTransaction(ctx, func(
+		ctx context.Context,
+		users repositories.UserRepository,
+		changes repositories.IdentityChangeRepository,
+	) error {
+		user, err = users.GetByID(ctx, userID)
+		if err != nil {
+			return fmt.Errorf("user %s not found: %w", userID, err)
+		}
+
+		change, err = entities.NewUsernameChange(userID, user.Username(), newUsername, s.identityGrace)
+		if err != nil {
+			return err
+		}
+
+		err = identityAvailable(ctx, users, change.Field, change.NewValue, userID)
+		if err != nil {
+			return err
+		}
+
+		before = entities.SnapshotOf(user)
+		user.ChangeUsername(newUsername)
+
+		return saveIdentityChange(ctx, users, changes, user, change)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = withFallbackActor(ctx, user.ID())
+	s.recordAudit(ctx, entities.AuditActionUsernameChange, user.ID(), auditDiff(before, user))
+	s.publishEvent(ctx, events.IdentityChangeApplied(change))
+
+	return user, nil
+}
+
+// RevertIdentityChange undoes the applied email or username change of token
+// within its grace period, restoring the old value if no other user took it
+// meanwhile. Whoever made the change may have taken over the account, so
+// every session of the user is signed out.
+func (s *UserService) RevertIdentityChange(ctx context.Context, token string) (_ *entities.User, err error) {
+	ctx, end := s.startSpan(ctx, "RevertIdentityChange")
+	defer end(&err)
+
+	err = s.checkSwitches(ctx, entities.SwitchReadOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	change, err := s.identityChangeByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	err = change.Revert()
+	if err != nil {
+		return nil, fmt.Errorf("identity change id=%v: %w", change.ID, err)
+	}
+
+	var (
+		user   *entities.User
+		before *entities.UserSnapshot
+	)
+
+	err = s.inIdentityTransaction(ctx, func(
+		ctx context.Context,
+		users repositories.UserRepository,
+		changes repositories.IdentityChangeRepository,
+	) error {
+		user, err = users.GetByID(ctx, change.UserID)
+		if err != nil {
+			return fmt.Errorf("user %s not found: %w", change.UserID, err)
+		}
+
+		if identityValue(user, change.Field) != change.NewValue {
+			return fmt.Errorf("identity change id=%v: %w", change.ID, entities.ErrIdentityChangeSuperseded)
+		}
+
+		err = identityAvailable(ctx, users, change.Field, change.OldValue, user.ID())
+		if err != nil {
+			return err
+		}
+
+		before = entities.SnapshotOf(user)
+
+		switch change.Field {
+		case entities.IdentityFieldEmail:
+			user.ChangeEmail(entities.Email(change.OldValue))
+		case entities.IdentityFieldUsername:
+			user.ChangeUsername(entities.Username(change.OldValue))
+		}
+
+		return saveIdentityChange(ctx, users, changes, user, change)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.sessionRepo.DeactivateByUserID(ctx, user.ID())
+	if err != nil {
+		return nil, fmt.Errorf("sign out user=%v after revert: %w", user.ID(), err)
+	}
+
+	ctx = withFallbackActor(ctx, user.ID())
+	s.recordAudit(ctx, entities.AuditActionIdentityRevert, user.ID(), auditDiff(before, user))
+	s.publishEvent(ctx, events.IdentityChangeReverted(change))
+
+	return user, nil
+}
+
+// IdentityHistory returns the email address and username changes of a
+// user, newest first.
+func (s *UserService) IdentityHistory(
+	ctx context.Context,
+	userID entities.UserID,
+) (_ []*entities.IdentityChange, err error) {
+	ctx, end := s.startSpan(ctx, "IdentityHistory")
+	defer end(&err)
+
+	if s.identityChanges == nil {
+		return nil, ErrIdentityChangesUnavailable
+	}
+
+	changes, err := s.identityChanges.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("identity history user=%v: %w", userID, err)
+	}
+
+	return changes, nil
+}
+
+// identityChangeByToken looks up the change of token. Malformed tokens are
+// reported like unknown ones.
+func (s *UserService) identityChangeByToken(ctx context.Context, token string) (*entities.IdentityChange, error) {
+	if s.identityChanges == nil {
+		return nil, ErrIdentityChangesUnavailable
+	}
+
+	parsed, err := uuid.Parse(token)
+	if err != nil {
+		return nil, fmt.Errorf("malformed identity change token: %w", entities.ErrIdentityChangeNotFound)
+	}
+
+	change, err := s.identityChanges.GetByToken(ctx, entities.IdentityChangeToken(parsed))
+	if err != nil {
+		return nil, fmt.Errorf("identity change lookup: %w", err)
+	}
+
+	return change, nil
+}
+
+// identityWrite runs writes of an identity change with the repositories it
+// is given.
+type identityWrite func(
+	ctx context.Context,
+	users repositories.UserRepository,
+	changes repositories.IdentityChangeRepository,
+) error
+
+// inIdentityTransaction runs write in a transaction, so that the uniqueness
+// checks hold when the user is saved. Without transactions, or when the
+// transaction has no identity change repository, the service's repositories
+// are used and the user's unique constraints are the last line of defense.
+func (s *UserService) inIdentityTransaction(ctx context.Context, write identityWrite) error {
+	if s.txRepo == nil {
+		return write(ctx, s.userRepo, s.identityChanges)
+	}
+
+	return s.txRepo.RunInTransaction(ctx, func(ctx context.Context, tx repositories.Transaction) error {
+		changes := tx.IdentityChangeRepository()
+		if changes == nil {
+			changes = s.identityChanges
+		}
+
+		return write(ctx, tx.UserRepository(), changes)
+	})
+}
+
+// saveIdentityChange saves user and records change, which is created if it
+// has no ID yet.
+func saveIdentityChange(
+	ctx context.Context,
+	users repositories.UserRepository,
+	changes repositories.IdentityChangeRepository,
+	user *entities.User,
+	change *entities.IdentityChange,
+) error {
+	err := users.Update(ctx, user)
+	if err != nil {
+		return fmt.Errorf("user=%v: failed to update user: %w", user.ID(), err)
+	}
+
+	if change.ID == 0 {
+		err = changes.Create(ctx, change)
+	} else {
+		err = changes.Update(ctx, change)
+	}
+
+	if err != nil {
+		return fmt.Errorf("record %v change user=%v: %w", change.Field, user.ID(), err)
+	}
+
+	return nil
+}
+
+// identityAvailable checks that no user other than userID has value as field.
+func identityAvailable(
+	ctx context.Context,
+	users repositories.UserRepository,
+	field entities.IdentityField,
+	value string,
+	userID entities.UserID,
+) error {
+	var (
+		owner *entities.User
+		err   error
+	)
+
+	switch field {
+	case entities.IdentityFieldEmail:
+		owner, err = users.GetByEmail(ctx, entities.Email(value))
+	case entities.IdentityFieldUsername:
+		owner, err = users.GetByUsername(ctx, entities.Username(value))
+	}
+
+	if entities.IsNotFoundError(err) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("look up %v owner: %w", field, err)
+	}
+
+	if owner.ID() != userID {
+		return fmt.Errorf("%v=%v: %w", field, value, entities.ErrUserAlreadyExists)
+	}
+
+	return nil
+}
+
+// identityValue returns the current value of field for user.
+func identityValue(user *entities.User, field entities.IdentityField) string {
+	if field == entities.IdentityFieldEmail {
+		return user.Email().String()
+	}
+
+	return user.Username().String()
+}
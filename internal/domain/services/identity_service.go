@@ -0,0 +1,406 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"unicode"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// usernameAttempts bounds the suffixed usernames tried for a new federated user.
+const usernameAttempts = 5
+
+// unknownName stands in for names an identity provider did not supply.
+const unknownName = "Unknown"
+
+// FederatedIdentity is what an external identity provider asserts about the
+// account that signed in.
+type FederatedIdentity struct {
+	Provider entities.IdentityProvider
+	// Subject is the provider's stable account identifier.
+	Subject       string
+	Email         string
+	EmailVerified bool
+	// PreferredUsername and the names seed the profile of users created on first login.
+	PreferredUsername string
+	FirstName         string
+	LastName          string
+}
+
+// FederatedLogin is the outcome of signing in through an identity provider.
+type FederatedLogin struct {
+	User     *entities.User
+	Identity *entities.UserIdentity
+	Session  *entities.UserSession
+	// Created is true when the user was created by this login.
+	Created bool
+	// Linked is true when the identity was linked by this login.
+	Linked bool
+}
+
+// IdentityService signs users in through external identity providers and
+// manages the identities linked to their accounts.
+type IdentityService struct {
+	identities repositories.IdentityRepository
+	users      *UserService
+}
+
+// NewIdentityService creates an identity service. Users are created, and
+// sessions started, through users.
+func NewIdentityService(identities repositories.IdentityRepository, users *UserService) *IdentityService {
+	return &IdentityService{
+		identities: identities,
+		users:      users,
+	}
+}
+
+// Login signs in through an external identity and starts a session.
+// Unknown identities are linked to the local user with the same email when the
+// provider verified it, and otherwise create a new user. An unverified email that
+// matches an existing user is rejected so that a provider cannot claim the account.
+func (s *IdentityService) Login(
+	ctx context.Context,
+	identity FederatedIdentity,
+	ipAddress, userAgent string,
+) (*FederatedLogin, error) {
+	err := s.users.checkSwitches(ctx, entities.SwitchLoginDisabled)
+	if err != nil {
+		return nil, err
+	}
+
+	login, err := s.resolve(ctx, identity)
+	if err != nil {
+		return nil, err
+	}
+
+	user := login.User
+	if !user.IsActive() {
+		s.users.publishEvent(ctx, events.UserLoginFailed(user.ID(), ipAddress, userAgent, "inactive_account"))
+		s.users.recordLoginAttempt(
+			ctx, user.ID(), ipAddress, userAgent, identity.Provider.String(), entities.LoginFailureInactiveAccount,
+		)
+
+		if user.Status() == entities.UserStatusSuspended {
+			return nil, fmt.Errorf("provider=%v: %w", identity.Provider, entities.ErrAccountSuspended)
+		}
+
+		return nil, fmt.Errorf("provider=%v: %w", identity.Provider, entities.ErrAccountInactive)
+	}
+
+	err = s.users.checkDeletionPending(ctx, user.ID())
+	if errors.Is(err, entities.ErrAccountDeleting) {
+		s.users.publishEvent(ctx, events.UserLoginFailed(user.ID(), ipAddress, userAgent, "pending_deletion"))
+		s.users.recordLoginAttempt(
+			ctx, user.ID(), ipAddress, userAgent, identity.Provider.String(), entities.LoginFailurePendingDeletion,
+		)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("provider=%v: %w", identity.Provider, err)
+	}
+
+	login.Identity.RecordLogin(identity.Email)
+
+	err = s.identities.RecordLogin(ctx, login.Identity)
+	if err != nil {
+		slog.Warn("failed to record identity login", "provider", identity.Provider, "error", err)
+	}
+
+	login.Session, err = s.users.startSession(ctx, user, ipAddress, userAgent)
+	if err != nil {
+		return nil, fmt.Errorf("session create for provider=%v: %w", identity.Provider, err)
+	}
+
+	s.users.recordLoginAttempt(
+		ctx, user.ID(), ipAddress, userAgent, identity.Provider.String(), entities.LoginFailureNone,
+	)
+
+	s.users.publishEvent(ctx, events.UserFederatedLogin(
+		user.ID(), identity.Provider, identity.Subject, ipAddress, userAgent, login.Linked,
+	))
+
+	return login, nil
+}
+
+// resolve finds the user behind identity, linking or creating it if unknown.
+func (s *IdentityService) resolve(ctx context.Context, identity FederatedIdentity) (*FederatedLogin, error) {
+	linked, err := s.identities.GetByProviderSubject(ctx, identity.Provider, identity.Subject)
+	if err == nil {
+		user, err := s.users.userRepo.GetByID(ctx, linked.UserID)
+		if err != nil {
+			return nil, fmt.Errorf("user of provider=%v: %w", identity.Provider, err)
+		}
+
+		return &FederatedLogin{User: user, Identity: linked}, nil
+	}
+
+	if !errors.Is(err, entities.ErrIdentityNotFound) {
+		return nil, fmt.Errorf("failed to look up identity provider=%v: %w", identity.Provider, err)
+	}
+
+	user, created, err := s.userForIdentity(ctx, identity)
+	if err != nil {
+		return nil, err
+	}
+
+	link, err := s.link(ctx, user.ID(), identity)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FederatedLogin{User: user, Identity: link, Created: created, Linked: true}, nil
+}
+
+// userForIdentity returns the local user with the identity's email, creating it if none exists.
+func (s *IdentityService) userForIdentity(
+	ctx context.Context,
+	identity FederatedIdentity,
+) (*entities.User, bool, error) {
+	email, err := entities.NewEmail(identity.Email)
+	if err != nil {
+		return nil, false, fmt.Errorf("provider=%v: %w", identity.Provider, err)
+	}
+
+	user, err := s.users.userRepo.GetByEmail(ctx, email)
+	if err == nil {
+		if !identity.EmailVerified {
+			return nil, false, fmt.Errorf(
+				"unverified email=%v from provider=%v: %w",
+				email, identity.Provider, entities.ErrUserAlreadyExists,
+			)
+		}
+
+		return user, false, nil
+	}
+
+	if !errors.Is(err, entities.ErrUserNotFound) {
+		return nil, false, fmt.Errorf("failed to look up email=%v: %w", email, err)
+	}
+
+	user, err = s.createUser(ctx, email, identity)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return user, true, nil
+}
+
+// createUser creates a user without a usable password from identity.
+// Users verified by the provider start out verified.
+func (s *IdentityService) createUser(
+	ctx context.Context,
+	email entities.Email,
+	identity FederatedIdentity,
+) (*entities.User, error) {
+	username, err := s.availableUsername(ctx, identity, email)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.users.CreateUser(ctx, &CreateUserRequest{
+		Email:        email.String(),
+		Username:     username,
+		PasswordHash: entities.NewUnusablePasswordHash().String(),
+		FirstName:    profileName(identity.FirstName),
+		LastName:     profileName(identity.LastName),
+		Status:       entities.UserStatusActive.String(),
+		Role:         entities.UserRoleUser.String(),
+		Source:       identity.Provider.String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create user for provider=%v: %w", identity.Provider, err)
+	}
+
+	if !identity.EmailVerified {
+		return user, nil
+	}
+
+	before := entities.SnapshotOf(user)
+	user.Verify()
+
+	err = s.users.userRepo.Update(ctx, user)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify user %s: %w", user.ID(), err)
+	}
+
+	s.users.recordAudit(ctx, entities.AuditActionUserVerify, user.ID(), auditDiff(before, user))
+	s.users.publishEvent(ctx, events.UserVerified(user.ID(), identity.Provider.String()))
+
+	return user, nil
+}
+
+// availableUsername derives an unused username from the identity, appending a
+// random suffix when the preferred one is taken or not allowed by the
+// username policy.
+func (s *IdentityService) availableUsername(
+	ctx context.Context,
+	identity FederatedIdentity,
+	email entities.Email,
+) (string, error) {
+	base := usernameBase(identity.PreferredUsername)
+	if base == "" {
+		local, _, _ := strings.Cut(email.String(), "@")
+		base = usernameBase(local)
+	}
+
+	candidate := base
+	for range usernameAttempts {
+		username, err := entities.NewUsername(candidate)
+		if err == nil && s.users.validator != nil {
+			err = s.users.validator.ValidateUsername(candidate)
+		}
+
+		if err == nil {
+			_, err = s.users.userRepo.GetByUsername(ctx, username)
+			if errors.Is(err, entities.ErrUserNotFound) {
+				return username.String(), nil
+			}
+
+			if err != nil {
+				return "", fmt.Errorf("failed to check username=%v: %w", username, err)
+			}
+		}
+
+		candidate = base + "-" + strings.ToLower(rand.Text()[:6])
+	}
+
+	return "", fmt.Errorf("no free username for provider=%v: %w", identity.Provider, entities.ErrUserAlreadyExists)
+}
+
+// usernameBase keeps the username characters of name, padded to the minimum
+// length and leaving room for a suffix.
+func usernameBase(name string) string {
+	base := strings.Map(func(r rune) rune {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-') {
+			return r
+		}
+
+		return -1
+	}, name)
+
+	if base == "" {
+		return ""
+	}
+
+	for len(base) < 3 {
+		base += "_"
+	}
+
+	return base[:min(len(base), 40)]
+}
+
+// profileName keeps the characters allowed in profile names, falling back to
+// unknownName when nothing usable remains.
+func profileName(name string) string {
+	name = strings.TrimSpace(strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || r == ' ' || r == '-' || r == '\'' {
+			return r
+		}
+
+		return -1
+	}, name))
+
+	if name == "" {
+		return unknownName
+	}
+
+	return name
+}
+
+// Link links identity to userID, for users adding a provider to their account.
+func (s *IdentityService) Link(
+	ctx context.Context,
+	userID entities.UserID,
+	identity FederatedIdentity,
+) (*entities.UserIdentity, error) {
+	_, err := s.users.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("user %s: %w", userID, err)
+	}
+
+	return s.link(ctx, userID, identity)
+}
+
+// link stores a new identity of userID and publishes the link.
+func (s *IdentityService) link(
+	ctx context.Context,
+	userID entities.UserID,
+	identity FederatedIdentity,
+) (*entities.UserIdentity, error) {
+	linked, err := entities.NewUserIdentity(userID, identity.Provider, identity.Subject, identity.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.identities.Create(ctx, linked)
+	if err != nil {
+		return nil, fmt.Errorf("failed to link provider=%v to %s: %w", identity.Provider, userID, err)
+	}
+
+	s.users.publishEvent(ctx, events.IdentityChanged(events.EventIdentityLinked, linked))
+
+	return linked, nil
+}
+
+// Unlink removes the user's identity at provider. Users without a password
+// must keep at least one identity to sign in with.
+func (s *IdentityService) Unlink(
+	ctx context.Context,
+	userID entities.UserID,
+	provider entities.IdentityProvider,
+) error {
+	user, err := s.users.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("user %s: %w", userID, err)
+	}
+
+	linked, err := s.identities.ListByUser(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to list identities of %s: %w", userID, err)
+	}
+
+	var target *entities.UserIdentity
+
+	for _, identity := range linked {
+		if identity.Provider == provider {
+			target = identity
+		}
+	}
+
+	if target == nil {
+		return fmt.Errorf("provider=%v user=%v: %w", provider, userID, entities.ErrIdentityNotFound)
+	}
+
+	if len(linked) == 1 && !user.PasswordHash().IsUsable() {
+		return fmt.Errorf("provider=%v user=%v: %w", provider, userID, entities.ErrLastLoginMethod)
+	}
+
+	err = s.identities.Delete(ctx, userID, provider)
+	if err != nil {
+		return fmt.Errorf("failed to unlink provider=%v from %s: %w", provider, userID, err)
+	}
+
+	s.users.publishEvent(ctx, events.IdentityChanged(events.EventIdentityUnlinked, target))
+
+	return nil
+}
+
+// ListIdentities returns the identities linked to userID.
+func (s *IdentityService) ListIdentities(
+	ctx context.Context,
+	userID entities.UserID,
+) ([]*entities.UserIdentity, error) {
+	linked, err := s.identities.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list identities of %s: %w", userID, err)
+	}
+
+	return linked, nil
+}
@@ -0,0 +1,154 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+)
+
+// WithImpersonationLifetime overrides how long impersonation sessions last.
+// Non-positive lifetimes are ignored in favor of the default.
+func WithImpersonationLifetime(lifetime time.Duration) UserServiceOption {
+	return func(s *UserService) {
+		if lifetime > 0 {
+			s.impersonation = lifetime
+		}
+	}
+}
+
+// Impersonate starts a session in which the admin adminID acts as targetID,
+// for support staff to see what the user sees. The session records adminID
+// as its impersonator, lasts the impersonation lifetime without renewal, and
+// is audited and announced with user.impersonation.started. Admins cannot
+// impersonate themselves or other admins.
+func (s *UserService) Impersonate(
+	ctx context.Context,
+	adminID, targetID entities.UserID,
+) (_ *entities.UserSession, err error) {
+	ctx, end := s.startSpan(ctx, "Impersonate")
+	defer end(&err)
+
+	err = s.checkSwitches(ctx, entities.SwitchLoginDisabled)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.authorizeImpersonation(ctx, adminID, targetID)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = withFallbackActor(ctx, adminID)
+	actor, _ := AuditActorFromContext(ctx)
+
+	session := entities.NewImpersonationSession(adminID, targetID, net.ParseIP(actor.IPAddress), "", s.impersonation)
+
+	err = s.sessionRepo.Create(ctx, session)
+	if err != nil {
+		return nil, fmt.Errorf("impersonation session user=%v by=%v: %w", targetID, adminID, err)
+	}
+
+	s.recordAudit(ctx, entities.AuditActionImpersonationStart, targetID, []entities.FieldChange{
+		{Field: "session", New: session.ID().String()},
+	})
+	s.publishEvent(ctx, events.ImpersonationStarted(session))
+
+	return session, nil
+}
+
+// authorizeImpersonation checks that adminID is an active admin, is the
+// actor of ctx if it has one, and may impersonate targetID.
+func (s *UserService) authorizeImpersonation(ctx context.Context, adminID, targetID entities.UserID) error {
+	if actor, ok := AuditActorFromContext(ctx); ok && actor.UserID != adminID {
+		return fmt.Errorf("actor=%v impersonating as=%v: %w", actor.UserID, adminID, entities.ErrInsufficientPrivileges)
+	}
+
+	if adminID == targetID {
+		return fmt.Errorf("user=%v: %w", adminID, entities.ErrImpersonationForbidden)
+	}
+
+	admin, err := s.userRepo.GetByID(ctx, adminID)
+	if err != nil {
+		return fmt.Errorf("impersonator id=%v: %w", adminID, err)
+	}
+
+	if !admin.IsActive() || admin.Role() != entities.UserRoleAdmin {
+		return fmt.Errorf("impersonator id=%v: %w", adminID, entities.ErrInsufficientPrivileges)
+	}
+
+	target, err := s.userRepo.GetByID(ctx, targetID)
+	if err != nil {
+		return fmt.Errorf("impersonated id=%v: %w", targetID, err)
+	}
+
+	if target.Role() == entities.UserRoleAdmin {
+		return fmt.Errorf("user=%v: %w", targetID, entities.ErrImpersonationForbidden)
+	}
+
+	if !target.IsActive() {
+		return fmt.Errorf("impersonated id=%v: %w", targetID, entities.ErrAccountInactive)
+	}
+
+	return nil
+}
+
+// EndImpersonation ends the impersonation session of token. Logout ends
+// impersonations the same way; expired ones end without an event.
+func (s *UserService) EndImpersonation(ctx context.Context, token string) (err error) {
+	ctx, end := s.startSpan(ctx, "EndImpersonation")
+	defer end(&err)
+
+	claims, err := s.parseSessionToken(token)
+	if err != nil {
+		return err
+	}
+
+	session, err := s.sessionRepo.GetByToken(ctx, claims.SessionToken)
+	if err != nil {
+		return fmt.Errorf("token=%v: %w", claims.SessionToken, entities.ErrSessionNotFound)
+	}
+
+	return s.endImpersonation(ctx, session)
+}
+
+// endImpersonation deactivates an impersonation session, audits it as an
+// operation of the impersonator and publishes user.impersonation.ended.
+func (s *UserService) endImpersonation(ctx context.Context, session *entities.UserSession) error {
+	impersonatorID, ok := session.Impersonator()
+	if !ok {
+		return fmt.Errorf("session=%v: %w", session.ID(), entities.ErrNotImpersonating)
+	}
+
+	if !session.IsActive() {
+		return nil
+	}
+
+	session.Deactivate()
+
+	err := s.sessionRepo.Update(ctx, session)
+	if err != nil {
+		return fmt.Errorf("end impersonation session=%v: %w", session.ID(), err)
+	}
+
+	ctx = withFallbackActor(ctx, impersonatorID)
+	s.recordAudit(ctx, entities.AuditActionImpersonationEnd, session.UserID(), []entities.FieldChange{
+		{Field: "session", Old: session.ID().String()},
+	})
+	s.publishEvent(ctx, events.ImpersonationEnded(session))
+
+	return nil
+}
+
+// policyFor returns the expiration policy of session. Impersonation
+// sessions are never renewed past the impersonation lifetime.
+func (s *UserService) policyFor(session *entities.UserSession) entities.SessionPolicy {
+	if session.IsImpersonation() {
+		return entities.ImpersonationPolicy(s.impersonation)
+	}
+
+	return s.sessions
+}
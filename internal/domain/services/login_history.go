@@ -0,0 +1,95 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// ErrLoginHistoryUnavailable is returned when no login history repository is configured.
+var ErrLoginHistoryUnavailable = errors.New("login history is not available")
+
+// WithLoginHistory records the successful and failed login attempts in repo.
+func WithLoginHistory(repo repositories.LoginHistoryRepository) UserServiceOption {
+	return func(s *UserService) {
+		s.loginHistory = repo
+	}
+}
+
+// recordLoginAttempt records a login attempt. userID is zero if no account
+// matched. Failures are logged only, so that logins survive an outage of
+// the login history.
+func (s *UserService) recordLoginAttempt(
+	ctx context.Context,
+	userID entities.UserID,
+	ipAddress, userAgent, method string,
+	failure entities.LoginFailure,
+) {
+	if s.loginHistory == nil {
+		return
+	}
+
+	attempt := entities.NewLoginAttempt(userID, ipAddress, userAgent, method, failure)
+
+	err := s.loginHistory.Record(ctx, attempt)
+	if err != nil {
+		slog.Warn("failed to record login attempt", "user_id", userID, "failure", failure, "error", err)
+	}
+}
+
+// RecentLogins returns the last limit login attempts of a user, successful
+// or not, newest first.
+func (s *UserService) RecentLogins(
+	ctx context.Context,
+	userID entities.UserID,
+	limit int,
+) (_ []*entities.LoginAttempt, err error) {
+	ctx, end := s.startSpan(ctx, "RecentLogins")
+	defer end(&err)
+
+	if s.loginHistory == nil {
+		return nil, ErrLoginHistoryUnavailable
+	}
+
+	attempts, err := s.loginHistory.ListByUser(ctx, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list logins user=%v: %w", userID, err)
+	}
+
+	return attempts, nil
+}
+
+// LoginFailuresByIP returns the last limit failed login attempts from
+// ipAddress within window, newest first. Failures of unknown accounts are
+// included.
+func (s *UserService) LoginFailuresByIP(
+	ctx context.Context,
+	ipAddress string,
+	window time.Duration,
+	limit int,
+) (_ []*entities.LoginAttempt, err error) {
+	ctx, end := s.startSpan(ctx, "LoginFailuresByIP")
+	defer end(&err)
+
+	if s.loginHistory == nil {
+		return nil, ErrLoginHistoryUnavailable
+	}
+
+	if window <= 0 {
+		return nil, entities.NewValidationError("window", "must be positive")
+	}
+
+	ipAddress = entities.CanonicalIPAddress(ipAddress)
+
+	attempts, err := s.loginHistory.ListFailuresByIP(ctx, ipAddress, time.Now().Add(-window), limit)
+	if err != nil {
+		return nil, fmt.Errorf("list login failures ip=%v: %w", ipAddress, err)
+	}
+
+	return attempts, nil
+}
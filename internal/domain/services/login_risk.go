@@ -0,0 +1,89 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+)
+
+// GeoResolver resolves the coarse location of an IP address.
+// *geoip.Resolver implements it with a MaxMind database.
+type GeoResolver interface {
+	// Locate returns the location of ip, or a zero location if it is unknown.
+	Locate(ctx context.Context, ip net.IP) (entities.GeoLocation, error)
+}
+
+// StepUpHook is called for suspicious logins before their session is
+// created. Returning an error, typically wrapping entities.ErrStepUpRequired,
+// denies the login until the user verified it some other way.
+type StepUpHook func(ctx context.Context, user *entities.User, risk entities.LoginRisk) error
+
+// WithLoginRiskDetection compares logins with the earlier sessions of their
+// user and publishes a user.login.suspicious event for new countries and
+// devices. Sessions are located with geo; without one only devices are
+// compared.
+func WithLoginRiskDetection(geo GeoResolver) UserServiceOption {
+	return func(s *UserService) {
+		s.loginRisk = true
+		s.geo = geo
+	}
+}
+
+// WithStepUpHook calls hook for suspicious logins. It requires
+// WithLoginRiskDetection.
+func WithStepUpHook(hook StepUpHook) UserServiceOption {
+	return func(s *UserService) {
+		s.stepUp = hook
+	}
+}
+
+// assessLogin locates a new session and flags it if it is suspicious. Only
+// the step-up hook can fail the login; failing lookups are logged and skipped.
+func (s *UserService) assessLogin(
+	ctx context.Context,
+	user *entities.User,
+	session *entities.UserSession,
+	ipAddress string,
+) error {
+	if !s.loginRisk {
+		return nil
+	}
+
+	if s.geo != nil && session.IPAddress() != nil {
+		location, err := s.geo.Locate(ctx, session.IPAddress())
+		if err != nil {
+			slog.Warn("geo lookup failed", "error", err)
+		}
+
+		session.SetLocation(location)
+	}
+
+	previous, err := s.sessionRepo.GetByUserID(ctx, user.ID(), false)
+	if err != nil {
+		slog.Warn("failed to load sessions for login risk", "error", err)
+
+		return nil
+	}
+
+	risk := entities.AssessLoginRisk(session, previous)
+	if !risk.IsSuspicious() {
+		return nil
+	}
+
+	s.publishEvent(ctx, events.UserLoginSuspicious(user.ID(), ipAddress, session.UserAgent(), risk))
+
+	if s.stepUp == nil {
+		return nil
+	}
+
+	err = s.stepUp(ctx, user, risk)
+	if err != nil {
+		return fmt.Errorf("step-up user=%v: %w", user.ID(), err)
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+)
+
+// LoginThrottle limits login attempts. *ratelimit.LoginThrottle implements it.
+type LoginThrottle interface {
+	// AllowLogin counts an attempt to sign in to account from ipAddress and
+	// returns how long to wait before the next one, or zero to proceed.
+	AllowLogin(ctx context.Context, ipAddress, account string) (time.Duration, error)
+}
+
+// WithLoginThrottle throttles AuthenticateUser. Throttled attempts fail with
+// a RateLimitError before the credentials are checked.
+func WithLoginThrottle(throttle LoginThrottle) UserServiceOption {
+	return func(s *UserService) {
+		s.loginThrottle = throttle
+	}
+}
+
+// throttleLogin returns a RateLimitError if the attempt must wait. Attempts
+// are allowed if the throttle fails, so that logins survive its outage.
+func (s *UserService) throttleLogin(ctx context.Context, email, ipAddress, userAgent string) error {
+	if s.loginThrottle == nil {
+		return nil
+	}
+
+	wait, err := s.loginThrottle.AllowLogin(ctx, ipAddress, email)
+	if err != nil {
+		slog.Warn("login throttle failed, allowing attempt", "error", err)
+
+		return nil
+	}
+
+	if wait <= 0 {
+		return nil
+	}
+
+	event := events.UserLoginFailed(entities.UserID(0), ipAddress, userAgent, "rate_limited")
+	event.TraceParent = s.tracer.TraceParent(ctx)
+	_ = s.eventPub.Publish(event)
+
+	s.recordLoginAttempt(ctx, 0, ipAddress, userAgent, entities.LoginMethodPassword, entities.LoginFailureRateLimited)
+
+	return entities.NewRateLimitError("too many login attempts", wait)
+}
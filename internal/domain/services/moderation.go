@@ -0,0 +1,571 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// ErrModerationUnavailable is returned when no moderation repository is configured.
+var ErrModerationUnavailable = errors.New("moderation is not available")
+
+// WithModeration lets moderators flag and suspend users, recording the
+// actions and the appeals against suspensions in repo.
+func WithModeration(repo repositories.ModerationRepository) UserServiceOption {
+	return func(s *UserService) {
+		s.moderation = repo
+	}
+}
+
+// FlagUser flags a user for review by other moderators for reason. Flags
+// do not restrict the user. The moderator is the actor of ctx, who must be
+// an active moderator or admin and outrank the user.
+func (s *UserService) FlagUser(
+	ctx context.Context,
+	userID entities.UserID,
+	reason string,
+) (_ *entities.ModerationAction, err error) {
+	ctx, end := s.startSpan(ctx, "FlagUser")
+	defer end(&err)
+
+	err = s.checkSwitches(ctx, entities.SwitchReadOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.moderation == nil {
+		return nil, ErrModerationUnavailable
+	}
+
+	moderator, err := s.authorizeModerator(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	action, err := entities.NewFlag(userID, moderator.ID(), reason)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("user %s not found: %w", userID, err)
+	}
+
+	err = authorizeModerationOf(moderator, user)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.moderation.CreateAction(ctx, action)
+	if err != nil {
+		return nil, err
+	}
+
+	s.recordAudit(ctx, entities.AuditActionUserFlag, userID, moderationReason(action))
+	s.publishEvent(ctx, events.UserFlagged(action))
+
+	return action, nil
+}
+
+// SuspendUser suspends a user for reason and signs out their sessions. The
+// suspension lapses after duration, or lasts until it is lifted if duration
+// is zero. Users with a suspension in effect are rejected with
+// ErrAlreadySuspended. The moderator is the actor of ctx, who must be an
+// active moderator or admin and outrank the user.
+func (s *UserService) SuspendUser(
+	ctx context.Context,
+	userID entities.UserID,
+	reason string,
+	duration time.Duration,
+) (_ *entities.ModerationAction, err error) {
+	ctx, end := s.startSpan(ctx, "SuspendUser")
+	defer end(&err)
+
+	err = s.checkSwitches(ctx, entities.SwitchReadOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.moderation == nil {
+		return nil, ErrModerationUnavailable
+	}
+
+	moderator, err := s.authorizeModerator(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	action, err := entities.NewSuspension(userID, moderator.ID(), reason, duration)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		user   *entities.User
+		before *entities.UserSnapshot
+	)
+
+	err = s.inModerationTransaction(ctx, func(
+		ctx context.Context,
+		users repositories.UserRepository,
+		moderation repositories.ModerationRepository,
+	) error {
+		user, err = users.GetByID(ctx, userID)
+		if err != nil {
+			return fmt.Errorf("user %s not found: %w", userID, err)
+		}
+
+		err = authorizeModerationOf(moderator, user)
+		if err != nil {
+			return err
+		}
+
+		_, err = moderation.GetActiveSuspension(ctx, userID)
+
+		switch {
+		case err == nil:
+			return fmt.Errorf("user=%v: %w", userID, entities.ErrAlreadySuspended)
+		case !errors.Is(err, entities.ErrNotSuspended):
+			return err
+		}
+
+		before = entities.SnapshotOf(user)
+
+		err = user.ChangeStatus(entities.UserStatusSuspended)
+		if err != nil {
+			return fmt.Errorf("failed to suspend user %s: %w", userID, err)
+		}
+
+		err = users.Update(ctx, user)
+		if err != nil {
+			return fmt.Errorf("failed to save suspended user %s: %w", userID, err)
+		}
+
+		return moderation.CreateAction(ctx, action)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.sessionRepo.DeactivateByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("sign out suspended user=%v: %w", userID, err)
+	}
+
+	s.recordAudit(ctx, entities.AuditActionUserSuspend, userID,
+		append(auditDiff(before, user), moderationReason(action)...))
+	s.publishEvent(ctx, events.SuspensionImposed(action))
+
+	return action, nil
+}
+
+// LiftSuspension lifts the suspension in effect for a user and reactivates
+// them. Users without one are rejected with ErrNotSuspended. The moderator
+// is the actor of ctx, who must be an active moderator or admin.
+func (s *UserService) LiftSuspension(
+	ctx context.Context,
+	userID entities.UserID,
+) (_ *entities.ModerationAction, err error) {
+	ctx, end := s.startSpan(ctx, "LiftSuspension")
+	defer end(&err)
+
+	err = s.checkSwitches(ctx, entities.SwitchReadOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.moderation == nil {
+		return nil, ErrModerationUnavailable
+	}
+
+	moderator, err := s.authorizeModerator(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var reinstated *reinstatement
+
+	err = s.inModerationTransaction(ctx, func(
+		ctx context.Context,
+		users repositories.UserRepository,
+		moderation repositories.ModerationRepository,
+	) error {
+		action, err := moderation.GetActiveSuspension(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		reinstated, err = reinstate(ctx, users, moderation, action, moderator.ID())
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.recordReinstatement(ctx, reinstated)
+
+	return reinstated.action, nil
+}
+
+// ReactivateLapsedSuspensions lifts up to limit suspensions that ended and
+// reactivates their users, and returns how many it lifted. Suspensions
+// lifted meanwhile are skipped; the other failures are joined.
+func (s *UserService) ReactivateLapsedSuspensions(ctx context.Context, limit int) (_ int, err error) {
+	ctx, end := s.startSpan(ctx, "ReactivateLapsedSuspensions")
+	defer end(&err)
+
+	err = s.checkSwitches(ctx, entities.SwitchReadOnly)
+	if err != nil {
+		return 0, err
+	}
+
+	if s.moderation == nil {
+		return 0, ErrModerationUnavailable
+	}
+
+	lapsed, err := s.moderation.ListLapsedSuspensions(ctx, time.Now(), limit)
+	if err != nil {
+		return 0, fmt.Errorf("list lapsed suspensions: %w", err)
+	}
+
+	var (
+		lifted int
+		errs   []error
+	)
+
+	for _, action := range lapsed {
+		var reinstated *reinstatement
+
+		err = s.inModerationTransaction(ctx, func(
+			ctx context.Context,
+			users repositories.UserRepository,
+			moderation repositories.ModerationRepository,
+		) error {
+			reinstated, err = reinstate(ctx, users, moderation, action, 0)
+
+			return err
+		})
+
+		switch {
+		case errors.Is(err, entities.ErrModerationActionNotFound), errors.Is(err, entities.ErrModerationActionLifted):
+			continue
+		case err != nil:
+			errs = append(errs, fmt.Errorf("lift lapsed suspension id=%d: %w", action.ID, err))
+
+			continue
+		}
+
+		lifted++
+
+		s.recordReinstatement(ctx, reinstated)
+	}
+
+	return lifted, errors.Join(errs...)
+}
+
+// AppealSuspension appeals the suspension in effect for a user with their
+// statement. Each suspension can be appealed once, and only by the user
+// themselves as the actor of ctx.
+func (s *UserService) AppealSuspension(
+	ctx context.Context,
+	userID entities.UserID,
+	statement string,
+) (_ *entities.Appeal, err error) {
+	ctx, end := s.startSpan(ctx, "AppealSuspension")
+	defer end(&err)
+
+	err = s.checkSwitches(ctx, entities.SwitchReadOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.moderation == nil {
+		return nil, ErrModerationUnavailable
+	}
+
+	if actor, ok := AuditActorFromContext(ctx); !ok || actor.UserID != userID {
+		return nil, fmt.Errorf("actor=%v appealing for user=%v: %w",
+			actor.UserID, userID, entities.ErrInsufficientPrivileges)
+	}
+
+	action, err := s.moderation.GetActiveSuspension(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	appeal, err := entities.NewAppeal(action, statement)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.moderation.CreateAppeal(ctx, appeal)
+	if err != nil {
+		return nil, err
+	}
+
+	s.publishEvent(ctx, events.AppealSubmitted(appeal))
+
+	return appeal, nil
+}
+
+// DecideAppeal grants or denies a pending appeal with a response to the
+// user. Granting lifts the appealed suspension unless it lapsed meanwhile.
+// The reviewer is the actor of ctx, who must be an active moderator or admin.
+func (s *UserService) DecideAppeal(
+	ctx context.Context,
+	appealID entities.AppealID,
+	grant bool,
+	response string,
+) (_ *entities.Appeal, err error) {
+	ctx, end := s.startSpan(ctx, "DecideAppeal")
+	defer end(&err)
+
+	err = s.checkSwitches(ctx, entities.SwitchReadOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.moderation == nil {
+		return nil, ErrModerationUnavailable
+	}
+
+	reviewer, err := s.authorizeModerator(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	appeal, err := s.moderation.GetAppeal(ctx, appealID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = appeal.Decide(reviewer.ID(), grant, response)
+	if err != nil {
+		return nil, fmt.Errorf("appeal id=%d: %w", appealID, err)
+	}
+
+	var reinstated *reinstatement
+
+	err = s.inModerationTransaction(ctx, func(
+		ctx context.Context,
+		users repositories.UserRepository,
+		moderation repositories.ModerationRepository,
+	) error {
+		err := moderation.DecideAppeal(ctx, appeal)
+		if err != nil {
+			return err
+		}
+
+		if !grant {
+			return nil
+		}
+
+		action, err := moderation.GetAction(ctx, appeal.ActionID)
+		if err != nil {
+			return err
+		}
+
+		if !action.IsActive() {
+			return nil
+		}
+
+		reinstated, err = reinstate(ctx, users, moderation, action, reviewer.ID())
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.recordAudit(ctx, entities.AuditActionAppealDecide, appeal.UserID, []entities.FieldChange{
+		{Field: "appeal", Old: entities.AppealPending.String(), New: appeal.Status.String()},
+	})
+	s.publishEvent(ctx, events.AppealDecided(appeal))
+
+	if reinstated != nil {
+		s.recordReinstatement(ctx, reinstated)
+	}
+
+	return appeal, nil
+}
+
+// ModerationHistory returns the moderation actions against a user, newest first.
+func (s *UserService) ModerationHistory(
+	ctx context.Context,
+	userID entities.UserID,
+) (_ []*entities.ModerationAction, err error) {
+	ctx, end := s.startSpan(ctx, "ModerationHistory")
+	defer end(&err)
+
+	if s.moderation == nil {
+		return nil, ErrModerationUnavailable
+	}
+
+	actions, err := s.moderation.ListActionsByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("moderation history user=%v: %w", userID, err)
+	}
+
+	return actions, nil
+}
+
+// PendingAppeals returns up to limit undecided appeals, oldest first.
+func (s *UserService) PendingAppeals(ctx context.Context, limit int) (_ []*entities.Appeal, err error) {
+	ctx, end := s.startSpan(ctx, "PendingAppeals")
+	defer end(&err)
+
+	if s.moderation == nil {
+		return nil, ErrModerationUnavailable
+	}
+
+	if limit <= 0 {
+		return nil, entities.NewValidationError("limit", "must be positive")
+	}
+
+	appeals, err := s.moderation.ListPendingAppeals(ctx, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list pending appeals: %w", err)
+	}
+
+	return appeals, nil
+}
+
+// authorizeModerator returns the actor of ctx if they are an active user
+// whose role may moderate others. Requests without an actor are rejected
+// like those of users without the role.
+func (s *UserService) authorizeModerator(ctx context.Context) (*entities.User, error) {
+	actor, ok := AuditActorFromContext(ctx)
+	if !ok || actor.UserID == 0 {
+		return nil, fmt.Errorf("moderation without an actor: %w", entities.ErrInsufficientPrivileges)
+	}
+
+	moderator, err := s.userRepo.GetByID(ctx, actor.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("moderator id=%v: %w", actor.UserID, err)
+	}
+
+	if !moderator.IsActive() || !moderator.Role().Grants(entities.PermissionUsersWrite) {
+		return nil, fmt.Errorf("moderator id=%v: %w", actor.UserID, entities.ErrInsufficientPrivileges)
+	}
+
+	return moderator, nil
+}
+
+// authorizeModerationOf checks that moderator may flag or suspend user:
+// moderators cannot act on themselves or on users of their own role or
+// above.
+func authorizeModerationOf(moderator, user *entities.User) error {
+	if moderator.ID() == user.ID() || !moderator.Role().Outranks(user.Role()) {
+		return fmt.Errorf("moderator=%v user=%v: %w", moderator.ID(), user.ID(), entities.ErrModerationForbidden)
+	}
+
+	return nil
+}
+
+// moderationWrite runs writes of a moderation action with the repositories
+// it is given.
+type moderationWrite func(
+	ctx context.Context,
+	users repositories.UserRepository,
+	moderation repositories.ModerationRepository,
+) error
+
+// inModerationTransaction runs write in a transaction, so that the status
+// of the user changes together with the action. Without transactions, or
+// when the transaction has no moderation repository, the service's
+// repositories are used.
+func (s *UserService) inModerationTransaction(ctx context.Context, write moderationWrite) error {
+	if s.txRepo == nil {
+		return write(ctx, s.userRepo, s.moderation)
+	}
+
+	return s.txRepo.RunInTransaction(ctx, func(ctx context.Context, tx repositories.Transaction) error {
+		moderation := tx.ModerationRepository()
+		if moderation == nil {
+			moderation = s.moderation
+		}
+
+		return write(ctx, tx.UserRepository(), moderation)
+	})
+}
+
+// reinstatement is a lifted suspension and the user it applied to, before
+// and after they were reactivated. The user is nil if they were deleted.
+type reinstatement struct {
+	action *entities.ModerationAction
+	user   *entities.User
+	before *entities.UserSnapshot
+}
+
+// reinstate lifts the suspension action on behalf of liftedBy and
+// reactivates its user, unless their status was changed meanwhile.
+func reinstate(
+	ctx context.Context,
+	users repositories.UserRepository,
+	moderation repositories.ModerationRepository,
+	action *entities.ModerationAction,
+	liftedBy entities.UserID,
+) (*reinstatement, error) {
+	err := action.Lift(liftedBy)
+	if err != nil {
+		return nil, fmt.Errorf("moderation action id=%d: %w", action.ID, err)
+	}
+
+	err = moderation.LiftAction(ctx, action)
+	if err != nil {
+		return nil, err
+	}
+
+	reinstated := &reinstatement{action: action}
+
+	user, err := users.GetByID(ctx, action.UserID)
+	if entities.IsNotFoundError(err) {
+		return reinstated, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("user %s not found: %w", action.UserID, err)
+	}
+
+	reinstated.user = user
+	reinstated.before = entities.SnapshotOf(user)
+
+	if user.Status() != entities.UserStatusSuspended {
+		return reinstated, nil
+	}
+
+	err = user.ChangeStatus(entities.UserStatusActive)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reinstate user %s: %w", action.UserID, err)
+	}
+
+	err = users.Update(ctx, user)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save reinstated user %s: %w", action.UserID, err)
+	}
+
+	return reinstated, nil
+}
+
+// recordReinstatement audits and publishes a lifted suspension.
+func (s *UserService) recordReinstatement(ctx context.Context, reinstated *reinstatement) {
+	changes := []entities.FieldChange{}
+	if reinstated.user != nil {
+		changes = auditDiff(reinstated.before, reinstated.user)
+	}
+
+	s.recordAudit(ctx, entities.AuditActionUserReinstate, reinstated.action.UserID, changes)
+	s.publishEvent(ctx, events.UserReinstated(reinstated.action))
+}
+
+// moderationReason returns the reason of action as an audited change.
+func moderationReason(action *entities.ModerationAction) []entities.FieldChange {
+	return []entities.FieldChange{{Field: "reason", New: action.Reason}}
+}
@@ -0,0 +1,228 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// Notification is what a channel delivers to one user about one event.
+type Notification struct {
+	// ID is the ID of the event, so receivers can drop redeliveries.
+	ID    entities.IDID
+	Type  events.EventType
+	Topic entities.NotificationTopic
+	// User is the recipient as stored when the event was handled.
+	User *entities.User
+	// Target is the target of the user's preference, the URL for webhooks.
+	Target     string
+	Summary    string
+	OccurredAt time.Time
+}
+
+// NotificationSender delivers notifications on one channel.
+type NotificationSender interface {
+	Notify(ctx context.Context, notification Notification) error
+}
+
+// notificationKind is how users are notified about an event type.
+type notificationKind struct {
+	topic   entities.NotificationTopic
+	summary string
+}
+
+// notificationKinds returns the event types users are notified about.
+func notificationKinds() map[events.EventType]notificationKind {
+	security := entities.NotificationTopicSecurity
+	account := entities.NotificationTopicAccount
+	organization := entities.NotificationTopicOrganization
+
+	return map[events.EventType]notificationKind{
+		events.EventUserLoginFail:          {security, "A sign-in to your account failed."},
+		events.EventUserLoginSuspicious:    {security, "A sign-in from a new country or device was detected."},
+		events.EventPasswordChanged:        {security, "Your password was changed."},
+		events.EventPasswordReset:          {security, "Your password was reset."},
+		events.EventRefreshTokenReused:     {security, "A revoked session token was used; your sessions were signed out."},
+		events.EventImpersonationStarted:   {security, "An administrator signed in as you to provide support."},
+		events.EventEmailChanged:           {security, "Your email address was changed."},
+		events.EventUsernameChanged:        {security, "Your username was changed."},
+		events.EventIdentityChangeReverted: {security, "A change of your email address or username was reverted."},
+		events.EventIdentityLinked:         {security, "An external account was linked to your account."},
+		events.EventIdentityUnlinked:       {security, "An external account was unlinked from your account."},
+		events.EventUserVerified:           {account, "Your email address was verified."},
+		events.EventRoleChanged:            {account, "Your role was changed."},
+		events.EventUserDataExported:       {account, "A copy of your data was exported."},
+		events.EventUserReinstated:         {account, "Your account suspension was lifted."},
+		events.EventAppealDecided:          {account, "Your appeal of a suspension was decided."},
+		events.EventDeletionRequested:      {account, "Your account is scheduled for deletion."},
+		events.EventDeletionCanceled:       {account, "The deletion of your account was canceled."},
+		events.EventMemberInvited:          {organization, "You were invited to an organization."},
+		events.EventMemberJoined:           {organization, "You joined an organization."},
+		events.EventMemberLeft:             {organization, "You left an organization."},
+		events.EventMemberRoleChanged:      {organization, "Your role in an organization was changed."},
+	}
+}
+
+// NotificationService manages the notification preferences of users and is
+// an events.EventHandler that notifies users about events on the channels
+// they opted in to. Like the other handlers it reloads the recipient rather
+// than reading the event payload, whose personal data may be redacted.
+type NotificationService struct {
+	prefs    repositories.NotificationPreferenceRepository
+	users    repositories.UserRepository
+	channels map[entities.NotificationChannel]NotificationSender
+}
+
+var _ events.EventHandler = (*NotificationService)(nil)
+
+// NotificationServiceOption configures a NotificationService.
+type NotificationServiceOption func(*NotificationService)
+
+// WithNotificationChannel delivers the notifications of channel with sender.
+// Channels without a sender are skipped.
+func WithNotificationChannel(
+	channel entities.NotificationChannel,
+	sender NotificationSender,
+) NotificationServiceOption {
+	return func(s *NotificationService) {
+		s.channels[channel] = sender
+	}
+}
+
+// NewNotificationService creates a notification service storing preferences
+// in prefs and notifying the users of users.
+func NewNotificationService(
+	prefs repositories.NotificationPreferenceRepository,
+	users repositories.UserRepository,
+	opts ...NotificationServiceOption,
+) *NotificationService {
+	s := &NotificationService{
+		prefs:    prefs,
+		users:    users,
+		channels: make(map[entities.NotificationChannel]NotificationSender),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Preferences returns the notification opt-ins of a user.
+func (s *NotificationService) Preferences(
+	ctx context.Context,
+	userID entities.UserID,
+) (*entities.NotificationPreferences, error) {
+	stored, err := s.prefs.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("load notification preferences user=%v: %w", userID, err)
+	}
+
+	return entities.NewNotificationPreferences(userID, stored), nil
+}
+
+// SetPreference opts a user in to or out of topic on channel. Webhooks
+// are posted to target.
+func (s *NotificationService) SetPreference(
+	ctx context.Context,
+	userID entities.UserID,
+	channel entities.NotificationChannel,
+	topic entities.NotificationTopic,
+	enabled bool,
+	target string,
+) (*entities.NotificationPreference, error) {
+	pref, err := entities.NewNotificationPreference(userID, channel, topic, enabled, target)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.prefs.Upsert(ctx, pref)
+	if err != nil {
+		return nil, fmt.Errorf("store notification preference: %w", err)
+	}
+
+	return pref, nil
+}
+
+// ResetPreference restores the default of topic on channel for a user.
+func (s *NotificationService) ResetPreference(
+	ctx context.Context,
+	userID entities.UserID,
+	channel entities.NotificationChannel,
+	topic entities.NotificationTopic,
+) error {
+	err := s.prefs.Delete(ctx, userID, channel, topic)
+	if err != nil {
+		return fmt.Errorf("reset notification preference: %w", err)
+	}
+
+	return nil
+}
+
+// EventTypes returns the event types users are notified about, to
+// subscribe the service with.
+func (s *NotificationService) EventTypes() []events.EventType {
+	return slices.Sorted(maps.Keys(notificationKinds()))
+}
+
+// Handle notifies the user event is about on every channel with a sender
+// that the user opted in to for the event's topic. A failing channel does
+// not keep the others from being notified; their errors are joined. Events
+// of other types, and events about users that were deleted, are ignored.
+func (s *NotificationService) Handle(ctx context.Context, event *events.UserEvent) error {
+	kind, ok := notificationKinds()[event.Type]
+	if !ok {
+		return nil
+	}
+
+	user, err := s.users.GetByID(ctx, event.UserID)
+	if entities.IsNotFoundError(err) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("load notification recipient id=%v: %w", event.UserID, err)
+	}
+
+	prefs, err := s.Preferences(ctx, user.ID())
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+
+	for _, channel := range entities.NotificationChannels() {
+		sender, ok := s.channels[channel]
+		if !ok {
+			continue
+		}
+
+		pref := prefs.Lookup(channel, kind.topic)
+		if !pref.Enabled {
+			continue
+		}
+
+		err = sender.Notify(ctx, Notification{
+			ID:         event.ID,
+			Type:       event.Type,
+			Topic:      kind.topic,
+			User:       user,
+			Target:     pref.Target,
+			Summary:    kind.summary,
+			OccurredAt: event.Timestamp,
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("notify channel=%v event=%v: %w", channel, event.ID, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
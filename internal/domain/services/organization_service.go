@@ -0,0 +1,354 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// Memberships resolve organization-scoped roles for PermissionChecker and SessionService.
+var _ OrganizationRoleResolver = (repositories.MembershipRepository)(nil)
+
+// OrganizationService manages organizations and their memberships.
+// Organizations and memberships are separate aggregates; operations that
+// touch both run in one transaction when transactions are configured.
+type OrganizationService struct {
+	orgRepo    repositories.OrganizationRepository
+	memberRepo repositories.MembershipRepository
+	userRepo   repositories.UserRepository
+	eventPub   events.EventPublisher
+	txRepo     repositories.TransactionalRepository
+}
+
+// OrganizationServiceOption configures optional OrganizationService collaborators.
+type OrganizationServiceOption func(*OrganizationService)
+
+// WithOrganizationTransactions creates organizations and their founding
+// membership atomically.
+func WithOrganizationTransactions(txRepo repositories.TransactionalRepository) OrganizationServiceOption {
+	return func(s *OrganizationService) {
+		s.txRepo = txRepo
+	}
+}
+
+// NewOrganizationService creates a new organization service.
+func NewOrganizationService(
+	orgRepo repositories.OrganizationRepository,
+	memberRepo repositories.MembershipRepository,
+	userRepo repositories.UserRepository,
+	eventPub events.EventPublisher,
+	opts ...OrganizationServiceOption,
+) *OrganizationService {
+	service := &OrganizationService{
+		orgRepo:    orgRepo,
+		memberRepo: memberRepo,
+		userRepo:   userRepo,
+		eventPub:   eventPub,
+	}
+
+	for _, opt := range opts {
+		opt(service)
+	}
+
+	return service
+}
+
+// CreateOrganization creates an organization with ownerID as its first admin.
+func (s *OrganizationService) CreateOrganization(
+	ctx context.Context,
+	ownerID entities.UserID,
+	name, slug string,
+) (*entities.Organization, error) {
+	orgName, err := entities.NewOrganizationName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	orgSlug, err := entities.NewOrganizationSlug(slug)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.userRepo.GetByID(ctx, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("owner %s: %w", ownerID, err)
+	}
+
+	_, err = s.orgRepo.GetBySlug(ctx, orgSlug)
+	if err == nil {
+		return nil, fmt.Errorf("slug=%v: %w", orgSlug, entities.ErrOrganizationAlreadyExists)
+	}
+
+	if !errors.Is(err, entities.ErrOrganizationNotFound) {
+		return nil, fmt.Errorf("failed to check slug %v: %w", orgSlug, err)
+	}
+
+	org := entities.NewOrganization(orgName, orgSlug)
+
+	err = s.createWithFounder(ctx, org, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.publishEvent(events.MembershipChanged(
+		events.EventOrganizationCreated, ownerID, org.ID(), entities.UserRoleAdmin, ownerID,
+	))
+
+	return org, nil
+}
+
+// createWithFounder stores org and the founder's admin membership.
+func (s *OrganizationService) createWithFounder(
+	ctx context.Context,
+	org *entities.Organization,
+	ownerID entities.UserID,
+) error {
+	create := func(
+		ctx context.Context,
+		orgRepo repositories.OrganizationRepository,
+		memberRepo repositories.MembershipRepository,
+	) error {
+		err := orgRepo.Create(ctx, org)
+		if err != nil {
+			return fmt.Errorf("failed to create organization: %w", err)
+		}
+
+		founder, err := entities.NewMembership(org.ID(), ownerID, entities.UserRoleAdmin)
+		if err != nil {
+			return err
+		}
+
+		err = memberRepo.Create(ctx, founder)
+		if err != nil {
+			return fmt.Errorf("failed to add founder to %s: %w", org.ID(), err)
+		}
+
+		return nil
+	}
+
+	if s.txRepo == nil {
+		return create(ctx, s.orgRepo, s.memberRepo)
+	}
+
+	err := NewUnitOfWork(s.txRepo, nil).
+		Add(func(ctx context.Context, tx repositories.Transaction) error {
+			return create(ctx, tx.OrganizationRepository(), tx.MembershipRepository())
+		}).
+		Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("slug=%v: %w", org.Slug(), err)
+	}
+
+	return nil
+}
+
+// Invite invites inviteeID to the organization with role.
+// Only admins of the organization may invite.
+func (s *OrganizationService) Invite(
+	ctx context.Context,
+	orgID entities.OrganizationID,
+	inviterID, inviteeID entities.UserID,
+	role entities.UserRole,
+) (*entities.Membership, error) {
+	err := s.requireAdmin(ctx, orgID, inviterID)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.userRepo.GetByID(ctx, inviteeID)
+	if err != nil {
+		return nil, fmt.Errorf("invitee %s: %w", inviteeID, err)
+	}
+
+	invitation, err := entities.NewInvitation(orgID, inviteeID, role, inviterID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.memberRepo.Create(ctx, invitation)
+	if err != nil {
+		return nil, fmt.Errorf("failed to invite %s to %s: %w", inviteeID, orgID, err)
+	}
+
+	s.publishEvent(events.MembershipChanged(events.EventMemberInvited, inviteeID, orgID, role, inviterID))
+
+	return invitation, nil
+}
+
+// Join accepts userID's pending invitation to the organization.
+func (s *OrganizationService) Join(
+	ctx context.Context,
+	orgID entities.OrganizationID,
+	userID entities.UserID,
+) (*entities.Membership, error) {
+	membership, err := s.memberRepo.Get(ctx, orgID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("no invitation for %s to %s: %w", userID, orgID, err)
+	}
+
+	err = membership.Accept()
+	if err != nil {
+		return nil, fmt.Errorf("user %s joining %s: %w", userID, orgID, err)
+	}
+
+	err = s.memberRepo.Update(ctx, membership)
+	if err != nil {
+		return nil, fmt.Errorf("failed to join %s: %w", orgID, err)
+	}
+
+	s.publishEvent(events.MembershipChanged(events.EventMemberJoined, userID, orgID, membership.Role(), userID))
+
+	return membership, nil
+}
+
+// Leave removes userID from the organization, or declines a pending invitation.
+// The last admin cannot leave.
+func (s *OrganizationService) Leave(
+	ctx context.Context,
+	orgID entities.OrganizationID,
+	userID entities.UserID,
+) error {
+	membership, err := s.memberRepo.Get(ctx, orgID, userID)
+	if err != nil {
+		return fmt.Errorf("user %s in %s: %w", userID, orgID, err)
+	}
+
+	err = s.ensureOtherAdmin(ctx, membership)
+	if err != nil {
+		return err
+	}
+
+	err = s.memberRepo.Delete(ctx, orgID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to leave %s: %w", orgID, err)
+	}
+
+	s.publishEvent(events.MembershipChanged(events.EventMemberLeft, userID, orgID, membership.Role(), userID))
+
+	return nil
+}
+
+// ChangeMemberRole changes the organization role of userID.
+// Only admins may change roles, and the last admin cannot be demoted.
+func (s *OrganizationService) ChangeMemberRole(
+	ctx context.Context,
+	orgID entities.OrganizationID,
+	actorID, userID entities.UserID,
+	role entities.UserRole,
+) (*entities.Membership, error) {
+	err := s.requireAdmin(ctx, orgID, actorID)
+	if err != nil {
+		return nil, err
+	}
+
+	membership, err := s.memberRepo.Get(ctx, orgID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("user %s in %s: %w", userID, orgID, err)
+	}
+
+	if role != entities.UserRoleAdmin {
+		err = s.ensureOtherAdmin(ctx, membership)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = membership.ChangeRole(role)
+	if err != nil {
+		return nil, fmt.Errorf("role=%v: %w", role, err)
+	}
+
+	err = s.memberRepo.Update(ctx, membership)
+	if err != nil {
+		return nil, fmt.Errorf("failed to change role of %s in %s: %w", userID, orgID, err)
+	}
+
+	s.publishEvent(events.MembershipChanged(events.EventMemberRoleChanged, userID, orgID, role, actorID))
+
+	return membership, nil
+}
+
+// ListMembers returns the members and pending invitations of an organization.
+func (s *OrganizationService) ListMembers(
+	ctx context.Context,
+	orgID entities.OrganizationID,
+	limit, offset int,
+) ([]*entities.Membership, error) {
+	if limit <= 0 {
+		return nil, entities.NewValidationError("limit", "must be positive")
+	}
+
+	members, err := s.memberRepo.ListByOrganization(ctx, orgID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("list members of %s: %w", orgID, err)
+	}
+
+	return members, nil
+}
+
+// ListUserOrganizations returns the organizations userID is an active member of.
+func (s *OrganizationService) ListUserOrganizations(
+	ctx context.Context,
+	userID entities.UserID,
+	limit int,
+) ([]*entities.Organization, error) {
+	if limit <= 0 {
+		return nil, entities.NewValidationError("limit", "must be positive")
+	}
+
+	orgs, err := s.orgRepo.ListByUser(ctx, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list organizations of %s: %w", userID, err)
+	}
+
+	return orgs, nil
+}
+
+// requireAdmin returns an authorization error unless userID administers the organization.
+func (s *OrganizationService) requireAdmin(
+	ctx context.Context,
+	orgID entities.OrganizationID,
+	userID entities.UserID,
+) error {
+	role, err := s.memberRepo.RoleInOrganization(ctx, userID, orgID)
+	if errors.Is(err, entities.ErrMembershipNotFound) || (err == nil && role != entities.UserRoleAdmin) {
+		return fmt.Errorf("user %s in %s: %w", userID, orgID, entities.ErrInsufficientPrivileges)
+	}
+
+	if err != nil {
+		return fmt.Errorf("resolve role of %s in %s: %w", userID, orgID, err)
+	}
+
+	return nil
+}
+
+// ensureOtherAdmin rejects removing or demoting the organization's last admin.
+func (s *OrganizationService) ensureOtherAdmin(ctx context.Context, membership *entities.Membership) error {
+	if !membership.IsAdmin() {
+		return nil
+	}
+
+	admins, err := s.memberRepo.CountAdmins(ctx, membership.OrganizationID())
+	if err != nil {
+		return fmt.Errorf("count admins of %s: %w", membership.OrganizationID(), err)
+	}
+
+	if admins <= 1 {
+		return fmt.Errorf("%s: %w", membership.OrganizationID(), entities.ErrLastOrganizationAdmin)
+	}
+
+	return nil
+}
+
+// publishEvent publishes an event and logs a warning if it fails.
+func (s *OrganizationService) publishEvent(event *events.UserEvent) {
+	err := s.eventPub.Publish(event)
+	if err != nil {
+		slog.Warn("failed to publish event", "error", err)
+	}
+}
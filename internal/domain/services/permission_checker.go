@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+)
+
+// OrganizationRoleResolver resolves the role a user holds inside an organization.
+// It is implemented by repositories.MembershipRepository.
+type OrganizationRoleResolver interface {
+	RoleInOrganization(
+		ctx context.Context,
+		userID entities.UserID,
+		orgID entities.OrganizationID,
+	) (entities.UserRole, error)
+}
+
+// PermissionChecker evaluates permissions for a user, scoped to the session's organization.
+type PermissionChecker struct {
+	orgRoles OrganizationRoleResolver
+}
+
+// NewPermissionChecker creates a new permission checker.
+// A nil resolver restricts evaluation to the user's global role.
+func NewPermissionChecker(orgRoles OrganizationRoleResolver) *PermissionChecker {
+	return &PermissionChecker{orgRoles: orgRoles}
+}
+
+// Can returns true if the user may perform the permission within the session scope.
+// Global admins are allowed everywhere; otherwise an organization-scoped session
+// is evaluated against the user's role in that organization.
+func (c *PermissionChecker) Can(
+	ctx context.Context,
+	session *entities.UserSession,
+	user *entities.User,
+	permission entities.Permission,
+) (bool, error) {
+	if !user.IsActive() {
+		return false, nil
+	}
+
+	if user.Role() == entities.UserRoleAdmin {
+		return true, nil
+	}
+
+	orgID, scoped := organizationScope(session)
+	if !scoped {
+		return user.Role().Grants(permission), nil
+	}
+
+	if c.orgRoles == nil {
+		return false, nil
+	}
+
+	role, err := c.orgRoles.RoleInOrganization(ctx, user.ID(), orgID)
+	if err != nil {
+		return false, fmt.Errorf("resolve role of user %s in %s: %w", user.ID(), orgID, err)
+	}
+
+	return role.Grants(permission), nil
+}
+
+// Require returns an authorization error if the permission is not granted.
+func (c *PermissionChecker) Require(
+	ctx context.Context,
+	session *entities.UserSession,
+	user *entities.User,
+	permission entities.Permission,
+) error {
+	allowed, err := c.Can(ctx, session, user, permission)
+	if err != nil {
+		return err
+	}
+
+	if !allowed {
+		return fmt.Errorf("permission=%s: %w", permission, entities.ErrInsufficientPrivileges)
+	}
+
+	return nil
+}
+
+// organizationScope returns the organization a session is scoped to, if any.
+func organizationScope(session *entities.UserSession) (entities.OrganizationID, bool) {
+	if session == nil {
+		return 0, false
+	}
+
+	return session.OrganizationID()
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// MaxReportRows bounds the rows of ranked reports such as FailuresByIP.
+const MaxReportRows = 1000
+
+// ReportingService reads the login and signup reports built from user events.
+type ReportingService struct {
+	analytics repositories.EventAnalyticsRepository
+}
+
+// NewReportingService creates a new reporting service.
+func NewReportingService(analytics repositories.EventAnalyticsRepository) *ReportingService {
+	return &ReportingService{analytics: analytics}
+}
+
+// LoginsByDay returns the login attempts per UTC day between from and to.
+func (s *ReportingService) LoginsByDay(ctx context.Context, from, to time.Time) ([]entities.DailyLogins, error) {
+	err := validateReportRange(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	days, err := s.analytics.LoginsByDay(ctx, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("logins by day from=%s to=%s: %w", formatReportTime(from), formatReportTime(to), err)
+	}
+
+	return days, nil
+}
+
+// FailuresByIP returns the limit addresses with the most failed logins
+// between from and to.
+func (s *ReportingService) FailuresByIP(
+	ctx context.Context,
+	from, to time.Time,
+	limit int,
+) ([]entities.IPFailures, error) {
+	err := validateReportRange(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 || limit > MaxReportRows {
+		return nil, entities.NewValidationError("limit", fmt.Sprintf("must be between 1 and %d", MaxReportRows))
+	}
+
+	failures, err := s.analytics.FailuresByIP(ctx, from, to, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failures by ip from=%s to=%s: %w", formatReportTime(from), formatReportTime(to), err)
+	}
+
+	return failures, nil
+}
+
+// SignupsBySource returns the signups per source between from and to.
+func (s *ReportingService) SignupsBySource(ctx context.Context, from, to time.Time) ([]entities.SignupSource, error) {
+	err := validateReportRange(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	sources, err := s.analytics.SignupsBySource(ctx, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("signups by source from=%s to=%s: %w", formatReportTime(from), formatReportTime(to), err)
+	}
+
+	return sources, nil
+}
+
+// validateReportRange rejects empty and open-ended report ranges.
+func validateReportRange(from, to time.Time) error {
+	if from.IsZero() || to.IsZero() {
+		return entities.NewValidationError("from", "report range must be bounded")
+	}
+
+	if !to.After(from) {
+		return entities.NewValidationError("to", "must be after from")
+	}
+
+	return nil
+}
+
+func formatReportTime(t time.Time) string {
+	return t.Format(time.RFC3339)
+}
@@ -0,0 +1,153 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+	"github.com/google/uuid"
+)
+
+// TokenPair is a short-lived access token with the refresh token that renews it.
+// Access token lifetimes are only enforced by strategies that encode an
+// expiry, such as signed JWTs; opaque tokens live as long as the session.
+type TokenPair struct {
+	AccessToken      string    `json:"accessToken"`
+	AccessExpiresAt  time.Time `json:"accessExpiresAt"`
+	RefreshToken     string    `json:"refreshToken"`
+	RefreshExpiresAt time.Time `json:"refreshExpiresAt"`
+}
+
+// WithRefreshPolicy overrides the access and refresh token lifetimes.
+// Invalid policies are ignored in favor of the default.
+func WithRefreshPolicy(policy entities.RefreshPolicy) UserServiceOption {
+	return func(s *UserService) {
+		if policy.Validate() == nil {
+			s.refresh = policy
+		}
+	}
+}
+
+// IssueTokenPair starts the refresh token family of a session and returns
+// the first access and refresh tokens for it.
+func (s *UserService) IssueTokenPair(
+	ctx context.Context,
+	session *entities.UserSession,
+	user *entities.User,
+) (*TokenPair, error) {
+	now := time.Now()
+	session.IssueRefreshToken(s.refreshExpiry(session, now))
+
+	err := s.sessionRepo.Update(ctx, session)
+	if err != nil {
+		return nil, fmt.Errorf("session=%v: failed to store refresh token: %w", session.ID(), err)
+	}
+
+	return s.tokenPair(session, user, now)
+}
+
+// RefreshSession exchanges a refresh token for a new token pair.
+// Refresh tokens are single-use: presenting one that was already rotated
+// revokes the session, invalidating every token issued for it.
+func (s *UserService) RefreshSession(ctx context.Context, refreshToken string) (_ *TokenPair, err error) {
+	ctx, end := s.startSpan(ctx, "RefreshSession")
+	defer end(&err)
+
+	parsed, err := uuid.Parse(refreshToken)
+	if err != nil {
+		return nil, fmt.Errorf("malformed refresh token: %w", entities.ErrInvalidRefreshToken)
+	}
+
+	token := entities.RefreshToken(parsed)
+
+	session, err := s.sessionRepo.GetByRefreshToken(ctx, token)
+	if err != nil {
+		return nil, fmt.Errorf("refresh token lookup: %w", entities.ErrInvalidRefreshToken)
+	}
+
+	now := time.Now()
+
+	_, err = session.RotateRefreshToken(token, s.refreshExpiry(session, now), now)
+	if errors.Is(err, entities.ErrRefreshTokenReused) {
+		s.revokeReusedSession(ctx, session)
+
+		return nil, fmt.Errorf("session=%v: %w", session.ID(), err)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("session=%v: %w", session.ID(), err)
+	}
+
+	if session.IsExpired() || s.policyFor(session).Exceeded(session, now) {
+		return nil, fmt.Errorf("session=%v: %w", session.ID(), entities.ErrSessionExpired)
+	}
+
+	user, err := s.userRepo.GetByID(ctx, session.UserID())
+	if err != nil {
+		return nil, fmt.Errorf("user not found for session=%v: %w", session.ID(), err)
+	}
+
+	if !user.IsActive() {
+		return nil, fmt.Errorf("session=%v: %w", session.ID(), entities.ErrAccountInactive)
+	}
+
+	s.policyFor(session).Renew(session, now)
+
+	// Only the first of concurrent refreshes with token may rotate it; the
+	// others, and a refresh racing a revocation, fail with a conflict.
+	err = s.sessionRepo.UpdateIfCurrent(ctx, session, token)
+	if err != nil {
+		return nil, fmt.Errorf("session=%v: failed to rotate refresh token: %w", session.ID(), err)
+	}
+
+	return s.tokenPair(session, user, now)
+}
+
+// revokeReusedSession persists the revocation of a session whose refresh
+// token was reused and reports it.
+func (s *UserService) revokeReusedSession(ctx context.Context, session *entities.UserSession) {
+	err := s.sessionRepo.Update(ctx, session)
+	if err != nil {
+		slog.Warn("failed to revoke session after refresh token reuse", "error", err)
+	}
+
+	s.publishEvent(ctx, events.RefreshTokenReused(session.UserID(), session.ID()))
+}
+
+// refreshExpiry returns when a refresh token issued now expires.
+func (s *UserService) refreshExpiry(session *entities.UserSession, now time.Time) time.Time {
+	expiresAt := now.Add(s.refresh.RefreshTokenLifetime)
+	if absolute := s.policyFor(session).AbsoluteExpiry(session); expiresAt.After(absolute) {
+		return absolute
+	}
+
+	return expiresAt
+}
+
+// tokenPair issues an access token and pairs it with the current refresh token.
+func (s *UserService) tokenPair(
+	session *entities.UserSession,
+	user *entities.User,
+	now time.Time,
+) (*TokenPair, error) {
+	accessExpiresAt := now.Add(s.refresh.AccessTokenLifetime)
+	if absolute := s.policyFor(session).AbsoluteExpiry(session); accessExpiresAt.After(absolute) {
+		accessExpiresAt = absolute
+	}
+
+	accessToken, err := s.issueToken(session, user, accessExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TokenPair{
+		AccessToken:      accessToken,
+		AccessExpiresAt:  accessExpiresAt,
+		RefreshToken:     session.RefreshToken().String(),
+		RefreshExpiresAt: session.RefreshExpiresAt(),
+	}, nil
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// SessionService provides business logic for session scoping.
+type SessionService struct {
+	sessionRepo repositories.SessionRepository
+	orgRoles    OrganizationRoleResolver
+}
+
+// NewSessionService creates a new session service.
+func NewSessionService(
+	sessionRepo repositories.SessionRepository,
+	orgRoles OrganizationRoleResolver,
+) *SessionService {
+	return &SessionService{
+		sessionRepo: sessionRepo,
+		orgRoles:    orgRoles,
+	}
+}
+
+// SwitchOrganization scopes a valid session to an organization the user belongs to.
+func (s *SessionService) SwitchOrganization(
+	ctx context.Context,
+	token entities.SessionToken,
+	orgID entities.OrganizationID,
+) (*entities.UserSession, error) {
+	session, err := s.validSession(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.orgRoles == nil {
+		return nil, fmt.Errorf("%s: %w", orgID, entities.ErrInsufficientPrivileges)
+	}
+
+	_, err = s.orgRoles.RoleInOrganization(ctx, session.UserID(), orgID)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"user %s is not a member of %s: %w",
+			session.UserID(), orgID, entities.ErrInsufficientPrivileges,
+		)
+	}
+
+	session.SwitchOrganization(orgID)
+
+	err = s.sessionRepo.Update(ctx, session)
+	if err != nil {
+		return nil, fmt.Errorf("failed to switch session to %s: %w", orgID, err)
+	}
+
+	return session, nil
+}
+
+// LeaveOrganization removes the organization scope from a valid session.
+func (s *SessionService) LeaveOrganization(
+	ctx context.Context,
+	token entities.SessionToken,
+) (*entities.UserSession, error) {
+	session, err := s.validSession(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	session.ClearOrganization()
+
+	err = s.sessionRepo.Update(ctx, session)
+	if err != nil {
+		return nil, fmt.Errorf("failed to clear session organization: %w", err)
+	}
+
+	return session, nil
+}
+
+// validSession loads a session and ensures it is active and not expired.
+func (s *SessionService) validSession(
+	ctx context.Context,
+	token entities.SessionToken,
+) (*entities.UserSession, error) {
+	session, err := s.sessionRepo.GetByToken(ctx, token)
+	if err != nil {
+		return nil, fmt.Errorf("token=%v: %w", token, entities.ErrSessionNotFound)
+	}
+
+	if session.IsExpired() {
+		return nil, fmt.Errorf("token=%v: %w", token, entities.ErrSessionExpired)
+	}
+
+	if !session.IsValid() {
+		return nil, fmt.Errorf("token=%v: %w", token, entities.ErrSessionNotFound)
+	}
+
+	return session, nil
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/google/uuid"
+)
+
+// TokenClaims is what a client token asserts about the session it belongs to.
+// UserID and Role are zero for strategies that only carry the session token.
+type TokenClaims struct {
+	SessionToken entities.SessionToken
+	UserID       entities.UserID
+	Role         entities.UserRole
+	ExpiresAt    time.Time
+}
+
+// TokenStrategy encodes sessions into the tokens handed to clients and decodes them back.
+type TokenStrategy interface {
+	// Issue encodes claims into a client token.
+	Issue(claims TokenClaims) (string, error)
+
+	// Parse verifies a client token and returns its claims.
+	Parse(token string) (*TokenClaims, error)
+}
+
+// UUIDTokenStrategy hands out the opaque session token itself.
+type UUIDTokenStrategy struct{}
+
+// Issue returns the session token as a string.
+func (UUIDTokenStrategy) Issue(claims TokenClaims) (string, error) {
+	return claims.SessionToken.String(), nil
+}
+
+// Parse parses an opaque session token.
+func (UUIDTokenStrategy) Parse(token string) (*TokenClaims, error) {
+	tokenUUID, err := uuid.Parse(token)
+	if err != nil {
+		return nil, fmt.Errorf("token=%v: %w", token, entities.ErrInvalidSessionToken)
+	}
+
+	return &TokenClaims{SessionToken: entities.SessionToken(tokenUUID)}, nil
+}
+
+// WithTokenStrategy replaces the default opaque UUID session tokens.
+func WithTokenStrategy(strategy TokenStrategy) UserServiceOption {
+	return func(s *UserService) {
+		if strategy != nil {
+			s.tokens = strategy
+		}
+	}
+}
+
+// IssueSessionToken returns the client token for a session of user.
+// Tokens expire with the session's absolute lifetime; idle expiry is still
+// enforced server-side by VerifySession.
+func (s *UserService) IssueSessionToken(
+	session *entities.UserSession,
+	user *entities.User,
+) (string, error) {
+	return s.issueToken(session, user, s.policyFor(session).AbsoluteExpiry(session))
+}
+
+// issueToken encodes a token for session that expires at expiresAt.
+func (s *UserService) issueToken(
+	session *entities.UserSession,
+	user *entities.User,
+	expiresAt time.Time,
+) (string, error) {
+	token, err := s.tokens.Issue(TokenClaims{
+		SessionToken: session.Token(),
+		UserID:       user.ID(),
+		Role:         user.Role(),
+		ExpiresAt:    expiresAt,
+	})
+	if err != nil {
+		return "", fmt.Errorf("session=%v: failed to issue token: %w", session.ID(), err)
+	}
+
+	return token, nil
+}
+
+// parseSessionToken decodes a client token into its claims.
+func (s *UserService) parseSessionToken(token string) (*TokenClaims, error) {
+	claims, err := s.tokens.Parse(token)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", entities.ErrInvalidSessionToken, err)
+	}
+
+	return claims, nil
+}
+
+// matchesClaims reports whether session and user are the ones the claims were issued for.
+// Tokens issued before a role change are rejected so they cannot carry stale privileges.
+func matchesClaims(claims *TokenClaims, session *entities.UserSession, user *entities.User) bool {
+	if claims.UserID != 0 && claims.UserID != session.UserID() {
+		return false
+	}
+
+	return claims.Role == "" || claims.Role == user.Role()
+}
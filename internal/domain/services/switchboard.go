@@ -0,0 +1,195 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// DefaultSwitchRefresh is how often a Switchboard reloads the feature flags.
+const DefaultSwitchRefresh = 10 * time.Second
+
+// ErrFeatureFlagsUnavailable is returned for setting a switch of a
+// Switchboard without feature flags.
+var ErrFeatureFlagsUnavailable = errors.New("feature flags are not available")
+
+// Switchboard holds the service switches that degrade the service
+// gracefully, such as read-only mode during maintenance. Switches default to
+// the configuration and are overridden at runtime by the feature flags of
+// the same name, which every instance sharing the database picks up within
+// the refresh interval.
+type Switchboard struct {
+	defaults map[entities.ServiceSwitch]bool
+	flags    repositories.FeatureFlagRepository
+	refresh  time.Duration
+	now      func() time.Time
+
+	mu       sync.Mutex
+	state    map[entities.ServiceSwitch]bool
+	loadedAt time.Time
+}
+
+// SwitchboardOption configures a Switchboard.
+type SwitchboardOption func(*Switchboard)
+
+// WithSwitchDefaults turns on the switches that are on in defaults, unless a
+// feature flag turns them off.
+func WithSwitchDefaults(defaults map[entities.ServiceSwitch]bool) SwitchboardOption {
+	return func(b *Switchboard) {
+		maps.Copy(b.defaults, defaults)
+	}
+}
+
+// WithFeatureFlags overrides the defaults with the feature flags of flags,
+// reloaded at most every refresh, or DefaultSwitchRefresh if refresh is not
+// positive.
+func WithFeatureFlags(flags repositories.FeatureFlagRepository, refresh time.Duration) SwitchboardOption {
+	return func(b *Switchboard) {
+		b.flags = flags
+		b.refresh = refresh
+
+		if refresh <= 0 {
+			b.refresh = DefaultSwitchRefresh
+		}
+	}
+}
+
+// NewSwitchboard creates a switchboard with every switch off, unless
+// configured otherwise by opts.
+func NewSwitchboard(opts ...SwitchboardOption) *Switchboard {
+	board := &Switchboard{
+		defaults: make(map[entities.ServiceSwitch]bool),
+		refresh:  DefaultSwitchRefresh,
+		now:      time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(board)
+	}
+
+	return board
+}
+
+// State returns whether each switch is on. If the feature flags cannot be
+// loaded, the last loaded state is kept, so that an outage of the database
+// does not switch the service back to normal operation.
+func (b *Switchboard) State(ctx context.Context) map[entities.ServiceSwitch]bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state != nil && (b.flags == nil || b.now().Sub(b.loadedAt) < b.refresh) {
+		return maps.Clone(b.state)
+	}
+
+	state, err := b.load(ctx)
+	if err != nil {
+		slog.Warn("failed to load feature flags, keeping service switches", "error", err)
+
+		if b.state == nil {
+			return maps.Clone(b.defaults)
+		}
+
+		return maps.Clone(b.state)
+	}
+
+	b.state = state
+	b.loadedAt = b.now()
+
+	return maps.Clone(state)
+}
+
+// load returns the defaults overridden by the stored feature flags.
+func (b *Switchboard) load(ctx context.Context) (map[entities.ServiceSwitch]bool, error) {
+	state := make(map[entities.ServiceSwitch]bool, len(entities.ServiceSwitches()))
+	for _, sw := range entities.ServiceSwitches() {
+		state[sw] = b.defaults[sw]
+	}
+
+	if b.flags == nil {
+		return state, nil
+	}
+
+	flags, err := b.flags.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list feature flags: %w", err)
+	}
+
+	for _, flag := range flags {
+		sw := entities.ServiceSwitch(flag.Name)
+		if sw.IsValid() {
+			state[sw] = flag.Enabled
+		}
+	}
+
+	return state, nil
+}
+
+// Check returns the error of the first of switches that is on, such as
+// ErrReadOnlyMode, or nil if all are off.
+func (b *Switchboard) Check(ctx context.Context, switches ...entities.ServiceSwitch) error {
+	state := b.State(ctx)
+
+	for _, sw := range switches {
+		if state[sw] {
+			return sw.Err()
+		}
+	}
+
+	return nil
+}
+
+// Set turns sw on or off through its feature flag. The change applies to
+// this instance at once and to the others within the refresh interval.
+func (b *Switchboard) Set(ctx context.Context, sw entities.ServiceSwitch, on bool) error {
+	if !sw.IsValid() {
+		return fmt.Errorf("switch=%v: %w", sw, entities.ErrInvalidServiceSwitch)
+	}
+
+	if b.flags == nil {
+		return fmt.Errorf("switch=%v: %w", sw, ErrFeatureFlagsUnavailable)
+	}
+
+	flag, err := entities.NewFeatureFlag(sw.String(), on, b.now())
+	if err != nil {
+		return err
+	}
+
+	err = b.flags.Set(ctx, flag)
+	if err != nil {
+		return fmt.Errorf("set switch=%v: %w", sw, err)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state != nil {
+		b.state[sw] = on
+	}
+
+	return nil
+}
+
+// WithSwitchboard enforces the switches of board: read-only mode rejects
+// changes to users, and registration and sign-in can be disabled on their
+// own. Sessions that were started keep working.
+func WithSwitchboard(board *Switchboard) UserServiceOption {
+	return func(s *UserService) {
+		s.switches = board
+	}
+}
+
+// checkSwitches returns the error of the first of switches that is on.
+func (s *UserService) checkSwitches(ctx context.Context, switches ...entities.ServiceSwitch) error {
+	if s.switches == nil {
+		return nil
+	}
+
+	return s.switches.Check(ctx, switches...)
+}
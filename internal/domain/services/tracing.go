@@ -0,0 +1,52 @@
+package services
+
+import (
+	"context"
+)
+
+// Tracer starts a span around a service operation. Repositories receive the
+// returned context so their spans become children of the operation's span.
+// The monitoring package provides an OpenTelemetry implementation.
+type Tracer interface {
+	Start(ctx context.Context, operation string) (context.Context, Span)
+
+	// TraceParent returns the W3C traceparent of the span in ctx, or "" if
+	// ctx carries none. It is attached to published events.
+	TraceParent(ctx context.Context) string
+}
+
+// Span is an in-progress operation span.
+type Span interface {
+	// End finishes the span, marking it failed if err is non-nil.
+	End(err error)
+}
+
+// WithTracer traces the service operations with tracer.
+func WithTracer(tracer Tracer) UserServiceOption {
+	return func(s *UserService) {
+		s.tracer = tracer
+	}
+}
+
+// noopTracer is the default Tracer; it records nothing.
+type noopTracer struct{}
+
+func (noopTracer) Start(ctx context.Context, _ string) (context.Context, Span) {
+	return ctx, noopSpan{}
+}
+
+func (noopTracer) TraceParent(context.Context) string { return "" }
+
+type noopSpan struct{}
+
+func (noopSpan) End(error) {}
+
+// startSpan starts the span of a UserService operation. Callers defer the
+// returned function with a pointer to their named error result.
+func (s *UserService) startSpan(ctx context.Context, operation string) (context.Context, func(*error)) {
+	ctx, span := s.tracer.Start(ctx, "UserService."+operation)
+
+	return ctx, func(err *error) {
+		span.End(*err)
+	}
+}
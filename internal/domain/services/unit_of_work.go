@@ -0,0 +1,190 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+var (
+	// ErrUnitOfWorkCompleted is returned when a unit of work is committed twice.
+	ErrUnitOfWorkCompleted = errors.New("unit of work already completed")
+
+	// ErrTransactionsUnavailable is returned when no transactional repository is configured.
+	ErrTransactionsUnavailable = errors.New("transactions are not available")
+
+	// ErrAuditUnavailable is returned when audit entries are recorded in a
+	// transaction of an engine without an audit log.
+	ErrAuditUnavailable = errors.New("audit log is not available")
+)
+
+// UnitOfWorkOperation is a repository write executed inside the unit of work transaction.
+// Repositories must be taken from tx so that all operations share the transaction.
+type UnitOfWorkOperation func(ctx context.Context, tx repositories.Transaction) error
+
+// EventFactory builds an event once the unit of work has committed,
+// so that IDs assigned by the database are available to it.
+type EventFactory func() *events.UserEvent
+
+// AuditFactory builds an audit entry when its operation runs, so that IDs
+// assigned by earlier operations are available to it.
+type AuditFactory func() *entities.AuditLog
+
+// UnitOfWork collects writes across aggregates and commits them in one transaction.
+// Any failing operation rolls back all of them; recorded events are only
+// published after a successful commit.
+type UnitOfWork struct {
+	txRepo     repositories.TransactionalRepository
+	eventPub   events.EventPublisher
+	operations []UnitOfWorkOperation
+	events     []EventFactory
+	completed  bool
+}
+
+// NewUnitOfWork creates an empty unit of work.
+func NewUnitOfWork(
+	txRepo repositories.TransactionalRepository,
+	eventPub events.EventPublisher,
+) *UnitOfWork {
+	return &UnitOfWork{
+		txRepo:   txRepo,
+		eventPub: eventPub,
+	}
+}
+
+// NewUnitOfWork creates a unit of work using the service's transactions and publisher.
+func (s *UserService) NewUnitOfWork() *UnitOfWork {
+	return NewUnitOfWork(s.txRepo, s.eventPub)
+}
+
+// Add appends an operation. Operations run in the order they were added.
+func (u *UnitOfWork) Add(op UnitOfWorkOperation) *UnitOfWork {
+	u.operations = append(u.operations, op)
+
+	return u
+}
+
+// CreateUser appends the creation of user.
+func (u *UnitOfWork) CreateUser(user *entities.User) *UnitOfWork {
+	return u.Add(func(ctx context.Context, tx repositories.Transaction) error {
+		err := tx.UserRepository().Create(ctx, user)
+		if err != nil {
+			return fmt.Errorf("failed to create user: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// UpdateUser appends the update of user.
+func (u *UnitOfWork) UpdateUser(user *entities.User) *UnitOfWork {
+	return u.Add(func(ctx context.Context, tx repositories.Transaction) error {
+		err := tx.UserRepository().Update(ctx, user)
+		if err != nil {
+			return fmt.Errorf("user=%v: failed to update user: %w", user.ID(), err)
+		}
+
+		return nil
+	})
+}
+
+// CreateSession appends the creation of session.
+func (u *UnitOfWork) CreateSession(session *entities.UserSession) *UnitOfWork {
+	return u.Add(func(ctx context.Context, tx repositories.Transaction) error {
+		err := tx.SessionRepository().Create(ctx, session)
+		if err != nil {
+			return fmt.Errorf("failed to create session: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// RecordAudit appends the recording of the audit entry built by factory,
+// which is discarded with the other writes if the unit of work fails.
+func (u *UnitOfWork) RecordAudit(factory AuditFactory) *UnitOfWork {
+	return u.Add(func(ctx context.Context, tx repositories.Transaction) error {
+		audit := tx.AuditRepository()
+		if audit == nil {
+			return ErrAuditUnavailable
+		}
+
+		entry := factory()
+
+		err := audit.Record(ctx, entry)
+		if err != nil {
+			return fmt.Errorf("action=%v: failed to record audit entry: %w", entry.Action, err)
+		}
+
+		return nil
+	})
+}
+
+// PublishAfterCommit records events to publish once the transaction has committed.
+// Nothing is published if the unit of work fails.
+func (u *UnitOfWork) PublishAfterCommit(factories ...EventFactory) *UnitOfWork {
+	u.events = append(u.events, factories...)
+
+	return u
+}
+
+// Commit runs all operations in a single transaction and publishes the
+// recorded events afterwards. A unit of work can only be committed once.
+func (u *UnitOfWork) Commit(ctx context.Context) error {
+	if u.completed {
+		return ErrUnitOfWorkCompleted
+	}
+
+	if u.txRepo == nil {
+		return ErrTransactionsUnavailable
+	}
+
+	u.completed = true
+
+	err := u.txRepo.RunInTransaction(
+		ctx,
+		func(ctx context.Context, tx repositories.Transaction) error {
+			for i, op := range u.operations {
+				err := op(ctx, tx)
+				if err != nil {
+					return fmt.Errorf("operation=%d: %w", i, err)
+				}
+			}
+
+			return nil
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("unit of work rolled back: %w", err)
+	}
+
+	u.publish()
+
+	return nil
+}
+
+// publish builds and publishes the recorded events, logging failures.
+func (u *UnitOfWork) publish() {
+	if u.eventPub == nil || len(u.events) == 0 {
+		return
+	}
+
+	batch := make([]*events.UserEvent, 0, len(u.events))
+
+	for _, factory := range u.events {
+		event := factory()
+		if event != nil {
+			batch = append(batch, event)
+		}
+	}
+
+	err := u.eventPub.PublishBatch(batch)
+	if err != nil {
+		slog.Warn("failed to publish unit of work events", "error", err)
+	}
+}
@@ -0,0 +1,300 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// defaultBulkChunkSize is the number of users changed per transaction in bulk operations.
+const defaultBulkChunkSize = 500
+
+// BulkFailure describes why a single user could not be changed by a bulk operation.
+type BulkFailure struct {
+	UserID entities.UserID `json:"userId"`
+	Error  string          `json:"error"`
+}
+
+// BulkOperationResult reports the outcome of a bulk operation per user.
+type BulkOperationResult struct {
+	Succeeded []entities.UserID `json:"succeeded"`
+	Failed    []BulkFailure     `json:"failed"`
+}
+
+// HasFailures returns true if at least one user could not be changed.
+func (r *BulkOperationResult) HasFailures() bool {
+	return len(r.Failed) > 0
+}
+
+// errBulkChunkFailed rolls back the transaction of a chunk in which a user
+// failed.
+var errBulkChunkFailed = errors.New("bulk chunk failed")
+
+// bulkMutation applies a change to a loaded user and reports whether it was valid.
+type bulkMutation func(user *entities.User) error
+
+// BulkChangeRole changes the role of many users in chunked transactions.
+// Individual failures are reported in the result and do not abort the remaining users.
+func (s *UserService) BulkChangeRole(
+	ctx context.Context,
+	userIDs []entities.UserID,
+	role entities.UserRole,
+	changedBy entities.UserID,
+) (_ *BulkOperationResult, err error) {
+	ctx, end := s.startSpan(ctx, "BulkChangeRole")
+	defer end(&err)
+
+	err = s.checkSwitches(ctx, entities.SwitchReadOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	if !role.IsValid() {
+		return nil, fmt.Errorf("role=%v: %w", role, entities.ErrInvalidUserRole)
+	}
+
+	ctx = withFallbackActor(ctx, changedBy)
+
+	result, err := s.runBulk(ctx, userIDs, func(user *entities.User) error {
+		return user.ChangeRole(role)
+	})
+
+	// Users changed before a cancellation are still audited.
+	s.recordBulkAudit(ctx, entities.AuditActionBulkRoleChange, "role", role.String(), result)
+
+	if err != nil {
+		return nil, err
+	}
+
+	s.publishBulkEvent(ctx, "role", role.String(), changedBy, result)
+
+	return result, nil
+}
+
+// BulkChangeStatus changes the status of many users in chunked transactions.
+// Individual failures are reported in the result and do not abort the remaining users.
+func (s *UserService) BulkChangeStatus(
+	ctx context.Context,
+	userIDs []entities.UserID,
+	status entities.UserStatus,
+	changedBy entities.UserID,
+) (_ *BulkOperationResult, err error) {
+	ctx, end := s.startSpan(ctx, "BulkChangeStatus")
+	defer end(&err)
+
+	err = s.checkSwitches(ctx, entities.SwitchReadOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	if !status.IsValid() {
+		return nil, fmt.Errorf("status=%v: %w", status, entities.ErrInvalidUserStatus)
+	}
+
+	ctx = withFallbackActor(ctx, changedBy)
+
+	result, err := s.runBulk(ctx, userIDs, func(user *entities.User) error {
+		err := user.ChangeStatus(status)
+		if err != nil {
+			return err
+		}
+
+		return s.validator.ValidateUserUpdate(user)
+	})
+
+	// Users changed before a cancellation are still audited.
+	s.recordBulkAudit(ctx, entities.AuditActionBulkStatusChange, "status", status.String(), result)
+
+	if err != nil {
+		return nil, err
+	}
+
+	s.publishBulkEvent(ctx, "status", status.String(), changedBy, result)
+
+	return result, nil
+}
+
+// runBulk splits userIDs into chunks and applies mutate to each user. On
+// cancellation it returns the users changed so far along with the error.
+func (s *UserService) runBulk(
+	ctx context.Context,
+	userIDs []entities.UserID,
+	mutate bulkMutation,
+) (*BulkOperationResult, error) {
+	if len(userIDs) == 0 {
+		return nil, entities.NewValidationError("user_ids", "must not be empty")
+	}
+
+	result := &BulkOperationResult{
+		Succeeded: make([]entities.UserID, 0, len(userIDs)),
+		Failed:    make([]BulkFailure, 0),
+	}
+
+	for start := 0; start < len(userIDs); start += s.bulkChunk {
+		err := ctx.Err()
+		if err != nil {
+			return result, fmt.Errorf("bulk operation cancelled: %w", err)
+		}
+
+		end := min(start+s.bulkChunk, len(userIDs))
+		s.runBulkChunk(ctx, userIDs[start:end], mutate, result)
+	}
+
+	return result, nil
+}
+
+// recordBulkAudit records a bulk operation that set field to value as one
+// audit entry naming the users it changed and those it failed to change.
+// Operations rejected before changing anyone are not recorded.
+func (s *UserService) recordBulkAudit(
+	ctx context.Context,
+	action entities.AuditAction,
+	field, value string,
+	result *BulkOperationResult,
+) {
+	if s.audit == nil || result == nil {
+		return
+	}
+
+	failed := make([]entities.UserID, 0, len(result.Failed))
+	for _, failure := range result.Failed {
+		failed = append(failed, failure.UserID)
+	}
+
+	ctx = context.WithoutCancel(ctx)
+	actor, _ := AuditActorFromContext(ctx)
+	entry := entities.NewBulkAuditLog(actor.UserID, action, field, value, result.Succeeded, failed, actor.IPAddress)
+
+	err := s.audit.Record(ctx, entry)
+	if err != nil {
+		slog.Warn("failed to record audit log", "action", action, "users", len(result.Succeeded), "error", err)
+	}
+}
+
+// runBulkChunk applies mutate to one chunk, inside a transaction when
+// available. A failed statement may abort the whole transaction, as on
+// PostgreSQL, failing every user after it; so a chunk in which a user
+// fails is rolled back and its users retried in a transaction each.
+func (s *UserService) runBulkChunk(
+	ctx context.Context,
+	chunk []entities.UserID,
+	mutate bulkMutation,
+	result *BulkOperationResult,
+) {
+	if s.txRepo == nil {
+		succeeded, failed := applyBulkChunk(ctx, s.userRepo, chunk, mutate)
+		result.Succeeded = append(result.Succeeded, succeeded...)
+		result.Failed = append(result.Failed, failed...)
+
+		return
+	}
+
+	var succeeded []entities.UserID
+
+	var failed []BulkFailure
+
+	err := s.txRepo.RunInTransaction(
+		ctx,
+		func(ctx context.Context, tx repositories.Transaction) error {
+			succeeded, failed = applyBulkChunk(ctx, tx.UserRepository(), chunk, mutate)
+			if len(failed) > 0 {
+				return errBulkChunkFailed
+			}
+
+			return nil
+		},
+	)
+
+	switch {
+	case errors.Is(err, errBulkChunkFailed) && len(chunk) > 1:
+		for _, id := range chunk {
+			s.runBulkChunk(ctx, []entities.UserID{id}, mutate, result)
+		}
+
+		return
+	case errors.Is(err, errBulkChunkFailed):
+		result.Failed = append(result.Failed, failed...)
+
+		return
+	case err != nil:
+		for _, id := range chunk {
+			result.Failed = append(result.Failed, BulkFailure{UserID: id, Error: err.Error()})
+		}
+
+		return
+	}
+
+	result.Succeeded = append(result.Succeeded, succeeded...)
+}
+
+// applyBulkChunk loads, mutates, and saves each user of a chunk.
+func applyBulkChunk(
+	ctx context.Context,
+	repo repositories.UserRepository,
+	chunk []entities.UserID,
+	mutate bulkMutation,
+) ([]entities.UserID, []BulkFailure) {
+	succeeded := make([]entities.UserID, 0, len(chunk))
+	failed := make([]BulkFailure, 0)
+
+	for _, id := range chunk {
+		err := applyBulkMutation(ctx, repo, id, mutate)
+		if err != nil {
+			failed = append(failed, BulkFailure{UserID: id, Error: err.Error()})
+
+			continue
+		}
+
+		succeeded = append(succeeded, id)
+	}
+
+	return succeeded, failed
+}
+
+// applyBulkMutation loads, mutates, and saves a single user.
+func applyBulkMutation(
+	ctx context.Context,
+	repo repositories.UserRepository,
+	id entities.UserID,
+	mutate bulkMutation,
+) error {
+	user, err := repo.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("user %s not found: %w", id, err)
+	}
+
+	err = mutate(user)
+	if err != nil {
+		return fmt.Errorf("invalid change for user %s: %w", id, err)
+	}
+
+	err = repo.Update(ctx, user)
+	if err != nil {
+		return fmt.Errorf("failed to save user %s: %w", id, err)
+	}
+
+	return nil
+}
+
+// publishBulkEvent publishes a single aggregated audit event for a bulk operation.
+func (s *UserService) publishBulkEvent(
+	ctx context.Context,
+	field, value string,
+	changedBy entities.UserID,
+	result *BulkOperationResult,
+) {
+	event := events.UsersBulkUpdated(
+		field,
+		value,
+		result.Succeeded,
+		len(result.Failed),
+		changedBy,
+	)
+
+	s.publishEvent(ctx, event)
+}
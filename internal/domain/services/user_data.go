@@ -0,0 +1,772 @@
+package services
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// exportPageSize is the number of events, audit and activity entries read
+// per query while exporting the data of a user.
+const exportPageSize = 500
+
+// exportLoginAttempts is the number of the newest login attempts exported,
+// the most the login history returns at once.
+const exportLoginAttempts = 1000
+
+// WithEventStore makes ExportUserData include the events about a user kept
+// in store. Exports scan the whole store, so they get slower as it grows.
+func WithEventStore(store events.EventStore) UserServiceOption {
+	return func(s *UserService) {
+		s.eventStore = store
+	}
+}
+
+// WithActivityExport makes ExportUserData include the activity feed of a
+// user kept in repo.
+func WithActivityExport(repo repositories.ActivityRepository) UserServiceOption {
+	return func(s *UserService) {
+		s.activity = repo
+	}
+}
+
+// WithPreferenceExport makes ExportUserData include the preferences of a
+// user kept in repo.
+func WithPreferenceExport(repo repositories.UserPreferenceRepository) UserServiceOption {
+	return func(s *UserService) {
+		s.preferences = repo
+	}
+}
+
+// WithUserStreamExport makes ExportUserData include the event stream of an
+// event-sourced user kept in store.
+func WithUserStreamExport(store repositories.UserEventStore) UserServiceOption {
+	return func(s *UserService) {
+		s.userStreams = store
+	}
+}
+
+// UserDataExport is the machine-readable archive of the personal data
+// stored about a user, as produced by ExportUserData.
+type UserDataExport struct {
+	ExportedAt      time.Time                `json:"exportedAt"`
+	Profile         ExportedProfile          `json:"profile"`
+	Sessions        []ExportedSession        `json:"sessions"`
+	Events          []*events.UserEvent      `json:"events"`
+	AuditLog        []ExportedAuditEntry     `json:"auditLog"`
+	LoginHistory    []ExportedLoginAttempt   `json:"loginHistory"`
+	IdentityChanges []ExportedIdentityChange `json:"identityChanges"`
+	Moderation      ExportedModeration       `json:"moderation"`
+	Activity        []ExportedActivityEntry  `json:"activity"`
+	Preferences     map[string]any           `json:"preferences"`
+	Stream          []ExportedStreamEvent    `json:"stream"`
+}
+
+// ExportedProfile is the profile of an exported user. The password hash is
+// left out.
+type ExportedProfile struct {
+	ID          entities.UserID       `json:"id"`
+	UUID        string                `json:"uuid"`
+	Email       string                `json:"email"`
+	Username    string                `json:"username"`
+	FirstName   string                `json:"firstName"`
+	LastName    string                `json:"lastName"`
+	Status      string                `json:"status"`
+	Role        string                `json:"role"`
+	Verified    bool                  `json:"verified"`
+	Tags        []string              `json:"tags"`
+	Metadata    entities.UserMetadata `json:"metadata"`
+	CreatedAt   time.Time             `json:"createdAt"`
+	UpdatedAt   time.Time             `json:"updatedAt"`
+	LastLoginAt *time.Time            `json:"lastLoginAt,omitempty"`
+}
+
+// ExportedSession is a session of an exported user. Tokens are left out.
+type ExportedSession struct {
+	ID         entities.SessionID         `json:"id"`
+	IPAddress  string                     `json:"ipAddress"`
+	UserAgent  string                     `json:"userAgent"`
+	DeviceInfo entities.SessionDeviceInfo `json:"deviceInfo"`
+	CreatedAt  time.Time                  `json:"createdAt"`
+	ExpiresAt  time.Time                  `json:"expiresAt"`
+	Active     bool                       `json:"active"`
+}
+
+// ExportedAuditEntry is an audit log entry about or performed by an exported user.
+type ExportedAuditEntry struct {
+	ID        entities.AuditLogID    `json:"id"`
+	ActorID   entities.UserID        `json:"actorId"`
+	UserID    entities.UserID        `json:"userId"`
+	Action    string                 `json:"action"`
+	Changes   []entities.FieldChange `json:"changes"`
+	IPAddress string                 `json:"ipAddress"`
+	CreatedAt time.Time              `json:"createdAt"`
+}
+
+// ExportedLoginAttempt is a login attempt on the account of an exported user.
+type ExportedLoginAttempt struct {
+	ID        entities.LoginAttemptID `json:"id"`
+	IPAddress string                  `json:"ipAddress"`
+	UserAgent string                  `json:"userAgent"`
+	Method    string                  `json:"method"`
+	Failure   string                  `json:"failure,omitempty"`
+	CreatedAt time.Time               `json:"createdAt"`
+}
+
+// ExportedIdentityChange is a change of the email address or username of an
+// exported user. The token is left out.
+type ExportedIdentityChange struct {
+	ID        entities.IdentityChangeID `json:"id"`
+	Field     string                    `json:"field"`
+	OldValue  string                    `json:"oldValue"`
+	NewValue  string                    `json:"newValue"`
+	Status    string                    `json:"status"`
+	ExpiresAt time.Time                 `json:"expiresAt"`
+	CreatedAt time.Time                 `json:"createdAt"`
+	UpdatedAt time.Time                 `json:"updatedAt"`
+}
+
+// ExportedModeration holds the moderation actions against an exported user
+// and the appeals the user made.
+type ExportedModeration struct {
+	Actions []ExportedModerationAction `json:"actions"`
+	Appeals []ExportedAppeal           `json:"appeals"`
+}
+
+// ExportedModerationAction is a flag or suspension of an exported user.
+type ExportedModerationAction struct {
+	ID        entities.ModerationActionID `json:"id"`
+	Kind      string                      `json:"kind"`
+	Reason    string                      `json:"reason"`
+	EndsAt    *time.Time                  `json:"endsAt,omitempty"`
+	LiftedAt  *time.Time                  `json:"liftedAt,omitempty"`
+	CreatedAt time.Time                   `json:"createdAt"`
+}
+
+// ExportedAppeal is an appeal of a suspension by an exported user.
+type ExportedAppeal struct {
+	ID        entities.AppealID           `json:"id"`
+	ActionID  entities.ModerationActionID `json:"actionId"`
+	Statement string                      `json:"statement"`
+	Status    string                      `json:"status"`
+	Response  string                      `json:"response,omitempty"`
+	CreatedAt time.Time                   `json:"createdAt"`
+	DecidedAt *time.Time                  `json:"decidedAt,omitempty"`
+}
+
+// ExportedActivityEntry is an entry of the activity feed of an exported user.
+type ExportedActivityEntry struct {
+	ID         entities.ActivityID `json:"id"`
+	Kind       string              `json:"kind"`
+	Summary    string              `json:"summary"`
+	OccurredAt time.Time           `json:"occurredAt"`
+}
+
+// ExportedStreamEvent is an event in the stream of an exported event-sourced
+// user. Password hashes are left out.
+type ExportedStreamEvent struct {
+	Version    int64                  `json:"version"`
+	Type       string                 `json:"type"`
+	Data       entities.UserEventData `json:"data"`
+	OccurredAt time.Time              `json:"occurredAt"`
+}
+
+// ExportUserData collects the profile, sessions, events, audit entries,
+// login history, identity changes, moderation, activity, preferences and
+// event stream of a user into an archive that marshals to JSON. Each of the
+// optional sections is only filled when the service has its repository.
+func (s *UserService) ExportUserData(
+	ctx context.Context,
+	userID entities.UserID,
+) (_ *UserDataExport, err error) {
+	ctx, end := s.startSpan(ctx, "ExportUserData")
+	defer end(&err)
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("user %s not found: %w", userID, err)
+	}
+
+	sessions, err := s.sessionRepo.GetByUserID(ctx, userID, false)
+	if err != nil {
+		return nil, fmt.Errorf("export sessions of user %s: %w", userID, err)
+	}
+
+	userEvents, err := s.eventsAbout(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("export events of user %s: %w", userID, err)
+	}
+
+	auditLog, err := s.auditEntriesOf(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("export audit log of user %s: %w", userID, err)
+	}
+
+	export := &UserDataExport{
+		ExportedAt: time.Now().UTC(),
+		Profile:    exportProfile(user),
+		Sessions:   make([]ExportedSession, 0, len(sessions)),
+		Events:     userEvents,
+		AuditLog:   auditLog,
+	}
+
+	for _, session := range sessions {
+		export.Sessions = append(export.Sessions, exportSession(session))
+	}
+
+	err = s.exportAccountHistory(ctx, userID, export)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.exportUserContent(ctx, userID, export)
+	if err != nil {
+		return nil, err
+	}
+
+	s.recordAudit(ctx, entities.AuditActionDataExport, userID, nil)
+
+	actor, _ := AuditActorFromContext(ctx)
+	s.publishEvent(ctx, events.UserDataExported(userID, actor.UserID))
+
+	return export, nil
+}
+
+// EraseUser anonymizes the personal data of a user in one transaction: the
+// profile is replaced with placeholders and soft deleted, the password made
+// unusable, the sessions, linked identities, login history, identity
+// changes, moderation actions and appeals, activity, preferences, event
+// stream and archived versions deleted, and the audit entries scrubbed.
+// Soft-deleted users can be erased too. Events published before the erasure
+// are not changed.
+func (s *UserService) EraseUser(ctx context.Context, userID entities.UserID) (err error) {
+	ctx, end := s.startSpan(ctx, "EraseUser")
+	defer end(&err)
+
+	err = s.checkSwitches(ctx, entities.SwitchReadOnly)
+	if err != nil {
+		return err
+	}
+
+	if s.txRepo == nil {
+		return ErrTransactionsUnavailable
+	}
+
+	var avatar entities.UserAvatar
+
+	err = s.txRepo.RunInTransaction(ctx, func(ctx context.Context, tx repositories.Transaction) error {
+		erased, err := eraseUser(ctx, tx, userID)
+		avatar = erased
+
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to erase user %s: %w", userID, err)
+	}
+
+	actor, _ := AuditActorFromContext(ctx)
+	s.completeErasure(ctx, userID, avatar, actor.UserID)
+
+	return nil
+}
+
+// completeErasure deletes the avatar of an erased user and records the
+// committed erasure on behalf of erasedBy.
+func (s *UserService) completeErasure(
+	ctx context.Context,
+	userID entities.UserID,
+	avatar entities.UserAvatar,
+	erasedBy entities.UserID,
+) {
+	// Blobs cannot be rolled back, so the avatar is deleted once the erasure
+	// is committed.
+	s.deleteAvatar(ctx, userID, avatar.Key)
+
+	// The erasure itself is recorded without the IP address, which may be
+	// the erased user's.
+	s.recordAudit(ContextWithAuditActor(ctx, AuditActor{UserID: erasedBy}), entities.AuditActionUserErase, userID, nil)
+	s.publishEvent(ctx, events.UserErased(userID, erasedBy))
+}
+
+// eraseUser anonymizes the data of userID with the repositories of tx and
+// returns the avatar the user had. The event stream and the archived
+// versions are deleted last, since changing the user appends to the one and
+// archives into the other.
+func eraseUser(
+	ctx context.Context,
+	tx repositories.Transaction,
+	userID entities.UserID,
+) (entities.UserAvatar, error) {
+	users := tx.UserRepository()
+
+	user, err := users.GetByID(ctx, userID)
+	if errors.Is(err, entities.ErrUserNotFound) {
+		// Soft-deleted users are restored for the update and deleted again below.
+		err = users.Restore(ctx, userID)
+		if err == nil {
+			user, err = users.GetByID(ctx, userID)
+		}
+	}
+
+	if err != nil {
+		return entities.UserAvatar{}, fmt.Errorf("user %s not found: %w", userID, err)
+	}
+
+	avatar := user.Avatar()
+
+	user.Anonymize()
+
+	err = users.Update(ctx, user)
+	if err != nil {
+		return entities.UserAvatar{}, fmt.Errorf("anonymize user: %w", err)
+	}
+
+	err = users.UpdatePassword(ctx, userID, entities.NewUnusablePasswordHash())
+	if err != nil {
+		return entities.UserAvatar{}, fmt.Errorf("reset password: %w", err)
+	}
+
+	err = users.Delete(ctx, userID)
+	if err != nil {
+		return entities.UserAvatar{}, fmt.Errorf("delete user: %w", err)
+	}
+
+	err = deleteSessions(ctx, tx.SessionRepository(), userID)
+	if err != nil {
+		return entities.UserAvatar{}, err
+	}
+
+	if identities := tx.IdentityRepository(); identities != nil {
+		err = unlinkIdentities(ctx, identities, userID)
+		if err != nil {
+			return entities.UserAvatar{}, err
+		}
+	}
+
+	if audit := tx.AuditRepository(); audit != nil {
+		_, err = audit.AnonymizeUser(ctx, userID)
+		if err != nil {
+			return entities.UserAvatar{}, fmt.Errorf("anonymize audit log: %w", err)
+		}
+	}
+
+	err = eraseAccountHistory(ctx, tx, userID)
+	if err != nil {
+		return entities.UserAvatar{}, err
+	}
+
+	// Erasing an account also settles its pending deletion.
+	if deletions := tx.AccountDeletionRepository(); deletions != nil {
+		err = deletions.Delete(ctx, userID)
+		if err != nil && !errors.Is(err, entities.ErrAccountDeletionNotFound) {
+			return entities.UserAvatar{}, fmt.Errorf("delete pending account deletion: %w", err)
+		}
+	}
+
+	// Without its stream an event-sourced user is read from the anonymized
+	// read model.
+	if streams := tx.UserEventStore(); streams != nil {
+		err = streams.DeleteStream(ctx, userID)
+		if err != nil {
+			return entities.UserAvatar{}, fmt.Errorf("delete user event stream: %w", err)
+		}
+	}
+
+	if history := tx.UserHistoryRepository(); history != nil {
+		_, err = history.DeleteVersions(ctx, userID)
+		if err != nil {
+			return entities.UserAvatar{}, fmt.Errorf("delete user versions: %w", err)
+		}
+	}
+
+	return avatar, nil
+}
+
+// eraseAccountHistory deletes the login history, identity changes,
+// moderation actions and appeals, activity and preferences of userID with
+// the repositories of tx that the engine stores.
+func eraseAccountHistory(ctx context.Context, tx repositories.Transaction, userID entities.UserID) error {
+	if logins := tx.LoginHistoryRepository(); logins != nil {
+		_, err := logins.DeleteByUser(ctx, userID)
+		if err != nil {
+			return fmt.Errorf("delete login history: %w", err)
+		}
+	}
+
+	if changes := tx.IdentityChangeRepository(); changes != nil {
+		_, err := changes.DeleteByUser(ctx, userID)
+		if err != nil {
+			return fmt.Errorf("delete identity changes: %w", err)
+		}
+	}
+
+	if moderation := tx.ModerationRepository(); moderation != nil {
+		_, err := moderation.DeleteByUser(ctx, userID)
+		if err != nil {
+			return fmt.Errorf("delete moderation actions: %w", err)
+		}
+	}
+
+	if activity := tx.ActivityRepository(); activity != nil {
+		_, err := activity.DeleteByUser(ctx, userID)
+		if err != nil {
+			return fmt.Errorf("delete activity: %w", err)
+		}
+	}
+
+	if preferences := tx.UserPreferenceRepository(); preferences != nil {
+		err := preferences.Delete(ctx, userID)
+		if err != nil {
+			return fmt.Errorf("delete preferences: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// deleteSessions deletes every session of userID, including inactive ones.
+func deleteSessions(ctx context.Context, sessions repositories.SessionRepository, userID entities.UserID) error {
+	all, err := sessions.GetByUserID(ctx, userID, false)
+	if err != nil {
+		return fmt.Errorf("list sessions: %w", err)
+	}
+
+	for _, session := range all {
+		err = sessions.Delete(ctx, session.ID())
+		if err != nil {
+			return fmt.Errorf("delete session %s: %w", session.ID(), err)
+		}
+	}
+
+	return nil
+}
+
+// unlinkIdentities deletes every external identity linked to userID.
+func unlinkIdentities(ctx context.Context, identities repositories.IdentityRepository, userID entities.UserID) error {
+	linked, err := identities.ListByUser(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("list identities: %w", err)
+	}
+
+	for _, identity := range linked {
+		err = identities.Delete(ctx, userID, identity.Provider)
+		if err != nil {
+			return fmt.Errorf("unlink identity provider=%v: %w", identity.Provider, err)
+		}
+	}
+
+	return nil
+}
+
+// eventsAbout returns the stored events about userID in the order they
+// were appended.
+func (s *UserService) eventsAbout(ctx context.Context, userID entities.UserID) ([]*events.UserEvent, error) {
+	found := make([]*events.UserEvent, 0)
+	if s.eventStore == nil {
+		return found, nil
+	}
+
+	var after int64
+
+	for {
+		batch, err := s.eventStore.ReadFrom(ctx, after, exportPageSize)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, stored := range batch {
+			if stored.Event.UserID == userID {
+				found = append(found, stored.Event)
+			}
+		}
+
+		if len(batch) < exportPageSize {
+			return found, nil
+		}
+
+		after = batch[len(batch)-1].Position
+	}
+}
+
+// auditEntriesOf returns the audit entries about or performed by userID,
+// newest first.
+func (s *UserService) auditEntriesOf(ctx context.Context, userID entities.UserID) ([]ExportedAuditEntry, error) {
+	found := make([]ExportedAuditEntry, 0)
+	if s.audit == nil {
+		return found, nil
+	}
+
+	seen := make(map[entities.AuditLogID]bool)
+
+	for _, filter := range []entities.AuditFilter{{UserID: userID}, {ActorID: userID}} {
+		filter.Limit = exportPageSize
+
+		for {
+			entries, err := s.audit.List(ctx, filter)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, entry := range entries {
+				if !seen[entry.ID] {
+					seen[entry.ID] = true
+
+					found = append(found, exportAuditEntry(entry))
+				}
+			}
+
+			if len(entries) < exportPageSize {
+				break
+			}
+
+			filter.Offset += exportPageSize
+		}
+	}
+
+	slices.SortFunc(found, func(a, b ExportedAuditEntry) int {
+		return cmp.Or(b.CreatedAt.Compare(a.CreatedAt), cmp.Compare(b.ID, a.ID))
+	})
+
+	return found, nil
+}
+
+// exportAccountHistory fills the login history, identity changes and
+// moderation sections of export.
+func (s *UserService) exportAccountHistory(ctx context.Context, userID entities.UserID, export *UserDataExport) error {
+	export.LoginHistory = make([]ExportedLoginAttempt, 0)
+	export.IdentityChanges = make([]ExportedIdentityChange, 0)
+	export.Moderation = ExportedModeration{
+		Actions: make([]ExportedModerationAction, 0),
+		Appeals: make([]ExportedAppeal, 0),
+	}
+
+	if s.loginHistory != nil {
+		attempts, err := s.loginHistory.ListByUser(ctx, userID, exportLoginAttempts)
+		if err != nil {
+			return fmt.Errorf("export login history of user %s: %w", userID, err)
+		}
+
+		for _, attempt := range attempts {
+			export.LoginHistory = append(export.LoginHistory, exportLoginAttempt(attempt))
+		}
+	}
+
+	if s.identityChanges != nil {
+		changes, err := s.identityChanges.ListByUser(ctx, userID)
+		if err != nil {
+			return fmt.Errorf("export identity changes of user %s: %w", userID, err)
+		}
+
+		for _, change := range changes {
+			export.IdentityChanges = append(export.IdentityChanges, exportIdentityChange(change))
+		}
+	}
+
+	if s.moderation != nil {
+		actions, err := s.moderation.ListActionsByUser(ctx, userID)
+		if err != nil {
+			return fmt.Errorf("export moderation actions of user %s: %w", userID, err)
+		}
+
+		for _, action := range actions {
+			export.Moderation.Actions = append(export.Moderation.Actions, exportModerationAction(action))
+		}
+
+		appeals, err := s.moderation.ListAppealsByUser(ctx, userID)
+		if err != nil {
+			return fmt.Errorf("export appeals of user %s: %w", userID, err)
+		}
+
+		for _, appeal := range appeals {
+			export.Moderation.Appeals = append(export.Moderation.Appeals, exportAppeal(appeal))
+		}
+	}
+
+	return nil
+}
+
+// exportUserContent fills the activity, preferences and event stream
+// sections of export.
+func (s *UserService) exportUserContent(ctx context.Context, userID entities.UserID, export *UserDataExport) error {
+	activity, err := s.activityOf(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("export activity of user %s: %w", userID, err)
+	}
+
+	export.Activity = activity
+	export.Preferences = make(map[string]any)
+	export.Stream = make([]ExportedStreamEvent, 0)
+
+	if s.preferences != nil {
+		prefs, err := s.preferences.Get(ctx, userID)
+		if err != nil {
+			return fmt.Errorf("export preferences of user %s: %w", userID, err)
+		}
+
+		for key, value := range prefs.Values {
+			export.Preferences[string(key)] = value
+		}
+	}
+
+	if s.userStreams != nil {
+		stream, err := s.userStreams.Load(ctx, userID, 0)
+		if err != nil {
+			return fmt.Errorf("export event stream of user %s: %w", userID, err)
+		}
+
+		for _, event := range stream {
+			export.Stream = append(export.Stream, exportStreamEvent(event))
+		}
+	}
+
+	return nil
+}
+
+// activityOf returns the activity feed of userID, newest first.
+func (s *UserService) activityOf(ctx context.Context, userID entities.UserID) ([]ExportedActivityEntry, error) {
+	found := make([]ExportedActivityEntry, 0)
+	if s.activity == nil {
+		return found, nil
+	}
+
+	var cursor string
+
+	for {
+		page, err := s.activity.ListPage(ctx, userID, cursor, exportPageSize)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, entry := range page.Entries {
+			found = append(found, ExportedActivityEntry{
+				ID:         entry.ID,
+				Kind:       entry.Kind.String(),
+				Summary:    entry.Summary,
+				OccurredAt: entry.OccurredAt,
+			})
+		}
+
+		if page.NextCursor == "" {
+			return found, nil
+		}
+
+		cursor = page.NextCursor
+	}
+}
+
+// exportProfile converts user for an export.
+func exportProfile(user *entities.User) ExportedProfile {
+	return ExportedProfile{
+		ID:          user.ID(),
+		UUID:        user.UUID().String(),
+		Email:       user.Email().String(),
+		Username:    user.Username().String(),
+		FirstName:   user.FirstName().String(),
+		LastName:    user.LastName().String(),
+		Status:      user.Status().String(),
+		Role:        user.Role().String(),
+		Verified:    user.IsVerified(),
+		Tags:        user.Tags(),
+		Metadata:    user.Metadata(),
+		CreatedAt:   user.CreatedAt(),
+		UpdatedAt:   user.UpdatedAt(),
+		LastLoginAt: user.LastLoginAt(),
+	}
+}
+
+// exportSession converts session for an export.
+func exportSession(session *entities.UserSession) ExportedSession {
+	return ExportedSession{
+		ID:         session.ID(),
+		IPAddress:  session.IPAddress().String(),
+		UserAgent:  session.UserAgent(),
+		DeviceInfo: session.DeviceInfo(),
+		CreatedAt:  session.CreatedAt(),
+		ExpiresAt:  session.ExpiresAt(),
+		Active:     session.IsActive(),
+	}
+}
+
+// exportAuditEntry converts entry for an export.
+func exportAuditEntry(entry *entities.AuditLog) ExportedAuditEntry {
+	return ExportedAuditEntry{
+		ID:        entry.ID,
+		ActorID:   entry.ActorID,
+		UserID:    entry.UserID,
+		Action:    entry.Action.String(),
+		Changes:   entry.Changes,
+		IPAddress: entry.IPAddress,
+		CreatedAt: entry.CreatedAt,
+	}
+}
+
+// exportLoginAttempt converts attempt for an export.
+func exportLoginAttempt(attempt *entities.LoginAttempt) ExportedLoginAttempt {
+	return ExportedLoginAttempt{
+		ID:        attempt.ID,
+		IPAddress: attempt.IPAddress,
+		UserAgent: attempt.UserAgent,
+		Method:    attempt.Method,
+		Failure:   attempt.Failure.String(),
+		CreatedAt: attempt.CreatedAt,
+	}
+}
+
+// exportIdentityChange converts change for an export.
+func exportIdentityChange(change *entities.IdentityChange) ExportedIdentityChange {
+	return ExportedIdentityChange{
+		ID:        change.ID,
+		Field:     change.Field.String(),
+		OldValue:  change.OldValue,
+		NewValue:  change.NewValue,
+		Status:    change.Status.String(),
+		ExpiresAt: change.ExpiresAt,
+		CreatedAt: change.CreatedAt,
+		UpdatedAt: change.UpdatedAt,
+	}
+}
+
+// exportModerationAction converts action for an export.
+func exportModerationAction(action *entities.ModerationAction) ExportedModerationAction {
+	return ExportedModerationAction{
+		ID:        action.ID,
+		Kind:      action.Kind.String(),
+		Reason:    action.Reason,
+		EndsAt:    action.EndsAt,
+		LiftedAt:  action.LiftedAt,
+		CreatedAt: action.CreatedAt,
+	}
+}
+
+// exportAppeal converts appeal for an export.
+func exportAppeal(appeal *entities.Appeal) ExportedAppeal {
+	return ExportedAppeal{
+		ID:        appeal.ID,
+		ActionID:  appeal.ActionID,
+		Statement: appeal.Statement,
+		Status:    appeal.Status.String(),
+		Response:  appeal.Response,
+		CreatedAt: appeal.CreatedAt,
+		DecidedAt: appeal.DecidedAt,
+	}
+}
+
+// exportStreamEvent converts event for an export.
+func exportStreamEvent(event *entities.UserStreamEvent) ExportedStreamEvent {
+	data := event.Data
+	data.Password = nil
+
+	return ExportedStreamEvent{
+		Version:    event.Version,
+		Type:       event.Type.String(),
+		Data:       data,
+		OccurredAt: event.OccurredAt,
+	}
+}
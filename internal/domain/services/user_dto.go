@@ -7,13 +7,20 @@ import "github.com/LarsArtmann/template-sqlc/internal/domain/entities"
 type CreateUserRequest struct {
 	Email        string         `json:"email"        validate:"required,email"`
 	Username     string         `json:"username"     validate:"required,min=3,max=50"`
-	PasswordHash string         `json:"passwordHash" validate:"required"`
+	Password     string         `json:"password"     validate:"required_without=PasswordHash"`
+	PasswordHash string         `json:"passwordHash" validate:"required_without=Password"`
 	FirstName    string         `json:"firstName"    validate:"required"`
 	LastName     string         `json:"lastName"     validate:"required"`
 	Status       string         `json:"status"       validate:"required"`
 	Role         string         `json:"role"         validate:"required"`
 	Tags         []string       `json:"tags"`
 	Metadata     map[string]any `json:"metadata"`
+	// Source is where the user signed up, such as an identity provider;
+	// empty for direct signups. It is carried by the user.created event.
+	Source string `json:"source,omitempty"`
+	// IdempotencyKey makes retries of the request create the user once; see
+	// WithIdempotency.
+	IdempotencyKey string `json:"-"`
 }
 
 // UpdateUserRequest represents a request to update a user.
@@ -24,4 +31,7 @@ type UpdateUserRequest struct {
 	Metadata  *map[string]any `json:"metadata,omitempty"`
 	Tags      *[]string       `json:"tags,omitempty"`
 	UpdatedBy string          `json:"updatedBy"           validate:"required"`
+	// IdempotencyKey makes retries of the request replay the first update;
+	// see WithIdempotency.
+	IdempotencyKey string `json:"-"`
 }
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// UserHistoryService answers point-in-time questions about user records.
+type UserHistoryService struct {
+	history repositories.UserHistoryRepository
+}
+
+// NewUserHistoryService creates a new user history service.
+func NewUserHistoryService(history repositories.UserHistoryRepository) *UserHistoryService {
+	return &UserHistoryService{history: history}
+}
+
+// GetUserAsOf returns what the user looked like at the given time.
+func (s *UserHistoryService) GetUserAsOf(
+	ctx context.Context,
+	userID entities.UserID,
+	at time.Time,
+) (*entities.UserSnapshot, error) {
+	snapshot, err := s.history.GetUserAsOf(ctx, userID, at)
+	if err != nil {
+		return nil, fmt.Errorf("user %s as of %s: %w", userID, at.Format(time.RFC3339), err)
+	}
+
+	return snapshot, nil
+}
+
+// DiffBetween returns the fields of a user that changed between two points in time.
+func (s *UserHistoryService) DiffBetween(
+	ctx context.Context,
+	userID entities.UserID,
+	from, to time.Time,
+) ([]entities.FieldChange, error) {
+	if to.Before(from) {
+		return nil, entities.NewValidationError("to", "must not be before from")
+	}
+
+	before, err := s.GetUserAsOf(ctx, userID, from)
+	if err != nil {
+		return nil, err
+	}
+
+	after, err := s.GetUserAsOf(ctx, userID, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return before.Diff(after), nil
+}
+
+// ListVersions returns archived versions of a user, newest first.
+func (s *UserHistoryService) ListVersions(
+	ctx context.Context,
+	userID entities.UserID,
+	limit int,
+) ([]*entities.UserSnapshot, error) {
+	if limit <= 0 {
+		return nil, entities.NewValidationError("limit", "must be positive")
+	}
+
+	versions, err := s.history.ListVersions(ctx, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list versions of user %s: %w", userID, err)
+	}
+
+	return versions, nil
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// UserPreferenceService reads and updates the preferences of users,
+// validating every update against its preference schema.
+type UserPreferenceService struct {
+	prefs  repositories.UserPreferenceRepository
+	schema entities.PreferenceSchema
+}
+
+// UserPreferenceServiceOption configures a UserPreferenceService.
+type UserPreferenceServiceOption func(*UserPreferenceService)
+
+// WithPreferenceDefinitions adds preferences to the schema, replacing the
+// definitions with the same keys.
+func WithPreferenceDefinitions(defs ...entities.PreferenceDefinition) UserPreferenceServiceOption {
+	return func(s *UserPreferenceService) {
+		for _, def := range defs {
+			s.schema = slices.DeleteFunc(s.schema, func(d entities.PreferenceDefinition) bool {
+				return d.Key == def.Key
+			})
+			s.schema = append(s.schema, def)
+		}
+	}
+}
+
+// NewUserPreferenceService creates a user preference service storing
+// preferences in prefs, with the entities.DefaultPreferenceSchema.
+func NewUserPreferenceService(
+	prefs repositories.UserPreferenceRepository,
+	opts ...UserPreferenceServiceOption,
+) *UserPreferenceService {
+	s := &UserPreferenceService{
+		prefs:  prefs,
+		schema: entities.DefaultPreferenceSchema(),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Schema returns the preferences users can set.
+func (s *UserPreferenceService) Schema() entities.PreferenceSchema {
+	return slices.Clone(s.schema)
+}
+
+// Get returns the preferences of a user.
+func (s *UserPreferenceService) Get(ctx context.Context, userID entities.UserID) (*entities.UserPreferences, error) {
+	prefs, err := s.prefs.Get(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("load preferences user=%v: %w", userID, err)
+	}
+
+	return prefs.WithSchema(s.schema), nil
+}
+
+// Update applies patch to the preferences of a user and returns the result.
+// Unknown keys are rejected with ErrUnknownPreference and values that do
+// not match their definition with ErrInvalidPreferenceValue.
+func (s *UserPreferenceService) Update(
+	ctx context.Context,
+	userID entities.UserID,
+	patch entities.PreferencePatch,
+) (*entities.UserPreferences, error) {
+	patch, err := s.schema.Validate(patch)
+	if err != nil {
+		return nil, err
+	}
+
+	if patch.IsEmpty() {
+		return s.Get(ctx, userID)
+	}
+
+	prefs, err := s.prefs.Patch(ctx, userID, patch)
+	if err != nil {
+		return nil, fmt.Errorf("update preferences user=%v: %w", userID, err)
+	}
+
+	return prefs.WithSchema(s.schema), nil
+}
@@ -0,0 +1,282 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// rebuildBatchSize is the page size users are read and projected with when
+// the read model is rebuilt.
+const rebuildBatchSize = 500
+
+// ReadModelObserver is notified how long events took to be projected into
+// the user read model, e.g. to export it as a metric.
+type ReadModelObserver interface {
+	ObserveReadModelLag(lag time.Duration)
+}
+
+// UserProjectionOption configures a UserProjection.
+type UserProjectionOption func(*UserProjection)
+
+// WithReadModelObserver reports the projection lag of every event to observer.
+func WithReadModelObserver(observer ReadModelObserver) UserProjectionOption {
+	return func(p *UserProjection) {
+		p.observer = observer
+	}
+}
+
+// UserProjection is an events.EventHandler that keeps the user read model in
+// sync with the users the events are about. Like the search index sync, it
+// reloads the users rather than reading the event payloads, so that
+// redelivered and reordered events never project stale data.
+type UserProjection struct {
+	views    repositories.UserReadModelRepository
+	users    repositories.UserRepository
+	observer ReadModelObserver
+	now      func() time.Time
+}
+
+var _ events.EventHandler = (*UserProjection)(nil)
+
+// NewUserProjection creates a projection of the users of users into views.
+func NewUserProjection(
+	views repositories.UserReadModelRepository,
+	users repositories.UserRepository,
+	opts ...UserProjectionOption,
+) *UserProjection {
+	projection := &UserProjection{views: views, users: users, now: time.Now}
+
+	for _, opt := range opts {
+		opt(projection)
+	}
+
+	return projection
+}
+
+// EventTypes returns the event types that change fields of the read model,
+// to subscribe the projection with.
+func (p *UserProjection) EventTypes() []events.EventType {
+	return []events.EventType{
+		events.EventUserCreated,
+		events.EventUserUpdated,
+		events.EventUserDeleted,
+		events.EventUserRestored,
+		events.EventUserActivated,
+		events.EventUserDeactivated,
+		events.EventUserSuspended,
+		events.EventUserReinstated,
+		events.EventUserVerified,
+		events.EventProfileUpdated,
+		events.EventRoleChanged,
+		events.EventEmailChanged,
+		events.EventUsernameChanged,
+		events.EventIdentityChangeReverted,
+		events.EventUsersBulkUpdated,
+		events.EventUserErased,
+	}
+}
+
+// Handle projects the users event is about. Events of other types are ignored.
+func (p *UserProjection) Handle(ctx context.Context, event *events.UserEvent) error {
+	if !slices.Contains(p.EventTypes(), event.Type) {
+		return nil
+	}
+
+	ids := []entities.UserID{event.UserID}
+
+	if event.Type == events.EventUsersBulkUpdated {
+		var err error
+
+		ids, err = decodeBulkUserIDs(event)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := p.Sync(ctx, ids...)
+	if err != nil {
+		return err
+	}
+
+	if p.observer != nil && !event.Timestamp.IsZero() {
+		p.observer.ObserveReadModelLag(max(p.now().Sub(event.Timestamp), 0))
+	}
+
+	return nil
+}
+
+// Sync projects the users with ids as stored now and removes the views of
+// those that were deleted.
+func (p *UserProjection) Sync(ctx context.Context, ids ...entities.UserID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	users, err := p.users.GetByIDs(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("load %d users to project: %w", len(ids), err)
+	}
+
+	err = p.project(ctx, users)
+	if err != nil {
+		return err
+	}
+
+	removed := slices.DeleteFunc(slices.Clone(ids), func(id entities.UserID) bool {
+		return slices.ContainsFunc(users, func(user *entities.User) bool { return user.ID() == id })
+	})
+
+	err = p.views.Remove(ctx, removed...)
+	if err != nil {
+		return fmt.Errorf("remove %d user views: %w", len(removed), err)
+	}
+
+	return nil
+}
+
+// Rebuild projects every live user and returns how many were projected, to
+// fill the read model of users that existed before it was enabled. Views of
+// deleted users are not removed.
+func (p *UserProjection) Rebuild(ctx context.Context) (int64, error) {
+	var (
+		projected int64
+		cursor    string
+	)
+
+	for {
+		page, err := p.users.ListPage(ctx, entities.UserFilter{}, cursor, rebuildBatchSize)
+		if err != nil {
+			return projected, fmt.Errorf("list users after cursor=%q: %w", cursor, err)
+		}
+
+		err = p.project(ctx, page.Users)
+		if err != nil {
+			return projected, err
+		}
+
+		projected += int64(len(page.Users))
+
+		if page.NextCursor == "" {
+			return projected, nil
+		}
+
+		cursor = page.NextCursor
+	}
+}
+
+// project stores the views of users.
+func (p *UserProjection) project(ctx context.Context, users []*entities.User) error {
+	projectedAt := p.now()
+	views := make([]*entities.UserView, 0, len(users))
+
+	for _, user := range users {
+		views = append(views, entities.NewUserView(user, projectedAt))
+	}
+
+	err := p.views.Upsert(ctx, views...)
+	if err != nil {
+		return fmt.Errorf("project %d users: %w", len(views), err)
+	}
+
+	return nil
+}
+
+// decodeBulkUserIDs returns the users of a users.bulk_updated event. Payloads
+// arrive as structs from in-process publishers and as raw JSON from brokers,
+// so both are decoded through JSON.
+func decodeBulkUserIDs(event *events.UserEvent) ([]entities.UserID, error) {
+	payload, ok := event.Data.(json.RawMessage)
+	if !ok {
+		var err error
+
+		payload, err = json.Marshal(event.Data)
+		if err != nil {
+			return nil, fmt.Errorf("encode event id=%v data: %w", event.ID, err)
+		}
+	}
+
+	var data events.UsersBulkUpdatedEvent
+
+	err := json.Unmarshal(payload, &data)
+	if err != nil {
+		return nil, fmt.Errorf("decode event id=%v data: %w", event.ID, err)
+	}
+
+	return data.UserIDs, nil
+}
+
+// UserDirectory serves user listings and searches from the read model, so
+// that they do not load the users table. Its results may lag behind writes,
+// which keep going through the UserService.
+type UserDirectory struct {
+	views repositories.UserReadModelRepository
+}
+
+// NewUserDirectory creates a directory serving the views of views.
+func NewUserDirectory(views repositories.UserReadModelRepository) *UserDirectory {
+	return &UserDirectory{views: views}
+}
+
+// ListUsers returns a page of the views of users matching filter, newest first.
+func (d *UserDirectory) ListUsers(
+	ctx context.Context,
+	filter entities.UserFilter,
+	limit, offset int,
+) ([]*entities.UserView, error) {
+	err := filter.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	views, err := d.views.List(ctx, filter, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list user views limit=%v offset=%v: %w", limit, offset, err)
+	}
+
+	return views, nil
+}
+
+// SearchUsers returns up to limit views of users whose email, username or
+// name has a word starting with each term of query, newest first. An empty
+// status matches users of any status.
+func (d *UserDirectory) SearchUsers(
+	ctx context.Context,
+	query string,
+	status entities.UserStatus,
+	limit int,
+) ([]*entities.UserView, error) {
+	filter := entities.UserFilter{Query: query}
+
+	if status != "" {
+		if !status.IsValid() {
+			return nil, fmt.Errorf("status=%v: %w", status, entities.ErrInvalidUserStatus)
+		}
+
+		filter.Statuses = []entities.UserStatus{status}
+	}
+
+	views, err := d.views.List(ctx, filter, limit, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search user views query=%v: %w", query, err)
+	}
+
+	return views, nil
+}
+
+// Lag returns how long the oldest change not yet projected into the read
+// model has been waiting, or 0 if the read model is current.
+func (d *UserDirectory) Lag(ctx context.Context) (time.Duration, error) {
+	lag, err := d.views.Lag(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get user read model lag: %w", err)
+	}
+
+	return lag, nil
+}
@@ -2,14 +2,15 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net"
+	"time"
 
 	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
 	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
 	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
-	"github.com/google/uuid"
 )
 
 const (
@@ -26,13 +27,102 @@ type UserService struct {
 	sessionRepo repositories.SessionRepository
 	eventPub    events.EventPublisher
 	validator   UserValidator
+	txRepo      repositories.TransactionalRepository
+	bulkChunk   int
+	sessions    entities.SessionPolicy
+	hasher      PasswordHasher
+	tokens      TokenStrategy
+	refresh     entities.RefreshPolicy
+	audit       repositories.AuditRepository
+	eventStore  events.EventStore
+	tracer      Tracer
+
+	idempotency    repositories.IdempotencyRepository
+	idempotencyTTL time.Duration
+
+	loginThrottle LoginThrottle
+
+	impersonation time.Duration
+
+	identityChanges repositories.IdentityChangeRepository
+	identityGrace   time.Duration
+
+	loginHistory repositories.LoginHistoryRepository
+
+	loginRisk bool
+	geo       GeoResolver
+	stepUp    StepUpHook
+
+	avatars AvatarStore
+
+	moderation repositories.ModerationRepository
+
+	deletions     repositories.AccountDeletionRepository
+	deletionGrace time.Duration
+
+	// activity, preferences and userStreams are only read by ExportUserData.
+	activity    repositories.ActivityRepository
+	preferences repositories.UserPreferenceRepository
+	userStreams repositories.UserEventStore
+
+	switches *Switchboard
+
+	dummy *dummyHash
+}
+
+// UserServiceOption configures optional UserService collaborators.
+type UserServiceOption func(*UserService)
+
+// WithTransactions enables transactional execution for multi-step operations.
+func WithTransactions(txRepo repositories.TransactionalRepository) UserServiceOption {
+	return func(s *UserService) {
+		s.txRepo = txRepo
+	}
 }
 
-// UserValidator defines validation interface for user operations.
+// WithBulkChunkSize overrides the number of users processed per bulk transaction.
+func WithBulkChunkSize(size int) UserServiceOption {
+	return func(s *UserService) {
+		if size > 0 {
+			s.bulkChunk = size
+		}
+	}
+}
+
+// WithSessionPolicy overrides the sliding session expiration policy.
+// Invalid policies are ignored in favor of the default.
+func WithSessionPolicy(policy entities.SessionPolicy) UserServiceOption {
+	return func(s *UserService) {
+		if policy.Validate() == nil {
+			s.sessions = policy
+		}
+	}
+}
+
+// WithPasswordHasher makes the service hash plaintext passwords on creation
+// and verify them with the hasher on authentication. Without it, users are
+// created from precomputed hashes only and cannot be authenticated.
+func WithPasswordHasher(hasher PasswordHasher) UserServiceOption {
+	return func(s *UserService) {
+		s.hasher = hasher
+	}
+}
+
+// PasswordHasher hashes plaintext passwords and verifies them in constant time.
+// It is implemented by the hashers in pkg/passwords.
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	Verify(hash, password string) error
+}
+
+// UserValidator defines validation interface for user operations. The
+// validations report every field that fails, not only the first.
 type UserValidator interface {
-	ValidateUserCreate(email, username, firstName, lastName string) error
+	ValidateUserCreate(ctx context.Context, email, username, firstName, lastName string) error
+	ValidateEmail(ctx context.Context, email string) error
+	ValidateUsername(username string) error
 	ValidateUserUpdate(user *entities.User) error
-	ValidatePasswordRequirements(password string) error
+	ValidatePasswordRequirements(ctx context.Context, password string) error
 }
 
 // NewUserService creates a new user service.
@@ -41,34 +131,71 @@ func NewUserService(
 	sessionRepo repositories.SessionRepository,
 	eventPub events.EventPublisher,
 	validator UserValidator,
+	opts ...UserServiceOption,
 ) *UserService {
-	return &UserService{
+	service := &UserService{
 		userRepo:    userRepo,
 		sessionRepo: sessionRepo,
 		eventPub:    eventPub,
 		validator:   validator,
+		bulkChunk:   defaultBulkChunkSize,
+		sessions:    entities.DefaultSessionPolicy(),
+		tokens:      UUIDTokenStrategy{},
+		refresh:     entities.DefaultRefreshPolicy(),
+		tracer:      noopTracer{},
+
+		impersonation: entities.SessionDurationImpersonation,
+
+		dummy: &dummyHash{},
 	}
+
+	for _, opt := range opts {
+		opt(service)
+	}
+
+	return service
 }
 
-// publishEvent publishes an event and logs a warning if it fails.
-func (s *UserService) publishEvent(event *events.UserEvent) {
+// publishEvent publishes an event carrying the trace of ctx and logs a
+// warning if it fails.
+func (s *UserService) publishEvent(ctx context.Context, event *events.UserEvent) {
+	event.TraceParent = s.tracer.TraceParent(ctx)
+
 	err := s.eventPub.Publish(event)
 	if err != nil {
 		slog.Warn("failed to publish event", "error", err)
 	}
 }
 
-// CreateUser creates a new user with business logic validation.
+// CreateUser creates a new user with business logic validation. Retries of
+// a request with an IdempotencyKey return the user created first.
 func (s *UserService) CreateUser(
 	ctx context.Context,
 	req *CreateUserRequest,
-) (*entities.User, error) {
-	// Validate request
-	err := s.validator.ValidateUserCreate(
-		req.Email,
-		req.Username,
-		req.FirstName,
-		req.LastName,
+) (_ *entities.User, err error) {
+	ctx, end := s.startSpan(ctx, "CreateUser")
+	defer end(&err)
+
+	err = s.checkSwitches(ctx, entities.SwitchReadOnly, entities.SwitchRegistrationDisabled)
+	if err != nil {
+		return nil, err
+	}
+
+	// Passwords are left out of the request fingerprint.
+	fingerprint := *req
+	fingerprint.Password, fingerprint.PasswordHash = "", ""
+
+	return s.idempotentUser(ctx, req.IdempotencyKey, "CreateUser", fingerprint,
+		func(ctx context.Context) (*entities.User, error) { return s.createUser(ctx, req) })
+}
+
+// createUser validates and stores the user of req.
+func (s *UserService) createUser(ctx context.Context, req *CreateUserRequest) (*entities.User, error) {
+	// Validate request: the errors of the fields and of the password are
+	// reported together.
+	err := errors.Join(
+		s.validator.ValidateUserCreate(ctx, req.Email, req.Username, req.FirstName, req.LastName),
+		s.validatePassword(ctx, req),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("validation failed: %w", err)
@@ -80,8 +207,13 @@ func (s *UserService) CreateUser(
 		return nil, err
 	}
 
+	passwordHash, err := s.resolvePasswordHash(req)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create domain entities
-	domainEntities, err := s.createDomainEntities(req)
+	domainEntities, err := s.createDomainEntities(req, passwordHash)
 	if err != nil {
 		return nil, err
 	}
@@ -108,8 +240,10 @@ func (s *UserService) CreateUser(
 		return nil, fmt.Errorf("failed to save user: %w", err)
 	}
 
+	s.recordAudit(ctx, entities.AuditActionUserCreate, user.ID(), auditDiff(nil, user))
+
 	// Publish event (non-blocking)
-	s.publishUserCreatedEvent(user, domainEntities)
+	s.publishUserCreatedEvent(ctx, user, domainEntities, req.Source)
 
 	return user, nil
 }
@@ -135,6 +269,36 @@ func (s *UserService) checkUserNotExists(ctx context.Context, email, username st
 	return nil
 }
 
+// CheckUsernameAvailability reports whether username can be registered. It
+// returns the validation errors of a malformed, reserved or offensive
+// username and ErrUserAlreadyExists for a taken one.
+func (s *UserService) CheckUsernameAvailability(ctx context.Context, username string) (err error) {
+	ctx, end := s.startSpan(ctx, "CheckUsernameAvailability")
+	defer end(&err)
+
+	if s.validator != nil {
+		err = s.validator.ValidateUsername(username)
+		if err != nil {
+			return fmt.Errorf("validation failed: %w", err)
+		}
+	}
+
+	validUsername, err := entities.NewUsername(username)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.userRepo.GetByUsername(ctx, validUsername)
+	switch {
+	case err == nil:
+		return fmt.Errorf("username=%v: %w", validUsername, entities.ErrUserAlreadyExists)
+	case entities.IsNotFoundError(err):
+		return nil
+	default:
+		return fmt.Errorf("look up username=%v: %w", validUsername, err)
+	}
+}
+
 // domainEntities holds created domain value objects.
 type domainEntities struct {
 	Email        entities.Email
@@ -145,7 +309,10 @@ type domainEntities struct {
 }
 
 // createDomainEntities creates domain value objects from request.
-func (s *UserService) createDomainEntities(req *CreateUserRequest) (*domainEntities, error) {
+func (s *UserService) createDomainEntities(
+	req *CreateUserRequest,
+	rawPasswordHash string,
+) (*domainEntities, error) {
 	email, err := entities.NewEmail(req.Email)
 	if err != nil {
 		return nil, fmt.Errorf("invalid email: %w", err)
@@ -166,7 +333,7 @@ func (s *UserService) createDomainEntities(req *CreateUserRequest) (*domainEntit
 		return nil, fmt.Errorf("invalid last name: %w", err)
 	}
 
-	passwordHash, err := entities.NewPasswordHash(req.PasswordHash)
+	passwordHash, err := entities.NewPasswordHash(rawPasswordHash)
 	if err != nil {
 		return nil, fmt.Errorf("invalid password hash: %w", err)
 	}
@@ -180,23 +347,62 @@ func (s *UserService) createDomainEntities(req *CreateUserRequest) (*domainEntit
 	}, nil
 }
 
-// publishUserCreatedEvent publishes user created event (non-blocking).
-func (s *UserService) publishUserCreatedEvent(user *entities.User, created *domainEntities) {
-	event := events.UserCreated(
-		user.ID(),
-		created.Email.String(),
-		created.Username.String(),
-		created.FirstName.String(),
-		created.LastName.String(),
-		user.Role().String(),
-		user.Status().String(),
-	)
+// validatePassword checks the plaintext password of req, if any, against
+// the password requirements.
+func (s *UserService) validatePassword(ctx context.Context, req *CreateUserRequest) error {
+	if req.Password == "" || s.hasher == nil {
+		return nil
+	}
+
+	return s.validator.ValidatePasswordRequirements(ctx, req.Password)
+}
+
+// resolvePasswordHash returns the hash to store for a new user.
+// A plaintext password, validated by createUser, is hashed when a hasher is
+// configured; otherwise the caller must supply a pre-computed hash.
+func (s *UserService) resolvePasswordHash(req *CreateUserRequest) (string, error) {
+	if req.Password == "" {
+		return req.PasswordHash, nil
+	}
+
+	if s.hasher == nil {
+		return "", entities.NewValidationError("password", "no password hasher configured")
+	}
 
-	s.publishEvent(event)
+	hash, err := s.hasher.Hash(req.Password)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	return hash, nil
+}
+
+// publishUserCreatedEvent publishes user created event (non-blocking).
+func (s *UserService) publishUserCreatedEvent(
+	ctx context.Context,
+	user *entities.User,
+	created *domainEntities,
+	source string,
+) {
+	event := events.NewUserEvent(events.EventUserCreated, user.ID(), events.UserCreatedEvent{
+		UserID:    user.ID(),
+		Email:     created.Email.String(),
+		Username:  created.Username.String(),
+		FirstName: created.FirstName.String(),
+		LastName:  created.LastName.String(),
+		Role:      user.Role().String(),
+		Status:    user.Status().String(),
+		Source:    source,
+	})
+
+	s.publishEvent(ctx, event)
 }
 
 // GetUser retrieves a user by ID with business logic checks.
-func (s *UserService) GetUser(ctx context.Context, userID entities.UserID) (*entities.User, error) {
+func (s *UserService) GetUser(ctx context.Context, userID entities.UserID) (_ *entities.User, err error) {
+	ctx, end := s.startSpan(ctx, "GetUser")
+	defer end(&err)
+
 	user, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user %s: %w", userID, err)
@@ -208,16 +414,32 @@ func (s *UserService) GetUser(ctx context.Context, userID entities.UserID) (*ent
 	return user, nil
 }
 
-// UpdateUser updates a user with business logic validation.
+// UpdateUser updates a user with business logic validation. Retries of a
+// request with an IdempotencyKey return the user as updated first.
 func (s *UserService) UpdateUser(
 	ctx context.Context,
 	req *UpdateUserRequest,
-) (*entities.User, error) {
+) (_ *entities.User, err error) {
+	ctx, end := s.startSpan(ctx, "UpdateUser")
+	defer end(&err)
+
+	err = s.checkSwitches(ctx, entities.SwitchReadOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.idempotentUser(ctx, req.IdempotencyKey, "UpdateUser", req,
+		func(ctx context.Context) (*entities.User, error) { return s.updateUser(ctx, req) })
+}
+
+// updateUser applies req to the stored user.
+func (s *UserService) updateUser(ctx context.Context, req *UpdateUserRequest) (*entities.User, error) {
 	user, err := s.userRepo.GetByID(ctx, req.UserID)
 	if err != nil {
 		return nil, fmt.Errorf("user not found: %w", err)
 	}
 
+	before := entities.SnapshotOf(user)
 	changes := s.applyProfileUpdates(user, req)
 
 	err = s.validator.ValidateUserUpdate(user)
@@ -230,9 +452,11 @@ func (s *UserService) UpdateUser(
 		return nil, fmt.Errorf("failed to update user: %w", err)
 	}
 
+	s.recordAudit(ctx, entities.AuditActionUserUpdate, user.ID(), auditDiff(before, user))
+
 	if len(changes) > 0 {
 		event := events.UserUpdated(user.ID(), changes, user.ID())
-		s.publishEvent(event)
+		s.publishEvent(ctx, event)
 	}
 
 	return user, nil
@@ -277,6 +501,14 @@ func (s *UserService) applyProfileUpdates(
 			metadata.Set(k, v)
 		}
 
+		// The avatar link is managed by UploadAvatar and RemoveAvatar, so it
+		// survives metadata updates and cannot be pointed at other blobs.
+		delete(metadata, entities.AvatarMetadataKey)
+
+		if avatar, ok := user.Metadata().Get(entities.AvatarMetadataKey); ok {
+			metadata.Set(entities.AvatarMetadataKey, avatar)
+		}
+
 		changes["metadata"] = map[string]any{
 			changeKeyOld: user.Metadata(),
 			changeKeyNew: metadata,
@@ -295,11 +527,32 @@ func (s *UserService) applyProfileUpdates(
 	return changes
 }
 
-// AuthenticateUser authenticates a user with email and password.
+// AuthenticateUser authenticates a user with email and password. Attempts
+// are throttled if the service has a LoginThrottle. It fails with
+// ErrPasswordHasherUnavailable unless the service has a PasswordHasher.
 func (s *UserService) AuthenticateUser(
 	ctx context.Context,
 	email, password, ipAddress, userAgent string,
-) (*entities.UserSession, error) {
+) (_ *entities.UserSession, err error) {
+	ctx, end := s.startSpan(ctx, "AuthenticateUser")
+	defer end(&err)
+
+	// Without a hasher, the stored hashes could only be compared with the
+	// password, letting anyone holding a hash sign in with it.
+	if s.hasher == nil {
+		return nil, ErrPasswordHasherUnavailable
+	}
+
+	err = s.checkSwitches(ctx, entities.SwitchLoginDisabled)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.throttleLogin(ctx, email, ipAddress, userAgent)
+	if err != nil {
+		return nil, fmt.Errorf("email=%v: %w", email, err)
+	}
+
 	// Validate email
 	emailEntity, err := entities.NewEmail(email)
 	if err != nil {
@@ -307,20 +560,30 @@ func (s *UserService) AuthenticateUser(
 	}
 
 	// Get user
-	user, err := s.userRepo.VerifyCredentials(ctx, emailEntity, entities.PasswordHash(password))
+	user, err := s.verifyCredentials(ctx, emailEntity, password)
 	if err != nil {
 		// Publish failed login event
 		event := events.UserLoginFailed(entities.UserID(0), ipAddress, userAgent, "unknown")
+		event.TraceParent = s.tracer.TraceParent(ctx)
 		_ = s.eventPub.Publish(event)
 
+		s.recordLoginAttempt(
+			ctx, 0, ipAddress, userAgent, entities.LoginMethodPassword, entities.LoginFailureInvalidCredentials,
+		)
+
 		return nil, fmt.Errorf("email=%v: %w", email, entities.ErrInvalidCredentials)
 	}
 
 	// Check if user is active
 	if !user.IsActive() {
 		event := events.UserLoginFailed(user.ID(), ipAddress, userAgent, "inactive_account")
+		event.TraceParent = s.tracer.TraceParent(ctx)
 		_ = s.eventPub.Publish(event)
 
+		s.recordLoginAttempt(
+			ctx, user.ID(), ipAddress, userAgent, entities.LoginMethodPassword, entities.LoginFailureInactiveAccount,
+		)
+
 		if user.Status() == entities.UserStatusSuspended {
 			return nil, fmt.Errorf("email=%v: %w", email, entities.ErrAccountSuspended)
 		}
@@ -328,7 +591,41 @@ func (s *UserService) AuthenticateUser(
 		return nil, fmt.Errorf("email=%v: %w", email, entities.ErrAccountInactive)
 	}
 
-	// Create session
+	err = s.checkDeletionPending(ctx, user.ID())
+	if errors.Is(err, entities.ErrAccountDeleting) {
+		event := events.UserLoginFailed(user.ID(), ipAddress, userAgent, "pending_deletion")
+		event.TraceParent = s.tracer.TraceParent(ctx)
+		_ = s.eventPub.Publish(event)
+
+		s.recordLoginAttempt(
+			ctx, user.ID(), ipAddress, userAgent, entities.LoginMethodPassword, entities.LoginFailurePendingDeletion,
+		)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("email=%v: %w", email, err)
+	}
+
+	session, err := s.startSession(ctx, user, ipAddress, userAgent)
+	if err != nil {
+		return nil, fmt.Errorf("session create for email=%v: %w", email, err)
+	}
+
+	s.recordLoginAttempt(ctx, user.ID(), ipAddress, userAgent, entities.LoginMethodPassword, entities.LoginFailureNone)
+
+	// Publish login event
+	event := events.UserLoggedIn(user.ID(), ipAddress, userAgent, "unknown")
+	s.publishEvent(ctx, event)
+
+	return session, nil
+}
+
+// startSession creates a session for an authenticated user and records the login.
+func (s *UserService) startSession(
+	ctx context.Context,
+	user *entities.User,
+	ipAddress, userAgent string,
+) (*entities.UserSession, error) {
 	deviceInfo := entities.NewSessionDeviceInfo()
 	deviceInfo.SetMetadata("user_agent", userAgent)
 
@@ -337,13 +634,17 @@ func (s *UserService) AuthenticateUser(
 		net.ParseIP(ipAddress),
 		userAgent,
 		deviceInfo,
-		entities.SessionDurationMedium,
+		s.sessions.InitialLifetime(),
 	)
 
-	// Save session
+	err := s.assessLogin(ctx, user, session, ipAddress)
+	if err != nil {
+		return nil, err
+	}
+
 	err = s.sessionRepo.Create(ctx, session)
 	if err != nil {
-		return nil, fmt.Errorf("session create for email=%v: %w", email, err)
+		return nil, err
 	}
 
 	// Update user last login
@@ -354,10 +655,6 @@ func (s *UserService) AuthenticateUser(
 		slog.Warn("failed to update last login", "error", err)
 	}
 
-	// Publish login event
-	event := events.UserLoggedIn(user.ID(), ipAddress, userAgent, "unknown")
-	s.publishEvent(event)
-
 	return session, nil
 }
 
@@ -365,17 +662,20 @@ func (s *UserService) AuthenticateUser(
 func (s *UserService) VerifySession(
 	ctx context.Context,
 	token string,
-) (*entities.UserSession, *entities.User, error) {
+) (_ *entities.UserSession, _ *entities.User, err error) {
+	ctx, end := s.startSpan(ctx, "VerifySession")
+	defer end(&err)
+
 	// Parse token
-	tokenUUID, err := uuid.Parse(token)
+	claims, err := s.parseSessionToken(token)
 	if err != nil {
-		return nil, nil, fmt.Errorf("token=%v: %w", token, entities.ErrInvalidSessionToken)
+		return nil, nil, err
 	}
 
-	sessionToken := entities.SessionToken(tokenUUID)
+	token = claims.SessionToken.String()
 
 	// Get session
-	session, err := s.sessionRepo.GetByToken(ctx, sessionToken)
+	session, err := s.sessionRepo.GetByToken(ctx, claims.SessionToken)
 	if err != nil {
 		return nil, nil, fmt.Errorf("token=%v: %w", token, entities.ErrSessionNotFound)
 	}
@@ -389,6 +689,11 @@ func (s *UserService) VerifySession(
 		return nil, nil, fmt.Errorf("token=%v: %w", token, entities.ErrSessionNotFound)
 	}
 
+	now := time.Now()
+	if s.policyFor(session).Exceeded(session, now) {
+		return nil, nil, fmt.Errorf("token=%v: %w", token, entities.ErrSessionExpired)
+	}
+
 	// Get user
 	user, err := s.userRepo.GetByID(ctx, session.UserID())
 	if err != nil {
@@ -400,23 +705,43 @@ func (s *UserService) VerifySession(
 		return nil, nil, fmt.Errorf("token=%v: %w", token, entities.ErrAccountInactive)
 	}
 
+	if !matchesClaims(claims, session, user) {
+		return nil, nil, fmt.Errorf("token=%v: %w", token, entities.ErrInvalidSessionToken)
+	}
+
+	// Slide the expiration window on activity, unless the session was
+	// rotated or revoked since it was read
+	if s.policyFor(session).Renew(session, now) {
+		err = s.sessionRepo.UpdateIfCurrent(ctx, session, session.RefreshToken())
+		if err != nil {
+			slog.Warn("failed to renew session", "error", err)
+		}
+	}
+
 	return session, user, nil
 }
 
-// Logout deactivates a session.
-func (s *UserService) Logout(ctx context.Context, token string) error {
+// Logout deactivates a session. Impersonation sessions are ended as by
+// EndImpersonation.
+func (s *UserService) Logout(ctx context.Context, token string) (err error) {
+	ctx, end := s.startSpan(ctx, "Logout")
+	defer end(&err)
+
 	// Parse token
-	tokenUUID, err := uuid.Parse(token)
+	claims, err := s.parseSessionToken(token)
 	if err != nil {
-		return fmt.Errorf("token=%v: %w", token, entities.ErrInvalidSessionToken)
+		return err
 	}
 
-	sessionToken := entities.SessionToken(tokenUUID)
+	session, err := s.sessionRepo.GetByToken(ctx, claims.SessionToken)
+	if err == nil && session.IsImpersonation() {
+		return s.endImpersonation(ctx, session)
+	}
 
 	// Deactivate session
-	err = s.sessionRepo.DeactivateByToken(ctx, sessionToken)
+	err = s.sessionRepo.DeactivateByToken(ctx, claims.SessionToken)
 	if err != nil {
-		return fmt.Errorf("failed to logout token=%v: %w", token, err)
+		return fmt.Errorf("failed to logout token=%v: %w", claims.SessionToken, err)
 	}
 
 	// Publish logout event
@@ -431,7 +756,15 @@ func (s *UserService) ChangeUserRole(
 	userID entities.UserID,
 	newRole entities.UserRole,
 	_ string,
-) (*entities.User, error) {
+) (_ *entities.User, err error) {
+	ctx, end := s.startSpan(ctx, "ChangeUserRole")
+	defer end(&err)
+
+	err = s.checkSwitches(ctx, entities.SwitchReadOnly)
+	if err != nil {
+		return nil, err
+	}
+
 	// Get user
 	user, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
@@ -440,6 +773,7 @@ func (s *UserService) ChangeUserRole(
 
 	// Track old role
 	oldRole := user.Role()
+	before := entities.SnapshotOf(user)
 
 	// Change role
 	err = user.ChangeRole(newRole)
@@ -453,6 +787,8 @@ func (s *UserService) ChangeUserRole(
 		return nil, fmt.Errorf("failed to change role to %s for user %s: %w", newRole, userID, err)
 	}
 
+	s.recordAudit(ctx, entities.AuditActionRoleChange, user.ID(), auditDiff(before, user))
+
 	// Publish event
 	event := events.RoleChanged(
 		user.ID(),
@@ -461,10 +797,7 @@ func (s *UserService) ChangeUserRole(
 		entities.UserID(0), // Placeholder - in real impl, pass the admin user ID
 	)
 
-	err = s.eventPub.Publish(event)
-	if err != nil {
-		slog.Warn("failed to publish event", "error", err)
-	}
+	s.publishEvent(ctx, event)
 
 	return user, nil
 }
@@ -473,12 +806,21 @@ func (s *UserService) ChangeUserRole(
 func (s *UserService) VerifyUser(
 	ctx context.Context,
 	userID entities.UserID,
-) (*entities.User, error) {
+) (_ *entities.User, err error) {
+	ctx, end := s.startSpan(ctx, "VerifyUser")
+	defer end(&err)
+
+	err = s.checkSwitches(ctx, entities.SwitchReadOnly)
+	if err != nil {
+		return nil, err
+	}
+
 	user, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
 		return nil, fmt.Errorf("user %s not found: %w", userID, err)
 	}
 
+	before := entities.SnapshotOf(user)
 	user.Verify()
 
 	err = s.userRepo.Update(ctx, user)
@@ -486,8 +828,10 @@ func (s *UserService) VerifyUser(
 		return nil, fmt.Errorf("failed to verify user %s: %w", userID, err)
 	}
 
+	s.recordAudit(ctx, entities.AuditActionUserVerify, user.ID(), auditDiff(before, user))
+
 	event := events.UserVerified(user.ID(), "admin")
-	s.publishEvent(event)
+	s.publishEvent(ctx, event)
 
 	return user, nil
 }
@@ -496,12 +840,22 @@ func (s *UserService) VerifyUser(
 func (s *UserService) DeactivateUser(
 	ctx context.Context,
 	userID entities.UserID,
-) (*entities.User, error) {
+) (_ *entities.User, err error) {
+	ctx, end := s.startSpan(ctx, "DeactivateUser")
+	defer end(&err)
+
+	err = s.checkSwitches(ctx, entities.SwitchReadOnly)
+	if err != nil {
+		return nil, err
+	}
+
 	user, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
 		return nil, fmt.Errorf("user %s not found: %w", userID, err)
 	}
 
+	before := entities.SnapshotOf(user)
+
 	err = user.ChangeStatus(entities.UserStatusInactive)
 	if err != nil {
 		return nil, fmt.Errorf("failed to deactivate user %s: %w", userID, err)
@@ -512,6 +866,8 @@ func (s *UserService) DeactivateUser(
 		return nil, fmt.Errorf("failed to save deactivated user %s: %w", userID, err)
 	}
 
+	s.recordAudit(ctx, entities.AuditActionStatusChange, user.ID(), auditDiff(before, user))
+
 	changes := map[string]any{
 		"status": map[string]any{
 			changeKeyOld: string(entities.UserStatusActive),
@@ -519,13 +875,177 @@ func (s *UserService) DeactivateUser(
 		},
 	}
 	event := events.UserUpdated(user.ID(), changes, userID)
-	s.publishEvent(event)
+	s.publishEvent(ctx, event)
 
 	return user, nil
 }
 
+// DeleteUser soft deletes a user. The user disappears from lookups and
+// listings but can be restored with RestoreUser until it is purged.
+func (s *UserService) DeleteUser(ctx context.Context, userID entities.UserID) (err error) {
+	ctx, end := s.startSpan(ctx, "DeleteUser")
+	defer end(&err)
+
+	err = s.checkSwitches(ctx, entities.SwitchReadOnly)
+	if err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("user %s not found: %w", userID, err)
+	}
+
+	// Deleted users stop showing their avatar, so restoring them does not
+	// bring it back.
+	avatar := user.Avatar()
+	if !avatar.IsZero() && s.avatars != nil {
+		user.ClearAvatar()
+
+		err = s.userRepo.Update(ctx, user)
+		if err != nil {
+			return fmt.Errorf("failed to remove avatar of user %s: %w", userID, err)
+		}
+	}
+
+	err = s.userRepo.Delete(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete user %s: %w", userID, err)
+	}
+
+	s.deleteAvatar(ctx, userID, avatar.Key)
+
+	s.recordAudit(ctx, entities.AuditActionUserDelete, userID, nil)
+
+	actor, _ := AuditActorFromContext(ctx)
+	s.publishEvent(ctx, events.UserDeleted(user, actor.UserID))
+
+	return nil
+}
+
+// RestoreUser restores a soft-deleted user.
+func (s *UserService) RestoreUser(ctx context.Context, userID entities.UserID) (_ *entities.User, err error) {
+	ctx, end := s.startSpan(ctx, "RestoreUser")
+	defer end(&err)
+
+	err = s.checkSwitches(ctx, entities.SwitchReadOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.userRepo.Restore(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore user %s: %w", userID, err)
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("restored user %s: %w", userID, err)
+	}
+
+	s.recordAudit(ctx, entities.AuditActionUserRestore, userID, nil)
+
+	actor, _ := AuditActorFromContext(ctx)
+	s.publishEvent(ctx, events.UserRestored(user, actor.UserID))
+
+	return user, nil
+}
+
+// PurgeDeletedUsers permanently removes the users deleted longer than
+// retention ago and returns how many were removed.
+func (s *UserService) PurgeDeletedUsers(ctx context.Context, retention time.Duration) (_ int64, err error) {
+	ctx, end := s.startSpan(ctx, "PurgeDeletedUsers")
+	defer end(&err)
+
+	err = s.checkSwitches(ctx, entities.SwitchReadOnly)
+	if err != nil {
+		return 0, err
+	}
+
+	cutoff := time.Now().Add(-retention)
+
+	purged, err := s.userRepo.PurgeDeletedOlderThan(ctx, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge users deleted before %v: %w", cutoff, err)
+	}
+
+	if purged > 0 {
+		slog.Info("purged deleted users", "count", purged, "cutoff", cutoff)
+	}
+
+	return purged, nil
+}
+
+// SearchUsersWithFacets searches users and returns facet counts in one round trip.
+// Facets are computed over every user matching the query, regardless of status,
+// so that clients can render counts for the filters they have not selected.
+func (s *UserService) SearchUsersWithFacets(
+	ctx context.Context,
+	query string,
+	status entities.UserStatus,
+	limit int,
+) (_ *entities.UserSearchResult, err error) {
+	ctx, end := s.startSpan(ctx, "SearchUsersWithFacets")
+	defer end(&err)
+
+	if !status.IsValid() {
+		return nil, fmt.Errorf("status=%v: %w", status, entities.ErrInvalidUserStatus)
+	}
+
+	result, err := s.userRepo.SearchWithFacets(ctx, query, status, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search users query=%v: %w", query, err)
+	}
+
+	return result, nil
+}
+
+// ListUsers returns a page of users matching filter, newest first.
+func (s *UserService) ListUsers(
+	ctx context.Context,
+	filter entities.UserFilter,
+	limit, offset int,
+) (_ []*entities.User, err error) {
+	ctx, end := s.startSpan(ctx, "ListUsers")
+	defer end(&err)
+
+	err = filter.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	users, err := s.userRepo.List(ctx, filter, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users limit=%v offset=%v: %w", limit, offset, err)
+	}
+
+	return users, nil
+}
+
+// ListUsersPage returns a page of users matching filter, newest first. Pass
+// the NextCursor of a page to fetch the one after it.
+func (s *UserService) ListUsersPage(
+	ctx context.Context,
+	filter entities.UserFilter,
+	cursor string,
+	limit int,
+) (_ *entities.UserPage, err error) {
+	ctx, end := s.startSpan(ctx, "ListUsersPage")
+	defer end(&err)
+
+	page, err := s.userRepo.ListPage(ctx, filter, cursor, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users page limit=%v: %w", limit, err)
+	}
+
+	return page, nil
+}
+
 // GetUserStats returns user statistics.
-func (s *UserService) GetUserStats(ctx context.Context) (*entities.UserStats, error) {
+func (s *UserService) GetUserStats(ctx context.Context) (_ *entities.UserStats, err error) {
+	ctx, end := s.startSpan(ctx, "GetUserStats")
+	defer end(&err)
+
 	stats, err := s.userRepo.GetStats(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user stats: %w", err)
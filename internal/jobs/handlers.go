@@ -0,0 +1,268 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// Names of the built-in jobs.
+const (
+	OutboxRelayJob          = "outbox.relay"
+	EmailSendJob            = "email.send"
+	CleanupJob              = "cleanup"
+	AnalyticsSyncJob        = "analytics.sync"
+	ModerationReactivateJob = "moderation.reactivate"
+	AccountDeletionJob      = "account.delete"
+)
+
+// ErrInvalidEmail is returned for email jobs without a recipient.
+var ErrInvalidEmail = errors.New("email has no recipient")
+
+// OutboxPublisher is an events.EventPublisher that stores each event as an
+// outbox.relay job instead of delivering it, so events survive a crash and
+// reach the relay's publisher at least once.
+type OutboxPublisher struct {
+	repo  repositories.JobRepository
+	codec events.CloudEventCodec
+}
+
+var _ events.EventPublisher = (*OutboxPublisher)(nil)
+
+// NewOutboxPublisher creates a publisher enqueueing events encoded as
+// structured-mode CloudEvents with codec.
+func NewOutboxPublisher(repo repositories.JobRepository, codec events.CloudEventCodec) *OutboxPublisher {
+	return &OutboxPublisher{repo: repo, codec: codec}
+}
+
+// Publish enqueues event for the relay.
+func (p *OutboxPublisher) Publish(event *events.UserEvent) error {
+	payload, err := p.codec.Serialize(event)
+	if err != nil {
+		return err
+	}
+
+	_, err = Enqueue(context.Background(), p.repo, OutboxRelayJob, json.RawMessage(payload))
+	if err != nil {
+		return fmt.Errorf("event=%v: %w", event.ID, err)
+	}
+
+	return nil
+}
+
+// PublishBatch enqueues events for the relay, one job per event.
+func (p *OutboxPublisher) PublishBatch(batch []*events.UserEvent) error {
+	for _, event := range batch {
+		err := p.Publish(event)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// NewOutboxRelay returns the outbox.relay handler, which decodes the
+// CloudEvent of the job with codec and passes it on to publisher, for
+// example a Dispatcher or a message broker adapter.
+func NewOutboxRelay(codec events.CloudEventCodec, publisher events.EventPublisher) Handler {
+	return HandlerFunc(func(_ context.Context, job *entities.Job) error {
+		event, err := codec.Deserialize(job.Payload)
+		if err != nil {
+			return Permanent(fmt.Errorf("job id=%d: %w", job.ID, err))
+		}
+
+		return publisher.Publish(event)
+	})
+}
+
+// Email is the payload of an email.send job.
+type Email struct {
+	To      string `json:"to"`
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+}
+
+// Mailer delivers emails, for example through SMTP or a provider's API.
+type Mailer interface {
+	Send(ctx context.Context, email Email) error
+}
+
+// NewEmailHandler returns the email.send handler, which sends the job's
+// Email with mailer.
+func NewEmailHandler(mailer Mailer) Handler {
+	return HandlerFunc(func(ctx context.Context, job *entities.Job) error {
+		email, err := Decode[Email](job)
+		if err != nil {
+			return err
+		}
+
+		if email.To == "" {
+			return Permanent(fmt.Errorf("job id=%d: %w", job.ID, ErrInvalidEmail))
+		}
+
+		return mailer.Send(ctx, email)
+	})
+}
+
+// Cleanup configures the cleanup handler. Nil repositories and zero
+// retentions skip their task.
+type Cleanup struct {
+	// Sessions has its expired sessions removed.
+	Sessions repositories.SessionRepository
+	// Jobs has its done jobs older than DoneJobRetention removed.
+	Jobs             repositories.JobRepository
+	DoneJobRetention time.Duration
+	// Users has the users soft deleted longer than DeletedUserRetention ago
+	// purged for good.
+	Users                repositories.UserRepository
+	DeletedUserRetention time.Duration
+	// Idempotency has its expired idempotency keys removed.
+	Idempotency repositories.IdempotencyRepository
+	// Activity has its entries older than ActivityRetention removed.
+	Activity          repositories.ActivityRepository
+	ActivityRetention time.Duration
+}
+
+// NewCleanupHandler returns the cleanup handler, which runs every configured
+// task of cleanup. Enqueue cleanup jobs periodically, e.g. from a scheduler;
+// the tasks are idempotent, so overlapping runs are harmless.
+func NewCleanupHandler(cleanup Cleanup) Handler {
+	return HandlerFunc(func(ctx context.Context, _ *entities.Job) error {
+		now := time.Now()
+
+		tasks := []struct {
+			name string
+			run  func() (int64, error)
+			skip bool
+		}{
+			{
+				name: "expired sessions",
+				run:  func() (int64, error) { return cleanup.Sessions.CleanupExpired(ctx) },
+				skip: cleanup.Sessions == nil,
+			},
+			{
+				name: "done jobs",
+				run:  func() (int64, error) { return cleanup.Jobs.DeleteDone(ctx, now.Add(-cleanup.DoneJobRetention)) },
+				skip: cleanup.Jobs == nil || cleanup.DoneJobRetention <= 0,
+			},
+			{
+				name: "deleted users",
+				run: func() (int64, error) {
+					return cleanup.Users.PurgeDeletedOlderThan(ctx, now.Add(-cleanup.DeletedUserRetention))
+				},
+				skip: cleanup.Users == nil || cleanup.DeletedUserRetention <= 0,
+			},
+			{
+				name: "expired idempotency keys",
+				run:  func() (int64, error) { return cleanup.Idempotency.DeleteExpired(ctx, now) },
+				skip: cleanup.Idempotency == nil,
+			},
+			{
+				name: "old activity",
+				run: func() (int64, error) {
+					return cleanup.Activity.DeleteOlderThan(ctx, now.Add(-cleanup.ActivityRetention))
+				},
+				skip: cleanup.Activity == nil || cleanup.ActivityRetention <= 0,
+			},
+		}
+
+		var errs []error
+
+		for _, task := range tasks {
+			if task.skip {
+				continue
+			}
+
+			removed, err := task.run()
+			if err != nil {
+				errs = append(errs, fmt.Errorf("clean up %v: %w", task.name, err))
+
+				continue
+			}
+
+			if removed > 0 {
+				slog.Info("cleaned up", "task", task.name, "count", removed)
+			}
+		}
+
+		return errors.Join(errs...)
+	})
+}
+
+// Syncer copies data from the primary store into an analytical store, such
+// as the DuckDB Syncer, and returns how many rows it copied.
+type Syncer interface {
+	Sync(ctx context.Context) (int64, error)
+}
+
+// NewAnalyticsSyncHandler returns the analytics.sync handler, which runs
+// syncer. Enqueue sync jobs periodically; every run replaces the copy, so
+// a failed run is simply retried.
+func NewAnalyticsSyncHandler(syncer Syncer) Handler {
+	return HandlerFunc(func(ctx context.Context, job *entities.Job) error {
+		copied, err := syncer.Sync(ctx)
+		if err != nil {
+			return fmt.Errorf("job id=%d: sync analytics: %w", job.ID, err)
+		}
+
+		slog.Info("synced analytics", "rows", copied)
+
+		return nil
+	})
+}
+
+// Reactivator lifts suspensions that ended, such as the UserService, and
+// returns how many it lifted.
+type Reactivator interface {
+	ReactivateLapsedSuspensions(ctx context.Context, limit int) (int, error)
+}
+
+// NewReactivationHandler returns the moderation.reactivate handler, which
+// lifts up to batch lapsed suspensions with reactivator. Enqueue the job
+// periodically; suspensions left over are lifted by the next run.
+func NewReactivationHandler(reactivator Reactivator, batch int) Handler {
+	return HandlerFunc(func(ctx context.Context, job *entities.Job) error {
+		lifted, err := reactivator.ReactivateLapsedSuspensions(ctx, batch)
+		if lifted > 0 {
+			slog.Info("lifted lapsed suspensions", "count", lifted)
+		}
+
+		if err != nil {
+			return fmt.Errorf("job id=%d: reactivate users: %w", job.ID, err)
+		}
+
+		return nil
+	})
+}
+
+// Deleter erases the accounts whose deletion grace period ended, such as the
+// UserService, and returns how many it erased.
+type Deleter interface {
+	DeleteDueAccounts(ctx context.Context, limit int) (int, error)
+}
+
+// NewAccountDeletionHandler returns the account.delete handler, which erases
+// up to batch accounts due for deletion with deleter. Enqueue the job
+// periodically; accounts left over are erased by the next run.
+func NewAccountDeletionHandler(deleter Deleter, batch int) Handler {
+	return HandlerFunc(func(ctx context.Context, job *entities.Job) error {
+		erased, err := deleter.DeleteDueAccounts(ctx, batch)
+		if erased > 0 {
+			slog.Info("erased accounts due for deletion", "count", erased)
+		}
+
+		if err != nil {
+			return fmt.Errorf("job id=%d: delete accounts: %w", job.ID, err)
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,139 @@
+// Package jobs runs background work from the database-backed job queue.
+//
+// Jobs are rows of the jobs table, enqueued with a handler name and a JSON
+// payload. A Pool claims due jobs with a lease (FOR UPDATE SKIP LOCKED on
+// PostgreSQL, a conditional update per job on SQLite and MySQL), runs the
+// handler registered under the job's name and finishes the job: it is marked
+// done, retried with exponential backoff, or marked failed once its attempts
+// are exhausted or its handler returns a Permanent error.
+//
+//	pool := jobs.NewPool(jobRepo, jobs.WithWorkers(8))
+//	pool.Register(jobs.EmailSendJob, jobs.NewEmailHandler(mailer))
+//	go pool.Run(ctx)
+//
+//	_, err := jobs.Enqueue(ctx, jobRepo, jobs.EmailSendJob, jobs.Email{To: "ada@example.com"})
+//
+// Delivery is at least once: a job whose worker dies is claimed again when
+// its lease expires, so handlers must be idempotent.
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+	"github.com/LarsArtmann/template-sqlc/pkg/dblock"
+)
+
+// ErrUnknownHandler is recorded on jobs whose name has no registered handler.
+var ErrUnknownHandler = errors.New("no handler registered for job")
+
+// Handler runs a claimed job. A returned error retries the job unless it is
+// Permanent or the job has no attempts left.
+type Handler interface {
+	Handle(ctx context.Context, job *entities.Job) error
+}
+
+// HandlerFunc adapts a function to Handler.
+type HandlerFunc func(ctx context.Context, job *entities.Job) error
+
+// Handle calls f.
+func (f HandlerFunc) Handle(ctx context.Context, job *entities.Job) error {
+	return f(ctx, job)
+}
+
+// permanentError marks an error that retrying cannot fix.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent marks err as not worth retrying; the job fails right away.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &permanentError{err: err}
+}
+
+// IsPermanent returns true if err was marked with Permanent.
+func IsPermanent(err error) bool {
+	var permanent *permanentError
+
+	return errors.As(err, &permanent)
+}
+
+// Exclusive wraps handler so that its jobs run one at a time across all
+// instances sharing the database of locker, under the lock "jobs:<name>".
+// A job whose lock is held elsewhere is retried.
+func Exclusive(locker dblock.Locker, name string, handler Handler) Handler {
+	return HandlerFunc(func(ctx context.Context, job *entities.Job) error {
+		lock, err := locker.TryLock(ctx, "jobs:"+name)
+		if err != nil {
+			return fmt.Errorf("job id=%d: %w", job.ID, err)
+		}
+
+		err = handler.Handle(ctx, job)
+
+		unlockErr := lock.Unlock(context.WithoutCancel(ctx))
+		if unlockErr != nil {
+			unlockErr = fmt.Errorf("job id=%d unlock: %w", job.ID, unlockErr)
+		}
+
+		return errors.Join(err, unlockErr)
+	})
+}
+
+// Enqueue queues a job for the handler registered under name, due now.
+// payload is marshaled to JSON.
+func Enqueue(ctx context.Context, repo repositories.JobRepository, name string, payload any) (*entities.Job, error) {
+	return Schedule(ctx, repo, name, payload, time.Time{})
+}
+
+// Schedule queues a job for the handler registered under name, due at runAt.
+// payload is marshaled to JSON.
+func Schedule(
+	ctx context.Context,
+	repo repositories.JobRepository,
+	name string,
+	payload any,
+	runAt time.Time,
+) (*entities.Job, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("job name=%v: %w: %w", name, entities.ErrInvalidJobPayload, err)
+	}
+
+	job, err := entities.NewJob(name, data, runAt, 0)
+	if err != nil {
+		return nil, fmt.Errorf("job name=%v: %w", name, err)
+	}
+
+	err = repo.Enqueue(ctx, job)
+	if err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// Decode unmarshals the payload of job. A payload that does not decode fails
+// the same way on every attempt, so the error is Permanent.
+func Decode[T any](job *entities.Job) (T, error) {
+	var payload T
+
+	err := json.Unmarshal(job.Payload, &payload)
+	if err != nil {
+		return payload, Permanent(fmt.Errorf("job id=%d name=%v: decode payload: %w", job.ID, job.Name, err))
+	}
+
+	return payload, nil
+}
@@ -0,0 +1,247 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+const (
+	defaultPoolWorkers      = 4
+	defaultPollInterval     = time.Second
+	defaultJobLease         = 5 * time.Minute
+	defaultRetryBackoff     = time.Second
+	defaultMaxRetryBackoff  = time.Hour
+	maxStoredJobErrorLength = 2000
+)
+
+// Pool claims due jobs and runs them on a fixed number of workers.
+type Pool struct {
+	repo         repositories.JobRepository
+	workers      int
+	pollInterval time.Duration
+	lease        time.Duration
+	backoff      time.Duration
+	maxBackoff   time.Duration
+
+	mu       sync.RWMutex
+	handlers map[string]Handler
+}
+
+// PoolOption configures a Pool.
+type PoolOption func(*Pool)
+
+// WithWorkers sets how many jobs run in parallel.
+func WithWorkers(workers int) PoolOption {
+	return func(p *Pool) {
+		if workers > 0 {
+			p.workers = workers
+		}
+	}
+}
+
+// WithPollInterval sets how long the pool waits before polling again when
+// no job was due.
+func WithPollInterval(interval time.Duration) PoolOption {
+	return func(p *Pool) {
+		if interval > 0 {
+			p.pollInterval = interval
+		}
+	}
+}
+
+// WithLease sets how long a claimed job is leased to its worker. Handlers
+// are cancelled when the lease runs out, since the job may be claimed again.
+func WithLease(lease time.Duration) PoolOption {
+	return func(p *Pool) {
+		if lease > 0 {
+			p.lease = lease
+		}
+	}
+}
+
+// WithBackoff sets the delay before the first retry, which doubles each
+// retry up to maxBackoff.
+func WithBackoff(backoff, maxBackoff time.Duration) PoolOption {
+	return func(p *Pool) {
+		if backoff >= 0 {
+			p.backoff = backoff
+		}
+
+		if maxBackoff >= p.backoff {
+			p.maxBackoff = maxBackoff
+		}
+	}
+}
+
+// NewPool creates a pool running the jobs of repo. Handlers are registered
+// with Register and the pool is started with Run.
+func NewPool(repo repositories.JobRepository, opts ...PoolOption) *Pool {
+	p := &Pool{
+		repo:         repo,
+		workers:      defaultPoolWorkers,
+		pollInterval: defaultPollInterval,
+		lease:        defaultJobLease,
+		backoff:      defaultRetryBackoff,
+		maxBackoff:   defaultMaxRetryBackoff,
+		handlers:     make(map[string]Handler),
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// Register runs handler for the jobs enqueued under name, replacing any
+// handler registered under it before.
+func (p *Pool) Register(name string, handler Handler) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.handlers[name] = handler
+}
+
+// handler returns the handler registered under name.
+func (p *Pool) handler(name string) (Handler, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	handler, ok := p.handlers[name]
+
+	return handler, ok
+}
+
+// Run claims and runs jobs until ctx is done, then waits for the running
+// jobs to finish. Running jobs are not cancelled with ctx but are bounded by
+// their lease, so a shutdown never loses a finished job's result.
+func (p *Pool) Run(ctx context.Context) error {
+	slots := make(chan struct{}, p.workers)
+
+	var running sync.WaitGroup
+	defer running.Wait()
+
+	for {
+		limit := p.workers - len(slots)
+		claimed := p.claim(ctx, limit)
+
+		for _, job := range claimed {
+			slots <- struct{}{}
+
+			running.Go(func() {
+				defer func() { <-slots }()
+
+				p.process(ctx, job)
+			})
+		}
+
+		// A full batch suggests more jobs are due, so poll again right away.
+		if len(claimed) > 0 && len(claimed) == limit {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(p.pollInterval):
+		}
+	}
+}
+
+// claim leases up to limit due jobs, logging failures to poll.
+func (p *Pool) claim(ctx context.Context, limit int) []*entities.Job {
+	if limit <= 0 || ctx.Err() != nil {
+		return nil
+	}
+
+	claimed, err := p.repo.Claim(ctx, time.Now(), p.lease, limit)
+	if err != nil {
+		if ctx.Err() == nil {
+			slog.Warn("failed to claim jobs", "error", err)
+		}
+
+		return nil
+	}
+
+	return claimed
+}
+
+// process runs job and records the outcome.
+func (p *Pool) process(parent context.Context, job *entities.Job) {
+	ctx := context.WithoutCancel(parent)
+
+	err := p.run(ctx, job)
+
+	switch {
+	case err == nil:
+		err = p.repo.Complete(ctx, job)
+	case IsPermanent(err) || !job.CanRetry():
+		slog.Error("job failed",
+			"job_id", job.ID,
+			"job_name", job.Name,
+			"attempts", job.Attempts,
+			"error", err,
+		)
+
+		err = p.repo.Fail(ctx, job, errorMessage(err))
+	default:
+		err = p.repo.Retry(ctx, job, time.Now().Add(p.retryDelay(job.Attempts)), errorMessage(err))
+	}
+
+	if err != nil {
+		slog.Warn("failed to finish job", "job_id", job.ID, "job_name", job.Name, "error", err)
+	}
+}
+
+// run calls the handler of job within its lease, turning a panic into an error.
+func (p *Pool) run(ctx context.Context, job *entities.Job) (err error) {
+	handler, ok := p.handler(job.Name)
+	if !ok {
+		return Permanent(fmt.Errorf("job name=%v: %w", job.Name, ErrUnknownHandler))
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, p.lease)
+	defer cancel()
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job name=%v: panic: %v", job.Name, r)
+		}
+	}()
+
+	return handler.Handle(ctx, job)
+}
+
+// retryDelay returns the backoff after the given attempt: the base backoff
+// doubled for each earlier attempt, capped at the maximum.
+func (p *Pool) retryDelay(attempt int) time.Duration {
+	delay := p.backoff
+
+	for range attempt - 1 {
+		if delay >= p.maxBackoff/2 {
+			return p.maxBackoff
+		}
+
+		delay *= 2
+	}
+
+	return min(delay, p.maxBackoff)
+}
+
+// errorMessage returns the error stored on a job, truncated to keep rows small.
+func errorMessage(err error) string {
+	message := err.Error()
+	if len(message) > maxStoredJobErrorLength {
+		// Cutting may split a rune, which PostgreSQL rejects in text columns.
+		return strings.ToValidUTF8(message[:maxStoredJobErrorLength], "")
+	}
+
+	return message
+}
@@ -0,0 +1,257 @@
+package monitoring
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultCheckTimeout bounds every health check that has no own deadline.
+	defaultCheckTimeout = 2 * time.Second
+
+	// HealthStatusUp is reported for passing checks and healthy reports.
+	HealthStatusUp = "up"
+	// HealthStatusDown is reported for failing checks and unhealthy reports.
+	HealthStatusDown = "down"
+)
+
+// ErrPendingMigrations is returned by MigrationCheck while migrations are pending.
+var ErrPendingMigrations = errors.New("pending migrations")
+
+// HealthCheck reports whether a dependency is usable.
+type HealthCheck func(ctx context.Context) error
+
+// Pinger is a dependency that can be pinged, such as *db.Pool or a message
+// broker connection.
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// PingCheck checks a dependency by pinging it.
+func PingCheck(pinger Pinger) HealthCheck {
+	return pinger.Ping
+}
+
+// MigrationCheck fails while pending reports migrations that are not applied.
+func MigrationCheck(pending func(ctx context.Context) (int, error)) HealthCheck {
+	return func(ctx context.Context) error {
+		count, err := pending(ctx)
+		if err != nil {
+			return fmt.Errorf("migration status: %w", err)
+		}
+
+		if count > 0 {
+			return fmt.Errorf("count=%v: %w", count, ErrPendingMigrations)
+		}
+
+		return nil
+	}
+}
+
+// CheckResult is the outcome of a single health check.
+type CheckResult struct {
+	Name      string  `json:"name"`
+	Status    string  `json:"status"`
+	LatencyMS float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// ModeCheck reports whether an operating mode, such as read-only mode, is on.
+type ModeCheck func(ctx context.Context) bool
+
+// HealthReport is the JSON body of the /healthz and /readyz endpoints.
+type HealthReport struct {
+	Status string          `json:"status"`
+	Checks []CheckResult   `json:"checks"`
+	Modes  map[string]bool `json:"modes,omitempty"`
+}
+
+// Healthy reports whether every check passed.
+func (r HealthReport) Healthy() bool {
+	return r.Status == HealthStatusUp
+}
+
+// namedCheck is a registered health check.
+type namedCheck struct {
+	name  string
+	check HealthCheck
+}
+
+// HealthChecker is a registry of liveness and readiness checks.
+//
+// Liveness checks tell whether the process must be restarted and should only
+// cover the process itself. Readiness checks tell whether it can serve
+// traffic and cover its dependencies: databases, event publishers and the
+// migration status.
+type HealthChecker struct {
+	timeout time.Duration
+
+	mu        sync.RWMutex
+	liveness  []namedCheck
+	readiness []namedCheck
+	modes     map[string]ModeCheck
+}
+
+// HealthOption configures a HealthChecker.
+type HealthOption func(*HealthChecker)
+
+// WithCheckTimeout bounds the duration of every check.
+func WithCheckTimeout(timeout time.Duration) HealthOption {
+	return func(h *HealthChecker) {
+		if timeout > 0 {
+			h.timeout = timeout
+		}
+	}
+}
+
+// NewHealthChecker creates a HealthChecker without checks.
+func NewHealthChecker(opts ...HealthOption) *HealthChecker {
+	checker := &HealthChecker{timeout: defaultCheckTimeout}
+
+	for _, opt := range opts {
+		opt(checker)
+	}
+
+	return checker
+}
+
+// RegisterLiveness adds a liveness check.
+func (h *HealthChecker) RegisterLiveness(name string, check HealthCheck) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.liveness = append(h.liveness, namedCheck{name: name, check: check})
+}
+
+// RegisterReadiness adds a readiness check.
+func (h *HealthChecker) RegisterReadiness(name string, check HealthCheck) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.readiness = append(h.readiness, namedCheck{name: name, check: check})
+}
+
+// RegisterMode adds an operating mode to the reports. Modes are reported so
+// that operators see a degraded service, but never fail a report.
+func (h *HealthChecker) RegisterMode(name string, mode ModeCheck) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.modes == nil {
+		h.modes = make(map[string]ModeCheck)
+	}
+
+	h.modes[name] = mode
+}
+
+// Liveness runs the liveness checks.
+func (h *HealthChecker) Liveness(ctx context.Context) HealthReport {
+	h.mu.RLock()
+	checks := h.liveness
+	modes := maps.Clone(h.modes)
+	h.mu.RUnlock()
+
+	return h.run(ctx, checks, modes)
+}
+
+// Readiness runs the liveness and readiness checks, as a process that is not
+// alive cannot be ready either.
+func (h *HealthChecker) Readiness(ctx context.Context) HealthReport {
+	h.mu.RLock()
+	checks := append(append([]namedCheck{}, h.liveness...), h.readiness...)
+	modes := maps.Clone(h.modes)
+	h.mu.RUnlock()
+
+	return h.run(ctx, checks, modes)
+}
+
+// LivenessHandler serves the liveness report, with status 503 if it fails.
+func (h *HealthChecker) LivenessHandler() http.Handler {
+	return reportHandler(h.Liveness)
+}
+
+// ReadinessHandler serves the readiness report, with status 503 if it fails.
+func (h *HealthChecker) ReadinessHandler() http.Handler {
+	return reportHandler(h.Readiness)
+}
+
+// run executes checks concurrently and collects their results in order,
+// along with the state of modes.
+func (h *HealthChecker) run(ctx context.Context, checks []namedCheck, modes map[string]ModeCheck) HealthReport {
+	report := HealthReport{Status: HealthStatusUp, Checks: make([]CheckResult, len(checks))}
+
+	var wg sync.WaitGroup
+
+	for i, check := range checks {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			report.Checks[i] = h.runCheck(ctx, check)
+		}()
+	}
+
+	wg.Wait()
+
+	for _, result := range report.Checks {
+		if result.Status != HealthStatusUp {
+			report.Status = HealthStatusDown
+		}
+	}
+
+	if len(modes) > 0 {
+		report.Modes = make(map[string]bool, len(modes))
+
+		for name, mode := range modes {
+			report.Modes[name] = mode(ctx)
+		}
+	}
+
+	return report
+}
+
+// runCheck executes a single check within the check timeout.
+func (h *HealthChecker) runCheck(ctx context.Context, check namedCheck) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check.check(ctx)
+	latency := time.Since(start)
+
+	result := CheckResult{
+		Name:      check.name,
+		Status:    HealthStatusUp,
+		LatencyMS: float64(latency) / float64(time.Millisecond),
+	}
+
+	if err != nil {
+		result.Status = HealthStatusDown
+		result.Error = err.Error()
+	}
+
+	return result
+}
+
+// reportHandler serves the JSON report produced by probe.
+func reportHandler(probe func(ctx context.Context) HealthReport) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		report := probe(req.Context())
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+
+		if !report.Healthy() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+
+		_ = json.NewEncoder(w).Encode(report)
+	})
+}
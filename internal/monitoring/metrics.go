@@ -6,10 +6,14 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"net/http/pprof"
+	"strconv"
 	"time"
 
-	"github.com/prometheus/client_golang/prometheus"          // DEPRECATED: prefer go.opentelemetry.io/otel
-	"github.com/prometheus/client_golang/prometheus/promhttp" // DEPRECATED: prefer go.opentelemetry.io/otel
+	"github.com/prometheus/client_golang/prometheus"            // DEPRECATED: prefer go.opentelemetry.io/otel
+	"github.com/prometheus/client_golang/prometheus/collectors" // DEPRECATED: prefer go.opentelemetry.io/otel
+	"github.com/prometheus/client_golang/prometheus/promhttp"   // DEPRECATED: prefer go.opentelemetry.io/otel
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -17,8 +21,14 @@ const (
 	readHeaderTimeout = 10 * time.Second
 	// metricNamespace is the namespace used for all prometheus metrics.
 	metricNamespace = "sqlc"
+	// unmatchedRoute labels requests that no ServeMux pattern matched, so that
+	// arbitrary paths cannot inflate the label cardinality.
+	unmatchedRoute = "unmatched"
 )
 
+// httpLabels are the labels of the HTTP request metrics.
+var httpLabels = []string{"method", "route", "code"}
+
 // Metrics collects and exposes sqlc-related metrics.
 type Metrics struct {
 	// Code generation metrics
@@ -27,9 +37,10 @@ type Metrics struct {
 	CodeGenTotal    prometheus.Counter
 
 	// Database query metrics
-	QueryDuration     prometheus.Histogram
-	QueryErrors       prometheus.Counter
-	QueryTotal        prometheus.Counter
+	QueryDuration     *prometheus.HistogramVec
+	QueryErrors       *prometheus.CounterVec
+	QueryTotal        *prometheus.CounterVec
+	QueryTimeouts     *prometheus.CounterVec
 	ActiveConnections prometheus.Gauge
 
 	// User operation metrics
@@ -41,6 +52,29 @@ type Metrics struct {
 	SessionCreations prometheus.Counter
 	SessionActive    prometheus.Gauge
 
+	// RPC metrics
+	RPCDuration *prometheus.HistogramVec
+
+	// Rate limiting metrics
+	RateLimitDecisions *prometheus.CounterVec
+
+	// Resilience metrics
+	CircuitBreakerState *prometheus.GaugeVec
+	Retries             *prometheus.CounterVec
+
+	// Event buffer metrics
+	EventBufferDepth prometheus.Gauge
+	EventsDropped    *prometheus.CounterVec
+
+	// Read model metrics
+	UserReadModelLag prometheus.Gauge
+
+	// HTTP metrics
+	HTTPRequests     *prometheus.CounterVec
+	HTTPDuration     *prometheus.HistogramVec
+	HTTPResponseSize *prometheus.HistogramVec
+	HTTPInFlight     prometheus.Gauge
+
 	// Configuration metrics
 	ConfigFileSize prometheus.Gauge
 	ConfigDatabase prometheus.Gauge
@@ -50,13 +84,23 @@ type Metrics struct {
 	BuildSuccess  prometheus.Counter
 	BuildFailures prometheus.Counter
 
+	queryNames *labelGuard
+	engines    *labelGuard
+
 	registry *prometheus.Registry
+	health   *HealthChecker
 	server   *http.Server
 }
 
 // NewMetrics creates a new metrics collector.
-func NewMetrics() *Metrics {
-	return newMetrics(prometheus.NewRegistry())
+func NewMetrics(opts ...MetricsOption) *Metrics {
+	metrics := newMetrics(prometheus.NewRegistry())
+
+	for _, opt := range opts {
+		opt(metrics)
+	}
+
+	return metrics
 }
 
 // HistogramConfig holds configuration for a histogram metric.
@@ -126,21 +170,46 @@ func newMetrics(registry *prometheus.Registry) *Metrics {
 			"codegen",
 		),
 
-		QueryDuration: newHistogram(HistogramConfig{
-			Name:      "sqlc_query_duration_seconds",
-			Help:      "Duration of database queries in seconds",
-			Buckets:   []float64{0.001, 0.005, 0.01, 0.05, 0.1, 0.5, 1, 5},
-			Subsystem: "query",
-		}),
-		QueryErrors: newCounter(
-			"sqlc_query_errors_total",
-			"Total number of database query errors",
-			"query",
+		QueryDuration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{ //nolint:exhaustruct // NativeHistogram fields are optional
+				Name:        "sqlc_query_duration_seconds",
+				Help:        "Duration of database queries in seconds by query, engine and status",
+				Buckets:     []float64{0.001, 0.005, 0.01, 0.05, 0.1, 0.5, 1, 5},
+				Namespace:   metricNamespace,
+				Subsystem:   "query",
+				ConstLabels: nil,
+			},
+			queryLabels,
 		),
-		QueryTotal: newCounter(
-			"sqlc_query_total",
-			"Total number of database queries executed",
-			"query",
+		QueryErrors: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name:        "sqlc_query_errors_total",
+				Help:        "Total number of database query errors by query and engine",
+				Namespace:   metricNamespace,
+				Subsystem:   "query",
+				ConstLabels: nil,
+			},
+			[]string{"query", "engine"},
+		),
+		QueryTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name:        "sqlc_query_total",
+				Help:        "Total number of database queries executed by query, engine and status",
+				Namespace:   metricNamespace,
+				Subsystem:   "query",
+				ConstLabels: nil,
+			},
+			queryLabels,
+		),
+		QueryTimeouts: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name:        "sqlc_query_timeouts_total",
+				Help:        "Total number of database queries that exceeded their deadline by query and class",
+				Namespace:   metricNamespace,
+				Subsystem:   "query",
+				ConstLabels: nil,
+			},
+			[]string{"query", "class"},
 		),
 		ActiveConnections: newGauge(
 			"sqlc_database_connections_active",
@@ -175,6 +244,110 @@ func newMetrics(registry *prometheus.Registry) *Metrics {
 			"session",
 		),
 
+		RPCDuration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{ //nolint:exhaustruct // NativeHistogram fields are optional
+				Name:        "sqlc_rpc_duration_seconds",
+				Help:        "Duration of RPC calls in seconds by method and status code",
+				Buckets:     prometheus.DefBuckets,
+				Namespace:   metricNamespace,
+				Subsystem:   "rpc",
+				ConstLabels: nil,
+			},
+			[]string{"method", "code"},
+		),
+
+		RateLimitDecisions: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name:        "sqlc_rate_limit_decisions_total",
+				Help:        "Total number of rate limiter decisions by scope and decision",
+				Namespace:   metricNamespace,
+				Subsystem:   "ratelimit",
+				ConstLabels: nil,
+			},
+			[]string{"scope", "decision"},
+		),
+
+		CircuitBreakerState: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        "sqlc_circuit_breaker_state",
+				Help:        "Circuit breaker state by dependency; 1 for the current state, which is closed, half_open or open",
+				Namespace:   metricNamespace,
+				Subsystem:   "resilience",
+				ConstLabels: nil,
+			},
+			[]string{"name", "state"},
+		),
+		Retries: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name:        "sqlc_retries_total",
+				Help:        "Total number of retried calls after transient failures by dependency",
+				Namespace:   metricNamespace,
+				Subsystem:   "resilience",
+				ConstLabels: nil,
+			},
+			[]string{"name"},
+		),
+
+		EventBufferDepth: newGauge(
+			"sqlc_event_buffer_depth",
+			"Number of events waiting in the event buffer to be published",
+			"events",
+		),
+		EventsDropped: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name:        "sqlc_events_dropped_total",
+				Help:        "Total number of buffered events that were not published by reason",
+				Namespace:   metricNamespace,
+				Subsystem:   "events",
+				ConstLabels: nil,
+			},
+			[]string{"reason"},
+		),
+
+		UserReadModelLag: newGauge(
+			"sqlc_user_read_model_lag_seconds",
+			"Seconds between the last projected user event and its projection into the user read model",
+			"read_model",
+		),
+
+		HTTPRequests: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name:        "sqlc_http_requests_total",
+				Help:        "Total number of HTTP requests by method, route and status code",
+				Namespace:   metricNamespace,
+				Subsystem:   "http",
+				ConstLabels: nil,
+			},
+			httpLabels,
+		),
+		HTTPDuration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{ //nolint:exhaustruct // NativeHistogram fields are optional
+				Name:        "sqlc_http_request_duration_seconds",
+				Help:        "Duration of HTTP requests in seconds by method, route and status code",
+				Buckets:     prometheus.DefBuckets,
+				Namespace:   metricNamespace,
+				Subsystem:   "http",
+				ConstLabels: nil,
+			},
+			httpLabels,
+		),
+		HTTPResponseSize: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{ //nolint:exhaustruct // NativeHistogram fields are optional
+				Name:        "sqlc_http_response_size_bytes",
+				Help:        "Size of HTTP response bodies in bytes by method, route and status code",
+				Buckets:     prometheus.ExponentialBuckets(100, 10, 6),
+				Namespace:   metricNamespace,
+				Subsystem:   "http",
+				ConstLabels: nil,
+			},
+			httpLabels,
+		),
+		HTTPInFlight: newGauge(
+			"sqlc_http_requests_in_flight",
+			"Number of HTTP requests being served",
+			"http",
+		),
+
 		ConfigFileSize: newGauge(
 			"sqlc_config_file_size_bytes",
 			"Size of sqlc configuration file in bytes",
@@ -203,7 +376,11 @@ func newMetrics(registry *prometheus.Registry) *Metrics {
 			"build",
 		),
 
+		queryNames: newLabelGuard(defaultQueryNameLimit),
+		engines:    newLabelGuard(engineLimit),
+
 		registry: registry,
+		health:   NewHealthChecker(),
 	}
 
 	registry.MustRegister(
@@ -213,12 +390,24 @@ func newMetrics(registry *prometheus.Registry) *Metrics {
 		metrics.QueryDuration,
 		metrics.QueryErrors,
 		metrics.QueryTotal,
+		metrics.QueryTimeouts,
 		metrics.ActiveConnections,
 		metrics.UserOperations,
 		metrics.UserCreations,
 		metrics.UserAuthentications,
 		metrics.SessionCreations,
 		metrics.SessionActive,
+		metrics.RPCDuration,
+		metrics.RateLimitDecisions,
+		metrics.CircuitBreakerState,
+		metrics.Retries,
+		metrics.EventBufferDepth,
+		metrics.EventsDropped,
+		metrics.UserReadModelLag,
+		metrics.HTTPRequests,
+		metrics.HTTPDuration,
+		metrics.HTTPResponseSize,
+		metrics.HTTPInFlight,
 		metrics.ConfigFileSize,
 		metrics.ConfigDatabase,
 		metrics.BuildDuration,
@@ -250,9 +439,23 @@ func (m *Metrics) ObserveCodeGen(duration time.Duration, err error) {
 	m.observeDurationWithErrors(m.CodeGenTotal, m.CodeGenDuration, duration, err, m.CodeGenErrors)
 }
 
-// ObserveQuery records metrics for database queries.
-func (m *Metrics) ObserveQuery(duration time.Duration, err error) {
-	m.observeDurationWithErrors(m.QueryTotal, m.QueryDuration, duration, err, m.QueryErrors)
+// exemplarObserver attaches the trace ID of ctx to observations.
+type exemplarObserver struct {
+	observer prometheus.Observer
+	ctx      context.Context //nolint:containedctx // Scoped to a single observation
+}
+
+func (o exemplarObserver) Observe(value float64) {
+	spanContext := trace.SpanContextFromContext(o.ctx)
+
+	exemplars, ok := o.observer.(prometheus.ExemplarObserver)
+	if !ok || !spanContext.IsSampled() {
+		o.observer.Observe(value)
+
+		return
+	}
+
+	exemplars.ObserveWithExemplar(value, prometheus.Labels{"trace_id": spanContext.TraceID().String()})
 }
 
 // RecordUserCreation records a user creation operation.
@@ -278,6 +481,49 @@ func (m *Metrics) SetActiveSessions(count int64) {
 	m.SessionActive.Set(float64(count))
 }
 
+// ObserveRPC records the duration and status code of an RPC call.
+func (m *Metrics) ObserveRPC(method, code string, duration time.Duration) {
+	m.RPCDuration.WithLabelValues(method, code).Observe(duration.Seconds())
+}
+
+// ObserveRateLimit records whether a rate limiter allowed a request in scope.
+func (m *Metrics) ObserveRateLimit(scope string, allowed bool) {
+	decision := "limited"
+	if allowed {
+		decision = "allowed"
+	}
+
+	m.RateLimitDecisions.WithLabelValues(scope, decision).Inc()
+}
+
+// ObserveCircuitState records that the circuit breaker of name changed to state.
+func (m *Metrics) ObserveCircuitState(name, state string) {
+	m.CircuitBreakerState.DeletePartialMatch(prometheus.Labels{"name": name})
+	m.CircuitBreakerState.WithLabelValues(name, state).Set(1)
+}
+
+// ObserveRetry records a retry of a call to name after a transient failure.
+func (m *Metrics) ObserveRetry(name string) {
+	m.Retries.WithLabelValues(name).Inc()
+}
+
+// ObserveEventBuffer records the number of events waiting to be published.
+func (m *Metrics) ObserveEventBuffer(depth int) {
+	m.EventBufferDepth.Set(float64(depth))
+}
+
+// ObserveEventsDropped records count events that were not published for
+// reason, e.g. "overflow".
+func (m *Metrics) ObserveEventsDropped(reason string, count int) {
+	m.EventsDropped.WithLabelValues(reason).Add(float64(count))
+}
+
+// ObserveReadModelLag records how long the last event took to be projected
+// into the user read model.
+func (m *Metrics) ObserveReadModelLag(lag time.Duration) {
+	m.UserReadModelLag.Set(lag.Seconds())
+}
+
 // SetActiveConnections sets the number of active database connections.
 func (m *Metrics) SetActiveConnections(count int64) {
 	m.ActiveConnections.Set(float64(count))
@@ -304,37 +550,136 @@ func (m *Metrics) ObserveBuild(duration time.Duration, success bool) {
 	}
 }
 
-// StartServer starts the metrics HTTP server.
-func (m *Metrics) StartServer(addr string) error {
+// SetHealthChecker replaces the checks served on /healthz and /readyz.
+// It must be called before StartServer.
+func (m *Metrics) SetHealthChecker(checker *HealthChecker) {
+	m.health = checker
+}
+
+// HealthChecker returns the checks served on /healthz and /readyz.
+func (m *Metrics) HealthChecker() *HealthChecker {
+	return m.health
+}
+
+// ServerOption configures the optional endpoints of the metrics server.
+type ServerOption func(*serverConfig)
+
+// serverConfig selects the optional endpoints of the metrics server.
+type serverConfig struct {
+	profiling      bool
+	runtimeMetrics bool
+}
+
+// WithProfiling mounts the net/http/pprof handlers under /debug/pprof/ when
+// enabled. Profiles expose internals, so only enable it on a private address.
+func WithProfiling(enabled bool) ServerOption {
+	return func(cfg *serverConfig) {
+		cfg.profiling = enabled
+	}
+}
+
+// WithRuntimeMetrics exports the Go runtime (GC, goroutines, memstats),
+// process and build info collectors when enabled.
+func WithRuntimeMetrics(enabled bool) ServerOption {
+	return func(cfg *serverConfig) {
+		cfg.runtimeMetrics = enabled
+	}
+}
+
+// Handler returns the handler of the metrics server.
+func (m *Metrics) Handler(opts ...ServerOption) (http.Handler, error) {
+	var cfg serverConfig
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if cfg.runtimeMetrics {
+		err := m.registerRuntimeCollectors()
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	mux := http.NewServeMux()
 	mux.Handle(
 		"/metrics",
 		promhttp.HandlerFor(
 			m.registry,
-			promhttp.HandlerOpts{ //nolint:exhaustruct // Only DisableCompression needed
+			promhttp.HandlerOpts{ //nolint:exhaustruct // Only compression and exemplars needed
 				DisableCompression: false,
+				EnableOpenMetrics:  true,
 			},
 		),
 	)
-	mux.HandleFunc("/", func(w http.ResponseWriter, _ *http.Request) {
-		_, _ = w.Write([]byte(`<html><head><title>sqlc Metrics</title></head>
-<body><h1>sqlc Metrics</h1>
-<p><a href="/metrics">Metrics</a></p>
-<p><a href="/health">Health Check</a></p>
-</body></html>`))
+	mux.HandleFunc("/{$}", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(indexPage(cfg.profiling)))
 	})
 	mux.HandleFunc("/health", func(w http.ResponseWriter, _ *http.Request) {
 		w.WriteHeader(http.StatusOK)
 		_, _ = w.Write([]byte("OK"))
 	})
+	mux.Handle("/healthz", m.health.LivenessHandler())
+	mux.Handle("/readyz", m.health.ReadinessHandler())
+
+	if cfg.profiling {
+		mux.HandleFunc("/debug/pprof/", pprof.Index)
+		mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+		mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+		mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+		mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	}
+
+	return mux, nil
+}
+
+// registerRuntimeCollectors registers the Go runtime, process and build info
+// collectors, tolerating repeated registration.
+func (m *Metrics) registerRuntimeCollectors() error {
+	for _, collector := range []prometheus.Collector{
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}), //nolint:exhaustruct // Defaults
+		collectors.NewBuildInfoCollector(),
+	} {
+		err := m.registry.Register(collector)
+		if err != nil && !errors.As(err, &prometheus.AlreadyRegisteredError{}) {
+			return fmt.Errorf("register runtime collector: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// indexPage renders the links of the metrics server.
+func indexPage(profiling bool) string {
+	page := `<html><head><title>sqlc Metrics</title></head>
+<body><h1>sqlc Metrics</h1>
+<p><a href="/metrics">Metrics</a></p>
+<p><a href="/health">Health Check</a></p>
+<p><a href="/healthz">Liveness</a></p>
+<p><a href="/readyz">Readiness</a></p>
+`
+	if profiling {
+		page += "<p><a href=\"/debug/pprof/\">Profiling</a></p>\n"
+	}
+
+	return page + "</body></html>"
+}
+
+// StartServer starts the metrics HTTP server.
+func (m *Metrics) StartServer(addr string, opts ...ServerOption) error {
+	handler, err := m.Handler(opts...)
+	if err != nil {
+		return fmt.Errorf("metrics handler addr=%v: %w", addr, err)
+	}
 
 	m.server = &http.Server{ //nolint:exhaustruct // Only required fields needed
 		Addr:              addr,
-		Handler:           mux,
+		Handler:           handler,
 		ReadHeaderTimeout: readHeaderTimeout,
 	}
 
-	err := m.server.ListenAndServe()
+	err = m.server.ListenAndServe()
 	if err != nil && !errors.Is(err, http.ErrServerClosed) {
 		return fmt.Errorf("server listen error addr=%v: %w", addr, err)
 	}
@@ -354,24 +699,43 @@ func (m *Metrics) Shutdown(ctx context.Context) error {
 	return nil
 }
 
-// Middleware for request tracking.
+// Middleware records the count, duration, response size and in-flight
+// number of HTTP requests. Requests are labeled with the ServeMux pattern that
+// matched them, so next should be, or wrap, an *http.ServeMux.
 func (m *Metrics) Middleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		m.HTTPInFlight.Inc()
+		defer m.HTTPInFlight.Dec()
+
 		start := time.Now()
 
 		wrapped := &responseWriter{ResponseWriter: writer, statusCode: http.StatusOK}
 
 		next.ServeHTTP(wrapped, req)
 
-		m.QueryDuration.Observe(time.Since(start).Seconds())
+		route := req.Pattern
+		if route == "" {
+			route = unmatchedRoute
+		}
+
+		labels := prometheus.Labels{
+			"method": req.Method,
+			"route":  route,
+			"code":   strconv.Itoa(wrapped.statusCode),
+		}
+
+		m.HTTPRequests.With(labels).Inc()
+		m.HTTPDuration.With(labels).Observe(time.Since(start).Seconds())
+		m.HTTPResponseSize.With(labels).Observe(float64(wrapped.written))
 	})
 }
 
-// responseWriter wraps http.ResponseWriter to capture status code.
+// responseWriter wraps http.ResponseWriter to capture status code and body size.
 type responseWriter struct {
 	http.ResponseWriter
 
 	statusCode int
+	written    int
 }
 
 func (rw *responseWriter) WriteHeader(code int) {
@@ -379,6 +743,18 @@ func (rw *responseWriter) WriteHeader(code int) {
 	rw.ResponseWriter.WriteHeader(code)
 }
 
+func (rw *responseWriter) Write(body []byte) (int, error) {
+	n, err := rw.ResponseWriter.Write(body)
+	rw.written += n
+
+	return n, err //nolint:wrapcheck // Pass-through of the wrapped writer
+}
+
 func (rw *responseWriter) StatusCode() int {
 	return rw.statusCode
 }
+
+// Unwrap returns the wrapped writer for http.ResponseController.
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
@@ -0,0 +1,140 @@
+package monitoring
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus" // DEPRECATED: prefer go.opentelemetry.io/otel
+)
+
+const (
+	// defaultQueryNameLimit bounds the distinct query names per metric.
+	defaultQueryNameLimit = 500
+	// engineLimit bounds the distinct database engines per metric.
+	engineLimit = 16
+	// maxLabelLength bounds the length of a query or engine label value.
+	maxLabelLength = 128
+
+	// OverflowLabel replaces label values beyond a cardinality limit.
+	OverflowLabel = "other"
+
+	queryStatusSuccess = "success"
+	queryStatusError   = "error"
+)
+
+// queryLabels are the labels of the query duration and count metrics.
+var queryLabels = []string{"query", "engine", "status"}
+
+// MetricsOption configures Metrics.
+type MetricsOption func(*Metrics)
+
+// WithQueryNameLimit bounds the distinct query names recorded. Further names
+// are recorded as OverflowLabel. Defaults to 500.
+func WithQueryNameLimit(limit int) MetricsOption {
+	return func(m *Metrics) {
+		if limit > 0 {
+			m.queryNames = newLabelGuard(limit)
+		}
+	}
+}
+
+// labelGuard caps the distinct values of a label. Values beyond the cap, and
+// values that do not look like identifiers, are replaced by OverflowLabel so
+// that unexpected input such as raw SQL cannot explode the series count.
+type labelGuard struct {
+	limit int
+
+	mu   sync.RWMutex
+	seen map[string]struct{}
+}
+
+func newLabelGuard(limit int) *labelGuard {
+	return &labelGuard{limit: limit, seen: make(map[string]struct{})}
+}
+
+// value returns the label value to record for value.
+func (g *labelGuard) value(value string) string {
+	if !isLabelIdentifier(value) {
+		return OverflowLabel
+	}
+
+	g.mu.RLock()
+	_, ok := g.seen[value]
+	g.mu.RUnlock()
+
+	if ok {
+		return value
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if _, ok := g.seen[value]; ok {
+		return value
+	}
+
+	if len(g.seen) >= g.limit {
+		return OverflowLabel
+	}
+
+	g.seen[value] = struct{}{}
+
+	return value
+}
+
+// isLabelIdentifier reports whether value is a short dotted identifier such
+// as "UserRepository.GetByID" or "GetUserByEmail".
+func isLabelIdentifier(value string) bool {
+	if value == "" || len(value) > maxLabelLength {
+		return false
+	}
+
+	for _, r := range value {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '.', r == '-':
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+// ObserveQuery records the duration and outcome of the named query on engine.
+func (m *Metrics) ObserveQuery(query, engine string, duration time.Duration, err error) {
+	m.ObserveQueryContext(context.Background(), query, engine, duration, err)
+}
+
+// ObserveQueryContext records a query like ObserveQuery and links the duration
+// to the sampled trace in ctx as an exemplar.
+func (m *Metrics) ObserveQueryContext(
+	ctx context.Context,
+	query, engine string,
+	duration time.Duration,
+	err error,
+) {
+	query = m.queryNames.value(query)
+	engine = m.engines.value(engine)
+
+	status := queryStatusSuccess
+	if err != nil {
+		status = queryStatusError
+	}
+
+	labels := prometheus.Labels{"query": query, "engine": engine, "status": status}
+
+	m.observeDurationWithErrors(
+		m.QueryTotal.With(labels),
+		exemplarObserver{m.QueryDuration.With(labels), ctx},
+		duration,
+		err,
+		m.QueryErrors.WithLabelValues(query, engine),
+	)
+}
+
+// ObserveQueryTimeout records that the named query of class, e.g. "read",
+// exceeded its deadline.
+func (m *Metrics) ObserveQueryTimeout(query, class string) {
+	m.QueryTimeouts.WithLabelValues(m.queryNames.value(query), class).Inc()
+}
@@ -0,0 +1,88 @@
+package monitoring
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+)
+
+// RedactingHandler is a slog.Handler that redacts the personal data in
+// record attributes before passing them to the next handler. Attributes are
+// recognized by their key as described by events.PIIFieldOf; the attributes
+// of a group with such a key are redacted as well.
+type RedactingHandler struct {
+	next   slog.Handler
+	policy events.RedactionPolicy
+}
+
+var _ slog.Handler = (*RedactingHandler)(nil)
+
+// NewRedactingHandler creates a handler that redacts attributes with policy
+// and writes the records to next.
+func NewRedactingHandler(next slog.Handler, policy events.RedactionPolicy) *RedactingHandler {
+	return &RedactingHandler{next: next, policy: policy}
+}
+
+// Enabled reports whether the next handler handles level.
+func (h *RedactingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle redacts the attributes of record and passes it on.
+func (h *RedactingHandler) Handle(ctx context.Context, record slog.Record) error {
+	if h.policy.KeepsAll() {
+		return h.next.Handle(ctx, record)
+	}
+
+	redacted := slog.NewRecord(record.Time, record.Level, record.Message, record.PC)
+	record.Attrs(func(attr slog.Attr) bool {
+		redacted.AddAttrs(h.redact(attr, ""))
+
+		return true
+	})
+
+	return h.next.Handle(ctx, redacted)
+}
+
+// WithAttrs returns a handler whose records carry the redacted attrs.
+func (h *RedactingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, 0, len(attrs))
+	for _, attr := range attrs {
+		redacted = append(redacted, h.redact(attr, ""))
+	}
+
+	return &RedactingHandler{next: h.next.WithAttrs(redacted), policy: h.policy}
+}
+
+// WithGroup returns a handler that nests the attributes of its records in name.
+func (h *RedactingHandler) WithGroup(name string) slog.Handler {
+	return &RedactingHandler{next: h.next.WithGroup(name), policy: h.policy}
+}
+
+// redact redacts attr if its key or that of an enclosing group names
+// personal data, given as field.
+func (h *RedactingHandler) redact(attr slog.Attr, field events.PIIField) slog.Attr {
+	if pii, ok := events.PIIFieldOf(attr.Key); ok {
+		field = pii
+	}
+
+	value := attr.Value.Resolve()
+
+	if value.Kind() == slog.KindGroup {
+		group := value.Group()
+		redacted := make([]slog.Attr, 0, len(group))
+
+		for _, nested := range group {
+			redacted = append(redacted, h.redact(nested, field))
+		}
+
+		return slog.Attr{Key: attr.Key, Value: slog.GroupValue(redacted...)}
+	}
+
+	if field == "" {
+		return attr
+	}
+
+	return slog.String(attr.Key, h.policy.Redact(field, value.String()))
+}
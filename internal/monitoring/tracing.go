@@ -0,0 +1,72 @@
+package monitoring
+
+import (
+	"context"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/services"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	tracerName = "github.com/LarsArtmann/template-sqlc/internal/domain/services"
+
+	traceParentKey = "traceparent"
+)
+
+// Tracer traces service operations with OpenTelemetry and carries their
+// trace across published events.
+type Tracer struct {
+	tracer     trace.Tracer
+	propagator propagation.TraceContext
+}
+
+// Ensure Tracer implements services.Tracer.
+var _ services.Tracer = (*Tracer)(nil)
+
+// NewTracer creates a Tracer from provider, typically an SDK TracerProvider
+// exporting spans over OTLP.
+func NewTracer(provider trace.TracerProvider) *Tracer {
+	return &Tracer{tracer: provider.Tracer(tracerName)}
+}
+
+// Start starts the span of a service operation.
+func (t *Tracer) Start(ctx context.Context, operation string) (context.Context, services.Span) {
+	ctx, span := t.tracer.Start(ctx, operation, trace.WithSpanKind(trace.SpanKindInternal))
+
+	return ctx, operationSpan{span: span}
+}
+
+// TraceParent returns the W3C traceparent of the span in ctx.
+func (t *Tracer) TraceParent(ctx context.Context) string {
+	carrier := propagation.MapCarrier{}
+	t.propagator.Inject(ctx, carrier)
+
+	return carrier.Get(traceParentKey)
+}
+
+// ContextFromEvent returns ctx carrying the span that emitted event, so that
+// event handlers continue its trace.
+func (t *Tracer) ContextFromEvent(ctx context.Context, event *events.UserEvent) context.Context {
+	if event.TraceParent == "" {
+		return ctx
+	}
+
+	return t.propagator.Extract(ctx, propagation.MapCarrier{traceParentKey: event.TraceParent})
+}
+
+// operationSpan adapts an OpenTelemetry span to services.Span.
+type operationSpan struct {
+	span trace.Span
+}
+
+func (s operationSpan) End(err error) {
+	if err != nil {
+		s.span.RecordError(err)
+		s.span.SetStatus(codes.Error, err.Error())
+	}
+
+	s.span.End()
+}
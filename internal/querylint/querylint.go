@@ -0,0 +1,225 @@
+// Package querylint statically checks sqlc query files for dangerous
+// patterns: SELECT *, unbounded writes, leading wildcard LIKE, list queries
+// without LIMIT, predicates no index covers and engine-specific pitfalls.
+//
+// Checks work on the query text and the CREATE TABLE and CREATE INDEX
+// statements of the schema; they do not connect to a database. A query can
+// silence a rule with a comment inside its block:
+//
+//	-- name: ListAllRoles :many
+//	-- lint:ignore missing-limit
+//	SELECT role FROM roles;
+package querylint
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Severity ranks a finding.
+type Severity string
+
+const (
+	// SeverityError marks queries that are wrong or dangerous.
+	SeverityError Severity = "error"
+	// SeverityWarning marks queries that will likely perform badly.
+	SeverityWarning Severity = "warning"
+	// SeverityInfo marks patterns worth knowing about.
+	SeverityInfo Severity = "info"
+)
+
+// Engine names, matching the sql/<engine> directories.
+const (
+	EngineSQLite   = "sqlite"
+	EnginePostgres = "postgres"
+	EngineMySQL    = "mysql"
+)
+
+// Finding is a rule violation in one query.
+type Finding struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Engine   string   `json:"engine"`
+	File     string   `json:"file"`
+	Line     int      `json:"line"`
+	Query    string   `json:"query"`
+	Message  string   `json:"message"`
+}
+
+// String formats the finding like a compiler diagnostic.
+func (f Finding) String() string {
+	return fmt.Sprintf("%s:%d: %s [%s] %s: %s", f.File, f.Line, f.Severity, f.Rule, f.Query, f.Message)
+}
+
+// Report collects the findings of a run.
+type Report struct {
+	Findings []Finding `json:"findings"`
+}
+
+// Count returns the number of findings with severity.
+func (r *Report) Count(severity Severity) int {
+	count := 0
+
+	for _, finding := range r.Findings {
+		if finding.Severity == severity {
+			count++
+		}
+	}
+
+	return count
+}
+
+// Query is a named sqlc query.
+type Query struct {
+	Name string
+	// Command is the sqlc command without colon, e.g. "many" or "exec".
+	Command string
+	File    string
+	// Line is the line of the "-- name:" annotation.
+	Line int
+	SQL  string
+	// Ignored are the rules silenced with lint:ignore.
+	Ignored []string
+}
+
+var (
+	nameAnnotation   = regexp.MustCompile(`^--\s*name:\s*(\w+)\s+:(\w+)`)
+	ignoreAnnotation = regexp.MustCompile(`^--\s*lint:ignore\s+(.+)$`)
+)
+
+// ParseQueries reads the named queries of the .sql files in dir.
+func ParseQueries(dir string) ([]Query, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.sql"))
+	if err != nil {
+		return nil, fmt.Errorf("dir=%v: %w", dir, err)
+	}
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("dir=%v: %w", dir, os.ErrNotExist)
+	}
+
+	var queries []Query
+
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("file=%v: %w", file, err)
+		}
+
+		queries = append(queries, parseQueryFile(filepath.Base(file), content)...)
+	}
+
+	return queries, nil
+}
+
+func parseQueryFile(file string, content []byte) []Query {
+	var (
+		queries []Query
+		current *Query
+		body    strings.Builder
+	)
+
+	flush := func() {
+		if current != nil {
+			current.SQL = strings.TrimSpace(body.String())
+			queries = append(queries, *current)
+		}
+
+		body.Reset()
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+
+		if match := nameAnnotation.FindStringSubmatch(text); match != nil {
+			flush()
+
+			current = &Query{Name: match[1], Command: match[2], File: file, Line: line}
+
+			continue
+		}
+
+		if current == nil {
+			continue
+		}
+
+		if match := ignoreAnnotation.FindStringSubmatch(text); match != nil {
+			current.Ignored = append(current.Ignored, strings.Fields(strings.ReplaceAll(match[1], ",", " "))...)
+
+			continue
+		}
+
+		body.WriteString(scanner.Text())
+		body.WriteByte('\n')
+	}
+
+	flush()
+
+	return queries
+}
+
+// Lint checks queries of engine against rules, using indexes for the
+// unindexed-predicate rule. Findings are ordered by file and line.
+func Lint(engine string, queries []Query, indexes *Indexes) []Finding {
+	var findings []Finding
+
+	for _, query := range queries {
+		analyzed := analyze(query.SQL)
+
+		for _, rule := range rules {
+			if slices.Contains(query.Ignored, rule.id) {
+				continue
+			}
+
+			for _, message := range rule.check(query, analyzed, indexes, engine) {
+				findings = append(findings, Finding{
+					Rule:     rule.id,
+					Severity: rule.severity,
+					Engine:   engine,
+					File:     query.File,
+					Line:     query.Line,
+					Query:    query.Name,
+					Message:  message,
+				})
+			}
+		}
+	}
+
+	slices.SortStableFunc(findings, func(a, b Finding) int {
+		if c := strings.Compare(a.File, b.File); c != 0 {
+			return c
+		}
+
+		return a.Line - b.Line
+	})
+
+	return findings
+}
+
+// LintDir lints the queries in queriesDir against the schema in schemaDir.
+// An empty schemaDir skips the unindexed-predicate rule.
+func LintDir(engine, queriesDir, schemaDir string) (*Report, error) {
+	queries, err := ParseQueries(queriesDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var indexes *Indexes
+
+	if schemaDir != "" {
+		indexes, err = ParseSchema(engine, schemaDir)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &Report{Findings: Lint(engine, queries, indexes)}, nil
+}
@@ -0,0 +1,195 @@
+package querylint
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Rule IDs.
+const (
+	RuleSelectStar         = "select-star"
+	RuleUnboundedWrite     = "unbounded-write"
+	RuleLeadingWildcard    = "leading-wildcard"
+	RuleMissingLimit       = "missing-limit"
+	RuleUnindexedPredicate = "unindexed-predicate"
+	RuleRandomOrder        = "random-order"
+	RuleOffsetPagination   = "offset-pagination"
+	RuleNotInSubquery      = "not-in-subquery"
+	RuleUnsupportedSyntax  = "unsupported-syntax"
+)
+
+// rule is one check; engine-specific checks look up the engine themselves.
+type rule struct {
+	id       string
+	severity Severity
+	check    func(query Query, sql *statement, indexes *Indexes, engine string) []string
+}
+
+// rules are run in this order for every query.
+var rules = []rule{
+	{id: RuleUnboundedWrite, severity: SeverityError, check: checkUnboundedWrite},
+	{id: RuleUnsupportedSyntax, severity: SeverityError, check: checkUnsupportedSyntax},
+	{id: RuleSelectStar, severity: SeverityWarning, check: matches(selectStar,
+		"SELECT * breaks when columns are added or reordered; list the columns")},
+	{id: RuleLeadingWildcard, severity: SeverityWarning, check: matches(leadingWildcard,
+		"LIKE with a leading wildcard cannot use an index; use full-text search")},
+	{id: RuleMissingLimit, severity: SeverityWarning, check: checkMissingLimit},
+	{id: RuleUnindexedPredicate, severity: SeverityWarning, check: checkUnindexedPredicate},
+	{id: RuleRandomOrder, severity: SeverityWarning, check: matches(randomOrder,
+		"ORDER BY RANDOM() sorts the whole table; sample by key instead")},
+	{id: RuleNotInSubquery, severity: SeverityWarning, check: matches(notInSubquery,
+		"NOT IN (SELECT ...) matches nothing if the subquery returns NULL; use NOT EXISTS")},
+	{id: RuleOffsetPagination, severity: SeverityInfo, check: matches(offset,
+		"OFFSET reads and discards the skipped rows; prefer keyset pagination for deep pages")},
+}
+
+var (
+	selectStar      = regexp.MustCompile(`\bselect\s+(?:distinct\s+)?(?:\w+\.)?\*`)
+	leadingWildcard = regexp.MustCompile(`\b(?:i?like)\s+(?:'%|concat\s*\(\s*'%'|'%'\s*\|\|)`)
+	randomOrder     = regexp.MustCompile(`\border\s+by\s+(?:random|rand)\s*\(`)
+	notInSubquery   = regexp.MustCompile(`\bnot\s+in\s*\(\s*select\b`)
+	offset          = regexp.MustCompile(`\boffset\b`)
+	limit           = regexp.MustCompile(`\blimit\b`)
+	boundedByInput  = regexp.MustCompile(`=\s*any\s*\(|\bin\s*\(\s*sqlc\.slice|sqlc\.slice\s*\(`)
+	groupBy         = regexp.MustCompile(`\bgroup\s+by\b`)
+	predicate       = regexp.MustCompile(`(?:\b\w+\.)?\b([a-z_]\w*)\s*(?:=|<>|!=|<=|>=|<|>|\bin\b|\bnot\s+in\b|\bi?like\b|\bis\b|\bbetween\b|@@)`)
+	keywords        = []string{"and", "or", "not", "where", "sqlc", "coalesce", "lower", "upper", "null", "true", "false"}
+)
+
+// unsupported lists syntax an engine rejects, by engine.
+var unsupported = map[string][]struct {
+	pattern *regexp.Regexp
+	message string
+}{
+	EngineSQLite: {
+		{regexp.MustCompile(`\bilike\b`), "SQLite has no ILIKE; LIKE is case-insensitive for ASCII"},
+		{regexp.MustCompile(`=\s*any\s*\(`), "SQLite has no = ANY(array); use sqlc.slice with IN"},
+		{regexp.MustCompile(`::\w`), "SQLite has no :: casts; use CAST(x AS type)"},
+		{regexp.MustCompile(`\bfor\s+update\b`), "SQLite has no SELECT ... FOR UPDATE; use BEGIN IMMEDIATE"},
+	},
+	EngineMySQL: {
+		{regexp.MustCompile(`\bilike\b`), "MySQL has no ILIKE; use LIKE with a case-insensitive collation"},
+		{regexp.MustCompile(`\breturning\b`), "MySQL has no RETURNING; select the row after writing it"},
+		{regexp.MustCompile(`=\s*any\s*\(`), "MySQL has no = ANY(array); use sqlc.slice with IN"},
+		{regexp.MustCompile(`::\w`), "MySQL has no :: casts; use CAST(x AS type)"},
+	},
+	EnginePostgres: {
+		{regexp.MustCompile(`\blimit\s+[^\s,;]+\s*,`), "PostgreSQL has no LIMIT offset, count; use LIMIT count OFFSET offset"},
+		{regexp.MustCompile(`\bon\s+duplicate\s+key\b`), "PostgreSQL has no ON DUPLICATE KEY; use ON CONFLICT"},
+	},
+}
+
+// statement is the normalized text of a query and the parts the rules need.
+type statement struct {
+	// code is the lower-cased query without comments, on one line.
+	code  string
+	kind  string
+	table string
+	where string
+	// simple is false for joins and subqueries, which the predicate rule skips.
+	simple bool
+}
+
+var (
+	whitespace  = regexp.MustCompile(`\s+`)
+	stringLit   = regexp.MustCompile(`'(?:[^']|'')*'`)
+	mainTable   = regexp.MustCompile(`^(?:select\b.*?\bfrom|update|delete\s+from|insert\s+into)\s+(\w+)`)
+	whereClause = regexp.MustCompile(`\bwhere\b(.*?)(?:\bgroup\s+by\b|\border\s+by\b|\blimit\b|\breturning\b|\bfor\s+update\b|\boffset\b|;|$)`)
+	complexity  = regexp.MustCompile(`\bjoin\b|\(\s*select\b|\bunion\b|^with\b`)
+)
+
+func analyze(sql string) *statement {
+	code := strings.ToLower(strings.TrimSpace(whitespace.ReplaceAllString(stripComments(sql), " ")))
+	stmt := &statement{code: code, simple: !complexity.MatchString(code)}
+
+	if fields := strings.Fields(code); len(fields) > 0 {
+		stmt.kind = fields[0]
+	}
+
+	if match := mainTable.FindStringSubmatch(code); match != nil {
+		stmt.table = match[1]
+	}
+
+	// Literals may contain keywords, so the clause is cut from blanked code.
+	blanked := stringLit.ReplaceAllString(code, "''")
+	if match := whereClause.FindStringSubmatch(blanked); match != nil {
+		stmt.where = match[1]
+	}
+
+	return stmt
+}
+
+func matches(pattern *regexp.Regexp, message string) func(Query, *statement, *Indexes, string) []string {
+	return func(_ Query, sql *statement, _ *Indexes, _ string) []string {
+		if pattern.MatchString(sql.code) {
+			return []string{message}
+		}
+
+		return nil
+	}
+}
+
+func checkUnboundedWrite(_ Query, sql *statement, _ *Indexes, _ string) []string {
+	if (sql.kind == "update" || sql.kind == "delete") && sql.where == "" {
+		return []string{fmt.Sprintf("%s without WHERE touches every row of %s", strings.ToUpper(sql.kind), sql.table)}
+	}
+
+	return nil
+}
+
+func checkUnsupportedSyntax(_ Query, sql *statement, _ *Indexes, engine string) []string {
+	var messages []string
+
+	for _, syntax := range unsupported[engine] {
+		if syntax.pattern.MatchString(sql.code) {
+			messages = append(messages, syntax.message)
+		}
+	}
+
+	return messages
+}
+
+func checkMissingLimit(query Query, sql *statement, _ *Indexes, _ string) []string {
+	if query.Command != "many" || (sql.kind != "select" && sql.kind != "with") {
+		return nil
+	}
+
+	// Grouped results are bounded by the distinct values of the grouping.
+	if limit.MatchString(sql.code) || boundedByInput.MatchString(sql.code) || groupBy.MatchString(sql.code) {
+		return nil
+	}
+
+	return []string{"list query has no LIMIT; page it or bound it by its input"}
+}
+
+func checkUnindexedPredicate(_ Query, sql *statement, indexes *Indexes, _ string) []string {
+	if indexes == nil || !sql.simple || sql.where == "" || !indexes.Table(sql.table) {
+		return nil
+	}
+
+	var columns []string
+
+	for _, match := range predicate.FindAllStringSubmatch(sql.where, -1) {
+		column := match[1]
+		if slices.Contains(keywords, column) || !indexes.HasColumn(sql.table, column) {
+			continue
+		}
+
+		if indexes.Indexed(sql.table, column) {
+			return nil
+		}
+
+		if !slices.Contains(columns, column) {
+			columns = append(columns, column)
+		}
+	}
+
+	if len(columns) == 0 {
+		return nil
+	}
+
+	return []string{fmt.Sprintf("no index leads with any of %s(%s); the WHERE scans the table",
+		sql.table, strings.Join(columns, ", "))}
+}
@@ -0,0 +1,205 @@
+package querylint
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Indexes are the columns of each table and the columns that lead an index,
+// a primary key or a unique constraint.
+type Indexes struct {
+	columns map[string]map[string]bool
+	leading map[string]map[string]bool
+}
+
+// Table reports whether the schema defines table.
+func (i *Indexes) Table(table string) bool {
+	_, ok := i.columns[table]
+
+	return ok
+}
+
+// HasColumn reports whether table has column.
+func (i *Indexes) HasColumn(table, column string) bool {
+	return i.columns[table][column]
+}
+
+// Indexed reports whether column leads an index of table.
+func (i *Indexes) Indexed(table, column string) bool {
+	return i.leading[table][column]
+}
+
+func (i *Indexes) addIndex(table, column string) {
+	if i.leading[table] == nil {
+		i.leading[table] = make(map[string]bool)
+	}
+
+	i.leading[table][column] = true
+}
+
+var (
+	createTable = regexp.MustCompile(`(?is)CREATE\s+TABLE\s+(?:IF\s+NOT\s+EXISTS\s+)?["` + "`" + `]?(\w+)["` + "`" + `]?\s*\(`)
+	createIndex = regexp.MustCompile(`(?is)CREATE\s+(?:UNIQUE\s+|FULLTEXT\s+)?INDEX\s+(?:IF\s+NOT\s+EXISTS\s+)?\w+\s+ON\s+(\w+)\s*(?:USING\s+\w+\s*)?\(\s*["` + "`" + `]?(\w+)["` + "`" + `]?\s*[,)\s]`)
+	addColumn   = regexp.MustCompile(`(?is)ALTER\s+TABLE\s+(\w+)\s+ADD\s+(?:COLUMN\s+)?(\w+)`)
+	renameCol   = regexp.MustCompile(`(?is)ALTER\s+TABLE\s+(\w+)\s+RENAME\s+COLUMN\s+(\w+)\s+TO\s+(\w+)`)
+	keyColumns  = regexp.MustCompile(`(?is)^(?:CONSTRAINT\s+\w+\s+)?(?:PRIMARY\s+KEY|UNIQUE(?:\s+KEY|\s+INDEX)?(?:\s+\w+)?|KEY\s+\w+|INDEX\s+\w+)\s*\(\s*["` + "`" + `]?(\w+)`)
+	references  = regexp.MustCompile(`(?i)\bREFERENCES\b`)
+)
+
+// nonColumns start table elements that are not column definitions.
+var nonColumns = []string{"PRIMARY", "UNIQUE", "KEY", "INDEX", "CONSTRAINT", "FOREIGN", "CHECK", "FULLTEXT"}
+
+// ParseSchema reads the indexes of the .sql files in dir, in file order, so
+// later migrations see the tables of earlier ones.
+func ParseSchema(engine, dir string) (*Indexes, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.sql"))
+	if err != nil {
+		return nil, fmt.Errorf("dir=%v: %w", dir, err)
+	}
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("dir=%v: %w", dir, os.ErrNotExist)
+	}
+
+	indexes := &Indexes{
+		columns: make(map[string]map[string]bool),
+		leading: make(map[string]map[string]bool),
+	}
+
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("file=%v: %w", file, err)
+		}
+
+		parseSchema(engine, stripComments(string(content)), indexes)
+	}
+
+	return indexes, nil
+}
+
+func parseSchema(engine, schema string, indexes *Indexes) {
+	for _, match := range createTable.FindAllStringSubmatchIndex(schema, -1) {
+		table := strings.ToLower(schema[match[2]:match[3]])
+		body := enclosed(schema[match[1]-1:])
+
+		parseTable(engine, table, body, indexes)
+	}
+
+	for _, match := range createIndex.FindAllStringSubmatch(schema, -1) {
+		indexes.addIndex(strings.ToLower(match[1]), strings.ToLower(match[2]))
+	}
+
+	for _, match := range addColumn.FindAllStringSubmatch(schema, -1) {
+		table := strings.ToLower(match[1])
+		if indexes.columns[table] != nil {
+			indexes.columns[table][strings.ToLower(match[2])] = true
+		}
+	}
+
+	for _, match := range renameCol.FindAllStringSubmatch(schema, -1) {
+		table, from, to := strings.ToLower(match[1]), strings.ToLower(match[2]), strings.ToLower(match[3])
+		if indexes.columns[table][from] {
+			delete(indexes.columns[table], from)
+			indexes.columns[table][to] = true
+		}
+	}
+}
+
+// parseTable records the columns of a CREATE TABLE body and the columns that
+// lead its keys. MySQL indexes foreign key columns implicitly.
+func parseTable(engine, table, body string, indexes *Indexes) {
+	columns := make(map[string]bool)
+	indexes.columns[table] = columns
+
+	for _, element := range splitTopLevel(body) {
+		element = strings.TrimSpace(element)
+		fields := strings.Fields(element)
+
+		if len(fields) == 0 {
+			continue
+		}
+
+		first := strings.ToUpper(fields[0])
+
+		if slices.Contains(nonColumns, first) {
+			if match := keyColumns.FindStringSubmatch(element); match != nil {
+				indexes.addIndex(table, strings.ToLower(match[1]))
+			}
+
+			continue
+		}
+
+		column := strings.ToLower(strings.Trim(fields[0], "\"`"))
+		columns[column] = true
+		upper := strings.ToUpper(element)
+
+		if strings.Contains(upper, "PRIMARY KEY") || strings.Contains(upper, " UNIQUE") {
+			indexes.addIndex(table, column)
+		}
+
+		if engine == EngineMySQL && references.MatchString(element) {
+			indexes.addIndex(table, column)
+		}
+	}
+}
+
+// enclosed returns the text inside the parenthesis s starts with.
+func enclosed(s string) string {
+	depth := 0
+
+	for i, r := range s {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return s[1:i]
+			}
+		}
+	}
+
+	return s
+}
+
+// splitTopLevel splits s on commas outside parentheses.
+func splitTopLevel(s string) []string {
+	var (
+		parts []string
+		depth int
+		start int
+	)
+
+	for i, r := range s {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+
+	return append(parts, s[start:])
+}
+
+// stripComments removes -- line comments.
+func stripComments(sql string) string {
+	lines := strings.Split(sql, "\n")
+	for i, line := range lines {
+		if index := strings.Index(line, "--"); index >= 0 {
+			lines[i] = line[:index]
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,125 @@
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// checkpointFileMode is the permission used when writing checkpoint files.
+const checkpointFileMode = 0o600
+
+// CheckpointStore persists the last applied event position per projection.
+type CheckpointStore interface {
+	// Load returns the saved position, or zero if the projection has none.
+	Load(ctx context.Context, projection string) (int64, error)
+	Save(ctx context.Context, projection string, position int64) error
+}
+
+// InMemoryCheckpointStore keeps checkpoints in memory.
+type InMemoryCheckpointStore struct {
+	mu        sync.RWMutex
+	positions map[string]int64
+}
+
+// NewInMemoryCheckpointStore creates a new InMemoryCheckpointStore.
+func NewInMemoryCheckpointStore() *InMemoryCheckpointStore {
+	return &InMemoryCheckpointStore{
+		mu:        sync.RWMutex{},
+		positions: make(map[string]int64),
+	}
+}
+
+// Load returns the saved position for a projection.
+func (s *InMemoryCheckpointStore) Load(_ context.Context, projection string) (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.positions[projection], nil
+}
+
+// Save stores the position for a projection.
+func (s *InMemoryCheckpointStore) Save(_ context.Context, projection string, position int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.positions[projection] = position
+
+	return nil
+}
+
+// FileCheckpointStore keeps checkpoints in a JSON file so CLI runs can resume.
+type FileCheckpointStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFileCheckpointStore creates a checkpoint store backed by the given file.
+// The file is created on the first save.
+func NewFileCheckpointStore(path string) *FileCheckpointStore {
+	return &FileCheckpointStore{
+		mu:   sync.Mutex{},
+		path: path,
+	}
+}
+
+// Load returns the saved position for a projection.
+func (s *FileCheckpointStore) Load(_ context.Context, projection string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	positions, err := s.read()
+	if err != nil {
+		return 0, err
+	}
+
+	return positions[projection], nil
+}
+
+// Save stores the position for a projection.
+func (s *FileCheckpointStore) Save(_ context.Context, projection string, position int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	positions, err := s.read()
+	if err != nil {
+		return err
+	}
+
+	positions[projection] = position
+
+	data, err := json.MarshalIndent(positions, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode checkpoints: %w", err)
+	}
+
+	err = os.WriteFile(s.path, data, checkpointFileMode)
+	if err != nil {
+		return fmt.Errorf("write checkpoints path=%s: %w", s.path, err)
+	}
+
+	return nil
+}
+
+func (s *FileCheckpointStore) read() (map[string]int64, error) {
+	positions := make(map[string]int64)
+
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return positions, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("read checkpoints path=%s: %w", s.path, err)
+	}
+
+	err = json.Unmarshal(data, &positions)
+	if err != nil {
+		return nil, fmt.Errorf("decode checkpoints path=%s: %w", s.path, err)
+	}
+
+	return positions, nil
+}
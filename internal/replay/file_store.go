@@ -0,0 +1,50 @@
+package replay
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+)
+
+// maxEventLineSize bounds a single JSON-encoded event in an export.
+const maxEventLineSize = 1 << 20
+
+// LoadEvents reads newline-delimited JSON events into an in-memory event store.
+// Events receive positions in file order, starting at 1.
+func LoadEvents(ctx context.Context, r io.Reader) (*events.InMemoryEventStore, error) {
+	store := events.NewInMemoryEventStore()
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxEventLineSize)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var event events.UserEvent
+
+		err := json.Unmarshal(scanner.Bytes(), &event)
+		if err != nil {
+			return nil, fmt.Errorf("decode event line=%d: %w", line, err)
+		}
+
+		err = store.Append(ctx, &event)
+		if err != nil {
+			return nil, fmt.Errorf("append event line=%d: %w", line, err)
+		}
+	}
+
+	err := scanner.Err()
+	if err != nil {
+		return nil, fmt.Errorf("scan events: %w", err)
+	}
+
+	return store, nil
+}
@@ -0,0 +1,109 @@
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+)
+
+// AuditProjection writes every event as a JSON line to an audit view.
+type AuditProjection struct {
+	mu  sync.Mutex
+	out io.Writer
+}
+
+// NewAuditProjection creates an audit projection writing to out.
+func NewAuditProjection(out io.Writer) *AuditProjection {
+	return &AuditProjection{
+		mu:  sync.Mutex{},
+		out: out,
+	}
+}
+
+// auditEntry is a single line of the audit view.
+type auditEntry struct {
+	Timestamp time.Time        `json:"timestamp"`
+	Type      events.EventType `json:"type"`
+	UserID    int64            `json:"userId"`
+	Data      any              `json:"data"`
+}
+
+// Name returns the projection name.
+func (p *AuditProjection) Name() string { return "audit" }
+
+// Reset is a no-op; the audit view is append-only and owned by the writer.
+func (p *AuditProjection) Reset(context.Context) error { return nil }
+
+// Apply appends the event to the audit view.
+func (p *AuditProjection) Apply(_ context.Context, event *events.UserEvent) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	line, err := json.Marshal(auditEntry{
+		Timestamp: event.Timestamp,
+		Type:      event.Type,
+		UserID:    event.UserID.Int64(),
+		Data:      event.Data,
+	})
+	if err != nil {
+		return fmt.Errorf("encode audit entry type=%s: %w", event.Type, err)
+	}
+
+	_, err = fmt.Fprintf(p.out, "%s\n", line)
+	if err != nil {
+		return fmt.Errorf("write audit entry type=%s: %w", event.Type, err)
+	}
+
+	return nil
+}
+
+// EventCountProjection is a read model counting events per type.
+type EventCountProjection struct {
+	mu     sync.RWMutex
+	counts map[events.EventType]int64
+}
+
+// NewEventCountProjection creates an empty event count read model.
+func NewEventCountProjection() *EventCountProjection {
+	return &EventCountProjection{
+		mu:     sync.RWMutex{},
+		counts: make(map[events.EventType]int64),
+	}
+}
+
+// Name returns the projection name.
+func (p *EventCountProjection) Name() string { return "event_counts" }
+
+// Reset clears all counts.
+func (p *EventCountProjection) Reset(context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.counts = make(map[events.EventType]int64)
+
+	return nil
+}
+
+// Apply increments the count for the event type.
+func (p *EventCountProjection) Apply(_ context.Context, event *events.UserEvent) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.counts[event.Type]++
+
+	return nil
+}
+
+// Counts returns a copy of the counts per event type.
+func (p *EventCountProjection) Counts() map[events.EventType]int64 {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return maps.Clone(p.counts)
+}
@@ -0,0 +1,248 @@
+// Package replay streams stored domain events through projections to rebuild
+// derived state such as audit views, read models, and caches.
+package replay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+)
+
+// DefaultBatchSize is the number of events read from the store per round trip.
+const DefaultBatchSize = 500
+
+// Sentinel errors for replay configuration.
+var (
+	ErrNoProjections       = errors.New("at least one projection is required")
+	ErrDuplicateProjection = errors.New("duplicate projection name")
+)
+
+// Projection consumes events to build derived state.
+type Projection interface {
+	// Name uniquely identifies the projection; it is used as the checkpoint key.
+	Name() string
+	// Reset discards all derived state before a rebuild from the start.
+	Reset(ctx context.Context) error
+	// Apply folds a single event into the projection.
+	Apply(ctx context.Context, event *events.UserEvent) error
+}
+
+// Options controls a replay run.
+type Options struct {
+	// BatchSize is the number of events read per batch. Defaults to DefaultBatchSize.
+	BatchSize int
+	// FromStart resets projections and ignores stored checkpoints.
+	FromStart bool
+	// DryRun reads and counts events without applying them or saving checkpoints.
+	DryRun bool
+	// Progress is called after every batch, if set.
+	Progress func(Progress)
+}
+
+// Progress reports the state of a running replay after each batch.
+type Progress struct {
+	Position  int64
+	Processed int64
+	Elapsed   time.Duration
+}
+
+// Report summarizes a finished replay.
+type Report struct {
+	DryRun    bool
+	Processed int64
+	// Applied counts events applied per projection.
+	Applied map[string]int64
+	// Checkpoints holds the final position per projection.
+	Checkpoints map[string]int64
+	Duration    time.Duration
+}
+
+// Replayer streams events from an event store through projections.
+type Replayer struct {
+	store       events.EventStore
+	checkpoints CheckpointStore
+	projections []Projection
+}
+
+// NewReplayer creates a new replayer for the given projections.
+func NewReplayer(
+	store events.EventStore,
+	checkpoints CheckpointStore,
+	projections ...Projection,
+) (*Replayer, error) {
+	if len(projections) == 0 {
+		return nil, ErrNoProjections
+	}
+
+	seen := make(map[string]bool, len(projections))
+	for _, projection := range projections {
+		if seen[projection.Name()] {
+			return nil, fmt.Errorf("projection=%s: %w", projection.Name(), ErrDuplicateProjection)
+		}
+
+		seen[projection.Name()] = true
+	}
+
+	return &Replayer{
+		store:       store,
+		checkpoints: checkpoints,
+		projections: projections,
+	}, nil
+}
+
+// Run replays events until the head of the store is reached.
+// Each projection resumes from its own checkpoint, which is saved after every batch,
+// so an interrupted replay continues where it stopped.
+func (r *Replayer) Run(ctx context.Context, opts Options) (*Report, error) {
+	started := time.Now()
+	batchSize := opts.BatchSize
+
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+
+	positions, err := r.startPositions(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{
+		DryRun:      opts.DryRun,
+		Processed:   0,
+		Applied:     make(map[string]int64, len(r.projections)),
+		Checkpoints: positions,
+		Duration:    0,
+	}
+	position := lowestPosition(positions)
+
+	for {
+		batch, err := r.store.ReadFrom(ctx, position, batchSize)
+		if err != nil {
+			return report, fmt.Errorf("read events after=%d: %w", position, err)
+		}
+
+		if len(batch) == 0 {
+			break
+		}
+
+		err = r.applyBatch(ctx, batch, report, opts.DryRun)
+		if err != nil {
+			return report, err
+		}
+
+		position = batch[len(batch)-1].Position
+		report.Processed += int64(len(batch))
+
+		if opts.Progress != nil {
+			opts.Progress(Progress{
+				Position:  position,
+				Processed: report.Processed,
+				Elapsed:   time.Since(started),
+			})
+		}
+	}
+
+	report.Duration = time.Since(started)
+
+	return report, nil
+}
+
+// startPositions resolves the position each projection resumes from,
+// resetting projections when replaying from the start.
+func (r *Replayer) startPositions(ctx context.Context, opts Options) (map[string]int64, error) {
+	positions := make(map[string]int64, len(r.projections))
+
+	for _, projection := range r.projections {
+		if opts.FromStart {
+			positions[projection.Name()] = 0
+
+			if opts.DryRun {
+				continue
+			}
+
+			err := projection.Reset(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("reset projection=%s: %w", projection.Name(), err)
+			}
+
+			continue
+		}
+
+		position, err := r.checkpoints.Load(ctx, projection.Name())
+		if err != nil {
+			return nil, fmt.Errorf("load checkpoint projection=%s: %w", projection.Name(), err)
+		}
+
+		positions[projection.Name()] = position
+	}
+
+	return positions, nil
+}
+
+// applyBatch applies a batch to every projection that has not yet seen it
+// and advances the projection checkpoints.
+func (r *Replayer) applyBatch(
+	ctx context.Context,
+	batch []events.StoredEvent,
+	report *Report,
+	dryRun bool,
+) error {
+	last := batch[len(batch)-1].Position
+
+	for _, projection := range r.projections {
+		name := projection.Name()
+
+		for _, stored := range batch {
+			if stored.Position <= report.Checkpoints[name] {
+				continue
+			}
+
+			report.Applied[name]++
+
+			if dryRun {
+				continue
+			}
+
+			err := projection.Apply(ctx, stored.Event)
+			if err != nil {
+				return fmt.Errorf(
+					"apply event position=%d projection=%s: %w",
+					stored.Position, name, err,
+				)
+			}
+		}
+
+		if last <= report.Checkpoints[name] {
+			continue
+		}
+
+		report.Checkpoints[name] = last
+
+		if dryRun {
+			continue
+		}
+
+		err := r.checkpoints.Save(ctx, name, last)
+		if err != nil {
+			return fmt.Errorf("save checkpoint projection=%s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// lowestPosition returns the smallest checkpoint, where reading must start.
+func lowestPosition(positions map[string]int64) int64 {
+	lowest := int64(-1)
+
+	for _, position := range positions {
+		if lowest < 0 || position < lowest {
+			lowest = position
+		}
+	}
+
+	return max(lowest, 0)
+}
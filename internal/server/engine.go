@@ -0,0 +1,80 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/LarsArtmann/template-sqlc/internal/changefeed"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+	"github.com/LarsArtmann/template-sqlc/internal/replay"
+	"github.com/LarsArtmann/template-sqlc/pkg/db"
+)
+
+// ErrEngineNotBuilt is returned for an engine whose build tag was not set.
+var ErrEngineNotBuilt = errors.New("engine not compiled in; rebuild with its build tag")
+
+// ErrChangefeedUnsupported is returned when the changefeed is enabled for an
+// engine without logical decoding.
+var ErrChangefeedUnsupported = errors.New("engine does not support the changefeed")
+
+// Repositories are the repositories of the configured engine.
+type Repositories struct {
+	Users    repositories.UserRepository
+	Sessions repositories.SessionRepository
+	Jobs     repositories.JobRepository
+	// Transactions runs the multi-step writes of the services atomically.
+	Transactions repositories.TransactionalRepository
+	// Audit records who changed which user.
+	Audit repositories.AuditRepository
+	// Idempotency keeps the responses of user writes with an idempotency key.
+	Idempotency repositories.IdempotencyRepository
+	// Notifications stores the notification preferences of users.
+	Notifications repositories.NotificationPreferenceRepository
+	// IdentityChanges records the email address and username changes of users.
+	IdentityChanges repositories.IdentityChangeRepository
+	// Moderation records the flags and suspensions of users and their appeals.
+	Moderation repositories.ModerationRepository
+	// AccountDeletions schedules the erasure of accounts after a grace period.
+	AccountDeletions repositories.AccountDeletionRepository
+	// Preferences stores the user interface and notification preferences of users.
+	Preferences repositories.UserPreferenceRepository
+	// Activity stores the activity feeds of users.
+	Activity repositories.ActivityRepository
+	// LoginHistory records the login attempts of users.
+	LoginHistory repositories.LoginHistoryRepository
+	// UserEvents stores the event streams of users in event-sourcing mode.
+	UserEvents repositories.UserEventStore
+	// UserReadModel serves user listings and searches from projected views.
+	UserReadModel repositories.UserReadModelRepository
+	// FeatureFlags stores the flags that switch the service at runtime.
+	FeatureFlags repositories.FeatureFlagRepository
+	// Changes reads the row changes of logical replication slots, and
+	// ChangeCheckpoints keeps how far they were published. Both are nil for
+	// engines without logical decoding.
+	Changes           changefeed.Source
+	ChangeCheckpoints replay.CheckpointStore
+}
+
+// engine creates the repositories of one engine over an open pool. It is
+// nil for engines compiled without their build tag.
+type engine func(pool *db.Pool) Repositories
+
+// engineFor returns the engine of driver.
+func engineFor(driver db.Driver) (engine, error) {
+	engines := map[db.Driver]engine{
+		db.DriverSQLite:   sqliteEngine(),
+		db.DriverPostgres: postgresEngine(),
+		db.DriverMySQL:    mysqlEngine(),
+	}
+
+	newRepositories, ok := engines[driver]
+	if !ok {
+		return nil, fmt.Errorf("driver=%v: %w", driver, db.ErrUnsupportedDriver)
+	}
+
+	if newRepositories == nil {
+		return nil, fmt.Errorf("driver=%v: %w", driver, ErrEngineNotBuilt)
+	}
+
+	return newRepositories, nil
+}
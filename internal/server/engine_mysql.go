@@ -0,0 +1,33 @@
+//go:build mysql
+
+package server
+
+import (
+	mysqladapter "github.com/LarsArtmann/template-sqlc/internal/adapters/mysql"
+	"github.com/LarsArtmann/template-sqlc/pkg/db"
+	_ "github.com/go-sql-driver/mysql"
+)
+
+// mysqlEngine creates the MySQL repositories.
+func mysqlEngine() engine {
+	return func(pool *db.Pool) Repositories {
+		return Repositories{
+			Users:            mysqladapter.NewUserRepository(pool.SQL()),
+			Sessions:         mysqladapter.NewSessionRepository(pool.SQL()),
+			Jobs:             mysqladapter.NewJobRepository(pool.SQL()),
+			Transactions:     mysqladapter.NewTransactionalRepository(pool.SQL()),
+			Audit:            mysqladapter.NewAuditRepository(pool.SQL()),
+			Idempotency:      mysqladapter.NewIdempotencyRepository(pool.SQL()),
+			Notifications:    mysqladapter.NewNotificationPreferenceRepository(pool.SQL()),
+			IdentityChanges:  mysqladapter.NewIdentityChangeRepository(pool.SQL()),
+			Moderation:       mysqladapter.NewModerationRepository(pool.SQL()),
+			AccountDeletions: mysqladapter.NewAccountDeletionRepository(pool.SQL()),
+			Preferences:      mysqladapter.NewUserPreferenceRepository(pool.SQL()),
+			Activity:         mysqladapter.NewActivityRepository(pool.SQL()),
+			LoginHistory:     mysqladapter.NewLoginHistoryRepository(pool.SQL()),
+			UserEvents:       mysqladapter.NewUserEventStore(pool.SQL()),
+			UserReadModel:    mysqladapter.NewUserReadModelRepository(pool.SQL()),
+			FeatureFlags:     mysqladapter.NewFeatureFlagRepository(pool.SQL()),
+		}
+	}
+}
@@ -0,0 +1,6 @@
+//go:build !sqlite
+
+package server
+
+// sqliteEngine is nil without the sqlite build tag.
+func sqliteEngine() engine { return nil }
@@ -0,0 +1,34 @@
+//go:build postgres
+
+package server
+
+import (
+	postgresadapter "github.com/LarsArtmann/template-sqlc/internal/adapters/postgres"
+	"github.com/LarsArtmann/template-sqlc/pkg/db"
+)
+
+// postgresEngine creates the PostgreSQL repositories.
+func postgresEngine() engine {
+	return func(pool *db.Pool) Repositories {
+		return Repositories{
+			Users:             postgresadapter.NewUserRepository(pool.PGX()),
+			Sessions:          postgresadapter.NewSessionRepository(pool.PGX()),
+			Jobs:              postgresadapter.NewJobRepository(pool.PGX()),
+			Transactions:      postgresadapter.NewTransactionalRepository(pool.PGX()),
+			Audit:             postgresadapter.NewAuditRepository(pool.PGX()),
+			Idempotency:       postgresadapter.NewIdempotencyRepository(pool.PGX()),
+			Notifications:     postgresadapter.NewNotificationPreferenceRepository(pool.PGX()),
+			IdentityChanges:   postgresadapter.NewIdentityChangeRepository(pool.PGX()),
+			Moderation:        postgresadapter.NewModerationRepository(pool.PGX()),
+			AccountDeletions:  postgresadapter.NewAccountDeletionRepository(pool.PGX()),
+			Preferences:       postgresadapter.NewUserPreferenceRepository(pool.PGX()),
+			Activity:          postgresadapter.NewActivityRepository(pool.PGX()),
+			LoginHistory:      postgresadapter.NewLoginHistoryRepository(pool.PGX()),
+			UserEvents:        postgresadapter.NewUserEventStore(pool.PGX()),
+			UserReadModel:     postgresadapter.NewUserReadModelRepository(pool.PGX()),
+			FeatureFlags:      postgresadapter.NewFeatureFlagRepository(pool.PGX()),
+			Changes:           postgresadapter.NewChangefeedSource(pool.PGX()),
+			ChangeCheckpoints: postgresadapter.NewChangefeedCheckpointStore(pool.PGX()),
+		}
+	}
+}
@@ -0,0 +1,33 @@
+//go:build sqlite
+
+package server
+
+import (
+	sqliteadapter "github.com/LarsArtmann/template-sqlc/internal/adapters/sqlite"
+	"github.com/LarsArtmann/template-sqlc/pkg/db"
+	_ "modernc.org/sqlite"
+)
+
+// sqliteEngine creates the SQLite repositories.
+func sqliteEngine() engine {
+	return func(pool *db.Pool) Repositories {
+		return Repositories{
+			Users:            sqliteadapter.NewUserRepository(pool.SQL()),
+			Sessions:         sqliteadapter.NewSessionRepository(pool.SQL()),
+			Jobs:             sqliteadapter.NewJobRepository(pool.SQL()),
+			Transactions:     sqliteadapter.NewTransactionalRepository(pool.SQL()),
+			Audit:            sqliteadapter.NewAuditRepository(pool.SQL()),
+			Idempotency:      sqliteadapter.NewIdempotencyRepository(pool.SQL()),
+			Notifications:    sqliteadapter.NewNotificationPreferenceRepository(pool.SQL()),
+			IdentityChanges:  sqliteadapter.NewIdentityChangeRepository(pool.SQL()),
+			Moderation:       sqliteadapter.NewModerationRepository(pool.SQL()),
+			AccountDeletions: sqliteadapter.NewAccountDeletionRepository(pool.SQL()),
+			Preferences:      sqliteadapter.NewUserPreferenceRepository(pool.SQL()),
+			Activity:         sqliteadapter.NewActivityRepository(pool.SQL()),
+			LoginHistory:     sqliteadapter.NewLoginHistoryRepository(pool.SQL()),
+			UserEvents:       sqliteadapter.NewUserEventStore(pool.SQL()),
+			UserReadModel:    sqliteadapter.NewUserReadModelRepository(pool.SQL()),
+			FeatureFlags:     sqliteadapter.NewFeatureFlagRepository(pool.SQL()),
+		}
+	}
+}
@@ -0,0 +1,941 @@
+// Package server wires the application together with fx: it opens the
+// configured database, builds the repositories of its engine, the event
+// publisher of the configured backend and the user service, and serves
+// GraphQL, gRPC and metrics until the application is stopped.
+//
+// Engines are compiled in with their build tag, as for the adapters; a
+// driver whose tag was not set fails with ErrEngineNotBuilt.
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"net"
+	"net/http"
+	"net/smtp"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/deadline"
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/email"
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/eventsourced"
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/geoip"
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/graphql"
+	grpcadapter "github.com/LarsArtmann/template-sqlc/internal/adapters/grpc"
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/instrumented"
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/memory"
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/messaging"
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/pwned"
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/resilience"
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/webhook"
+	"github.com/LarsArtmann/template-sqlc/internal/changefeed"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/services"
+	"github.com/LarsArtmann/template-sqlc/internal/jobs"
+	"github.com/LarsArtmann/template-sqlc/internal/monitoring"
+	"github.com/LarsArtmann/template-sqlc/internal/validation"
+	"github.com/LarsArtmann/template-sqlc/pkg/config"
+	"github.com/LarsArtmann/template-sqlc/pkg/db"
+	"github.com/LarsArtmann/template-sqlc/pkg/dblock"
+	"github.com/LarsArtmann/template-sqlc/pkg/passwords"
+	"github.com/LarsArtmann/template-sqlc/pkg/ratelimit"
+	"github.com/LarsArtmann/template-sqlc/pkg/storage"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/sesv2"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/redis/go-redis/v9"
+	"github.com/segmentio/kafka-go"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxevent"
+	"golang.org/x/oauth2/google"
+	grpclib "google.golang.org/grpc"
+)
+
+const readHeaderTimeout = 10 * time.Second
+
+// gcsScope authorizes reading and writing Google Cloud Storage objects.
+const gcsScope = "https://www.googleapis.com/auth/devstorage.read_write"
+
+// reactivationBatch is how many lapsed suspensions a moderation.reactivate
+// job lifts.
+const reactivationBatch = 100
+
+// accountDeletionBatch is how many accounts due for deletion an
+// account.delete job erases.
+const accountDeletionBatch = 100
+
+// New creates the application for cfg. Extra options are applied after
+// the server module, e.g. fx.Populate in tests.
+func New(cfg config.Config, opts ...fx.Option) *fx.App {
+	return fx.New(
+		fx.WithLogger(func() fxevent.Logger { return &fxevent.SlogLogger{Logger: slog.Default()} }),
+		fx.StartTimeout(cfg.Server.ShutdownTimeout),
+		fx.StopTimeout(cfg.Server.ShutdownTimeout),
+		Module(cfg),
+		fx.Options(opts...),
+	)
+}
+
+// Module provides the components of the server and starts its listeners.
+func Module(cfg config.Config) fx.Option {
+	return fx.Module("server",
+		fx.Supply(cfg),
+		fx.Provide(
+			newMetrics,
+			newPool,
+			dblock.New,
+			newRepositories,
+			newDispatcher,
+			newPublisher,
+			newLimiter,
+			newBlobStore,
+			newSwitchboard,
+			newUserService,
+		),
+		fx.Invoke(
+			serveHTTP,
+			serveGRPC,
+			serveMetrics,
+			runJobs,
+			sendEmails,
+			notifyUsers,
+			recordActivity,
+			projectUsers,
+			streamChanges,
+		),
+	)
+}
+
+// newMetrics creates the metrics with an empty health checker, which the
+// other components register their checks with.
+func newMetrics() *monitoring.Metrics {
+	metrics := monitoring.NewMetrics()
+	metrics.SetHealthChecker(monitoring.NewHealthChecker())
+
+	return metrics
+}
+
+// newPool opens the database and closes it when the application stops.
+func newPool(lc fx.Lifecycle, cfg config.Config, metrics *monitoring.Metrics) (*db.Pool, error) {
+	pool, err := db.Open(context.Background(), cfg.Database.DB(), db.WithMetrics(metrics))
+	if err != nil {
+		return nil, err
+	}
+
+	metrics.HealthChecker().RegisterReadiness("database", monitoring.PingCheck(pool))
+
+	lc.Append(fx.StopHook(pool.Close))
+
+	return pool, nil
+}
+
+// newRepositories creates the repositories of the configured engine, event
+// sourcing users if enabled, bounded by the configured timeouts,
+// instrumented with metrics and traces, retrying transient failures behind a
+// circuit breaker.
+func newRepositories(cfg config.Config, pool *db.Pool, metrics *monitoring.Metrics) (Repositories, error) {
+	newEngine, err := engineFor(cfg.Database.Driver)
+	if err != nil {
+		return Repositories{}, err
+	}
+
+	repos := newEngine(pool)
+	if cfg.Database.EventSourcing.Enabled {
+		snapshots := eventsourced.WithSnapshotEvery(cfg.Database.EventSourcing.SnapshotEvery)
+		repos.Users = eventsourced.NewUserRepository(repos.Users, repos.UserEvents, snapshots)
+		repos.Transactions = eventsourced.NewTransactionalRepository(repos.Transactions, snapshots)
+	}
+
+	timeouts := cfg.Database.Timeouts
+	deadlineOpts := []deadline.Option{
+		deadline.WithTimeouts(deadline.Timeouts{Read: timeouts.Read, Write: timeouts.Write, Search: timeouts.Search}),
+		deadline.WithObserver(metrics),
+	}
+	repos.Users = deadline.NewUserRepository(repos.Users, deadlineOpts...)
+	repos.Sessions = deadline.NewSessionRepository(repos.Sessions, deadlineOpts...)
+
+	opts := []instrumented.Option{
+		instrumented.WithMetrics(metrics),
+		instrumented.WithEngine(string(cfg.Database.Driver)),
+	}
+	repos.Users = instrumented.NewUserRepository(repos.Users, opts...)
+	repos.Sessions = instrumented.NewSessionRepository(repos.Sessions, opts...)
+
+	// Every attempt is instrumented; the breaker sees the outcome after retries.
+	resilienceOpts := []resilience.Option{
+		resilience.WithEngine(string(cfg.Database.Driver)),
+		resilience.WithObserver(metrics),
+	}
+	repos.Users = resilience.NewUserRepository(repos.Users, resilienceOpts...)
+	repos.Sessions = resilience.NewSessionRepository(repos.Sessions, resilienceOpts...)
+
+	return repos, nil
+}
+
+// newDispatcher creates the in-process dispatcher that in-process
+// subscribers receive the events of every backend from, and drains it when
+// the application stops.
+func newDispatcher(lc fx.Lifecycle) *events.Dispatcher {
+	dispatcher := events.NewDispatcher()
+
+	lc.Append(fx.StopHook(dispatcher.Close))
+
+	return dispatcher
+}
+
+// newPublisher creates the event publisher of the configured backend, which
+// redacts personal data as configured.
+func newPublisher(
+	lc fx.Lifecycle,
+	cfg config.Config,
+	dispatcher *events.Dispatcher,
+	metrics *monitoring.Metrics,
+) (events.EventPublisher, error) {
+	publisher, err := newBackendPublisher(lc, cfg, dispatcher, metrics)
+	if err != nil {
+		return nil, err
+	}
+
+	return events.NewRedactingPublisher(publisher, cfg.Redaction.Policy()), nil
+}
+
+// newBackendPublisher creates the event publisher of the configured backend
+// and drains it when the application stops. Broker publishers are guarded
+// by a circuit breaker and buffered, and events are published to the
+// dispatcher too, so that in-process subscribers receive them whatever the
+// backend.
+func newBackendPublisher(
+	lc fx.Lifecycle,
+	cfg config.Config,
+	dispatcher *events.Dispatcher,
+	metrics *monitoring.Metrics,
+) (events.EventPublisher, error) {
+	topics := messaging.WithTopics(messaging.Topics{Default: cfg.Events.Topic})
+	observer := resilience.WithObserver(metrics)
+
+	switch cfg.Events.Backend {
+	case config.EventBackendNATS:
+		conn, err := nats.Connect(cfg.Events.URL)
+		if err != nil {
+			return nil, fmt.Errorf("connect nats url=%v: %w", config.RedactDSN(cfg.Events.URL), err)
+		}
+
+		js, err := jetstream.New(conn)
+		if err != nil {
+			conn.Close()
+
+			return nil, fmt.Errorf("jetstream: %w", err)
+		}
+
+		lc.Append(fx.StopHook(conn.Drain))
+
+		publisher := resilience.NewPublisher("nats", messaging.NewNATSPublisher(js, topics), observer)
+
+		return events.NewFanOutPublisher(bufferPublisher(lc, cfg.Events.Buffer, publisher, metrics), dispatcher), nil
+	case config.EventBackendKafka:
+		writer := &kafka.Writer{ //nolint:exhaustruct // Only required fields needed
+			Addr:     kafka.TCP(cfg.Events.Brokers...),
+			Balancer: &kafka.Hash{}, //nolint:exhaustruct // Defaults
+		}
+
+		lc.Append(fx.StopHook(writer.Close))
+
+		publisher := resilience.NewPublisher("kafka", messaging.NewKafkaPublisher(writer, topics), observer)
+
+		return events.NewFanOutPublisher(bufferPublisher(lc, cfg.Events.Buffer, publisher, metrics), dispatcher), nil
+	default:
+		return dispatcher, nil
+	}
+}
+
+// bufferPublisher publishes the events of publisher asynchronously in
+// batches, unless the buffer is disabled, and publishes the buffered events
+// when the application stops, before the broker connection is closed.
+func bufferPublisher(
+	lc fx.Lifecycle,
+	cfg config.EventBufferConfig,
+	publisher events.EventPublisher,
+	metrics *monitoring.Metrics,
+) events.EventPublisher {
+	if cfg.Capacity == 0 {
+		return publisher
+	}
+
+	buffered := messaging.NewBufferedPublisher(publisher,
+		messaging.WithBufferPolicy(messaging.BufferPolicy{
+			Capacity:      cfg.Capacity,
+			BatchSize:     cfg.BatchSize,
+			FlushInterval: cfg.FlushInterval,
+			Overflow:      messaging.Overflow(cfg.Overflow),
+		}),
+		messaging.WithBufferObserver(metrics),
+	)
+
+	lc.Append(fx.StopHook(buffered.Close))
+
+	return buffered
+}
+
+func newUserService(
+	lc fx.Lifecycle,
+	cfg config.Config,
+	repos Repositories,
+	publisher events.EventPublisher,
+	limiter ratelimit.Limiter,
+	metrics *monitoring.Metrics,
+	blobs storage.BlobStore,
+	board *services.Switchboard,
+) (*services.UserService, error) {
+	throttle := ratelimit.NewLoginThrottle(
+		ratelimit.Rule{Limiter: ratelimit.Instrument(limiter, metrics, "login_ip"), Limit: cfg.RateLimit.LoginPerIP},
+		ratelimit.Rule{
+			Limiter: ratelimit.Instrument(limiter, metrics, "login_account"),
+			Limit:   cfg.RateLimit.LoginPerAccount,
+		},
+	)
+
+	opts := []services.UserServiceOption{
+		services.WithPasswordHasher(passwords.NewBcryptHasher(passwords.DefaultBcryptCost)),
+		services.WithTransactions(repos.Transactions),
+		services.WithAuditLog(repos.Audit),
+		services.WithSessionPolicy(cfg.Sessions.Policy()),
+		services.WithIdempotency(repos.Idempotency, cfg.Server.IdempotencyTTL),
+		services.WithLoginThrottle(throttle),
+		services.WithIdentityChanges(repos.IdentityChanges, entities.IdentityChangeGracePeriod),
+		services.WithLoginHistory(repos.LoginHistory),
+		services.WithModeration(repos.Moderation),
+		services.WithAccountDeletion(repos.AccountDeletions, cfg.AccountDeletion.GracePeriod),
+		services.WithActivityExport(repos.Activity),
+		services.WithPreferenceExport(repos.Preferences),
+		services.WithUserStreamExport(repos.UserEvents),
+		services.WithSwitchboard(board),
+	}
+
+	if cfg.Sessions.LoginRisk {
+		geo, err := newGeoResolver(lc, cfg.Sessions.GeoIPDatabase)
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, services.WithLoginRiskDetection(geo))
+	}
+
+	if blobs != nil {
+		opts = append(opts, services.WithAvatars(blobs))
+	}
+
+	emails, err := newEmailPolicy(cfg.EmailAddresses)
+	if err != nil {
+		return nil, err
+	}
+
+	usernames, err := newUsernamePolicy(cfg.Usernames)
+	if err != nil {
+		return nil, err
+	}
+
+	validator := validation.NewUserValidator(
+		validation.WithPasswordPolicy(newPasswordPolicy(cfg.Passwords)),
+		validation.WithEmailPolicy(emails),
+		validation.WithUsernamePolicy(usernames),
+	)
+
+	// The changefeed reports the changes of users and sessions, so the
+	// service leaves their events to it rather than publishing them twice.
+	if cfg.Changefeed.Enabled {
+		publisher = events.NewFilteringPublisher(publisher, changefeed.EventTypes()...)
+	}
+
+	return services.NewUserService(repos.Users, repos.Sessions, publisher, validator, opts...), nil
+}
+
+// newPasswordPolicy returns the configured password policy, checking
+// passwords against the breach API if enabled.
+func newPasswordPolicy(cfg config.PasswordPolicyConfig) validation.PasswordPolicy {
+	policy := cfg.Policy()
+	if cfg.BreachCheck {
+		policy.Breaches = pwned.NewClient(pwned.WithBaseURL(cfg.BreachAPIURL), pwned.WithTimeout(cfg.BreachTimeout))
+	}
+
+	return policy
+}
+
+// newEmailPolicy returns the configured email policy, with the domains of
+// the disposable domains file, looking up MX records if enabled.
+func newEmailPolicy(cfg config.EmailAddressPolicyConfig) (validation.EmailPolicy, error) {
+	policy, err := cfg.Policy()
+	if err != nil {
+		return validation.EmailPolicy{}, fmt.Errorf("email policy: %w", err)
+	}
+
+	if cfg.CheckMX {
+		policy.Resolver = net.DefaultResolver
+	}
+
+	if cfg.DisposableDomainsFile == "" {
+		return policy, nil
+	}
+
+	file, err := os.Open(cfg.DisposableDomainsFile) //nolint:gosec // The path is chosen by the operator.
+	if err != nil {
+		return validation.EmailPolicy{}, fmt.Errorf("open disposable domains file: %w", err)
+	}
+
+	defer func() { _ = file.Close() }()
+
+	domains, err := validation.ReadDomainSet(file)
+	if err != nil {
+		return validation.EmailPolicy{}, fmt.Errorf("disposable domains file=%v: %w", cfg.DisposableDomainsFile, err)
+	}
+
+	maps.Copy(domains, policy.DisposableDomains)
+	policy.DisposableDomains = domains
+
+	return policy, nil
+}
+
+// newUsernamePolicy returns the configured username policy, with the
+// entries of the reserved and profanity files.
+func newUsernamePolicy(cfg config.UsernamePolicyConfig) (validation.UsernamePolicy, error) {
+	policy, err := cfg.Policy()
+	if err != nil {
+		return validation.UsernamePolicy{}, fmt.Errorf("username policy: %w", err)
+	}
+
+	files := []struct {
+		path string
+		list *validation.NameList
+	}{
+		{cfg.ReservedFile, policy.Reserved},
+		{cfg.ProfanityFile, policy.Profanity},
+	}
+
+	for _, f := range files {
+		if f.path == "" {
+			continue
+		}
+
+		err = addNamesFromFile(f.list, f.path)
+		if err != nil {
+			return validation.UsernamePolicy{}, err
+		}
+	}
+
+	return policy, nil
+}
+
+// addNamesFromFile adds the entries of the file at path to list.
+func addNamesFromFile(list *validation.NameList, path string) error {
+	file, err := os.Open(path) //nolint:gosec // The path is chosen by the operator.
+	if err != nil {
+		return fmt.Errorf("open username list: %w", err)
+	}
+
+	defer func() { _ = file.Close() }()
+
+	err = list.AddFrom(file)
+	if err != nil {
+		return fmt.Errorf("username list file=%v: %w", path, err)
+	}
+
+	return nil
+}
+
+// newSwitchboard creates the service switches, set by the configuration
+// and overridden by the feature flags of the engine, and reports them on the
+// health endpoints.
+func newSwitchboard(cfg config.Config, repos Repositories, metrics *monitoring.Metrics) *services.Switchboard {
+	opts := []services.SwitchboardOption{services.WithSwitchDefaults(cfg.Maintenance.Switches())}
+	if repos.FeatureFlags != nil {
+		opts = append(opts, services.WithFeatureFlags(repos.FeatureFlags, cfg.Maintenance.FlagRefresh))
+	}
+
+	board := services.NewSwitchboard(opts...)
+
+	for _, sw := range entities.ServiceSwitches() {
+		metrics.HealthChecker().RegisterMode(sw.String(), func(ctx context.Context) bool {
+			return board.State(ctx)[sw]
+		})
+	}
+
+	return board
+}
+
+// newGeoResolver opens the GeoIP database at path, or returns nil to
+// compare only devices if path is empty.
+func newGeoResolver(lc fx.Lifecycle, path string) (services.GeoResolver, error) {
+	if path == "" {
+		return nil, nil //nolint:nilnil // no database means no locations
+	}
+
+	geo, err := geoip.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	lc.Append(fx.StopHook(geo.Close))
+
+	return geo, nil
+}
+
+// newBlobStore opens the store of the configured storage backend, or
+// returns nil to disable uploads.
+func newBlobStore(lc fx.Lifecycle, cfg config.Config) (storage.BlobStore, error) {
+	ctx := context.Background()
+	conf := cfg.Storage
+
+	switch conf.Backend {
+	case config.StorageBackendLocal:
+		local, err := storage.NewLocal(conf.Dir, conf.BaseURL)
+		if err != nil {
+			return nil, err
+		}
+
+		lc.Append(fx.StopHook(local.Close))
+
+		return local, nil
+	case config.StorageBackendS3:
+		var opts []func(*awsconfig.LoadOptions) error
+		if conf.Region != "" {
+			opts = append(opts, awsconfig.WithRegion(conf.Region))
+		}
+
+		awsCfg, err := awsconfig.LoadDefaultConfig(ctx, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("load aws config: %w", err)
+		}
+
+		client := s3.NewFromConfig(awsCfg, func(o *s3.Options) {
+			if conf.Endpoint != "" {
+				o.BaseEndpoint = &conf.Endpoint
+				o.UsePathStyle = true
+			}
+		})
+
+		return storage.NewS3(client, conf.Bucket, conf.BaseURL), nil
+	case config.StorageBackendGCS:
+		client, err := google.DefaultClient(ctx, gcsScope)
+		if err != nil {
+			return nil, fmt.Errorf("load google credentials: %w", err)
+		}
+
+		var opts []storage.GCSOption
+		if conf.Endpoint != "" {
+			opts = append(opts, storage.WithGCSEndpoint(conf.Endpoint))
+		}
+
+		if conf.BaseURL != "" {
+			opts = append(opts, storage.WithGCSBaseURL(conf.BaseURL))
+		}
+
+		return storage.NewGCS(client, conf.Bucket, opts...), nil
+	case config.StorageBackendNone:
+	}
+
+	return nil, nil //nolint:nilnil // no backend means no uploads
+}
+
+// newLimiter keeps the rate limit buckets in Redis if configured, so that
+// instances share them, and in memory otherwise.
+func newLimiter(lc fx.Lifecycle, cfg config.Config) ratelimit.Limiter {
+	if cfg.RateLimit.RedisAddr == "" {
+		return ratelimit.NewMemory()
+	}
+
+	client := redis.NewClient(&redis.Options{Addr: cfg.RateLimit.RedisAddr}) //nolint:exhaustruct // Defaults
+
+	lc.Append(fx.StopHook(client.Close))
+
+	return ratelimit.NewRedis(client)
+}
+
+// serveHTTP serves GraphQL on /graphql and the files of the local storage
+// backend below the path of its base URL, rate limited per client IP address.
+func serveHTTP(lc fx.Lifecycle, cfg config.Config, metrics *monitoring.Metrics,
+	users *services.UserService, repos Repositories, limiter ratelimit.Limiter, blobs storage.BlobStore,
+) error {
+	mux := http.NewServeMux()
+	mux.Handle("/graphql", graphql.NewHandler(users, repos.Users, repos.Sessions))
+
+	if local, ok := blobs.(*storage.Local); ok {
+		base, err := url.Parse(cfg.Storage.BaseURL)
+		if err != nil {
+			return fmt.Errorf("storage base url=%v: %w", cfg.Storage.BaseURL, err)
+		}
+
+		prefix := strings.TrimSuffix(base.Path, "/")
+		mux.Handle(prefix+"/", http.StripPrefix(prefix, http.FileServerFS(local.FS())))
+	}
+
+	rule := ratelimit.Rule{Limiter: ratelimit.Instrument(limiter, metrics, "http"), Limit: cfg.RateLimit.Requests}
+
+	listenHTTP(lc, "http", cfg.Server.HTTPAddr, metrics.Middleware(ratelimit.Middleware(rule, ratelimit.ClientIP)(mux)))
+
+	return nil
+}
+
+// serveMetrics serves the metrics, health and, with Profiling, pprof endpoints.
+func serveMetrics(lc fx.Lifecycle, cfg config.Config, metrics *monitoring.Metrics) error {
+	handler, err := metrics.Handler(
+		monitoring.WithProfiling(cfg.Server.Profiling),
+		monitoring.WithRuntimeMetrics(true),
+	)
+	if err != nil {
+		return err
+	}
+
+	listenHTTP(lc, "metrics", cfg.Server.MetricsAddr, handler)
+
+	return nil
+}
+
+// listenHTTP binds addr when the application starts, so that a taken port
+// fails the start, and shuts the server down gracefully when it stops.
+func listenHTTP(lc fx.Lifecycle, name, addr string, handler http.Handler) {
+	server := &http.Server{ //nolint:exhaustruct // Only required fields needed
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			listener, err := listen(ctx, name, addr)
+			if err != nil {
+				return err
+			}
+
+			go func() {
+				err := server.Serve(listener)
+				if err != nil && !errors.Is(err, http.ErrServerClosed) {
+					slog.Error("server stopped", "server", name, "error", err)
+				}
+			}()
+
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			err := server.Shutdown(ctx)
+			if err != nil {
+				return fmt.Errorf("shutdown %s server: %w", name, err)
+			}
+
+			return nil
+		},
+	})
+}
+
+// serveGRPC serves the user service over gRPC, rate limited per client IP
+// address.
+func serveGRPC(lc fx.Lifecycle, cfg config.Config, metrics *monitoring.Metrics,
+	users *services.UserService, limiter ratelimit.Limiter,
+) {
+	rule := ratelimit.Rule{Limiter: ratelimit.Instrument(limiter, metrics, "grpc"), Limit: cfg.RateLimit.Requests}
+	server := grpclib.NewServer(grpcadapter.ServerOptions(users, metrics,
+		ratelimit.UnaryServerInterceptor(rule, ratelimit.PeerIP))...)
+	grpcadapter.NewServer(users).Register(server)
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			listener, err := listen(ctx, "grpc", cfg.Server.GRPCAddr)
+			if err != nil {
+				return err
+			}
+
+			go func() {
+				err := server.Serve(listener)
+				if err != nil {
+					slog.Error("server stopped", "server", "grpc", "error", err)
+				}
+			}()
+
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			stopped := make(chan struct{})
+
+			go func() {
+				server.GracefulStop()
+				close(stopped)
+			}()
+
+			select {
+			case <-stopped:
+				return nil
+			case <-ctx.Done():
+				server.Stop()
+
+				return fmt.Errorf("shutdown grpc server: %w", ctx.Err())
+			}
+		},
+	})
+}
+
+// runJobs runs the background job worker pool with the cleanup and
+// suspension reactivation handlers until the application stops, then waits
+// for the running jobs. Both jobs hold a database lock, so one instance
+// runs each at a time.
+func runJobs(
+	lc fx.Lifecycle,
+	cfg config.Config,
+	repos Repositories,
+	users *services.UserService,
+	locker dblock.Locker,
+) {
+	if cfg.Jobs.Workers == 0 {
+		return
+	}
+
+	pool := jobs.NewPool(repos.Jobs, jobs.WithWorkers(cfg.Jobs.Workers))
+	pool.Register(jobs.CleanupJob, jobs.Exclusive(locker, jobs.CleanupJob, jobs.NewCleanupHandler(jobs.Cleanup{
+		Sessions:          repos.Sessions,
+		Jobs:              repos.Jobs,
+		DoneJobRetention:  cfg.Jobs.DoneRetention,
+		Idempotency:       repos.Idempotency,
+		Activity:          repos.Activity,
+		ActivityRetention: cfg.Activity.Retention,
+	})))
+	pool.Register(jobs.ModerationReactivateJob, jobs.Exclusive(locker, jobs.ModerationReactivateJob,
+		jobs.NewReactivationHandler(users, reactivationBatch)))
+	pool.Register(jobs.AccountDeletionJob, jobs.Exclusive(locker, jobs.AccountDeletionJob,
+		jobs.NewAccountDeletionHandler(users, accountDeletionBatch)))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				defer close(done)
+
+				_ = pool.Run(ctx)
+			}()
+
+			return nil
+		},
+		OnStop: func(stopCtx context.Context) error {
+			cancel()
+
+			select {
+			case <-done:
+				return nil
+			case <-stopCtx.Done():
+				return fmt.Errorf("stop job workers: %w", stopCtx.Err())
+			}
+		},
+	})
+}
+
+// sendEmails subscribes the notifier emailing users about verification,
+// password reset, identity change and suspension events, if an email backend is configured.
+func sendEmails(cfg config.Config, repos Repositories, dispatcher *events.Dispatcher) error {
+	if cfg.Email.Backend == config.EmailBackendNone {
+		return nil
+	}
+
+	sender, err := newEmailSender(cfg.Email)
+	if err != nil {
+		return err
+	}
+
+	notifier := email.NewNotifier(sender, repos.Users, cfg.Email.From, email.Links{
+		Verification:   cfg.Email.VerificationURL,
+		PasswordReset:  cfg.Email.PasswordResetURL,
+		EmailChange:    cfg.Email.EmailChangeURL,
+		IdentityRevert: cfg.Email.IdentityRevertURL,
+	})
+	dispatcher.Subscribe("email", notifier, notifier.EventTypes()...)
+
+	return nil
+}
+
+// notifyUsers subscribes the notification service notifying users about
+// account events on the channels they opted in to, if notifications are
+// enabled. Users are emailed only if an email backend is configured.
+func notifyUsers(cfg config.Config, repos Repositories, dispatcher *events.Dispatcher) error {
+	if !cfg.Notifications.Enabled {
+		return nil
+	}
+
+	var webhookOpts []webhook.Option
+	if cfg.Notifications.WebhookSecret != "" {
+		webhookOpts = append(webhookOpts, webhook.WithSigningSecret([]byte(cfg.Notifications.WebhookSecret)))
+	}
+
+	opts := []services.NotificationServiceOption{
+		services.WithNotificationChannel(entities.NotificationChannelWebhook, webhook.NewSender(webhookOpts...)),
+		services.WithNotificationChannel(entities.NotificationChannelInApp, memory.NewNotificationInbox(0)),
+	}
+
+	if cfg.Email.Backend != config.EmailBackendNone {
+		sender, err := newEmailSender(cfg.Email)
+		if err != nil {
+			return err
+		}
+
+		notifier := email.NewNotifier(sender, repos.Users, cfg.Email.From, email.Links{})
+		opts = append(opts, services.WithNotificationChannel(entities.NotificationChannelEmail, notifier))
+	}
+
+	notifications := services.NewNotificationService(repos.Notifications, repos.Users, opts...)
+	dispatcher.Subscribe("notifications", notifications, notifications.EventTypes()...)
+
+	return nil
+}
+
+// recordActivity subscribes the activity feed recording the activity of
+// users, if activity feeds are enabled.
+func recordActivity(cfg config.Config, repos Repositories, dispatcher *events.Dispatcher) {
+	if !cfg.Activity.Enabled {
+		return
+	}
+
+	feed := services.NewActivityFeed(repos.Activity, repos.Users)
+	dispatcher.Subscribe("activity", feed, feed.EventTypes()...)
+}
+
+// projectUsers subscribes the projection keeping the user read model in
+// sync, if it is enabled, and projects the users stored before in the
+// background when the application starts.
+func projectUsers(lc fx.Lifecycle, cfg config.Config, repos Repositories, dispatcher *events.Dispatcher,
+	metrics *monitoring.Metrics,
+) {
+	if !cfg.ReadModel.Enabled {
+		return
+	}
+
+	projection := services.NewUserProjection(repos.UserReadModel, repos.Users,
+		services.WithReadModelObserver(metrics))
+	dispatcher.Subscribe("read_model", projection, projection.EventTypes()...)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				projected, err := projection.Rebuild(ctx)
+				if err != nil {
+					slog.Error("rebuild user read model", "projected", projected, "error", err)
+				}
+			}()
+
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			cancel()
+
+			return nil
+		},
+	})
+}
+
+// streamChanges publishes the changes of users and sessions read from the
+// replication slot of the changefeed, if it is enabled, until the
+// application stops. Only the instance holding the lock of the slot reads it.
+func streamChanges(
+	lc fx.Lifecycle,
+	cfg config.Config,
+	repos Repositories,
+	publisher events.EventPublisher,
+	locker dblock.Locker,
+) error {
+	if !cfg.Changefeed.Enabled {
+		return nil
+	}
+
+	if repos.Changes == nil || repos.ChangeCheckpoints == nil {
+		return fmt.Errorf("changefeed driver=%v: %w", cfg.Database.Driver, ErrChangefeedUnsupported)
+	}
+
+	listener, err := changefeed.NewListener(repos.Changes, repos.ChangeCheckpoints, publisher, cfg.Changefeed.Slot,
+		changefeed.WithBatchSize(cfg.Changefeed.BatchSize),
+		changefeed.WithPollInterval(cfg.Changefeed.PollInterval),
+		changefeed.WithLocker(locker),
+	)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				defer close(done)
+
+				err := listener.Run(ctx)
+				if err != nil {
+					slog.Error("changefeed stopped", "slot", cfg.Changefeed.Slot, "error", err)
+				}
+			}()
+
+			return nil
+		},
+		OnStop: func(stopCtx context.Context) error {
+			cancel()
+
+			select {
+			case <-done:
+				return nil
+			case <-stopCtx.Done():
+				return fmt.Errorf("stop changefeed: %w", stopCtx.Err())
+			}
+		},
+	})
+
+	return nil
+}
+
+// newEmailSender creates the sender of the configured email backend.
+func newEmailSender(cfg config.EmailConfig) (email.EmailSender, error) {
+	if cfg.Backend == config.EmailBackendSMTP {
+		var auth smtp.Auth
+
+		if cfg.SMTPUsername != "" {
+			host, _, err := net.SplitHostPort(cfg.SMTPAddr)
+			if err != nil {
+				return nil, fmt.Errorf("smtp addr=%v: %w", cfg.SMTPAddr, err)
+			}
+
+			auth = smtp.PlainAuth("", cfg.SMTPUsername, cfg.SMTPPassword, host)
+		}
+
+		return email.NewSMTPSender(cfg.SMTPAddr, auth), nil
+	}
+
+	var opts []func(*awsconfig.LoadOptions) error
+	if cfg.SESRegion != "" {
+		opts = append(opts, awsconfig.WithRegion(cfg.SESRegion))
+	}
+
+	awsCfg, err := awsconfig.LoadDefaultConfig(context.Background(), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("load aws config: %w", err)
+	}
+
+	return email.NewSESSender(sesv2.NewFromConfig(awsCfg)), nil
+}
+
+// listen binds addr and logs the bound address, which differs from addr
+// for port 0.
+func listen(ctx context.Context, name, addr string) (net.Listener, error) {
+	var config net.ListenConfig
+
+	listener, err := config.Listen(ctx, "tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("listen %s addr=%v: %w", name, addr, err)
+	}
+
+	slog.Info("server listening", "server", name, "addr", listener.Addr().String())
+
+	return listener, nil
+}
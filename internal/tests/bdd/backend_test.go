@@ -0,0 +1,80 @@
+package bdd
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/services"
+	"github.com/LarsArtmann/template-sqlc/internal/tests/integration"
+	"github.com/LarsArtmann/template-sqlc/pkg/passwords"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// databaseEnv selects the backend the scenarios run against. It is empty or
+// "mock" for the in-process mocks, or the name of a database backend compiled
+// in with its build tag:
+//
+//	BDD_DATABASE=sqlite go test -tags sqlite ./internal/tests/bdd/...
+const databaseEnv = "BDD_DATABASE"
+
+// backend provides the repositories every scenario starts from.
+type backend interface {
+	// repositories returns empty repositories for a new scenario.
+	repositories() (repositories.UserRepository, repositories.SessionRepository, error)
+	// setPassword makes password the one user authenticates with.
+	setPassword(ctx context.Context, users repositories.UserRepository, user *entities.User, password string) error
+	// serviceOptions returns the options the user service needs with this backend.
+	serviceOptions() []services.UserServiceOption
+}
+
+// databaseBackends holds the database backends registered by the files
+// compiled in with their build tags.
+var databaseBackends = map[string]func(tb testing.TB) backend{}
+
+// newBackend returns the backend selected by BDD_DATABASE.
+func newBackend(tb testing.TB) backend {
+	tb.Helper()
+
+	name := os.Getenv(databaseEnv)
+	if name == "" || name == "mock" {
+		return mockBackend{hasher: passwords.NewBcryptHasher(bcrypt.MinCost)}
+	}
+
+	newDatabase, ok := databaseBackends[name]
+	if !ok {
+		tb.Fatalf("%s=%v: unknown backend, or its build tag is missing", databaseEnv, name)
+	}
+
+	return newDatabase(tb)
+}
+
+// mockBackend runs the scenarios against the in-process mock repositories.
+// Passwords are stored as real bcrypt hashes and verified by the service.
+type mockBackend struct {
+	hasher *passwords.BcryptHasher
+}
+
+func (mockBackend) repositories() (repositories.UserRepository, repositories.SessionRepository, error) {
+	return integration.NewMockUserRepository(), integration.NewMockSessionRepository(), nil
+}
+
+func (b mockBackend) setPassword(
+	ctx context.Context,
+	users repositories.UserRepository,
+	user *entities.User,
+	password string,
+) error {
+	hash, err := b.hasher.Hash(password)
+	if err != nil {
+		return err
+	}
+
+	return users.UpdatePassword(ctx, user.ID(), entities.PasswordHash(hash))
+}
+
+func (b mockBackend) serviceOptions() []services.UserServiceOption {
+	return []services.UserServiceOption{services.WithPasswordHasher(b.hasher)}
+}
@@ -0,0 +1,61 @@
+//go:build sqlite
+
+package bdd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/memory"
+	sqliteadapter "github.com/LarsArtmann/template-sqlc/internal/adapters/sqlite"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/services"
+	"github.com/LarsArtmann/template-sqlc/internal/tests/containers"
+	"github.com/LarsArtmann/template-sqlc/pkg/passwords"
+	"golang.org/x/crypto/bcrypt"
+)
+
+//nolint:gochecknoinits // Backends register themselves behind their build tags.
+func init() {
+	databaseBackends["sqlite"] = func(tb testing.TB) backend {
+		return &sqliteBackend{tb: tb, dir: tb.TempDir(), hasher: passwords.NewBcryptHasher(bcrypt.MinCost)}
+	}
+}
+
+// sqliteBackend runs every scenario against its own SQLite database file.
+// Passwords are stored as real bcrypt hashes and verified by the service.
+// Sessions stay in memory until the SQLite session repository is implemented.
+type sqliteBackend struct {
+	tb        testing.TB
+	dir       string
+	hasher    *passwords.BcryptHasher
+	scenarios int
+}
+
+func (b *sqliteBackend) repositories() (repositories.UserRepository, repositories.SessionRepository, error) {
+	b.scenarios++
+	db := containers.SQLiteFile(b.tb, filepath.Join(b.dir, fmt.Sprintf("scenario-%03d.db", b.scenarios)))
+
+	return sqliteadapter.NewUserRepository(db), memory.NewSessionRepository(), nil
+}
+
+func (b *sqliteBackend) setPassword(
+	ctx context.Context,
+	users repositories.UserRepository,
+	user *entities.User,
+	password string,
+) error {
+	hash, err := b.hasher.Hash(password)
+	if err != nil {
+		return err
+	}
+
+	return users.UpdatePassword(ctx, user.ID(), entities.PasswordHash(hash))
+}
+
+func (b *sqliteBackend) serviceOptions() []services.UserServiceOption {
+	return []services.UserServiceOption{services.WithPasswordHasher(b.hasher)}
+}
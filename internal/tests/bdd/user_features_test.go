@@ -6,13 +6,14 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"testing"
 	"time"
 
 	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
 	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
 	"github.com/LarsArtmann/template-sqlc/internal/domain/services"
-	"github.com/LarsArtmann/template-sqlc/internal/tests/integration"
 	"github.com/LarsArtmann/template-sqlc/internal/validation"
 	apperrors "github.com/LarsArtmann/template-sqlc/pkg/errors"
 	"github.com/cucumber/godog"
@@ -37,9 +38,10 @@ const (
 
 // UserFeaturesTestSuite contains BDD tests for user functionality.
 type UserFeaturesTestSuite struct {
+	backend        backend
 	userService    *services.UserService
-	userRepo       *integration.MockUserRepository
-	sessionRepo    *integration.MockSessionRepository
+	userRepo       repositories.UserRepository
+	sessionRepo    repositories.SessionRepository
 	eventPublisher *events.InMemoryEventPublisher
 	validator      *validation.UserValidator
 	currentUser    *entities.User
@@ -52,15 +54,6 @@ type UserFeaturesTestSuite struct {
 func (s *UserFeaturesTestSuite) InitializeContext(ctx *godog.ScenarioContext) {
 	s.eventPublisher = events.NewInMemoryEventPublisher()
 	s.validator = validation.NewUserValidator()
-	s.userRepo = integration.NewMockUserRepository()
-	s.sessionRepo = integration.NewMockSessionRepository()
-
-	s.userService = services.NewUserService(
-		s.userRepo,
-		s.sessionRepo,
-		s.eventPublisher,
-		s.validator,
-	)
 
 	// Background steps
 	ctx.Given(`^a clean user system$`, s.cleanUserSystem)
@@ -91,6 +84,7 @@ func (s *UserFeaturesTestSuite) InitializeContext(ctx *godog.ScenarioContext) {
 	ctx.When(`^I verify the user account$`, s.verifyUserAccount)
 	ctx.When(`^I deactivate the user account$`, s.deactivateUserAccount)
 	ctx.When(`^I get user statistics$`, s.getUserStatistics)
+	ctx.When(`^I get the user with ID (\d+)$`, s.getUserByID)
 	ctx.When(`^the user role is "([^"]*)"$`, s.setUserRole)
 	ctx.When(`^the user status is "([^"]*)"$`, s.setUserStatus)
 	ctx.When(`^the session expires$`, s.expireSession)
@@ -144,13 +138,19 @@ func (s *UserFeaturesTestSuite) InitializeContext(ctx *godog.ScenarioContext) {
 // Background steps
 
 func (s *UserFeaturesTestSuite) cleanUserSystem() error {
-	s.userRepo = integration.NewMockUserRepository()
-	s.sessionRepo = integration.NewMockSessionRepository()
+	userRepo, sessionRepo, err := s.backend.repositories()
+	if err != nil {
+		return err
+	}
+
+	s.userRepo = userRepo
+	s.sessionRepo = sessionRepo
 	s.userService = services.NewUserService(
 		s.userRepo,
 		s.sessionRepo,
 		s.eventPublisher,
 		s.validator,
+		s.backend.serviceOptions()...,
 	)
 	s.currentUser = nil
 	s.currentSession = nil
@@ -229,7 +229,7 @@ func (s *UserFeaturesTestSuite) haveValidUserCredentials() error {
 	s.lastError = err
 
 	if user != nil {
-		s.userRepo.SetPasswordVerification(user.Email().String(), "correct_password")
+		return s.backend.setPassword(context.Background(), s.userRepo, user, "correct_password")
 	}
 
 	return nil
@@ -256,9 +256,7 @@ func (s *UserFeaturesTestSuite) haveValidCredentialsForCurrentAccount() error {
 		return errors.New("no current user to set credentials for")
 	}
 
-	s.userRepo.SetPasswordVerification(s.currentUser.Email().String(), "correct_password")
-
-	return nil
+	return s.backend.setPassword(context.Background(), s.userRepo, s.currentUser, "correct_password")
 }
 
 func (s *UserFeaturesTestSuite) createMultipleStatusAccounts() error {
@@ -440,6 +438,17 @@ func (s *UserFeaturesTestSuite) getUserStatistics() error {
 	return nil
 }
 
+func (s *UserFeaturesTestSuite) getUserByID(id int64) error {
+	user, err := s.userService.GetUser(context.Background(), entities.UserID(id))
+	s.lastError = err
+
+	if err == nil {
+		s.currentUser = user
+	}
+
+	return nil
+}
+
 func (s *UserFeaturesTestSuite) setUserRole(role string) error {
 	if s.currentUser == nil {
 		return errors.New("no current user to set role")
@@ -562,7 +571,7 @@ func (s *UserFeaturesTestSuite) requireCurrentUserStringProperty(
 func (s *UserFeaturesTestSuite) userShouldHaveID(expectedIDStr string) error {
 	return s.requireCurrentUserStringProperty(
 		"no current user",
-		func() string { return s.currentUser.ID().String() },
+		func() string { return strconv.FormatInt(s.currentUser.ID().Int64(), 10) },
 		expectedIDStr,
 		"ID",
 	)
@@ -907,10 +916,11 @@ func TestUserFeatures(t *testing.T) {
 	}
 
 	featurePath := filepath.Join(wd, "..", "..", "..", "test", "features", "user")
+	db := newBackend(t)
 
 	suite := godog.TestSuite{
 		ScenarioInitializer: func(ctx *godog.ScenarioContext) {
-			features := &UserFeaturesTestSuite{}
+			features := &UserFeaturesTestSuite{backend: db}
 			features.InitializeContext(ctx)
 		},
 		Options: &godog.Options{
@@ -928,17 +938,10 @@ func TestUserFeatures(t *testing.T) {
 func TestUserManagementFeatures(t *testing.T) {
 	t.Parallel()
 
-	suite := &UserFeaturesTestSuite{}
+	suite := &UserFeaturesTestSuite{backend: mockBackend{}}
 	suite.eventPublisher = events.NewInMemoryEventPublisher()
 	suite.validator = validation.NewUserValidator()
-	suite.userRepo = integration.NewMockUserRepository()
-	suite.sessionRepo = integration.NewMockSessionRepository()
-	suite.userService = services.NewUserService(
-		suite.userRepo,
-		suite.sessionRepo,
-		suite.eventPublisher,
-		suite.validator,
-	)
+	require.NoError(t, suite.cleanUserSystem())
 
 	t.Run("User Creation", func(t *testing.T) {
 		err := suite.createUserWithValidData()
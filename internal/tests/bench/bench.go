@@ -0,0 +1,23 @@
+// Package bench benchmarks the repository adapters of every engine.
+//
+// The benchmarks compare access patterns that have a cheaper and a more
+// expensive variant, so a regression in an adapter shows up as a change in
+// the ratio as well as in absolute time:
+//
+//   - single inserts with Create against batches with CreateBatch,
+//   - point lookups against searches,
+//   - offset against keyset pagination, on the first page and deep in the list,
+//   - user creation through the service with and without event publishing.
+//
+// Datasets are built with pkg/fixtures, so every run inserts the same users.
+// Sub-benchmarks are named key=value, which benchstat uses to group results
+// by engine:
+//
+//	go test -tags sqlite,postgres,mysql -run '^$' -bench . -count 10 ./internal/tests/bench > new.txt
+//	benchstat old.txt new.txt
+//	benchstat -col /engine new.txt
+//
+// The in-memory repositories always run and serve as the baseline. The other
+// engines are behind their build tags and use internal/tests/containers, so
+// they need Docker or a TEST_POSTGRES_DSN / TEST_MYSQL_DSN.
+package bench
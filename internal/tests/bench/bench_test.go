@@ -0,0 +1,347 @@
+package bench
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/memory"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/services"
+	"github.com/LarsArtmann/template-sqlc/internal/validation"
+	"github.com/LarsArtmann/template-sqlc/pkg/fixtures"
+	"github.com/stretchr/testify/require"
+)
+
+// engine creates empty user repositories of one database engine.
+type engine struct {
+	name string
+	// newUserRepository returns a repository on a freshly migrated database,
+	// which is removed when tb finishes.
+	newUserRepository func(tb testing.TB) repositories.UserRepository
+}
+
+// engines holds the memory engine and those registered by the engine files
+// compiled in with their build tags.
+var engines = []engine{{
+	name: "memory",
+	newUserRepository: func(testing.TB) repositories.UserRepository {
+		return memory.NewUserRepository()
+	},
+}}
+
+// register adds e to the benchmarked engines.
+func register(e engine) {
+	engines = append(engines, e)
+}
+
+// seedBatchSize is the CreateBatch chunk size used to seed datasets.
+const seedBatchSize = 500
+
+// seed inserts count fixture users named user0001, user0002 and so on.
+func seed(tb testing.TB, repo repositories.UserRepository, count int) []*entities.User {
+	tb.Helper()
+
+	users, err := fixtures.NewSeeder(repo, fixtures.WithBatchSize(seedBatchSize)).
+		GenerateUsers(context.Background(), "user", count, nil)
+	require.NoError(tb, err)
+
+	return users
+}
+
+// buildUsers builds count unsaved fixture users named prefix00000001 and so on.
+func buildUsers(tb testing.TB, prefix string, count int) []*entities.User {
+	tb.Helper()
+
+	users := make([]*entities.User, count)
+
+	for i := range users {
+		user, err := fixtures.User().Named(fmt.Sprintf("%s%08d", prefix, i+1)).Build()
+		require.NoError(tb, err)
+
+		users[i] = user
+	}
+
+	return users
+}
+
+// discardPublisher drops every event; it measures the service without
+// publishing cost.
+type discardPublisher struct{}
+
+var _ events.EventPublisher = discardPublisher{}
+
+func (discardPublisher) Publish(*events.UserEvent) error { return nil }
+
+func (discardPublisher) PublishBatch([]*events.UserEvent) error { return nil }
+
+const (
+	// datasetSize is the number of users seeded for read benchmarks.
+	datasetSize = 1000
+	// batchSize is the number of users inserted per CreateBatch.
+	batchSize = 100
+	// pageSize is the number of users fetched per page or search.
+	pageSize = 20
+	// passwordHash is a bcrypt hash, as CreateUser validates the hash format.
+	passwordHash = "$2a$10$N9qo8uLOickgx2ZMRZoMyeIjZRGdjGj/n3.rsQ5pPjZ5yVlWK5WAe"
+)
+
+// forEachEngine runs bench as a sub-benchmark named engine=<name> per engine.
+func forEachEngine(b *testing.B, bench func(b *testing.B, e engine)) {
+	b.Helper()
+
+	for _, e := range engines {
+		b.Run("engine="+e.name, func(b *testing.B) {
+			bench(b, e)
+		})
+	}
+}
+
+// reportPerUser reports the time per user when every op handles perOp users.
+func reportPerUser(b *testing.B, perOp int) {
+	b.Helper()
+
+	b.ReportMetric(float64(b.Elapsed().Nanoseconds())/float64(b.N*perOp), "ns/user")
+}
+
+// Insert benchmarks start a fresh repository every datasetSize users, with the
+// timer stopped, so ns/op does not depend on how many users earlier iterations
+// left behind and the same fixture users can be inserted again.
+func BenchmarkInsert(b *testing.B) {
+	forEachEngine(b, func(b *testing.B, e engine) {
+		users := buildUsers(b, "insert", datasetSize)
+
+		b.Run("mode=single", func(b *testing.B) {
+			ctx := context.Background()
+
+			var repo repositories.UserRepository
+
+			b.ReportAllocs()
+
+			for i := range b.N {
+				if i%datasetSize == 0 {
+					b.StopTimer()
+					repo = e.newUserRepository(b)
+					b.StartTimer()
+				}
+
+				err := repo.Create(ctx, users[i%datasetSize])
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+
+			reportPerUser(b, 1)
+		})
+
+		b.Run(fmt.Sprintf("mode=batch%d", batchSize), func(b *testing.B) {
+			ctx := context.Background()
+			batches := datasetSize / batchSize
+
+			var repo repositories.UserRepository
+
+			b.ReportAllocs()
+
+			for i := range b.N {
+				batch := i % batches
+				if batch == 0 {
+					b.StopTimer()
+					repo = e.newUserRepository(b)
+					b.StartTimer()
+				}
+
+				err := repo.CreateBatch(ctx, users[batch*batchSize:(batch+1)*batchSize])
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+
+			reportPerUser(b, batchSize)
+		})
+	})
+}
+
+func BenchmarkLookup(b *testing.B) {
+	forEachEngine(b, func(b *testing.B, e engine) {
+		ctx := context.Background()
+		repo := e.newUserRepository(b)
+		users := seed(b, repo, datasetSize)
+
+		b.Run("pattern=id", func(b *testing.B) {
+			b.ReportAllocs()
+
+			for i := 0; b.Loop(); i++ {
+				_, err := repo.GetByID(ctx, users[i%len(users)].ID())
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+
+		b.Run("pattern=email", func(b *testing.B) {
+			b.ReportAllocs()
+
+			for i := 0; b.Loop(); i++ {
+				_, err := repo.GetByEmail(ctx, users[i%len(users)].Email())
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+
+		// "user01" matches the hundred users user0100 to user0199.
+		b.Run("pattern=search", func(b *testing.B) {
+			b.ReportAllocs()
+
+			for b.Loop() {
+				result, err := repo.SearchWithFacets(ctx, "user01", "", pageSize)
+				if err != nil {
+					b.Fatal(err)
+				}
+
+				if len(result.Users) == 0 {
+					b.Fatal("search matched no users")
+				}
+			}
+		})
+	})
+}
+
+func BenchmarkPagination(b *testing.B) {
+	forEachEngine(b, func(b *testing.B, e engine) {
+		ctx := context.Background()
+		repo := e.newUserRepository(b)
+		seed(b, repo, datasetSize)
+
+		for _, depth := range []int{0, datasetSize / 2} {
+			b.Run(fmt.Sprintf("strategy=offset/depth=%d", depth), func(b *testing.B) {
+				b.ReportAllocs()
+
+				for b.Loop() {
+					users, err := repo.List(ctx, entities.UserFilter{}, pageSize, depth)
+					if err != nil {
+						b.Fatal(err)
+					}
+
+					if len(users) != pageSize {
+						b.Fatalf("got %d users, want %d", len(users), pageSize)
+					}
+				}
+			})
+
+			b.Run(fmt.Sprintf("strategy=keyset/depth=%d", depth), func(b *testing.B) {
+				cursor := keysetCursor(b, repo, depth)
+
+				b.ReportAllocs()
+
+				for b.Loop() {
+					page, err := repo.ListPage(ctx, entities.UserFilter{}, cursor, pageSize)
+					if err != nil {
+						b.Fatal(err)
+					}
+
+					if len(page.Users) != pageSize {
+						b.Fatalf("got %d users, want %d", len(page.Users), pageSize)
+					}
+				}
+			})
+		}
+	})
+}
+
+// keysetCursor walks the pages of repo and returns the cursor of the page
+// that starts depth users into the list.
+func keysetCursor(b *testing.B, repo repositories.UserRepository, depth int) string {
+	b.Helper()
+
+	cursor := ""
+
+	for range depth / pageSize {
+		page, err := repo.ListPage(context.Background(), entities.UserFilter{}, cursor, pageSize)
+		require.NoError(b, err)
+
+		cursor = page.NextCursor
+	}
+
+	return cursor
+}
+
+// BenchmarkEventPublishing measures CreateUser with each publisher. Like the
+// insert benchmarks, it starts a fresh service every datasetSize users.
+func BenchmarkEventPublishing(b *testing.B) {
+	publishers := []struct {
+		name string
+		new  func(b *testing.B) events.EventPublisher
+	}{
+		{"none", func(*testing.B) events.EventPublisher { return discardPublisher{} }},
+		{"memory", func(*testing.B) events.EventPublisher { return events.NewInMemoryEventPublisher() }},
+		{"dispatcher", newDispatcher},
+	}
+	requests := createUserRequests(datasetSize)
+
+	forEachEngine(b, func(b *testing.B, e engine) {
+		for _, publisher := range publishers {
+			b.Run("publisher="+publisher.name, func(b *testing.B) {
+				ctx := context.Background()
+
+				var service *services.UserService
+
+				b.ReportAllocs()
+
+				for i := range b.N {
+					if i%datasetSize == 0 {
+						b.StopTimer()
+						service = services.NewUserService(
+							e.newUserRepository(b),
+							memory.NewSessionRepository(),
+							publisher.new(b),
+							validation.NewUserValidator(),
+						)
+						b.StartTimer()
+					}
+
+					_, err := service.CreateUser(ctx, requests[i%datasetSize])
+					if err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	})
+}
+
+// newDispatcher returns a dispatcher with one subscriber that handles every
+// event, drained when b finishes.
+func newDispatcher(b *testing.B) events.EventPublisher {
+	b.Helper()
+
+	dispatcher := events.NewDispatcher()
+	dispatcher.Subscribe("discard", events.EventHandlerFunc(func(context.Context, *events.UserEvent) error {
+		return nil
+	}))
+	b.Cleanup(func() { _ = dispatcher.Close(context.Background()) })
+
+	return dispatcher
+}
+
+// createUserRequests returns count requests for distinct users.
+func createUserRequests(count int) []*services.CreateUserRequest {
+	requests := make([]*services.CreateUserRequest, count)
+
+	for i := range requests {
+		name := fmt.Sprintf("event%08d", i+1)
+		requests[i] = &services.CreateUserRequest{
+			Email:        name + "@example.com",
+			Username:     name,
+			PasswordHash: passwordHash,
+			FirstName:    "Event",
+			LastName:     "Fixture",
+			Status:       entities.UserStatusActive.String(),
+			Role:         entities.UserRoleUser.String(),
+		}
+	}
+
+	return requests
+}
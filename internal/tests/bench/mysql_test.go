@@ -0,0 +1,21 @@
+//go:build mysql
+
+package bench
+
+import (
+	"testing"
+
+	mysqladapter "github.com/LarsArtmann/template-sqlc/internal/adapters/mysql"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+	"github.com/LarsArtmann/template-sqlc/internal/tests/containers"
+)
+
+//nolint:gochecknoinits // Engines register themselves behind their build tags.
+func init() {
+	register(engine{
+		name: "mysql",
+		newUserRepository: func(tb testing.TB) repositories.UserRepository {
+			return mysqladapter.NewUserRepository(containers.MySQL(tb))
+		},
+	})
+}
@@ -0,0 +1,21 @@
+//go:build postgres
+
+package bench
+
+import (
+	"testing"
+
+	postgresadapter "github.com/LarsArtmann/template-sqlc/internal/adapters/postgres"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+	"github.com/LarsArtmann/template-sqlc/internal/tests/containers"
+)
+
+//nolint:gochecknoinits // Engines register themselves behind their build tags.
+func init() {
+	register(engine{
+		name: "postgres",
+		newUserRepository: func(tb testing.TB) repositories.UserRepository {
+			return postgresadapter.NewUserRepository(containers.Postgres(tb))
+		},
+	})
+}
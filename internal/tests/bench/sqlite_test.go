@@ -0,0 +1,21 @@
+//go:build sqlite
+
+package bench
+
+import (
+	"testing"
+
+	sqliteadapter "github.com/LarsArtmann/template-sqlc/internal/adapters/sqlite"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+	"github.com/LarsArtmann/template-sqlc/internal/tests/containers"
+)
+
+//nolint:gochecknoinits // Engines register themselves behind their build tags.
+func init() {
+	register(engine{
+		name: "sqlite",
+		newUserRepository: func(tb testing.TB) repositories.UserRepository {
+			return sqliteadapter.NewUserRepository(containers.SQLite(tb))
+		},
+	})
+}
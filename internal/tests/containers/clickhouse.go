@@ -0,0 +1,90 @@
+//go:build clickhouse
+
+package containers
+
+import (
+	"context"
+	"database/sql"
+	"net/url"
+	"os"
+	"sync"
+	"testing"
+
+	_ "github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go/modules/clickhouse"
+)
+
+// ClickHouseImage is the image of the ClickHouse container.
+const ClickHouseImage = "clickhouse/clickhouse-server:25.8-alpine"
+
+var clickhouseServer struct {
+	once sync.Once
+	dsn  string
+	err  error
+}
+
+// clickhouseDSN returns TEST_CLICKHOUSE_DSN or the DSN of the shared container.
+func clickhouseDSN(tb testing.TB) string {
+	tb.Helper()
+
+	if dsn := os.Getenv("TEST_CLICKHOUSE_DSN"); dsn != "" {
+		return dsn
+	}
+
+	skipWithoutDocker(tb)
+
+	clickhouseServer.once.Do(func() {
+		ctx := context.Background()
+
+		container, err := clickhouse.Run(ctx, ClickHouseImage,
+			clickhouse.WithUsername("test"),
+			clickhouse.WithPassword("test"),
+		)
+		if err != nil {
+			clickhouseServer.err = err
+
+			return
+		}
+
+		clickhouseServer.dsn, clickhouseServer.err = container.ConnectionString(ctx)
+	})
+
+	require.NoError(tb, clickhouseServer.err, "start ClickHouse container")
+
+	return clickhouseServer.dsn
+}
+
+// ClickHouse returns a database handle on a new database with every
+// ClickHouse migration applied. The database is dropped after the test.
+func ClickHouse(tb testing.TB) *sql.DB {
+	tb.Helper()
+
+	name := uniqueName()
+
+	admin, err := sql.Open("clickhouse", clickhouseDSN(tb))
+	require.NoError(tb, err)
+	tb.Cleanup(func() { _ = admin.Close() })
+
+	_, err = admin.Exec("CREATE DATABASE " + name)
+	require.NoError(tb, err)
+	tb.Cleanup(func() { _, _ = admin.Exec("DROP DATABASE " + name) })
+
+	dsn, err := url.Parse(clickhouseDSN(tb))
+	require.NoError(tb, err)
+	dsn.Path = "/" + name
+
+	db, err := sql.Open("clickhouse", dsn.String())
+	require.NoError(tb, err)
+	tb.Cleanup(func() { _ = db.Close() })
+
+	migrations, err := Migrations("clickhouse")
+	require.NoError(tb, err)
+
+	for i, ddl := range migrations {
+		_, err = db.Exec(ddl)
+		require.NoError(tb, err, "migration %d", i+1)
+	}
+
+	return db
+}
@@ -0,0 +1,86 @@
+//go:build cockroachdb
+
+package containers
+
+import (
+	"context"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go/modules/cockroachdb"
+)
+
+// CockroachDBImage is the image of the CockroachDB container.
+const CockroachDBImage = "cockroachdb/cockroach:latest-v25.2"
+
+var cockroachServer struct {
+	once sync.Once
+	dsn  string
+	err  error
+}
+
+// cockroachDSN returns TEST_COCKROACHDB_DSN or the DSN of the shared container.
+func cockroachDSN(tb testing.TB) string {
+	tb.Helper()
+
+	if dsn := os.Getenv("TEST_COCKROACHDB_DSN"); dsn != "" {
+		return dsn
+	}
+
+	skipWithoutDocker(tb)
+
+	cockroachServer.once.Do(func() {
+		ctx := context.Background()
+
+		container, err := cockroachdb.Run(ctx, CockroachDBImage, cockroachdb.WithInsecure())
+		if err != nil {
+			cockroachServer.err = err
+
+			return
+		}
+
+		cockroachServer.dsn, cockroachServer.err = container.ConnectionString(ctx)
+	})
+
+	require.NoError(tb, cockroachServer.err, "start CockroachDB container")
+
+	return cockroachServer.dsn
+}
+
+// CockroachDB returns a pool on a new database with every CockroachDB
+// migration applied. The database is dropped after the test.
+func CockroachDB(tb testing.TB) *pgxpool.Pool {
+	tb.Helper()
+
+	ctx := context.Background()
+	name := uniqueName()
+
+	admin, err := pgxpool.New(ctx, cockroachDSN(tb))
+	require.NoError(tb, err)
+	tb.Cleanup(admin.Close)
+
+	_, err = admin.Exec(ctx, "CREATE DATABASE "+name)
+	require.NoError(tb, err)
+	tb.Cleanup(func() { _, _ = admin.Exec(context.Background(), "DROP DATABASE "+name+" CASCADE") })
+
+	config, err := pgxpool.ParseConfig(cockroachDSN(tb))
+	require.NoError(tb, err)
+	config.ConnConfig.Database = name
+
+	pool, err := pgxpool.NewWithConfig(ctx, config)
+	require.NoError(tb, err)
+	tb.Cleanup(pool.Close)
+
+	migrations, err := Migrations("cockroachdb")
+	require.NoError(tb, err)
+
+	for i, ddl := range migrations {
+		_, err = pool.Exec(ctx, ddl)
+		require.NoError(tb, err, "migration %d", i+1)
+	}
+
+	return pool
+}
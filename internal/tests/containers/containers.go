@@ -0,0 +1,96 @@
+// Package containers provides migrated databases for the integration tests
+// and benchmarks.
+//
+// PostgreSQL, MySQL, CockroachDB, ClickHouse and Elasticsearch run in
+// containers started with testcontainers-go, so the tests only need Docker.
+// A container is started on first use, shared by the tests of a package, and
+// removed by the testcontainers reaper when the test binary exits. Every test
+// gets its own schema, database or index, with all migrations of the engine
+// applied, and can run in parallel.
+//
+// Setting TEST_POSTGRES_DSN, TEST_MYSQL_DSN, TEST_COCKROACHDB_DSN,
+// TEST_CLICKHOUSE_DSN or TEST_ELASTICSEARCH_URL uses that server instead of
+// a container. Without Docker and without a DSN the tests are skipped.
+//
+// The engine helpers are behind the engine build tags:
+//
+//	go test -tags postgres,mysql,sqlite ./internal/tests/integration/...
+//	go test -tags postgres,cockroachdb ./internal/tests/integration/...
+//	go test -tags clickhouse ./internal/tests/integration/...
+//	go test -tags sqlite,elasticsearch ./internal/tests/integration/...
+package containers
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/testcontainers/testcontainers-go"
+)
+
+// sequence keeps names unique between tests started in the same instant.
+var sequence atomic.Int64
+
+// uniqueName returns a name for a throwaway schema or database.
+func uniqueName() string {
+	return fmt.Sprintf("test_%d_%d", time.Now().UnixNano(), sequence.Add(1))
+}
+
+// skipWithoutDocker skips tb when no healthy Docker provider is available.
+// It is testcontainers.SkipIfProviderIsNotHealthy for benchmarks as well as tests.
+func skipWithoutDocker(tb testing.TB) {
+	tb.Helper()
+
+	defer func() {
+		if r := recover(); r != nil {
+			tb.Skipf("Docker is not running: %v", r)
+		}
+	}()
+
+	provider, err := testcontainers.ProviderDocker.GetProvider()
+	if err != nil {
+		tb.Skipf("Docker is not running: %v", err)
+	}
+
+	err = provider.Health(context.Background())
+	if err != nil {
+		tb.Skipf("Docker is not running: %v", err)
+	}
+}
+
+// Migrations returns the DDL of every migration of engine, in file order.
+func Migrations(engine string) ([]string, error) {
+	_, file, _, _ := runtime.Caller(0)
+	dir := filepath.Join(filepath.Dir(file), "..", "..", "..", "sql", engine, "schema")
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.sql"))
+	if err != nil {
+		return nil, fmt.Errorf("engine=%v: %w", engine, err)
+	}
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("engine=%v dir=%v: %w", engine, dir, os.ErrNotExist)
+	}
+
+	sort.Strings(files)
+
+	migrations := make([]string, 0, len(files))
+
+	for _, name := range files {
+		ddl, err := os.ReadFile(name)
+		if err != nil {
+			return nil, fmt.Errorf("migration=%v: %w", name, err)
+		}
+
+		migrations = append(migrations, strings.TrimSpace(string(ddl)))
+	}
+
+	return migrations, nil
+}
@@ -0,0 +1,32 @@
+//go:build duckdb
+
+package containers
+
+import (
+	"database/sql"
+	"testing"
+
+	_ "github.com/duckdb/duckdb-go/v2"
+	"github.com/stretchr/testify/require"
+)
+
+// DuckDB opens an in-memory DuckDB database with every DuckDB migration applied.
+func DuckDB(tb testing.TB) *sql.DB {
+	tb.Helper()
+
+	db, err := sql.Open("duckdb", "")
+	require.NoError(tb, err)
+	// Every connection to an in-memory database would open a database of its own.
+	db.SetMaxOpenConns(1)
+	tb.Cleanup(func() { _ = db.Close() })
+
+	migrations, err := Migrations("duckdb")
+	require.NoError(tb, err)
+
+	for i, ddl := range migrations {
+		_, err = db.Exec(ddl)
+		require.NoError(tb, err, "migration %d", i+1)
+	}
+
+	return db
+}
@@ -0,0 +1,71 @@
+//go:build elasticsearch
+
+package containers
+
+import (
+	"context"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/elastic/go-elasticsearch/v8"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	tcelasticsearch "github.com/testcontainers/testcontainers-go/modules/elasticsearch"
+)
+
+// ElasticsearchImage is the image of the Elasticsearch container.
+const ElasticsearchImage = "docker.elastic.co/elasticsearch/elasticsearch:8.19.7"
+
+var elasticsearchServer struct {
+	once sync.Once
+	url  string
+	err  error
+}
+
+// elasticsearchURL returns TEST_ELASTICSEARCH_URL or the URL of the shared container.
+func elasticsearchURL(tb testing.TB) string {
+	tb.Helper()
+
+	if url := os.Getenv("TEST_ELASTICSEARCH_URL"); url != "" {
+		return url
+	}
+
+	skipWithoutDocker(tb)
+
+	elasticsearchServer.once.Do(func() {
+		container, err := tcelasticsearch.Run(context.Background(), ElasticsearchImage,
+			testcontainers.WithEnv(map[string]string{"xpack.security.enabled": "false"}),
+		)
+		if err != nil {
+			elasticsearchServer.err = err
+
+			return
+		}
+
+		elasticsearchServer.url = container.Settings.Address
+	})
+
+	require.NoError(tb, elasticsearchServer.err, "start Elasticsearch container")
+
+	return elasticsearchServer.url
+}
+
+// Elasticsearch returns a client and the name of an index of the test,
+// which is deleted after the test if it was created.
+func Elasticsearch(tb testing.TB) (*elasticsearch.Client, string) {
+	tb.Helper()
+
+	client, err := elasticsearch.NewClient(elasticsearch.Config{Addresses: []string{elasticsearchURL(tb)}})
+	require.NoError(tb, err)
+
+	index := uniqueName()
+	tb.Cleanup(func() {
+		res, err := client.Indices.Delete([]string{index})
+		if err == nil {
+			_ = res.Body.Close()
+		}
+	})
+
+	return client, index
+}
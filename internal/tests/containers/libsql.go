@@ -0,0 +1,41 @@
+//go:build libsql
+
+package containers
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/libsql"
+	"github.com/libsql/sqlite-antlr4-parser/sqliteparserutils"
+	"github.com/stretchr/testify/require"
+)
+
+// LibSQL creates a local libSQL database file with every SQLite migration
+// applied. libSQL shares the SQLite dialect and migrations, but executes one
+// statement per call, so the migrations are split into statements.
+func LibSQL(tb testing.TB) *sql.DB {
+	tb.Helper()
+
+	connector, err := libsql.Connector(libsql.Config{URL: "file:" + filepath.Join(tb.TempDir(), "libsql.db")})
+	require.NoError(tb, err)
+
+	db := sql.OpenDB(connector)
+	db.SetMaxOpenConns(1)
+	tb.Cleanup(func() { _ = db.Close() })
+
+	migrations, err := Migrations("sqlite")
+	require.NoError(tb, err)
+
+	for i, ddl := range migrations {
+		statements, _ := sqliteparserutils.SplitStatement(ddl)
+
+		for _, statement := range statements {
+			_, err = db.Exec(statement)
+			require.NoError(tb, err, "migration %d", i+1)
+		}
+	}
+
+	return db
+}
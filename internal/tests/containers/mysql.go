@@ -0,0 +1,93 @@
+//go:build mysql
+
+package containers
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"sync"
+	"testing"
+
+	driver "github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go/modules/mysql"
+)
+
+// MySQLImage is the image of the MySQL container.
+const MySQLImage = "mysql:8.4"
+
+var mysqlServer struct {
+	once sync.Once
+	dsn  string
+	err  error
+}
+
+// mysqlDSN returns TEST_MYSQL_DSN or the DSN of the shared container, whose
+// root user may create databases.
+func mysqlDSN(tb testing.TB) string {
+	tb.Helper()
+
+	if dsn := os.Getenv("TEST_MYSQL_DSN"); dsn != "" {
+		return dsn
+	}
+
+	skipWithoutDocker(tb)
+
+	mysqlServer.once.Do(func() {
+		ctx := context.Background()
+
+		container, err := mysql.Run(ctx, MySQLImage,
+			mysql.WithDatabase("app"),
+			mysql.WithUsername("root"),
+			mysql.WithPassword("app"),
+		)
+		if err != nil {
+			mysqlServer.err = err
+
+			return
+		}
+
+		mysqlServer.dsn, mysqlServer.err = container.ConnectionString(ctx)
+	})
+
+	require.NoError(tb, mysqlServer.err, "start MySQL container")
+
+	return mysqlServer.dsn
+}
+
+// MySQL returns a connection to a new database with every MySQL migration
+// applied. The database is dropped after the test.
+func MySQL(tb testing.TB) *sql.DB {
+	tb.Helper()
+
+	config, err := driver.ParseDSN(mysqlDSN(tb))
+	require.NoError(tb, err)
+	config.ParseTime = true
+	config.MultiStatements = true
+
+	admin, err := sql.Open("mysql", config.FormatDSN())
+	require.NoError(tb, err)
+	tb.Cleanup(func() { _ = admin.Close() })
+
+	name := uniqueName()
+	_, err = admin.Exec("CREATE DATABASE " + name)
+	require.NoError(tb, err)
+	tb.Cleanup(func() { _, _ = admin.Exec("DROP DATABASE " + name) })
+
+	config.DBName = name
+
+	db, err := sql.Open("mysql", config.FormatDSN())
+	require.NoError(tb, err)
+	tb.Cleanup(func() { _ = db.Close() })
+
+	migrations, err := Migrations("mysql")
+	require.NoError(tb, err)
+
+	for i, ddl := range migrations {
+		_, err = db.Exec(ddl)
+		require.NoError(tb, err, "migration %d", i+1)
+	}
+
+	return db
+}
@@ -0,0 +1,87 @@
+//go:build postgres
+
+package containers
+
+import (
+	"context"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+)
+
+// PostgresImage is the image of the PostgreSQL container.
+const PostgresImage = "postgres:17-alpine"
+
+var postgresServer struct {
+	once sync.Once
+	dsn  string
+	err  error
+}
+
+// postgresDSN returns TEST_POSTGRES_DSN or the DSN of the shared container.
+func postgresDSN(tb testing.TB) string {
+	tb.Helper()
+
+	if dsn := os.Getenv("TEST_POSTGRES_DSN"); dsn != "" {
+		return dsn
+	}
+
+	skipWithoutDocker(tb)
+
+	postgresServer.once.Do(func() {
+		ctx := context.Background()
+
+		container, err := postgres.Run(ctx, PostgresImage,
+			postgres.WithDatabase("app"),
+			postgres.WithUsername("app"),
+			postgres.WithPassword("app"),
+			postgres.BasicWaitStrategies(),
+		)
+		if err != nil {
+			postgresServer.err = err
+
+			return
+		}
+
+		postgresServer.dsn, postgresServer.err = container.ConnectionString(ctx, "sslmode=disable")
+	})
+
+	require.NoError(tb, postgresServer.err, "start PostgreSQL container")
+
+	return postgresServer.dsn
+}
+
+// Postgres returns a pool whose search_path is a new schema with every
+// PostgreSQL migration applied. The schema is dropped after the test.
+func Postgres(tb testing.TB) *pgxpool.Pool {
+	tb.Helper()
+
+	ctx := context.Background()
+	schema := uniqueName()
+
+	config, err := pgxpool.ParseConfig(postgresDSN(tb))
+	require.NoError(tb, err)
+	config.ConnConfig.RuntimeParams["search_path"] = schema
+
+	pool, err := pgxpool.NewWithConfig(ctx, config)
+	require.NoError(tb, err)
+	tb.Cleanup(pool.Close)
+
+	_, err = pool.Exec(ctx, "CREATE SCHEMA "+schema)
+	require.NoError(tb, err)
+	tb.Cleanup(func() { _, _ = pool.Exec(context.Background(), "DROP SCHEMA "+schema+" CASCADE") })
+
+	migrations, err := Migrations("postgres")
+	require.NoError(tb, err)
+
+	for i, ddl := range migrations {
+		_, err = pool.Exec(ctx, ddl)
+		require.NoError(tb, err, "migration %d", i+1)
+	}
+
+	return pool
+}
@@ -0,0 +1,46 @@
+//go:build sqlite
+
+package containers
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+)
+
+// SQLite opens an in-memory database with every SQLite migration applied.
+// SQLite needs no container; it is here so every engine is set up alike.
+func SQLite(tb testing.TB) *sql.DB {
+	tb.Helper()
+
+	return openSQLite(tb, ":memory:")
+}
+
+// SQLiteFile creates a database file at path with every SQLite migration
+// applied, for tests that want the database on disk.
+func SQLiteFile(tb testing.TB, path string) *sql.DB {
+	tb.Helper()
+
+	return openSQLite(tb, path)
+}
+
+func openSQLite(tb testing.TB, dsn string) *sql.DB {
+	tb.Helper()
+
+	db, err := sql.Open("sqlite", dsn)
+	require.NoError(tb, err)
+	db.SetMaxOpenConns(1)
+	tb.Cleanup(func() { _ = db.Close() })
+
+	migrations, err := Migrations("sqlite")
+	require.NoError(tb, err)
+
+	for i, ddl := range migrations {
+		_, err = db.Exec(ddl)
+		require.NoError(tb, err, "migration %d", i+1)
+	}
+
+	return db
+}
@@ -0,0 +1,71 @@
+//go:build clickhouse
+
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/clickhouse"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/services"
+	"github.com/LarsArtmann/template-sqlc/internal/tests/containers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClickHouseReporting(t *testing.T) {
+	ctx := context.Background()
+	db := containers.ClickHouse(t)
+	sink := clickhouse.NewSink(db, clickhouse.WithBatchSize(2), clickhouse.WithFlushInterval(time.Hour))
+	reports := services.NewReportingService(clickhouse.NewRepository(db))
+
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	at := func(event *events.UserEvent, offset time.Duration) *events.UserEvent {
+		event.Timestamp = day.Add(offset)
+
+		return event
+	}
+
+	published := []*events.UserEvent{
+		at(events.UserCreated(1, "ada@example.com", "ada", "Ada", "L", "user", "active"), time.Hour),
+		at(events.NewUserEvent(events.EventUserCreated, 4, events.UserCreatedEvent{UserID: 4, Source: "google"}), 26*time.Hour),
+		at(events.UserLoggedIn(1, "10.0.0.1", "test", "desktop"), 2*time.Hour),
+		at(events.UserLoginFailed(2, "10.0.0.9", "test", "invalid_password"), 3*time.Hour),
+		at(events.UserLoginFailed(3, "10.0.0.9", "test", "invalid_password"), 4*time.Hour),
+		at(events.UserLoginFailed(1, "10.0.0.1", "test", "invalid_password"), 25*time.Hour),
+		at(events.UserFederatedLogin(4, "google", "sub", "10.0.0.2", "test", true), 26*time.Hour),
+		at(events.UserVerified(1, "email"), 27*time.Hour),
+	}
+	published = append(published, published[2])
+
+	for _, event := range published {
+		require.NoError(t, sink.Handle(ctx, event))
+	}
+
+	require.NoError(t, sink.Close(ctx))
+
+	from, to := day, day.AddDate(0, 0, 2)
+
+	logins, err := reports.LoginsByDay(ctx, from, to)
+	require.NoError(t, err)
+	assert.Equal(t, []entities.DailyLogins{
+		{Day: day, Logins: 1, Failures: 2, Users: 1},
+		{Day: day.AddDate(0, 0, 1), Logins: 1, Failures: 1, Users: 1},
+	}, logins, "the duplicate login is counted once")
+
+	failures, err := reports.FailuresByIP(ctx, from, to, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []entities.IPFailures{
+		{IPAddress: "10.0.0.9", Failures: 2, Users: 2, LastAt: day.Add(4 * time.Hour)},
+	}, failures)
+
+	signups, err := reports.SignupsBySource(ctx, from, to)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []entities.SignupSource{
+		{Source: entities.SignupSourceDirect, Signups: 1},
+		{Source: "google", Signups: 1},
+	}, signups)
+}
@@ -0,0 +1,79 @@
+//go:build postgres && cockroachdb
+
+package integration
+
+import (
+	"context"
+	"testing"
+
+	cockroachadapter "github.com/LarsArtmann/template-sqlc/internal/adapters/cockroachdb"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/tests/containers"
+	"github.com/LarsArtmann/template-sqlc/internal/tests/repositorytest"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newCockroachDBUser(t *testing.T, name string) *entities.User {
+	t.Helper()
+
+	user, err := entities.NewUser(
+		entities.Email(name+"@example.com"),
+		entities.Username(name),
+		entities.PasswordHash("hash-"+name),
+		"First",
+		"Last",
+		entities.UserStatusActive,
+		entities.UserRoleUser,
+		nil,
+		nil,
+	)
+	require.NoError(t, err)
+
+	return user
+}
+
+func TestCockroachDBUserRepositoryConformance(t *testing.T) {
+	repositorytest.RunUserRepositoryTests(t, func(t *testing.T) repositorytest.Repositories {
+		db := containers.CockroachDB(t)
+
+		return repositorytest.Repositories{
+			Users:            cockroachadapter.NewUserRepository(db),
+			Sessions:         cockroachadapter.NewSessionRepository(db),
+			Jobs:             cockroachadapter.NewJobRepository(db),
+			Idempotency:      cockroachadapter.NewIdempotencyRepository(db),
+			Notifications:    cockroachadapter.NewNotificationPreferenceRepository(db),
+			IdentityChanges:  cockroachadapter.NewIdentityChangeRepository(db),
+			Moderation:       cockroachadapter.NewModerationRepository(db),
+			AccountDeletions: cockroachadapter.NewAccountDeletionRepository(db),
+			Preferences:      cockroachadapter.NewUserPreferenceRepository(db),
+			Activity:         cockroachadapter.NewActivityRepository(db),
+			LoginHistory:     cockroachadapter.NewLoginHistoryRepository(db),
+			UserEvents:       cockroachadapter.NewUserEventStore(db),
+			UserReadModel:    cockroachadapter.NewUserReadModelRepository(db),
+			FeatureFlags:     cockroachadapter.NewFeatureFlagRepository(db),
+		}
+	})
+}
+
+func TestCockroachDBExecuteTx(t *testing.T) {
+	ctx := context.Background()
+	db := containers.CockroachDB(t)
+
+	user := newCockroachDBUser(t, "ada")
+
+	err := cockroachadapter.ExecuteTx(ctx, db, func(tx pgx.Tx) error {
+		return cockroachadapter.NewUserRepository(tx).Create(ctx, user)
+	})
+	require.NoError(t, err)
+
+	stored, err := cockroachadapter.NewUserRepository(db).GetByID(ctx, user.ID())
+	require.NoError(t, err)
+	assert.Equal(t, user.UUID(), stored.UUID())
+
+	err = cockroachadapter.ExecuteTx(ctx, db, func(tx pgx.Tx) error {
+		return cockroachadapter.NewUserRepository(tx).Create(ctx, newCockroachDBUser(t, "ada"))
+	})
+	require.ErrorIs(t, err, entities.ErrUserAlreadyExists)
+}
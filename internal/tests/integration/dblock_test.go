@@ -0,0 +1,104 @@
+package integration
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/pkg/dblock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+)
+
+// testLocker checks the lock contract with two lockers over one database,
+// which stand for two instances of the application.
+func testLocker(t *testing.T, first, second dblock.Locker) {
+	t.Helper()
+
+	ctx := context.Background()
+	name := t.Name()
+
+	_, err := first.TryLock(ctx, "")
+	require.ErrorIs(t, err, dblock.ErrInvalidName)
+
+	lock, err := first.TryLock(ctx, name)
+	require.NoError(t, err)
+
+	_, err = second.TryLock(ctx, name)
+	require.ErrorIs(t, err, dblock.ErrLocked)
+
+	other, err := second.TryLock(ctx, name+"/other")
+	require.NoError(t, err)
+	require.NoError(t, other.Unlock(ctx))
+
+	waitCtx, cancel := context.WithTimeout(ctx, 200*time.Millisecond)
+	defer cancel()
+
+	_, err = second.Lock(waitCtx, name)
+	require.Error(t, err, "the lock is held")
+
+	acquired := make(chan dblock.Lock)
+
+	go func() {
+		lock, err := second.Lock(ctx, name)
+		assert.NoError(t, err)
+
+		acquired <- lock
+	}()
+
+	require.NoError(t, lock.Unlock(ctx))
+	require.ErrorIs(t, lock.Unlock(ctx), dblock.ErrLockNotHeld)
+
+	select {
+	case lock = <-acquired:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the waiting holder did not acquire the released lock")
+	}
+
+	errFailed := errors.New("failed")
+	err = dblock.Do(ctx, first, name+"/do", func(context.Context) error { return errFailed })
+	require.ErrorIs(t, err, errFailed)
+
+	require.NoError(t, lock.Unlock(ctx))
+}
+
+// openSQLiteFile opens a SQLite database file shared by the tests of t.
+func openSQLiteFile(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "locks.db")+"?_pragma=busy_timeout(5000)")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	return db
+}
+
+func TestTableLocker(t *testing.T) {
+	db := openSQLiteFile(t)
+	opts := []dblock.TableOption{dblock.WithRetryInterval(10 * time.Millisecond)}
+
+	testLocker(t, dblock.NewTable(db, opts...), dblock.NewTable(db, opts...))
+}
+
+func TestTableLockerLeaseExpiry(t *testing.T) {
+	ctx := context.Background()
+	db := openSQLiteFile(t)
+	locker := dblock.NewTable(db, dblock.WithLease(time.Hour))
+
+	lock, err := locker.TryLock(ctx, "crashed")
+	require.NoError(t, err)
+
+	// A holder that crashed stops renewing its lease.
+	_, err = db.ExecContext(ctx, "UPDATE dblock_locks SET expires_at = 0")
+	require.NoError(t, err)
+
+	taken, err := locker.TryLock(ctx, "crashed")
+	require.NoError(t, err)
+
+	require.ErrorIs(t, lock.Unlock(ctx), dblock.ErrLockNotHeld)
+	require.NoError(t, taken.Unlock(ctx))
+}
@@ -0,0 +1,102 @@
+//go:build sqlite && duckdb
+
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/duckdb"
+	sqliteadapter "github.com/LarsArtmann/template-sqlc/internal/adapters/sqlite"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/jobs"
+	"github.com/LarsArtmann/template-sqlc/internal/tests/containers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDuckDBAnalyticsSync(t *testing.T) {
+	ctx := context.Background()
+	source := sqliteadapter.NewUserRepository(containers.SQLite(t))
+	db := containers.DuckDB(t)
+	analytics := duckdb.NewAnalyticsRepository(db)
+
+	create := func(name string) *entities.User {
+		user, err := entities.NewUser(
+			entities.Email(name+"@example.com"),
+			entities.Username(name),
+			entities.PasswordHash("hash-"+name),
+			"First",
+			"Last",
+			entities.UserStatusActive,
+			entities.UserRoleUser,
+			nil,
+			nil,
+		)
+		require.NoError(t, err)
+		require.NoError(t, source.Create(ctx, user))
+
+		return user
+	}
+
+	grace := create("grace")
+	create("alan")
+	barbara := create("barbara")
+	ada := create("ada")
+
+	require.NoError(t, source.MarkVerified(ctx, grace.ID()))
+	require.NoError(t, source.Suspend(ctx, barbara.ID()))
+	require.NoError(t, source.Delete(ctx, ada.ID()))
+
+	syncer := duckdb.NewSyncer(source, db, duckdb.WithBatchSize(2))
+
+	copied, err := syncer.Sync(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), copied, "deleted users are not copied")
+
+	want, err := source.CountByStatus(ctx)
+	require.NoError(t, err)
+
+	counts, err := analytics.CountByStatus(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, want, counts)
+
+	stats, err := analytics.GetStats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), stats.TotalUsers)
+	assert.Equal(t, int64(1), stats.SuspendedUsers)
+	assert.Equal(t, int64(1), stats.VerifiedUsers)
+	assert.Equal(t, int64(3), stats.NewUsers7d)
+
+	now := time.Now().UTC()
+
+	cohorts, err := analytics.Cohorts(ctx, entities.CohortIntervalMonth, now.AddDate(-1, 0, 0))
+	require.NoError(t, err)
+	assert.Equal(t, []entities.UserCohort{{
+		Start:    time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC),
+		Users:    3,
+		Verified: 1,
+		LoggedIn: 0,
+		Active:   2,
+	}}, cohorts)
+
+	cohorts, err = analytics.Cohorts(ctx, entities.CohortIntervalDay, now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, cohorts)
+
+	_, err = analytics.Cohorts(ctx, "year", now)
+	require.ErrorIs(t, err, entities.ErrInvalidCohort)
+
+	// The sync job replaces the copy with the current users.
+	create("margaret")
+	require.NoError(t, jobs.NewAnalyticsSyncHandler(syncer).Handle(ctx, &entities.Job{Name: jobs.AnalyticsSyncJob}))
+
+	stats, err = analytics.GetStats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), stats.TotalUsers)
+
+	syncedAt, err := analytics.SyncedAt(ctx)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), syncedAt, time.Minute)
+}
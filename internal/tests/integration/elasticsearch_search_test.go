@@ -0,0 +1,93 @@
+//go:build sqlite && elasticsearch
+
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/search"
+	sqliteadapter "github.com/LarsArtmann/template-sqlc/internal/adapters/sqlite"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+	"github.com/LarsArtmann/template-sqlc/internal/tests/containers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestElasticsearchSearch(t *testing.T) {
+	ctx := context.Background()
+	db := sqliteadapter.NewUserRepository(containers.SQLite(t))
+	client, name := containers.Elasticsearch(t)
+
+	index := search.NewElasticsearchIndexer(client, name, search.WithRefresh())
+	require.NoError(t, index.CreateIndex(ctx))
+
+	var users []*entities.User
+
+	for _, seed := range []struct {
+		name string
+		tags []string
+	}{
+		{"ada", []string{"math", "pioneer"}},
+		{"adele", []string{"smalltalk"}},
+		{"grace", []string{"cobol", "pioneer"}},
+	} {
+		user, err := entities.NewUser(
+			entities.Email(seed.name+"@example.com"),
+			entities.Username(seed.name),
+			entities.PasswordHash("hash-"+seed.name),
+			"First",
+			"Last",
+			entities.UserStatusActive,
+			entities.UserRoleUser,
+			nil,
+			seed.tags,
+		)
+		require.NoError(t, err)
+		require.NoError(t, db.Create(ctx, user))
+
+		users = append(users, user)
+	}
+
+	indexed, err := search.Reindex(ctx, index, db)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), indexed)
+
+	repo := search.NewUserRepository(db, index)
+
+	for _, filter := range []entities.UserFilter{
+		{Query: "ad"},
+		{TagsAny: []string{"pioneer"}},
+		{TagsAll: []string{"math", "pioneer"}},
+	} {
+		want, err := db.List(ctx, filter, 10, 0)
+		require.NoError(t, err)
+
+		got, err := repo.List(ctx, filter, 10, 0)
+		require.NoError(t, err)
+		assert.Equal(t, searchUsernames(want), searchUsernames(got), "filter %+v", filter)
+	}
+
+	sync := search.NewSyncHandler(index, db)
+	require.NoError(t, db.Suspend(ctx, users[0].ID()))
+	require.NoError(t, sync.Handle(ctx, events.NewUserEvent(events.EventUserSuspended, users[0].ID(), nil)))
+
+	result, err := repo.SearchWithFacets(ctx, "ad", entities.UserStatusSuspended, 10)
+	require.NoError(t, err)
+	require.Len(t, result.Users, 1)
+	assert.Equal(t, users[0].ID(), result.Users[0].ID())
+	assert.Equal(t, map[entities.UserStatus]int64{
+		entities.UserStatusActive:    1,
+		entities.UserStatusSuspended: 1,
+	}, result.Facets.ByStatus)
+}
+
+func searchUsernames(users []*entities.User) []string {
+	names := make([]string, 0, len(users))
+	for _, user := range users {
+		names = append(names, user.Username().String())
+	}
+
+	return names
+}
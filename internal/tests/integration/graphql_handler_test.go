@@ -0,0 +1,230 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/graphql"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/services"
+	"github.com/LarsArtmann/template-sqlc/internal/validation"
+	"github.com/LarsArtmann/template-sqlc/pkg/passwords"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// graphqlPassword is the password of the users of the GraphQL tests.
+const graphqlPassword = "Correct-Horse-42"
+
+// newGraphQLUserService creates a user service hashing with the cheapest bcrypt cost.
+func newGraphQLUserService(
+	userRepo *MockUserRepository,
+	sessionRepo *MockSessionRepository,
+) *services.UserService {
+	return services.NewUserService(
+		userRepo,
+		sessionRepo,
+		events.NewInMemoryEventPublisher(),
+		validation.NewUserValidator(),
+		services.WithPasswordHasher(passwords.NewBcryptHasher(bcrypt.MinCost)),
+	)
+}
+
+// createGraphQLUser creates an active user with role and signs them in,
+// returning the user and a session token.
+func createGraphQLUser(
+	t *testing.T,
+	users *services.UserService,
+	name string,
+	role entities.UserRole,
+) (*entities.User, string) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	req := newTestCreateUserRequest(name, "First", "Last")
+	req.Email = name + "@example.com"
+	req.PasswordHash = ""
+	req.Password = graphqlPassword
+	req.Role = string(role)
+
+	user, err := users.CreateUser(ctx, req)
+	require.NoError(t, err)
+
+	session, err := users.AuthenticateUser(ctx, req.Email, graphqlPassword, "127.0.0.1", "test")
+	require.NoError(t, err)
+
+	token, err := users.IssueSessionToken(session, user)
+	require.NoError(t, err)
+
+	return user, token
+}
+
+// postGraphQL sends query to server with token as bearer token, if any.
+func postGraphQL(t *testing.T, server *httptest.Server, token, query string) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, server.URL, strings.NewReader(query))
+	require.NoError(t, err)
+
+	req.Header.Set("Content-Type", "application/json")
+
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+
+	return resp
+}
+
+// graphqlErrorCodes returns the codes of the errors in the body of resp.
+func graphqlErrorCodes(t *testing.T, resp *http.Response) []string {
+	t.Helper()
+
+	defer resp.Body.Close()
+
+	var body struct {
+		Errors []struct {
+			Extensions struct{ Code string }
+		}
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+
+	codes := make([]string, 0, len(body.Errors))
+	for _, presented := range body.Errors {
+		codes = append(codes, presented.Extensions.Code)
+	}
+
+	return codes
+}
+
+// countingUserRepository counts batched lookups.
+type countingUserRepository struct {
+	*MockUserRepository
+
+	batches atomic.Int32
+}
+
+func (r *countingUserRepository) GetByIDs(
+	ctx context.Context,
+	ids []entities.UserID,
+) ([]*entities.User, error) {
+	r.batches.Add(1)
+
+	return r.MockUserRepository.GetByIDs(ctx, ids)
+}
+
+func TestGraphQLBatchesUserLookups(t *testing.T) {
+	userRepo := &countingUserRepository{MockUserRepository: NewMockUserRepository()}
+	sessionRepo := NewMockSessionRepository()
+	users := newGraphQLUserService(userRepo.MockUserRepository, sessionRepo)
+
+	_, token := createGraphQLUser(t, users, "ada", entities.UserRoleAdmin)
+	createGraphQLUser(t, users, "alan", entities.UserRoleUser)
+
+	server := httptest.NewServer(graphql.NewHandler(users, userRepo, sessionRepo))
+	t.Cleanup(server.Close)
+
+	query := `{"query":"{ a: user(id: \"1\") { username } b: user(id: \"2\") { username } stats { totalUsers } }"}`
+
+	resp := postGraphQL(t, server, token, query)
+
+	defer resp.Body.Close()
+
+	var body struct {
+		Data struct {
+			A     struct{ Username string }
+			B     struct{ Username string }
+			Stats struct{ TotalUsers int }
+		}
+		Errors []any
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	require.Empty(t, body.Errors)
+
+	assert.Equal(t, "ada", body.Data.A.Username)
+	assert.Equal(t, "alan", body.Data.B.Username)
+	assert.Equal(t, int32(1), userRepo.batches.Load(), "both users are loaded in one batch")
+}
+
+func TestGraphQLErrorsCarryCodes(t *testing.T) {
+	userRepo := NewMockUserRepository()
+	sessionRepo := NewMockSessionRepository()
+	users := newGraphQLUserService(userRepo, sessionRepo)
+
+	_, token := createGraphQLUser(t, users, "ada", entities.UserRoleAdmin)
+
+	server := httptest.NewServer(graphql.NewHandler(users, userRepo, sessionRepo))
+	t.Cleanup(server.Close)
+
+	query := `{"query":"{ user(id: \"99\") { username } }"}`
+
+	resp := postGraphQL(t, server, token, query)
+
+	defer resp.Body.Close()
+
+	var body struct {
+		Errors []struct {
+			Message    string
+			Extensions struct{ Code string }
+		}
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	require.Len(t, body.Errors, 1)
+	assert.Equal(t, entities.CodeNotFound, body.Errors[0].Extensions.Code)
+	assert.Contains(t, body.Errors[0].Message, "user not found")
+}
+
+func TestGraphQLRequiresSession(t *testing.T) {
+	userRepo := NewMockUserRepository()
+	sessionRepo := NewMockSessionRepository()
+	users := newGraphQLUserService(userRepo, sessionRepo)
+
+	server := httptest.NewServer(graphql.NewHandler(users, userRepo, sessionRepo))
+	t.Cleanup(server.Close)
+
+	for _, token := range []string{"", "forged"} {
+		resp := postGraphQL(t, server, token, `{"query":"{ stats { totalUsers } }"}`)
+		_ = resp.Body.Close()
+
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode, token)
+	}
+}
+
+func TestGraphQLScopesQueriesToTheCaller(t *testing.T) {
+	userRepo := NewMockUserRepository()
+	sessionRepo := NewMockSessionRepository()
+	users := newGraphQLUserService(userRepo, sessionRepo)
+
+	ada, adaToken := createGraphQLUser(t, users, "ada", entities.UserRoleAdmin)
+	alan, alanToken := createGraphQLUser(t, users, "alan", entities.UserRoleUser)
+
+	server := httptest.NewServer(graphql.NewHandler(users, userRepo, sessionRepo))
+	t.Cleanup(server.Close)
+
+	userQuery := func(id entities.UserID) string {
+		return fmt.Sprintf(`{"query":"{ user(id: \"%d\") { username sessions { ipAddress } } }"}`, id)
+	}
+
+	assert.Empty(t, graphqlErrorCodes(t, postGraphQL(t, server, alanToken, userQuery(alan.ID()))))
+	assert.Empty(t, graphqlErrorCodes(t, postGraphQL(t, server, adaToken, userQuery(alan.ID()))))
+
+	forbidden := []string{entities.CodeForbidden}
+	assert.Equal(t, forbidden, graphqlErrorCodes(t, postGraphQL(t, server, alanToken, userQuery(ada.ID()))))
+	assert.Equal(t, forbidden, graphqlErrorCodes(t, postGraphQL(t, server, alanToken,
+		`{"query":"{ users(status: active) { username } }"}`)))
+	assert.Equal(t, forbidden, graphqlErrorCodes(t, postGraphQL(t, server, alanToken,
+		`{"query":"{ stats { totalUsers } }"}`)))
+	assert.Equal(t, forbidden, graphqlErrorCodes(t, postGraphQL(t, server, alanToken,
+		fmt.Sprintf(`{"query":"{ session(token: \"%s\") { isActive } }"}`, adaToken))))
+}
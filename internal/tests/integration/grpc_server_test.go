@@ -0,0 +1,203 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	grpcadapter "github.com/LarsArtmann/template-sqlc/internal/adapters/grpc"
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/grpc/usersv1"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/services"
+	"github.com/LarsArtmann/template-sqlc/internal/monitoring"
+	"github.com/LarsArtmann/template-sqlc/internal/validation"
+	"github.com/LarsArtmann/template-sqlc/pkg/passwords"
+	"github.com/LarsArtmann/template-sqlc/pkg/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// newGRPCClient serves the user service, configured by opts, over an
+// in-memory listener.
+func newGRPCClient(
+	t *testing.T,
+	opts ...services.UserServiceOption,
+) (usersv1.UserServiceClient, *services.UserService) {
+	t.Helper()
+
+	users := services.NewUserService(
+		NewMockUserRepository(),
+		NewMockSessionRepository(),
+		events.NewInMemoryEventPublisher(),
+		validation.NewUserValidator(),
+		append([]services.UserServiceOption{
+			services.WithPasswordHasher(passwords.NewBcryptHasher(bcrypt.MinCost)),
+		}, opts...)...,
+	)
+
+	server := grpc.NewServer(grpcadapter.ServerOptions(users, monitoring.NewMetrics())...)
+	grpcadapter.NewServer(users).Register(server)
+
+	listener := bufconn.Listen(1 << 20)
+
+	go func() { _ = server.Serve(listener) }()
+
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient(
+		"passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return usersv1.NewUserServiceClient(conn), users
+}
+
+// authenticateGRPC signs in over client and returns ctx carrying the session token.
+func authenticateGRPC(
+	ctx context.Context,
+	t *testing.T,
+	client usersv1.UserServiceClient,
+	email, password string,
+) context.Context {
+	t.Helper()
+
+	auth, err := client.Authenticate(ctx, &usersv1.AuthenticateRequest{
+		Email:     email,
+		Password:  password,
+		IpAddress: "127.0.0.1",
+	})
+	require.NoError(t, err)
+
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+auth.GetSession().GetToken())
+}
+
+func TestGRPCUserService(t *testing.T) {
+	ctx := context.Background()
+	client, users := newGRPCClient(t)
+
+	admin, err := users.CreateUser(ctx, &services.CreateUserRequest{
+		Email:     "admin@example.com",
+		Username:  "grpcadmin",
+		Password:  "Correct-Horse-42",
+		FirstName: "Ada",
+		LastName:  "Lovelace",
+		Status:    testStatusActive,
+		Role:      "admin",
+	})
+	require.NoError(t, err)
+
+	_, err = client.CreateUser(ctx, &usersv1.CreateUserRequest{Email: "not-an-email"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = client.ListUsers(ctx, &usersv1.ListUsersRequest{Status: testStatusActive})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	adminCtx := authenticateGRPC(ctx, t, client, "admin@example.com", "Correct-Horse-42")
+
+	created, err := client.CreateUser(adminCtx, &usersv1.CreateUserRequest{
+		Email:     "grpc@example.com",
+		Username:  "grpcuser",
+		Password:  "Correct-Horse-42",
+		FirstName: "Grace",
+		LastName:  "Hopper",
+		Status:    testStatusActive,
+		Role:      testRoleUser,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "grpcuser", created.GetUser().GetUsername())
+	assert.Equal(t, testStatusActive, created.GetUser().GetStatus())
+
+	_, err = client.Authenticate(ctx, &usersv1.AuthenticateRequest{
+		Email:    "grpc@example.com",
+		Password: "wrong",
+	})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	listed, err := client.ListUsers(adminCtx, &usersv1.ListUsersRequest{Status: testStatusActive})
+	require.NoError(t, err)
+	require.Len(t, listed.GetUsers(), 2)
+	assert.ElementsMatch(t,
+		[]int64{admin.ID().Int64(), created.GetUser().GetId()},
+		[]int64{listed.GetUsers()[0].GetId(), listed.GetUsers()[1].GetId()})
+
+	_, err = client.GetStats(adminCtx, &usersv1.GetStatsRequest{})
+	require.NoError(t, err)
+
+	userCtx := authenticateGRPC(ctx, t, client, "grpc@example.com", "Correct-Horse-42")
+
+	_, err = client.ListUsers(userCtx, &usersv1.ListUsersRequest{Status: testStatusActive})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = client.GetStats(userCtx, &usersv1.GetStatsRequest{})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+}
+
+func TestGRPCSignupIgnoresRoleAndStatus(t *testing.T) {
+	ctx := context.Background()
+	client, _ := newGRPCClient(t)
+
+	created, err := client.CreateUser(ctx, &usersv1.CreateUserRequest{
+		Email:     "mallory@example.com",
+		Username:  "mallory",
+		Password:  "Correct-Horse-42",
+		FirstName: "Mallory",
+		LastName:  "Martin",
+		Status:    testStatusActive,
+		Role:      "admin",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, testRoleUser, created.GetUser().GetRole())
+	assert.Equal(t, "pending", created.GetUser().GetStatus())
+
+	_, err = client.Authenticate(ctx, &usersv1.AuthenticateRequest{
+		Email:    "mallory@example.com",
+		Password: "Correct-Horse-42",
+	})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err), "pending users cannot sign in")
+
+	_, err = client.CreateUser(
+		metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer forged"),
+		&usersv1.CreateUserRequest{Email: "eve@example.com", Username: "eve", Password: "Correct-Horse-42"},
+	)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestGRPCAuthenticateThrottlesByPeerAddress(t *testing.T) {
+	ctx := context.Background()
+	limiter := ratelimit.NewMemory()
+	client, _ := newGRPCClient(t, services.WithLoginThrottle(ratelimit.NewLoginThrottle(
+		ratelimit.Rule{Limiter: limiter, Limit: ratelimit.Limit{Burst: 2, Every: time.Minute}},
+		ratelimit.Rule{Limiter: limiter, Limit: ratelimit.Limit{Burst: 10, Every: time.Minute}},
+	)))
+
+	// Forged addresses do not give every attempt a bucket of its own.
+	for i, address := range []string{"192.0.2.1", "192.0.2.2", "", "192.0.2.3"} {
+		_, err := client.Authenticate(ctx, &usersv1.AuthenticateRequest{
+			Email:     fmt.Sprintf("user%d@example.com", i),
+			Password:  "wrong",
+			IpAddress: address,
+		})
+
+		want := codes.Unauthenticated
+		if i >= 2 {
+			want = codes.ResourceExhausted
+		}
+
+		assert.Equal(t, want, status.Code(err), "attempt %d from %q", i, address)
+	}
+}
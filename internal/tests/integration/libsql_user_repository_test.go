@@ -0,0 +1,34 @@
+//go:build sqlite && libsql
+
+package integration
+
+import (
+	"testing"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/libsql"
+	"github.com/LarsArtmann/template-sqlc/internal/tests/containers"
+	"github.com/LarsArtmann/template-sqlc/internal/tests/repositorytest"
+)
+
+func TestLibSQLUserRepositoryConformance(t *testing.T) {
+	repositorytest.RunUserRepositoryTests(t, func(t *testing.T) repositorytest.Repositories {
+		db := containers.LibSQL(t)
+
+		return repositorytest.Repositories{
+			Users:            libsql.NewUserRepository(db),
+			Sessions:         libsql.NewSessionRepository(db),
+			Jobs:             libsql.NewJobRepository(db),
+			Idempotency:      libsql.NewIdempotencyRepository(db),
+			Notifications:    libsql.NewNotificationPreferenceRepository(db),
+			IdentityChanges:  libsql.NewIdentityChangeRepository(db),
+			Moderation:       libsql.NewModerationRepository(db),
+			AccountDeletions: libsql.NewAccountDeletionRepository(db),
+			Preferences:      libsql.NewUserPreferenceRepository(db),
+			Activity:         libsql.NewActivityRepository(db),
+			LoginHistory:     libsql.NewLoginHistoryRepository(db),
+			UserEvents:       libsql.NewUserEventStore(db),
+			UserReadModel:    libsql.NewUserReadModelRepository(db),
+			FeatureFlags:     libsql.NewFeatureFlagRepository(db),
+		}
+	})
+}
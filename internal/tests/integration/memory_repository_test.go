@@ -0,0 +1,44 @@
+package integration
+
+import (
+	"testing"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/eventsourced"
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/memory"
+	"github.com/LarsArtmann/template-sqlc/internal/tests/repositorytest"
+)
+
+func TestMemoryRepositoryConformance(t *testing.T) {
+	repositorytest.RunUserRepositoryTests(t, func(*testing.T) repositorytest.Repositories {
+		users := memory.NewUserRepository()
+
+		return repositorytest.Repositories{
+			Users:            users,
+			Sessions:         memory.NewSessionRepository(),
+			Jobs:             memory.NewJobRepository(),
+			Idempotency:      memory.NewIdempotencyRepository(),
+			Notifications:    memory.NewNotificationPreferenceRepository(),
+			IdentityChanges:  memory.NewIdentityChangeRepository(),
+			Moderation:       memory.NewModerationRepository(),
+			AccountDeletions: memory.NewAccountDeletionRepository(),
+			Preferences:      memory.NewUserPreferenceRepository(),
+			Activity:         memory.NewActivityRepository(),
+			LoginHistory:     memory.NewLoginHistoryRepository(),
+			UserEvents:       memory.NewUserEventStore(),
+			UserReadModel:    memory.NewUserReadModelRepository(users),
+			FeatureFlags:     memory.NewFeatureFlagRepository(),
+		}
+	})
+}
+
+// Snapshots every other event, so users are loaded from snapshots as well as
+// from whole streams.
+func TestEventSourcedRepositoryConformance(t *testing.T) {
+	repositorytest.RunUserRepositoryTests(t, func(*testing.T) repositorytest.Repositories {
+		store := memory.NewUserEventStore()
+
+		return repositorytest.Repositories{
+			Users: eventsourced.NewUserRepository(memory.NewUserRepository(), store, eventsourced.WithSnapshotEvery(2)),
+		}
+	})
+}
@@ -3,6 +3,10 @@ package integration
 
 import (
 	"context"
+	"maps"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
 	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
@@ -11,9 +15,9 @@ import (
 // NewMockUserRepository creates a new MockUserRepository for testing.
 func NewMockUserRepository() *MockUserRepository {
 	return &MockUserRepository{
-		users:                 make(map[entities.UserID]*entities.User),
-		passwordVerifications: make(map[string]string),
-		idCounter:             1,
+		users:     make(map[entities.UserID]*entities.User),
+		deleted:   make(map[entities.UserID]deletedUser),
+		idCounter: 1,
 	}
 }
 
@@ -107,34 +111,19 @@ func (MockUserRepositoryStub) Update(context.Context, *entities.User) error {
 	return nil
 }
 
-// Search stub implementation.
-func (MockUserRepositoryStub) Search(
-	context.Context,
-	string,
-	entities.UserStatus,
-	int,
-) ([]*entities.User, error) {
-	return []*entities.User{}, nil
-}
-
-// SearchByTags stub implementation.
-func (MockUserRepositoryStub) SearchByTags(
-	context.Context,
-	[]string,
-	entities.UserStatus,
-	int,
-	int,
-) ([]*entities.User, error) {
-	return []*entities.User{}, nil
-}
-
 // MockUserRepository implements UserRepository for testing.
 type MockUserRepository struct {
 	MockUserRepositoryStub
 
-	users                 map[entities.UserID]*entities.User
-	passwordVerifications map[string]string
-	idCounter             entities.UserID
+	users     map[entities.UserID]*entities.User
+	deleted   map[entities.UserID]deletedUser
+	idCounter entities.UserID
+}
+
+// deletedUser is a soft-deleted user and when it was deleted.
+type deletedUser struct {
+	user      *entities.User
+	deletedAt time.Time
 }
 
 // Create stores a new user in the mock repository.
@@ -161,9 +150,74 @@ func (m *MockUserRepository) GetByID(
 	return user, nil
 }
 
-// SetPasswordVerification sets the expected password for an email in the mock repository.
-func (m *MockUserRepository) SetPasswordVerification(email, password string) {
-	m.passwordVerifications[email] = password
+// GetByIDForUpdate retrieves a user by ID; the mock does not lock.
+func (m *MockUserRepository) GetByIDForUpdate(
+	ctx context.Context,
+	id entities.UserID,
+	_ ...repositories.LockOption,
+) (*entities.User, error) {
+	return m.GetByID(ctx, id)
+}
+
+// ClaimNext returns up to limit users with the given status, ordered by ID.
+func (m *MockUserRepository) ClaimNext(
+	_ context.Context,
+	status entities.UserStatus,
+	limit int,
+) ([]*entities.User, error) {
+	claimed := make([]*entities.User, 0, limit)
+
+	for _, id := range slices.Sorted(maps.Keys(m.users)) {
+		if len(claimed) == limit {
+			break
+		}
+
+		if m.users[id].Status() == status {
+			claimed = append(claimed, m.users[id])
+		}
+	}
+
+	return claimed, nil
+}
+
+// UpdatePassword replaces the password hash of a user in the mock repository.
+func (m *MockUserRepository) UpdatePassword(
+	_ context.Context,
+	id entities.UserID,
+	password entities.PasswordHash,
+) error {
+	user, ok := m.users[id]
+	if !ok {
+		return entities.ErrUserNotFound
+	}
+
+	record := user.Record()
+	record.Password = password
+
+	updated, err := entities.ReconstructUser(record)
+	if err != nil {
+		return err
+	}
+
+	m.users[id] = updated
+
+	return nil
+}
+
+// GetByIDs retrieves the users with the given IDs from the mock repository.
+func (m *MockUserRepository) GetByIDs(
+	_ context.Context,
+	ids []entities.UserID,
+) ([]*entities.User, error) {
+	users := make([]*entities.User, 0, len(ids))
+
+	for _, id := range ids {
+		if user, ok := m.users[id]; ok {
+			users = append(users, user)
+		}
+	}
+
+	return users, nil
 }
 
 // GetByUUID retrieves a user by their UUID from the mock repository.
@@ -196,34 +250,219 @@ func (m *MockUserRepository) GetByUsername(
 	username entities.Username,
 ) (*entities.User, error) {
 	return findUserBy(m.users, func(u *entities.User) bool {
-		return u.Username() == username
+		return u.Username().Canonical() == username.Canonical()
 	})
 }
 
 // Delete removes a user from the mock repository.
 func (m *MockUserRepository) Delete(_ context.Context, id entities.UserID) error {
+	m.softDelete(id)
+
+	return nil
+}
+
+// softDelete moves a stored user to the deleted users.
+func (m *MockUserRepository) softDelete(id entities.UserID) bool {
+	user, ok := m.users[id]
+	if !ok {
+		return false
+	}
+
 	delete(m.users, id)
+	m.deleted[id] = deletedUser{user: user, deletedAt: time.Now()}
+
+	return true
+}
+
+// Restore moves a deleted user back to the stored users.
+func (m *MockUserRepository) Restore(_ context.Context, id entities.UserID) error {
+	deleted, ok := m.deleted[id]
+	if !ok {
+		return entities.ErrUserNotFound
+	}
+
+	delete(m.deleted, id)
+	m.users[id] = deleted.user
 
 	return nil
 }
 
-// List retrieves all users with a specific status from the mock repository.
-func (m *MockUserRepository) List(
+// PurgeDeletedOlderThan drops the users deleted before cutoff.
+func (m *MockUserRepository) PurgeDeletedOlderThan(_ context.Context, cutoff time.Time) (int64, error) {
+	var purged int64
+
+	for id, deleted := range m.deleted {
+		if deleted.deletedAt.Before(cutoff) {
+			delete(m.deleted, id)
+
+			purged++
+		}
+	}
+
+	return purged, nil
+}
+
+// CreateBatch stores new users in the mock repository.
+func (m *MockUserRepository) CreateBatch(ctx context.Context, users []*entities.User) error {
+	for _, user := range users {
+		err := m.Create(ctx, user)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// UpdateStatusBatch changes the status of the stored users with the given IDs.
+func (m *MockUserRepository) UpdateStatusBatch(
 	_ context.Context,
+	ids []entities.UserID,
 	status entities.UserStatus,
-	_, _ int,
+) (int64, error) {
+	var updated int64
+
+	for _, id := range ids {
+		user, ok := m.users[id]
+		if !ok {
+			continue
+		}
+
+		err := user.ChangeStatus(status)
+		if err != nil {
+			return updated, err
+		}
+
+		updated++
+	}
+
+	return updated, nil
+}
+
+// DeleteBatch removes the users with the given IDs from the mock repository.
+func (m *MockUserRepository) DeleteBatch(_ context.Context, ids []entities.UserID) (int64, error) {
+	var deleted int64
+
+	for _, id := range ids {
+		if m.softDelete(id) {
+			deleted++
+		}
+	}
+
+	return deleted, nil
+}
+
+// List retrieves a page of the users matching filter, newest first.
+func (m *MockUserRepository) List(
+	_ context.Context,
+	filter entities.UserFilter,
+	limit, offset int,
 ) ([]*entities.User, error) {
-	result := make([]*entities.User, 0)
+	matched := m.filtered(filter)
+	if offset >= len(matched) {
+		return []*entities.User{}, nil
+	}
+
+	return matched[offset:min(offset+limit, len(matched))], nil
+}
+
+// ListPage pages through the users matching filter, newest first.
+func (m *MockUserRepository) ListPage(
+	_ context.Context,
+	filter entities.UserFilter,
+	cursor string,
+	limit int,
+) (*entities.UserPage, error) {
+	after, err := entities.DecodeUserCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	bound := after.Bound()
+	page := make([]*entities.User, 0, limit+1)
+
+	for _, user := range m.filtered(filter) {
+		if user.CreatedAt().After(bound.CreatedAt) ||
+			(user.CreatedAt().Equal(bound.CreatedAt) && user.ID() >= bound.ID) {
+			continue
+		}
+
+		page = append(page, user)
+		if len(page) > limit {
+			break
+		}
+	}
+
+	return entities.NewUserPage(page, limit), nil
+}
+
+// filtered returns the users matching filter, newest first.
+func (m *MockUserRepository) filtered(filter entities.UserFilter) []*entities.User {
+	users := slices.SortedFunc(maps.Values(m.users), func(a, b *entities.User) int {
+		if c := b.CreatedAt().Compare(a.CreatedAt()); c != 0 {
+			return c
+		}
+
+		return int(b.ID() - a.ID())
+	})
+
+	return slices.DeleteFunc(users, func(user *entities.User) bool { return !filter.Matches(user) })
+}
+
+// SearchWithFacets searches users by name, email, or username and counts facets.
+func (m *MockUserRepository) SearchWithFacets(
+	_ context.Context,
+	query string,
+	status entities.UserStatus,
+	limit int,
+) (*entities.UserSearchResult, error) {
+	result := &entities.UserSearchResult{
+		Users:  make([]*entities.User, 0),
+		Facets: entities.NewSearchFacets(),
+	}
+	tagCounts := make(map[string]int64)
+	needle := strings.ToLower(query)
 
 	for _, user := range m.users {
-		if user.Status() == status {
-			result = append(result, user)
+		if !mockUserMatches(user, needle) {
+			continue
+		}
+
+		result.Facets.ByStatus[user.Status()]++
+		result.Facets.ByRole[user.Role()]++
+
+		for _, tag := range user.Tags() {
+			tagCounts[tag]++
+		}
+
+		if user.Status() == status && len(result.Users) < limit {
+			result.Users = append(result.Users, user)
 		}
 	}
 
+	for tag, count := range tagCounts {
+		result.Facets.TopTags = append(result.Facets.TopTags, entities.TagCount{Tag: tag, Count: count})
+	}
+
 	return result, nil
 }
 
+// mockUserMatches reports whether a user's searchable fields contain the needle.
+func mockUserMatches(user *entities.User, needle string) bool {
+	for _, field := range []string{
+		user.Email().String(),
+		user.Username().String(),
+		user.FirstName().String(),
+		user.LastName().String(),
+	} {
+		if strings.Contains(strings.ToLower(field), needle) {
+			return true
+		}
+	}
+
+	return false
+}
+
 // CountByStatus counts users by their status in the mock repository.
 func (m *MockUserRepository) CountByStatus(
 	_ context.Context,
@@ -249,53 +488,16 @@ func (m *MockUserRepository) GetStats(_ context.Context) (*entities.UserStats, e
 	return stats, nil
 }
 
-// VerifyCredentials verifies user credentials against the mock repository.
-func (m *MockUserRepository) VerifyCredentials(
+// GetCredentials returns the user of email with its password hash.
+func (m *MockUserRepository) GetCredentials(
 	ctx context.Context,
 	email entities.Email,
-	password entities.PasswordHash,
 ) (*entities.User, error) {
-	expectedPassword := m.passwordVerifications[email.String()]
-	if expectedPassword != password.String() {
-		return nil, entities.ErrInvalidCredentials
-	}
-
-	user, err := m.GetByEmail(ctx, email)
-	if err != nil {
-		return nil, err
-	}
-
-	return user, nil
-}
-
-// MockSessionRepositoryStub provides default stub implementations for SessionRepository methods.
-// Embed this in mock implementations to avoid duplicating stub code.
-type MockSessionRepositoryStub struct{}
-
-// Update stub implementation.
-func (MockSessionRepositoryStub) Update(context.Context, *entities.UserSession) error {
-	return nil
-}
-
-// DeactivateByToken stub implementation.
-func (MockSessionRepositoryStub) DeactivateByToken(context.Context, entities.SessionToken) error {
-	return nil
-}
-
-// DeactivateByUserID stub implementation.
-func (MockSessionRepositoryStub) DeactivateByUserID(context.Context, entities.UserID) error {
-	return nil
-}
-
-// CleanupExpired stub implementation.
-func (MockSessionRepositoryStub) CleanupExpired(context.Context) (int64, error) {
-	return 0, nil
+	return m.GetByEmail(ctx, email)
 }
 
 // MockSessionRepository implements SessionRepository for testing.
 type MockSessionRepository struct {
-	MockSessionRepositoryStub
-
 	sessions  map[entities.SessionID]*entities.UserSession
 	idCounter entities.SessionID
 }
@@ -305,6 +507,7 @@ func (m *MockSessionRepository) Create(_ context.Context, session *entities.User
 	sessionID := m.idCounter
 	m.idCounter++
 
+	session.SetID(sessionID)
 	m.sessions[sessionID] = session
 
 	return nil
@@ -320,6 +523,16 @@ func (m *MockSessionRepository) GetByToken(
 	})
 }
 
+// GetByRefreshToken retrieves a session by current or rotated refresh token from the mock repository.
+func (m *MockSessionRepository) GetByRefreshToken(
+	_ context.Context,
+	token entities.RefreshToken,
+) (*entities.UserSession, error) {
+	return findSessionBy(m.sessions, func(s *entities.UserSession) bool {
+		return s.HasRefreshToken(token)
+	})
+}
+
 // GetByUserID retrieves all sessions for a user from the mock repository.
 func (m *MockSessionRepository) GetByUserID(
 	_ context.Context,
@@ -346,6 +559,73 @@ func (m *MockSessionRepository) Delete(_ context.Context, id entities.SessionID)
 	return nil
 }
 
+// Update replaces a stored session in the mock repository.
+func (m *MockSessionRepository) Update(_ context.Context, session *entities.UserSession) error {
+	if _, ok := m.sessions[session.ID()]; !ok {
+		return entities.ErrSessionNotFound
+	}
+
+	m.sessions[session.ID()] = session
+
+	return nil
+}
+
+// UpdateIfCurrent replaces a stored session in the mock repository, provided
+// it is still active with the expected refresh token. The mock hands out the
+// stored sessions themselves, so a session updated in place is current.
+func (m *MockSessionRepository) UpdateIfCurrent(
+	_ context.Context,
+	session *entities.UserSession,
+	expected entities.RefreshToken,
+) error {
+	stored, ok := m.sessions[session.ID()]
+	if !ok || (stored != session && (!stored.IsActive() || stored.RefreshToken() != expected)) {
+		return entities.ErrSessionConflict
+	}
+
+	m.sessions[session.ID()] = session
+
+	return nil
+}
+
+// DeactivateByToken deactivates the session with the given token in the mock repository.
+func (m *MockSessionRepository) DeactivateByToken(ctx context.Context, token entities.SessionToken) error {
+	session, err := m.GetByToken(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	session.Deactivate()
+
+	return nil
+}
+
+// DeactivateByUserID deactivates every session of a user in the mock repository.
+func (m *MockSessionRepository) DeactivateByUserID(_ context.Context, userID entities.UserID) error {
+	for _, session := range m.sessions {
+		if session.UserID() == userID {
+			session.Deactivate()
+		}
+	}
+
+	return nil
+}
+
+// CleanupExpired removes the expired sessions from the mock repository.
+func (m *MockSessionRepository) CleanupExpired(_ context.Context) (int64, error) {
+	var removed int64
+
+	for id, session := range m.sessions {
+		if session.IsExpired() {
+			delete(m.sessions, id)
+
+			removed++
+		}
+	}
+
+	return removed, nil
+}
+
 // GetActiveSessions counts active sessions for a user in the mock repository.
 func (m *MockSessionRepository) GetActiveSessions(
 	_ context.Context,
@@ -0,0 +1,14 @@
+package integration
+
+import (
+	"testing"
+
+	"github.com/LarsArtmann/template-sqlc/internal/tests/repositorytest"
+)
+
+// The mock user repository stubs its writes, so only the session contract runs.
+func TestMockSessionRepositoryConformance(t *testing.T) {
+	repositorytest.RunUserRepositoryTests(t, func(*testing.T) repositorytest.Repositories {
+		return repositorytest.Repositories{Sessions: NewMockSessionRepository()}
+	})
+}
@@ -0,0 +1,16 @@
+//go:build mysql
+
+package integration
+
+import (
+	"testing"
+
+	"github.com/LarsArtmann/template-sqlc/internal/tests/containers"
+	"github.com/LarsArtmann/template-sqlc/pkg/dblock"
+)
+
+func TestMySQLLocker(t *testing.T) {
+	db := containers.MySQL(t)
+
+	testLocker(t, dblock.NewMySQL(db), dblock.NewMySQL(db))
+}
@@ -0,0 +1,114 @@
+//go:build mysql
+
+package integration
+
+import (
+	"context"
+	"testing"
+
+	mysqladapter "github.com/LarsArtmann/template-sqlc/internal/adapters/mysql"
+	mysqldb "github.com/LarsArtmann/template-sqlc/internal/db/mysql"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+	"github.com/LarsArtmann/template-sqlc/internal/tests/containers"
+	"github.com/LarsArtmann/template-sqlc/internal/tests/repositorytest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+func newMySQLUser(t *testing.T, name string, tags ...string) *entities.User {
+	t.Helper()
+
+	user, err := entities.NewUser(
+		entities.Email(name+"@example.com"),
+		entities.Username(name),
+		entities.PasswordHash("hash-"+name),
+		"First",
+		"Last",
+		entities.UserStatusActive,
+		entities.UserRoleUser,
+		nil,
+		tags,
+	)
+	require.NoError(t, err)
+
+	return user
+}
+
+func TestMySQLUserRepository(t *testing.T) {
+	ctx := context.Background()
+	db := containers.MySQL(t)
+	repo := mysqladapter.NewUserRepository(db)
+
+	grace := newMySQLUser(t, "grace", "compilers", "navy")
+	require.NoError(t, repo.Create(ctx, grace))
+	require.NoError(t, repo.Create(ctx, newMySQLUser(t, "alan", "compilers")))
+
+	loaded, err := repo.GetByUUID(ctx, entities.NewUuIDFromUUID(grace.UUID()))
+	require.NoError(t, err)
+	assert.Equal(t, grace.UUID(), loaded.UUID())
+	assert.Equal(t, []string{"compilers", "navy"}, loaded.Tags())
+
+	found, err := repo.List(ctx, entities.UserFilter{Query: "grace"}, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, grace.ID(), found[0].ID())
+
+	tagged, err := repo.List(ctx, entities.UserFilter{TagsAny: []string{"navy", "unknown"}}, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, tagged, 1)
+	assert.Equal(t, grace.ID(), tagged[0].ID())
+
+	err = repo.Create(ctx, newMySQLUser(t, "grace"))
+	require.ErrorIs(t, err, entities.ErrUserAlreadyExists)
+
+	_, err = db.ExecContext(ctx, `CREATE TABLE user_notes (
+		id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+		user_id BIGINT UNSIGNED NOT NULL,
+		FOREIGN KEY (user_id) REFERENCES users(id)
+	)`)
+	require.NoError(t, err)
+
+	_, err = db.ExecContext(ctx, "INSERT INTO user_notes (user_id) VALUES (?)", 999999)
+	err = mysqldb.HandleDBError(
+		err,
+		"create note",
+		entities.ErrUserNotFound,
+		entities.ErrUserAlreadyExists,
+		entities.ErrInvalidReference,
+	)
+	require.ErrorIs(t, err, entities.ErrInvalidReference)
+}
+
+func TestUserServiceIntegrationSuiteMySQL(t *testing.T) {
+	suite.Run(t, &UserServiceIntegrationTestSuite{
+		newUserRepository: func(t *testing.T) repositories.UserRepository {
+			return mysqladapter.NewUserRepository(containers.MySQL(t))
+		},
+	})
+}
+
+func TestMySQLUserRepositoryConformance(t *testing.T) {
+	repositorytest.RunUserRepositoryTests(t, func(t *testing.T) repositorytest.Repositories {
+		db := containers.MySQL(t)
+
+		return repositorytest.Repositories{
+			Users:            mysqladapter.NewUserRepository(db),
+			Sessions:         mysqladapter.NewSessionRepository(db),
+			Jobs:             mysqladapter.NewJobRepository(db),
+			Idempotency:      mysqladapter.NewIdempotencyRepository(db),
+			Notifications:    mysqladapter.NewNotificationPreferenceRepository(db),
+			IdentityChanges:  mysqladapter.NewIdentityChangeRepository(db),
+			Moderation:       mysqladapter.NewModerationRepository(db),
+			AccountDeletions: mysqladapter.NewAccountDeletionRepository(db),
+			Preferences:      mysqladapter.NewUserPreferenceRepository(db),
+			Activity:         mysqladapter.NewActivityRepository(db),
+			LoginHistory:     mysqladapter.NewLoginHistoryRepository(db),
+			UserEvents:       mysqladapter.NewUserEventStore(db),
+			UserReadModel:    mysqladapter.NewUserReadModelRepository(db),
+			FeatureFlags:     mysqladapter.NewFeatureFlagRepository(db),
+			Transactions:     mysqladapter.NewTransactionalRepository(db),
+		}
+	})
+}
@@ -0,0 +1,153 @@
+//go:build sqlite
+
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/oauth"
+	sqliteadapter "github.com/LarsArtmann/template-sqlc/internal/adapters/sqlite"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/services"
+	"github.com/LarsArtmann/template-sqlc/internal/tests/containers"
+	"github.com/LarsArtmann/template-sqlc/internal/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeGitHubAccount is what the fake GitHub server returns for an authorization code.
+type fakeGitHubAccount struct {
+	ID       int64
+	Login    string
+	Name     string
+	Email    string
+	Verified bool
+}
+
+// newFakeGitHub serves the OAuth token endpoint and user API of GitHub Enterprise.
+// The access token is the authorization code, which selects the account.
+func newFakeGitHub(t *testing.T, accounts map[string]fakeGitHubAccount) *httptest.Server {
+	t.Helper()
+
+	account := func(r *http.Request) (fakeGitHubAccount, bool) {
+		acct, ok := accounts[strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")]
+
+		return acct, ok
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /login/oauth/access_token", func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, r.ParseForm())
+		assert.NotEmpty(t, r.PostForm.Get("code_verifier"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"access_token": r.PostForm.Get("code"),
+			"token_type":   "bearer",
+		})
+	})
+	mux.HandleFunc("GET /api/v3/user", func(w http.ResponseWriter, r *http.Request) {
+		acct, ok := account(r)
+		if !ok {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]any{"id": acct.ID, "login": acct.Login, "name": acct.Name})
+	})
+	mux.HandleFunc("GET /api/v3/user/emails", func(w http.ResponseWriter, r *http.Request) {
+		acct, _ := account(r)
+		_ = json.NewEncoder(w).Encode([]map[string]any{
+			{"email": "noreply@example.com", "primary": false, "verified": true},
+			{"email": acct.Email, "primary": true, "verified": acct.Verified},
+		})
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestSQLiteFederatedLoginWithGitHub(t *testing.T) {
+	ctx := context.Background()
+	db := containers.SQLite(t)
+	repo := sqliteadapter.NewUserRepository(db)
+	publisher := events.NewInMemoryEventPublisher()
+	users := services.NewUserService(
+		repo,
+		NewMockSessionRepository(),
+		publisher,
+		validation.NewUserValidator(),
+	)
+	identities := services.NewIdentityService(sqliteadapter.NewIdentityRepository(db), users)
+
+	existing := createSQLiteUser(t, repo, "taken@example.com", "taken", "Taken")
+
+	server := newFakeGitHub(t, map[string]fakeGitHubAccount{
+		"octo":     {ID: 583231, Login: "octocat", Name: "Mona Lisa", Email: "mona@example.com", Verified: true},
+		"squatter": {ID: 99, Login: "squatter", Email: "taken@example.com"},
+	})
+	auth := oauth.NewAuthenticator(
+		identities,
+		oauth.NewGitHubProvider(oauth.Config{ClientID: "client"}, oauth.WithGitHubEnterprise(server.URL)),
+	)
+
+	redirect, req, err := auth.Begin("github")
+	require.NoError(t, err)
+
+	location, err := url.Parse(redirect)
+	require.NoError(t, err)
+	assert.Equal(t, req.State, location.Query().Get("state"))
+	assert.Equal(t, "S256", location.Query().Get("code_challenge_method"))
+
+	_, err = auth.Complete(ctx, "github", "octo", "forged", req, "203.0.113.9", "test")
+	require.ErrorIs(t, err, oauth.ErrStateMismatch)
+
+	first, err := auth.Complete(ctx, "github", "octo", req.State, req, "203.0.113.9", "test")
+	require.NoError(t, err)
+	assert.True(t, first.Created)
+	assert.True(t, first.Linked)
+	assert.NotNil(t, first.Session)
+	assert.Equal(t, "octocat", first.User.Username().String())
+	assert.Equal(t, "Mona", first.User.FirstName().String())
+	assert.True(t, first.User.IsVerified())
+	assert.Equal(t, "583231", first.Identity.Subject)
+
+	again, err := auth.Complete(ctx, "github", "octo", req.State, req, "203.0.113.9", "test")
+	require.NoError(t, err)
+	assert.False(t, again.Created)
+	assert.False(t, again.Linked)
+	assert.Equal(t, first.User.ID(), again.User.ID())
+	require.NotNil(t, again.Identity.LastLoginAt)
+
+	var login *events.FederatedLoginEvent
+
+	for _, event := range publisher.Events() {
+		if data, ok := event.Data.(events.FederatedLoginEvent); ok {
+			login = &data
+		}
+	}
+
+	require.NotNil(t, login)
+	assert.Equal(t, "github", login.Provider)
+	assert.False(t, login.FirstLogin)
+
+	err = identities.Unlink(ctx, first.User.ID(), "github")
+	require.ErrorIs(t, err, entities.ErrLastLoginMethod)
+
+	_, err = auth.Complete(ctx, "github", "squatter", req.State, req, "203.0.113.9", "test")
+	require.ErrorIs(t, err, entities.ErrUserAlreadyExists, "unverified emails must not take over accounts")
+
+	linked, err := identities.ListIdentities(ctx, existing.ID())
+	require.NoError(t, err)
+	assert.Empty(t, linked)
+}
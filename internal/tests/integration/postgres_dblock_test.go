@@ -0,0 +1,16 @@
+//go:build postgres
+
+package integration
+
+import (
+	"testing"
+
+	"github.com/LarsArtmann/template-sqlc/internal/tests/containers"
+	"github.com/LarsArtmann/template-sqlc/pkg/dblock"
+)
+
+func TestPostgresLocker(t *testing.T) {
+	pool := containers.Postgres(t)
+
+	testLocker(t, dblock.NewPostgres(pool), dblock.NewPostgres(pool))
+}
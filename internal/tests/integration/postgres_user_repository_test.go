@@ -0,0 +1,95 @@
+//go:build postgres
+
+package integration
+
+import (
+	"context"
+	"testing"
+
+	postgresadapter "github.com/LarsArtmann/template-sqlc/internal/adapters/postgres"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+	"github.com/LarsArtmann/template-sqlc/internal/tests/containers"
+	"github.com/LarsArtmann/template-sqlc/internal/tests/repositorytest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestPostgresUserRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := postgresadapter.NewUserRepository(containers.Postgres(t))
+
+	for _, name := range []string{"grace", "alan", "barbara"} {
+		user, err := entities.NewUser(
+			entities.Email(name+"@example.com"),
+			entities.Username(name),
+			entities.PasswordHash("hash-"+name),
+			"First",
+			"Last",
+			entities.UserStatusActive,
+			entities.UserRoleUser,
+			entities.UserMetadata{"name": name},
+			[]string{"team-" + name, "compilers"},
+		)
+		require.NoError(t, err)
+		require.NoError(t, repo.Create(ctx, user))
+	}
+
+	found, err := repo.List(ctx, entities.UserFilter{Query: "gra"}, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, "grace", found[0].Metadata()["name"])
+
+	tagged, err := repo.List(ctx, entities.UserFilter{TagsAny: []string{"compilers"}}, 10, 0)
+	require.NoError(t, err)
+	assert.Len(t, tagged, 3)
+
+	result, err := repo.SearchWithFacets(ctx, "example.com", entities.UserStatusActive, 2)
+	require.NoError(t, err)
+	assert.Len(t, result.Users, 2)
+	assert.Equal(t, int64(3), result.Facets.ByStatus[entities.UserStatusActive])
+
+	require.NoError(t, repo.Suspend(ctx, found[0].ID()))
+
+	credentials, err := repo.GetCredentials(ctx, "grace@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, entities.PasswordHash("hash-grace"), credentials.PasswordHash())
+
+	require.NoError(t, repo.Delete(ctx, found[0].ID()))
+
+	_, err = repo.GetByID(ctx, found[0].ID())
+	require.ErrorIs(t, err, entities.ErrUserNotFound)
+}
+
+func TestUserServiceIntegrationSuitePostgres(t *testing.T) {
+	suite.Run(t, &UserServiceIntegrationTestSuite{
+		newUserRepository: func(t *testing.T) repositories.UserRepository {
+			return postgresadapter.NewUserRepository(containers.Postgres(t))
+		},
+	})
+}
+
+func TestPostgresUserRepositoryConformance(t *testing.T) {
+	repositorytest.RunUserRepositoryTests(t, func(t *testing.T) repositorytest.Repositories {
+		db := containers.Postgres(t)
+
+		return repositorytest.Repositories{
+			Users:            postgresadapter.NewUserRepository(db),
+			Sessions:         postgresadapter.NewSessionRepository(db),
+			Jobs:             postgresadapter.NewJobRepository(db),
+			Idempotency:      postgresadapter.NewIdempotencyRepository(db),
+			Notifications:    postgresadapter.NewNotificationPreferenceRepository(db),
+			IdentityChanges:  postgresadapter.NewIdentityChangeRepository(db),
+			Moderation:       postgresadapter.NewModerationRepository(db),
+			AccountDeletions: postgresadapter.NewAccountDeletionRepository(db),
+			Preferences:      postgresadapter.NewUserPreferenceRepository(db),
+			Activity:         postgresadapter.NewActivityRepository(db),
+			LoginHistory:     postgresadapter.NewLoginHistoryRepository(db),
+			UserEvents:       postgresadapter.NewUserEventStore(db),
+			UserReadModel:    postgresadapter.NewUserReadModelRepository(db),
+			FeatureFlags:     postgresadapter.NewFeatureFlagRepository(db),
+			Transactions:     postgresadapter.NewTransactionalRepository(db),
+		}
+	})
+}
@@ -0,0 +1,138 @@
+//go:build sqlite
+
+package integration
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/grpc/usersv1"
+	sqliteadapter "github.com/LarsArtmann/template-sqlc/internal/adapters/sqlite"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/server"
+	"github.com/LarsArtmann/template-sqlc/internal/tests/containers"
+	"github.com/LarsArtmann/template-sqlc/pkg/config"
+	"github.com/LarsArtmann/template-sqlc/pkg/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// freeAddr returns a loopback address with a currently unused port.
+func freeAddr(t *testing.T) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	addr := listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	return addr
+}
+
+func get(t *testing.T, url string) (int, string) {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, url, nil)
+	require.NoError(t, err)
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	return resp.StatusCode, string(body)
+}
+
+func TestServerServesAndShutsDown(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "app.db")
+	containers.SQLiteFile(t, path)
+
+	cfg := config.Default()
+	cfg.Database = config.DatabaseConfig{Driver: db.DriverSQLite, SQLite: path}
+	cfg.Server.HTTPAddr = freeAddr(t)
+	cfg.Server.GRPCAddr = freeAddr(t)
+	cfg.Server.MetricsAddr = freeAddr(t)
+
+	app := server.New(cfg)
+	require.NoError(t, app.Start(ctx))
+
+	code, _ := get(t, "http://"+cfg.Server.MetricsAddr+"/readyz")
+	assert.Equal(t, http.StatusOK, code)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "http://"+cfg.Server.HTTPAddr+"/graphql",
+		strings.NewReader(`{"query":"{ stats { totalUsers } }"}`))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode, "GraphQL requires a session")
+
+	conn, err := grpc.NewClient(cfg.Server.GRPCAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+
+	defer func() { _ = conn.Close() }()
+
+	_, err = usersv1.NewUserServiceClient(conn).CreateUser(ctx, &usersv1.CreateUserRequest{
+		Email: "ada@example.com", Username: "ada", Password: "Sup3r-secret!", FirstName: "Ada", LastName: "Lovelace",
+		Status: "active", Role: "user",
+	})
+	require.NoError(t, err)
+
+	code, metrics := get(t, "http://"+cfg.Server.MetricsAddr+"/metrics")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Contains(t, metrics, `route="/graphql"`)
+
+	require.NoError(t, app.Stop(ctx))
+
+	_, err = net.Dial("tcp", cfg.Server.HTTPAddr)
+	require.Error(t, err, "the HTTP server is shut down")
+}
+
+func TestServerAuditsUserChanges(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "app.db")
+	store := containers.SQLiteFile(t, path)
+
+	cfg := config.Default()
+	cfg.Database = config.DatabaseConfig{Driver: db.DriverSQLite, SQLite: path}
+	cfg.Server.HTTPAddr = freeAddr(t)
+	cfg.Server.GRPCAddr = freeAddr(t)
+	cfg.Server.MetricsAddr = freeAddr(t)
+
+	app := server.New(cfg)
+	require.NoError(t, app.Start(ctx))
+
+	defer func() { require.NoError(t, app.Stop(ctx)) }()
+
+	conn, err := grpc.NewClient(cfg.Server.GRPCAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+
+	defer func() { _ = conn.Close() }()
+
+	created, err := usersv1.NewUserServiceClient(conn).CreateUser(ctx, &usersv1.CreateUserRequest{
+		Email: "ada@example.com", Username: "ada", Password: "Sup3r-secret!", FirstName: "Ada", LastName: "Lovelace",
+		Status: "active", Role: "user",
+	})
+	require.NoError(t, err)
+
+	entries, err := sqliteadapter.NewAuditRepository(store).List(ctx, entities.AuditFilter{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, entities.AuditActionUserCreate, entries[0].Action)
+	assert.Equal(t, created.GetUser().GetId(), int64(entries[0].UserID))
+}
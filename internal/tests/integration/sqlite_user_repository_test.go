@@ -0,0 +1,812 @@
+//go:build sqlite
+
+package integration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/eventsourced"
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/mappers"
+	sqliteadapter "github.com/LarsArtmann/template-sqlc/internal/adapters/sqlite"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/services"
+	"github.com/LarsArtmann/template-sqlc/internal/tests/containers"
+	"github.com/LarsArtmann/template-sqlc/internal/tests/repositorytest"
+	"github.com/LarsArtmann/template-sqlc/internal/validation"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+func createSQLiteUser(
+	t *testing.T,
+	repo repositories.UserRepository,
+	email, username, firstName string,
+	tags ...string,
+) *entities.User {
+	t.Helper()
+
+	user, err := entities.NewUser(
+		entities.Email(email),
+		entities.Username(username),
+		entities.PasswordHash("$2a$10$hash-"+username),
+		entities.FirstName(firstName),
+		entities.LastName("Tester"),
+		entities.UserStatusActive,
+		entities.UserRoleUser,
+		entities.NewUserMetadata(),
+		tags,
+	)
+	require.NoError(t, err)
+
+	require.NoError(t, repo.Create(context.Background(), user))
+	require.NotZero(t, user.ID())
+
+	return user
+}
+
+func TestSQLiteUserRepositoryRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	repo := sqliteadapter.NewUserRepository(containers.SQLite(t))
+
+	created := createSQLiteUser(t, repo, "ada@example.com", "ada", "Ada", "admin", "beta")
+	created.Metadata()["theme"] = "dark"
+	require.NoError(t, repo.Update(ctx, created))
+
+	loaded, err := repo.GetByUUID(ctx, entities.NewUuIDFromUUID(created.UUID()))
+	require.NoError(t, err)
+	assert.Equal(t, created.ID(), loaded.ID())
+	assert.Equal(t, created.UUID(), loaded.UUID())
+	assert.Equal(t, created.Email(), loaded.Email())
+	assert.Equal(t, []string{"admin", "beta"}, loaded.Tags())
+	assert.Equal(t, "dark", loaded.Metadata()["theme"])
+	assert.False(t, loaded.CreatedAt().IsZero())
+
+	_, err = repo.GetByEmail(ctx, "missing@example.com")
+	require.ErrorIs(t, err, entities.ErrUserNotFound)
+
+	batch, err := repo.GetByIDs(ctx, []entities.UserID{created.ID(), entities.UserID(999)})
+	require.NoError(t, err)
+	require.Len(t, batch, 1)
+	assert.Equal(t, created.ID(), batch[0].ID())
+
+	duplicate, err := entities.NewUser(
+		"ada@example.com", "ada2", "hash", "Ada", "Again",
+		entities.UserStatusActive, entities.UserRoleUser, nil, nil,
+	)
+	require.NoError(t, err)
+	require.ErrorIs(t, repo.Create(ctx, duplicate), entities.ErrUserAlreadyExists)
+
+	credentials, err := repo.GetCredentials(ctx, created.Email())
+	require.NoError(t, err)
+	assert.Equal(t, created.PasswordHash(), credentials.PasswordHash())
+
+	require.NoError(t, repo.Suspend(ctx, created.ID()))
+	require.NoError(t, repo.MarkVerified(ctx, created.ID()))
+
+	loaded, err = repo.GetByID(ctx, created.ID())
+	require.NoError(t, err)
+	assert.Equal(t, entities.UserStatusSuspended, loaded.Status())
+	assert.True(t, loaded.IsVerified())
+
+	require.NoError(t, repo.Delete(ctx, created.ID()))
+
+	_, err = repo.GetByID(ctx, created.ID())
+	require.True(t, errors.Is(err, entities.ErrUserNotFound))
+}
+
+func TestSQLiteUserMapperRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	repo := sqliteadapter.NewUserRepository(containers.SQLite(t))
+	mapper := mappers.NewUserMapper()
+
+	created := createSQLiteUser(t, repo, "grace@example.com", "grace", "Grace", "navy")
+	created.Metadata()["theme"] = "light"
+	created.RecordLogin()
+	require.NoError(t, repo.Update(ctx, created))
+
+	loaded, err := repo.GetByID(ctx, created.ID())
+	require.NoError(t, err)
+
+	model, err := mapper.SQLiteUserFromDomain(loaded)
+	require.NoError(t, err)
+	assert.Equal(t, loaded.UUID().String(), model.UUID)
+	assert.JSONEq(t, `["navy"]`, model.Tags)
+
+	mapped, err := mapper.DomainUserFromSQLite(model)
+	require.NoError(t, err)
+	assert.Equal(t, loaded.Record(), mapped.Record())
+}
+
+func TestSQLiteUserRepositorySearch(t *testing.T) {
+	ctx := context.Background()
+	repo := sqliteadapter.NewUserRepository(containers.SQLite(t))
+
+	createSQLiteUser(t, repo, "grace@example.com", "grace", "Grace", "navy", "compilers")
+	createSQLiteUser(t, repo, "alan@example.com", "alan", "Alan", "compilers")
+	createSQLiteUser(t, repo, "barbara@example.com", "barbara", "Barbara", "languages")
+
+	found, err := repo.List(ctx, entities.UserFilter{Query: "gra"}, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, entities.Username("grace"), found[0].Username())
+
+	found, err = repo.List(ctx, entities.UserFilter{Query: `"unbalanced`}, 10, 0)
+	require.NoError(t, err)
+	assert.Empty(t, found)
+
+	tagged, err := repo.List(ctx, entities.UserFilter{TagsAny: []string{"compilers"}}, 10, 0)
+	require.NoError(t, err)
+	assert.Len(t, tagged, 2)
+
+	result, err := repo.SearchWithFacets(ctx, "example.com", entities.UserStatusActive, 2)
+	require.NoError(t, err)
+	assert.Len(t, result.Users, 2)
+	assert.Equal(t, int64(3), result.Facets.ByStatus[entities.UserStatusActive])
+	require.NotEmpty(t, result.Facets.TopTags)
+	assert.Equal(t, entities.TagCount{Tag: "compilers", Count: 2}, result.Facets.TopTags[0])
+
+	counts, err := repo.CountByStatus(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), counts[entities.UserStatusActive])
+
+	stats, err := repo.GetStats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), stats.TotalUsers)
+	assert.Equal(t, int64(3), stats.NewUsers7d)
+	assert.InDelta(t, 100.0, stats.ActivePercentage, 0.001)
+}
+
+func TestSQLiteUserRepositoryListFilter(t *testing.T) {
+	ctx := context.Background()
+	repo := sqliteadapter.NewUserRepository(containers.SQLite(t))
+
+	grace := createSQLiteUser(t, repo, "grace@example.com", "grace", "Grace", "navy", "compilers")
+	alan := createSQLiteUser(t, repo, "alan@example.com", "alan", "Alan", "compilers")
+	barbara := createSQLiteUser(t, repo, "barbara@example.com", "barbara", "Barbara", "languages")
+
+	require.NoError(t, repo.MarkVerified(ctx, grace.ID()))
+	require.NoError(t, repo.ChangeRole(ctx, alan.ID(), entities.UserRoleAdmin))
+	require.NoError(t, repo.Suspend(ctx, barbara.ID()))
+
+	verified, unverified := true, false
+
+	tests := []struct {
+		name     string
+		filter   entities.UserFilter
+		expected []string
+	}{
+		{"no criteria", entities.UserFilter{}, []string{"barbara", "alan", "grace"}},
+		{
+			"status set",
+			entities.UserFilter{Statuses: []entities.UserStatus{entities.UserStatusActive, entities.UserStatusPending}},
+			[]string{"alan", "grace"},
+		},
+		{"role set", entities.UserFilter{Roles: []entities.UserRole{entities.UserRoleAdmin}}, []string{"alan"}},
+		{"verified", entities.UserFilter{Verified: &verified}, []string{"grace"}},
+		{"unverified", entities.UserFilter{Verified: &unverified}, []string{"barbara", "alan"}},
+		{"any tag", entities.UserFilter{TagsAny: []string{"navy", "languages"}}, []string{"barbara", "grace"}},
+		{"all tags", entities.UserFilter{TagsAll: []string{"navy", "compilers"}}, []string{"grace"}},
+		{"created before", entities.UserFilter{CreatedBefore: time.Now().Add(-time.Hour)}, []string{}},
+		{"created after", entities.UserFilter{CreatedAfter: time.Now().Add(-time.Hour)}, []string{"barbara", "alan", "grace"}},
+		{"query and tag", entities.UserFilter{Query: "al", TagsAny: []string{"compilers"}}, []string{"alan"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users, err := repo.List(ctx, tt.filter, 10, 0)
+			require.NoError(t, err)
+
+			names := make([]string, 0, len(users))
+			for _, user := range users {
+				names = append(names, user.Username().String())
+			}
+
+			assert.Equal(t, tt.expected, names)
+		})
+	}
+
+	_, err := repo.List(ctx, entities.UserFilter{Statuses: []entities.UserStatus{"bogus"}}, 10, 0)
+	require.ErrorIs(t, err, entities.ErrInvalidUserStatus)
+}
+
+func TestSQLiteUserRepositorySoftDelete(t *testing.T) {
+	ctx := context.Background()
+	repo := sqliteadapter.NewUserRepository(containers.SQLite(t))
+
+	grace := createSQLiteUser(t, repo, "grace@example.com", "grace", "Grace")
+	createSQLiteUser(t, repo, "alan@example.com", "alan", "Alan")
+
+	require.NoError(t, repo.Delete(ctx, grace.ID()))
+
+	_, err := repo.GetByEmail(ctx, grace.Email())
+	require.ErrorIs(t, err, entities.ErrUserNotFound)
+	require.ErrorIs(t, repo.Update(ctx, grace), entities.ErrUserNotFound)
+
+	listed, err := repo.List(ctx, entities.UserFilter{}, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+
+	require.NoError(t, repo.Restore(ctx, grace.ID()))
+	require.ErrorIs(t, repo.Restore(ctx, grace.ID()), entities.ErrUserNotFound)
+
+	restored, err := repo.GetByID(ctx, grace.ID())
+	require.NoError(t, err)
+	assert.Equal(t, grace.Email(), restored.Email())
+
+	require.NoError(t, repo.Delete(ctx, grace.ID()))
+
+	purged, err := repo.PurgeDeletedOlderThan(ctx, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Zero(t, purged)
+
+	purged, err = repo.PurgeDeletedOlderThan(ctx, time.Now().Add(time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+
+	require.ErrorIs(t, repo.Restore(ctx, grace.ID()), entities.ErrUserNotFound)
+}
+
+func TestSQLiteAuditLogFromService(t *testing.T) {
+	ctx := context.Background()
+	db := containers.SQLite(t)
+	repo := sqliteadapter.NewUserRepository(db)
+	audit := sqliteadapter.NewAuditRepository(db)
+	users := services.NewUserService(
+		repo,
+		sqliteadapter.NewSessionRepository(db),
+		events.NewInMemoryEventPublisher(),
+		validation.NewUserValidator(),
+		services.WithAuditLog(audit),
+	)
+
+	start := time.Now().Add(-time.Second)
+	target := createSQLiteUser(t, repo, "grace@example.com", "grace", "Grace")
+	admin := createSQLiteUser(t, repo, "root@example.com", "root", "Root")
+
+	adminCtx := services.ContextWithAuditActor(ctx, services.AuditActor{UserID: admin.ID(), IPAddress: "203.0.113.7"})
+	_, err := users.ChangeUserRole(adminCtx, target.ID(), entities.UserRoleModerator, "")
+	require.NoError(t, err)
+	_, err = users.DeactivateUser(ctx, target.ID())
+	require.NoError(t, err)
+
+	listing := services.NewAuditService(audit)
+
+	entries, err := listing.List(ctx, entities.AuditFilter{UserID: target.ID(), Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, entities.AuditActionStatusChange, entries[0].Action)
+	assert.True(t, entries[0].IsSystem())
+
+	roleChanges, err := listing.List(ctx, entities.AuditFilter{
+		Action: entities.AuditActionRoleChange,
+		From:   start,
+		To:     time.Now().Add(time.Second),
+		Limit:  10,
+	})
+	require.NoError(t, err)
+	require.Len(t, roleChanges, 1)
+	assert.Equal(t, admin.ID(), roleChanges[0].ActorID)
+	assert.Equal(t, "203.0.113.7", roleChanges[0].IPAddress)
+	assert.Equal(t, []entities.FieldChange{{Field: "role", Old: "user", New: "moderator"}}, roleChanges[0].Changes)
+
+	future, err := listing.List(ctx, entities.AuditFilter{From: time.Now().Add(time.Hour), Limit: 10})
+	require.NoError(t, err)
+	assert.Empty(t, future)
+
+	anonymized, err := audit.AnonymizeUser(ctx, admin.ID())
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), anonymized)
+
+	roleChanges, err = listing.List(ctx, entities.AuditFilter{Action: entities.AuditActionRoleChange, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, roleChanges, 1)
+	assert.Empty(t, roleChanges[0].IPAddress)
+	assert.Empty(t, roleChanges[0].Changes)
+}
+
+func TestSQLiteBulkOperationAuditsOnce(t *testing.T) {
+	ctx := context.Background()
+	db := containers.SQLite(t)
+	repo := sqliteadapter.NewUserRepository(db)
+	audit := sqliteadapter.NewAuditRepository(db)
+	users := services.NewUserService(
+		repo,
+		sqliteadapter.NewSessionRepository(db),
+		events.NewInMemoryEventPublisher(),
+		validation.NewUserValidator(),
+		services.WithAuditLog(audit),
+	)
+
+	ada := createSQLiteUser(t, repo, "ada@example.com", "ada", "Ada")
+	grace := createSQLiteUser(t, repo, "grace@example.com", "grace", "Grace")
+	admin := createSQLiteUser(t, repo, "root@example.com", "root", "Root")
+	missing := entities.UserID(999999)
+
+	adminCtx := services.ContextWithAuditActor(ctx, services.AuditActor{UserID: admin.ID(), IPAddress: "203.0.113.7"})
+	result, err := users.BulkChangeRole(adminCtx,
+		[]entities.UserID{ada.ID(), grace.ID(), missing}, entities.UserRoleModerator, admin.ID())
+	require.NoError(t, err)
+	require.Len(t, result.Succeeded, 2)
+
+	entries, err := audit.List(ctx, entities.AuditFilter{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, entries, 1, "a bulk operation is audited once, not once per user")
+
+	entry := entries[0]
+	assert.Equal(t, entities.AuditActionBulkRoleChange, entry.Action)
+	assert.Equal(t, admin.ID(), entry.ActorID)
+	assert.Zero(t, entry.UserID)
+	assert.Equal(t, "203.0.113.7", entry.IPAddress)
+	assert.Equal(t, []entities.FieldChange{
+		{Field: "role", New: "moderator"},
+		{Field: "succeeded", New: fmt.Sprintf("%d,%d", ada.ID(), grace.ID())},
+		{Field: "failed", New: "999999"},
+	}, entry.Changes)
+}
+
+func TestSQLiteOrganizationMembership(t *testing.T) {
+	ctx := context.Background()
+	db := containers.SQLite(t)
+	repo := sqliteadapter.NewUserRepository(db)
+	memberships := sqliteadapter.NewMembershipRepository(db)
+	orgs := services.NewOrganizationService(
+		sqliteadapter.NewOrganizationRepository(db),
+		memberships,
+		repo,
+		events.NewInMemoryEventPublisher(),
+	)
+
+	owner := createSQLiteUser(t, repo, "ada@example.com", "ada", "Ada")
+	member := createSQLiteUser(t, repo, "alan@example.com", "alan", "Alan")
+
+	org, err := orgs.CreateOrganization(ctx, owner.ID(), "Analytical Engines", "analytical-engines")
+	require.NoError(t, err)
+	require.NotZero(t, org.ID())
+
+	_, err = orgs.CreateOrganization(ctx, member.ID(), "Copycat", "analytical-engines")
+	require.ErrorIs(t, err, entities.ErrOrganizationAlreadyExists)
+
+	_, err = orgs.Invite(ctx, org.ID(), owner.ID(), member.ID(), entities.UserRoleUser)
+	require.NoError(t, err)
+
+	_, err = memberships.RoleInOrganization(ctx, member.ID(), org.ID())
+	require.ErrorIs(t, err, entities.ErrMembershipNotFound, "pending invitations grant no role")
+
+	joined, err := orgs.Join(ctx, org.ID(), member.ID())
+	require.NoError(t, err)
+	require.NotNil(t, joined.JoinedAt())
+
+	role, err := memberships.RoleInOrganization(ctx, member.ID(), org.ID())
+	require.NoError(t, err)
+	assert.Equal(t, entities.UserRoleUser, role)
+
+	err = orgs.Leave(ctx, org.ID(), owner.ID())
+	require.ErrorIs(t, err, entities.ErrLastOrganizationAdmin)
+
+	_, err = orgs.ChangeMemberRole(ctx, org.ID(), owner.ID(), member.ID(), entities.UserRoleAdmin)
+	require.NoError(t, err)
+	require.NoError(t, orgs.Leave(ctx, org.ID(), owner.ID()))
+
+	members, err := orgs.ListMembers(ctx, org.ID(), 10, 0)
+	require.NoError(t, err)
+	require.Len(t, members, 1)
+	assert.True(t, members[0].IsAdmin())
+
+	memberOrgs, err := orgs.ListUserOrganizations(ctx, member.ID(), 10)
+	require.NoError(t, err)
+	require.Len(t, memberOrgs, 1)
+	assert.Equal(t, org.Slug(), memberOrgs[0].Slug())
+
+	ownerOrgs, err := orgs.ListUserOrganizations(ctx, owner.ID(), 10)
+	require.NoError(t, err)
+	assert.Empty(t, ownerOrgs)
+}
+
+func TestSQLiteOrganizationRepositoryCRUD(t *testing.T) {
+	ctx := context.Background()
+	repo := sqliteadapter.NewOrganizationRepository(containers.SQLite(t))
+
+	first := entities.NewOrganization("First", "first")
+	second := entities.NewOrganization("Second", "second")
+	require.NoError(t, repo.Create(ctx, first))
+	require.NoError(t, repo.Create(ctx, second))
+	require.NotZero(t, first.ID())
+
+	err := repo.Create(ctx, entities.NewOrganization("Copycat", "first"))
+	require.ErrorIs(t, err, entities.ErrOrganizationAlreadyExists)
+
+	first.Rename("Renamed")
+	require.NoError(t, repo.Update(ctx, first))
+
+	loaded, err := repo.GetByID(ctx, first.ID())
+	require.NoError(t, err)
+	assert.Equal(t, entities.OrganizationName("Renamed"), loaded.Name())
+
+	page, err := repo.List(ctx, 1, 1)
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, second.ID(), page[0].ID())
+
+	_, err = repo.List(ctx, 0, 0)
+	require.Error(t, err)
+
+	require.NoError(t, repo.Delete(ctx, first.ID()))
+
+	_, err = repo.GetByID(ctx, first.ID())
+	require.ErrorIs(t, err, entities.ErrOrganizationNotFound)
+	require.ErrorIs(t, repo.Delete(ctx, first.ID()), entities.ErrOrganizationNotFound)
+	require.ErrorIs(t, repo.Update(ctx, first), entities.ErrOrganizationNotFound)
+}
+
+func TestSQLiteUserRepositoryBatch(t *testing.T) {
+	ctx := context.Background()
+	repo := sqliteadapter.NewUserRepository(containers.SQLite(t))
+
+	newUsers := func(prefix string, count int) []*entities.User {
+		users := make([]*entities.User, 0, count)
+
+		for i := range count {
+			user, err := entities.NewUser(
+				entities.Email(fmt.Sprintf("%s%d@example.com", prefix, i)),
+				entities.Username(fmt.Sprintf("%s%d", prefix, i)),
+				"hash", "Bulk", "Tester",
+				entities.UserStatusActive, entities.UserRoleUser, nil, nil,
+			)
+			require.NoError(t, err)
+
+			users = append(users, user)
+		}
+
+		return users
+	}
+
+	users := newUsers("bulk", 2500)
+	require.NoError(t, repo.CreateBatch(ctx, users))
+
+	ids := make([]entities.UserID, 0, len(users))
+	for _, user := range users {
+		require.NotZero(t, user.ID())
+		ids = append(ids, user.ID())
+	}
+
+	updated, err := repo.UpdateStatusBatch(ctx, append(ids, entities.UserID(999999)), entities.UserStatusSuspended)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(users)), updated)
+
+	loaded, err := repo.GetByID(ctx, ids[1500])
+	require.NoError(t, err)
+	assert.Equal(t, entities.UserStatusSuspended, loaded.Status())
+
+	deleted, err := repo.DeleteBatch(ctx, ids[:10])
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), deleted)
+
+	deleted, err = repo.DeleteBatch(ctx, ids[:20])
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), deleted, "already deleted users are skipped")
+
+	conflicting := append(newUsers("fresh", 3), newUsers("bulk", 1)...)
+	require.ErrorIs(t, repo.CreateBatch(ctx, conflicting), entities.ErrUserAlreadyExists)
+
+	_, err = repo.GetByEmail(ctx, "fresh0@example.com")
+	require.ErrorIs(t, err, entities.ErrUserNotFound, "a failed batch creates no users")
+}
+
+func TestSQLiteUserRepositoryListPage(t *testing.T) {
+	ctx := context.Background()
+	repo := sqliteadapter.NewUserRepository(containers.SQLite(t))
+
+	// Three users share a creation time, so pages must break ties by ID.
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	createdAt := []time.Time{base, base.Add(time.Hour), base.Add(time.Hour), base.Add(time.Hour), base.Add(2 * time.Hour)}
+
+	for i, at := range createdAt {
+		user, err := entities.ReconstructUser(entities.UserRecord{
+			UUID:      uuid.New(),
+			Email:     entities.Email(fmt.Sprintf("page%d@example.com", i)),
+			Username:  entities.Username(fmt.Sprintf("page%d", i)),
+			Password:  "hash",
+			FirstName: "Page",
+			LastName:  "Tester",
+			Status:    entities.UserStatusActive,
+			Role:      entities.UserRoleUser,
+			CreatedAt: at,
+			UpdatedAt: at,
+		})
+		require.NoError(t, err)
+		require.NoError(t, repo.Create(ctx, user))
+	}
+
+	var (
+		seen   []string
+		cursor string
+	)
+
+	for {
+		page, err := repo.ListPage(ctx, entities.UserFilter{}, cursor, 2)
+		require.NoError(t, err)
+		require.LessOrEqual(t, len(page.Users), 2)
+
+		for _, user := range page.Users {
+			seen = append(seen, user.Username().String())
+		}
+
+		if page.NextCursor == "" {
+			break
+		}
+
+		cursor = page.NextCursor
+	}
+
+	assert.Equal(t, []string{"page4", "page3", "page2", "page1", "page0"}, seen)
+
+	page, err := repo.ListPage(ctx, entities.UserFilter{Statuses: []entities.UserStatus{entities.UserStatusSuspended}}, "", 10)
+	require.NoError(t, err)
+	assert.Empty(t, page.Users)
+	assert.Empty(t, page.NextCursor)
+
+	_, err = repo.ListPage(ctx, entities.UserFilter{}, "not-a-cursor", 10)
+	require.ErrorIs(t, err, entities.ErrInvalidCursor)
+}
+
+func TestUserServiceIntegrationSuiteSQLite(t *testing.T) {
+	suite.Run(t, &UserServiceIntegrationTestSuite{
+		newUserRepository: func(t *testing.T) repositories.UserRepository {
+			return sqliteadapter.NewUserRepository(containers.SQLite(t))
+		},
+	})
+}
+
+func TestSQLiteUserRepositoryConformance(t *testing.T) {
+	repositorytest.RunUserRepositoryTests(t, func(t *testing.T) repositorytest.Repositories {
+		db := containers.SQLite(t)
+
+		return repositorytest.Repositories{
+			Users:            sqliteadapter.NewUserRepository(db),
+			Sessions:         sqliteadapter.NewSessionRepository(db),
+			Jobs:             sqliteadapter.NewJobRepository(db),
+			Idempotency:      sqliteadapter.NewIdempotencyRepository(db),
+			Notifications:    sqliteadapter.NewNotificationPreferenceRepository(db),
+			IdentityChanges:  sqliteadapter.NewIdentityChangeRepository(db),
+			Moderation:       sqliteadapter.NewModerationRepository(db),
+			AccountDeletions: sqliteadapter.NewAccountDeletionRepository(db),
+			Preferences:      sqliteadapter.NewUserPreferenceRepository(db),
+			Activity:         sqliteadapter.NewActivityRepository(db),
+			LoginHistory:     sqliteadapter.NewLoginHistoryRepository(db),
+			UserEvents:       sqliteadapter.NewUserEventStore(db),
+			UserReadModel:    sqliteadapter.NewUserReadModelRepository(db),
+			FeatureFlags:     sqliteadapter.NewFeatureFlagRepository(db),
+			Transactions:     sqliteadapter.NewTransactionalRepository(db),
+		}
+	})
+}
+
+func TestSQLiteEventSourcedRepositoryConformance(t *testing.T) {
+	repositorytest.RunUserRepositoryTests(t, func(t *testing.T) repositorytest.Repositories {
+		db := containers.SQLite(t)
+		users := sqliteadapter.NewUserRepository(db)
+		store := sqliteadapter.NewUserEventStore(db)
+
+		return repositorytest.Repositories{
+			Users: eventsourced.NewUserRepository(users, store, eventsourced.WithSnapshotEvery(2)),
+		}
+	})
+}
+
+func TestSQLiteTransactionalRepository(t *testing.T) {
+	ctx := context.Background()
+	db := containers.SQLite(t)
+	users := sqliteadapter.NewUserRepository(db)
+	txRepo := sqliteadapter.NewTransactionalRepository(db)
+	errAbort := errors.New("abort")
+
+	err := txRepo.RunInTransaction(ctx, func(ctx context.Context, tx repositories.Transaction) error {
+		createSQLiteUser(t, tx.UserRepository(), "rolled@example.com", "rolled", "Rolled")
+
+		return errAbort
+	})
+	require.ErrorIs(t, err, errAbort)
+
+	_, err = users.GetByEmail(ctx, "rolled@example.com")
+	require.ErrorIs(t, err, entities.ErrUserNotFound)
+
+	var committed *entities.User
+
+	err = txRepo.RunInTransaction(ctx, func(ctx context.Context, tx repositories.Transaction) error {
+		committed = createSQLiteUser(t, tx.UserRepository(), "committed@example.com", "committed", "Committed")
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	loaded, err := users.GetByEmail(ctx, "committed@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, committed.ID(), loaded.ID())
+
+	service := services.NewUserService(
+		users,
+		sqliteadapter.NewSessionRepository(db),
+		events.NewInMemoryEventPublisher(),
+		validation.NewUserValidator(),
+		services.WithTransactions(txRepo),
+	)
+
+	result, err := service.BulkChangeStatus(ctx, []entities.UserID{committed.ID()}, entities.UserStatusSuspended, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []entities.UserID{committed.ID()}, result.Succeeded)
+
+	loaded, err = users.GetByID(ctx, committed.ID())
+	require.NoError(t, err)
+	assert.Equal(t, entities.UserStatusSuspended, loaded.Status())
+}
+
+func TestSQLiteUnitOfWorkRecordsAudit(t *testing.T) {
+	ctx := context.Background()
+	db := containers.SQLite(t)
+	audit := sqliteadapter.NewAuditRepository(db)
+	txRepo := sqliteadapter.NewTransactionalRepository(db)
+	errAbort := errors.New("abort")
+
+	newUser := func(username string) *entities.User {
+		user, err := entities.NewUser(
+			entities.Email(username+"@example.com"),
+			entities.Username(username),
+			entities.PasswordHash("$2a$10$hash-"+username),
+			entities.FirstName("Audit"),
+			entities.LastName("Tester"),
+			entities.UserStatusActive,
+			entities.UserRoleUser,
+			entities.NewUserMetadata(),
+			nil,
+		)
+		require.NoError(t, err)
+
+		return user
+	}
+
+	signUp := func(user *entities.User) *services.UnitOfWork {
+		return services.NewUnitOfWork(txRepo, nil).
+			CreateUser(user).
+			RecordAudit(func() *entities.AuditLog {
+				return entities.NewAuditLog(user.ID(), user.ID(), entities.AuditActionUserCreate, nil, "")
+			})
+	}
+
+	err := signUp(newUser("rolled")).
+		Add(func(context.Context, repositories.Transaction) error { return errAbort }).
+		Commit(ctx)
+	require.ErrorIs(t, err, errAbort)
+
+	entries, err := audit.List(ctx, entities.AuditFilter{Action: entities.AuditActionUserCreate, Limit: 10})
+	require.NoError(t, err)
+	assert.Empty(t, entries, "the rollback discards the audit entry")
+
+	committed := newUser("committed")
+	require.NoError(t, signUp(committed).Commit(ctx))
+
+	entries, err = audit.List(ctx, entities.AuditFilter{Action: entities.AuditActionUserCreate, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, committed.ID(), entries[0].UserID)
+}
+
+func TestSQLiteUserHistoryRepository(t *testing.T) {
+	ctx := context.Background()
+	db := containers.SQLite(t)
+	users := sqliteadapter.NewUserRepository(db)
+	history := sqliteadapter.NewUserHistoryRepository(db)
+
+	user := createSQLiteUser(t, users, "history@example.com", "history", "Before")
+
+	after := entities.FirstName("After")
+	require.NoError(t, user.UpdateProfile(&after, nil, nil, nil))
+	require.NoError(t, users.Update(ctx, user))
+
+	// Recording a login changes no archived column, so it adds no version.
+	user.RecordLogin()
+	require.NoError(t, users.Update(ctx, user))
+
+	versions, err := history.ListVersions(ctx, user.ID(), 10)
+	require.NoError(t, err)
+	require.Len(t, versions, 1)
+	assert.Equal(t, entities.HistoryOperationUpdate, versions[0].Operation)
+	assert.Equal(t, entities.FirstName("Before"), versions[0].FirstName)
+	assert.False(t, versions[0].IsCurrent())
+
+	current, err := history.GetUserAsOf(ctx, user.ID(), time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, entities.FirstName("After"), current.FirstName)
+	assert.True(t, current.IsCurrent())
+
+	deleted, err := history.DeleteVersions(ctx, user.ID())
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+
+	versions, err = history.ListVersions(ctx, user.ID(), 10)
+	require.NoError(t, err)
+	assert.Empty(t, versions)
+}
+
+func TestSQLiteEraseUserDeletesArchivedVersions(t *testing.T) {
+	ctx := context.Background()
+	db := containers.SQLite(t)
+	users := sqliteadapter.NewUserRepository(db)
+
+	user := createSQLiteUser(t, users, "erased@example.com", "erased", "Erased")
+
+	renamed := entities.FirstName("Renamed")
+	require.NoError(t, user.UpdateProfile(&renamed, nil, nil, nil))
+	require.NoError(t, users.Update(ctx, user))
+
+	service := services.NewUserService(
+		users,
+		sqliteadapter.NewSessionRepository(db),
+		events.NewInMemoryEventPublisher(),
+		validation.NewUserValidator(),
+		services.WithTransactions(sqliteadapter.NewTransactionalRepository(db)),
+	)
+
+	require.NoError(t, service.EraseUser(ctx, user.ID()))
+
+	var leaked int
+
+	err := db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM users_history
+		 WHERE email = ? OR username = ? OR first_name IN (?, ?) OR last_name = ?`,
+		"erased@example.com", "erased", "Erased", "Renamed", "Tester",
+	).Scan(&leaked)
+	require.NoError(t, err)
+	assert.Zero(t, leaked, "users_history keeps personal data of the erased user")
+
+	versions, err := sqliteadapter.NewUserHistoryRepository(db).ListVersions(ctx, user.ID(), 10)
+	require.NoError(t, err)
+	assert.Empty(t, versions)
+}
+
+func TestSQLiteSessionUpdateIfCurrent(t *testing.T) {
+	ctx := context.Background()
+	db := containers.SQLite(t)
+	sessions := sqliteadapter.NewSessionRepository(db)
+
+	user := createSQLiteUser(t, sqliteadapter.NewUserRepository(db), "ada@example.com", "ada", "Ada")
+	session := entities.NewUserSession(user.ID(), nil, "", entities.NewSessionDeviceInfo(), time.Hour)
+	require.NoError(t, sessions.Create(ctx, session))
+
+	now := time.Now()
+	session.IssueRefreshToken(now.Add(time.Hour))
+	require.NoError(t, sessions.Update(ctx, session))
+
+	// Two requests read the session, then both rotate its refresh token.
+	issued := session.RefreshToken()
+	first, err := sessions.GetByToken(ctx, session.Token())
+	require.NoError(t, err)
+	second, err := sessions.GetByToken(ctx, session.Token())
+	require.NoError(t, err)
+
+	for _, read := range []*entities.UserSession{first, second} {
+		_, err = read.RotateRefreshToken(issued, now.Add(time.Hour), now)
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, sessions.UpdateIfCurrent(ctx, first, issued))
+	require.ErrorIs(t, sessions.UpdateIfCurrent(ctx, second, issued), entities.ErrSessionConflict,
+		"the token was rotated already")
+
+	stale, err := sessions.GetByToken(ctx, session.Token())
+	require.NoError(t, err)
+	require.NoError(t, sessions.DeactivateByToken(ctx, session.Token()))
+	require.ErrorIs(t, sessions.UpdateIfCurrent(ctx, stale, stale.RefreshToken()), entities.ErrSessionConflict,
+		"the session was revoked")
+
+	stored, err := sessions.GetByToken(ctx, session.Token())
+	require.NoError(t, err)
+	assert.False(t, stored.IsActive())
+}
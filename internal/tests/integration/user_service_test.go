@@ -4,13 +4,20 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/tokens"
 	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
 	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
 	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
 	"github.com/LarsArtmann/template-sqlc/internal/domain/services"
+	"github.com/LarsArtmann/template-sqlc/internal/monitoring"
 	"github.com/LarsArtmann/template-sqlc/internal/validation"
+	"github.com/LarsArtmann/template-sqlc/pkg/passwords"
 	"github.com/stretchr/testify/suite"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	"golang.org/x/crypto/bcrypt"
 )
 
 const testPasswordHash = "$2a$10$N9qo8uLOickgx2ZMRZoMyeIjZRGdjGj/n3.rsQ5pPjZ5yVlWK5WAe"
@@ -33,15 +40,22 @@ func newTestCreateUserRequest(username, firstName, lastName string) *services.Cr
 }
 
 // UserServiceIntegrationTestSuite contains integration tests for UserService.
+// It runs against the mock repositories, and against every database engine
+// when its build tag is set.
 type UserServiceIntegrationTestSuite struct {
 	suite.Suite
 
+	// newUserRepository creates the user repository of each test; nil uses
+	// the mock. Sessions always use the mock, as not every engine stores them.
+	newUserRepository func(t *testing.T) repositories.UserRepository
+
 	ctx            context.Context //nolint:containedctx // Test context stored in struct
 	userService    *services.UserService
 	userRepo       repositories.UserRepository
 	sessionRepo    repositories.SessionRepository
 	eventPublisher *events.InMemoryEventPublisher
 	validator      *validation.UserValidator
+	hasher         *passwords.BcryptHasher
 	cleanup        []func() error
 }
 
@@ -55,33 +69,41 @@ func (s *UserServiceIntegrationTestSuite) SetupSuite() {
 	// Initialize validator
 	s.validator = validation.NewUserValidator()
 
-	// Initialize mock repositories
-	s.userRepo = NewMockUserRepository()
-	s.sessionRepo = NewMockSessionRepository()
-
-	// Create user service
-	s.userService = services.NewUserService(
-		s.userRepo,
-		s.sessionRepo,
-		s.eventPublisher,
-		s.validator,
-	)
+	s.hasher = passwords.NewBcryptHasher(bcrypt.MinCost)
 }
 
-// SetupTest resets state before each test.
+// SetupTest gives each test fresh repositories and a clean event log.
 func (s *UserServiceIntegrationTestSuite) SetupTest() {
-	// Reset mock repositories for each test
 	s.eventPublisher.Clear()
+
 	s.userRepo = NewMockUserRepository()
+	if s.newUserRepository != nil {
+		s.userRepo = s.newUserRepository(s.T())
+	}
+
 	s.sessionRepo = NewMockSessionRepository()
+
 	s.userService = services.NewUserService(
 		s.userRepo,
 		s.sessionRepo,
 		s.eventPublisher,
 		s.validator,
+		services.WithPasswordHasher(s.hasher),
 	)
 }
 
+// loginPassword stores the hash of a password for user and returns the
+// password, which AuthenticateUser then accepts.
+func (s *UserServiceIntegrationTestSuite) loginPassword(user *entities.User) string {
+	const password = "correct_password"
+
+	hash, err := s.hasher.Hash(password)
+	s.Require().NoError(err)
+	s.Require().NoError(s.userRepo.UpdatePassword(s.ctx, user.ID(), entities.PasswordHash(hash)))
+
+	return password
+}
+
 // TearDownSuite cleans up the test suite.
 func (s *UserServiceIntegrationTestSuite) TearDownSuite() {
 	// Cleanup test resources
@@ -196,16 +218,10 @@ func (s *UserServiceIntegrationTestSuite) TestAuthenticateUser() {
 	user, err := s.userService.CreateUser(s.ctx, req)
 	s.Require().NoError(err)
 
-	// Mock the password verification
-	// In a real implementation, this would be handled by the repository
-	if mockRepo, ok := s.userRepo.(*MockUserRepository); ok {
-		mockRepo.SetPasswordVerification(user.Email().String(), "correct_password")
-	}
-
 	// Test successful authentication
 	session, err := s.userService.AuthenticateUser(s.ctx,
 		"test@example.com",
-		"correct_password",
+		s.loginPassword(user),
 		"127.0.0.1",
 		"test-user-agent")
 	if err == nil {
@@ -299,6 +315,214 @@ func (s *UserServiceIntegrationTestSuite) TestGetUserStats() {
 	s.Positive(stats.ActiveUsers)
 }
 
+func (s *UserServiceIntegrationTestSuite) TestBulkChangeRole() {
+	first, err := s.userService.CreateUser(s.ctx, newTestCreateUserRequest("bulkuser1", "John", "Doe"))
+	s.Require().NoError(err)
+
+	secondReq := newTestCreateUserRequest("bulkuser2", "Jane", "Doe")
+	secondReq.Email = "second@example.com"
+
+	second, err := s.userService.CreateUser(s.ctx, secondReq)
+	s.Require().NoError(err)
+
+	missing := entities.UserID(999)
+
+	result, err := s.userService.BulkChangeRole(
+		s.ctx,
+		[]entities.UserID{first.ID(), second.ID(), missing},
+		entities.UserRoleModerator,
+		entities.UserID(0),
+	)
+	s.Require().NoError(err)
+	s.ElementsMatch([]entities.UserID{first.ID(), second.ID()}, result.Succeeded)
+	s.Require().Len(result.Failed, 1)
+	s.Equal(missing, result.Failed[0].UserID)
+
+	reloaded, err := s.userService.GetUser(s.ctx, first.ID())
+	s.Require().NoError(err)
+	s.Equal(entities.UserRoleModerator, reloaded.Role())
+
+	userEvents := s.eventPublisher.Events()
+	s.Equal(events.EventUsersBulkUpdated, userEvents[len(userEvents)-1].Type)
+}
+
+func (s *UserServiceIntegrationTestSuite) TestBulkChangeStatusRejectsInvalidStatus() {
+	result, err := s.userService.BulkChangeStatus(
+		s.ctx,
+		[]entities.UserID{1},
+		entities.UserStatus("unknown"),
+		entities.UserID(0),
+	)
+	s.Require().Error(err)
+	s.Nil(result)
+	s.True(entities.IsValidationError(err))
+}
+
+func (s *UserServiceIntegrationTestSuite) TestSearchUsersWithFacets() {
+	active, err := s.userService.CreateUser(s.ctx, newTestCreateUserRequest("facetuser1", "John", "Doe"))
+	s.Require().NoError(err)
+
+	inactiveReq := newTestCreateUserRequest("facetuser2", "Jane", "Doe")
+	inactiveReq.Email = "second@example.com"
+
+	inactive, err := s.userService.CreateUser(s.ctx, inactiveReq)
+	s.Require().NoError(err)
+
+	_, err = s.userService.DeactivateUser(s.ctx, inactive.ID())
+	s.Require().NoError(err)
+
+	result, err := s.userService.SearchUsersWithFacets(s.ctx, "doe", entities.UserStatusActive, 10)
+	s.Require().NoError(err)
+	s.Require().Len(result.Users, 1)
+	s.Equal(active.ID(), result.Users[0].ID())
+	s.Equal(int64(1), result.Facets.ByStatus[entities.UserStatusActive])
+	s.Equal(int64(1), result.Facets.ByStatus[entities.UserStatusInactive])
+	s.Equal(int64(2), result.Facets.ByRole[entities.UserRoleUser])
+}
+
+func (s *UserServiceIntegrationTestSuite) TestCreateUserHashesPassword() {
+	req := newTestCreateUserRequest("hasheduser", "John", "Doe")
+	req.PasswordHash = ""
+	req.Password = "Correct-Horse-42"
+
+	user, err := s.userService.CreateUser(s.ctx, req)
+	s.Require().NoError(err)
+	s.Require().NoError(s.hasher.Verify(user.PasswordHash().String(), req.Password))
+
+	session, err := s.userService.AuthenticateUser(s.ctx, req.Email, req.Password, "127.0.0.1", "test")
+	s.Require().NoError(err)
+	s.Equal(user.ID(), session.UserID())
+
+	_, err = s.userService.AuthenticateUser(s.ctx, req.Email, "Wrong-Horse-42", "127.0.0.1", "test")
+	s.Require().ErrorIs(err, entities.ErrInvalidCredentials)
+}
+
+func (s *UserServiceIntegrationTestSuite) TestCreateUserPropagatesTrace() {
+	tracer := monitoring.NewTracer(noop.NewTracerProvider())
+	service := services.NewUserService(
+		s.userRepo,
+		s.sessionRepo,
+		s.eventPublisher,
+		s.validator,
+		services.WithTracer(tracer),
+	)
+
+	spanContext := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x4b, 0xf9, 0x2f, 0x35},
+		SpanID:     trace.SpanID{0x00, 0xf0, 0x67, 0xaa},
+		TraceFlags: trace.FlagsSampled,
+	})
+	ctx := trace.ContextWithSpanContext(s.ctx, spanContext)
+
+	_, err := service.CreateUser(ctx, newTestCreateUserRequest("traceduser", "John", "Doe"))
+	s.Require().NoError(err)
+
+	published := s.eventPublisher.Events()
+	s.Require().Len(published, 1)
+	s.Contains(published[0].TraceParent, spanContext.TraceID().String())
+
+	handlerCtx := tracer.ContextFromEvent(context.Background(), published[0])
+	s.Equal(spanContext.TraceID(), trace.SpanContextFromContext(handlerCtx).TraceID())
+}
+
+func (s *UserServiceIntegrationTestSuite) TestVerifySessionWithJWT() {
+	strategy := tokens.NewJWTStrategy(
+		"template-sqlc",
+		tokens.NewHMACKey("test", []byte("0123456789abcdef0123456789abcdef")),
+	)
+	service := services.NewUserService(
+		s.userRepo,
+		s.sessionRepo,
+		s.eventPublisher,
+		s.validator,
+		services.WithPasswordHasher(s.hasher),
+		services.WithTokenStrategy(strategy),
+	)
+
+	user, err := service.CreateUser(s.ctx, newTestCreateUserRequest("jwtuser", "John", "Doe"))
+	s.Require().NoError(err)
+
+	session, err := service.AuthenticateUser(s.ctx, "test@example.com", s.loginPassword(user), "127.0.0.1", "test")
+	s.Require().NoError(err)
+
+	token, err := service.IssueSessionToken(session, user)
+	s.Require().NoError(err)
+
+	verified, verifiedUser, err := service.VerifySession(s.ctx, token)
+	s.Require().NoError(err)
+	s.Equal(session.Token(), verified.Token())
+	s.Equal(user.ID(), verifiedUser.ID())
+
+	_, _, err = service.VerifySession(s.ctx, session.Token().String())
+	s.Require().ErrorIs(err, entities.ErrInvalidSessionToken)
+
+	_, err = service.ChangeUserRole(s.ctx, user.ID(), entities.UserRoleModerator, "admin")
+	s.Require().NoError(err)
+
+	_, _, err = service.VerifySession(s.ctx, token)
+	s.Require().ErrorIs(err, entities.ErrInvalidSessionToken)
+}
+
+func (s *UserServiceIntegrationTestSuite) TestRefreshSessionDetectsReuse() {
+	user, err := s.userService.CreateUser(s.ctx, newTestCreateUserRequest("refreshuser", "John", "Doe"))
+	s.Require().NoError(err)
+
+	session, err := s.userService.AuthenticateUser(s.ctx, "test@example.com", s.loginPassword(user), "127.0.0.1", "test")
+	s.Require().NoError(err)
+
+	issued, err := s.userService.IssueTokenPair(s.ctx, session, user)
+	s.Require().NoError(err)
+
+	refreshed, err := s.userService.RefreshSession(s.ctx, issued.RefreshToken)
+	s.Require().NoError(err)
+	s.NotEqual(issued.RefreshToken, refreshed.RefreshToken)
+
+	_, err = s.userService.RefreshSession(s.ctx, issued.RefreshToken)
+	s.Require().ErrorIs(err, entities.ErrRefreshTokenReused)
+
+	_, err = s.userService.RefreshSession(s.ctx, refreshed.RefreshToken)
+	s.Require().ErrorIs(err, entities.ErrInvalidRefreshToken)
+
+	_, _, err = s.userService.VerifySession(s.ctx, refreshed.AccessToken)
+	s.Require().Error(err)
+
+	userEvents := s.eventPublisher.Events()
+	s.Equal(events.EventRefreshTokenReused, userEvents[len(userEvents)-1].Type)
+}
+
+func (s *UserServiceIntegrationTestSuite) TestDeleteAndRestoreUser() {
+	user, err := s.userService.CreateUser(s.ctx, newTestCreateUserRequest("deleteuser", "John", "Doe"))
+	s.Require().NoError(err)
+
+	s.Require().NoError(s.userService.DeleteUser(s.ctx, user.ID()))
+
+	_, err = s.userService.GetUser(s.ctx, user.ID())
+	s.Require().ErrorIs(err, entities.ErrUserNotFound)
+
+	userEvents := s.eventPublisher.Events()
+	s.Equal(events.EventUserDeleted, userEvents[len(userEvents)-1].Type)
+
+	restored, err := s.userService.RestoreUser(s.ctx, user.ID())
+	s.Require().NoError(err)
+	s.Equal(user.ID(), restored.ID())
+
+	userEvents = s.eventPublisher.Events()
+	s.Equal(events.EventUserRestored, userEvents[len(userEvents)-1].Type)
+
+	_, err = s.userService.RestoreUser(s.ctx, user.ID())
+	s.Require().ErrorIs(err, entities.ErrUserNotFound)
+
+	s.Require().NoError(s.userService.DeleteUser(s.ctx, user.ID()))
+
+	purged, err := s.userService.PurgeDeletedUsers(s.ctx, time.Hour)
+	s.Require().NoError(err)
+	s.Zero(purged)
+
+	purged, err = s.userService.PurgeDeletedUsers(s.ctx, 0)
+	s.Require().NoError(err)
+	s.Equal(int64(1), purged)
+}
+
 // Test suite runner.
 func TestUserServiceIntegrationSuite(t *testing.T) {
 	suite.Run(t, new(UserServiceIntegrationTestSuite))
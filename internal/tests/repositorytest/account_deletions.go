@@ -0,0 +1,115 @@
+package repositorytest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runAccountDeletionRepositoryTests runs the pending account deletion contract.
+func runAccountDeletionRepositoryTests(t *testing.T, factory Factory) {
+	t.Helper()
+
+	deletionTests := []struct {
+		name string
+		run  func(t *testing.T, repo repositories.AccountDeletionRepository, userIDs []entities.UserID)
+	}{
+		{"CreateAndGet", testAccountDeletionCreateAndGet},
+		{"Delete", testAccountDeletionDelete},
+		{"ListDue", testAccountDeletionListDue},
+	}
+
+	for _, tt := range deletionTests {
+		t.Run("AccountDeletions/"+tt.name, func(t *testing.T) {
+			repos := factory(t)
+			if repos.AccountDeletions == nil {
+				t.Skip("no account deletion repository")
+			}
+
+			// Deletions reference stored users where the store enforces it.
+			userIDs := []entities.UserID{1, 2, 3}
+			if repos.Users != nil {
+				for i := range userIDs {
+					userIDs[i] = newUser(t, repos.Users, fmt.Sprintf("deleted%d", i)).ID()
+				}
+			}
+
+			tt.run(t, repos.AccountDeletions, userIDs)
+		})
+	}
+}
+
+func testAccountDeletionCreateAndGet(
+	t *testing.T,
+	repo repositories.AccountDeletionRepository,
+	userIDs []entities.UserID,
+) {
+	ctx := context.Background()
+	userID := userIDs[0]
+
+	_, err := repo.GetByUser(ctx, userID)
+	require.ErrorIs(t, err, entities.ErrAccountDeletionNotFound)
+
+	deletion := entities.NewAccountDeletion(userID, userIDs[1], time.Hour)
+	require.NoError(t, repo.Create(ctx, deletion))
+
+	got, err := repo.GetByUser(ctx, userID)
+	require.NoError(t, err)
+	assert.Equal(t, userID, got.UserID)
+	assert.Equal(t, userIDs[1], got.RequestedBy)
+	assert.WithinDuration(t, deletion.RequestedAt, got.RequestedAt, time.Millisecond)
+	assert.WithinDuration(t, deletion.ScheduledFor, got.ScheduledFor, time.Millisecond)
+
+	again := entities.NewAccountDeletion(userID, userID, time.Minute)
+	require.ErrorIs(t, repo.Create(ctx, again), entities.ErrAccountDeletionPending)
+}
+
+func testAccountDeletionDelete(t *testing.T, repo repositories.AccountDeletionRepository, userIDs []entities.UserID) {
+	ctx := context.Background()
+	userID := userIDs[0]
+
+	require.ErrorIs(t, repo.Delete(ctx, userID), entities.ErrAccountDeletionNotFound)
+
+	require.NoError(t, repo.Create(ctx, entities.NewAccountDeletion(userID, userID, time.Hour)))
+	require.NoError(t, repo.Delete(ctx, userID))
+
+	_, err := repo.GetByUser(ctx, userID)
+	require.ErrorIs(t, err, entities.ErrAccountDeletionNotFound)
+	require.ErrorIs(t, repo.Delete(ctx, userID), entities.ErrAccountDeletionNotFound)
+
+	// A deleted deletion can be requested again.
+	require.NoError(t, repo.Create(ctx, entities.NewAccountDeletion(userID, userID, time.Hour)))
+}
+
+func testAccountDeletionListDue(t *testing.T, repo repositories.AccountDeletionRepository, userIDs []entities.UserID) {
+	ctx := context.Background()
+
+	later := entities.NewAccountDeletion(userIDs[0], userIDs[0], 2*time.Minute)
+	earlier := entities.NewAccountDeletion(userIDs[1], userIDs[1], time.Minute)
+	distant := entities.NewAccountDeletion(userIDs[2], userIDs[2], 2*time.Hour)
+
+	for _, deletion := range []*entities.AccountDeletion{later, earlier, distant} {
+		require.NoError(t, repo.Create(ctx, deletion))
+	}
+
+	due, err := repo.ListDue(ctx, time.Now(), 10)
+	require.NoError(t, err)
+	assert.Empty(t, due)
+
+	due, err = repo.ListDue(ctx, time.Now().Add(time.Hour), 10)
+	require.NoError(t, err)
+	require.Len(t, due, 2)
+	assert.Equal(t, earlier.UserID, due[0].UserID)
+	assert.Equal(t, later.UserID, due[1].UserID)
+
+	due, err = repo.ListDue(ctx, time.Now().Add(time.Hour), 1)
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	assert.Equal(t, earlier.UserID, due[0].UserID)
+}
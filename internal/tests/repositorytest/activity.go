@@ -0,0 +1,156 @@
+package repositorytest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runActivityRepositoryTests runs the activity feed contract.
+func runActivityRepositoryTests(t *testing.T, factory Factory) {
+	t.Helper()
+
+	activityTests := []struct {
+		name string
+		run  func(t *testing.T, repo repositories.ActivityRepository, userID entities.UserID)
+	}{
+		{"Pages", testActivityPages},
+		{"Redelivery", testActivityRedelivery},
+		{"Isolation", testActivityIsolation},
+		{"DeleteOlderThan", testActivityDeleteOlderThan},
+		{"DeleteByUser", testActivityDeleteByUser},
+	}
+
+	for _, tt := range activityTests {
+		t.Run("Activity/"+tt.name, func(t *testing.T) {
+			repos := factory(t)
+			if repos.Activity == nil {
+				t.Skip("no activity repository")
+			}
+
+			// Entries reference a stored user where the store enforces it.
+			userID := entities.UserID(1)
+			if repos.Users != nil {
+				userID = newUser(t, repos.Users, "active").ID()
+			}
+
+			tt.run(t, repos.Activity, userID)
+		})
+	}
+}
+
+// recordActivity records an entry of eventID that occurred at.
+func recordActivity(
+	t *testing.T,
+	repo repositories.ActivityRepository,
+	userID entities.UserID,
+	eventID entities.IDID,
+	summary string,
+	at time.Time,
+) {
+	t.Helper()
+
+	entry, err := entities.NewActivityEntry(userID, eventID, "user.login", entities.ActivityKindSignIn, summary, 0, at)
+	require.NoError(t, err)
+	require.NoError(t, repo.Record(context.Background(), entry))
+}
+
+// activitySummaries returns the summaries of entries in order.
+func activitySummaries(entries []*entities.ActivityEntry) []string {
+	summaries := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		summaries = append(summaries, entry.Summary)
+	}
+
+	return summaries
+}
+
+func testActivityPages(t *testing.T, repo repositories.ActivityRepository, userID entities.UserID) {
+	ctx := context.Background()
+	base := time.Now().Add(-time.Hour).Truncate(time.Second)
+
+	recordActivity(t, repo, userID, 1, "first", base)
+	recordActivity(t, repo, userID, 2, "second", base.Add(time.Minute))
+	// Entries of the same instant are ordered by ID.
+	recordActivity(t, repo, userID, 3, "third", base.Add(time.Minute))
+
+	page, err := repo.ListPage(ctx, userID, "", 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"third", "second"}, activitySummaries(page.Entries))
+	require.NotEmpty(t, page.NextCursor)
+
+	entry := page.Entries[0]
+	assert.NotZero(t, entry.ID)
+	assert.Equal(t, userID, entry.UserID)
+	assert.Equal(t, entities.IDID(3), entry.EventID)
+	assert.Equal(t, "user.login", entry.EventType)
+	assert.Equal(t, entities.ActivityKindSignIn, entry.Kind)
+	assert.True(t, base.Add(time.Minute).Equal(entry.OccurredAt), entry.OccurredAt)
+
+	page, err = repo.ListPage(ctx, userID, page.NextCursor, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"first"}, activitySummaries(page.Entries))
+	assert.Empty(t, page.NextCursor)
+
+	_, err = repo.ListPage(ctx, userID, "not a cursor", 2)
+	require.ErrorIs(t, err, entities.ErrInvalidCursor)
+
+	_, err = repo.ListPage(ctx, userID, "", 0)
+	require.Error(t, err)
+}
+
+func testActivityRedelivery(t *testing.T, repo repositories.ActivityRepository, userID entities.UserID) {
+	at := time.Now().Add(-time.Minute).Truncate(time.Second)
+
+	recordActivity(t, repo, userID, 7, "Signed in", at)
+	recordActivity(t, repo, userID, 7, "Signed in", at)
+
+	page, err := repo.ListPage(context.Background(), userID, "", 10)
+	require.NoError(t, err)
+	assert.Len(t, page.Entries, 1)
+}
+
+func testActivityIsolation(t *testing.T, repo repositories.ActivityRepository, userID entities.UserID) {
+	recordActivity(t, repo, userID, 1, "Signed in", time.Now().Truncate(time.Second))
+
+	page, err := repo.ListPage(context.Background(), userID+1000, "", 10)
+	require.NoError(t, err)
+	assert.Empty(t, page.Entries)
+}
+
+func testActivityDeleteOlderThan(t *testing.T, repo repositories.ActivityRepository, userID entities.UserID) {
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+
+	recordActivity(t, repo, userID, 1, "old", now.Add(-48*time.Hour))
+	recordActivity(t, repo, userID, 2, "recent", now.Add(-time.Hour))
+
+	deleted, err := repo.DeleteOlderThan(ctx, now.Add(-24*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+
+	page, err := repo.ListPage(ctx, userID, "", 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"recent"}, activitySummaries(page.Entries))
+}
+
+func testActivityDeleteByUser(t *testing.T, repo repositories.ActivityRepository, userID entities.UserID) {
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+
+	recordActivity(t, repo, userID, 1, "first", now.Add(-time.Hour))
+	recordActivity(t, repo, userID, 2, "second", now)
+
+	deleted, err := repo.DeleteByUser(ctx, userID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), deleted)
+
+	page, err := repo.ListPage(ctx, userID, "", 10)
+	require.NoError(t, err)
+	assert.Empty(t, page.Entries)
+}
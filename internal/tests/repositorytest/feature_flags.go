@@ -0,0 +1,77 @@
+package repositorytest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runFeatureFlagRepositoryTests runs the feature flag contract.
+func runFeatureFlagRepositoryTests(t *testing.T, factory Factory) {
+	t.Helper()
+
+	featureFlagTests := []struct {
+		name string
+		run  func(t *testing.T, repo repositories.FeatureFlagRepository)
+	}{
+		{"SetAndList", testFeatureFlagsSetAndList},
+		{"Overwrite", testFeatureFlagsOverwrite},
+	}
+
+	for _, tt := range featureFlagTests {
+		t.Run("FeatureFlags/"+tt.name, func(t *testing.T) {
+			repos := factory(t)
+			if repos.FeatureFlags == nil {
+				t.Skip("no feature flag repository")
+			}
+
+			tt.run(t, repos.FeatureFlags)
+		})
+	}
+}
+
+// setFlag stores the flag name as enabled at updatedAt.
+func setFlag(t *testing.T, repo repositories.FeatureFlagRepository, name string, enabled bool, updatedAt time.Time) {
+	t.Helper()
+
+	flag, err := entities.NewFeatureFlag(name, enabled, updatedAt)
+	require.NoError(t, err)
+	require.NoError(t, repo.Set(context.Background(), flag))
+}
+
+func testFeatureFlagsSetAndList(t *testing.T, repo repositories.FeatureFlagRepository) {
+	flags, err := repo.List(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, flags)
+
+	now := time.Now().Truncate(time.Second)
+	setFlag(t, repo, "maintenance.read_only", true, now)
+	setFlag(t, repo, "beta.search", false, now)
+
+	flags, err = repo.List(context.Background())
+	require.NoError(t, err)
+	require.Len(t, flags, 2)
+
+	assert.Equal(t, "beta.search", flags[0].Name, "flags are listed by name")
+	assert.False(t, flags[0].Enabled)
+	assert.Equal(t, "maintenance.read_only", flags[1].Name)
+	assert.True(t, flags[1].Enabled)
+	assert.WithinDuration(t, now, flags[1].UpdatedAt, time.Second)
+}
+
+func testFeatureFlagsOverwrite(t *testing.T, repo repositories.FeatureFlagRepository) {
+	start := time.Now().Add(-time.Hour).Truncate(time.Second)
+	setFlag(t, repo, "maintenance.login_disabled", true, start)
+	setFlag(t, repo, "maintenance.login_disabled", false, start.Add(time.Minute))
+
+	flags, err := repo.List(context.Background())
+	require.NoError(t, err)
+	require.Len(t, flags, 1)
+	assert.False(t, flags[0].Enabled)
+	assert.WithinDuration(t, start.Add(time.Minute), flags[0].UpdatedAt, time.Second)
+}
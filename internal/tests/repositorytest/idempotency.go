@@ -0,0 +1,126 @@
+package repositorytest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runIdempotencyRepositoryTests runs the idempotency key contract.
+func runIdempotencyRepositoryTests(t *testing.T, factory Factory) {
+	t.Helper()
+
+	idempotencyTests := []struct {
+		name string
+		run  func(t *testing.T, repo repositories.IdempotencyRepository)
+	}{
+		{"ReserveAndGet", testIdempotencyReserveAndGet},
+		{"Complete", testIdempotencyComplete},
+		{"Release", testIdempotencyRelease},
+		{"DeleteExpired", testIdempotencyDeleteExpired},
+	}
+
+	for _, tt := range idempotencyTests {
+		t.Run("Idempotency/"+tt.name, func(t *testing.T) {
+			repos := factory(t)
+			if repos.Idempotency == nil {
+				t.Skip("no idempotency repository")
+			}
+
+			tt.run(t, repos.Idempotency)
+		})
+	}
+}
+
+// reserveKey stores a pending record of key that is locked for lock.
+func reserveKey(
+	t *testing.T,
+	repo repositories.IdempotencyRepository,
+	key string,
+	lock time.Duration,
+) *entities.IdempotencyRecord {
+	t.Helper()
+
+	idempotencyKey, err := entities.NewIdempotencyKey(key)
+	require.NoError(t, err)
+
+	record := entities.NewIdempotencyRecord(idempotencyKey, "CreateUser", "hash-"+key, lock)
+	require.NoError(t, repo.Reserve(context.Background(), record))
+
+	return record
+}
+
+func testIdempotencyReserveAndGet(t *testing.T, repo repositories.IdempotencyRepository) {
+	ctx := context.Background()
+	record := reserveKey(t, repo, "key-1", time.Minute)
+
+	got, err := repo.Get(ctx, record.Key)
+	require.NoError(t, err)
+	assert.Equal(t, record.Key, got.Key)
+	assert.Equal(t, "CreateUser", got.Operation)
+	assert.Equal(t, "hash-key-1", got.RequestHash)
+	assert.Empty(t, got.Response)
+	assert.False(t, got.IsCompleted())
+	assert.WithinDuration(t, record.CreatedAt, got.CreatedAt, time.Millisecond)
+	assert.WithinDuration(t, record.ExpiresAt, got.ExpiresAt, time.Millisecond)
+
+	duplicate := entities.NewIdempotencyRecord(record.Key, "UpdateUser", "other", time.Minute)
+	require.ErrorIs(t, repo.Reserve(ctx, duplicate), entities.ErrIdempotencyKeyExists)
+
+	_, err = repo.Get(ctx, "missing")
+	require.ErrorIs(t, err, entities.ErrIdempotencyKeyNotFound)
+}
+
+func testIdempotencyComplete(t *testing.T, repo repositories.IdempotencyRepository) {
+	ctx := context.Background()
+	record := reserveKey(t, repo, "key-1", time.Minute)
+
+	record.Complete([]byte(`{"id":1}`), time.Hour)
+	require.NoError(t, repo.Complete(ctx, record))
+
+	got, err := repo.Get(ctx, record.Key)
+	require.NoError(t, err)
+	assert.True(t, got.IsCompleted())
+	assert.JSONEq(t, `{"id":1}`, string(got.Response))
+	assert.WithinDuration(t, *record.CompletedAt, *got.CompletedAt, time.Millisecond)
+	assert.WithinDuration(t, record.ExpiresAt, got.ExpiresAt, time.Millisecond)
+
+	// A completed record is neither completed again nor released.
+	require.ErrorIs(t, repo.Complete(ctx, record), entities.ErrIdempotencyKeyNotFound)
+	require.ErrorIs(t, repo.Release(ctx, record.Key), entities.ErrIdempotencyKeyNotFound)
+}
+
+func testIdempotencyRelease(t *testing.T, repo repositories.IdempotencyRepository) {
+	ctx := context.Background()
+	record := reserveKey(t, repo, "key-1", time.Minute)
+
+	require.NoError(t, repo.Release(ctx, record.Key))
+
+	_, err := repo.Get(ctx, record.Key)
+	require.ErrorIs(t, err, entities.ErrIdempotencyKeyNotFound)
+	require.ErrorIs(t, repo.Release(ctx, record.Key), entities.ErrIdempotencyKeyNotFound)
+
+	// A released key can be reserved again.
+	reserveKey(t, repo, "key-1", time.Minute)
+}
+
+func testIdempotencyDeleteExpired(t *testing.T, repo repositories.IdempotencyRepository) {
+	ctx := context.Background()
+	stale := reserveKey(t, repo, "stale", time.Minute)
+	fresh := reserveKey(t, repo, "fresh", time.Hour)
+
+	deleted, err := repo.DeleteExpired(ctx, time.Now().Add(2*time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+
+	_, err = repo.Get(ctx, stale.Key)
+	require.ErrorIs(t, err, entities.ErrIdempotencyKeyNotFound)
+
+	_, err = repo.Get(ctx, fresh.Key)
+	require.NoError(t, err)
+}
@@ -0,0 +1,151 @@
+package repositorytest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runIdentityChangeRepositoryTests runs the identity change contract.
+func runIdentityChangeRepositoryTests(t *testing.T, factory Factory) {
+	t.Helper()
+
+	identityChangeTests := []struct {
+		name string
+		run  func(t *testing.T, repo repositories.IdentityChangeRepository, userID entities.UserID)
+	}{
+		{"CreateAndGet", testIdentityChangeCreateAndGet},
+		{"Update", testIdentityChangeUpdate},
+		{"ListByUser", testIdentityChangeListByUser},
+		{"DeleteByUser", testIdentityChangeDeleteByUser},
+	}
+
+	for _, tt := range identityChangeTests {
+		t.Run("IdentityChanges/"+tt.name, func(t *testing.T) {
+			repos := factory(t)
+			if repos.IdentityChanges == nil {
+				t.Skip("no identity change repository")
+			}
+
+			// Changes reference a stored user where the store enforces it.
+			userID := entities.UserID(1)
+			if repos.Users != nil {
+				userID = newUser(t, repos.Users, "changer").ID()
+			}
+
+			tt.run(t, repos.IdentityChanges, userID)
+		})
+	}
+}
+
+// newEmailChange stores a pending change of userID's email to changed.
+func newEmailChange(
+	t *testing.T,
+	repo repositories.IdentityChangeRepository,
+	userID entities.UserID,
+	changed string,
+) *entities.IdentityChange {
+	t.Helper()
+
+	change, err := entities.NewEmailChange(userID, entities.Email("old@example.com"), entities.Email(changed),
+		entities.EmailChangeLifetime)
+	require.NoError(t, err)
+	require.NoError(t, repo.Create(context.Background(), change))
+
+	return change
+}
+
+func testIdentityChangeCreateAndGet(
+	t *testing.T,
+	repo repositories.IdentityChangeRepository,
+	userID entities.UserID,
+) {
+	ctx := context.Background()
+	change := newEmailChange(t, repo, userID, "new@example.com")
+	assert.NotZero(t, change.ID)
+
+	got, err := repo.GetByToken(ctx, change.Token)
+	require.NoError(t, err)
+	assert.Equal(t, change.ID, got.ID)
+	assert.Equal(t, userID, got.UserID)
+	assert.Equal(t, entities.IdentityFieldEmail, got.Field)
+	assert.Equal(t, "old@example.com", got.OldValue)
+	assert.Equal(t, "new@example.com", got.NewValue)
+	assert.Equal(t, entities.IdentityChangePending, got.Status)
+	assert.WithinDuration(t, change.ExpiresAt, got.ExpiresAt, time.Millisecond)
+
+	_, err = repo.GetByToken(ctx, entities.NewIdentityChangeToken())
+	require.ErrorIs(t, err, entities.ErrIdentityChangeNotFound)
+}
+
+func testIdentityChangeUpdate(t *testing.T, repo repositories.IdentityChangeRepository, userID entities.UserID) {
+	ctx := context.Background()
+	change := newEmailChange(t, repo, userID, "new@example.com")
+	pendingToken := change.Token
+
+	require.NoError(t, change.Apply(entities.IdentityChangeGracePeriod))
+	require.NoError(t, repo.Update(ctx, change))
+
+	// Applying issues a new token, so the confirmation token is spent.
+	_, err := repo.GetByToken(ctx, pendingToken)
+	require.ErrorIs(t, err, entities.ErrIdentityChangeNotFound)
+
+	got, err := repo.GetByToken(ctx, change.Token)
+	require.NoError(t, err)
+	assert.Equal(t, entities.IdentityChangeApplied, got.Status)
+	assert.WithinDuration(t, change.ExpiresAt, got.ExpiresAt, time.Millisecond)
+
+	change.ID += 1000
+	require.ErrorIs(t, repo.Update(ctx, change), entities.ErrIdentityChangeNotFound)
+}
+
+func testIdentityChangeListByUser(
+	t *testing.T,
+	repo repositories.IdentityChangeRepository,
+	userID entities.UserID,
+) {
+	ctx := context.Background()
+
+	changes, err := repo.ListByUser(ctx, userID)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+
+	first := newEmailChange(t, repo, userID, "first@example.com")
+	second := newEmailChange(t, repo, userID, "second@example.com")
+
+	changes, err = repo.ListByUser(ctx, userID)
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	assert.Equal(t, second.ID, changes[0].ID)
+	assert.Equal(t, first.ID, changes[1].ID)
+
+	others, err := repo.ListByUser(ctx, userID+1000)
+	require.NoError(t, err)
+	assert.Empty(t, others)
+}
+
+func testIdentityChangeDeleteByUser(
+	t *testing.T,
+	repo repositories.IdentityChangeRepository,
+	userID entities.UserID,
+) {
+	ctx := context.Background()
+	change := newEmailChange(t, repo, userID, "first@example.com")
+	newEmailChange(t, repo, userID, "second@example.com")
+
+	deleted, err := repo.DeleteByUser(ctx, userID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), deleted)
+
+	changes, err := repo.ListByUser(ctx, userID)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+
+	_, err = repo.GetByToken(ctx, change.Token)
+	require.ErrorIs(t, err, entities.ErrIdentityChangeNotFound)
+}
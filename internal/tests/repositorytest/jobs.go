@@ -0,0 +1,216 @@
+package repositorytest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runJobRepositoryTests runs the job queue contract.
+func runJobRepositoryTests(t *testing.T, factory Factory) {
+	t.Helper()
+
+	jobTests := []struct {
+		name string
+		run  func(t *testing.T, repo repositories.JobRepository)
+	}{
+		{"EnqueueAndGet", testJobEnqueueAndGet},
+		{"ClaimDue", testJobClaimDue},
+		{"LeaseExpiry", testJobLeaseExpiry},
+		{"Finish", testJobFinish},
+		{"DeleteDone", testJobDeleteDone},
+	}
+
+	for _, tt := range jobTests {
+		t.Run("Jobs/"+tt.name, func(t *testing.T) {
+			repos := factory(t)
+			if repos.Jobs == nil {
+				t.Skip("no job repository")
+			}
+
+			tt.run(t, repos.Jobs)
+		})
+	}
+}
+
+// enqueueJob stores a job named name due at runAt.
+func enqueueJob(t *testing.T, repo repositories.JobRepository, name string, runAt time.Time) *entities.Job {
+	t.Helper()
+
+	job, err := entities.NewJob(name, []byte(`{"name":"`+name+`"}`), runAt, 3)
+	require.NoError(t, err)
+	require.NoError(t, repo.Enqueue(context.Background(), job))
+	require.NotZero(t, job.ID)
+
+	return job
+}
+
+func jobIDs(jobs []*entities.Job) []entities.JobID {
+	ids := make([]entities.JobID, 0, len(jobs))
+	for _, job := range jobs {
+		ids = append(ids, job.ID)
+	}
+
+	return ids
+}
+
+func testJobEnqueueAndGet(t *testing.T, repo repositories.JobRepository) {
+	ctx := context.Background()
+	runAt := time.Now().UTC().Add(time.Hour).Truncate(time.Millisecond)
+	job := enqueueJob(t, repo, "email.send", runAt)
+
+	got, err := repo.Get(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, job.ID, got.ID)
+	assert.Equal(t, "email.send", got.Name)
+	assert.JSONEq(t, `{"name":"email.send"}`, string(got.Payload))
+	assert.Equal(t, entities.JobStatusPending, got.Status)
+	assert.Zero(t, got.Attempts)
+	assert.Equal(t, 3, got.MaxAttempts)
+	assert.WithinDuration(t, runAt, got.RunAt, time.Millisecond)
+	assert.Nil(t, got.LockedUntil)
+	assert.Empty(t, got.LastError)
+
+	_, err = repo.Get(ctx, job.ID+1000)
+	require.ErrorIs(t, err, entities.ErrJobNotFound)
+}
+
+func testJobClaimDue(t *testing.T, repo repositories.JobRepository) {
+	ctx := context.Background()
+	now := time.Now().UTC()
+	second := enqueueJob(t, repo, "second", now.Add(-time.Minute))
+	first := enqueueJob(t, repo, "first", now.Add(-2*time.Minute))
+	enqueueJob(t, repo, "later", now.Add(time.Hour))
+
+	claimed, err := repo.Claim(ctx, now, time.Minute, 1)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+	assert.Equal(t, first.ID, claimed[0].ID)
+	assert.Equal(t, entities.JobStatusRunning, claimed[0].Status)
+	assert.Equal(t, 1, claimed[0].Attempts)
+	require.NotNil(t, claimed[0].LockedUntil)
+	assert.WithinDuration(t, now.Add(time.Minute), *claimed[0].LockedUntil, time.Millisecond)
+
+	claimed, err = repo.Claim(ctx, now, time.Minute, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []entities.JobID{second.ID}, jobIDs(claimed))
+
+	claimed, err = repo.Claim(ctx, now, time.Minute, 10)
+	require.NoError(t, err)
+	assert.Empty(t, claimed)
+
+	stored, err := repo.Get(ctx, first.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entities.JobStatusRunning, stored.Status)
+	assert.Equal(t, 1, stored.Attempts)
+}
+
+func testJobLeaseExpiry(t *testing.T, repo repositories.JobRepository) {
+	ctx := context.Background()
+	now := time.Now().UTC()
+	job := enqueueJob(t, repo, "slow", now.Add(-time.Minute))
+
+	claimed, err := repo.Claim(ctx, now, time.Minute, 1)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+
+	stale := claimed[0]
+
+	claimed, err = repo.Claim(ctx, now.Add(2*time.Minute), time.Minute, 1)
+	require.NoError(t, err)
+	require.Equal(t, []entities.JobID{job.ID}, jobIDs(claimed))
+	assert.Equal(t, 2, claimed[0].Attempts)
+
+	// The first worker's lease expired, so it may no longer finish the job.
+	require.ErrorIs(t, repo.Complete(ctx, stale), entities.ErrJobLeaseLost)
+	require.ErrorIs(t, repo.Retry(ctx, stale, now, "late"), entities.ErrJobLeaseLost)
+	require.ErrorIs(t, repo.Fail(ctx, stale, "late"), entities.ErrJobLeaseLost)
+
+	require.NoError(t, repo.Complete(ctx, claimed[0]))
+	assert.Equal(t, entities.JobStatusDone, claimed[0].Status)
+
+	stored, err := repo.Get(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entities.JobStatusDone, stored.Status)
+	assert.Nil(t, stored.LockedUntil)
+}
+
+func testJobFinish(t *testing.T, repo repositories.JobRepository) {
+	ctx := context.Background()
+	now := time.Now().UTC()
+	job := enqueueJob(t, repo, "flaky", now.Add(-time.Minute))
+
+	claimed, err := repo.Claim(ctx, now, time.Minute, 1)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+
+	retryAt := now.Add(time.Hour)
+	require.NoError(t, repo.Retry(ctx, claimed[0], retryAt, "boom"))
+	assert.Equal(t, entities.JobStatusPending, claimed[0].Status)
+
+	stored, err := repo.Get(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entities.JobStatusPending, stored.Status)
+	assert.Equal(t, 1, stored.Attempts)
+	assert.Equal(t, "boom", stored.LastError)
+	assert.WithinDuration(t, retryAt, stored.RunAt, time.Millisecond)
+	assert.Nil(t, stored.LockedUntil)
+
+	// A finished claim cannot be finished twice.
+	require.ErrorIs(t, repo.Complete(ctx, claimed[0]), entities.ErrJobLeaseLost)
+
+	claimed, err = repo.Claim(ctx, now, time.Minute, 1)
+	require.NoError(t, err)
+	assert.Empty(t, claimed, "the retry is not due yet")
+
+	claimed, err = repo.Claim(ctx, retryAt.Add(time.Second), time.Minute, 1)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+	assert.Equal(t, 2, claimed[0].Attempts)
+
+	require.NoError(t, repo.Fail(ctx, claimed[0], "gave up"))
+
+	stored, err = repo.Get(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entities.JobStatusFailed, stored.Status)
+	assert.Equal(t, "gave up", stored.LastError)
+
+	claimed, err = repo.Claim(ctx, retryAt.Add(time.Hour), time.Minute, 1)
+	require.NoError(t, err)
+	assert.Empty(t, claimed, "failed jobs are not claimed")
+}
+
+func testJobDeleteDone(t *testing.T, repo repositories.JobRepository) {
+	ctx := context.Background()
+	now := time.Now().UTC()
+	done := enqueueJob(t, repo, "done", now.Add(-2*time.Minute))
+	failed := enqueueJob(t, repo, "failed", now.Add(-time.Minute))
+	pending := enqueueJob(t, repo, "pending", now.Add(time.Hour))
+
+	claimed, err := repo.Claim(ctx, now, time.Minute, 2)
+	require.NoError(t, err)
+	require.Equal(t, []entities.JobID{done.ID, failed.ID}, jobIDs(claimed))
+	require.NoError(t, repo.Complete(ctx, claimed[0]))
+	require.NoError(t, repo.Fail(ctx, claimed[1], "boom"))
+
+	deleted, err := repo.DeleteDone(ctx, now.Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Zero(t, deleted)
+
+	deleted, err = repo.DeleteDone(ctx, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+
+	_, err = repo.Get(ctx, done.ID)
+	require.ErrorIs(t, err, entities.ErrJobNotFound)
+
+	for _, id := range []entities.JobID{failed.ID, pending.ID} {
+		_, err = repo.Get(ctx, id)
+		require.NoError(t, err)
+	}
+}
@@ -0,0 +1,152 @@
+package repositorytest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runLoginHistoryRepositoryTests runs the login history contract.
+func runLoginHistoryRepositoryTests(t *testing.T, factory Factory) {
+	t.Helper()
+
+	loginHistoryTests := []struct {
+		name string
+		run  func(t *testing.T, repo repositories.LoginHistoryRepository, userID entities.UserID)
+	}{
+		{"ListByUser", testLoginHistoryListByUser},
+		{"ListFailuresByIP", testLoginHistoryListFailuresByIP},
+		{"InvalidLimit", testLoginHistoryInvalidLimit},
+		{"DeleteByUser", testLoginHistoryDeleteByUser},
+	}
+
+	for _, tt := range loginHistoryTests {
+		t.Run("LoginHistory/"+tt.name, func(t *testing.T) {
+			repos := factory(t)
+			if repos.LoginHistory == nil {
+				t.Skip("no login history repository")
+			}
+
+			// Attempts reference a stored user where the store enforces it.
+			userID := entities.UserID(1)
+			if repos.Users != nil {
+				userID = newUser(t, repos.Users, "active").ID()
+			}
+
+			tt.run(t, repos.LoginHistory, userID)
+		})
+	}
+}
+
+// recordLogin records an attempt of userID from ipAddress that happened at.
+func recordLogin(
+	t *testing.T,
+	repo repositories.LoginHistoryRepository,
+	userID entities.UserID,
+	ipAddress string,
+	failure entities.LoginFailure,
+	at time.Time,
+) *entities.LoginAttempt {
+	t.Helper()
+
+	attempt := entities.NewLoginAttempt(userID, ipAddress, "agent", entities.LoginMethodPassword, failure)
+	attempt.CreatedAt = at
+	require.NoError(t, repo.Record(context.Background(), attempt))
+	require.NotZero(t, attempt.ID)
+
+	return attempt
+}
+
+// loginIDs returns the IDs of attempts in order.
+func loginIDs(attempts []*entities.LoginAttempt) []entities.LoginAttemptID {
+	ids := make([]entities.LoginAttemptID, 0, len(attempts))
+	for _, attempt := range attempts {
+		ids = append(ids, attempt.ID)
+	}
+
+	return ids
+}
+
+func testLoginHistoryListByUser(t *testing.T, repo repositories.LoginHistoryRepository, userID entities.UserID) {
+	ctx := context.Background()
+	base := time.Now().Add(-time.Hour).Truncate(time.Second)
+
+	first := recordLogin(t, repo, userID, "192.0.2.1", entities.LoginFailureNone, base)
+	failed := recordLogin(t, repo, userID, "192.0.2.1", entities.LoginFailureInactiveAccount, base.Add(time.Minute))
+	// Attempts of the same instant are ordered by ID.
+	last := recordLogin(t, repo, userID, "192.0.2.2", entities.LoginFailureNone, base.Add(time.Minute))
+	recordLogin(t, repo, 0, "192.0.2.1", entities.LoginFailureInvalidCredentials, base.Add(time.Minute))
+
+	attempts, err := repo.ListByUser(ctx, userID, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []entities.LoginAttemptID{last.ID, failed.ID, first.ID}, loginIDs(attempts))
+
+	attempt := attempts[1]
+	assert.Equal(t, userID, attempt.UserID)
+	assert.Equal(t, "192.0.2.1", attempt.IPAddress)
+	assert.Equal(t, "agent", attempt.UserAgent)
+	assert.Equal(t, entities.LoginMethodPassword, attempt.Method)
+	assert.Equal(t, entities.LoginFailureInactiveAccount, attempt.Failure)
+	assert.True(t, base.Add(time.Minute).Equal(attempt.CreatedAt), attempt.CreatedAt)
+
+	attempts, err = repo.ListByUser(ctx, userID, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []entities.LoginAttemptID{last.ID, failed.ID}, loginIDs(attempts))
+
+	attempts, err = repo.ListByUser(ctx, userID+1000, 10)
+	require.NoError(t, err)
+	assert.Empty(t, attempts)
+}
+
+func testLoginHistoryListFailuresByIP(t *testing.T, repo repositories.LoginHistoryRepository, userID entities.UserID) {
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+
+	recordLogin(t, repo, userID, "192.0.2.1", entities.LoginFailureInvalidCredentials, now.Add(-2*time.Hour))
+	recordLogin(t, repo, userID, "192.0.2.1", entities.LoginFailureNone, now.Add(-time.Minute))
+	recordLogin(t, repo, userID, "192.0.2.2", entities.LoginFailureInvalidCredentials, now.Add(-time.Minute))
+	inactive := recordLogin(t, repo, userID, "192.0.2.1", entities.LoginFailureInactiveAccount, now.Add(-time.Minute))
+	unknown := recordLogin(t, repo, 0, "192.0.2.1", entities.LoginFailureRateLimited, now)
+
+	attempts, err := repo.ListFailuresByIP(ctx, "192.0.2.1", now.Add(-time.Hour), 10)
+	require.NoError(t, err)
+	assert.Equal(t, []entities.LoginAttemptID{unknown.ID, inactive.ID}, loginIDs(attempts))
+	assert.Zero(t, attempts[0].UserID, "failures of unknown accounts")
+}
+
+func testLoginHistoryInvalidLimit(t *testing.T, repo repositories.LoginHistoryRepository, userID entities.UserID) {
+	ctx := context.Background()
+
+	_, err := repo.ListByUser(ctx, userID, 0)
+	require.Error(t, err)
+
+	_, err = repo.ListFailuresByIP(ctx, "192.0.2.1", time.Now().Add(-time.Hour), 0)
+	require.Error(t, err)
+}
+
+func testLoginHistoryDeleteByUser(t *testing.T, repo repositories.LoginHistoryRepository, userID entities.UserID) {
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+
+	recordLogin(t, repo, userID, "192.0.2.1", entities.LoginFailureNone, now)
+	recordLogin(t, repo, userID, "192.0.2.1", entities.LoginFailureInactiveAccount, now)
+	unknown := recordLogin(t, repo, 0, "192.0.2.1", entities.LoginFailureInvalidCredentials, now)
+
+	deleted, err := repo.DeleteByUser(ctx, userID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), deleted)
+
+	attempts, err := repo.ListByUser(ctx, userID, 10)
+	require.NoError(t, err)
+	assert.Empty(t, attempts)
+
+	// Attempts of unknown accounts are kept.
+	attempts, err = repo.ListFailuresByIP(ctx, "192.0.2.1", now.Add(-time.Hour), 10)
+	require.NoError(t, err)
+	assert.Equal(t, []entities.LoginAttemptID{unknown.ID}, loginIDs(attempts))
+}
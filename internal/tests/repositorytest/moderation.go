@@ -0,0 +1,278 @@
+package repositorytest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runModerationRepositoryTests runs the moderation contract.
+func runModerationRepositoryTests(t *testing.T, factory Factory) {
+	t.Helper()
+
+	moderationTests := []struct {
+		name string
+		run  func(t *testing.T, repo repositories.ModerationRepository, userID entities.UserID)
+	}{
+		{"CreateAndGet", testModerationCreateAndGet},
+		{"Lift", testModerationLift},
+		{"ActiveSuspension", testModerationActiveSuspension},
+		{"ListLapsed", testModerationListLapsed},
+		{"Appeals", testModerationAppeals},
+		{"PendingAppeals", testModerationPendingAppeals},
+		{"DeleteByUser", testModerationDeleteByUser},
+	}
+
+	for _, tt := range moderationTests {
+		t.Run("Moderation/"+tt.name, func(t *testing.T) {
+			repos := factory(t)
+			if repos.Moderation == nil {
+				t.Skip("no moderation repository")
+			}
+
+			// Actions reference a stored user where the store enforces it.
+			userID := entities.UserID(1)
+			if repos.Users != nil {
+				userID = newUser(t, repos.Users, "moderated").ID()
+			}
+
+			tt.run(t, repos.Moderation, userID)
+		})
+	}
+}
+
+// newSuspension stores a suspension of userID lasting duration.
+func newSuspension(
+	t *testing.T,
+	repo repositories.ModerationRepository,
+	userID entities.UserID,
+	duration time.Duration,
+) *entities.ModerationAction {
+	t.Helper()
+
+	action, err := entities.NewSuspension(userID, 0, "spam", duration)
+	require.NoError(t, err)
+	require.NoError(t, repo.CreateAction(context.Background(), action))
+
+	return action
+}
+
+func testModerationCreateAndGet(t *testing.T, repo repositories.ModerationRepository, userID entities.UserID) {
+	ctx := context.Background()
+
+	flag, err := entities.NewFlag(userID, 0, "suspicious sign-ups")
+	require.NoError(t, err)
+	require.NoError(t, repo.CreateAction(ctx, flag))
+	assert.NotZero(t, flag.ID)
+
+	got, err := repo.GetAction(ctx, flag.ID)
+	require.NoError(t, err)
+	assert.Equal(t, userID, got.UserID)
+	assert.Equal(t, entities.ModerationFlag, got.Kind)
+	assert.Equal(t, "suspicious sign-ups", got.Reason)
+	assert.Nil(t, got.EndsAt)
+	assert.True(t, got.IsActive())
+
+	suspension := newSuspension(t, repo, userID, time.Hour)
+
+	got, err = repo.GetAction(ctx, suspension.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entities.ModerationSuspension, got.Kind)
+	require.NotNil(t, got.EndsAt)
+	assert.WithinDuration(t, *suspension.EndsAt, *got.EndsAt, time.Millisecond)
+
+	actions, err := repo.ListActionsByUser(ctx, userID)
+	require.NoError(t, err)
+	require.Len(t, actions, 2)
+	assert.Equal(t, suspension.ID, actions[0].ID)
+	assert.Equal(t, flag.ID, actions[1].ID)
+
+	_, err = repo.GetAction(ctx, suspension.ID+1000)
+	require.ErrorIs(t, err, entities.ErrModerationActionNotFound)
+}
+
+func testModerationLift(t *testing.T, repo repositories.ModerationRepository, userID entities.UserID) {
+	ctx := context.Background()
+	action := newSuspension(t, repo, userID, 0)
+
+	require.NoError(t, action.Lift(userID))
+	require.NoError(t, repo.LiftAction(ctx, action))
+
+	got, err := repo.GetAction(ctx, action.ID)
+	require.NoError(t, err)
+	assert.False(t, got.IsActive())
+	assert.Equal(t, userID, got.LiftedBy)
+	assert.WithinDuration(t, *action.LiftedAt, *got.LiftedAt, time.Millisecond)
+
+	// Lifting twice is reported like lifting an unknown action.
+	require.ErrorIs(t, repo.LiftAction(ctx, action), entities.ErrModerationActionNotFound)
+
+	action.ID += 1000
+	require.ErrorIs(t, repo.LiftAction(ctx, action), entities.ErrModerationActionNotFound)
+}
+
+func testModerationActiveSuspension(t *testing.T, repo repositories.ModerationRepository, userID entities.UserID) {
+	ctx := context.Background()
+
+	_, err := repo.GetActiveSuspension(ctx, userID)
+	require.ErrorIs(t, err, entities.ErrNotSuspended)
+
+	flag, err := entities.NewFlag(userID, 0, "spam")
+	require.NoError(t, err)
+	require.NoError(t, repo.CreateAction(ctx, flag))
+
+	_, err = repo.GetActiveSuspension(ctx, userID)
+	require.ErrorIs(t, err, entities.ErrNotSuspended)
+
+	action := newSuspension(t, repo, userID, 0)
+
+	got, err := repo.GetActiveSuspension(ctx, userID)
+	require.NoError(t, err)
+	assert.Equal(t, action.ID, got.ID)
+
+	require.NoError(t, action.Lift(0))
+	require.NoError(t, repo.LiftAction(ctx, action))
+
+	_, err = repo.GetActiveSuspension(ctx, userID)
+	require.ErrorIs(t, err, entities.ErrNotSuspended)
+}
+
+func testModerationListLapsed(t *testing.T, repo repositories.ModerationRepository, userID entities.UserID) {
+	ctx := context.Background()
+
+	later := newSuspension(t, repo, userID, 2*time.Minute)
+	earlier := newSuspension(t, repo, userID, time.Minute)
+	newSuspension(t, repo, userID, 0)
+
+	lifted := newSuspension(t, repo, userID, time.Minute)
+	require.NoError(t, lifted.Lift(0))
+	require.NoError(t, repo.LiftAction(ctx, lifted))
+
+	lapsed, err := repo.ListLapsedSuspensions(ctx, time.Now(), 10)
+	require.NoError(t, err)
+	assert.Empty(t, lapsed)
+
+	lapsed, err = repo.ListLapsedSuspensions(ctx, time.Now().Add(time.Hour), 10)
+	require.NoError(t, err)
+	require.Len(t, lapsed, 2)
+	assert.Equal(t, earlier.ID, lapsed[0].ID)
+	assert.Equal(t, later.ID, lapsed[1].ID)
+
+	lapsed, err = repo.ListLapsedSuspensions(ctx, time.Now().Add(time.Hour), 1)
+	require.NoError(t, err)
+	require.Len(t, lapsed, 1)
+	assert.Equal(t, earlier.ID, lapsed[0].ID)
+}
+
+func testModerationAppeals(t *testing.T, repo repositories.ModerationRepository, userID entities.UserID) {
+	ctx := context.Background()
+	action := newSuspension(t, repo, userID, 0)
+
+	appeal, err := entities.NewAppeal(action, "It was a misunderstanding.")
+	require.NoError(t, err)
+	require.NoError(t, repo.CreateAppeal(ctx, appeal))
+	assert.NotZero(t, appeal.ID)
+
+	again, err := entities.NewAppeal(action, "Please reconsider.")
+	require.NoError(t, err)
+	require.ErrorIs(t, repo.CreateAppeal(ctx, again), entities.ErrAppealExists)
+
+	got, err := repo.GetAppeal(ctx, appeal.ID)
+	require.NoError(t, err)
+	assert.Equal(t, action.ID, got.ActionID)
+	assert.Equal(t, userID, got.UserID)
+	assert.Equal(t, "It was a misunderstanding.", got.Statement)
+	assert.Equal(t, entities.AppealPending, got.Status)
+	assert.Empty(t, got.Response)
+	assert.Nil(t, got.DecidedAt)
+
+	require.NoError(t, appeal.Decide(userID, false, "The suspension stands."))
+	require.NoError(t, repo.DecideAppeal(ctx, appeal))
+
+	got, err = repo.GetAppeal(ctx, appeal.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entities.AppealDenied, got.Status)
+	assert.Equal(t, userID, got.ReviewerID)
+	assert.Equal(t, "The suspension stands.", got.Response)
+	require.NotNil(t, got.DecidedAt)
+	assert.WithinDuration(t, *appeal.DecidedAt, *got.DecidedAt, time.Millisecond)
+
+	// Deciding twice is reported like deciding an unknown appeal.
+	require.ErrorIs(t, repo.DecideAppeal(ctx, appeal), entities.ErrAppealNotFound)
+
+	_, err = repo.GetAppeal(ctx, appeal.ID+1000)
+	require.ErrorIs(t, err, entities.ErrAppealNotFound)
+}
+
+func testModerationPendingAppeals(t *testing.T, repo repositories.ModerationRepository, userID entities.UserID) {
+	ctx := context.Background()
+
+	pending, err := repo.ListPendingAppeals(ctx, 10)
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+
+	appeals := make([]*entities.Appeal, 3)
+
+	for i := range appeals {
+		appeal, err := entities.NewAppeal(newSuspension(t, repo, userID, 0), "Please reconsider.")
+		require.NoError(t, err)
+		require.NoError(t, repo.CreateAppeal(ctx, appeal))
+
+		appeals[i] = appeal
+	}
+
+	require.NoError(t, appeals[1].Decide(0, true, ""))
+	require.NoError(t, repo.DecideAppeal(ctx, appeals[1]))
+
+	pending, err = repo.ListPendingAppeals(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, pending, 2)
+	assert.Equal(t, appeals[0].ID, pending[0].ID)
+	assert.Equal(t, appeals[2].ID, pending[1].ID)
+
+	pending, err = repo.ListPendingAppeals(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, appeals[0].ID, pending[0].ID)
+}
+
+func testModerationDeleteByUser(t *testing.T, repo repositories.ModerationRepository, userID entities.UserID) {
+	ctx := context.Background()
+	first := newSuspension(t, repo, userID, 0)
+	second := newSuspension(t, repo, userID, time.Hour)
+
+	older, err := entities.NewAppeal(first, "It was a misunderstanding.")
+	require.NoError(t, err)
+	require.NoError(t, repo.CreateAppeal(ctx, older))
+
+	newer, err := entities.NewAppeal(second, "Please reconsider.")
+	require.NoError(t, err)
+	require.NoError(t, repo.CreateAppeal(ctx, newer))
+
+	appeals, err := repo.ListAppealsByUser(ctx, userID)
+	require.NoError(t, err)
+	require.Len(t, appeals, 2)
+	assert.Equal(t, newer.ID, appeals[0].ID)
+	assert.Equal(t, older.ID, appeals[1].ID)
+
+	deleted, err := repo.DeleteByUser(ctx, userID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), deleted)
+
+	actions, err := repo.ListActionsByUser(ctx, userID)
+	require.NoError(t, err)
+	assert.Empty(t, actions)
+
+	// The appeals of the actions are deleted with them.
+	appeals, err = repo.ListAppealsByUser(ctx, userID)
+	require.NoError(t, err)
+	assert.Empty(t, appeals)
+
+	_, err = repo.GetAppeal(ctx, older.ID)
+	require.ErrorIs(t, err, entities.ErrAppealNotFound)
+}
@@ -0,0 +1,114 @@
+package repositorytest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runNotificationRepositoryTests runs the notification preference contract.
+func runNotificationRepositoryTests(t *testing.T, factory Factory) {
+	t.Helper()
+
+	notificationTests := []struct {
+		name string
+		run  func(t *testing.T, repo repositories.NotificationPreferenceRepository, userID entities.UserID)
+	}{
+		{"UpsertAndList", testNotificationUpsertAndList},
+		{"Delete", testNotificationDelete},
+	}
+
+	for _, tt := range notificationTests {
+		t.Run("Notifications/"+tt.name, func(t *testing.T) {
+			repos := factory(t)
+			if repos.Notifications == nil {
+				t.Skip("no notification preference repository")
+			}
+
+			// Preferences reference a stored user where the store enforces it.
+			userID := entities.UserID(1)
+			if repos.Users != nil {
+				userID = newUser(t, repos.Users, "subscriber").ID()
+			}
+
+			tt.run(t, repos.Notifications, userID)
+		})
+	}
+}
+
+// setPreference stores a preference of userID.
+func setPreference(
+	t *testing.T,
+	repo repositories.NotificationPreferenceRepository,
+	userID entities.UserID,
+	channel entities.NotificationChannel,
+	topic entities.NotificationTopic,
+	enabled bool,
+	target string,
+) *entities.NotificationPreference {
+	t.Helper()
+
+	pref, err := entities.NewNotificationPreference(userID, channel, topic, enabled, target)
+	require.NoError(t, err)
+	require.NoError(t, repo.Upsert(context.Background(), pref))
+
+	return pref
+}
+
+func testNotificationUpsertAndList(
+	t *testing.T,
+	repo repositories.NotificationPreferenceRepository,
+	userID entities.UserID,
+) {
+	ctx := context.Background()
+
+	prefs, err := repo.ListByUser(ctx, userID)
+	require.NoError(t, err)
+	assert.Empty(t, prefs)
+
+	setPreference(t, repo, userID, entities.NotificationChannelWebhook, entities.NotificationTopicSecurity,
+		true, "https://hooks.example.com/a")
+	setPreference(t, repo, userID, entities.NotificationChannelEmail, entities.NotificationTopicAccount, false, "")
+	// Upserting again replaces the preference of the channel and topic.
+	updated := setPreference(t, repo, userID, entities.NotificationChannelWebhook, entities.NotificationTopicSecurity,
+		false, "https://hooks.example.com/b")
+
+	prefs, err = repo.ListByUser(ctx, userID)
+	require.NoError(t, err)
+	require.Len(t, prefs, 2)
+
+	assert.Equal(t, entities.NotificationChannelEmail, prefs[0].Channel)
+	assert.Equal(t, entities.NotificationTopicAccount, prefs[0].Topic)
+	assert.False(t, prefs[0].Enabled)
+
+	webhook := prefs[1]
+	assert.Equal(t, userID, webhook.UserID)
+	assert.Equal(t, entities.NotificationChannelWebhook, webhook.Channel)
+	assert.Equal(t, entities.NotificationTopicSecurity, webhook.Topic)
+	assert.False(t, webhook.Enabled)
+	assert.Equal(t, "https://hooks.example.com/b", webhook.Target)
+	assert.WithinDuration(t, updated.UpdatedAt, webhook.UpdatedAt, time.Millisecond)
+
+	others, err := repo.ListByUser(ctx, userID+1000)
+	require.NoError(t, err)
+	assert.Empty(t, others)
+}
+
+func testNotificationDelete(t *testing.T, repo repositories.NotificationPreferenceRepository, userID entities.UserID) {
+	ctx := context.Background()
+	setPreference(t, repo, userID, entities.NotificationChannelInApp, entities.NotificationTopicOrganization, false, "")
+
+	require.NoError(t, repo.Delete(ctx, userID, entities.NotificationChannelInApp, entities.NotificationTopicOrganization))
+
+	prefs, err := repo.ListByUser(ctx, userID)
+	require.NoError(t, err)
+	assert.Empty(t, prefs)
+
+	err = repo.Delete(ctx, userID, entities.NotificationChannelInApp, entities.NotificationTopicOrganization)
+	require.ErrorIs(t, err, entities.ErrNotificationPreferenceNotFound)
+}
@@ -0,0 +1,823 @@
+// Package repositorytest is a conformance suite for UserRepository,
+// SessionRepository, JobRepository, IdempotencyRepository,
+// NotificationPreferenceRepository, IdentityChangeRepository,
+// ModerationRepository, AccountDeletionRepository and
+// UserPreferenceRepository adapters. The
+// SQLite, PostgreSQL and MySQL adapters run it, and a new adapter
+// proves the same contract by running it too:
+//
+//	func TestUserRepositoryConformance(t *testing.T) {
+//		repositorytest.RunUserRepositoryTests(t, func(t *testing.T) repositorytest.Repositories {
+//			return repositorytest.Repositories{Users: myadapter.NewUserRepository(openDB(t))}
+//		})
+//	}
+//
+// The factory is called once per subtest and must return repositories over an
+// empty store. A nil repository skips its subtests.
+package repositorytest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+	"github.com/LarsArtmann/template-sqlc/pkg/fixtures"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Repositories are the adapters under test.
+type Repositories struct {
+	Users    repositories.UserRepository
+	Sessions repositories.SessionRepository
+	Jobs     repositories.JobRepository
+	// Idempotency is tested for the idempotency key contract.
+	Idempotency repositories.IdempotencyRepository
+	// Notifications is tested for the notification preference contract.
+	Notifications repositories.NotificationPreferenceRepository
+	// IdentityChanges is tested for the identity change history contract.
+	IdentityChanges repositories.IdentityChangeRepository
+	// Moderation is tested for the moderation action and appeal contract.
+	Moderation repositories.ModerationRepository
+	// AccountDeletions is tested for the pending account deletion contract.
+	AccountDeletions repositories.AccountDeletionRepository
+	// Preferences is tested for the user preference contract.
+	Preferences repositories.UserPreferenceRepository
+	// Activity is tested for the activity feed contract.
+	Activity repositories.ActivityRepository
+	// LoginHistory is tested for the login history contract.
+	LoginHistory repositories.LoginHistoryRepository
+	// UserEvents is tested for the user event store contract.
+	UserEvents repositories.UserEventStore
+	// UserReadModel is tested for the user read model contract, over the
+	// users of Users.
+	UserReadModel repositories.UserReadModelRepository
+	// FeatureFlags is tested for the feature flag contract.
+	FeatureFlags repositories.FeatureFlagRepository
+	// Transactions run the locking reads of the users of their
+	// transactions, over the store of Users.
+	Transactions repositories.TransactionalRepository
+}
+
+// Factory returns repositories over an empty store for one subtest.
+type Factory func(t *testing.T) Repositories
+
+// RunUserRepositoryTests runs the repository contract against the
+// repositories of factory.
+func RunUserRepositoryTests(t *testing.T, factory Factory) {
+	t.Helper()
+
+	userTests := []struct {
+		name string
+		run  func(t *testing.T, repo repositories.UserRepository)
+	}{
+		{"CreateAndGet", testCreateAndGet},
+		{"Duplicates", testDuplicates},
+		{"CanonicalUsernames", testCanonicalUsernames},
+		{"NotFound", testNotFound},
+		{"Update", testUpdate},
+		{"GetByIDs", testGetByIDs},
+		{"SoftDelete", testSoftDelete},
+		{"Batch", testBatch},
+		{"List", testList},
+		{"ListPage", testListPage},
+		{"SearchWithFacets", testSearchWithFacets},
+		{"Counts", testCounts},
+		{"Credentials", testCredentials},
+		{"StatusAndRole", testStatusAndRole},
+		{"Locking", testLocking},
+	}
+
+	for _, tt := range userTests {
+		t.Run("Users/"+tt.name, func(t *testing.T) {
+			repos := factory(t)
+			if repos.Users == nil {
+				t.Skip("no user repository")
+			}
+
+			tt.run(t, repos.Users)
+		})
+	}
+
+	t.Run("Users/LockingInTransaction", func(t *testing.T) {
+		repos := factory(t)
+		if repos.Users == nil || repos.Transactions == nil {
+			t.Skip("no user repository or transactions")
+		}
+
+		testLockingInTransaction(t, repos)
+	})
+
+	sessionTests := []struct {
+		name string
+		run  func(t *testing.T, repos Repositories, userID entities.UserID)
+	}{
+		{"CreateAndGet", testSessionCreateAndGet},
+		{"UpdateAndDelete", testSessionUpdateAndDelete},
+		{"Deactivate", testSessionDeactivate},
+		{"CleanupExpired", testSessionCleanupExpired},
+		{"RefreshTokens", testSessionRefreshTokens},
+		{"RoundTrip", testSessionRoundTrip},
+	}
+
+	for _, tt := range sessionTests {
+		t.Run("Sessions/"+tt.name, func(t *testing.T) {
+			repos := factory(t)
+			if repos.Sessions == nil {
+				t.Skip("no session repository")
+			}
+
+			// Sessions reference a stored user where the store enforces it.
+			userID := entities.UserID(1)
+			if repos.Users != nil {
+				userID = newUser(t, repos.Users, "owner").ID()
+			}
+
+			tt.run(t, repos, userID)
+		})
+	}
+
+	runJobRepositoryTests(t, factory)
+	runIdempotencyRepositoryTests(t, factory)
+	runNotificationRepositoryTests(t, factory)
+	runIdentityChangeRepositoryTests(t, factory)
+	runModerationRepositoryTests(t, factory)
+	runAccountDeletionRepositoryTests(t, factory)
+	runUserPreferenceRepositoryTests(t, factory)
+	runActivityRepositoryTests(t, factory)
+	runLoginHistoryRepositoryTests(t, factory)
+	runUserEventStoreTests(t, factory)
+	runUserReadModelTests(t, factory)
+	runFeatureFlagRepositoryTests(t, factory)
+}
+
+// buildUser returns an unsaved active user named name.
+func buildUser(t *testing.T, name string, tags ...string) *entities.User {
+	t.Helper()
+
+	user, err := fixtures.User().Named(name).WithTags(tags...).WithMetadata("name", name).Build()
+	require.NoError(t, err)
+
+	return user
+}
+
+// newUser stores an active user named name.
+func newUser(t *testing.T, repo repositories.UserRepository, name string, tags ...string) *entities.User {
+	t.Helper()
+
+	user := buildUser(t, name, tags...)
+	require.NoError(t, repo.Create(context.Background(), user))
+	require.NotZero(t, user.ID())
+
+	return user
+}
+
+func usernames(users []*entities.User) []string {
+	names := make([]string, 0, len(users))
+	for _, user := range users {
+		names = append(names, user.Username().String())
+	}
+
+	return names
+}
+
+func testCreateAndGet(t *testing.T, repo repositories.UserRepository) {
+	ctx := context.Background()
+	created := newUser(t, repo, "ada", "admin", "beta")
+
+	lookups := map[string]func() (*entities.User, error){
+		"id":       func() (*entities.User, error) { return repo.GetByID(ctx, created.ID()) },
+		"uuid":     func() (*entities.User, error) { return repo.GetByUUID(ctx, entities.NewUuIDFromUUID(created.UUID())) },
+		"email":    func() (*entities.User, error) { return repo.GetByEmail(ctx, created.Email()) },
+		"username": func() (*entities.User, error) { return repo.GetByUsername(ctx, created.Username()) },
+	}
+
+	for by, lookup := range lookups {
+		loaded, err := lookup()
+		require.NoError(t, err, "by %s", by)
+		assert.Equal(t, created.ID(), loaded.ID(), "by %s", by)
+		assert.Equal(t, created.UUID(), loaded.UUID(), "by %s", by)
+		assert.Equal(t, created.Email(), loaded.Email(), "by %s", by)
+		assert.Equal(t, created.Username(), loaded.Username(), "by %s", by)
+		assert.Equal(t, created.FirstName(), loaded.FirstName(), "by %s", by)
+		assert.Equal(t, created.Status(), loaded.Status(), "by %s", by)
+		assert.Equal(t, created.Role(), loaded.Role(), "by %s", by)
+		assert.Equal(t, []string{"admin", "beta"}, loaded.Tags(), "by %s", by)
+		assert.Equal(t, "ada", loaded.Metadata()["name"], "by %s", by)
+		assert.False(t, loaded.CreatedAt().IsZero(), "by %s", by)
+	}
+}
+
+func testDuplicates(t *testing.T, repo repositories.UserRepository) {
+	ctx := context.Background()
+	newUser(t, repo, "ada")
+
+	sameEmail, err := entities.NewUser(
+		"ada@example.com", "ada2", "hash", "Ada", "Again",
+		entities.UserStatusActive, entities.UserRoleUser, nil, nil,
+	)
+	require.NoError(t, err)
+	require.ErrorIs(t, repo.Create(ctx, sameEmail), entities.ErrUserAlreadyExists)
+
+	sameUsername, err := entities.NewUser(
+		"ada2@example.com", "ada", "hash", "Ada", "Again",
+		entities.UserStatusActive, entities.UserRoleUser, nil, nil,
+	)
+	require.NoError(t, err)
+	require.ErrorIs(t, repo.Create(ctx, sameUsername), entities.ErrUserAlreadyExists)
+}
+
+func testCanonicalUsernames(t *testing.T, repo repositories.UserRepository) {
+	ctx := context.Background()
+	ada := newUser(t, repo, "ada")
+
+	loaded, err := repo.GetByUsername(ctx, "ADA")
+	require.NoError(t, err)
+	assert.Equal(t, ada.ID(), loaded.ID())
+	assert.Equal(t, entities.Username("ada"), loaded.Username(), "the username keeps its spelling")
+
+	otherCase, err := entities.NewUser(
+		"ada2@example.com", "Ada", "hash", "Ada", "Again",
+		entities.UserStatusActive, entities.UserRoleUser, nil, nil,
+	)
+	require.NoError(t, err)
+	require.ErrorIs(t, repo.Create(ctx, otherCase), entities.ErrUserAlreadyExists)
+
+	grace := newUser(t, repo, "grace")
+	grace.ChangeUsername("ADA")
+	require.ErrorIs(t, repo.Update(ctx, grace), entities.ErrUserAlreadyExists)
+
+	grace.ChangeUsername("Grace")
+	require.NoError(t, repo.Update(ctx, grace), "a user can change the case of their own username")
+
+	loaded, err = repo.GetByUsername(ctx, "grace")
+	require.NoError(t, err)
+	assert.Equal(t, entities.Username("Grace"), loaded.Username())
+}
+
+func testNotFound(t *testing.T, repo repositories.UserRepository) {
+	ctx := context.Background()
+	newUser(t, repo, "ada")
+
+	_, err := repo.GetByID(ctx, entities.UserID(999999))
+	require.ErrorIs(t, err, entities.ErrUserNotFound)
+
+	_, err = repo.GetByUUID(ctx, entities.NewUuIDFromUUID(uuid.New()))
+	require.ErrorIs(t, err, entities.ErrUserNotFound)
+
+	_, err = repo.GetByEmail(ctx, "missing@example.com")
+	require.ErrorIs(t, err, entities.ErrUserNotFound)
+
+	_, err = repo.GetByUsername(ctx, "missing")
+	require.ErrorIs(t, err, entities.ErrUserNotFound)
+
+	require.ErrorIs(t, repo.Restore(ctx, entities.UserID(999999)), entities.ErrUserNotFound)
+}
+
+func testUpdate(t *testing.T, repo repositories.UserRepository) {
+	ctx := context.Background()
+	user := newUser(t, repo, "grace", "navy")
+
+	firstName := entities.FirstName("Amazing")
+	metadata := entities.UserMetadata{"theme": "dark"}
+	tags := []string{"navy", "compilers"}
+
+	require.NoError(t, user.UpdateProfile(&firstName, nil, &metadata, &tags))
+	user.RecordLogin()
+	require.NoError(t, repo.Update(ctx, user))
+
+	loaded, err := repo.GetByID(ctx, user.ID())
+	require.NoError(t, err)
+	assert.Equal(t, firstName, loaded.FirstName())
+	assert.Equal(t, "dark", loaded.Metadata()["theme"])
+	assert.Equal(t, tags, loaded.Tags())
+	assert.NotNil(t, loaded.LastLoginAt())
+}
+
+func testGetByIDs(t *testing.T, repo repositories.UserRepository) {
+	ada := newUser(t, repo, "ada")
+	alan := newUser(t, repo, "alan")
+
+	users, err := repo.GetByIDs(context.Background(), []entities.UserID{ada.ID(), entities.UserID(999999), alan.ID()})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"ada", "alan"}, usernames(users), "unknown IDs are skipped")
+
+	users, err = repo.GetByIDs(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Empty(t, users)
+}
+
+func testSoftDelete(t *testing.T, repo repositories.UserRepository) {
+	ctx := context.Background()
+	grace := newUser(t, repo, "grace")
+	newUser(t, repo, "alan")
+
+	require.NoError(t, repo.Delete(ctx, grace.ID()))
+
+	_, err := repo.GetByID(ctx, grace.ID())
+	require.ErrorIs(t, err, entities.ErrUserNotFound)
+
+	_, err = repo.GetByEmail(ctx, grace.Email())
+	require.ErrorIs(t, err, entities.ErrUserNotFound)
+
+	listed, err := repo.List(ctx, entities.UserFilter{}, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alan"}, usernames(listed))
+
+	require.NoError(t, repo.Restore(ctx, grace.ID()))
+	require.ErrorIs(t, repo.Restore(ctx, grace.ID()), entities.ErrUserNotFound, "only deleted users are restored")
+
+	restored, err := repo.GetByID(ctx, grace.ID())
+	require.NoError(t, err)
+	assert.Equal(t, grace.Email(), restored.Email())
+
+	require.NoError(t, repo.Delete(ctx, grace.ID()))
+
+	purged, err := repo.PurgeDeletedOlderThan(ctx, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Zero(t, purged)
+
+	purged, err = repo.PurgeDeletedOlderThan(ctx, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+
+	require.ErrorIs(t, repo.Restore(ctx, grace.ID()), entities.ErrUserNotFound, "purged users are gone")
+}
+
+func testBatch(t *testing.T, repo repositories.UserRepository) {
+	ctx := context.Background()
+
+	users := []*entities.User{buildUser(t, "bulk0"), buildUser(t, "bulk1"), buildUser(t, "bulk2")}
+	require.NoError(t, repo.CreateBatch(ctx, users))
+
+	ids := make([]entities.UserID, 0, len(users))
+	for _, user := range users {
+		require.NotZero(t, user.ID())
+
+		ids = append(ids, user.ID())
+	}
+
+	updated, err := repo.UpdateStatusBatch(ctx, append(ids, entities.UserID(999999)), entities.UserStatusSuspended)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), updated)
+
+	loaded, err := repo.GetByID(ctx, ids[1])
+	require.NoError(t, err)
+	assert.Equal(t, entities.UserStatusSuspended, loaded.Status())
+
+	deleted, err := repo.DeleteBatch(ctx, ids[:2])
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), deleted)
+
+	deleted, err = repo.DeleteBatch(ctx, ids)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted, "already deleted users are skipped")
+
+	conflicting := []*entities.User{buildUser(t, "fresh"), buildUser(t, "bulk0")}
+	require.ErrorIs(t, repo.CreateBatch(ctx, conflicting), entities.ErrUserAlreadyExists)
+
+	_, err = repo.GetByEmail(ctx, "fresh@example.com")
+	require.ErrorIs(t, err, entities.ErrUserNotFound, "a failed batch creates no users")
+}
+
+func testList(t *testing.T, repo repositories.UserRepository) {
+	ctx := context.Background()
+	grace := newUser(t, repo, "grace", "navy", "compilers")
+	alan := newUser(t, repo, "alan", "compilers")
+	barbara := newUser(t, repo, "barbara", "languages")
+
+	require.NoError(t, repo.MarkVerified(ctx, grace.ID()))
+	require.NoError(t, repo.ChangeRole(ctx, alan.ID(), entities.UserRoleAdmin))
+	require.NoError(t, repo.Suspend(ctx, barbara.ID()))
+
+	verified := true
+
+	tests := []struct {
+		name     string
+		filter   entities.UserFilter
+		expected []string
+	}{
+		{"no criteria", entities.UserFilter{}, []string{"barbara", "alan", "grace"}},
+		{"query", entities.UserFilter{Query: "gra"}, []string{"grace"}},
+		{"status", entities.UserFilter{Statuses: []entities.UserStatus{entities.UserStatusActive}}, []string{"alan", "grace"}},
+		{"role", entities.UserFilter{Roles: []entities.UserRole{entities.UserRoleAdmin}}, []string{"alan"}},
+		{"verified", entities.UserFilter{Verified: &verified}, []string{"grace"}},
+		{"any tag", entities.UserFilter{TagsAny: []string{"navy", "languages"}}, []string{"barbara", "grace"}},
+		{"all tags", entities.UserFilter{TagsAll: []string{"navy", "compilers"}}, []string{"grace"}},
+		{"created before", entities.UserFilter{CreatedBefore: time.Now().Add(-time.Hour)}, []string{}},
+	}
+
+	for _, tt := range tests {
+		users, err := repo.List(ctx, tt.filter, 10, 0)
+		require.NoError(t, err, tt.name)
+		assert.Equal(t, tt.expected, usernames(users), tt.name)
+	}
+
+	page, err := repo.List(ctx, entities.UserFilter{}, 2, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alan", "grace"}, usernames(page))
+
+	_, err = repo.List(ctx, entities.UserFilter{Statuses: []entities.UserStatus{"bogus"}}, 10, 0)
+	require.ErrorIs(t, err, entities.ErrInvalidUserStatus)
+}
+
+func testListPage(t *testing.T, repo repositories.UserRepository) {
+	ctx := context.Background()
+
+	// Three users share a creation time, so pages must break ties by ID.
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	createdAt := []time.Time{base, base.Add(time.Hour), base.Add(time.Hour), base.Add(time.Hour), base.Add(2 * time.Hour)}
+
+	for i, at := range createdAt {
+		user, err := entities.ReconstructUser(entities.UserRecord{
+			UUID:      uuid.New(),
+			Email:     entities.Email(fmt.Sprintf("page%d@example.com", i)),
+			Username:  entities.Username(fmt.Sprintf("page%d", i)),
+			Password:  "hash",
+			FirstName: "Page",
+			LastName:  "Tester",
+			Status:    entities.UserStatusActive,
+			Role:      entities.UserRoleUser,
+			CreatedAt: at,
+			UpdatedAt: at,
+		})
+		require.NoError(t, err)
+		require.NoError(t, repo.Create(ctx, user))
+	}
+
+	var (
+		seen   []string
+		cursor string
+	)
+
+	for {
+		page, err := repo.ListPage(ctx, entities.UserFilter{}, cursor, 2)
+		require.NoError(t, err)
+		require.LessOrEqual(t, len(page.Users), 2)
+
+		seen = append(seen, usernames(page.Users)...)
+
+		if page.NextCursor == "" {
+			break
+		}
+
+		cursor = page.NextCursor
+	}
+
+	assert.Equal(t, []string{"page4", "page3", "page2", "page1", "page0"}, seen)
+
+	page, err := repo.ListPage(ctx, entities.UserFilter{Statuses: []entities.UserStatus{entities.UserStatusSuspended}}, "", 10)
+	require.NoError(t, err)
+	assert.Empty(t, page.Users)
+	assert.Empty(t, page.NextCursor)
+
+	_, err = repo.ListPage(ctx, entities.UserFilter{}, "not-a-cursor", 10)
+	require.ErrorIs(t, err, entities.ErrInvalidCursor)
+}
+
+func testSearchWithFacets(t *testing.T, repo repositories.UserRepository) {
+	ctx := context.Background()
+	newUser(t, repo, "grace", "navy", "compilers")
+	newUser(t, repo, "alan", "compilers")
+	barbara := newUser(t, repo, "barbara", "languages")
+
+	require.NoError(t, repo.Suspend(ctx, barbara.ID()))
+
+	result, err := repo.SearchWithFacets(ctx, "example.com", entities.UserStatusActive, 1)
+	require.NoError(t, err)
+	assert.Len(t, result.Users, 1, "users are limited")
+	assert.Equal(t, int64(2), result.Facets.ByStatus[entities.UserStatusActive])
+	assert.Equal(t, int64(1), result.Facets.ByStatus[entities.UserStatusSuspended])
+	assert.Equal(t, int64(3), result.Facets.ByRole[entities.UserRoleUser])
+	assert.Contains(t, result.Facets.TopTags, entities.TagCount{Tag: "compilers", Count: 2})
+
+	result, err = repo.SearchWithFacets(ctx, "barbara", entities.UserStatusSuspended, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"barbara"}, usernames(result.Users))
+}
+
+func testCounts(t *testing.T, repo repositories.UserRepository) {
+	ctx := context.Background()
+	grace := newUser(t, repo, "grace")
+	newUser(t, repo, "alan")
+	barbara := newUser(t, repo, "barbara")
+
+	require.NoError(t, repo.MarkVerified(ctx, grace.ID()))
+	require.NoError(t, repo.Suspend(ctx, barbara.ID()))
+
+	counts, err := repo.CountByStatus(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), counts[entities.UserStatusActive])
+	assert.Equal(t, int64(1), counts[entities.UserStatusSuspended])
+
+	stats, err := repo.GetStats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), stats.TotalUsers)
+	assert.Equal(t, int64(1), stats.SuspendedUsers)
+	assert.Equal(t, int64(1), stats.VerifiedUsers)
+	assert.Equal(t, int64(3), stats.NewUsers7d)
+}
+
+func testCredentials(t *testing.T, repo repositories.UserRepository) {
+	ctx := context.Background()
+	user := newUser(t, repo, "ada")
+
+	found, err := repo.GetCredentials(ctx, user.Email())
+	require.NoError(t, err)
+	assert.Equal(t, user.ID(), found.ID())
+	assert.Equal(t, user.PasswordHash(), found.PasswordHash())
+
+	_, err = repo.GetCredentials(ctx, "missing@example.com")
+	require.ErrorIs(t, err, entities.ErrUserNotFound)
+
+	require.NoError(t, repo.UpdatePassword(ctx, user.ID(), "$2a$10$rotated"))
+
+	found, err = repo.GetCredentials(ctx, user.Email())
+	require.NoError(t, err)
+	assert.Equal(t, entities.PasswordHash("$2a$10$rotated"), found.PasswordHash())
+}
+
+func testStatusAndRole(t *testing.T, repo repositories.UserRepository) {
+	ctx := context.Background()
+	user := newUser(t, repo, "ada")
+
+	statusOf := func() entities.UserStatus {
+		loaded, err := repo.GetByID(ctx, user.ID())
+		require.NoError(t, err)
+
+		return loaded.Status()
+	}
+
+	require.NoError(t, repo.Suspend(ctx, user.ID()))
+	assert.Equal(t, entities.UserStatusSuspended, statusOf())
+
+	require.NoError(t, repo.Deactivate(ctx, user.ID()))
+	assert.Equal(t, entities.UserStatusInactive, statusOf())
+
+	require.NoError(t, repo.Activate(ctx, user.ID()))
+	assert.Equal(t, entities.UserStatusActive, statusOf())
+
+	require.NoError(t, repo.ChangeStatus(ctx, user.ID(), entities.UserStatusPending))
+	assert.Equal(t, entities.UserStatusPending, statusOf())
+
+	require.ErrorIs(t, repo.ChangeStatus(ctx, user.ID(), "bogus"), entities.ErrInvalidUserStatus)
+	require.ErrorIs(t, repo.ChangeRole(ctx, user.ID(), "bogus"), entities.ErrInvalidUserRole)
+
+	require.NoError(t, repo.ChangeRole(ctx, user.ID(), entities.UserRoleModerator))
+	require.NoError(t, repo.MarkVerified(ctx, user.ID()))
+
+	loaded, err := repo.GetByID(ctx, user.ID())
+	require.NoError(t, err)
+	assert.Equal(t, entities.UserRoleModerator, loaded.Role())
+	assert.True(t, loaded.IsVerified())
+}
+
+// testLocking accepts adapters.ErrRowLockingNotSupported from engines
+// without row locks and adapters.ErrLockOutsideTransaction from repositories
+// not bound to a transaction.
+func testLocking(t *testing.T, repo repositories.UserRepository) {
+	ctx := context.Background()
+	ada := newUser(t, repo, "ada")
+	alan := newUser(t, repo, "alan")
+	barbara := newUser(t, repo, "barbara")
+
+	require.NoError(t, repo.Suspend(ctx, alan.ID()))
+	require.NoError(t, repo.Suspend(ctx, barbara.ID()))
+
+	locked, err := repo.GetByIDForUpdate(ctx, ada.ID())
+	for _, unavailable := range []error{adapters.ErrRowLockingNotSupported, adapters.ErrLockOutsideTransaction} {
+		if errors.Is(err, unavailable) {
+			_, err = repo.ClaimNext(ctx, entities.UserStatusSuspended, 1)
+			require.ErrorIs(t, err, unavailable)
+
+			return
+		}
+	}
+
+	require.NoError(t, err)
+	testLockedUsers(t, repo, ada, locked)
+}
+
+// testLockingInTransaction runs the locking reads in a transaction, where
+// engines with row locks must support them.
+func testLockingInTransaction(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	ada := newUser(t, repos.Users, "ada")
+	alan := newUser(t, repos.Users, "alan")
+	barbara := newUser(t, repos.Users, "barbara")
+
+	require.NoError(t, repos.Users.Suspend(ctx, alan.ID()))
+	require.NoError(t, repos.Users.Suspend(ctx, barbara.ID()))
+
+	err := repos.Transactions.RunInTransaction(ctx, func(ctx context.Context, tx repositories.Transaction) error {
+		repo := tx.UserRepository()
+
+		locked, err := repo.GetByIDForUpdate(ctx, ada.ID())
+		if errors.Is(err, adapters.ErrRowLockingNotSupported) {
+			return nil
+		}
+
+		require.NoError(t, err)
+		testLockedUsers(t, repo, ada, locked)
+
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+// testLockedUsers checks the locking reads of repo, given ada, locked by it,
+// and the suspended alan and barbara.
+func testLockedUsers(t *testing.T, repo repositories.UserRepository, ada, locked *entities.User) {
+	t.Helper()
+
+	ctx := context.Background()
+	assert.Equal(t, ada.Email(), locked.Email())
+
+	_, err := repo.GetByIDForUpdate(ctx, entities.UserID(999999))
+	require.ErrorIs(t, err, entities.ErrUserNotFound)
+
+	claimed, err := repo.ClaimNext(ctx, entities.UserStatusSuspended, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alan"}, usernames(claimed), "claims in ID order")
+}
+
+// newSession stores a session of userID that expires after lifetime.
+func newSession(
+	t *testing.T,
+	repo repositories.SessionRepository,
+	userID entities.UserID,
+	lifetime time.Duration,
+) *entities.UserSession {
+	t.Helper()
+
+	session := entities.NewUserSession(
+		userID,
+		net.ParseIP("203.0.113.7"),
+		"repositorytest",
+		entities.NewSessionDeviceInfo(),
+		lifetime,
+	)
+	require.NoError(t, repo.Create(context.Background(), session))
+
+	return session
+}
+
+func testSessionCreateAndGet(t *testing.T, repos Repositories, userID entities.UserID) {
+	ctx := context.Background()
+	repo := repos.Sessions
+	session := newSession(t, repo, userID, time.Hour)
+
+	loaded, err := repo.GetByToken(ctx, session.Token())
+	require.NoError(t, err)
+	assert.Equal(t, userID, loaded.UserID())
+	assert.Equal(t, "repositorytest", loaded.UserAgent())
+	assert.True(t, loaded.IsValid())
+
+	_, err = repo.GetByToken(ctx, entities.NewSessionToken())
+	require.ErrorIs(t, err, entities.ErrSessionNotFound)
+
+	_, err = repo.GetByRefreshToken(ctx, entities.NewRefreshToken())
+	require.ErrorIs(t, err, entities.ErrSessionNotFound)
+
+	sessions, err := repo.GetByUserID(ctx, userID, true)
+	require.NoError(t, err)
+	assert.Len(t, sessions, 1)
+
+	active, err := repo.GetActiveSessions(ctx, userID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), active)
+
+	stats, err := repo.GetSessionStats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), stats.TotalSessions)
+	assert.Equal(t, int64(1), stats.ActiveSessions)
+}
+
+func testSessionUpdateAndDelete(t *testing.T, repos Repositories, userID entities.UserID) {
+	ctx := context.Background()
+	repo := repos.Sessions
+	session := newSession(t, repo, userID, time.Hour)
+
+	expiresAt := time.Now().Add(48 * time.Hour).Truncate(time.Second)
+	session.ExtendUntil(expiresAt)
+	require.NoError(t, repo.Update(ctx, session))
+
+	loaded, err := repo.GetByToken(ctx, session.Token())
+	require.NoError(t, err)
+	assert.WithinDuration(t, expiresAt, loaded.ExpiresAt(), time.Second)
+
+	require.NoError(t, repo.Delete(ctx, session.ID()))
+
+	_, err = repo.GetByToken(ctx, session.Token())
+	require.ErrorIs(t, err, entities.ErrSessionNotFound)
+}
+
+func testSessionDeactivate(t *testing.T, repos Repositories, userID entities.UserID) {
+	ctx := context.Background()
+	repo := repos.Sessions
+	first := newSession(t, repo, userID, time.Hour)
+	newSession(t, repo, userID, time.Hour)
+	newSession(t, repo, userID, time.Hour)
+
+	require.NoError(t, repo.DeactivateByToken(ctx, first.Token()))
+
+	loaded, err := repo.GetByToken(ctx, first.Token())
+	require.NoError(t, err)
+	assert.False(t, loaded.IsActive())
+
+	active, err := repo.GetByUserID(ctx, userID, true)
+	require.NoError(t, err)
+	assert.Len(t, active, 2)
+
+	all, err := repo.GetByUserID(ctx, userID, false)
+	require.NoError(t, err)
+	assert.Len(t, all, 3)
+
+	require.NoError(t, repo.DeactivateByUserID(ctx, userID))
+
+	count, err := repo.GetActiveSessions(ctx, userID)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+}
+
+func testSessionCleanupExpired(t *testing.T, repos Repositories, userID entities.UserID) {
+	ctx := context.Background()
+	repo := repos.Sessions
+	expired := newSession(t, repo, userID, -time.Minute)
+	current := newSession(t, repo, userID, time.Hour)
+
+	removed, err := repo.CleanupExpired(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), removed)
+
+	_, err = repo.GetByToken(ctx, expired.Token())
+	require.ErrorIs(t, err, entities.ErrSessionNotFound)
+
+	_, err = repo.GetByToken(ctx, current.Token())
+	require.NoError(t, err)
+}
+
+func testSessionRefreshTokens(t *testing.T, repos Repositories, userID entities.UserID) {
+	ctx := context.Background()
+	repo := repos.Sessions
+	session := entities.NewUserSession(userID, nil, "repositorytest", entities.NewSessionDeviceInfo(), time.Hour)
+	first := session.IssueRefreshToken(time.Now().Add(time.Hour))
+	require.NoError(t, repo.Create(ctx, session))
+
+	refreshExpiresAt := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+	second, err := session.RotateRefreshToken(first, refreshExpiresAt, time.Now())
+	require.NoError(t, err)
+	require.NoError(t, repo.Update(ctx, session))
+
+	loaded, err := repo.GetByRefreshToken(ctx, second)
+	require.NoError(t, err)
+	assert.Equal(t, session.ID(), loaded.ID())
+	assert.Equal(t, second, loaded.RefreshToken())
+	assert.Equal(t, []entities.RefreshToken{first}, loaded.RotatedRefreshTokens())
+	assert.WithinDuration(t, refreshExpiresAt, loaded.RefreshExpiresAt(), time.Second)
+
+	// A rotated token still finds its session, so that reuse can revoke it.
+	reused, err := repo.GetByRefreshToken(ctx, first)
+	require.NoError(t, err)
+	assert.Equal(t, session.ID(), reused.ID())
+}
+
+func testSessionRoundTrip(t *testing.T, repos Repositories, userID entities.UserID) {
+	ctx := context.Background()
+	repo := repos.Sessions
+	impersonatorID := userID + 1000
+	session := entities.NewImpersonationSession(
+		impersonatorID,
+		userID,
+		net.ParseIP("2001:db8::1"),
+		"repositorytest",
+		time.Hour,
+	)
+	session.SetLocation(entities.GeoLocation{Country: "DE", Region: "BE", City: "Berlin"})
+	session.DeviceInfo().SetMetadata("app", "cli")
+	require.NoError(t, repo.Create(ctx, session))
+
+	loaded, err := repo.GetByToken(ctx, session.Token())
+	require.NoError(t, err)
+	assert.Equal(t, session.ID(), loaded.ID())
+	assert.True(t, session.IPAddress().Equal(loaded.IPAddress()))
+	assert.Equal(t, session.Location(), loaded.Location())
+	assert.True(t, loaded.RefreshToken().IsZero())
+	assert.Empty(t, loaded.RotatedRefreshTokens())
+	assert.WithinDuration(t, session.CreatedAt(), loaded.CreatedAt(), time.Second)
+
+	app, ok := loaded.DeviceInfo().GetMetadata("app")
+	require.True(t, ok)
+	assert.Equal(t, "cli", app)
+
+	impersonator, ok := loaded.Impersonator()
+	require.True(t, ok)
+	assert.Equal(t, impersonatorID, impersonator)
+}
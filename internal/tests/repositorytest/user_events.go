@@ -0,0 +1,174 @@
+package repositorytest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runUserEventStoreTests runs the user event store contract.
+func runUserEventStoreTests(t *testing.T, factory Factory) {
+	t.Helper()
+
+	userEventTests := []struct {
+		name string
+		run  func(t *testing.T, store repositories.UserEventStore, user *entities.User)
+	}{
+		{"AppendAndLoad", testUserEventsAppendAndLoad},
+		{"Conflict", testUserEventsConflict},
+		{"Snapshots", testUserEventsSnapshots},
+		{"DeleteStream", testUserEventsDeleteStream},
+	}
+
+	for _, tt := range userEventTests {
+		t.Run("UserEvents/"+tt.name, func(t *testing.T) {
+			repos := factory(t)
+			if repos.UserEvents == nil {
+				t.Skip("no user event store")
+			}
+
+			// Streams reference a stored user where the store enforces it.
+			user := buildUser(t, "ada", "beta")
+			if repos.Users != nil {
+				require.NoError(t, repos.Users.Create(context.Background(), user))
+			} else {
+				user.SetID(1)
+			}
+
+			tt.run(t, repos.UserEvents, user)
+		})
+	}
+}
+
+// renamed returns the event that changes the first name of user at.
+func renamed(user *entities.User, name entities.FirstName, at time.Time) *entities.UserStreamEvent {
+	return &entities.UserStreamEvent{
+		UserID:     user.ID(),
+		Type:       entities.UserEventProfileUpdated,
+		Data:       entities.UserEventData{FirstName: &name},
+		OccurredAt: at,
+	}
+}
+
+func testUserEventsAppendAndLoad(t *testing.T, store repositories.UserEventStore, user *entities.User) {
+	ctx := context.Background()
+	at := time.Now().Truncate(time.Second)
+
+	created := entities.NewUserCreatedEvent(user)
+	rename := renamed(user, "Grace", at)
+	require.NoError(t, store.Append(ctx, user.ID(), 0, []*entities.UserStreamEvent{created, rename}))
+	assert.Equal(t, []int64{1, 2}, []int64{created.Version, rename.Version})
+
+	events, err := store.Load(ctx, user.ID(), 0)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, entities.UserEventCreated, events[0].Type)
+	assert.Equal(t, user.Email(), *events[0].Data.Email)
+	assert.Equal(t, []string{"beta"}, *events[0].Data.Tags)
+	assert.Equal(t, "ada", (*events[0].Data.Metadata)["name"])
+	assert.Equal(t, int64(2), events[1].Version)
+	assert.Equal(t, entities.FirstName("Grace"), *events[1].Data.FirstName)
+	assert.Nil(t, events[1].Data.Email)
+	assert.True(t, at.Equal(events[1].OccurredAt), events[1].OccurredAt)
+
+	replayed := new(entities.User)
+	for _, event := range events {
+		require.NoError(t, replayed.Apply(event))
+	}
+
+	assert.Equal(t, user.UUID(), replayed.UUID())
+	assert.Equal(t, entities.FirstName("Grace"), replayed.FirstName())
+
+	events, err = store.Load(ctx, user.ID(), 1)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, int64(2), events[0].Version)
+
+	events, err = store.Load(ctx, user.ID()+1000, 0)
+	require.NoError(t, err)
+	assert.Empty(t, events)
+}
+
+func testUserEventsConflict(t *testing.T, store repositories.UserEventStore, user *entities.User) {
+	ctx := context.Background()
+	at := time.Now().Truncate(time.Second)
+
+	require.NoError(t, store.Append(ctx, user.ID(), 0, []*entities.UserStreamEvent{entities.NewUserCreatedEvent(user)}))
+
+	err := store.Append(ctx, user.ID(), 0, []*entities.UserStreamEvent{renamed(user, "Grace", at)})
+	require.ErrorIs(t, err, entities.ErrUserStreamConflict)
+
+	err = store.Append(ctx, user.ID(), 2, []*entities.UserStreamEvent{renamed(user, "Grace", at)})
+	require.ErrorIs(t, err, entities.ErrUserStreamConflict)
+
+	require.NoError(t, store.Append(ctx, user.ID(), 1, []*entities.UserStreamEvent{renamed(user, "Grace", at)}))
+
+	events, err := store.Load(ctx, user.ID(), 0)
+	require.NoError(t, err)
+	assert.Len(t, events, 2)
+}
+
+func testUserEventsSnapshots(t *testing.T, store repositories.UserEventStore, user *entities.User) {
+	ctx := context.Background()
+	at := time.Now().Truncate(time.Second)
+
+	_, err := store.LatestSnapshot(ctx, user.ID())
+	require.ErrorIs(t, err, entities.ErrUserSnapshotNotFound)
+
+	state := user.Record()
+	for _, version := range []int64{4, 2, 4} {
+		require.NoError(t, store.SaveSnapshot(ctx, &entities.UserStreamSnapshot{
+			UserID:    user.ID(),
+			Version:   version,
+			Deleted:   version == 4,
+			State:     state,
+			CreatedAt: at,
+		}))
+	}
+
+	snapshot, err := store.LatestSnapshot(ctx, user.ID())
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), snapshot.Version)
+	assert.True(t, snapshot.Deleted)
+	assert.True(t, at.Equal(snapshot.CreatedAt), snapshot.CreatedAt)
+
+	restored, err := entities.ReconstructUser(snapshot.State)
+	require.NoError(t, err)
+	assert.Equal(t, user.ID(), restored.ID())
+	assert.Equal(t, user.Email(), restored.Email())
+	assert.Equal(t, user.Tags(), restored.Tags())
+	assert.Equal(t, "ada", restored.Metadata()["name"])
+}
+
+func testUserEventsDeleteStream(t *testing.T, store repositories.UserEventStore, user *entities.User) {
+	ctx := context.Background()
+	at := time.Now().Truncate(time.Second)
+
+	require.NoError(t, store.Append(ctx, user.ID(), 0, []*entities.UserStreamEvent{
+		renamed(user, "Ada", at),
+		renamed(user, "Grace", at),
+	}))
+	require.NoError(t, store.SaveSnapshot(ctx, &entities.UserStreamSnapshot{
+		UserID:    user.ID(),
+		Version:   2,
+		State:     user.Record(),
+		CreatedAt: at,
+	}))
+
+	require.NoError(t, store.DeleteStream(ctx, user.ID()))
+
+	events, err := store.Load(ctx, user.ID(), 0)
+	require.NoError(t, err)
+	assert.Empty(t, events)
+
+	_, err = store.LatestSnapshot(ctx, user.ID())
+	require.ErrorIs(t, err, entities.ErrUserSnapshotNotFound)
+
+	// A later append starts a new stream.
+	require.NoError(t, store.Append(ctx, user.ID(), 0, []*entities.UserStreamEvent{renamed(user, "Ada", at)}))
+}
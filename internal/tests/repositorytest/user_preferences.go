@@ -0,0 +1,117 @@
+package repositorytest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runUserPreferenceRepositoryTests runs the user preference contract.
+func runUserPreferenceRepositoryTests(t *testing.T, factory Factory) {
+	t.Helper()
+
+	preferenceTests := []struct {
+		name string
+		run  func(t *testing.T, repo repositories.UserPreferenceRepository, userID entities.UserID)
+	}{
+		{"Empty", testUserPreferencesEmpty},
+		{"Patch", testUserPreferencesPatch},
+		{"Isolation", testUserPreferencesIsolation},
+		{"Delete", testUserPreferencesDelete},
+	}
+
+	for _, tt := range preferenceTests {
+		t.Run("Preferences/"+tt.name, func(t *testing.T) {
+			repos := factory(t)
+			if repos.Preferences == nil {
+				t.Skip("no user preference repository")
+			}
+
+			// Preferences reference a stored user where the store enforces it.
+			userID := entities.UserID(1)
+			if repos.Users != nil {
+				userID = newUser(t, repos.Users, "preferrer").ID()
+			}
+
+			tt.run(t, repos.Preferences, userID)
+		})
+	}
+}
+
+func testUserPreferencesEmpty(t *testing.T, repo repositories.UserPreferenceRepository, userID entities.UserID) {
+	prefs, err := repo.Get(context.Background(), userID)
+	require.NoError(t, err)
+	assert.Equal(t, userID, prefs.UserID)
+	assert.Empty(t, prefs.Values)
+	assert.Equal(t, "system", prefs.Theme())
+	assert.True(t, prefs.Bool(entities.PreferenceNotificationSounds))
+}
+
+func testUserPreferencesPatch(t *testing.T, repo repositories.UserPreferenceRepository, userID entities.UserID) {
+	ctx := context.Background()
+
+	prefs, err := repo.Patch(ctx, userID, entities.PreferencePatch{
+		Set: map[entities.PreferenceKey]any{
+			entities.PreferenceTheme:              "dark",
+			entities.PreferencePageSize:           50,
+			entities.PreferenceNotificationSounds: false,
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "dark", prefs.Theme())
+	assert.NotZero(t, prefs.UpdatedAt)
+
+	// Preferences the patch does not mention keep their stored values.
+	_, err = repo.Patch(ctx, userID, entities.PreferencePatch{
+		Set:   map[entities.PreferenceKey]any{entities.PreferenceLocale: "de-CH"},
+		Unset: []entities.PreferenceKey{entities.PreferenceTheme},
+	})
+	require.NoError(t, err)
+
+	prefs, err = repo.Get(ctx, userID)
+	require.NoError(t, err)
+	assert.Len(t, prefs.Values, 3)
+	assert.Equal(t, "system", prefs.Theme())
+	assert.Equal(t, "de-CH", prefs.Locale())
+	assert.Equal(t, 50, prefs.Int(entities.PreferencePageSize))
+	assert.False(t, prefs.Bool(entities.PreferenceNotificationSounds))
+}
+
+func testUserPreferencesIsolation(
+	t *testing.T,
+	repo repositories.UserPreferenceRepository,
+	userID entities.UserID,
+) {
+	ctx := context.Background()
+
+	_, err := repo.Patch(ctx, userID, entities.PreferencePatch{
+		Set: map[entities.PreferenceKey]any{entities.PreferenceTheme: "light"},
+	})
+	require.NoError(t, err)
+
+	others, err := repo.Get(ctx, userID+1000)
+	require.NoError(t, err)
+	assert.Empty(t, others.Values)
+}
+
+func testUserPreferencesDelete(t *testing.T, repo repositories.UserPreferenceRepository, userID entities.UserID) {
+	ctx := context.Background()
+
+	// Deleting preferences that were never stored is not an error.
+	require.NoError(t, repo.Delete(ctx, userID))
+
+	_, err := repo.Patch(ctx, userID, entities.PreferencePatch{
+		Set: map[entities.PreferenceKey]any{entities.PreferenceTheme: "dark"},
+	})
+	require.NoError(t, err)
+	require.NoError(t, repo.Delete(ctx, userID))
+
+	prefs, err := repo.Get(ctx, userID)
+	require.NoError(t, err)
+	assert.Empty(t, prefs.Values)
+	assert.Equal(t, "system", prefs.Theme())
+}
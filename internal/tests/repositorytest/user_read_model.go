@@ -0,0 +1,188 @@
+package repositorytest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/pkg/fixtures"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runUserReadModelTests runs the user read model contract. Views are
+// projected from stored users, so it needs a user repository too.
+func runUserReadModelTests(t *testing.T, factory Factory) {
+	t.Helper()
+
+	readModelTests := []struct {
+		name string
+		run  func(t *testing.T, repos Repositories)
+	}{
+		{"UpsertAndList", testUserViewsUpsertAndList},
+		{"StaleUpsert", testUserViewsStaleUpsert},
+		{"Remove", testUserViewsRemove},
+		{"Lag", testUserViewsLag},
+	}
+
+	for _, tt := range readModelTests {
+		t.Run("UserReadModel/"+tt.name, func(t *testing.T) {
+			repos := factory(t)
+			if repos.UserReadModel == nil || repos.Users == nil {
+				t.Skip("no user read model")
+			}
+
+			tt.run(t, repos)
+		})
+	}
+}
+
+// project stores the views of users as loaded from repos.
+func project(t *testing.T, repos Repositories, users ...*entities.User) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	for _, user := range users {
+		stored, err := repos.Users.GetByID(ctx, user.ID())
+		require.NoError(t, err)
+		require.NoError(t, repos.UserReadModel.Upsert(ctx, entities.NewUserView(stored, time.Now())))
+	}
+}
+
+func viewUsernames(views []*entities.UserView) []string {
+	names := make([]string, 0, len(views))
+	for _, view := range views {
+		names = append(names, view.Username.String())
+	}
+
+	return names
+}
+
+func testUserViewsUpsertAndList(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	start := time.Now().Add(-time.Hour)
+
+	ada := fixtures.User().Named("ada").WithFullName("Ada", "Lovelace").WithTags("beta", "admin").
+		WithCreatedAt(start).MustBuild()
+	grace := fixtures.User().Named("grace").WithFullName("Grace", "Hopper").WithTags("beta").
+		WithStatus(entities.UserStatusSuspended).WithCreatedAt(start.Add(time.Minute)).MustBuild()
+	linus := fixtures.User().Named("linus").WithCreatedAt(start.Add(2 * time.Minute)).MustBuild()
+
+	for _, user := range []*entities.User{ada, grace, linus} {
+		require.NoError(t, repos.Users.Create(ctx, user))
+	}
+
+	project(t, repos, ada, grace, linus)
+
+	all, err := repos.UserReadModel.List(ctx, entities.UserFilter{}, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"linus", "grace", "ada"}, viewUsernames(all))
+
+	view := all[2]
+	assert.Equal(t, ada.ID(), view.UserID)
+	assert.Equal(t, ada.UUID(), view.UUID)
+	assert.Equal(t, ada.Email(), view.Email)
+	assert.Equal(t, "Ada Lovelace", view.FullName)
+	assert.Equal(t, entities.UserStatusActive, view.Status)
+	assert.ElementsMatch(t, []string{"beta", "admin"}, view.Tags)
+
+	page, err := repos.UserReadModel.List(ctx, entities.UserFilter{}, 1, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"grace"}, viewUsernames(page))
+
+	filters := map[string]struct {
+		filter entities.UserFilter
+		want   []string
+	}{
+		"query prefix": {entities.UserFilter{Query: "hop"}, []string{"grace"}},
+		"query terms":  {entities.UserFilter{Query: "Ada love"}, []string{"ada"}},
+		"query infix":  {entities.UserFilter{Query: "race"}, []string{}},
+		"status": {
+			entities.UserFilter{Statuses: []entities.UserStatus{entities.UserStatusSuspended}},
+			[]string{"grace"},
+		},
+		"tags any": {entities.UserFilter{TagsAny: []string{"beta"}}, []string{"grace", "ada"}},
+		"tags all": {entities.UserFilter{TagsAll: []string{"beta", "admin"}}, []string{"ada"}},
+		"created":  {entities.UserFilter{CreatedAfter: start.Add(90 * time.Second)}, []string{"linus"}},
+	}
+
+	for name, tt := range filters {
+		views, err := repos.UserReadModel.List(ctx, tt.filter, 10, 0)
+		require.NoError(t, err, name)
+		assert.Equal(t, tt.want, viewUsernames(views), name)
+	}
+}
+
+func testUserViewsStaleUpsert(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	user := newUser(t, repos.Users, "ada")
+	project(t, repos, user)
+
+	stored, err := repos.Users.GetByID(ctx, user.ID())
+	require.NoError(t, err)
+
+	stale := entities.NewUserView(stored, time.Now())
+	stale.FullName = "Stale"
+	stale.SourceUpdatedAt = stale.SourceUpdatedAt.Add(-time.Hour)
+	require.NoError(t, repos.UserReadModel.Upsert(ctx, stale))
+
+	fresh := entities.NewUserView(stored, time.Now())
+	fresh.FullName = "Fresh"
+	fresh.SourceUpdatedAt = fresh.SourceUpdatedAt.Add(time.Hour)
+	require.NoError(t, repos.UserReadModel.Upsert(ctx, fresh))
+
+	views, err := repos.UserReadModel.List(ctx, entities.UserFilter{}, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, views, 1)
+	assert.Equal(t, "Fresh", views[0].FullName)
+
+	require.NoError(t, repos.UserReadModel.Upsert(ctx, stale))
+
+	views, err = repos.UserReadModel.List(ctx, entities.UserFilter{}, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, views, 1)
+	assert.Equal(t, "Fresh", views[0].FullName, "older views must not replace newer ones")
+}
+
+func testUserViewsRemove(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	ada := newUser(t, repos.Users, "ada")
+	grace := newUser(t, repos.Users, "grace")
+	project(t, repos, ada, grace)
+
+	require.NoError(t, repos.UserReadModel.Remove(ctx, ada.ID(), entities.UserID(1<<40)))
+	require.NoError(t, repos.UserReadModel.Remove(ctx))
+
+	views, err := repos.UserReadModel.List(ctx, entities.UserFilter{}, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"grace"}, viewUsernames(views))
+}
+
+func testUserViewsLag(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+
+	lag, err := repos.UserReadModel.Lag(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, lag, "an empty read model is current")
+
+	user := fixtures.User().Named("ada").WithCreatedAt(time.Now().Add(-time.Hour)).MustBuild()
+	require.NoError(t, repos.Users.Create(ctx, user))
+
+	lag, err = repos.UserReadModel.Lag(ctx)
+	require.NoError(t, err)
+	assert.Greater(t, lag, 50*time.Minute, "the user waits since it was stored")
+
+	project(t, repos, user)
+
+	lag, err = repos.UserReadModel.Lag(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, lag)
+
+	require.NoError(t, repos.UserReadModel.Remove(ctx, user.ID()))
+
+	lag, err = repos.UserReadModel.Lag(ctx)
+	require.NoError(t, err)
+	assert.Greater(t, lag, 50*time.Minute)
+}
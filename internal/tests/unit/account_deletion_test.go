@@ -0,0 +1,168 @@
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/memory"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/services"
+	"github.com/LarsArtmann/template-sqlc/pkg/fixtures"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// accountDeletionFixture is a user and a service scheduling deletions in memory.
+type accountDeletionFixture struct {
+	*serviceFixture
+
+	deletions *memory.AccountDeletionRepository
+	ada       *entities.User
+}
+
+func newAccountDeletionFixture(t *testing.T) *accountDeletionFixture {
+	t.Helper()
+
+	f := &accountDeletionFixture{
+		serviceFixture: newServiceFixture(),
+		deletions:      memory.NewAccountDeletionRepository(),
+	}
+
+	f.ada = f.createUser(t, fixtures.User().Named("ada").WithPassword(hashPassword(t, "secret")).MustBuild())
+	f.tx.deletionRepo = f.deletions
+	f.start(
+		services.WithPasswordHasher(testHasher),
+		services.WithAccountDeletion(f.deletions, 24*time.Hour),
+		services.WithTransactions(f.tx),
+	)
+
+	return f
+}
+
+func TestRequestAndCancelAccountDeletion(t *testing.T) {
+	ctx := context.Background()
+	f := newAccountDeletionFixture(t)
+
+	session := entities.NewUserSession(f.ada.ID(), nil, "", entities.NewSessionDeviceInfo(), time.Hour)
+	require.NoError(t, f.sessions.Create(ctx, session))
+
+	deletion, err := f.service.RequestAccountDeletion(ctx, f.ada.ID())
+	require.NoError(t, err)
+	assert.Equal(t, f.ada.ID(), deletion.RequestedBy)
+	assert.WithinDuration(t, time.Now().Add(24*time.Hour), deletion.ScheduledFor, time.Minute)
+
+	active, err := f.sessions.GetByUserID(ctx, f.ada.ID(), true)
+	require.NoError(t, err)
+	assert.Empty(t, active)
+
+	requested := lastEventData[events.AccountDeletionEvent](t, f.publisher, events.EventDeletionRequested)
+	assert.WithinDuration(t, deletion.ScheduledFor, requested.ScheduledFor, time.Millisecond)
+
+	_, err = f.service.RequestAccountDeletion(ctx, f.ada.ID())
+	require.ErrorIs(t, err, entities.ErrAccountDeletionPending)
+
+	pending, err := f.service.PendingAccountDeletion(ctx, f.ada.ID())
+	require.NoError(t, err)
+	assert.Equal(t, deletion.ScheduledFor, pending.ScheduledFor)
+
+	admin := services.ContextWithAuditActor(ctx, services.AuditActor{UserID: 42})
+	require.NoError(t, f.service.CancelAccountDeletion(admin, f.ada.ID()))
+	require.ErrorIs(t, f.service.CancelAccountDeletion(ctx, f.ada.ID()), entities.ErrAccountDeletionNotFound)
+
+	_, err = f.service.PendingAccountDeletion(ctx, f.ada.ID())
+	require.ErrorIs(t, err, entities.ErrAccountDeletionNotFound)
+
+	canceled := lastEventData[events.AccountDeletionEvent](t, f.publisher, events.EventDeletionCanceled)
+	assert.Equal(t, entities.UserID(42), canceled.CanceledBy)
+
+	require.Len(t, f.audit.entries, 2)
+	assert.Equal(t, entities.AuditActionDeletionRequest, f.audit.entries[0].Action)
+	assert.Equal(t, entities.AuditActionDeletionCancel, f.audit.entries[1].Action)
+	assert.Equal(t, entities.UserID(42), f.audit.entries[1].ActorID)
+}
+
+func TestPendingAccountDeletionBlocksLogin(t *testing.T) {
+	ctx := context.Background()
+	f := newAccountDeletionFixture(t)
+	password := "secret"
+
+	_, err := f.service.RequestAccountDeletion(ctx, f.ada.ID())
+	require.NoError(t, err)
+
+	_, err = f.service.AuthenticateUser(ctx, "ada@example.com", password, "192.0.2.1", "firefox")
+	require.ErrorIs(t, err, entities.ErrAccountDeleting)
+
+	active, err := f.sessions.GetByUserID(ctx, f.ada.ID(), true)
+	require.NoError(t, err)
+	assert.Empty(t, active)
+
+	require.NoError(t, f.service.CancelAccountDeletion(ctx, f.ada.ID()))
+
+	_, err = f.service.AuthenticateUser(ctx, "ada@example.com", password, "192.0.2.1", "firefox")
+	require.NoError(t, err)
+}
+
+func TestRequestAccountDeletionRejects(t *testing.T) {
+	ctx := context.Background()
+	f := newAccountDeletionFixture(t)
+
+	_, err := f.service.RequestAccountDeletion(ctx, f.ada.ID()+1000)
+	require.ErrorIs(t, err, entities.ErrUserNotFound)
+	assert.Empty(t, eventsOfType(f.publisher, events.EventDeletionRequested))
+
+	service := services.NewUserService(f.users, f.sessions, f.publisher, nil)
+	_, err = service.RequestAccountDeletion(ctx, f.ada.ID())
+	require.ErrorIs(t, err, services.ErrAccountDeletionUnavailable)
+
+	_, err = service.DeleteDueAccounts(ctx, 10)
+	require.ErrorIs(t, err, services.ErrAccountDeletionUnavailable)
+}
+
+func TestDeleteDueAccounts(t *testing.T) {
+	ctx := context.Background()
+	f := newAccountDeletionFixture(t)
+
+	grace := fixtures.User().Named("grace").MustBuild()
+	require.NoError(t, f.users.Create(ctx, grace))
+
+	// Ada's grace period ended a minute ago, Grace's has just begun.
+	require.NoError(t, f.deletions.Create(ctx, entities.NewAccountDeletion(f.ada.ID(), f.ada.ID(), -time.Minute)))
+
+	_, err := f.service.RequestAccountDeletion(ctx, grace.ID())
+	require.NoError(t, err)
+
+	erased, err := f.service.DeleteDueAccounts(ctx, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, erased)
+
+	_, err = f.users.GetByID(ctx, f.ada.ID())
+	require.ErrorIs(t, err, entities.ErrUserNotFound)
+
+	_, err = f.users.GetByID(ctx, grace.ID())
+	require.NoError(t, err)
+
+	_, err = f.deletions.GetByUser(ctx, f.ada.ID())
+	require.ErrorIs(t, err, entities.ErrAccountDeletionNotFound)
+
+	compliance := lastEventData[events.ComplianceEvent](t, f.publisher, events.EventUserErased)
+	assert.Equal(t, f.ada.ID(), compliance.UserID)
+	assert.Equal(t, f.ada.ID(), compliance.RequestedBy)
+
+	erased, err = f.service.DeleteDueAccounts(ctx, 10)
+	require.NoError(t, err)
+	assert.Zero(t, erased)
+}
+
+func TestEraseUserSettlesPendingDeletion(t *testing.T) {
+	ctx := context.Background()
+	f := newAccountDeletionFixture(t)
+
+	_, err := f.service.RequestAccountDeletion(ctx, f.ada.ID())
+	require.NoError(t, err)
+	require.NoError(t, f.service.EraseUser(ctx, f.ada.ID()))
+
+	_, err = f.service.PendingAccountDeletion(ctx, f.ada.ID())
+	require.ErrorIs(t, err, entities.ErrAccountDeletionNotFound)
+}
@@ -0,0 +1,117 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/memory"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/services"
+	"github.com/LarsArtmann/template-sqlc/internal/jobs"
+	"github.com/LarsArtmann/template-sqlc/pkg/fixtures"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errActivityDown = errors.New("activity store down")
+
+// failingActivity is an activity repository whose writes fail.
+type failingActivity struct {
+	*memory.ActivityRepository
+}
+
+func (failingActivity) Record(context.Context, *entities.ActivityEntry) error {
+	return errActivityDown
+}
+
+func TestActivityFeedProjectsEvents(t *testing.T) {
+	ctx := context.Background()
+	users := memory.NewUserRepository()
+	ada := fixtures.User().Named("ada").MustBuild()
+	require.NoError(t, users.Create(ctx, ada))
+
+	feed := services.NewActivityFeed(memory.NewActivityRepository(), users)
+	assert.Contains(t, feed.EventTypes(), events.EventRoleChanged)
+
+	// Redacted events arrive as JSON; their summaries carry no personal data.
+	login, err := events.RedactionPolicy{Mode: events.RedactionHash}.RedactEvent(
+		events.UserFederatedLogin(ada.ID(), "google", "subject", "192.0.2.1", "agent", false))
+	require.NoError(t, err)
+
+	update := events.UserUpdated(ada.ID(), map[string]any{
+		"first_name": map[string]any{"old": "Ada", "new": "Augusta"},
+		"avatar":     map[string]any{"old": "", "new": "https://cdn.example.com/a.png"},
+	}, ada.ID())
+	roleChange := events.RoleChanged(ada.ID(), "user", "admin", 2)
+
+	for _, event := range []*events.UserEvent{
+		login,
+		update,
+		roleChange,
+		// Redeliveries are recorded once and unknown types are ignored.
+		roleChange,
+		events.UsersBulkUpdated("role", "admin", []entities.UserID{ada.ID()}, 0, 2),
+	} {
+		require.NoError(t, feed.Handle(ctx, event), event.Type)
+	}
+
+	page, err := feed.List(ctx, ada.ID(), "", 10)
+	require.NoError(t, err)
+	require.Len(t, page.Entries, 3)
+
+	summaries := map[string]*entities.ActivityEntry{}
+	for _, entry := range page.Entries {
+		summaries[entry.Summary] = entry
+	}
+
+	require.Contains(t, summaries, "Signed in with google")
+	assert.Equal(t, entities.ActivityKindSignIn, summaries["Signed in with google"].Kind)
+
+	require.Contains(t, summaries, "Updated profile: avatar, first name")
+	assert.Zero(t, summaries["Updated profile: avatar, first name"].ActorID, "users updating themselves")
+
+	changed := summaries["Role changed from user to admin"]
+	require.NotNil(t, changed)
+	assert.Equal(t, entities.ActivityKindAccount, changed.Kind)
+	assert.Equal(t, entities.UserID(2), changed.ActorID)
+	assert.Equal(t, roleChange.ID, changed.EventID)
+	assert.Equal(t, string(events.EventRoleChanged), changed.EventType)
+}
+
+func TestActivityFeedIgnoresDeletedUsers(t *testing.T) {
+	ctx := context.Background()
+	users := memory.NewUserRepository()
+	ada := fixtures.User().Named("ada").MustBuild()
+	require.NoError(t, users.Create(ctx, ada))
+
+	feed := services.NewActivityFeed(failingActivity{memory.NewActivityRepository()}, users)
+
+	err := feed.Handle(ctx, events.UserLoggedIn(ada.ID(), "192.0.2.1", "agent", "web"))
+	require.ErrorIs(t, err, errActivityDown, "stored users are retried")
+
+	err = feed.Handle(ctx, events.UserLoggedIn(ada.ID()+1, "192.0.2.1", "agent", "web"))
+	require.NoError(t, err)
+}
+
+func TestCleanupHandlerDeletesOldActivity(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewActivityRepository()
+
+	for id, age := range map[entities.IDID]time.Duration{1: 48 * time.Hour, 2: time.Hour} {
+		entry, err := entities.NewActivityEntry(1, id, "user.login", entities.ActivityKindSignIn, "Signed in", 0,
+			time.Now().Add(-age))
+		require.NoError(t, err)
+		require.NoError(t, repo.Record(ctx, entry))
+	}
+
+	handler := jobs.NewCleanupHandler(jobs.Cleanup{Activity: repo, ActivityRetention: 24 * time.Hour})
+	require.NoError(t, handler.Handle(ctx, &entities.Job{}))
+
+	page, err := repo.ListPage(ctx, 1, "", 10)
+	require.NoError(t, err)
+	require.Len(t, page.Entries, 1)
+	assert.Equal(t, entities.IDID(2), page.Entries[0].EventID)
+}
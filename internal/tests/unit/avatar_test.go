@@ -0,0 +1,182 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/services"
+	"github.com/LarsArtmann/template-sqlc/pkg/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// avatarFixture is a user and a service storing avatars in memory.
+type avatarFixture struct {
+	*serviceFixture
+
+	blobs *storage.Memory
+	user  *entities.User
+}
+
+func newAvatarFixture(t *testing.T) *avatarFixture {
+	t.Helper()
+
+	f := &avatarFixture{
+		serviceFixture: newServiceFixture(),
+		blobs:          storage.NewMemory("https://cdn.example.com"),
+	}
+
+	f.user = f.createUser(t, newTestUser(t, entities.UserRoleUser))
+	f.start(
+		services.WithAvatars(f.blobs),
+		services.WithTransactions(f.tx),
+	)
+
+	return f
+}
+
+// encodeTestImage returns a width x height image in format "png" or "jpeg".
+func encodeTestImage(t *testing.T, format string, width, height int) []byte {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := range width {
+		for y := range height {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 0x80, A: 0xff}) //nolint:gosec // wraps on purpose
+		}
+	}
+
+	var buf bytes.Buffer
+
+	if format == "png" {
+		require.NoError(t, png.Encode(&buf, img))
+	} else {
+		require.NoError(t, jpeg.Encode(&buf, img, nil))
+	}
+
+	return buf.Bytes()
+}
+
+func TestUploadAvatar(t *testing.T) {
+	ctx := context.Background()
+	f := newAvatarFixture(t)
+
+	user, err := f.service.UploadAvatar(ctx, f.user.ID(), bytes.NewReader(encodeTestImage(t, "jpeg", 600, 400)))
+	require.NoError(t, err)
+
+	avatar := user.Avatar()
+	assert.Equal(t, entities.AvatarSize, avatar.Width, "scaled down")
+	assert.Equal(t, entities.AvatarSize, avatar.Height, "cropped to a square")
+	assert.Equal(t, "image/jpeg", avatar.ContentType)
+	assert.True(t, strings.HasPrefix(avatar.Key, fmt.Sprintf("avatars/%d/", f.user.ID())), avatar.Key)
+	assert.Equal(t, "https://cdn.example.com/"+avatar.Key, avatar.URL)
+	assert.Equal(t, []string{avatar.Key}, f.blobs.Keys())
+
+	stored, err := f.users.GetByID(ctx, f.user.ID())
+	require.NoError(t, err)
+	assert.Equal(t, avatar.Key, stored.Avatar().Key)
+	assert.Equal(t, avatar.Size, stored.Avatar().Size)
+
+	// Small PNG images keep their size and format, and replace the old avatar.
+	user, err = f.service.UploadAvatar(ctx, f.user.ID(), bytes.NewReader(encodeTestImage(t, "png", 64, 64)))
+	require.NoError(t, err)
+
+	replaced := user.Avatar()
+	assert.Equal(t, 64, replaced.Width)
+	assert.Equal(t, "image/png", replaced.ContentType)
+	assert.Equal(t, []string{replaced.Key}, f.blobs.Keys(), "old avatar deleted")
+
+	require.Len(t, f.audit.entries, 2)
+	assert.Equal(t, entities.AuditActionAvatarChange, f.audit.entries[1].Action)
+	assert.Equal(t, avatar.URL, f.audit.entries[1].Changes[0].Old)
+
+	user, err = f.service.RemoveAvatar(ctx, f.user.ID())
+	require.NoError(t, err)
+	assert.True(t, user.Avatar().IsZero())
+	assert.Empty(t, f.blobs.Keys())
+}
+
+func TestUploadAvatarRejects(t *testing.T) {
+	ctx := context.Background()
+	f := newAvatarFixture(t)
+
+	tests := []struct {
+		name   string
+		upload []byte
+		err    error
+	}{
+		{name: "truncated", upload: []byte("GIF89a"), err: entities.ErrInvalidAvatar},
+		{name: "text", upload: []byte("hello"), err: entities.ErrInvalidAvatar},
+		{
+			name:   "too many pixels",
+			upload: encodeTestImage(t, "png", entities.AvatarMaxDimension+1, 1),
+			err:    entities.ErrAvatarTooLarge,
+		},
+		{name: "too many bytes", upload: make([]byte, entities.AvatarMaxBytes+1), err: entities.ErrAvatarTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := f.service.UploadAvatar(ctx, f.user.ID(), bytes.NewReader(tt.upload))
+			require.ErrorIs(t, err, tt.err)
+		})
+	}
+
+	assert.Empty(t, f.blobs.Keys())
+}
+
+func TestAvatarSurvivesMetadataUpdates(t *testing.T) {
+	ctx := context.Background()
+	f := newAvatarFixture(t)
+
+	user, err := f.service.UploadAvatar(ctx, f.user.ID(), bytes.NewReader(encodeTestImage(t, "png", 32, 32)))
+	require.NoError(t, err)
+
+	metadata := map[string]any{"bio": "hi", entities.AvatarMetadataKey: map[string]any{"key": "avatars/999/x.png"}}
+	user, err = f.service.UpdateUser(ctx, &services.UpdateUserRequest{UserID: f.user.ID(), Metadata: &metadata})
+	require.NoError(t, err)
+
+	assert.Equal(t, "hi", user.Metadata()["bio"])
+	assert.Equal(t, []string{user.Avatar().Key}, f.blobs.Keys(), "avatar links cannot be overwritten")
+}
+
+func TestAvatarDeletedWithUser(t *testing.T) {
+	ctx := context.Background()
+
+	for name, remove := range map[string]func(*services.UserService, entities.UserID) error{
+		"delete": func(s *services.UserService, id entities.UserID) error { return s.DeleteUser(ctx, id) },
+		"erase":  func(s *services.UserService, id entities.UserID) error { return s.EraseUser(ctx, id) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			f := newAvatarFixture(t)
+
+			_, err := f.service.UploadAvatar(ctx, f.user.ID(), bytes.NewReader(encodeTestImage(t, "png", 32, 32)))
+			require.NoError(t, err)
+			require.Len(t, f.blobs.Keys(), 1)
+
+			require.NoError(t, remove(f.service, f.user.ID()))
+			assert.Empty(t, f.blobs.Keys())
+
+			require.NoError(t, f.users.Restore(ctx, f.user.ID()))
+			restored, err := f.users.GetByID(ctx, f.user.ID())
+			require.NoError(t, err)
+			assert.True(t, restored.Avatar().IsZero())
+		})
+	}
+}
+
+func TestAvatarsUnavailable(t *testing.T) {
+	f := newServiceFixture()
+	f.start()
+
+	_, err := f.service.UploadAvatar(context.Background(), 1, strings.NewReader(""))
+	require.ErrorIs(t, err, services.ErrAvatarsUnavailable)
+}
@@ -0,0 +1,125 @@
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/cache"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingUserRepository serves one stored user and counts database reads.
+type countingUserRepository struct {
+	repositories.UserRepository
+
+	user  *entities.User
+	reads int
+}
+
+func (r *countingUserRepository) GetByID(_ context.Context, id entities.UserID) (*entities.User, error) {
+	r.reads++
+	if id != r.user.ID() {
+		return nil, entities.ErrUserNotFound
+	}
+
+	return r.user, nil
+}
+
+func (r *countingUserRepository) GetByEmail(_ context.Context, email entities.Email) (*entities.User, error) {
+	r.reads++
+	if email != r.user.Email() {
+		return nil, entities.ErrUserNotFound
+	}
+
+	return r.user, nil
+}
+
+func (r *countingUserRepository) Update(_ context.Context, user *entities.User) error {
+	r.user = user
+
+	return nil
+}
+
+func TestCachedUserRepository(t *testing.T) {
+	ctx := context.Background()
+
+	user := newTestUser(t, entities.UserRoleUser)
+	user.SetID(7)
+
+	backend, err := cache.NewLRUBackend(16)
+	require.NoError(t, err)
+
+	db := &countingUserRepository{user: user}
+	repo := cache.NewUserRepository(db, backend)
+
+	first, err := repo.GetByID(ctx, 7)
+	require.NoError(t, err)
+
+	second, err := repo.GetByEmail(ctx, user.Email())
+	require.NoError(t, err)
+	assert.Equal(t, 1, db.reads, "the email lookup is served from the cached user")
+	assert.Equal(t, first.UUID(), second.UUID())
+	assert.Equal(t, user.Metadata(), second.Metadata())
+
+	record := user.Record()
+	record.Email = entities.Email("renamed@example.com")
+	renamed, err := entities.ReconstructUser(record)
+	require.NoError(t, err)
+	require.NoError(t, repo.Update(ctx, renamed))
+
+	got, err := repo.GetByID(ctx, 7)
+	require.NoError(t, err)
+	assert.Equal(t, 2, db.reads, "updates invalidate the cached user")
+	assert.Equal(t, renamed.Email(), got.Email())
+
+	_, err = repo.GetByEmail(ctx, user.Email())
+	require.ErrorIs(t, err, entities.ErrUserNotFound, "stale email keys are not trusted")
+	assert.Equal(t, 3, db.reads)
+
+	_, err = repo.GetByID(ctx, 8)
+	require.ErrorIs(t, err, entities.ErrUserNotFound)
+}
+
+func TestCachedUserRepositoryLeavesOutPasswordHashes(t *testing.T) {
+	ctx := context.Background()
+
+	user := newTestUser(t, entities.UserRoleUser)
+	user.SetID(7)
+	require.True(t, user.PasswordHash().IsUsable())
+
+	backend, err := cache.NewLRUBackend(16)
+	require.NoError(t, err)
+
+	repo := cache.NewUserRepository(&countingUserRepository{user: user}, backend)
+
+	_, err = repo.GetByID(ctx, 7)
+	require.NoError(t, err)
+
+	value, ok, err := backend.Get(ctx, "users:id:7")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.NotContains(t, string(value), user.PasswordHash().String())
+
+	cached, err := repo.GetByID(ctx, 7)
+	require.NoError(t, err)
+	assert.Empty(t, cached.PasswordHash())
+	assert.True(t, cached.PasswordHash().IsUsable())
+
+	record := user.Record()
+	record.Password = entities.NewUnusablePasswordHash()
+	passwordless, err := entities.ReconstructUser(record)
+	require.NoError(t, err)
+
+	repo = cache.NewUserRepository(&countingUserRepository{user: passwordless}, backend)
+	repo.Invalidate(ctx, 7)
+
+	_, err = repo.GetByID(ctx, 7)
+	require.NoError(t, err)
+
+	cached, err = repo.GetByID(ctx, 7)
+	require.NoError(t, err)
+	assert.False(t, cached.PasswordHash().IsUsable(), "cached users still tell they have no password")
+}
@@ -0,0 +1,336 @@
+package unit
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/memory"
+	"github.com/LarsArtmann/template-sqlc/internal/changefeed"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/services"
+	"github.com/LarsArtmann/template-sqlc/internal/replay"
+	"github.com/LarsArtmann/template-sqlc/internal/validation"
+	"github.com/LarsArtmann/template-sqlc/pkg/passwords"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// memorySource is a replication slot holding wal2json messages in memory.
+type memorySource struct {
+	messages []changefeed.Message
+	advanced changefeed.LSN
+}
+
+func (s *memorySource) CreateSlot(context.Context, string) error {
+	return nil
+}
+
+func (s *memorySource) Peek(context.Context, string, []string, int) ([]changefeed.Message, error) {
+	var held []changefeed.Message
+
+	for _, message := range s.messages {
+		if message.LSN > s.advanced {
+			held = append(held, message)
+		}
+	}
+
+	return held, nil
+}
+
+func (s *memorySource) Advance(_ context.Context, _ string, lsn changefeed.LSN) error {
+	s.advanced = lsn
+
+	return nil
+}
+
+// add appends a committed transaction of the wal2json changes, one LSN apart.
+func (s *memorySource) add(changes ...string) changefeed.LSN {
+	lsn := changefeed.LSN(len(s.messages) + 1)
+
+	for _, data := range append(append([]string{`{"action":"B"}`}, changes...), `{"action":"C"}`) {
+		s.messages = append(s.messages, changefeed.Message{LSN: lsn, Data: []byte(data)})
+		lsn++
+	}
+
+	return lsn - 1
+}
+
+// flakyPublisher fails the next failures publishes before publishing to events.
+type flakyPublisher struct {
+	*events.InMemoryEventPublisher
+
+	failures int
+}
+
+func (p *flakyPublisher) Publish(event *events.UserEvent) error {
+	if p.failures > 0 {
+		p.failures--
+
+		return errBrokerDown
+	}
+
+	return p.InMemoryEventPublisher.Publish(event)
+}
+
+// convertChange decodes a wal2json change and converts it.
+func convertChange(t *testing.T, converter changefeed.Converter, data string) []*events.UserEvent {
+	t.Helper()
+
+	change, err := changefeed.Decode(changefeed.Message{LSN: 1, Data: []byte(data)})
+	require.NoError(t, err)
+
+	converted, err := converter(change)
+	require.NoError(t, err)
+
+	return converted
+}
+
+const (
+	adaInserted = `{"action":"I","schema":"public","table":"users","columns":[
+		{"name":"id","type":"bigint","value":7},{"name":"email","type":"text","value":"ada@example.com"},
+		{"name":"username","type":"text","value":"ada"},{"name":"first_name","type":"text","value":"Ada"},
+		{"name":"last_name","type":"text","value":"Lovelace"},{"name":"role","type":"text","value":"user"},
+		{"name":"status","type":"text","value":"active"},{"name":"password_hash","type":"text","value":"secret"}]}`
+	adaRenamed = `{"action":"U","schema":"public","table":"users","columns":[
+		{"name":"id","type":"bigint","value":7},{"name":"first_name","type":"text","value":"Augusta"},
+		{"name":"updated_at","type":"timestamptz","value":"2026-10-15 12:00:00+00"}],
+		"identity":[{"name":"id","type":"bigint","value":7},{"name":"first_name","type":"text","value":"Ada"},
+		{"name":"updated_at","type":"timestamptz","value":"2026-10-14 12:00:00+00"}]}`
+)
+
+func TestParseLSN(t *testing.T) {
+	lsn, err := changefeed.ParseLSN("16/B374D848")
+	require.NoError(t, err)
+	assert.Equal(t, changefeed.LSN(0x16B374D848), lsn)
+	assert.Equal(t, "16/B374D848", lsn.String())
+
+	for _, invalid := range []string{"", "16", "16/XYZ", "100000000/0"} {
+		_, err = changefeed.ParseLSN(invalid)
+		require.ErrorIs(t, err, changefeed.ErrInvalidLSN, invalid)
+	}
+}
+
+func TestDecodeWal2JSON(t *testing.T) {
+	change, err := changefeed.Decode(changefeed.Message{LSN: 42, Data: []byte(adaInserted)})
+	require.NoError(t, err)
+	assert.Equal(t, changefeed.ActionInsert, change.Action)
+	assert.Equal(t, "public", change.Schema)
+	assert.Equal(t, "users", change.Table)
+	assert.Equal(t, changefeed.LSN(42), change.LSN)
+
+	id, ok := change.New("id")
+	require.True(t, ok)
+	assert.Equal(t, "bigint", id.Type)
+	assert.EqualValues(t, "7", id.Value, "numbers are kept as json.Number")
+
+	_, err = changefeed.Decode(changefeed.Message{Data: []byte(`{"action":"X"}`)})
+	require.ErrorIs(t, err, changefeed.ErrUnknownAction)
+
+	_, err = changefeed.Decode(changefeed.Message{Data: []byte(`not json`)})
+	require.ErrorIs(t, err, changefeed.ErrInvalidChange)
+}
+
+func TestUserChanges(t *testing.T) {
+	created := convertChange(t, changefeed.UserChanges, adaInserted)
+	require.Len(t, created, 1)
+	assert.Equal(t, events.UserCreatedEvent{
+		UserID: 7, Email: "ada@example.com", Username: "ada", FirstName: "Ada", LastName: "Lovelace",
+		Role: "user", Status: "active",
+	}, created[0].Data)
+
+	updated := convertChange(t, changefeed.UserChanges, adaRenamed)
+	require.Len(t, updated, 1)
+	assert.Equal(t, events.EventUserUpdated, updated[0].Type)
+	assert.Equal(t, map[string]any{"first_name": map[string]any{"old": "Ada", "new": "Augusta"}},
+		updated[0].Data.(events.UserUpdatedEvent).Changes)
+
+	tests := []struct {
+		name   string
+		change string
+		want   []events.EventType
+	}{
+		{"soft delete", `{"action":"U","schema":"public","table":"users",
+			"columns":[{"name":"id","value":7},{"name":"deleted_at","value":"2026-10-15 12:00:00+00"}],
+			"identity":[{"name":"id","value":7},{"name":"deleted_at","value":null}]}`,
+			[]events.EventType{events.EventUserDeleted}},
+		{"restore", `{"action":"U","schema":"public","table":"users",
+			"columns":[{"name":"id","value":7},{"name":"deleted_at","value":null}],
+			"identity":[{"name":"id","value":7},{"name":"deleted_at","value":"2026-10-15 12:00:00+00"}]}`,
+			[]events.EventType{events.EventUserRestored}},
+		{"role change", `{"action":"U","schema":"public","table":"users",
+			"columns":[{"name":"id","value":7},{"name":"role","value":"admin"}],
+			"identity":[{"name":"id","value":7},{"name":"role","value":"user"}]}`,
+			[]events.EventType{events.EventRoleChanged}},
+		{"login timestamp", `{"action":"U","schema":"public","table":"users",
+			"columns":[{"name":"id","value":7},{"name":"last_login_at","value":"2026-10-15 12:00:00+00"}],
+			"identity":[{"name":"id","value":7},{"name":"last_login_at","value":null}]}`,
+			nil},
+		{"erasure", `{"action":"D","schema":"public","table":"users","identity":[{"name":"id","value":7}]}`,
+			[]events.EventType{events.EventUserErased}},
+		{"truncate", `{"action":"T","schema":"public","table":"users"}`, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var types []events.EventType
+			for _, event := range convertChange(t, changefeed.UserChanges, tt.change) {
+				assert.Equal(t, entities.UserID(7), event.UserID)
+
+				types = append(types, event.Type)
+			}
+
+			assert.Equal(t, tt.want, types)
+		})
+	}
+}
+
+func TestSessionChanges(t *testing.T) {
+	login := convertChange(t, changefeed.SessionChanges, `{"action":"I","schema":"public","table":"user_sessions",
+		"columns":[{"name":"id","value":3},{"name":"user_id","value":7},{"name":"ip_address","value":"192.0.2.1"},
+		{"name":"user_agent","value":"curl"},{"name":"device_info","value":"{\"device\":\"laptop\"}"},
+		{"name":"is_active","value":true}]}`)
+	require.Len(t, login, 1)
+	assert.Equal(t, events.UserLoginEvent{
+		UserID: 7, IPAddress: "192.0.2.1", UserAgent: "curl", Device: "laptop", Success: true,
+	}, login[0].Data)
+
+	logout := convertChange(t, changefeed.SessionChanges, `{"action":"U","schema":"public","table":"user_sessions",
+		"columns":[{"name":"id","value":3},{"name":"user_id","value":7},{"name":"is_active","value":false}],
+		"identity":[{"name":"id","value":3},{"name":"user_id","value":7},{"name":"is_active","value":true}]}`)
+	require.Len(t, logout, 1)
+	assert.Equal(t, events.UserLogoutEvent{UserID: 7, SessionID: 3}, logout[0].Data)
+
+	// Deleting a session that was signed out before is not a second logout.
+	assert.Empty(t, convertChange(t, changefeed.SessionChanges, `{"action":"D","schema":"public",
+		"table":"user_sessions","identity":[{"name":"id","value":3},{"name":"user_id","value":7},
+		{"name":"is_active","value":false}]}`))
+}
+
+func TestListenerPublishesCommittedTransactions(t *testing.T) {
+	ctx := context.Background()
+	source := &memorySource{}
+	checkpoints := replay.NewInMemoryCheckpointStore()
+	publisher := events.NewInMemoryEventPublisher()
+
+	listener, err := changefeed.NewListener(source, checkpoints, publisher, "users_changefeed")
+	require.NoError(t, err)
+
+	source.add(adaInserted)
+	commit := source.add(adaRenamed, `{"action":"I","schema":"public","table":"organizations",
+		"columns":[{"name":"id","value":1}]}`)
+
+	read, err := listener.Poll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 7, read)
+
+	published := publisher.Events()
+	require.Len(t, published, 2)
+	assert.Equal(t, events.EventUserCreated, published[0].Type)
+	assert.Equal(t, events.EventUserUpdated, published[1].Type)
+
+	position, err := checkpoints.Load(ctx, "users_changefeed")
+	require.NoError(t, err)
+	assert.Equal(t, int64(commit), position)
+	assert.Equal(t, commit, source.advanced)
+
+	read, err = listener.Poll(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, read)
+	assert.Len(t, publisher.Events(), 2)
+}
+
+func TestListenerReplaysUnpublishedTransactions(t *testing.T) {
+	ctx := context.Background()
+	source := &memorySource{}
+	checkpoints := replay.NewInMemoryCheckpointStore()
+	publisher := &flakyPublisher{InMemoryEventPublisher: events.NewInMemoryEventPublisher(), failures: 1}
+
+	listener, err := changefeed.NewListener(source, checkpoints, publisher, "users_changefeed")
+	require.NoError(t, err)
+
+	first := source.add(adaInserted)
+	source.add(adaRenamed)
+
+	// A crash after checkpointing the first transaction, but before the
+	// slot was advanced, leaves it in the slot.
+	require.NoError(t, checkpoints.Save(ctx, "users_changefeed", int64(first)))
+
+	_, err = listener.Poll(ctx)
+	require.ErrorIs(t, err, errBrokerDown)
+	assert.Empty(t, publisher.Events())
+	assert.Zero(t, source.advanced)
+
+	_, err = listener.Poll(ctx)
+	require.NoError(t, err)
+
+	published := publisher.Events()
+	require.Len(t, published, 1, "the checkpointed transaction is skipped")
+	assert.Equal(t, events.EventUserUpdated, published[0].Type)
+}
+
+func TestNewListenerRejects(t *testing.T) {
+	source := &memorySource{}
+	checkpoints := replay.NewInMemoryCheckpointStore()
+	publisher := events.NewInMemoryEventPublisher()
+
+	_, err := changefeed.NewListener(source, checkpoints, publisher, "")
+	require.ErrorIs(t, err, changefeed.ErrSlotRequired)
+
+	_, err = changefeed.NewListener(source, checkpoints, publisher, "users_changefeed",
+		changefeed.WithConverters(nil))
+	require.ErrorIs(t, err, changefeed.ErrNoConverters)
+}
+
+func TestChangefeedDeliversTableEventsOnce(t *testing.T) {
+	ctx := context.Background()
+	dispatcher := events.NewDispatcher()
+
+	var created atomic.Int32
+
+	dispatcher.Subscribe("activity", events.EventHandlerFunc(func(context.Context, *events.UserEvent) error {
+		created.Add(1)
+
+		return nil
+	}), events.EventUserCreated)
+
+	// The service publishes as the server wires it while the changefeed runs.
+	service := services.NewUserService(memory.NewUserRepository(), memory.NewSessionRepository(),
+		events.NewFilteringPublisher(dispatcher, changefeed.EventTypes()...), validation.NewUserValidator(),
+		services.WithPasswordHasher(passwords.NewBcryptHasher(bcrypt.MinCost)))
+
+	_, err := service.CreateUser(ctx, &services.CreateUserRequest{
+		Email: "ada@example.com", Username: "ada", Password: "Sup3r-secret!", FirstName: "Ada", LastName: "Lovelace",
+		Status: "active", Role: "user",
+	})
+	require.NoError(t, err)
+
+	source := &memorySource{}
+	source.add(adaInserted)
+
+	listener, err := changefeed.NewListener(source, replay.NewInMemoryCheckpointStore(), dispatcher, "users_changefeed")
+	require.NoError(t, err)
+
+	_, err = listener.Poll(ctx)
+	require.NoError(t, err)
+	require.NoError(t, dispatcher.Close(ctx))
+
+	assert.Equal(t, int32(1), created.Load(), "user.created is delivered by the changefeed only")
+}
+
+func TestFilteringPublisherDropsTypes(t *testing.T) {
+	next := events.NewInMemoryEventPublisher()
+	publisher := events.NewFilteringPublisher(next, events.EventUserLogin)
+
+	require.NoError(t, publisher.Publish(events.UserLoggedIn(1, "203.0.113.7", "", "")))
+	require.NoError(t, publisher.PublishBatch([]*events.UserEvent{
+		events.UserLoggedIn(1, "203.0.113.7", "", ""),
+		events.UserVerified(1, "email"),
+	}))
+
+	published := next.Events()
+	require.Len(t, published, 1)
+	assert.Equal(t, events.EventUserVerified, published[0].Type)
+}
@@ -0,0 +1,76 @@
+package unit
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testCodec = events.CloudEventCodec{Source: "/template-sqlc/users", TypePrefix: "com.example.users."}
+
+// assertSameEvent compares a decoded event with the original, including its data payload.
+func assertSameEvent(t *testing.T, want, got *events.UserEvent) {
+	t.Helper()
+
+	assert.Equal(t, want.ID, got.ID)
+	assert.Equal(t, want.Type, got.Type)
+	assert.Equal(t, want.UserID, got.UserID)
+	assert.Equal(t, want.Version, got.Version)
+	assert.Equal(t, want.TraceParent, got.TraceParent)
+	assert.True(t, want.Timestamp.Equal(got.Timestamp))
+
+	wantData, err := json.Marshal(want.Data)
+	require.NoError(t, err)
+	gotData, err := json.Marshal(got.Data)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(wantData), string(gotData))
+}
+
+func TestCloudEventStructuredRoundTrip(t *testing.T) {
+	event := events.UserCreated(42, "a@example.com", "alice", "Alice", "Doe", "user", "active")
+
+	payload, err := testCodec.Serialize(event)
+	require.NoError(t, err)
+
+	var envelope map[string]any
+	require.NoError(t, json.Unmarshal(payload, &envelope))
+	assert.Equal(t, "1.0", envelope["specversion"])
+	assert.Equal(t, "/template-sqlc/users", envelope["source"])
+	assert.Equal(t, "com.example.users.user.created", envelope["type"])
+	assert.Equal(t, "42", envelope["subject"])
+
+	decoded, err := testCodec.Deserialize(payload)
+	require.NoError(t, err)
+	assertSameEvent(t, event, decoded)
+
+	var data events.UserCreatedEvent
+	require.NoError(t, json.Unmarshal(decoded.Data.(json.RawMessage), &data))
+	assert.Equal(t, "alice", data.Username)
+}
+
+func TestCloudEventBinaryRoundTrip(t *testing.T) {
+	event := events.UserVerified(42, "email")
+	event.TraceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	header := http.Header{}
+
+	body, err := testCodec.WriteBinary(header, event)
+	require.NoError(t, err)
+	assert.Equal(t, "application/json", header.Get("Content-Type"))
+	assert.Equal(t, "com.example.users.user.verified", header.Get("ce-type"))
+
+	decoded, err := testCodec.ReadBinary(header, body)
+	require.NoError(t, err)
+	assertSameEvent(t, event, decoded)
+}
+
+func TestCloudEventRejectsForeignType(t *testing.T) {
+	payload, err := events.CloudEventCodec{TypePrefix: "org.other."}.Serialize(events.UserVerified(1, "email"))
+	require.NoError(t, err)
+
+	_, err = testCodec.Deserialize(payload)
+	require.ErrorIs(t, err, events.ErrInvalidCloudEvent)
+}
@@ -0,0 +1,129 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/cockroachdb"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	apperrors "github.com/LarsArtmann/template-sqlc/pkg/errors"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTx records how a transaction ended. Methods it does not override
+// panic through the nil embedded pgx.Tx.
+type fakeTx struct {
+	pgx.Tx
+
+	commitErr  error
+	committed  bool
+	rolledBack bool
+}
+
+func (tx *fakeTx) Commit(context.Context) error {
+	tx.committed = true
+
+	return tx.commitErr
+}
+
+func (tx *fakeTx) Rollback(context.Context) error {
+	tx.rolledBack = true
+
+	return nil
+}
+
+// fakeBeginner hands out txs to the first attempts and new transactions
+// to later ones.
+type fakeBeginner struct {
+	txs   []*fakeTx
+	begun int
+}
+
+func (b *fakeBeginner) BeginTx(context.Context, pgx.TxOptions) (pgx.Tx, error) {
+	tx := &fakeTx{}
+	if b.begun < len(b.txs) {
+		tx = b.txs[b.begun]
+	}
+
+	b.begun++
+
+	return tx, nil
+}
+
+func newFakeBeginner(txs ...*fakeTx) *fakeBeginner {
+	return &fakeBeginner{txs: txs}
+}
+
+var errSerialization = &pgconn.PgError{Code: cockroachdb.SerializationFailure, Message: "restart transaction"}
+
+func TestCockroachDBExecuteTxRetriesSerializationFailures(t *testing.T) {
+	first, second := &fakeTx{}, &fakeTx{}
+	db := newFakeBeginner(first, second)
+	attempts := 0
+
+	err := cockroachdb.ExecuteTx(context.Background(), db, func(pgx.Tx) error {
+		attempts++
+		if attempts == 1 {
+			// Repositories wrap driver errors.
+			return apperrors.NewDatabaseError("update user failed", errSerialization)
+		}
+
+		return nil
+	}, cockroachdb.WithRetryBackoff(0))
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, attempts)
+	assert.True(t, first.rolledBack)
+	assert.False(t, first.committed)
+	assert.True(t, second.committed)
+}
+
+func TestCockroachDBExecuteTxRetriesFailedCommits(t *testing.T) {
+	first, second := &fakeTx{commitErr: errSerialization}, &fakeTx{}
+	db := newFakeBeginner(first, second)
+
+	err := cockroachdb.ExecuteTx(context.Background(), db, func(pgx.Tx) error { return nil },
+		cockroachdb.WithRetryBackoff(0))
+	require.NoError(t, err)
+	assert.True(t, second.committed)
+}
+
+func TestCockroachDBExecuteTxStopsRetrying(t *testing.T) {
+	attempts := 0
+
+	err := cockroachdb.ExecuteTx(context.Background(), newFakeBeginner(), func(pgx.Tx) error {
+		attempts++
+
+		return errSerialization
+	}, cockroachdb.WithMaxRetries(2), cockroachdb.WithRetryBackoff(0))
+	require.ErrorIs(t, err, cockroachdb.ErrRetriesExhausted)
+	assert.True(t, cockroachdb.IsRetryable(err))
+	assert.Equal(t, 3, attempts)
+}
+
+func TestCockroachDBExecuteTxReturnsOtherErrors(t *testing.T) {
+	tx := &fakeTx{}
+	attempts := 0
+
+	err := cockroachdb.ExecuteTx(context.Background(), newFakeBeginner(tx), func(pgx.Tx) error {
+		attempts++
+
+		return entities.ErrUserAlreadyExists
+	})
+	require.ErrorIs(t, err, entities.ErrUserAlreadyExists)
+	assert.Equal(t, 1, attempts)
+	assert.True(t, tx.rolledBack)
+}
+
+func TestCockroachDBExecuteTxHonorsCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := cockroachdb.ExecuteTx(ctx, newFakeBeginner(), func(pgx.Tx) error { return errSerialization })
+	require.ErrorIs(t, err, context.Canceled)
+	assert.False(t, errors.Is(err, cockroachdb.ErrRetriesExhausted))
+}
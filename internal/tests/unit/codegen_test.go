@@ -0,0 +1,95 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/pkg/codegen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeGenerator stands in for sqlc and creates the output directory.
+type fakeGenerator struct {
+	runs int
+	err  error
+}
+
+func (g *fakeGenerator) Generate(_ context.Context, dir, _ string, out io.Writer) error {
+	g.runs++
+	fmt.Fprintln(out, "generated users.sql")
+
+	if g.err != nil {
+		return g.err
+	}
+
+	return os.MkdirAll(filepath.Join(dir, "db"), 0o750)
+}
+
+type codegenObserver struct{ errs []error }
+
+func (o *codegenObserver) ObserveCodeGen(_ time.Duration, err error) { o.errs = append(o.errs, err) }
+
+func TestCodegenSkipsUnchangedInputs(t *testing.T) {
+	dir := t.TempDir()
+	config := filepath.Join(dir, "sqlc.yaml")
+
+	writeFile(t, config, `version: "2"
+sql:
+  - engine: sqlite
+    schema: schema.sql
+    queries: queries
+    gen:
+      go:
+        package: db
+        out: db
+`)
+	writeFile(t, filepath.Join(dir, "schema.sql"), "CREATE TABLE users (id INTEGER);")
+	writeFile(t, filepath.Join(dir, "queries", "users.sql"), "-- name: ListUsers :many\nSELECT id FROM users;")
+
+	generator := &fakeGenerator{}
+	observer := &codegenObserver{}
+
+	var stages []codegen.Stage
+
+	runner := codegen.New(
+		codegen.WithGenerator(generator),
+		codegen.WithObserver(observer),
+		codegen.WithProgress(func(event codegen.Event) { stages = append(stages, event.Stage) }),
+	)
+
+	first, err := runner.Run(context.Background(), config)
+	require.NoError(t, err)
+	assert.False(t, first.Cached)
+	assert.Equal(t, []string{"sqlc.yaml", filepath.Join("queries", "users.sql"), "schema.sql"}, first.Inputs)
+	assert.Equal(t, []codegen.Stage{
+		codegen.StageHashing, codegen.StageGenerating, codegen.StageOutput, codegen.StageDone,
+	}, stages)
+
+	second, err := runner.Run(context.Background(), config)
+	require.NoError(t, err)
+	assert.True(t, second.Cached)
+	assert.Equal(t, first.Hash, second.Hash)
+	assert.Equal(t, 1, generator.runs)
+
+	writeFile(t, filepath.Join(dir, "queries", "users.sql"), "-- name: ListUsers :many\nSELECT id FROM users LIMIT 10;")
+
+	third, err := runner.Run(context.Background(), config)
+	require.NoError(t, err)
+	assert.False(t, third.Cached)
+	assert.NotEqual(t, first.Hash, third.Hash)
+	assert.Equal(t, 2, generator.runs)
+	assert.Equal(t, []error{nil, nil}, observer.errs, "cached runs are not observed")
+
+	generator.err = errors.New("exit status 1")
+
+	_, err = codegen.New(codegen.WithGenerator(generator), codegen.WithForce(true)).Run(context.Background(), config)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "generated users.sql")
+}
@@ -0,0 +1,304 @@
+package unit
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/converters"
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/mappers"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"pgregory.net/rapid"
+)
+
+// engines lists every engine the converters support.
+var engines = []string{
+	converters.DbTypeSQLite, converters.DbTypePostgres, converters.DbTypeMySQL, converters.DbTypeCockroachDB,
+}
+
+// uuidGen draws arbitrary UUIDs, including uuid.Nil.
+func uuidGen() *rapid.Generator[uuid.UUID] {
+	return rapid.OneOf(
+		rapid.Just(uuid.Nil),
+		rapid.Custom(func(t *rapid.T) uuid.UUID {
+			var id uuid.UUID
+			copy(id[:], rapid.SliceOfN(rapid.Byte(), len(id), len(id)).Draw(t, "bytes"))
+
+			return id
+		}),
+	)
+}
+
+// timeGen draws UTC times between years 1 and 9999, including the zero time.
+func timeGen() *rapid.Generator[time.Time] {
+	return rapid.OneOf(
+		rapid.Just(time.Time{}),
+		rapid.Custom(func(t *rapid.T) time.Time {
+			sec := rapid.Int64Range(-62135596800, 253402300799).Draw(t, "sec")
+			nsec := rapid.Int64Range(0, int64(time.Second)-1).Draw(t, "nsec")
+
+			return time.Unix(sec, nsec).UTC()
+		}),
+	)
+}
+
+// metadataValueGen draws values that survive a JSON round trip unchanged.
+func metadataValueGen() *rapid.Generator[any] {
+	return rapid.OneOf(
+		rapid.Just[any](nil),
+		rapid.Map(rapid.Bool(), func(v bool) any { return v }),
+		rapid.Map(rapid.String(), func(v string) any { return v }),
+		rapid.Map(rapid.Float64Range(-math.MaxFloat64, math.MaxFloat64), func(v float64) any { return v }),
+		rapid.Map(rapid.SliceOf(rapid.String()), func(v []string) any {
+			values := make([]any, len(v))
+			for i, s := range v {
+				values[i] = s
+			}
+
+			return values
+		}),
+	)
+}
+
+// metadataGen draws user metadata, including nil.
+func metadataGen() *rapid.Generator[entities.UserMetadata] {
+	return rapid.OneOf(
+		rapid.Just[entities.UserMetadata](nil),
+		rapid.Map(rapid.MapOf(rapid.String(), metadataValueGen()), func(v map[string]any) entities.UserMetadata {
+			return entities.UserMetadata(v)
+		}),
+	)
+}
+
+func TestUUIDConverterRoundTripProperty(t *testing.T) {
+	for _, engine := range engines {
+		t.Run(engine, func(t *testing.T) {
+			converter := converters.NewUUIDConverter(engine)
+
+			rapid.Check(t, func(t *rapid.T) {
+				id := uuidGen().Draw(t, "id")
+
+				parsed, err := converter.DBToDomain(converter.DomainToDB(id))
+				require.NoError(t, err)
+				assert.Equal(t, id, parsed)
+
+				value, err := converters.Default().ToDB(engine, id)
+				require.NoError(t, err)
+
+				parsed, err = converters.FromDB[uuid.UUID](converters.Default(), engine, value)
+				require.NoError(t, err)
+				assert.Equal(t, id, parsed)
+			})
+		})
+	}
+}
+
+func TestNullableUUIDConverterRoundTripProperty(t *testing.T) {
+	converter := converters.NewPgUUIDConverter()
+
+	rapid.Check(t, func(t *rapid.T) {
+		id := rapid.Ptr(uuidGen(), true).Draw(t, "id")
+
+		parsed, err := converter.DBToDomain(converter.DomainToDB(id))
+		require.NoError(t, err)
+		assert.Equal(t, id, parsed)
+	})
+}
+
+func TestTimeConverterRoundTripProperty(t *testing.T) {
+	for _, engine := range engines {
+		t.Run(engine, func(t *testing.T) {
+			converter := converters.NewTimeConverter(engine)
+
+			rapid.Check(t, func(t *rapid.T) {
+				at := timeGen().Draw(t, "at")
+
+				parsed, err := converter.DBToDomain(converter.DomainToDB(at))
+				require.NoError(t, err)
+				assert.True(t, at.Equal(parsed), "want %v, got %v", at, parsed)
+
+				// SQLite may hand timestamps back as RFC 3339 text.
+				if !at.IsZero() {
+					parsed, err = converter.DBToDomain(at.Format(time.RFC3339))
+					require.NoError(t, err)
+					assert.True(t, at.Truncate(time.Second).Equal(parsed), "want %v, got %v", at, parsed)
+				}
+			})
+		})
+	}
+}
+
+func TestNullableTimeConverterRoundTripProperty(t *testing.T) {
+	nullTime := converters.NewNullTimeConverter()
+	timestamptz := converters.NewTimestamptzConverter()
+
+	rapid.Check(t, func(t *rapid.T) {
+		at := rapid.Ptr(timeGen(), true).Draw(t, "at")
+
+		parsed, err := nullTime.DBToDomain(nullTime.DomainToDB(at))
+		require.NoError(t, err)
+		assert.Equal(t, at, parsed)
+
+		parsed, err = timestamptz.DBToDomain(timestamptz.DomainToDB(at))
+		require.NoError(t, err)
+		assert.Equal(t, at, parsed)
+
+		if at == nil {
+			decoded, err := mappers.DecodeNullableTime(nil)
+			require.NoError(t, err)
+			assert.Nil(t, decoded)
+
+			return
+		}
+
+		decoded, err := mappers.DecodeNullableTime(*at)
+		require.NoError(t, err)
+		assert.Equal(t, at, decoded)
+
+		decoded, err = mappers.DecodeNullableTime(at.Format(time.RFC3339Nano))
+		require.NoError(t, err)
+		require.NotNil(t, decoded)
+		assert.True(t, at.Equal(*decoded), "want %v, got %v", at, decoded)
+	})
+}
+
+// TestNullableTimeConverterRoundTripNil pins the case rapid once shrank
+// TestNullableTimeConverterRoundTripProperty to: a nil time must survive
+// every converter, not fail as an unsupported database model.
+func TestNullableTimeConverterRoundTripNil(t *testing.T) {
+	var at *time.Time
+
+	nullTime := converters.NewNullTimeConverter()
+	parsed, err := nullTime.DBToDomain(nullTime.DomainToDB(at))
+	require.NoError(t, err)
+	assert.Nil(t, parsed)
+
+	timestamptz := converters.NewTimestamptzConverter()
+	parsed, err = timestamptz.DBToDomain(timestamptz.DomainToDB(at))
+	require.NoError(t, err)
+	assert.Nil(t, parsed)
+
+	decoded, err := mappers.DecodeNullableTime(at)
+	require.NoError(t, err)
+	assert.Nil(t, decoded)
+}
+
+func TestBoolConverterRoundTripProperty(t *testing.T) {
+	for _, engine := range engines {
+		t.Run(engine, func(t *testing.T) {
+			converter := converters.NewBoolConverter(engine)
+
+			rapid.Check(t, func(t *rapid.T) {
+				value := rapid.Bool().Draw(t, "value")
+
+				parsed, err := converter.DBToDomain(converter.DomainToDB(value))
+				require.NoError(t, err)
+				assert.Equal(t, value, parsed)
+
+				// SQLite and MySQL store booleans as integers.
+				var stored int64
+				if value {
+					stored = 1
+				}
+
+				parsed, err = converter.DBToDomain(stored)
+				require.NoError(t, err)
+				assert.Equal(t, value, parsed)
+			})
+		})
+	}
+
+	nullBool := converters.NewNullBoolConverter()
+
+	rapid.Check(t, func(t *rapid.T) {
+		value := rapid.Ptr(rapid.Bool(), true).Draw(t, "value")
+
+		parsed, err := nullBool.DBToDomain(nullBool.DomainToDB(value))
+		require.NoError(t, err)
+		assert.Equal(t, value, parsed)
+	})
+}
+
+func TestMetadataRoundTripProperty(t *testing.T) {
+	rapid.Check(t, func(t *rapid.T) {
+		metadata := metadataGen().Draw(t, "metadata")
+
+		encoded, err := mappers.EncodeMetadata(metadata)
+		require.NoError(t, err)
+
+		want := metadata
+		if want == nil {
+			want = entities.NewUserMetadata()
+		}
+
+		decoded, err := mappers.DecodeMetadata(encoded)
+		require.NoError(t, err)
+		assert.Equal(t, want, decoded)
+
+		decoded, err = mappers.DecodeMetadata([]byte(encoded))
+		require.NoError(t, err)
+		assert.Equal(t, want, decoded)
+	})
+}
+
+func TestTagsRoundTripProperty(t *testing.T) {
+	rapid.Check(t, func(t *rapid.T) {
+		tags := rapid.OneOf(rapid.Just[[]string](nil), rapid.SliceOf(rapid.String())).Draw(t, "tags")
+
+		want := tags
+		if want == nil {
+			want = []string{}
+		}
+
+		encoded, err := mappers.EncodeTags(tags)
+		require.NoError(t, err)
+
+		decoded, err := mappers.DecodeTags(encoded)
+		require.NoError(t, err)
+		assert.Equal(t, want, decoded)
+
+		decoded, err = mappers.DecodeTags(tags)
+		require.NoError(t, err)
+		assert.Equal(t, tags, decoded)
+
+		reencoded, err := mappers.TagsJSON[string](encoded)
+		require.NoError(t, err)
+		assert.Equal(t, encoded, reencoded)
+	})
+}
+
+func TestSessionTokenConverterRoundTripProperty(t *testing.T) {
+	converter := converters.NewDefaultSessionTokenConverter()
+
+	for _, engine := range engines {
+		t.Run(engine, func(t *testing.T) {
+			rapid.Check(t, func(t *rapid.T) {
+				token := entities.SessionToken(uuidGen().Draw(t, "token"))
+
+				parsed, err := converter.DBToDomain(converter.DomainToDB(token))
+				require.NoError(t, err)
+				assert.Equal(t, token, parsed)
+
+				value, err := converters.Default().ToDB(engine, token)
+				require.NoError(t, err)
+
+				parsed, err = converters.FromDB[entities.SessionToken](converters.Default(), engine, value)
+				require.NoError(t, err)
+				assert.Equal(t, token, parsed)
+
+				// Drivers may return the token as a native UUID or as bytes.
+				parsed, err = converter.DBToDomain(token.UUID())
+				require.NoError(t, err)
+				assert.Equal(t, token, parsed)
+
+				id := token.UUID()
+				parsed, err = converter.DBToDomain(id[:])
+				require.NoError(t, err)
+				assert.Equal(t, token, parsed)
+			})
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package unit
+
+import (
+	"database/sql"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/converters"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// money is a custom domain type stored as integer cents.
+type money int64
+
+type centsConverter struct{}
+
+func (centsConverter) DomainToDB(domain money) int64 { return int64(domain) }
+
+func (centsConverter) DBToDomain(db int64) (money, error) { return money(db), nil }
+
+// decimalConverter stores money as a decimal string.
+type decimalConverter struct{}
+
+func (decimalConverter) DomainToDB(domain money) string {
+	return strconv.FormatFloat(float64(domain)/100, 'f', 2, 64)
+}
+
+func (decimalConverter) DBToDomain(db string) (money, error) {
+	value, err := strconv.ParseFloat(db, 64)
+
+	return money(value * 100), err
+}
+
+func TestConverterRegistryCustomType(t *testing.T) {
+	registry := converters.NewRegistry()
+	assert.False(t, registry.Registered(reflect.TypeFor[money](), converters.DbTypeSQLite))
+
+	converters.Register[money, int64](registry, converters.AnyEngine, centsConverter{})
+	converters.Register[money, string](registry, converters.DbTypePostgres, decimalConverter{})
+
+	value, err := registry.ToDB(converters.DbTypeSQLite, money(1250))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1250), value)
+
+	value, err = registry.ToDB(converters.DbTypePostgres, money(1250))
+	require.NoError(t, err)
+	assert.Equal(t, "12.50", value)
+
+	amount, err := converters.FromDB[money](registry, converters.DbTypePostgres, "3.75")
+	require.NoError(t, err)
+	assert.Equal(t, money(375), amount)
+
+	_, err = converters.FromDB[money](registry, converters.DbTypeMySQL, "3.75")
+	require.Error(t, err)
+
+	converter, err := converters.Lookup[money, int64](registry, converters.DbTypeMySQL)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), converter.DomainToDB(5))
+
+	_, err = converters.Lookup[money, int64](registry, converters.DbTypePostgres)
+	require.ErrorIs(t, err, converters.ErrConverterNotFound)
+
+	_, err = registry.ToDB(converters.DbTypeSQLite, 1.5)
+	require.ErrorIs(t, err, converters.ErrConverterNotFound)
+}
+
+func TestDefaultConverterRegistryUUID(t *testing.T) {
+	id := uuid.New()
+
+	value, err := converters.Default().ToDB(converters.DbTypeMySQL, id)
+	require.NoError(t, err)
+	assert.Equal(t, id[:], value)
+
+	value, err = converters.Default().ToDB(converters.DbTypeSQLite, id)
+	require.NoError(t, err)
+	assert.Equal(t, id.String(), value)
+
+	parsed, err := converters.FromDB[uuid.UUID](converters.Default(), converters.DbTypeMySQL, id[:])
+	require.NoError(t, err)
+	assert.Equal(t, id, parsed)
+}
+
+func TestNullableConverters(t *testing.T) {
+	now := time.Now()
+
+	nullTime := converters.NewNullTimeConverter()
+	assert.Equal(t, sql.NullTime{}, nullTime.DomainToDB(nil))
+	assert.Equal(t, sql.NullTime{Time: now, Valid: true}, nullTime.DomainToDB(&now))
+
+	parsed, err := nullTime.DBToDomain(sql.NullTime{})
+	require.NoError(t, err)
+	assert.Nil(t, parsed)
+
+	timestamptz := converters.NewTimestamptzConverter()
+	parsed, err = timestamptz.DBToDomain(timestamptz.DomainToDB(&now))
+	require.NoError(t, err)
+	require.NotNil(t, parsed)
+	assert.True(t, now.Equal(*parsed))
+
+	_, err = timestamptz.DBToDomain(pgtype.Timestamptz{InfinityModifier: pgtype.Infinity, Valid: true})
+	require.Error(t, err)
+
+	converter, err := converters.Lookup[*time.Time, pgtype.Timestamptz](converters.Default(), converters.DbTypePostgres)
+	require.NoError(t, err)
+	assert.False(t, converter.DomainToDB(nil).Valid)
+
+	_, err = converters.Lookup[*time.Time, pgtype.Timestamptz](converters.Default(), converters.DbTypeMySQL)
+	require.ErrorIs(t, err, converters.ErrConverterNotFound)
+
+	id := uuid.New()
+	pgUUID := converters.NewPgUUIDConverter()
+	roundTripped, err := pgUUID.DBToDomain(pgUUID.DomainToDB(&id))
+	require.NoError(t, err)
+	assert.Equal(t, id, *roundTripped)
+
+	name := "ada"
+	nullString := converters.NewNullStringConverter()
+	assert.Equal(t, sql.NullString{String: name, Valid: true}, nullString.DomainToDB(&name))
+}
@@ -0,0 +1,115 @@
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/memory"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/services"
+	"github.com/LarsArtmann/template-sqlc/pkg/fixtures"
+	"github.com/LarsArtmann/template-sqlc/pkg/passwords"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// tracingHasher records the calls of a bcrypt hasher.
+type tracingHasher struct {
+	*passwords.BcryptHasher
+
+	hashes   int
+	verified []string
+}
+
+func (h *tracingHasher) Hash(password string) (string, error) {
+	h.hashes++
+
+	return h.BcryptHasher.Hash(password)
+}
+
+func (h *tracingHasher) Verify(hash, password string) error {
+	h.verified = append(h.verified, hash)
+
+	return h.BcryptHasher.Verify(hash, password)
+}
+
+// credentialLookups counts the credential lookups of a user repository.
+type credentialLookups struct {
+	*memory.UserRepository
+
+	lookups int
+}
+
+func (r *credentialLookups) GetCredentials(ctx context.Context, email entities.Email) (*entities.User, error) {
+	r.lookups++
+
+	return r.UserRepository.GetCredentials(ctx, email)
+}
+
+func TestAuthenticateUserTakesEqualPaths(t *testing.T) {
+	ctx := context.Background()
+	hasher := &tracingHasher{BcryptHasher: passwords.NewBcryptHasher(bcrypt.MinCost)}
+	users := &credentialLookups{UserRepository: memory.NewUserRepository()}
+
+	hash, err := hasher.Hash("secret")
+	require.NoError(t, err)
+
+	ada := fixtures.User().Named("ada").WithPassword(entities.PasswordHash(hash)).MustBuild()
+	require.NoError(t, users.Create(ctx, ada))
+
+	sso := fixtures.User().Named("sso").WithPassword(entities.NewUnusablePasswordHash()).MustBuild()
+	require.NoError(t, users.Create(ctx, sso))
+
+	service := services.NewUserService(users, memory.NewSessionRepository(), events.NewInMemoryEventPublisher(), nil,
+		services.WithPasswordHasher(hasher))
+
+	attempts := []struct {
+		name, email, password string
+	}{
+		{"wrong password", "ada@example.com", "wrong"},
+		{"unknown email", "nobody@example.com", "secret"},
+		{"unusable password", "sso@example.com", entities.NewUnusablePasswordHash().String()},
+		{"unknown email again", "ghost@example.com", "secret"},
+	}
+
+	for _, attempt := range attempts {
+		lookups, verified := users.lookups, len(hasher.verified)
+
+		_, err = service.AuthenticateUser(ctx, attempt.email, attempt.password, "192.0.2.1", "firefox")
+		require.ErrorIs(t, err, entities.ErrInvalidCredentials, attempt.name)
+		assert.Equal(t, lookups+1, users.lookups, "%s: one lookup", attempt.name)
+		require.Len(t, hasher.verified, verified+1, "%s: one verification", attempt.name)
+		assert.True(t, hasher.Identifies(hasher.verified[verified]), "%s: a real bcrypt hash", attempt.name)
+	}
+
+	dummy := hasher.verified[1]
+	assert.NotEqual(t, hash, dummy)
+	assert.Equal(t, []string{hash, dummy, dummy, dummy}, hasher.verified, "missing hashes verify the dummy hash")
+	assert.Equal(t, 2, hasher.hashes, "the dummy hash is computed once")
+
+	cost, err := bcrypt.Cost([]byte(dummy))
+	require.NoError(t, err)
+	assert.Equal(t, bcrypt.MinCost, cost, "the dummy hash costs as much as a stored one")
+
+	_, err = service.AuthenticateUser(ctx, "ada@example.com", "secret", "192.0.2.1", "firefox")
+	require.NoError(t, err)
+}
+
+func TestAuthenticateUserRequiresHasher(t *testing.T) {
+	ctx := context.Background()
+	users := &credentialLookups{UserRepository: memory.NewUserRepository()}
+	hash := hashPassword(t, "secret")
+
+	require.NoError(t, users.Create(ctx, fixtures.User().Named("ada").WithPassword(hash).MustBuild()))
+
+	service := services.NewUserService(users, memory.NewSessionRepository(), events.NewInMemoryEventPublisher(), nil)
+
+	for _, password := range []string{hash.String(), "secret"} {
+		_, err := service.AuthenticateUser(ctx, "ada@example.com", password, "192.0.2.1", "firefox")
+		require.ErrorIs(t, err, services.ErrPasswordHasherUnavailable)
+	}
+
+	assert.Zero(t, users.lookups, "credentials are not looked up without a hasher")
+}
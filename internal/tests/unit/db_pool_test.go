@@ -0,0 +1,52 @@
+package unit
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/pkg/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+)
+
+// recordingGauge counts updates of the active connections gauge.
+type recordingGauge struct {
+	updates atomic.Int32
+	last    atomic.Int64
+}
+
+func (g *recordingGauge) SetActiveConnections(count int64) {
+	g.updates.Add(1)
+	g.last.Store(count)
+}
+
+func TestPoolReportsConnectionsAndHealth(t *testing.T) {
+	gauge := &recordingGauge{}
+
+	pool, err := db.Open(context.Background(), db.Config{
+		Driver:              db.DriverSQLite,
+		DSN:                 ":memory:",
+		MaxOpenConns:        2,
+		HealthCheckInterval: 5 * time.Millisecond,
+	}, db.WithMetrics(gauge))
+	require.NoError(t, err)
+
+	assert.True(t, pool.Healthy())
+	assert.Equal(t, int64(2), pool.Stats().Max)
+	assert.Eventually(t, func() bool { return gauge.updates.Load() > 2 }, time.Second, time.Millisecond)
+
+	require.NoError(t, pool.Close())
+	require.NoError(t, pool.Close())
+	assert.Error(t, pool.SQL().Ping())
+}
+
+func TestPoolRejectsInvalidConfig(t *testing.T) {
+	_, err := db.Open(context.Background(), db.Config{Driver: db.DriverSQLite})
+	require.ErrorIs(t, err, db.ErrMissingDSN)
+
+	_, err = db.Open(context.Background(), db.Config{Driver: "oracle", DSN: "x"})
+	require.ErrorIs(t, err, db.ErrUnsupportedDriver)
+}
@@ -0,0 +1,102 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/deadline"
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/memory"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/monitoring"
+	apperrors "github.com/LarsArtmann/template-sqlc/pkg/errors"
+	"github.com/LarsArtmann/template-sqlc/pkg/fixtures"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errInterrupted = errors.New("interrupted (9)")
+
+// stuckUserRepository blocks GetByID and List until their context is done,
+// failing like a driver that does not wrap the context error.
+type stuckUserRepository struct {
+	*memory.UserRepository
+}
+
+func (r *stuckUserRepository) GetByID(ctx context.Context, _ entities.UserID) (*entities.User, error) {
+	<-ctx.Done()
+
+	return nil, errInterrupted
+}
+
+func (r *stuckUserRepository) List(
+	ctx context.Context,
+	_ entities.UserFilter,
+	_, _ int,
+) ([]*entities.User, error) {
+	<-ctx.Done()
+
+	return nil, ctx.Err()
+}
+
+func TestDeadlineRepositoryTimesOutPerClass(t *testing.T) {
+	ctx := context.Background()
+	metrics := monitoring.NewMetrics()
+	users := deadline.NewUserRepository(
+		&stuckUserRepository{UserRepository: memory.NewUserRepository()},
+		deadline.WithTimeouts(deadline.Timeouts{Read: 10 * time.Millisecond, Search: 20 * time.Millisecond}),
+		deadline.WithObserver(metrics),
+	)
+
+	_, err := users.GetByID(ctx, 1)
+
+	var timeoutErr *entities.TimeoutError
+	require.ErrorAs(t, err, &timeoutErr)
+	assert.Equal(t, "UserRepository.GetByID", timeoutErr.Operation)
+	assert.Equal(t, 10*time.Millisecond, timeoutErr.Timeout)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.ErrorIs(t, err, errInterrupted)
+	assert.True(t, apperrors.IsTimeoutError(err))
+
+	_, err = users.List(ctx, entities.UserFilter{}, 10, 0)
+	require.ErrorIs(t, err, entities.NewTimeoutError("UserRepository.List", 0, nil))
+
+	// A zero timeout leaves writes unbounded.
+	require.NoError(t, users.Create(ctx, fixtures.User().Named("ada").MustBuild()))
+
+	assert.InDelta(t, 1, testutil.ToFloat64(metrics.QueryTimeouts.WithLabelValues("UserRepository.GetByID", "read")), 0)
+	assert.InDelta(t, 1, testutil.ToFloat64(metrics.QueryTimeouts.WithLabelValues("UserRepository.List", "search")), 0)
+}
+
+func TestDeadlineRepositoryKeepsOtherErrors(t *testing.T) {
+	users := deadline.NewUserRepository(memory.NewUserRepository())
+
+	_, err := users.GetByID(context.Background(), 1)
+	require.ErrorIs(t, err, entities.ErrUserNotFound)
+	assert.False(t, entities.IsTimeoutError(err))
+
+	// Cancellations are not timeouts.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	stuck := deadline.NewUserRepository(&stuckUserRepository{UserRepository: memory.NewUserRepository()})
+	_, err = stuck.List(ctx, entities.UserFilter{}, 10, 0)
+	require.ErrorIs(t, err, context.Canceled)
+	assert.False(t, entities.IsTimeoutError(err))
+}
+
+func TestDeadlineRepositoryReportsCallerDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	users := deadline.NewUserRepository(&stuckUserRepository{UserRepository: memory.NewUserRepository()})
+
+	_, err := users.GetByID(ctx, 1)
+
+	var timeoutErr *entities.TimeoutError
+	require.ErrorAs(t, err, &timeoutErr)
+	assert.Zero(t, timeoutErr.Timeout, "the deadline of the caller came first")
+	assert.Equal(t, apperrors.ErrCodeTimeout, apperrors.CodeOf(err))
+}
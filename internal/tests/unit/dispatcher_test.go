@@ -0,0 +1,104 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDispatcherRoutesByType(t *testing.T) {
+	dispatcher := events.NewDispatcher()
+
+	var created, all atomic.Int32
+
+	dispatcher.Subscribe("created", events.EventHandlerFunc(func(context.Context, *events.UserEvent) error {
+		created.Add(1)
+
+		return nil
+	}), events.EventUserCreated)
+	dispatcher.Subscribe("all", events.EventHandlerFunc(func(context.Context, *events.UserEvent) error {
+		all.Add(1)
+
+		return nil
+	}))
+
+	require.NoError(t, dispatcher.PublishBatch([]*events.UserEvent{
+		events.UserCreated(1, "a@example.com", "alice", "Alice", "Doe", "user", "active"),
+		events.UserVerified(1, "email"),
+	}))
+	require.NoError(t, dispatcher.Close(context.Background()))
+
+	assert.Equal(t, int32(1), created.Load())
+	assert.Equal(t, int32(2), all.Load())
+	require.ErrorIs(t, dispatcher.Publish(events.UserVerified(1, "email")), events.ErrDispatcherClosed)
+}
+
+func TestDispatcherRetriesAndDeadLetters(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		letters []events.DeadLetter
+	)
+
+	dispatcher := events.NewDispatcher(
+		events.WithDeliveryAttempts(3, 0),
+		events.WithDeadLetterHandler(func(letter events.DeadLetter) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			letters = append(letters, letter)
+		}),
+	)
+
+	var flakyCalls, brokenCalls atomic.Int32
+
+	dispatcher.Subscribe("flaky", events.EventHandlerFunc(func(context.Context, *events.UserEvent) error {
+		if flakyCalls.Add(1) < 3 {
+			return errors.New("temporarily unavailable")
+		}
+
+		return nil
+	}))
+	dispatcher.Subscribe("broken", events.EventHandlerFunc(func(context.Context, *events.UserEvent) error {
+		brokenCalls.Add(1)
+
+		panic("projection bug")
+	}))
+
+	require.NoError(t, dispatcher.Publish(events.UserVerified(1, "email")))
+	require.NoError(t, dispatcher.Close(context.Background()))
+
+	assert.Equal(t, int32(3), flakyCalls.Load())
+	assert.Equal(t, int32(3), brokenCalls.Load())
+	require.Len(t, letters, 1)
+	assert.Equal(t, "broken", letters[0].Handler)
+	assert.Equal(t, 3, letters[0].Attempts)
+	assert.ErrorContains(t, letters[0].Err, "projection bug")
+}
+
+func TestFanOutPublisherReachesEveryPublisher(t *testing.T) {
+	broker := &failingPublisher{}
+	dispatcher := events.NewDispatcher()
+
+	var handled atomic.Int32
+
+	dispatcher.Subscribe("activity", events.EventHandlerFunc(func(context.Context, *events.UserEvent) error {
+		handled.Add(1)
+
+		return nil
+	}))
+
+	publisher := events.NewFanOutPublisher(broker, dispatcher)
+
+	require.ErrorIs(t, publisher.Publish(events.UserVerified(1, "email")), errBrokerDown)
+	require.ErrorIs(t, publisher.PublishBatch([]*events.UserEvent{events.UserVerified(2, "email")}), errBrokerDown)
+	require.NoError(t, dispatcher.Close(context.Background()))
+
+	assert.Equal(t, 2, broker.calls)
+	assert.Equal(t, int32(2), handled.Load(), "a failing broker does not keep events from the dispatcher")
+}
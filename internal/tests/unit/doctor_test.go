@@ -0,0 +1,101 @@
+package unit
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/LarsArtmann/template-sqlc/internal/doctor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o750))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+}
+
+func TestDoctorReportsDrift(t *testing.T) {
+	root := t.TempDir()
+
+	writeFile(t, filepath.Join(root, "queries", "user.sql"), `
+-- name: GetUser :one
+SELECT id FROM users WHERE id = ?;
+
+-- name: ListUsers :many
+SELECT id FROM users;
+
+-- name: NewQuery :exec
+DELETE FROM users WHERE id = ?;
+`)
+	writeFile(t, filepath.Join(root, "db", "querier.go"), `package db
+
+import "context"
+
+type Querier interface {
+	GetUser(ctx context.Context, id int64) (int64, error)
+	ListUsers(ctx context.Context) ([]int64, error)
+	StaleQuery(ctx context.Context) error
+}
+
+type User struct {
+	ID       int64
+	Metadata interface{}
+}
+`)
+	writeFile(t, filepath.Join(root, "adapter", "user_repository.go"), `package adapter
+
+import "context"
+
+type UserRepository struct{ queries Querier }
+
+func (r *UserRepository) GetByID(ctx context.Context, id int64) (int64, error) {
+	return r.get(ctx, id)
+}
+
+func (r *UserRepository) get(ctx context.Context, id int64) (int64, error) {
+	return r.queries.GetUser(ctx, id)
+}
+
+func (r *UserRepository) Purge(ctx context.Context) error {
+	return nil
+}
+
+func (r *UserRepository) Engine() string { return "test" }
+`)
+
+	report, err := doctor.Run(context.Background(), doctor.Config{
+		Root: root,
+		Engines: []doctor.Engine{
+			{Name: "test", Queries: "queries", Generated: "db", Adapter: "adapter"},
+		},
+	})
+	require.NoError(t, err)
+	assert.True(t, report.HasErrors())
+
+	messages := make(map[string][]string)
+	for _, finding := range report.Findings {
+		messages[finding.Check] = append(messages[finding.Check], finding.Message)
+	}
+
+	assert.Equal(t, []string{
+		"query NewQuery in user.sql is not generated; run sqlc generate",
+		"generated method StaleQuery has no query; run sqlc generate",
+	}, messages[doctor.CheckGenerated])
+	assert.Equal(t, []string{
+		"query ListUsers is not used by any adapter",
+		"query StaleQuery is not used by any adapter",
+	}, messages[doctor.CheckUnused])
+	assert.Equal(t, []string{"repository method UserRepository.Purge runs no query"}, messages[doctor.CheckBacking])
+	assert.Equal(t, []string{
+		"User.Metadata has no Go type; add a column or db_type override",
+	}, messages[doctor.CheckOverrides])
+}
+
+func TestDoctorFailsOnMissingDirectory(t *testing.T) {
+	_, err := doctor.Run(context.Background(), doctor.DefaultConfig(t.TempDir()))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -0,0 +1,204 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/validation"
+	"github.com/LarsArtmann/template-sqlc/pkg/config"
+	apperrors "github.com/LarsArtmann/template-sqlc/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewEmailSyntax(t *testing.T) {
+	tests := []struct {
+		input string
+		want  entities.Email
+	}{
+		{"Ada.Lovelace+tag@Example.COM", "ada.lovelace+tag@example.com"},
+		{"o'brien!#$%&*/=?^_`{|}~-@example.com", "o'brien!#$%&*/=?^_`{|}~-@example.com"},
+		{"ada@münchen.de", "ada@xn--mnchen-3ya.de"},
+		{"ada@MÜNCHEN.de", "ada@xn--mnchen-3ya.de"},
+		{"ada@xn--mnchen-3ya.de", "ada@xn--mnchen-3ya.de"},
+		{"ada@a.b", "ada@a.b"},
+		{"ada@example.com.", ""},
+		{"ada.@example.com", ""},
+		{".ada@example.com", ""},
+		{"a..da@example.com", ""},
+		{`"ada lovelace"@example.com`, ""},
+		{"ada@[192.0.2.1]", ""},
+		{"ada@192.0.2.1", ""},
+		{"ada@localhost", ""},
+		{"ada@-example.com", ""},
+		{"ada@exa_mple.com", ""},
+		{"ada@xn--abc.com", ""},
+		{strings.Repeat("a", 65) + "@example.com", ""},
+		{"ada@" + strings.Repeat("a", 64) + ".com", ""},
+	}
+
+	for _, tt := range tests {
+		email, err := entities.NewEmail(tt.input)
+		if tt.want == "" {
+			require.ErrorIs(t, err, entities.ErrInvalidEmail, tt.input)
+
+			continue
+		}
+
+		require.NoError(t, err, tt.input)
+		assert.Equal(t, tt.want, email, tt.input)
+	}
+
+	email, err := entities.NewEmail("Ada@Bücher.Example")
+	require.NoError(t, err)
+	assert.Equal(t, "ada", email.LocalPart())
+	assert.Equal(t, "xn--bcher-kva.example", email.Domain())
+}
+
+// stubResolver answers lookups from its records; names without any are not
+// found, and err fails every lookup.
+type stubResolver struct {
+	mx    map[string][]*net.MX
+	hosts map[string][]string
+	err   error
+}
+
+func (r stubResolver) LookupMX(_ context.Context, name string) ([]*net.MX, error) {
+	if records, ok := r.mx[name]; ok || r.err != nil {
+		return records, r.err
+	}
+
+	return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+}
+
+func (r stubResolver) LookupHost(_ context.Context, host string) ([]string, error) {
+	if addresses, ok := r.hosts[host]; ok || r.err != nil {
+		return addresses, r.err
+	}
+
+	return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+}
+
+func TestEmailPolicyMXCheck(t *testing.T) {
+	policy := validation.EmailPolicy{Resolver: stubResolver{
+		mx: map[string][]*net.MX{
+			"example.com":  {{Host: "mx.example.com.", Pref: 10}},
+			"nomail.com":   {{Host: ".", Pref: 0}},
+			"implicit.org": {},
+		},
+		hosts: map[string][]string{"implicit.org": {"192.0.2.1"}},
+	}}
+
+	tests := []struct {
+		email string
+		code  apperrors.ErrorCode
+	}{
+		{"ada@example.com", ""},
+		{"ada@implicit.org", ""},
+		{"ada@nomail.com", apperrors.ErrCodeUndeliverable},
+		{"ada@missing.example", apperrors.ErrCodeUndeliverable},
+	}
+
+	for _, tt := range tests {
+		err := policy.Check(context.Background(), entities.Email(tt.email))
+		if tt.code == "" {
+			require.NoError(t, err, tt.email)
+
+			continue
+		}
+
+		fields := apperrors.FieldErrorsOf(err)
+		require.Len(t, fields, 1, tt.email)
+		assert.Equal(t, tt.code, fields[0].Code, tt.email)
+		assert.Equal(t, "email", fields[0].Field)
+		assert.True(t, apperrors.IsValidationError(err))
+	}
+}
+
+func TestEmailPolicyLookupFailure(t *testing.T) {
+	errDNSDown := &net.DNSError{Err: "server misbehaving", Name: "example.com", IsTemporary: true}
+	email := entities.Email("ada@example.com")
+
+	closed := validation.EmailPolicy{Resolver: stubResolver{err: errDNSDown}, MXTimeout: time.Second}
+	err := closed.Check(context.Background(), email)
+	require.ErrorIs(t, err, errDNSDown)
+	assert.False(t, apperrors.IsValidationError(err))
+
+	validator := validation.NewUserValidator(validation.WithEmailPolicy(closed))
+	require.ErrorIs(t, validator.ValidateUserCreate(context.Background(), email.String(), "ada", "Ada", "L"),
+		errDNSDown)
+
+	open := validation.EmailPolicy{Resolver: stubResolver{err: errDNSDown}, MXFailOpen: true}
+	require.NoError(t, open.Check(context.Background(), email))
+}
+
+func TestEmailPolicyDisposableDomains(t *testing.T) {
+	domains, err := validation.ReadDomainSet(strings.NewReader(
+		"# disposable domains\nmailinator.com\n\n  Wegwerf-Mäil.de  # comment\n"))
+	require.NoError(t, err)
+	assert.Len(t, domains, 2)
+
+	validator := validation.NewUserValidator(validation.WithEmailPolicy(validation.EmailPolicy{
+		Resolver:          stubResolver{err: errors.New("not consulted")},
+		DisposableDomains: domains,
+	}))
+
+	for _, email := range []string{"ada@mailinator.com", "ada@eu.MAILINATOR.com", "ada@wegwerf-mäil.de"} {
+		err := validator.ValidateEmail(context.Background(), email)
+
+		fields := apperrors.FieldErrorsOf(err)
+		require.Len(t, fields, 1, email)
+		assert.Equal(t, apperrors.ErrCodeNotAllowed, fields[0].Code, email)
+	}
+
+	assert.False(t, domains.Contains("notmailinator.com"))
+	assert.False(t, domains.Contains("com"))
+
+	fields := apperrors.FieldErrorsOf(validator.ValidateEmail(context.Background(), "not-an-email"))
+	require.Len(t, fields, 1)
+	assert.Equal(t, apperrors.ErrCodeInvalidFormat, fields[0].Code, "the policy runs on valid addresses only")
+
+	_, err = validation.NewDomainSet("not a domain")
+	require.ErrorIs(t, err, entities.ErrInvalidDomain)
+}
+
+func TestEmailAddressPolicyConfig(t *testing.T) {
+	cfg, err := config.Load(nil, noEnv)
+	require.NoError(t, err)
+	assert.False(t, cfg.EmailAddresses.CheckMX)
+	assert.True(t, cfg.EmailAddresses.MXFailOpen)
+	assert.Equal(t, validation.DefaultMXTimeout, cfg.EmailAddresses.MXTimeout)
+
+	path := filepath.Join(t.TempDir(), "app.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+email_addresses:
+  check_mx: true
+  mx_timeout: 1s
+  disposable_domains: [Mailinator.com, yopmail.com]
+`), 0o600))
+
+	cfg, err = config.Load([]string{"-config", path, "-email-address-mx-fail-open=false"}, noEnv)
+	require.NoError(t, err)
+	assert.True(t, cfg.EmailAddresses.CheckMX)
+	assert.False(t, cfg.EmailAddresses.MXFailOpen)
+
+	policy, err := cfg.EmailAddresses.Policy()
+	require.NoError(t, err)
+	assert.Equal(t, time.Second, policy.MXTimeout)
+	assert.True(t, policy.DisposableDomains.Contains("mailinator.com"))
+	assert.Nil(t, policy.Resolver, "the resolver is set by the server")
+
+	_, err = config.Load([]string{"-email-address-mx-timeout", "-1s"}, noEnv)
+	require.ErrorIs(t, err, config.ErrInvalidConfig)
+
+	require.NoError(t, os.WriteFile(path, []byte("email_addresses:\n  disposable_domains: [\"-bad-\"]\n"), 0o600))
+	_, err = config.Load([]string{"-config", path}, noEnv)
+	require.ErrorIs(t, err, entities.ErrInvalidDomain)
+}
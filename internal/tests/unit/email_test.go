@@ -0,0 +1,354 @@
+package unit
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/email"
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/memory"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+	"github.com/LarsArtmann/template-sqlc/pkg/fixtures"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sesv2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSender = "App <no-reply@example.com>"
+
+// outbox records sent messages.
+type outbox struct {
+	mu   sync.Mutex
+	sent []email.Message
+}
+
+func (o *outbox) Send(_ context.Context, msg email.Message) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.sent = append(o.sent, msg)
+
+	return nil
+}
+
+func TestDefaultTemplatesRender(t *testing.T) {
+	templates := email.DefaultTemplates()
+	expiresAt := time.Date(2026, time.March, 14, 15, 9, 0, 0, time.UTC)
+
+	for _, kind := range []email.Template{
+		email.TemplateVerification,
+		email.TemplatePasswordReset,
+		email.TemplateSuspension,
+		email.TemplateNotification,
+		email.TemplateAccountDeletion,
+	} {
+		msg, err := templates.Render(kind, email.TemplateData{
+			Name:         "Ada <3",
+			Link:         "https://app.example.com/page?token=abc",
+			ExpiresAt:    expiresAt,
+			Reason:       "spam",
+			Summary:      "Your password was changed.",
+			ScheduledFor: expiresAt,
+		})
+		require.NoError(t, err, kind)
+
+		assert.NotEmpty(t, msg.Subject, kind)
+		assert.NotContains(t, msg.Subject, "\n", kind)
+		assert.Contains(t, msg.Text, "Hello Ada <3,", kind)
+		assert.Contains(t, msg.HTML, "Hello Ada &lt;3,", kind)
+	}
+
+	msg, err := templates.Render(email.TemplatePasswordReset, email.TemplateData{
+		Link:      "https://app.example.com/reset?token=abc",
+		ExpiresAt: expiresAt,
+	})
+	require.NoError(t, err)
+	assert.Contains(t, msg.Text, "https://app.example.com/reset?token=abc")
+	assert.Contains(t, msg.Text, "14 March 2026 at 15:09 UTC")
+
+	_, err = templates.Render("welcome", email.TemplateData{})
+	require.ErrorIs(t, err, email.ErrUnknownTemplate)
+}
+
+func TestParseTemplatesRequiresSubject(t *testing.T) {
+	templates, err := email.ParseTemplates(fstest.MapFS{
+		"welcome.txt": {Data: []byte(`{{define "subject"}}Welcome{{end}}{{define "text"}}Hi {{.Name}}{{end}}`)},
+	})
+	require.NoError(t, err)
+
+	msg, err := templates.Render("welcome", email.TemplateData{Name: "Ada"})
+	require.NoError(t, err)
+	assert.Equal(t, email.Message{Subject: "Welcome", Text: "Hi Ada"}, msg)
+
+	_, err = email.ParseTemplates(fstest.MapFS{"welcome.txt": {Data: []byte("Hi")}})
+	require.ErrorIs(t, err, email.ErrUnknownTemplate)
+}
+
+func TestNotifierSendsLifecycleEmails(t *testing.T) {
+	ctx := context.Background()
+	users := memory.NewUserRepository()
+	ada := fixtures.User().Named("ada").MustBuild()
+	grace := fixtures.User().Named("grace").Verified().MustBuild()
+	require.NoError(t, users.Create(ctx, ada))
+	require.NoError(t, users.Create(ctx, grace))
+
+	sent := &outbox{}
+	notifier := email.NewNotifier(sent, users, testSender, email.Links{
+		Verification:  "https://app.example.com/verify?lang=en",
+		PasswordReset: "https://app.example.com/reset",
+	})
+	expiresAt := time.Now().Add(time.Hour)
+
+	// Redacted events arrive as JSON without personal data.
+	redacted, err := events.RedactionPolicy{Mode: events.RedactionHash}.RedactEvent(
+		events.PasswordResetRequested(ada.ID(), "reset-token", expiresAt))
+	require.NoError(t, err)
+
+	for _, event := range []*events.UserEvent{
+		events.UserVerificationRequested(ada.ID(), "verify-token", expiresAt),
+		redacted,
+		events.UserSuspended(ada.ID(), "spam", entities.UserID(1)),
+		// Grace is verified already, the token expired and the user is gone.
+		events.UserVerificationRequested(grace.ID(), "verify-token", expiresAt),
+		events.PasswordResetRequested(ada.ID(), "late-token", time.Now().Add(-time.Minute)),
+		events.PasswordResetRequested(entities.UserID(999), "reset-token", expiresAt),
+		events.UsersBulkUpdated("status", entities.UserStatusSuspended.String(), []entities.UserID{grace.ID()}, 0, 1),
+		events.UsersBulkUpdated("role", entities.UserRoleAdmin.String(), []entities.UserID{grace.ID()}, 0, 1),
+		events.UserLoggedIn(ada.ID(), "192.0.2.1", "test-agent", "web"),
+		events.DeletionRequested(&entities.AccountDeletion{UserID: ada.ID(), ScheduledFor: expiresAt}),
+	} {
+		require.NoError(t, notifier.Handle(ctx, event), event.Type)
+	}
+
+	require.Len(t, sent.sent, 5)
+
+	for _, msg := range sent.sent {
+		assert.Equal(t, testSender, msg.From)
+	}
+
+	assert.Equal(t, []string{"ada@example.com"}, sent.sent[0].To)
+	assert.Contains(t, sent.sent[0].Text, "https://app.example.com/verify?lang=en&token=verify-token")
+	assert.Contains(t, sent.sent[1].Text, "https://app.example.com/reset?token=reset-token")
+	assert.Contains(t, sent.sent[2].Text, "Reason: spam")
+	assert.Equal(t, []string{"grace@example.com"}, sent.sent[3].To)
+	assert.NotContains(t, sent.sent[3].Text, "Reason:")
+	assert.Equal(t, "Your account is scheduled for deletion", sent.sent[4].Subject)
+	assert.Contains(t, sent.sent[4].Text, expiresAt.UTC().Format("2 January 2006 at 15:04 MST"))
+}
+
+func TestNotifierSendsIdentityChangeEmails(t *testing.T) {
+	ctx := context.Background()
+	users := memory.NewUserRepository()
+	ada := fixtures.User().Named("ada").MustBuild()
+	require.NoError(t, users.Create(ctx, ada))
+
+	emailChange, err := entities.NewEmailChange(ada.ID(), ada.Email(), "ada@new.example.com", time.Hour)
+	require.NoError(t, err)
+	usernameChange, err := entities.NewUsernameChange(ada.ID(), ada.Username(), "lovelace", time.Hour)
+	require.NoError(t, err)
+
+	sent := &outbox{}
+	notifier := email.NewNotifier(sent, users, testSender, email.Links{
+		EmailChange:    "https://app.example.com/email",
+		IdentityRevert: "https://app.example.com/revert",
+	})
+	assert.Contains(t, notifier.EventTypes(), events.EventEmailChanged)
+
+	requested := events.EmailChangeRequested(emailChange)
+	require.NoError(t, emailChange.Apply(time.Hour))
+
+	for _, event := range []*events.UserEvent{
+		requested,
+		events.IdentityChangeApplied(emailChange),
+		events.IdentityChangeApplied(usernameChange),
+	} {
+		require.NoError(t, notifier.Handle(ctx, event), event.Type)
+	}
+
+	require.Len(t, sent.sent, 3)
+	assert.Equal(t, []string{"ada@new.example.com"}, sent.sent[0].To)
+	assert.Contains(t, sent.sent[0].Text, "https://app.example.com/email?token=")
+	assert.Equal(t, []string{"ada@example.com"}, sent.sent[1].To)
+	assert.Equal(t, "Your email address was changed", sent.sent[1].Subject)
+	assert.Contains(t, sent.sent[1].Text, "https://app.example.com/revert?token="+emailChange.Token.String())
+	assert.Equal(t, "Your username was changed", sent.sent[2].Subject)
+
+	// Without the links, the events are not emailed.
+	assert.NotContains(t, email.NewNotifier(sent, users, testSender, email.Links{}).EventTypes(),
+		events.EventEmailChanged)
+}
+
+// sesRecorder records the inputs of SendEmail.
+type sesRecorder struct {
+	inputs []*sesv2.SendEmailInput
+}
+
+func (r *sesRecorder) SendEmail(
+	_ context.Context,
+	params *sesv2.SendEmailInput,
+	_ ...func(*sesv2.Options),
+) (*sesv2.SendEmailOutput, error) {
+	r.inputs = append(r.inputs, params)
+
+	return &sesv2.SendEmailOutput{MessageId: aws.String("id")}, nil
+}
+
+func TestSESSender(t *testing.T) {
+	client := &sesRecorder{}
+	sender := email.NewSESSender(client, email.WithConfigurationSet("transactional"))
+
+	err := sender.Send(context.Background(), email.Message{
+		From:    testSender,
+		To:      []string{"ada@example.com"},
+		Subject: "Hello",
+		Text:    "Hello Ada",
+		HTML:    "<p>Hello Ada</p>",
+	})
+	require.NoError(t, err)
+	require.Len(t, client.inputs, 1)
+
+	input := client.inputs[0]
+	assert.Equal(t, testSender, aws.ToString(input.FromEmailAddress))
+	assert.Equal(t, []string{"ada@example.com"}, input.Destination.ToAddresses)
+	assert.Equal(t, "transactional", aws.ToString(input.ConfigurationSetName))
+	assert.Equal(t, "Hello", aws.ToString(input.Content.Simple.Subject.Data))
+	assert.Equal(t, "Hello Ada", aws.ToString(input.Content.Simple.Body.Text.Data))
+	assert.Equal(t, "<p>Hello Ada</p>", aws.ToString(input.Content.Simple.Body.Html.Data))
+
+	err = sender.Send(context.Background(), email.Message{From: testSender, To: []string{"not an address"},
+		Subject: "Hello", Text: "Hello"})
+	require.ErrorIs(t, err, email.ErrInvalidMessage)
+	assert.Len(t, client.inputs, 1)
+}
+
+// smtpTranscript is what a fake SMTP server received.
+type smtpTranscript struct {
+	from string
+	to   []string
+	data string
+}
+
+// serveSMTP accepts one mail on a local listener without TLS or
+// authentication and returns its address and the received mail.
+func serveSMTP(t *testing.T) (string, <-chan smtpTranscript) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	received := make(chan smtpTranscript, 1)
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		text := textproto.NewConn(conn)
+		mail := smtpTranscript{}
+
+		_ = text.PrintfLine("220 localhost ready")
+
+		for {
+			line, err := text.ReadLine()
+			if err != nil {
+				return
+			}
+
+			verb, arg, _ := strings.Cut(line, " ")
+
+			switch strings.ToUpper(verb) {
+			case "EHLO", "HELO":
+				_ = text.PrintfLine("250 localhost")
+			case "MAIL":
+				mail.from = strings.Trim(strings.TrimPrefix(arg, "FROM:"), "<>")
+				_ = text.PrintfLine("250 OK")
+			case "RCPT":
+				mail.to = append(mail.to, strings.Trim(strings.TrimPrefix(arg, "TO:"), "<>"))
+				_ = text.PrintfLine("250 OK")
+			case "DATA":
+				_ = text.PrintfLine("354 go ahead")
+
+				data, _ := io.ReadAll(text.DotReader())
+				mail.data = string(data)
+				_ = text.PrintfLine("250 OK")
+			case "QUIT":
+				_ = text.PrintfLine("221 bye")
+				received <- mail
+
+				return
+			default:
+				_ = text.PrintfLine("250 OK")
+			}
+		}
+	}()
+
+	return listener.Addr().String(), received
+}
+
+func TestSMTPSenderEncodesMultipartMessage(t *testing.T) {
+	addr, received := serveSMTP(t)
+	sender := email.NewSMTPSender(addr, nil)
+
+	err := sender.Send(context.Background(), email.Message{
+		From:    testSender,
+		To:      []string{"Ada Lovelace <ada@example.com>"},
+		Subject: "Grüße",
+		Text:    "Hello Ada",
+		HTML:    "<p>Hello Ada</p>",
+	})
+	require.NoError(t, err)
+
+	mailed := <-received
+	assert.Equal(t, "no-reply@example.com", mailed.from)
+	assert.Equal(t, []string{"ada@example.com"}, mailed.to)
+
+	msg, err := mail.ReadMessage(bufio.NewReader(strings.NewReader(mailed.data)))
+	require.NoError(t, err)
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	require.NoError(t, err)
+	assert.Equal(t, "Grüße", subject)
+	assert.Contains(t, msg.Header.Get("Message-Id"), "@example.com>")
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	assert.Equal(t, "multipart/alternative", mediaType)
+
+	parts := multipart.NewReader(msg.Body, params["boundary"])
+
+	var bodies []string
+
+	for {
+		part, err := parts.NextPart()
+		if err == io.EOF {
+			break
+		}
+
+		require.NoError(t, err)
+
+		body, err := io.ReadAll(part)
+		require.NoError(t, err)
+
+		bodies = append(bodies, part.Header.Get("Content-Type")+": "+string(body))
+	}
+
+	assert.Equal(t, []string{
+		`text/plain; charset="utf-8": Hello Ada`,
+		`text/html; charset="utf-8": <p>Hello Ada</p>`,
+	}, bodies)
+}
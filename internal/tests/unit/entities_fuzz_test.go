@@ -0,0 +1,220 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/converters"
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/tokens"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/services"
+	"github.com/LarsArtmann/template-sqlc/internal/validation"
+)
+
+// Fuzz targets for the value-object constructors and the session token
+// parsing path. Run one with, for example:
+//
+//	go test -run '^$' -fuzz '^FuzzNewEmail$' -fuzztime 30s ./internal/tests/unit
+
+func FuzzNewEmail(f *testing.F) {
+	for _, seed := range []string{
+		"ada@example.com", "Ada.Lovelace+tag@Example.COM", " ada@example.com\t",
+		"", "@", "ada@", "@example.com", "ada@@example.com", "ada..x@example.com",
+		"ada@example.c", "ada@exämple.com", "ada@example.com\n",
+	} {
+		f.Add(seed)
+	}
+
+	validator := validation.NewUserValidator()
+
+	f.Fuzz(func(t *testing.T, input string) {
+		email, err := entities.NewEmail(input)
+		if err != nil {
+			if !errors.Is(err, entities.ErrInvalidEmail) {
+				t.Fatalf("NewEmail(%q) error = %v, want ErrInvalidEmail", input, err)
+			}
+
+			// The service validates requests before building entities, so an
+			// email the validator accepts must be accepted here as well.
+			if validator.ValidateUserCreate(context.Background(), input, "fuzzer", "Ada", "Lovelace") == nil {
+				t.Fatalf("validator accepts %q but NewEmail rejects it: %v", input, err)
+			}
+
+			return
+		}
+
+		if email.String() != strings.ToLower(strings.TrimSpace(email.String())) {
+			t.Fatalf("NewEmail(%q) = %q, want it trimmed and lower case", input, email)
+		}
+
+		again, err := entities.NewEmail(email.String())
+		if err != nil || again != email {
+			t.Fatalf("NewEmail(%q) = %q, %v, want %q", email, again, err, email)
+		}
+	})
+}
+
+func FuzzNewUsername(f *testing.F) {
+	for _, seed := range []string{
+		"ada", "ada_lovelace-1", " ada ", "ab", strings.Repeat("a", 51), "admin", "ADMIN",
+		"ada lovelace", "ada!", "", "ädä", "ada\n",
+	} {
+		f.Add(seed)
+	}
+
+	validator := validation.NewUserValidator()
+
+	f.Fuzz(func(t *testing.T, input string) {
+		username, err := entities.NewUsername(input)
+
+		validatorErr := validator.ValidateUserCreate(context.Background(), "ada@example.com", input, "Ada", "Lovelace")
+		if (err == nil) != (validatorErr == nil) {
+			t.Fatalf("NewUsername(%q) error = %v, but validator error = %v", input, err, validatorErr)
+		}
+
+		if err != nil {
+			if !errors.Is(err, entities.ErrInvalidUsername) {
+				t.Fatalf("NewUsername(%q) error = %v, want ErrInvalidUsername", input, err)
+			}
+
+			return
+		}
+
+		if n := len(username.String()); n < 3 || n > 50 {
+			t.Fatalf("NewUsername(%q) = %q of length %d, want 3 to 50", input, username, n)
+		}
+
+		again, err := entities.NewUsername(username.String())
+		if err != nil || again != username {
+			t.Fatalf("NewUsername(%q) = %q, %v, want %q", username, again, err, username)
+		}
+	})
+}
+
+func FuzzNewPasswordHash(f *testing.F) {
+	for _, seed := range []string{
+		"$2a$10$N9qo8uLOickgx2ZMRZoMyeIjZRGdjGj/n3.rsQ5pPjZ5yVlWK5WAe",
+		"", "short", strings.Repeat("x", 31), strings.Repeat("x", 32),
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, input string) {
+		hash, err := entities.NewPasswordHash(input)
+		if err != nil {
+			if !errors.Is(err, entities.ErrInvalidPasswordHash) {
+				t.Fatalf("NewPasswordHash(%q) error = %v, want ErrInvalidPasswordHash", input, err)
+			}
+
+			return
+		}
+
+		if hash.String() != input {
+			t.Fatalf("NewPasswordHash(%q) = %q, want the input unchanged", input, hash)
+		}
+
+		again, err := entities.NewPasswordHash(hash.String())
+		if err != nil || again != hash {
+			t.Fatalf("NewPasswordHash(%q) = %q, %v, want %q", hash, again, err, hash)
+		}
+	})
+}
+
+func FuzzUUIDTokenStrategyParse(f *testing.F) {
+	token := entities.NewSessionToken().String()
+
+	for _, seed := range []string{
+		token, strings.ToUpper(token), "urn:uuid:" + token, "{" + token + "}",
+		strings.ReplaceAll(token, "-", ""), "", "not-a-token", token + " ",
+	} {
+		f.Add(seed)
+	}
+
+	strategy := services.UUIDTokenStrategy{}
+	converter := converters.NewDefaultSessionTokenConverter()
+
+	f.Fuzz(func(t *testing.T, input string) {
+		claims, err := strategy.Parse(input)
+
+		// Tokens are read back from the database with the converter, which
+		// must agree with the strategy on what a token is.
+		stored, convErr := converter.DBToDomain(input)
+		if (err == nil) != (convErr == nil) {
+			t.Fatalf("Parse(%q) error = %v, but converter error = %v", input, err, convErr)
+		}
+
+		if err != nil {
+			if !errors.Is(err, entities.ErrInvalidSessionToken) {
+				t.Fatalf("Parse(%q) error = %v, want ErrInvalidSessionToken", input, err)
+			}
+
+			return
+		}
+
+		if stored != claims.SessionToken {
+			t.Fatalf("Parse(%q) = %v, but converter = %v", input, claims.SessionToken, stored)
+		}
+
+		reissued, err := strategy.Issue(*claims)
+		if err != nil {
+			t.Fatalf("Issue(%v) error = %v", claims.SessionToken, err)
+		}
+
+		again, err := strategy.Parse(reissued)
+		if err != nil || again.SessionToken != claims.SessionToken {
+			t.Fatalf("Parse(%q) = %v, %v, want %v", reissued, again, err, claims.SessionToken)
+		}
+	})
+}
+
+func FuzzJWTStrategyParse(f *testing.F) {
+	strategy := tokens.NewJWTStrategy("template-sqlc",
+		tokens.NewHMACKey("hmac-1", []byte("0123456789abcdef0123456789abcdef")))
+
+	token, err := strategy.Issue(newTokenClaims())
+	if err != nil {
+		f.Fatal(err)
+	}
+
+	header, rest, _ := strings.Cut(token, ".")
+	payload, _, _ := strings.Cut(rest, ".")
+
+	for _, seed := range []string{
+		token, token + "x", header + "." + payload + ".", header + "." + payload,
+		"", "..", "a.b.c", "Bearer " + token,
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, input string) {
+		claims, err := strategy.Parse(input)
+		if err != nil {
+			return
+		}
+
+		if claims.Role != "" && !claims.Role.IsValid() {
+			t.Fatalf("Parse(%q) role = %q, want a valid role", input, claims.Role)
+		}
+
+		if !claims.ExpiresAt.After(time.Now().Add(-time.Minute)) {
+			t.Fatalf("Parse(%q) accepted a token that expired at %v", input, claims.ExpiresAt)
+		}
+
+		reissued, err := strategy.Issue(*claims)
+		if err != nil {
+			t.Fatalf("Issue(%+v) error = %v", claims, err)
+		}
+
+		again, err := strategy.Parse(reissued)
+		if err != nil {
+			t.Fatalf("Parse(%q) error = %v", reissued, err)
+		}
+
+		if again.SessionToken != claims.SessionToken || again.UserID != claims.UserID || again.Role != claims.Role {
+			t.Fatalf("Parse(Issue(%+v)) = %+v", claims, again)
+		}
+	})
+}
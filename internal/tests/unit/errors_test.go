@@ -0,0 +1,106 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	grpcadapter "github.com/LarsArtmann/template-sqlc/internal/adapters/grpc"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	apperrors "github.com/LarsArtmann/template-sqlc/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestErrorCodesOfDomainErrors(t *testing.T) {
+	tests := []struct {
+		err    error
+		code   apperrors.ErrorCode
+		status int
+		grpc   codes.Code
+	}{
+		{entities.ErrInvalidEmail, apperrors.ErrCodeValidationFailed, http.StatusBadRequest, codes.InvalidArgument},
+		{entities.ErrUserNotFound, apperrors.ErrCodeNotFound, http.StatusNotFound, codes.NotFound},
+		{entities.ErrUserAlreadyExists, apperrors.ErrCodeResourceConflict, http.StatusConflict, codes.AlreadyExists},
+		{entities.ErrInvalidCredentials, apperrors.ErrCodeUnauthorized, http.StatusUnauthorized, codes.Unauthenticated},
+		{entities.ErrAccountSuspended, apperrors.ErrCodeForbidden, http.StatusForbidden, codes.PermissionDenied},
+		{
+			entities.NewRateLimitError("slow down", time.Second),
+			apperrors.ErrCodeRateLimited, http.StatusTooManyRequests, codes.ResourceExhausted,
+		},
+		{
+			entities.NewTimeoutError("UserRepository.GetByID", time.Second, nil),
+			apperrors.ErrCodeTimeout, http.StatusRequestTimeout, codes.DeadlineExceeded,
+		},
+		{
+			entities.ErrReadOnlyMode,
+			apperrors.ErrCodeUnavailable, http.StatusServiceUnavailable, codes.Unavailable,
+		},
+		{
+			entities.NewInternalError("boom", nil),
+			apperrors.ErrCodeInternal, http.StatusInternalServerError, codes.Internal,
+		},
+		{
+			apperrors.NewDatabaseError("query failed", errors.New("disk full")),
+			apperrors.ErrCodeDatabase, http.StatusInternalServerError, codes.Internal,
+		},
+		{errors.New("unclassified"), apperrors.ErrCodeInternal, http.StatusInternalServerError, codes.Internal},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.code), func(t *testing.T) {
+			err := fmt.Errorf("get user id=1: %w", tt.err)
+
+			assert.Equal(t, tt.code, apperrors.CodeOf(err))
+			assert.Equal(t, tt.status, apperrors.HTTPStatus(err))
+			assert.Equal(t, tt.grpc, status.Code(grpcadapter.ToStatus(err)))
+		})
+	}
+
+	assert.Empty(t, apperrors.CodeOf(nil))
+	assert.Equal(t, codes.Canceled, status.Code(grpcadapter.ToStatus(fmt.Errorf("list: %w", context.Canceled))))
+}
+
+func TestErrorHelpersMatchDomainAndAppErrors(t *testing.T) {
+	wrapped := fmt.Errorf("update user id=1: %w", entities.ErrUserNotFound)
+
+	assert.True(t, apperrors.IsNotFoundError(wrapped))
+	assert.True(t, apperrors.IsNotFoundError(apperrors.NewUserNotFoundError()))
+	assert.False(t, apperrors.IsNotFoundError(nil))
+	assert.True(t, apperrors.IsValidationError(fmt.Errorf("create: %w", entities.ErrInvalidUsername)))
+	assert.True(t, apperrors.IsConflictError(entities.ErrMembershipAlreadyExists))
+	assert.True(t, apperrors.IsUnauthorizedError(entities.ErrSessionExpired))
+	assert.True(t, apperrors.IsForbiddenError(entities.ErrInsufficientPrivileges))
+	assert.True(t, apperrors.IsRateLimitError(entities.NewRateLimitError("slow down", time.Second)))
+	assert.True(t, apperrors.IsInternalServerError(errors.New("unclassified")))
+
+	// The outermost code wins.
+	internal := entities.NewInternalError("lookup failed", entities.ErrUserNotFound)
+	assert.False(t, apperrors.IsNotFoundError(internal))
+	assert.ErrorIs(t, internal, entities.ErrUserNotFound)
+}
+
+func TestErrorsIsMatchesEqualErrors(t *testing.T) {
+	// Errors built again match their sentinels through any wrapping.
+	err := fmt.Errorf("get user id=1: %w", entities.ErrUserNotFound)
+	require.ErrorIs(t, err, entities.NewNotFoundError("user", "user not found"))
+	require.NotErrorIs(t, err, entities.ErrSessionNotFound)
+	require.NotErrorIs(t, err, entities.NewConflictError("user", "user not found"))
+
+	require.ErrorIs(t, fmt.Errorf("create: %w", entities.ErrInvalidEmail),
+		entities.NewValidationError("email", "must be a valid email address"))
+	require.ErrorIs(t, entities.NewRateLimitError("slow down", time.Second),
+		entities.NewRateLimitError("slow down", time.Minute))
+
+	// Application errors match by code and the details of the target.
+	appErr := fmt.Errorf("lookup: %w", apperrors.NewUserNotFoundError())
+	require.ErrorIs(t, appErr, apperrors.NewUserNotFoundError())
+	require.ErrorIs(t, appErr, apperrors.NewAppError(apperrors.ErrCodeResourceNotFound, "", 0))
+	require.NotErrorIs(t, appErr, apperrors.NewSessionNotFoundError())
+	require.NotErrorIs(t, appErr, apperrors.NewAlreadyExistsError("user"))
+}
@@ -0,0 +1,213 @@
+package unit
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/messaging"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+	"github.com/LarsArtmann/template-sqlc/internal/monitoring"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// batchingPublisher records the published batches. With a gate, every
+// batch waits until the gate is closed.
+type batchingPublisher struct {
+	gate chan struct{}
+	err  error
+
+	mu      sync.Mutex
+	calls   int
+	batches [][]*events.UserEvent
+}
+
+func (p *batchingPublisher) Publish(event *events.UserEvent) error {
+	return p.PublishBatch([]*events.UserEvent{event})
+}
+
+func (p *batchingPublisher) PublishBatch(batch []*events.UserEvent) error {
+	p.mu.Lock()
+	p.calls++
+	p.mu.Unlock()
+
+	if p.gate != nil {
+		<-p.gate
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.err != nil {
+		return p.err
+	}
+
+	p.batches = append(p.batches, batch)
+
+	return nil
+}
+
+func (p *batchingPublisher) called() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.calls
+}
+
+func (p *batchingPublisher) sizes() []int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	sizes := make([]int, 0, len(p.batches))
+	for _, batch := range p.batches {
+		sizes = append(sizes, len(batch))
+	}
+
+	return sizes
+}
+
+func newBufferEvent() *events.UserEvent {
+	return events.UserCreated(7, "a@example.com", "alice", "Alice", "Doe", "user", "active")
+}
+
+func TestBufferedPublisherFlushesBySizeAndOnClose(t *testing.T) {
+	next := &batchingPublisher{}
+	publisher := messaging.NewBufferedPublisher(next,
+		messaging.WithBufferPolicy(messaging.BufferPolicy{Capacity: 10, BatchSize: 2}))
+
+	require.NoError(t, publisher.PublishBatch([]*events.UserEvent{newBufferEvent(), newBufferEvent()}))
+	require.Eventually(t, func() bool { return next.called() == 1 }, time.Second, time.Millisecond)
+
+	require.NoError(t, publisher.Publish(newBufferEvent()))
+	require.NoError(t, publisher.Close(context.Background()))
+
+	assert.Equal(t, []int{2, 1}, next.sizes())
+	require.ErrorIs(t, publisher.Publish(newBufferEvent()), messaging.ErrPublisherClosed)
+}
+
+func TestBufferedPublisherFlushesByInterval(t *testing.T) {
+	next := &batchingPublisher{}
+	publisher := messaging.NewBufferedPublisher(next,
+		messaging.WithBufferPolicy(messaging.BufferPolicy{BatchSize: 100, FlushInterval: 5 * time.Millisecond}))
+
+	t.Cleanup(func() { _ = publisher.Close(context.Background()) })
+
+	require.NoError(t, publisher.Publish(newBufferEvent()))
+	require.Eventually(t, func() bool { return next.called() == 1 }, time.Second, time.Millisecond)
+}
+
+func TestBufferedPublisherOverflow(t *testing.T) {
+	tests := []struct {
+		overflow messaging.Overflow
+		wantErr  error
+	}{
+		{messaging.OverflowDrop, nil},
+		{messaging.OverflowReject, messaging.ErrBufferFull},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.overflow), func(t *testing.T) {
+			next := &batchingPublisher{gate: make(chan struct{})}
+			metrics := monitoring.NewMetrics()
+			publisher := messaging.NewBufferedPublisher(next,
+				messaging.WithBufferPolicy(messaging.BufferPolicy{Capacity: 1, BatchSize: 1, Overflow: tt.overflow}),
+				messaging.WithBufferObserver(metrics),
+			)
+
+			// The first event is being published and the second fills the buffer.
+			require.NoError(t, publisher.Publish(newBufferEvent()))
+			require.Eventually(t, func() bool { return next.called() == 1 }, time.Second, time.Millisecond)
+			require.NoError(t, publisher.Publish(newBufferEvent()))
+
+			err := publisher.PublishBatch([]*events.UserEvent{newBufferEvent(), newBufferEvent()})
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+
+			assert.InDelta(t, 2, testutil.ToFloat64(metrics.EventsDropped.WithLabelValues("overflow")), 0)
+			assert.InDelta(t, 1, testutil.ToFloat64(metrics.EventBufferDepth), 0)
+
+			close(next.gate)
+			require.NoError(t, publisher.Close(context.Background()))
+			assert.Equal(t, []int{1, 1}, next.sizes())
+		})
+	}
+}
+
+func TestBufferedPublisherDropsFailedBatches(t *testing.T) {
+	next := &batchingPublisher{err: errBrokerDown}
+	metrics := monitoring.NewMetrics()
+	publisher := messaging.NewBufferedPublisher(next, messaging.WithBufferObserver(metrics))
+
+	require.NoError(t, publisher.PublishBatch([]*events.UserEvent{newBufferEvent(), newBufferEvent()}))
+	require.NoError(t, publisher.Close(context.Background()))
+
+	assert.InDelta(t, 2, testutil.ToFloat64(metrics.EventsDropped.WithLabelValues("publish_failed")), 0)
+}
+
+func TestBufferedPublisherCloseGivesUpAtDeadline(t *testing.T) {
+	next := &batchingPublisher{gate: make(chan struct{})}
+	metrics := monitoring.NewMetrics()
+	publisher := messaging.NewBufferedPublisher(next,
+		messaging.WithBufferPolicy(messaging.BufferPolicy{BatchSize: 1}),
+		messaging.WithBufferObserver(metrics),
+	)
+
+	require.NoError(t, publisher.PublishBatch([]*events.UserEvent{newBufferEvent(), newBufferEvent()}))
+	require.Eventually(t, func() bool { return next.called() == 1 }, time.Second, time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	require.ErrorIs(t, publisher.Close(ctx), context.DeadlineExceeded)
+
+	// The batch in flight completes; the queued event is dropped.
+	close(next.gate)
+	require.Eventually(t, func() bool {
+		return testutil.ToFloat64(metrics.EventsDropped.WithLabelValues("shutdown")) == 1
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, []int{1}, next.sizes())
+}
+
+func TestBufferedPublisherCloseReleasesBlockedPublishers(t *testing.T) {
+	next := &batchingPublisher{gate: make(chan struct{})}
+	defer close(next.gate)
+
+	publisher := messaging.NewBufferedPublisher(next,
+		messaging.WithBufferPolicy(messaging.BufferPolicy{Capacity: 1, BatchSize: 1, Overflow: messaging.OverflowBlock}),
+	)
+
+	// The first event is in flight and the second fills the queue.
+	require.NoError(t, publisher.PublishBatch([]*events.UserEvent{newBufferEvent()}))
+	require.Eventually(t, func() bool { return next.called() == 1 }, time.Second, time.Millisecond)
+	require.NoError(t, publisher.Publish(newBufferEvent()))
+
+	blocked := make(chan error, 1)
+
+	go func() { blocked <- publisher.Publish(newBufferEvent()) }()
+
+	// Give the publisher time to block on the full queue.
+	time.Sleep(20 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	closed := make(chan error, 1)
+
+	go func() { closed <- publisher.Close(ctx) }()
+
+	select {
+	case err := <-closed:
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	case <-time.After(time.Second):
+		t.Fatal("Close deadlocked with a publisher blocked on the full buffer")
+	}
+
+	require.ErrorIs(t, <-blocked, messaging.ErrPublisherClosed)
+	require.ErrorIs(t, publisher.Publish(newBufferEvent()), messaging.ErrPublisherClosed)
+}
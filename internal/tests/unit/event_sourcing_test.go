@@ -0,0 +1,92 @@
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/eventsourced"
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/memory"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/pkg/fixtures"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserChangeEventsReplay(t *testing.T) {
+	before := fixtures.User().Named("ada").WithTags("beta").MustBuild()
+
+	record := before.Record()
+	record.FirstName = "Grace"
+	record.Role = entities.UserRoleModerator
+	record.UpdatedAt = record.UpdatedAt.Add(time.Minute)
+	after, err := entities.ReconstructUser(record)
+	require.NoError(t, err)
+
+	changes := entities.UserChangeEvents(before, after)
+	require.Len(t, changes, 2)
+	assert.Equal(t, entities.UserEventProfileUpdated, changes[0].Type)
+	assert.Nil(t, changes[0].Data.Tags)
+	assert.Equal(t, entities.UserEventRoleChanged, changes[1].Type)
+
+	replayed := new(entities.User)
+	require.ErrorIs(t, replayed.Apply(changes[0]), entities.ErrInvalidUserEvent)
+
+	for _, event := range append([]*entities.UserStreamEvent{entities.NewUserCreatedEvent(before)}, changes...) {
+		require.NoError(t, replayed.Apply(event))
+	}
+
+	assert.Equal(t, before.UUID(), replayed.UUID())
+	assert.Equal(t, entities.FirstName("Grace"), replayed.FirstName())
+	assert.Equal(t, entities.UserRoleModerator, replayed.Role())
+	assert.Equal(t, []string{"beta"}, replayed.Tags())
+	assert.True(t, after.UpdatedAt().Equal(replayed.UpdatedAt()))
+
+	require.ErrorIs(t, replayed.Apply(entities.NewUserCreatedEvent(before)), entities.ErrInvalidUserEvent)
+	assert.Empty(t, entities.UserChangeEvents(after, after))
+}
+
+func TestEventSourcedUserRepositoryAdoptsAndSnapshots(t *testing.T) {
+	ctx := context.Background()
+	readModel := memory.NewUserRepository()
+	store := memory.NewUserEventStore()
+	repo := eventsourced.NewUserRepository(readModel, store, eventsourced.WithSnapshotEvery(2))
+
+	// Users created before event sourcing are served from the read model.
+	user := fixtures.User().Named("ada").MustBuild()
+	require.NoError(t, readModel.Create(ctx, user))
+
+	found, err := repo.GetByID(ctx, user.ID())
+	require.NoError(t, err)
+	assert.Equal(t, user.Email(), found.Email())
+
+	// Their first change starts the stream with their current state.
+	require.NoError(t, repo.ChangeRole(ctx, user.ID(), entities.UserRoleAdmin))
+
+	events, err := store.Load(ctx, user.ID(), 0)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, entities.UserEventCreated, events[0].Type)
+	assert.Equal(t, entities.UserEventRoleChanged, events[1].Type)
+
+	snapshot, err := store.LatestSnapshot(ctx, user.ID())
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), snapshot.Version)
+	assert.Equal(t, entities.UserRoleAdmin, snapshot.State.Role)
+
+	require.NoError(t, repo.Delete(ctx, user.ID()))
+
+	_, err = repo.GetByID(ctx, user.ID())
+	require.ErrorIs(t, err, entities.ErrUserNotFound)
+
+	require.NoError(t, repo.Restore(ctx, user.ID()))
+
+	snapshot, err = store.LatestSnapshot(ctx, user.ID())
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), snapshot.Version)
+	assert.False(t, snapshot.Deleted)
+
+	found, err = repo.GetByID(ctx, user.ID())
+	require.NoError(t, err)
+	assert.Equal(t, entities.UserRoleAdmin, found.Role())
+}
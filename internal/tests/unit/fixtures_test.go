@@ -0,0 +1,57 @@
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/tests/integration"
+	"github.com/LarsArtmann/template-sqlc/pkg/fixtures"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFixturesAreDeterministic(t *testing.T) {
+	first := fixtures.User().Named("ada").WithRole(entities.UserRoleAdmin).WithTags("beta").Verified().MustBuild()
+	second := fixtures.User().Named("ada").WithRole(entities.UserRoleAdmin).WithTags("beta").Verified().MustBuild()
+
+	assert.Equal(t, first.UUID(), second.UUID())
+	assert.Equal(t, entities.Email("ada@example.com"), first.Email())
+	assert.Equal(t, entities.UserRoleAdmin, first.Role())
+	assert.Equal(t, []string{"beta"}, first.Tags())
+	assert.True(t, first.IsVerified())
+
+	_, err := fixtures.Session(first).Build()
+	require.ErrorIs(t, err, fixtures.ErrUnsavedUser)
+
+	first.SetID(7)
+
+	session := fixtures.Session(first).Named("laptop").Expired().MustBuild()
+	assert.Equal(t, fixtures.Session(first).Named("laptop").MustBuild().Token(), session.Token())
+	assert.True(t, session.IsExpired())
+}
+
+func TestFixturesSeeder(t *testing.T) {
+	ctx := context.Background()
+	users := integration.NewMockUserRepository()
+	seeder := fixtures.NewSeeder(users,
+		fixtures.WithSessions(integration.NewMockSessionRepository()),
+		fixtures.WithBatchSize(2),
+	)
+
+	dataset, err := seeder.Dataset(ctx, 3, 2)
+	require.NoError(t, err)
+	require.Len(t, dataset.Users, 3)
+	assert.Equal(t, entities.Username("user0003"), dataset.Users[2].Username())
+
+	for _, user := range dataset.Users {
+		require.NotZero(t, user.ID())
+		assert.Len(t, dataset.Sessions[user.ID()], 2)
+	}
+
+	orphan := fixtures.User().Named("orphan").MustBuild()
+	orphan.SetID(999)
+
+	_, err = seeder.Sessions(ctx, fixtures.Session(orphan))
+	require.ErrorIs(t, err, entities.ErrUserNotFound)
+}
@@ -0,0 +1,70 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/LarsArtmann/template-sqlc/internal/monitoring"
+	"github.com/LarsArtmann/template-sqlc/pkg/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthCheckerProbes(t *testing.T) {
+	pool, err := db.Open(context.Background(), db.Config{Driver: db.DriverSQLite, DSN: ":memory:"})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = pool.Close() })
+
+	pending := 1
+	checker := monitoring.NewHealthChecker()
+	checker.RegisterReadiness("database", monitoring.PingCheck(pool))
+	checker.RegisterReadiness("migrations", monitoring.MigrationCheck(func(context.Context) (int, error) {
+		return pending, nil
+	}))
+
+	recorder := httptest.NewRecorder()
+	checker.LivenessHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	checker.ReadinessHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+
+	var report monitoring.HealthReport
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &report))
+	require.Len(t, report.Checks, 2)
+	assert.Equal(t, monitoring.HealthStatusUp, report.Checks[0].Status)
+	assert.Equal(t, monitoring.HealthStatusDown, report.Checks[1].Status)
+	assert.Contains(t, report.Checks[1].Error, monitoring.ErrPendingMigrations.Error())
+
+	pending = 0
+	assert.True(t, checker.Readiness(context.Background()).Healthy())
+
+	checker.RegisterLiveness("deadlock", func(context.Context) error { return errors.New("stuck") })
+	assert.False(t, checker.Liveness(context.Background()).Healthy())
+	assert.False(t, checker.Readiness(context.Background()).Healthy())
+}
+
+func TestHealthReportsModes(t *testing.T) {
+	readOnly := true
+	checker := monitoring.NewHealthChecker()
+	assert.Nil(t, checker.Liveness(context.Background()).Modes)
+
+	checker.RegisterMode("maintenance.read_only", func(context.Context) bool { return readOnly })
+	checker.RegisterMode("maintenance.login_disabled", func(context.Context) bool { return false })
+
+	recorder := httptest.NewRecorder()
+	checker.ReadinessHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code, "modes do not fail the report")
+
+	var report monitoring.HealthReport
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &report))
+	assert.Equal(t, map[string]bool{"maintenance.read_only": true, "maintenance.login_disabled": false}, report.Modes)
+
+	readOnly = false
+	assert.False(t, checker.Liveness(context.Background()).Modes["maintenance.read_only"])
+}
@@ -0,0 +1,30 @@
+package unit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/LarsArtmann/template-sqlc/internal/monitoring"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetricsMiddlewareLabelsRoutes(t *testing.T) {
+	metrics := monitoring.NewMetrics()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /users/{id}", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("alice"))
+	})
+	handler := metrics.Middleware(mux)
+
+	for _, path := range []string{"/users/1", "/users/2", "/missing"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	assert.InDelta(t, 2, testutil.ToFloat64(metrics.HTTPRequests.WithLabelValues("GET", "GET /users/{id}", "200")), 0)
+	assert.InDelta(t, 1, testutil.ToFloat64(metrics.HTTPRequests.WithLabelValues("GET", "unmatched", "404")), 0)
+	assert.InDelta(t, 0, testutil.ToFloat64(metrics.HTTPInFlight), 0)
+	assert.Equal(t, 2, testutil.CollectAndCount(metrics.HTTPResponseSize))
+}
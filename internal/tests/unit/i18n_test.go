@@ -0,0 +1,182 @@
+package unit
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	grpcadapter "github.com/LarsArtmann/template-sqlc/internal/adapters/grpc"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/validation"
+	apperrors "github.com/LarsArtmann/template-sqlc/pkg/errors"
+	"github.com/LarsArtmann/template-sqlc/pkg/i18n"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestDefaultCatalogsAreComplete(t *testing.T) {
+	catalog := i18n.DefaultCatalog()
+	require.Equal(t, language.English, catalog.Languages()[0])
+	require.Len(t, catalog.Languages(), 5)
+
+	errorCodes := []apperrors.ErrorCode{
+		apperrors.ErrCodeValidationFailed, apperrors.ErrCodeInvalidInput, apperrors.ErrCodeMissingField,
+		apperrors.ErrCodeInvalidFormat, apperrors.ErrCodeTooShort, apperrors.ErrCodeTooLong,
+		apperrors.ErrCodeOutOfRange, apperrors.ErrCodeNotAllowed, apperrors.ErrCodeTooWeak,
+		apperrors.ErrCodeUndeliverable, apperrors.ErrCodeUnauthorized, apperrors.ErrCodeForbidden,
+		apperrors.ErrCodeNotFound, apperrors.ErrCodeResourceConflict, apperrors.ErrCodeRateLimited,
+		apperrors.ErrCodeTimeout, apperrors.ErrCodeUnavailable,
+	}
+
+	for _, tag := range catalog.Languages()[1:] {
+		localizer := catalog.Localizer(tag)
+
+		for _, code := range errorCodes {
+			assert.NotEmpty(t, localizer.ErrorMessage(code, ""), "%v %v", tag, code)
+		}
+
+		for _, code := range errorCodes[2:10] {
+			fieldErr := apperrors.FieldError{
+				Field: "x", Code: code, Message: "english", Params: map[string]any{"min": 3, "max": 9},
+			}
+			assert.NotEqual(t, "english", localizer.FieldMessage(fieldErr), "%v %v", tag, code)
+		}
+	}
+}
+
+func TestNegotiateLanguage(t *testing.T) {
+	catalog := i18n.DefaultCatalog()
+
+	tests := []struct {
+		header string
+		want   language.Tag
+	}{
+		{"de-CH, de;q=0.9, en;q=0.8", language.German},
+		{"fr-CA", language.French},
+		{"ja, nl;q=0.5", language.Dutch},
+		{"ja", language.English},
+		{"", language.English},
+		{"not a language;;", language.English},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, catalog.Negotiate(tt.header), tt.header)
+	}
+}
+
+func TestLocalizeValidationErrors(t *testing.T) {
+	catalog := i18n.DefaultCatalog()
+	err := fmt.Errorf("validation failed: %w",
+		validation.NewUserValidator().ValidateUserCreate(context.Background(), "not-an-email", "ab", "Ada", "Lovelace"))
+
+	localized := catalog.Localizer(language.German).Localize(err)
+
+	assert.Equal(t, "Validierung fehlgeschlagen: email: muss eine gültige E-Mail-Adresse sein; "+
+		"username: muss mindestens 3 Zeichen lang sein", localized.Error())
+	assert.Equal(t, apperrors.ErrCodeValidationFailed, apperrors.CodeOf(localized))
+	assert.Equal(t, http.StatusUnprocessableEntity, apperrors.HTTPStatus(localized))
+	require.ErrorIs(t, localized, err)
+
+	fields := apperrors.FieldErrorsOf(localized)
+	require.Len(t, fields, 2)
+	assert.Equal(t, "muss eine gültige E-Mail-Adresse sein", fields[0].Message)
+	assert.Equal(t, map[string]any{"min": 3}, fields[1].Params)
+	assert.Equal(t, "must be a valid email address", apperrors.FieldErrorsOf(err)[0].Message, "the original is kept")
+
+	assert.Equal(t, err, catalog.Localizer(language.English).Localize(err), "English is the source language")
+	assert.Equal(t, context.Canceled, catalog.Localizer(language.German).Localize(context.Canceled))
+	assert.Equal(t, "Nicht gefunden", catalog.Localizer(language.German).Localize(entities.ErrUserNotFound).Error())
+}
+
+func TestLocalizeFallsBackToEnglish(t *testing.T) {
+	catalog := i18n.NewCatalog()
+	catalog.Add(language.German, i18n.Messages{
+		Errors: map[apperrors.ErrorCode]string{apperrors.ErrCodeValidationFailed: "Ungültig"},
+		Fields: map[string]string{"TOO_LONG": "höchstens {max} Zeichen", "tags.NOT_ALLOWED": "doppelt"},
+	})
+
+	german := catalog.Localizer(catalog.Negotiate("de-DE"))
+
+	var errs apperrors.ValidationErrors
+	errs.Add("tags[3]", apperrors.ErrCodeNotAllowed, "duplicate tag found")
+	errs.Add("name", apperrors.ErrCodeTooLong, "must not exceed 100 characters")
+	errs.Add("name", apperrors.ErrCodeTooShort, "must be at least 3 characters", "min", 3)
+
+	assert.Equal(t, []string{"doppelt", "must not exceed 100 characters", "must be at least 3 characters"},
+		fieldMessages(apperrors.FieldErrorsOf(german.Localize(errs))),
+		"field indexes are ignored, and messages without a translation or with a missing param fall back")
+
+	assert.Equal(t, entities.ErrReadOnlyMode, german.Localize(entities.ErrReadOnlyMode), "no message for the code")
+}
+
+func TestParseCatalog(t *testing.T) {
+	catalog, err := i18n.ParseCatalog(fstest.MapFS{
+		"pt-BR.json": {Data: []byte(`{"errors": {"NOT_FOUND": "Não encontrado"}}`)},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []language.Tag{language.English, language.BrazilianPortuguese}, catalog.Languages())
+	assert.Equal(t, "Não encontrado",
+		catalog.Localizer(catalog.Negotiate("pt-BR")).Localize(entities.ErrUserNotFound).Error())
+
+	_, err = i18n.ParseCatalog(fstest.MapFS{"de.json": {Data: []byte(`{"errors": [`)}})
+	require.ErrorIs(t, err, i18n.ErrInvalidCatalog)
+
+	_, err = i18n.ParseCatalog(fstest.MapFS{"not_a_tag!.json": {Data: []byte(`{}`)}})
+	require.ErrorIs(t, err, i18n.ErrInvalidCatalog)
+}
+
+func TestI18nMiddleware(t *testing.T) {
+	var negotiated language.Tag
+
+	handler := i18n.Middleware(i18n.DefaultCatalog())(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		localizer, ok := i18n.FromContext(r.Context())
+		require.True(t, ok)
+
+		negotiated = localizer.Language()
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/graphql", nil)
+	req.Header.Set("Accept-Language", "es-MX,es;q=0.9")
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, language.Spanish, negotiated)
+	assert.Equal(t, "Accept-Language", rec.Header().Get("Vary"))
+}
+
+func TestI18nUnaryServerInterceptor(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("accept-language", "fr"))
+	info := &grpclib.UnaryServerInfo{FullMethod: "/users.v1.UserService/CreateUser"}
+
+	failing := func(context.Context, any) (any, error) {
+		return nil, validation.NewUserValidator().ValidateUserCreate(ctx, "ada@example.com", "ada", "", "Lovelace")
+	}
+
+	_, err := i18n.UnaryServerInterceptor(i18n.DefaultCatalog())(ctx, nil, info,
+		func(ctx context.Context, req any) (any, error) {
+			return grpcadapter.ErrorInterceptor()(ctx, req, info, failing)
+		})
+
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+	assert.Equal(t, "La validation a échoué: first_name: est obligatoire", st.Message())
+}
+
+func fieldMessages(fields apperrors.ValidationErrors) []string {
+	messages := make([]string, len(fields))
+	for i, fieldErr := range fields {
+		messages[i] = fieldErr.Message
+	}
+
+	return messages
+}
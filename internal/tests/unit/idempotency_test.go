@@ -0,0 +1,180 @@
+package unit
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/memory"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/services"
+	"github.com/LarsArtmann/template-sqlc/pkg/passwords"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// idempotencyFixture is a user service keeping idempotent responses in memory.
+type idempotencyFixture struct {
+	*serviceFixture
+
+	idempotency *memory.IdempotencyRepository
+}
+
+func newIdempotencyFixture() *idempotencyFixture {
+	f := &idempotencyFixture{
+		serviceFixture: newServiceFixture(),
+		idempotency:    memory.NewIdempotencyRepository(),
+	}
+
+	f.start(
+		services.WithPasswordHasher(passwords.NewBcryptHasher(bcrypt.MinCost)),
+		services.WithIdempotency(f.idempotency, time.Hour),
+	)
+
+	return f
+}
+
+func createUserRequest(key string) *services.CreateUserRequest {
+	return &services.CreateUserRequest{
+		Email:          "retry@example.com",
+		Username:       "retry",
+		Password:       "Correct-Horse-42",
+		FirstName:      "Re",
+		LastName:       "Try",
+		Status:         string(entities.UserStatusActive),
+		Role:           string(entities.UserRoleUser),
+		IdempotencyKey: key,
+	}
+}
+
+func TestCreateUserIdempotencyReplaysResponse(t *testing.T) {
+	ctx := context.Background()
+	f := newIdempotencyFixture()
+
+	first, err := f.service.CreateUser(ctx, createUserRequest("signup-1"))
+	require.NoError(t, err)
+
+	retry, err := f.service.CreateUser(ctx, createUserRequest("signup-1"))
+	require.NoError(t, err)
+	assert.Equal(t, first.ID(), retry.ID())
+	assert.Equal(t, first.UUID(), retry.UUID())
+	assert.Equal(t, first.Email(), retry.Email())
+	assert.Len(t, f.publisher.Events(), 1)
+
+	// The replay is the user created, as it is now.
+	firstName := "Changed"
+	_, err = f.service.UpdateUser(ctx, &services.UpdateUserRequest{
+		UserID: first.ID(), FirstName: &firstName, UpdatedBy: "test",
+	})
+	require.NoError(t, err)
+
+	retry, err = f.service.CreateUser(ctx, createUserRequest("signup-1"))
+	require.NoError(t, err)
+	assert.Equal(t, "Changed", retry.FirstName().String())
+}
+
+func TestCreateUserIdempotencyKeepsNoPersonalData(t *testing.T) {
+	ctx := context.Background()
+	f := newIdempotencyFixture()
+	f.start(
+		services.WithPasswordHasher(passwords.NewBcryptHasher(bcrypt.MinCost)),
+		services.WithIdempotency(f.idempotency, time.Hour),
+		services.WithTransactions(f.tx),
+	)
+
+	user, err := f.service.CreateUser(ctx, createUserRequest("signup-1"))
+	require.NoError(t, err)
+
+	record, err := f.idempotency.Get(ctx, "signup-1")
+	require.NoError(t, err)
+	assert.JSONEq(t, fmt.Sprintf(`{"ID":%d}`, user.ID()), string(record.Response))
+
+	require.NoError(t, f.service.EraseUser(ctx, user.ID()))
+
+	_, err = f.service.CreateUser(ctx, createUserRequest("signup-1"))
+	require.ErrorIs(t, err, entities.ErrUserNotFound, "an erased user is not replayed")
+}
+
+func TestCreateUserIdempotencyKeyMismatch(t *testing.T) {
+	ctx := context.Background()
+	f := newIdempotencyFixture()
+
+	_, err := f.service.CreateUser(ctx, createUserRequest("signup-1"))
+	require.NoError(t, err)
+
+	other := createUserRequest("signup-1")
+	other.Email = "other@example.com"
+
+	_, err = f.service.CreateUser(ctx, other)
+	require.ErrorIs(t, err, entities.ErrIdempotencyKeyMismatch)
+
+	// Keys are scoped to their operation.
+	firstName := "Changed"
+	_, err = f.service.UpdateUser(ctx, &services.UpdateUserRequest{
+		UserID: 1, FirstName: &firstName, UpdatedBy: "test", IdempotencyKey: "signup-1",
+	})
+	require.ErrorIs(t, err, entities.ErrIdempotencyKeyMismatch)
+}
+
+func TestCreateUserIdempotencyReleasesFailedWrite(t *testing.T) {
+	ctx := context.Background()
+	f := newIdempotencyFixture()
+
+	invalid := createUserRequest("signup-1")
+	invalid.Email = "not-an-email"
+
+	_, err := f.service.CreateUser(ctx, invalid)
+	require.Error(t, err)
+
+	_, err = f.idempotency.Get(ctx, "signup-1")
+	require.ErrorIs(t, err, entities.ErrIdempotencyKeyNotFound)
+
+	user, err := f.service.CreateUser(ctx, createUserRequest("signup-1"))
+	require.NoError(t, err)
+	assert.NotZero(t, user.ID())
+}
+
+func TestCreateUserIdempotencyInProgress(t *testing.T) {
+	ctx := context.Background()
+	f := newIdempotencyFixture()
+
+	_, err := f.service.CreateUser(ctx, createUserRequest("signup-1"))
+	require.NoError(t, err)
+
+	stored, err := f.idempotency.Get(ctx, "signup-1")
+	require.NoError(t, err)
+
+	// A pending record of the same request stands for a running write.
+	pending := entities.NewIdempotencyRecord("signup-2", "CreateUser", stored.RequestHash, time.Minute)
+	require.NoError(t, f.idempotency.Reserve(ctx, pending))
+
+	_, err = f.service.CreateUser(ctx, createUserRequest("signup-2"))
+	require.ErrorIs(t, err, entities.ErrIdempotentRequestInProgress)
+}
+
+func TestCreateUserWithoutIdempotencyKey(t *testing.T) {
+	ctx := context.Background()
+	f := newIdempotencyFixture()
+
+	_, err := f.service.CreateUser(ctx, createUserRequest(""))
+	require.NoError(t, err)
+
+	_, err = f.service.CreateUser(ctx, createUserRequest(""))
+	require.ErrorIs(t, err, entities.ErrUserAlreadyExists)
+}
+
+func TestNewIdempotencyKey(t *testing.T) {
+	for _, key := range []string{"a", "9b2f0c1e-7a4d-4c2b-8f3e-1d2c3b4a5f60", strings.Repeat("k", 255)} {
+		got, err := entities.NewIdempotencyKey(key)
+		require.NoError(t, err, key)
+		assert.Equal(t, key, got.String())
+	}
+
+	for _, key := range []string{"", "with space", "tab\t", "ünicode", strings.Repeat("k", 256)} {
+		_, err := entities.NewIdempotencyKey(key)
+		require.ErrorIs(t, err, entities.ErrInvalidIdempotencyKey, key)
+	}
+}
@@ -0,0 +1,193 @@
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/memory"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/services"
+	"github.com/LarsArtmann/template-sqlc/pkg/fixtures"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// identityChangeFixture is two users and a service recording identity
+// changes in memory.
+type identityChangeFixture struct {
+	*serviceFixture
+
+	ada   *entities.User
+	grace *entities.User
+}
+
+func newIdentityChangeFixture(t *testing.T) *identityChangeFixture {
+	t.Helper()
+
+	f := &identityChangeFixture{serviceFixture: newServiceFixture()}
+
+	f.ada = f.createUser(t, fixtures.User().Named("ada").MustBuild())
+	f.grace = f.createUser(t, fixtures.User().Named("grace").MustBuild())
+	f.start(services.WithIdentityChanges(memory.NewIdentityChangeRepository(), time.Hour))
+
+	return f
+}
+
+func TestEmailChangeConfirmAndRevert(t *testing.T) {
+	f := newIdentityChangeFixture(t)
+	ctx := context.Background()
+
+	session := entities.NewUserSession(f.ada.ID(), nil, "", entities.NewSessionDeviceInfo(), time.Hour)
+	require.NoError(t, f.sessions.Create(ctx, session))
+
+	require.NoError(t, f.service.RequestEmailChange(ctx, f.ada.ID(), "Ada.New@Example.com"))
+
+	// The address only changes once the new one confirms it.
+	stored, err := f.users.GetByID(ctx, f.ada.ID())
+	require.NoError(t, err)
+	assert.Equal(t, "ada@example.com", stored.Email().String())
+
+	requested := lastEventData[events.EmailChangeRequestedEvent](t, f.publisher, events.EventEmailChangeRequested)
+	assert.Equal(t, "ada.new@example.com", requested.Email)
+
+	user, err := f.service.ConfirmEmailChange(ctx, requested.Token)
+	require.NoError(t, err)
+	assert.Equal(t, "ada.new@example.com", user.Email().String())
+	assert.True(t, user.IsVerified())
+
+	// The confirmation token is spent.
+	_, err = f.service.ConfirmEmailChange(ctx, requested.Token)
+	require.ErrorIs(t, err, entities.ErrIdentityChangeNotFound)
+
+	changed := lastEventData[events.IdentityChangeEvent](t, f.publisher, events.EventEmailChanged)
+	assert.Equal(t, "ada@example.com", changed.OldValue)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), changed.RevertableUntil, time.Minute)
+
+	user, err = f.service.RevertIdentityChange(ctx, changed.RevertToken)
+	require.NoError(t, err)
+	assert.Equal(t, "ada@example.com", user.Email().String())
+
+	_, err = f.service.RevertIdentityChange(ctx, changed.RevertToken)
+	require.ErrorIs(t, err, entities.ErrIdentityChangeNotApplied)
+
+	active, err := f.sessions.GetByUserID(ctx, f.ada.ID(), true)
+	require.NoError(t, err)
+	assert.Empty(t, active)
+
+	history, err := f.service.IdentityHistory(ctx, f.ada.ID())
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, entities.IdentityChangeReverted, history[0].Status)
+
+	require.Len(t, f.audit.entries, 2)
+	assert.Equal(t, entities.AuditActionEmailChange, f.audit.entries[0].Action)
+	assert.Equal(t, f.ada.ID(), f.audit.entries[0].ActorID)
+	assert.Equal(t, entities.AuditActionIdentityRevert, f.audit.entries[1].Action)
+	assert.Len(t, eventsOfType(f.publisher, events.EventIdentityChangeReverted), 1)
+}
+
+func TestRequestEmailChangeRejects(t *testing.T) {
+	f := newIdentityChangeFixture(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name  string
+		email string
+		err   error
+	}{
+		{"invalid address", "not-an-email", entities.ErrInvalidEmail},
+		{"current address", "ada@example.com", entities.ErrIdentityUnchanged},
+		{"address of another user", "grace@example.com", entities.ErrUserAlreadyExists},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := f.service.RequestEmailChange(ctx, f.ada.ID(), tt.email)
+			require.ErrorIs(t, err, tt.err)
+		})
+	}
+
+	assert.Empty(t, eventsOfType(f.publisher, events.EventEmailChangeRequested))
+}
+
+func TestConfirmEmailChangeRechecksAddress(t *testing.T) {
+	f := newIdentityChangeFixture(t)
+	ctx := context.Background()
+
+	require.NoError(t, f.service.RequestEmailChange(ctx, f.ada.ID(), "shared@example.com"))
+	requested := lastEventData[events.EmailChangeRequestedEvent](t, f.publisher, events.EventEmailChangeRequested)
+
+	// Another user claims the address before it is confirmed.
+	require.NoError(t, f.service.RequestEmailChange(ctx, f.grace.ID(), "shared@example.com"))
+	graceRequest := lastEventData[events.EmailChangeRequestedEvent](t, f.publisher, events.EventEmailChangeRequested)
+	_, err := f.service.ConfirmEmailChange(ctx, graceRequest.Token)
+	require.NoError(t, err)
+
+	_, err = f.service.ConfirmEmailChange(ctx, requested.Token)
+	require.ErrorIs(t, err, entities.ErrUserAlreadyExists)
+
+	_, err = f.service.ConfirmEmailChange(ctx, "not-a-token")
+	require.ErrorIs(t, err, entities.ErrIdentityChangeNotFound)
+}
+
+func TestChangeUsername(t *testing.T) {
+	f := newIdentityChangeFixture(t)
+	ctx := context.Background()
+
+	_, err := f.service.ChangeUsername(ctx, f.ada.ID(), "grace")
+	require.ErrorIs(t, err, entities.ErrUserAlreadyExists)
+
+	_, err = f.service.ChangeUsername(ctx, f.ada.ID(), "ada")
+	require.ErrorIs(t, err, entities.ErrIdentityUnchanged)
+
+	user, err := f.service.ChangeUsername(ctx, f.ada.ID(), "lovelace")
+	require.NoError(t, err)
+	assert.Equal(t, "lovelace", user.Username().String())
+
+	first := lastEventData[events.IdentityChangeEvent](t, f.publisher, events.EventUsernameChanged)
+	assert.Equal(t, "ada", first.OldValue)
+
+	_, err = f.service.ChangeUsername(ctx, f.ada.ID(), "countess")
+	require.NoError(t, err)
+
+	// Reverting the first change would undo the second one.
+	_, err = f.service.RevertIdentityChange(ctx, first.RevertToken)
+	require.ErrorIs(t, err, entities.ErrIdentityChangeSuperseded)
+
+	second := lastEventData[events.IdentityChangeEvent](t, f.publisher, events.EventUsernameChanged)
+	user, err = f.service.RevertIdentityChange(ctx, second.RevertToken)
+	require.NoError(t, err)
+	assert.Equal(t, "lovelace", user.Username().String())
+
+	history, err := f.service.IdentityHistory(ctx, f.ada.ID())
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, "countess", history[0].NewValue)
+	assert.Equal(t, entities.IdentityChangeReverted, history[0].Status)
+	assert.Equal(t, entities.IdentityChangeApplied, history[1].Status)
+}
+
+func TestIdentityChangeLifecycle(t *testing.T) {
+	change, err := entities.NewEmailChange(1, "old@example.com", "new@example.com", -time.Minute)
+	require.NoError(t, err)
+	require.ErrorIs(t, change.Revert(), entities.ErrIdentityChangeNotApplied)
+	require.ErrorIs(t, change.Apply(time.Hour), entities.ErrIdentityChangeExpired)
+
+	change, err = entities.NewUsernameChange(1, "ada", "lovelace", -time.Minute)
+	require.NoError(t, err)
+	require.ErrorIs(t, change.Apply(time.Hour), entities.ErrIdentityChangeNotPending)
+	require.ErrorIs(t, change.Revert(), entities.ErrIdentityChangeExpired)
+}
+
+func TestIdentityChangesUnavailable(t *testing.T) {
+	f := newServiceFixture()
+	f.start()
+
+	err := f.service.RequestEmailChange(context.Background(), 1, "ada@example.com")
+	require.ErrorIs(t, err, services.ErrIdentityChangesUnavailable)
+
+	_, err = f.service.RevertIdentityChange(context.Background(), "token")
+	require.ErrorIs(t, err, services.ErrIdentityChangesUnavailable)
+}
@@ -0,0 +1,144 @@
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/services"
+	"github.com/LarsArtmann/template-sqlc/pkg/fixtures"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// impersonationFixture is an admin, a user and a service auditing in memory.
+type impersonationFixture struct {
+	*serviceFixture
+
+	admin *entities.User
+	user  *entities.User
+}
+
+func newImpersonationFixture(t *testing.T) *impersonationFixture {
+	t.Helper()
+
+	f := &impersonationFixture{serviceFixture: newServiceFixture()}
+
+	f.admin = f.createUser(t, fixtures.User().Named("grace").WithRole(entities.UserRoleAdmin).MustBuild())
+	f.user = f.createUser(t, fixtures.User().Named("ada").MustBuild())
+	f.start(services.WithImpersonationLifetime(10 * time.Minute))
+
+	return f
+}
+
+func (f *impersonationFixture) eventTypes() []events.EventType {
+	var types []events.EventType
+
+	for _, event := range f.publisher.Events() {
+		types = append(types, event.Type)
+	}
+
+	return types
+}
+
+func TestImpersonate(t *testing.T) {
+	f := newImpersonationFixture(t)
+	ctx := services.ContextWithAuditActor(context.Background(),
+		services.AuditActor{UserID: f.admin.ID(), IPAddress: "192.0.2.1"})
+
+	session, err := f.service.Impersonate(ctx, f.admin.ID(), f.user.ID())
+	require.NoError(t, err)
+
+	impersonatorID, ok := session.Impersonator()
+	require.True(t, ok)
+	assert.Equal(t, f.admin.ID(), impersonatorID)
+	assert.Equal(t, f.user.ID(), session.UserID())
+	assert.WithinDuration(t, time.Now().Add(10*time.Minute), session.ExpiresAt(), time.Minute)
+
+	token, err := f.service.IssueSessionToken(session, f.user)
+	require.NoError(t, err)
+
+	verified, user, err := f.service.VerifySession(ctx, token)
+	require.NoError(t, err)
+	assert.Equal(t, f.user.ID(), user.ID())
+	assert.Equal(t, session.ExpiresAt(), verified.ExpiresAt(), "impersonations are not renewed")
+
+	require.NoError(t, f.service.Logout(ctx, token))
+
+	_, _, err = f.service.VerifySession(ctx, token)
+	require.Error(t, err)
+
+	assert.Equal(t, []events.EventType{events.EventImpersonationStarted, events.EventImpersonationEnded},
+		f.eventTypes())
+
+	data, ok := f.publisher.Events()[0].Data.(events.ImpersonationEvent)
+	require.True(t, ok)
+	assert.Equal(t, f.admin.ID(), data.ImpersonatorID)
+	assert.Equal(t, f.user.ID(), data.UserID)
+
+	require.Len(t, f.audit.entries, 2)
+
+	for i, action := range []entities.AuditAction{
+		entities.AuditActionImpersonationStart,
+		entities.AuditActionImpersonationEnd,
+	} {
+		entry := f.audit.entries[i]
+		assert.Equal(t, action, entry.Action)
+		assert.Equal(t, f.admin.ID(), entry.ActorID)
+		assert.Equal(t, f.user.ID(), entry.UserID)
+		assert.Equal(t, "192.0.2.1", entry.IPAddress)
+	}
+}
+
+func TestImpersonateRequiresAdmin(t *testing.T) {
+	f := newImpersonationFixture(t)
+
+	tests := []struct {
+		name          string
+		actor         entities.UserID
+		admin, target entities.UserID
+		err           error
+	}{
+		{"user", 0, f.user.ID(), f.admin.ID(), entities.ErrInsufficientPrivileges},
+		{"self", 0, f.admin.ID(), f.admin.ID(), entities.ErrImpersonationForbidden},
+		{"another actor", f.user.ID(), f.admin.ID(), f.user.ID(), entities.ErrInsufficientPrivileges},
+		{"unknown target", 0, f.admin.ID(), entities.UserID(999), entities.ErrUserNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.actor != 0 {
+				ctx = services.ContextWithAuditActor(ctx, services.AuditActor{UserID: tt.actor})
+			}
+
+			_, err := f.service.Impersonate(ctx, tt.admin, tt.target)
+			require.ErrorIs(t, err, tt.err)
+		})
+	}
+
+	assert.Empty(t, f.publisher.Events())
+	assert.Empty(t, f.audit.entries)
+}
+
+func TestEndImpersonation(t *testing.T) {
+	f := newImpersonationFixture(t)
+	ctx := context.Background()
+
+	login, err := f.service.Impersonate(ctx, f.admin.ID(), f.user.ID())
+	require.NoError(t, err)
+
+	require.NoError(t, f.service.EndImpersonation(ctx, login.Token().String()))
+	require.NoError(t, f.service.EndImpersonation(ctx, login.Token().String()), "already ended")
+
+	regular := entities.NewUserSession(f.user.ID(), nil, "", entities.NewSessionDeviceInfo(), time.Hour)
+	require.NoError(t, f.sessions.Create(ctx, regular))
+
+	err = f.service.EndImpersonation(ctx, regular.Token().String())
+	require.ErrorIs(t, err, entities.ErrNotImpersonating)
+
+	assert.Equal(t, []events.EventType{events.EventImpersonationStarted, events.EventImpersonationEnded},
+		f.eventTypes())
+}
@@ -0,0 +1,91 @@
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/instrumented"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// countingObserver counts observed queries and failures.
+type countingObserver struct {
+	queries  int
+	failures int
+	engine   string
+}
+
+func (o *countingObserver) ObserveQuery(_, engine string, _ time.Duration, err error) {
+	o.engine = engine
+	o.queries++
+	if err != nil {
+		o.failures++
+	}
+}
+
+// recordingTracerProvider records the names of started spans.
+type recordingTracerProvider struct {
+	noop.TracerProvider
+
+	spans []string
+}
+
+func (p *recordingTracerProvider) Tracer(string, ...trace.TracerOption) trace.Tracer {
+	return recordingTracer{provider: p}
+}
+
+type recordingTracer struct {
+	noop.Tracer
+
+	provider *recordingTracerProvider
+}
+
+func (t recordingTracer) Start(
+	ctx context.Context,
+	name string,
+	opts ...trace.SpanStartOption,
+) (context.Context, trace.Span) {
+	t.provider.spans = append(t.provider.spans, name)
+
+	return t.Tracer.Start(ctx, name, opts...)
+}
+
+func TestInstrumentedUserRepository(t *testing.T) {
+	ctx := context.Background()
+
+	user := newTestUser(t, entities.UserRoleUser)
+	user.SetID(7)
+
+	observer := &countingObserver{}
+	provider := &recordingTracerProvider{}
+	repo := instrumented.NewUserRepository(
+		&countingUserRepository{user: user},
+		instrumented.WithMetrics(observer),
+		instrumented.WithTracerProvider(provider),
+		instrumented.WithSlowThreshold(0),
+		instrumented.WithEngine("sqlite"),
+	)
+
+	found, err := repo.GetByID(ctx, 7)
+	require.NoError(t, err)
+	assert.Equal(t, user, found)
+
+	_, err = repo.GetByID(ctx, 8)
+	require.ErrorIs(t, err, entities.ErrUserNotFound)
+
+	require.NoError(t, repo.Update(ctx, user))
+
+	assert.Equal(t, 3, observer.queries)
+	assert.Equal(t, 1, observer.failures)
+	assert.Equal(t, "sqlite", observer.engine)
+	assert.Equal(t, []string{
+		"UserRepository.GetByID",
+		"UserRepository.GetByID",
+		"UserRepository.Update",
+	}, provider.spans)
+}
@@ -0,0 +1,401 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/memory"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+	"github.com/LarsArtmann/template-sqlc/internal/jobs"
+	"github.com/LarsArtmann/template-sqlc/pkg/dblock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startPool runs pool until the test ends.
+func startPool(t *testing.T, pool *jobs.Pool) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+
+	go func() { done <- pool.Run(ctx) }()
+
+	t.Cleanup(func() {
+		cancel()
+		require.NoError(t, <-done)
+	})
+}
+
+// waitForJob waits until the job with id has status and returns it.
+func waitForJob(t *testing.T, repo *memory.JobRepository, id entities.JobID, status entities.JobStatus) *entities.Job {
+	t.Helper()
+
+	var job *entities.Job
+
+	require.Eventually(t, func() bool {
+		var err error
+
+		job, err = repo.Get(context.Background(), id)
+		require.NoError(t, err)
+
+		return job.Status == status
+	}, 5*time.Second, 5*time.Millisecond, "job %d never became %v", id, status)
+
+	return job
+}
+
+func TestPoolFinishesJobs(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewJobRepository()
+	pool := jobs.NewPool(repo, jobs.WithPollInterval(5*time.Millisecond), jobs.WithBackoff(0, 0))
+
+	var flakyCalls atomic.Int32
+
+	pool.Register("ok", jobs.HandlerFunc(func(context.Context, *entities.Job) error { return nil }))
+	pool.Register("flaky", jobs.HandlerFunc(func(context.Context, *entities.Job) error {
+		if flakyCalls.Add(1) == 1 {
+			return errors.New("temporary")
+		}
+
+		return nil
+	}))
+	pool.Register("permanent", jobs.HandlerFunc(func(context.Context, *entities.Job) error {
+		return jobs.Permanent(errors.New("bad input"))
+	}))
+	pool.Register("panics", jobs.HandlerFunc(func(context.Context, *entities.Job) error {
+		panic("boom")
+	}))
+
+	enqueue := func(name string) entities.JobID {
+		job, err := jobs.Enqueue(ctx, repo, name, map[string]string{"name": name})
+		require.NoError(t, err)
+
+		return job.ID
+	}
+
+	ok, flaky, permanent, panics, unknown := enqueue("ok"), enqueue("flaky"), enqueue("permanent"),
+		enqueue("panics"), enqueue("unknown")
+
+	startPool(t, pool)
+
+	assert.Equal(t, 1, waitForJob(t, repo, ok, entities.JobStatusDone).Attempts)
+	assert.Equal(t, 2, waitForJob(t, repo, flaky, entities.JobStatusDone).Attempts)
+
+	job := waitForJob(t, repo, permanent, entities.JobStatusFailed)
+	assert.Equal(t, 1, job.Attempts)
+	assert.Equal(t, "bad input", job.LastError)
+
+	job = waitForJob(t, repo, panics, entities.JobStatusFailed)
+	assert.Equal(t, entities.DefaultJobMaxAttempts, job.Attempts)
+	assert.Contains(t, job.LastError, "panic: boom")
+
+	job = waitForJob(t, repo, unknown, entities.JobStatusFailed)
+	assert.Equal(t, 1, job.Attempts)
+	assert.Contains(t, job.LastError, jobs.ErrUnknownHandler.Error())
+}
+
+func TestPoolBacksOffRetries(t *testing.T) {
+	repo := memory.NewJobRepository()
+	pool := jobs.NewPool(repo, jobs.WithPollInterval(5*time.Millisecond), jobs.WithBackoff(time.Hour, 2*time.Hour))
+	pool.Register("failing", jobs.HandlerFunc(func(context.Context, *entities.Job) error {
+		return errors.New("unavailable")
+	}))
+
+	job, err := jobs.Enqueue(context.Background(), repo, "failing", nil)
+	require.NoError(t, err)
+
+	startPool(t, pool)
+
+	require.Eventually(t, func() bool {
+		stored, err := repo.Get(context.Background(), job.ID)
+		require.NoError(t, err)
+
+		return stored.Attempts == 1 && stored.Status == entities.JobStatusPending
+	}, 5*time.Second, 5*time.Millisecond)
+
+	stored, err := repo.Get(context.Background(), job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "unavailable", stored.LastError)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), stored.RunAt, time.Minute)
+}
+
+func TestPoolShutdownWaitsForRunningJobs(t *testing.T) {
+	repo := memory.NewJobRepository()
+	pool := jobs.NewPool(repo, jobs.WithPollInterval(5*time.Millisecond))
+
+	started, release := make(chan struct{}), make(chan struct{})
+
+	pool.Register("slow", jobs.HandlerFunc(func(ctx context.Context, _ *entities.Job) error {
+		close(started)
+		<-release
+
+		return ctx.Err()
+	}))
+
+	job, err := jobs.Enqueue(context.Background(), repo, "slow", nil)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+
+	go func() { done <- pool.Run(ctx) }()
+
+	<-started
+	cancel()
+
+	select {
+	case <-done:
+		t.Fatal("Run returned before the running job finished")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(release)
+	require.NoError(t, <-done)
+
+	stored, err := repo.Get(context.Background(), job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entities.JobStatusDone, stored.Status)
+}
+
+// lockedPublisher is an InMemoryEventPublisher safe for the pool's workers.
+type lockedPublisher struct {
+	mu        sync.Mutex
+	publisher *events.InMemoryEventPublisher
+}
+
+func (p *lockedPublisher) Publish(event *events.UserEvent) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.publisher.Publish(event)
+}
+
+func (p *lockedPublisher) PublishBatch(batch []*events.UserEvent) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.publisher.PublishBatch(batch)
+}
+
+func (p *lockedPublisher) Events() []*events.UserEvent {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return slices.Clone(p.publisher.Events())
+}
+
+func TestOutboxRelaysPublishedEvents(t *testing.T) {
+	repo := memory.NewJobRepository()
+	codec := events.CloudEventCodec{Source: "/template-sqlc/users", TypePrefix: "com.example.users."}
+	relayed := &lockedPublisher{publisher: events.NewInMemoryEventPublisher()}
+
+	outbox := jobs.NewOutboxPublisher(repo, codec)
+	require.NoError(t, outbox.PublishBatch([]*events.UserEvent{
+		events.UserVerified(7, "email"),
+		events.UserVerified(8, "email"),
+	}))
+
+	pool := jobs.NewPool(repo, jobs.WithPollInterval(5*time.Millisecond))
+	pool.Register(jobs.OutboxRelayJob, jobs.NewOutboxRelay(codec, relayed))
+	startPool(t, pool)
+
+	require.Eventually(t, func() bool { return len(relayed.Events()) == 2 }, 5*time.Second, 5*time.Millisecond)
+
+	userIDs := make([]entities.UserID, 0, 2)
+	for _, event := range relayed.Events() {
+		assert.Equal(t, events.EventUserVerified, event.Type)
+
+		userIDs = append(userIDs, event.UserID)
+	}
+
+	assert.ElementsMatch(t, []entities.UserID{7, 8}, userIDs)
+}
+
+// recordingMailer records the emails it sends.
+type recordingMailer struct {
+	mu   sync.Mutex
+	sent []jobs.Email
+}
+
+func (m *recordingMailer) Send(_ context.Context, email jobs.Email) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.sent = append(m.sent, email)
+
+	return nil
+}
+
+func TestEmailHandler(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewJobRepository()
+	mailer := &recordingMailer{}
+	handler := jobs.NewEmailHandler(mailer)
+
+	email := jobs.Email{To: "ada@example.com", Subject: "Welcome", Body: "Hello Ada"}
+
+	job, err := jobs.Enqueue(ctx, repo, jobs.EmailSendJob, email)
+	require.NoError(t, err)
+	require.NoError(t, handler.Handle(ctx, job))
+	assert.Equal(t, []jobs.Email{email}, mailer.sent)
+
+	job, err = jobs.Enqueue(ctx, repo, jobs.EmailSendJob, jobs.Email{Subject: "Nobody"})
+	require.NoError(t, err)
+
+	err = handler.Handle(ctx, job)
+	require.ErrorIs(t, err, jobs.ErrInvalidEmail)
+	assert.True(t, jobs.IsPermanent(err))
+
+	job.Payload = []byte(`"not an email"`)
+	assert.True(t, jobs.IsPermanent(handler.Handle(ctx, job)))
+}
+
+func TestCleanupHandlerDeletesOldDoneJobs(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewJobRepository()
+
+	job, err := jobs.Enqueue(ctx, repo, "ok", nil)
+	require.NoError(t, err)
+
+	claimed, err := repo.Claim(ctx, time.Now(), time.Minute, 1)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+	require.NoError(t, repo.Complete(ctx, claimed[0]))
+
+	handler := jobs.NewCleanupHandler(jobs.Cleanup{
+		Sessions:         memory.NewSessionRepository(),
+		Jobs:             repo,
+		DoneJobRetention: time.Hour,
+	})
+
+	require.NoError(t, handler.Handle(ctx, job))
+
+	_, err = repo.Get(ctx, job.ID)
+	require.NoError(t, err, "the job finished less than the retention ago")
+
+	handler = jobs.NewCleanupHandler(jobs.Cleanup{Jobs: repo, DoneJobRetention: time.Nanosecond})
+	require.NoError(t, handler.Handle(ctx, job))
+
+	_, err = repo.Get(ctx, job.ID)
+	require.ErrorIs(t, err, entities.ErrJobNotFound)
+}
+
+func TestCleanupHandlerDeletesExpiredIdempotencyKeys(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewIdempotencyRepository()
+
+	expired := entities.NewIdempotencyRecord("expired", "CreateUser", "hash", -time.Second)
+	live := entities.NewIdempotencyRecord("live", "CreateUser", "hash", time.Hour)
+	require.NoError(t, repo.Reserve(ctx, expired))
+	require.NoError(t, repo.Reserve(ctx, live))
+
+	handler := jobs.NewCleanupHandler(jobs.Cleanup{Idempotency: repo})
+	require.NoError(t, handler.Handle(ctx, &entities.Job{}))
+
+	_, err := repo.Get(ctx, expired.Key)
+	require.ErrorIs(t, err, entities.ErrIdempotencyKeyNotFound)
+
+	_, err = repo.Get(ctx, live.Key)
+	require.NoError(t, err)
+}
+
+// mapLocker is a dblock.Locker over a set of held names.
+type mapLocker struct {
+	mu   sync.Mutex
+	held map[string]bool
+}
+
+func (l *mapLocker) Lock(ctx context.Context, name string) (dblock.Lock, error) {
+	return l.TryLock(ctx, name)
+}
+
+func (l *mapLocker) TryLock(_ context.Context, name string) (dblock.Lock, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.held[name] {
+		return nil, dblock.ErrLocked
+	}
+
+	l.held[name] = true
+
+	return mapLock{locker: l, name: name}, nil
+}
+
+type mapLock struct {
+	locker *mapLocker
+	name   string
+}
+
+func (l mapLock) Unlock(context.Context) error {
+	l.locker.mu.Lock()
+	defer l.locker.mu.Unlock()
+
+	delete(l.locker.held, l.name)
+
+	return nil
+}
+
+func TestExclusiveHandler(t *testing.T) {
+	ctx := context.Background()
+	locker := &mapLocker{held: map[string]bool{}}
+
+	var runs atomic.Int32
+
+	handler := jobs.Exclusive(locker, jobs.CleanupJob, jobs.HandlerFunc(func(context.Context, *entities.Job) error {
+		runs.Add(1)
+
+		return nil
+	}))
+
+	require.NoError(t, handler.Handle(ctx, &entities.Job{}))
+	assert.Equal(t, int32(1), runs.Load())
+	assert.Empty(t, locker.held, "the lock is released after the job")
+
+	// Another instance is running a cleanup job.
+	lock, err := locker.TryLock(ctx, "jobs:"+jobs.CleanupJob)
+	require.NoError(t, err)
+
+	err = handler.Handle(ctx, &entities.Job{})
+	require.ErrorIs(t, err, dblock.ErrLocked)
+	assert.False(t, jobs.IsPermanent(err), "the job is retried")
+	assert.Equal(t, int32(1), runs.Load())
+
+	require.NoError(t, lock.Unlock(ctx))
+}
+
+// syncerFunc adapts a function to jobs.Syncer.
+type syncerFunc func(ctx context.Context) (int64, error)
+
+func (f syncerFunc) Sync(ctx context.Context) (int64, error) { return f(ctx) }
+
+func TestAnalyticsSyncHandler(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewJobRepository()
+
+	job, err := jobs.Enqueue(ctx, repo, jobs.AnalyticsSyncJob, nil)
+	require.NoError(t, err)
+
+	synced := 0
+	handler := jobs.NewAnalyticsSyncHandler(syncerFunc(func(context.Context) (int64, error) {
+		synced++
+
+		return 3, nil
+	}))
+	require.NoError(t, handler.Handle(ctx, job))
+	assert.Equal(t, 1, synced)
+
+	errSync := errors.New("analytics store unavailable")
+	handler = jobs.NewAnalyticsSyncHandler(syncerFunc(func(context.Context) (int64, error) { return 0, errSync }))
+
+	err = handler.Handle(ctx, job)
+	require.ErrorIs(t, err, errSync)
+	assert.False(t, jobs.IsPermanent(err), "a failed sync is retried")
+}
@@ -0,0 +1,94 @@
+package unit
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/tokens"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTokenClaims() services.TokenClaims {
+	return services.TokenClaims{
+		SessionToken: entities.NewSessionToken(),
+		UserID:       entities.UserID(7),
+		Role:         entities.UserRoleModerator,
+		ExpiresAt:    time.Now().Add(time.Hour).Truncate(time.Second),
+	}
+}
+
+func TestJWTStrategyRoundTrip(t *testing.T) {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	keys := map[string]tokens.SigningKey{
+		"HS256": tokens.NewHMACKey("hmac-1", []byte("0123456789abcdef0123456789abcdef")),
+		"EdDSA": tokens.NewEd25519Key("ed-1", private),
+	}
+
+	for name, key := range keys {
+		t.Run(name, func(t *testing.T) {
+			strategy := tokens.NewJWTStrategy("template-sqlc", key)
+			claims := newTokenClaims()
+
+			token, err := strategy.Issue(claims)
+			require.NoError(t, err)
+
+			parsed, err := strategy.Parse(token)
+			require.NoError(t, err)
+			assert.Equal(t, claims.SessionToken, parsed.SessionToken)
+			assert.Equal(t, claims.UserID, parsed.UserID)
+			assert.Equal(t, claims.Role, parsed.Role)
+			assert.True(t, claims.ExpiresAt.Equal(parsed.ExpiresAt))
+		})
+	}
+}
+
+func TestJWTStrategyKeyRotation(t *testing.T) {
+	strategy := tokens.NewJWTStrategy("template-sqlc", tokens.NewHMACKey("old", []byte("old-secret-old-secret-old-secret")))
+
+	oldToken, err := strategy.Issue(newTokenClaims())
+	require.NoError(t, err)
+
+	strategy.Rotate(tokens.NewHMACKey("new", []byte("new-secret-new-secret-new-secret")))
+
+	_, err = strategy.Parse(oldToken)
+	require.NoError(t, err)
+
+	strategy.Retire("old")
+
+	_, err = strategy.Parse(oldToken)
+	require.ErrorIs(t, err, tokens.ErrUnknownKey)
+
+	newToken, err := strategy.Issue(newTokenClaims())
+	require.NoError(t, err)
+
+	_, err = strategy.Parse(newToken)
+	require.NoError(t, err)
+}
+
+func TestJWTStrategyRejectsForeignTokens(t *testing.T) {
+	claims := newTokenClaims()
+	claims.ExpiresAt = time.Now().Add(-time.Minute)
+
+	strategy := tokens.NewJWTStrategy("template-sqlc", tokens.NewHMACKey("k", []byte("secret-secret-secret-secret-1234")))
+
+	expired, err := strategy.Issue(claims)
+	require.NoError(t, err)
+
+	_, err = strategy.Parse(expired)
+	require.Error(t, err)
+
+	other := tokens.NewJWTStrategy("someone-else", tokens.NewHMACKey("k", []byte("secret-secret-secret-secret-1234")))
+
+	foreign, err := other.Issue(newTokenClaims())
+	require.NoError(t, err)
+
+	_, err = strategy.Parse(foreign)
+	require.Error(t, err)
+}
@@ -0,0 +1,44 @@
+package unit
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters"
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/converters"
+	"github.com/LarsArtmann/template-sqlc/internal/db/shared"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+	"github.com/stretchr/testify/assert"
+)
+
+// poolDB is a database handle that, like *sql.DB, begins transactions.
+type poolDB struct {
+	shared.DBTX
+}
+
+func (poolDB) BeginTx(context.Context, *sql.TxOptions) (*sql.Tx, error) {
+	return nil, sql.ErrConnDone
+}
+
+func TestWithLockingClause(t *testing.T) {
+	query := "SELECT id FROM users WHERE id = $1;\n"
+
+	assert.Equal(t, "SELECT id FROM users WHERE id = $1 FOR UPDATE", adapters.WithLockingClause(query))
+	assert.Equal(
+		t,
+		"SELECT id FROM users WHERE id = $1 FOR SHARE NOWAIT",
+		adapters.WithLockingClause(query, repositories.WithShareLock(), repositories.WithNoWait()),
+	)
+	assert.Equal(
+		t,
+		"SELECT id FROM users WHERE id = $1 FOR UPDATE SKIP LOCKED",
+		adapters.WithLockingClause(query, repositories.WithSkipLocked()),
+	)
+}
+
+func TestDBUserRepositoryInTransaction(t *testing.T) {
+	assert.False(t, adapters.NewDBUserRepository(poolDB{}, converters.DbTypeMySQL).InTransaction(),
+		"a handle that begins transactions is not one")
+	assert.True(t, adapters.NewDBUserRepository(&sql.Tx{}, converters.DbTypeMySQL).InTransaction())
+}
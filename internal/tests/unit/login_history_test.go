@@ -0,0 +1,97 @@
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/memory"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/services"
+	"github.com/LarsArtmann/template-sqlc/pkg/fixtures"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// loginFailures returns the failures of attempts in order.
+func loginFailures(attempts []*entities.LoginAttempt) []entities.LoginFailure {
+	failures := make([]entities.LoginFailure, 0, len(attempts))
+	for _, attempt := range attempts {
+		failures = append(failures, attempt.Failure)
+	}
+
+	return failures
+}
+
+func TestAuthenticateUserRecordsLoginHistory(t *testing.T) {
+	ctx := context.Background()
+	users := memory.NewUserRepository()
+
+	ada := fixtures.User().Named("ada").WithPassword(hashPassword(t, "secret")).MustBuild()
+	require.NoError(t, users.Create(ctx, ada))
+
+	bob := fixtures.User().Named("bob").WithPassword(hashPassword(t, "secret")).WithStatus(entities.UserStatusSuspended).MustBuild()
+	require.NoError(t, users.Create(ctx, bob))
+
+	service := services.NewUserService(
+		users, memory.NewSessionRepository(), events.NewInMemoryEventPublisher(), nil,
+		services.WithPasswordHasher(testHasher),
+		services.WithLoginHistory(memory.NewLoginHistoryRepository()),
+	)
+
+	_, err := service.AuthenticateUser(ctx, "ada@example.com", "wrong", "::ffff:192.0.2.1", "firefox")
+	require.ErrorIs(t, err, entities.ErrInvalidCredentials)
+
+	_, err = service.AuthenticateUser(ctx, "ada@example.com", "secret", "192.0.2.1", "firefox")
+	require.NoError(t, err)
+
+	_, err = service.AuthenticateUser(ctx, "bob@example.com", "secret", "192.0.2.1", "firefox")
+	require.ErrorIs(t, err, entities.ErrAccountSuspended)
+
+	logins, err := service.RecentLogins(ctx, ada.ID(), 10)
+	require.NoError(t, err)
+	require.Len(t, logins, 1, "failures of unknown credentials belong to no account")
+	assert.True(t, logins[0].Succeeded())
+	assert.Equal(t, entities.LoginMethodPassword, logins[0].Method)
+	assert.Equal(t, "firefox", logins[0].UserAgent)
+
+	// Addresses are compared in canonical form.
+	failures, err := service.LoginFailuresByIP(ctx, "192.0.2.1", time.Hour, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []entities.LoginFailure{
+		entities.LoginFailureInactiveAccount,
+		entities.LoginFailureInvalidCredentials,
+	}, loginFailures(failures))
+	assert.Equal(t, bob.ID(), failures[0].UserID)
+	assert.Zero(t, failures[1].UserID)
+
+	_, err = service.LoginFailuresByIP(ctx, "192.0.2.1", 0, 10)
+	require.Error(t, err)
+}
+
+func TestAuthenticateUserRecordsThrottledLogins(t *testing.T) {
+	ctx := context.Background()
+	service := services.NewUserService(
+		memory.NewUserRepository(), memory.NewSessionRepository(), events.NewInMemoryEventPublisher(), nil,
+		services.WithPasswordHasher(testHasher),
+		services.WithLoginThrottle(fixedThrottle(time.Minute)),
+		services.WithLoginHistory(memory.NewLoginHistoryRepository()),
+	)
+
+	_, err := service.AuthenticateUser(ctx, "ada@example.com", "secret", "192.0.2.1", "firefox")
+	require.True(t, entities.IsRateLimitError(err))
+
+	failures, err := service.LoginFailuresByIP(ctx, "192.0.2.1", time.Hour, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []entities.LoginFailure{entities.LoginFailureRateLimited}, loginFailures(failures))
+}
+
+func TestLoginHistoryUnavailable(t *testing.T) {
+	service := services.NewUserService(
+		memory.NewUserRepository(), memory.NewSessionRepository(), events.NewInMemoryEventPublisher(), nil,
+	)
+
+	_, err := service.RecentLogins(context.Background(), 1, 10)
+	require.ErrorIs(t, err, services.ErrLoginHistoryUnavailable)
+}
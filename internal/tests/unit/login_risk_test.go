@@ -0,0 +1,157 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/geoip"
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/memory"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/services"
+	"github.com/LarsArtmann/template-sqlc/pkg/fixtures"
+	"github.com/oschwald/geoip2-golang"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errUnknownAddress = errors.New("unknown address")
+
+// geoTable locates the addresses it has and fails for the others.
+type geoTable map[string]entities.GeoLocation
+
+func (g geoTable) Locate(_ context.Context, ip net.IP) (entities.GeoLocation, error) {
+	location, ok := g[ip.String()]
+	if !ok {
+		return entities.GeoLocation{}, errUnknownAddress
+	}
+
+	return location, nil
+}
+
+// cityTable is a geoip.CityReader over a map.
+type cityTable map[string]*geoip2.City
+
+func (c cityTable) City(ip net.IP) (*geoip2.City, error) {
+	city, ok := c[ip.String()]
+	if !ok {
+		return nil, errUnknownAddress
+	}
+
+	return city, nil
+}
+
+func TestAuthenticateUserFlagsSuspiciousLogins(t *testing.T) {
+	ctx := context.Background()
+	users := memory.NewUserRepository()
+	sessions := memory.NewSessionRepository()
+	publisher := events.NewInMemoryEventPublisher()
+
+	ada := fixtures.User().Named("ada").WithPassword(hashPassword(t, "secret")).MustBuild()
+	require.NoError(t, users.Create(ctx, ada))
+
+	geo := geoTable{
+		"192.0.2.1": {Country: "DE", Region: "BE", City: "Berlin"},
+		"192.0.2.2": {Country: "DE", Region: "HH", City: "Hamburg"},
+		"192.0.2.3": {Country: "FR"},
+	}
+	service := services.NewUserService(users, sessions, publisher, nil,
+		services.WithPasswordHasher(testHasher),
+		services.WithLoginRiskDetection(geo),
+	)
+
+	login := func(ip, agent string) *entities.UserSession {
+		t.Helper()
+
+		session, err := service.AuthenticateUser(ctx, "ada@example.com", "secret", ip, agent)
+		require.NoError(t, err)
+
+		return session
+	}
+
+	suspicious := func() []*events.UserEvent {
+		var flagged []*events.UserEvent
+
+		for _, event := range publisher.Events() {
+			if event.Type == events.EventUserLoginSuspicious {
+				flagged = append(flagged, event)
+			}
+		}
+
+		return flagged
+	}
+
+	first := login("192.0.2.1", "firefox")
+	assert.Equal(t, entities.GeoLocation{Country: "DE", Region: "BE", City: "Berlin"}, first.Location())
+
+	// Another city of a known country, and an unknown address, are fine.
+	login("192.0.2.2", "firefox")
+	unknown := login("198.51.100.1", "firefox")
+	assert.True(t, unknown.Location().IsZero())
+	assert.Empty(t, suspicious())
+
+	login("192.0.2.3", "safari")
+
+	flagged := suspicious()
+	require.Len(t, flagged, 1)
+
+	data, ok := flagged[0].Data.(events.SuspiciousLoginEvent)
+	require.True(t, ok)
+	assert.Equal(t, "FR", data.Country)
+	assert.Equal(t, []string{"new_country", "new_device"}, data.Reasons)
+	assert.Equal(t, ada.ID(), flagged[0].UserID)
+}
+
+func TestAuthenticateUserStepUp(t *testing.T) {
+	ctx := context.Background()
+	users := memory.NewUserRepository()
+	sessions := memory.NewSessionRepository()
+
+	ada := fixtures.User().Named("ada").WithPassword(hashPassword(t, "secret")).MustBuild()
+	require.NoError(t, users.Create(ctx, ada))
+
+	var risks []entities.LoginRisk
+
+	service := services.NewUserService(users, sessions, events.NewInMemoryEventPublisher(), nil,
+		services.WithPasswordHasher(testHasher),
+		services.WithLoginRiskDetection(nil),
+		services.WithStepUpHook(func(_ context.Context, _ *entities.User, risk entities.LoginRisk) error {
+			risks = append(risks, risk)
+
+			return entities.ErrStepUpRequired
+		}),
+	)
+
+	_, err := service.AuthenticateUser(ctx, "ada@example.com", "secret", "192.0.2.1", "firefox")
+	require.NoError(t, err)
+
+	_, err = service.AuthenticateUser(ctx, "ada@example.com", "secret", "192.0.2.1", "curl")
+	require.ErrorIs(t, err, entities.ErrStepUpRequired)
+	assert.True(t, entities.IsAuthenticationError(err))
+
+	require.Len(t, risks, 1)
+	assert.Equal(t, []entities.LoginRiskReason{entities.LoginRiskNewDevice}, risks[0].Reasons)
+
+	stored, err := sessions.GetByUserID(ctx, ada.ID(), false)
+	require.NoError(t, err)
+	assert.Len(t, stored, 1, "denied logins start no session")
+}
+
+func TestGeoIPResolver(t *testing.T) {
+	berlin := &geoip2.City{}
+	berlin.Country.IsoCode = "DE"
+	berlin.City.Names = map[string]string{"en": "Berlin", "de": "Berlin"}
+
+	resolver := geoip.NewResolver(cityTable{"192.0.2.1": berlin})
+
+	location, err := resolver.Locate(context.Background(), net.ParseIP("192.0.2.1"))
+	require.NoError(t, err)
+	assert.Equal(t, entities.GeoLocation{Country: "DE", City: "Berlin"}, location)
+	assert.Equal(t, "Berlin, DE", location.String())
+
+	_, err = resolver.Locate(context.Background(), net.ParseIP("192.0.2.2"))
+	require.ErrorIs(t, err, errUnknownAddress)
+	require.NoError(t, resolver.Close())
+}
@@ -0,0 +1,118 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/messaging"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errBrokerDown = errors.New("broker unavailable")
+
+// flakyJetStream fails the first failures publishes and records the rest.
+type flakyJetStream struct {
+	failures int
+	calls    int
+	msgs     []*nats.Msg
+}
+
+func (f *flakyJetStream) PublishMsg(
+	_ context.Context,
+	msg *nats.Msg,
+	_ ...jetstream.PublishOpt,
+) (*jetstream.PubAck, error) {
+	f.calls++
+	if f.calls <= f.failures {
+		return nil, errBrokerDown
+	}
+
+	f.msgs = append(f.msgs, msg)
+
+	return &jetstream.PubAck{}, nil
+}
+
+// flakyWriter fails the first failures writes and records the rest.
+type flakyWriter struct {
+	failures int
+	calls    int
+	msgs     []kafka.Message
+}
+
+func (f *flakyWriter) WriteMessages(_ context.Context, msgs ...kafka.Message) error {
+	f.calls++
+	if f.calls <= f.failures {
+		return errBrokerDown
+	}
+
+	f.msgs = append(f.msgs, msgs...)
+
+	return nil
+}
+
+var fastRetry = messaging.WithRetry(messaging.RetryPolicy{
+	MaxAttempts:    3,
+	InitialBackoff: time.Millisecond,
+	MaxBackoff:     time.Millisecond,
+})
+
+func TestNATSPublisherRetriesAndRoutes(t *testing.T) {
+	js := &flakyJetStream{failures: 2}
+	publisher := messaging.NewNATSPublisher(js, fastRetry, messaging.WithTopics(messaging.Topics{
+		ByType: map[events.EventType]string{events.EventUserCreated: "users.created"},
+	}))
+
+	event := events.UserCreated(7, "a@example.com", "alice", "Alice", "Doe", "user", "active")
+	require.NoError(t, publisher.Publish(event))
+
+	assert.Equal(t, 3, js.calls)
+	require.Len(t, js.msgs, 1)
+	assert.Equal(t, "users.created", js.msgs[0].Subject)
+	assert.Equal(t, "application/json", js.msgs[0].Header.Get("Content-Type"))
+	assert.NotEmpty(t, js.msgs[0].Header.Get(jetstream.MsgIDHeader))
+}
+
+func TestNATSPublisherGivesUp(t *testing.T) {
+	js := &flakyJetStream{failures: 5}
+	publisher := messaging.NewNATSPublisher(js, fastRetry)
+
+	err := publisher.Publish(events.UserVerified(7, "email"))
+
+	require.ErrorIs(t, err, errBrokerDown)
+	assert.Equal(t, 3, js.calls)
+}
+
+func TestKafkaPublisherCloudEvents(t *testing.T) {
+	writer := &flakyWriter{failures: 1}
+	publisher := messaging.NewKafkaPublisher(
+		writer,
+		fastRetry,
+		messaging.WithSerializer(events.CloudEventCodec{Source: "/users", TypePrefix: "com.example."}),
+	)
+
+	batch := []*events.UserEvent{
+		events.UserCreated(7, "a@example.com", "alice", "Alice", "Doe", "user", "active"),
+		events.UserVerified(entities.UserID(8), "email"),
+	}
+	require.NoError(t, publisher.PublishBatch(batch))
+
+	require.Len(t, writer.msgs, 2)
+	assert.Equal(t, "users.events", writer.msgs[0].Topic)
+	assert.Equal(t, []byte("8"), writer.msgs[1].Key)
+
+	var envelope map[string]any
+	require.NoError(t, json.Unmarshal(writer.msgs[0].Value, &envelope))
+	assert.Equal(t, "1.0", envelope["specversion"])
+	assert.Equal(t, "com.example.user.created", envelope["type"])
+	assert.Equal(t, "/users", envelope["source"])
+	assert.Equal(t, "7", envelope["subject"])
+}
@@ -0,0 +1,40 @@
+package unit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/LarsArtmann/template-sqlc/internal/monitoring"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serve(t *testing.T, handler http.Handler, path string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+
+	return recorder
+}
+
+func TestMetricsServerOptionalEndpoints(t *testing.T) {
+	metrics := monitoring.NewMetrics()
+
+	handler, err := metrics.Handler()
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, serve(t, handler, "/debug/pprof/").Code)
+	assert.NotContains(t, serve(t, handler, "/metrics").Body.String(), "go_goroutines")
+
+	handler, err = metrics.Handler(monitoring.WithProfiling(true), monitoring.WithRuntimeMetrics(true))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, serve(t, handler, "/debug/pprof/").Code)
+
+	body := serve(t, handler, "/metrics").Body.String()
+	assert.Contains(t, body, "go_goroutines")
+	assert.Contains(t, body, "go_build_info")
+
+	_, err = metrics.Handler(monitoring.WithRuntimeMetrics(true))
+	require.NoError(t, err)
+}
@@ -0,0 +1,353 @@
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/memory"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/services"
+	"github.com/LarsArtmann/template-sqlc/pkg/fixtures"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// moderationFixture is a user, a moderator and a service moderating in memory.
+type moderationFixture struct {
+	*serviceFixture
+
+	moderation *memory.ModerationRepository
+	ada        *entities.User
+	moderator  *entities.User
+	// ctx acts on behalf of the moderator.
+	ctx context.Context
+}
+
+// as returns a context acting on behalf of user.
+func (f *moderationFixture) as(user *entities.User) context.Context {
+	return services.ContextWithAuditActor(context.Background(), services.AuditActor{UserID: user.ID()})
+}
+
+func newModerationFixture(t *testing.T) *moderationFixture {
+	t.Helper()
+
+	f := &moderationFixture{
+		serviceFixture: newServiceFixture(),
+		moderation:     memory.NewModerationRepository(),
+	}
+
+	f.ada = f.createUser(t, fixtures.User().Named("ada").MustBuild())
+	f.moderator = f.createUser(t, fixtures.User().Named("moderator").WithRole(entities.UserRoleModerator).MustBuild())
+	f.ctx = f.as(f.moderator)
+	f.start(services.WithModeration(f.moderation))
+
+	return f
+}
+
+// status returns the stored status of user.
+func (f *moderationFixture) status(t *testing.T, user *entities.User) entities.UserStatus {
+	t.Helper()
+
+	stored, err := f.users.GetByID(context.Background(), user.ID())
+	require.NoError(t, err)
+
+	return stored.Status()
+}
+
+func TestSuspendAndLift(t *testing.T) {
+	f := newModerationFixture(t)
+
+	session := entities.NewUserSession(f.ada.ID(), nil, "", entities.NewSessionDeviceInfo(), time.Hour)
+	require.NoError(t, f.sessions.Create(f.ctx, session))
+
+	action, err := f.service.SuspendUser(f.ctx, f.ada.ID(), "  spam  ", 24*time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, "spam", action.Reason)
+	assert.Equal(t, f.moderator.ID(), action.ModeratorID)
+	require.NotNil(t, action.EndsAt)
+	assert.Equal(t, entities.UserStatusSuspended, f.status(t, f.ada))
+
+	active, err := f.sessions.GetByUserID(f.ctx, f.ada.ID(), true)
+	require.NoError(t, err)
+	assert.Empty(t, active)
+
+	suspended := lastEventData[events.UserSuspendedEvent](t, f.publisher, events.EventUserSuspended)
+	assert.Equal(t, action.ID, suspended.ActionID)
+	assert.Equal(t, "spam", suspended.Reason)
+
+	_, err = f.service.SuspendUser(f.ctx, f.ada.ID(), "more spam", 0)
+	require.ErrorIs(t, err, entities.ErrAlreadySuspended)
+
+	lifted, err := f.service.LiftSuspension(f.ctx, f.ada.ID())
+	require.NoError(t, err)
+	assert.Equal(t, action.ID, lifted.ID)
+	assert.Equal(t, f.moderator.ID(), lifted.LiftedBy)
+	assert.Equal(t, entities.UserStatusActive, f.status(t, f.ada))
+
+	_, err = f.service.LiftSuspension(f.ctx, f.ada.ID())
+	require.ErrorIs(t, err, entities.ErrNotSuspended)
+
+	reinstated := lastEventData[events.ModerationEvent](t, f.publisher, events.EventUserReinstated)
+	assert.Equal(t, action.ID, reinstated.ActionID)
+
+	require.Len(t, f.audit.entries, 2)
+	assert.Equal(t, entities.AuditActionUserSuspend, f.audit.entries[0].Action)
+	assert.Equal(t, f.moderator.ID(), f.audit.entries[0].ActorID)
+	assert.Contains(t, f.audit.entries[0].Changes, entities.FieldChange{Field: "reason", New: "spam"})
+	assert.Equal(t, entities.AuditActionUserReinstate, f.audit.entries[1].Action)
+}
+
+func TestSuspendUserRejects(t *testing.T) {
+	f := newModerationFixture(t)
+
+	tests := []struct {
+		name     string
+		userID   entities.UserID
+		reason   string
+		duration time.Duration
+		err      error
+	}{
+		{"blank reason", f.ada.ID(), " ", time.Hour, entities.ErrInvalidModerationReason},
+		{"negative duration", f.ada.ID(), "spam", -time.Hour, entities.ErrInvalidSuspensionDuration},
+		{"unknown user", f.ada.ID() + 1000, "spam", time.Hour, entities.ErrUserNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := f.service.SuspendUser(f.ctx, tt.userID, tt.reason, tt.duration)
+			require.ErrorIs(t, err, tt.err)
+		})
+	}
+
+	assert.Equal(t, entities.UserStatusActive, f.status(t, f.ada))
+	assert.Empty(t, eventsOfType(f.publisher, events.EventUserSuspended))
+
+	service := services.NewUserService(f.users, f.sessions, f.publisher, nil)
+	_, err := service.SuspendUser(f.ctx, f.ada.ID(), "spam", 0)
+	require.ErrorIs(t, err, services.ErrModerationUnavailable)
+}
+
+func TestFlagUser(t *testing.T) {
+	f := newModerationFixture(t)
+
+	flag, err := f.service.FlagUser(f.ctx, f.ada.ID(), "suspicious sign-ups")
+	require.NoError(t, err)
+	assert.Equal(t, entities.ModerationFlag, flag.Kind)
+
+	// Flags leave the user alone.
+	assert.Equal(t, entities.UserStatusActive, f.status(t, f.ada))
+
+	flagged := lastEventData[events.ModerationEvent](t, f.publisher, events.EventUserFlagged)
+	assert.Equal(t, "flag", flagged.Kind)
+	assert.Equal(t, f.moderator.ID(), flagged.ModeratorID)
+
+	history, err := f.service.ModerationHistory(f.ctx, f.ada.ID())
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, flag.ID, history[0].ID)
+
+	_, err = f.service.AppealSuspension(f.as(f.ada), f.ada.ID(), "Why?")
+	require.ErrorIs(t, err, entities.ErrNotSuspended)
+}
+
+func TestReactivateLapsedSuspensions(t *testing.T) {
+	f := newModerationFixture(t)
+	ctx := context.Background()
+
+	// A suspension that ended a minute ago.
+	lapsed, err := entities.NewSuspension(f.ada.ID(), f.moderator.ID(), "spam", time.Hour)
+	require.NoError(t, err)
+
+	ended := time.Now().Add(-time.Minute)
+	lapsed.EndsAt = &ended
+	require.NoError(t, f.moderation.CreateAction(ctx, lapsed))
+	require.NoError(t, f.ada.ChangeStatus(entities.UserStatusSuspended))
+	require.NoError(t, f.users.Update(ctx, f.ada))
+
+	bob := f.createUser(t, fixtures.User().Named("bob").MustBuild())
+
+	running, err := f.service.SuspendUser(f.ctx, bob.ID(), "spam", time.Hour)
+	require.NoError(t, err)
+
+	lifted, err := f.service.ReactivateLapsedSuspensions(ctx, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, lifted)
+	assert.Equal(t, entities.UserStatusActive, f.status(t, f.ada))
+	assert.Equal(t, entities.UserStatusSuspended, f.status(t, bob))
+
+	reinstated := lastEventData[events.ModerationEvent](t, f.publisher, events.EventUserReinstated)
+	assert.Equal(t, lapsed.ID, reinstated.ActionID)
+	assert.Zero(t, reinstated.LiftedBy)
+
+	lifted, err = f.service.ReactivateLapsedSuspensions(ctx, 10)
+	require.NoError(t, err)
+	assert.Zero(t, lifted)
+
+	stored, err := f.moderation.GetAction(ctx, running.ID)
+	require.NoError(t, err)
+	assert.True(t, stored.IsActive())
+}
+
+func TestAppeals(t *testing.T) {
+	tests := []struct {
+		name       string
+		grant      bool
+		status     entities.UserStatus
+		reinstated int
+	}{
+		{"granted", true, entities.UserStatusActive, 1},
+		{"denied", false, entities.UserStatusSuspended, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newModerationFixture(t)
+
+			action, err := f.service.SuspendUser(f.ctx, f.ada.ID(), "spam", 0)
+			require.NoError(t, err)
+
+			appeal, err := f.service.AppealSuspension(f.as(f.ada), f.ada.ID(), "It was a misunderstanding.")
+			require.NoError(t, err)
+			assert.Equal(t, action.ID, appeal.ActionID)
+
+			_, err = f.service.AppealSuspension(f.as(f.ada), f.ada.ID(), "Please reconsider.")
+			require.ErrorIs(t, err, entities.ErrAppealExists)
+
+			pending, err := f.service.PendingAppeals(f.ctx, 10)
+			require.NoError(t, err)
+			require.Len(t, pending, 1)
+
+			decided, err := f.service.DecideAppeal(f.ctx, appeal.ID, tt.grant, "Thanks for explaining.")
+			require.NoError(t, err)
+			assert.Equal(t, f.moderator.ID(), decided.ReviewerID)
+			assert.Equal(t, tt.status, f.status(t, f.ada))
+
+			_, err = f.service.DecideAppeal(f.ctx, appeal.ID, tt.grant, "")
+			require.ErrorIs(t, err, entities.ErrAppealNotPending)
+
+			pending, err = f.service.PendingAppeals(f.ctx, 10)
+			require.NoError(t, err)
+			assert.Empty(t, pending)
+
+			assert.Len(t, eventsOfType(f.publisher, events.EventAppealDecided), 1)
+			assert.Len(t, eventsOfType(f.publisher, events.EventUserReinstated), tt.reinstated)
+		})
+	}
+}
+
+func TestModerationRequiresModerator(t *testing.T) {
+	f := newModerationFixture(t)
+
+	admin := fixtures.User().Named("admin").WithRole(entities.UserRoleAdmin).MustBuild()
+	require.NoError(t, f.users.Create(context.Background(), admin))
+
+	_, err := f.service.FlagUser(f.as(admin), f.ada.ID(), "spam")
+	require.NoError(t, err)
+
+	action, err := f.service.SuspendUser(f.ctx, f.ada.ID(), "spam", 0)
+	require.NoError(t, err)
+
+	appeal, err := f.service.AppealSuspension(f.as(f.ada), f.ada.ID(), "It was a misunderstanding.")
+	require.NoError(t, err)
+
+	bob := fixtures.User().Named("bob").MustBuild()
+	require.NoError(t, f.users.Create(context.Background(), bob))
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+	}{
+		{"no actor", context.Background()},
+		{"user", f.as(bob)},
+		{"suspended user", f.as(f.ada)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := f.service.FlagUser(tt.ctx, bob.ID(), "spam")
+			require.ErrorIs(t, err, entities.ErrInsufficientPrivileges)
+
+			_, err = f.service.SuspendUser(tt.ctx, bob.ID(), "spam", 0)
+			require.ErrorIs(t, err, entities.ErrInsufficientPrivileges)
+
+			_, err = f.service.LiftSuspension(tt.ctx, f.ada.ID())
+			require.ErrorIs(t, err, entities.ErrInsufficientPrivileges)
+
+			_, err = f.service.DecideAppeal(tt.ctx, appeal.ID, true, "")
+			require.ErrorIs(t, err, entities.ErrInsufficientPrivileges)
+		})
+	}
+
+	assert.Equal(t, entities.UserStatusActive, f.status(t, bob))
+	assert.Equal(t, entities.UserStatusSuspended, f.status(t, f.ada))
+
+	stored, err := f.moderation.GetAction(context.Background(), action.ID)
+	require.NoError(t, err)
+	assert.True(t, stored.IsActive())
+
+	history, err := f.service.ModerationHistory(f.ctx, bob.ID())
+	require.NoError(t, err)
+	assert.Empty(t, history)
+}
+
+func TestModerationRequiresOutrankingTheUser(t *testing.T) {
+	f := newModerationFixture(t)
+
+	peer := f.createUser(t, fixtures.User().Named("peer").WithRole(entities.UserRoleModerator).MustBuild())
+	admin := f.createUser(t, fixtures.User().Named("admin").WithRole(entities.UserRoleAdmin).MustBuild())
+	other := f.createUser(t, fixtures.User().Named("other").WithRole(entities.UserRoleAdmin).MustBuild())
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		user *entities.User
+	}{
+		{"moderator on themselves", f.ctx, f.moderator},
+		{"moderator on a moderator", f.ctx, peer},
+		{"moderator on an admin", f.ctx, admin},
+		{"admin on themselves", f.as(admin), admin},
+		{"admin on an admin", f.as(admin), other},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := f.service.FlagUser(tt.ctx, tt.user.ID(), "spam")
+			require.ErrorIs(t, err, entities.ErrModerationForbidden)
+
+			_, err = f.service.SuspendUser(tt.ctx, tt.user.ID(), "spam", 0)
+			require.ErrorIs(t, err, entities.ErrModerationForbidden)
+
+			assert.Equal(t, entities.UserStatusActive, f.status(t, tt.user))
+		})
+	}
+
+	assert.Empty(t, eventsOfType(f.publisher, events.EventUserFlagged))
+	assert.Empty(t, eventsOfType(f.publisher, events.EventUserSuspended))
+
+	_, err := f.service.SuspendUser(f.as(admin), peer.ID(), "spam", 0)
+	require.NoError(t, err)
+	assert.Equal(t, entities.UserStatusSuspended, f.status(t, peer))
+}
+
+func TestAppealRequiresSuspendedUser(t *testing.T) {
+	f := newModerationFixture(t)
+
+	_, err := f.service.SuspendUser(f.ctx, f.ada.ID(), "spam", 0)
+	require.NoError(t, err)
+
+	for name, ctx := range map[string]context.Context{
+		"no actor":  context.Background(),
+		"moderator": f.ctx,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := f.service.AppealSuspension(ctx, f.ada.ID(), "Not me.")
+			require.ErrorIs(t, err, entities.ErrInsufficientPrivileges)
+		})
+	}
+
+	pending, err := f.service.PendingAppeals(f.ctx, 10)
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+}
@@ -0,0 +1,38 @@
+package unit
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+
+	mysqldb "github.com/LarsArtmann/template-sqlc/internal/db/mysql"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	driver "github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMySQLHandleDBError(t *testing.T) {
+	handle := func(err error) error {
+		return mysqldb.HandleDBError(
+			err,
+			"create user",
+			entities.ErrUserNotFound,
+			entities.ErrUserAlreadyExists,
+			entities.ErrInvalidReference,
+		)
+	}
+
+	duplicate := &driver.MySQLError{Number: 1062, Message: "Duplicate entry 'a@b.c' for key 'email'"}
+	foreignKey := &driver.MySQLError{Number: 1452, Message: "Cannot add or update a child row"}
+	other := &driver.MySQLError{Number: 1205, Message: "Lock wait timeout exceeded"}
+
+	assert.NoError(t, handle(nil))
+	assert.ErrorIs(t, handle(sql.ErrNoRows), entities.ErrUserNotFound)
+	assert.ErrorIs(t, handle(fmt.Errorf("insert: %w", duplicate)), entities.ErrUserAlreadyExists)
+	assert.ErrorIs(t, handle(foreignKey), entities.ErrInvalidReference)
+
+	err := handle(other)
+	assert.ErrorIs(t, err, other)
+	assert.False(t, errors.Is(err, entities.ErrUserAlreadyExists))
+}
@@ -0,0 +1,257 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/email"
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/memory"
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/webhook"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/services"
+	"github.com/LarsArtmann/template-sqlc/pkg/fixtures"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errChannelDown = errors.New("channel down")
+
+// notificationRecorder records notifications and fails with err.
+type notificationRecorder struct {
+	mu   sync.Mutex
+	got  []services.Notification
+	fail error
+}
+
+func (r *notificationRecorder) Notify(_ context.Context, notification services.Notification) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.got = append(r.got, notification)
+
+	return r.fail
+}
+
+func TestNewNotificationPreference(t *testing.T) {
+	userID := entities.UserID(1)
+
+	tests := []struct {
+		name    string
+		channel entities.NotificationChannel
+		topic   entities.NotificationTopic
+		enabled bool
+		target  string
+		err     error
+	}{
+		{"email", entities.NotificationChannelEmail, entities.NotificationTopicSecurity, false, "", nil},
+		{"webhook", entities.NotificationChannelWebhook, entities.NotificationTopicAccount, true,
+			"https://hooks.example.com/x", nil},
+		{"disabled webhook without target", entities.NotificationChannelWebhook, entities.NotificationTopicAccount,
+			false, "", nil},
+		{"unknown channel", "sms", entities.NotificationTopicSecurity, true, "", entities.ErrInvalidNotificationChannel},
+		{"unknown topic", entities.NotificationChannelEmail, "billing", true, "", entities.ErrInvalidNotificationTopic},
+		{"webhook without target", entities.NotificationChannelWebhook, entities.NotificationTopicSecurity, true, "",
+			entities.ErrInvalidNotificationTarget},
+		{"webhook with relative target", entities.NotificationChannelWebhook, entities.NotificationTopicSecurity,
+			true, "/hooks", entities.ErrInvalidNotificationTarget},
+		{"email with target", entities.NotificationChannelEmail, entities.NotificationTopicSecurity, true,
+			"https://hooks.example.com/x", entities.ErrInvalidNotificationTarget},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pref, err := entities.NewNotificationPreference(userID, tt.channel, tt.topic, tt.enabled, tt.target)
+			if tt.err != nil {
+				require.ErrorIs(t, err, tt.err)
+				assert.True(t, entities.IsValidationError(err))
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.enabled, pref.Enabled)
+			assert.Equal(t, tt.target, pref.Target)
+		})
+	}
+}
+
+func TestNotificationPreferencesDefaults(t *testing.T) {
+	userID := entities.UserID(1)
+	optOut, err := entities.NewNotificationPreference(userID, entities.NotificationChannelEmail,
+		entities.NotificationTopicOrganization, false, "")
+	require.NoError(t, err)
+
+	prefs := entities.NewNotificationPreferences(userID, []*entities.NotificationPreference{optOut})
+
+	assert.True(t, prefs.Allows(entities.NotificationChannelEmail, entities.NotificationTopicSecurity))
+	assert.True(t, prefs.Allows(entities.NotificationChannelInApp, entities.NotificationTopicOrganization))
+	assert.False(t, prefs.Allows(entities.NotificationChannelWebhook, entities.NotificationTopicSecurity))
+	assert.False(t, prefs.Allows(entities.NotificationChannelEmail, entities.NotificationTopicOrganization))
+	assert.Len(t, prefs.All(), 9)
+}
+
+func TestNotificationServiceFansOutByPreference(t *testing.T) {
+	ctx := context.Background()
+	users := memory.NewUserRepository()
+	ada := fixtures.User().Named("ada").MustBuild()
+	require.NoError(t, users.Create(ctx, ada))
+
+	emails, webhooks, inbox := &notificationRecorder{}, &notificationRecorder{}, &notificationRecorder{}
+	service := services.NewNotificationService(memory.NewNotificationPreferenceRepository(), users,
+		services.WithNotificationChannel(entities.NotificationChannelEmail, emails),
+		services.WithNotificationChannel(entities.NotificationChannelWebhook, webhooks),
+		services.WithNotificationChannel(entities.NotificationChannelInApp, inbox),
+	)
+
+	_, err := service.SetPreference(ctx, ada.ID(), entities.NotificationChannelWebhook,
+		entities.NotificationTopicSecurity, true, "https://hooks.example.com/ada")
+	require.NoError(t, err)
+	_, err = service.SetPreference(ctx, ada.ID(), entities.NotificationChannelEmail,
+		entities.NotificationTopicOrganization, false, "")
+	require.NoError(t, err)
+
+	assert.Contains(t, service.EventTypes(), events.EventPasswordChanged)
+
+	// Redacted events arrive as JSON without personal data.
+	redacted, err := events.RedactionPolicy{Mode: events.RedactionHash}.RedactEvent(
+		events.UserLoginFailed(ada.ID(), "192.0.2.1", "test-agent", "web"))
+	require.NoError(t, err)
+
+	for _, event := range []*events.UserEvent{
+		redacted,
+		events.MembershipChanged(events.EventMemberInvited, ada.ID(), 7, entities.UserRoleUser, 2),
+		// Logins are not notified and the user is gone.
+		events.UserLoggedIn(ada.ID(), "192.0.2.1", "test-agent", "web"),
+		events.UserLoginFailed(entities.UserID(999), "192.0.2.1", "test-agent", "web"),
+	} {
+		require.NoError(t, service.Handle(ctx, event), event.Type)
+	}
+
+	require.Len(t, emails.got, 1)
+	assert.Equal(t, entities.NotificationTopicSecurity, emails.got[0].Topic)
+	assert.Equal(t, redacted.ID, emails.got[0].ID)
+	assert.Equal(t, ada.ID(), emails.got[0].User.ID())
+
+	require.Len(t, webhooks.got, 1)
+	assert.Equal(t, "https://hooks.example.com/ada", webhooks.got[0].Target)
+
+	require.Len(t, inbox.got, 2)
+	assert.Equal(t, entities.NotificationTopicOrganization, inbox.got[1].Topic)
+
+	// A failing channel does not keep the others from being notified.
+	webhooks.fail = errChannelDown
+	err = service.Handle(ctx, events.UserLoginFailed(ada.ID(), "192.0.2.1", "test-agent", "web"))
+	require.ErrorIs(t, err, errChannelDown)
+	assert.Len(t, emails.got, 2)
+	assert.Len(t, inbox.got, 3)
+
+	require.NoError(t, service.ResetPreference(ctx, ada.ID(), entities.NotificationChannelWebhook,
+		entities.NotificationTopicSecurity))
+
+	prefs, err := service.Preferences(ctx, ada.ID())
+	require.NoError(t, err)
+	assert.False(t, prefs.Allows(entities.NotificationChannelWebhook, entities.NotificationTopicSecurity))
+}
+
+func TestWebhookSenderSignsPayload(t *testing.T) {
+	secret := []byte("webhook-secret")
+	received := make(chan *http.Request, 1)
+	bodies := make(chan []byte, 1)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- r
+		bodies <- body
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+
+	ada := fixtures.User().Named("ada").MustBuild()
+	event := events.UserLoginFailed(entities.UserID(1), "192.0.2.1", "test-agent", "web")
+	notification := services.Notification{
+		ID:         event.ID,
+		Type:       event.Type,
+		Topic:      entities.NotificationTopicSecurity,
+		User:       ada,
+		Target:     server.URL,
+		Summary:    "A sign-in to your account failed.",
+		OccurredAt: event.Timestamp,
+	}
+
+	sender := webhook.NewSender(webhook.WithHTTPClient(server.Client()), webhook.WithSigningSecret(secret))
+	require.NoError(t, sender.Notify(context.Background(), notification))
+
+	req, body := <-received, <-bodies
+	assert.Equal(t, http.MethodPost, req.Method)
+	assert.Equal(t, webhook.Sign(secret, body), req.Header.Get(webhook.SignatureHeader))
+
+	var payload webhook.Payload
+	require.NoError(t, json.Unmarshal(body, &payload))
+	assert.Equal(t, event.ID, payload.ID)
+	assert.Equal(t, events.EventUserLoginFail, payload.Type)
+	assert.Equal(t, notification.Summary, payload.Summary)
+	assert.NotContains(t, string(body), "ada@example.com")
+
+	notification.Target = ""
+	require.ErrorIs(t, sender.Notify(context.Background(), notification), webhook.ErrNoTarget)
+}
+
+func TestWebhookSenderRejectsErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusGone)
+	}))
+	t.Cleanup(server.Close)
+
+	err := webhook.NewSender().Notify(context.Background(), services.Notification{
+		User:   fixtures.User().Named("ada").MustBuild(),
+		Target: server.URL,
+	})
+	require.ErrorIs(t, err, webhook.ErrDeliveryFailed)
+}
+
+func TestNotificationInbox(t *testing.T) {
+	ctx := context.Background()
+	ada := fixtures.User().Named("ada").MustBuild()
+	inbox := memory.NewNotificationInbox(2)
+
+	var ids []entities.IDID
+
+	for range 3 {
+		event := events.UserVerified(ada.ID(), "email")
+		ids = append(ids, event.ID)
+		require.NoError(t, inbox.Notify(ctx, services.Notification{ID: event.ID, User: ada}))
+	}
+
+	// Redeliveries are ignored.
+	require.NoError(t, inbox.Notify(ctx, services.Notification{ID: ids[2], User: ada}))
+
+	got := inbox.List(ada.ID())
+	require.Len(t, got, 2)
+	assert.Equal(t, ids[2], got[0].ID)
+	assert.Equal(t, ids[1], got[1].ID)
+	assert.Empty(t, inbox.List(entities.UserID(999)))
+}
+
+func TestNotifierEmailsNotifications(t *testing.T) {
+	ada := fixtures.User().Named("ada").MustBuild()
+	sent := &outbox{}
+	notifier := email.NewNotifier(sent, memory.NewUserRepository(), testSender, email.Links{})
+
+	require.NoError(t, notifier.Notify(context.Background(), services.Notification{
+		User:    ada,
+		Summary: "Your password was changed.",
+	}))
+
+	require.Len(t, sent.sent, 1)
+	assert.Equal(t, "Your password was changed.", sent.sent[0].Subject)
+	assert.Equal(t, []string{"ada@example.com"}, sent.sent[0].To)
+	assert.Contains(t, sent.sent[0].HTML, "<p>Your password was changed.</p>")
+}
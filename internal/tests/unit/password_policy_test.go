@@ -0,0 +1,174 @@
+package unit
+
+import (
+	"context"
+	"crypto/sha1" //nolint:gosec // the range API is keyed by SHA-1
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/pwned"
+	"github.com/LarsArtmann/template-sqlc/internal/validation"
+	"github.com/LarsArtmann/template-sqlc/pkg/config"
+	apperrors "github.com/LarsArtmann/template-sqlc/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errBreachAPIDown = errors.New("breach api down")
+
+// breachedRange is a BreachRangeAPI of a fixed set of breached passwords.
+type breachedRange struct {
+	breached map[string]int
+	prefixes []string
+	err      error
+}
+
+func (r *breachedRange) Range(_ context.Context, prefix string) (map[string]int, error) {
+	r.prefixes = append(r.prefixes, prefix)
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	counts := make(map[string]int)
+
+	for password, count := range r.breached {
+		hash := sha1Hex(password)
+		if strings.HasPrefix(hash, prefix) {
+			counts[hash[len(prefix):]] = count
+		}
+	}
+
+	return counts, nil
+}
+
+func sha1Hex(password string) string {
+	sum := sha1.Sum([]byte(password)) //nolint:gosec // see the import
+
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
+
+func TestDefaultPasswordPolicy(t *testing.T) {
+	ctx := context.Background()
+	validator := validation.NewUserValidator()
+
+	tests := []struct {
+		password string
+		valid    bool
+	}{
+		{"Sh0rt!", false},
+		{"alllowercase", false},
+		{"Sunflower42", true},
+		{"PASSWORD123", false},
+		{"Correct-Horse-Battery-Staple", true},
+		{strings.Repeat("Ab1", 50), false},
+	}
+
+	for _, tt := range tests {
+		err := validator.ValidatePasswordRequirements(ctx, tt.password)
+		if tt.valid {
+			require.NoError(t, err, tt.password)
+		} else {
+			require.True(t, apperrors.IsValidationError(err), "%s: %v", tt.password, err)
+		}
+	}
+
+	policy := validation.DefaultPasswordPolicy()
+	policy.MinCharacterClasses = 0
+	require.True(t, apperrors.IsValidationError(policy.Check(ctx, "Password123")), "common in any case")
+}
+
+func TestPasswordPolicyStrength(t *testing.T) {
+	ctx := context.Background()
+	policy := validation.PasswordPolicy{MinStrength: validation.StrengthVeryUnguessable}
+
+	for _, password := range []string{"P@ssw0rd1", "qwerty123", "Summer2024!", "abcdefgh", "zzzzzzzzzz", "drowssap"} {
+		require.True(t, apperrors.IsValidationError(policy.Check(ctx, password)), password)
+	}
+
+	require.NoError(t, policy.Check(ctx, "Xk9#mQ2$vL7p"))
+	require.NoError(t, policy.Check(ctx, "correct horse battery staple"))
+
+	assert.Less(t, validation.EstimateStrength("P4ssw0rd").Guesses, validation.EstimateStrength("Pqsswxrd").Guesses)
+	assert.Less(t, validation.EstimateStrength("password").Score, validation.StrengthVeryGuessable)
+	assert.Equal(t, validation.StrengthVeryUnguessable, validation.EstimateStrength("vY8!rT3#pQ").Score)
+}
+
+func TestPasswordPolicyBreaches(t *testing.T) {
+	ctx := context.Background()
+	breaches := &breachedRange{breached: map[string]int{"Breached-Pa55": 42}}
+	policy := validation.PasswordPolicy{Breaches: breaches}
+
+	require.True(t, apperrors.IsValidationError(policy.Check(ctx, "Breached-Pa55")))
+	require.NoError(t, policy.Check(ctx, "Unbreached-Pa55"))
+	assert.Equal(t, []string{sha1Hex("Breached-Pa55")[:5], sha1Hex("Unbreached-Pa55")[:5]}, breaches.prefixes,
+		"only the first five hash characters are sent")
+
+	breaches.err = errBreachAPIDown
+	require.ErrorIs(t, policy.Check(ctx, "Unbreached-Pa55"), errBreachAPIDown)
+
+	policy.BreachFailOpen = true
+	require.NoError(t, policy.Check(ctx, "Unbreached-Pa55"))
+
+	policy = validation.PasswordPolicy{MinLength: 12, Breaches: breaches}
+	prefixes := len(breaches.prefixes)
+	require.Error(t, policy.Check(ctx, "short"))
+	assert.Len(t, breaches.prefixes, prefixes, "weak passwords are rejected without a request")
+}
+
+func TestPasswordPolicyValidate(t *testing.T) {
+	require.NoError(t, validation.DefaultPasswordPolicy().Validate())
+	require.NoError(t, validation.PasswordPolicy{}.Validate())
+
+	for _, policy := range []validation.PasswordPolicy{
+		{MinLength: 20, MaxLength: 10},
+		{MinLength: -1},
+		{MinCharacterClasses: 5},
+		{MinStrength: 5},
+	} {
+		require.Error(t, policy.Validate(), "%+v", policy)
+	}
+
+	cfg, err := config.Load([]string{"-password-min-strength", "3", "-password-breach-check"}, noEnv)
+	require.NoError(t, err)
+	assert.Equal(t, validation.StrengthSafelyUnguessable, cfg.Passwords.Policy().MinStrength)
+	assert.True(t, cfg.Passwords.BreachCheck)
+	assert.Equal(t, validation.DefaultMinPasswordLength, cfg.Passwords.Policy().MinLength)
+
+	_, err = config.Load([]string{"-password-min-strength", "5"}, noEnv)
+	require.True(t, apperrors.IsValidationError(err), "%v", err)
+}
+
+func TestPwnedClientRange(t *testing.T) {
+	hash := sha1Hex("Breached-Pa55")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/range/"+hash[:5] {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		assert.Equal(t, "true", r.Header.Get("Add-Padding"))
+		assert.NotEmpty(t, r.Header.Get("User-Agent"))
+
+		fmt.Fprintf(w, "%s:42\r\n%s:0\r\n", strings.ToLower(hash[5:]), strings.Repeat("0", 35))
+	}))
+	defer server.Close()
+
+	client := pwned.NewClient(pwned.WithBaseURL(server.URL + "/"))
+
+	counts, err := client.Range(context.Background(), hash[:5])
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{hash[5:]: 42}, counts, "padding is left out")
+
+	policy := validation.PasswordPolicy{Breaches: client}
+	require.True(t, apperrors.IsValidationError(policy.Check(context.Background(), "Breached-Pa55")))
+
+	_, err = client.Range(context.Background(), "FFFFF")
+	require.ErrorIs(t, err, pwned.ErrUnexpectedResponse)
+}
@@ -0,0 +1,58 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/LarsArtmann/template-sqlc/pkg/passwords"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// fastArgon2idParams keeps argon2id cheap enough for unit tests.
+func fastArgon2idParams() passwords.Argon2idParams {
+	return passwords.Argon2idParams{
+		Memory:      1024,
+		Iterations:  1,
+		Parallelism: 1,
+		SaltLength:  16,
+		KeyLength:   32,
+	}
+}
+
+func TestPasswordHashers(t *testing.T) {
+	hashers := map[string]passwords.PasswordHasher{
+		"bcrypt":   passwords.NewBcryptHasher(bcrypt.MinCost),
+		"argon2id": passwords.NewArgon2idHasher(fastArgon2idParams()),
+	}
+
+	for name, hasher := range hashers {
+		t.Run(name, func(t *testing.T) {
+			hash, err := hasher.Hash("s3cret-Password")
+			require.NoError(t, err)
+			assert.NotContains(t, hash, "s3cret-Password")
+
+			require.NoError(t, hasher.Verify(hash, "s3cret-Password"))
+			require.ErrorIs(t, hasher.Verify(hash, "wrong"), passwords.ErrMismatchedPassword)
+
+			_, err = hasher.Hash("")
+			require.ErrorIs(t, err, passwords.ErrEmptyPassword)
+		})
+	}
+}
+
+func TestMultiHasherVerifiesLegacyHashes(t *testing.T) {
+	legacy := passwords.NewBcryptHasher(bcrypt.MinCost)
+	hasher := passwords.NewMultiHasher(passwords.NewArgon2idHasher(fastArgon2idParams()), legacy)
+
+	oldHash, err := legacy.Hash("s3cret-Password")
+	require.NoError(t, err)
+	require.NoError(t, hasher.Verify(oldHash, "s3cret-Password"))
+
+	newHash, err := hasher.Hash("s3cret-Password")
+	require.NoError(t, err)
+	assert.Contains(t, newHash, "$argon2id$")
+	require.NoError(t, hasher.Verify(newHash, "s3cret-Password"))
+
+	require.ErrorIs(t, hasher.Verify("plain-text", "plain-text"), passwords.ErrUnsupportedHash)
+}
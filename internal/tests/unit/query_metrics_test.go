@@ -0,0 +1,31 @@
+package unit
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/instrumented"
+	"github.com/LarsArtmann/template-sqlc/internal/monitoring"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ instrumented.QueryObserver = (*monitoring.Metrics)(nil)
+
+func TestQueryMetricsLabelsAndCardinalityGuard(t *testing.T) {
+	metrics := monitoring.NewMetrics(monitoring.WithQueryNameLimit(2))
+
+	metrics.ObserveQuery("UserRepository.GetByID", "sqlite", time.Millisecond, nil)
+	metrics.ObserveQuery("UserRepository.GetByID", "sqlite", time.Millisecond, errors.New("boom"))
+	metrics.ObserveQuery("UserRepository.Update", "postgres", time.Millisecond, nil)
+	metrics.ObserveQuery("UserRepository.Delete", "postgres", time.Millisecond, nil)
+	metrics.ObserveQuery("SELECT * FROM users", "postgres", time.Millisecond, nil)
+
+	total := metrics.QueryTotal
+	assert.InDelta(t, 1, testutil.ToFloat64(total.WithLabelValues("UserRepository.GetByID", "sqlite", "success")), 0)
+	assert.InDelta(t, 1, testutil.ToFloat64(total.WithLabelValues("UserRepository.GetByID", "sqlite", "error")), 0)
+	assert.InDelta(t, 1, testutil.ToFloat64(total.WithLabelValues("UserRepository.Update", "postgres", "success")), 0)
+	assert.InDelta(t, 2, testutil.ToFloat64(total.WithLabelValues(monitoring.OverflowLabel, "postgres", "success")), 0)
+	assert.InDelta(t, 1, testutil.ToFloat64(metrics.QueryErrors.WithLabelValues("UserRepository.GetByID", "sqlite")), 0)
+}
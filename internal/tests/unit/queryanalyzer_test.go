@@ -0,0 +1,56 @@
+package unit
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/LarsArtmann/template-sqlc/pkg/db"
+	"github.com/LarsArtmann/template-sqlc/pkg/queryanalyzer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryAnalyzerSQLite(t *testing.T) {
+	database, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = database.Close() })
+
+	database.SetMaxOpenConns(1)
+
+	_, err = database.Exec(`
+CREATE TABLE users (id INTEGER PRIMARY KEY, email TEXT UNIQUE NOT NULL, status TEXT NOT NULL, created_at TEXT NOT NULL);
+`)
+	require.NoError(t, err)
+
+	analyzer, err := queryanalyzer.New(database, db.DriverSQLite,
+		queryanalyzer.WithParams("GetUser", int64(1)),
+		queryanalyzer.WithParams("Broken", int64(1), int64(2)),
+	)
+	require.NoError(t, err)
+
+	report, err := analyzer.Analyze(context.Background(), []queryanalyzer.Query{
+		{Name: "GetUser", SQL: "SELECT id FROM users WHERE id = sqlc.arg(id);"},
+		{Name: "ListByStatus", SQL: `-- Newest first.
+SELECT u.id FROM users u
+WHERE u.status = sqlc.arg(status) AND u.created_at > sqlc.arg(since)
+ORDER BY u.created_at DESC
+LIMIT sqlc.arg(page_size);`},
+		{Name: "Broken", SQL: "SELECT id FROM users WHERE id = sqlc.arg(id);"},
+	})
+	require.NoError(t, err)
+	require.Len(t, report.Results, 3)
+
+	get := report.Results[0]
+	assert.Empty(t, get.Error)
+	assert.Empty(t, get.SeqScans)
+	assert.Empty(t, get.Suggestions)
+
+	list := report.Results[1]
+	assert.Empty(t, list.Error)
+	assert.Equal(t, []string{"users"}, list.SeqScans)
+	assert.Equal(t, []string{"CREATE INDEX idx_users_status_created_at ON users (status, created_at);"}, list.Suggestions)
+	assert.NotEmpty(t, list.Notes, "the ORDER BY needs a temporary b-tree")
+
+	assert.Contains(t, report.Results[2].Error, queryanalyzer.ErrSampleCount.Error())
+}
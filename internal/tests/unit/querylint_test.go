@@ -0,0 +1,67 @@
+package unit
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/LarsArtmann/template-sqlc/internal/querylint"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryLint(t *testing.T) {
+	root := t.TempDir()
+
+	writeFile(t, filepath.Join(root, "schema", "001_users.sql"), `
+CREATE TABLE users (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    email VARCHAR(255) UNIQUE NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    org_id BIGINT REFERENCES orgs(id),
+    nickname VARCHAR(255)
+);
+CREATE INDEX idx_users_status ON users(status);
+`)
+	writeFile(t, filepath.Join(root, "queries", "user.sql"), `
+-- name: GetUser :one
+SELECT id, email FROM users WHERE id = ?;
+
+-- name: ListUsers :many
+SELECT * FROM users WHERE status = ?;
+
+-- name: FindByNickname :many
+SELECT id FROM users WHERE nickname = ? LIMIT 10;
+
+-- name: ListByOrg :many
+SELECT id FROM users WHERE org_id = ? LIMIT 10;
+
+-- name: PurgeUsers :exec
+DELETE FROM users;
+
+-- name: SearchUsers :many
+-- lint:ignore missing-limit
+SELECT id FROM users WHERE email ILIKE '%' || ? || '%';
+`)
+
+	report, err := querylint.LintDir(querylint.EngineMySQL, filepath.Join(root, "queries"), filepath.Join(root, "schema"))
+	require.NoError(t, err)
+
+	type hit struct{ rule, query string }
+
+	hits := make([]hit, 0, len(report.Findings))
+	for _, finding := range report.Findings {
+		hits = append(hits, hit{finding.Rule, finding.Query})
+	}
+
+	assert.Equal(t, []hit{
+		{querylint.RuleSelectStar, "ListUsers"},
+		{querylint.RuleMissingLimit, "ListUsers"},
+		{querylint.RuleUnindexedPredicate, "FindByNickname"},
+		{querylint.RuleUnboundedWrite, "PurgeUsers"},
+		{querylint.RuleUnsupportedSyntax, "SearchUsers"},
+		{querylint.RuleLeadingWildcard, "SearchUsers"},
+	}, hits, "MySQL indexes the org_id foreign key implicitly")
+	assert.Equal(t, 2, report.Count(querylint.SeverityError))
+	assert.Equal(t, "user.sql:14: error [unbounded-write] PurgeUsers: DELETE without WHERE touches every row of users",
+		report.Findings[3].String())
+}
@@ -0,0 +1,228 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	grpcadapter "github.com/LarsArtmann/template-sqlc/internal/adapters/grpc"
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/memory"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/services"
+	"github.com/LarsArtmann/template-sqlc/pkg/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeClock is a settable clock.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+// failingLimiter fails every request.
+type failingLimiter struct{}
+
+func (failingLimiter) Allow(context.Context, string, ratelimit.Limit) (ratelimit.Result, error) {
+	return ratelimit.Result{}, errors.New("redis down")
+}
+
+// decisionRecorder counts decisions by scope.
+type decisionRecorder struct {
+	allowed map[string]int
+	limited map[string]int
+}
+
+func (r *decisionRecorder) ObserveRateLimit(scope string, allowed bool) {
+	if allowed {
+		r.allowed[scope]++
+	} else {
+		r.limited[scope]++
+	}
+}
+
+func TestMemoryLimiterTokenBucket(t *testing.T) {
+	ctx := context.Background()
+	clock := &fakeClock{now: time.Unix(1_700_000_000, 0)}
+	limiter := ratelimit.NewMemory(ratelimit.WithClock(clock.Now))
+	limit := ratelimit.Limit{Burst: 2, Every: time.Second}
+
+	for want := 1; want >= 0; want-- {
+		result, err := limiter.Allow(ctx, "client", limit)
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, want, result.Remaining)
+	}
+
+	result, err := limiter.Allow(ctx, "client", limit)
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, time.Second, result.RetryAfter)
+
+	result, err = limiter.Allow(ctx, "other", limit)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed, "keys have their own buckets")
+
+	clock.Advance(600 * time.Millisecond)
+
+	result, err = limiter.Allow(ctx, "client", limit)
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 400*time.Millisecond, result.RetryAfter)
+
+	clock.Advance(400 * time.Millisecond)
+
+	result, err = limiter.Allow(ctx, "client", limit)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+
+	// Refilled buckets are forgotten.
+	clock.Advance(time.Hour)
+
+	_, err = limiter.Allow(ctx, "new", limit)
+	require.NoError(t, err)
+	assert.Equal(t, 1, limiter.Len())
+
+	_, err = limiter.Allow(ctx, "client", ratelimit.Limit{Burst: 1})
+	require.ErrorIs(t, err, ratelimit.ErrInvalidLimit)
+}
+
+func TestRuleWithoutLimitAllows(t *testing.T) {
+	result, err := ratelimit.Rule{Limiter: failingLimiter{}}.Allow(context.Background(), "client")
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	recorder := &decisionRecorder{allowed: map[string]int{}, limited: map[string]int{}}
+	rule := ratelimit.Rule{
+		Limiter: ratelimit.Instrument(ratelimit.NewMemory(), recorder, "http"),
+		Limit:   ratelimit.Limit{Burst: 1, Every: time.Minute},
+	}
+	handler := ratelimit.Middleware(rule, ratelimit.ClientIP)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	serve := func(remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/graphql", nil)
+		req.RemoteAddr = remoteAddr
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		return rec
+	}
+
+	assert.Equal(t, http.StatusNoContent, serve("192.0.2.1:1234").Code)
+
+	limited := serve("192.0.2.1:5678")
+	assert.Equal(t, http.StatusTooManyRequests, limited.Code)
+	assert.Equal(t, "60", limited.Header().Get("Retry-After"))
+
+	assert.Equal(t, http.StatusNoContent, serve("192.0.2.2:1234").Code)
+	assert.Equal(t, map[string]int{"http": 2}, recorder.allowed)
+	assert.Equal(t, map[string]int{"http": 1}, recorder.limited)
+
+	failing := ratelimit.Middleware(ratelimit.Rule{Limiter: failingLimiter{}, Limit: rule.Limit}, ratelimit.ClientIP)
+	rec := httptest.NewRecorder()
+	failing(http.NotFoundHandler()).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code, "requests pass when the limiter fails")
+}
+
+func TestRateLimitInterceptor(t *testing.T) {
+	rule := ratelimit.Rule{Limiter: ratelimit.NewMemory(), Limit: ratelimit.Limit{Burst: 1, Every: time.Minute}}
+	interceptor := ratelimit.UnaryServerInterceptor(rule, func(context.Context, string) string { return "client" })
+	info := &grpclib.UnaryServerInfo{FullMethod: "/users.v1.UserService/ListUsers"}
+	handler := func(context.Context, any) (any, error) { return "ok", nil }
+
+	resp, err := interceptor(context.Background(), nil, info, handler)
+	require.NoError(t, err)
+	assert.Equal(t, "ok", resp)
+
+	_, err = interceptor(context.Background(), nil, info, handler)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
+
+func TestLoginThrottle(t *testing.T) {
+	ctx := context.Background()
+	limiter := ratelimit.NewMemory()
+	throttle := ratelimit.NewLoginThrottle(
+		ratelimit.Rule{Limiter: limiter, Limit: ratelimit.Limit{Burst: 3, Every: time.Minute}},
+		ratelimit.Rule{Limiter: limiter, Limit: ratelimit.Limit{Burst: 2, Every: time.Minute}},
+	)
+
+	for range 2 {
+		wait, err := throttle.AllowLogin(ctx, "192.0.2.1", "Ada@Example.com")
+		require.NoError(t, err)
+		assert.Zero(t, wait)
+	}
+
+	wait, err := throttle.AllowLogin(ctx, "192.0.2.2", "ada@example.com")
+	require.NoError(t, err)
+	assert.Positive(t, wait, "accounts are limited across addresses")
+
+	wait, err = throttle.AllowLogin(ctx, "192.0.2.1", "grace@example.com")
+	require.NoError(t, err)
+	assert.Zero(t, wait)
+
+	wait, err = throttle.AllowLogin(ctx, "192.0.2.1", "alan@example.com")
+	require.NoError(t, err)
+	assert.Positive(t, wait, "addresses are limited across accounts")
+
+	for _, account := range []string{
+		"edsger@example.com", "barbara@example.com", "donald@example.com", "john@example.com",
+	} {
+		wait, err = throttle.AllowLogin(ctx, "", account)
+		require.NoError(t, err)
+	}
+
+	assert.Positive(t, wait, "attempts without an address share a bucket")
+}
+
+// fixedThrottle makes every login wait.
+type fixedThrottle time.Duration
+
+func (f fixedThrottle) AllowLogin(context.Context, string, string) (time.Duration, error) {
+	return time.Duration(f), nil
+}
+
+func TestAuthenticateUserThrottled(t *testing.T) {
+	publisher := events.NewInMemoryEventPublisher()
+	service := services.NewUserService(memory.NewUserRepository(), memory.NewSessionRepository(), publisher, nil,
+		services.WithPasswordHasher(testHasher),
+		services.WithLoginThrottle(fixedThrottle(time.Minute)),
+	)
+
+	_, err := service.AuthenticateUser(context.Background(), "ada@example.com", "secret", "192.0.2.1", "test-agent")
+	require.True(t, entities.IsRateLimitError(err))
+
+	var rateLimitErr *entities.RateLimitError
+	require.ErrorAs(t, err, &rateLimitErr)
+	assert.Equal(t, time.Minute, rateLimitErr.RetryAfter)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(grpcadapter.ToStatus(err)))
+
+	published := publisher.Events()
+	require.Len(t, published, 1)
+	assert.Equal(t, events.EventUserLoginFail, published[0].Type)
+}
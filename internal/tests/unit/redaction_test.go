@@ -0,0 +1,119 @@
+package unit
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+	"github.com/LarsArtmann/template-sqlc/internal/monitoring"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedactionPolicyRedact(t *testing.T) {
+	mask := events.RedactionPolicy{Mode: events.RedactionMask}
+
+	assert.Equal(t, "j***@example.com", mask.Redact(events.PIIEmail, "john@example.com"))
+	assert.Equal(t, "J***", mask.Redact(events.PIIName, "John"))
+	assert.Equal(t, "192.0.2.0", mask.Redact(events.PIIIPAddress, "192.0.2.7"))
+	assert.Equal(t, "2001:db8:1::", mask.Redact(events.PIIIPAddress, "2001:db8:1:2::7"))
+	assert.Empty(t, mask.Redact(events.PIIName, ""), "empty values are kept")
+
+	hash := events.RedactionPolicy{Mode: events.RedactionHash, HashKey: []byte("secret")}
+	hashed := hash.Redact(events.PIIEmail, "john@example.com")
+
+	assert.Contains(t, hashed, "sha256:")
+	assert.NotContains(t, hashed, "john")
+	assert.Equal(t, hashed, hash.Redact(events.PIIEmail, "john@example.com"), "hashes correlate values")
+	assert.NotEqual(t, hashed, events.RedactionPolicy{Mode: events.RedactionHash, HashKey: []byte("other")}.
+		Redact(events.PIIEmail, "john@example.com"), "hashes are keyed")
+
+	overridden := events.RedactionPolicy{
+		Mode:   events.RedactionHash,
+		Fields: map[events.PIIField]events.RedactionMode{events.PIIIPAddress: events.RedactionNone},
+	}
+	assert.Equal(t, "192.0.2.7", overridden.Redact(events.PIIIPAddress, "192.0.2.7"))
+	assert.NotEqual(t, "John", overridden.Redact(events.PIIName, "John"))
+}
+
+func TestRedactionPolicyValidate(t *testing.T) {
+	require.NoError(t, events.FullPayloads().Validate())
+	require.ErrorIs(t, events.RedactionPolicy{Mode: "shred"}.Validate(), events.ErrInvalidRedactionMode)
+	require.ErrorIs(t, events.RedactionPolicy{
+		Mode:   events.RedactionMask,
+		Fields: map[events.PIIField]events.RedactionMode{events.PIIEmail: "shred"},
+	}.Validate(), events.ErrInvalidRedactionMode)
+}
+
+func TestRedactingPublisher(t *testing.T) {
+	inner := events.NewInMemoryEventPublisher()
+	publisher := events.NewRedactingPublisher(inner, events.RedactionPolicy{Mode: events.RedactionMask})
+
+	created := events.UserCreated(12, "john@example.com", "johnny", "John", "Doe", "user", "active")
+	updated := events.UserUpdated(12, map[string]any{
+		"first_name": map[string]any{"old": "Jon", "new": "John"},
+		"status":     "inactive",
+	}, 1)
+
+	require.NoError(t, publisher.Publish(created))
+	require.NoError(t, publisher.PublishBatch([]*events.UserEvent{updated}))
+
+	published := inner.Events()
+	require.Len(t, published, 2)
+
+	data, err := json.Marshal(published[0].Data)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "john@")
+	assert.NotContains(t, string(data), "Doe")
+	assert.NotContains(t, string(data), "johnny")
+	assert.Contains(t, string(data), `"userId":12`)
+	assert.Contains(t, string(data), `"role":"user"`)
+
+	data, err = json.Marshal(published[1].Data)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "Jon")
+	assert.Contains(t, string(data), `"status":"inactive"`)
+
+	original, ok := created.Data.(events.UserCreatedEvent)
+	require.True(t, ok, "the published event is a copy")
+	assert.Equal(t, "john@example.com", original.Email)
+}
+
+func TestRedactingPublisherFullPayloads(t *testing.T) {
+	inner := events.NewInMemoryEventPublisher()
+	publisher := events.NewRedactingPublisher(inner, events.FullPayloads())
+
+	created := events.UserCreated(12, "john@example.com", "johnny", "John", "Doe", "user", "active")
+	require.NoError(t, publisher.Publish(created))
+
+	published := inner.Events()
+	require.Len(t, published, 1)
+	assert.Same(t, created, published[0])
+}
+
+func TestRedactingHandler(t *testing.T) {
+	var out bytes.Buffer
+
+	logger := slog.New(monitoring.NewRedactingHandler(
+		slog.NewJSONHandler(&out, nil),
+		events.RedactionPolicy{Mode: events.RedactionMask},
+	)).With("ip_address", "192.0.2.7")
+
+	logger.Info("login",
+		"email", "john@example.com",
+		"user_id", 12,
+		slog.Group("user", "username", "johnny", "role", "user"),
+		slog.Group("email", "old", "jon@example.com"),
+	)
+
+	line := out.String()
+	assert.NotContains(t, line, "192.0.2.7")
+	assert.Contains(t, line, `"ip_address":"192.0.2.0"`)
+	assert.NotContains(t, line, "john@")
+	assert.NotContains(t, line, "jon@")
+	assert.NotContains(t, line, "johnny")
+	assert.Contains(t, line, `"user_id":12`)
+	assert.Contains(t, line, `"role":"user"`)
+}
@@ -0,0 +1,69 @@
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+	"github.com/LarsArtmann/template-sqlc/internal/replay"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newReplayStore(t *testing.T) *events.InMemoryEventStore {
+	t.Helper()
+
+	store := events.NewInMemoryEventStore()
+	err := store.Append(
+		context.Background(),
+		events.UserCreated(entities.UserID(1), "a@example.com", "a", "A", "A", "user", "active"),
+		events.UserLoggedIn(entities.UserID(1), "127.0.0.1", "agent", "desktop"),
+		events.UserLoggedIn(entities.UserID(1), "127.0.0.1", "agent", "desktop"),
+	)
+	require.NoError(t, err)
+
+	return store
+}
+
+func TestReplayResumesFromCheckpoint(t *testing.T) {
+	ctx := context.Background()
+	store := newReplayStore(t)
+	checkpoints := replay.NewInMemoryCheckpointStore()
+	counts := replay.NewEventCountProjection()
+
+	replayer, err := replay.NewReplayer(store, checkpoints, counts)
+	require.NoError(t, err)
+
+	report, err := replayer.Run(ctx, replay.Options{BatchSize: 2})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), report.Processed)
+	assert.Equal(t, int64(2), counts.Counts()[events.EventUserLogin])
+
+	err = store.Append(ctx, events.UserVerified(entities.UserID(1), "email"))
+	require.NoError(t, err)
+
+	report, err = replayer.Run(ctx, replay.Options{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), report.Applied[counts.Name()])
+	assert.Equal(t, int64(4), report.Checkpoints[counts.Name()])
+	assert.Equal(t, int64(2), counts.Counts()[events.EventUserLogin])
+}
+
+func TestReplayDryRunLeavesProjectionsUntouched(t *testing.T) {
+	ctx := context.Background()
+	checkpoints := replay.NewInMemoryCheckpointStore()
+	counts := replay.NewEventCountProjection()
+
+	replayer, err := replay.NewReplayer(newReplayStore(t), checkpoints, counts)
+	require.NoError(t, err)
+
+	report, err := replayer.Run(ctx, replay.Options{DryRun: true, FromStart: true})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), report.Applied[counts.Name()])
+	assert.Empty(t, counts.Counts())
+
+	position, err := checkpoints.Load(ctx, counts.Name())
+	require.NoError(t, err)
+	assert.Zero(t, position)
+}
@@ -0,0 +1,105 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// routedUserRepository records which database served each call.
+type routedUserRepository struct {
+	repositories.UserRepository
+
+	name  string
+	calls *[]string
+	err   error
+}
+
+func (r *routedUserRepository) GetByID(_ context.Context, _ entities.UserID) (*entities.User, error) {
+	*r.calls = append(*r.calls, r.name)
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	return &entities.User{}, nil
+}
+
+func (r *routedUserRepository) Update(_ context.Context, _ *entities.User) error {
+	*r.calls = append(*r.calls, r.name)
+
+	return nil
+}
+
+func TestReplicaUserRepositoryRouting(t *testing.T) {
+	ctx := context.Background()
+
+	var calls []string
+
+	primary := &routedUserRepository{name: "primary", calls: &calls}
+	broken := &routedUserRepository{name: "broken", calls: &calls, err: errors.New("connection refused")}
+	healthy := &routedUserRepository{name: "healthy", calls: &calls}
+	lagging := &routedUserRepository{name: "lagging", calls: &calls}
+
+	repo := adapters.NewReplicaUserRepository(primary, []adapters.Replica{
+		{Name: "broken", Repo: broken},
+		{Name: "healthy", Repo: healthy},
+		{Name: "lagging", Repo: lagging, Lag: func(context.Context) (time.Duration, error) {
+			return time.Minute, nil
+		}},
+	}, adapters.WithMaxStaleness(0))
+
+	for range 4 {
+		_, err := repo.GetByID(ctx, 1)
+		require.NoError(t, err)
+	}
+
+	assert.NotContains(t, calls, "lagging", "replicas beyond the staleness tolerance are skipped")
+	assert.Equal(t, 1, countOf(calls, "broken"), "failed replicas cool down")
+	assert.Equal(t, 1, countOf(calls, "primary"), "failed replica reads fall back to the primary")
+
+	calls = nil
+	_, err := repo.GetByID(adapters.ContextWithPrimary(ctx), 1)
+	require.NoError(t, err)
+	require.NoError(t, repo.Update(ctx, &entities.User{}))
+	assert.Equal(t, []string{"primary", "primary"}, calls)
+}
+
+func TestReplicaUserRepositoryReadsOwnWrites(t *testing.T) {
+	ctx := context.Background()
+
+	var calls []string
+
+	primary := &routedUserRepository{name: "primary", calls: &calls}
+	replica := &routedUserRepository{name: "replica", calls: &calls, err: entities.ErrUserNotFound}
+	repo := adapters.NewReplicaUserRepository(primary, []adapters.Replica{{Name: "replica", Repo: replica}},
+		adapters.WithMaxStaleness(time.Hour))
+
+	_, err := repo.GetByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"replica", "primary"}, calls, "not found on a replica is confirmed on the primary")
+
+	calls = nil
+	require.NoError(t, repo.Update(ctx, &entities.User{}))
+	_, err = repo.GetByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"primary", "primary"}, calls, "reads after a write stay on the primary")
+}
+
+func countOf(values []string, value string) int {
+	count := 0
+
+	for _, v := range values {
+		if v == value {
+			count++
+		}
+	}
+
+	return count
+}
@@ -0,0 +1,60 @@
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fixedReports returns fixed reports and records the requested limit.
+type fixedReports struct {
+	limit int
+}
+
+func (r *fixedReports) LoginsByDay(context.Context, time.Time, time.Time) ([]entities.DailyLogins, error) {
+	return []entities.DailyLogins{{Logins: 3, Failures: 1, Users: 2}}, nil
+}
+
+func (r *fixedReports) FailuresByIP(_ context.Context, _, _ time.Time, limit int) ([]entities.IPFailures, error) {
+	r.limit = limit
+
+	return []entities.IPFailures{{IPAddress: "10.0.0.1", Failures: 5, Users: 4}}, nil
+}
+
+func (r *fixedReports) SignupsBySource(context.Context, time.Time, time.Time) ([]entities.SignupSource, error) {
+	return []entities.SignupSource{{Source: entities.SignupSourceDirect, Signups: 2}}, nil
+}
+
+func TestReportingServiceValidatesRange(t *testing.T) {
+	ctx := context.Background()
+	repo := &fixedReports{}
+	service := services.NewReportingService(repo)
+
+	to := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	from := to.AddDate(0, 0, -7)
+
+	days, err := service.LoginsByDay(ctx, from, to)
+	require.NoError(t, err)
+	require.Len(t, days, 1)
+	assert.InDelta(t, 25.0, days[0].FailureRate(), 0.001)
+
+	_, err = service.LoginsByDay(ctx, to, from)
+	assert.True(t, entities.IsValidationError(err))
+
+	_, err = service.SignupsBySource(ctx, time.Time{}, to)
+	assert.True(t, entities.IsValidationError(err))
+
+	_, err = service.FailuresByIP(ctx, from, to, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 10, repo.limit)
+
+	for _, limit := range []int{0, services.MaxReportRows + 1} {
+		_, err = service.FailuresByIP(ctx, from, to, limit)
+		assert.True(t, entities.IsValidationError(err), "limit=%d", limit)
+	}
+}
@@ -0,0 +1,320 @@
+package unit
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/memory"
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/resilience"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+	"github.com/LarsArtmann/template-sqlc/internal/monitoring"
+	apperrors "github.com/LarsArtmann/template-sqlc/pkg/errors"
+	"github.com/LarsArtmann/template-sqlc/pkg/fixtures"
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyUserRepository fails GetByID and Update with the queued errors
+// before delegating to the memory repository.
+type flakyUserRepository struct {
+	*memory.UserRepository
+
+	mu    sync.Mutex
+	errs  []error
+	calls int
+}
+
+func (r *flakyUserRepository) GetByID(ctx context.Context, id entities.UserID) (*entities.User, error) {
+	err := r.next()
+	if err != nil {
+		return nil, err
+	}
+
+	return r.UserRepository.GetByID(ctx, id)
+}
+
+func (r *flakyUserRepository) Update(ctx context.Context, user *entities.User) error {
+	err := r.next()
+	if err != nil {
+		return err
+	}
+
+	return r.UserRepository.Update(ctx, user)
+}
+
+// next counts a call and returns the next queued error, if any.
+func (r *flakyUserRepository) next() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.calls++
+
+	var err error
+	if len(r.errs) > 0 {
+		err, r.errs = r.errs[0], r.errs[1:]
+	}
+
+	return err
+}
+
+// fail queues errs for the next calls.
+func (r *flakyUserRepository) fail(errs ...error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.errs = append(r.errs, errs...)
+}
+
+// recordingObserver records retries and circuit breaker states.
+type recordingObserver struct {
+	mu      sync.Mutex
+	retries int
+	states  []string
+}
+
+func (o *recordingObserver) ObserveRetry(string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.retries++
+}
+
+func (o *recordingObserver) ObserveCircuitState(_, state string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.states = append(o.states, state)
+}
+
+// quickRetry retries transient failures of engine without waiting long.
+func quickRetry(engine string) resilience.Option {
+	policy := resilience.DefaultRetryPolicy(engine)
+	policy.InitialBackoff = time.Millisecond
+	policy.MaxBackoff = time.Millisecond
+
+	return resilience.WithRetry(policy)
+}
+
+var errSerializationFailure = &pgconn.PgError{Code: "40001", Message: "could not serialize access"}
+
+func TestTransientErrorsPerEngine(t *testing.T) {
+	tests := []struct {
+		engine    string
+		err       error
+		transient bool
+	}{
+		{"postgres", errSerializationFailure, true},
+		{"postgres", &pgconn.PgError{Code: "40P01"}, true},
+		{"postgres", &pgconn.PgError{Code: "08006"}, true},
+		{"postgres", &pgconn.PgError{Code: "23505"}, false},
+		{"cockroachdb", errSerializationFailure, true},
+		{"mysql", &mysql.MySQLError{Number: 1213}, true},
+		{"mysql", &mysql.MySQLError{Number: 1205}, true},
+		{"mysql", mysql.ErrInvalidConn, true},
+		{"mysql", &mysql.MySQLError{Number: 1062}, false},
+		{"sqlite", errors.New("database is locked (5) (SQLITE_BUSY)"), true},
+		{"libsql", errors.New("database is locked"), true},
+		{"sqlite", errors.New("UNIQUE constraint failed: users.email"), false},
+		{"mysql", errSerializationFailure, false},
+		{"sqlite", driver.ErrBadConn, true},
+		{"postgres", syscall.ECONNRESET, true},
+		{"duckdb", syscall.ECONNRESET, true},
+		{"postgres", context.DeadlineExceeded, false},
+		{"postgres", entities.ErrUserNotFound, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s/%v", tt.engine, tt.err), func(t *testing.T) {
+			// Repositories wrap the driver errors.
+			err := apperrors.NewDatabaseError("get user failed", tt.err)
+
+			assert.Equal(t, tt.transient, resilience.TransientFor(tt.engine)(err))
+		})
+	}
+}
+
+func TestWriteTransientErrorsPerEngine(t *testing.T) {
+	tests := []struct {
+		engine    string
+		err       error
+		transient bool
+	}{
+		{"postgres", errSerializationFailure, true},
+		{"postgres", &pgconn.PgError{Code: "40P01"}, true},
+		{"postgres", &pgconn.PgError{Code: "08006"}, false},
+		{"postgres", &pgconn.PgError{Code: "57P01"}, false},
+		{"mysql", &mysql.MySQLError{Number: 1213}, true},
+		{"mysql", mysql.ErrInvalidConn, false},
+		{"mysql", &mysql.MySQLError{Number: 1053}, false},
+		{"sqlite", errors.New("database is locked (5) (SQLITE_BUSY)"), true},
+		{"sqlite", driver.ErrBadConn, true},
+		{"postgres", syscall.ECONNREFUSED, true},
+		{"postgres", syscall.ECONNRESET, false},
+		{"postgres", syscall.EPIPE, false},
+		{"mysql", io.ErrUnexpectedEOF, false},
+		{"duckdb", syscall.ECONNRESET, false},
+		{"duckdb", driver.ErrBadConn, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s/%v", tt.engine, tt.err), func(t *testing.T) {
+			err := apperrors.NewDatabaseError("update user failed", tt.err)
+
+			assert.Equal(t, tt.transient, resilience.WriteTransientFor(tt.engine)(err))
+		})
+	}
+}
+
+func TestResilientRepositoryRetriesWritesOnlyBeforeSending(t *testing.T) {
+	ctx := context.Background()
+	repo := &flakyUserRepository{UserRepository: memory.NewUserRepository()}
+	user := fixtures.User().Named("ada").MustBuild()
+	require.NoError(t, repo.Create(ctx, user))
+
+	users := resilience.NewUserRepository(repo, quickRetry("postgres"))
+
+	// A reset connection may have applied the update, so it is not retried.
+	repo.fail(syscall.ECONNRESET)
+	require.ErrorIs(t, users.Update(ctx, user), syscall.ECONNRESET)
+	assert.Equal(t, 1, repo.calls)
+
+	// Reads are retried after the same failure.
+	repo.fail(syscall.ECONNRESET)
+	_, err := users.GetByID(ctx, user.ID())
+	require.NoError(t, err)
+	assert.Equal(t, 3, repo.calls)
+
+	// A bad connection from the pool was never used, so the update is retried.
+	repo.fail(driver.ErrBadConn)
+	require.NoError(t, users.Update(ctx, user))
+	assert.Equal(t, 5, repo.calls)
+}
+
+func TestResilientRepositoryRetriesTransientFailures(t *testing.T) {
+	ctx := context.Background()
+	repo := &flakyUserRepository{UserRepository: memory.NewUserRepository()}
+	user := fixtures.User().Named("ada").MustBuild()
+	require.NoError(t, repo.Create(ctx, user))
+
+	observer := &recordingObserver{}
+	users := resilience.NewUserRepository(repo, quickRetry("postgres"), resilience.WithObserver(observer))
+
+	repo.fail(errSerializationFailure, apperrors.NewDatabaseError("get user failed", errSerializationFailure))
+
+	got, err := users.GetByID(ctx, user.ID())
+	require.NoError(t, err)
+	assert.Equal(t, user.ID(), got.ID())
+	assert.Equal(t, 3, repo.calls)
+	assert.Equal(t, 2, observer.retries)
+
+	// Permanent failures are returned at once and unchanged.
+	_, err = users.GetByID(ctx, user.ID()+1)
+	require.ErrorIs(t, err, entities.ErrUserNotFound)
+	assert.Equal(t, 4, repo.calls)
+
+	// The last transient failure is returned once the attempts are exhausted.
+	repo.fail(errSerializationFailure, errSerializationFailure, errSerializationFailure)
+
+	_, err = users.GetByID(ctx, user.ID())
+	require.ErrorIs(t, err, errSerializationFailure)
+	assert.Equal(t, 7, repo.calls)
+}
+
+func TestCircuitBreakerOpensAndRecovers(t *testing.T) {
+	ctx := context.Background()
+	repo := &flakyUserRepository{UserRepository: memory.NewUserRepository()}
+	user := fixtures.User().Named("ada").MustBuild()
+	require.NoError(t, repo.Create(ctx, user))
+
+	now := time.Now()
+	observer := &recordingObserver{}
+	users := resilience.NewUserRepository(repo,
+		resilience.WithRetry(resilience.RetryPolicy{MaxAttempts: 1}),
+		resilience.WithBreaker(resilience.BreakerPolicy{FailureThreshold: 2, OpenTimeout: time.Minute}),
+		resilience.WithClock(func() time.Time { return now }),
+		resilience.WithObserver(observer),
+	)
+
+	// Failures of the request do not count.
+	_, err := users.GetByID(ctx, user.ID()+1)
+	require.ErrorIs(t, err, entities.ErrUserNotFound)
+
+	repo.fail(errSerializationFailure, errSerializationFailure)
+
+	for range 2 {
+		_, err = users.GetByID(ctx, user.ID())
+		require.ErrorIs(t, err, errSerializationFailure)
+	}
+
+	_, err = users.GetByID(ctx, user.ID())
+	require.ErrorIs(t, err, resilience.ErrCircuitOpen)
+	assert.True(t, apperrors.IsAppError(err))
+	assert.Equal(t, apperrors.ErrCodeUnavailable, apperrors.CodeOf(err))
+	assert.Equal(t, 3, repo.calls, "open circuits do not call the repository")
+
+	// A failed trial opens the breaker again.
+	now = now.Add(time.Minute)
+	repo.fail(errSerializationFailure)
+
+	_, err = users.GetByID(ctx, user.ID())
+	require.ErrorIs(t, err, errSerializationFailure)
+
+	_, err = users.GetByID(ctx, user.ID())
+	require.ErrorIs(t, err, resilience.ErrCircuitOpen)
+
+	// A successful trial closes it.
+	now = now.Add(time.Minute)
+
+	_, err = users.GetByID(ctx, user.ID())
+	require.NoError(t, err)
+
+	_, err = users.GetByID(ctx, user.ID())
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"closed", "open", "half_open", "open", "half_open", "closed"}, observer.states)
+}
+
+// failingPublisher fails every publish.
+type failingPublisher struct {
+	calls int
+}
+
+func (p *failingPublisher) Publish(*events.UserEvent) error {
+	p.calls++
+
+	return errBrokerDown
+}
+
+func (p *failingPublisher) PublishBatch([]*events.UserEvent) error {
+	p.calls++
+
+	return errBrokerDown
+}
+
+func TestResilientPublisherBreaksAndReportsMetrics(t *testing.T) {
+	next := &failingPublisher{}
+	metrics := monitoring.NewMetrics()
+	publisher := resilience.NewPublisher("nats", next,
+		resilience.WithBreaker(resilience.BreakerPolicy{FailureThreshold: 2, OpenTimeout: time.Minute}),
+		resilience.WithObserver(metrics),
+	)
+
+	require.ErrorIs(t, publisher.Publish(&events.UserEvent{}), errBrokerDown)
+	require.ErrorIs(t, publisher.PublishBatch(nil), errBrokerDown)
+	require.ErrorIs(t, publisher.Publish(&events.UserEvent{}), resilience.ErrCircuitOpen)
+	assert.Equal(t, 2, next.calls, "publishes are not retried by default")
+
+	assert.InDelta(t, 1, testutil.ToFloat64(metrics.CircuitBreakerState.WithLabelValues("nats", "open")), 0)
+	assert.Equal(t, 1, testutil.CollectAndCount(metrics.CircuitBreakerState), "only the current state is reported")
+}
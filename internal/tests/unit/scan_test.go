@@ -0,0 +1,73 @@
+package unit
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/LarsArtmann/template-sqlc/pkg/scan"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// widgetRow stands in for a generated row struct.
+type widgetRow struct {
+	ID   int64
+	Name string
+}
+
+// widget is the entity of widgetRow.
+type widget struct {
+	ID    int64
+	Label string
+}
+
+var errBlankWidget = errors.New("blank widget")
+
+func domainWidget(row *widgetRow) (*widget, error) {
+	if row.Name == "" {
+		return nil, errBlankWidget
+	}
+
+	return &widget{ID: row.ID, Label: row.Name}, nil
+}
+
+func TestScanRegisteredMapper(t *testing.T) {
+	scan.Register(domainWidget)
+
+	item, err := scan.One[*widget](&widgetRow{ID: 1, Name: "gear"})
+	require.NoError(t, err)
+	assert.Equal(t, &widget{ID: 1, Label: "gear"}, item)
+
+	items, err := scan.All[*widget]([]*widgetRow{{ID: 1, Name: "gear"}, {ID: 2, Name: "bolt"}})
+	require.NoError(t, err)
+	assert.Equal(t, []*widget{{ID: 1, Label: "gear"}, {ID: 2, Label: "bolt"}}, items)
+
+	_, err = scan.All[*widget]([]*widgetRow{{ID: 1, Name: "gear"}, {ID: 2}})
+	require.ErrorIs(t, err, errBlankWidget)
+
+	_, err = scan.One[*widget]((*widgetRow)(nil))
+	require.ErrorIs(t, err, scan.ErrNilRow)
+}
+
+func TestScanUnregisteredMapper(t *testing.T) {
+	registry := scan.NewRegistry()
+
+	_, err := scan.Lookup[widgetRow, *widget](registry)
+	require.ErrorIs(t, err, scan.ErrNoMapper)
+
+	scan.RegisterIn(registry, domainWidget)
+
+	mapper, err := scan.Lookup[widgetRow, *widget](registry)
+	require.NoError(t, err)
+
+	item, err := mapper.One(&widgetRow{ID: 3, Name: "nut"})
+	require.NoError(t, err)
+	assert.Equal(t, "nut", item.Label)
+
+	// Mappers are keyed by entity type as well as row type.
+	_, err = scan.Lookup[widgetRow, widget](registry)
+	require.ErrorIs(t, err, scan.ErrNoMapper)
+
+	_, err = scan.All[widget]([]*widgetRow{{ID: 1, Name: "gear"}})
+	require.ErrorIs(t, err, scan.ErrNoMapper)
+}
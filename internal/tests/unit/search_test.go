@@ -0,0 +1,192 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/memory"
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/search"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// brokenIndexer fails every search, so the decorator falls back to the database.
+type brokenIndexer struct {
+	repositories.SearchIndexer
+}
+
+var errIndexDown = errors.New("index down")
+
+func (brokenIndexer) Search(context.Context, entities.UserFilter, int, int) ([]entities.UserID, error) {
+	return nil, errIndexDown
+}
+
+func (brokenIndexer) SearchWithFacets(
+	context.Context,
+	string,
+	entities.UserStatus,
+	int,
+) ([]entities.UserID, *entities.SearchFacets, error) {
+	return nil, nil, errIndexDown
+}
+
+// seedSearchUsers creates users with distinct names and tags in repo.
+func seedSearchUsers(t *testing.T, repo repositories.UserRepository) []*entities.User {
+	t.Helper()
+
+	var users []*entities.User
+
+	for _, seed := range []struct {
+		name, last string
+		tags       []string
+	}{
+		{"ada", "Lovelace", []string{"math", "pioneer"}},
+		{"adele", "Goldberg", []string{"smalltalk"}},
+		{"grace", "Hopper", []string{"cobol", "pioneer"}},
+		{"alan", "Turing", []string{"math"}},
+	} {
+		user, err := entities.NewUser(
+			entities.Email(seed.name+"@example.com"),
+			entities.Username(seed.name),
+			entities.PasswordHash("hash-"+seed.name),
+			entities.FirstName(seed.name),
+			entities.LastName(seed.last),
+			entities.UserStatusActive,
+			entities.UserRoleUser,
+			nil,
+			seed.tags,
+		)
+		require.NoError(t, err)
+		require.NoError(t, repo.Create(context.Background(), user))
+
+		users = append(users, user)
+	}
+
+	return users
+}
+
+func usernames(users []*entities.User) []string {
+	names := make([]string, 0, len(users))
+	for _, user := range users {
+		names = append(names, user.Username().String())
+	}
+
+	return names
+}
+
+func TestSearchRepositoryServesFromIndex(t *testing.T) {
+	ctx := context.Background()
+	db := memory.NewUserRepository()
+	users := seedSearchUsers(t, db)
+
+	index, err := search.OpenBleveIndexer("")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = index.Close() })
+
+	indexed, err := search.Reindex(ctx, index, db)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), indexed)
+
+	repo := search.NewUserRepository(db, index)
+
+	for _, filter := range []entities.UserFilter{
+		{Query: "ad"},
+		{Query: "ADA lovelace"},
+		{TagsAny: []string{"pioneer", "smalltalk"}},
+		{TagsAll: []string{"math", "pioneer"}},
+		{Query: "a", TagsAny: []string{"math"}},
+	} {
+		want, err := db.List(ctx, filter, 10, 0)
+		require.NoError(t, err)
+
+		got, err := repo.List(ctx, filter, 10, 0)
+		require.NoError(t, err)
+		assert.Equal(t, usernames(want), usernames(got), "filter %+v", filter)
+	}
+
+	result, err := repo.SearchWithFacets(ctx, "ad", "", 10)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"ada", "adele"}, usernames(result.Users))
+	assert.Equal(t, map[entities.UserStatus]int64{entities.UserStatusActive: 2}, result.Facets.ByStatus)
+	assert.Equal(t, []entities.TagCount{{Tag: "math", Count: 1}, {Tag: "pioneer", Count: 1}, {Tag: "smalltalk", Count: 1}},
+		result.Facets.TopTags)
+
+	// Changes reach the index through the events.
+	sync := search.NewSyncHandler(index, db)
+	ada, adele := users[0], users[1]
+
+	require.NoError(t, db.Suspend(ctx, ada.ID()))
+	require.NoError(t, sync.Handle(ctx, events.NewUserEvent(events.EventUserSuspended, ada.ID(), nil)))
+	require.NoError(t, db.Delete(ctx, adele.ID()))
+	require.NoError(t, sync.Handle(ctx, events.NewUserEvent(events.EventUserDeleted, adele.ID(), nil)))
+
+	result, err = repo.SearchWithFacets(ctx, "ad", entities.UserStatusSuspended, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ada"}, usernames(result.Users))
+	assert.Equal(t, map[entities.UserStatus]int64{entities.UserStatusSuspended: 1}, result.Facets.ByStatus)
+}
+
+func TestSearchSyncFollowsIdentityChanges(t *testing.T) {
+	ctx := context.Background()
+	db := memory.NewUserRepository()
+	users := seedSearchUsers(t, db)
+
+	index, err := search.OpenBleveIndexer("")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = index.Close() })
+
+	_, err = search.Reindex(ctx, index, db)
+	require.NoError(t, err)
+
+	repo := search.NewUserRepository(db, index)
+	sync := search.NewSyncHandler(index, db)
+	grace := users[2]
+
+	rename := func(username entities.Username, eventType events.EventType) {
+		t.Helper()
+
+		grace.ChangeUsername(username)
+		require.NoError(t, db.Update(ctx, grace))
+		require.NoError(t, sync.Handle(ctx, events.NewUserEvent(eventType, grace.ID(), nil)))
+	}
+
+	rename("amazing", events.EventUsernameChanged)
+
+	listed, err := repo.List(ctx, entities.UserFilter{Query: "amazing"}, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"amazing"}, usernames(listed))
+
+	grace.ChangeEmail("hopper@example.com")
+	require.NoError(t, db.Update(ctx, grace))
+	require.NoError(t, sync.Handle(ctx, events.NewUserEvent(events.EventEmailChanged, grace.ID(), nil)))
+
+	listed, err = repo.List(ctx, entities.UserFilter{Query: "hopper@example.com"}, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"amazing"}, usernames(listed))
+
+	rename("grace", events.EventIdentityChangeReverted)
+
+	listed, err = repo.List(ctx, entities.UserFilter{Query: "amazing"}, 10, 0)
+	require.NoError(t, err)
+	assert.Empty(t, listed)
+}
+
+func TestSearchRepositoryFallsBackToDatabase(t *testing.T) {
+	ctx := context.Background()
+	db := memory.NewUserRepository()
+	seedSearchUsers(t, db)
+
+	repo := search.NewUserRepository(db, brokenIndexer{})
+
+	listed, err := repo.List(ctx, entities.UserFilter{TagsAny: []string{"math"}}, 10, 0)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"ada", "alan"}, usernames(listed))
+
+	result, err := repo.SearchWithFacets(ctx, "grace", "", 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"grace"}, usernames(result.Users))
+}
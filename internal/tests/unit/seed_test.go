@@ -0,0 +1,108 @@
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/memory"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/pkg/fixtures"
+	"github.com/LarsArtmann/template-sqlc/pkg/seed"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var seedAnchor = time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC) //nolint:gochecknoglobals // Test anchor time
+
+func generateDemo(t *testing.T, opts ...seed.Option) (*seed.Dataset, *memory.LoginHistoryRepository) {
+	t.Helper()
+
+	logins := memory.NewLoginHistoryRepository()
+	generator := seed.NewGenerator(memory.NewUserRepository(), append([]seed.Option{
+		seed.WithSessions(memory.NewSessionRepository()),
+		seed.WithLoginHistory(logins),
+		seed.WithBatchSize(7),
+		seed.WithNow(seedAnchor),
+	}, opts...)...)
+
+	volume := seed.Volume{Users: 40, SessionsPerUser: 2, LoginsPerUser: 3}
+
+	dataset, err := generator.Generate(context.Background(), volume)
+	require.NoError(t, err)
+
+	return dataset, logins
+}
+
+func TestSeedGeneratorIsDeterministic(t *testing.T) {
+	first, _ := generateDemo(t, seed.WithSeed(42))
+	second, _ := generateDemo(t, seed.WithSeed(42))
+	other, _ := generateDemo(t, seed.WithSeed(43))
+
+	require.Len(t, second.Users, len(first.Users))
+
+	for i, user := range first.Users {
+		assert.Equal(t, user.UUID(), second.Users[i].UUID())
+		assert.Equal(t, user.Email(), second.Users[i].Email())
+		assert.Equal(t, user.Status(), second.Users[i].Status())
+		assert.Equal(t, user.Tags(), second.Users[i].Tags())
+		assert.Equal(t, user.CreatedAt(), second.Users[i].CreatedAt())
+	}
+
+	require.Len(t, second.Sessions, len(first.Sessions))
+	assert.Equal(t, first.Sessions[0].Token(), second.Sessions[0].Token())
+	assert.Equal(t, first.Logins[len(first.Logins)-1], second.Logins[len(second.Logins)-1])
+
+	assert.NotEqual(t, first.Users[0].Username(), other.Users[0].Username(), "seeds generate different users")
+}
+
+func TestSeedGeneratorVolume(t *testing.T) {
+	dataset, logins := generateDemo(t)
+
+	require.Len(t, dataset.Users, 40)
+	assert.Len(t, dataset.Logins, 120)
+
+	active, expired := 0, 0
+
+	for _, user := range dataset.Users {
+		require.NotZero(t, user.ID())
+		assert.True(t, user.CreatedAt().Before(seedAnchor))
+
+		if user.IsActive() {
+			active++
+		}
+
+		history, err := logins.ListByUser(context.Background(), user.ID(), 10)
+		require.NoError(t, err)
+		require.Len(t, history, 3)
+
+		if !user.IsActive() {
+			assert.Equal(t, entities.LoginFailureInactiveAccount, history[0].Failure, "inactive users fail to sign in")
+		}
+	}
+
+	assert.Len(t, dataset.Sessions, 2*active, "only active users have sessions")
+
+	for _, session := range dataset.Sessions {
+		if session.ExpiresAt().Before(seedAnchor) {
+			expired++
+		}
+	}
+
+	assert.Positive(t, expired)
+	assert.Less(t, expired, len(dataset.Sessions))
+}
+
+func TestSeedGeneratorRequiresRepositories(t *testing.T) {
+	generator := seed.NewGenerator(memory.NewUserRepository())
+	ctx := context.Background()
+
+	_, err := generator.Generate(ctx, seed.Volume{Users: 1, SessionsPerUser: 1})
+	require.ErrorIs(t, err, fixtures.ErrNoSessionRepository)
+
+	_, err = generator.Generate(ctx, seed.Volume{Users: 1, LoginsPerUser: 1})
+	require.ErrorIs(t, err, seed.ErrNoLoginHistory)
+
+	_, err = generator.Generate(ctx, seed.Volume{Users: -1})
+	require.ErrorIs(t, err, seed.ErrInvalidVolume)
+}
@@ -0,0 +1,202 @@
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/memory"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/services"
+	"github.com/LarsArtmann/template-sqlc/internal/validation"
+	"github.com/LarsArtmann/template-sqlc/pkg/passwords"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// testHasher hashes the passwords of users that tests authenticate.
+var testHasher = passwords.NewBcryptHasher(bcrypt.MinCost)
+
+// hashPassword returns the testHasher hash of password.
+func hashPassword(t *testing.T, password string) entities.PasswordHash {
+	t.Helper()
+
+	hash, err := testHasher.Hash(password)
+	require.NoError(t, err)
+
+	return entities.PasswordHash(hash)
+}
+
+// serviceFixture is a user service over in-memory repositories that audits
+// in memory. Tests add the repositories of their features to tx before
+// starting the service.
+type serviceFixture struct {
+	service   *services.UserService
+	users     *memory.UserRepository
+	sessions  *memory.SessionRepository
+	publisher *events.InMemoryEventPublisher
+	audit     *memoryAudit
+	tx        *memoryTransactions
+}
+
+func newServiceFixture() *serviceFixture {
+	f := &serviceFixture{
+		users:     memory.NewUserRepository(),
+		sessions:  memory.NewSessionRepository(),
+		publisher: events.NewInMemoryEventPublisher(),
+		audit:     &memoryAudit{},
+	}
+
+	f.tx = &memoryTransactions{
+		userRepo:    f.users,
+		sessionRepo: f.sessions,
+		auditRepo:   f.audit,
+	}
+
+	return f
+}
+
+// start creates the service with opts. Services that run transactions are
+// started with services.WithTransactions(f.tx).
+func (f *serviceFixture) start(opts ...services.UserServiceOption) {
+	f.service = services.NewUserService(f.users, f.sessions, f.publisher, validation.NewUserValidator(),
+		append([]services.UserServiceOption{services.WithAuditLog(f.audit)}, opts...)...)
+}
+
+// createUser stores user and returns it.
+func (f *serviceFixture) createUser(t *testing.T, user *entities.User) *entities.User {
+	t.Helper()
+
+	require.NoError(t, f.users.Create(context.Background(), user))
+
+	return user
+}
+
+// memoryAudit keeps audit entries in a slice.
+type memoryAudit struct {
+	entries []*entities.AuditLog
+}
+
+func (a *memoryAudit) Record(_ context.Context, entry *entities.AuditLog) error {
+	entry.ID = entities.AuditLogID(len(a.entries) + 1)
+	a.entries = append(a.entries, entry)
+
+	return nil
+}
+
+func (a *memoryAudit) List(_ context.Context, filter entities.AuditFilter) ([]*entities.AuditLog, error) {
+	var matched []*entities.AuditLog
+
+	for _, entry := range a.entries {
+		if (filter.UserID == 0 || entry.UserID == filter.UserID) &&
+			(filter.ActorID == 0 || entry.ActorID == filter.ActorID) {
+			matched = append(matched, entry)
+		}
+	}
+
+	return matched[min(filter.Offset, len(matched)):min(filter.Offset+filter.Limit, len(matched))], nil
+}
+
+func (a *memoryAudit) AnonymizeUser(_ context.Context, userID entities.UserID) (int64, error) {
+	var anonymized int64
+
+	for _, entry := range a.entries {
+		if entry.UserID == userID || entry.ActorID == userID {
+			entry.Changes = []entities.FieldChange{}
+			entry.IPAddress = ""
+			anonymized++
+		}
+	}
+
+	return anonymized, nil
+}
+
+// memoryTransactions runs transactions directly against in-memory repositories.
+type memoryTransactions struct {
+	fakeTransactions
+
+	userRepo       repositories.UserRepository
+	sessionRepo    repositories.SessionRepository
+	auditRepo      repositories.AuditRepository
+	deletionRepo   repositories.AccountDeletionRepository
+	loginRepo      repositories.LoginHistoryRepository
+	changeRepo     repositories.IdentityChangeRepository
+	moderationRepo repositories.ModerationRepository
+	activityRepo   repositories.ActivityRepository
+	preferenceRepo repositories.UserPreferenceRepository
+	streamStore    repositories.UserEventStore
+}
+
+func (m *memoryTransactions) RunInTransaction(
+	ctx context.Context,
+	fn func(ctx context.Context, tx repositories.Transaction) error,
+) error {
+	return fn(ctx, m)
+}
+
+func (m *memoryTransactions) UserRepository() repositories.UserRepository {
+	return m.userRepo
+}
+
+func (m *memoryTransactions) SessionRepository() repositories.SessionRepository {
+	return m.sessionRepo
+}
+
+func (m *memoryTransactions) AuditRepository() repositories.AuditRepository {
+	return m.auditRepo
+}
+
+func (m *memoryTransactions) AccountDeletionRepository() repositories.AccountDeletionRepository {
+	return m.deletionRepo
+}
+
+func (m *memoryTransactions) LoginHistoryRepository() repositories.LoginHistoryRepository {
+	return m.loginRepo
+}
+
+func (m *memoryTransactions) IdentityChangeRepository() repositories.IdentityChangeRepository {
+	return m.changeRepo
+}
+
+func (m *memoryTransactions) ModerationRepository() repositories.ModerationRepository {
+	return m.moderationRepo
+}
+
+func (m *memoryTransactions) ActivityRepository() repositories.ActivityRepository {
+	return m.activityRepo
+}
+
+func (m *memoryTransactions) UserPreferenceRepository() repositories.UserPreferenceRepository {
+	return m.preferenceRepo
+}
+
+func (m *memoryTransactions) UserEventStore() repositories.UserEventStore {
+	return m.streamStore
+}
+
+// eventsOfType returns the published events of eventType.
+func eventsOfType(publisher *events.InMemoryEventPublisher, eventType events.EventType) []*events.UserEvent {
+	var matched []*events.UserEvent
+
+	for _, event := range publisher.Events() {
+		if event.Type == eventType {
+			matched = append(matched, event)
+		}
+	}
+
+	return matched
+}
+
+// lastEventData returns the data of the last published event of eventType.
+func lastEventData[T any](t *testing.T, publisher *events.InMemoryEventPublisher, eventType events.EventType) T {
+	t.Helper()
+
+	published := eventsOfType(publisher, eventType)
+	require.NotEmpty(t, published, eventType)
+
+	data, ok := published[len(published)-1].Data.(T)
+	require.True(t, ok)
+
+	return data
+}
@@ -0,0 +1,94 @@
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/services"
+	"github.com/LarsArtmann/template-sqlc/internal/validation"
+	"github.com/LarsArtmann/template-sqlc/pkg/fixtures"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// racingSessions runs race once, right after the next session is read by
+// refresh token, as a concurrent request would.
+type racingSessions struct {
+	repositories.SessionRepository
+
+	race func()
+}
+
+func (r *racingSessions) GetByRefreshToken(
+	ctx context.Context,
+	token entities.RefreshToken,
+) (*entities.UserSession, error) {
+	session, err := r.SessionRepository.GetByRefreshToken(ctx, token)
+
+	if race := r.race; race != nil {
+		r.race = nil
+		race()
+	}
+
+	return session, err
+}
+
+// newRefreshFixture stores a session of a user and issues its first token
+// pair with a service reading sessions through racing.
+func newRefreshFixture(t *testing.T) (*serviceFixture, *racingSessions, *entities.UserSession, *services.TokenPair) {
+	t.Helper()
+
+	f := newServiceFixture()
+	user := f.createUser(t, fixtures.User().Named("ada").MustBuild())
+
+	racing := &racingSessions{SessionRepository: f.sessions}
+	f.service = services.NewUserService(f.users, racing, f.publisher, validation.NewUserValidator())
+
+	session := entities.NewUserSession(user.ID(), nil, "", entities.NewSessionDeviceInfo(), time.Hour)
+	require.NoError(t, f.sessions.Create(context.Background(), session))
+
+	pair, err := f.service.IssueTokenPair(context.Background(), session, user)
+	require.NoError(t, err)
+
+	return f, racing, session, pair
+}
+
+func TestRefreshSessionConflictsWithConcurrentRefresh(t *testing.T) {
+	ctx := context.Background()
+	f, racing, session, pair := newRefreshFixture(t)
+
+	var winner *services.TokenPair
+
+	racing.race = func() {
+		var err error
+		winner, err = f.service.RefreshSession(ctx, pair.RefreshToken)
+		require.NoError(t, err)
+	}
+
+	_, err := f.service.RefreshSession(ctx, pair.RefreshToken)
+	require.ErrorIs(t, err, entities.ErrSessionConflict)
+
+	stored, err := f.sessions.GetByToken(ctx, session.Token())
+	require.NoError(t, err)
+	assert.Equal(t, winner.RefreshToken, stored.RefreshToken().String(),
+		"the losing refresh must not replace the token of the winner")
+}
+
+func TestRefreshSessionConflictsWithConcurrentRevocation(t *testing.T) {
+	ctx := context.Background()
+	f, racing, session, pair := newRefreshFixture(t)
+
+	racing.race = func() {
+		require.NoError(t, f.sessions.DeactivateByToken(ctx, session.Token()))
+	}
+
+	_, err := f.service.RefreshSession(ctx, pair.RefreshToken)
+	require.ErrorIs(t, err, entities.ErrSessionConflict)
+
+	stored, err := f.sessions.GetByToken(ctx, session.Token())
+	require.NoError(t, err)
+	assert.False(t, stored.IsActive(), "a refresh must not revive a revoked session")
+}
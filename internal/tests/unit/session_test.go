@@ -0,0 +1,200 @@
+package unit
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// staticOrgRoles resolves every membership to a fixed role.
+type staticOrgRoles struct {
+	role entities.UserRole
+}
+
+func (r staticOrgRoles) RoleInOrganization(
+	context.Context,
+	entities.UserID,
+	entities.OrganizationID,
+) (entities.UserRole, error) {
+	return r.role, nil
+}
+
+// newTestUser creates an active user with the given role.
+func newTestUser(t *testing.T, role entities.UserRole) *entities.User {
+	t.Helper()
+
+	user, err := entities.NewUser(
+		entities.Email("test@example.com"),
+		entities.Username("testuser"),
+		entities.PasswordHash("$2a$10$N9qo8uLOickgx2ZMRZoMyeIjZRGdjGj/n3.rsQ5pPjZ5yVlWK5WAe"),
+		entities.FirstName("John"),
+		entities.LastName("Doe"),
+		entities.UserStatusActive,
+		role,
+		entities.NewUserMetadata(),
+		[]string{},
+	)
+	require.NoError(t, err)
+
+	return user
+}
+
+func TestSessionOrganizationScope(t *testing.T) {
+	session := entities.NewUserSession(
+		entities.UserID(1),
+		net.ParseIP("127.0.0.1"),
+		"test-agent",
+		entities.NewSessionDeviceInfo(),
+		entities.SessionDurationShort,
+	)
+
+	_, scoped := session.OrganizationID()
+	assert.False(t, scoped)
+
+	session.SwitchOrganization(entities.OrganizationID(42))
+
+	orgID, scoped := session.OrganizationID()
+	assert.True(t, scoped)
+	assert.Equal(t, entities.OrganizationID(42), orgID)
+
+	session.ClearOrganization()
+
+	_, scoped = session.OrganizationID()
+	assert.False(t, scoped)
+}
+
+func TestPermissionCheckerOrganizationScope(t *testing.T) {
+	ctx := context.Background()
+	user := newTestUser(t, entities.UserRoleUser)
+	session := entities.NewUserSession(
+		entities.UserID(1),
+		net.ParseIP("127.0.0.1"),
+		"test-agent",
+		entities.NewSessionDeviceInfo(),
+		entities.SessionDurationShort,
+	)
+
+	checker := services.NewPermissionChecker(staticOrgRoles{role: entities.UserRoleModerator})
+
+	allowed, err := checker.Can(ctx, session, user, entities.PermissionUsersWrite)
+	require.NoError(t, err)
+	assert.False(t, allowed, "global user role must not grant write access")
+
+	session.SwitchOrganization(entities.OrganizationID(7))
+
+	allowed, err = checker.Can(ctx, session, user, entities.PermissionUsersWrite)
+	require.NoError(t, err)
+	assert.True(t, allowed, "organization moderator role must grant write access")
+
+	err = checker.Require(ctx, session, user, entities.PermissionRolesManage)
+	require.ErrorIs(t, err, entities.ErrInsufficientPrivileges)
+}
+
+func TestSessionPolicySlidingExpiration(t *testing.T) {
+	policy := entities.SessionPolicy{
+		AbsoluteLifetime: 10 * time.Hour,
+		IdleTimeout:      4 * time.Hour,
+		RenewalThreshold: 2 * time.Hour,
+	}
+	require.NoError(t, policy.Validate())
+
+	session := entities.NewUserSession(
+		entities.UserID(1),
+		net.ParseIP("127.0.0.1"),
+		"test-agent",
+		entities.NewSessionDeviceInfo(),
+		policy.InitialLifetime(),
+	)
+	created := session.CreatedAt()
+
+	assert.False(t, policy.Renew(session, created.Add(time.Hour)), "plenty of time left")
+
+	assert.True(t, policy.Renew(session, created.Add(3*time.Hour)))
+	assert.Equal(t, created.Add(7*time.Hour), session.ExpiresAt())
+
+	assert.True(t, policy.Renew(session, created.Add(9*time.Hour)))
+	assert.Equal(t, created.Add(10*time.Hour), session.ExpiresAt(), "renewal is capped")
+
+	assert.True(t, policy.Exceeded(session, created.Add(10*time.Hour)))
+}
+
+func TestSessionPolicyValidate(t *testing.T) {
+	policy := entities.DefaultSessionPolicy()
+	require.NoError(t, policy.Validate())
+
+	policy.IdleTimeout = policy.AbsoluteLifetime + time.Hour
+	assert.True(t, entities.IsValidationError(policy.Validate()))
+}
+
+func TestSessionRefreshTokenRotation(t *testing.T) {
+	session := entities.NewUserSession(
+		entities.UserID(1),
+		net.ParseIP("127.0.0.1"),
+		"test-agent",
+		entities.NewSessionDeviceInfo(),
+		entities.SessionDurationShort,
+	)
+	now := time.Now()
+
+	first := session.IssueRefreshToken(now.Add(time.Hour))
+
+	second, err := session.RotateRefreshToken(first, now.Add(time.Hour), now)
+	require.NoError(t, err)
+	assert.NotEqual(t, first, second)
+	assert.True(t, session.HasRefreshToken(first))
+
+	_, err = session.RotateRefreshToken(entities.NewRefreshToken(), now.Add(time.Hour), now)
+	require.ErrorIs(t, err, entities.ErrInvalidRefreshToken)
+
+	_, err = session.RotateRefreshToken(second, now.Add(time.Hour), now.Add(2*time.Hour))
+	require.ErrorIs(t, err, entities.ErrSessionExpired)
+
+	_, err = session.RotateRefreshToken(first, now.Add(time.Hour), now)
+	require.ErrorIs(t, err, entities.ErrRefreshTokenReused)
+	assert.False(t, session.IsActive(), "reuse revokes the session")
+
+	_, err = session.RotateRefreshToken(second, now.Add(time.Hour), now)
+	require.ErrorIs(t, err, entities.ErrInvalidRefreshToken)
+}
+
+func TestReconstructSession(t *testing.T) {
+	session := entities.NewUserSession(
+		entities.UserID(7),
+		net.ParseIP("10.0.0.1"),
+		"test-agent",
+		entities.NewSessionDeviceInfo(),
+		entities.SessionDurationShort,
+	)
+	session.SwitchOrganization(entities.OrganizationID(3))
+	first := session.IssueRefreshToken(time.Now().Add(time.Hour))
+
+	_, err := session.RotateRefreshToken(first, time.Now().Add(time.Hour), time.Now())
+	require.NoError(t, err)
+
+	record := session.Record()
+
+	restored, err := entities.ReconstructSession(record)
+	require.NoError(t, err)
+	assert.Equal(t, record, restored.Record())
+	assert.Equal(t, session.Token(), restored.Token())
+	assert.Equal(t, session.CreatedAt(), restored.CreatedAt())
+	assert.True(t, restored.HasRefreshToken(first))
+
+	orgID, ok := restored.OrganizationID()
+	require.True(t, ok)
+	assert.Equal(t, entities.OrganizationID(3), orgID)
+
+	session.SwitchOrganization(entities.OrganizationID(4))
+
+	orgID, _ = restored.OrganizationID()
+	assert.Equal(t, entities.OrganizationID(3), orgID, "the record does not share state with the session")
+
+	_, err = entities.ReconstructSession(entities.SessionRecord{ID: 1})
+	require.ErrorIs(t, err, entities.ErrInvalidSessionToken)
+}
@@ -0,0 +1,212 @@
+package unit
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/LarsArtmann/template-sqlc/pkg/sqlcconfig"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite golden files")
+
+// configLayers are the layers the config builder CLI ships with.
+var configLayers = os.DirFS(filepath.Join("..", "..", "..", "config", "internal"))
+
+func TestSQLCConfigGolden(t *testing.T) {
+	tests := []struct {
+		name      string
+		databases []string
+		profile   string
+	}{
+		{name: "sqlite", databases: []string{"sqlite"}},
+		{name: "all", databases: []string{"sqlite", "postgres", "mysql"}},
+		{name: "cockroachdb", databases: []string{"cockroachdb"}},
+		{name: "hobby", databases: []string{"sqlite"}, profile: "hobby"},
+		{name: "microservice", databases: []string{"postgres", "mysql"}, profile: "microservice"},
+		{name: "enterprise", databases: []string{"sqlite", "postgres", "mysql"}, profile: "enterprise"},
+		{name: "turso", databases: []string{"sqlite"}, profile: "turso"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := sqlcconfig.NewBuilder(configLayers).Databases(tt.databases...).Strict()
+			if tt.profile != "" {
+				builder.Profile(tt.profile)
+			}
+
+			config, err := builder.Build()
+			require.NoError(t, err)
+
+			data, err := config.Marshal()
+			require.NoError(t, err)
+
+			golden := filepath.Join("testdata", "sqlcconfig", tt.name+".golden.yaml")
+			if *updateGolden {
+				require.NoError(t, os.MkdirAll(filepath.Dir(golden), 0o750))
+				require.NoError(t, os.WriteFile(golden, data, 0o600))
+			}
+
+			want, err := os.ReadFile(golden)
+			require.NoError(t, err)
+			assert.Equal(t, string(want), string(data))
+		})
+	}
+}
+
+func TestSQLCConfigTypedLayers(t *testing.T) {
+	config, err := sqlcconfig.NewBuilder(configLayers).
+		Databases("sqlite").
+		Override("sqlite", sqlcconfig.Override{Column: "users.id", GoType: "github.com/google/uuid.UUID"}).
+		SQL(sqlcconfig.SQL{
+			Name:    "sqlite",
+			Queries: sqlcconfig.Paths{"../examples/sqlite/reports"},
+			Gen:     sqlcconfig.Gen{Go: &sqlcconfig.GenOptions{Package: "db"}},
+		}).
+		Build()
+	require.NoError(t, err)
+	require.Len(t, config.SQL, 1)
+
+	sql := config.SQL[0]
+	assert.Equal(t, sqlcconfig.EngineSQLite, sql.Engine)
+	assert.Equal(t, sqlcconfig.Paths{"../examples/sqlite/queries", "../examples/sqlite/reports"}, sql.Queries)
+	assert.Equal(t, "db", sql.Gen.Go.Package)
+	assert.Equal(t, "internal/db/sqlite", sql.Gen.Go.Out, "unset typed fields keep earlier values")
+	assert.Equal(t, "users.id", sql.Gen.Go.Overrides[len(sql.Gen.Go.Overrides)-1].Column)
+}
+
+func TestSQLCConfigValidation(t *testing.T) {
+	fsys := fstest.MapFS{
+		"base/common.yaml": {Data: []byte("version: \"2\"\n")},
+		"databases/broken.yaml": {Data: []byte(`
+sql:
+  - name: broken
+    engine: mysql
+    schema: schema.sql
+    gen:
+      go:
+        package: db
+        out: db
+        sql_package: pgx/v5
+        emit_interfce: true
+`)},
+	}
+
+	_, err := sqlcconfig.NewBuilder(fsys).Databases("broken").Build()
+
+	var errs sqlcconfig.ValidationErrors
+	require.True(t, errors.As(err, &errs), "got %v", err)
+	assert.Equal(t, sqlcconfig.ValidationErrors{
+		{Path: "sql[0]", Message: `missing required key "queries"`},
+		{Path: "sql[0].gen.go", Message: `unknown key "emit_interfce"; did you mean "emit_interface"?`},
+		{Path: "sql[0].gen.go.sql_package", Message: `"pgx/v5" requires engine "postgresql", got "mysql"`},
+	}, errs)
+}
+
+func TestSQLCConfigMigrate(t *testing.T) {
+	v2, report, err := sqlcconfig.Migrate([]byte(`
+version: "1"
+packages:
+  - name: db
+    path: internal/db
+    queries: sql/query
+    schema: sql/schema
+    engine: postgresql
+    emit_interface: true
+    emit_magic: true
+    overrides:
+      - postgres_type: uuid
+        go_type: github.com/google/uuid.UUID
+rename:
+  url: URL
+`))
+	require.NoError(t, err)
+
+	assert.Equal(t, `version: "2"
+sql:
+  - name: db
+    engine: postgresql
+    schema:
+      - sql/schema
+    queries:
+      - sql/query
+    gen:
+      go:
+        package: db
+        out: internal/db
+        emit_interface: true
+        overrides:
+          - db_type: uuid
+            go_type: github.com/google/uuid.UUID
+overrides:
+  go:
+    rename:
+      url: URL
+`, string(v2))
+	assert.Contains(t, report.Mapped, sqlcconfig.MigrationNote{From: "packages[0].path", To: "sql[0].gen.go.out"})
+	assert.Equal(t, []sqlcconfig.MigrationNote{
+		{From: "packages[0].emit_magic", Message: "unknown package option; dropped"},
+	}, report.Unmapped)
+
+	_, _, err = sqlcconfig.Migrate(v2)
+	assert.ErrorIs(t, err, sqlcconfig.ErrUnsupportedVersion)
+}
+
+func TestSQLCConfigBoundedContexts(t *testing.T) {
+	builder := sqlcconfig.NewBuilder(configLayers).
+		Databases("sqlite", "postgres").
+		Contexts(
+			sqlcconfig.BoundedContext{
+				Name:      "billing",
+				DependsOn: []string{"identity"},
+				Schema:    sqlcconfig.Paths{"sql/{engine}/billing"},
+				Queries:   sqlcconfig.Paths{"sql/{engine}/billing/queries"},
+				Engines:   []string{"postgres"},
+			},
+			sqlcconfig.BoundedContext{
+				Name:    "identity",
+				Schema:  sqlcconfig.Paths{"sql/{engine}/identity"},
+				Queries: sqlcconfig.Paths{"sql/{engine}/identity/queries"},
+			},
+		)
+
+	config, err := builder.Build()
+	require.NoError(t, err)
+
+	names := make([]string, len(config.SQL))
+	for i, sql := range config.SQL {
+		names[i] = sql.Name
+	}
+
+	assert.Equal(t, []string{"sqlite-identity", "postgres-identity", "postgres-billing"}, names)
+
+	billing := config.SQL[2]
+	assert.Equal(t, sqlcconfig.Paths{"sql/postgres/identity", "sql/postgres/billing"}, billing.Schema)
+	assert.Equal(t, "billing", billing.Gen.Go.Package)
+	assert.Equal(t, "internal/db/postgres/billing", billing.Gen.Go.Out)
+	assert.Equal(t, "pgx/v5", billing.Gen.Go.SQLPackage, "template options are kept")
+
+	require.NotNil(t, builder.Manifest())
+	assert.Equal(t, sqlcconfig.ManifestEntry{
+		Context:   "billing",
+		SQL:       "postgres-billing",
+		Engine:    "postgresql",
+		Package:   "billing",
+		Out:       "internal/db/postgres/billing",
+		DependsOn: []string{"identity"},
+	}, builder.Manifest().Contexts[2])
+
+	_, err = sqlcconfig.NewBuilder(configLayers).
+		Databases("sqlite").
+		Contexts(
+			sqlcconfig.BoundedContext{Name: "a", DependsOn: []string{"b"}, Queries: sqlcconfig.Paths{"a"}},
+			sqlcconfig.BoundedContext{Name: "b", DependsOn: []string{"a"}, Queries: sqlcconfig.Paths{"b"}},
+		).
+		Build()
+	assert.ErrorIs(t, err, sqlcconfig.ErrContextCycle)
+}
@@ -0,0 +1,129 @@
+package unit
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/LarsArtmann/template-sqlc/pkg/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateBlobKey(t *testing.T) {
+	for _, key := range []string{"a.png", "avatars/1/0f3c.jpg", "a_b-c/D.E"} {
+		require.NoError(t, storage.ValidateKey(key), key)
+	}
+
+	for _, key := range []string{"", "/a", "a/", "a//b", "../a", "a/./b", "a b", "a\\b", strings.Repeat("a", 1025)} {
+		require.ErrorIs(t, storage.ValidateKey(key), storage.ErrInvalidKey, key)
+	}
+}
+
+// testBlobStore runs the behavior every BlobStore shares against store.
+func testBlobStore(t *testing.T, store storage.BlobStore) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	require.NoError(t, store.Put(ctx, "avatars/1/a.png", strings.NewReader("first"), "image/png"))
+	require.NoError(t, store.Put(ctx, "avatars/1/a.png", strings.NewReader("second"), "image/png"))
+
+	body, err := store.Get(ctx, "avatars/1/a.png")
+	require.NoError(t, err)
+
+	data, err := io.ReadAll(body)
+	require.NoError(t, err)
+	require.NoError(t, body.Close())
+	assert.Equal(t, "second", string(data), "put replaces blobs")
+
+	require.NoError(t, store.Delete(ctx, "avatars/1/a.png"))
+	require.NoError(t, store.Delete(ctx, "avatars/1/a.png"), "deleting a missing blob is not an error")
+
+	_, err = store.Get(ctx, "avatars/1/a.png")
+	require.ErrorIs(t, err, storage.ErrNotFound)
+
+	err = store.Put(ctx, "../escape.png", strings.NewReader("x"), "image/png")
+	require.ErrorIs(t, err, storage.ErrInvalidKey)
+}
+
+func TestLocalBlobStore(t *testing.T) {
+	store, err := storage.NewLocal(t.TempDir(), "https://example.com/files/")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = store.Close() })
+
+	testBlobStore(t, store)
+
+	assert.Equal(t, "https://example.com/files/avatars/1/a.png", store.URL("avatars/1/a.png"))
+
+	require.NoError(t, store.Put(context.Background(), "b/c.txt", strings.NewReader("served"), "text/plain"))
+
+	rec := httptest.NewRecorder()
+	http.FileServerFS(store.FS()).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/b/c.txt", nil))
+	assert.Equal(t, "served", rec.Body.String())
+}
+
+func TestMemoryBlobStore(t *testing.T) {
+	store := storage.NewMemory("https://cdn.example.com")
+
+	testBlobStore(t, store)
+
+	require.NoError(t, store.Put(context.Background(), "a.png", strings.NewReader("x"), "image/png"))
+	assert.Equal(t, []string{"a.png"}, store.Keys())
+
+	contentType, ok := store.ContentType("a.png")
+	assert.True(t, ok)
+	assert.Equal(t, "image/png", contentType)
+}
+
+// fakeGCS serves the object routes of the Google Cloud Storage JSON API
+// that storage.GCS uses from memory.
+type fakeGCS struct {
+	mu      sync.Mutex
+	objects map[string]string
+}
+
+func (f *fakeGCS) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/upload/storage/v1/b/bucket/o":
+		data, _ := io.ReadAll(r.Body)
+		f.objects[r.URL.Query().Get("name")] = string(data)
+	case strings.HasPrefix(r.URL.Path, "/storage/v1/b/bucket/o/"):
+		name := strings.TrimPrefix(r.URL.Path, "/storage/v1/b/bucket/o/")
+
+		data, ok := f.objects[name]
+		if !ok {
+			http.NotFound(w, r)
+
+			return
+		}
+
+		if r.Method == http.MethodDelete {
+			delete(f.objects, name)
+
+			return
+		}
+
+		_, _ = io.WriteString(w, data)
+	default:
+		http.Error(w, "unexpected request", http.StatusBadRequest)
+	}
+}
+
+func TestGCSBlobStore(t *testing.T) {
+	server := httptest.NewServer(&fakeGCS{objects: make(map[string]string)})
+	t.Cleanup(server.Close)
+
+	store := storage.NewGCS(server.Client(), "bucket", storage.WithGCSEndpoint(server.URL))
+
+	testBlobStore(t, store)
+
+	assert.Equal(t, "https://storage.googleapis.com/bucket/avatars/1/a.png", store.URL("avatars/1/a.png"))
+}
@@ -0,0 +1,155 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/memory"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/services"
+	"github.com/LarsArtmann/template-sqlc/internal/validation"
+	"github.com/LarsArtmann/template-sqlc/pkg/fixtures"
+	"github.com/LarsArtmann/template-sqlc/pkg/passwords"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var errFlagsDown = errors.New("flags down")
+
+// flakyFeatureFlags fails to list its flags while down is set.
+type flakyFeatureFlags struct {
+	repositories.FeatureFlagRepository
+
+	down bool
+}
+
+func (f *flakyFeatureFlags) List(ctx context.Context) ([]*entities.FeatureFlag, error) {
+	if f.down {
+		return nil, errFlagsDown
+	}
+
+	return f.FeatureFlagRepository.List(ctx)
+}
+
+// storeFlag stores the flag of sw behind the back of any switchboard.
+func storeFlag(t *testing.T, flags repositories.FeatureFlagRepository, sw entities.ServiceSwitch, on bool) {
+	t.Helper()
+
+	flag, err := entities.NewFeatureFlag(sw.String(), on, time.Now())
+	require.NoError(t, err)
+	require.NoError(t, flags.Set(context.Background(), flag))
+}
+
+func TestSwitchboardDefaultsAndFlags(t *testing.T) {
+	ctx := context.Background()
+	flags := &flakyFeatureFlags{FeatureFlagRepository: memory.NewFeatureFlagRepository()}
+
+	board := services.NewSwitchboard(
+		services.WithSwitchDefaults(map[entities.ServiceSwitch]bool{entities.SwitchRegistrationDisabled: true}),
+		services.WithFeatureFlags(flags, time.Nanosecond),
+	)
+
+	require.NoError(t, board.Check(ctx, entities.SwitchReadOnly, entities.SwitchLoginDisabled))
+	require.ErrorIs(t, board.Check(ctx, entities.SwitchReadOnly, entities.SwitchRegistrationDisabled),
+		entities.ErrRegistrationDisabled)
+
+	// Flags override the defaults both ways.
+	storeFlag(t, flags, entities.SwitchReadOnly, true)
+	storeFlag(t, flags, entities.SwitchRegistrationDisabled, false)
+	storeFlag(t, flags, "beta.search", true)
+
+	assert.Equal(t, map[entities.ServiceSwitch]bool{
+		entities.SwitchReadOnly:             true,
+		entities.SwitchRegistrationDisabled: false,
+		entities.SwitchLoginDisabled:        false,
+	}, board.State(ctx), "unknown flags are ignored")
+
+	// An outage of the flags keeps the last state.
+	flags.down = true
+	require.ErrorIs(t, board.Check(ctx, entities.SwitchReadOnly), entities.ErrReadOnlyMode)
+
+	flags.down = false
+	require.NoError(t, board.Set(ctx, entities.SwitchReadOnly, false))
+	require.NoError(t, board.Check(ctx, entities.SwitchReadOnly))
+
+	err := board.Set(ctx, "beta.search", true)
+	require.ErrorIs(t, err, entities.ErrInvalidServiceSwitch)
+}
+
+func TestSwitchboardCachesFlags(t *testing.T) {
+	ctx := context.Background()
+	flags := memory.NewFeatureFlagRepository()
+	board := services.NewSwitchboard(services.WithFeatureFlags(flags, time.Hour))
+
+	require.NoError(t, board.Check(ctx, entities.SwitchLoginDisabled))
+
+	// Other instances' changes apply after the refresh interval, and this
+	// instance's changes at once.
+	storeFlag(t, flags, entities.SwitchLoginDisabled, true)
+	require.NoError(t, board.Check(ctx, entities.SwitchLoginDisabled))
+
+	require.NoError(t, board.Set(ctx, entities.SwitchReadOnly, true))
+	require.ErrorIs(t, board.Check(ctx, entities.SwitchReadOnly), entities.ErrReadOnlyMode)
+
+	unshared := services.NewSwitchboard()
+	err := unshared.Set(ctx, entities.SwitchReadOnly, true)
+	require.ErrorIs(t, err, services.ErrFeatureFlagsUnavailable)
+}
+
+func TestUserServiceEnforcesSwitches(t *testing.T) {
+	ctx := context.Background()
+	users := memory.NewUserRepository()
+
+	hasher := passwords.NewBcryptHasher(bcrypt.MinCost)
+	hash, err := hasher.Hash("secret")
+	require.NoError(t, err)
+
+	ada := fixtures.User().Named("ada").WithPassword(entities.PasswordHash(hash)).MustBuild()
+	require.NoError(t, users.Create(ctx, ada))
+
+	flags := memory.NewFeatureFlagRepository()
+	board := services.NewSwitchboard(services.WithFeatureFlags(flags, time.Hour))
+	service := services.NewUserService(users, memory.NewSessionRepository(), events.NewInMemoryEventPublisher(),
+		validation.NewUserValidator(),
+		services.WithPasswordHasher(hasher),
+		services.WithSwitchboard(board),
+	)
+
+	require.NoError(t, board.Set(ctx, entities.SwitchRegistrationDisabled, true))
+
+	_, err = service.CreateUser(ctx, createUserRequest(""))
+	require.ErrorIs(t, err, entities.ErrRegistrationDisabled)
+	assert.True(t, entities.IsUnavailableError(err))
+
+	_, err = service.AuthenticateUser(ctx, "ada@example.com", "secret", "192.0.2.1", "firefox")
+	require.NoError(t, err)
+
+	require.NoError(t, board.Set(ctx, entities.SwitchRegistrationDisabled, false))
+	require.NoError(t, board.Set(ctx, entities.SwitchReadOnly, true))
+
+	_, err = service.CreateUser(ctx, createUserRequest(""))
+	require.ErrorIs(t, err, entities.ErrReadOnlyMode)
+
+	_, err = service.ChangeUserRole(ctx, ada.ID(), entities.UserRoleAdmin, "")
+	require.ErrorIs(t, err, entities.ErrReadOnlyMode)
+	require.ErrorIs(t, service.DeleteUser(ctx, ada.ID()), entities.ErrReadOnlyMode)
+
+	_, err = service.AuthenticateUser(ctx, "ada@example.com", "secret", "192.0.2.1", "firefox")
+	require.NoError(t, err, "signing in does not change the user")
+
+	require.NoError(t, board.Set(ctx, entities.SwitchLoginDisabled, true))
+
+	_, err = service.AuthenticateUser(ctx, "ada@example.com", "secret", "192.0.2.1", "firefox")
+	require.ErrorIs(t, err, entities.ErrLoginDisabled)
+
+	require.NoError(t, board.Set(ctx, entities.SwitchReadOnly, false))
+
+	created, err := service.CreateUser(ctx, createUserRequest(""))
+	require.NoError(t, err)
+	assert.Equal(t, "retry", created.Username().String())
+}
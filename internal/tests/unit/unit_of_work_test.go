@@ -0,0 +1,242 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingUsers stages created users and assigns IDs like the database would.
+type recordingUsers struct {
+	repositories.UserRepository
+
+	created []*entities.User
+}
+
+func (r *recordingUsers) Create(_ context.Context, user *entities.User) error {
+	user.SetID(entities.UserID(len(r.created) + 1))
+	r.created = append(r.created, user)
+
+	return nil
+}
+
+// recordingSessions stages created sessions and can be told to fail.
+type recordingSessions struct {
+	repositories.SessionRepository
+
+	created []*entities.UserSession
+	fail    error
+}
+
+func (r *recordingSessions) Create(_ context.Context, session *entities.UserSession) error {
+	if r.fail != nil {
+		return r.fail
+	}
+
+	r.created = append(r.created, session)
+
+	return nil
+}
+
+// fakeTransactions runs operations against staged repositories and records
+// whether the transaction was committed or rolled back.
+type fakeTransactions struct {
+	users      *recordingUsers
+	sessions   *recordingSessions
+	audit      *memoryAudit
+	committed  bool
+	rolledBack bool
+}
+
+func (f *fakeTransactions) BeginTx(context.Context) (repositories.Transaction, error) {
+	return f, nil
+}
+
+func (f *fakeTransactions) RunInTransaction(
+	ctx context.Context,
+	fn func(ctx context.Context, tx repositories.Transaction) error,
+) error {
+	err := fn(ctx, f)
+	if err != nil {
+		_ = f.Rollback()
+
+		return err
+	}
+
+	return f.Commit()
+}
+
+func (f *fakeTransactions) Commit() error {
+	f.committed = true
+
+	return nil
+}
+
+func (f *fakeTransactions) Rollback() error {
+	f.rolledBack = true
+
+	return nil
+}
+
+func (f *fakeTransactions) UserRepository() repositories.UserRepository {
+	return f.users
+}
+
+func (f *fakeTransactions) SessionRepository() repositories.SessionRepository {
+	return f.sessions
+}
+
+func (f *fakeTransactions) OrganizationRepository() repositories.OrganizationRepository {
+	return nil
+}
+
+func (f *fakeTransactions) MembershipRepository() repositories.MembershipRepository {
+	return nil
+}
+
+func (f *fakeTransactions) IdentityRepository() repositories.IdentityRepository {
+	return nil
+}
+
+func (f *fakeTransactions) AuditRepository() repositories.AuditRepository {
+	if f.audit == nil {
+		return nil
+	}
+
+	return f.audit
+}
+
+func (f *fakeTransactions) UserHistoryRepository() repositories.UserHistoryRepository {
+	return nil
+}
+
+func (f *fakeTransactions) IdentityChangeRepository() repositories.IdentityChangeRepository {
+	return nil
+}
+
+func (f *fakeTransactions) ModerationRepository() repositories.ModerationRepository {
+	return nil
+}
+
+func (f *fakeTransactions) AccountDeletionRepository() repositories.AccountDeletionRepository {
+	return nil
+}
+
+func (f *fakeTransactions) LoginHistoryRepository() repositories.LoginHistoryRepository {
+	return nil
+}
+
+func (f *fakeTransactions) ActivityRepository() repositories.ActivityRepository {
+	return nil
+}
+
+func (f *fakeTransactions) UserPreferenceRepository() repositories.UserPreferenceRepository {
+	return nil
+}
+
+func (f *fakeTransactions) UserEventStore() repositories.UserEventStore {
+	return nil
+}
+
+// signUp builds a unit of work that creates a user and a session for it.
+func signUp(
+	t *testing.T,
+	txRepo *fakeTransactions,
+	publisher events.EventPublisher,
+) *services.UnitOfWork {
+	t.Helper()
+
+	user := newTestUser(t, entities.UserRoleUser)
+
+	return services.NewUnitOfWork(txRepo, publisher).
+		CreateUser(user).
+		Add(func(ctx context.Context, tx repositories.Transaction) error {
+			session := entities.NewUserSession(
+				user.ID(),
+				net.ParseIP("127.0.0.1"),
+				"test-agent",
+				entities.NewSessionDeviceInfo(),
+				entities.SessionDurationShort,
+			)
+
+			return tx.SessionRepository().Create(ctx, session)
+		}).
+		PublishAfterCommit(func() *events.UserEvent {
+			return events.UserCreated(
+				user.ID(),
+				user.Email().String(),
+				user.Username().String(),
+				user.FirstName().String(),
+				user.LastName().String(),
+				user.Role().String(),
+				user.Status().String(),
+			)
+		})
+}
+
+func TestUnitOfWorkCommit(t *testing.T) {
+	txRepo := &fakeTransactions{users: &recordingUsers{}, sessions: &recordingSessions{}}
+	publisher := events.NewInMemoryEventPublisher()
+	uow := signUp(t, txRepo, publisher)
+
+	require.NoError(t, uow.Commit(context.Background()))
+
+	assert.True(t, txRepo.committed)
+	require.Len(t, txRepo.sessions.created, 1)
+	assert.Equal(t, entities.UserID(1), txRepo.sessions.created[0].UserID())
+	require.Len(t, publisher.Events(), 1)
+	assert.Equal(t, entities.UserID(1), publisher.Events()[0].UserID)
+
+	require.ErrorIs(t, uow.Commit(context.Background()), services.ErrUnitOfWorkCompleted)
+}
+
+func TestUnitOfWorkRollback(t *testing.T) {
+	failure := errors.New("session store down")
+	txRepo := &fakeTransactions{
+		users:    &recordingUsers{},
+		sessions: &recordingSessions{fail: failure},
+	}
+	publisher := events.NewInMemoryEventPublisher()
+
+	err := signUp(t, txRepo, publisher).Commit(context.Background())
+
+	require.ErrorIs(t, err, failure)
+	assert.True(t, txRepo.rolledBack)
+	assert.False(t, txRepo.committed)
+	assert.Empty(t, publisher.Events())
+}
+
+func TestUnitOfWorkRecordsAudit(t *testing.T) {
+	txRepo := &fakeTransactions{users: &recordingUsers{}, sessions: &recordingSessions{}, audit: &memoryAudit{}}
+	user := newTestUser(t, entities.UserRoleUser)
+
+	err := services.NewUnitOfWork(txRepo, nil).
+		CreateUser(user).
+		RecordAudit(func() *entities.AuditLog {
+			return entities.NewAuditLog(0, user.ID(), entities.AuditActionUserCreate, nil, "")
+		}).
+		Commit(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, txRepo.audit.entries, 1)
+	assert.Equal(t, entities.UserID(1), txRepo.audit.entries[0].UserID, "the entry sees the ID of the created user")
+
+	err = services.NewUnitOfWork(&fakeTransactions{users: &recordingUsers{}}, nil).
+		RecordAudit(func() *entities.AuditLog { return nil }).
+		Commit(context.Background())
+	require.ErrorIs(t, err, services.ErrAuditUnavailable)
+}
+
+func TestUnitOfWorkWithoutTransactions(t *testing.T) {
+	uow := services.NewUnitOfWork(nil, events.NewInMemoryEventPublisher())
+
+	require.ErrorIs(t, uow.Commit(context.Background()), services.ErrTransactionsUnavailable)
+}
@@ -0,0 +1,115 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/services"
+	"github.com/LarsArtmann/template-sqlc/pkg/fixtures"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errTransactionAborted = errors.New("current transaction is aborted")
+
+// abortingUsers stages the updates of a transaction and, like PostgreSQL,
+// fails every statement after the first failed one.
+type abortingUsers struct {
+	repositories.UserRepository
+
+	aborted bool
+	staged  []*entities.User
+}
+
+func (r *abortingUsers) GetByID(ctx context.Context, id entities.UserID) (*entities.User, error) {
+	if r.aborted {
+		return nil, errTransactionAborted
+	}
+
+	user, err := r.UserRepository.GetByID(ctx, id)
+	r.aborted = err != nil
+
+	return user, err
+}
+
+func (r *abortingUsers) Update(_ context.Context, user *entities.User) error {
+	if r.aborted {
+		return errTransactionAborted
+	}
+
+	r.staged = append(r.staged, user)
+
+	return nil
+}
+
+// abortingTransaction is a transaction whose users are abortingUsers.
+type abortingTransaction struct {
+	*memoryTransactions
+
+	users *abortingUsers
+}
+
+func (t *abortingTransaction) UserRepository() repositories.UserRepository {
+	return t.users
+}
+
+// abortingTransactions applies the staged updates of committed transactions
+// and discards those of rolled back ones.
+type abortingTransactions struct {
+	*memoryTransactions
+
+	runs int
+}
+
+func (a *abortingTransactions) RunInTransaction(
+	ctx context.Context,
+	fn func(ctx context.Context, tx repositories.Transaction) error,
+) error {
+	a.runs++
+
+	tx := &abortingTransaction{
+		memoryTransactions: a.memoryTransactions,
+		users:              &abortingUsers{UserRepository: a.userRepo},
+	}
+
+	err := fn(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	for _, user := range tx.users.staged {
+		err = a.userRepo.Update(ctx, user)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func TestBulkChangeRoleRetriesAbortedChunks(t *testing.T) {
+	f := newServiceFixture()
+	ada := f.createUser(t, fixtures.User().Named("ada").MustBuild())
+	grace := f.createUser(t, fixtures.User().Named("grace").MustBuild())
+	missing := grace.ID() + 1000
+
+	txRepo := &abortingTransactions{memoryTransactions: f.tx}
+	f.start(services.WithTransactions(txRepo))
+
+	result, err := f.service.BulkChangeRole(context.Background(),
+		[]entities.UserID{ada.ID(), missing, grace.ID()}, entities.UserRoleModerator, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []entities.UserID{ada.ID(), grace.ID()}, result.Succeeded)
+	require.Len(t, result.Failed, 1)
+	assert.Equal(t, missing, result.Failed[0].UserID)
+	assert.Equal(t, 4, txRepo.runs, "the chunk and then each of its users")
+
+	for _, user := range []*entities.User{ada, grace} {
+		stored, err := f.users.GetByID(context.Background(), user.ID())
+		require.NoError(t, err)
+		assert.Equal(t, entities.UserRoleModerator, stored.Role())
+	}
+}
@@ -0,0 +1,243 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/memory"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// userDataFixture is a user with a session, an event, an audit entry, a
+// login attempt, an identity change, a suspension with an appeal, an
+// activity entry, a preference and an event stream.
+type userDataFixture struct {
+	*serviceFixture
+
+	logins      *memory.LoginHistoryRepository
+	changes     *memory.IdentityChangeRepository
+	moderation  *memory.ModerationRepository
+	activity    *memory.ActivityRepository
+	preferences *memory.UserPreferenceRepository
+	streams     *memory.UserEventStore
+	user        *entities.User
+}
+
+func newUserDataFixture(t *testing.T) *userDataFixture {
+	t.Helper()
+
+	ctx := context.Background()
+	f := &userDataFixture{
+		serviceFixture: newServiceFixture(),
+		logins:         memory.NewLoginHistoryRepository(),
+		changes:        memory.NewIdentityChangeRepository(),
+		moderation:     memory.NewModerationRepository(),
+		activity:       memory.NewActivityRepository(),
+		preferences:    memory.NewUserPreferenceRepository(),
+		streams:        memory.NewUserEventStore(),
+	}
+
+	f.user = f.createUser(t, newTestUser(t, entities.UserRoleUser))
+	require.NoError(t, f.sessions.Create(ctx, entities.NewUserSession(
+		f.user.ID(),
+		net.ParseIP("192.0.2.7"),
+		"test-agent",
+		entities.NewSessionDeviceInfo(),
+		entities.SessionDurationShort,
+	)))
+	require.NoError(t, f.audit.Record(ctx, entities.NewAuditLog(
+		f.user.ID(),
+		f.user.ID(),
+		entities.AuditActionUserUpdate,
+		[]entities.FieldChange{{Field: "first_name", Old: "Jon", New: "John"}},
+		"192.0.2.7",
+	)))
+
+	f.seedAccountHistory(t)
+
+	store := events.NewInMemoryEventStore()
+	require.NoError(t, store.Append(ctx,
+		events.UserLoggedIn(f.user.ID(), "192.0.2.7", "test-agent", "desktop"),
+		events.UserLoggedIn(f.user.ID()+1, "192.0.2.8", "test-agent", "desktop"),
+	))
+
+	f.tx.loginRepo = f.logins
+	f.tx.changeRepo = f.changes
+	f.tx.moderationRepo = f.moderation
+	f.tx.activityRepo = f.activity
+	f.tx.preferenceRepo = f.preferences
+	f.tx.streamStore = f.streams
+	f.start(
+		services.WithEventStore(store),
+		services.WithLoginHistory(f.logins),
+		services.WithIdentityChanges(f.changes, entities.IdentityChangeGracePeriod),
+		services.WithModeration(f.moderation),
+		services.WithActivityExport(f.activity),
+		services.WithPreferenceExport(f.preferences),
+		services.WithUserStreamExport(f.streams),
+		services.WithTransactions(f.tx),
+	)
+
+	return f
+}
+
+// seedAccountHistory stores the login history, identity change, moderation,
+// activity, preferences and event stream of the fixture user.
+func (f *userDataFixture) seedAccountHistory(t *testing.T) {
+	t.Helper()
+
+	ctx := context.Background()
+	userID := f.user.ID()
+
+	require.NoError(t, f.logins.Record(ctx,
+		entities.NewLoginAttempt(userID, "192.0.2.7", "test-agent", "password", "")))
+
+	renamed, err := entities.NewUsername("renamed")
+	require.NoError(t, err)
+	change, err := entities.NewUsernameChange(userID, f.user.Username(), renamed, time.Hour)
+	require.NoError(t, err)
+	require.NoError(t, f.changes.Create(ctx, change))
+
+	suspension, err := entities.NewSuspension(userID, userID+1, "spam", time.Hour)
+	require.NoError(t, err)
+	require.NoError(t, f.moderation.CreateAction(ctx, suspension))
+	appeal, err := entities.NewAppeal(suspension, "it was not spam")
+	require.NoError(t, err)
+	require.NoError(t, f.moderation.CreateAppeal(ctx, appeal))
+
+	entry, err := entities.NewActivityEntry(userID, 1, "user.login", entities.ActivityKindSignIn,
+		"Signed in", 0, time.Now())
+	require.NoError(t, err)
+	require.NoError(t, f.activity.Record(ctx, entry))
+
+	_, err = f.preferences.Patch(ctx, userID, entities.PreferencePatch{
+		Set: map[entities.PreferenceKey]any{entities.PreferenceTheme: "dark"},
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, f.streams.Append(ctx, userID, 0,
+		[]*entities.UserStreamEvent{entities.NewUserCreatedEvent(f.user)}))
+}
+
+func TestExportUserData(t *testing.T) {
+	ctx := context.Background()
+	f := newUserDataFixture(t)
+
+	export, err := f.service.ExportUserData(ctx, f.user.ID())
+	require.NoError(t, err)
+
+	assert.Equal(t, "test@example.com", export.Profile.Email)
+	require.Len(t, export.Sessions, 1)
+	assert.Equal(t, "192.0.2.7", export.Sessions[0].IPAddress)
+	require.Len(t, export.Events, 1)
+	assert.Equal(t, f.user.ID(), export.Events[0].UserID)
+	require.Len(t, export.AuditLog, 1)
+	assert.Equal(t, "first_name", export.AuditLog[0].Changes[0].Field)
+	require.Len(t, export.LoginHistory, 1)
+	assert.Equal(t, "password", export.LoginHistory[0].Method)
+	require.Len(t, export.IdentityChanges, 1)
+	assert.Equal(t, "renamed", export.IdentityChanges[0].NewValue)
+	require.Len(t, export.Moderation.Actions, 1)
+	assert.Equal(t, "spam", export.Moderation.Actions[0].Reason)
+	require.Len(t, export.Moderation.Appeals, 1)
+	assert.Equal(t, "it was not spam", export.Moderation.Appeals[0].Statement)
+	require.Len(t, export.Activity, 1)
+	assert.Equal(t, "Signed in", export.Activity[0].Summary)
+	assert.Equal(t, "dark", export.Preferences[string(entities.PreferenceTheme)])
+	require.Len(t, export.Stream, 1)
+	assert.Nil(t, export.Stream[0].Data.Password, "password hashes are not exported")
+
+	archive, err := json.Marshal(export)
+	require.NoError(t, err)
+	assert.Contains(t, string(archive), `"email":"test@example.com"`)
+	assert.NotContains(t, string(archive), f.user.PasswordHash().String())
+
+	published := f.publisher.Events()
+	require.NotEmpty(t, published)
+	assert.Equal(t, events.EventUserDataExported, published[len(published)-1].Type)
+}
+
+func TestEraseUser(t *testing.T) {
+	ctx := context.Background()
+	f := newUserDataFixture(t)
+
+	require.NoError(t, f.service.DeleteUser(ctx, f.user.ID()))
+	require.NoError(t, f.service.EraseUser(ctx, f.user.ID()))
+
+	_, err := f.users.GetByID(ctx, f.user.ID())
+	require.ErrorIs(t, err, entities.ErrUserNotFound, "erased users stay deleted")
+
+	require.NoError(t, f.users.Restore(ctx, f.user.ID()))
+	erased, err := f.users.GetByID(ctx, f.user.ID())
+	require.NoError(t, err)
+	assert.NotEqual(t, f.user.Email(), erased.Email())
+	assert.NotEqual(t, f.user.Username(), erased.Username())
+	assert.Equal(t, entities.UserStatusInactive, erased.Status())
+
+	sessions, err := f.sessions.GetByUserID(ctx, f.user.ID(), false)
+	require.NoError(t, err)
+	assert.Empty(t, sessions)
+
+	for _, entry := range f.audit.entries {
+		assert.Empty(t, entry.IPAddress, "audit action=%v", entry.Action)
+	}
+
+	f.assertAccountHistoryErased(t)
+
+	published := f.publisher.Events()
+	require.NotEmpty(t, published)
+	assert.Equal(t, events.EventUserErased, published[len(published)-1].Type)
+}
+
+// assertAccountHistoryErased checks that the login history, identity
+// changes, moderation, activity, preferences and event stream of the
+// fixture user are gone.
+func (f *userDataFixture) assertAccountHistoryErased(t *testing.T) {
+	t.Helper()
+
+	ctx := context.Background()
+	userID := f.user.ID()
+
+	attempts, err := f.logins.ListByUser(ctx, userID, 10)
+	require.NoError(t, err)
+	assert.Empty(t, attempts, "login history")
+
+	changes, err := f.changes.ListByUser(ctx, userID)
+	require.NoError(t, err)
+	assert.Empty(t, changes, "identity changes")
+
+	actions, err := f.moderation.ListActionsByUser(ctx, userID)
+	require.NoError(t, err)
+	assert.Empty(t, actions, "moderation actions")
+
+	appeals, err := f.moderation.ListAppealsByUser(ctx, userID)
+	require.NoError(t, err)
+	assert.Empty(t, appeals, "appeals")
+
+	page, err := f.activity.ListPage(ctx, userID, "", 10)
+	require.NoError(t, err)
+	assert.Empty(t, page.Entries, "activity")
+
+	prefs, err := f.preferences.Get(ctx, userID)
+	require.NoError(t, err)
+	assert.Empty(t, prefs.Values, "preferences")
+
+	stream, err := f.streams.Load(ctx, userID, 0)
+	require.NoError(t, err)
+	assert.Empty(t, stream, "event stream")
+}
+
+func TestEraseUserRequiresTransactions(t *testing.T) {
+	f := newServiceFixture()
+	f.start()
+
+	err := f.service.EraseUser(context.Background(), 1)
+	require.ErrorIs(t, err, services.ErrTransactionsUnavailable)
+}
@@ -0,0 +1,77 @@
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// snapshotHistory serves snapshots from a fixed, oldest-first timeline.
+type snapshotHistory struct {
+	versions []*entities.UserSnapshot
+}
+
+func (h snapshotHistory) GetUserAsOf(
+	_ context.Context,
+	_ entities.UserID,
+	at time.Time,
+) (*entities.UserSnapshot, error) {
+	for _, version := range h.versions {
+		if !at.Before(version.ValidFrom) && (version.ValidTo == nil || at.Before(*version.ValidTo)) {
+			return version, nil
+		}
+	}
+
+	return nil, entities.ErrUserNotFound
+}
+
+func (h snapshotHistory) ListVersions(
+	context.Context,
+	entities.UserID,
+	int,
+) ([]*entities.UserSnapshot, error) {
+	return h.versions, nil
+}
+
+func (h snapshotHistory) DeleteVersions(context.Context, entities.UserID) (int64, error) {
+	return int64(len(h.versions)), nil
+}
+
+func TestUserHistoryDiffBetween(t *testing.T) {
+	ctx := context.Background()
+	changedAt := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	original := entities.SnapshotOf(newTestUser(t, entities.UserRoleUser))
+	original.Operation = entities.HistoryOperationUpdate
+	original.ValidFrom = changedAt.AddDate(0, -1, 0)
+	original.ValidTo = &changedAt
+
+	current := *original
+	current.Operation = entities.HistoryOperationCurrent
+	current.Role = entities.UserRoleModerator
+	current.ValidFrom = changedAt
+	current.ValidTo = nil
+
+	service := services.NewUserHistoryService(snapshotHistory{
+		versions: []*entities.UserSnapshot{original, &current},
+	})
+
+	lastMonth, err := service.GetUserAsOf(ctx, original.UserID, changedAt.Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, entities.UserRoleUser, lastMonth.Role)
+	assert.False(t, lastMonth.IsCurrent())
+
+	changes, err := service.DiffBetween(ctx, original.UserID, changedAt.Add(-time.Hour), changedAt)
+	require.NoError(t, err)
+	assert.Equal(t, []entities.FieldChange{
+		{Field: "role", Old: "user", New: "moderator"},
+	}, changes)
+
+	_, err = service.DiffBetween(ctx, original.UserID, changedAt, changedAt.Add(-time.Hour))
+	assert.True(t, entities.IsValidationError(err))
+}
@@ -0,0 +1,130 @@
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/memory"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreferenceKeyIsValid(t *testing.T) {
+	assert.True(t, entities.PreferenceEmailDigest.IsValid())
+	assert.Equal(t, "notifications", entities.PreferenceEmailDigest.Namespace())
+
+	for _, key := range []entities.PreferenceKey{"theme", "ui.", ".theme", "ui..theme", "UI.theme", `ui."theme`} {
+		assert.False(t, key.IsValid(), key)
+	}
+}
+
+func TestPreferenceSchemaValidate(t *testing.T) {
+	schema := entities.DefaultPreferenceSchema()
+
+	tests := []struct {
+		name  string
+		set   map[entities.PreferenceKey]any
+		unset []entities.PreferenceKey
+		err   error
+	}{
+		{"unknown key", map[entities.PreferenceKey]any{"ui.font": "serif"}, nil, entities.ErrUnknownPreference},
+		{"unknown unset", nil, []entities.PreferenceKey{"ui.font"}, entities.ErrUnknownPreference},
+		{
+			"enum outside allowed",
+			map[entities.PreferenceKey]any{entities.PreferenceTheme: "blue"},
+			nil,
+			entities.ErrInvalidPreferenceValue,
+		},
+		{
+			"int out of range",
+			map[entities.PreferenceKey]any{entities.PreferencePageSize: 1000},
+			nil,
+			entities.ErrInvalidPreferenceValue,
+		},
+		{
+			"fractional int",
+			map[entities.PreferenceKey]any{entities.PreferencePageSize: 12.5},
+			nil,
+			entities.ErrInvalidPreferenceValue,
+		},
+		{
+			"wrong type",
+			map[entities.PreferenceKey]any{entities.PreferenceNotificationSounds: "yes"},
+			nil,
+			entities.ErrInvalidPreferenceValue,
+		},
+		{
+			"set and unset",
+			map[entities.PreferenceKey]any{entities.PreferenceTheme: "dark"},
+			[]entities.PreferenceKey{entities.PreferenceTheme},
+			entities.ErrInvalidPreferenceValue,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := schema.Validate(entities.PreferencePatch{Set: tt.set, Unset: tt.unset})
+			require.ErrorIs(t, err, tt.err)
+		})
+	}
+
+	// JSON numbers decode as float64 and are normalized to int.
+	patch, err := schema.Validate(entities.PreferencePatch{
+		Set: map[entities.PreferenceKey]any{entities.PreferencePageSize: float64(40)},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 40, patch.Set[entities.PreferencePageSize])
+}
+
+func TestUserPreferencesGetters(t *testing.T) {
+	prefs := entities.NewUserPreferences(1, map[entities.PreferenceKey]any{
+		entities.PreferenceLocale:   "fr",
+		entities.PreferencePageSize: float64(75),
+		// A value that no longer matches its definition reads as the default.
+		entities.PreferenceTheme: "sepia",
+	}, time.Now())
+
+	assert.Equal(t, "fr", prefs.Locale())
+	assert.Equal(t, 75, prefs.Int(entities.PreferencePageSize))
+	assert.Equal(t, "system", prefs.Theme())
+	assert.Equal(t, "off", prefs.EmailDigest())
+	assert.True(t, prefs.Bool(entities.PreferenceNotificationSounds))
+	assert.Nil(t, prefs.Value("ui.font"))
+}
+
+func TestUserPreferenceServiceUpdate(t *testing.T) {
+	ctx := context.Background()
+	service := services.NewUserPreferenceService(memory.NewUserPreferenceRepository(),
+		services.WithPreferenceDefinitions(entities.PreferenceDefinition{
+			Key:     "ui.font",
+			Type:    entities.PreferenceTypeEnum,
+			Default: "sans",
+			Allowed: []string{"sans", "serif"},
+		}),
+	)
+
+	prefs, err := service.Update(ctx, 1, entities.PreferencePatch{
+		Set: map[entities.PreferenceKey]any{"ui.font": "serif", entities.PreferenceEmailDigest: "weekly"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "serif", prefs.String("ui.font"))
+	assert.Equal(t, "weekly", prefs.EmailDigest())
+
+	_, err = service.Update(ctx, 1, entities.PreferencePatch{
+		Set: map[entities.PreferenceKey]any{entities.PreferenceEmailDigest: "hourly"},
+	})
+	require.ErrorIs(t, err, entities.ErrInvalidPreferenceValue)
+
+	prefs, err = service.Update(ctx, 1, entities.PreferencePatch{Unset: []entities.PreferenceKey{"ui.font"}})
+	require.NoError(t, err)
+	assert.Equal(t, "sans", prefs.String("ui.font"))
+	assert.Equal(t, "weekly", prefs.EmailDigest())
+
+	prefs, err = service.Get(ctx, 2)
+	require.NoError(t, err)
+	assert.Empty(t, prefs.Values)
+	assert.Len(t, service.Schema(), len(entities.DefaultPreferenceSchema())+1)
+}
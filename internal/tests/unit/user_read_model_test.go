@@ -0,0 +1,92 @@
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/memory"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lagRecorder records the projection lags it observes.
+type lagRecorder struct {
+	lags []time.Duration
+}
+
+func (r *lagRecorder) ObserveReadModelLag(lag time.Duration) {
+	r.lags = append(r.lags, lag)
+}
+
+func viewNames(views []*entities.UserView) []string {
+	names := make([]string, 0, len(views))
+	for _, view := range views {
+		names = append(names, view.Username.String())
+	}
+
+	return names
+}
+
+func TestUserProjectionKeepsReadModelInSync(t *testing.T) {
+	ctx := context.Background()
+	db := memory.NewUserRepository()
+	users := seedSearchUsers(t, db)
+	ada, adele, grace, alan := users[0], users[1], users[2], users[3]
+
+	views := memory.NewUserReadModelRepository(db)
+	recorder := &lagRecorder{}
+	projection := services.NewUserProjection(views, db, services.WithReadModelObserver(recorder))
+	directory := services.NewUserDirectory(views)
+
+	lag, err := directory.Lag(ctx)
+	require.NoError(t, err)
+	assert.Positive(t, lag, "users stored before the projection are not in the read model")
+
+	projected, err := projection.Rebuild(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), projected)
+
+	lag, err = directory.Lag(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, lag)
+
+	// Changes reach the read model through the events.
+	require.NoError(t, db.Suspend(ctx, ada.ID()))
+	require.NoError(t, projection.Handle(ctx, events.NewUserEvent(events.EventUserSuspended, ada.ID(), nil)))
+	require.NoError(t, db.Delete(ctx, adele.ID()))
+	require.NoError(t, projection.Handle(ctx, events.NewUserEvent(events.EventUserDeleted, adele.ID(), nil)))
+	require.NoError(t, projection.Handle(ctx, events.NewUserEvent(events.EventUserLogin, grace.ID(), nil)))
+	assert.Len(t, recorder.lags, 2, "only projected events are observed")
+
+	found, err := directory.SearchUsers(ctx, "ad", entities.UserStatusSuspended, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ada"}, viewNames(found))
+
+	found, err = directory.SearchUsers(ctx, "ad", "", 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ada"}, viewNames(found), "deleted users are removed")
+
+	// Bulk updates project every user they name.
+	for _, user := range []*entities.User{grace, alan} {
+		require.NoError(t, db.ChangeRole(ctx, user.ID(), entities.UserRoleModerator))
+	}
+
+	bulk := events.UsersBulkUpdated("role", "moderator", []entities.UserID{grace.ID(), alan.ID()}, 0, ada.ID())
+	require.NoError(t, projection.Handle(ctx, bulk))
+
+	moderators := entities.UserFilter{Roles: []entities.UserRole{entities.UserRoleModerator}}
+	listed, err := directory.ListUsers(ctx, moderators, 10, 0)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"grace", "alan"}, viewNames(listed))
+
+	_, err = directory.SearchUsers(ctx, "ad", "unknown", 10)
+	require.ErrorIs(t, err, entities.ErrInvalidUserStatus)
+
+	lag, err = directory.Lag(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, lag)
+}
@@ -284,6 +284,23 @@ func TestUsernameValidation(t *testing.T) {
 	}
 }
 
+func TestUsernameCanonical(t *testing.T) {
+	tests := map[entities.Username]string{
+		"ada":                 "ada",
+		"Ada_L-42":            "ada_l-42",
+		"ＡＤＡ":                 "ada",
+		"Straße":              "strasse",
+		"ﬁnn":                 "finn",
+		"Ǆemal":               "džemal",
+		"ΣΊΣΥΦΟΣ":             "σίσυφοσ",
+		"A\u030angstr\u00f6m": "\u00e5ngstr\u00f6m",
+	}
+
+	for username, want := range tests {
+		assert.Equal(t, want, username.Canonical(), username)
+	}
+}
+
 func TestPasswordHashValidation(t *testing.T) {
 	tests := []struct {
 		name     string
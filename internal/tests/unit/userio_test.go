@@ -0,0 +1,180 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/memory"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+	"github.com/LarsArtmann/template-sqlc/internal/userio"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const importCSV = `email,username,first_name,last_name,role,tags,verified
+ada@example.com,ada,Ada,Lovelace,admin,beta|math,true
+not-an-email,grace,Grace,Hopper,,,
+linus@example.com,linus,Linus,Torvalds,,,
+ada@example.com,ada2,Ada,Byron,,,
+`
+
+func importUsers(
+	t *testing.T,
+	users *memory.UserRepository,
+	format userio.Format,
+	data string,
+	opts userio.ImportOptions,
+) (*userio.ImportReport, error) {
+	t.Helper()
+
+	decoder, err := userio.NewDecoder(strings.NewReader(data), format)
+	require.NoError(t, err)
+
+	return userio.NewImporter(users).Import(context.Background(), decoder, opts)
+}
+
+func TestImportUsersFromCSV(t *testing.T) {
+	ctx := context.Background()
+	users := memory.NewUserRepository()
+
+	var batches []int64
+
+	report, err := importUsers(t, users, userio.FormatCSV, importCSV, userio.ImportOptions{
+		DryRun:   true,
+		Progress: func(p userio.ImportProgress) { batches = append(batches, p.Processed) },
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), report.Processed)
+	assert.Equal(t, int64(2), report.Created)
+	assert.Equal(t, []int64{4}, batches)
+
+	_, err = users.GetByUsername(ctx, "ada")
+	require.ErrorIs(t, err, entities.ErrUserNotFound, "dry runs do not write")
+
+	report, err = importUsers(t, users, userio.FormatCSV, importCSV, userio.ImportOptions{BatchSize: 1})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), report.Created)
+	require.Len(t, report.Rejected, 2)
+	assert.Equal(t, int64(2), report.Rejected[0].Record)
+	require.ErrorIs(t, report.Rejected[0], entities.ErrInvalidEmail)
+	require.ErrorIs(t, report.Rejected[1], userio.ErrDuplicateRecord)
+
+	ada, err := users.GetByUsername(ctx, "ada")
+	require.NoError(t, err)
+	assert.Equal(t, entities.UserRoleAdmin, ada.Role())
+	assert.Equal(t, entities.UserStatusActive, ada.Status())
+	assert.Equal(t, []string{"beta", "math"}, ada.Tags())
+	assert.True(t, ada.IsVerified())
+	assert.False(t, ada.PasswordHash().IsUsable(), "imported users reset their password")
+
+	_, err = importUsers(t, users, userio.FormatCSV, "email,nickname\n", userio.ImportOptions{})
+	require.ErrorIs(t, err, userio.ErrUnknownField)
+}
+
+func TestImportUsersDuplicatePolicies(t *testing.T) {
+	ctx := context.Background()
+	users := memory.NewUserRepository()
+
+	_, err := importUsers(t, users, userio.FormatCSV, importCSV, userio.ImportOptions{})
+	require.NoError(t, err)
+
+	update := `{"email":"ada@example.com","username":"countess","last_name":"King","role":"user"}
+{"email":"new@example.com","username":"linus","first_name":"New","last_name":"User"}
+{"email":"grace@example.com","username":"grace","first_name":"Grace","last_name":"Hopper"}
+`
+
+	report, err := importUsers(t, users, userio.FormatNDJSON, update, userio.ImportOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), report.Skipped, "the email address or username of a stored user is a duplicate")
+	assert.Equal(t, int64(1), report.Created)
+
+	report, err = importUsers(t, users, userio.FormatNDJSON, update, userio.ImportOptions{
+		OnDuplicate: userio.DuplicateUpdate,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), report.Updated)
+	require.Len(t, report.Rejected, 1, "updates cannot take the username of another user")
+	require.ErrorIs(t, report.Rejected[0], entities.ErrUserAlreadyExists)
+
+	ada, err := users.GetByEmail(ctx, "ada@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, entities.Username("countess"), ada.Username())
+	assert.Equal(t, entities.FirstName("Ada"), ada.FirstName(), "empty fields keep the stored values")
+	assert.Equal(t, entities.LastName("King"), ada.LastName())
+	assert.Equal(t, entities.UserRoleUser, ada.Role())
+
+	report, err = importUsers(t, users, userio.FormatNDJSON, update, userio.ImportOptions{
+		OnDuplicate: userio.DuplicateFail,
+	})
+	require.ErrorIs(t, err, entities.ErrUserAlreadyExists)
+	assert.Equal(t, int64(1), report.Processed)
+
+	_, err = importUsers(t, users, userio.FormatNDJSON, update, userio.ImportOptions{OnDuplicate: "merge"})
+	require.ErrorIs(t, err, userio.ErrInvalidDuplicatePolicy)
+}
+
+func TestExportUsersRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	source := memory.NewUserRepository()
+
+	_, err := importUsers(t, source, userio.FormatCSV, importCSV, userio.ImportOptions{})
+	require.NoError(t, err)
+
+	for _, format := range []userio.Format{userio.FormatCSV, userio.FormatJSON, userio.FormatNDJSON} {
+		var buf bytes.Buffer
+
+		exported, err := userio.NewExporter(source).Export(ctx, &buf, userio.ExportOptions{Format: format})
+		require.NoError(t, err, format)
+		assert.Equal(t, int64(2), exported, format)
+		assert.NotContains(t, buf.String(), "password_hash", format)
+
+		target := memory.NewUserRepository()
+		report, err := importUsers(t, target, format, buf.String(), userio.ImportOptions{})
+		require.NoError(t, err, format)
+		assert.Equal(t, int64(2), report.Created, format)
+		assert.Empty(t, report.Rejected, format)
+
+		ada, err := target.GetByUsername(ctx, "ada")
+		require.NoError(t, err, format)
+		assert.Equal(t, []string{"beta", "math"}, ada.Tags(), format)
+		assert.Equal(t, entities.UserRoleAdmin, ada.Role(), format)
+	}
+
+	var empty bytes.Buffer
+
+	_, err = userio.NewExporter(memory.NewUserRepository()).Export(ctx, &empty,
+		userio.ExportOptions{Format: userio.FormatJSON})
+	require.NoError(t, err)
+	assert.JSONEq(t, "[]", empty.String())
+}
+
+func TestExportUsersSelectsAndMasksFields(t *testing.T) {
+	ctx := context.Background()
+	users := memory.NewUserRepository()
+
+	_, err := importUsers(t, users, userio.FormatCSV, importCSV, userio.ImportOptions{})
+	require.NoError(t, err)
+
+	fields, err := userio.ParseFields("email, username,role")
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+
+	_, err = userio.NewExporter(users).Export(ctx, &buf, userio.ExportOptions{
+		Format:    userio.FormatCSV,
+		Fields:    fields,
+		Filter:    entities.UserFilter{Roles: []entities.UserRole{entities.UserRoleAdmin}},
+		Redaction: events.RedactionPolicy{Mode: events.RedactionMask},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "email,username,role\na***@example.com,a***,admin\n", buf.String())
+
+	_, err = userio.ParseFields("email,ssn")
+	require.ErrorIs(t, err, userio.ErrUnknownField)
+
+	_, err = userio.NewExporter(users).Export(ctx, &buf, userio.ExportOptions{Format: "xml"})
+	require.ErrorIs(t, err, userio.ErrInvalidFormat)
+}
@@ -0,0 +1,139 @@
+package unit
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/memory"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/services"
+	"github.com/LarsArtmann/template-sqlc/internal/validation"
+	"github.com/LarsArtmann/template-sqlc/pkg/config"
+	apperrors "github.com/LarsArtmann/template-sqlc/pkg/errors"
+	"github.com/LarsArtmann/template-sqlc/pkg/passwords"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestNormalizeUsername(t *testing.T) {
+	tests := map[string]string{
+		"Admin":       "admin",
+		"a_d-m.i n":   "admin",
+		"4dm1n":       "admin",
+		"ADMÍN":       "admin",
+		"ＡＤＭＩＮ":       "admin",
+		"аdmin":       "admin", // Cyrillic а
+		"İstanbul":    "istanbul",
+		"Straße":      "strasse",
+		"s0ph1e_g3rm": "sophiegerm",
+	}
+
+	for input, want := range tests {
+		assert.Equal(t, want, validation.NormalizeUsername(input), input)
+	}
+}
+
+func TestNameListMatch(t *testing.T) {
+	list, err := validation.ReadNameList(strings.NewReader(
+		"# staff\nsupport\n*admin*\nbot?  # bot1, bot2...\n\n"))
+	require.NoError(t, err)
+	assert.Equal(t, 3, list.Len())
+
+	for _, name := range []string{"support", "SUPP0RT", "sup_port", "admin", "siteadmin", "the-4dmin-team", "bot7"} {
+		assert.True(t, list.Match(name), name)
+	}
+
+	for _, name := range []string{"supporter", "adm", "bot", "bot12", "ada"} {
+		assert.False(t, list.Match(name), name)
+	}
+
+	var empty *validation.NameList
+	assert.False(t, empty.Match("admin"), "nil lists are empty")
+
+	for _, entry := range []string{"*", "**?", "_-_"} {
+		_, err = validation.NewNameList(entry)
+		require.True(t, apperrors.IsValidationError(err), "%q: %v", entry, err)
+	}
+}
+
+func TestUsernamePolicy(t *testing.T) {
+	profanity, err := validation.NewNameList("*heck*")
+	require.NoError(t, err)
+
+	policy := validation.DefaultUsernamePolicy()
+	policy.Profanity = profanity
+	validator := validation.NewUserValidator(validation.WithUsernamePolicy(policy))
+
+	tests := []struct {
+		username string
+		reason   string
+	}{
+		{"ada", ""},
+		{"Adm1n", "reserved"},
+		{"s_u_p_p_o_r_t", "reserved"},
+		{"what-the-h3ck", "profanity"},
+	}
+
+	for _, tt := range tests {
+		err := validator.ValidateUsername(tt.username)
+		if tt.reason == "" {
+			require.NoError(t, err, tt.username)
+
+			continue
+		}
+
+		fields := apperrors.FieldErrorsOf(err)
+		require.Len(t, fields, 1, tt.username)
+		assert.Equal(t, apperrors.ErrCodeNotAllowed, fields[0].Code, tt.username)
+		assert.Equal(t, tt.reason, fields[0].Params["reason"], tt.username)
+	}
+
+	fields := apperrors.FieldErrorsOf(validator.ValidateUsername("no spaces"))
+	require.Len(t, fields, 1)
+	assert.Equal(t, apperrors.ErrCodeInvalidFormat, fields[0].Code)
+}
+
+func TestCheckUsernameAvailability(t *testing.T) {
+	ctx := context.Background()
+	service := services.NewUserService(memory.NewUserRepository(), memory.NewSessionRepository(),
+		events.NewInMemoryEventPublisher(), validation.NewUserValidator(),
+		services.WithPasswordHasher(passwords.NewBcryptHasher(bcrypt.MinCost)))
+
+	_, err := service.CreateUser(ctx, &services.CreateUserRequest{
+		Email: "ada@example.com", Username: "ada", Password: "Correct-Horse-42", FirstName: "Ada", LastName: "Lovelace",
+		Status: string(entities.UserStatusActive), Role: string(entities.UserRoleUser),
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, service.CheckUsernameAvailability(ctx, "grace"))
+	require.ErrorIs(t, service.CheckUsernameAvailability(ctx, "ada"), entities.ErrUserAlreadyExists)
+
+	err = service.CheckUsernameAvailability(ctx, "r00t")
+	require.True(t, apperrors.IsValidationError(err), "%v", err)
+	assert.Equal(t, "reserved", apperrors.FieldErrorsOf(err)[0].Params["reason"])
+}
+
+func TestUsernamePolicyConfig(t *testing.T) {
+	cfg, err := config.Load(nil, noEnv)
+	require.NoError(t, err)
+
+	policy, err := cfg.Usernames.Policy()
+	require.NoError(t, err)
+	assert.True(t, policy.Reserved.Match("administrator"), "the built-in names are kept")
+	assert.False(t, policy.Profanity.Match("ada"))
+
+	cfg.Usernames.Reserved = []string{"acme*"}
+	cfg.Usernames.Profanity = []string{"*heck*"}
+
+	policy, err = cfg.Usernames.Policy()
+	require.NoError(t, err)
+	assert.True(t, policy.Reserved.Match("AcmeCorp"))
+	assert.True(t, policy.Reserved.Match("admin"))
+	assert.True(t, policy.Profanity.Match("heckler"))
+
+	cfg.Usernames.Profanity = []string{"*"}
+	require.Error(t, cfg.Validate())
+}
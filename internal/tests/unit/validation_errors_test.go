@@ -0,0 +1,138 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	grpcadapter "github.com/LarsArtmann/template-sqlc/internal/adapters/grpc"
+	"github.com/LarsArtmann/template-sqlc/internal/adapters/memory"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/services"
+	"github.com/LarsArtmann/template-sqlc/internal/validation"
+	apperrors "github.com/LarsArtmann/template-sqlc/pkg/errors"
+	"github.com/LarsArtmann/template-sqlc/pkg/passwords"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestValidateUserCreateReportsEveryField(t *testing.T) {
+	validator := validation.NewUserValidator()
+
+	err := validator.ValidateUserCreate(context.Background(), "not-an-email", "ab", "", "L0velace")
+	require.Error(t, err)
+
+	fields := apperrors.FieldErrorsOf(err)
+	require.Len(t, fields, 4)
+
+	assert.Equal(t, apperrors.FieldError{
+		Field: "email", Code: apperrors.ErrCodeInvalidFormat, Message: "must be a valid email address",
+	}, fields[0])
+	assert.Equal(t, apperrors.FieldError{
+		Field: "username", Code: apperrors.ErrCodeTooShort, Message: "must be at least 3 characters",
+		Params: map[string]any{"min": 3},
+	}, fields[1])
+	assert.Equal(t, "first_name", fields[2].Field)
+	assert.Equal(t, apperrors.ErrCodeMissingField, fields[2].Code)
+	assert.Equal(t, "last_name", fields[3].Field)
+	assert.Equal(t, apperrors.ErrCodeInvalidFormat, fields[3].Code)
+
+	wrapped := fmt.Errorf("validation failed: %w", err)
+	assert.True(t, apperrors.IsValidationError(wrapped))
+	assert.Equal(t, apperrors.ErrCodeValidationFailed, apperrors.CodeOf(wrapped))
+	assert.Equal(t, http.StatusUnprocessableEntity, apperrors.HTTPStatus(wrapped))
+	require.ErrorIs(t, wrapped, apperrors.NewMissingFieldError("first_name"))
+	require.NotErrorIs(t, wrapped, apperrors.NewMissingFieldError("email"))
+
+	require.NoError(t, validator.ValidateUserCreate(context.Background(), "ada@example.com", "ada", "Ada", "Lovelace"))
+}
+
+func TestValidationErrorsBody(t *testing.T) {
+	var errs apperrors.ValidationErrors
+	require.NoError(t, errs.Err(), "no errors are no error")
+
+	errs.Add("limit", apperrors.ErrCodeOutOfRange, "must be between 1 and 1000", "min", 1, "max", 1000)
+	errs.Add("offset", apperrors.ErrCodeOutOfRange, "must be non-negative", "min", 0)
+
+	body, err := json.Marshal(errs.Err())
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"code": "VALIDATION_FAILED",
+		"message": "Validation failed",
+		"errors": [
+			{"field": "limit", "code": "OUT_OF_RANGE", "message": "must be between 1 and 1000",
+			 "params": {"min": 1, "max": 1000}},
+			{"field": "offset", "code": "OUT_OF_RANGE", "message": "must be non-negative", "params": {"min": 0}}
+		]
+	}`, string(body))
+
+	assert.Equal(t, errs, apperrors.FieldErrorsOf(validation.NewUserValidator().ValidatePagination(0, -1)))
+	assert.Equal(t,
+		"VALIDATION_FAILED: limit: must be between 1 and 1000; offset: must be non-negative", errs.Error())
+}
+
+func TestValidationErrorsToGRPCFieldViolations(t *testing.T) {
+	err := validation.NewUserValidator().ValidateTags([]string{"go", "two words", "Go"})
+
+	st, ok := status.FromError(grpcadapter.ToStatus(fmt.Errorf("update user: %w", err)))
+	require.True(t, ok)
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+	require.Len(t, st.Details(), 1)
+
+	badRequest, ok := st.Details()[0].(*errdetails.BadRequest)
+	require.True(t, ok)
+	require.Len(t, badRequest.GetFieldViolations(), 2)
+	assert.Equal(t, "tags[1]", badRequest.GetFieldViolations()[0].GetField())
+	assert.Equal(t, "INVALID_FORMAT", badRequest.GetFieldViolations()[0].GetReason())
+	assert.Equal(t, "tags[2]", badRequest.GetFieldViolations()[1].GetField())
+	assert.Equal(t, "duplicate tag found", badRequest.GetFieldViolations()[1].GetDescription())
+
+	st, ok = status.FromError(grpcadapter.ToStatus(apperrors.NewValidationError("email", "is taken")))
+	require.True(t, ok)
+	require.Len(t, st.Details(), 1, "single field errors become violations too")
+
+	st, ok = status.FromError(grpcadapter.ToStatus(apperrors.NewInvalidInputError("bad request")))
+	require.True(t, ok)
+	assert.Empty(t, st.Details())
+}
+
+func TestPasswordPolicyReportsEveryRequirement(t *testing.T) {
+	policy := validation.PasswordPolicy{
+		MinLength: 12, MinCharacterClasses: 3, MinStrength: validation.StrengthVeryUnguessable,
+	}
+
+	fields := apperrors.FieldErrorsOf(policy.Check(context.Background(), "abcdefg"))
+	require.Len(t, fields, 3)
+	assert.Equal(t, apperrors.ErrCodeTooShort, fields[0].Code)
+	assert.Equal(t, apperrors.ErrCodeTooWeak, fields[1].Code)
+	assert.Equal(t, 3, fields[1].Params["min_classes"])
+	assert.Equal(t, apperrors.ErrCodeTooWeak, fields[2].Code)
+	assert.Equal(t, int(validation.StrengthVeryUnguessable), fields[2].Params["min_strength"])
+}
+
+func TestCreateUserReportsFieldsAndPasswordTogether(t *testing.T) {
+	service := services.NewUserService(memory.NewUserRepository(), memory.NewSessionRepository(),
+		events.NewInMemoryEventPublisher(), validation.NewUserValidator(),
+		services.WithPasswordHasher(passwords.NewBcryptHasher(bcrypt.MinCost)))
+
+	_, err := service.CreateUser(context.Background(), &services.CreateUserRequest{
+		Email:     "ada@example",
+		Username:  "ada",
+		Password:  "short",
+		FirstName: "Ada",
+		LastName:  "Lovelace",
+	})
+	require.Error(t, err)
+
+	fields := apperrors.FieldErrorsOf(err)
+	assert.True(t, fields.Has("email"))
+	assert.True(t, fields.Has("password"))
+	assert.False(t, fields.Has("username"))
+	assert.Equal(t, http.StatusUnprocessableEntity, apperrors.HTTPStatus(err))
+}
@@ -0,0 +1,269 @@
+package userio
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+)
+
+// ErrMissingField is returned for a CSV file without a required column.
+var ErrMissingField = errors.New("missing field")
+
+// Decoder reads the records of an import file one at a time, so that files
+// larger than memory can be imported.
+type Decoder struct {
+	format  Format
+	csv     *csv.Reader
+	columns []Field
+	json    *json.Decoder
+	started bool
+}
+
+// NewDecoder creates a decoder of the records in format read from r.
+func NewDecoder(r io.Reader, format Format) (*Decoder, error) {
+	decoder := &Decoder{format: format}
+
+	switch format {
+	case FormatCSV:
+		decoder.csv = csv.NewReader(r)
+		decoder.csv.ReuseRecord = true
+	case FormatJSON, FormatNDJSON:
+		decoder.json = json.NewDecoder(r)
+		decoder.json.DisallowUnknownFields()
+	default:
+		return nil, fmt.Errorf("format=%v: %w", format, ErrInvalidFormat)
+	}
+
+	return decoder, nil
+}
+
+// Next returns the next record, or io.EOF after the last one.
+func (d *Decoder) Next() (*Record, error) {
+	if !d.started {
+		err := d.start()
+		if err != nil {
+			return nil, err
+		}
+
+		d.started = true
+	}
+
+	if d.csv != nil {
+		return d.nextCSV()
+	}
+
+	if !d.json.More() {
+		return nil, io.EOF
+	}
+
+	var record Record
+
+	err := d.json.Decode(&record)
+	if err != nil {
+		return nil, fmt.Errorf("decode record: %w", err)
+	}
+
+	return &record, nil
+}
+
+// start reads the CSV header or the opening bracket of a JSON array.
+func (d *Decoder) start() error {
+	switch d.format {
+	case FormatCSV:
+		header, err := d.csv.Read()
+		if err != nil {
+			return fmt.Errorf("read header: %w", err)
+		}
+
+		for _, name := range header {
+			field := Field(name)
+			if !field.IsValid() {
+				return fmt.Errorf("column=%q: %w", name, ErrUnknownField)
+			}
+
+			d.columns = append(d.columns, field)
+		}
+
+		for _, field := range []Field{FieldEmail, FieldUsername} {
+			if !slices.Contains(d.columns, field) {
+				return fmt.Errorf("column=%v: %w", field, ErrMissingField)
+			}
+		}
+	case FormatJSON:
+		token, err := d.json.Token()
+		if err != nil {
+			return fmt.Errorf("read array: %w", err)
+		}
+
+		if token != json.Delim('[') {
+			return fmt.Errorf("token=%v: %w", token, ErrInvalidFormat)
+		}
+	case FormatNDJSON:
+	}
+
+	return nil
+}
+
+func (d *Decoder) nextCSV() (*Record, error) {
+	row, err := d.csv.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, io.EOF
+		}
+
+		return nil, fmt.Errorf("read row: %w", err)
+	}
+
+	var record Record
+
+	for i, field := range d.columns {
+		err = record.setCell(field, row[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &record, nil
+}
+
+// Encoder writes the selected fields of records to an export file.
+type Encoder struct {
+	format  Format
+	fields  []Field
+	w       io.Writer
+	csv     *csv.Writer
+	written int64
+}
+
+// NewEncoder creates an encoder of the fields of records in format to w.
+// Close must be called after the last record.
+func NewEncoder(w io.Writer, format Format, fields []Field) (*Encoder, error) {
+	if !format.IsValid() {
+		return nil, fmt.Errorf("format=%v: %w", format, ErrInvalidFormat)
+	}
+
+	encoder := &Encoder{format: format, fields: fields, w: w}
+
+	if format == FormatCSV {
+		encoder.csv = csv.NewWriter(w)
+
+		header := make([]string, 0, len(fields))
+		for _, field := range fields {
+			header = append(header, string(field))
+		}
+
+		err := encoder.csv.Write(header)
+		if err != nil {
+			return nil, fmt.Errorf("write header: %w", err)
+		}
+	}
+
+	return encoder, nil
+}
+
+// Encode writes the selected fields of record.
+func (e *Encoder) Encode(record *Record) error {
+	if e.csv != nil {
+		row := make([]string, 0, len(e.fields))
+
+		for _, field := range e.fields {
+			cell, err := record.cell(field)
+			if err != nil {
+				return err
+			}
+
+			row = append(row, cell)
+		}
+
+		err := e.csv.Write(row)
+		if err != nil {
+			return fmt.Errorf("write row: %w", err)
+		}
+
+		e.written++
+
+		return nil
+	}
+
+	data, err := e.marshal(record)
+	if err != nil {
+		return err
+	}
+
+	prefix, suffix := "", "\n"
+	if e.format == FormatJSON {
+		prefix, suffix = ",\n", ""
+		if e.written == 0 {
+			prefix = "[\n"
+		}
+	}
+
+	_, err = fmt.Fprintf(e.w, "%s%s%s", prefix, data, suffix)
+	if err != nil {
+		return fmt.Errorf("write record: %w", err)
+	}
+
+	e.written++
+
+	return nil
+}
+
+// marshal encodes the selected, non-empty fields of record as a JSON object
+// in field order.
+func (e *Encoder) marshal(record *Record) ([]byte, error) {
+	var buf bytes.Buffer
+
+	buf.WriteByte('{')
+
+	for _, field := range e.fields {
+		value := record.value(field)
+		if value == nil {
+			continue
+		}
+
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("encode field=%v: %w", field, err)
+		}
+
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+
+		fmt.Fprintf(&buf, "%q:%s", field, data)
+	}
+
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// Close completes the file: it closes the JSON array and flushes CSV rows.
+func (e *Encoder) Close() error {
+	switch e.format {
+	case FormatCSV:
+		e.csv.Flush()
+
+		err := e.csv.Error()
+		if err != nil {
+			return fmt.Errorf("flush rows: %w", err)
+		}
+	case FormatJSON:
+		closing := "\n]\n"
+		if e.written == 0 {
+			closing = "[]\n"
+		}
+
+		_, err := io.WriteString(e.w, closing)
+		if err != nil {
+			return fmt.Errorf("close array: %w", err)
+		}
+	case FormatNDJSON:
+	}
+
+	return nil
+}
@@ -0,0 +1,119 @@
+package userio
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// DefaultExportPageSize is the number of users read per page.
+const DefaultExportPageSize = 500
+
+// ExportOptions configures an export.
+type ExportOptions struct {
+	Format Format
+	// Fields default to DefaultExportFields.
+	Fields []Field
+	// Filter selects the exported users; the zero value exports all.
+	Filter entities.UserFilter
+	// Redaction masks or hashes the email addresses, usernames and names.
+	// The zero value keeps them.
+	Redaction events.RedactionPolicy
+	// PageSize defaults to DefaultExportPageSize.
+	PageSize int
+	// Progress, if set, is called after every page.
+	Progress func(ExportProgress)
+}
+
+// ExportProgress reports how far an export got.
+type ExportProgress struct {
+	Exported int64
+	Elapsed  time.Duration
+}
+
+// Exporter exports the users of a repository.
+type Exporter struct {
+	users repositories.UserRepository
+}
+
+// NewExporter creates an exporter of users.
+func NewExporter(users repositories.UserRepository) *Exporter {
+	return &Exporter{users: users}
+}
+
+// Export writes the users matching the filter to w, newest first, and
+// returns how many were written. Users are read page by page, so exports
+// larger than memory stream through.
+func (e *Exporter) Export(ctx context.Context, w io.Writer, opts ExportOptions) (int64, error) {
+	err := opts.Filter.Validate()
+	if err != nil {
+		return 0, err
+	}
+
+	policy := opts.Redaction
+	if policy.Mode == "" {
+		policy.Mode = events.RedactionNone
+	}
+
+	err = policy.Validate()
+	if err != nil {
+		return 0, err
+	}
+
+	fields := opts.Fields
+	if len(fields) == 0 {
+		fields = DefaultExportFields()
+	}
+
+	pageSize := opts.PageSize
+	if pageSize <= 0 {
+		pageSize = DefaultExportPageSize
+	}
+
+	encoder, err := NewEncoder(w, opts.Format, fields)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		exported int64
+		cursor   string
+		started  = time.Now()
+	)
+
+	for {
+		page, err := e.users.ListPage(ctx, opts.Filter, cursor, pageSize)
+		if err != nil {
+			return exported, fmt.Errorf("list users after cursor=%q: %w", cursor, err)
+		}
+
+		for _, user := range page.Users {
+			record := NewRecord(user)
+			record.Redact(policy)
+
+			err = encoder.Encode(record)
+			if err != nil {
+				return exported, fmt.Errorf("export user id=%v: %w", user.ID(), err)
+			}
+
+			exported++
+		}
+
+		if opts.Progress != nil {
+			opts.Progress(ExportProgress{Exported: exported, Elapsed: time.Since(started)})
+		}
+
+		if page.NextCursor == "" {
+			break
+		}
+
+		cursor = page.NextCursor
+	}
+
+	return exported, encoder.Close()
+}
@@ -0,0 +1,341 @@
+package userio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// DefaultImportBatchSize is the number of new users created per batch.
+const DefaultImportBatchSize = 500
+
+var (
+	// ErrInvalidDuplicatePolicy is returned for an unknown duplicate policy.
+	ErrInvalidDuplicatePolicy = errors.New("invalid duplicate policy")
+	// ErrDuplicateRecord is returned for a record whose email address or
+	// username appeared in an earlier record of the same file.
+	ErrDuplicateRecord = errors.New("duplicate record")
+)
+
+// DuplicatePolicy says what importing a user that already exists does. A
+// record duplicates the stored users with its email address or username;
+// updates apply to the user with its email address.
+type DuplicatePolicy string
+
+// Supported duplicate policies.
+const (
+	// DuplicateSkip keeps the stored user unchanged.
+	DuplicateSkip DuplicatePolicy = "skip"
+	// DuplicateUpdate updates the stored user with the non-empty fields of
+	// the record.
+	DuplicateUpdate DuplicatePolicy = "update"
+	// DuplicateFail stops the import at the first duplicate.
+	DuplicateFail DuplicatePolicy = "fail"
+)
+
+// IsValid returns true if the duplicate policy is a valid value.
+func (p DuplicatePolicy) IsValid() bool {
+	switch p {
+	case DuplicateSkip, DuplicateUpdate, DuplicateFail:
+		return true
+	default:
+		return false
+	}
+}
+
+// ImportOptions configures an import.
+type ImportOptions struct {
+	// OnDuplicate defaults to DuplicateSkip.
+	OnDuplicate DuplicatePolicy
+	// BatchSize defaults to DefaultImportBatchSize.
+	BatchSize int
+	// DryRun validates the records and looks up duplicates without writing.
+	DryRun bool
+	// Progress, if set, is called after every batch.
+	Progress func(ImportProgress)
+}
+
+// ImportProgress reports how far an import got.
+type ImportProgress struct {
+	Processed int64
+	Elapsed   time.Duration
+}
+
+// RecordError is a record that was rejected; the import goes on without it.
+type RecordError struct {
+	// Record is the position of the record in the file, starting at 1.
+	Record int64  `json:"record"`
+	Email  string `json:"email,omitempty"`
+	Reason string `json:"reason"`
+	Err    error  `json:"-"`
+}
+
+func (e *RecordError) Error() string {
+	return fmt.Sprintf("record=%d email=%s: %v", e.Record, e.Email, e.Err)
+}
+
+func (e *RecordError) Unwrap() error {
+	return e.Err
+}
+
+// ImportReport summarizes an import. With DryRun, Created and Updated count
+// the users that would have been created and updated.
+type ImportReport struct {
+	Processed int64          `json:"processed"`
+	Created   int64          `json:"created"`
+	Updated   int64          `json:"updated"`
+	Skipped   int64          `json:"skipped"`
+	Rejected  []*RecordError `json:"rejected,omitempty"`
+	DryRun    bool           `json:"dry_run"`
+	Duration  time.Duration  `json:"duration"`
+}
+
+// Importer imports users into a repository.
+type Importer struct {
+	users repositories.UserRepository
+}
+
+// NewImporter creates an importer into users.
+func NewImporter(users repositories.UserRepository) *Importer {
+	return &Importer{users: users}
+}
+
+// importRun is the state of one import.
+type importRun struct {
+	*Importer
+
+	opts    ImportOptions
+	report  *ImportReport
+	started time.Time
+	pending []*entities.User
+	// seen holds the email addresses and usernames of the records so far.
+	seen map[string]bool
+}
+
+// Import imports the records of decoder. Invalid records are rejected and
+// reported while the import goes on; decoding errors, repository errors and
+// duplicates under DuplicateFail stop it. New users are created in batches,
+// so a stopped import keeps the batches created before.
+func (i *Importer) Import(ctx context.Context, decoder *Decoder, opts ImportOptions) (*ImportReport, error) {
+	if opts.OnDuplicate == "" {
+		opts.OnDuplicate = DuplicateSkip
+	}
+
+	if !opts.OnDuplicate.IsValid() {
+		return nil, fmt.Errorf("policy=%v: %w", opts.OnDuplicate, ErrInvalidDuplicatePolicy)
+	}
+
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultImportBatchSize
+	}
+
+	run := &importRun{
+		Importer: i,
+		opts:     opts,
+		report:   &ImportReport{DryRun: opts.DryRun},
+		started:  time.Now(),
+		seen:     make(map[string]bool),
+	}
+
+	err := run.run(ctx, decoder)
+	run.report.Duration = time.Since(run.started)
+
+	return run.report, err
+}
+
+func (r *importRun) run(ctx context.Context, decoder *Decoder) error {
+	for {
+		err := ctx.Err()
+		if err != nil {
+			return err
+		}
+
+		record, err := decoder.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return fmt.Errorf("record=%d: %w", r.report.Processed+1, err)
+		}
+
+		r.report.Processed++
+
+		err = r.importRecord(ctx, record)
+
+		var rejected *RecordError
+		if errors.As(err, &rejected) {
+			r.report.Rejected = append(r.report.Rejected, rejected)
+		} else if err != nil {
+			return err
+		}
+
+		if r.report.Processed%int64(r.opts.BatchSize) == 0 {
+			err = r.flush(ctx)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return r.flush(ctx)
+}
+
+// importRecord queues the user of record for creation or handles it as a
+// duplicate. It returns a *RecordError for a rejected record.
+func (r *importRun) importRecord(ctx context.Context, record *Record) error {
+	reject := func(err error) error {
+		return &RecordError{Record: r.report.Processed, Email: record.Email, Reason: err.Error(), Err: err}
+	}
+
+	email, err := entities.NewEmail(record.Email)
+	if err != nil {
+		return reject(err)
+	}
+
+	username, err := entities.NewUsername(record.Username)
+	if err != nil {
+		return reject(err)
+	}
+
+	keys := []string{
+		"email:" + strings.ToLower(email.String()),
+		"username:" + strings.ToLower(username.String()),
+	}
+
+	for _, key := range keys {
+		if r.seen[key] {
+			return reject(fmt.Errorf("%s: %w", key, ErrDuplicateRecord))
+		}
+	}
+
+	for _, key := range keys {
+		r.seen[key] = true
+	}
+
+	existing, err := r.lookup(ctx, func(ctx context.Context) (*entities.User, error) {
+		return r.users.GetByEmail(ctx, email)
+	})
+	if err != nil {
+		return err
+	}
+
+	namesake, err := r.lookup(ctx, func(ctx context.Context) (*entities.User, error) {
+		return r.users.GetByUsername(ctx, username)
+	})
+	if err != nil {
+		return err
+	}
+
+	if existing == nil && namesake == nil {
+		user, err := record.User()
+		if err != nil {
+			return reject(err)
+		}
+
+		r.pending = append(r.pending, user)
+
+		return nil
+	}
+
+	switch r.opts.OnDuplicate {
+	case DuplicateFail:
+		return fmt.Errorf("record=%d email=%s: %w", r.report.Processed, record.Email, entities.ErrUserAlreadyExists)
+	case DuplicateSkip:
+		r.report.Skipped++
+
+		return nil
+	case DuplicateUpdate:
+	}
+
+	// Updates cannot take the username of another user.
+	if existing == nil || (namesake != nil && namesake.ID() != existing.ID()) {
+		return reject(fmt.Errorf("username=%s: %w", username, entities.ErrUserAlreadyExists))
+	}
+
+	return r.update(ctx, existing, record, reject)
+}
+
+// lookup returns the user found by get, or nil if there is none.
+func (r *importRun) lookup(
+	ctx context.Context,
+	get func(ctx context.Context) (*entities.User, error),
+) (*entities.User, error) {
+	user, err := get(ctx)
+	if errors.Is(err, entities.ErrUserNotFound) {
+		return nil, nil //nolint:nilnil // no user is not an error here
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("look up record=%d: %w", r.report.Processed, err)
+	}
+
+	return user, nil
+}
+
+// update applies record to the stored user.
+func (r *importRun) update(
+	ctx context.Context,
+	user *entities.User,
+	record *Record,
+	reject func(error) error,
+) error {
+	err := record.Apply(user)
+	if err != nil {
+		return reject(err)
+	}
+
+	var password entities.PasswordHash
+
+	if record.PasswordHash != "" {
+		password, err = entities.NewPasswordHash(record.PasswordHash)
+		if err != nil {
+			return reject(err)
+		}
+	}
+
+	r.report.Updated++
+
+	if r.opts.DryRun {
+		return nil
+	}
+
+	err = r.users.Update(ctx, user)
+	if err != nil {
+		return fmt.Errorf("update user id=%v: %w", user.ID(), err)
+	}
+
+	if password != "" {
+		err = r.users.UpdatePassword(ctx, user.ID(), password)
+		if err != nil {
+			return fmt.Errorf("update password of user id=%v: %w", user.ID(), err)
+		}
+	}
+
+	return nil
+}
+
+// flush creates the pending users and reports the progress.
+func (r *importRun) flush(ctx context.Context) error {
+	if len(r.pending) > 0 && !r.opts.DryRun {
+		err := r.users.CreateBatch(ctx, r.pending)
+		if err != nil {
+			return fmt.Errorf("create %d users: %w", len(r.pending), err)
+		}
+	}
+
+	r.report.Created += int64(len(r.pending))
+	r.pending = r.pending[:0]
+
+	if r.opts.Progress != nil {
+		r.opts.Progress(ImportProgress{Processed: r.report.Processed, Elapsed: time.Since(r.started)})
+	}
+
+	return nil
+}
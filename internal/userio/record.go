@@ -0,0 +1,472 @@
+// Package userio imports users into and exports them from a UserRepository
+// as CSV, JSON or newline-delimited JSON files.
+//
+// Files of every format hold the same records: CSV files name their columns
+// in a header row, JSON files hold an array of objects and NDJSON files one
+// object per line, keyed by the Field names. Exports can be imported again,
+// except for password hashes, which are only exported when asked for.
+package userio
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+)
+
+var (
+	// ErrInvalidFormat is returned for an unknown file format.
+	ErrInvalidFormat = errors.New("invalid format")
+	// ErrUnknownField is returned for a column or field that is not a Field.
+	ErrUnknownField = errors.New("unknown field")
+)
+
+// Format is the encoding of an import or export file.
+type Format string
+
+// Supported formats.
+const (
+	FormatCSV Format = "csv"
+	// FormatJSON is a JSON array of records.
+	FormatJSON Format = "json"
+	// FormatNDJSON is one JSON record per line.
+	FormatNDJSON Format = "ndjson"
+)
+
+// IsValid returns true if the format is a valid value.
+func (f Format) IsValid() bool {
+	switch f {
+	case FormatCSV, FormatJSON, FormatNDJSON:
+		return true
+	default:
+		return false
+	}
+}
+
+// FormatOf returns the format of path by its extension; .jsonl files are
+// NDJSON.
+func FormatOf(path string) (Format, error) {
+	ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(path), "."))
+	if ext == "jsonl" {
+		return FormatNDJSON, nil
+	}
+
+	format := Format(ext)
+	if !format.IsValid() {
+		return "", fmt.Errorf("path=%s: %w", path, ErrInvalidFormat)
+	}
+
+	return format, nil
+}
+
+// Field is a column of CSV files and a key of JSON records.
+type Field string
+
+// Fields of a record. Imports ignore the ID, UUID and timestamps, as the
+// repository assigns them.
+const (
+	FieldID           Field = "id"
+	FieldUUID         Field = "uuid"
+	FieldEmail        Field = "email"
+	FieldUsername     Field = "username"
+	FieldFirstName    Field = "first_name"
+	FieldLastName     Field = "last_name"
+	FieldStatus       Field = "status"
+	FieldRole         Field = "role"
+	FieldVerified     Field = "verified"
+	FieldTags         Field = "tags"
+	FieldMetadata     Field = "metadata"
+	FieldPasswordHash Field = "password_hash"
+	FieldCreatedAt    Field = "created_at"
+	FieldUpdatedAt    Field = "updated_at"
+	FieldLastLoginAt  Field = "last_login_at"
+)
+
+// tagSeparator separates the tags of a CSV cell.
+const tagSeparator = "|"
+
+// Fields returns every field in column order.
+func Fields() []Field {
+	return []Field{
+		FieldID, FieldUUID, FieldEmail, FieldUsername, FieldFirstName, FieldLastName, FieldStatus, FieldRole,
+		FieldVerified, FieldTags, FieldMetadata, FieldPasswordHash, FieldCreatedAt, FieldUpdatedAt, FieldLastLoginAt,
+	}
+}
+
+// DefaultExportFields returns the fields exported unless others are
+// selected: every field but the password hash.
+func DefaultExportFields() []Field {
+	return slices.DeleteFunc(Fields(), func(f Field) bool { return f == FieldPasswordHash })
+}
+
+// IsValid returns true if the field is a valid value.
+func (f Field) IsValid() bool {
+	return slices.Contains(Fields(), f)
+}
+
+// ParseFields parses a comma-separated list of fields, such as
+// "email,username,status".
+func ParseFields(list string) ([]Field, error) {
+	var fields []Field
+
+	for name := range strings.SplitSeq(list, ",") {
+		field := Field(strings.TrimSpace(name))
+		if field == "" {
+			continue
+		}
+
+		if !field.IsValid() {
+			return nil, fmt.Errorf("field=%v: %w", field, ErrUnknownField)
+		}
+
+		fields = append(fields, field)
+	}
+
+	return fields, nil
+}
+
+// Record is a user as stored in an import or export file. Empty fields of
+// imported records take their defaults, or keep the stored values when an
+// existing user is updated.
+type Record struct {
+	ID           entities.UserID `json:"id,omitempty"`
+	UUID         string          `json:"uuid,omitempty"`
+	Email        string          `json:"email"`
+	Username     string          `json:"username"`
+	FirstName    string          `json:"first_name"`
+	LastName     string          `json:"last_name"`
+	Status       string          `json:"status,omitempty"`
+	Role         string          `json:"role,omitempty"`
+	Verified     bool            `json:"verified,omitempty"`
+	Tags         []string        `json:"tags,omitempty"`
+	Metadata     map[string]any  `json:"metadata,omitempty"`
+	PasswordHash string          `json:"password_hash,omitempty"`
+	CreatedAt    *time.Time      `json:"created_at,omitempty"`
+	UpdatedAt    *time.Time      `json:"updated_at,omitempty"`
+	LastLoginAt  *time.Time      `json:"last_login_at,omitempty"`
+}
+
+// NewRecord returns the record of user.
+func NewRecord(user *entities.User) *Record {
+	createdAt, updatedAt := user.CreatedAt(), user.UpdatedAt()
+
+	return &Record{
+		ID:           user.ID(),
+		UUID:         user.UUID().String(),
+		Email:        user.Email().String(),
+		Username:     user.Username().String(),
+		FirstName:    user.FirstName().String(),
+		LastName:     user.LastName().String(),
+		Status:       user.Status().String(),
+		Role:         user.Role().String(),
+		Verified:     user.IsVerified(),
+		Tags:         slices.Clone(user.Tags()),
+		Metadata:     user.Metadata(),
+		PasswordHash: user.PasswordHash().String(),
+		CreatedAt:    &createdAt,
+		UpdatedAt:    &updatedAt,
+		LastLoginAt:  user.LastLoginAt(),
+	}
+}
+
+// Redact redacts the personal data of the record with policy.
+func (r *Record) Redact(policy events.RedactionPolicy) {
+	r.Email = policy.Redact(events.PIIEmail, r.Email)
+	r.Username = policy.Redact(events.PIIUsername, r.Username)
+	r.FirstName = policy.Redact(events.PIIName, r.FirstName)
+	r.LastName = policy.Redact(events.PIIName, r.LastName)
+}
+
+// value returns the value of field, or nil if it is empty.
+func (r *Record) value(field Field) any {
+	switch field {
+	case FieldID:
+		if r.ID != 0 {
+			return r.ID
+		}
+	case FieldUUID:
+		return stringValue(r.UUID)
+	case FieldEmail:
+		return stringValue(r.Email)
+	case FieldUsername:
+		return stringValue(r.Username)
+	case FieldFirstName:
+		return stringValue(r.FirstName)
+	case FieldLastName:
+		return stringValue(r.LastName)
+	case FieldStatus:
+		return stringValue(r.Status)
+	case FieldRole:
+		return stringValue(r.Role)
+	case FieldVerified:
+		return r.Verified
+	case FieldTags:
+		if r.Tags != nil {
+			return r.Tags
+		}
+	case FieldMetadata:
+		if r.Metadata != nil {
+			return r.Metadata
+		}
+	case FieldPasswordHash:
+		return stringValue(r.PasswordHash)
+	case FieldCreatedAt:
+		return timeValue(r.CreatedAt)
+	case FieldUpdatedAt:
+		return timeValue(r.UpdatedAt)
+	case FieldLastLoginAt:
+		return timeValue(r.LastLoginAt)
+	}
+
+	return nil
+}
+
+// stringValue returns s, or nil if it is empty.
+func stringValue(s string) any {
+	if s == "" {
+		return nil
+	}
+
+	return s
+}
+
+// timeValue returns t in UTC, or nil if it is unset.
+func timeValue(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+
+	return t.UTC()
+}
+
+// cell returns the value of field as a CSV cell.
+func (r *Record) cell(field Field) (string, error) {
+	switch value := r.value(field).(type) {
+	case nil:
+		return "", nil
+	case string:
+		return value, nil
+	case bool:
+		return strconv.FormatBool(value), nil
+	case entities.UserID:
+		return strconv.FormatInt(int64(value), 10), nil
+	case []string:
+		return strings.Join(value, tagSeparator), nil
+	case time.Time:
+		return value.Format(time.RFC3339Nano), nil
+	default:
+		data, err := json.Marshal(value)
+		if err != nil {
+			return "", fmt.Errorf("encode field=%v: %w", field, err)
+		}
+
+		return string(data), nil
+	}
+}
+
+// setCell sets field from the CSV cell value.
+func (r *Record) setCell(field Field, value string) error {
+	if value == "" {
+		return nil
+	}
+
+	var err error
+
+	switch field {
+	case FieldID:
+		var id int64
+
+		id, err = strconv.ParseInt(value, 10, 64)
+		r.ID = entities.UserID(id)
+	case FieldUUID:
+		r.UUID = value
+	case FieldEmail:
+		r.Email = value
+	case FieldUsername:
+		r.Username = value
+	case FieldFirstName:
+		r.FirstName = value
+	case FieldLastName:
+		r.LastName = value
+	case FieldStatus:
+		r.Status = value
+	case FieldRole:
+		r.Role = value
+	case FieldVerified:
+		r.Verified, err = strconv.ParseBool(value)
+	case FieldTags:
+		r.Tags = strings.Split(value, tagSeparator)
+	case FieldMetadata:
+		err = json.Unmarshal([]byte(value), &r.Metadata)
+	case FieldPasswordHash:
+		r.PasswordHash = value
+	case FieldCreatedAt:
+		r.CreatedAt, err = parseTime(value)
+	case FieldUpdatedAt:
+		r.UpdatedAt, err = parseTime(value)
+	case FieldLastLoginAt:
+		r.LastLoginAt, err = parseTime(value)
+	default:
+		return fmt.Errorf("field=%v: %w", field, ErrUnknownField)
+	}
+
+	if err != nil {
+		return fmt.Errorf("field=%v value=%q: %w", field, value, err)
+	}
+
+	return nil
+}
+
+func parseTime(value string) (*time.Time, error) {
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return nil, err
+	}
+
+	return &t, nil
+}
+
+// User returns a new user with the fields of the record. Users without a
+// password hash get an unusable one and sign in after resetting it.
+func (r *Record) User() (*entities.User, error) {
+	email, err := entities.NewEmail(r.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	username, err := entities.NewUsername(r.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	firstName, err := entities.NewFirstName(r.FirstName)
+	if err != nil {
+		return nil, err
+	}
+
+	lastName, err := entities.NewLastName(r.LastName)
+	if err != nil {
+		return nil, err
+	}
+
+	password := entities.NewUnusablePasswordHash()
+	if r.PasswordHash != "" {
+		password, err = entities.NewPasswordHash(r.PasswordHash)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	metadata := entities.NewUserMetadata()
+	for key, value := range r.Metadata {
+		metadata.Set(key, value)
+	}
+
+	tags := r.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+
+	user, err := entities.NewUser(email, username, password, firstName, lastName,
+		r.status(entities.UserStatusActive), r.role(entities.UserRoleUser), metadata, tags)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.Verified {
+		user.Verify()
+	}
+
+	return user, nil
+}
+
+// Apply updates user with the non-empty fields of the record but the email
+// address, which identifies the user.
+func (r *Record) Apply(user *entities.User) error {
+	var (
+		firstName *entities.FirstName
+		lastName  *entities.LastName
+		metadata  *entities.UserMetadata
+		tags      *[]string
+	)
+
+	if r.FirstName != "" {
+		name, err := entities.NewFirstName(r.FirstName)
+		if err != nil {
+			return err
+		}
+
+		firstName = &name
+	}
+
+	if r.LastName != "" {
+		name, err := entities.NewLastName(r.LastName)
+		if err != nil {
+			return err
+		}
+
+		lastName = &name
+	}
+
+	if r.Metadata != nil {
+		values := entities.UserMetadata(r.Metadata)
+		metadata = &values
+	}
+
+	if r.Tags != nil {
+		tags = &r.Tags
+	}
+
+	err := user.UpdateProfile(firstName, lastName, metadata, tags)
+	if err != nil {
+		return err
+	}
+
+	if r.Username != "" && r.Username != user.Username().String() {
+		username, err := entities.NewUsername(r.Username)
+		if err != nil {
+			return err
+		}
+
+		user.ChangeUsername(username)
+	}
+
+	err = user.ChangeStatus(r.status(user.Status()))
+	if err != nil {
+		return err
+	}
+
+	err = user.ChangeRole(r.role(user.Role()))
+	if err != nil {
+		return err
+	}
+
+	if r.Verified {
+		user.Verify()
+	}
+
+	return nil
+}
+
+func (r *Record) status(fallback entities.UserStatus) entities.UserStatus {
+	if r.Status == "" {
+		return fallback
+	}
+
+	return entities.UserStatus(r.Status)
+}
+
+func (r *Record) role(fallback entities.UserRole) entities.UserRole {
+	if r.Role == "" {
+		return fallback
+	}
+
+	return entities.UserRole(r.Role)
+}
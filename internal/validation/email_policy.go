@@ -0,0 +1,182 @@
+package validation
+
+import (
+	"bufio"
+	"context"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/pkg/errors"
+)
+
+// DefaultMXTimeout bounds the DNS lookups of an EmailPolicy without MXTimeout.
+const DefaultMXTimeout = 3 * time.Second
+
+// MXResolver looks up the mail exchangers of a domain, and its addresses for
+// domains without any. *net.Resolver implements it; tests use a stub.
+type MXResolver interface {
+	LookupMX(ctx context.Context, name string) ([]*net.MX, error)
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+// EmailPolicy holds the checks of an email address beyond its syntax. The
+// zero value checks nothing.
+type EmailPolicy struct {
+	// Resolver checks that the domain accepts mail; nil skips the check.
+	Resolver MXResolver
+	// MXTimeout bounds the lookups of Resolver, DefaultMXTimeout if zero.
+	MXTimeout time.Duration
+	// MXFailOpen accepts addresses whose lookup failed for another reason
+	// than the domain not existing, so that a DNS outage does not block
+	// sign-ups.
+	MXFailOpen bool
+	// DisposableDomains rejects addresses of these domains and their
+	// subdomains.
+	DisposableDomains DomainSet
+}
+
+// Check returns the checks email fails as errors.ValidationErrors of the
+// field "email": NOT_ALLOWED for a disposable domain and UNDELIVERABLE for a
+// domain without mail exchanger. A failed lookup is returned as is, unless
+// the policy fails open.
+func (p EmailPolicy) Check(ctx context.Context, email entities.Email) error {
+	var errs errors.ValidationErrors
+
+	err := p.check(ctx, &errs, email)
+	if err != nil {
+		return err
+	}
+
+	return errs.Err()
+}
+
+// check adds the checks email fails to errs.
+func (p EmailPolicy) check(ctx context.Context, errs *errors.ValidationErrors, email entities.Email) error {
+	domain := email.Domain()
+
+	if p.DisposableDomains.Contains(domain) {
+		errs.Add("email", errors.ErrCodeNotAllowed, "disposable email addresses are not allowed", "domain", domain)
+
+		return nil
+	}
+
+	if p.Resolver == nil {
+		return nil
+	}
+
+	deliverable, err := p.acceptsMail(ctx, domain)
+	if err != nil {
+		if p.MXFailOpen {
+			return nil
+		}
+
+		return fmt.Errorf("mx lookup domain=%v: %w", domain, err)
+	}
+
+	if !deliverable {
+		errs.Add("email", errors.ErrCodeUndeliverable, "domain does not accept email", "domain", domain)
+	}
+
+	return nil
+}
+
+// acceptsMail reports whether domain has a mail exchanger: an MX record
+// other than the null MX of RFC 7505 or, without MX records, an address,
+// which RFC 5321 treats as the implicit MX.
+func (p EmailPolicy) acceptsMail(ctx context.Context, domain string) (bool, error) {
+	timeout := p.MXTimeout
+	if timeout <= 0 {
+		timeout = DefaultMXTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	records, err := p.Resolver.LookupMX(ctx, domain)
+	if err != nil && !isNotFound(err) {
+		return false, err //nolint:wrapcheck // Wrapped by check
+	}
+
+	if len(records) > 0 {
+		return len(records) > 1 || strings.TrimSuffix(records[0].Host, ".") != "", nil
+	}
+
+	addresses, err := p.Resolver.LookupHost(ctx, domain)
+	if err != nil && !isNotFound(err) {
+		return false, err //nolint:wrapcheck // Wrapped by check
+	}
+
+	return len(addresses) > 0, nil
+}
+
+// isNotFound reports whether err is a lookup of a name or record that does
+// not exist.
+func isNotFound(err error) bool {
+	var dnsErr *net.DNSError
+
+	return stderrors.As(err, &dnsErr) && dnsErr.IsNotFound
+}
+
+// DomainSet is a set of normalized domain names.
+type DomainSet map[string]struct{}
+
+// NewDomainSet returns the set of domains, normalized as the domains of
+// entities.NewEmail are. Blank entries and comments starting with # are
+// skipped.
+func NewDomainSet(domains ...string) (DomainSet, error) {
+	set := make(DomainSet, len(domains))
+
+	for _, domain := range domains {
+		domain, _, _ = strings.Cut(domain, "#")
+
+		domain = strings.TrimSpace(domain)
+		if domain == "" {
+			continue
+		}
+
+		normalized, err := entities.NormalizeDomain(domain)
+		if err != nil {
+			return nil, fmt.Errorf("domain=%q: %w", domain, err)
+		}
+
+		set[normalized] = struct{}{}
+	}
+
+	return set, nil
+}
+
+// ReadDomainSet reads a DomainSet of one domain per line, as in the
+// disposable email domain lists that are published for this purpose.
+func ReadDomainSet(r io.Reader) (DomainSet, error) {
+	var domains []string
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		domains = append(domains, scanner.Text())
+	}
+
+	err := scanner.Err()
+	if err != nil {
+		return nil, fmt.Errorf("read domains: %w", err)
+	}
+
+	return NewDomainSet(domains...)
+}
+
+// Contains reports whether domain or one of its parent domains is in s.
+func (s DomainSet) Contains(domain string) bool {
+	for domain != "" {
+		if _, ok := s[domain]; ok {
+			return true
+		}
+
+		_, domain, _ = strings.Cut(domain, ".")
+	}
+
+	return false
+}
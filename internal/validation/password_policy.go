@@ -0,0 +1,196 @@
+package validation
+
+import (
+	"context"
+	"crypto/sha1" //nolint:gosec // the k-anonymity range APIs are keyed by SHA-1
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/LarsArtmann/template-sqlc/pkg/errors"
+)
+
+// Defaults of DefaultPasswordPolicy.
+const (
+	DefaultMinPasswordLength   = 8
+	DefaultMaxPasswordLength   = 128
+	DefaultMinCharacterClasses = 3
+)
+
+// breachPrefixLength is the number of hash characters sent to a BreachRangeAPI.
+const breachPrefixLength = 5
+
+// BreachRangeAPI is a k-anonymity breached-password API such as the range
+// API of Have I Been Pwned. Given the first five hexadecimal characters of
+// the SHA-1 hash of a password, it returns the remaining characters of every
+// breached hash with that prefix, in upper case, and how often each was
+// seen. Neither the password nor its full hash leaves the process.
+type BreachRangeAPI interface {
+	Range(ctx context.Context, prefix string) (map[string]int, error)
+}
+
+// PasswordPolicy is a configurable set of password requirements. The zero
+// value of a field disables its requirement.
+type PasswordPolicy struct {
+	MinLength int
+	MaxLength int
+	// MinCharacterClasses is how many of lowercase letters, uppercase
+	// letters, digits and symbols a password must mix.
+	MinCharacterClasses int
+	// MinStrength is the lowest EstimateStrength score accepted.
+	MinStrength StrengthScore
+	// RejectCommon rejects the most common passwords in any letter case.
+	RejectCommon bool
+	// Breaches rejects passwords seen in data breaches; nil skips the check.
+	Breaches BreachRangeAPI
+	// BreachFailOpen accepts passwords whose breach check failed, so that
+	// an outage of the API does not block sign-ups.
+	BreachFailOpen bool
+}
+
+// DefaultPasswordPolicy returns the policy of NewUserValidator: 8 to 128
+// characters of three character classes that are not a common password.
+func DefaultPasswordPolicy() PasswordPolicy {
+	return PasswordPolicy{
+		MinLength:           DefaultMinPasswordLength,
+		MaxLength:           DefaultMaxPasswordLength,
+		MinCharacterClasses: DefaultMinCharacterClasses,
+		RejectCommon:        true,
+	}
+}
+
+// Validate reports contradictory or out-of-range requirements.
+func (p PasswordPolicy) Validate() error {
+	switch {
+	case p.MinLength < 0 || p.MaxLength < 0:
+		return errors.NewValidationError("password_policy", "lengths must not be negative")
+	case p.MaxLength > 0 && p.MinLength > p.MaxLength:
+		return errors.NewValidationError("password_policy",
+			fmt.Sprintf("min_length=%d exceeds max_length=%d", p.MinLength, p.MaxLength))
+	case p.MinCharacterClasses < 0 || p.MinCharacterClasses > len(characterClasses):
+		return errors.NewValidationError("password_policy",
+			fmt.Sprintf("min_character_classes must be between 0 and %d", len(characterClasses)))
+	case p.MinStrength < StrengthTooGuessable || p.MinStrength > StrengthVeryUnguessable:
+		return errors.NewValidationError("password_policy",
+			fmt.Sprintf("min_strength must be between %d and %d", StrengthTooGuessable, StrengthVeryUnguessable))
+	}
+
+	return nil
+}
+
+// Check returns the requirements password misses as
+// errors.ValidationErrors of the field "password". The breach check runs
+// only for a password that meets all others, so that weak passwords are
+// rejected without a request; a failed check is returned as is, unless the
+// policy fails open.
+func (p PasswordPolicy) Check(ctx context.Context, password string) error {
+	var errs errors.ValidationErrors
+
+	validateLength(&errs, "password", password, p.MinLength, p.MaxLength)
+
+	if classes := countCharacterClasses(password); classes < p.MinCharacterClasses {
+		errs.Add("password", errors.ErrCodeTooWeak,
+			fmt.Sprintf("must contain at least %d of: %s", p.MinCharacterClasses, strings.Join(characterClasses, ", ")),
+			"min_classes", p.MinCharacterClasses, "classes", characterClasses)
+	}
+
+	if p.RejectCommon && isCommonPassword(password) {
+		errs.Add("password", errors.ErrCodeNotAllowed, "password is too common, please choose a stronger one")
+	} else if p.MinStrength > StrengthTooGuessable {
+		if score := EstimateStrength(password).Score; score < p.MinStrength {
+			errs.Add("password", errors.ErrCodeTooWeak, "password is too easy to guess, please choose a stronger one",
+				"min_strength", int(p.MinStrength), "strength", int(score))
+		}
+	}
+
+	if len(errs) > 0 || p.Breaches == nil {
+		return errs.Err()
+	}
+
+	breaches, err := breachCount(ctx, p.Breaches, password)
+	if err != nil {
+		if p.BreachFailOpen {
+			return nil
+		}
+
+		return fmt.Errorf("breach check: %w", err)
+	}
+
+	if breaches > 0 {
+		errs.Add("password", errors.ErrCodeNotAllowed,
+			"password appeared in a data breach, please choose a different one")
+	}
+
+	return errs.Err()
+}
+
+// breachCount returns how often password was seen in breaches.
+func breachCount(ctx context.Context, api BreachRangeAPI, password string) (int, error) {
+	sum := sha1.Sum([]byte(password)) //nolint:gosec // see the import
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	counts, err := api.Range(ctx, hash[:breachPrefixLength])
+	if err != nil {
+		return 0, err
+	}
+
+	return counts[hash[breachPrefixLength:]], nil
+}
+
+// characterClasses names the classes countCharacterClasses counts.
+//
+//nolint:gochecknoglobals // Intentional lookup table
+var characterClasses = []string{"uppercase letters", "lowercase letters", "numbers", "special characters"}
+
+// countCharacterClasses counts how many character classes are present.
+func countCharacterClasses(password string) int {
+	var hasUpper, hasLower, hasNumber, hasSpecial bool
+
+	for _, char := range password {
+		switch {
+		case unicode.IsUpper(char):
+			hasUpper = true
+		case unicode.IsLower(char):
+			hasLower = true
+		case unicode.IsNumber(char):
+			hasNumber = true
+		case unicode.IsPunct(char) || unicode.IsSymbol(char):
+			hasSpecial = true
+		}
+	}
+
+	count := 0
+
+	for _, has := range []bool{hasUpper, hasLower, hasNumber, hasSpecial} {
+		if has {
+			count++
+		}
+	}
+
+	return count
+}
+
+// commonPasswords are the most common passwords, most common first. The
+// strength estimate ranks dictionary matches by their position.
+//
+//nolint:gochecknoglobals // Intentional lookup table
+var commonPasswords = []string{
+	"123456", "password", "12345678", "qwerty", "123456789", "12345", "1234", "1234567", "dragon",
+	"baseball", "football", "letmein", "monkey", "abc123", "1234567890", "master", "iloveyou", "welcome",
+	"admin", "hello", "freedom", "whatever", "qazwsx", "trustno1", "123qwe", "1q2w3e4r", "zxcvbnm",
+	"starwars", "soccer", "password123",
+}
+
+// isCommonPassword checks against common passwords.
+func isCommonPassword(password string) bool {
+	lowercase := strings.ToLower(password)
+
+	for _, common := range commonPasswords {
+		if lowercase == common {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,337 @@
+package validation
+
+import (
+	"math"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// StrengthScore rates how hard a password is to guess, from 0 to 4 like
+// the score of zxcvbn.
+type StrengthScore int
+
+// Strength scores by the number of guesses an attacker needs.
+const (
+	// StrengthTooGuessable falls to fewer than 10^3 guesses.
+	StrengthTooGuessable StrengthScore = iota
+	// StrengthVeryGuessable falls to fewer than 10^6 guesses.
+	StrengthVeryGuessable
+	// StrengthSomewhatGuessable falls to fewer than 10^8 guesses.
+	StrengthSomewhatGuessable
+	// StrengthSafelyUnguessable falls to fewer than 10^10 guesses.
+	StrengthSafelyUnguessable
+	// StrengthVeryUnguessable needs at least 10^10 guesses.
+	StrengthVeryUnguessable
+)
+
+// Parameters of the guess estimate, after zxcvbn.
+const (
+	bruteforceCardinality = 10
+	minYearSpace          = 20
+	keyboardStarts        = 94
+	keyboardDegree        = 4
+	minPatternLength      = 3
+	minKeyboardLength     = 4
+)
+
+// strengthThresholds are the guesses at which each score above
+// StrengthTooGuessable starts.
+//
+//nolint:gochecknoglobals // Intentional lookup table
+var strengthThresholds = []float64{1e3, 1e6, 1e8, 1e10}
+
+// keyboardRows are the rows of a QWERTY keyboard.
+//
+//nolint:gochecknoglobals // Intentional lookup table
+var keyboardRows = []string{"1234567890", "qwertyuiop", "asdfghjkl", "zxcvbnm"}
+
+// leetSubstitutions undo common character substitutions.
+//
+//nolint:gochecknoglobals // Intentional lookup table
+var leetSubstitutions = strings.NewReplacer(
+	"4", "a", "@", "a", "3", "e", "1", "i", "!", "i", "0", "o", "$", "s", "5", "s", "7", "t",
+)
+
+// PasswordStrength is an estimate of how hard a password is to guess.
+type PasswordStrength struct {
+	// Guesses is the estimated number of guesses to find the password.
+	Guesses float64
+	Score   StrengthScore
+}
+
+// match is a guessable pattern of a password: runes [start, end).
+type match struct {
+	start, end int
+	guesses    float64
+}
+
+// EstimateStrength estimates the strength of password in the manner of
+// zxcvbn: the password is split into the cheapest sequence of common
+// passwords, including reversed, capitalized and l33t variants, alphabetic
+// and numeric sequences, repeated characters, keyboard runs and years,
+// with any remaining characters guessed by brute force.
+func EstimateStrength(password string) PasswordStrength {
+	runes := []rune(password)
+
+	lower := make([]rune, len(runes))
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+
+	var matches []match
+
+	matches = append(matches, dictionaryMatches(runes, lower)...)
+	matches = append(matches, sequenceMatches(lower)...)
+	matches = append(matches, repeatMatches(lower)...)
+	matches = append(matches, keyboardMatches(lower)...)
+	matches = append(matches, yearMatches(lower)...)
+
+	// best[i] is the fewest guesses for the first i runes.
+	best := make([]float64, len(runes)+1)
+	best[0] = 1
+
+	for end := 1; end <= len(runes); end++ {
+		best[end] = best[end-1] * bruteforceCardinality
+
+		for _, m := range matches {
+			if m.end == end {
+				best[end] = math.Min(best[end], best[m.start]*m.guesses)
+			}
+		}
+	}
+
+	guesses := best[len(runes)]
+	score := StrengthTooGuessable
+
+	for _, threshold := range strengthThresholds {
+		if guesses >= threshold {
+			score++
+		}
+	}
+
+	return PasswordStrength{Guesses: guesses, Score: score}
+}
+
+// dictionaryMatches finds the common passwords in password, forwards and
+// reversed, and after undoing l33t substitutions.
+func dictionaryMatches(runes, lower []rune) []match {
+	var matches []match
+
+	for start := range lower {
+		for end := start + minPatternLength; end <= len(lower); end++ {
+			token := string(lower[start:end])
+			variations := uppercaseVariations(runes[start:end])
+
+			candidates := []struct {
+				word   string
+				factor float64
+			}{
+				{token, 1},
+				{reverse(token), 2},
+				{leetSubstitutions.Replace(token), 2},
+			}
+
+			for _, candidate := range candidates {
+				if candidate.word == token && candidate.factor > 1 {
+					continue
+				}
+
+				if rank := commonPasswordRank(candidate.word); rank > 0 {
+					matches = append(matches, match{start, end, float64(rank) * variations * candidate.factor})
+				}
+			}
+		}
+	}
+
+	return matches
+}
+
+// commonPasswordRank returns the 1-based rank of word among the common
+// passwords, or 0.
+func commonPasswordRank(word string) int {
+	for i, common := range commonPasswords {
+		if word == common {
+			return i + 1
+		}
+	}
+
+	return 0
+}
+
+// uppercaseVariations is the number of ways to capitalize token that are
+// as likely as its own capitalization.
+func uppercaseVariations(token []rune) float64 {
+	var upper, lower int
+
+	for _, r := range token {
+		switch {
+		case unicode.IsUpper(r):
+			upper++
+		case unicode.IsLower(r):
+			lower++
+		}
+	}
+
+	switch {
+	case upper == 0:
+		return 1
+	case lower == 0, upper == 1 && unicode.IsUpper(token[0]):
+		return 2
+	}
+
+	variations := 0.0
+	for i := 1; i <= min(upper, lower); i++ {
+		variations += binomial(upper+lower, i)
+	}
+
+	return variations
+}
+
+// sequenceMatches finds runs like "abc", "9876" or "1357" of one step.
+func sequenceMatches(lower []rune) []match {
+	var matches []match
+
+	for start := 0; start < len(lower)-1; {
+		step := lower[start+1] - lower[start]
+		end := start + 1
+
+		for end < len(lower) && lower[end]-lower[end-1] == step && sameClass(lower[end], lower[start]) {
+			end++
+		}
+
+		alphanumeric := unicode.IsDigit(lower[start]) || unicode.IsLetter(lower[start])
+		sequential := step != 0 && step >= -2 && step <= 2 && alphanumeric && sameClass(lower[start+1], lower[start])
+
+		if end-start >= minPatternLength && sequential {
+			base := 26.0
+
+			switch {
+			case strings.ContainsRune("az019", lower[start]):
+				base = 4
+			case unicode.IsDigit(lower[start]):
+				base = 10
+			}
+
+			if step < 0 {
+				base *= 2
+			}
+
+			matches = append(matches, match{start, end, base * float64(end-start)})
+		}
+
+		start = max(end-1, start+1)
+	}
+
+	return matches
+}
+
+// repeatMatches finds runs of one repeated character.
+func repeatMatches(lower []rune) []match {
+	var matches []match
+
+	for start := 0; start < len(lower); {
+		end := start + 1
+		for end < len(lower) && lower[end] == lower[start] {
+			end++
+		}
+
+		if end-start >= minPatternLength {
+			matches = append(matches, match{start, end, cardinality(lower[start]) * float64(end-start)})
+		}
+
+		start = end
+	}
+
+	return matches
+}
+
+// keyboardMatches finds runs of adjacent keys of one keyboard row, in
+// either direction.
+func keyboardMatches(lower []rune) []match {
+	var matches []match
+
+	for start := range lower {
+		for end := start + minKeyboardLength; end <= len(lower); end++ {
+			token := string(lower[start:end])
+
+			for _, row := range keyboardRows {
+				if strings.Contains(row, token) || strings.Contains(row, reverse(token)) {
+					guesses := keyboardStarts * keyboardDegree * float64(end-start-1)
+					matches = append(matches, match{start, end, guesses})
+				}
+			}
+		}
+	}
+
+	return matches
+}
+
+// yearMatches finds years of the last and the current century.
+func yearMatches(lower []rune) []match {
+	var matches []match
+
+	now := time.Now().Year()
+
+	for start := 0; start+4 <= len(lower); start++ {
+		token := string(lower[start : start+4])
+		if !strings.HasPrefix(token, "19") && !strings.HasPrefix(token, "20") {
+			continue
+		}
+
+		year := 0
+
+		for _, r := range token {
+			if !unicode.IsDigit(r) {
+				year = -1
+
+				break
+			}
+
+			year = year*10 + int(r-'0')
+		}
+
+		if year > 0 {
+			space := max(math.Abs(float64(year-now)), minYearSpace)
+			matches = append(matches, match{start, start + 4, space})
+		}
+	}
+
+	return matches
+}
+
+// cardinality is the size of the character class of r.
+func cardinality(r rune) float64 {
+	switch {
+	case unicode.IsDigit(r):
+		return 10
+	case unicode.IsLetter(r):
+		return 26
+	}
+
+	return 33
+}
+
+// sameClass reports whether a and b are both digits or both letters.
+func sameClass(a, b rune) bool {
+	return unicode.IsDigit(a) == unicode.IsDigit(b) && unicode.IsLetter(a) == unicode.IsLetter(b)
+}
+
+func reverse(s string) string {
+	runes := []rune(s)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+
+	return string(runes)
+}
+
+// binomial returns n choose k.
+func binomial(n, k int) float64 {
+	result := 1.0
+	for i := 1; i <= k; i++ {
+		result = result * float64(n-k+i) / float64(i)
+	}
+
+	return result
+}
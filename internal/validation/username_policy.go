@@ -0,0 +1,242 @@
+package validation
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/pkg/errors"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// UsernamePolicy holds the lists of usernames that cannot be registered
+// beyond their syntax. Both lists match the NormalizeUsername skeletons of
+// usernames, so that "Adm1n" and "a_d-m-i-n" are as reserved as "admin".
+type UsernamePolicy struct {
+	// Reserved are names of the service, such as "admin" or "support".
+	Reserved *NameList
+	// Profanity are offensive names, usually as wildcard patterns.
+	Profanity *NameList
+}
+
+// DefaultUsernamePolicy returns the policy of NewUserValidator: the
+// reserved usernames of entities.ReservedUsernames, which NewUsername
+// rejects regardless of the policy, and no profanity.
+func DefaultUsernamePolicy() UsernamePolicy {
+	reserved := &NameList{}
+	for name := range entities.ReservedUsernames {
+		_ = reserved.Add(name)
+	}
+
+	return UsernamePolicy{Reserved: reserved}
+}
+
+// Check returns the list username is on as errors.ValidationErrors of the
+// field "username", with the list as the param "reason".
+func (p UsernamePolicy) Check(username string) error {
+	var errs errors.ValidationErrors
+
+	p.check(&errs, username)
+
+	return errs.Err()
+}
+
+// check adds the list username is on to errs.
+func (p UsernamePolicy) check(errs *errors.ValidationErrors, username string) {
+	switch {
+	case p.Reserved.Match(username):
+		errs.Add("username", errors.ErrCodeNotAllowed, "username is reserved", "reason", "reserved")
+	case p.Profanity.Match(username):
+		errs.Add("username", errors.ErrCodeNotAllowed, "username is not allowed", "reason", "profanity")
+	}
+}
+
+// NameList is a list of usernames and wildcard patterns, in which * matches
+// any characters and ? one. Entries and usernames are compared by their
+// NormalizeUsername skeletons. The zero value and nil are empty lists.
+type NameList struct {
+	names    map[string]struct{}
+	patterns []string
+}
+
+// NewNameList returns the list of entries, see NameList.Add.
+func NewNameList(entries ...string) (*NameList, error) {
+	list := &NameList{}
+
+	err := list.Add(entries...)
+	if err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// ReadNameList reads a NameList of one entry per line.
+func ReadNameList(r io.Reader) (*NameList, error) {
+	list := &NameList{}
+
+	err := list.AddFrom(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// Add adds entries to the list. Blank entries and comments starting with #
+// are skipped; entries without a character that could be part of a username
+// are rejected, since they would match nothing or everything.
+func (l *NameList) Add(entries ...string) error {
+	for _, entry := range entries {
+		entry, _, _ = strings.Cut(entry, "#")
+
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		skeleton := NormalizeUsername(entry)
+		if strings.Trim(skeleton, "*?") == "" {
+			return errors.NewValidationError("name_list",
+				fmt.Sprintf("entry %q matches no username or every one", entry))
+		}
+
+		if !strings.ContainsAny(skeleton, "*?") {
+			if l.names == nil {
+				l.names = make(map[string]struct{})
+			}
+
+			l.names[skeleton] = struct{}{}
+
+			continue
+		}
+
+		l.patterns = append(l.patterns, skeleton)
+	}
+
+	return nil
+}
+
+// AddFrom adds the entries of r, one per line.
+func (l *NameList) AddFrom(r io.Reader) error {
+	var entries []string
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		entries = append(entries, scanner.Text())
+	}
+
+	err := scanner.Err()
+	if err != nil {
+		return fmt.Errorf("read names: %w", err)
+	}
+
+	return l.Add(entries...)
+}
+
+// Len returns the number of entries.
+func (l *NameList) Len() int {
+	if l == nil {
+		return 0
+	}
+
+	return len(l.names) + len(l.patterns)
+}
+
+// Match reports whether username matches an entry of the list.
+func (l *NameList) Match(username string) bool {
+	if l.Len() == 0 {
+		return false
+	}
+
+	skeleton := NormalizeUsername(username)
+	if _, ok := l.names[skeleton]; ok {
+		return true
+	}
+
+	for _, pattern := range l.patterns {
+		if matchWildcard(pattern, skeleton) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// confusables maps characters that are easily mistaken for a Latin letter,
+// such as digits in leetspeak and Cyrillic and Greek homoglyphs, to it.
+//
+//nolint:gochecknoglobals // Intentional lookup table
+var confusables = map[rune]rune{
+	'0': 'o', '1': 'i', '3': 'e', '4': 'a', '5': 's', '7': 't', '8': 'b', '9': 'g',
+	'@': 'a', '$': 's', '!': 'i', '|': 'i', 'ı': 'i', 'ł': 'l', 'ø': 'o', 'đ': 'd',
+	'а': 'a', 'в': 'b', 'е': 'e', 'к': 'k', 'м': 'm', 'н': 'h', 'о': 'o', 'р': 'p',
+	'с': 'c', 'т': 't', 'у': 'y', 'х': 'x', 'ѕ': 's', 'і': 'i', 'ј': 'j', 'һ': 'h',
+	'ԁ': 'd', 'ԛ': 'q', 'ԝ': 'w', 'ӏ': 'i',
+	'α': 'a', 'β': 'b', 'ε': 'e', 'η': 'n', 'ι': 'i', 'κ': 'k', 'ν': 'v', 'ο': 'o',
+	'ρ': 'p', 'τ': 't', 'υ': 'u', 'χ': 'x',
+}
+
+// NormalizeUsername returns the skeleton usernames are compared by: with
+// compatibility forms such as full-width letters folded, case folded,
+// without accents, with confusable characters replaced by the Latin letter
+// they resemble and without the separators _, - and . in between, so that
+// "ＡＤＭÍＮ" and "4d_m1n" become "admin".
+func NormalizeUsername(username string) string {
+	// A chain keeps state, so it is built per call.
+	fold := transform.Chain(norm.NFKD, cases.Fold(), runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+
+	folded, _, err := transform.String(fold, username)
+	if err != nil {
+		folded = strings.ToLower(username)
+	}
+
+	return strings.Map(func(r rune) rune {
+		if latin, ok := confusables[r]; ok {
+			return latin
+		}
+
+		switch {
+		case r == '_' || r == '-' || r == '.' || unicode.IsSpace(r):
+			return -1
+		default:
+			return r
+		}
+	}, folded)
+}
+
+// matchWildcard reports whether name matches pattern, in which * matches
+// any characters and ? one.
+func matchWildcard(pattern, name string) bool {
+	p, n := []rune(pattern), []rune(name)
+	i, j := 0, 0
+	star, backtrack := -1, 0
+
+	for j < len(n) {
+		switch {
+		case i < len(p) && (p[i] == '?' || p[i] == n[j]):
+			i++
+			j++
+		case i < len(p) && p[i] == '*':
+			star, backtrack = i, j
+			i++
+		case star >= 0:
+			backtrack++
+			i, j = star+1, backtrack
+		default:
+			return false
+		}
+	}
+
+	for i < len(p) && p[i] == '*' {
+		i++
+	}
+
+	return i == len(p)
+}
@@ -3,8 +3,10 @@
 package validation
 
 import (
+	"context"
 	"fmt"
 	"regexp"
+	"slices"
 	"strings"
 	"unicode"
 
@@ -12,51 +14,78 @@ import (
 	"github.com/LarsArtmann/template-sqlc/pkg/errors"
 )
 
+// Limits of searches and tags.
 const (
-	minPasswordLength      = 8
-	maxPasswordLength      = 128
-	minCharacterCategories = 3
+	maxPaginationLimit   = 1000
+	maxSearchQueryLength = 500
+	maxTags              = 50
+	maxTagLength         = 50
 )
 
 // UserValidator implements user validation logic.
 type UserValidator struct {
 	usernameRegex *regexp.Regexp
+	passwords     PasswordPolicy
+	emails        EmailPolicy
+	usernames     UsernamePolicy
 }
 
-// NewUserValidator creates a new user validator.
-func NewUserValidator() *UserValidator {
-	return &UserValidator{
-		usernameRegex: regexp.MustCompile(`^[a-zA-Z0-9_-]{3,50}$`),
+// UserValidatorOption configures a UserValidator.
+type UserValidatorOption func(*UserValidator)
+
+// WithPasswordPolicy replaces DefaultPasswordPolicy.
+func WithPasswordPolicy(policy PasswordPolicy) UserValidatorOption {
+	return func(v *UserValidator) {
+		v.passwords = policy
 	}
 }
 
-// ValidateUserCreate validates user creation request.
-func (v *UserValidator) ValidateUserCreate(email, username, firstName, lastName string) error {
-	// Validate email
-	err := v.validateEmail(email)
-	if err != nil {
-		return fmt.Errorf("email=%v: %w", email, err)
+// WithEmailPolicy sets the checks of email addresses beyond their syntax,
+// which none are by default.
+func WithEmailPolicy(policy EmailPolicy) UserValidatorOption {
+	return func(v *UserValidator) {
+		v.emails = policy
 	}
+}
 
-	// Validate username
-	err = v.validateUsername(username)
-	if err != nil {
-		return fmt.Errorf("username=%v: %w", username, err)
+// WithUsernamePolicy replaces DefaultUsernamePolicy.
+func WithUsernamePolicy(policy UsernamePolicy) UserValidatorOption {
+	return func(v *UserValidator) {
+		v.usernames = policy
 	}
+}
 
-	// Validate first name
-	err = v.validateName("first_name", firstName)
-	if err != nil {
-		return fmt.Errorf("firstName=%v: %w", firstName, err)
+// NewUserValidator creates a new user validator.
+func NewUserValidator(opts ...UserValidatorOption) *UserValidator {
+	validator := &UserValidator{
+		usernameRegex: regexp.MustCompile(`^[a-zA-Z0-9_-]{3,50}$`),
+		passwords:     DefaultPasswordPolicy(),
+		usernames:     DefaultUsernamePolicy(),
 	}
 
-	// Validate last name
-	err = v.validateName("last_name", lastName)
+	for _, opt := range opts {
+		opt(validator)
+	}
+
+	return validator
+}
+
+// ValidateUserCreate validates a user creation request. It reports the first
+// failure of every field as errors.ValidationErrors, or the error of a failed
+// lookup of the email policy.
+func (v *UserValidator) ValidateUserCreate(ctx context.Context, email, username, firstName, lastName string) error {
+	var errs errors.ValidationErrors
+
+	err := v.checkEmail(ctx, &errs, email)
 	if err != nil {
-		return fmt.Errorf("lastName=%v: %w", lastName, err)
+		return err
 	}
 
-	return nil
+	v.validateUsername(&errs, username)
+	v.validateName(&errs, "first_name", firstName)
+	v.validateName(&errs, "last_name", lastName)
+
+	return errs.Err()
 }
 
 // ValidateUserUpdate validates user update request.
@@ -73,289 +102,196 @@ func (v *UserValidator) ValidateUserUpdate(user *entities.User) error {
 	return nil
 }
 
-// ValidatePasswordRequirements validates password strength.
-func (v *UserValidator) ValidatePasswordRequirements(password string) error {
-	err := v.validatePasswordLength(password)
+// ValidatePasswordRequirements checks password against the password policy.
+func (v *UserValidator) ValidatePasswordRequirements(ctx context.Context, password string) error {
+	return v.passwords.Check(ctx, password)
+}
+
+// ValidateEmail validates an email address by its syntax and the email
+// policy, as ValidateUserCreate does.
+func (v *UserValidator) ValidateEmail(ctx context.Context, email string) error {
+	var errs errors.ValidationErrors
+
+	err := v.checkEmail(ctx, &errs, email)
 	if err != nil {
 		return err
 	}
 
-	if v.countCharacterCategories(password) < minCharacterCategories {
-		return errors.NewValidationError(
-			"password",
-			"must contain at least 3 of: uppercase letters, lowercase letters, numbers, special characters",
-		)
-	}
+	return errs.Err()
+}
 
-	if v.isCommonPassword(password) {
-		return errors.NewValidationError(
-			"password",
-			"password is too common, please choose a stronger one",
-		)
-	}
+// ValidateUsername validates a username by its syntax and the username
+// policy, as ValidateUserCreate does.
+func (v *UserValidator) ValidateUsername(username string) error {
+	var errs errors.ValidationErrors
 
-	return nil
-}
+	v.validateUsername(&errs, username)
 
-// validateLength checks string length constraints.
-func validateLength(field, value string, minLen, maxLen int) error {
-	if minLen > 0 && len(value) < minLen {
-		return errors.NewValidationError(
-			field,
-			fmt.Sprintf("must be at least %d characters", minLen),
-		)
-	}
+	return errs.Err()
+}
 
-	if maxLen > 0 && len(value) > maxLen {
-		return errors.NewValidationError(
-			field,
-			fmt.Sprintf("must not exceed %d characters", maxLen),
-		)
+// checkEmail adds the failures of email to errs, running the email policy
+// only on a valid address.
+func (v *UserValidator) checkEmail(ctx context.Context, errs *errors.ValidationErrors, email string) error {
+	address, ok := v.validateEmail(errs, email)
+	if !ok {
+		return nil
 	}
 
-	return nil
+	return v.emails.check(ctx, errs, address)
 }
 
-// validatePasswordLength checks password length requirements.
-func (v *UserValidator) validatePasswordLength(password string) error {
-	return validateLength("password", password, minPasswordLength, maxPasswordLength)
-}
+// validateLength adds an error of field if value is outside [minLen, maxLen];
+// a limit of 0 is not checked. It reports whether value is within.
+func validateLength(errs *errors.ValidationErrors, field, value string, minLen, maxLen int) bool {
+	if minLen > 0 && len(value) < minLen {
+		errs.Add(field, errors.ErrCodeTooShort, fmt.Sprintf("must be at least %d characters", minLen), "min", minLen)
 
-// countCharacterCategories counts how many character categories are present.
-func (v *UserValidator) countCharacterCategories(password string) int {
-	var hasUpper, hasLower, hasNumber, hasSpecial bool
-
-	for _, char := range password {
-		switch {
-		case unicode.IsUpper(char):
-			hasUpper = true
-		case unicode.IsLower(char):
-			hasLower = true
-		case unicode.IsNumber(char):
-			hasNumber = true
-		case unicode.IsPunct(char) || unicode.IsSymbol(char):
-			hasSpecial = true
-		}
+		return false
 	}
 
-	count := 0
+	if maxLen > 0 && len(value) > maxLen {
+		errs.Add(field, errors.ErrCodeTooLong, fmt.Sprintf("must not exceed %d characters", maxLen), "max", maxLen)
 
-	for _, has := range []bool{hasUpper, hasLower, hasNumber, hasSpecial} {
-		if has {
-			count++
-		}
+		return false
 	}
 
-	return count
+	return true
 }
 
-// ValidateEmail validates email format.
-func (v *UserValidator) validateEmail(email string) error {
+// validateEmail validates the syntax of email, as entities.NewEmail does,
+// and returns the address if it is valid.
+func (v *UserValidator) validateEmail(errs *errors.ValidationErrors, email string) (entities.Email, bool) {
 	email = strings.TrimSpace(email)
 
 	if email == "" {
-		return errors.NewMissingFieldError("email")
-	}
+		errs.Add("email", errors.ErrCodeMissingField, "is required")
 
-	if len(email) > 254 { //nolint:mnd // RFC 5321
-		return errors.NewValidationError("email", "must not exceed 254 characters")
+		return "", false
 	}
 
-	if !entities.EmailRegex.MatchString(email) {
-		return errors.NewInvalidFormatError("email", "must be a valid email address")
+	if !validateLength(errs, "email", email, 0, 254) { //nolint:mnd // RFC 5321
+		return "", false
 	}
 
-	// Additional checks
-	if strings.Count(email, "@") != 1 {
-		return errors.NewInvalidFormatError("email", "must contain exactly one @ symbol")
-	}
-
-	parts := strings.Split(email, "@")
-	localPart := parts[0]
-	domainPart := parts[1]
-
-	if len(localPart) == 0 {
-		return errors.NewInvalidFormatError("email", "local part cannot be empty")
-	}
-
-	if len(domainPart) == 0 {
-		return errors.NewInvalidFormatError("email", "domain part cannot be empty")
-	}
+	address, err := entities.NewEmail(email)
+	if err != nil {
+		errs.Add("email", errors.ErrCodeInvalidFormat, "must be a valid email address")
 
-	// Check for invalid characters in local part
-	if strings.Contains(localPart, "..") {
-		return errors.NewInvalidFormatError("email", "cannot contain consecutive dots")
+		return "", false
 	}
 
-	return nil
+	return address, true
 }
 
-// ValidateUsername validates username format.
-func (v *UserValidator) validateUsername(username string) error {
+// validateUsername validates username format.
+func (v *UserValidator) validateUsername(errs *errors.ValidationErrors, username string) {
 	username = strings.TrimSpace(username)
 
 	if username == "" {
-		return errors.NewMissingFieldError("username")
-	}
+		errs.Add("username", errors.ErrCodeMissingField, "is required")
 
-	if len(username) < 3 { //nolint:mnd // minimum username length
-		return errors.NewValidationError("username", "must be at least 3 characters long")
+		return
 	}
 
-	if len(username) > 50 { //nolint:mnd // maximum username length
-		return errors.NewValidationError("username", "must not exceed 50 characters")
+	if !validateLength(errs, "username", username, 3, 50) { //nolint:mnd // username length
+		return
 	}
 
 	if !v.usernameRegex.MatchString(username) {
-		return errors.NewInvalidFormatError(
-			"username",
-			"can only contain letters, numbers, underscores, and hyphens",
-		)
-	}
+		errs.Add("username", errors.ErrCodeInvalidFormat, "can only contain letters, numbers, underscores, and hyphens")
 
-	// Check for reserved usernames
-	if v.isReservedUsername(username) {
-		return errors.NewValidationError("username", "username is reserved")
+		return
 	}
 
-	return nil
+	v.usernames.check(errs, username)
 }
 
-// ValidateName validates first/last name.
-func (v *UserValidator) validateName(field, name string) error {
+// validateName validates first/last name.
+func (v *UserValidator) validateName(errs *errors.ValidationErrors, field, name string) {
 	name = strings.TrimSpace(name)
 
 	if name == "" {
-		return errors.NewMissingFieldError(field)
+		errs.Add(field, errors.ErrCodeMissingField, "is required")
+
+		return
 	}
 
-	if len(name) > 100 { //nolint:mnd // maximum name length
-		return errors.NewValidationError(field, "must not exceed 100 characters")
+	if !validateLength(errs, field, name, 0, 100) { //nolint:mnd // maximum name length
+		return
 	}
 
 	// Basic validation - names should contain letters and possibly spaces/hyphens
 	for _, char := range name {
 		if !unicode.IsLetter(char) && char != ' ' && char != '-' && char != '\'' {
-			return errors.NewInvalidFormatError(
-				field,
-				"can only contain letters, spaces, hyphens, and apostrophes",
-			)
-		}
-	}
-
-	return nil
-}
-
-// isReservedUsername checks if username is reserved.
-func (v *UserValidator) isReservedUsername(username string) bool {
-	lowercase := strings.ToLower(username)
+			errs.Add(field, errors.ErrCodeInvalidFormat, "can only contain letters, spaces, hyphens, and apostrophes")
 
-	return entities.ReservedUsernames[lowercase]
-}
-
-// isCommonPassword checks against common passwords.
-func (v *UserValidator) isCommonPassword(password string) bool {
-	// In a real implementation, you'd use a comprehensive list
-	// or integrate with a service like HaveIBeenPwned
-	commonPasswords := map[string]bool{
-		"password":    true,
-		"123456":      true,
-		"123456789":   true,
-		"12345678":    true,
-		"12345":       true,
-		"1234567":     true,
-		"1234567890":  true,
-		"qwerty":      true,
-		"abc123":      true,
-		"password123": true,
-		"admin":       true,
-		"letmein":     true,
-		"welcome":     true,
-		"monkey":      true,
-		"1234":        true,
-		"dragon":      true,
-		"master":      true,
-		"hello":       true,
-		"freedom":     true,
-		"whatever":    true,
-		"qazwsx":      true,
-		"trustno1":    true,
-		"123qwe":      true,
-		"1q2w3e4r":    true,
-		"zxcvbnm":     true,
-		"iloveyou":    true,
-		"starwars":    true,
-		"football":    true,
-		"baseball":    true,
-		"soccer":      true,
+			return
+		}
 	}
-
-	lowercase := strings.ToLower(password)
-
-	return commonPasswords[lowercase]
 }
 
 // ValidateUserRole validates user role.
 func (v *UserValidator) ValidateUserRole(role string) error {
-	validRoles := map[string]bool{
-		string(entities.UserRoleUser):      true,
-		string(entities.UserRoleAdmin):     true,
-		string(entities.UserRoleModerator): true,
-	}
-
-	if !validRoles[role] {
-		return errors.NewValidationError("role", "must be one of: user, admin, moderator")
-	}
-
-	return nil
+	return validateOneOf("role", role, []string{
+		string(entities.UserRoleUser),
+		string(entities.UserRoleAdmin),
+		string(entities.UserRoleModerator),
+	})
 }
 
 // ValidateUserStatus validates user status.
 func (v *UserValidator) ValidateUserStatus(status string) error {
-	validStatuses := map[string]bool{
-		string(entities.UserStatusActive):    true,
-		string(entities.UserStatusInactive):  true,
-		string(entities.UserStatusSuspended): true,
-		string(entities.UserStatusPending):   true,
-	}
+	return validateOneOf("status", status, []string{
+		string(entities.UserStatusActive),
+		string(entities.UserStatusInactive),
+		string(entities.UserStatusSuspended),
+		string(entities.UserStatusPending),
+	})
+}
 
-	if !validStatuses[status] {
-		return errors.NewValidationError(
-			"status",
-			"must be one of: active, inactive, suspended, pending",
-		)
+// validateOneOf returns an error of field unless value is one of allowed.
+func validateOneOf(field, value string, allowed []string) error {
+	if slices.Contains(allowed, value) {
+		return nil
 	}
 
-	return nil
+	var errs errors.ValidationErrors
+	errs.Add(field, errors.ErrCodeNotAllowed, "must be one of: "+strings.Join(allowed, ", "), "allowed", allowed)
+
+	return errs
 }
 
 // ValidatePagination validates pagination parameters.
 func (v *UserValidator) ValidatePagination(limit, offset int) error {
-	if limit < 1 {
-		return errors.NewValidationError("limit", "must be at least 1")
-	}
+	var errs errors.ValidationErrors
 
-	if limit > 1000 { //nolint:mnd // max pagination limit
-		return errors.NewValidationError("limit", "must not exceed 1000")
+	if limit < 1 || limit > maxPaginationLimit {
+		errs.Add("limit", errors.ErrCodeOutOfRange,
+			fmt.Sprintf("must be between 1 and %d", maxPaginationLimit), "min", 1, "max", maxPaginationLimit)
 	}
 
 	if offset < 0 {
-		return errors.NewValidationError("offset", "must be non-negative")
+		errs.Add("offset", errors.ErrCodeOutOfRange, "must be non-negative", "min", 0)
 	}
 
-	return nil
+	return errs.Err()
 }
 
 // ValidateSearchQuery validates search query.
 func (v *UserValidator) ValidateSearchQuery(query string) error {
+	var errs errors.ValidationErrors
+
 	query = strings.TrimSpace(query)
 
 	if query == "" {
-		return errors.NewValidationError("query", "search query cannot be empty")
+		errs.Add("query", errors.ErrCodeMissingField, "search query cannot be empty")
+
+		return errs
 	}
 
-	if len(query) > 500 { //nolint:mnd // max search query length
-		return errors.NewValidationError("query", "search query must not exceed 500 characters")
+	if !validateLength(&errs, "query", query, 0, maxSearchQueryLength) {
+		return errs
 	}
 
 	// Basic validation - prevent injection attempts
@@ -375,46 +311,56 @@ func (v *UserValidator) ValidateSearchQuery(query string) error {
 	lowercase := strings.ToLower(query)
 	for _, pattern := range invalidPatterns {
 		if strings.Contains(lowercase, pattern) {
-			return errors.NewValidationError("query", "search query contains invalid characters")
+			errs.Add("query", errors.ErrCodeNotAllowed, "search query contains invalid characters")
+
+			break
 		}
 	}
 
-	return nil
+	return errs.Err()
 }
 
-// ValidateTags validates user tags.
+// ValidateTags validates user tags. Errors of single tags are reported by
+// index, as "tags[2]".
 func (v *UserValidator) ValidateTags(tags []string) error {
-	if len(tags) > 50 { //nolint:mnd // max tags count
-		return errors.NewValidationError("tags", "cannot have more than 50 tags")
+	var errs errors.ValidationErrors
+
+	if len(tags) > maxTags {
+		errs.Add("tags", errors.ErrCodeOutOfRange,
+			fmt.Sprintf("cannot have more than %d tags", maxTags), "max", maxTags)
 	}
 
 	seen := make(map[string]bool)
 
-	for _, tag := range tags {
+	for i, tag := range tags {
 		tag = strings.TrimSpace(tag)
 
 		if tag == "" {
 			continue // Skip empty tags
 		}
 
-		if len(tag) > 50 { //nolint:mnd // max tag length
-			return errors.NewValidationError("tags", "tag must not exceed 50 characters")
+		field := fmt.Sprintf("tags[%d]", i)
+
+		if !validateLength(&errs, field, tag, 0, maxTagLength) {
+			continue
 		}
 
 		// Basic validation for tag content
-		if strings.Contains(tag, " ") || strings.Contains(tag, "\t") {
-			return errors.NewValidationError("tags", "tag cannot contain whitespace")
+		if strings.ContainsAny(tag, " \t") {
+			errs.Add(field, errors.ErrCodeInvalidFormat, "tag cannot contain whitespace")
+
+			continue
 		}
 
 		lowercase := strings.ToLower(tag)
 		if seen[lowercase] {
-			return errors.NewValidationError("tags", "duplicate tag found")
+			errs.Add(field, errors.ErrCodeNotAllowed, "duplicate tag found")
 		}
 
 		seen[lowercase] = true
 	}
 
-	return nil
+	return errs.Err()
 }
 
 // SanitizeString sanitizes input string.
@@ -0,0 +1,279 @@
+// Package codegen runs sqlc generate from Go and skips the run when nothing
+// changed since the last one.
+//
+// The inputs of a run are the sqlc configuration file and every schema and
+// query file it references. Their hash is stored in a cache file next to the
+// configuration; a later run with the same hash, and with all output
+// directories present, is reported as cached instead of invoking sqlc.
+package codegen
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+)
+
+// DefaultCacheFile is the cache file name, relative to the configuration.
+const DefaultCacheFile = ".sqlc-cache.json"
+
+// ErrNoConfig is returned when the sqlc configuration file does not exist.
+var ErrNoConfig = errors.New("sqlc configuration not found")
+
+// Generator runs sqlc generate for a configuration, writing its output to out.
+type Generator interface {
+	Generate(ctx context.Context, dir, config string, out io.Writer) error
+}
+
+// Exec runs the sqlc binary.
+type Exec struct {
+	// Binary is the sqlc executable, looked up in PATH if it has no separator.
+	Binary string
+}
+
+// Generate runs `sqlc generate -f config` in dir.
+func (e Exec) Generate(ctx context.Context, dir, config string, out io.Writer) error {
+	binary, err := exec.LookPath(e.Binary)
+	if err != nil {
+		return fmt.Errorf("binary=%v: %w", e.Binary, err)
+	}
+
+	cmd := exec.CommandContext(ctx, binary, "generate", "-f", config)
+	cmd.Dir = dir
+	cmd.Stdout = out
+	cmd.Stderr = out
+
+	err = cmd.Run()
+	if err != nil {
+		return fmt.Errorf("sqlc generate: %w", err)
+	}
+
+	return nil
+}
+
+// Observer records code generation runs; *monitoring.Metrics implements it.
+type Observer interface {
+	ObserveCodeGen(duration time.Duration, err error)
+}
+
+// Stage is the step a progress Event reports.
+type Stage string
+
+// Stages of a run, in order. A run ends with StageCached, StageDone or
+// StageFailed.
+const (
+	StageHashing    Stage = "hashing"
+	StageCached     Stage = "cached"
+	StageGenerating Stage = "generating"
+	StageOutput     Stage = "output"
+	StageDone       Stage = "done"
+	StageFailed     Stage = "failed"
+)
+
+// Event reports the progress of a run.
+type Event struct {
+	Stage   Stage         `json:"stage"`
+	Config  string        `json:"config"`
+	Message string        `json:"message,omitempty"`
+	Elapsed time.Duration `json:"elapsed"`
+	Err     error         `json:"-"`
+}
+
+// Result describes a finished run.
+type Result struct {
+	// Cached is true when sqlc was skipped because the inputs were unchanged.
+	Cached bool
+	// Hash is the hash of the configuration, schema and query files.
+	Hash string
+	// Inputs are the files that were hashed, relative to the configuration.
+	Inputs   []string
+	Duration time.Duration
+}
+
+// Runner runs sqlc generate with caching.
+type Runner struct {
+	generator Generator
+	observer  Observer
+	progress  func(Event)
+	cacheFile string
+	force     bool
+}
+
+// Option configures a Runner.
+type Option func(*Runner)
+
+// WithGenerator replaces the sqlc binary, e.g. with a pinned version.
+func WithGenerator(generator Generator) Option {
+	return func(r *Runner) {
+		r.generator = generator
+	}
+}
+
+// WithObserver records every generation, but not cached runs.
+func WithObserver(observer Observer) Option {
+	return func(r *Runner) {
+		r.observer = observer
+	}
+}
+
+// WithProgress streams the events of each run to fn, including every line
+// sqlc prints. Events are delivered one at a time, in order.
+func WithProgress(fn func(Event)) Option {
+	return func(r *Runner) {
+		r.progress = fn
+	}
+}
+
+// WithCacheFile sets the cache file, relative to the configuration's
+// directory unless absolute.
+func WithCacheFile(name string) Option {
+	return func(r *Runner) {
+		r.cacheFile = name
+	}
+}
+
+// WithForce regenerates even when the inputs are unchanged.
+func WithForce(force bool) Option {
+	return func(r *Runner) {
+		r.force = force
+	}
+}
+
+// New creates a Runner using the sqlc binary from PATH.
+func New(opts ...Option) *Runner {
+	runner := &Runner{
+		generator: Exec{Binary: "sqlc"},
+		progress:  func(Event) {},
+		cacheFile: DefaultCacheFile,
+	}
+
+	for _, opt := range opts {
+		opt(runner)
+	}
+
+	return runner
+}
+
+// Run generates the code of the sqlc configuration file config, unless its
+// inputs are unchanged since the last successful run.
+func (r *Runner) Run(ctx context.Context, config string) (_ *Result, err error) {
+	start := time.Now()
+	emit := func(stage Stage, message string, err error) {
+		r.progress(Event{Stage: stage, Config: config, Message: message, Elapsed: time.Since(start), Err: err})
+	}
+
+	defer func() {
+		if err != nil {
+			emit(StageFailed, err.Error(), err)
+		}
+	}()
+
+	emit(StageHashing, "", nil)
+
+	inputs, err := collectInputs(config)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{Hash: inputs.hash, Inputs: inputs.files}
+	cachePath := r.cachePath(config)
+
+	cache, err := loadCache(cachePath)
+	if err != nil {
+		return nil, err
+	}
+
+	key := filepath.Base(config)
+
+	if !r.force && cache[key] == inputs.hash && outputsExist(inputs.outputs) {
+		result.Cached = true
+		result.Duration = time.Since(start)
+		emit(StageCached, inputs.hash, nil)
+
+		return result, nil
+	}
+
+	emit(StageGenerating, "", nil)
+
+	err = r.generate(ctx, config, emit)
+	result.Duration = time.Since(start)
+
+	if r.observer != nil {
+		r.observer.ObserveCodeGen(result.Duration, err)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	cache[key] = inputs.hash
+
+	err = saveCache(cachePath, cache)
+	if err != nil {
+		return nil, err
+	}
+
+	emit(StageDone, inputs.hash, nil)
+
+	return result, nil
+}
+
+// generate runs the generator and streams its output line by line.
+func (r *Runner) generate(ctx context.Context, config string, emit func(Stage, string, error)) error {
+	var output bytes.Buffer
+
+	reader, writer := io.Pipe()
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		scanner := bufio.NewScanner(reader)
+		for scanner.Scan() {
+			line := scanner.Text()
+			output.WriteString(line + "\n")
+			emit(StageOutput, line, nil)
+		}
+
+		_, _ = io.Copy(io.Discard, reader)
+	}()
+
+	err := r.generator.Generate(ctx, filepath.Dir(config), filepath.Base(config), writer)
+	_ = writer.Close()
+	<-done
+
+	if err != nil {
+		if message := bytes.TrimSpace(output.Bytes()); len(message) > 0 {
+			return fmt.Errorf("config=%v: %w\n%s", config, err, message)
+		}
+
+		return fmt.Errorf("config=%v: %w", config, err)
+	}
+
+	return nil
+}
+
+func (r *Runner) cachePath(config string) string {
+	if filepath.IsAbs(r.cacheFile) {
+		return r.cacheFile
+	}
+
+	return filepath.Join(filepath.Dir(config), r.cacheFile)
+}
+
+func outputsExist(outputs []string) bool {
+	for _, out := range outputs {
+		info, err := os.Stat(out)
+		if err != nil || !info.IsDir() {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,160 @@
+package codegen
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/LarsArtmann/template-sqlc/pkg/sqlcconfig"
+	"go.yaml.in/yaml/v3"
+)
+
+// inputs are the files a run depends on and the directories it writes.
+type inputs struct {
+	hash    string
+	files   []string
+	outputs []string
+}
+
+// collectInputs hashes the configuration and the schema and query files of
+// every sql entry. Paths are hashed relative to the configuration, so moving
+// the checkout does not invalidate the cache.
+func collectInputs(config string) (*inputs, error) {
+	data, err := os.ReadFile(config)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("config=%v: %w", config, ErrNoConfig)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("config=%v: %w", config, err)
+	}
+
+	parsed := &sqlcconfig.Config{}
+
+	err = yaml.Unmarshal(data, parsed)
+	if err != nil {
+		return nil, fmt.Errorf("config=%v: %w", config, err)
+	}
+
+	dir := filepath.Dir(config)
+	files := []string{filepath.Base(config)}
+	collected := &inputs{}
+
+	for _, sql := range parsed.SQL {
+		for _, path := range slices.Concat(sql.Schema, sql.Queries) {
+			found, err := sqlFiles(dir, path)
+			if err != nil {
+				return nil, fmt.Errorf("config=%v sql=%v: %w", config, sql.Name, err)
+			}
+
+			files = append(files, found...)
+		}
+
+		if sql.Gen.Go != nil {
+			collected.outputs = append(collected.outputs, filepath.Join(dir, sql.Gen.Go.Out))
+		}
+	}
+
+	slices.Sort(files[1:])
+	files = slices.Compact(files)
+
+	hash := sha256.New()
+
+	for _, file := range files {
+		content, err := os.ReadFile(filepath.Join(dir, file))
+		if err != nil {
+			return nil, fmt.Errorf("file=%v: %w", file, err)
+		}
+
+		fmt.Fprintf(hash, "%s\x00%d\x00", filepath.ToSlash(file), len(content))
+		hash.Write(content)
+	}
+
+	collected.hash = hex.EncodeToString(hash.Sum(nil))
+	collected.files = files
+
+	return collected, nil
+}
+
+// sqlFiles returns path if it is a file, or the .sql files below it, relative
+// to dir.
+func sqlFiles(dir, path string) ([]string, error) {
+	root := filepath.Join(dir, path)
+
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, fmt.Errorf("path=%v: %w", path, err)
+	}
+
+	if !info.IsDir() {
+		return []string{filepath.Clean(path)}, nil
+	}
+
+	var files []string
+
+	err = filepath.WalkDir(root, func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() || filepath.Ext(name) != ".sql" {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, name)
+		if err != nil {
+			return err
+		}
+
+		files = append(files, rel)
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("path=%v: %w", path, err)
+	}
+
+	return files, nil
+}
+
+// loadCache reads the input hashes of the last successful runs, keyed by
+// configuration file name.
+func loadCache(path string) (map[string]string, error) {
+	cache := map[string]string{}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cache, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("cache=%v: %w", path, err)
+	}
+
+	// A corrupt cache only costs a regeneration.
+	if json.Unmarshal(data, &cache) != nil {
+		return map[string]string{}, nil
+	}
+
+	return cache, nil
+}
+
+func saveCache(path string, cache map[string]string) error {
+	data, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return fmt.Errorf("cache=%v: %w", path, err)
+	}
+
+	err = os.WriteFile(path, append(data, '\n'), 0o644)
+	if err != nil {
+		return fmt.Errorf("cache=%v: %w", path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,392 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/events"
+	"github.com/LarsArtmann/template-sqlc/pkg/db"
+	"go.yaml.in/yaml/v3"
+)
+
+// FileEnv names the YAML configuration file when -config is not given.
+const FileEnv = "CONFIG_FILE"
+
+// Environment variables of the connection settings, shared by every command.
+const (
+	DriverEnv = "DATABASE_DRIVER"
+	DSNEnv    = "DATABASE_DSN"
+)
+
+// setting is one configuration value settable from the environment and a flag.
+type setting struct {
+	env     string
+	flag    string
+	usage   string
+	boolean bool
+	set     func(cfg *Config, value string) error
+}
+
+// settings lists every value that can be set from the environment and flags.
+func settings() []setting {
+	return []setting{
+		{env: DriverEnv, flag: "driver", usage: "database engine: sqlite, postgres or mysql",
+			set: func(cfg *Config, value string) error { cfg.Database.Driver = db.Driver(value); return nil }},
+		stringSetting(DSNEnv, "dsn", "data source name of the selected engine",
+			func(cfg *Config) *string { return &cfg.Database.DSN }),
+		stringSetting("DATABASE_SQLITE_DSN", "sqlite-dsn", "SQLite data source name",
+			func(cfg *Config) *string { return &cfg.Database.SQLite }),
+		stringSetting("DATABASE_POSTGRES_DSN", "postgres-dsn", "PostgreSQL data source name",
+			func(cfg *Config) *string { return &cfg.Database.Postgres }),
+		stringSetting("DATABASE_MYSQL_DSN", "mysql-dsn", "MySQL data source name",
+			func(cfg *Config) *string { return &cfg.Database.MySQL }),
+		intSetting("DATABASE_MAX_OPEN_CONNS", "db-max-open-conns", "maximum open connections",
+			func(cfg *Config) *int { return &cfg.Database.Pool.MaxOpenConns }),
+		intSetting("DATABASE_MAX_IDLE_CONNS", "db-max-idle-conns", "maximum idle connections",
+			func(cfg *Config) *int { return &cfg.Database.Pool.MaxIdleConns }),
+		durationSetting("DATABASE_CONN_MAX_LIFETIME", "db-conn-max-lifetime", "maximum connection lifetime",
+			func(cfg *Config) *time.Duration { return &cfg.Database.Pool.ConnMaxLifetime }),
+		durationSetting("DATABASE_CONN_MAX_IDLE_TIME", "db-conn-max-idle-time", "maximum connection idle time",
+			func(cfg *Config) *time.Duration { return &cfg.Database.Pool.ConnMaxIdleTime }),
+		durationSetting("DATABASE_READ_TIMEOUT", "db-read-timeout", "timeout of repository reads",
+			func(cfg *Config) *time.Duration { return &cfg.Database.Timeouts.Read }),
+		durationSetting("DATABASE_WRITE_TIMEOUT", "db-write-timeout", "timeout of repository writes",
+			func(cfg *Config) *time.Duration { return &cfg.Database.Timeouts.Write }),
+		durationSetting("DATABASE_SEARCH_TIMEOUT", "db-search-timeout", "timeout of repository lists and searches",
+			func(cfg *Config) *time.Duration { return &cfg.Database.Timeouts.Search }),
+		boolSetting("DATABASE_EVENT_SOURCING", "db-event-sourcing", "load users from their event streams",
+			func(cfg *Config) *bool { return &cfg.Database.EventSourcing.Enabled }),
+		intSetting("DATABASE_SNAPSHOT_EVERY", "db-snapshot-every", "events between snapshots of event-sourced users",
+			func(cfg *Config) *int { return &cfg.Database.EventSourcing.SnapshotEvery }),
+		stringSetting("HTTP_ADDR", "http-addr", "GraphQL listen address",
+			func(cfg *Config) *string { return &cfg.Server.HTTPAddr }),
+		stringSetting("GRPC_ADDR", "grpc-addr", "gRPC listen address",
+			func(cfg *Config) *string { return &cfg.Server.GRPCAddr }),
+		stringSetting("METRICS_ADDR", "metrics-addr", "metrics and health listen address",
+			func(cfg *Config) *string { return &cfg.Server.MetricsAddr }),
+		boolSetting("PROFILING", "profiling", "serve /debug/pprof/ on the metrics address",
+			func(cfg *Config) *bool { return &cfg.Server.Profiling }),
+		durationSetting("SHUTDOWN_TIMEOUT", "shutdown-timeout", "graceful shutdown timeout",
+			func(cfg *Config) *time.Duration { return &cfg.Server.ShutdownTimeout }),
+		durationSetting("IDEMPOTENCY_TTL", "idempotency-ttl", "how long idempotent responses are replayed",
+			func(cfg *Config) *time.Duration { return &cfg.Server.IdempotencyTTL }),
+		durationSetting("SESSION_ABSOLUTE_LIFETIME", "session-absolute-lifetime", "maximum session lifetime",
+			func(cfg *Config) *time.Duration { return &cfg.Sessions.AbsoluteLifetime }),
+		durationSetting("SESSION_IDLE_TIMEOUT", "session-idle-timeout", "session lifetime without activity",
+			func(cfg *Config) *time.Duration { return &cfg.Sessions.IdleTimeout }),
+		durationSetting("SESSION_RENEWAL_THRESHOLD", "session-renewal-threshold",
+			"remaining lifetime that renews a session",
+			func(cfg *Config) *time.Duration { return &cfg.Sessions.RenewalThreshold }),
+		boolSetting("SESSION_LOGIN_RISK", "session-login-risk", "flag logins from new countries and devices",
+			func(cfg *Config) *bool { return &cfg.Sessions.LoginRisk }),
+		stringSetting("GEOIP_DATABASE", "geoip-database", "MaxMind City database locating logins",
+			func(cfg *Config) *string { return &cfg.Sessions.GeoIPDatabase }),
+		{env: "EVENTS_BACKEND", flag: "events-backend", usage: "event backend: memory, nats or kafka",
+			set: func(cfg *Config, value string) error { cfg.Events.Backend = EventBackend(value); return nil }},
+		stringSetting("EVENTS_URL", "events-url", "NATS server URL",
+			func(cfg *Config) *string { return &cfg.Events.URL }),
+		{env: "EVENTS_BROKERS", flag: "events-brokers", usage: "comma-separated Kafka brokers",
+			set: func(cfg *Config, value string) error { cfg.Events.Brokers = splitList(value); return nil }},
+		stringSetting("EVENTS_TOPIC", "events-topic", "subject or topic of the events",
+			func(cfg *Config) *string { return &cfg.Events.Topic }),
+		intSetting("EVENTS_BUFFER_CAPACITY", "events-buffer-capacity",
+			"events buffered for asynchronous publishing; 0 publishes synchronously",
+			func(cfg *Config) *int { return &cfg.Events.Buffer.Capacity }),
+		intSetting("EVENTS_BUFFER_BATCH_SIZE", "events-buffer-batch-size", "events published per batch",
+			func(cfg *Config) *int { return &cfg.Events.Buffer.BatchSize }),
+		durationSetting("EVENTS_BUFFER_FLUSH_INTERVAL", "events-buffer-flush-interval",
+			"how often buffered events are published",
+			func(cfg *Config) *time.Duration { return &cfg.Events.Buffer.FlushInterval }),
+		{env: "EVENTS_BUFFER_OVERFLOW", flag: "events-buffer-overflow", usage: "full event buffer: block, drop or reject",
+			set: func(cfg *Config, value string) error { cfg.Events.Buffer.Overflow = EventOverflow(value); return nil }},
+		intSetting("JOB_WORKERS", "job-workers", "concurrent background jobs; 0 disables them",
+			func(cfg *Config) *int { return &cfg.Jobs.Workers }),
+		durationSetting("JOB_DONE_RETENTION", "job-done-retention", "how long finished jobs are kept",
+			func(cfg *Config) *time.Duration { return &cfg.Jobs.DoneRetention }),
+		{env: "REDACTION_MODE", flag: "redaction-mode", usage: "personal data in events and logs: none, mask or hash",
+			set: func(cfg *Config, value string) error { cfg.Redaction.Mode = events.RedactionMode(value); return nil }},
+		stringSetting("REDACTION_HASH_KEY", "redaction-hash-key", "secret key of the redaction hashes",
+			func(cfg *Config) *string { return &cfg.Redaction.HashKey }),
+		stringSetting("RATE_LIMIT_REDIS_ADDR", "rate-limit-redis-addr", "Redis address sharing the rate limits",
+			func(cfg *Config) *string { return &cfg.RateLimit.RedisAddr }),
+		intSetting("RATE_LIMIT_REQUESTS_BURST", "rate-limit-requests-burst",
+			"requests per client IP allowed at once; 0 disables the limit",
+			func(cfg *Config) *int { return &cfg.RateLimit.Requests.Burst }),
+		durationSetting("RATE_LIMIT_REQUESTS_EVERY", "rate-limit-requests-every", "refill interval of one request",
+			func(cfg *Config) *time.Duration { return &cfg.RateLimit.Requests.Every }),
+		intSetting("RATE_LIMIT_LOGIN_IP_BURST", "rate-limit-login-ip-burst",
+			"login attempts per client IP allowed at once; 0 disables the limit",
+			func(cfg *Config) *int { return &cfg.RateLimit.LoginPerIP.Burst }),
+		durationSetting("RATE_LIMIT_LOGIN_IP_EVERY", "rate-limit-login-ip-every",
+			"refill interval of one login attempt per client IP",
+			func(cfg *Config) *time.Duration { return &cfg.RateLimit.LoginPerIP.Every }),
+		intSetting("RATE_LIMIT_LOGIN_ACCOUNT_BURST", "rate-limit-login-account-burst",
+			"login attempts per account allowed at once; 0 disables the limit",
+			func(cfg *Config) *int { return &cfg.RateLimit.LoginPerAccount.Burst }),
+		durationSetting("RATE_LIMIT_LOGIN_ACCOUNT_EVERY", "rate-limit-login-account-every",
+			"refill interval of one login attempt per account",
+			func(cfg *Config) *time.Duration { return &cfg.RateLimit.LoginPerAccount.Every }),
+		{env: "EMAIL_BACKEND", flag: "email-backend", usage: "email backend: none, smtp or ses",
+			set: func(cfg *Config, value string) error { cfg.Email.Backend = EmailBackend(value); return nil }},
+		stringSetting("EMAIL_FROM", "email-from", "sender of the emails",
+			func(cfg *Config) *string { return &cfg.Email.From }),
+		stringSetting("EMAIL_VERIFICATION_URL", "email-verification-url", "page that verifies email addresses",
+			func(cfg *Config) *string { return &cfg.Email.VerificationURL }),
+		stringSetting("EMAIL_PASSWORD_RESET_URL", "email-password-reset-url", "page that resets passwords",
+			func(cfg *Config) *string { return &cfg.Email.PasswordResetURL }),
+		stringSetting("EMAIL_CHANGE_URL", "email-change-url", "page that confirms new email addresses",
+			func(cfg *Config) *string { return &cfg.Email.EmailChangeURL }),
+		stringSetting("EMAIL_IDENTITY_REVERT_URL", "email-identity-revert-url",
+			"page that reverts email address and username changes",
+			func(cfg *Config) *string { return &cfg.Email.IdentityRevertURL }),
+		stringSetting("EMAIL_SMTP_ADDR", "email-smtp-addr", "SMTP server address",
+			func(cfg *Config) *string { return &cfg.Email.SMTPAddr }),
+		stringSetting("EMAIL_SMTP_USERNAME", "email-smtp-username", "SMTP username",
+			func(cfg *Config) *string { return &cfg.Email.SMTPUsername }),
+		stringSetting("EMAIL_SMTP_PASSWORD", "email-smtp-password", "SMTP password",
+			func(cfg *Config) *string { return &cfg.Email.SMTPPassword }),
+		stringSetting("EMAIL_SES_REGION", "email-ses-region", "AWS region of Amazon SES",
+			func(cfg *Config) *string { return &cfg.Email.SESRegion }),
+		boolSetting("NOTIFICATIONS_ENABLED", "notifications", "notify users about account events",
+			func(cfg *Config) *bool { return &cfg.Notifications.Enabled }),
+		stringSetting("NOTIFICATIONS_WEBHOOK_SECRET", "notifications-webhook-secret", "secret signing webhook bodies",
+			func(cfg *Config) *string { return &cfg.Notifications.WebhookSecret }),
+		boolSetting("ACTIVITY_ENABLED", "activity", "record the activity feeds of users",
+			func(cfg *Config) *bool { return &cfg.Activity.Enabled }),
+		durationSetting("ACTIVITY_RETENTION", "activity-retention", "how long activity feed entries are kept",
+			func(cfg *Config) *time.Duration { return &cfg.Activity.Retention }),
+		boolSetting("READ_MODEL_ENABLED", "read-model", "project users into the read model listings are served from",
+			func(cfg *Config) *bool { return &cfg.ReadModel.Enabled }),
+		boolSetting("MAINTENANCE_READ_ONLY", "read-only", "reject changes to users",
+			func(cfg *Config) *bool { return &cfg.Maintenance.ReadOnly }),
+		boolSetting("MAINTENANCE_REGISTRATION_DISABLED", "registration-disabled", "reject creating users",
+			func(cfg *Config) *bool { return &cfg.Maintenance.RegistrationDisabled }),
+		boolSetting("MAINTENANCE_LOGIN_DISABLED", "login-disabled", "reject signing in",
+			func(cfg *Config) *bool { return &cfg.Maintenance.LoginDisabled }),
+		durationSetting("MAINTENANCE_FLAG_REFRESH", "maintenance-flag-refresh",
+			"how often the maintenance feature flags are reloaded",
+			func(cfg *Config) *time.Duration { return &cfg.Maintenance.FlagRefresh }),
+		intSetting("PASSWORD_MIN_LENGTH", "password-min-length", "minimum length of new passwords",
+			func(cfg *Config) *int { return &cfg.Passwords.MinLength }),
+		intSetting("PASSWORD_MAX_LENGTH", "password-max-length", "maximum length of new passwords",
+			func(cfg *Config) *int { return &cfg.Passwords.MaxLength }),
+		intSetting("PASSWORD_MIN_CHARACTER_CLASSES", "password-min-character-classes",
+			"how many of lowercase, uppercase, digits and symbols new passwords mix",
+			func(cfg *Config) *int { return &cfg.Passwords.MinCharacterClasses }),
+		intSetting("PASSWORD_MIN_STRENGTH", "password-min-strength",
+			"lowest strength score of new passwords, from 0 to 4",
+			func(cfg *Config) *int { return &cfg.Passwords.MinStrength }),
+		boolSetting("PASSWORD_BREACH_CHECK", "password-breach-check", "reject passwords of known data breaches",
+			func(cfg *Config) *bool { return &cfg.Passwords.BreachCheck }),
+		stringSetting("PASSWORD_BREACH_API_URL", "password-breach-api-url",
+			"k-anonymity range API of breached passwords",
+			func(cfg *Config) *string { return &cfg.Passwords.BreachAPIURL }),
+		durationSetting("PASSWORD_BREACH_TIMEOUT", "password-breach-timeout", "timeout of a breach check",
+			func(cfg *Config) *time.Duration { return &cfg.Passwords.BreachTimeout }),
+		boolSetting("PASSWORD_BREACH_FAIL_OPEN", "password-breach-fail-open",
+			"accept passwords whose breach check failed",
+			func(cfg *Config) *bool { return &cfg.Passwords.BreachFailOpen }),
+		boolSetting("EMAIL_ADDRESS_CHECK_MX", "email-address-check-mx",
+			"reject email addresses of domains without a mail exchanger",
+			func(cfg *Config) *bool { return &cfg.EmailAddresses.CheckMX }),
+		durationSetting("EMAIL_ADDRESS_MX_TIMEOUT", "email-address-mx-timeout", "timeout of an MX lookup",
+			func(cfg *Config) *time.Duration { return &cfg.EmailAddresses.MXTimeout }),
+		boolSetting("EMAIL_ADDRESS_MX_FAIL_OPEN", "email-address-mx-fail-open",
+			"accept email addresses whose MX lookup failed",
+			func(cfg *Config) *bool { return &cfg.EmailAddresses.MXFailOpen }),
+		stringSetting("EMAIL_ADDRESS_DISPOSABLE_DOMAINS_FILE", "email-address-disposable-domains-file",
+			"file of disposable email domains to reject, one per line",
+			func(cfg *Config) *string { return &cfg.EmailAddresses.DisposableDomainsFile }),
+		stringSetting("USERNAME_RESERVED_FILE", "username-reserved-file",
+			"file of reserved usernames and patterns, one per line",
+			func(cfg *Config) *string { return &cfg.Usernames.ReservedFile }),
+		stringSetting("USERNAME_PROFANITY_FILE", "username-profanity-file",
+			"file of offensive usernames and patterns, one per line",
+			func(cfg *Config) *string { return &cfg.Usernames.ProfanityFile }),
+		durationSetting("ACCOUNT_DELETION_GRACE_PERIOD", "account-deletion-grace-period",
+			"how long a requested account deletion can be canceled",
+			func(cfg *Config) *time.Duration { return &cfg.AccountDeletion.GracePeriod }),
+		boolSetting("CHANGEFEED_ENABLED", "changefeed",
+			"publish the changes of users and sessions from logical decoding",
+			func(cfg *Config) *bool { return &cfg.Changefeed.Enabled }),
+		stringSetting("CHANGEFEED_SLOT", "changefeed-slot", "logical replication slot of the changefeed",
+			func(cfg *Config) *string { return &cfg.Changefeed.Slot }),
+		durationSetting("CHANGEFEED_POLL_INTERVAL", "changefeed-poll-interval",
+			"how often the changefeed reads its replication slot",
+			func(cfg *Config) *time.Duration { return &cfg.Changefeed.PollInterval }),
+		intSetting("CHANGEFEED_BATCH_SIZE", "changefeed-batch-size", "changes read from the replication slot at once",
+			func(cfg *Config) *int { return &cfg.Changefeed.BatchSize }),
+		{env: "STORAGE_BACKEND", flag: "storage-backend", usage: "file storage backend: none, local, s3 or gcs",
+			set: func(cfg *Config, value string) error { cfg.Storage.Backend = StorageBackend(value); return nil }},
+		stringSetting("STORAGE_BASE_URL", "storage-base-url", "URL clients download stored files from",
+			func(cfg *Config) *string { return &cfg.Storage.BaseURL }),
+		stringSetting("STORAGE_DIR", "storage-dir", "directory of the local storage backend",
+			func(cfg *Config) *string { return &cfg.Storage.Dir }),
+		stringSetting("STORAGE_BUCKET", "storage-bucket", "bucket of the s3 and gcs storage backends",
+			func(cfg *Config) *string { return &cfg.Storage.Bucket }),
+		stringSetting("STORAGE_REGION", "storage-region", "AWS region of the s3 storage backend",
+			func(cfg *Config) *string { return &cfg.Storage.Region }),
+		stringSetting("STORAGE_ENDPOINT", "storage-endpoint", "API endpoint of the s3 and gcs storage backends",
+			func(cfg *Config) *string { return &cfg.Storage.Endpoint }),
+	}
+}
+
+// Load builds the configuration from the defaults, the YAML file, the
+// environment read through getenv and the command-line args, in increasing
+// precedence, and validates it. The -h flag returns flag.ErrHelp.
+func Load(args []string, getenv func(string) string) (Config, error) {
+	flags := flag.NewFlagSet("config", flag.ContinueOnError)
+	path := flags.String("config", getenv(FileEnv), "YAML configuration file (env "+FileEnv+")")
+
+	var fromFlags []func(cfg *Config) error
+
+	for _, s := range settings() {
+		// Flags are applied after the file and the environment, but their
+		// values are checked while parsing.
+		apply := func(value string) error {
+			err := s.set(&Config{}, value)
+			if err != nil {
+				return err
+			}
+
+			fromFlags = append(fromFlags, func(cfg *Config) error { return s.set(cfg, value) })
+
+			return nil
+		}
+
+		usage := s.usage + " (env " + s.env + ")"
+		if s.boolean {
+			flags.BoolFunc(s.flag, usage, apply)
+		} else {
+			flags.Func(s.flag, usage, apply)
+		}
+	}
+
+	err := flags.Parse(args)
+	if err != nil {
+		return Config{}, fmt.Errorf("parse flags: %w", err)
+	}
+
+	if flags.NArg() > 0 {
+		return Config{}, fmt.Errorf("unexpected arguments %q: %w", flags.Args(), ErrInvalidConfig)
+	}
+
+	cfg := Default()
+
+	if *path != "" {
+		err = cfg.loadFile(*path)
+		if err != nil {
+			return Config{}, err
+		}
+	}
+
+	for _, s := range settings() {
+		value := getenv(s.env)
+		if value == "" {
+			continue
+		}
+
+		err = s.set(&cfg, value)
+		if err != nil {
+			return Config{}, fmt.Errorf("env %s: %w", s.env, err)
+		}
+	}
+
+	for _, set := range fromFlags {
+		_ = set(&cfg)
+	}
+
+	err = cfg.Validate()
+	if err != nil {
+		return Config{}, err
+	}
+
+	return cfg, nil
+}
+
+// loadFile overlays the YAML file at path. Unknown keys are rejected so
+// that typos do not go unnoticed.
+func (c *Config) loadFile(path string) error {
+	file, err := os.Open(path) //nolint:gosec // The path is chosen by the operator.
+	if err != nil {
+		return fmt.Errorf("open config file: %w", err)
+	}
+
+	defer func() { _ = file.Close() }()
+
+	decoder := yaml.NewDecoder(file)
+	decoder.KnownFields(true)
+
+	err = decoder.Decode(c)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("decode config file path=%v: %w", path, err)
+	}
+
+	return nil
+}
+
+func stringSetting(env, name, usage string, field func(cfg *Config) *string) setting {
+	return setting{env: env, flag: name, usage: usage, set: func(cfg *Config, value string) error {
+		*field(cfg) = value
+
+		return nil
+	}}
+}
+
+func intSetting(env, name, usage string, field func(cfg *Config) *int) setting {
+	return setting{env: env, flag: name, usage: usage, set: func(cfg *Config, value string) error {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("parse integer %q: %w", value, err)
+		}
+
+		*field(cfg) = n
+
+		return nil
+	}}
+}
+
+func boolSetting(env, name, usage string, field func(cfg *Config) *bool) setting {
+	return setting{env: env, flag: name, usage: usage, boolean: true, set: func(cfg *Config, value string) error {
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("parse boolean %q: %w", value, err)
+		}
+
+		*field(cfg) = b
+
+		return nil
+	}}
+}
+
+func durationSetting(env, name, usage string, field func(cfg *Config) *time.Duration) setting {
+	return setting{env: env, flag: name, usage: usage, set: func(cfg *Config, value string) error {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("parse duration %q: %w", value, err)
+		}
+
+		*field(cfg) = d
+
+		return nil
+	}}
+}
+
+// splitList splits a comma-separated list, dropping empty items.
+func splitList(value string) []string {
+	var items []string
+
+	for item := range strings.SplitSeq(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
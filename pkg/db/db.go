@@ -0,0 +1,351 @@
+// Package db opens pooled database connections from configuration, checks
+// their health periodically and reports pool usage to metrics.
+//
+// PostgreSQL connections use pgxpool. MySQL, SQLite and libSQL use
+// database/sql; the caller imports the driver, github.com/go-sql-driver/mysql,
+// modernc.org/sqlite or github.com/tursodatabase/go-libsql.
+package db
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Driver names a supported database engine.
+type Driver string
+
+// Supported drivers.
+const (
+	DriverPostgres Driver = "postgres"
+	DriverMySQL    Driver = "mysql"
+	DriverSQLite   Driver = "sqlite"
+	DriverLibSQL   Driver = "libsql"
+)
+
+// Pool defaults applied to zero Config fields.
+const (
+	DefaultMaxOpenConns        = 25
+	DefaultMaxIdleConns        = 5
+	DefaultConnMaxLifetime     = 30 * time.Minute
+	DefaultConnMaxIdleTime     = 5 * time.Minute
+	DefaultHealthCheckInterval = 15 * time.Second
+	DefaultHealthCheckTimeout  = 2 * time.Second
+)
+
+var (
+	// ErrUnsupportedDriver is returned for a driver this package cannot open.
+	ErrUnsupportedDriver = errors.New("unsupported database driver")
+
+	// ErrMissingDSN is returned when no data source name is configured.
+	ErrMissingDSN = errors.New("missing data source name")
+)
+
+// Config describes a connection pool. Zero durations and sizes use the defaults.
+type Config struct {
+	Driver Driver
+	DSN    string
+
+	MaxOpenConns int
+	// MaxIdleConns caps idle connections for database/sql; pgxpool keeps this
+	// many idle connections open instead.
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+
+	HealthCheckInterval time.Duration
+	HealthCheckTimeout  time.Duration
+}
+
+// withDefaults fills zero fields with the package defaults.
+func (c Config) withDefaults() Config {
+	c.MaxOpenConns = orDefault(c.MaxOpenConns, DefaultMaxOpenConns)
+	c.MaxIdleConns = min(orDefault(c.MaxIdleConns, DefaultMaxIdleConns), c.MaxOpenConns)
+	c.ConnMaxLifetime = orDefault(c.ConnMaxLifetime, DefaultConnMaxLifetime)
+	c.ConnMaxIdleTime = orDefault(c.ConnMaxIdleTime, DefaultConnMaxIdleTime)
+	c.HealthCheckInterval = orDefault(c.HealthCheckInterval, DefaultHealthCheckInterval)
+	c.HealthCheckTimeout = orDefault(c.HealthCheckTimeout, DefaultHealthCheckTimeout)
+
+	return c
+}
+
+// orDefault returns fallback for non-positive values.
+func orDefault[T int | time.Duration](value, fallback T) T {
+	if value <= 0 {
+		return fallback
+	}
+
+	return value
+}
+
+// ConnectionGauge receives the number of connections in use.
+// *monitoring.Metrics satisfies it.
+type ConnectionGauge interface {
+	SetActiveConnections(count int64)
+}
+
+// Stats is a snapshot of pool usage.
+type Stats struct {
+	Open  int64
+	InUse int64
+	Idle  int64
+	Max   int64
+}
+
+// Option configures a Pool.
+type Option func(*Pool)
+
+// WithMetrics reports the connections in use to gauge on every health check.
+func WithMetrics(gauge ConnectionGauge) Option {
+	return func(p *Pool) {
+		p.gauge = gauge
+	}
+}
+
+// WithConnector opens a database/sql pool through connector instead of the
+// DSN, for drivers configured beyond a DSN, such as a libSQL embedded replica.
+// The DSN may then be empty. Closing the pool closes connector if it
+// implements io.Closer.
+func WithConnector(connector driver.Connector) Option {
+	return func(p *Pool) {
+		p.connector = connector
+	}
+}
+
+// WithHealthListener calls listener whenever the pool turns healthy (nil) or
+// unhealthy (the ping error).
+func WithHealthListener(listener func(err error)) Option {
+	return func(p *Pool) {
+		p.onHealth = listener
+	}
+}
+
+// Pool is an open connection pool with a background health check.
+type Pool struct {
+	cfg Config
+
+	sqlDB   *sql.DB
+	pgxPool *pgxpool.Pool
+
+	connector driver.Connector
+	gauge     ConnectionGauge
+	onHealth  func(err error)
+
+	healthy   atomic.Bool
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// Open creates the pool, verifies it with a ping and starts the health check.
+func Open(ctx context.Context, cfg Config, opts ...Option) (*Pool, error) {
+	pool := &Pool{
+		cfg:  cfg.withDefaults(),
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(pool)
+	}
+
+	if cfg.DSN == "" && pool.connector == nil {
+		return nil, fmt.Errorf("driver=%v: %w", cfg.Driver, ErrMissingDSN)
+	}
+
+	err := pool.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = pool.Ping(ctx)
+	if err != nil {
+		_ = pool.closeConnections()
+
+		return nil, fmt.Errorf("driver=%v ping: %w", cfg.Driver, err)
+	}
+
+	pool.healthy.Store(true)
+	pool.reportStats()
+
+	go pool.monitor()
+
+	return pool, nil
+}
+
+// connect creates the driver pool without connecting yet.
+func (p *Pool) connect(ctx context.Context) error {
+	switch p.cfg.Driver {
+	case DriverPostgres:
+		poolConfig, err := pgxpool.ParseConfig(p.cfg.DSN)
+		if err != nil {
+			return fmt.Errorf("driver=%v parse dsn: %w", p.cfg.Driver, err)
+		}
+
+		poolConfig.MaxConns = int32(p.cfg.MaxOpenConns)
+		poolConfig.MinIdleConns = int32(p.cfg.MaxIdleConns)
+		poolConfig.MaxConnLifetime = p.cfg.ConnMaxLifetime
+		poolConfig.MaxConnIdleTime = p.cfg.ConnMaxIdleTime
+		poolConfig.HealthCheckPeriod = p.cfg.HealthCheckInterval
+
+		p.pgxPool, err = pgxpool.NewWithConfig(ctx, poolConfig)
+		if err != nil {
+			return fmt.Errorf("driver=%v: %w", p.cfg.Driver, err)
+		}
+	case DriverMySQL, DriverSQLite, DriverLibSQL:
+		sqlDB, err := p.openSQL()
+		if err != nil {
+			return fmt.Errorf("driver=%v: %w", p.cfg.Driver, err)
+		}
+
+		sqlDB.SetMaxOpenConns(p.cfg.MaxOpenConns)
+		sqlDB.SetMaxIdleConns(p.cfg.MaxIdleConns)
+		sqlDB.SetConnMaxLifetime(p.cfg.ConnMaxLifetime)
+		sqlDB.SetConnMaxIdleTime(p.cfg.ConnMaxIdleTime)
+
+		p.sqlDB = sqlDB
+	default:
+		return fmt.Errorf("driver=%v: %w", p.cfg.Driver, ErrUnsupportedDriver)
+	}
+
+	return nil
+}
+
+// openSQL opens the database/sql pool through the connector, if any.
+func (p *Pool) openSQL() (*sql.DB, error) {
+	if p.connector != nil {
+		return sql.OpenDB(p.connector), nil
+	}
+
+	return sql.Open(string(p.cfg.Driver), p.cfg.DSN)
+}
+
+// SQL returns the database/sql handle for MySQL, SQLite and libSQL, or nil for PostgreSQL.
+func (p *Pool) SQL() *sql.DB { return p.sqlDB }
+
+// PGX returns the pgx pool for PostgreSQL, or nil for other drivers.
+func (p *Pool) PGX() *pgxpool.Pool { return p.pgxPool }
+
+// Driver returns the engine of the pool.
+func (p *Pool) Driver() Driver { return p.cfg.Driver }
+
+// Healthy reports whether the last health check succeeded.
+func (p *Pool) Healthy() bool { return p.healthy.Load() }
+
+// Ping checks that a connection can be used.
+func (p *Pool) Ping(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, p.cfg.HealthCheckTimeout)
+	defer cancel()
+
+	if p.pgxPool != nil {
+		return p.pgxPool.Ping(ctx)
+	}
+
+	return p.sqlDB.PingContext(ctx)
+}
+
+// Stats returns the current pool usage.
+func (p *Pool) Stats() Stats {
+	if p.pgxPool != nil {
+		stat := p.pgxPool.Stat()
+
+		return Stats{
+			Open:  int64(stat.TotalConns()),
+			InUse: int64(stat.AcquiredConns()),
+			Idle:  int64(stat.IdleConns()),
+			Max:   int64(stat.MaxConns()),
+		}
+	}
+
+	stat := p.sqlDB.Stats()
+
+	return Stats{
+		Open:  int64(stat.OpenConnections),
+		InUse: int64(stat.InUse),
+		Idle:  int64(stat.Idle),
+		Max:   int64(stat.MaxOpenConnections),
+	}
+}
+
+// monitor pings the database and reports stats until Close.
+func (p *Pool) monitor() {
+	defer close(p.done)
+
+	ticker := time.NewTicker(p.cfg.HealthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+			p.check()
+		}
+	}
+}
+
+// check runs one health check and notifies the listener on state changes.
+func (p *Pool) check() {
+	err := p.Ping(context.Background())
+	p.reportStats()
+
+	healthy := err == nil
+	if p.healthy.Swap(healthy) == healthy {
+		return
+	}
+
+	if err != nil {
+		slog.Warn("database unhealthy", "driver", p.cfg.Driver, "error", err)
+	} else {
+		slog.Info("database healthy again", "driver", p.cfg.Driver)
+	}
+
+	if p.onHealth != nil {
+		p.onHealth(err)
+	}
+}
+
+// reportStats feeds the connections in use to the gauge.
+func (p *Pool) reportStats() {
+	if p.gauge != nil {
+		p.gauge.SetActiveConnections(p.Stats().InUse)
+	}
+}
+
+// Close stops the health check and closes all connections.
+func (p *Pool) Close() error {
+	var err error
+
+	p.closeOnce.Do(func() {
+		close(p.stop)
+		<-p.done
+
+		err = p.closeConnections()
+	})
+
+	return err
+}
+
+// closeConnections closes the driver pool.
+func (p *Pool) closeConnections() error {
+	if p.pgxPool != nil {
+		p.pgxPool.Close()
+
+		return nil
+	}
+
+	err := p.sqlDB.Close()
+	if err != nil {
+		return fmt.Errorf("driver=%v close: %w", p.cfg.Driver, err)
+	}
+
+	return nil
+}
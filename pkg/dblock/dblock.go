@@ -0,0 +1,94 @@
+// Package dblock provides named locks held in the database, so that several
+// instances of the application sharing a database can keep a task, such as
+// applying migrations or a periodic cleanup, to one instance at a time:
+//
+//	locker, err := dblock.New(pool)
+//	if err != nil {
+//		return err
+//	}
+//
+//	return dblock.Do(ctx, locker, "migrations", applyMigrations)
+//
+// PostgreSQL uses session-level advisory locks and MySQL named locks, both
+// held on a dedicated connection until Unlock. SQLite and libSQL have
+// neither, so their locks are rows of a lock table with a lease that the
+// holder renews.
+package dblock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/LarsArtmann/template-sqlc/pkg/db"
+)
+
+var (
+	// ErrLocked is returned by TryLock for a lock held elsewhere.
+	ErrLocked = errors.New("lock held by another holder")
+
+	// ErrLockNotHeld is returned by Unlock for a lock that was released or lost.
+	ErrLockNotHeld = errors.New("lock not held")
+
+	// ErrInvalidName is returned for an empty lock name.
+	ErrInvalidName = errors.New("lock name must not be empty")
+)
+
+// Locker acquires named locks. A lock excludes every other holder of the
+// same name in the database, in this process or another one.
+type Locker interface {
+	// Lock waits until the lock named name is acquired or ctx is done.
+	Lock(ctx context.Context, name string) (Lock, error)
+	// TryLock acquires the lock named name, or returns ErrLocked at once if
+	// it is held.
+	TryLock(ctx context.Context, name string) (Lock, error)
+}
+
+// Lock is an acquired lock.
+type Lock interface {
+	// Unlock releases the lock. It returns ErrLockNotHeld if the lock was
+	// lost in the meantime, for example because its connection broke.
+	Unlock(ctx context.Context) error
+}
+
+// New returns the Locker of the engine of pool.
+func New(pool *db.Pool) (Locker, error) {
+	switch pool.Driver() {
+	case db.DriverPostgres:
+		return NewPostgres(pool.PGX()), nil
+	case db.DriverMySQL:
+		return NewMySQL(pool.SQL()), nil
+	case db.DriverSQLite, db.DriverLibSQL:
+		return NewTable(pool.SQL()), nil
+	}
+
+	return nil, fmt.Errorf("driver=%v: %w", pool.Driver(), db.ErrUnsupportedDriver)
+}
+
+// Do runs fn while holding the lock named name, waiting for the lock first.
+func Do(ctx context.Context, locker Locker, name string, fn func(ctx context.Context) error) error {
+	lock, err := locker.Lock(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	err = fn(ctx)
+
+	// Release the lock even if ctx is done, so that it is not held until
+	// its connection or lease expires.
+	unlockErr := lock.Unlock(context.WithoutCancel(ctx))
+	if unlockErr != nil {
+		unlockErr = fmt.Errorf("unlock name=%v: %w", name, unlockErr)
+	}
+
+	return errors.Join(err, unlockErr)
+}
+
+// validateName rejects names no engine can lock.
+func validateName(name string) error {
+	if name == "" {
+		return ErrInvalidName
+	}
+
+	return nil
+}
@@ -0,0 +1,112 @@
+package dblock
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+)
+
+// maxMySQLLockName is the longest name GET_LOCK accepts.
+const maxMySQLLockName = 64
+
+// MySQL is a Locker using MySQL named locks. Each lock holds a connection
+// of the pool until Unlock.
+type MySQL struct {
+	db *sql.DB
+}
+
+var _ Locker = (*MySQL)(nil)
+
+// NewMySQL creates a Locker holding named locks on connections of db.
+func NewMySQL(db *sql.DB) *MySQL {
+	return &MySQL{db: db}
+}
+
+// Lock waits for the named lock of name.
+func (m *MySQL) Lock(ctx context.Context, name string) (Lock, error) {
+	// A negative timeout waits until ctx is done.
+	return m.lock(ctx, name, -1)
+}
+
+// TryLock acquires the named lock of name if it is free.
+func (m *MySQL) TryLock(ctx context.Context, name string) (Lock, error) {
+	return m.lock(ctx, name, 0)
+}
+
+// lock calls GET_LOCK with timeout seconds on a dedicated connection.
+func (m *MySQL) lock(ctx context.Context, name string, timeout int) (Lock, error) {
+	err := validateName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("lock name=%v: %w", name, err)
+	}
+
+	lockName := mysqlLockName(name)
+
+	var acquired sql.NullInt64
+
+	err = conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, ?)", lockName, timeout).Scan(&acquired)
+	if err != nil {
+		_ = conn.Close()
+
+		return nil, fmt.Errorf("lock name=%v: %w", name, err)
+	}
+
+	if acquired.Int64 != 1 {
+		_ = conn.Close()
+
+		return nil, fmt.Errorf("lock name=%v: %w", name, ErrLocked)
+	}
+
+	return &mysqlLock{conn: conn, name: lockName}, nil
+}
+
+// mysqlLockName returns name, or its hash if it is longer than MySQL allows.
+func mysqlLockName(name string) string {
+	if len(name) <= maxMySQLLockName {
+		return name
+	}
+
+	sum := sha256.Sum256([]byte(name))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// mysqlLock is a named lock held on conn.
+type mysqlLock struct {
+	conn *sql.Conn
+	name string
+}
+
+// Unlock releases the named lock and returns its connection to the pool.
+func (l *mysqlLock) Unlock(ctx context.Context) error {
+	if l.conn == nil {
+		return ErrLockNotHeld
+	}
+
+	defer func() {
+		_ = l.conn.Close()
+		l.conn = nil
+	}()
+
+	// RELEASE_LOCK returns 0 for a lock held by another connection and NULL
+	// for a lock nobody holds.
+	var released sql.NullInt64
+
+	err := l.conn.QueryRowContext(ctx, "SELECT RELEASE_LOCK(?)", l.name).Scan(&released)
+	if err != nil {
+		return fmt.Errorf("unlock name=%v: %w", l.name, err)
+	}
+
+	if released.Int64 != 1 {
+		return ErrLockNotHeld
+	}
+
+	return nil
+}
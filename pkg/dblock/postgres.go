@@ -0,0 +1,116 @@
+package dblock
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Postgres is a Locker using PostgreSQL session-level advisory locks. Each
+// lock holds a connection of the pool until Unlock. CockroachDB does not
+// implement advisory locks.
+type Postgres struct {
+	pool *pgxpool.Pool
+}
+
+var _ Locker = (*Postgres)(nil)
+
+// NewPostgres creates a Locker holding advisory locks on connections of pool.
+func NewPostgres(pool *pgxpool.Pool) *Postgres {
+	return &Postgres{pool: pool}
+}
+
+// Lock waits for the advisory lock of name.
+func (p *Postgres) Lock(ctx context.Context, name string) (Lock, error) {
+	return p.lock(ctx, name, func(conn *pgxpool.Conn, key int64) (bool, error) {
+		_, err := conn.Exec(ctx, "SELECT pg_advisory_lock($1)", key)
+
+		return err == nil, err
+	})
+}
+
+// TryLock acquires the advisory lock of name if it is free.
+func (p *Postgres) TryLock(ctx context.Context, name string) (Lock, error) {
+	return p.lock(ctx, name, func(conn *pgxpool.Conn, key int64) (bool, error) {
+		var acquired bool
+
+		err := conn.QueryRow(ctx, "SELECT pg_try_advisory_lock($1)", key).Scan(&acquired)
+
+		return acquired, err
+	})
+}
+
+// lock acquires the advisory lock of name with acquire on a dedicated connection.
+func (p *Postgres) lock(
+	ctx context.Context,
+	name string,
+	acquire func(conn *pgxpool.Conn, key int64) (bool, error),
+) (Lock, error) {
+	err := validateName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := p.pool.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("lock name=%v: %w", name, err)
+	}
+
+	key := advisoryKey(name)
+
+	acquired, err := acquire(conn, key)
+	if err != nil {
+		conn.Release()
+
+		return nil, fmt.Errorf("lock name=%v: %w", name, err)
+	}
+
+	if !acquired {
+		conn.Release()
+
+		return nil, fmt.Errorf("lock name=%v: %w", name, ErrLocked)
+	}
+
+	return &postgresLock{conn: conn, key: key}, nil
+}
+
+// advisoryKey maps name to the 64-bit key of its advisory lock.
+func advisoryKey(name string) int64 {
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(name))
+
+	return int64(hash.Sum64()) //nolint:gosec // The key only needs to be stable.
+}
+
+// postgresLock is an advisory lock held on conn.
+type postgresLock struct {
+	conn *pgxpool.Conn
+	key  int64
+}
+
+// Unlock releases the advisory lock and returns its connection to the pool.
+func (l *postgresLock) Unlock(ctx context.Context) error {
+	if l.conn == nil {
+		return ErrLockNotHeld
+	}
+
+	defer func() {
+		l.conn.Release()
+		l.conn = nil
+	}()
+
+	var released bool
+
+	err := l.conn.QueryRow(ctx, "SELECT pg_advisory_unlock($1)", l.key).Scan(&released)
+	if err != nil {
+		return fmt.Errorf("unlock key=%v: %w", l.key, err)
+	}
+
+	if !released {
+		return ErrLockNotHeld
+	}
+
+	return nil
+}
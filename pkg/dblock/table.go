@@ -0,0 +1,230 @@
+package dblock
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Table defaults applied unless overridden by a TableOption.
+const (
+	DefaultLease         = 30 * time.Second
+	DefaultRetryInterval = 100 * time.Millisecond
+)
+
+// createLockTable creates the lock table on first use, so that locks can
+// guard the migrations themselves.
+const createLockTable = `CREATE TABLE IF NOT EXISTS dblock_locks (
+    name       TEXT PRIMARY KEY,
+    owner      TEXT    NOT NULL,
+    expires_at INTEGER NOT NULL
+)`
+
+// Table is a Locker storing locks as rows of the dblock_locks table, for
+// SQLite and libSQL. A lock is leased and renewed by its holder, so that
+// the lock of a crashed holder is free again after the lease.
+type Table struct {
+	db            *sql.DB
+	lease         time.Duration
+	retryInterval time.Duration
+
+	mu      sync.Mutex
+	created bool
+}
+
+var _ Locker = (*Table)(nil)
+
+// TableOption configures a Table.
+type TableOption func(*Table)
+
+// WithLease sets how long a lock survives its holder. Holders renew the
+// lease every third of it.
+func WithLease(lease time.Duration) TableOption {
+	return func(t *Table) {
+		if lease > 0 {
+			t.lease = lease
+		}
+	}
+}
+
+// WithRetryInterval sets how long Lock waits between attempts.
+func WithRetryInterval(interval time.Duration) TableOption {
+	return func(t *Table) {
+		if interval > 0 {
+			t.retryInterval = interval
+		}
+	}
+}
+
+// NewTable creates a Locker storing locks in db.
+func NewTable(db *sql.DB, opts ...TableOption) *Table {
+	table := &Table{
+		db:            db,
+		lease:         DefaultLease,
+		retryInterval: DefaultRetryInterval,
+	}
+
+	for _, opt := range opts {
+		opt(table)
+	}
+
+	return table
+}
+
+// Lock attempts to acquire the lock of name until it succeeds or ctx is done.
+func (t *Table) Lock(ctx context.Context, name string) (Lock, error) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("lock name=%v: %w", name, ctx.Err())
+		case <-timer.C:
+		}
+
+		lock, err := t.TryLock(ctx, name)
+		if !errors.Is(err, ErrLocked) {
+			return lock, err
+		}
+
+		timer.Reset(t.retryInterval)
+	}
+}
+
+// TryLock acquires the lock of name if it is free or its lease expired.
+func (t *Table) TryLock(ctx context.Context, name string) (Lock, error) {
+	err := validateName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	err = t.createTable(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	owner := rand.Text()
+	now := time.Now()
+
+	result, err := t.db.ExecContext(ctx, `INSERT INTO dblock_locks (name, owner, expires_at) VALUES (?, ?, ?)
+ON CONFLICT (name) DO UPDATE SET owner = excluded.owner, expires_at = excluded.expires_at
+WHERE dblock_locks.expires_at <= ?`,
+		name, owner, now.Add(t.lease).UnixMilli(), now.UnixMilli())
+	if err != nil {
+		return nil, fmt.Errorf("lock name=%v: %w", name, err)
+	}
+
+	acquired, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("lock name=%v: %w", name, err)
+	}
+
+	if acquired == 0 {
+		return nil, fmt.Errorf("lock name=%v: %w", name, ErrLocked)
+	}
+
+	lock := &tableLock{
+		table: t,
+		name:  name,
+		owner: owner,
+		stop:  make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+	go lock.renew()
+
+	return lock, nil
+}
+
+// createTable creates the lock table once per Table.
+func (t *Table) createTable(ctx context.Context) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.created {
+		return nil
+	}
+
+	_, err := t.db.ExecContext(ctx, createLockTable)
+	if err != nil {
+		return fmt.Errorf("create lock table: %w", err)
+	}
+
+	t.created = true
+
+	return nil
+}
+
+// tableLock is a row of the lock table owned by owner.
+type tableLock struct {
+	table *Table
+	name  string
+	owner string
+
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// renew extends the lease every third of it until Unlock or until the
+// lease cannot be extended.
+func (l *tableLock) renew() {
+	defer close(l.done)
+
+	ticker := time.NewTicker(l.table.lease / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+		}
+
+		result, err := l.table.db.ExecContext(context.Background(),
+			"UPDATE dblock_locks SET expires_at = ? WHERE name = ? AND owner = ?",
+			time.Now().Add(l.table.lease).UnixMilli(), l.name, l.owner)
+		if err == nil {
+			var renewed int64
+
+			renewed, err = result.RowsAffected()
+			if err == nil && renewed == 0 {
+				err = ErrLockNotHeld
+			}
+		}
+
+		if err != nil {
+			slog.Warn("failed to renew lock lease", "name", l.name, "error", err)
+
+			return
+		}
+	}
+}
+
+// Unlock stops renewing the lease and deletes the row of the lock.
+func (l *tableLock) Unlock(ctx context.Context) error {
+	l.stopOnce.Do(func() { close(l.stop) })
+	<-l.done
+
+	result, err := l.table.db.ExecContext(ctx,
+		"DELETE FROM dblock_locks WHERE name = ? AND owner = ?", l.name, l.owner)
+	if err != nil {
+		return fmt.Errorf("unlock name=%v: %w", l.name, err)
+	}
+
+	released, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("unlock name=%v: %w", l.name, err)
+	}
+
+	if released == 0 {
+		return ErrLockNotHeld
+	}
+
+	return nil
+}
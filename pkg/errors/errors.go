@@ -1,10 +1,22 @@
 // Package errors provides standardized application error types with HTTP status codes.
+//
+// Every error is classified by its ErrorCode: AppError carries one, and the
+// domain errors report theirs through the Coder interface, so CodeOf, the
+// Is*Error helpers and HTTPStatus work on both, however deeply wrapped:
+//
+//	switch {
+//	case errors.IsNotFoundError(err):
+//		// 404
+//	case errors.IsValidationError(err):
+//		// 400
+//	}
 package errors
 
 import (
 	"errors"
 	"fmt"
 	"net/http"
+	"reflect"
 	"slices"
 )
 
@@ -22,6 +34,19 @@ const (
 	ErrCodeInvalidFormat ErrorCode = "INVALID_FORMAT"
 	// ErrCodeConstraintFailed indicates a database constraint was violated.
 	ErrCodeConstraintFailed ErrorCode = "CONSTRAINT_FAILED"
+	// ErrCodeTooShort indicates a value is shorter than its minimum length.
+	ErrCodeTooShort ErrorCode = "TOO_SHORT"
+	// ErrCodeTooLong indicates a value is longer than its maximum length.
+	ErrCodeTooLong ErrorCode = "TOO_LONG"
+	// ErrCodeOutOfRange indicates a number is outside its allowed range.
+	ErrCodeOutOfRange ErrorCode = "OUT_OF_RANGE"
+	// ErrCodeNotAllowed indicates a well-formed value that is not accepted,
+	// such as a reserved username or a duplicate.
+	ErrCodeNotAllowed ErrorCode = "NOT_ALLOWED"
+	// ErrCodeTooWeak indicates a password that does not meet the password policy.
+	ErrCodeTooWeak ErrorCode = "TOO_WEAK"
+	// ErrCodeUndeliverable indicates an email address whose domain accepts no mail.
+	ErrCodeUndeliverable ErrorCode = "UNDELIVERABLE"
 
 	// ErrCodeUnauthorized indicates authentication is required.
 	ErrCodeUnauthorized ErrorCode = "UNAUTHORIZED"
@@ -61,6 +86,9 @@ const (
 	// ErrCodeUnavailable indicates the service is unavailable.
 	ErrCodeUnavailable ErrorCode = "UNAVAILABLE"
 
+	// ErrCodeRateLimited indicates too many requests were made.
+	ErrCodeRateLimited ErrorCode = "RATE_LIMITED"
+
 	// ErrCodeBusinessLogic indicates a business logic rule was violated.
 	ErrCodeBusinessLogic ErrorCode = "BUSINESS_LOGIC_ERROR"
 	// ErrCodeInvalidState indicates the resource is in an invalid state for this operation.
@@ -69,6 +97,12 @@ const (
 	ErrCodePermissionDenied ErrorCode = "PERMISSION_DENIED"
 )
 
+// Coder is implemented by errors that carry an ErrorCode without depending on
+// this package, such as the domain errors.
+type Coder interface {
+	ErrorCode() string
+}
+
 // AppError represents a structured application error.
 type AppError struct {
 	Code       ErrorCode      `json:"code"`
@@ -92,6 +126,29 @@ func (e *AppError) Unwrap() error {
 	return e.Cause
 }
 
+// ErrorCode returns the code of the error.
+func (e *AppError) ErrorCode() string {
+	return string(e.Code)
+}
+
+// Is reports whether target is an AppError with the same code whose details
+// are all present in e, so that errors.Is matches the errors built by the
+// constructors, such as NewUserNotFoundError.
+func (e *AppError) Is(target error) bool {
+	t, ok := target.(*AppError)
+	if !ok || t.Code != e.Code {
+		return false
+	}
+
+	for key, value := range t.Details {
+		if !reflect.DeepEqual(e.Details[key], value) {
+			return false
+		}
+	}
+
+	return true
+}
+
 // NewAppError creates a new application error.
 func NewAppError(code ErrorCode, message string, httpStatus int) *AppError {
 	return &AppError{
@@ -328,6 +385,40 @@ func IsAppError(err error) bool {
 	return ok
 }
 
+// CodeOf returns the code of the outermost error in err's tree that has one.
+// An error without a code is internal, and a nil error has the empty code.
+func CodeOf(err error) ErrorCode {
+	if err == nil {
+		return ""
+	}
+
+	var coder Coder
+	if !errors.As(err, &coder) {
+		return ErrCodeInternal
+	}
+
+	return ErrorCode(coder.ErrorCode())
+}
+
+// HTTPStatus returns the HTTP status of err from its code, or the status of
+// the error that carries the code if it has one, like AppError.
+func HTTPStatus(err error) int {
+	var coder Coder
+	if !errors.As(err, &coder) {
+		return http.StatusInternalServerError
+	}
+
+	if statusCoder, ok := coder.(interface{ StatusCode() int }); ok {
+		return statusCoder.StatusCode()
+	}
+
+	if status, ok := errorCodeToHTTPStatus[ErrorCode(coder.ErrorCode())]; ok {
+		return status
+	}
+
+	return http.StatusInternalServerError
+}
+
 // IsValidationError checks if err is a validation error.
 func IsValidationError(err error) bool {
 	return hasErrorCode(
@@ -337,42 +428,49 @@ func IsValidationError(err error) bool {
 		ErrCodeMissingField,
 		ErrCodeInvalidFormat,
 		ErrCodeConstraintFailed,
+		ErrCodeTooShort,
+		ErrCodeTooLong,
+		ErrCodeOutOfRange,
+		ErrCodeNotAllowed,
+		ErrCodeTooWeak,
+		ErrCodeUndeliverable,
 	)
 }
 
 // IsNotFoundError checks if err is a not found error.
 func IsNotFoundError(err error) bool {
-	appErr := &AppError{}
-	if errors.As(err, &appErr) {
-		return appErr.Code == ErrCodeResourceNotFound || appErr.Code == ErrCodeNotFound
-	}
+	return hasErrorCode(err, ErrCodeNotFound, ErrCodeResourceNotFound)
+}
 
-	return false
+// IsConflictError checks if err is a conflict error.
+func IsConflictError(err error) bool {
+	return hasErrorCode(err, ErrCodeAlreadyExists, ErrCodeResourceConflict)
 }
 
 // IsUnauthorizedError checks if err is an unauthorized error.
 func IsUnauthorizedError(err error) bool {
-	appErr := &AppError{}
-	if errors.As(err, &appErr) {
-		return appErr.Code == ErrCodeUnauthorized ||
-			appErr.Code == ErrCodeInvalidCredentials ||
-			appErr.Code == ErrCodeTokenExpired ||
-			appErr.Code == ErrCodeTokenInvalid
-	}
+	return hasErrorCode(
+		err,
+		ErrCodeUnauthorized,
+		ErrCodeInvalidCredentials,
+		ErrCodeTokenExpired,
+		ErrCodeTokenInvalid,
+	)
+}
 
-	return false
+// IsRateLimitError checks if err is a rate limit error.
+func IsRateLimitError(err error) bool {
+	return hasErrorCode(err, ErrCodeRateLimited)
 }
 
-// hasErrorCode checks if err contains any of the given error codes.
-func hasErrorCode(err error, codes ...ErrorCode) bool {
-	appErr := &AppError{}
-	if errors.As(err, &appErr) {
-		if slices.Contains(codes, appErr.Code) {
-			return true
-		}
-	}
+// IsTimeoutError checks if err is a timeout error.
+func IsTimeoutError(err error) bool {
+	return hasErrorCode(err, ErrCodeTimeout)
+}
 
-	return false
+// hasErrorCode checks if the code of err is any of codes.
+func hasErrorCode(err error, codes ...ErrorCode) bool {
+	return err != nil && slices.Contains(codes, CodeOf(err))
 }
 
 // IsForbiddenError checks if err is a forbidden error.
@@ -383,10 +481,12 @@ func IsForbiddenError(err error) bool {
 		ErrCodeInsufficientPrivileges,
 		ErrCodeAccountSuspended,
 		ErrCodeAccountInactive,
+		ErrCodePermissionDenied,
 	)
 }
 
-// IsInternalServerError checks if err is an internal server error.
+// IsInternalServerError checks if err is an internal server error. Errors
+// without a code are internal.
 func IsInternalServerError(err error) bool {
 	return hasErrorCode(
 		err,
@@ -405,6 +505,12 @@ var errorCodeToHTTPStatus = map[ErrorCode]int{
 	ErrCodeMissingField:           http.StatusBadRequest,
 	ErrCodeInvalidFormat:          http.StatusBadRequest,
 	ErrCodeConstraintFailed:       http.StatusBadRequest,
+	ErrCodeTooShort:               http.StatusBadRequest,
+	ErrCodeTooLong:                http.StatusBadRequest,
+	ErrCodeOutOfRange:             http.StatusBadRequest,
+	ErrCodeNotAllowed:             http.StatusBadRequest,
+	ErrCodeTooWeak:                http.StatusBadRequest,
+	ErrCodeUndeliverable:          http.StatusBadRequest,
 	ErrCodeBusinessLogic:          http.StatusBadRequest,
 	ErrCodeInvalidState:           http.StatusBadRequest,
 	ErrCodeUnauthorized:           http.StatusUnauthorized,
@@ -420,6 +526,7 @@ var errorCodeToHTTPStatus = map[ErrorCode]int{
 	ErrCodeResourceNotFound:       http.StatusNotFound,
 	ErrCodeAlreadyExists:          http.StatusConflict,
 	ErrCodeResourceConflict:       http.StatusConflict,
+	ErrCodeRateLimited:            http.StatusTooManyRequests,
 	ErrCodeTimeout:                http.StatusRequestTimeout,
 	ErrCodeUnavailable:            http.StatusServiceUnavailable,
 }
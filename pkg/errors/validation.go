@@ -0,0 +1,197 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// FieldError is one failed requirement of one field of a request. Params
+// carry the values of the requirement, such as {"min": 8} for TOO_SHORT, so
+// that clients can render their own messages.
+type FieldError struct {
+	Field   string         `json:"field"`
+	Code    ErrorCode      `json:"code"`
+	Message string         `json:"message"`
+	Params  map[string]any `json:"params,omitempty"`
+}
+
+// String returns "field: message".
+func (e FieldError) String() string {
+	return e.Field + ": " + e.Message
+}
+
+// AppError returns the error of the field alone, with the field and the
+// params as details, so that errors.Is matches constructors such as
+// NewMissingFieldError.
+func (e FieldError) AppError() *AppError {
+	details := map[string]any{"field": e.Field}
+	maps.Copy(details, e.Params)
+
+	return NewAppErrorWithDetails(e.Code, e.Message, http.StatusBadRequest, details)
+}
+
+// ValidationErrors collects every field error of a request instead of only
+// the first. Its code is VALIDATION_FAILED and its status 422; the adapters
+// map it to an HTTP body with MarshalJSON and to gRPC field violations with
+// FieldErrorsOf.
+//
+//	var errs errors.ValidationErrors
+//	if email == "" {
+//		errs.Add("email", errors.ErrCodeMissingField, "is required")
+//	}
+//	return errs.Err()
+type ValidationErrors []FieldError
+
+// Add appends an error of field. kvPairs are alternating param names and values.
+func (e *ValidationErrors) Add(field string, code ErrorCode, message string, kvPairs ...any) {
+	var params map[string]any
+
+	for i := 0; i+1 < len(kvPairs); i += 2 {
+		if params == nil {
+			params = make(map[string]any)
+		}
+
+		params[fmt.Sprint(kvPairs[i])] = kvPairs[i+1]
+	}
+
+	*e = append(*e, FieldError{Field: field, Code: code, Message: message, Params: params})
+}
+
+// Has reports whether field has an error.
+func (e ValidationErrors) Has(field string) bool {
+	for _, fieldErr := range e {
+		if fieldErr.Field == field {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Err returns e, or nil without errors, so that an empty collection is not
+// returned as a non-nil error.
+func (e ValidationErrors) Err() error {
+	if len(e) == 0 {
+		return nil
+	}
+
+	return e
+}
+
+// Error implements the error interface.
+func (e ValidationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, fieldErr := range e {
+		messages[i] = fieldErr.String()
+	}
+
+	return fmt.Sprintf("%s: %s", ErrCodeValidationFailed, strings.Join(messages, "; "))
+}
+
+// ErrorCode returns ErrCodeValidationFailed.
+func (e ValidationErrors) ErrorCode() string {
+	return string(ErrCodeValidationFailed)
+}
+
+// StatusCode returns http.StatusUnprocessableEntity.
+func (e ValidationErrors) StatusCode() int {
+	return http.StatusUnprocessableEntity
+}
+
+// Unwrap returns the error of each field, see FieldError.AppError.
+func (e ValidationErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, fieldErr := range e {
+		errs[i] = fieldErr.AppError()
+	}
+
+	return errs
+}
+
+// MarshalJSON encodes the body of a 422 response:
+//
+//	{"code": "VALIDATION_FAILED", "message": "Validation failed", "errors": [...]}
+func (e ValidationErrors) MarshalJSON() ([]byte, error) {
+	fields := []FieldError(e)
+	if fields == nil {
+		fields = []FieldError{}
+	}
+
+	//nolint:wrapcheck // Marshaling plain values
+	return json.Marshal(struct {
+		Code    ErrorCode    `json:"code"`
+		Message string       `json:"message"`
+		Errors  []FieldError `json:"errors"`
+	}{ErrCodeValidationFailed, "Validation failed", fields})
+}
+
+// FieldErrorer is implemented by errors that carry the field errors of the
+// errors they wrap in another form, such as translated.
+type FieldErrorer interface {
+	FieldErrors() ValidationErrors
+}
+
+// FieldErrorsOf returns the field errors in err's tree: the entries of every
+// ValidationErrors and every AppError with a field detail, such as those of
+// NewValidationError, unless a FieldErrorer above them replaces them. It
+// returns nil for errors that concern no field.
+func FieldErrorsOf(err error) ValidationErrors {
+	//nolint:errorlint // Walking the tree one level at a time
+	switch typed := err.(type) {
+	case nil:
+		return nil
+	case FieldErrorer:
+		return slices.Clone(typed.FieldErrors())
+	case ValidationErrors:
+		return slices.Clone(typed)
+	case *AppError:
+		if fieldErr, ok := fieldErrorOf(typed); ok {
+			return ValidationErrors{fieldErr}
+		}
+	}
+
+	var fields ValidationErrors
+
+	switch wrapped := err.(type) { //nolint:errorlint // See above
+	case interface{ Unwrap() error }:
+		fields = FieldErrorsOf(wrapped.Unwrap())
+	case interface{ Unwrap() []error }:
+		for _, inner := range wrapped.Unwrap() {
+			fields = append(fields, FieldErrorsOf(inner)...)
+		}
+	}
+
+	return fields
+}
+
+// fieldErrorOf returns the field error of an AppError with a field detail.
+func fieldErrorOf(appErr *AppError) (FieldError, bool) {
+	field, ok := appErr.Details["field"].(string)
+	if !ok {
+		return FieldError{}, false
+	}
+
+	fieldErr := FieldError{Field: field, Code: appErr.Code, Message: appErr.Message}
+
+	for key, value := range appErr.Details {
+		switch key {
+		case "field":
+		case "message":
+			if message, ok := value.(string); ok {
+				fieldErr.Message = message
+			}
+		default:
+			if fieldErr.Params == nil {
+				fieldErr.Params = make(map[string]any)
+			}
+
+			fieldErr.Params[key] = value
+		}
+	}
+
+	return fieldErr, true
+}
@@ -0,0 +1,182 @@
+package fixtures
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+)
+
+// ErrNoSessionRepository is returned when seeding sessions without a session repository.
+var ErrNoSessionRepository = errors.New("seeder has no session repository")
+
+// Seeder inserts fixtures through the repositories.
+type Seeder struct {
+	users     repositories.UserRepository
+	sessions  repositories.SessionRepository
+	batchSize int
+}
+
+// SeederOption configures a Seeder.
+type SeederOption func(*Seeder)
+
+// WithSessions lets the seeder insert sessions.
+func WithSessions(sessions repositories.SessionRepository) SeederOption {
+	return func(s *Seeder) {
+		s.sessions = sessions
+	}
+}
+
+// WithBatchSize inserts users with CreateBatch in chunks of size; the default
+// of 0 inserts them one by one with Create.
+func WithBatchSize(size int) SeederOption {
+	return func(s *Seeder) {
+		s.batchSize = size
+	}
+}
+
+// NewSeeder creates a seeder inserting users into users.
+func NewSeeder(users repositories.UserRepository, opts ...SeederOption) *Seeder {
+	seeder := &Seeder{users: users}
+
+	for _, opt := range opts {
+		opt(seeder)
+	}
+
+	return seeder
+}
+
+// Users builds and inserts the users in order; the returned users carry their IDs.
+func (s *Seeder) Users(ctx context.Context, builders ...*UserBuilder) ([]*entities.User, error) {
+	users := make([]*entities.User, 0, len(builders))
+
+	for _, builder := range builders {
+		user, err := builder.Build()
+		if err != nil {
+			return nil, err
+		}
+
+		users = append(users, user)
+	}
+
+	if s.batchSize <= 0 {
+		for _, user := range users {
+			err := s.users.Create(ctx, user)
+			if err != nil {
+				return nil, fmt.Errorf("seed user email=%v: %w", user.Email(), err)
+			}
+		}
+
+		return users, nil
+	}
+
+	for start := 0; start < len(users); start += s.batchSize {
+		chunk := users[start:min(start+s.batchSize, len(users))]
+
+		err := s.users.CreateBatch(ctx, chunk)
+		if err != nil {
+			return nil, fmt.Errorf("seed users %d-%d: %w", start, start+len(chunk), err)
+		}
+	}
+
+	return users, nil
+}
+
+// GenerateUsers inserts count users named prefix0001, prefix0002 and so on.
+// customize, if not nil, adjusts the builder of the i-th user.
+func (s *Seeder) GenerateUsers(
+	ctx context.Context,
+	prefix string,
+	count int,
+	customize func(i int, builder *UserBuilder),
+) ([]*entities.User, error) {
+	builders := make([]*UserBuilder, count)
+
+	for i := range builders {
+		builders[i] = User().Named(fmt.Sprintf("%s%04d", prefix, i+1))
+		if customize != nil {
+			customize(i, builders[i])
+		}
+	}
+
+	return s.Users(ctx, builders...)
+}
+
+// Sessions builds and inserts the sessions. Each session's user must exist in
+// the user repository, which keeps seeded sessions referentially intact on
+// engines without foreign keys.
+func (s *Seeder) Sessions(ctx context.Context, builders ...*SessionBuilder) ([]*entities.UserSession, error) {
+	if s.sessions == nil {
+		return nil, ErrNoSessionRepository
+	}
+
+	sessions := make([]*entities.UserSession, 0, len(builders))
+	checked := make(map[entities.UserID]bool)
+
+	for _, builder := range builders {
+		session, err := builder.Build()
+		if err != nil {
+			return nil, err
+		}
+
+		if !checked[session.UserID()] {
+			_, err = s.users.GetByID(ctx, session.UserID())
+			if err != nil {
+				return nil, fmt.Errorf("seed session user=%v: %w", session.UserID(), err)
+			}
+
+			checked[session.UserID()] = true
+		}
+
+		err = s.sessions.Create(ctx, session)
+		if err != nil {
+			return nil, fmt.Errorf("seed session user=%v: %w", session.UserID(), err)
+		}
+
+		sessions = append(sessions, session)
+	}
+
+	return sessions, nil
+}
+
+// Dataset is a seeded set of users and their sessions.
+type Dataset struct {
+	Users []*entities.User
+	// Sessions maps user IDs to their sessions.
+	Sessions map[entities.UserID][]*entities.UserSession
+}
+
+// Dataset inserts users generated with GenerateUsers and sessionsPerUser
+// active sessions for each of them; sessions need WithSessions.
+func (s *Seeder) Dataset(ctx context.Context, users, sessionsPerUser int) (*Dataset, error) {
+	seeded, err := s.GenerateUsers(ctx, "user", users, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	dataset := &Dataset{Users: seeded, Sessions: make(map[entities.UserID][]*entities.UserSession)}
+	if sessionsPerUser == 0 {
+		return dataset, nil
+	}
+
+	builders := make([]*SessionBuilder, 0, users*sessionsPerUser)
+
+	for _, user := range seeded {
+		for i := range sessionsPerUser {
+			builders = append(builders, Session(user).Named(fmt.Sprintf("session%d", i+1)))
+		}
+	}
+
+	sessions, err := s.Sessions(ctx, builders...)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, session := range sessions {
+		dataset.Sessions[session.UserID()] = append(dataset.Sessions[session.UserID()], session)
+	}
+
+	return dataset, nil
+}
@@ -0,0 +1,128 @@
+package fixtures
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/google/uuid"
+)
+
+// ErrUnsavedUser is returned when a session is built for a user without an ID.
+var ErrUnsavedUser = errors.New("session fixture needs a stored user")
+
+// SessionBuilder builds a session of a stored user.
+type SessionBuilder struct {
+	user      *entities.User
+	name      string
+	ipAddress net.IP
+	userAgent string
+	createdAt time.Time
+	lifetime  time.Duration
+	expired   bool
+	inactive  bool
+}
+
+// Session starts an active session of user that expires in a day.
+func Session(user *entities.User) *SessionBuilder {
+	return &SessionBuilder{
+		user:      user,
+		name:      "session",
+		ipAddress: net.ParseIP("203.0.113.10"),
+		userAgent: "fixtures",
+		lifetime:  24 * time.Hour,
+	}
+}
+
+// Named sets the name the session token derives from; the sessions of one
+// user need distinct names.
+func (b *SessionBuilder) Named(name string) *SessionBuilder {
+	b.name = name
+
+	return b
+}
+
+// WithIPAddress sets the client address.
+func (b *SessionBuilder) WithIPAddress(ip net.IP) *SessionBuilder {
+	b.ipAddress = ip
+
+	return b
+}
+
+// WithUserAgent sets the client user agent.
+func (b *SessionBuilder) WithUserAgent(userAgent string) *SessionBuilder {
+	b.userAgent = userAgent
+
+	return b
+}
+
+// WithCreatedAt sets the creation time; by default it is the build time.
+func (b *SessionBuilder) WithCreatedAt(createdAt time.Time) *SessionBuilder {
+	b.createdAt = createdAt
+
+	return b
+}
+
+// WithLifetime sets the time from creation to expiry.
+func (b *SessionBuilder) WithLifetime(lifetime time.Duration) *SessionBuilder {
+	b.lifetime = lifetime
+
+	return b
+}
+
+// Expired makes the session expire an hour before the build time.
+func (b *SessionBuilder) Expired() *SessionBuilder {
+	b.expired = true
+
+	return b
+}
+
+// Inactive deactivates the session.
+func (b *SessionBuilder) Inactive() *SessionBuilder {
+	b.inactive = true
+
+	return b
+}
+
+// Build returns the session. The user must have been stored.
+func (b *SessionBuilder) Build() (*entities.UserSession, error) {
+	if b.user == nil || b.user.ID() == 0 {
+		return nil, fmt.Errorf("session name=%v: %w", b.name, ErrUnsavedUser)
+	}
+
+	createdAt := b.createdAt
+
+	switch {
+	case b.expired:
+		createdAt = time.Now().Add(-time.Hour - b.lifetime)
+	case createdAt.IsZero():
+		createdAt = time.Now()
+	}
+
+	seed := fmt.Sprintf("session:%s:%s", b.user.UUID(), b.name)
+
+	return entities.ReconstructSession(entities.SessionRecord{
+		UserID:           b.user.ID(),
+		Token:            entities.SessionToken(uuid.NewSHA1(Namespace, []byte(seed))),
+		DeviceInfo:       entities.NewSessionDeviceInfo(),
+		IPAddress:        b.ipAddress,
+		UserAgent:        b.userAgent,
+		CreatedAt:        createdAt,
+		ExpiresAt:        createdAt.Add(b.lifetime),
+		IsActive:         !b.inactive,
+		RefreshToken:     entities.RefreshToken(uuid.NewSHA1(Namespace, []byte("refresh:"+seed))),
+		RefreshExpiresAt: createdAt.Add(b.lifetime),
+	})
+}
+
+// MustBuild is Build that panics on invalid fixtures.
+func (b *SessionBuilder) MustBuild() *entities.UserSession {
+	session, err := b.Build()
+	if err != nil {
+		panic(err)
+	}
+
+	return session
+}
@@ -0,0 +1,162 @@
+// Package i18n localizes the messages of errors and validation failures by
+// their error code. Catalogs of messages are JSON files named by their BCP 47
+// language tag, such as de.json:
+//
+//	{
+//	  "errors": {"VALIDATION_FAILED": "Validierung fehlgeschlagen"},
+//	  "fields": {
+//	    "TOO_SHORT": "muss mindestens {min} Zeichen lang sein",
+//	    "email.INVALID_FORMAT": "muss eine gültige E-Mail-Adresse sein"
+//	  }
+//	}
+//
+// "errors" holds the message of each code, "fields" the message of a field
+// error by "<field>.<code>" or by its code alone, with the params of the
+// error in braces. The source messages are English; they are the fallback of
+// every message a catalog lacks.
+//
+// The locale of a request is negotiated from its Accept-Language header, see
+// Middleware and UnaryServerInterceptor, and errors are localized with
+// Localize.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+	"sync"
+
+	apperrors "github.com/LarsArtmann/template-sqlc/pkg/errors"
+	"golang.org/x/text/language"
+)
+
+// ErrInvalidCatalog is returned for a catalog that does not parse.
+var ErrInvalidCatalog = errors.New("invalid message catalog")
+
+// Messages are the localized messages of one language.
+type Messages struct {
+	// Errors are the messages of errors by code.
+	Errors map[apperrors.ErrorCode]string `json:"errors"`
+	// Fields are the messages of field errors by "<field>.<code>" or code.
+	Fields map[string]string `json:"fields"`
+}
+
+//go:embed locales
+var embeddedLocales embed.FS
+
+// Catalog holds the messages of the supported languages.
+type Catalog struct {
+	tags     []language.Tag
+	messages map[language.Tag]Messages
+	matcher  language.Matcher
+}
+
+// defaultCatalog is parsed once, as every adapter shares it.
+//
+//nolint:gochecknoglobals // Parsed once on first use
+var defaultCatalog = sync.OnceValue(func() *Catalog {
+	sub, err := fs.Sub(embeddedLocales, "locales")
+	if err != nil {
+		panic(err)
+	}
+
+	catalog, err := ParseCatalog(sub)
+	if err != nil {
+		panic(err)
+	}
+
+	return catalog
+})
+
+// DefaultCatalog returns the catalogs embedded in the binary. It panics if
+// they do not parse, which the tests catch.
+func DefaultCatalog() *Catalog {
+	return defaultCatalog()
+}
+
+// NewCatalog returns a catalog of the source language, English, only.
+func NewCatalog() *Catalog {
+	catalog := &Catalog{messages: make(map[language.Tag]Messages)}
+	catalog.Add(language.English, Messages{})
+
+	return catalog
+}
+
+// ParseCatalog parses the <tag>.json catalogs in the root of fsys, so that
+// the embedded ones can be replaced or extended.
+func ParseCatalog(fsys fs.FS) (*Catalog, error) {
+	names, err := fs.Glob(fsys, "*.json")
+	if err != nil {
+		return nil, fmt.Errorf("list message catalogs: %w", err)
+	}
+
+	catalog := NewCatalog()
+
+	for _, name := range names {
+		tag, err := language.Parse(strings.TrimSuffix(path.Base(name), ".json"))
+		if err != nil {
+			return nil, fmt.Errorf("message catalog=%v: %w: %w", name, ErrInvalidCatalog, err)
+		}
+
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, fmt.Errorf("read message catalog=%v: %w", name, err)
+		}
+
+		var messages Messages
+
+		err = json.Unmarshal(data, &messages)
+		if err != nil {
+			return nil, fmt.Errorf("message catalog=%v: %w: %w", name, ErrInvalidCatalog, err)
+		}
+
+		catalog.Add(tag, messages)
+	}
+
+	return catalog, nil
+}
+
+// Add sets the messages of tag, replacing any it had.
+func (c *Catalog) Add(tag language.Tag, messages Messages) {
+	if _, ok := c.messages[tag]; !ok {
+		c.tags = append(c.tags, tag)
+	}
+
+	c.messages[tag] = messages
+	c.matcher = language.NewMatcher(c.tags)
+}
+
+// Languages returns the supported languages, the source language first.
+func (c *Catalog) Languages() []language.Tag {
+	return append([]language.Tag(nil), c.tags...)
+}
+
+// Negotiate returns the supported language that best matches the
+// Accept-Language header values, or the source language.
+func (c *Catalog) Negotiate(acceptLanguage ...string) language.Tag {
+	var desired []language.Tag
+
+	for _, header := range acceptLanguage {
+		tags, _, err := language.ParseAcceptLanguage(header)
+		if err == nil {
+			desired = append(desired, tags...)
+		}
+	}
+
+	_, index, confidence := c.matcher.Match(desired...)
+	if confidence == language.No {
+		return language.English
+	}
+
+	return c.tags[index]
+}
+
+// Localizer returns the localizer of tag, which should be a supported
+// language, as returned by Negotiate.
+func (c *Catalog) Localizer(tag language.Tag) *Localizer {
+	return &Localizer{tag: tag, messages: c.messages[tag]}
+}
@@ -0,0 +1,150 @@
+package i18n
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	apperrors "github.com/LarsArtmann/template-sqlc/pkg/errors"
+	"golang.org/x/text/language"
+)
+
+// placeholder matches the {param} placeholders of a message.
+//
+//nolint:gochecknoglobals // Compiled once
+var placeholder = regexp.MustCompile(`\{(\w+)\}`)
+
+// fieldIndex matches the index of a field of a list, as in "tags[2]".
+//
+//nolint:gochecknoglobals // Compiled once
+var fieldIndex = regexp.MustCompile(`\[\d+\]`)
+
+// Localizer translates messages into one language.
+type Localizer struct {
+	tag      language.Tag
+	messages Messages
+}
+
+// Language returns the language of the localizer.
+func (l *Localizer) Language() language.Tag {
+	return l.tag
+}
+
+// ErrorMessage returns the message of code, or fallback if the language has none.
+func (l *Localizer) ErrorMessage(code apperrors.ErrorCode, fallback string) string {
+	if message, ok := l.messages.Errors[code]; ok {
+		return message
+	}
+
+	return fallback
+}
+
+// FieldMessage returns the message of fieldErr by "<field>.<code>", with any
+// index of the field left out, or by its code, or the message of fieldErr if
+// the language has neither or lacks a param of the message.
+func (l *Localizer) FieldMessage(fieldErr apperrors.FieldError) string {
+	field := fieldIndex.ReplaceAllString(fieldErr.Field, "")
+
+	for _, key := range []string{field + "." + string(fieldErr.Code), string(fieldErr.Code)} {
+		message, ok := l.messages.Fields[key]
+		if !ok {
+			continue
+		}
+
+		if expanded, ok := expand(message, fieldErr.Params); ok {
+			return expanded
+		}
+	}
+
+	return fieldErr.Message
+}
+
+// Localize returns err with a localized message and field errors. Errors
+// without a code, such as context.Canceled, and errors in the source
+// language are returned unchanged.
+func (l *Localizer) Localize(err error) error {
+	var coder apperrors.Coder
+	if l == nil || l.tag == language.English || !errors.As(err, &coder) {
+		return err
+	}
+
+	message := l.ErrorMessage(apperrors.ErrorCode(coder.ErrorCode()), "")
+	if message == "" {
+		return err
+	}
+
+	fields := apperrors.FieldErrorsOf(err)
+	for i := range fields {
+		fields[i].Message = l.FieldMessage(fields[i])
+	}
+
+	if len(fields) > 0 {
+		messages := make([]string, len(fields))
+		for i, fieldErr := range fields {
+			messages[i] = fieldErr.String()
+		}
+
+		message += ": " + strings.Join(messages, "; ")
+	}
+
+	return &Error{err: err, message: message, fields: fields}
+}
+
+// Error is an error with a localized message. It wraps the original error,
+// so that its code and status are kept.
+type Error struct {
+	err     error
+	message string
+	fields  apperrors.ValidationErrors
+}
+
+// Error returns the localized message.
+func (e *Error) Error() string {
+	return e.message
+}
+
+// Unwrap returns the original error.
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+// FieldErrors returns the localized field errors, see errors.FieldErrorsOf.
+func (e *Error) FieldErrors() apperrors.ValidationErrors {
+	return e.fields
+}
+
+// Localize localizes err into the language negotiated for ctx, see
+// Localizer.Localize.
+func Localize(ctx context.Context, err error) error {
+	localizer, ok := FromContext(ctx)
+	if !ok {
+		return err
+	}
+
+	return localizer.Localize(err)
+}
+
+// expand replaces the placeholders of message by params. Lists are joined
+// by commas. It reports false if a param is missing.
+func expand(message string, params map[string]any) (string, bool) {
+	complete := true
+
+	expanded := placeholder.ReplaceAllStringFunc(message, func(match string) string {
+		value, ok := params[match[1:len(match)-1]]
+		if !ok {
+			complete = false
+
+			return match
+		}
+
+		if list, ok := value.([]string); ok {
+			return strings.Join(list, ", ")
+		}
+
+		return fmt.Sprint(value)
+	})
+
+	return expanded, complete
+}
@@ -0,0 +1,55 @@
+package i18n
+
+import (
+	"context"
+	"net/http"
+
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// acceptLanguageMetadata is the metadata key of the languages of an RPC.
+const acceptLanguageMetadata = "accept-language"
+
+// localizerKey is the context key of the localizer of a request.
+type localizerKey struct{}
+
+// NewContext returns ctx with localizer.
+func NewContext(ctx context.Context, localizer *Localizer) context.Context {
+	return context.WithValue(ctx, localizerKey{}, localizer)
+}
+
+// FromContext returns the localizer of the request of ctx.
+func FromContext(ctx context.Context) (*Localizer, bool) {
+	localizer, ok := ctx.Value(localizerKey{}).(*Localizer)
+
+	return localizer, ok
+}
+
+// Middleware negotiates the language of each request from its
+// Accept-Language header and stores its localizer in the context.
+func Middleware(catalog *Catalog) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			tag := catalog.Negotiate(r.Header.Values("Accept-Language")...)
+			w.Header().Add("Vary", "Accept-Language")
+
+			next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), catalog.Localizer(tag))))
+		})
+	}
+}
+
+// UnaryServerInterceptor negotiates the language of each RPC from its
+// accept-language metadata and stores its localizer in the context.
+func UnaryServerInterceptor(catalog *Catalog) grpclib.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req any,
+		_ *grpclib.UnaryServerInfo,
+		handler grpclib.UnaryHandler,
+	) (any, error) {
+		tag := catalog.Negotiate(metadata.ValueFromIncomingContext(ctx, acceptLanguageMetadata)...)
+
+		return handler(NewContext(ctx, catalog.Localizer(tag)), req)
+	}
+}
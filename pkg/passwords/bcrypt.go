@@ -0,0 +1,62 @@
+package passwords
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// DefaultBcryptCost is the bcrypt work factor used when none is configured.
+const DefaultBcryptCost = 12
+
+// BcryptHasher hashes passwords with bcrypt.
+type BcryptHasher struct {
+	cost int
+}
+
+// NewBcryptHasher creates a bcrypt hasher. Costs outside bcrypt's supported
+// range fall back to DefaultBcryptCost.
+func NewBcryptHasher(cost int) *BcryptHasher {
+	if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		cost = DefaultBcryptCost
+	}
+
+	return &BcryptHasher{cost: cost}
+}
+
+// Hash returns the bcrypt hash of password.
+func (h *BcryptHasher) Hash(password string) (string, error) {
+	if password == "" {
+		return "", ErrEmptyPassword
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.cost)
+	if err != nil {
+		return "", fmt.Errorf("bcrypt: %w", err)
+	}
+
+	return string(hash), nil
+}
+
+// Verify compares password with a bcrypt hash in constant time.
+func (h *BcryptHasher) Verify(hash, password string) error {
+	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return ErrMismatchedPassword
+	}
+
+	if err != nil {
+		return fmt.Errorf("bcrypt: %w: %w", ErrUnsupportedHash, err)
+	}
+
+	return nil
+}
+
+// Identifies reports whether hash is a bcrypt hash.
+func (h *BcryptHasher) Identifies(hash string) bool {
+	return strings.HasPrefix(hash, "$2a$") ||
+		strings.HasPrefix(hash, "$2b$") ||
+		strings.HasPrefix(hash, "$2y$")
+}
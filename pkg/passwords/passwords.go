@@ -0,0 +1,80 @@
+// Package passwords provides password hashing and constant-time verification.
+package passwords
+
+import "errors"
+
+var (
+	// ErrMismatchedPassword is returned when a password does not match its hash.
+	ErrMismatchedPassword = errors.New("password does not match hash")
+
+	// ErrUnsupportedHash is returned when a hash was not produced by a known algorithm.
+	ErrUnsupportedHash = errors.New("unsupported password hash")
+
+	// ErrEmptyPassword is returned when hashing an empty password.
+	ErrEmptyPassword = errors.New("password must not be empty")
+)
+
+// PasswordHasher hashes passwords and verifies them against stored hashes.
+type PasswordHasher interface {
+	// Hash returns an encoded hash of password including its salt and parameters.
+	Hash(password string) (string, error)
+
+	// Verify compares password with hash in constant time.
+	// It returns ErrMismatchedPassword when they do not match.
+	Verify(hash, password string) error
+}
+
+// MultiHasher hashes with a primary algorithm and verifies hashes of any
+// registered algorithm, which allows migrating stored hashes over time.
+type MultiHasher struct {
+	primary   IdentifyingHasher
+	verifiers []IdentifyingHasher
+}
+
+// IdentifyingHasher is a PasswordHasher that recognizes its own hashes.
+type IdentifyingHasher interface {
+	PasswordHasher
+	Identifies(hash string) bool
+}
+
+// NewMultiHasher creates a hasher that hashes with primary and verifies
+// hashes produced by primary or any of the legacy hashers.
+func NewMultiHasher(primary IdentifyingHasher, legacy ...IdentifyingHasher) *MultiHasher {
+	return &MultiHasher{
+		primary:   primary,
+		verifiers: append([]IdentifyingHasher{primary}, legacy...),
+	}
+}
+
+// Hash hashes password with the primary algorithm.
+func (m *MultiHasher) Hash(password string) (string, error) {
+	return m.primary.Hash(password)
+}
+
+// Identifies reports whether any registered algorithm produced hash.
+func (m *MultiHasher) Identifies(hash string) bool {
+	for _, verifier := range m.verifiers {
+		if verifier.Identifies(hash) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Verify checks password with whichever algorithm produced hash.
+func (m *MultiHasher) Verify(hash, password string) error {
+	for _, verifier := range m.verifiers {
+		if verifier.Identifies(hash) {
+			return verifier.Verify(hash, password)
+		}
+	}
+
+	return ErrUnsupportedHash
+}
+
+var (
+	_ IdentifyingHasher = (*BcryptHasher)(nil)
+	_ IdentifyingHasher = (*Argon2idHasher)(nil)
+	_ IdentifyingHasher = (*MultiHasher)(nil)
+)
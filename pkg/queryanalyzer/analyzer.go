@@ -0,0 +1,249 @@
+// Package queryanalyzer runs EXPLAIN for named sqlc queries against a
+// development database and reports sequential scans, estimated costs and the
+// indexes that would avoid the scans.
+//
+// sqlc.arg, sqlc.narg, sqlc.slice and @name parameters are rewritten to the
+// engine's placeholders before planning. Parameters are bound as follows:
+//
+//   - PostgreSQL plans queries without sample parameters with
+//     EXPLAIN (GENERIC_PLAN), which needs PostgreSQL 16 or later.
+//   - MySQL and SQLite bind "1" to every parameter, and 10 to LIMIT and
+//     OFFSET parameters, unless samples are given with WithParams.
+//
+// With WithAnalyze, read-only queries are executed with EXPLAIN ANALYZE inside
+// a read-only transaction that is rolled back. Writes are never executed, and
+// SQLite has no EXPLAIN ANALYZE.
+//
+// Plans depend on the data: the planner prefers a sequential scan over an
+// index for small tables, so analyze a database with representative volumes.
+package queryanalyzer
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/pkg/db"
+)
+
+var (
+	// ErrUnsupportedDriver is returned for drivers the analyzer cannot explain for.
+	ErrUnsupportedDriver = errors.New("unsupported database driver")
+
+	// ErrSampleCount is reported when the sample parameters of a query do not
+	// match its placeholders.
+	ErrSampleCount = errors.New("wrong number of sample parameters")
+)
+
+// Query is a named sqlc query.
+type Query struct {
+	Name string
+	SQL  string
+}
+
+// Result is the analysis of one query.
+type Result struct {
+	Query string `json:"query"`
+	// Analyzed is true when the query was executed with EXPLAIN ANALYZE.
+	Analyzed bool `json:"analyzed"`
+	// Cost is the planner's estimated total cost; SQLite does not estimate it.
+	Cost float64 `json:"cost,omitempty"`
+	// Time is the measured execution time of analyzed queries.
+	Time time.Duration `json:"time,omitempty"`
+	// SeqScans are the tables read in full.
+	SeqScans []string `json:"seq_scans,omitempty"`
+	// Suggestions are CREATE INDEX statements for the scanned tables.
+	Suggestions []string `json:"suggestions,omitempty"`
+	Notes       []string `json:"notes,omitempty"`
+	// Plan is the raw EXPLAIN output.
+	Plan string `json:"plan,omitempty"`
+	// Error is set when the query could not be explained.
+	Error string `json:"error,omitempty"`
+}
+
+// Report collects the results of one engine.
+type Report struct {
+	Engine  db.Driver `json:"engine"`
+	Results []Result  `json:"results"`
+}
+
+// Analyzer explains queries against a database.
+type Analyzer struct {
+	db      *sql.DB
+	driver  db.Driver
+	params  map[string][]any
+	analyze bool
+	timeout time.Duration
+}
+
+// Option configures an Analyzer.
+type Option func(*Analyzer)
+
+// WithParams sets the sample parameters of the query named name, in
+// placeholder order.
+func WithParams(name string, args ...any) Option {
+	return func(a *Analyzer) {
+		a.params[name] = args
+	}
+}
+
+// WithAnalyze executes read-only queries with EXPLAIN ANALYZE.
+func WithAnalyze(analyze bool) Option {
+	return func(a *Analyzer) {
+		a.analyze = analyze
+	}
+}
+
+// WithTimeout bounds the time spent on each query.
+func WithTimeout(timeout time.Duration) Option {
+	return func(a *Analyzer) {
+		a.timeout = timeout
+	}
+}
+
+// New creates an Analyzer for database, which is opened with driver's
+// database/sql driver; for PostgreSQL use github.com/jackc/pgx/v5/stdlib.
+func New(database *sql.DB, driver db.Driver, opts ...Option) (*Analyzer, error) {
+	switch driver {
+	case db.DriverPostgres, db.DriverMySQL, db.DriverSQLite:
+	default:
+		return nil, fmt.Errorf("driver=%v: %w", driver, ErrUnsupportedDriver)
+	}
+
+	analyzer := &Analyzer{
+		db:      database,
+		driver:  driver,
+		params:  make(map[string][]any),
+		timeout: 30 * time.Second,
+	}
+
+	for _, opt := range opts {
+		opt(analyzer)
+	}
+
+	return analyzer, nil
+}
+
+// Analyze explains every query. A query that cannot be explained gets a
+// Result with Error set; only a cancelled ctx stops the run.
+func (a *Analyzer) Analyze(ctx context.Context, queries []Query) (*Report, error) {
+	report := &Report{Engine: a.driver, Results: make([]Result, 0, len(queries))}
+
+	for _, query := range queries {
+		err := ctx.Err()
+		if err != nil {
+			return nil, fmt.Errorf("query=%v: %w", query.Name, err)
+		}
+
+		result, err := a.explainQuery(ctx, query)
+		if err != nil {
+			result = Result{Query: query.Name, Error: err.Error()}
+		}
+
+		report.Results = append(report.Results, result)
+	}
+
+	return report, nil
+}
+
+func (a *Analyzer) explainQuery(ctx context.Context, query Query) (Result, error) {
+	ctx, cancel := context.WithTimeout(ctx, a.timeout)
+	defer cancel()
+
+	prepared := prepare(a.driver, query.SQL)
+
+	args, sampled := a.params[query.Name]
+	if sampled && len(args) != prepared.count {
+		return Result{}, fmt.Errorf("%d parameters, %d sample values: %w", prepared.count, len(args), ErrSampleCount)
+	}
+
+	if !sampled && a.driver != db.DriverPostgres {
+		args = prepared.samples()
+	}
+
+	result := Result{Query: query.Name}
+	analyze := a.analyze && a.driver != db.DriverSQLite
+
+	if analyze && !readOnly(prepared.sql) {
+		analyze = false
+
+		result.Notes = append(result.Notes, "writes are not executed; the plan is estimated")
+	}
+
+	var (
+		explained *plan
+		err       error
+	)
+
+	switch a.driver {
+	case db.DriverPostgres:
+		explained, err = a.explainPostgres(ctx, prepared, args, analyze)
+	case db.DriverMySQL:
+		explained, err = a.explainMySQL(ctx, prepared.sql, args, analyze)
+	case db.DriverSQLite:
+		explained, err = a.explainSQLite(ctx, prepared.sql, args)
+	}
+
+	if err != nil {
+		return Result{}, err
+	}
+
+	result.Analyzed = explained.analyzed
+	result.Plan = explained.raw
+	result.Cost = explained.cost
+	result.Time = explained.time
+	result.Notes = append(result.Notes, explained.notes...)
+
+	// Scans of derived tables and common table expressions are left out.
+	aliases := tableAliases(prepared.sql)
+
+	for _, scanned := range explained.seqScans {
+		table, ok := aliases[strings.ToLower(scanned)]
+		if ok && !slices.Contains(result.SeqScans, table) {
+			result.SeqScans = append(result.SeqScans, table)
+		}
+	}
+
+	result.Suggestions = suggestIndexes(prepared.sql, aliases, result.SeqScans)
+
+	return result, nil
+}
+
+// explain runs an EXPLAIN statement and passes each row to scan. Statements
+// that execute the query run in a read-only transaction that is rolled back.
+func (a *Analyzer) explain(ctx context.Context, analyze bool, statement string, args []any, scan func(*sql.Rows) error) error {
+	var querier interface {
+		QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	} = a.db
+
+	if analyze {
+		tx, err := a.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+		if err != nil {
+			return fmt.Errorf("begin: %w", err)
+		}
+
+		defer func() { _ = tx.Rollback() }()
+
+		querier = tx
+	}
+
+	rows, err := querier.QueryContext(ctx, statement, args...)
+	if err != nil {
+		return fmt.Errorf("explain: %w", err)
+	}
+
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		err := scan(rows)
+		if err != nil {
+			return fmt.Errorf("scan plan: %w", err)
+		}
+	}
+
+	return rows.Err()
+}
@@ -0,0 +1,203 @@
+package queryanalyzer
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var errPlanEmpty = errors.New("parse plan: empty plan")
+
+// plan is the engine-independent summary of an EXPLAIN.
+type plan struct {
+	raw      string
+	analyzed bool
+	cost     float64
+	time     time.Duration
+	seqScans []string
+	notes    []string
+}
+
+func (p *plan) addScan(table string) {
+	if table != "" && !slices.Contains(p.seqScans, table) {
+		p.seqScans = append(p.seqScans, table)
+	}
+}
+
+func (p *plan) addNote(note string) {
+	if !slices.Contains(p.notes, note) {
+		p.notes = append(p.notes, note)
+	}
+}
+
+// postgresNode is a node of EXPLAIN (FORMAT JSON).
+type postgresNode struct {
+	NodeType  string         `json:"Node Type"`
+	Relation  string         `json:"Relation Name"`
+	TotalCost float64        `json:"Total Cost"`
+	SortKey   []string       `json:"Sort Key"`
+	Plans     []postgresNode `json:"Plans"`
+}
+
+func (a *Analyzer) explainPostgres(ctx context.Context, query prepared, args []any, analyze bool) (*plan, error) {
+	explained := &plan{}
+	options := "FORMAT JSON"
+
+	switch {
+	case args == nil && query.count > 0:
+		options = "GENERIC_PLAN, " + options
+
+		if analyze {
+			analyze = false
+
+			explained.addNote("no sample parameters; planned generically without executing")
+		}
+	case analyze:
+		options = "ANALYZE, " + options
+	}
+
+	var raw []byte
+
+	err := a.explain(ctx, analyze, fmt.Sprintf("EXPLAIN (%s) %s", options, query.sql), args, func(rows *sql.Rows) error {
+		return rows.Scan(&raw)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var plans []struct {
+		Plan          postgresNode `json:"Plan"`
+		ExecutionTime float64      `json:"Execution Time"`
+	}
+
+	err = json.Unmarshal(raw, &plans)
+	if err != nil {
+		return nil, fmt.Errorf("parse plan: %w", err)
+	}
+
+	if len(plans) == 0 {
+		return nil, errPlanEmpty
+	}
+
+	explained.raw = string(raw)
+	explained.analyzed = analyze
+	explained.cost = plans[0].Plan.TotalCost
+
+	if analyze {
+		explained.time = milliseconds(plans[0].ExecutionTime)
+	}
+
+	var walk func(node postgresNode)
+
+	walk = func(node postgresNode) {
+		switch node.NodeType {
+		case "Seq Scan":
+			explained.addScan(node.Relation)
+		case "Sort":
+			explained.addNote(fmt.Sprintf("sorts on %s; an index in that order avoids the sort",
+				strings.Join(node.SortKey, ", ")))
+		}
+
+		for _, child := range node.Plans {
+			walk(child)
+		}
+	}
+
+	walk(plans[0].Plan)
+
+	return explained, nil
+}
+
+var (
+	mysqlTableScan  = regexp.MustCompile(`Table scan on (\w+)`)
+	mysqlCost       = regexp.MustCompile(`\(cost=([\d.e+]+)`)
+	mysqlActualTime = regexp.MustCompile(`actual time=[\d.]+\.\.([\d.]+)`)
+)
+
+// explainMySQL reads the tree format, which EXPLAIN ANALYZE also prints and
+// which needs MySQL 8.0.18 or later.
+func (a *Analyzer) explainMySQL(ctx context.Context, query string, args []any, analyze bool) (*plan, error) {
+	statement := "EXPLAIN FORMAT=TREE " + query
+	if analyze {
+		statement = "EXPLAIN ANALYZE " + query
+	}
+
+	var raw string
+
+	err := a.explain(ctx, analyze, statement, args, func(rows *sql.Rows) error {
+		return rows.Scan(&raw)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	explained := &plan{raw: raw, analyzed: analyze}
+	first, _, _ := strings.Cut(raw, "\n")
+
+	if match := mysqlCost.FindStringSubmatch(first); match != nil {
+		explained.cost, _ = strconv.ParseFloat(match[1], 64)
+	}
+
+	if match := mysqlActualTime.FindStringSubmatch(first); match != nil {
+		actual, _ := strconv.ParseFloat(match[1], 64)
+		explained.time = milliseconds(actual)
+	}
+
+	for _, match := range mysqlTableScan.FindAllStringSubmatch(raw, -1) {
+		explained.addScan(match[1])
+	}
+
+	if strings.Contains(raw, "Sort:") {
+		explained.addNote("sorts the rows; an index in ORDER BY order avoids the sort")
+	}
+
+	return explained, nil
+}
+
+var sqliteScan = regexp.MustCompile(`^SCAN (?:TABLE )?(\w+)(?: AS (\w+))?$`)
+
+// explainSQLite reads EXPLAIN QUERY PLAN. A SCAN without USING reads the whole
+// table; SQLite reports aliases instead of table names.
+func (a *Analyzer) explainSQLite(ctx context.Context, query string, args []any) (*plan, error) {
+	var details []string
+
+	err := a.explain(ctx, false, "EXPLAIN QUERY PLAN "+query, args, func(rows *sql.Rows) error {
+		var (
+			id, parent, unused int
+			detail             string
+		)
+
+		err := rows.Scan(&id, &parent, &unused, &detail)
+		details = append(details, detail)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	explained := &plan{raw: strings.Join(details, "\n")}
+
+	for _, detail := range details {
+		if match := sqliteScan.FindStringSubmatch(detail); match != nil {
+			explained.addScan(match[1])
+		}
+
+		if strings.HasPrefix(detail, "USE TEMP B-TREE FOR ORDER BY") {
+			explained.addNote("sorts with a temporary b-tree; an index in ORDER BY order avoids the sort")
+		}
+	}
+
+	return explained, nil
+}
+
+func milliseconds(ms float64) time.Duration {
+	return time.Duration(ms * float64(time.Millisecond))
+}
@@ -0,0 +1,212 @@
+package queryanalyzer
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/LarsArtmann/template-sqlc/pkg/db"
+)
+
+// Sample values bound when a query has no samples.
+const (
+	sampleValue = "1"
+	sampleLimit = int64(10)
+)
+
+var (
+	sqlcParam   = regexp.MustCompile(`(?i)sqlc\.(?:n?arg|slice)\(\s*['"]?(\w+)['"]?\s*\)|(?:^|[^\w@])@(\w+)`)
+	sqlcEmbed   = regexp.MustCompile(`(?i)sqlc\.embed\(\s*(\w+)\s*\)`)
+	placeholder = regexp.MustCompile(`[$?](\d*)`)
+	comment     = regexp.MustCompile(`--[^\n]*`)
+	stringLit   = regexp.MustCompile(`'(?:[^']|'')*'`)
+	limitBefore = regexp.MustCompile(`(?i)\b(?:limit|offset)\s*$`)
+	writes      = regexp.MustCompile(`(?i)\b(?:insert|update|delete|merge|for\s+update|for\s+share|nextval|setval)\b`)
+)
+
+// prepared is a query with engine placeholders.
+type prepared struct {
+	sql   string
+	count int
+	// limits marks the parameters of LIMIT and OFFSET, by position.
+	limits []bool
+}
+
+// prepare strips the comments of query and rewrites its sqlc parameters to
+// driver's placeholders the way sqlc does: numbered by first use, $n for
+// PostgreSQL and ?n for SQLite, and one ? per use for MySQL.
+func prepare(driver db.Driver, query string) prepared {
+	query = comment.ReplaceAllString(query, "")
+	query = sqlcEmbed.ReplaceAllString(strings.TrimSpace(query), "$1.*")
+	query = strings.TrimSuffix(query, ";")
+
+	var names []string
+
+	query = sqlcParam.ReplaceAllStringFunc(query, func(match string) string {
+		groups := sqlcParam.FindStringSubmatch(match)
+		name, prefix := groups[1], ""
+
+		if name == "" {
+			name = groups[2]
+			prefix = match[:strings.Index(match, "@")]
+		}
+
+		if driver == db.DriverMySQL {
+			return prefix + "?"
+		}
+
+		index := slices.Index(names, name)
+		if index < 0 {
+			names = append(names, name)
+			index = len(names) - 1
+		}
+
+		if driver == db.DriverSQLite {
+			return prefix + "?" + strconv.Itoa(index+1)
+		}
+
+		return prefix + "$" + strconv.Itoa(index+1)
+	})
+
+	p := prepared{sql: query}
+	blanked := stringLit.ReplaceAllStringFunc(query, func(literal string) string {
+		return strings.Repeat("_", len(literal))
+	})
+
+	p.limits = []bool{}
+
+	for _, match := range placeholder.FindAllStringSubmatchIndex(blanked, -1) {
+		limit := limitBefore.MatchString(blanked[:match[0]])
+
+		// Unnumbered placeholders count in order of appearance.
+		if match[2] == match[3] {
+			if blanked[match[0]] == '?' {
+				p.limits = append(p.limits, limit)
+			}
+
+			continue
+		}
+
+		index, _ := strconv.Atoi(blanked[match[2]:match[3]])
+		for len(p.limits) < index {
+			p.limits = append(p.limits, false)
+		}
+
+		p.limits[index-1] = p.limits[index-1] || limit
+	}
+
+	p.count = len(p.limits)
+
+	return p
+}
+
+// samples returns the default sample parameters.
+func (p prepared) samples() []any {
+	args := make([]any, p.count)
+	for i, limit := range p.limits {
+		if limit {
+			args[i] = sampleLimit
+		} else {
+			args[i] = sampleValue
+		}
+	}
+
+	return args
+}
+
+// readOnly reports whether executing query cannot change or lock data.
+func readOnly(query string) bool {
+	code := strings.ToLower(stringLit.ReplaceAllString(query, "''"))
+	fields := strings.Fields(code)
+
+	if len(fields) == 0 || (fields[0] != "select" && fields[0] != "with") {
+		return false
+	}
+
+	return !writes.MatchString(code)
+}
+
+var (
+	tableRef  = regexp.MustCompile(`(?i)\b(?:from|join|update|into)\s+["` + "`" + `]?(\w+)["` + "`" + `]?(?:\s+(?:as\s+)?(\w+))?`)
+	cteName   = regexp.MustCompile(`(?i)(?:\bwith(?:\s+recursive)?|,)\s*(\w+)\s+as\s*(?:not\s+)?(?:materialized\s*)?\(`)
+	predicate = regexp.MustCompile(`(?i)(?:\b(\w+)\.)?\b(\w+)\s*(=|<>|!=|<=|>=|<|>|\bin\b|\blike\b|\bilike\b|\bbetween\b)\s*(?:\(\s*)?(?:\$\d+|\?\d*|any\s*\(\s*\$\d+)`)
+	aliasStop = []string{"where", "join", "left", "right", "inner", "outer", "cross", "on", "set", "values", "order", "group", "limit", "using", "natural", "full"}
+)
+
+// tableAliases maps the tables of query and their aliases to the table
+// names. Common table expressions are not tables and are left out.
+func tableAliases(query string) map[string]string {
+	var ctes []string
+
+	for _, match := range cteName.FindAllStringSubmatch(query, -1) {
+		ctes = append(ctes, strings.ToLower(match[1]))
+	}
+
+	aliases := map[string]string{}
+
+	for _, match := range tableRef.FindAllStringSubmatch(query, -1) {
+		table := strings.ToLower(match[1])
+		if slices.Contains(ctes, table) {
+			continue
+		}
+
+		aliases[table] = table
+
+		if alias := strings.ToLower(match[2]); alias != "" && !slices.Contains(aliasStop, alias) {
+			aliases[alias] = table
+		}
+	}
+
+	return aliases
+}
+
+// suggestIndexes proposes an index on the parameter-compared columns of each
+// scanned table: equality columns first, range and pattern columns after.
+// Unqualified columns are attributed to the table of single-table queries.
+func suggestIndexes(query string, aliases map[string]string, tables []string) []string {
+	single := ""
+
+	for _, table := range aliases {
+		if single == "" {
+			single = table
+		} else if single != table {
+			single = "-"
+		}
+	}
+
+	var suggestions []string
+
+	for _, table := range tables {
+		var equal, other []string
+
+		for _, match := range predicate.FindAllStringSubmatch(query, -1) {
+			owner := aliases[strings.ToLower(match[1])]
+			if match[1] == "" {
+				owner = single
+			}
+
+			column := strings.ToLower(match[2])
+			if owner != table || slices.Contains(equal, column) || slices.Contains(other, column) {
+				continue
+			}
+
+			if match[3] == "=" || strings.EqualFold(match[3], "in") {
+				equal = append(equal, column)
+			} else {
+				other = append(other, column)
+			}
+		}
+
+		columns := append(equal, other...)
+		if len(columns) == 0 {
+			continue
+		}
+
+		suggestions = append(suggestions, fmt.Sprintf("CREATE INDEX idx_%s_%s ON %s (%s);",
+			table, strings.Join(columns, "_"), table, strings.Join(columns, ", ")))
+	}
+
+	return suggestions
+}
@@ -0,0 +1,47 @@
+package ratelimit
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// unknownAddress keys the bucket of login attempts without a client address.
+const unknownAddress = "unknown"
+
+// LoginThrottle limits login attempts per client IP address, against
+// password spraying, and per account, against guessing one password from
+// many addresses. It satisfies services.LoginThrottle.
+type LoginThrottle struct {
+	perIP      Rule
+	perAccount Rule
+}
+
+// NewLoginThrottle creates a throttle applying perIP and perAccount to
+// every attempt.
+func NewLoginThrottle(perIP, perAccount Rule) *LoginThrottle {
+	return &LoginThrottle{perIP: perIP, perAccount: perAccount}
+}
+
+// AllowLogin takes a token for the address and one for the account of a
+// login attempt. It returns how long to wait if either bucket is empty,
+// or zero if the attempt may proceed. Attempts of unknown addresses share
+// one bucket, so that leaving the address out does not evade the limit.
+func (t *LoginThrottle) AllowLogin(ctx context.Context, ipAddress, account string) (time.Duration, error) {
+	if ipAddress == "" {
+		ipAddress = unknownAddress
+	}
+
+	ipResult, err := t.perIP.Allow(ctx, "login:ip:"+ipAddress)
+	if err != nil {
+		return 0, err
+	}
+
+	// Accounts are keyed case-insensitively, like email addresses.
+	result, err := t.perAccount.Allow(ctx, "login:account:"+strings.ToLower(strings.TrimSpace(account)))
+	if err != nil {
+		return 0, err
+	}
+
+	return max(ipResult.RetryAfter, result.RetryAfter), nil
+}
@@ -0,0 +1,101 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often Memory forgets refilled buckets.
+const sweepInterval = time.Minute
+
+// memoryBucket is a bucket with the limit it was last taken from.
+type memoryBucket struct {
+	bucket
+
+	limit Limit
+}
+
+// Memory keeps buckets in process memory. Each process limits on its own,
+// so N instances allow up to N times the limit.
+type Memory struct {
+	mu        sync.Mutex
+	buckets   map[string]*memoryBucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+var _ Limiter = (*Memory)(nil)
+
+// MemoryOption configures a Memory limiter.
+type MemoryOption func(*Memory)
+
+// WithClock makes the limiter read the time from now.
+func WithClock(now func() time.Time) MemoryOption {
+	return func(m *Memory) {
+		m.now = now
+	}
+}
+
+// NewMemory creates an in-process limiter.
+func NewMemory(opts ...MemoryOption) *Memory {
+	limiter := &Memory{
+		buckets: make(map[string]*memoryBucket),
+		now:     time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(limiter)
+	}
+
+	limiter.lastSweep = limiter.now()
+
+	return limiter
+}
+
+// Allow takes a token from the bucket of key.
+func (m *Memory) Allow(_ context.Context, key string, limit Limit) (Result, error) {
+	err := limit.Validate()
+	if err != nil {
+		return Result{}, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	m.sweep(now)
+
+	b, ok := m.buckets[key]
+	if !ok {
+		b = &memoryBucket{bucket: bucket{tokens: float64(limit.Burst), updated: now}}
+		m.buckets[key] = b
+	}
+
+	b.limit = limit
+
+	return b.take(limit, now), nil
+}
+
+// sweep forgets the buckets that refilled, since a new bucket is full too.
+func (m *Memory) sweep(now time.Time) {
+	if now.Sub(m.lastSweep) < sweepInterval {
+		return
+	}
+
+	m.lastSweep = now
+
+	for key, b := range m.buckets {
+		if b.full(b.limit, now) {
+			delete(m.buckets, key)
+		}
+	}
+}
+
+// Len returns the number of buckets kept, including refilled ones not yet swept.
+func (m *Memory) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return len(m.buckets)
+}
@@ -0,0 +1,109 @@
+package ratelimit
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// KeyFunc returns the key of the bucket an HTTP request takes from. An
+// empty key is not limited.
+type KeyFunc func(r *http.Request) string
+
+// ClientIP keys requests by the IP address of the connection. Behind a
+// proxy, key by the forwarded address the proxy sets instead.
+func ClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+// Middleware rejects requests whose bucket under key is empty with 429 Too
+// Many Requests and a Retry-After header. Requests are let through if the
+// limiter fails, so that an outage of Redis does not take the API down.
+func Middleware(rule Rule, key KeyFunc) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			result, ok := allow(r.Context(), rule, key(r))
+			if !ok {
+				w.Header().Set("Retry-After", strconv.Itoa(retryAfterSeconds(result.RetryAfter)))
+				http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// UnaryKeyFunc returns the key of the bucket an RPC takes from. An empty
+// key is not limited.
+type UnaryKeyFunc func(ctx context.Context, fullMethod string) string
+
+// PeerIP keys RPCs by the IP address of the calling peer.
+func PeerIP(ctx context.Context, _ string) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+
+	return host
+}
+
+// UnaryServerInterceptor rejects RPCs whose bucket under key is empty with
+// codes.ResourceExhausted. RPCs are let through if the limiter fails.
+func UnaryServerInterceptor(rule Rule, key UnaryKeyFunc) grpclib.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req any,
+		info *grpclib.UnaryServerInfo,
+		handler grpclib.UnaryHandler,
+	) (any, error) {
+		result, ok := allow(ctx, rule, key(ctx, info.FullMethod))
+		if !ok {
+			return nil, status.Errorf(codes.ResourceExhausted,
+				"rate limit exceeded, retry after %v", result.RetryAfter.Round(time.Millisecond))
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// allow takes a token for key and reports whether the request may proceed.
+func allow(ctx context.Context, rule Rule, key string) (Result, bool) {
+	if key == "" {
+		return Result{Allowed: true}, true
+	}
+
+	result, err := rule.Allow(ctx, key)
+	if err != nil {
+		slog.Warn("rate limiter failed, allowing request", "error", err)
+
+		return Result{Allowed: true}, true
+	}
+
+	return result, result.Allowed
+}
+
+// retryAfterSeconds rounds wait up to whole seconds, at least one.
+func retryAfterSeconds(wait time.Duration) int {
+	return max(1, int(math.Ceil(wait.Seconds())))
+}
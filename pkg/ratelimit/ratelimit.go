@@ -0,0 +1,138 @@
+// Package ratelimit limits requests with token buckets kept in memory or in
+// Redis, and applies them as HTTP middleware, as a gRPC interceptor and to
+// login attempts.
+//
+// Every key, such as a client IP address, has a bucket of Limit.Burst
+// tokens that refills one token every Limit.Every. A request takes a token
+// and is rejected while the bucket is empty:
+//
+//	limiter := ratelimit.NewRedis(client)
+//	rule := ratelimit.Rule{Limiter: limiter, Limit: ratelimit.Limit{Burst: 20, Every: time.Second}}
+//	handler = ratelimit.Middleware(rule, ratelimit.ClientIP)(handler)
+//
+// The memory limiter keeps the buckets of one process; Redis shares them
+// between all instances.
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// ErrInvalidLimit is returned for a limit with a negative burst or an
+// enabled limit without a refill interval.
+var ErrInvalidLimit = errors.New("invalid rate limit")
+
+// Limit sizes a token bucket. The zero Limit disables limiting.
+type Limit struct {
+	// Burst is how many requests may be made at once.
+	Burst int `yaml:"burst"`
+	// Every is how long one token takes to refill.
+	Every time.Duration `yaml:"every"`
+}
+
+// Enabled reports whether the limit rejects any requests.
+func (l Limit) Enabled() bool {
+	return l.Burst > 0
+}
+
+// Validate returns ErrInvalidLimit for a limit no bucket can have.
+func (l Limit) Validate() error {
+	if l.Burst < 0 || (l.Enabled() && l.Every <= 0) {
+		return fmt.Errorf("burst=%d every=%v: %w", l.Burst, l.Every, ErrInvalidLimit)
+	}
+
+	return nil
+}
+
+// Result is the outcome of one request.
+type Result struct {
+	Allowed bool
+	// Remaining is how many more requests the bucket allows right now.
+	Remaining int
+	// RetryAfter is how long a rejected request should wait for a token.
+	RetryAfter time.Duration
+}
+
+// Limiter takes tokens from the buckets of keys.
+// Implementations must be safe for concurrent use.
+type Limiter interface {
+	// Allow takes a token from the bucket of key sized by limit.
+	Allow(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// Rule applies Limit with Limiter. A rule without a limiter or with a
+// disabled limit allows every request.
+type Rule struct {
+	Limiter Limiter
+	Limit   Limit
+}
+
+// Allow takes a token for key, allowing the request if the rule is disabled.
+func (r Rule) Allow(ctx context.Context, key string) (Result, error) {
+	if r.Limiter == nil || !r.Limit.Enabled() {
+		return Result{Allowed: true, Remaining: math.MaxInt}, nil
+	}
+
+	return r.Limiter.Allow(ctx, key, r.Limit)
+}
+
+// Recorder records limiter decisions. *monitoring.Metrics satisfies it.
+type Recorder interface {
+	ObserveRateLimit(scope string, allowed bool)
+}
+
+// instrumented records the decisions of a Limiter under a scope.
+type instrumented struct {
+	limiter  Limiter
+	recorder Recorder
+	scope    string
+}
+
+// Instrument records every decision of limiter with recorder under scope,
+// such as "http" or "login_ip".
+func Instrument(limiter Limiter, recorder Recorder, scope string) Limiter {
+	return &instrumented{limiter: limiter, recorder: recorder, scope: scope}
+}
+
+// Allow takes a token and records whether the request was allowed.
+func (i *instrumented) Allow(ctx context.Context, key string, limit Limit) (Result, error) {
+	result, err := i.limiter.Allow(ctx, key, limit)
+	if err == nil {
+		i.recorder.ObserveRateLimit(i.scope, result.Allowed)
+	}
+
+	return result, err
+}
+
+// bucket is the state of a token bucket at updated.
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// take refills b for the time since its update and takes a token if one
+// is left.
+func (b *bucket) take(limit Limit, now time.Time) Result {
+	elapsed := max(now.Sub(b.updated), 0)
+	b.tokens = min(float64(limit.Burst), b.tokens+float64(elapsed)/float64(limit.Every))
+	b.updated = now
+
+	if b.tokens < 1 {
+		return Result{RetryAfter: time.Duration(math.Ceil((1 - b.tokens) * float64(limit.Every)))}
+	}
+
+	b.tokens--
+
+	return Result{Allowed: true, Remaining: int(b.tokens)}
+}
+
+// full reports whether b is refilled at now, so that it can be forgotten.
+func (b *bucket) full(limit Limit, now time.Time) bool {
+	missing := float64(limit.Burst) - b.tokens
+
+	return now.Sub(b.updated) >= time.Duration(missing*float64(limit.Every))
+}
@@ -0,0 +1,113 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultRedisPrefix is prepended to the Redis keys of buckets.
+const DefaultRedisPrefix = "ratelimit:"
+
+// errUnexpectedReply is returned for a script reply of the wrong shape.
+var errUnexpectedReply = errors.New("unexpected rate limit script reply")
+
+// takeScript refills and takes from a bucket stored as a hash of its tokens
+// and update time in microseconds, the same way as bucket.take. It returns
+// whether the request is allowed, the whole tokens left and the retry
+// delay in microseconds. The key expires once the bucket is full again.
+const takeScript = `
+local burst = tonumber(ARGV[1])
+local every = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'updated')
+local tokens = tonumber(state[1])
+local updated = tonumber(state[2])
+if tokens == nil or updated == nil then
+  tokens = burst
+  updated = now
+end
+
+tokens = math.min(burst, tokens + math.max(now - updated, 0) / every)
+
+local allowed = 0
+local retry = 0
+if tokens >= 1 then
+  tokens = tokens - 1
+  allowed = 1
+else
+  retry = math.ceil((1 - tokens) * every)
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'updated', now)
+redis.call('PEXPIRE', KEYS[1], math.ceil((burst - tokens) * every / 1000) + 1)
+
+return {allowed, math.floor(tokens), retry}
+`
+
+// Redis keeps buckets in Redis, so that all instances share the limits.
+// Each Allow is one atomic script call.
+type Redis struct {
+	client redis.Scripter
+	script *redis.Script
+	prefix string
+	now    func() time.Time
+}
+
+var _ Limiter = (*Redis)(nil)
+
+// RedisOption configures a Redis limiter.
+type RedisOption func(*Redis)
+
+// WithPrefix sets the prefix of the bucket keys instead of DefaultRedisPrefix.
+func WithPrefix(prefix string) RedisOption {
+	return func(r *Redis) {
+		r.prefix = prefix
+	}
+}
+
+// NewRedis creates a limiter on client, which may be a *redis.Client,
+// *redis.ClusterClient or any other redis.Scripter.
+func NewRedis(client redis.Scripter, opts ...RedisOption) *Redis {
+	limiter := &Redis{
+		client: client,
+		script: redis.NewScript(takeScript),
+		prefix: DefaultRedisPrefix,
+		now:    time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(limiter)
+	}
+
+	return limiter
+}
+
+// Allow takes a token from the bucket of key.
+func (r *Redis) Allow(ctx context.Context, key string, limit Limit) (Result, error) {
+	err := limit.Validate()
+	if err != nil {
+		return Result{}, err
+	}
+
+	reply, err := r.script.Run(ctx, r.client, []string{r.prefix + key},
+		limit.Burst, limit.Every.Microseconds(), r.now().UnixMicro()).Int64Slice()
+	if err != nil {
+		return Result{}, fmt.Errorf("redis rate limit key=%v: %w", key, err)
+	}
+
+	const replyLen = 3
+	if len(reply) != replyLen {
+		return Result{}, fmt.Errorf("redis rate limit key=%v reply=%v: %w", key, reply, errUnexpectedReply)
+	}
+
+	return Result{
+		Allowed:    reply[0] == 1,
+		Remaining:  int(reply[1]),
+		RetryAfter: time.Duration(reply[2]) * time.Microsecond,
+	}, nil
+}
@@ -0,0 +1,137 @@
+// Package scan maps sqlc row structs to domain entities with mapper
+// functions registered per row and entity type:
+//
+//	scan.Register[sqlite.Users, *entities.User](domainUser)
+//	users, err := scan.All[*entities.User](rows)
+//
+// Mappers are typed, so a row can only reach a mapper of its own type. A
+// row without a registered mapper is reported as ErrNoMapper instead of
+// being passed on as an untyped value.
+package scan
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+var (
+	// ErrNoMapper is returned for a row type without a mapper to the entity type.
+	ErrNoMapper = errors.New("no row mapper registered")
+	// ErrNilRow is returned when a nil row is mapped.
+	ErrNilRow = errors.New("nil row")
+)
+
+// Mapper converts a row into an entity.
+type Mapper[Row, Entity any] func(row *Row) (Entity, error)
+
+// One converts row, rejecting a nil row.
+//
+//nolint:ireturn // Entity is a type parameter.
+func (m Mapper[Row, Entity]) One(row *Row) (Entity, error) {
+	if row == nil {
+		var zero Entity
+
+		return zero, fmt.Errorf("row type=%v: %w", reflect.TypeFor[Row](), ErrNilRow)
+	}
+
+	return m(row)
+}
+
+// All converts rows in order, stopping at the first error.
+func (m Mapper[Row, Entity]) All(rows []*Row) ([]Entity, error) {
+	items := make([]Entity, 0, len(rows))
+
+	for _, row := range rows {
+		item, err := m.One(row)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// Registry holds mappers keyed by row and entity type. It is safe for
+// concurrent use.
+type Registry struct {
+	mu      sync.RWMutex
+	mappers map[registryKey]any
+}
+
+type registryKey struct {
+	row    reflect.Type
+	entity reflect.Type
+}
+
+var defaultRegistry = sync.OnceValue(NewRegistry)
+
+// Default returns the process-wide registry used by Register, One and All.
+func Default() *Registry {
+	return defaultRegistry()
+}
+
+// NewRegistry creates an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{mappers: map[registryKey]any{}}
+}
+
+// Register adds fn to the default registry as the mapper from Row to Entity,
+// replacing any mapper registered for the same types.
+func Register[Row, Entity any](fn Mapper[Row, Entity]) {
+	RegisterIn(Default(), fn)
+}
+
+// RegisterIn adds fn to registry as the mapper from Row to Entity, replacing
+// any mapper registered for the same types.
+func RegisterIn[Row, Entity any](registry *Registry, fn Mapper[Row, Entity]) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	registry.mappers[keyFor[Row, Entity]()] = fn
+}
+
+// Lookup returns the mapper from Row to Entity registered in registry.
+func Lookup[Row, Entity any](registry *Registry) (Mapper[Row, Entity], error) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	mapper, ok := registry.mappers[keyFor[Row, Entity]()].(Mapper[Row, Entity])
+	if !ok {
+		return nil, fmt.Errorf("row type=%v entity type=%v: %w",
+			reflect.TypeFor[Row](), reflect.TypeFor[Entity](), ErrNoMapper)
+	}
+
+	return mapper, nil
+}
+
+// One converts row into Entity with the mapper of the default registry.
+//
+//nolint:ireturn // Entity is a type parameter.
+func One[Entity, Row any](row *Row) (Entity, error) {
+	mapper, err := Lookup[Row, Entity](Default())
+	if err != nil {
+		var zero Entity
+
+		return zero, err
+	}
+
+	return mapper.One(row)
+}
+
+// All converts rows into entities with the mapper of the default registry.
+func All[Entity, Row any](rows []*Row) ([]Entity, error) {
+	mapper, err := Lookup[Row, Entity](Default())
+	if err != nil {
+		return nil, err
+	}
+
+	return mapper.All(rows)
+}
+
+func keyFor[Row, Entity any]() registryKey {
+	return registryKey{row: reflect.TypeFor[Row](), entity: reflect.TypeFor[Entity]()}
+}
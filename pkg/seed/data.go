@@ -0,0 +1,40 @@
+package seed
+
+// The word lists the demo users are drawn from.
+//
+//nolint:gochecknoglobals // Intentional lookup tables for generated data
+var (
+	firstNames = []string{
+		"Ada", "Alan", "Barbara", "Brian", "Claude", "Dennis", "Donald", "Edsger", "Frances", "Grace",
+		"Guido", "Hedy", "Ivan", "James", "Jean", "John", "Ken", "Katherine", "Leslie", "Linus",
+		"Margaret", "Mary", "Niklaus", "Radia", "Rasmus", "Rob", "Shafi", "Sophie", "Tim", "Yukihiro",
+	}
+	lastNames = []string{
+		"Allen", "Backus", "Bartik", "Berners-Lee", "Dijkstra", "Goldwasser", "Hamilton", "Hopper", "Johnson",
+		"Kay", "Kernighan", "Knuth", "Lamport", "Liskov", "Lovelace", "Matsumoto", "McCarthy", "Perlman",
+		"Pike", "Ritchie", "Rossum", "Shannon", "Stroustrup", "Sutherland", "Thompson", "Torvalds", "Turing",
+		"Wilson", "Wirth", "Wozniak",
+	}
+	tags = []string{"beta", "newsletter", "early-adopter", "enterprise", "trial", "partner", "mobile", "api"}
+
+	locales   = []string{"en-US", "en-GB", "de-DE", "fr-FR", "nl-NL", "es-ES", "ja-JP", "pt-BR"}
+	timezones = []string{
+		"America/New_York", "America/Los_Angeles", "Europe/London", "Europe/Berlin", "Europe/Amsterdam",
+		"Europe/Madrid", "Asia/Tokyo", "America/Sao_Paulo",
+	}
+
+	userAgents = []string{
+		"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0 Safari/537.36",
+		"Mozilla/5.0 (Macintosh; Intel Mac OS X 14_4) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4",
+		"Mozilla/5.0 (X11; Linux x86_64; rv:125.0) Gecko/20100101 Firefox/125.0",
+		"Mozilla/5.0 (iPhone; CPU iPhone OS 17_4 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Mobile/15E148",
+		"Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0 Mobile",
+	}
+
+	// networks are the documentation address ranges of RFC 5737, so demo
+	// data never names real clients.
+	networks = []string{"192.0.2", "198.51.100", "203.0.113"}
+
+	// loginMethods are weighted towards passwords.
+	loginMethods = []string{"password", "password", "password", "google", "github"}
+)
@@ -0,0 +1,342 @@
+// Package seed generates realistic demo datasets of users, their sessions
+// and login histories and inserts them through the repositories of any
+// engine, so that demos, local development and load tests start from
+// meaningful data.
+//
+// Datasets are deterministic: the same seed and anchor time always generate
+// the same users, sessions and logins, with the same UUIDs and tokens.
+//
+//	generator := seed.NewGenerator(users, seed.WithSessions(sessions), seed.WithSeed(42))
+//	dataset, err := generator.Generate(ctx, seed.Volume{Users: 1000, SessionsPerUser: 2, LoginsPerUser: 5})
+//
+// Users are inserted in batches through CreateBatch, the fastest insert path
+// of each engine; sessions and login attempts one by one.
+package seed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/LarsArtmann/template-sqlc/internal/domain/entities"
+	"github.com/LarsArtmann/template-sqlc/internal/domain/repositories"
+	"github.com/LarsArtmann/template-sqlc/pkg/fixtures"
+)
+
+// Defaults of a Generator.
+const (
+	DefaultSeed      uint64 = 1
+	DefaultBatchSize        = 500
+)
+
+// Spans of the generated timestamps before the anchor time.
+const (
+	signupSpan     = 365 * 24 * time.Hour
+	sessionSpan    = 7 * 24 * time.Hour
+	loginSpan      = 30 * 24 * time.Hour
+	sessionTTL     = 30 * 24 * time.Hour
+	expiredSession = 45 * 24 * time.Hour
+)
+
+var (
+	// ErrNoLoginHistory is returned when generating logins without a login
+	// history repository.
+	ErrNoLoginHistory = errors.New("generator has no login history repository")
+	// ErrInvalidVolume is returned for a negative volume.
+	ErrInvalidVolume = errors.New("invalid volume")
+)
+
+// Volume is the size of a dataset.
+type Volume struct {
+	Users int
+	// SessionsPerUser is the number of sessions of each active user; about
+	// one in five is expired.
+	SessionsPerUser int
+	// LoginsPerUser is the number of login attempts of each user; about one
+	// in seven failed.
+	LoginsPerUser int
+}
+
+// Validate reports negative counts.
+func (v Volume) Validate() error {
+	if v.Users < 0 || v.SessionsPerUser < 0 || v.LoginsPerUser < 0 {
+		return fmt.Errorf("users=%d sessions=%d logins=%d: %w",
+			v.Users, v.SessionsPerUser, v.LoginsPerUser, ErrInvalidVolume)
+	}
+
+	return nil
+}
+
+// Generator generates and inserts demo datasets.
+type Generator struct {
+	users     repositories.UserRepository
+	sessions  repositories.SessionRepository
+	logins    repositories.LoginHistoryRepository
+	seed      uint64
+	batchSize int
+	password  entities.PasswordHash
+	now       time.Time
+}
+
+// Option configures a Generator.
+type Option func(*Generator)
+
+// WithSessions lets the generator insert sessions.
+func WithSessions(sessions repositories.SessionRepository) Option {
+	return func(g *Generator) {
+		g.sessions = sessions
+	}
+}
+
+// WithLoginHistory lets the generator insert login attempts.
+func WithLoginHistory(logins repositories.LoginHistoryRepository) Option {
+	return func(g *Generator) {
+		g.logins = logins
+	}
+}
+
+// WithSeed sets the seed the dataset derives from; it defaults to DefaultSeed.
+func WithSeed(seed uint64) Option {
+	return func(g *Generator) {
+		g.seed = seed
+	}
+}
+
+// WithBatchSize inserts users in batches of size, DefaultBatchSize by default.
+func WithBatchSize(size int) Option {
+	return func(g *Generator) {
+		if size > 0 {
+			g.batchSize = size
+		}
+	}
+}
+
+// WithPassword sets the password hash of every user, so that demo users can
+// sign in; it defaults to fixtures.DefaultPassword, which matches no password.
+func WithPassword(hash entities.PasswordHash) Option {
+	return func(g *Generator) {
+		g.password = hash
+	}
+}
+
+// WithNow sets the anchor time the timestamps lie before; it defaults to
+// the time the generator was created.
+func WithNow(now time.Time) Option {
+	return func(g *Generator) {
+		g.now = now
+	}
+}
+
+// NewGenerator creates a generator inserting users into users.
+func NewGenerator(users repositories.UserRepository, opts ...Option) *Generator {
+	generator := &Generator{
+		users:     users,
+		seed:      DefaultSeed,
+		batchSize: DefaultBatchSize,
+		password:  fixtures.DefaultPassword,
+		now:       time.Now().Truncate(time.Second),
+	}
+
+	for _, opt := range opts {
+		opt(generator)
+	}
+
+	return generator
+}
+
+// Dataset is a generated and inserted set of users, sessions and logins.
+type Dataset struct {
+	Users    []*entities.User
+	Sessions []*entities.UserSession
+	Logins   []*entities.LoginAttempt
+}
+
+// Generate generates a dataset of volume and inserts it. Usernames carry
+// the position of the user, so generating a second dataset into the same
+// database fails on the duplicates unless its seed differs.
+func (g *Generator) Generate(ctx context.Context, volume Volume) (*Dataset, error) {
+	err := volume.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	if volume.SessionsPerUser > 0 && g.sessions == nil {
+		return nil, fixtures.ErrNoSessionRepository
+	}
+
+	if volume.LoginsPerUser > 0 && g.logins == nil {
+		return nil, ErrNoLoginHistory
+	}
+
+	rng := rand.New(rand.NewPCG(g.seed, g.seed^0x9e3779b97f4a7c15)) //nolint:gosec // demo data, not secrets
+
+	builders := make([]*fixtures.UserBuilder, volume.Users)
+	for i := range builders {
+		builders[i] = g.user(rng, i)
+	}
+
+	seeder := fixtures.NewSeeder(g.users, fixtures.WithSessions(g.sessions), fixtures.WithBatchSize(g.batchSize))
+
+	users, err := seeder.Users(ctx, builders...)
+	if err != nil {
+		return nil, err
+	}
+
+	dataset := &Dataset{Users: users}
+
+	if volume.SessionsPerUser > 0 {
+		dataset.Sessions, err = seeder.Sessions(ctx, g.sessionBuilders(rng, users, volume.SessionsPerUser)...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, user := range users {
+		for range volume.LoginsPerUser {
+			attempt := g.login(rng, user)
+
+			err = g.logins.Record(ctx, attempt)
+			if err != nil {
+				return nil, fmt.Errorf("seed login of user id=%v: %w", user.ID(), err)
+			}
+
+			dataset.Logins = append(dataset.Logins, attempt)
+		}
+	}
+
+	return dataset, nil
+}
+
+// user returns the builder of the i-th user.
+func (g *Generator) user(rng *rand.Rand, i int) *fixtures.UserBuilder {
+	first, last := pick(rng, firstNames), pick(rng, lastNames)
+	name := fmt.Sprintf("%s.%s%d", strings.ToLower(first), strings.ToLower(last), i+1)
+	username := strings.ReplaceAll(name, ".", "_")
+
+	builder := fixtures.User().
+		Named(username).
+		WithEmail(entities.Email(name+"@example.com")).
+		WithFullName(entities.FirstName(first), entities.LastName(last)).
+		WithPassword(g.password).
+		WithStatus(weighted(rng, map[entities.UserStatus]int{
+			entities.UserStatusActive:    85,
+			entities.UserStatusInactive:  7,
+			entities.UserStatusPending:   5,
+			entities.UserStatusSuspended: 3,
+		})).
+		WithRole(weighted(rng, map[entities.UserRole]int{
+			entities.UserRoleUser:      92,
+			entities.UserRoleModerator: 6,
+			entities.UserRoleAdmin:     2,
+		})).
+		WithMetadata("locale", pick(rng, locales)).
+		WithMetadata("timezone", pick(rng, timezones)).
+		WithCreatedAt(g.before(rng, signupSpan))
+
+	for _, tag := range tags {
+		if rng.IntN(4) == 0 {
+			builder.WithTags(tag)
+		}
+	}
+
+	if rng.IntN(5) > 0 {
+		builder.Verified()
+	}
+
+	return builder
+}
+
+// sessionBuilders returns perUser sessions of each active user.
+func (g *Generator) sessionBuilders(
+	rng *rand.Rand,
+	users []*entities.User,
+	perUser int,
+) []*fixtures.SessionBuilder {
+	var builders []*fixtures.SessionBuilder
+
+	for _, user := range users {
+		if !user.IsActive() {
+			continue
+		}
+
+		for i := range perUser {
+			builder := fixtures.Session(user).
+				Named(fmt.Sprintf("demo%d", i+1)).
+				WithIPAddress(net.ParseIP(address(rng))).
+				WithUserAgent(pick(rng, userAgents)).
+				WithLifetime(sessionTTL).
+				WithCreatedAt(g.before(rng, sessionSpan))
+
+			if rng.IntN(5) == 0 {
+				builder.WithCreatedAt(g.now.Add(-expiredSession))
+			}
+
+			builders = append(builders, builder)
+		}
+	}
+
+	return builders
+}
+
+// login returns an attempt of user. Inactive users fail to sign in.
+func (g *Generator) login(rng *rand.Rand, user *entities.User) *entities.LoginAttempt {
+	failure := entities.LoginFailureNone
+
+	switch {
+	case !user.IsActive():
+		failure = entities.LoginFailureInactiveAccount
+	case rng.IntN(7) == 0:
+		failure = entities.LoginFailureInvalidCredentials
+	}
+
+	attempt := entities.NewLoginAttempt(
+		user.ID(), address(rng), pick(rng, userAgents), pick(rng, loginMethods), failure,
+	)
+	attempt.CreatedAt = g.before(rng, loginSpan)
+
+	return attempt
+}
+
+// before returns a time up to span before the anchor time.
+func (g *Generator) before(rng *rand.Rand, span time.Duration) time.Time {
+	return g.now.Add(-time.Duration(rng.Int64N(int64(span)))).Truncate(time.Second)
+}
+
+// address returns a documentation IPv4 address.
+func address(rng *rand.Rand) string {
+	return fmt.Sprintf("%s.%d", pick(rng, networks), 1+rng.IntN(254))
+}
+
+func pick[T any](rng *rand.Rand, values []T) T {
+	return values[rng.IntN(len(values))]
+}
+
+// weighted picks one of the keys of weights with their relative weights. Keys
+// are visited in sorted order, as map order would break determinism.
+func weighted[T ~string](rng *rand.Rand, weights map[T]int) T {
+	keys := make([]T, 0, len(weights))
+	total := 0
+
+	for key, weight := range weights {
+		keys = append(keys, key)
+		total += weight
+	}
+
+	slices.Sort(keys)
+
+	n := rng.IntN(total)
+
+	for _, key := range keys {
+		n -= weights[key]
+		if n < 0 {
+			return key
+		}
+	}
+
+	return keys[len(keys)-1]
+}
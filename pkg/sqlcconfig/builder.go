@@ -0,0 +1,406 @@
+// Package sqlcconfig builds sqlc configurations from layers: a base
+// configuration, one layer per database and an optional project profile.
+//
+// The layers are YAML files read from a file system laid out as
+//
+//	base/common.yaml
+//	databases/<database>.yaml
+//	profiles/<profile>.yaml
+//
+// They are deep-merged in that order, followed by any typed layers added
+// through the Builder, and the result is validated against the sqlc v2
+// schema before it is returned:
+//
+//	cfg, err := sqlcconfig.NewBuilder(os.DirFS("config/internal")).
+//		Databases("sqlite", "postgres").
+//		Profile("microservice").
+//		Build()
+package sqlcconfig
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// sqlDefaultsKey holds the profile settings merged into every sql block.
+const sqlDefaultsKey = "sql_defaults"
+
+var (
+	// ErrNoSQLConfig is returned when a database layer has no sql entries.
+	ErrNoSQLConfig = errors.New("no sql configuration found")
+
+	// ErrConflicts is returned by strict builds when layers conflict.
+	ErrConflicts = errors.New("configuration layers conflict")
+
+	// ErrNoContexts is returned by WriteManifest when the last build had no
+	// bounded contexts.
+	ErrNoContexts = errors.New("no bounded contexts built")
+)
+
+// defaultStrategies are the list merge strategies of sqlc configurations.
+// Lists of named items, such as sql blocks, rules and plugins, merge by name.
+var defaultStrategies = map[string]MergeStrategy{
+	"sql.*.queries":          StrategyUnion,
+	"sql.*.schema":           StrategyUnion,
+	"sql.*.rules":            StrategyUnion,
+	"sql.*.gen.go.overrides": StrategyAppend,
+}
+
+// layer is a typed overlay added through the Builder.
+type layer struct {
+	name  string
+	value Config
+}
+
+// Builder assembles a Config from layers. Its methods return the builder so
+// calls can be chained; errors surface from Build.
+type Builder struct {
+	fsys      fs.FS
+	databases []string
+	profile   string
+	strict    bool
+	layers    []layer
+	contexts  []BoundedContext
+	manifest  *Manifest
+	merger    *Merger
+}
+
+// NewBuilder creates a builder reading its YAML layers from fsys.
+func NewBuilder(fsys fs.FS) *Builder {
+	return &Builder{
+		fsys:   fsys,
+		merger: NewMerger(defaultStrategies),
+	}
+}
+
+// Databases adds the database layers, e.g. "sqlite" for databases/sqlite.yaml.
+func (b *Builder) Databases(names ...string) *Builder {
+	b.databases = append(b.databases, names...)
+
+	return b
+}
+
+// Profile layers profiles/<name>.yaml on top of the databases, e.g. "hobby",
+// "microservice", "enterprise" or "turso". Its sql_defaults are merged into every sql
+// entry.
+func (b *Builder) Profile(name string) *Builder {
+	b.profile = name
+
+	return b
+}
+
+// Strategy overrides the merge strategy of the list at path, e.g.
+// "sql.*.gen.go.overrides".
+func (b *Builder) Strategy(path string, strategy MergeStrategy) *Builder {
+	b.merger.SetStrategy(path, strategy)
+
+	return b
+}
+
+// Strict makes Build fail when layers conflict.
+func (b *Builder) Strict() *Builder {
+	b.strict = true
+
+	return b
+}
+
+// SQL merges sql into the entry with the same name, or adds it.
+func (b *Builder) SQL(sql SQL) *Builder {
+	return b.Layer("sql "+sql.Name, Config{SQL: []SQL{sql}})
+}
+
+// Rule adds a vet rule, or replaces the rule with the same name.
+func (b *Builder) Rule(rule Rule) *Builder {
+	return b.Layer("rule "+rule.Name, Config{Rules: []Rule{rule}})
+}
+
+// Override adds a type override to the Go generator of the named sql entry.
+func (b *Builder) Override(sqlName string, override Override) *Builder {
+	return b.Layer("override "+sqlName, Config{SQL: []SQL{{
+		Name: sqlName,
+		Gen:  Gen{Go: &GenOptions{Overrides: []Override{override}}},
+	}}})
+}
+
+// Contexts splits the generated code into one package per bounded context.
+// See BoundedContext for how the sql entries are expanded.
+func (b *Builder) Contexts(contexts ...BoundedContext) *Builder {
+	b.contexts = append(b.contexts, contexts...)
+
+	return b
+}
+
+// Manifest returns the bounded context packages of the last build, or nil
+// without contexts.
+func (b *Builder) Manifest() *Manifest {
+	return b.manifest
+}
+
+// Layer merges a typed configuration after the YAML layers. name identifies
+// it in conflicts and changes.
+func (b *Builder) Layer(name string, config Config) *Builder {
+	b.layers = append(b.layers, layer{name: name, value: config})
+
+	return b
+}
+
+// Conflicts returns the values the layers of the last build could not merge
+// cleanly.
+func (b *Builder) Conflicts() []Conflict {
+	return b.merger.Conflicts()
+}
+
+// Changes returns the scalars replaced by later layers in the last build.
+func (b *Builder) Changes() []Change {
+	return b.merger.Changes()
+}
+
+// Build merges and validates the layers. Schema violations are returned as
+// ValidationErrors.
+func (b *Builder) Build() (*Config, error) {
+	merged, err := b.Merge()
+	if err != nil {
+		return nil, err
+	}
+
+	if b.strict && len(b.Conflicts()) > 0 {
+		return nil, fmt.Errorf("conflicts=%v: %w", len(b.Conflicts()), ErrConflicts)
+	}
+
+	if errs := Validate(merged); len(errs) > 0 {
+		return nil, errs
+	}
+
+	config := &Config{}
+
+	err = convert(merged, config)
+	if err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// Merge merges the layers without validating them.
+func (b *Builder) Merge() (map[string]any, error) {
+	b.merger.reset()
+
+	config, err := b.loadLayer(path.Join("base", "common.yaml"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load base config: %w", err)
+	}
+
+	for _, db := range b.databases {
+		config, err = b.mergeDatabase(config, db)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build %s config: %w", db, err)
+		}
+	}
+
+	if b.profile != "" {
+		config, err = b.mergeProfile(config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to apply profile %s: %w", b.profile, err)
+		}
+	}
+
+	for _, typed := range b.layers {
+		overlay := map[string]any{}
+
+		err = convert(typed.value, &overlay)
+		if err != nil {
+			return nil, fmt.Errorf("layer=%v: %w", typed.name, err)
+		}
+
+		// Unset fields of typed layers must not override earlier layers.
+		pruned, _ := prune(overlay).(map[string]any)
+		config = b.merger.Merge(config, pruned, typed.name)
+	}
+
+	b.manifest = nil
+
+	if len(b.contexts) > 0 {
+		b.manifest, err = expandContexts(config, b.contexts)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	config["version"] = "2"
+
+	return config, nil
+}
+
+// WriteFile builds the configuration and writes it to name. With bounded
+// contexts, write the manifest with WriteManifest afterwards.
+func (b *Builder) WriteFile(name string) error {
+	config, err := b.Build()
+	if err != nil {
+		return err
+	}
+
+	data, err := config.Marshal()
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(name, data, 0o644)
+	if err != nil {
+		return fmt.Errorf("file=%v: %w", name, err)
+	}
+
+	return nil
+}
+
+// WriteManifest writes the bounded context manifest of the last build to name.
+func (b *Builder) WriteManifest(name string) error {
+	if b.manifest == nil {
+		return fmt.Errorf("file=%v: %w", name, ErrNoContexts)
+	}
+
+	data, err := b.manifest.Marshal()
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(name, data, 0o644)
+	if err != nil {
+		return fmt.Errorf("file=%v: %w", name, err)
+	}
+
+	return nil
+}
+
+// loadLayer loads a YAML layer from the builder's file system.
+func (b *Builder) loadLayer(name string) (map[string]any, error) {
+	data, err := fs.ReadFile(b.fsys, name)
+	if err != nil {
+		return nil, err
+	}
+
+	config := map[string]any{}
+
+	err = yaml.Unmarshal(data, &config)
+	if err != nil {
+		return nil, fmt.Errorf("layer=%v: %w", name, err)
+	}
+
+	return config, nil
+}
+
+// mergeDatabase merges every sql block of a database layer into config.
+func (b *Builder) mergeDatabase(config map[string]any, db string) (map[string]any, error) {
+	name := path.Join("databases", db+".yaml")
+
+	overlay, err := b.loadLayer(name)
+	if err != nil {
+		return nil, err
+	}
+
+	// Layers are merged explicitly, so the extends hint is not part of the output.
+	delete(overlay, "extends")
+
+	sqlConfigs, ok := overlay["sql"].([]any)
+	if !ok || len(sqlConfigs) == 0 {
+		return nil, fmt.Errorf("db=%v: %w", db, ErrNoSQLConfig)
+	}
+
+	return b.merger.Merge(config, overlay, name), nil
+}
+
+// mergeProfile merges the profile into config and its sql defaults into
+// every sql block.
+func (b *Builder) mergeProfile(config map[string]any) (map[string]any, error) {
+	name := path.Join("profiles", b.profile+".yaml")
+
+	overlay, err := b.loadLayer(name)
+	if err != nil {
+		return nil, err
+	}
+
+	defaults, _ := overlay[sqlDefaultsKey].(map[string]any)
+	delete(overlay, sqlDefaultsKey)
+
+	// Expand the defaults into one named overlay per sql block, so they merge
+	// with the sql.* strategies and report full paths.
+	sqlConfigs, _ := config["sql"].([]any)
+	blocks := make([]any, 0, len(sqlConfigs))
+
+	for _, sqlConfig := range sqlConfigs {
+		blockName, ok := itemName(sqlConfig)
+		if !ok || defaults == nil {
+			continue
+		}
+
+		block, _ := clone(defaults).(map[string]any)
+		block["name"] = blockName
+		blocks = append(blocks, block)
+	}
+
+	if len(blocks) > 0 {
+		overlay["sql"] = blocks
+	}
+
+	return b.merger.Merge(config, overlay, name), nil
+}
+
+// prune drops the empty strings, nulls and empty collections left by the
+// zero values of a typed layer.
+func prune(value any) any {
+	switch typed := value.(type) {
+	case map[string]any:
+		pruned := make(map[string]any, len(typed))
+		for key, item := range typed {
+			if item = prune(item); item != nil {
+				pruned[key] = item
+			}
+		}
+
+		if len(pruned) == 0 {
+			return nil
+		}
+
+		return pruned
+	case []any:
+		pruned := make([]any, 0, len(typed))
+		for _, item := range typed {
+			if item = prune(item); item != nil {
+				pruned = append(pruned, item)
+			}
+		}
+
+		if len(pruned) == 0 {
+			return nil
+		}
+
+		return pruned
+	case string:
+		if typed == "" {
+			return nil
+		}
+
+		return typed
+	default:
+		return value
+	}
+}
+
+// convert round-trips from through YAML into to.
+func convert(from, to any) error {
+	data, err := yaml.Marshal(from)
+	if err != nil {
+		return fmt.Errorf("marshal: %w", err)
+	}
+
+	err = yaml.Unmarshal(data, to)
+	if err != nil {
+		return fmt.Errorf("unmarshal: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,293 @@
+package sqlcconfig
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// enginePlaceholder in a bounded context path is replaced by the name of the
+// sql entry the context is generated for, e.g. "sql/{engine}/billing".
+const enginePlaceholder = "{engine}"
+
+var (
+	// ErrUnknownContext is returned when a context depends on an undeclared one.
+	ErrUnknownContext = errors.New("unknown bounded context")
+
+	// ErrContextCycle is returned when context dependencies form a cycle.
+	ErrContextCycle = errors.New("bounded context dependency cycle")
+
+	// ErrInvalidContext is returned for contexts without a usable name or queries.
+	ErrInvalidContext = errors.New("invalid bounded context")
+)
+
+// contextNamePattern keeps context names usable as Go package names.
+var contextNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// BoundedContext is a part of the schema generated into its own package, so
+// its repositories can be wired independently of the other contexts.
+//
+// When a Builder has contexts, every sql entry from the database layers is
+// used as a template: it is replaced by one entry per context, named
+// "<entry>-<context>", that keeps the entry's engine and generator options.
+type BoundedContext struct {
+	Name string `yaml:"name" json:"name"`
+	// Schema and Queries may contain "{engine}".
+	Schema  Paths `yaml:"schema" json:"schema"`
+	Queries Paths `yaml:"queries" json:"queries"`
+	// Package defaults to Name.
+	Package string `yaml:"package,omitempty" json:"package,omitempty"`
+	// Out defaults to the template's output directory joined with Name.
+	Out string `yaml:"out,omitempty" json:"out,omitempty"`
+	// DependsOn names the contexts whose schema this context references.
+	// Their schema is added to this context's, so sqlc can resolve the
+	// references; enable omit_unused_structs to keep their models out.
+	DependsOn []string `yaml:"depends_on,omitempty" json:"depends_on,omitempty"`
+	// Engines restricts the context to these sql entries; empty means all.
+	Engines []string `yaml:"engines,omitempty" json:"engines,omitempty"`
+}
+
+// ParseContexts reads bounded contexts from YAML with a top-level
+// "contexts" list.
+func ParseContexts(data []byte) ([]BoundedContext, error) {
+	var file struct {
+		Contexts []BoundedContext `yaml:"contexts"`
+	}
+
+	err := yaml.Unmarshal(data, &file)
+	if err != nil {
+		return nil, fmt.Errorf("parse contexts: %w", err)
+	}
+
+	return file.Contexts, nil
+}
+
+// Manifest lists the packages generated for bounded contexts and their
+// dependencies, in dependency order.
+type Manifest struct {
+	Contexts []ManifestEntry `json:"contexts"`
+}
+
+// ManifestEntry is one generated package.
+type ManifestEntry struct {
+	Context string `json:"context"`
+	// SQL is the name of the generated sql entry, "<entry>-<context>".
+	SQL       string   `json:"sql"`
+	Engine    string   `json:"engine"`
+	Package   string   `json:"package"`
+	Out       string   `json:"out"`
+	DependsOn []string `json:"depends_on"`
+}
+
+// Marshal encodes the manifest as indented JSON.
+func (m *Manifest) Marshal() ([]byte, error) {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("marshal manifest: %w", err)
+	}
+
+	return append(data, '\n'), nil
+}
+
+// sortContexts orders contexts so every context follows its dependencies.
+func sortContexts(contexts []BoundedContext) ([]BoundedContext, error) {
+	byName := make(map[string]BoundedContext, len(contexts))
+
+	for _, bc := range contexts {
+		if !contextNamePattern.MatchString(bc.Name) {
+			return nil, fmt.Errorf("context=%q: name must match %s: %w", bc.Name, contextNamePattern, ErrInvalidContext)
+		}
+
+		if len(bc.Queries) == 0 {
+			return nil, fmt.Errorf("context=%v: no queries: %w", bc.Name, ErrInvalidContext)
+		}
+
+		if _, ok := byName[bc.Name]; ok {
+			return nil, fmt.Errorf("context=%v: declared twice: %w", bc.Name, ErrInvalidContext)
+		}
+
+		byName[bc.Name] = bc
+	}
+
+	sorted := make([]BoundedContext, 0, len(contexts))
+	state := make(map[string]int, len(contexts)) // 1 visiting, 2 done
+
+	var visit func(name string, chain []string) error
+
+	visit = func(name string, chain []string) error {
+		switch state[name] {
+		case 1:
+			return fmt.Errorf("chain=%v: %w", strings.Join(append(chain, name), " -> "), ErrContextCycle)
+		case 2:
+			return nil
+		}
+
+		state[name] = 1
+
+		for _, dependency := range byName[name].DependsOn {
+			if _, ok := byName[dependency]; !ok {
+				return fmt.Errorf("context=%v depends_on=%v: %w", name, dependency, ErrUnknownContext)
+			}
+
+			err := visit(dependency, append(chain, name))
+			if err != nil {
+				return err
+			}
+		}
+
+		state[name] = 2
+		sorted = append(sorted, byName[name])
+
+		return nil
+	}
+
+	for _, bc := range contexts {
+		err := visit(bc.Name, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return sorted, nil
+}
+
+// expandContexts replaces every sql entry of config by one entry per context.
+func expandContexts(config map[string]any, contexts []BoundedContext) (*Manifest, error) {
+	sorted, err := sortContexts(contexts)
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]BoundedContext, len(sorted))
+	for _, bc := range sorted {
+		byName[bc.Name] = bc
+	}
+
+	templates, _ := config["sql"].([]any)
+	expanded := make([]any, 0, len(templates)*len(sorted))
+	manifest := &Manifest{Contexts: []ManifestEntry{}}
+
+	for _, template := range templates {
+		templateName, ok := itemName(template)
+		if !ok {
+			return nil, fmt.Errorf("sql entries need a name to expand contexts: %w", ErrInvalidContext)
+		}
+
+		for _, bc := range sorted {
+			if len(bc.Engines) > 0 && !slices.Contains(bc.Engines, templateName) {
+				continue
+			}
+
+			entry, _ := clone(template).(map[string]any)
+			entry, manifestEntry := contextEntry(entry, templateName, bc, byName)
+
+			expanded = append(expanded, entry)
+			manifest.Contexts = append(manifest.Contexts, manifestEntry)
+		}
+	}
+
+	config["sql"] = expanded
+
+	return manifest, nil
+}
+
+// contextEntry turns a clone of the template sql entry into the entry of bc.
+func contextEntry(
+	entry map[string]any,
+	templateName string,
+	bc BoundedContext,
+	contexts map[string]BoundedContext,
+) (map[string]any, ManifestEntry) {
+	expand := func(paths Paths) []any {
+		expanded := make([]any, len(paths))
+		for i, p := range paths {
+			expanded[i] = strings.ReplaceAll(p, enginePlaceholder, templateName)
+		}
+
+		return expanded
+	}
+
+	var schema []any
+
+	for _, dependency := range transitiveDependencies(bc, contexts) {
+		for _, p := range expand(contexts[dependency].Schema) {
+			if !slices.Contains(schema, p) {
+				schema = append(schema, p)
+			}
+		}
+	}
+
+	for _, p := range expand(bc.Schema) {
+		if !slices.Contains(schema, p) {
+			schema = append(schema, p)
+		}
+	}
+
+	name := templateName + "-" + bc.Name
+	entry["name"] = name
+	entry["schema"] = schema
+	entry["queries"] = expand(bc.Queries)
+
+	goGen, _ := lookup(entry, "gen", "go").(map[string]any)
+	if goGen == nil {
+		goGen = map[string]any{}
+		entry["gen"] = map[string]any{"go": goGen}
+	}
+
+	pkg := bc.Package
+	if pkg == "" {
+		pkg = bc.Name
+	}
+
+	out := strings.ReplaceAll(bc.Out, enginePlaceholder, templateName)
+	if out == "" {
+		templateOut, _ := goGen["out"].(string)
+		out = path.Join(templateOut, bc.Name)
+	}
+
+	goGen["package"] = pkg
+	goGen["out"] = out
+
+	engine, _ := entry["engine"].(string)
+
+	return entry, ManifestEntry{
+		Context:   bc.Name,
+		SQL:       name,
+		Engine:    engine,
+		Package:   pkg,
+		Out:       out,
+		DependsOn: append([]string{}, bc.DependsOn...),
+	}
+}
+
+// transitiveDependencies returns every context bc depends on, dependencies
+// first. The contexts have been checked for cycles.
+func transitiveDependencies(bc BoundedContext, contexts map[string]BoundedContext) []string {
+	var order []string
+
+	seen := map[string]bool{}
+
+	var visit func(name string)
+
+	visit = func(name string) {
+		for _, dependency := range contexts[name].DependsOn {
+			if seen[dependency] {
+				continue
+			}
+
+			seen[dependency] = true
+			visit(dependency)
+			order = append(order, dependency)
+		}
+	}
+
+	visit(bc.Name)
+
+	return order
+}
@@ -0,0 +1,298 @@
+package sqlcconfig
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// MergeStrategy decides how an overlay list is combined with a base list.
+type MergeStrategy string
+
+const (
+	// StrategyReplace uses the overlay list instead of the base list.
+	StrategyReplace MergeStrategy = "replace"
+	// StrategyAppend appends the overlay items to the base items.
+	StrategyAppend MergeStrategy = "append"
+	// StrategyUnion appends the overlay items missing from the base list.
+	StrategyUnion MergeStrategy = "union"
+	// StrategyByName deep-merges items with the same "name" key and appends
+	// the others.
+	StrategyByName MergeStrategy = "by-name"
+)
+
+// wildcard matches any list item or map key in a strategy path.
+const wildcard = "*"
+
+// Conflict is a value an overlay could not merge cleanly.
+type Conflict struct {
+	Path    string
+	Layer   string
+	Message string
+}
+
+func (c Conflict) String() string {
+	return fmt.Sprintf("%s: %s (%s)", c.Path, c.Message, c.Layer)
+}
+
+// Change records an overlay replacing a scalar set by an earlier layer.
+type Change struct {
+	Path  string
+	Layer string
+	From  any
+	To    any
+}
+
+func (c Change) String() string {
+	return fmt.Sprintf("%s: %v -> %v (%s)", c.Path, c.From, c.To, c.Layer)
+}
+
+// Merger deep-merges configuration layers and records what each layer changed.
+//
+// Maps merge key by key. Lists merge with the strategy registered for their
+// dotted path, where "*" matches any list item or key, e.g. "sql.*.queries".
+// Lists without a strategy merge by name when every item is a map with a
+// "name" key, and are replaced otherwise.
+type Merger struct {
+	strategies map[string]MergeStrategy
+	conflicts  []Conflict
+	changes    []Change
+}
+
+// NewMerger creates a Merger with the given list strategies.
+func NewMerger(strategies map[string]MergeStrategy) *Merger {
+	merger := &Merger{strategies: make(map[string]MergeStrategy, len(strategies))}
+
+	for path, strategy := range strategies {
+		merger.strategies[path] = strategy
+	}
+
+	return merger
+}
+
+// SetStrategy registers the strategy for the list at path.
+func (m *Merger) SetStrategy(path string, strategy MergeStrategy) {
+	m.strategies[path] = strategy
+}
+
+// Merge returns base with overlay merged on top. Neither input is modified.
+// layer names the overlay in conflicts and changes.
+func (m *Merger) Merge(base, overlay map[string]any, layer string) map[string]any {
+	merged, _ := m.merge(nil, base, overlay, layer).(map[string]any)
+
+	return merged
+}
+
+// Conflicts returns the values that could not be merged cleanly.
+func (m *Merger) Conflicts() []Conflict {
+	return m.conflicts
+}
+
+// Changes returns the scalars replaced by later layers.
+func (m *Merger) Changes() []Change {
+	return m.changes
+}
+
+// reset forgets the conflicts and changes of earlier merges.
+func (m *Merger) reset() {
+	m.conflicts = nil
+	m.changes = nil
+}
+
+func (m *Merger) merge(path []string, base, overlay any, layer string) any {
+	if base == nil {
+		return clone(overlay)
+	}
+
+	if overlay == nil {
+		return clone(base)
+	}
+
+	switch overlayValue := overlay.(type) {
+	case map[string]any:
+		baseMap, ok := base.(map[string]any)
+		if !ok {
+			return m.conflict(path, base, overlay, layer)
+		}
+
+		merged := clone(baseMap).(map[string]any)
+		for key, value := range overlayValue {
+			merged[key] = m.merge(append(path, key), baseMap[key], value, layer)
+		}
+
+		return merged
+	case []any:
+		baseList, ok := base.([]any)
+		if !ok {
+			return m.conflict(path, base, overlay, layer)
+		}
+
+		return m.mergeList(path, baseList, overlayValue, layer)
+	default:
+		if _, ok := base.(map[string]any); ok {
+			return m.conflict(path, base, overlay, layer)
+		}
+
+		if _, ok := base.([]any); ok {
+			return m.conflict(path, base, overlay, layer)
+		}
+
+		if !reflect.DeepEqual(base, overlay) {
+			m.changes = append(m.changes, Change{Path: joinPath(path), Layer: layer, From: base, To: overlay})
+		}
+
+		return overlay
+	}
+}
+
+func (m *Merger) mergeList(path []string, base, overlay []any, layer string) []any {
+	switch m.strategy(path, base, overlay) {
+	case StrategyAppend:
+		return clone(append(slices.Clip(base), overlay...)).([]any)
+	case StrategyUnion:
+		merged := clone(base).([]any)
+		for _, item := range overlay {
+			if !slices.ContainsFunc(merged, func(existing any) bool { return reflect.DeepEqual(existing, item) }) {
+				merged = append(merged, clone(item))
+			}
+		}
+
+		return merged
+	case StrategyByName:
+		return m.mergeByName(path, base, overlay, layer)
+	default:
+		return clone(overlay).([]any)
+	}
+}
+
+func (m *Merger) mergeByName(path []string, base, overlay []any, layer string) []any {
+	merged := clone(base).([]any)
+	seen := make(map[string]bool, len(overlay))
+
+	for _, item := range overlay {
+		name, ok := itemName(item)
+		if !ok {
+			m.conflicts = append(m.conflicts, Conflict{
+				Path: joinPath(path), Layer: layer, Message: "list item without a name cannot be merged by name",
+			})
+			merged = append(merged, clone(item))
+
+			continue
+		}
+
+		if seen[name] {
+			m.conflicts = append(m.conflicts, Conflict{
+				Path: joinPath(path), Layer: layer, Message: fmt.Sprintf("duplicate item %q", name),
+			})
+		}
+
+		seen[name] = true
+
+		index := slices.IndexFunc(merged, func(existing any) bool {
+			existingName, ok := itemName(existing)
+
+			return ok && existingName == name
+		})
+		if index < 0 {
+			merged = append(merged, clone(item))
+
+			continue
+		}
+
+		merged[index] = m.merge(append(path, name), merged[index], item, layer)
+	}
+
+	return merged
+}
+
+// strategy returns the strategy registered for path, or the default for the lists.
+func (m *Merger) strategy(path []string, base, overlay []any) MergeStrategy {
+	for pattern, strategy := range m.strategies {
+		if matchPath(strings.Split(pattern, "."), path) {
+			return strategy
+		}
+	}
+
+	named := func(item any) bool {
+		_, ok := itemName(item)
+
+		return ok
+	}
+
+	if len(base)+len(overlay) > 0 && !slices.ContainsFunc(base, not(named)) && !slices.ContainsFunc(overlay, not(named)) {
+		return StrategyByName
+	}
+
+	return StrategyReplace
+}
+
+func (m *Merger) conflict(path []string, base, overlay any, layer string) any {
+	m.conflicts = append(m.conflicts, Conflict{
+		Path:    joinPath(path),
+		Layer:   layer,
+		Message: fmt.Sprintf("cannot merge %T into %T; using the overlay", overlay, base),
+	})
+
+	return clone(overlay)
+}
+
+// itemName returns the "name" key of a list item.
+func itemName(item any) (string, bool) {
+	values, ok := item.(map[string]any)
+	if !ok {
+		return "", false
+	}
+
+	name, ok := values["name"].(string)
+
+	return name, ok
+}
+
+func matchPath(pattern, path []string) bool {
+	if len(pattern) != len(path) {
+		return false
+	}
+
+	for i := range pattern {
+		if pattern[i] != wildcard && pattern[i] != path[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func joinPath(path []string) string {
+	if len(path) == 0 {
+		return "."
+	}
+
+	return strings.Join(path, ".")
+}
+
+func not[T any](predicate func(T) bool) func(T) bool {
+	return func(value T) bool { return !predicate(value) }
+}
+
+// clone deep-copies decoded YAML values so merges never alias their inputs.
+func clone(value any) any {
+	switch typed := value.(type) {
+	case map[string]any:
+		copied := make(map[string]any, len(typed))
+		for key, item := range typed {
+			copied[key] = clone(item)
+		}
+
+		return copied
+	case []any:
+		copied := make([]any, len(typed))
+		for i, item := range typed {
+			copied[i] = clone(item)
+		}
+
+		return copied
+	default:
+		return value
+	}
+}
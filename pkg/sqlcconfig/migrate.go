@@ -0,0 +1,248 @@
+package sqlcconfig
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// ErrUnsupportedVersion is returned by Migrate for configurations that are
+// not sqlc version 1.
+var ErrUnsupportedVersion = errors.New("only version 1 configurations can be migrated")
+
+// v1SQLKeys are the package keys that move to the sql entry in version 2.
+// Every other known key moves to gen.go.
+var v1SQLKeys = map[string]bool{
+	"engine":                 true,
+	"schema":                 true,
+	"queries":                true,
+	"strict_function_checks": true,
+	"strict_order_by":        true,
+	"database":               true,
+	"analyzer":               true,
+	"rules":                  true,
+}
+
+// v1Renamed are package keys whose version 2 name differs.
+var v1Renamed = map[string]string{
+	"name": "package",
+	"path": "out",
+}
+
+// v1TopLevel are the keys copied unchanged to the version 2 configuration.
+var v1TopLevel = map[string]bool{
+	"project": true,
+	"cloud":   true,
+	"plugins": true,
+	"rules":   true,
+	"options": true,
+}
+
+// MigrationNote describes how one version 1 key was migrated, or why it
+// was not.
+type MigrationNote struct {
+	From    string
+	To      string
+	Message string
+}
+
+// MigrationReport lists what Migrate moved and what needs a manual fix.
+type MigrationReport struct {
+	Mapped   []MigrationNote
+	Unmapped []MigrationNote
+}
+
+// String formats the report as a diff: "-" lines are version 1 keys, "+" lines
+// their version 2 location and "!" lines keys that need a manual fix.
+func (r *MigrationReport) String() string {
+	var sb strings.Builder
+
+	for _, note := range r.Mapped {
+		if note.From == note.To {
+			continue
+		}
+
+		fmt.Fprintf(&sb, "- %s\n+ %s\n", note.From, note.To)
+	}
+
+	for _, note := range r.Unmapped {
+		fmt.Fprintf(&sb, "! %s: %s\n", note.From, note.Message)
+	}
+
+	return sb.String()
+}
+
+func (r *MigrationReport) mapped(from, to string) {
+	r.Mapped = append(r.Mapped, MigrationNote{From: from, To: to})
+}
+
+func (r *MigrationReport) unmapped(from, format string, args ...any) {
+	r.Unmapped = append(r.Unmapped, MigrationNote{From: from, Message: fmt.Sprintf(format, args...)})
+}
+
+// Migrate upgrades a version 1 sqlc configuration to version 2. Each package
+// becomes an sql entry with a gen.go block, and the global overrides and
+// renames move under overrides.go. Keys that cannot be mapped, and schema
+// violations of the result, are listed in the report's Unmapped notes rather
+// than failing the migration.
+func Migrate(v1 []byte) ([]byte, *MigrationReport, error) {
+	legacy := map[string]any{}
+
+	err := yaml.Unmarshal(v1, &legacy)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parse config: %w", err)
+	}
+
+	if version, _ := legacy["version"].(string); version != "1" {
+		return nil, nil, fmt.Errorf("version=%q: %w", version, ErrUnsupportedVersion)
+	}
+
+	report := &MigrationReport{}
+	migrated := map[string]any{"version": "2"}
+	goOverrides := map[string]any{}
+
+	for _, key := range sortedKeys(legacy) {
+		value := legacy[key]
+
+		switch {
+		case key == "version":
+		case key == "packages":
+			migrated["sql"] = migratePackages(value, report)
+		case key == "overrides":
+			goOverrides["overrides"] = migrateOverrides(value, key, "overrides.go.overrides", report)
+		case key == "rename":
+			goOverrides["rename"] = value
+			report.mapped(key, "overrides.go.rename")
+		case v1TopLevel[key]:
+			migrated[key] = value
+			report.mapped(key, key)
+		default:
+			report.unmapped(key, "unknown version 1 key; dropped")
+		}
+	}
+
+	if len(goOverrides) > 0 {
+		migrated["overrides"] = map[string]any{"go": goOverrides}
+	}
+
+	for _, violation := range Validate(migrated) {
+		report.unmapped(violation.Path, "%s", violation.Message)
+	}
+
+	config := &Config{}
+
+	err = convert(migrated, config)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data, err := config.Marshal()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return data, report, nil
+}
+
+// migratePackages turns version 1 packages into version 2 sql entries.
+func migratePackages(value any, report *MigrationReport) []any {
+	packages, ok := value.([]any)
+	if !ok {
+		report.unmapped("packages", "must be a list, got %T; dropped", value)
+
+		return nil
+	}
+
+	entries := make([]any, 0, len(packages))
+
+	for i, pkg := range packages {
+		from := "packages[" + strconv.Itoa(i) + "]"
+
+		fields, ok := pkg.(map[string]any)
+		if !ok {
+			report.unmapped(from, "must be a mapping, got %T; dropped", pkg)
+
+			continue
+		}
+
+		to := "sql[" + strconv.Itoa(len(entries)) + "]"
+		entries = append(entries, migratePackage(fields, from, to, report))
+	}
+
+	return entries
+}
+
+// migratePackage moves the keys of one package to its sql entry and gen.go.
+func migratePackage(pkg map[string]any, from, to string, report *MigrationReport) map[string]any {
+	entry := map[string]any{}
+	gen := map[string]any{}
+
+	if name, ok := pkg["name"].(string); ok {
+		entry["name"] = name
+	}
+
+	for _, key := range sortedKeys(pkg) {
+		value := pkg[key]
+		source := from + "." + key
+
+		switch {
+		case v1SQLKeys[key]:
+			entry[key] = value
+			report.mapped(source, to+"."+key)
+		case key == "overrides":
+			gen[key] = migrateOverrides(value, source, to+".gen.go.overrides", report)
+		case v1Renamed[key] != "":
+			gen[v1Renamed[key]] = value
+			report.mapped(source, to+".gen.go."+v1Renamed[key])
+		case goGenSchema.fields[key] != nil:
+			gen[key] = value
+			report.mapped(source, to+".gen.go."+key)
+		default:
+			report.unmapped(source, "unknown package option; dropped")
+		}
+	}
+
+	entry["gen"] = map[string]any{"go": gen}
+
+	return entry
+}
+
+// migrateOverrides rewrites the deprecated postgres_type key of overrides.
+func migrateOverrides(value any, from, to string, report *MigrationReport) any {
+	overrides, ok := value.([]any)
+	if !ok {
+		report.unmapped(from, "must be a list, got %T; dropped", value)
+
+		return nil
+	}
+
+	for i, override := range overrides {
+		fields, ok := override.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		source := from + "[" + strconv.Itoa(i) + "]"
+		target := to + "[" + strconv.Itoa(i) + "]"
+
+		if dbType, ok := fields["postgres_type"]; ok {
+			delete(fields, "postgres_type")
+			fields["db_type"] = dbType
+			report.mapped(source+".postgres_type", target+".db_type")
+		}
+
+		for _, key := range sortedKeys(fields) {
+			if overrideSchema.fields[key] == nil {
+				delete(fields, key)
+				report.unmapped(source+"."+key, "unknown override option; dropped")
+			}
+		}
+	}
+
+	report.mapped(from, to)
+
+	return overrides
+}
@@ -0,0 +1,221 @@
+package sqlcconfig
+
+import (
+	"bytes"
+	"fmt"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// Engine is a database engine supported by sqlc.
+type Engine string
+
+// Supported engines.
+const (
+	EnginePostgreSQL Engine = "postgresql"
+	EngineMySQL      Engine = "mysql"
+	EngineSQLite     Engine = "sqlite"
+)
+
+// Config is an sqlc version 2 configuration file.
+type Config struct {
+	Version   string         `yaml:"version"`
+	Project   *Project       `yaml:"project,omitempty"`
+	Cloud     *Cloud         `yaml:"cloud,omitempty"`
+	SQL       []SQL          `yaml:"sql"`
+	Overrides *Overrides     `yaml:"overrides,omitempty"`
+	Plugins   []Plugin       `yaml:"plugins,omitempty"`
+	Rules     []Rule         `yaml:"rules,omitempty"`
+	Options   map[string]any `yaml:"options,omitempty"`
+}
+
+// Marshal encodes the configuration as sqlc.yaml, indented like the layers.
+func (c *Config) Marshal() ([]byte, error) {
+	var buf bytes.Buffer
+
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+
+	err := encoder.Encode(c)
+	if err != nil {
+		return nil, fmt.Errorf("marshal config: %w", err)
+	}
+
+	err = encoder.Close()
+	if err != nil {
+		return nil, fmt.Errorf("marshal config: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Project identifies the sqlc Cloud project.
+type Project struct {
+	ID string `yaml:"id"`
+}
+
+// Cloud configures sqlc Cloud.
+type Cloud struct {
+	Organization string `yaml:"organization,omitempty"`
+	Project      string `yaml:"project,omitempty"`
+	Hostname     string `yaml:"hostname,omitempty"`
+}
+
+// SQL configures code generation for one engine. Entries are merged across
+// layers by Name.
+type SQL struct {
+	Name                 string    `yaml:"name,omitempty"`
+	Engine               Engine    `yaml:"engine"`
+	Schema               Paths     `yaml:"schema"`
+	Queries              Paths     `yaml:"queries"`
+	StrictFunctionChecks bool      `yaml:"strict_function_checks,omitempty"`
+	StrictOrderBy        bool      `yaml:"strict_order_by,omitempty"`
+	Rules                []string  `yaml:"rules,omitempty"`
+	Database             *Database `yaml:"database,omitempty"`
+	Analyzer             *Analyzer `yaml:"analyzer,omitempty"`
+	Gen                  Gen       `yaml:"gen,omitempty"`
+	Codegen              []Codegen `yaml:"codegen,omitempty"`
+}
+
+// Paths is a list of files or directories. sqlc also accepts a single path.
+type Paths []string
+
+// UnmarshalYAML accepts a single path as well as a list.
+func (p *Paths) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*p = Paths{node.Value}
+
+		return nil
+	}
+
+	var paths []string
+
+	err := node.Decode(&paths)
+	if err != nil {
+		return fmt.Errorf("line=%v: %w", node.Line, err)
+	}
+
+	*p = paths
+
+	return nil
+}
+
+// Database connects sqlc to a live database for analysis and vet.
+type Database struct {
+	URI     string `yaml:"uri,omitempty"`
+	Managed bool   `yaml:"managed,omitempty"`
+}
+
+// Analyzer configures query analysis.
+type Analyzer struct {
+	Database bool `yaml:"database"`
+}
+
+// Gen holds the built-in code generators.
+type Gen struct {
+	Go   *GenOptions `yaml:"go,omitempty"`
+	JSON *GenJSON    `yaml:"json,omitempty"`
+}
+
+// GenOptions configures the Go code generator. Zero values leave the sqlc
+// default in place, so a typed layer can enable options but not disable them;
+// disable options in a YAML layer instead.
+type GenOptions struct {
+	Package                     string            `yaml:"package"`
+	Out                         string            `yaml:"out"`
+	SQLPackage                  string            `yaml:"sql_package,omitempty"`
+	SQLDriver                   string            `yaml:"sql_driver,omitempty"`
+	BuildTags                   string            `yaml:"build_tags,omitempty"`
+	EmitInterface               bool              `yaml:"emit_interface,omitempty"`
+	EmitJSONTags                bool              `yaml:"emit_json_tags,omitempty"`
+	JSONTagsIDUppercase         bool              `yaml:"json_tags_id_uppercase,omitempty"`
+	EmitDBTags                  bool              `yaml:"emit_db_tags,omitempty"`
+	EmitPreparedQueries         bool              `yaml:"emit_prepared_queries,omitempty"`
+	EmitExactTableNames         bool              `yaml:"emit_exact_table_names,omitempty"`
+	EmitEmptySlices             bool              `yaml:"emit_empty_slices,omitempty"`
+	EmitExportedQueries         bool              `yaml:"emit_exported_queries,omitempty"`
+	EmitResultStructPointers    bool              `yaml:"emit_result_struct_pointers,omitempty"`
+	EmitParamsStructPointers    bool              `yaml:"emit_params_struct_pointers,omitempty"`
+	EmitMethodsWithDBArgument   bool              `yaml:"emit_methods_with_db_argument,omitempty"`
+	EmitPointersForNullTypes    bool              `yaml:"emit_pointers_for_null_types,omitempty"`
+	EmitEnumValidMethod         bool              `yaml:"emit_enum_valid_method,omitempty"`
+	EmitAllEnumValues           bool              `yaml:"emit_all_enum_values,omitempty"`
+	EmitSQLAsComment            bool              `yaml:"emit_sql_as_comment,omitempty"`
+	JSONTagsCaseStyle           string            `yaml:"json_tags_case_style,omitempty"`
+	OmitUnusedStructs           bool              `yaml:"omit_unused_structs,omitempty"`
+	OmitSQLCVersion             bool              `yaml:"omit_sqlc_version,omitempty"`
+	OutputBatchFileName         string            `yaml:"output_batch_file_name,omitempty"`
+	OutputDBFileName            string            `yaml:"output_db_file_name,omitempty"`
+	OutputModelsFileName        string            `yaml:"output_models_file_name,omitempty"`
+	OutputQuerierFileName       string            `yaml:"output_querier_file_name,omitempty"`
+	OutputCopyfromFileName      string            `yaml:"output_copyfrom_file_name,omitempty"`
+	OutputFilesSuffix           string            `yaml:"output_files_suffix,omitempty"`
+	QueryParameterLimit         *int              `yaml:"query_parameter_limit,omitempty"`
+	InflectionExcludeTableNames []string          `yaml:"inflection_exclude_table_names,omitempty"`
+	Overrides                   []Override        `yaml:"overrides,omitempty"`
+	Rename                      map[string]string `yaml:"rename,omitempty"`
+}
+
+// GenJSON configures the JSON generator.
+type GenJSON struct {
+	Out      string `yaml:"out"`
+	Filename string `yaml:"filename,omitempty"`
+	Indent   string `yaml:"indent,omitempty"`
+}
+
+// Codegen runs a plugin generator.
+type Codegen struct {
+	Plugin  string `yaml:"plugin"`
+	Out     string `yaml:"out"`
+	Options any    `yaml:"options,omitempty"`
+}
+
+// Overrides holds the overrides applied to every sql entry.
+type Overrides struct {
+	Go *GoOverrides `yaml:"go,omitempty"`
+}
+
+// GoOverrides are the global Go type overrides and renames.
+type GoOverrides struct {
+	Overrides []Override        `yaml:"overrides,omitempty"`
+	Rename    map[string]string `yaml:"rename,omitempty"`
+}
+
+// Override maps a database type or column to a Go type. GoType is either an
+// import path qualified type name or a mapping with import, package and type.
+type Override struct {
+	DBType      string `yaml:"db_type,omitempty"`
+	Column      string `yaml:"column,omitempty"`
+	GoType      any    `yaml:"go_type"`
+	GoStructTag string `yaml:"go_struct_tag,omitempty"`
+	Nullable    bool   `yaml:"nullable,omitempty"`
+	Unsigned    bool   `yaml:"unsigned,omitempty"`
+	Engine      Engine `yaml:"engine,omitempty"`
+}
+
+// Plugin declares a codegen plugin.
+type Plugin struct {
+	Name    string   `yaml:"name"`
+	Env     []string `yaml:"env,omitempty"`
+	Process *Process `yaml:"process,omitempty"`
+	WASM    *WASM    `yaml:"wasm,omitempty"`
+}
+
+// Process runs a plugin as a local executable.
+type Process struct {
+	Cmd    string `yaml:"cmd"`
+	Format string `yaml:"format,omitempty"`
+}
+
+// WASM runs a plugin as a WebAssembly module.
+type WASM struct {
+	URL    string `yaml:"url"`
+	SHA256 string `yaml:"sha256"`
+}
+
+// Rule is a CEL rule checked by sqlc vet.
+type Rule struct {
+	Name    string `yaml:"name"`
+	Rule    string `yaml:"rule"`
+	Message string `yaml:"message,omitempty"`
+}
@@ -0,0 +1,438 @@
+package sqlcconfig
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ValidationError is a schema violation at a YAML path, e.g.
+// "sql[0].gen.go.emit_interface".
+type ValidationError struct {
+	Path    string
+	Message string
+}
+
+func (e ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+// ValidationErrors is every schema violation of a configuration.
+type ValidationErrors []ValidationError
+
+func (e ValidationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+
+	return "configuration does not match the sqlc schema:\n  " + strings.Join(messages, "\n  ")
+}
+
+// kind is the YAML type a schema node accepts.
+type kind int
+
+const (
+	kindAny kind = iota
+	kindString
+	kindBool
+	kindInt
+	kindObject
+	kindList
+	// kindStrings accepts a string or a list of strings, like sqlc's paths.
+	kindStrings
+)
+
+func (k kind) String() string {
+	switch k {
+	case kindString:
+		return "a string"
+	case kindBool:
+		return "a boolean"
+	case kindInt:
+		return "an integer"
+	case kindObject:
+		return "a mapping"
+	case kindList:
+		return "a list"
+	case kindStrings:
+		return "a string or a list of strings"
+	default:
+		return "any value"
+	}
+}
+
+// schema mirrors a node of the sqlc v2 JSON schema. Objects reject unknown
+// keys.
+type schema struct {
+	kind     kind
+	required []string
+	fields   map[string]*schema
+	items    *schema
+	values   *schema
+	enum     []string
+}
+
+func str(enum ...string) *schema { return &schema{kind: kindString, enum: enum} }
+
+func object(required []string, fields map[string]*schema) *schema {
+	return &schema{kind: kindObject, required: required, fields: fields}
+}
+
+func listOf(items *schema) *schema { return &schema{kind: kindList, items: items} }
+
+func mapOf(values *schema) *schema { return &schema{kind: kindObject, values: values} }
+
+var (
+	boolean = &schema{kind: kindBool}
+	integer = &schema{kind: kindInt}
+	anyType = &schema{kind: kindAny}
+	paths   = &schema{kind: kindStrings}
+)
+
+// engines are the values accepted for an sql entry's engine.
+var engines = []string{string(EnginePostgreSQL), string(EngineMySQL), string(EngineSQLite)}
+
+// overrideSchema is a gen.go type override.
+var overrideSchema = object(nil, map[string]*schema{
+	"db_type":       str(),
+	"column":        str(),
+	"go_type":       anyType,
+	"go_struct_tag": str(),
+	"nullable":      boolean,
+	"unsigned":      boolean,
+	"engine":        str(engines...),
+})
+
+// goGenSchema is the gen.go block of an sql entry.
+var goGenSchema = object([]string{"package", "out"}, map[string]*schema{
+	"package":                        str(),
+	"out":                            str(),
+	"sql_package":                    str("pgx/v4", "pgx/v5", "database/sql"),
+	"sql_driver":                     str(),
+	"build_tags":                     str(),
+	"emit_interface":                 boolean,
+	"emit_json_tags":                 boolean,
+	"json_tags_id_uppercase":         boolean,
+	"emit_db_tags":                   boolean,
+	"emit_prepared_queries":          boolean,
+	"emit_exact_table_names":         boolean,
+	"emit_empty_slices":              boolean,
+	"emit_exported_queries":          boolean,
+	"emit_result_struct_pointers":    boolean,
+	"emit_params_struct_pointers":    boolean,
+	"emit_methods_with_db_argument":  boolean,
+	"emit_pointers_for_null_types":   boolean,
+	"emit_enum_valid_method":         boolean,
+	"emit_all_enum_values":           boolean,
+	"emit_sql_as_comment":            boolean,
+	"json_tags_case_style":           str("camel", "pascal", "snake", "none"),
+	"omit_unused_structs":            boolean,
+	"omit_sqlc_version":              boolean,
+	"output_batch_file_name":         str(),
+	"output_db_file_name":            str(),
+	"output_models_file_name":        str(),
+	"output_querier_file_name":       str(),
+	"output_copyfrom_file_name":      str(),
+	"output_files_suffix":            str(),
+	"query_parameter_limit":          integer,
+	"inflection_exclude_table_names": listOf(str()),
+	"overrides":                      listOf(overrideSchema),
+	"rename":                         mapOf(str()),
+})
+
+// sqlSchema is one entry of the sql list.
+var sqlSchema = object([]string{"engine", "schema", "queries"}, map[string]*schema{
+	"name":                   str(),
+	"engine":                 str(engines...),
+	"schema":                 paths,
+	"queries":                paths,
+	"strict_function_checks": boolean,
+	"strict_order_by":        boolean,
+	"rules":                  listOf(str()),
+	"database": object(nil, map[string]*schema{
+		"uri":     str(),
+		"managed": boolean,
+	}),
+	"analyzer": object(nil, map[string]*schema{
+		"database": boolean,
+	}),
+	"gen": object(nil, map[string]*schema{
+		"go": goGenSchema,
+		"json": object([]string{"out"}, map[string]*schema{
+			"out":      str(),
+			"filename": str(),
+			"indent":   str(),
+		}),
+	}),
+	"codegen": listOf(object([]string{"plugin", "out"}, map[string]*schema{
+		"plugin":  str(),
+		"out":     str(),
+		"options": anyType,
+	})),
+})
+
+// sqlcSchema is the sqlc v2 configuration file.
+var sqlcSchema = object([]string{"version", "sql"}, map[string]*schema{
+	"version": str("2"),
+	"project": object(nil, map[string]*schema{"id": str()}),
+	"cloud": object(nil, map[string]*schema{
+		"organization": str(),
+		"project":      str(),
+		"hostname":     str(),
+	}),
+	"sql": listOf(sqlSchema),
+	"overrides": object(nil, map[string]*schema{
+		"go": object(nil, map[string]*schema{
+			"overrides": listOf(overrideSchema),
+			"rename":    mapOf(str()),
+		}),
+	}),
+	"plugins": listOf(object([]string{"name"}, map[string]*schema{
+		"name": str(),
+		"env":  listOf(str()),
+		"process": object([]string{"cmd"}, map[string]*schema{
+			"cmd":    str(),
+			"format": str("json", "protobuf"),
+		}),
+		"wasm": object([]string{"url", "sha256"}, map[string]*schema{
+			"url":    str(),
+			"sha256": str(),
+		}),
+	})),
+	"rules": listOf(object([]string{"name", "rule"}, map[string]*schema{
+		"name":    str(),
+		"rule":    str(),
+		"message": str(),
+	})),
+	"options": anyType,
+})
+
+// Validate checks config against the sqlc v2 schema and the engine-specific
+// constraints sqlc enforces at generate time. It returns every violation,
+// ordered by path.
+func Validate(config map[string]any) ValidationErrors {
+	var errs ValidationErrors
+
+	validateNode(sqlcSchema, config, "", &errs)
+	validateEngines(config, &errs)
+
+	slices.SortStableFunc(errs, func(a, b ValidationError) int { return strings.Compare(a.Path, b.Path) })
+
+	return errs
+}
+
+func validateNode(node *schema, value any, path string, errs *ValidationErrors) {
+	fail := func(format string, args ...any) {
+		*errs = append(*errs, ValidationError{Path: displayPath(path), Message: fmt.Sprintf(format, args...)})
+	}
+
+	switch node.kind {
+	case kindAny:
+	case kindString:
+		text, ok := value.(string)
+		if !ok {
+			fail("must be %s, got %T", node.kind, value)
+
+			return
+		}
+
+		if len(node.enum) > 0 && !slices.Contains(node.enum, text) {
+			fail("%q is not one of %s", text, strings.Join(node.enum, ", "))
+		}
+	case kindBool:
+		if _, ok := value.(bool); !ok {
+			fail("must be %s, got %T", node.kind, value)
+		}
+	case kindInt:
+		if _, ok := value.(int); !ok {
+			fail("must be %s, got %T", node.kind, value)
+		}
+	case kindStrings:
+		validateStrings(value, path, fail)
+	case kindList:
+		items, ok := value.([]any)
+		if !ok {
+			fail("must be %s, got %T", node.kind, value)
+
+			return
+		}
+
+		for i, item := range items {
+			validateNode(node.items, item, path+"["+strconv.Itoa(i)+"]", errs)
+		}
+	case kindObject:
+		validateObject(node, value, path, errs, fail)
+	}
+}
+
+func validateStrings(value any, path string, fail func(string, ...any)) {
+	switch typed := value.(type) {
+	case string:
+	case []any:
+		for i, item := range typed {
+			if _, ok := item.(string); !ok {
+				fail("item %d of %s must be a string, got %T", i, displayPath(path), item)
+			}
+		}
+	default:
+		fail("must be %s, got %T", kindStrings, value)
+	}
+}
+
+func validateObject(node *schema, value any, path string, errs *ValidationErrors, fail func(string, ...any)) {
+	fields, ok := value.(map[string]any)
+	if !ok {
+		fail("must be %s, got %T", node.kind, value)
+
+		return
+	}
+
+	for _, key := range node.required {
+		if _, ok := fields[key]; !ok {
+			fail("missing required key %q", key)
+		}
+	}
+
+	for _, key := range sortedKeys(fields) {
+		child := node.values
+		if node.fields != nil {
+			child = node.fields[key]
+		}
+
+		if child == nil {
+			fail("unknown key %q%s", key, suggest(key, node.fields))
+
+			continue
+		}
+
+		validateNode(child, fields[key], joinKey(path, key), errs)
+	}
+}
+
+// validateEngines applies the constraints that depend on an sql entry's engine.
+func validateEngines(config map[string]any, errs *ValidationErrors) {
+	sqlConfigs, _ := config["sql"].([]any)
+	names := make(map[string]int, len(sqlConfigs))
+
+	for i, sqlConfig := range sqlConfigs {
+		block, ok := sqlConfig.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		path := "sql[" + strconv.Itoa(i) + "]"
+		fail := func(key, format string, args ...any) {
+			*errs = append(*errs, ValidationError{Path: joinKey(path, key), Message: fmt.Sprintf(format, args...)})
+		}
+
+		if name, ok := block["name"].(string); ok {
+			if first, seen := names[name]; seen {
+				fail("name", "duplicate name %q, first used by sql[%d]", name, first)
+			}
+
+			names[name] = i
+		}
+
+		engine, _ := block["engine"].(string)
+		goGen, _ := lookup(block, "gen", "go").(map[string]any)
+
+		if sqlPackage, _ := goGen["sql_package"].(string); strings.HasPrefix(sqlPackage, "pgx/") && engine != string(EnginePostgreSQL) {
+			fail("gen.go.sql_package", "%q requires engine %q, got %q", sqlPackage, EnginePostgreSQL, engine)
+		}
+
+		withDB, _ := goGen["emit_methods_with_db_argument"].(bool)
+		if prepared, _ := goGen["emit_prepared_queries"].(bool); withDB && prepared {
+			fail("gen.go.emit_methods_with_db_argument", "cannot be combined with emit_prepared_queries")
+		}
+
+		if limit, ok := goGen["query_parameter_limit"].(int); ok && limit < 0 {
+			fail("gen.go.query_parameter_limit", "must not be negative, got %d", limit)
+		}
+	}
+}
+
+// suggest returns a hint naming the known key closest to key.
+func suggest(key string, fields map[string]*schema) string {
+	best, bestDistance := "", len(key)/2+1
+
+	for candidate := range fields {
+		if distance := levenshtein(key, candidate); distance < bestDistance ||
+			(distance == bestDistance && candidate < best) {
+			best, bestDistance = candidate, distance
+		}
+	}
+
+	if best == "" {
+		return ""
+	}
+
+	return fmt.Sprintf("; did you mean %q?", best)
+}
+
+func levenshtein(a, b string) int {
+	previous := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current := make([]int, len(b)+1)
+		current[0] = i
+
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+
+		previous = current
+	}
+
+	return previous[len(b)]
+}
+
+// lookup returns the value at the nested keys of a decoded YAML mapping.
+func lookup(value any, keys ...string) any {
+	for _, key := range keys {
+		fields, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+
+		value = fields[key]
+	}
+
+	return value
+}
+
+func joinKey(path, key string) string {
+	if path == "" {
+		return key
+	}
+
+	return path + "." + key
+}
+
+func displayPath(path string) string {
+	if path == "" {
+		return "."
+	}
+
+	return path
+}
+
+func sortedKeys(fields map[string]any) []string {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+
+	return keys
+}
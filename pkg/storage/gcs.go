@@ -0,0 +1,172 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// DefaultGCSEndpoint is the endpoint of the Google Cloud Storage JSON API.
+const DefaultGCSEndpoint = "https://storage.googleapis.com"
+
+// ErrUnexpectedStatus is returned when Google Cloud Storage answers a
+// request with an error status.
+var ErrUnexpectedStatus = errors.New("unexpected storage response status")
+
+// GCS stores blobs as objects of a Google Cloud Storage bucket through the
+// JSON API. The HTTP client authorizes the requests, for example one from
+// golang.org/x/oauth2/google.DefaultClient with the devstorage.read_write
+// scope.
+type GCS struct {
+	client   *http.Client
+	bucket   string
+	endpoint string
+	baseURL  string
+}
+
+var _ BlobStore = (*GCS)(nil)
+
+// GCSOption configures a GCS store.
+type GCSOption func(*GCS)
+
+// WithGCSEndpoint sends the API requests to endpoint instead of
+// DefaultGCSEndpoint, such as an emulator.
+func WithGCSEndpoint(endpoint string) GCSOption {
+	return func(g *GCS) {
+		g.endpoint = endpoint
+	}
+}
+
+// WithGCSBaseURL serves blobs from baseURL, such as a CDN in front of the
+// bucket, instead of the public URL of the bucket.
+func WithGCSBaseURL(baseURL string) GCSOption {
+	return func(g *GCS) {
+		g.baseURL = baseURL
+	}
+}
+
+// NewGCS creates a store in bucket sending requests with client.
+func NewGCS(client *http.Client, bucket string, opts ...GCSOption) *GCS {
+	g := &GCS{
+		client:   client,
+		bucket:   bucket,
+		endpoint: DefaultGCSEndpoint,
+		baseURL:  DefaultGCSEndpoint + "/" + bucket,
+	}
+
+	for _, opt := range opts {
+		opt(g)
+	}
+
+	return g
+}
+
+// Put uploads body as the object key with a simple media upload.
+func (g *GCS) Put(ctx context.Context, key string, body io.Reader, contentType string) error {
+	err := ValidateKey(key)
+	if err != nil {
+		return err
+	}
+
+	target := g.endpoint + "/upload/storage/v1/b/" + url.PathEscape(g.bucket) +
+		"/o?uploadType=media&name=" + url.QueryEscape(key)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, body)
+	if err != nil {
+		return fmt.Errorf("gcs put bucket=%v key=%v: %w", g.bucket, key, err)
+	}
+
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := g.do(req)
+	if err != nil {
+		return fmt.Errorf("gcs put bucket=%v key=%v: %w", g.bucket, key, err)
+	}
+
+	return drain(resp)
+}
+
+// Get downloads the object key.
+func (g *GCS) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	err := ValidateKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, g.objectURL(key)+"?alt=media", nil)
+	if err != nil {
+		return nil, fmt.Errorf("gcs get bucket=%v key=%v: %w", g.bucket, key, err)
+	}
+
+	resp, err := g.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("gcs get bucket=%v key=%v: %w", g.bucket, key, err)
+	}
+
+	return resp.Body, nil
+}
+
+// Delete removes the object key.
+func (g *GCS) Delete(ctx context.Context, key string) error {
+	err := ValidateKey(key)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, g.objectURL(key), nil)
+	if err != nil {
+		return fmt.Errorf("gcs delete bucket=%v key=%v: %w", g.bucket, key, err)
+	}
+
+	resp, err := g.do(req)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("gcs delete bucket=%v key=%v: %w", g.bucket, key, err)
+	}
+
+	return drain(resp)
+}
+
+// URL returns the address of key below the base URL.
+func (g *GCS) URL(key string) string {
+	return joinURL(g.baseURL, key)
+}
+
+// objectURL returns the API resource of the object key.
+func (g *GCS) objectURL(key string) string {
+	return g.endpoint + "/storage/v1/b/" + url.PathEscape(g.bucket) + "/o/" + url.PathEscape(key)
+}
+
+// do sends req and returns the response if its status is 2xx. Responses
+// with 404 are ErrNotFound.
+func (g *GCS) do(req *http.Request) (*http.Response, error) {
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices {
+		return resp, nil
+	}
+
+	_ = drain(resp)
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+
+	return nil, fmt.Errorf("status=%d: %w", resp.StatusCode, ErrUnexpectedStatus)
+}
+
+// drain reads and closes the body of resp, so its connection can be reused.
+func drain(resp *http.Response) error {
+	_, err := io.Copy(io.Discard, resp.Body)
+
+	return errors.Join(err, resp.Body.Close())
+}
@@ -0,0 +1,156 @@
+package storage
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+)
+
+// File modes of stored blobs and their directories.
+const (
+	dirMode  = 0o750
+	fileMode = 0o640
+)
+
+// Local stores blobs as files under a directory, which a web server or
+// http.FileServerFS(local.FS()) serves at the base URL. Keys cannot escape
+// the directory.
+type Local struct {
+	root    *os.Root
+	baseURL string
+}
+
+var _ BlobStore = (*Local)(nil)
+
+// NewLocal creates a store in dir, which is created if missing, serving
+// blobs from baseURL. Close the store to release the directory.
+func NewLocal(dir, baseURL string) (*Local, error) {
+	err := os.MkdirAll(dir, dirMode)
+	if err != nil {
+		return nil, fmt.Errorf("create blob directory %s: %w", dir, err)
+	}
+
+	root, err := os.OpenRoot(dir)
+	if err != nil {
+		return nil, fmt.Errorf("open blob directory %s: %w", dir, err)
+	}
+
+	return &Local{root: root, baseURL: baseURL}, nil
+}
+
+// Put writes body to a temporary file and renames it to key, so readers
+// never see partial blobs. The content type is not stored; file servers
+// derive it from the extension of the key.
+func (l *Local) Put(_ context.Context, key string, body io.Reader, _ string) error {
+	err := ValidateKey(key)
+	if err != nil {
+		return err
+	}
+
+	if dir := path.Dir(key); dir != "." {
+		err = l.root.MkdirAll(dir, dirMode)
+		if err != nil {
+			return fmt.Errorf("create directory of key=%v: %w", key, err)
+		}
+	}
+
+	tmp, err := tempName(key)
+	if err != nil {
+		return err
+	}
+
+	err = l.writeFile(tmp, body)
+	if err != nil {
+		_ = l.root.Remove(tmp)
+
+		return fmt.Errorf("write key=%v: %w", key, err)
+	}
+
+	err = l.root.Rename(tmp, key)
+	if err != nil {
+		_ = l.root.Remove(tmp)
+
+		return fmt.Errorf("rename key=%v: %w", key, err)
+	}
+
+	return nil
+}
+
+// writeFile creates name and copies body into it.
+func (l *Local) writeFile(name string, body io.Reader) error {
+	f, err := l.root.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_EXCL, fileMode)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(f, body)
+
+	return errors.Join(err, f.Close())
+}
+
+// Get opens the file of key.
+func (l *Local) Get(_ context.Context, key string) (io.ReadCloser, error) {
+	err := ValidateKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := l.root.Open(key)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("key=%v: %w", key, ErrNotFound)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("open key=%v: %w", key, err)
+	}
+
+	return f, nil
+}
+
+// Delete removes the file of key.
+func (l *Local) Delete(_ context.Context, key string) error {
+	err := ValidateKey(key)
+	if err != nil {
+		return err
+	}
+
+	err = l.root.Remove(key)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("delete key=%v: %w", key, err)
+	}
+
+	return nil
+}
+
+// URL returns the address of key below the base URL.
+func (l *Local) URL(key string) string {
+	return joinURL(l.baseURL, key)
+}
+
+// FS returns the directory of the store, to serve it over HTTP.
+func (l *Local) FS() fs.FS {
+	return l.root.FS()
+}
+
+// Close releases the directory.
+func (l *Local) Close() error {
+	return l.root.Close()
+}
+
+// tempName returns a unique name next to key to write it to.
+func tempName(key string) (string, error) {
+	var suffix [8]byte
+
+	_, err := rand.Read(suffix[:])
+	if err != nil {
+		return "", fmt.Errorf("temporary name of key=%v: %w", key, err)
+	}
+
+	return key + ".tmp-" + hex.EncodeToString(suffix[:]), nil
+}
@@ -0,0 +1,97 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"sync"
+)
+
+// memoryBlob is a blob held by Memory.
+type memoryBlob struct {
+	data        []byte
+	contentType string
+}
+
+// Memory keeps blobs in process memory, for tests and development.
+type Memory struct {
+	mu      sync.Mutex
+	blobs   map[string]memoryBlob
+	baseURL string
+}
+
+var _ BlobStore = (*Memory)(nil)
+
+// NewMemory creates an empty store serving blobs from baseURL.
+func NewMemory(baseURL string) *Memory {
+	return &Memory{blobs: make(map[string]memoryBlob), baseURL: baseURL}
+}
+
+// Put reads body into memory.
+func (m *Memory) Put(_ context.Context, key string, body io.Reader, contentType string) error {
+	err := ValidateKey(key)
+	if err != nil {
+		return err
+	}
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return fmt.Errorf("read key=%v: %w", key, err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.blobs[key] = memoryBlob{data: data, contentType: contentType}
+
+	return nil
+}
+
+// Get returns a reader over the blob of key.
+func (m *Memory) Get(_ context.Context, key string) (io.ReadCloser, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	blob, ok := m.blobs[key]
+	if !ok {
+		return nil, fmt.Errorf("key=%v: %w", key, ErrNotFound)
+	}
+
+	return io.NopCloser(bytes.NewReader(blob.data)), nil
+}
+
+// Delete forgets the blob of key.
+func (m *Memory) Delete(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.blobs, key)
+
+	return nil
+}
+
+// URL returns the address of key below the base URL.
+func (m *Memory) URL(key string) string {
+	return joinURL(m.baseURL, key)
+}
+
+// Keys returns the keys of the stored blobs in order.
+func (m *Memory) Keys() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return slices.Sorted(maps.Keys(m.blobs))
+}
+
+// ContentType returns the content type the blob of key was stored with.
+func (m *Memory) ContentType(key string) (string, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	blob, ok := m.blobs[key]
+
+	return blob.contentType, ok
+}
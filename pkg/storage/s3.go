@@ -0,0 +1,111 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// S3Client is the part of *s3.Client that S3 uses.
+type S3Client interface {
+	PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error)
+	GetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error)
+	DeleteObject(
+		ctx context.Context,
+		params *s3.DeleteObjectInput,
+		optFns ...func(*s3.Options),
+	) (*s3.DeleteObjectOutput, error)
+}
+
+// S3 stores blobs as objects of an Amazon S3 bucket, or of any service
+// with an S3 compatible API.
+type S3 struct {
+	client  S3Client
+	bucket  string
+	baseURL string
+}
+
+var _ BlobStore = (*S3)(nil)
+
+// NewS3 creates a store in bucket serving blobs from baseURL, such as a
+// CDN in front of the bucket. An empty baseURL serves them from the
+// virtual-hosted URL of the bucket.
+func NewS3(client S3Client, bucket, baseURL string) *S3 {
+	if baseURL == "" {
+		baseURL = "https://" + bucket + ".s3.amazonaws.com"
+	}
+
+	return &S3{client: client, bucket: bucket, baseURL: baseURL}
+}
+
+// Put uploads body as the object key.
+func (s *S3) Put(ctx context.Context, key string, body io.Reader, contentType string) error {
+	err := ValidateKey(key)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(key),
+		Body:        body,
+		ContentType: aws.String(contentType),
+	})
+	if err != nil {
+		return fmt.Errorf("s3 put bucket=%v key=%v: %w", s.bucket, key, err)
+	}
+
+	return nil
+}
+
+// Get downloads the object key.
+func (s *S3) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	err := ValidateKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+
+	var noSuchKey *types.NoSuchKey
+	if errors.As(err, &noSuchKey) {
+		return nil, fmt.Errorf("s3 get bucket=%v key=%v: %w", s.bucket, key, ErrNotFound)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("s3 get bucket=%v key=%v: %w", s.bucket, key, err)
+	}
+
+	return out.Body, nil
+}
+
+// Delete removes the object key. S3 reports no error for missing objects.
+func (s *S3) Delete(ctx context.Context, key string) error {
+	err := ValidateKey(key)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return fmt.Errorf("s3 delete bucket=%v key=%v: %w", s.bucket, key, err)
+	}
+
+	return nil
+}
+
+// URL returns the address of key below the base URL.
+func (s *S3) URL(key string) string {
+	return joinURL(s.baseURL, key)
+}
@@ -0,0 +1,83 @@
+// Package storage stores blobs, such as avatar images, in the local
+// filesystem, Amazon S3, Google Cloud Storage or memory behind one
+// BlobStore interface:
+//
+//	store, err := storage.NewLocal("var/files", "https://example.com/files")
+//	err = store.Put(ctx, "avatars/1/a.png", bytes.NewReader(data), "image/png")
+//	url := store.URL("avatars/1/a.png")
+//
+// Keys are slash-separated relative paths. Blobs are served to clients from
+// URL, so the stores are meant for public files.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// maxKeyLength bounds keys to the object name limit of S3 and GCS.
+const maxKeyLength = 1024
+
+var (
+	// ErrNotFound is returned by Get for a key without a blob.
+	ErrNotFound = errors.New("blob not found")
+	// ErrInvalidKey is returned for a key that is not a clean relative path.
+	ErrInvalidKey = errors.New("invalid blob key")
+)
+
+// BlobStore stores blobs under keys.
+type BlobStore interface {
+	// Put stores body under key with contentType, replacing any blob stored
+	// there. Bodies should be seekable, like *bytes.Reader, so that stores
+	// can sign or retry the upload.
+	Put(ctx context.Context, key string, body io.Reader, contentType string) error
+	// Get opens the blob under key or returns ErrNotFound. Callers close it.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the blob under key. Deleting a missing blob is not an error.
+	Delete(ctx context.Context, key string) error
+	// URL returns the address clients download the blob under key from.
+	URL(key string) string
+}
+
+// ValidateKey returns ErrInvalidKey unless key is a relative path of
+// letters, digits, dots, dashes and underscores separated by single
+// slashes, without . or .. segments.
+func ValidateKey(key string) error {
+	if key == "" || len(key) > maxKeyLength {
+		return fmt.Errorf("key=%q: %w", key, ErrInvalidKey)
+	}
+
+	for segment := range strings.SplitSeq(key, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return fmt.Errorf("key=%q: %w", key, ErrInvalidKey)
+		}
+
+		for _, r := range segment {
+			if !isKeyRune(r) {
+				return fmt.Errorf("key=%q: %w", key, ErrInvalidKey)
+			}
+		}
+	}
+
+	return nil
+}
+
+// isKeyRune returns true for the characters keys may contain besides slashes.
+func isKeyRune(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') ||
+		r == '.' || r == '-' || r == '_'
+}
+
+// joinURL appends the escaped segments of key to baseURL.
+func joinURL(baseURL, key string) string {
+	u, err := url.JoinPath(baseURL, strings.Split(key, "/")...)
+	if err != nil {
+		return strings.TrimSuffix(baseURL, "/") + "/" + key
+	}
+
+	return u
+}
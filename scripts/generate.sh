@@ -58,4 +58,13 @@ for file in internal/db/mysql/models.go internal/db/mysql/user.sql.go; do
 	fi
 done
 
+# Generate the user mappers from the sqlc models
+go generate ./internal/adapters/mappers/
+
+# Generate gRPC server and protobuf types from proto/
+buf generate
+
+# Generate GraphQL executable schema and resolver stubs
+(cd internal/adapters/graphql && go run github.com/99designs/gqlgen generate)
+
 echo "Code generation and deduplication complete"
@@ -0,0 +1,18 @@
+-- User events for ClickHouse
+-- The sink records the events the login and signup reports are built from.
+-- It may insert an event more than once, so copies with the same sorting
+-- key are collapsed by ReplacingMergeTree and the reports read with FINAL.
+
+CREATE TABLE IF NOT EXISTS user_events (
+    event_id Int64,
+    type LowCardinality(String),
+    user_id Int64,
+    -- Empty for events without an address, such as signups.
+    ip_address String,
+    -- Signup source, an identity provider or 'direct'; empty for logins.
+    source LowCardinality(String),
+    occurred_at DateTime64(3, 'UTC')
+)
+ENGINE = ReplacingMergeTree
+PARTITION BY toYYYYMM(occurred_at)
+ORDER BY (type, occurred_at, event_id);
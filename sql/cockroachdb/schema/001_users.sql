@@ -0,0 +1,37 @@
+-- Users schema for CockroachDB
+-- The primary key is a random UUID, so that inserts spread over the ranges of
+-- the cluster instead of appending to the last one. id stays the numeric key
+-- the application refers to users by; unique_rowid() generates it without a
+-- sequence. The columns keep the order of the PostgreSQL schema after all of
+-- its migrations, because the shared queries select users with *.
+
+CREATE TABLE users (
+    id INT8 UNIQUE NOT NULL DEFAULT unique_rowid(),
+    uuid UUID NOT NULL DEFAULT gen_random_uuid(),
+    email TEXT UNIQUE NOT NULL,
+    username TEXT UNIQUE NOT NULL,
+    password_hash TEXT NOT NULL,
+    first_name TEXT NOT NULL,
+    last_name TEXT NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    last_login_at TIMESTAMPTZ NULL,
+    is_active BOOLEAN DEFAULT TRUE,
+    is_verified BOOLEAN DEFAULT FALSE,
+    profile_metadata JSONB DEFAULT '{}',
+    status TEXT NOT NULL DEFAULT 'active',
+    role TEXT NOT NULL DEFAULT 'user',
+    tags TEXT[] NOT NULL DEFAULT '{}',
+    deleted_at TIMESTAMPTZ NULL,
+    PRIMARY KEY (uuid)
+);
+
+CREATE INDEX idx_users_status ON users(status);
+CREATE INDEX idx_users_role ON users(role);
+CREATE INVERTED INDEX idx_users_tags ON users(tags);
+
+-- Listings page through users ordered by (created_at, id).
+CREATE INDEX idx_users_created_at_id ON users(created_at, id);
+
+-- Only deleted users are looked up by deletion time, when purging.
+CREATE INDEX idx_users_deleted_at ON users(deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,18 @@
+-- Full-text search for CockroachDB
+-- users_search_document builds the tsvector searched by SearchUsers, as on
+-- PostgreSQL. CockroachDB cannot index calls to user-defined functions, so
+-- the search scans the users matching the other filters.
+
+CREATE FUNCTION users_search_document(
+    email TEXT,
+    username TEXT,
+    first_name TEXT,
+    last_name TEXT
+) RETURNS TSVECTOR
+LANGUAGE SQL
+IMMUTABLE
+AS $$
+    SELECT setweight(to_tsvector('simple', username), 'A')
+        || setweight(to_tsvector('simple', email), 'A')
+        || setweight(to_tsvector('simple', first_name || ' ' || last_name), 'B')
+$$;
@@ -0,0 +1,26 @@
+-- Organizations and memberships for CockroachDB
+-- A membership row with status 'invited' is a pending invitation. Like users,
+-- organizations are keyed by a random UUID and referred to by id.
+
+CREATE TABLE organizations (
+    id INT8 UNIQUE NOT NULL DEFAULT unique_rowid(),
+    uuid UUID NOT NULL DEFAULT gen_random_uuid(),
+    name TEXT NOT NULL,
+    slug TEXT UNIQUE NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (uuid)
+);
+
+CREATE TABLE organization_members (
+    organization_id INT8 NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    user_id INT8 NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role TEXT NOT NULL CHECK (role IN ('user', 'moderator', 'admin')),
+    status TEXT NOT NULL CHECK (status IN ('invited', 'active')),
+    invited_by INT8 REFERENCES users(id) ON DELETE SET NULL,
+    invited_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    joined_at TIMESTAMPTZ,
+    PRIMARY KEY (organization_id, user_id)
+);
+
+CREATE INDEX idx_organization_members_user ON organization_members(user_id, status);
@@ -0,0 +1,15 @@
+-- External identities for CockroachDB
+-- Links users to accounts at OAuth2/OIDC providers. A provider subject belongs
+-- to one user, and a user has at most one identity per provider.
+
+CREATE TABLE user_identities (
+    id INT8 PRIMARY KEY DEFAULT unique_rowid(),
+    user_id INT8 NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    provider TEXT NOT NULL,
+    subject TEXT NOT NULL,
+    email TEXT NOT NULL DEFAULT '',
+    linked_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_login_at TIMESTAMPTZ,
+    UNIQUE (provider, subject),
+    UNIQUE (user_id, provider)
+);
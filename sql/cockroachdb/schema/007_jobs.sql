@@ -0,0 +1,21 @@
+-- Background jobs for CockroachDB
+-- See the PostgreSQL schema for the claiming protocol. INTEGER is 64 bits wide
+-- on CockroachDB, so the counters are INT4 to match the generated code.
+
+CREATE TABLE jobs (
+    id INT8 PRIMARY KEY DEFAULT unique_rowid(),
+    name TEXT NOT NULL,
+    payload JSONB NOT NULL DEFAULT '{}',
+    status TEXT NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'running', 'done', 'failed')),
+    attempts INT4 NOT NULL DEFAULT 0,
+    max_attempts INT4 NOT NULL DEFAULT 5,
+    run_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    locked_until TIMESTAMPTZ,
+    last_error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_jobs_status_run_at ON jobs(status, run_at);
+CREATE INDEX idx_jobs_status_locked_until ON jobs(status, locked_until);
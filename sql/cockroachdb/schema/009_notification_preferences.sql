@@ -0,0 +1,13 @@
+-- Notification preferences for CockroachDB
+-- Opts users in to or out of a topic on a channel. Channels and topics without
+-- a row use the application defaults; target is the URL of webhooks.
+
+CREATE TABLE notification_preferences (
+    user_id INT8 NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    channel TEXT NOT NULL,
+    topic TEXT NOT NULL,
+    enabled BOOLEAN NOT NULL,
+    target TEXT NOT NULL DEFAULT '',
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, channel, topic)
+);
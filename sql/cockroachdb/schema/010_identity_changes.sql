@@ -0,0 +1,19 @@
+-- Identity change history for CockroachDB
+-- Records changes of the email address and username of users. Email changes
+-- stay pending until the new address confirms them; applied changes can be
+-- reverted with their token until expires_at.
+
+CREATE TABLE identity_changes (
+    id INT8 PRIMARY KEY DEFAULT unique_rowid(),
+    user_id INT8 NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    field TEXT NOT NULL,
+    old_value TEXT NOT NULL,
+    new_value TEXT NOT NULL,
+    status TEXT NOT NULL,
+    token UUID NOT NULL UNIQUE,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_identity_changes_user_created ON identity_changes(user_id, created_at);
@@ -0,0 +1,19 @@
+-- Activity feeds for CockroachDB
+-- Human-readable entries projected from domain events, one per user and
+-- event. Entries older than the retention are pruned by the cleanup job.
+-- actor_id is 0 when the user caused the activity.
+
+CREATE TABLE user_activity (
+    id INT8 PRIMARY KEY DEFAULT unique_rowid(),
+    user_id INT8 NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    event_id INT8 NOT NULL,
+    event_type TEXT NOT NULL,
+    kind TEXT NOT NULL,
+    summary TEXT NOT NULL,
+    actor_id INT8 NOT NULL DEFAULT 0,
+    occurred_at TIMESTAMPTZ NOT NULL,
+    UNIQUE (user_id, event_id)
+);
+
+CREATE INDEX idx_user_activity_user_occurred ON user_activity(user_id, occurred_at, id);
+CREATE INDEX idx_user_activity_occurred ON user_activity(occurred_at);
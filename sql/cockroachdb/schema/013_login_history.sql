@@ -0,0 +1,17 @@
+-- Login history for CockroachDB
+-- One row per login attempt, successful or failed, for security pages and
+-- abuse detection. user_id is NULL for attempts on unknown accounts; failure
+-- is empty for successful attempts. Rows are removed with their user.
+
+CREATE TABLE login_history (
+    id INT8 PRIMARY KEY DEFAULT unique_rowid(),
+    user_id INT8 REFERENCES users(id) ON DELETE CASCADE,
+    ip_address TEXT NOT NULL DEFAULT '',
+    user_agent TEXT NOT NULL DEFAULT '',
+    method TEXT NOT NULL,
+    failure TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX idx_login_history_user_created ON login_history(user_id, created_at);
+CREATE INDEX idx_login_history_ip_created ON login_history(ip_address, created_at);
@@ -0,0 +1,27 @@
+-- User read model for CockroachDB
+-- One denormalized view per live user that listings and searches are served
+-- from, projected from the domain events. search_text holds the lowercase
+-- words of the email, username and full name, each preceded by a space, so
+-- that a query term matches as a word prefix with LIKE '% term%'.
+-- source_updated_at is the update time of the projected user, which the lag
+-- is measured against.
+
+CREATE TABLE user_read_model (
+    user_id INT8 PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    uuid UUID NOT NULL,
+    email TEXT NOT NULL,
+    username TEXT NOT NULL,
+    full_name TEXT NOT NULL,
+    status TEXT NOT NULL,
+    role TEXT NOT NULL,
+    tags TEXT[] NOT NULL DEFAULT '{}',
+    is_verified BOOLEAN NOT NULL DEFAULT FALSE,
+    search_text TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    source_updated_at TIMESTAMPTZ NOT NULL,
+    projected_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX idx_user_read_model_created ON user_read_model(created_at DESC, user_id DESC);
+CREATE INDEX idx_user_read_model_status ON user_read_model(status);
+CREATE INVERTED INDEX idx_user_read_model_tags ON user_read_model(tags);
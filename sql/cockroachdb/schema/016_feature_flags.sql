@@ -0,0 +1,9 @@
+-- Feature flags for CockroachDB
+-- Named switches that change the behavior of running instances without a
+-- deploy, such as maintenance modes. Flags without a row use their default.
+
+CREATE TABLE feature_flags (
+    name VARCHAR(100) PRIMARY KEY,
+    enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
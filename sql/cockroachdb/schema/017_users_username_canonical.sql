@@ -0,0 +1,12 @@
+-- Canonical usernames for CockroachDB
+-- username_canonical is the username in Unicode NFKC with its case folded, as
+-- entities.Username.Canonical computes it. It is unique, so that usernames
+-- differing only by case or compatibility characters cannot coexist, and
+-- usernames are looked up by it. Usernames so far are ASCII, so LOWER is
+-- their canonical form; the migration fails if two of them differ only by
+-- case.
+ALTER TABLE users ADD COLUMN username_canonical TEXT NOT NULL DEFAULT '';
+
+UPDATE users SET username_canonical = LOWER(username);
+
+CREATE UNIQUE INDEX idx_users_username_canonical ON users(username_canonical);
@@ -0,0 +1,19 @@
+-- Analytical copy of the users for DuckDB
+-- The sync job replaces the rows with the live users of the primary store;
+-- only the columns the aggregate queries read are copied. sync_state keeps
+-- the time of the last sync, so readers can tell how stale the copy is.
+
+CREATE TABLE users (
+    id BIGINT PRIMARY KEY,
+    status VARCHAR NOT NULL,
+    role VARCHAR NOT NULL,
+    is_verified BOOLEAN NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    last_login_at TIMESTAMPTZ
+);
+
+CREATE TABLE sync_state (
+    name VARCHAR PRIMARY KEY,
+    synced_at TIMESTAMPTZ NOT NULL,
+    row_count BIGINT NOT NULL
+);
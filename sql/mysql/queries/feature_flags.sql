@@ -0,0 +1,9 @@
+-- name: ListFeatureFlags :many
+SELECT name, enabled, updated_at
+FROM feature_flags
+ORDER BY name;
+
+-- name: SetFeatureFlag :exec
+INSERT INTO feature_flags (name, enabled, updated_at)
+VALUES (sqlc.arg(name), sqlc.arg(enabled), sqlc.arg(updated_at))
+ON DUPLICATE KEY UPDATE enabled = VALUES(enabled), updated_at = VALUES(updated_at);
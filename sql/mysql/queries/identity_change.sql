@@ -0,0 +1,24 @@
+-- name: CreateIdentityChange :execresult
+INSERT INTO identity_changes (user_id, field, old_value, new_value, status, token, expires_at, created_at, updated_at)
+VALUES (sqlc.arg(user_id), sqlc.arg(field), sqlc.arg(old_value), sqlc.arg(new_value), sqlc.arg(status), sqlc.arg(token), sqlc.arg(expires_at), sqlc.arg(created_at), sqlc.arg(updated_at));
+
+-- name: GetIdentityChangeByToken :one
+SELECT id, user_id, field, old_value, new_value, status, token, expires_at, created_at, updated_at
+FROM identity_changes
+WHERE token = sqlc.arg(token)
+LIMIT 1;
+
+-- name: UpdateIdentityChange :execrows
+UPDATE identity_changes
+SET status = sqlc.arg(status), token = sqlc.arg(token), expires_at = sqlc.arg(expires_at), updated_at = sqlc.arg(updated_at)
+WHERE id = sqlc.arg(id);
+
+-- name: ListIdentityChanges :many
+SELECT id, user_id, field, old_value, new_value, status, token, expires_at, created_at, updated_at
+FROM identity_changes
+WHERE user_id = sqlc.arg(user_id)
+ORDER BY created_at DESC, id DESC;
+
+-- name: DeleteIdentityChangesByUser :execrows
+DELETE FROM identity_changes
+WHERE user_id = sqlc.arg(user_id);
@@ -0,0 +1,52 @@
+-- name: EnqueueJob :execresult
+INSERT INTO jobs (
+    name, payload, max_attempts, run_at, last_error, created_at, updated_at
+) VALUES (
+    sqlc.arg(name), sqlc.arg(payload), sqlc.arg(max_attempts),
+    sqlc.arg(run_at), '', sqlc.arg(created_at), sqlc.arg(created_at)
+);
+
+-- name: GetJob :one
+SELECT id, name, payload, status, attempts, max_attempts, run_at, locked_until, last_error, created_at, updated_at
+FROM jobs
+WHERE id = sqlc.arg(id)
+LIMIT 1;
+
+-- name: ListDueJobs :many
+-- Due jobs are pending ones whose run_at has passed and running ones whose lease expired.
+SELECT id, name, payload, status, attempts, max_attempts, run_at, locked_until, last_error, created_at, updated_at
+FROM jobs
+WHERE (status = 'pending' AND run_at <= sqlc.arg(now))
+   OR (status = 'running' AND locked_until < sqlc.arg(now))
+ORDER BY run_at, id
+LIMIT ?;
+
+-- name: ClaimJob :execrows
+-- Claims a job returned by ListDueJobs unless another worker has claimed or
+-- finished it since; status and attempts are the ones that were listed.
+UPDATE jobs
+SET status = 'running', attempts = attempts + 1,
+    locked_until = sqlc.arg(locked_until), updated_at = sqlc.arg(now)
+WHERE id = sqlc.arg(id) AND status = sqlc.arg(status) AND attempts = sqlc.arg(attempts);
+
+-- name: CompleteJob :execrows
+-- Finishing a claim matches its attempts, so a worker whose lease expired
+-- cannot overwrite a job another worker has claimed since.
+UPDATE jobs
+SET status = 'done', locked_until = NULL, last_error = '', updated_at = sqlc.arg(now)
+WHERE id = sqlc.arg(id) AND status = 'running' AND attempts = sqlc.arg(attempts);
+
+-- name: RetryJob :execrows
+UPDATE jobs
+SET status = 'pending', run_at = sqlc.arg(run_at), locked_until = NULL,
+    last_error = sqlc.arg(last_error), updated_at = sqlc.arg(now)
+WHERE id = sqlc.arg(id) AND status = 'running' AND attempts = sqlc.arg(attempts);
+
+-- name: FailJob :execrows
+UPDATE jobs
+SET status = 'failed', locked_until = NULL, last_error = sqlc.arg(last_error), updated_at = sqlc.arg(now)
+WHERE id = sqlc.arg(id) AND status = 'running' AND attempts = sqlc.arg(attempts);
+
+-- name: DeleteDoneJobs :execrows
+DELETE FROM jobs
+WHERE status = 'done' AND updated_at < sqlc.arg(cutoff);
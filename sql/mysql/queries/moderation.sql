@@ -0,0 +1,71 @@
+-- name: CreateModerationAction :execresult
+INSERT INTO moderation_actions (user_id, kind, reason, moderator_id, ends_at, created_at)
+VALUES (sqlc.arg(user_id), sqlc.arg(kind), sqlc.arg(reason), sqlc.arg(moderator_id), sqlc.arg(ends_at), sqlc.arg(created_at));
+
+-- name: GetModerationAction :one
+SELECT id, user_id, kind, reason, moderator_id, ends_at, lifted_at, lifted_by, created_at
+FROM moderation_actions
+WHERE id = sqlc.arg(id)
+LIMIT 1;
+
+-- name: LiftModerationAction :execrows
+UPDATE moderation_actions
+SET lifted_at = sqlc.arg(lifted_at), lifted_by = sqlc.arg(lifted_by)
+WHERE id = sqlc.arg(id) AND lifted_at IS NULL;
+
+-- name: GetActiveSuspension :one
+SELECT id, user_id, kind, reason, moderator_id, ends_at, lifted_at, lifted_by, created_at
+FROM moderation_actions
+WHERE user_id = sqlc.arg(user_id) AND kind = 'suspension' AND lifted_at IS NULL
+ORDER BY created_at DESC, id DESC
+LIMIT 1;
+
+-- name: ListModerationActions :many
+SELECT id, user_id, kind, reason, moderator_id, ends_at, lifted_at, lifted_by, created_at
+FROM moderation_actions
+WHERE user_id = sqlc.arg(user_id)
+ORDER BY created_at DESC, id DESC;
+
+-- name: ListLapsedSuspensions :many
+-- Lapsed suspensions ended by now but were not lifted yet.
+SELECT id, user_id, kind, reason, moderator_id, ends_at, lifted_at, lifted_by, created_at
+FROM moderation_actions
+WHERE kind = 'suspension' AND lifted_at IS NULL AND ends_at <= sqlc.arg(now)
+ORDER BY ends_at, id
+LIMIT ?;
+
+-- name: CreateModerationAppeal :execresult
+INSERT INTO moderation_appeals (action_id, user_id, statement, status, response, created_at)
+VALUES (sqlc.arg(action_id), sqlc.arg(user_id), sqlc.arg(statement), sqlc.arg(status), '', sqlc.arg(created_at));
+
+-- name: GetModerationAppeal :one
+SELECT id, action_id, user_id, statement, status, reviewer_id, response, created_at, decided_at
+FROM moderation_appeals
+WHERE id = sqlc.arg(id)
+LIMIT 1;
+
+-- name: DecideModerationAppeal :execrows
+UPDATE moderation_appeals
+SET status = sqlc.arg(status), reviewer_id = sqlc.arg(reviewer_id), response = sqlc.arg(response), decided_at = sqlc.arg(decided_at)
+WHERE id = sqlc.arg(id) AND status = 'pending';
+
+-- name: ListPendingModerationAppeals :many
+SELECT id, action_id, user_id, statement, status, reviewer_id, response, created_at, decided_at
+FROM moderation_appeals
+WHERE status = 'pending'
+ORDER BY created_at, id
+LIMIT ?;
+
+-- name: DeleteModerationActionsByUser :execrows
+DELETE FROM moderation_actions
+WHERE user_id = sqlc.arg(user_id);
+
+-- name: ListModerationAppealsByUser :many
+SELECT id, action_id, user_id, statement, status, reviewer_id, response, created_at, decided_at
+FROM moderation_appeals
+WHERE user_id = sqlc.arg(user_id)
+ORDER BY created_at DESC, id DESC;
+
+-- name: DeleteModerationAppealsByUser :execrows
+DELETE FROM moderation_appeals
+WHERE user_id = sqlc.arg(user_id);
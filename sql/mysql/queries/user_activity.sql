@@ -0,0 +1,23 @@
+-- name: RecordUserActivity :exec
+-- Entries of an event already recorded for the user are ignored.
+INSERT INTO user_activity (user_id, event_id, event_type, kind, summary, actor_id, occurred_at)
+VALUES (sqlc.arg(user_id), sqlc.arg(event_id), sqlc.arg(event_type), sqlc.arg(kind), sqlc.arg(summary), sqlc.arg(actor_id), sqlc.arg(occurred_at))
+ON DUPLICATE KEY UPDATE id = id;
+
+-- name: ListUserActivityPage :many
+-- The first page's keyset cursor is replaced by a bound beyond any stored value.
+SELECT id, user_id, event_id, event_type, kind, summary, actor_id, occurred_at
+FROM user_activity
+WHERE user_id = sqlc.arg(user_id)
+  AND (occurred_at < sqlc.arg(cursor_occurred_at)
+       OR (occurred_at = sqlc.arg(cursor_occurred_at) AND id < sqlc.arg(cursor_id)))
+ORDER BY occurred_at DESC, id DESC
+LIMIT ?;
+
+-- name: DeleteUserActivityBefore :execrows
+DELETE FROM user_activity
+WHERE occurred_at < sqlc.arg(cutoff);
+
+-- name: DeleteUserActivityByUser :execrows
+DELETE FROM user_activity
+WHERE user_id = sqlc.arg(user_id);
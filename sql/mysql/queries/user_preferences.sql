@@ -0,0 +1,23 @@
+-- name: GetUserPreferences :one
+SELECT user_id, preferences, updated_at
+FROM user_preferences
+WHERE user_id = sqlc.arg(user_id)
+LIMIT 1;
+
+-- name: SetUserPreference :exec
+-- Sets one preference; name must be a valid preference key, since it is
+-- quoted into a JSON path.
+INSERT INTO user_preferences (user_id, preferences, updated_at)
+VALUES (sqlc.arg(user_id), JSON_OBJECT(sqlc.arg(name), CAST(sqlc.arg(value) AS JSON)), sqlc.arg(updated_at))
+ON DUPLICATE KEY UPDATE
+    preferences = JSON_SET(preferences, CONCAT('$."', sqlc.arg(name), '"'), CAST(sqlc.arg(value) AS JSON)),
+    updated_at = VALUES(updated_at);
+
+-- name: RemoveUserPreference :exec
+UPDATE user_preferences
+SET preferences = JSON_REMOVE(preferences, CONCAT('$."', sqlc.arg(name), '"')), updated_at = sqlc.arg(updated_at)
+WHERE user_id = sqlc.arg(user_id);
+
+-- name: DeleteUserPreferences :execrows
+DELETE FROM user_preferences
+WHERE user_id = sqlc.arg(user_id);
@@ -0,0 +1,63 @@
+-- name: UpsertUserView :exec
+-- A view projected from an older version of the user is not stored, so
+-- views projected out of order never go back in time. Assignments are
+-- evaluated in order, so source_updated_at is replaced last.
+INSERT INTO user_read_model (
+    user_id, uuid, email, username, full_name, status, role, tags, is_verified,
+    search_text, created_at, source_updated_at, projected_at
+) VALUES (
+    sqlc.arg(user_id), sqlc.arg(uuid), sqlc.arg(email), sqlc.arg(username), sqlc.arg(full_name),
+    sqlc.arg(status), sqlc.arg(role), sqlc.arg(tags), sqlc.arg(is_verified),
+    sqlc.arg(search_text), sqlc.arg(created_at), sqlc.arg(source_updated_at), sqlc.arg(projected_at)
+)
+ON DUPLICATE KEY UPDATE
+    uuid = IF(source_updated_at <= VALUES(source_updated_at), VALUES(uuid), uuid),
+    email = IF(source_updated_at <= VALUES(source_updated_at), VALUES(email), email),
+    username = IF(source_updated_at <= VALUES(source_updated_at), VALUES(username), username),
+    full_name = IF(source_updated_at <= VALUES(source_updated_at), VALUES(full_name), full_name),
+    status = IF(source_updated_at <= VALUES(source_updated_at), VALUES(status), status),
+    role = IF(source_updated_at <= VALUES(source_updated_at), VALUES(role), role),
+    tags = IF(source_updated_at <= VALUES(source_updated_at), VALUES(tags), tags),
+    is_verified = IF(source_updated_at <= VALUES(source_updated_at), VALUES(is_verified), is_verified),
+    search_text = IF(source_updated_at <= VALUES(source_updated_at), VALUES(search_text), search_text),
+    created_at = IF(source_updated_at <= VALUES(source_updated_at), VALUES(created_at), created_at),
+    projected_at = IF(source_updated_at <= VALUES(source_updated_at), VALUES(projected_at), projected_at),
+    source_updated_at = GREATEST(source_updated_at, VALUES(source_updated_at));
+
+-- name: DeleteUserViews :exec
+DELETE FROM user_read_model WHERE user_id IN (sqlc.slice('ids'));
+
+-- name: ListUserViews :many
+-- Lists views matching a UserFilter, newest first with the user ID breaking
+-- ties. Empty sets match any view; a negative verified matches both states.
+-- Open time ranges are replaced by bounds beyond any stored value.
+-- Statuses, roles, tag sets and the query terms are JSON arrays of strings.
+SELECT user_id, uuid, email, username, full_name, status, role, tags, is_verified,
+       created_at, source_updated_at, projected_at
+FROM user_read_model
+WHERE (JSON_LENGTH(sqlc.arg(statuses)) = 0 OR JSON_CONTAINS(sqlc.arg(statuses), JSON_QUOTE(status)))
+  AND (JSON_LENGTH(sqlc.arg(roles)) = 0 OR JSON_CONTAINS(sqlc.arg(roles), JSON_QUOTE(role)))
+  AND (CAST(sqlc.arg(verified) AS SIGNED) < 0 OR is_verified = CAST(sqlc.arg(verified) AS SIGNED))
+  AND created_at > sqlc.arg(created_after)
+  AND created_at < sqlc.arg(created_before)
+  AND (JSON_LENGTH(sqlc.arg(tags_any)) = 0 OR JSON_OVERLAPS(tags, sqlc.arg(tags_any)))
+  AND JSON_CONTAINS(tags, sqlc.arg(tags_all))
+  AND NOT EXISTS (
+      SELECT 1 FROM JSON_TABLE(sqlc.arg(terms), '$[*]' COLUMNS (term VARCHAR(500) PATH '$')) AS terms
+      WHERE user_read_model.search_text NOT LIKE CONCAT('% ', terms.term, '%')
+  )
+ORDER BY created_at DESC, user_id DESC
+LIMIT ? OFFSET ?;
+
+-- name: GetOldestUnprojectedUserChange :one
+-- Returns the update time of the user changed longest ago whose change is
+-- not in the read model: a live user without a current view, or a deleted
+-- user whose view was not removed.
+SELECT users.updated_at
+FROM users
+LEFT JOIN user_read_model ON user_read_model.user_id = users.id
+WHERE (users.deleted_at IS NULL
+       AND (user_read_model.user_id IS NULL OR user_read_model.source_updated_at < users.updated_at))
+   OR (users.deleted_at IS NOT NULL AND user_read_model.user_id IS NOT NULL)
+ORDER BY users.updated_at
+LIMIT 1;
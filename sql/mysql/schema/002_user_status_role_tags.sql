@@ -0,0 +1,9 @@
+-- Status, role and tags for MySQL
+-- Tags are stored as a JSON array and expanded with JSON_TABLE for facets.
+
+ALTER TABLE users ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'active';
+ALTER TABLE users ADD COLUMN role VARCHAR(20) NOT NULL DEFAULT 'user';
+ALTER TABLE users ADD COLUMN tags JSON NULL;
+
+CREATE INDEX idx_users_status ON users(status);
+CREATE INDEX idx_users_role ON users(role);
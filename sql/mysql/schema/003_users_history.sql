@@ -0,0 +1,23 @@
+-- User history for MySQL
+-- sqlc cannot parse MySQL triggers, so the history is recorded application-side:
+-- run RecordUserHistory in the same transaction before every update/delete.
+
+CREATE TABLE users_history (
+    history_id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT UNSIGNED NOT NULL,
+    operation VARCHAR(10) NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    username VARCHAR(50) NOT NULL,
+    first_name VARCHAR(100) NOT NULL,
+    last_name VARCHAR(100) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    role VARCHAR(20) NOT NULL,
+    tags JSON NULL,
+    is_active BOOLEAN NULL,
+    is_verified BOOLEAN NULL,
+    profile_metadata JSON NULL,
+    valid_from TIMESTAMP NOT NULL,
+    valid_to TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_users_history_user_validity ON users_history(user_id, valid_from, valid_to);
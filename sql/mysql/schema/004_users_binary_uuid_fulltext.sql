@@ -0,0 +1,12 @@
+-- Binary UUIDs and full-text search for MySQL
+-- UUIDs move from CHAR(36) to BINARY(16), halving the unique index size;
+-- the FULLTEXT index backs SearchUsers.
+
+ALTER TABLE users ADD COLUMN uuid_bin BINARY(16) NULL;
+UPDATE users SET uuid_bin = UUID_TO_BIN(uuid);
+ALTER TABLE users DROP COLUMN uuid;
+ALTER TABLE users RENAME COLUMN uuid_bin TO uuid;
+ALTER TABLE users MODIFY uuid BINARY(16) NOT NULL;
+
+CREATE UNIQUE INDEX idx_users_uuid ON users(uuid);
+CREATE FULLTEXT INDEX idx_users_fulltext ON users(email, username, first_name, last_name);
@@ -0,0 +1,17 @@
+-- Audit log for MySQL
+-- One row per mutating operation, written by the service layer.
+-- actor_id is NULL for operations performed by the system.
+
+CREATE TABLE audit_log (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    actor_id BIGINT UNSIGNED NULL,
+    user_id BIGINT UNSIGNED NOT NULL,
+    action VARCHAR(50) NOT NULL,
+    changes JSON NOT NULL,
+    ip_address VARCHAR(45) NOT NULL DEFAULT '',
+    created_at TIMESTAMP(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6)
+);
+
+CREATE INDEX idx_audit_log_user_created ON audit_log(user_id, created_at);
+CREATE INDEX idx_audit_log_actor_created ON audit_log(actor_id, created_at);
+CREATE INDEX idx_audit_log_action_created ON audit_log(action, created_at);
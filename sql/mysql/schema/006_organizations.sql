@@ -0,0 +1,29 @@
+-- Organizations and memberships for MySQL
+-- A membership row with status 'invited' is a pending invitation.
+
+CREATE TABLE organizations (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    uuid BINARY(16) NOT NULL UNIQUE,
+    name VARCHAR(100) NOT NULL,
+    slug VARCHAR(50) NOT NULL UNIQUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE organization_members (
+    organization_id BIGINT UNSIGNED NOT NULL,
+    user_id BIGINT UNSIGNED NOT NULL,
+    role VARCHAR(20) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    invited_by BIGINT UNSIGNED NULL,
+    invited_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    joined_at TIMESTAMP NULL,
+    PRIMARY KEY (organization_id, user_id),
+    CONSTRAINT fk_organization_members_org FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE,
+    CONSTRAINT fk_organization_members_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_organization_members_inviter FOREIGN KEY (invited_by) REFERENCES users(id) ON DELETE SET NULL,
+    CONSTRAINT chk_organization_members_role CHECK (role IN ('user', 'moderator', 'admin')),
+    CONSTRAINT chk_organization_members_status CHECK (status IN ('invited', 'active'))
+);
+
+CREATE INDEX idx_organization_members_user ON organization_members(user_id, status);
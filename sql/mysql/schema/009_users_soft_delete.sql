@@ -0,0 +1,8 @@
+-- Soft delete for MySQL
+-- deleted_at marks deleted users: lookups skip them until they are restored or
+-- purged. is_active stays in step so that users_history records deletions.
+ALTER TABLE users ADD COLUMN deleted_at TIMESTAMP NULL DEFAULT NULL;
+
+UPDATE users SET deleted_at = COALESCE(updated_at, CURRENT_TIMESTAMP) WHERE is_active = FALSE;
+
+CREATE INDEX idx_users_deleted_at ON users(deleted_at);
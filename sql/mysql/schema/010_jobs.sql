@@ -0,0 +1,23 @@
+-- Background jobs for MySQL
+-- A job runs the handler registered under name with its JSON payload. Workers
+-- poll for due jobs and claim each with a conditional update, leasing it until
+-- locked_until; a running job whose lease expired is claimed again. attempts
+-- counts the claims and doubles as the version the conditional updates compare.
+
+CREATE TABLE jobs (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    payload JSON NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INT NOT NULL DEFAULT 0,
+    max_attempts INT NOT NULL DEFAULT 5,
+    run_at TIMESTAMP(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    locked_until TIMESTAMP(6) NULL,
+    last_error TEXT NOT NULL,
+    created_at TIMESTAMP(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    updated_at TIMESTAMP(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    CONSTRAINT chk_jobs_status CHECK (status IN ('pending', 'running', 'done', 'failed'))
+);
+
+CREATE INDEX idx_jobs_status_run_at ON jobs(status, run_at);
+CREATE INDEX idx_jobs_status_locked_until ON jobs(status, locked_until);
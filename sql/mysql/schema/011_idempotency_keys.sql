@@ -0,0 +1,17 @@
+-- Idempotency keys for MySQL
+-- Records the outcome of a write made with a client's idempotency key, so
+-- that retries of the write replay its response instead of repeating it. A
+-- record is pending until completed_at is set; expires_at frees the key of a
+-- pending write after a short lock and of a completed one after the TTL.
+
+CREATE TABLE idempotency_keys (
+    idempotency_key VARCHAR(255) NOT NULL PRIMARY KEY,
+    operation VARCHAR(100) NOT NULL,
+    request_hash CHAR(64) NOT NULL,
+    response MEDIUMTEXT NOT NULL,
+    created_at TIMESTAMP(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    completed_at TIMESTAMP(6) NULL,
+    expires_at TIMESTAMP(6) NOT NULL
+);
+
+CREATE INDEX idx_idempotency_keys_expires_at ON idempotency_keys(expires_at);
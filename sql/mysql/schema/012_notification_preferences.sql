@@ -0,0 +1,14 @@
+-- Notification preferences for MySQL
+-- Opts users in to or out of a topic on a channel. Channels and topics without
+-- a row use the application defaults; target is the URL of webhooks.
+
+CREATE TABLE notification_preferences (
+    user_id BIGINT UNSIGNED NOT NULL,
+    channel VARCHAR(20) NOT NULL,
+    topic VARCHAR(20) NOT NULL,
+    enabled BOOLEAN NOT NULL,
+    target VARCHAR(2048) NOT NULL DEFAULT '',
+    updated_at TIMESTAMP(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    PRIMARY KEY (user_id, channel, topic),
+    CONSTRAINT fk_notification_preferences_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
@@ -0,0 +1,21 @@
+-- Identity change history for MySQL
+-- Records changes of the email address and username of users. Email changes
+-- stay pending until the new address confirms them; applied changes can be
+-- reverted with their token until expires_at.
+
+CREATE TABLE identity_changes (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT UNSIGNED NOT NULL,
+    field VARCHAR(20) NOT NULL,
+    old_value VARCHAR(255) NOT NULL,
+    new_value VARCHAR(255) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    token CHAR(36) NOT NULL,
+    expires_at TIMESTAMP(6) NOT NULL,
+    created_at TIMESTAMP(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    updated_at TIMESTAMP(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    CONSTRAINT uq_identity_changes_token UNIQUE (token),
+    CONSTRAINT fk_identity_changes_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_identity_changes_user_created ON identity_changes(user_id, created_at);
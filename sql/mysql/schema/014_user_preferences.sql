@@ -0,0 +1,10 @@
+-- User preferences for MySQL
+-- One JSON object per user mapping preference keys like ui.theme to values.
+-- Preferences without a key use the defaults of the application's schema.
+
+CREATE TABLE user_preferences (
+    user_id BIGINT UNSIGNED NOT NULL PRIMARY KEY,
+    preferences JSON NOT NULL,
+    updated_at TIMESTAMP(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    CONSTRAINT fk_user_preferences_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
@@ -0,0 +1,20 @@
+-- Activity feeds for MySQL
+-- Human-readable entries projected from domain events, one per user and
+-- event. Entries older than the retention are pruned by the cleanup job.
+-- actor_id is 0 when the user caused the activity.
+
+CREATE TABLE user_activity (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT UNSIGNED NOT NULL,
+    event_id BIGINT NOT NULL,
+    event_type VARCHAR(100) NOT NULL,
+    kind VARCHAR(20) NOT NULL,
+    summary VARCHAR(255) NOT NULL,
+    actor_id BIGINT UNSIGNED NOT NULL DEFAULT 0,
+    occurred_at TIMESTAMP(6) NOT NULL,
+    CONSTRAINT uq_user_activity_event UNIQUE (user_id, event_id),
+    CONSTRAINT fk_user_activity_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_user_activity_user_occurred ON user_activity(user_id, occurred_at, id);
+CREATE INDEX idx_user_activity_occurred ON user_activity(occurred_at);
@@ -0,0 +1,18 @@
+-- Login history for MySQL
+-- One row per login attempt, successful or failed, for security pages and
+-- abuse detection. user_id is NULL for attempts on unknown accounts; failure
+-- is empty for successful attempts. Rows are removed with their user.
+
+CREATE TABLE login_history (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT UNSIGNED,
+    ip_address VARCHAR(45) NOT NULL DEFAULT '',
+    user_agent TEXT NOT NULL,
+    method VARCHAR(50) NOT NULL,
+    failure VARCHAR(50) NOT NULL DEFAULT '',
+    created_at TIMESTAMP(6) NOT NULL,
+    CONSTRAINT fk_login_history_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_login_history_user_created ON login_history(user_id, created_at);
+CREATE INDEX idx_login_history_ip_created ON login_history(ip_address, created_at);
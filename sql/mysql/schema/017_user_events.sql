@@ -0,0 +1,25 @@
+-- User event store for MySQL
+-- The event streams of event-sourced users, numbered from 1 per user, and
+-- snapshots of their state every few events so that loading a user replays
+-- only the events after its latest snapshot. The primary keys reject a
+-- second event of the same version, so concurrent writers conflict.
+
+CREATE TABLE user_events (
+    user_id BIGINT UNSIGNED NOT NULL,
+    version BIGINT NOT NULL,
+    event_type VARCHAR(50) NOT NULL,
+    data JSON NOT NULL,
+    occurred_at TIMESTAMP(6) NOT NULL,
+    PRIMARY KEY (user_id, version),
+    CONSTRAINT fk_user_events_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE user_snapshots (
+    user_id BIGINT UNSIGNED NOT NULL,
+    version BIGINT NOT NULL,
+    deleted BOOLEAN NOT NULL DEFAULT FALSE,
+    state JSON NOT NULL,
+    created_at TIMESTAMP(6) NOT NULL,
+    PRIMARY KEY (user_id, version),
+    CONSTRAINT fk_user_snapshots_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
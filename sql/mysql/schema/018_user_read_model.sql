@@ -0,0 +1,27 @@
+-- User read model for MySQL
+-- One denormalized view per live user that listings and searches are served
+-- from, projected from the domain events. search_text holds the lowercase
+-- words of the email, username and full name, each preceded by a space, so
+-- that a query term matches as a word prefix with LIKE '% term%'.
+-- source_updated_at is the update time of the projected user, which the lag
+-- is measured against.
+
+CREATE TABLE user_read_model (
+    user_id BIGINT UNSIGNED NOT NULL PRIMARY KEY,
+    uuid CHAR(36) NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    username VARCHAR(50) NOT NULL,
+    full_name VARCHAR(201) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    role VARCHAR(20) NOT NULL,
+    tags JSON NOT NULL,
+    is_verified BOOLEAN NOT NULL DEFAULT FALSE,
+    search_text VARCHAR(1024) NOT NULL,
+    created_at TIMESTAMP(6) NOT NULL,
+    source_updated_at TIMESTAMP(6) NOT NULL,
+    projected_at TIMESTAMP(6) NOT NULL,
+    CONSTRAINT fk_user_read_model_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_user_read_model_created ON user_read_model(created_at DESC, user_id DESC);
+CREATE INDEX idx_user_read_model_status ON user_read_model(status);
@@ -0,0 +1,13 @@
+-- Canonical usernames for MySQL
+-- username_canonical is the username in Unicode NFKC with its case folded, as
+-- entities.Username.Canonical computes it. It is unique, so that usernames
+-- differing only by case or compatibility characters cannot coexist, and
+-- usernames are looked up by it. The binary collation leaves equality to the
+-- canonical form. Usernames so far are ASCII, so LOWER is their canonical
+-- form; the migration fails if two of them differ only by case.
+ALTER TABLE users ADD COLUMN username_canonical VARCHAR(255)
+    CHARACTER SET utf8mb4 COLLATE utf8mb4_bin NOT NULL DEFAULT '';
+
+UPDATE users SET username_canonical = LOWER(username);
+
+CREATE UNIQUE INDEX idx_users_username_canonical ON users(username_canonical);
@@ -0,0 +1,40 @@
+-- Moderation for MySQL
+-- Records the flags and suspensions moderators impose on users and the
+-- appeals of suspensions. Actions stay in effect until lifted_at is set;
+-- suspensions with an ends_at are lifted once it passes. moderator_id and
+-- lifted_by are kept when the moderator is deleted and are 0 for the system.
+
+CREATE TABLE moderation_actions (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT UNSIGNED NOT NULL,
+    kind VARCHAR(20) NOT NULL,
+    reason VARCHAR(500) NOT NULL,
+    moderator_id BIGINT UNSIGNED NOT NULL DEFAULT 0,
+    ends_at TIMESTAMP(6) NULL,
+    lifted_at TIMESTAMP(6) NULL,
+    lifted_by BIGINT UNSIGNED NOT NULL DEFAULT 0,
+    created_at TIMESTAMP(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    CONSTRAINT fk_moderation_actions_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_moderation_actions_user_created ON moderation_actions(user_id, created_at);
+CREATE INDEX idx_moderation_actions_ends_at ON moderation_actions(ends_at);
+
+-- Each suspension can be appealed once.
+CREATE TABLE moderation_appeals (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    action_id BIGINT UNSIGNED NOT NULL,
+    user_id BIGINT UNSIGNED NOT NULL,
+    statement TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    reviewer_id BIGINT UNSIGNED NOT NULL DEFAULT 0,
+    response TEXT NOT NULL,
+    created_at TIMESTAMP(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    decided_at TIMESTAMP(6) NULL,
+    CONSTRAINT uq_moderation_appeals_action UNIQUE (action_id),
+    CONSTRAINT fk_moderation_appeals_action FOREIGN KEY (action_id)
+        REFERENCES moderation_actions(id) ON DELETE CASCADE,
+    CONSTRAINT fk_moderation_appeals_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_moderation_appeals_status_created ON moderation_appeals(status, created_at);
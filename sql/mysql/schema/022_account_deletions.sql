@@ -0,0 +1,14 @@
+-- Account deletions for MySQL
+-- Holds the accounts whose deletion was requested, until the deletion is
+-- canceled or the account is erased once scheduled_for passes. requested_by
+-- is kept when the requester is deleted.
+
+CREATE TABLE account_deletions (
+    user_id BIGINT UNSIGNED NOT NULL PRIMARY KEY,
+    requested_by BIGINT UNSIGNED NOT NULL DEFAULT 0,
+    requested_at TIMESTAMP(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    scheduled_for TIMESTAMP(6) NOT NULL,
+    CONSTRAINT fk_account_deletions_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_account_deletions_scheduled_for ON account_deletions(scheduled_for);
@@ -0,0 +1,43 @@
+-- User sessions for MySQL
+-- One row per session, removed with its user. device_info and
+-- rotated_refresh_tokens are JSON; the location columns are empty when the
+-- IP address could not be resolved. impersonator_id is kept when the
+-- impersonator is deleted, so the session stays attributed to them.
+--
+-- session_refresh_tokens indexes every refresh token issued to a session,
+-- the current one and the rotated ones, so that a rotated token presented
+-- again is traced back to its session.
+
+CREATE TABLE user_sessions (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT UNSIGNED NOT NULL,
+    session_token CHAR(36) NOT NULL,
+    device_info JSON NOT NULL,
+    ip_address VARCHAR(45) NOT NULL DEFAULT '',
+    user_agent TEXT NOT NULL,
+    country VARCHAR(2) NOT NULL DEFAULT '',
+    region VARCHAR(10) NOT NULL DEFAULT '',
+    city VARCHAR(255) NOT NULL DEFAULT '',
+    organization_id BIGINT UNSIGNED,
+    impersonator_id BIGINT UNSIGNED,
+    refresh_token CHAR(36) NOT NULL,
+    refresh_expires_at TIMESTAMP(6) NULL,
+    rotated_refresh_tokens JSON NOT NULL,
+    created_at TIMESTAMP(6) NOT NULL,
+    expires_at TIMESTAMP(6) NOT NULL,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    CONSTRAINT uq_user_sessions_token UNIQUE (session_token),
+    CONSTRAINT fk_user_sessions_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_user_sessions_organization FOREIGN KEY (organization_id)
+        REFERENCES organizations(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_user_sessions_user ON user_sessions(user_id, is_active);
+CREATE INDEX idx_user_sessions_expires_at ON user_sessions(expires_at);
+
+CREATE TABLE session_refresh_tokens (
+    refresh_token CHAR(36) NOT NULL PRIMARY KEY,
+    session_id BIGINT UNSIGNED NOT NULL,
+    CONSTRAINT fk_session_refresh_tokens_session FOREIGN KEY (session_id)
+        REFERENCES user_sessions(id) ON DELETE CASCADE
+);
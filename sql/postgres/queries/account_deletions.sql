@@ -0,0 +1,20 @@
+-- name: CreateAccountDeletion :exec
+INSERT INTO account_deletions (user_id, requested_by, requested_at, scheduled_for)
+VALUES (sqlc.arg(user_id), sqlc.arg(requested_by), sqlc.arg(requested_at), sqlc.arg(scheduled_for));
+
+-- name: GetAccountDeletion :one
+SELECT user_id, requested_by, requested_at, scheduled_for
+FROM account_deletions
+WHERE user_id = sqlc.arg(user_id)
+LIMIT 1;
+
+-- name: DeleteAccountDeletion :execrows
+DELETE FROM account_deletions
+WHERE user_id = sqlc.arg(user_id);
+
+-- name: ListDueAccountDeletions :many
+SELECT user_id, requested_by, requested_at, scheduled_for
+FROM account_deletions
+WHERE scheduled_for <= sqlc.arg(now)::timestamptz
+ORDER BY scheduled_for, user_id
+LIMIT sqlc.arg(row_limit);
@@ -0,0 +1,34 @@
+-- name: CreateChangefeedSlot :exec
+-- Creates the logical replication slot decoded by wal2json unless it exists.
+SELECT pg_create_logical_replication_slot(sqlc.arg(slot_name)::text, 'wal2json')
+WHERE NOT EXISTS (
+    SELECT 1 FROM pg_replication_slots WHERE slot_name = sqlc.arg(slot_name)::text
+);
+
+-- name: PeekChangefeedChanges :many
+-- Reads the changes of a slot without consuming them, stopping after the
+-- transaction that reaches row_limit. tables is a comma-separated list of
+-- qualified table names.
+-- lint:ignore missing-limit
+SELECT lsn::text AS lsn, data::text AS data
+FROM pg_logical_slot_peek_changes(
+    sqlc.arg(slot_name)::text, NULL, sqlc.arg(row_limit)::int,
+    'format-version', '2', 'include-transaction', 'true', 'add-tables', sqlc.arg(tables)::text
+);
+
+-- name: AdvanceChangefeedSlot :exec
+-- Consumes the changes of a slot up to upto_lsn.
+SELECT pg_replication_slot_advance(sqlc.arg(slot_name)::text, sqlc.arg(upto_lsn)::text::pg_lsn);
+
+-- name: GetChangefeedCheckpoint :one
+SELECT lsn
+FROM changefeed_checkpoints
+WHERE slot_name = sqlc.arg(slot_name)
+LIMIT 1;
+
+-- name: SaveChangefeedCheckpoint :exec
+INSERT INTO changefeed_checkpoints (slot_name, lsn, updated_at)
+VALUES (sqlc.arg(slot_name), sqlc.arg(lsn), sqlc.arg(updated_at))
+ON CONFLICT (slot_name) DO UPDATE SET
+    lsn = EXCLUDED.lsn,
+    updated_at = EXCLUDED.updated_at;
@@ -0,0 +1,10 @@
+-- name: ListFeatureFlags :many
+SELECT name, enabled, updated_at
+FROM feature_flags
+ORDER BY name;
+
+-- name: SetFeatureFlag :exec
+INSERT INTO feature_flags (name, enabled, updated_at)
+VALUES (sqlc.arg(name), sqlc.arg(enabled), sqlc.arg(updated_at))
+ON CONFLICT (name) DO UPDATE
+SET enabled = excluded.enabled, updated_at = excluded.updated_at;
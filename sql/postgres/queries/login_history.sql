@@ -0,0 +1,25 @@
+-- name: RecordLoginAttempt :one
+INSERT INTO login_history (user_id, ip_address, user_agent, method, failure, created_at)
+VALUES (sqlc.narg(user_id), sqlc.arg(ip_address), sqlc.arg(user_agent), sqlc.arg(method), sqlc.arg(failure), sqlc.arg(created_at))
+RETURNING id;
+
+-- name: ListLoginAttemptsByUser :many
+SELECT id, user_id, ip_address, user_agent, method, failure, created_at
+FROM login_history
+WHERE user_id = sqlc.arg(user_id)
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: ListLoginFailuresByIP :many
+-- Includes the failures of unknown accounts, whose user_id is NULL.
+SELECT id, user_id, ip_address, user_agent, method, failure, created_at
+FROM login_history
+WHERE ip_address = sqlc.arg(ip_address)
+  AND failure <> ''
+  AND created_at >= sqlc.arg(since)
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: DeleteLoginAttemptsByUser :execrows
+DELETE FROM login_history
+WHERE user_id = sqlc.arg(user_id);
@@ -1,23 +1,42 @@
 -- name: CreateUser :one
 INSERT INTO users (
-    uuid, email, username, password_hash, 
-    first_name, last_name, profile_metadata, is_active
+    uuid, email, username, password_hash,
+    first_name, last_name, profile_metadata, is_active,
+    is_verified, status, role, tags, created_at, updated_at,
+    username_canonical
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15
 )
 RETURNING *;
 
+-- name: CopyUsers :copyfrom
+-- Bulk insert through the COPY protocol; IDs are looked up by UUID afterwards.
+INSERT INTO users (
+    uuid, email, username, password_hash,
+    first_name, last_name, profile_metadata, is_active,
+    is_verified, status, role, tags, created_at, updated_at,
+    username_canonical
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15
+);
+
+-- name: GetUserIDsByUUIDs :many
+SELECT id, uuid FROM users WHERE uuid = ANY(sqlc.arg(uuids)::uuid[]);
+
 -- name: GetUserByID :one
-SELECT * FROM users WHERE id = $1 AND is_active = TRUE;
+SELECT * FROM users WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: GetUsersByIDs :many
+SELECT * FROM users WHERE id = ANY(sqlc.arg(ids)::bigint[]) AND deleted_at IS NULL;
 
 -- name: GetUserByUUID :one
-SELECT * FROM users WHERE uuid = $1 AND is_active = TRUE;
+SELECT * FROM users WHERE uuid = $1 AND deleted_at IS NULL;
 
 -- name: GetUserByEmail :one
-SELECT * FROM users WHERE email = $1 AND is_active = TRUE;
+SELECT * FROM users WHERE email = $1 AND deleted_at IS NULL;
 
 -- name: GetUserByUsername :one
-SELECT * FROM users WHERE username = $1 AND is_active = TRUE;
+SELECT * FROM users WHERE username_canonical = $1 AND deleted_at IS NULL;
 
 -- name: UpdateUser :one
 UPDATE users 
@@ -28,8 +47,14 @@ SET
     last_name = COALESCE($5, last_name),
     profile_metadata = COALESCE($6, profile_metadata),
     is_active = COALESCE($7, is_active),
-    is_verified = COALESCE($8, is_verified)
-WHERE id = $1
+    is_verified = COALESCE($8, is_verified),
+    status = COALESCE(sqlc.narg(status), status),
+    role = COALESCE(sqlc.narg(role), role),
+    tags = COALESCE(sqlc.narg(tags), tags),
+    last_login_at = COALESCE(sqlc.narg(last_login_at), last_login_at),
+    username_canonical = sqlc.arg(username_canonical),
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND deleted_at IS NULL
 RETURNING *;
 
 -- name: UpdatePassword :exec
@@ -38,18 +63,33 @@ SET password_hash = $2, updated_at = CURRENT_TIMESTAMP
 WHERE id = $1;
 
 -- name: SoftDeleteUser :exec
-UPDATE users 
-SET is_active = FALSE, updated_at = CURRENT_TIMESTAMP
-WHERE id = $1;
+UPDATE users
+SET is_active = FALSE, deleted_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: RestoreUser :execrows
+UPDATE users
+SET is_active = TRUE, deleted_at = NULL, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND deleted_at IS NOT NULL;
+
+-- name: PurgeDeletedUsers :execrows
+-- Permanently removes users soft deleted before the cutoff.
+DELETE FROM users
+WHERE deleted_at IS NOT NULL AND deleted_at < sqlc.arg(cutoff)::timestamptz;
+
+-- name: SoftDeleteUsers :execrows
+UPDATE users
+SET is_active = FALSE, deleted_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+WHERE id = ANY(sqlc.arg(ids)::bigint[]) AND deleted_at IS NULL;
 
 -- name: ListUsers :many
 SELECT * FROM users 
-WHERE is_active = TRUE 
+WHERE deleted_at IS NULL 
 ORDER BY created_at DESC
 LIMIT $1 OFFSET $2;
 
 -- name: CountActiveUsers :one
-SELECT COUNT(*) FROM users WHERE is_active = TRUE;
+SELECT COUNT(*) FROM users WHERE deleted_at IS NULL;
 
 -- name: UpdateLastLogin :exec
 UPDATE users 
@@ -64,7 +104,159 @@ WHERE uuid = $1;
 -- name: GetUserStats :one
 SELECT 
     COUNT(*) as total_users,
-    COUNT(*) FILTER (WHERE is_active = TRUE) as active_users,
+    COUNT(*) FILTER (WHERE deleted_at IS NULL) as active_users,
     COUNT(*) FILTER (WHERE is_verified = TRUE) as verified_users,
-    COUNT(*) FILTER (WHERE last_login_at IS NOT NULL) as users_with_logins
+    COUNT(*) FILTER (WHERE last_login_at IS NOT NULL) as users_with_logins,
+    COUNT(*) FILTER (WHERE status = 'inactive') as inactive_users,
+    COUNT(*) FILTER (WHERE status = 'suspended') as suspended_users,
+    COUNT(*) FILTER (WHERE created_at >= NOW() - INTERVAL '30 days') as new_users_30d,
+    COUNT(*) FILTER (WHERE created_at >= NOW() - INTERVAL '7 days') as new_users_7d
 FROM users;
+
+-- name: FilterUsers :many
+-- Lists users matching a UserFilter, newest first with the ID breaking ties.
+-- Empty sets and an empty query match any user; a negative verified matches
+-- both states. Open time ranges and the first page's keyset cursor are
+-- replaced by bounds beyond any stored value.
+-- The query is a to_tsquery expression over users_search_document.
+SELECT * FROM users
+WHERE deleted_at IS NULL
+  AND (cardinality(sqlc.arg(statuses)::text[]) = 0 OR status = ANY(sqlc.arg(statuses)::text[]))
+  AND (cardinality(sqlc.arg(roles)::text[]) = 0 OR role = ANY(sqlc.arg(roles)::text[]))
+  AND (sqlc.arg(verified)::int < 0 OR is_verified = (sqlc.arg(verified)::int = 1))
+  AND created_at > sqlc.arg(created_after)::timestamptz
+  AND created_at < sqlc.arg(created_before)::timestamptz
+  AND (cardinality(sqlc.arg(tags_any)::text[]) = 0 OR tags && sqlc.arg(tags_any)::text[])
+  AND tags @> sqlc.arg(tags_all)::text[]
+  AND (sqlc.arg(query)::text = ''
+       OR users_search_document(email, username, first_name, last_name)
+          @@ to_tsquery('simple', sqlc.arg(query)::text))
+  AND (created_at, id) < (sqlc.arg(cursor_created_at)::timestamptz, sqlc.arg(cursor_id)::bigint)
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(row_limit)::int OFFSET sqlc.arg(row_offset)::int;
+
+-- name: CountUsersByStatus :many
+SELECT status, COUNT(*) AS total
+FROM users
+WHERE deleted_at IS NULL
+GROUP BY status;
+
+-- name: UpdateUserStatus :exec
+UPDATE users
+SET status = $2, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: UpdateUsersStatus :execrows
+UPDATE users
+SET status = sqlc.arg(status), updated_at = CURRENT_TIMESTAMP
+WHERE id = ANY(sqlc.arg(ids)::bigint[]) AND deleted_at IS NULL;
+
+-- name: UpdateUserRole :exec
+UPDATE users
+SET role = $2, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: MarkUserVerified :exec
+UPDATE users
+SET is_verified = TRUE, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: SearchUsersWithFacets :many
+-- Returns one page of matching users together with facet counts computed
+-- over every match. Facets are JSONB columns repeated on each row; a single
+-- row with NULL user columns is returned when the page is empty.
+WITH matched AS (
+    SELECT id, uuid, email, username, first_name, last_name, status, role, tags, created_at
+    FROM users
+    WHERE deleted_at IS NULL
+      AND (
+          email ILIKE '%' || sqlc.arg(query)::text || '%'
+          OR username ILIKE '%' || sqlc.arg(query)::text || '%'
+          OR first_name ILIKE '%' || sqlc.arg(query)::text || '%'
+          OR last_name ILIKE '%' || sqlc.arg(query)::text || '%'
+      )
+),
+status_facets AS (
+    SELECT COALESCE(jsonb_object_agg(status, total), '{}'::jsonb) AS counts
+    FROM (SELECT status, COUNT(*) AS total FROM matched GROUP BY status) AS s
+),
+role_facets AS (
+    SELECT COALESCE(jsonb_object_agg(role, total), '{}'::jsonb) AS counts
+    FROM (SELECT role, COUNT(*) AS total FROM matched GROUP BY role) AS r
+),
+tag_facets AS (
+    SELECT COALESCE(jsonb_agg(jsonb_build_array(tag, total) ORDER BY total DESC, tag), '[]'::jsonb) AS counts
+    FROM (
+        SELECT tag, COUNT(*) AS total
+        FROM matched, unnest(matched.tags) AS tag
+        GROUP BY tag
+        ORDER BY total DESC, tag
+        LIMIT sqlc.arg(tag_limit)::int
+    ) AS t
+),
+page AS (
+    SELECT id, uuid, email, username, first_name, last_name, status, role, tags, created_at
+    FROM matched
+    WHERE sqlc.arg(status)::text = '' OR status = sqlc.arg(status)::text
+    ORDER BY created_at DESC
+    LIMIT sqlc.arg(result_limit)::int
+)
+SELECT
+    page.id, page.uuid, page.email, page.username, page.first_name, page.last_name,
+    page.status, page.role, page.tags, page.created_at,
+    status_facets.counts::jsonb AS status_facets,
+    role_facets.counts::jsonb AS role_facets,
+    tag_facets.counts::jsonb AS tag_facets
+FROM status_facets
+CROSS JOIN role_facets
+CROSS JOIN tag_facets
+LEFT JOIN page ON TRUE;
+
+-- name: GetUserByIDForUpdate :one
+-- Locks the user row until the surrounding transaction ends.
+SELECT * FROM users WHERE id = $1 AND deleted_at IS NULL LIMIT 1 FOR UPDATE;
+
+-- name: ClaimUsersByStatus :many
+-- Work-queue claim: locks the oldest users in a status, skipping rows
+-- already claimed by concurrent transactions.
+SELECT * FROM users
+WHERE status = sqlc.arg(status)::text AND deleted_at IS NULL
+ORDER BY updated_at, id
+LIMIT sqlc.arg(claim_limit)::int
+FOR UPDATE SKIP LOCKED;
+
+-- name: GetUserAsOf :one
+-- Returns the version of a user that was valid at the given time, preferring
+-- archived versions over the current row.
+SELECT user_id, operation, email, username, first_name, last_name, status, role, tags,
+       is_active, is_verified, valid_from, valid_to
+FROM (
+    SELECT u.id AS user_id, 'current' AS operation, u.email, u.username, u.first_name, u.last_name,
+           u.status, u.role, u.tags, u.is_active, u.is_verified,
+           COALESCE(u.updated_at, u.created_at) AS valid_from,
+           NULL::timestamptz AS valid_to, 1 AS is_current
+    FROM users u
+    WHERE u.id = sqlc.arg(user_id)::bigint
+      AND COALESCE(u.updated_at, u.created_at) <= sqlc.arg(as_of)::timestamptz
+    UNION ALL
+    SELECT h.user_id, h.operation, h.email, h.username, h.first_name, h.last_name,
+           h.status, h.role, h.tags, h.is_active, h.is_verified,
+           h.valid_from, h.valid_to, 0
+    FROM users_history h
+    WHERE h.user_id = sqlc.arg(user_id)::bigint
+      AND h.valid_from <= sqlc.arg(as_of)::timestamptz
+      AND h.valid_to > sqlc.arg(as_of)::timestamptz
+) AS versions
+ORDER BY is_current, valid_from DESC
+LIMIT 1;
+
+-- name: ListUserHistory :many
+SELECT * FROM users_history
+WHERE user_id = $1
+ORDER BY valid_from DESC
+LIMIT $2;
+
+-- name: DeleteUserHistory :execrows
+-- Removes every archived version of a user.
+DELETE FROM users_history
+WHERE user_id = sqlc.arg(user_id);
@@ -0,0 +1,18 @@
+-- name: GetUserPreferences :one
+SELECT user_id, preferences, updated_at
+FROM user_preferences
+WHERE user_id = sqlc.arg(user_id)
+LIMIT 1;
+
+-- name: PatchUserPreferences :one
+-- Merges the set object into the stored preferences and removes the removed keys.
+INSERT INTO user_preferences (user_id, preferences, updated_at)
+VALUES (sqlc.arg(user_id), sqlc.arg(set)::jsonb, sqlc.arg(updated_at))
+ON CONFLICT (user_id) DO UPDATE
+SET preferences = (user_preferences.preferences || excluded.preferences) - sqlc.arg(removed)::text[],
+    updated_at = excluded.updated_at
+RETURNING user_id, preferences, updated_at;
+
+-- name: DeleteUserPreferences :execrows
+DELETE FROM user_preferences
+WHERE user_id = sqlc.arg(user_id);
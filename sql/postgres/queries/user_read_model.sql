@@ -0,0 +1,54 @@
+-- name: UpsertUserView :exec
+-- A view projected from an older version of the user is not stored, so
+-- views projected out of order never go back in time.
+INSERT INTO user_read_model (
+    user_id, uuid, email, username, full_name, status, role, tags, is_verified,
+    search_text, created_at, source_updated_at, projected_at
+) VALUES (
+    sqlc.arg(user_id), sqlc.arg(uuid), sqlc.arg(email), sqlc.arg(username), sqlc.arg(full_name),
+    sqlc.arg(status), sqlc.arg(role), sqlc.arg(tags), sqlc.arg(is_verified),
+    sqlc.arg(search_text), sqlc.arg(created_at), sqlc.arg(source_updated_at), sqlc.arg(projected_at)
+)
+ON CONFLICT (user_id) DO UPDATE
+SET uuid = excluded.uuid, email = excluded.email, username = excluded.username,
+    full_name = excluded.full_name, status = excluded.status, role = excluded.role, tags = excluded.tags,
+    is_verified = excluded.is_verified, search_text = excluded.search_text, created_at = excluded.created_at,
+    source_updated_at = excluded.source_updated_at, projected_at = excluded.projected_at
+WHERE user_read_model.source_updated_at <= excluded.source_updated_at;
+
+-- name: DeleteUserViews :exec
+DELETE FROM user_read_model WHERE user_id = ANY(sqlc.arg(ids)::bigint[]);
+
+-- name: ListUserViews :many
+-- Lists views matching a UserFilter, newest first with the user ID breaking
+-- ties. Empty sets match any view; a negative verified matches both states.
+-- Open time ranges are replaced by bounds beyond any stored value.
+SELECT user_id, uuid, email, username, full_name, status, role, tags, is_verified,
+       created_at, source_updated_at, projected_at
+FROM user_read_model
+WHERE (cardinality(sqlc.arg(statuses)::text[]) = 0 OR status = ANY(sqlc.arg(statuses)::text[]))
+  AND (cardinality(sqlc.arg(roles)::text[]) = 0 OR role = ANY(sqlc.arg(roles)::text[]))
+  AND (sqlc.arg(verified)::int < 0 OR is_verified = (sqlc.arg(verified)::int = 1))
+  AND created_at > sqlc.arg(created_after)::timestamptz
+  AND created_at < sqlc.arg(created_before)::timestamptz
+  AND (cardinality(sqlc.arg(tags_any)::text[]) = 0 OR tags && sqlc.arg(tags_any)::text[])
+  AND tags @> sqlc.arg(tags_all)::text[]
+  AND NOT EXISTS (
+      SELECT 1 FROM unnest(sqlc.arg(terms)::text[]) AS term
+      WHERE user_read_model.search_text NOT LIKE '% ' || term || '%'
+  )
+ORDER BY created_at DESC, user_id DESC
+LIMIT sqlc.arg(row_limit)::int OFFSET sqlc.arg(row_offset)::int;
+
+-- name: GetOldestUnprojectedUserChange :one
+-- Returns the update time of the user changed longest ago whose change is
+-- not in the read model: a live user without a current view, or a deleted
+-- user whose view was not removed.
+SELECT users.updated_at
+FROM users
+LEFT JOIN user_read_model ON user_read_model.user_id = users.id
+WHERE (users.deleted_at IS NULL
+       AND (user_read_model.user_id IS NULL OR user_read_model.source_updated_at < users.updated_at))
+   OR (users.deleted_at IS NOT NULL AND user_read_model.user_id IS NOT NULL)
+ORDER BY users.updated_at
+LIMIT 1;
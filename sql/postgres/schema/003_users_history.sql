@@ -0,0 +1,48 @@
+-- User history for PostgreSQL
+-- Triggers copy the previous row version into users_history on every update/delete.
+
+CREATE TABLE users_history (
+    history_id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    operation TEXT NOT NULL,
+    email TEXT NOT NULL,
+    username TEXT NOT NULL,
+    first_name TEXT NOT NULL,
+    last_name TEXT NOT NULL,
+    status TEXT NOT NULL,
+    role TEXT NOT NULL,
+    tags TEXT[] NOT NULL DEFAULT '{}',
+    is_active BOOLEAN,
+    is_verified BOOLEAN,
+    profile_metadata JSONB,
+    valid_from TIMESTAMPTZ NOT NULL,
+    valid_to TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_users_history_user_validity ON users_history(user_id, valid_from, valid_to);
+
+CREATE FUNCTION record_users_history() RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO users_history (
+        user_id, operation, email, username, first_name, last_name,
+        status, role, tags, is_active, is_verified, profile_metadata, valid_from
+    ) VALUES (
+        OLD.id, LOWER(TG_OP), OLD.email, OLD.username, OLD.first_name, OLD.last_name,
+        OLD.status, OLD.role, OLD.tags, OLD.is_active, OLD.is_verified, OLD.profile_metadata,
+        COALESCE(OLD.updated_at, OLD.created_at, CURRENT_TIMESTAMP)
+    );
+    IF TG_OP = 'UPDATE' THEN
+        NEW.updated_at = CURRENT_TIMESTAMP;
+        RETURN NEW;
+    END IF;
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER users_history_on_update
+    BEFORE UPDATE ON users
+    FOR EACH ROW EXECUTE FUNCTION record_users_history();
+
+CREATE TRIGGER users_history_on_delete
+    BEFORE DELETE ON users
+    FOR EACH ROW EXECUTE FUNCTION record_users_history();
@@ -0,0 +1,20 @@
+-- Full-text search for PostgreSQL
+-- users_search_document builds the tsvector searched by SearchUsers; the
+-- expression index keeps matching on it index-backed without a stored column.
+
+CREATE FUNCTION users_search_document(
+    email TEXT,
+    username TEXT,
+    first_name TEXT,
+    last_name TEXT
+) RETURNS tsvector
+LANGUAGE sql
+IMMUTABLE
+AS $$
+    SELECT setweight(to_tsvector('simple', username), 'A')
+        || setweight(to_tsvector('simple', email), 'A')
+        || setweight(to_tsvector('simple', first_name || ' ' || last_name), 'B')
+$$;
+
+CREATE INDEX idx_users_search_document ON users
+    USING GIN (users_search_document(email, username, first_name, last_name));
@@ -0,0 +1,17 @@
+-- Audit log for PostgreSQL
+-- One row per mutating operation, written by the service layer.
+-- actor_id is NULL for operations performed by the system.
+
+CREATE TABLE audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    actor_id BIGINT,
+    user_id BIGINT NOT NULL,
+    action TEXT NOT NULL,
+    changes JSONB NOT NULL DEFAULT '[]',
+    ip_address TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_audit_log_user_created ON audit_log(user_id, created_at);
+CREATE INDEX idx_audit_log_actor_created ON audit_log(actor_id, created_at);
+CREATE INDEX idx_audit_log_action_created ON audit_log(action, created_at);
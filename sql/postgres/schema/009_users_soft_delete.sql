@@ -0,0 +1,9 @@
+-- Soft delete for PostgreSQL
+-- deleted_at marks deleted users: lookups skip them until they are restored or
+-- purged. is_active stays in step so that users_history records deletions.
+ALTER TABLE users ADD COLUMN deleted_at TIMESTAMPTZ NULL;
+
+UPDATE users SET deleted_at = COALESCE(updated_at, CURRENT_TIMESTAMP) WHERE is_active = FALSE;
+
+-- Only deleted users are looked up by deletion time, when purging.
+CREATE INDEX idx_users_deleted_at ON users(deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,23 @@
+-- Background jobs for PostgreSQL
+-- A job runs the handler registered under name with its JSON payload. Workers
+-- claim due jobs with FOR UPDATE SKIP LOCKED and lease them until locked_until;
+-- a running job whose lease expired is claimed again. attempts counts the
+-- claims and doubles as the version that finishing a claim compares against.
+
+CREATE TABLE jobs (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    payload JSONB NOT NULL DEFAULT '{}',
+    status TEXT NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'running', 'done', 'failed')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    max_attempts INTEGER NOT NULL DEFAULT 5,
+    run_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    locked_until TIMESTAMPTZ,
+    last_error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_jobs_status_run_at ON jobs(status, run_at);
+CREATE INDEX idx_jobs_status_locked_until ON jobs(status, locked_until);
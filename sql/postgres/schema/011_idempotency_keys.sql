@@ -0,0 +1,17 @@
+-- Idempotency keys for PostgreSQL
+-- Records the outcome of a write made with a client's idempotency key, so
+-- that retries of the write replay its response instead of repeating it. A
+-- record is pending until completed_at is set; expires_at frees the key of a
+-- pending write after a short lock and of a completed one after the TTL.
+
+CREATE TABLE idempotency_keys (
+    idempotency_key TEXT PRIMARY KEY,
+    operation TEXT NOT NULL,
+    request_hash TEXT NOT NULL,
+    response TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    completed_at TIMESTAMPTZ,
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX idx_idempotency_keys_expires_at ON idempotency_keys(expires_at);
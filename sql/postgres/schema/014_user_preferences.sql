@@ -0,0 +1,9 @@
+-- User preferences for PostgreSQL
+-- One JSON object per user mapping preference keys like ui.theme to values.
+-- Preferences without a key use the defaults of the application's schema.
+
+CREATE TABLE user_preferences (
+    user_id BIGINT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    preferences JSONB NOT NULL DEFAULT '{}',
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,19 @@
+-- Activity feeds for PostgreSQL
+-- Human-readable entries projected from domain events, one per user and
+-- event. Entries older than the retention are pruned by the cleanup job.
+-- actor_id is 0 when the user caused the activity.
+
+CREATE TABLE user_activity (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    event_id BIGINT NOT NULL,
+    event_type TEXT NOT NULL,
+    kind TEXT NOT NULL,
+    summary TEXT NOT NULL,
+    actor_id BIGINT NOT NULL DEFAULT 0,
+    occurred_at TIMESTAMPTZ NOT NULL,
+    UNIQUE (user_id, event_id)
+);
+
+CREATE INDEX idx_user_activity_user_occurred ON user_activity(user_id, occurred_at, id);
+CREATE INDEX idx_user_activity_occurred ON user_activity(occurred_at);
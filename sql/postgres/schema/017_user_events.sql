@@ -0,0 +1,23 @@
+-- User event store for PostgreSQL
+-- The event streams of event-sourced users, numbered from 1 per user, and
+-- snapshots of their state every few events so that loading a user replays
+-- only the events after its latest snapshot. The primary keys reject a
+-- second event of the same version, so concurrent writers conflict.
+
+CREATE TABLE user_events (
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    version BIGINT NOT NULL,
+    event_type TEXT NOT NULL,
+    data JSONB NOT NULL DEFAULT '{}',
+    occurred_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (user_id, version)
+);
+
+CREATE TABLE user_snapshots (
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    version BIGINT NOT NULL,
+    deleted BOOLEAN NOT NULL DEFAULT FALSE,
+    state JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (user_id, version)
+);
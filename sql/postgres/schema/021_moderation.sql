@@ -0,0 +1,35 @@
+-- Moderation for PostgreSQL
+-- Records the flags and suspensions moderators impose on users and the
+-- appeals of suspensions. Actions stay in effect until lifted_at is set;
+-- suspensions with an ends_at are lifted once it passes. moderator_id and
+-- lifted_by are kept when the moderator is deleted and are 0 for the system.
+
+CREATE TABLE moderation_actions (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind TEXT NOT NULL,
+    reason TEXT NOT NULL,
+    moderator_id BIGINT NOT NULL DEFAULT 0,
+    ends_at TIMESTAMPTZ,
+    lifted_at TIMESTAMPTZ,
+    lifted_by BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_moderation_actions_user_created ON moderation_actions(user_id, created_at);
+CREATE INDEX idx_moderation_actions_ends_at ON moderation_actions(ends_at) WHERE lifted_at IS NULL;
+
+-- Each suspension can be appealed once.
+CREATE TABLE moderation_appeals (
+    id BIGSERIAL PRIMARY KEY,
+    action_id BIGINT NOT NULL UNIQUE REFERENCES moderation_actions(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    statement TEXT NOT NULL,
+    status TEXT NOT NULL,
+    reviewer_id BIGINT NOT NULL DEFAULT 0,
+    response TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    decided_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_moderation_appeals_status_created ON moderation_appeals(status, created_at);
@@ -0,0 +1,13 @@
+-- Account deletions for PostgreSQL
+-- Holds the accounts whose deletion was requested, until the deletion is
+-- canceled or the account is erased once scheduled_for passes. requested_by
+-- is kept when the requester is deleted.
+
+CREATE TABLE account_deletions (
+    user_id BIGINT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    requested_by BIGINT NOT NULL DEFAULT 0,
+    requested_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    scheduled_for TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX idx_account_deletions_scheduled_for ON account_deletions(scheduled_for);
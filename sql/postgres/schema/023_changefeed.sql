@@ -0,0 +1,15 @@
+-- Changefeed for PostgreSQL
+-- Keeps the commit position up to which the changes of a logical
+-- replication slot were published as domain events. Reading the slot needs
+-- wal_level = logical and the wal2json output plugin.
+--
+-- Updates report the old values of every column, so changed fields can be
+-- told apart from unchanged ones.
+
+CREATE TABLE changefeed_checkpoints (
+    slot_name TEXT PRIMARY KEY,
+    lsn BIGINT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE users REPLICA IDENTITY FULL;
@@ -0,0 +1,43 @@
+-- User sessions for PostgreSQL
+-- One row per session, removed with its user. device_info and
+-- rotated_refresh_tokens are JSON; the location columns are empty when the
+-- IP address could not be resolved. impersonator_id is kept when the
+-- impersonator is deleted, so the session stays attributed to them.
+--
+-- session_refresh_tokens indexes every refresh token issued to a session,
+-- the current one and the rotated ones, so that a rotated token presented
+-- again is traced back to its session.
+
+CREATE TABLE user_sessions (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    session_token UUID NOT NULL UNIQUE,
+    device_info JSONB NOT NULL DEFAULT '{}',
+    ip_address TEXT NOT NULL DEFAULT '',
+    user_agent TEXT NOT NULL DEFAULT '',
+    country TEXT NOT NULL DEFAULT '',
+    region TEXT NOT NULL DEFAULT '',
+    city TEXT NOT NULL DEFAULT '',
+    organization_id BIGINT REFERENCES organizations(id) ON DELETE SET NULL,
+    impersonator_id BIGINT,
+    refresh_token UUID NOT NULL,
+    refresh_expires_at TIMESTAMPTZ,
+    rotated_refresh_tokens JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMPTZ NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE
+);
+
+CREATE INDEX idx_user_sessions_user ON user_sessions(user_id, is_active);
+CREATE INDEX idx_user_sessions_expires_at ON user_sessions(expires_at);
+
+CREATE TABLE session_refresh_tokens (
+    refresh_token UUID PRIMARY KEY,
+    session_id BIGINT NOT NULL REFERENCES user_sessions(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_session_refresh_tokens_session ON session_refresh_tokens(session_id);
+
+-- The changefeed tells signed out sessions from active ones by the old value
+-- of is_active.
+ALTER TABLE user_sessions REPLICA IDENTITY FULL;
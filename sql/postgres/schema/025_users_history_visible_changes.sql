@@ -0,0 +1,16 @@
+-- User history of visible changes for PostgreSQL
+-- Archives a version only when a column users_history keeps changes, so that
+-- writes of last_login_at, password_hash or updated_at alone, such as every
+-- successful login, add no version.
+
+DROP TRIGGER users_history_on_update ON users;
+
+CREATE TRIGGER users_history_on_update
+    BEFORE UPDATE ON users
+    FOR EACH ROW
+    WHEN ((OLD.email, OLD.username, OLD.first_name, OLD.last_name, OLD.status, OLD.role,
+           OLD.tags, OLD.is_active, OLD.is_verified, OLD.profile_metadata)
+          IS DISTINCT FROM
+          (NEW.email, NEW.username, NEW.first_name, NEW.last_name, NEW.status, NEW.role,
+           NEW.tags, NEW.is_active, NEW.is_verified, NEW.profile_metadata))
+    EXECUTE FUNCTION record_users_history();
@@ -0,0 +1,26 @@
+-- name: CreateAuditLog :one
+INSERT INTO audit_log (
+    actor_id, user_id, action, changes, ip_address, created_at
+) VALUES (
+    sqlc.narg(actor_id), sqlc.arg(user_id), sqlc.arg(action),
+    sqlc.arg(changes), sqlc.arg(ip_address), sqlc.arg(created_at)
+)
+RETURNING id;
+
+-- name: ListAuditLogs :many
+-- Zero user/actor IDs and an empty action match any value.
+SELECT id, actor_id, user_id, action, changes, ip_address, created_at
+FROM audit_log
+WHERE (CAST(sqlc.arg(user_id) AS INTEGER) = 0 OR user_id = sqlc.arg(user_id))
+  AND (CAST(sqlc.arg(actor_id) AS INTEGER) = 0 OR actor_id = sqlc.arg(actor_id))
+  AND (CAST(sqlc.arg(action) AS TEXT) = '' OR action = sqlc.arg(action))
+  AND created_at >= sqlc.arg(created_from)
+  AND created_at < sqlc.arg(created_to)
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(row_limit) OFFSET sqlc.arg(row_offset);
+
+-- name: AnonymizeAuditLogs :execrows
+-- Clears the personal data of the entries about or performed by a user.
+UPDATE audit_log
+SET changes = '[]', ip_address = ''
+WHERE user_id = sqlc.arg(user_id) OR actor_id = sqlc.arg(user_id);
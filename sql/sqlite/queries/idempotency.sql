@@ -0,0 +1,22 @@
+-- name: ReserveIdempotencyKey :exec
+INSERT INTO idempotency_keys (idempotency_key, operation, request_hash, created_at, expires_at)
+VALUES (sqlc.arg(idempotency_key), sqlc.arg(operation), sqlc.arg(request_hash), sqlc.arg(created_at), sqlc.arg(expires_at));
+
+-- name: GetIdempotencyKey :one
+SELECT idempotency_key, operation, request_hash, response, created_at, completed_at, expires_at
+FROM idempotency_keys
+WHERE idempotency_key = sqlc.arg(idempotency_key)
+LIMIT 1;
+
+-- name: CompleteIdempotencyKey :execrows
+UPDATE idempotency_keys
+SET response = sqlc.arg(response), completed_at = sqlc.arg(completed_at), expires_at = sqlc.arg(expires_at)
+WHERE idempotency_key = sqlc.arg(idempotency_key) AND completed_at IS NULL;
+
+-- name: ReleaseIdempotencyKey :execrows
+DELETE FROM idempotency_keys
+WHERE idempotency_key = sqlc.arg(idempotency_key) AND completed_at IS NULL;
+
+-- name: DeleteExpiredIdempotencyKeys :execrows
+DELETE FROM idempotency_keys
+WHERE expires_at <= sqlc.arg(now);
@@ -0,0 +1,25 @@
+-- name: CreateUserIdentity :one
+INSERT INTO user_identities (user_id, provider, subject, email, linked_at)
+VALUES (sqlc.arg(user_id), sqlc.arg(provider), sqlc.arg(subject), sqlc.arg(email), sqlc.arg(linked_at))
+RETURNING id;
+
+-- name: GetUserIdentity :one
+SELECT id, user_id, provider, subject, email, linked_at, last_login_at
+FROM user_identities
+WHERE provider = sqlc.arg(provider) AND subject = sqlc.arg(subject)
+LIMIT 1;
+
+-- name: RecordIdentityLogin :execrows
+UPDATE user_identities
+SET email = sqlc.arg(email), last_login_at = sqlc.arg(last_login_at)
+WHERE id = sqlc.arg(id);
+
+-- name: ListUserIdentities :many
+SELECT id, user_id, provider, subject, email, linked_at, last_login_at
+FROM user_identities
+WHERE user_id = sqlc.arg(user_id)
+ORDER BY provider;
+
+-- name: DeleteUserIdentity :execrows
+DELETE FROM user_identities
+WHERE user_id = sqlc.arg(user_id) AND provider = sqlc.arg(provider);
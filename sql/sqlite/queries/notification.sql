@@ -0,0 +1,15 @@
+-- name: ListNotificationPreferences :many
+SELECT user_id, channel, topic, enabled, target, updated_at
+FROM notification_preferences
+WHERE user_id = sqlc.arg(user_id)
+ORDER BY channel, topic;
+
+-- name: UpsertNotificationPreference :exec
+INSERT INTO notification_preferences (user_id, channel, topic, enabled, target, updated_at)
+VALUES (sqlc.arg(user_id), sqlc.arg(channel), sqlc.arg(topic), sqlc.arg(enabled), sqlc.arg(target), sqlc.arg(updated_at))
+ON CONFLICT (user_id, channel, topic) DO UPDATE
+SET enabled = excluded.enabled, target = excluded.target, updated_at = excluded.updated_at;
+
+-- name: DeleteNotificationPreference :execrows
+DELETE FROM notification_preferences
+WHERE user_id = sqlc.arg(user_id) AND channel = sqlc.arg(channel) AND topic = sqlc.arg(topic);
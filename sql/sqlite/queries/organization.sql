@@ -0,0 +1,74 @@
+-- name: CreateOrganization :one
+INSERT INTO organizations (uuid, name, slug, created_at, updated_at)
+VALUES (sqlc.arg(uuid), sqlc.arg(name), sqlc.arg(slug), sqlc.arg(created_at), sqlc.arg(updated_at))
+RETURNING id;
+
+-- name: GetOrganizationByID :one
+SELECT id, uuid, name, slug, created_at, updated_at
+FROM organizations
+WHERE id = sqlc.arg(id)
+LIMIT 1;
+
+-- name: GetOrganizationBySlug :one
+SELECT id, uuid, name, slug, created_at, updated_at
+FROM organizations
+WHERE slug = sqlc.arg(slug)
+LIMIT 1;
+
+-- name: UpdateOrganization :execrows
+UPDATE organizations
+SET name = sqlc.arg(name), slug = sqlc.arg(slug), updated_at = sqlc.arg(updated_at)
+WHERE id = sqlc.arg(id);
+
+-- name: DeleteOrganization :execrows
+DELETE FROM organizations
+WHERE id = sqlc.arg(id);
+
+-- name: ListOrganizations :many
+SELECT id, uuid, name, slug, created_at, updated_at
+FROM organizations
+ORDER BY id
+LIMIT sqlc.arg(row_limit) OFFSET sqlc.arg(row_offset);
+
+-- name: ListOrganizationsByUser :many
+SELECT o.id, o.uuid, o.name, o.slug, o.created_at, o.updated_at
+FROM organizations o
+JOIN organization_members m ON m.organization_id = o.id
+WHERE m.user_id = sqlc.arg(user_id) AND m.status = 'active'
+ORDER BY o.name
+LIMIT sqlc.arg(row_limit);
+
+-- name: CreateMembership :exec
+INSERT INTO organization_members (
+    organization_id, user_id, role, status, invited_by, invited_at, joined_at
+) VALUES (
+    sqlc.arg(organization_id), sqlc.arg(user_id), sqlc.arg(role), sqlc.arg(status),
+    sqlc.narg(invited_by), sqlc.arg(invited_at), sqlc.narg(joined_at)
+);
+
+-- name: GetMembership :one
+SELECT organization_id, user_id, role, status, invited_by, invited_at, joined_at
+FROM organization_members
+WHERE organization_id = sqlc.arg(organization_id) AND user_id = sqlc.arg(user_id)
+LIMIT 1;
+
+-- name: UpdateMembership :execrows
+UPDATE organization_members
+SET role = sqlc.arg(role), status = sqlc.arg(status), joined_at = sqlc.narg(joined_at)
+WHERE organization_id = sqlc.arg(organization_id) AND user_id = sqlc.arg(user_id);
+
+-- name: DeleteMembership :execrows
+DELETE FROM organization_members
+WHERE organization_id = sqlc.arg(organization_id) AND user_id = sqlc.arg(user_id);
+
+-- name: ListMemberships :many
+SELECT organization_id, user_id, role, status, invited_by, invited_at, joined_at
+FROM organization_members
+WHERE organization_id = sqlc.arg(organization_id)
+ORDER BY invited_at, user_id
+LIMIT sqlc.arg(row_limit) OFFSET sqlc.arg(row_offset);
+
+-- name: CountOrganizationAdmins :one
+SELECT COUNT(*)
+FROM organization_members
+WHERE organization_id = sqlc.arg(organization_id) AND role = 'admin' AND status = 'active';
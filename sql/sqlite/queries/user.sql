@@ -1,23 +1,28 @@
 -- name: CreateUser :one
 INSERT INTO users (
-    uuid, email, username, password_hash, 
-    first_name, last_name, profile_metadata, is_active
+    uuid, email, username, password_hash,
+    first_name, last_name, profile_metadata, is_active,
+    is_verified, status, role, tags, created_at, updated_at,
+    username_canonical
 ) VALUES (
-    ?, ?, ?, ?, ?, ?, ?, ?
+    ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
 )
 RETURNING *;
 
 -- name: GetUserByID :one
-SELECT * FROM users WHERE id = ? AND is_active = TRUE;
+SELECT * FROM users WHERE id = ? AND deleted_at IS NULL;
+
+-- name: GetUsersByIDs :many
+SELECT * FROM users WHERE id IN (sqlc.slice('ids')) AND deleted_at IS NULL;
 
 -- name: GetUserByUUID :one
-SELECT * FROM users WHERE uuid = ? AND is_active = TRUE;
+SELECT * FROM users WHERE uuid = ? AND deleted_at IS NULL;
 
 -- name: GetUserByEmail :one
-SELECT * FROM users WHERE email = ? AND is_active = TRUE;
+SELECT * FROM users WHERE email = ? AND deleted_at IS NULL;
 
 -- name: GetUserByUsername :one
-SELECT * FROM users WHERE username = ? AND is_active = TRUE;
+SELECT * FROM users WHERE username_canonical = ? AND deleted_at IS NULL;
 
 -- name: UpdateUser :one
 UPDATE users 
@@ -28,8 +33,14 @@ SET
     last_name = COALESCE(?, last_name),
     profile_metadata = COALESCE(?, profile_metadata),
     is_active = COALESCE(?, is_active),
-    is_verified = COALESCE(?, is_verified)
-WHERE id = ?
+    is_verified = COALESCE(?, is_verified),
+    status = COALESCE(sqlc.narg(status), status),
+    role = COALESCE(sqlc.narg(role), role),
+    tags = COALESCE(sqlc.narg(tags), tags),
+    last_login_at = COALESCE(sqlc.narg(last_login_at), last_login_at),
+    username_canonical = sqlc.arg(username_canonical),
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id) AND deleted_at IS NULL
 RETURNING *;
 
 -- name: UpdatePassword :exec
@@ -38,18 +49,33 @@ SET password_hash = ?, updated_at = CURRENT_TIMESTAMP
 WHERE id = ?;
 
 -- name: SoftDeleteUser :exec
-UPDATE users 
-SET is_active = FALSE, updated_at = CURRENT_TIMESTAMP
-WHERE id = ?;
+UPDATE users
+SET is_active = FALSE, deleted_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND deleted_at IS NULL;
+
+-- name: RestoreUser :execrows
+UPDATE users
+SET is_active = TRUE, deleted_at = NULL, updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND deleted_at IS NOT NULL;
+
+-- name: PurgeDeletedUsers :execrows
+-- Permanently removes users soft deleted before the cutoff.
+DELETE FROM users
+WHERE deleted_at IS NOT NULL AND deleted_at < sqlc.arg(cutoff);
+
+-- name: SoftDeleteUsers :execrows
+UPDATE users
+SET is_active = FALSE, deleted_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+WHERE id IN (sqlc.slice('ids')) AND deleted_at IS NULL;
 
 -- name: ListUsers :many
 SELECT * FROM users 
-WHERE is_active = TRUE 
+WHERE deleted_at IS NULL 
 ORDER BY created_at DESC
 LIMIT ? OFFSET ?;
 
 -- name: CountActiveUsers :one
-SELECT COUNT(*) FROM users WHERE is_active = TRUE;
+SELECT COUNT(*) FROM users WHERE deleted_at IS NULL;
 
 -- name: UpdateLastLogin :exec
 UPDATE users 
@@ -64,7 +90,154 @@ WHERE uuid = ?;
 -- name: GetUserStats :one
 SELECT 
     COUNT(*) as total_users,
-    COUNT(CASE WHEN is_active = TRUE THEN 1 END) as active_users,
+    COUNT(CASE WHEN deleted_at IS NULL THEN 1 END) as active_users,
     COUNT(CASE WHEN is_verified = TRUE THEN 1 END) as verified_users,
-    COUNT(CASE WHEN last_login_at IS NOT NULL THEN 1 END) as users_with_logins
+    COUNT(CASE WHEN last_login_at IS NOT NULL THEN 1 END) as users_with_logins,
+    COUNT(CASE WHEN status = 'inactive' THEN 1 END) as inactive_users,
+    COUNT(CASE WHEN status = 'suspended' THEN 1 END) as suspended_users,
+    COUNT(CASE WHEN created_at >= datetime('now', '-30 days') THEN 1 END) as new_users_30d,
+    COUNT(CASE WHEN created_at >= datetime('now', '-7 days') THEN 1 END) as new_users_7d
 FROM users;
+
+-- name: FilterUsers :many
+-- Lists users matching a UserFilter, newest first with the ID breaking ties.
+-- Empty sets and an empty query match any user; a negative verified matches
+-- both states. Open time ranges and the first page's keyset cursor are
+-- replaced by bounds beyond any stored value.
+-- Statuses, roles and tag sets are JSON arrays of strings.
+SELECT * FROM users
+WHERE deleted_at IS NULL
+  AND (json_array_length(CAST(sqlc.arg(statuses) AS TEXT)) = 0
+       OR status IN (SELECT value FROM json_each(CAST(sqlc.arg(statuses) AS TEXT))))
+  AND (json_array_length(CAST(sqlc.arg(roles) AS TEXT)) = 0
+       OR role IN (SELECT value FROM json_each(CAST(sqlc.arg(roles) AS TEXT))))
+  AND (CAST(sqlc.arg(verified) AS INTEGER) < 0 OR is_verified = CAST(sqlc.arg(verified) AS INTEGER))
+  AND created_at > sqlc.arg(created_after)
+  AND created_at < sqlc.arg(created_before)
+  AND (json_array_length(CAST(sqlc.arg(tags_any) AS TEXT)) = 0 OR EXISTS (
+      SELECT 1 FROM json_each(users.tags) AS user_tag
+      WHERE user_tag.value IN (SELECT value FROM json_each(CAST(sqlc.arg(tags_any) AS TEXT)))
+  ))
+  AND NOT EXISTS (
+      SELECT 1 FROM json_each(CAST(sqlc.arg(tags_all) AS TEXT)) AS wanted
+      WHERE wanted.value NOT IN (SELECT value FROM json_each(users.tags))
+  )
+  AND (CAST(sqlc.arg(query) AS TEXT) = ''
+       OR id IN (SELECT rowid FROM users_fts WHERE users_fts MATCH CAST(sqlc.arg(query) AS TEXT)))
+  AND (created_at < sqlc.arg(cursor_created_at)
+       OR (created_at = sqlc.arg(cursor_created_at) AND id < sqlc.arg(cursor_id)))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(row_limit) OFFSET sqlc.arg(row_offset);
+
+-- name: CountUsersByStatus :many
+SELECT status, COUNT(*) AS total
+FROM users
+WHERE deleted_at IS NULL
+GROUP BY status;
+
+-- name: UpdateUserStatus :exec
+UPDATE users
+SET status = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?;
+
+-- name: UpdateUsersStatus :execrows
+UPDATE users
+SET status = sqlc.arg(status), updated_at = CURRENT_TIMESTAMP
+WHERE id IN (sqlc.slice('ids')) AND deleted_at IS NULL;
+
+-- name: UpdateUserRole :exec
+UPDATE users
+SET role = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?;
+
+-- name: MarkUserVerified :exec
+UPDATE users
+SET is_verified = TRUE, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?;
+
+-- name: SearchUsersWithFacets :many
+-- Returns one page of matching users together with facet counts computed
+-- over every match. Facets are JSON columns repeated on each row; a single
+-- row with NULL user columns is returned when the page is empty.
+WITH matched AS (
+    SELECT id, uuid, email, username, first_name, last_name, status, role, tags, created_at
+    FROM users
+    WHERE deleted_at IS NULL
+      AND (
+          email LIKE '%' || CAST(sqlc.arg(query) AS TEXT) || '%'
+          OR username LIKE '%' || CAST(sqlc.arg(query) AS TEXT) || '%'
+          OR first_name LIKE '%' || CAST(sqlc.arg(query) AS TEXT) || '%'
+          OR last_name LIKE '%' || CAST(sqlc.arg(query) AS TEXT) || '%'
+      )
+),
+status_facets AS (
+    SELECT json_group_object(status, total) AS counts
+    FROM (SELECT status, COUNT(*) AS total FROM matched GROUP BY status)
+),
+role_facets AS (
+    SELECT json_group_object(role, total) AS counts
+    FROM (SELECT role, COUNT(*) AS total FROM matched GROUP BY role)
+),
+tag_facets AS (
+    SELECT json_group_array(json_array(tag, total)) AS counts
+    FROM (
+        SELECT je.value AS tag, COUNT(*) AS total
+        FROM matched, json_each(matched.tags) AS je
+        GROUP BY je.value
+        ORDER BY total DESC, tag
+        LIMIT sqlc.arg(tag_limit)
+    )
+),
+page AS (
+    SELECT id, uuid, email, username, first_name, last_name, status, role, tags, created_at
+    FROM matched
+    WHERE CAST(sqlc.arg(status) AS TEXT) = '' OR status = CAST(sqlc.arg(status) AS TEXT)
+    ORDER BY created_at DESC
+    LIMIT sqlc.arg(result_limit)
+)
+SELECT
+    page.id, page.uuid, page.email, page.username, page.first_name, page.last_name,
+    page.status, page.role, page.tags, page.created_at,
+    status_facets.counts AS status_facets,
+    role_facets.counts AS role_facets,
+    tag_facets.counts AS tag_facets
+FROM status_facets
+CROSS JOIN role_facets
+CROSS JOIN tag_facets
+LEFT JOIN page ON TRUE;
+
+-- name: GetUserAsOf :one
+-- Returns the version of a user that was valid at the given time, preferring
+-- archived versions over the current row.
+SELECT user_id, operation, email, username, first_name, last_name, status, role, tags,
+       is_active, is_verified, valid_from, valid_to
+FROM (
+    SELECT u.id AS user_id, 'current' AS operation, u.email, u.username, u.first_name, u.last_name,
+           u.status, u.role, u.tags, u.is_active, u.is_verified,
+           COALESCE(u.updated_at, u.created_at) AS valid_from,
+           NULL AS valid_to, 1 AS is_current
+    FROM users u
+    WHERE u.id = sqlc.arg(user_id)
+      AND COALESCE(u.updated_at, u.created_at) <= sqlc.arg(as_of)
+    UNION ALL
+    SELECT h.user_id, h.operation, h.email, h.username, h.first_name, h.last_name,
+           h.status, h.role, h.tags, h.is_active, h.is_verified,
+           h.valid_from, h.valid_to, 0
+    FROM users_history h
+    WHERE h.user_id = sqlc.arg(user_id)
+      AND h.valid_from <= sqlc.arg(as_of)
+      AND h.valid_to > sqlc.arg(as_of)
+) AS versions
+ORDER BY is_current, valid_from DESC
+LIMIT 1;
+
+-- name: ListUserHistory :many
+SELECT * FROM users_history
+WHERE user_id = ?
+ORDER BY valid_from DESC
+LIMIT ?;
+
+-- name: DeleteUserHistory :execrows
+-- Removes every archived version of a user.
+DELETE FROM users_history
+WHERE user_id = sqlc.arg(user_id);
@@ -0,0 +1,35 @@
+-- name: GetUserStreamVersion :one
+SELECT CAST(COALESCE(MAX(version), 0) AS INTEGER) AS version
+FROM user_events
+WHERE user_id = sqlc.arg(user_id);
+
+-- name: AppendUserEvent :exec
+INSERT INTO user_events (user_id, version, event_type, data, occurred_at)
+VALUES (sqlc.arg(user_id), sqlc.arg(version), sqlc.arg(event_type), sqlc.arg(data), sqlc.arg(occurred_at));
+
+-- name: ListUserEvents :many
+SELECT user_id, version, event_type, data, occurred_at
+FROM user_events
+WHERE user_id = sqlc.arg(user_id)
+  AND version > sqlc.arg(after_version)
+ORDER BY version;
+
+-- name: SaveUserSnapshot :exec
+INSERT INTO user_snapshots (user_id, version, deleted, state, created_at)
+VALUES (sqlc.arg(user_id), sqlc.arg(version), sqlc.arg(deleted), sqlc.arg(state), sqlc.arg(created_at))
+ON CONFLICT (user_id, version) DO NOTHING;
+
+-- name: GetLatestUserSnapshot :one
+SELECT user_id, version, deleted, state, created_at
+FROM user_snapshots
+WHERE user_id = sqlc.arg(user_id)
+ORDER BY version DESC
+LIMIT 1;
+
+-- name: DeleteUserEvents :execrows
+DELETE FROM user_events
+WHERE user_id = sqlc.arg(user_id);
+
+-- name: DeleteUserSnapshots :execrows
+DELETE FROM user_snapshots
+WHERE user_id = sqlc.arg(user_id);
@@ -0,0 +1,18 @@
+-- name: GetUserPreferences :one
+SELECT user_id, preferences, updated_at
+FROM user_preferences
+WHERE user_id = sqlc.arg(user_id)
+LIMIT 1;
+
+-- name: PatchUserPreferences :one
+-- Merges patch into the stored preferences with json_patch (RFC 7396):
+-- keys set to null are removed.
+INSERT INTO user_preferences (user_id, preferences, updated_at)
+VALUES (sqlc.arg(user_id), json_patch('{}', sqlc.arg(patch)), sqlc.arg(updated_at))
+ON CONFLICT (user_id) DO UPDATE
+SET preferences = json_patch(user_preferences.preferences, sqlc.arg(patch)), updated_at = excluded.updated_at
+RETURNING user_id, preferences, updated_at;
+
+-- name: DeleteUserPreferences :execrows
+DELETE FROM user_preferences
+WHERE user_id = sqlc.arg(user_id);
@@ -0,0 +1,64 @@
+-- name: UpsertUserView :exec
+-- A view projected from an older version of the user is not stored, so
+-- views projected out of order never go back in time.
+INSERT INTO user_read_model (
+    user_id, uuid, email, username, full_name, status, role, tags, is_verified,
+    search_text, created_at, source_updated_at, projected_at
+) VALUES (
+    sqlc.arg(user_id), sqlc.arg(uuid), sqlc.arg(email), sqlc.arg(username), sqlc.arg(full_name),
+    sqlc.arg(status), sqlc.arg(role), sqlc.arg(tags), sqlc.arg(is_verified),
+    sqlc.arg(search_text), sqlc.arg(created_at), sqlc.arg(source_updated_at), sqlc.arg(projected_at)
+)
+ON CONFLICT (user_id) DO UPDATE
+SET uuid = excluded.uuid, email = excluded.email, username = excluded.username,
+    full_name = excluded.full_name, status = excluded.status, role = excluded.role, tags = excluded.tags,
+    is_verified = excluded.is_verified, search_text = excluded.search_text, created_at = excluded.created_at,
+    source_updated_at = excluded.source_updated_at, projected_at = excluded.projected_at
+WHERE user_read_model.source_updated_at <= excluded.source_updated_at;
+
+-- name: DeleteUserViews :exec
+DELETE FROM user_read_model WHERE user_id IN (sqlc.slice('ids'));
+
+-- name: ListUserViews :many
+-- Lists views matching a UserFilter, newest first with the user ID breaking
+-- ties. Empty sets match any view; a negative verified matches both states.
+-- Open time ranges are replaced by bounds beyond any stored value.
+-- Statuses, roles, tag sets and the query terms are JSON arrays of strings.
+SELECT user_id, uuid, email, username, full_name, status, role, tags, is_verified,
+       created_at, source_updated_at, projected_at
+FROM user_read_model
+WHERE (json_array_length(CAST(sqlc.arg(statuses) AS TEXT)) = 0
+       OR status IN (SELECT value FROM json_each(CAST(sqlc.arg(statuses) AS TEXT))))
+  AND (json_array_length(CAST(sqlc.arg(roles) AS TEXT)) = 0
+       OR role IN (SELECT value FROM json_each(CAST(sqlc.arg(roles) AS TEXT))))
+  AND (CAST(sqlc.arg(verified) AS INTEGER) < 0 OR is_verified = CAST(sqlc.arg(verified) AS INTEGER))
+  AND created_at > sqlc.arg(created_after)
+  AND created_at < sqlc.arg(created_before)
+  AND (json_array_length(CAST(sqlc.arg(tags_any) AS TEXT)) = 0 OR EXISTS (
+      SELECT 1 FROM json_each(user_read_model.tags) AS view_tag
+      WHERE view_tag.value IN (SELECT value FROM json_each(CAST(sqlc.arg(tags_any) AS TEXT)))
+  ))
+  AND NOT EXISTS (
+      SELECT 1 FROM json_each(CAST(sqlc.arg(tags_all) AS TEXT)) AS wanted
+      WHERE wanted.value NOT IN (SELECT value FROM json_each(user_read_model.tags))
+  )
+  AND NOT EXISTS (
+      SELECT 1 FROM json_each(CAST(sqlc.arg(terms) AS TEXT)) AS term
+      WHERE user_read_model.search_text NOT LIKE '% ' || term.value || '%'
+  )
+ORDER BY created_at DESC, user_id DESC
+LIMIT sqlc.arg(row_limit) OFFSET sqlc.arg(row_offset);
+
+-- name: GetOldestUnprojectedUserChange :one
+-- Returns the update time of the user changed longest ago whose change is
+-- not in the read model: a live user without a current view, or a deleted
+-- user whose view was not removed.
+SELECT users.updated_at
+FROM users
+LEFT JOIN user_read_model ON user_read_model.user_id = users.id
+WHERE (users.deleted_at IS NULL
+       AND (user_read_model.user_id IS NULL
+            OR user_read_model.source_updated_at < users.updated_at))
+   OR (users.deleted_at IS NOT NULL AND user_read_model.user_id IS NOT NULL)
+ORDER BY users.updated_at
+LIMIT 1;
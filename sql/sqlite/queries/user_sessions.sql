@@ -0,0 +1,125 @@
+-- name: CreateSession :one
+INSERT INTO user_sessions (
+    user_id, session_token, device_info, ip_address, user_agent, country, region, city,
+    organization_id, impersonator_id, refresh_token, refresh_expires_at, rotated_refresh_tokens,
+    created_at, expires_at, is_active
+) VALUES (
+    sqlc.arg(user_id), sqlc.arg(session_token), sqlc.arg(device_info), sqlc.arg(ip_address),
+    sqlc.arg(user_agent), sqlc.arg(country), sqlc.arg(region), sqlc.arg(city),
+    sqlc.narg(organization_id), sqlc.narg(impersonator_id), sqlc.arg(refresh_token),
+    sqlc.narg(refresh_expires_at), sqlc.arg(rotated_refresh_tokens),
+    sqlc.arg(created_at), sqlc.arg(expires_at), sqlc.arg(is_active)
+)
+RETURNING id;
+
+-- name: AddSessionRefreshToken :exec
+-- Tokens indexed before are left alone, so every token can be added again
+-- on each update.
+INSERT INTO session_refresh_tokens (refresh_token, session_id)
+VALUES (sqlc.arg(refresh_token), sqlc.arg(session_id))
+ON CONFLICT (refresh_token) DO NOTHING;
+
+-- name: GetSessionByToken :one
+SELECT id, user_id, session_token, device_info, ip_address, user_agent, country, region, city,
+       organization_id, impersonator_id, refresh_token, refresh_expires_at, rotated_refresh_tokens,
+       created_at, expires_at, is_active
+FROM user_sessions
+WHERE session_token = sqlc.arg(session_token)
+LIMIT 1;
+
+-- name: GetSessionByRefreshToken :one
+-- Matches the current and the rotated refresh tokens of a session.
+SELECT s.id, s.user_id, s.session_token, s.device_info, s.ip_address, s.user_agent, s.country,
+       s.region, s.city, s.organization_id, s.impersonator_id, s.refresh_token, s.refresh_expires_at,
+       s.rotated_refresh_tokens, s.created_at, s.expires_at, s.is_active
+FROM user_sessions s
+JOIN session_refresh_tokens t ON t.session_id = s.id
+WHERE t.refresh_token = sqlc.arg(refresh_token)
+LIMIT 1;
+
+-- name: ListSessionsByUser :many
+-- lint:ignore missing-limit
+SELECT id, user_id, session_token, device_info, ip_address, user_agent, country, region, city,
+       organization_id, impersonator_id, refresh_token, refresh_expires_at, rotated_refresh_tokens,
+       created_at, expires_at, is_active
+FROM user_sessions
+WHERE user_id = sqlc.arg(user_id)
+ORDER BY id DESC;
+
+-- name: ListActiveSessionsByUser :many
+-- lint:ignore missing-limit
+SELECT id, user_id, session_token, device_info, ip_address, user_agent, country, region, city,
+       organization_id, impersonator_id, refresh_token, refresh_expires_at, rotated_refresh_tokens,
+       created_at, expires_at, is_active
+FROM user_sessions
+WHERE user_id = sqlc.arg(user_id) AND is_active = TRUE
+ORDER BY id DESC;
+
+-- name: UpdateSession :execrows
+UPDATE user_sessions SET
+    device_info = sqlc.arg(device_info),
+    ip_address = sqlc.arg(ip_address),
+    user_agent = sqlc.arg(user_agent),
+    country = sqlc.arg(country),
+    region = sqlc.arg(region),
+    city = sqlc.arg(city),
+    organization_id = sqlc.narg(organization_id),
+    impersonator_id = sqlc.narg(impersonator_id),
+    refresh_token = sqlc.arg(refresh_token),
+    refresh_expires_at = sqlc.narg(refresh_expires_at),
+    rotated_refresh_tokens = sqlc.arg(rotated_refresh_tokens),
+    expires_at = sqlc.arg(expires_at),
+    is_active = sqlc.arg(is_active)
+WHERE id = sqlc.arg(id);
+
+-- name: UpdateSessionIfCurrent :execrows
+-- Stores the session only while it is active with the expected refresh token,
+-- so that concurrent rotations and revocations are not overwritten.
+UPDATE user_sessions SET
+    device_info = sqlc.arg(device_info),
+    ip_address = sqlc.arg(ip_address),
+    user_agent = sqlc.arg(user_agent),
+    country = sqlc.arg(country),
+    region = sqlc.arg(region),
+    city = sqlc.arg(city),
+    organization_id = sqlc.narg(organization_id),
+    impersonator_id = sqlc.narg(impersonator_id),
+    refresh_token = sqlc.arg(refresh_token),
+    refresh_expires_at = sqlc.narg(refresh_expires_at),
+    rotated_refresh_tokens = sqlc.arg(rotated_refresh_tokens),
+    expires_at = sqlc.arg(expires_at),
+    is_active = sqlc.arg(is_active)
+WHERE id = sqlc.arg(id)
+  AND refresh_token = sqlc.arg(expected_refresh_token)
+  AND is_active = TRUE;
+
+-- name: DeleteSession :execrows
+DELETE FROM user_sessions
+WHERE id = sqlc.arg(id);
+
+-- name: DeactivateSessionByToken :execrows
+UPDATE user_sessions SET is_active = FALSE
+WHERE session_token = sqlc.arg(session_token);
+
+-- name: DeactivateSessionsByUser :exec
+UPDATE user_sessions SET is_active = FALSE
+WHERE user_id = sqlc.arg(user_id);
+
+-- name: DeleteExpiredSessions :execrows
+DELETE FROM user_sessions
+WHERE expires_at < sqlc.arg(now);
+
+-- name: CountActiveSessions :one
+SELECT COUNT(*) AS count
+FROM user_sessions
+WHERE user_id = sqlc.arg(user_id) AND is_active = TRUE AND expires_at >= sqlc.arg(now);
+
+-- name: GetSessionStats :one
+SELECT
+    COUNT(*) AS total_sessions,
+    COUNT(CASE WHEN is_active = TRUE AND expires_at >= sqlc.arg(now) THEN 1 END) AS active_sessions,
+    COUNT(CASE WHEN expires_at < sqlc.arg(now) THEN 1 END) AS expired_sessions,
+    COUNT(CASE WHEN created_at >= sqlc.arg(day_ago) THEN 1 END) AS sessions_24h,
+    COUNT(CASE WHEN created_at >= sqlc.arg(week_ago) THEN 1 END) AS sessions_7d,
+    COUNT(CASE WHEN created_at >= sqlc.arg(month_ago) THEN 1 END) AS sessions_30d
+FROM user_sessions;
@@ -0,0 +1,9 @@
+-- Status, role and tags for SQLite
+-- Tags are stored as a JSON array and expanded with json_each for facets.
+
+ALTER TABLE users ADD COLUMN status TEXT NOT NULL DEFAULT 'active';
+ALTER TABLE users ADD COLUMN role TEXT NOT NULL DEFAULT 'user';
+ALTER TABLE users ADD COLUMN tags TEXT NOT NULL DEFAULT '[]';
+
+CREATE INDEX idx_users_status ON users(status);
+CREATE INDEX idx_users_role ON users(role);
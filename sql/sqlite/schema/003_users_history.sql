@@ -0,0 +1,53 @@
+-- User history for SQLite
+-- Triggers copy the previous row version into users_history on every update/delete.
+-- The update trigger also stamps updated_at so the current version starts where
+-- the history ends (recursive_triggers is off by default, so this does not recurse).
+
+CREATE TABLE users_history (
+    history_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    operation TEXT NOT NULL,
+    email TEXT NOT NULL,
+    username TEXT NOT NULL,
+    first_name TEXT NOT NULL,
+    last_name TEXT NOT NULL,
+    status TEXT NOT NULL,
+    role TEXT NOT NULL,
+    tags TEXT NOT NULL DEFAULT '[]',
+    is_active BOOLEAN,
+    is_verified BOOLEAN,
+    profile_metadata TEXT,
+    valid_from DATETIME NOT NULL,
+    valid_to DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_users_history_user_validity ON users_history(user_id, valid_from, valid_to);
+
+CREATE TRIGGER users_history_on_update
+AFTER UPDATE ON users
+FOR EACH ROW
+BEGIN
+    INSERT INTO users_history (
+        user_id, operation, email, username, first_name, last_name,
+        status, role, tags, is_active, is_verified, profile_metadata, valid_from
+    ) VALUES (
+        OLD.id, 'update', OLD.email, OLD.username, OLD.first_name, OLD.last_name,
+        OLD.status, OLD.role, OLD.tags, OLD.is_active, OLD.is_verified, OLD.profile_metadata,
+        COALESCE(OLD.updated_at, OLD.created_at, CURRENT_TIMESTAMP)
+    );
+    UPDATE users SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+CREATE TRIGGER users_history_on_delete
+AFTER DELETE ON users
+FOR EACH ROW
+BEGIN
+    INSERT INTO users_history (
+        user_id, operation, email, username, first_name, last_name,
+        status, role, tags, is_active, is_verified, profile_metadata, valid_from
+    ) VALUES (
+        OLD.id, 'delete', OLD.email, OLD.username, OLD.first_name, OLD.last_name,
+        OLD.status, OLD.role, OLD.tags, OLD.is_active, OLD.is_verified, OLD.profile_metadata,
+        COALESCE(OLD.updated_at, OLD.created_at, CURRENT_TIMESTAMP)
+    );
+END;
@@ -0,0 +1,34 @@
+-- Full-text search for SQLite (FTS5)
+-- External-content index over the searchable user columns, kept in sync by triggers.
+
+CREATE VIRTUAL TABLE users_fts USING fts5(
+    email,
+    username,
+    first_name,
+    last_name,
+    content='users',
+    content_rowid='id'
+);
+
+CREATE TRIGGER users_fts_after_insert
+AFTER INSERT ON users
+BEGIN
+    INSERT INTO users_fts (rowid, email, username, first_name, last_name)
+    VALUES (NEW.id, NEW.email, NEW.username, NEW.first_name, NEW.last_name);
+END;
+
+CREATE TRIGGER users_fts_after_delete
+AFTER DELETE ON users
+BEGIN
+    INSERT INTO users_fts (users_fts, rowid, email, username, first_name, last_name)
+    VALUES ('delete', OLD.id, OLD.email, OLD.username, OLD.first_name, OLD.last_name);
+END;
+
+CREATE TRIGGER users_fts_after_update
+AFTER UPDATE OF email, username, first_name, last_name ON users
+BEGIN
+    INSERT INTO users_fts (users_fts, rowid, email, username, first_name, last_name)
+    VALUES ('delete', OLD.id, OLD.email, OLD.username, OLD.first_name, OLD.last_name);
+    INSERT INTO users_fts (rowid, email, username, first_name, last_name)
+    VALUES (NEW.id, NEW.email, NEW.username, NEW.first_name, NEW.last_name);
+END;
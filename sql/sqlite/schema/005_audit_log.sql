@@ -0,0 +1,17 @@
+-- Audit log for SQLite
+-- One row per mutating operation, written by the service layer.
+-- actor_id is NULL for operations performed by the system.
+
+CREATE TABLE audit_log (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    actor_id INTEGER,
+    user_id INTEGER NOT NULL,
+    action TEXT NOT NULL,
+    changes TEXT NOT NULL DEFAULT '[]',
+    ip_address TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_audit_log_user_created ON audit_log(user_id, created_at);
+CREATE INDEX idx_audit_log_actor_created ON audit_log(actor_id, created_at);
+CREATE INDEX idx_audit_log_action_created ON audit_log(action, created_at);
@@ -0,0 +1,24 @@
+-- Organizations and memberships for SQLite
+-- A membership row with status 'invited' is a pending invitation.
+
+CREATE TABLE organizations (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    uuid TEXT UNIQUE NOT NULL,
+    name TEXT NOT NULL,
+    slug TEXT UNIQUE NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE organization_members (
+    organization_id INTEGER NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role TEXT NOT NULL CHECK (role IN ('user', 'moderator', 'admin')),
+    status TEXT NOT NULL CHECK (status IN ('invited', 'active')),
+    invited_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    invited_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    joined_at DATETIME NULL,
+    PRIMARY KEY (organization_id, user_id)
+);
+
+CREATE INDEX idx_organization_members_user ON organization_members(user_id, status);
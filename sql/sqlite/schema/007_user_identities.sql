@@ -0,0 +1,15 @@
+-- External identities for SQLite
+-- Links users to accounts at OAuth2/OIDC providers. A provider subject belongs
+-- to one user, and a user has at most one identity per provider.
+
+CREATE TABLE user_identities (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    provider TEXT NOT NULL,
+    subject TEXT NOT NULL,
+    email TEXT NOT NULL DEFAULT '',
+    linked_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_login_at DATETIME NULL,
+    UNIQUE (provider, subject),
+    UNIQUE (user_id, provider)
+);
@@ -0,0 +1,3 @@
+-- Keyset pagination index for SQLite
+-- Listings page through users ordered by (created_at, id).
+CREATE INDEX idx_users_created_at_id ON users(created_at, id);
@@ -0,0 +1,9 @@
+-- User preferences for SQLite
+-- One JSON object per user mapping preference keys like ui.theme to values.
+-- Preferences without a key use the defaults of the application's schema.
+
+CREATE TABLE user_preferences (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    preferences TEXT NOT NULL DEFAULT '{}' CHECK (json_valid(preferences)),
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
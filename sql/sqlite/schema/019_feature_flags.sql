@@ -0,0 +1,9 @@
+-- Feature flags for SQLite
+-- Named switches that change the behavior of running instances without a
+-- deploy, such as maintenance modes. Flags without a row use their default.
+
+CREATE TABLE feature_flags (
+    name TEXT PRIMARY KEY CHECK (length(name) BETWEEN 1 AND 100),
+    enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
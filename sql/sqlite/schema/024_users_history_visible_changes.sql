@@ -0,0 +1,31 @@
+-- User history of visible changes for SQLite
+-- Archives a version only when a column users_history keeps changes, so that
+-- writes of last_login_at, password_hash or updated_at alone, such as every
+-- successful login, add no version.
+
+DROP TRIGGER users_history_on_update;
+
+CREATE TRIGGER users_history_on_update
+AFTER UPDATE ON users
+FOR EACH ROW
+WHEN OLD.email IS NOT NEW.email
+    OR OLD.username IS NOT NEW.username
+    OR OLD.first_name IS NOT NEW.first_name
+    OR OLD.last_name IS NOT NEW.last_name
+    OR OLD.status IS NOT NEW.status
+    OR OLD.role IS NOT NEW.role
+    OR OLD.tags IS NOT NEW.tags
+    OR OLD.is_active IS NOT NEW.is_active
+    OR OLD.is_verified IS NOT NEW.is_verified
+    OR OLD.profile_metadata IS NOT NEW.profile_metadata
+BEGIN
+    INSERT INTO users_history (
+        user_id, operation, email, username, first_name, last_name,
+        status, role, tags, is_active, is_verified, profile_metadata, valid_from
+    ) VALUES (
+        OLD.id, 'update', OLD.email, OLD.username, OLD.first_name, OLD.last_name,
+        OLD.status, OLD.role, OLD.tags, OLD.is_active, OLD.is_verified, OLD.profile_metadata,
+        COALESCE(OLD.updated_at, OLD.created_at, CURRENT_TIMESTAMP)
+    );
+    UPDATE users SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
@@ -0,0 +1,28 @@
+Feature: User Lookup
+  As a system administrator
+  I want to look up user accounts and reject bad credentials
+  So that only existing users can be found and signed in
+
+  Background:
+    Given a clean user system
+    And the event publisher is cleared
+
+  Scenario: The first user gets the first ID
+    When I create a user with valid data
+    Then the user should be created successfully
+    And the user should have ID 1
+
+  Scenario: Get an existing user by ID
+    Given an active user account
+    When I get the user with ID 1
+    Then the user should have ID 1
+
+  Scenario: Get a user that does not exist
+    When I get the user with ID 999
+    Then I should receive a "user not found" error
+
+  Scenario: Authenticate with the wrong password
+    Given I have invalid user credentials
+    When I attempt to authenticate with these credentials
+    Then I should receive a "invalid credentials" error
+    And a user login failed event should be published